@@ -0,0 +1,154 @@
+// Command rotate-keys manages the keys that encrypt stored payment provider credentials.
+//
+// Rotating a local key:
+//  1. rotate-keys generate-key, and prepend "<version>:<key>" to PAYMENT_SYNC_KEYRING
+//     (or point PAYMENT_SYNC_KMS_KEY_ID at the new KMS key) and deploy every service
+//  2. rotate-keys reencrypt, or wait for the subscription processor to do it
+//  3. rotate-keys status until only the new version is listed, then remove the old key
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	awsclient "github.com/cyphera/cyphera-api/libs/go/client/aws"
+	"github.com/cyphera/cyphera-api/libs/go/client/payment_sync"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: rotate-keys <command> [flags]
+
+Commands:
+  status        Show how many provider configurations and webhook secrets use each key version
+  reencrypt     Re-encrypt all provider configurations and webhook secrets under the current key version
+  generate-key  Print a new random AES-256 key for PAYMENT_SYNC_KEYRING
+`)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "generate-key":
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			log.Fatalf("Failed to generate key: %v", err)
+		}
+		fmt.Println(hex.EncodeToString(key))
+		return
+	case "status", "reencrypt":
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	batchSize := flags.Int("batch-size", payment_sync.DefaultReencryptionBatchSize, "configurations re-encrypted per query")
+	envFile := flags.String("env", ".env", "optional .env file to load")
+	_ = flags.Parse(os.Args[2:])
+
+	if err := godotenv.Load(*envFile); err != nil && !os.IsNotExist(err) {
+		log.Printf("Warning: Error loading %s: %v", *envFile, err)
+	}
+
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		stage = helpers.StageLocal
+	}
+	logger.InitLogger(stage)
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	ctx := context.Background()
+
+	secretsClient, err := awsclient.NewSecretsManagerClient(ctx)
+	if err != nil {
+		logger.Fatal("Failed to initialize AWS Secrets Manager client", zap.Error(err))
+	}
+
+	dsn, err := secretsClient.GetSecretString(ctx, "DATABASE_URL_ARN", "DATABASE_URL")
+	if err != nil || dsn == "" {
+		logger.Fatal("Failed to get DATABASE_URL", zap.Error(err))
+	}
+
+	connPool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		logger.Fatal("Unable to create connection pool", zap.Error(err))
+	}
+	defer connPool.Close()
+
+	paymentSyncEncryptionKey, err := secretsClient.GetSecretString(ctx, "PAYMENT_SYNC_ENCRYPTION_KEY_ARN", "PAYMENT_SYNC_ENCRYPTION_KEY")
+	if err != nil || paymentSyncEncryptionKey == "" {
+		logger.Fatal("Failed to get Payment Sync Encryption Key", zap.Error(err))
+	}
+	paymentSyncKeys, err := payment_sync.LoadKeyProvider(ctx, secretsClient, paymentSyncEncryptionKey)
+	if err != nil {
+		logger.Fatal("Failed to initialize Payment Sync key provider", zap.Error(err))
+	}
+	client := payment_sync.NewPaymentSyncClientWithKeyProvider(db.New(connPool), logger.Log, paymentSyncEncryptionKey, paymentSyncKeys)
+
+	if os.Args[1] == "reencrypt" {
+		result, err := client.ReencryptConfigurations(ctx, *batchSize)
+		if err != nil {
+			logger.Fatal("Re-encryption failed", zap.Error(err))
+		}
+		fmt.Printf("Re-encrypted %d of %d configurations and webhook secrets (%d skipped, %d failed)\n",
+			result.Reencrypted, result.Scanned, result.Skipped, result.Failed)
+		if result.Failed > 0 || result.Skipped > 0 {
+			fmt.Println("Run reencrypt again before retiring old keys.")
+		}
+	}
+
+	if err := printStatus(ctx, client); err != nil {
+		logger.Fatal("Failed to read key status", zap.Error(err))
+	}
+}
+
+// printStatus lists configuration and webhook secret counts per key version and whether each version can be decrypted
+func printStatus(ctx context.Context, client *payment_sync.PaymentSyncClient) error {
+	counts, err := client.KeyVersionCounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := client.CurrentKeyVersion()
+	fmt.Printf("Current key version: %s\n\n", current)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY VERSION\tCONFIGURATIONS\tWEBHOOK SECRETS\tDECRYPTABLE")
+	retirable := true
+	for _, count := range counts {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%t\n", count.KeyVersion, count.Configurations, count.WebhookSecrets, count.Available)
+		if count.KeyVersion != current {
+			retirable = false
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if retirable {
+		fmt.Println("\nAll configurations and webhook secrets use the current key; older keys can be removed.")
+	} else {
+		fmt.Println("\nSome configurations or webhook secrets still use older keys; keep them until reencrypt has finished.")
+	}
+
+	return nil
+}
@@ -298,7 +298,7 @@ func (h *AccountHandler) createWalletsForActiveNetworks(ctx *gin.Context, worksp
 		_, err = h.common.db.CreateWallet(ctx.Request.Context(), db.CreateWalletParams{
 			WorkspaceID:   workspaceID,
 			WalletType:    walletData.WalletType,
-			WalletAddress: walletData.WalletAddress,
+			WalletAddress: helpers.NormalizeWalletAddress(walletData.WalletAddress, string(network.NetworkType)),
 			NetworkType:   network.NetworkType,
 			NetworkID:     pgtype.UUID{Bytes: network.ID, Valid: true},
 			Nickname:      pgtype.Text{String: nickname, Valid: true},
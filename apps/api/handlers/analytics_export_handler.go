@@ -0,0 +1,459 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/requests"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AnalyticsExportHandler manages analytics export and scheduled report endpoints
+type AnalyticsExportHandler struct {
+	common  *CommonServices
+	service interfaces.AnalyticsExportService
+	logger  *zap.Logger
+}
+
+// NewAnalyticsExportHandler creates a handler with interface dependency
+func NewAnalyticsExportHandler(
+	common *CommonServices,
+	service interfaces.AnalyticsExportService,
+	logger *zap.Logger,
+) *AnalyticsExportHandler {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &AnalyticsExportHandler{
+		common:  common,
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Use types from the centralized packages
+type CreateAnalyticsExportRequest = requests.CreateAnalyticsExportRequest
+type AnalyticsReportScheduleRequest = requests.AnalyticsReportScheduleRequest
+type AnalyticsExportJobResponse = responses.AnalyticsExportJobResponse
+type AnalyticsReportScheduleResponse = responses.AnalyticsReportScheduleResponse
+
+// CreateAnalyticsExport queues an export of analytics datasets
+// @Summary Create an analytics export
+// @Description Queue an export of payments, invoices, subscriptions, MRR movements and gas fees for a date range as CSV or Parquet files. Amounts are also reported in the export currency. The export is queued and run by the subscription processor; poll the export until it is completed, then download its files.
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param export body CreateAnalyticsExportRequest true "Export"
+// @Success 202 {object} AnalyticsExportJobResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /analytics/exports [post]
+func (h *AnalyticsExportHandler) CreateAnalyticsExport(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	var req CreateAnalyticsExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid start_date format, expected YYYY-MM-DD", err)
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid end_date format, expected YYYY-MM-DD", err)
+		return
+	}
+
+	job, err := h.service.CreateExport(c.Request.Context(), params.CreateAnalyticsExportParams{
+		WorkspaceID: workspaceID,
+		Datasets:    req.Datasets,
+		Format:      req.Format,
+		Currency:    req.Currency,
+		StartDate:   startDate,
+		EndDate:     endDate.AddDate(0, 0, 1), // Include the whole end date
+	})
+	if err != nil {
+		h.handleExportError(c, err)
+		return
+	}
+
+	// The export stays queued until the subscription processor runs it
+
+	sendSuccess(c, http.StatusAccepted, toAnalyticsExportJobResponse(*job))
+}
+
+// ListAnalyticsExports lists a workspace's analytics exports
+// @Summary List analytics exports
+// @Description List analytics exports, newest first
+// @Tags Analytics
+// @Produce json
+// @Param limit query int false "Number of exports per page (max 100)"
+// @Param page query int false "Page number"
+// @Success 200 {object} PaginatedResponse{data=[]AnalyticsExportJobResponse}
+// @Failure 400 {object} ErrorResponse
+// @Router /analytics/exports [get]
+func (h *AnalyticsExportHandler) ListAnalyticsExports(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	pageParams, err := helpers.ParsePaginationParams(c)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	jobs, total, err := h.service.ListExports(c.Request.Context(), workspaceID, pageParams.Limit, pageParams.Offset)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list analytics exports", err)
+		return
+	}
+
+	items := make([]AnalyticsExportJobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		items = append(items, toAnalyticsExportJobResponse(job))
+	}
+	response := sendPaginatedSuccess(c, http.StatusOK, items, int(pageParams.Page), int(pageParams.Limit), int(total))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAnalyticsExport returns an analytics export and its files
+// @Summary Get an analytics export
+// @Description Get an analytics export's status and, once completed, its files
+// @Tags Analytics
+// @Produce json
+// @Param export_id path string true "Export ID"
+// @Success 200 {object} AnalyticsExportJobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /analytics/exports/{export_id} [get]
+func (h *AnalyticsExportHandler) GetAnalyticsExport(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+	exportID, err := uuid.Parse(c.Param("export_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid export ID format", err)
+		return
+	}
+
+	job, err := h.service.GetExport(c.Request.Context(), workspaceID, exportID)
+	if err != nil {
+		handleDBError(c, err, "Analytics export not found")
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toAnalyticsExportJobResponse(*job))
+}
+
+// DownloadAnalyticsExportFile downloads one dataset file of a completed export
+// @Summary Download an analytics export file
+// @Description Download the CSV or Parquet file for one dataset of a completed analytics export
+// @Tags Analytics
+// @Produce text/csv
+// @Produce application/vnd.apache.parquet
+// @Param export_id path string true "Export ID"
+// @Param dataset path string true "Dataset"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /analytics/exports/{export_id}/files/{dataset} [get]
+func (h *AnalyticsExportHandler) DownloadAnalyticsExportFile(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+	exportID, err := uuid.Parse(c.Param("export_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid export ID format", err)
+		return
+	}
+
+	body, file, err := h.service.OpenExportFile(c.Request.Context(), workspaceID, exportID, c.Param("dataset"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrExportNotReady):
+			sendError(c, http.StatusConflict, err.Error(), err)
+		case errors.Is(err, services.ErrExportFileNotFound):
+			sendError(c, http.StatusNotFound, "Export file not found", err)
+		default:
+			handleDBError(c, err, "Analytics export not found")
+		}
+		return
+	}
+	defer body.Close()
+
+	c.Header("Content-Type", file.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Filename))
+	c.Header("Content-Length", fmt.Sprintf("%d", file.SizeBytes))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, body); err != nil {
+		h.logger.Error("Failed to write analytics export file",
+			zap.String("export_id", exportID.String()),
+			zap.String("dataset", file.Dataset),
+			zap.Error(err))
+	}
+}
+
+// ListAnalyticsReportSchedules lists a workspace's scheduled reports
+// @Summary List scheduled analytics reports
+// @Description List the weekly and monthly analytics reports emailed to the workspace
+// @Tags Analytics
+// @Produce json
+// @Success 200 {array} AnalyticsReportScheduleResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /analytics/report-schedules [get]
+func (h *AnalyticsExportHandler) ListAnalyticsReportSchedules(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	schedules, err := h.service.ListReportSchedules(c.Request.Context(), workspaceID)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list report schedules", err)
+		return
+	}
+
+	items := make([]AnalyticsReportScheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		items = append(items, toAnalyticsReportScheduleResponse(schedule))
+	}
+	sendList(c, items)
+}
+
+// CreateAnalyticsReportSchedule schedules a recurring analytics report
+// @Summary Create a scheduled analytics report
+// @Description Email an export of the previous week (every Monday) or month (on the 1st) to the recipients, at midnight UTC
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param schedule body AnalyticsReportScheduleRequest true "Schedule"
+// @Success 201 {object} AnalyticsReportScheduleResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /analytics/report-schedules [post]
+func (h *AnalyticsExportHandler) CreateAnalyticsReportSchedule(c *gin.Context) {
+	scheduleParams, ok := h.parseScheduleRequest(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.CreateReportSchedule(c.Request.Context(), scheduleParams)
+	if err != nil {
+		h.handleExportError(c, err)
+		return
+	}
+
+	sendSuccess(c, http.StatusCreated, toAnalyticsReportScheduleResponse(*schedule))
+}
+
+// GetAnalyticsReportSchedule returns a scheduled report
+// @Summary Get a scheduled analytics report
+// @Tags Analytics
+// @Produce json
+// @Param schedule_id path string true "Schedule ID"
+// @Success 200 {object} AnalyticsReportScheduleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /analytics/report-schedules/{schedule_id} [get]
+func (h *AnalyticsExportHandler) GetAnalyticsReportSchedule(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+	scheduleID, err := uuid.Parse(c.Param("schedule_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid schedule ID format", err)
+		return
+	}
+
+	schedule, err := h.service.GetReportSchedule(c.Request.Context(), workspaceID, scheduleID)
+	if err != nil {
+		handleDBError(c, err, "Report schedule not found")
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toAnalyticsReportScheduleResponse(*schedule))
+}
+
+// UpdateAnalyticsReportSchedule replaces a scheduled report's settings
+// @Summary Update a scheduled analytics report
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param schedule_id path string true "Schedule ID"
+// @Param schedule body AnalyticsReportScheduleRequest true "Schedule"
+// @Success 200 {object} AnalyticsReportScheduleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /analytics/report-schedules/{schedule_id} [put]
+func (h *AnalyticsExportHandler) UpdateAnalyticsReportSchedule(c *gin.Context) {
+	scheduleID, err := uuid.Parse(c.Param("schedule_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid schedule ID format", err)
+		return
+	}
+
+	scheduleParams, ok := h.parseScheduleRequest(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.UpdateReportSchedule(c.Request.Context(), scheduleID, scheduleParams)
+	if err != nil {
+		h.handleExportError(c, err)
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toAnalyticsReportScheduleResponse(*schedule))
+}
+
+// DeleteAnalyticsReportSchedule removes a scheduled report
+// @Summary Delete a scheduled analytics report
+// @Tags Analytics
+// @Param schedule_id path string true "Schedule ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /analytics/report-schedules/{schedule_id} [delete]
+func (h *AnalyticsExportHandler) DeleteAnalyticsReportSchedule(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+	scheduleID, err := uuid.Parse(c.Param("schedule_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid schedule ID format", err)
+		return
+	}
+
+	if err := h.service.DeleteReportSchedule(c.Request.Context(), workspaceID, scheduleID); err != nil {
+		handleDBError(c, err, "Report schedule not found")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseScheduleRequest binds a schedule request to service parameters, writing the error response when it is invalid
+func (h *AnalyticsExportHandler) parseScheduleRequest(c *gin.Context) (params.AnalyticsReportScheduleParams, bool) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return params.AnalyticsReportScheduleParams{}, false
+	}
+
+	var req AnalyticsReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return params.AnalyticsReportScheduleParams{}, false
+	}
+
+	return params.AnalyticsReportScheduleParams{
+		WorkspaceID: workspaceID,
+		Name:        req.Name,
+		Frequency:   req.Frequency,
+		Datasets:    req.Datasets,
+		Format:      req.Format,
+		Currency:    req.Currency,
+		Recipients:  req.Recipients,
+		Active:      req.Active == nil || *req.Active,
+	}, true
+}
+
+// handleExportError maps export validation errors to HTTP responses
+func (h *AnalyticsExportHandler) handleExportError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidExport) {
+		sendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	handleDBError(c, err, "Report schedule not found")
+}
+
+// toAnalyticsExportJobResponse converts an export job to a response
+func toAnalyticsExportJobResponse(job db.AnalyticsExportJob) AnalyticsExportJobResponse {
+	resp := AnalyticsExportJobResponse{
+		ID:          job.ID.String(),
+		Object:      "analytics_export",
+		Datasets:    job.Datasets,
+		Format:      job.Format,
+		Currency:    job.FiatCurrency,
+		StartDate:   job.StartDate.Time.UTC().Format("2006-01-02"),
+		EndDate:     job.EndDate.Time.UTC().AddDate(0, 0, -1).Format("2006-01-02"),
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		Files:       []responses.AnalyticsExportFileResponse{},
+		StartedAt:   optionalUnix(job.StartedAt),
+		CompletedAt: optionalUnix(job.CompletedAt),
+		CreatedAt:   job.CreatedAt.Time.Unix(),
+	}
+	if job.ReportScheduleID.Valid {
+		scheduleID := uuid.UUID(job.ReportScheduleID.Bytes).String()
+		resp.ReportScheduleID = &scheduleID
+	}
+	if job.ErrorMessage.Valid {
+		resp.ErrorMessage = job.ErrorMessage.String
+	}
+
+	files, err := services.ExportFiles(job)
+	if err != nil {
+		return resp
+	}
+	for _, file := range files {
+		resp.Files = append(resp.Files, responses.AnalyticsExportFileResponse{
+			Dataset:     file.Dataset,
+			Filename:    file.Filename,
+			ContentType: file.ContentType,
+			RowCount:    file.RowCount,
+			SizeBytes:   file.SizeBytes,
+			DownloadURL: fmt.Sprintf("/api/v1/analytics/exports/%s/files/%s", job.ID, file.Dataset),
+		})
+	}
+	return resp
+}
+
+// toAnalyticsReportScheduleResponse converts a report schedule to a response
+func toAnalyticsReportScheduleResponse(schedule db.AnalyticsReportSchedule) AnalyticsReportScheduleResponse {
+	return AnalyticsReportScheduleResponse{
+		ID:         schedule.ID.String(),
+		Object:     "analytics_report_schedule",
+		Name:       schedule.Name,
+		Frequency:  schedule.Frequency,
+		Datasets:   schedule.Datasets,
+		Format:     schedule.Format,
+		Currency:   schedule.FiatCurrency.String,
+		Recipients: schedule.Recipients,
+		Active:     schedule.Active,
+		NextRunAt:  schedule.NextRunAt.Time.Unix(),
+		LastRunAt:  optionalUnix(schedule.LastRunAt),
+		CreatedAt:  schedule.CreatedAt.Time.Unix(),
+		UpdatedAt:  schedule.UpdatedAt.Time.Unix(),
+	}
+}
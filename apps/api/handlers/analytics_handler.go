@@ -3,11 +3,14 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -17,24 +20,27 @@ import (
 
 // AnalyticsHandler manages analytics and dashboard endpoints
 type AnalyticsHandler struct {
-	common  *CommonServices
-	service interfaces.AnalyticsService
-	logger  *zap.Logger
+	common        *CommonServices
+	service       interfaces.AnalyticsService
+	gasFeeService interfaces.GasFeeService
+	logger        *zap.Logger
 }
 
 // NewAnalyticsHandler creates a handler with interface dependencies
 func NewAnalyticsHandler(
 	common *CommonServices,
 	service interfaces.AnalyticsService,
+	gasFeeService interfaces.GasFeeService,
 	logger *zap.Logger,
 ) *AnalyticsHandler {
 	if logger == nil {
 		logger = zap.L()
 	}
 	return &AnalyticsHandler{
-		common:  common,
-		service: service,
-		logger:  logger,
+		common:        common,
+		service:       service,
+		gasFeeService: gasFeeService,
+		logger:        logger,
 	}
 }
 
@@ -559,6 +565,205 @@ func (h *AnalyticsHandler) GetGasFeePieChart(c *gin.Context) {
 	c.JSON(http.StatusOK, pieChart)
 }
 
+// GetGasFeeEstimateAccuracy compares gas fee estimates with actual fees
+// @Summary Get gas fee estimate accuracy
+// @Description Compare gas fee estimates made at payment creation with the actual fees paid, per network and estimate source
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param days query int false "Number of days to include (default: 30)"
+// @Success 200 {array} business.GasFeeEstimateAccuracy
+// @Router /api/v1/analytics/gas-fee-accuracy [get]
+func (h *AnalyticsHandler) GetGasFeeEstimateAccuracy(c *gin.Context) {
+	workspaceIDStr := c.GetHeader("X-Workspace-ID")
+	if workspaceIDStr == "" {
+		sendError(c, http.StatusBadRequest, "X-Workspace-ID header is required", nil)
+		return
+	}
+	workspaceID, err := uuid.Parse(workspaceIDStr)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID", nil)
+		return
+	}
+
+	if h.gasFeeService == nil {
+		sendError(c, http.StatusInternalServerError, "Gas fee service not initialized", nil)
+		return
+	}
+
+	daysStr := c.DefaultQuery("days", "30")
+	days, _ := strconv.Atoi(daysStr)
+	if days <= 0 {
+		days = 30
+	}
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -days)
+
+	accuracy, err := h.gasFeeService.GetGasFeeEstimateAccuracy(c.Request.Context(), workspaceID, startDate, endDate)
+	if err != nil {
+		handleDBError(c, err, "Failed to get gas fee estimate accuracy")
+		return
+	}
+
+	c.JSON(http.StatusOK, accuracy)
+}
+
+// maxCohortMonths bounds the cohort window calculated on demand
+const maxCohortMonths = 36
+
+// GetCohortAnalysis returns logo and net revenue retention cohorts
+// @Summary Get cohort retention analysis
+// @Description Get logo retention and net revenue retention matrices for customer cohorts grouped by signup month, product or network
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param cohort_by query string false "Cohort dimension: month, product, network (default: month)"
+// @Param months query int false "Number of months of cohorts to include (default: 12, max: 36)"
+// @Param currency query string false "Currency code (default: workspace default currency)"
+// @Success 200 {object} business.CohortAnalysis
+// @Router /api/v1/analytics/cohorts [get]
+func (h *AnalyticsHandler) GetCohortAnalysis(c *gin.Context) {
+	workspaceIDStr := c.GetHeader("X-Workspace-ID")
+	if workspaceIDStr == "" {
+		sendError(c, http.StatusBadRequest, "X-Workspace-ID header is required", nil)
+		return
+	}
+	workspaceID, err := uuid.Parse(workspaceIDStr)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID", nil)
+		return
+	}
+
+	// Check if service is initialized
+	if !h.checkService(c) {
+		return
+	}
+
+	cohortBy := c.DefaultQuery("cohort_by", business.CohortByMonth)
+	switch cohortBy {
+	case business.CohortByMonth, business.CohortByProduct, business.CohortByNetwork:
+	default:
+		sendError(c, http.StatusBadRequest, "cohort_by must be one of month, product, network", nil)
+		return
+	}
+
+	monthsStr := c.DefaultQuery("months", "12")
+	months, _ := strconv.Atoi(monthsStr)
+	if months <= 0 {
+		months = 12
+	}
+	if months > maxCohortMonths {
+		months = maxCohortMonths
+	}
+
+	currency := c.Query("currency")
+
+	analysis, err := h.service.GetCohortAnalysis(c.Request.Context(), workspaceID, cohortBy, months, currency)
+	if err != nil {
+		handleDBError(c, err, "Failed to get cohort analysis")
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// maxMRRMovementsPageSize bounds the number of movements returned per page
+const maxMRRMovementsPageSize = 200
+
+// GetMRRMovements returns the MRR movements ledger
+// @Summary Get MRR movements
+// @Description Get new, expansion, contraction, churn and reactivation MRR for a date range, with a page of the subscription changes behind them
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param start_date query string false "Start date, YYYY-MM-DD (default: 30 days before end_date)"
+// @Param end_date query string false "End date, inclusive, YYYY-MM-DD (default: today)"
+// @Param type query string false "Movement type: new, expansion, contraction, churn, reactivation"
+// @Param currency query string false "Reporting currency code (default: workspace default currency)"
+// @Param limit query int false "Number of movements to return (default: 50, max: 200)"
+// @Param offset query int false "Number of movements to skip"
+// @Success 200 {object} business.MRRMovementReport
+// @Router /api/v1/analytics/mrr-movements [get]
+func (h *AnalyticsHandler) GetMRRMovements(c *gin.Context) {
+	workspaceIDStr := c.GetHeader("X-Workspace-ID")
+	if workspaceIDStr == "" {
+		sendError(c, http.StatusBadRequest, "X-Workspace-ID header is required", nil)
+		return
+	}
+	workspaceID, err := uuid.Parse(workspaceIDStr)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID", nil)
+		return
+	}
+
+	// Check if service is initialized
+	if !h.checkService(c) {
+		return
+	}
+
+	endDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		endDate, err = time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid end_date format, expected YYYY-MM-DD", err)
+			return
+		}
+	}
+
+	startDate := endDate.AddDate(0, 0, -30)
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		startDate, err = time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid start_date format, expected YYYY-MM-DD", err)
+			return
+		}
+	}
+
+	if endDate.Before(startDate) {
+		sendError(c, http.StatusBadRequest, "end_date must not be before start_date", nil)
+		return
+	}
+
+	movementType := c.Query("type")
+	if movementType != "" && !slices.Contains(business.MRRMovementTypes, movementType) {
+		sendError(c, http.StatusBadRequest, "type must be one of new, expansion, contraction, churn, reactivation", nil)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > maxMRRMovementsPageSize {
+		limit = maxMRRMovementsPageSize
+	}
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	report, err := h.service.GetMRRMovements(c.Request.Context(), params.ListMRRMovementsParams{
+		WorkspaceID: workspaceID,
+		Currency:    c.Query("currency"),
+		StartDate:   startDate,
+		// end_date is inclusive
+		EndDate:      endDate.AddDate(0, 0, 1),
+		MovementType: movementType,
+		Limit:        int32(limit),
+		Offset:       int32(offset),
+	})
+	if err != nil {
+		handleDBError(c, err, "Failed to get MRR movements")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // GetHourlyMetrics returns hourly metrics for today
 // @Summary Get hourly metrics
 // @Description Get metrics broken down by hour for today
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/cyphera/cyphera-api/libs/go/helpers"
 	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/services"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/requests"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
@@ -38,6 +40,9 @@ func NewAPIKeyHandler(common *CommonServices, logger *zap.Logger) *APIKeyHandler
 // Use types from the centralized packages
 type CreateAPIKeyRequest = requests.CreateAPIKeyRequest
 type UpdateAPIKeyRequest = requests.UpdateAPIKeyRequest
+type RotateAPIKeyRequest = requests.RotateAPIKeyRequest
+type RotateAPIKeyResponse = responses.RotateAPIKeyResponse
+type ListAPIKeyUsageResponse = responses.ListAPIKeyUsageResponse
 type APIKeyResponse = responses.APIKeyResponse
 type ListAPIKeysResponse = responses.ListAPIKeysResponse
 
@@ -54,6 +59,8 @@ func convertAPIKeyResponse(helperResponse responses.APIKeyResponse) APIKeyRespon
 		CreatedAt:   helperResponse.CreatedAt,
 		UpdatedAt:   helperResponse.UpdatedAt,
 		KeyPrefix:   helperResponse.KeyPrefix,
+		RotatedFrom: helperResponse.RotatedFrom,
+		RotatedAt:   helperResponse.RotatedAt,
 	}
 }
 
@@ -285,4 +292,152 @@ func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
 	sendSuccess(c, http.StatusNoContent, nil)
 }
 
+// RotateAPIKey godoc
+// @Summary Rotate an API key
+// @Description Issues a new secret for an API key. The old key stays valid for the grace period so clients can switch over without downtime.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param api_key_id path string true "API Key ID"
+// @Param body body RotateAPIKeyRequest false "Rotation options"
+// @Success 201 {object} RotateAPIKeyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api-keys/{api_key_id}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	workspaceID := c.GetHeader("X-Workspace-ID")
+	parsedWorkspaceID, err := uuid.Parse(workspaceID)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	apiKeyId := c.Param("api_key_id")
+	parsedUUID, err := uuid.Parse(apiKeyId)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	// The body is optional; an empty body rotates with the default grace period
+	var req RotateAPIKeyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	gracePeriod := services.DefaultAPIKeyRotationGracePeriod
+	if req.GracePeriodSeconds != nil {
+		gracePeriod = time.Duration(*req.GracePeriodSeconds) * time.Second
+	}
+
+	newAPIKey, fullKey, err := h.apiKeyService.RotateAPIKey(c.Request.Context(), params.RotateAPIKeyParams{
+		ID:          parsedUUID,
+		WorkspaceID: parsedWorkspaceID,
+		GracePeriod: gracePeriod,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		handleDBError(c, err, "API key not found or cannot be rotated")
+		return
+	}
+
+	// Include the full key in the response (only time it's shown)
+	keyResponse := convertAPIKeyResponse(helpers.ToAPIKeyResponse(newAPIKey))
+	keyResponse.Key = fullKey
+
+	response := RotateAPIKeyResponse{
+		Object: "api_key_rotation",
+		APIKey: keyResponse,
+	}
+
+	// Report when the previous key stops working
+	if previousKey, err := h.apiKeyService.GetAPIKey(c.Request.Context(), parsedUUID, parsedWorkspaceID); err == nil && previousKey.ExpiresAt.Valid {
+		expiresAt := previousKey.ExpiresAt.Time.Unix()
+		response.PreviousKeyExpiresAt = &expiresAt
+	}
+
+	sendSuccess(c, http.StatusCreated, response)
+}
+
+// GetAPIKeyUsage godoc
+// @Summary Get API key usage
+// @Description Retrieves daily request counts, last IP and last user agent for an API key
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param api_key_id path string true "API Key ID"
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to 30 days ago"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} ListAPIKeyUsageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api-keys/{api_key_id}/usage [get]
+func (h *APIKeyHandler) GetAPIKeyUsage(c *gin.Context) {
+	workspaceID := c.GetHeader("X-Workspace-ID")
+	parsedWorkspaceID, err := uuid.Parse(workspaceID)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	apiKeyId := c.Param("api_key_id")
+	parsedUUID, err := uuid.Parse(apiKeyId)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid UUID format", err)
+		return
+	}
+
+	endDate := time.Now().UTC()
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		endDate, err = time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid end_date format, expected YYYY-MM-DD", err)
+			return
+		}
+	}
+
+	startDate := endDate.AddDate(0, 0, -30)
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		startDate, err = time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid start_date format, expected YYYY-MM-DD", err)
+			return
+		}
+	}
+
+	if endDate.Before(startDate) {
+		sendError(c, http.StatusBadRequest, "end_date must not be before start_date", nil)
+		return
+	}
+
+	usage, err := h.apiKeyService.ListAPIKeyUsage(c.Request.Context(), parsedUUID, parsedWorkspaceID, startDate, endDate)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to retrieve API key usage", err)
+		return
+	}
+
+	response := ListAPIKeyUsageResponse{
+		Object: "list",
+		Data:   make([]responses.APIKeyUsageResponse, len(usage)),
+	}
+	for i, day := range usage {
+		response.Data[i] = responses.APIKeyUsageResponse{
+			Object:        "api_key_usage",
+			Date:          day.UsageDate.Time.Format("2006-01-02"),
+			RequestCount:  day.RequestCount,
+			LastIP:        day.LastIp.String,
+			LastUserAgent: day.LastUserAgent.String,
+			LastUsedAt:    day.LastUsedAt.Time.Unix(),
+		}
+		response.TotalRequests += day.RequestCount
+	}
+
+	sendSuccess(c, http.StatusOK, response)
+}
+
 // Helper functions moved to helpers.ToAPIKeyResponse and services.APIKeyService
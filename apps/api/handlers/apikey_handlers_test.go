@@ -936,6 +936,152 @@ func TestAPIKeyHandler_DeleteAPIKey(t *testing.T) {
 	}
 }
 
+func TestAPIKeyHandler_RotateAPIKey(t *testing.T) {
+	workspaceID := uuid.New()
+	apiKeyID := uuid.New()
+	newAPIKeyID := uuid.New()
+	now := time.Now()
+
+	rotatedKey := db.ApiKey{
+		ID:            newAPIKeyID,
+		WorkspaceID:   workspaceID,
+		Name:          "Production Key",
+		AccessLevel:   db.ApiKeyLevelWrite,
+		KeyPrefix:     pgtype.Text{String: "cyk_newkey12", Valid: true},
+		Metadata:      []byte(`{}`),
+		CreatedAt:     pgtype.Timestamptz{Time: now, Valid: true},
+		UpdatedAt:     pgtype.Timestamptz{Time: now, Valid: true},
+		RotatedFromID: pgtype.UUID{Bytes: apiKeyID, Valid: true},
+	}
+	previousKey := db.ApiKey{
+		ID:          apiKeyID,
+		WorkspaceID: workspaceID,
+		Name:        "Production Key",
+		AccessLevel: db.ApiKeyLevelWrite,
+		ExpiresAt:   pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+		RotatedAt:   pgtype.Timestamptz{Time: now, Valid: true},
+		Metadata:    []byte(`{}`),
+	}
+	gracePeriod := int64(3600)
+
+	tests := []struct {
+		name           string
+		workspaceID    string
+		apiKeyID       string
+		requestBody    interface{}
+		setupMocks     func(*mocks.MockAPIKeyService)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:        "successfully rotates API key with default grace period",
+			workspaceID: workspaceID.String(),
+			apiKeyID:    apiKeyID.String(),
+			setupMocks: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().
+					RotateAPIKey(gomock.Any(), params.RotateAPIKeyParams{
+						ID:          apiKeyID,
+						WorkspaceID: workspaceID,
+						GracePeriod: 24 * time.Hour,
+					}).
+					Return(rotatedKey, "cyk_newkey12_full", nil)
+				mockService.EXPECT().
+					GetAPIKey(gomock.Any(), apiKeyID, workspaceID).
+					Return(previousKey, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:        "successfully rotates API key with custom grace period",
+			workspaceID: workspaceID.String(),
+			apiKeyID:    apiKeyID.String(),
+			requestBody: handlers.RotateAPIKeyRequest{GracePeriodSeconds: &gracePeriod},
+			setupMocks: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().
+					RotateAPIKey(gomock.Any(), params.RotateAPIKeyParams{
+						ID:          apiKeyID,
+						WorkspaceID: workspaceID,
+						GracePeriod: time.Hour,
+					}).
+					Return(rotatedKey, "cyk_newkey12_full", nil)
+				mockService.EXPECT().
+					GetAPIKey(gomock.Any(), apiKeyID, workspaceID).
+					Return(previousKey, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "fails with invalid workspace ID",
+			workspaceID:    "invalid-uuid",
+			apiKeyID:       apiKeyID.String(),
+			setupMocks:     func(mockService *mocks.MockAPIKeyService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid workspace ID format",
+		},
+		{
+			name:           "fails with invalid API key ID",
+			workspaceID:    workspaceID.String(),
+			apiKeyID:       "invalid-uuid",
+			setupMocks:     func(mockService *mocks.MockAPIKeyService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid UUID format",
+		},
+		{
+			name:        "fails when API key not found or already rotated",
+			workspaceID: workspaceID.String(),
+			apiKeyID:    apiKeyID.String(),
+			setupMocks: func(mockService *mocks.MockAPIKeyService) {
+				mockService.EXPECT().
+					RotateAPIKey(gomock.Any(), gomock.Any()).
+					Return(db.ApiKey{}, "", pgx.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "API key not found or cannot be rotated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService, ctrl := setupAPIKeyHandler(t)
+			defer ctrl.Finish()
+
+			tt.setupMocks(mockService)
+
+			headers := map[string]string{}
+			if tt.workspaceID != "" {
+				headers["X-Workspace-ID"] = tt.workspaceID
+			}
+
+			c, w := createTestContext(http.MethodPost, fmt.Sprintf("/api-keys/%s/rotate", tt.apiKeyID), tt.requestBody, headers)
+			c.Params = gin.Params{
+				gin.Param{Key: "api_key_id", Value: tt.apiKeyID},
+			}
+
+			handler.RotateAPIKey(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedError != "" {
+				var errorResponse handlers.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &errorResponse)
+				require.NoError(t, err)
+				assert.Contains(t, errorResponse.Error, tt.expectedError)
+			}
+
+			if tt.expectedStatus == http.StatusCreated {
+				var response handlers.RotateAPIKeyResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, newAPIKeyID.String(), response.APIKey.ID)
+				assert.Equal(t, "cyk_newkey12_full", response.APIKey.Key)
+				assert.Equal(t, apiKeyID.String(), response.APIKey.RotatedFrom)
+				require.NotNil(t, response.PreviousKeyExpiresAt)
+				assert.Equal(t, previousKey.ExpiresAt.Time.Unix(), *response.PreviousKeyExpiresAt)
+			}
+		})
+	}
+}
+
 func TestAPIKeyHandler_EdgeCases(t *testing.T) {
 	handler, mockService, ctrl := setupAPIKeyHandler(t)
 	defer ctrl.Finish()
@@ -1224,6 +1224,8 @@ func (h *CircleHandler) createCypheraWalletEntry(ctx context.Context, qtx *db.Qu
 
 	spew.Dump(walletData)
 
+	walletData.Address = helpers.NormalizeWalletAddress(walletData.Address, string(getNetworkType(walletData.Blockchain)))
+
 	// Check if wallet already exists in our database
 	dbWallet, err := h.common.db.GetWalletByAddressAndCircleNetworkType(ctx, db.GetWalletByAddressAndCircleNetworkTypeParams{
 		WalletAddress:     walletData.Address,
@@ -1405,6 +1407,8 @@ func (h *CircleHandler) GetWallet(c *gin.Context) {
 	err = helpers.WithTransaction(c.Request.Context(), pool, func(tx pgx.Tx) error {
 		qtx := h.common.WithTx(tx)
 
+		walletData.Address = helpers.NormalizeWalletAddress(walletData.Address, string(getNetworkType(walletData.Blockchain)))
+
 		// Check if wallet already exists in our database
 		dbWallet, err := h.common.db.GetWalletByAddressAndCircleNetworkType(c.Request.Context(), db.GetWalletByAddressAndCircleNetworkTypeParams{
 			WalletAddress:     walletData.Address,
@@ -1623,6 +1627,8 @@ func (h *CircleHandler) GetWalletBalance(c *gin.Context) {
 		networkIDPgType.Valid = true
 	}
 
+	walletData.Address = helpers.NormalizeWalletAddress(walletData.Address, string(getNetworkType(walletData.Blockchain)))
+
 	// Check if this wallet already exists in our database
 	_, err = h.common.db.GetWalletByAddressAndCircleNetworkType(c.Request.Context(), db.GetWalletByAddressAndCircleNetworkTypeParams{
 		WalletAddress:     walletData.Address,
@@ -1872,6 +1878,8 @@ func (h *CircleHandler) ListWallets(c *gin.Context) {
 			return
 		}
 
+		walletData.Address = helpers.NormalizeWalletAddress(walletData.Address, string(getNetworkType(walletData.Blockchain)))
+
 		// Check if wallet already exists in our database
 		dbWallet, err := h.common.db.GetWalletByAddressAndCircleNetworkType(c.Request.Context(), db.GetWalletByAddressAndCircleNetworkTypeParams{
 			WalletAddress:     walletData.Address,
@@ -29,6 +29,7 @@ type CommonServices struct {
 	db                        db.Querier
 	dbPool                    *pgxpool.Pool // Optional: for transaction support
 	cypheraSmartWalletAddress string
+	solanaDelegateAddress     string
 	CMCClient                 *coinmarketcap.Client
 	CMCAPIKey                 string
 	APIKeyService             interfaces.APIKeyService
@@ -52,6 +53,7 @@ type CommonServicesConfig struct {
 	DB                        db.Querier
 	DBPool                    *pgxpool.Pool // Optional: for transaction support
 	CypheraSmartWalletAddress string
+	SolanaDelegateAddress     string // Optional: the delegate Solana customers approve on their token accounts
 	CMCClient                 *coinmarketcap.Client
 	CMCAPIKey                 string
 	APIKeyService             interfaces.APIKeyService
@@ -73,6 +75,7 @@ func NewCommonServices(config CommonServicesConfig) *CommonServices {
 		db:                        config.DB,
 		dbPool:                    config.DBPool,
 		cypheraSmartWalletAddress: config.CypheraSmartWalletAddress,
+		solanaDelegateAddress:     config.SolanaDelegateAddress,
 		CMCClient:                 config.CMCClient,
 		CMCAPIKey:                 config.CMCAPIKey,
 		APIKeyService:             config.APIKeyService,
@@ -86,15 +89,15 @@ func NewCommonServices(config CommonServicesConfig) *CommonServices {
 }
 
 // NewCommonServicesWithPool creates CommonServices with database pool for transaction support
-// This is the recommended constructor when you need transaction support
-func NewCommonServicesWithPool(db *db.Queries, pool *pgxpool.Pool, cypheraSmartWalletAddress string, cmcClient *coinmarketcap.Client, cmcAPIKey string) *CommonServices {
+// This is the recommended constructor when you need transaction support. The tax service is passed in
+// so that it is the one payments use, with the same tax provider and VAT number verification.
+func NewCommonServicesWithPool(db *db.Queries, pool *pgxpool.Pool, cypheraSmartWalletAddress string, cmcClient *coinmarketcap.Client, cmcAPIKey string, taxService interfaces.TaxService) *CommonServices {
 	// Initialize logger
 	log := logger.Log
 
 	// Initialize services
 	currencyService := services.NewCurrencyService(db)
 	exchangeRateService := services.NewExchangeRateService(db, cmcAPIKey)
-	taxService := services.NewTaxService(db)
 	discountService := services.NewDiscountService(db)
 	gasSponsorshipService := services.NewGasSponsorshipService(db)
 
@@ -206,6 +209,12 @@ func (s *CommonServices) GetCypheraSmartWalletAddress() string {
 	return s.cypheraSmartWalletAddress
 }
 
+// GetSolanaDelegateAddress returns the Solana payment delegate address, or an empty string when Solana
+// payments are not configured
+func (s *CommonServices) GetSolanaDelegateAddress() string {
+	return s.solanaDelegateAddress
+}
+
 // GetLogger returns the logger
 func (s *CommonServices) GetLogger() *zap.Logger {
 	return s.logger
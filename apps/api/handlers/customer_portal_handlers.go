@@ -0,0 +1,860 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/constants"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/requests"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// CustomerPortalHandler handles the customer self-service portal and the merchant
+// endpoints that configure it and issue portal links
+type CustomerPortalHandler struct {
+	common        *CommonServices
+	portalService interfaces.CustomerPortalService
+	logger        *zap.Logger
+}
+
+// NewCustomerPortalHandler creates a new customer portal handler
+func NewCustomerPortalHandler(
+	common *CommonServices,
+	portalService interfaces.CustomerPortalService,
+	logger *zap.Logger,
+) *CustomerPortalHandler {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &CustomerPortalHandler{
+		common:        common,
+		portalService: portalService,
+		logger:        logger,
+	}
+}
+
+// Use types from the centralized packages
+type CreateCustomerPortalSessionRequest = requests.CreateCustomerPortalSessionRequest
+type UpdateCustomerPortalSettingsRequest = requests.UpdateCustomerPortalSettingsRequest
+type UpdateCustomerBillingDetailsRequest = requests.UpdateCustomerBillingDetailsRequest
+type SwitchSubscriptionWalletRequest = requests.SwitchSubscriptionWalletRequest
+type ReauthorizeSubscriptionRequest = requests.ReauthorizeSubscriptionRequest
+type CustomerPortalSessionResponse = responses.CustomerPortalSessionResponse
+type CustomerPortalSettingsResponse = responses.CustomerPortalSettingsResponse
+type CustomerPortalProfileResponse = responses.CustomerPortalProfileResponse
+type CustomerPortalSubscriptionResponse = responses.CustomerPortalSubscriptionResponse
+type CustomerPortalInvoiceResponse = responses.CustomerPortalInvoiceResponse
+type CustomerPortalListResponse = responses.CustomerPortalListResponse
+
+// CreatePortalSession godoc
+// @Summary Create a customer portal link
+// @Description Creates a short-lived link that lets a customer manage their subscriptions with this workspace. The token is only returned once.
+// @Tags customer-portal
+// @Accept json
+// @Produce json
+// @Param request body CreateCustomerPortalSessionRequest true "Portal session details"
+// @Success 201 {object} CustomerPortalSessionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /customer-portal/sessions [post]
+func (h *CustomerPortalHandler) CreatePortalSession(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	var req CreateCustomerPortalSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	createParams := params.CreateCustomerPortalSessionParams{
+		WorkspaceID: workspaceID,
+		CustomerID:  uuid.MustParse(req.CustomerID),
+		ReturnURL:   req.ReturnURL,
+	}
+	if req.ExpiresInSeconds != nil {
+		createParams.TTL = time.Duration(*req.ExpiresInSeconds) * time.Second
+	}
+	if apiKeyID, err := uuid.Parse(c.GetString("apiKeyID")); err == nil {
+		createParams.CreatedByAPIKeyID = &apiKeyID
+	}
+	if userID, err := uuid.Parse(c.GetString("userID")); err == nil {
+		createParams.CreatedByUserID = &userID
+	}
+
+	session, token, err := h.portalService.CreateSession(c.Request.Context(), createParams)
+	if err != nil {
+		h.handlePortalError(c, err, "Customer not found")
+		return
+	}
+
+	response := toCustomerPortalSessionResponse(session)
+	response.Token = token
+	response.URL = h.portalService.SessionURL(token)
+	sendSuccess(c, http.StatusCreated, response)
+}
+
+// RevokePortalSession godoc
+// @Summary Revoke a customer portal link
+// @Description Ends a customer portal session before it expires
+// @Tags customer-portal
+// @Produce json
+// @Param session_id path string true "Portal session ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /customer-portal/sessions/{session_id} [delete]
+func (h *CustomerPortalHandler) RevokePortalSession(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid session ID format", err)
+		return
+	}
+
+	if err := h.portalService.RevokeSession(c.Request.Context(), sessionID, workspaceID); err != nil {
+		h.handlePortalError(c, err, "Portal session not found")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetPortalSettings godoc
+// @Summary Get customer portal settings
+// @Description Returns which actions customers may take in the portal and the defaults for portal links
+// @Tags customer-portal
+// @Produce json
+// @Success 200 {object} CustomerPortalSettingsResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /customer-portal/settings [get]
+func (h *CustomerPortalHandler) GetPortalSettings(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	settings, err := h.portalService.GetSettings(c.Request.Context(), workspaceID)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to get customer portal settings", err)
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toCustomerPortalSettingsResponse(settings))
+}
+
+// UpdatePortalSettings godoc
+// @Summary Update customer portal settings
+// @Description Enables or disables portal actions such as cancelling, pausing or switching wallets
+// @Tags customer-portal
+// @Accept json
+// @Produce json
+// @Param request body UpdateCustomerPortalSettingsRequest true "Settings to change"
+// @Success 200 {object} CustomerPortalSettingsResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /customer-portal/settings [put]
+func (h *CustomerPortalHandler) UpdatePortalSettings(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	var req UpdateCustomerPortalSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	updateParams := params.UpdateCustomerPortalSettingsParams{
+		WorkspaceID:        workspaceID,
+		AllowCancel:        req.AllowCancel,
+		AllowPause:         req.AllowPause,
+		AllowResume:        req.AllowResume,
+		AllowUpdateBilling: req.AllowUpdateBilling,
+		AllowWalletSwitch:  req.AllowWalletSwitch,
+		DefaultReturnURL:   req.DefaultReturnURL,
+	}
+	if req.SessionTTLSeconds != nil {
+		ttl := time.Duration(*req.SessionTTLSeconds) * time.Second
+		updateParams.SessionTTL = &ttl
+	}
+
+	settings, err := h.portalService.UpdateSettings(c.Request.Context(), updateParams)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to update customer portal settings", err)
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toCustomerPortalSettingsResponse(settings))
+}
+
+// GetMe godoc
+// @Summary Get the signed-in customer
+// @Description Returns the customer's profile, billing details and wallets. Portal links also return what the merchant allows.
+// @Tags portal
+// @Produce json
+// @Success 200 {object} CustomerPortalProfileResponse
+// @Failure 401 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/me [get]
+func (h *CustomerPortalHandler) GetMe(c *gin.Context) {
+	scope, ok := h.portalScope(c)
+	if !ok {
+		return
+	}
+
+	customer, wallets, err := h.portalService.GetCustomer(c.Request.Context(), scope)
+	if err != nil {
+		h.handlePortalError(c, err, "Customer not found")
+		return
+	}
+
+	response := CustomerPortalProfileResponse{
+		Customer: helpers.ToCustomerResponse(customer),
+		Billing:  toCustomerBillingDetailsResponse(customer),
+		Wallets:  make([]responses.CustomerWalletResponse, len(wallets)),
+	}
+	for i, wallet := range wallets {
+		response.Wallets[i] = helpers.ToCustomerWalletResponse(wallet)
+	}
+
+	if scope.WorkspaceID != nil {
+		settings, err := h.portalService.GetSettings(c.Request.Context(), *scope.WorkspaceID)
+		if err != nil {
+			sendError(c, http.StatusInternalServerError, "Failed to get customer portal settings", err)
+			return
+		}
+		permissions := services.PermissionsFromSettings(settings)
+		response.Permissions = &permissions
+	}
+
+	sendSuccess(c, http.StatusOK, response)
+}
+
+// UpdateBillingDetails godoc
+// @Summary Update billing details
+// @Description Updates the signed-in customer's name, contact details and billing address
+// @Tags portal
+// @Accept json
+// @Produce json
+// @Param request body UpdateCustomerBillingDetailsRequest true "Billing fields to change"
+// @Success 200 {object} CustomerPortalProfileResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/me/billing [patch]
+func (h *CustomerPortalHandler) UpdateBillingDetails(c *gin.Context) {
+	scope, ok := h.portalScope(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateCustomerBillingDetailsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	customer, err := h.portalService.UpdateBillingDetails(c.Request.Context(), params.UpdateCustomerBillingDetailsParams{
+		Scope:             scope,
+		Name:              req.Name,
+		Email:             req.Email,
+		Phone:             req.Phone,
+		BillingCountry:    req.BillingCountry,
+		BillingState:      req.BillingState,
+		BillingCity:       req.BillingCity,
+		BillingPostalCode: req.BillingPostalCode,
+	})
+	if err != nil {
+		h.handlePortalError(c, err, "Customer not found")
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, CustomerPortalProfileResponse{
+		Customer: helpers.ToCustomerResponse(customer),
+		Billing:  toCustomerBillingDetailsResponse(customer),
+	})
+}
+
+// ListWallets godoc
+// @Summary List the customer's wallets
+// @Description Lists the wallets the signed-in customer can pay from
+// @Tags portal
+// @Produce json
+// @Success 200 {object} CustomerPortalListResponse
+// @Failure 401 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/wallets [get]
+func (h *CustomerPortalHandler) ListWallets(c *gin.Context) {
+	scope, ok := h.portalScope(c)
+	if !ok {
+		return
+	}
+
+	_, wallets, err := h.portalService.GetCustomer(c.Request.Context(), scope)
+	if err != nil {
+		h.handlePortalError(c, err, "Customer not found")
+		return
+	}
+
+	data := make([]responses.CustomerWalletResponse, len(wallets))
+	for i, wallet := range wallets {
+		data[i] = helpers.ToCustomerWalletResponse(wallet)
+	}
+
+	sendSuccess(c, http.StatusOK, CustomerPortalListResponse{Object: "list", Data: data})
+}
+
+// ListSubscriptions godoc
+// @Summary List the customer's subscriptions
+// @Description Lists the signed-in customer's subscriptions and the actions each merchant allows
+// @Tags portal
+// @Produce json
+// @Success 200 {object} CustomerPortalListResponse
+// @Failure 401 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/subscriptions [get]
+func (h *CustomerPortalHandler) ListSubscriptions(c *gin.Context) {
+	scope, ok := h.portalScope(c)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.portalService.ListSubscriptions(c.Request.Context(), scope)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list subscriptions", err)
+		return
+	}
+
+	data := make([]CustomerPortalSubscriptionResponse, len(subscriptions))
+	for i, sub := range subscriptions {
+		data[i] = toCustomerPortalSubscriptionResponse(sub)
+	}
+
+	sendSuccess(c, http.StatusOK, CustomerPortalListResponse{Object: "list", Data: data})
+}
+
+// ListInvoices godoc
+// @Summary List the customer's invoices
+// @Description Lists the signed-in customer's finalized invoices, newest first
+// @Tags portal
+// @Produce json
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} CustomerPortalListResponse
+// @Failure 400 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/invoices [get]
+func (h *CustomerPortalHandler) ListInvoices(c *gin.Context) {
+	scope, ok := h.portalScope(c)
+	if !ok {
+		return
+	}
+
+	pagination, err := helpers.ParsePaginationParams(c)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	// Fetch one extra row to tell whether there is another page
+	invoices, err := h.portalService.ListInvoices(c.Request.Context(), scope, pagination.Limit+1, pagination.Offset)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list invoices", err)
+		return
+	}
+
+	hasMore := len(invoices) > int(pagination.Limit)
+	if hasMore {
+		invoices = invoices[:pagination.Limit]
+	}
+
+	data := make([]CustomerPortalInvoiceResponse, len(invoices))
+	for i, invoice := range invoices {
+		data[i] = toCustomerPortalInvoiceResponse(invoice)
+	}
+
+	sendSuccess(c, http.StatusOK, CustomerPortalListResponse{Object: "list", Data: data, HasMore: hasMore})
+}
+
+// ListPayments godoc
+// @Summary List the customer's payments
+// @Description Lists the signed-in customer's payments, newest first
+// @Tags portal
+// @Produce json
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} CustomerPortalListResponse
+// @Failure 400 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/payments [get]
+func (h *CustomerPortalHandler) ListPayments(c *gin.Context) {
+	scope, ok := h.portalScope(c)
+	if !ok {
+		return
+	}
+
+	pagination, err := helpers.ParsePaginationParams(c)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	payments, err := h.portalService.ListPayments(c.Request.Context(), scope, pagination.Limit+1, pagination.Offset)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list payments", err)
+		return
+	}
+
+	hasMore := len(payments) > int(pagination.Limit)
+	if hasMore {
+		payments = payments[:pagination.Limit]
+	}
+
+	data := make([]responses.PaymentResponse, len(payments))
+	for i, payment := range payments {
+		data[i] = helpers.ToPaymentResponse(payment)
+	}
+
+	sendSuccess(c, http.StatusOK, CustomerPortalListResponse{Object: "list", Data: data, HasMore: hasMore})
+}
+
+// CancelSubscription godoc
+// @Summary Cancel a subscription
+// @Description Schedules cancellation of one of the customer's subscriptions at the end of the billing period
+// @Tags portal
+// @Accept json
+// @Produce json
+// @Param subscription_id path string true "Subscription ID"
+// @Param request body CancelSubscriptionRequest true "Cancellation details"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/subscriptions/{subscription_id}/cancel [post]
+func (h *CustomerPortalHandler) CancelSubscription(c *gin.Context) {
+	scope, subscriptionID, ok := h.portalSubscription(c)
+	if !ok {
+		return
+	}
+
+	var req CancelSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.portalService.CancelSubscription(c.Request.Context(), scope, subscriptionID, req.Reason, req.Feedback); err != nil {
+		h.handlePortalActionError(c, err, "Failed to cancel subscription")
+		return
+	}
+
+	sendSuccessMessage(c, http.StatusOK, "Subscription will be cancelled at the end of the billing period")
+}
+
+// PreviewCancellation godoc
+// @Summary Preview cancelling a subscription
+// @Description Shows when service ends and what the customer is charged if they cancel
+// @Tags portal
+// @Produce json
+// @Param subscription_id path string true "Subscription ID"
+// @Success 200 {object} business.ChangePreview
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/subscriptions/{subscription_id}/preview-cancel [post]
+func (h *CustomerPortalHandler) PreviewCancellation(c *gin.Context) {
+	scope, subscriptionID, ok := h.portalSubscription(c)
+	if !ok {
+		return
+	}
+
+	preview, err := h.portalService.PreviewCancellation(c.Request.Context(), scope, subscriptionID)
+	if err != nil {
+		h.handlePortalActionError(c, err, "Failed to preview cancellation")
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, preview)
+}
+
+// PauseSubscription godoc
+// @Summary Pause a subscription
+// @Description Pauses one of the customer's subscriptions, optionally until a given date
+// @Tags portal
+// @Accept json
+// @Produce json
+// @Param subscription_id path string true "Subscription ID"
+// @Param request body PauseSubscriptionRequest true "Pause details"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/subscriptions/{subscription_id}/pause [post]
+func (h *CustomerPortalHandler) PauseSubscription(c *gin.Context) {
+	scope, subscriptionID, ok := h.portalSubscription(c)
+	if !ok {
+		return
+	}
+
+	var req PauseSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	var pauseUntil *time.Time
+	if req.PauseUntil != "" {
+		parsed, err := time.Parse(time.RFC3339, req.PauseUntil)
+		if err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid pause_until format, expected RFC3339", err)
+			return
+		}
+		if !parsed.After(time.Now()) {
+			sendError(c, http.StatusBadRequest, "pause_until must be in the future", nil)
+			return
+		}
+		pauseUntil = &parsed
+	}
+
+	if err := h.portalService.PauseSubscription(c.Request.Context(), scope, subscriptionID, pauseUntil, req.Reason); err != nil {
+		h.handlePortalActionError(c, err, "Failed to pause subscription")
+		return
+	}
+
+	sendSuccessMessage(c, http.StatusOK, "Subscription paused")
+}
+
+// ResumeSubscription godoc
+// @Summary Resume a subscription
+// @Description Resumes one of the customer's paused subscriptions
+// @Tags portal
+// @Produce json
+// @Param subscription_id path string true "Subscription ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/subscriptions/{subscription_id}/resume [post]
+func (h *CustomerPortalHandler) ResumeSubscription(c *gin.Context) {
+	scope, subscriptionID, ok := h.portalSubscription(c)
+	if !ok {
+		return
+	}
+
+	if err := h.portalService.ResumeSubscription(c.Request.Context(), scope, subscriptionID); err != nil {
+		h.handlePortalActionError(c, err, "Failed to resume subscription")
+		return
+	}
+
+	sendSuccessMessage(c, http.StatusOK, "Subscription resumed")
+}
+
+// SwitchSubscriptionWallet godoc
+// @Summary Pay a subscription from another wallet
+// @Description Moves a subscription to another of the customer's wallets using a delegation signed by that wallet
+// @Tags portal
+// @Accept json
+// @Produce json
+// @Param subscription_id path string true "Subscription ID"
+// @Param request body SwitchSubscriptionWalletRequest true "New wallet and delegation"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/subscriptions/{subscription_id}/wallet [post]
+func (h *CustomerPortalHandler) SwitchSubscriptionWallet(c *gin.Context) {
+	scope, subscriptionID, ok := h.portalSubscription(c)
+	if !ok {
+		return
+	}
+
+	var req SwitchSubscriptionWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	caveatsJSON, err := json.Marshal(req.Delegation.Caveats)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid delegation caveats", err)
+		return
+	}
+
+	_, err = h.portalService.SwitchSubscriptionWallet(c.Request.Context(), params.SwitchSubscriptionWalletParams{
+		Scope:            scope,
+		SubscriptionID:   subscriptionID,
+		CustomerWalletID: uuid.MustParse(req.CustomerWalletID),
+		Delegation: params.DelegationParams{
+			Delegate:  req.Delegation.Delegate,
+			Delegator: req.Delegation.Delegator,
+			Authority: req.Delegation.Authority,
+			Salt:      req.Delegation.Salt,
+			Signature: req.Delegation.Signature,
+			Caveats:   caveatsJSON,
+		},
+	})
+	if err != nil {
+		h.handlePortalActionError(c, err, "Failed to switch subscription wallet")
+		return
+	}
+
+	sendSuccessMessage(c, http.StatusOK, "Subscription wallet updated")
+}
+
+// ReauthorizeSubscription godoc
+// @Summary Re-sign a subscription's delegation
+// @Description Replaces a revoked, expired or exhausted delegation with a new one signed by the same wallet
+// @Tags portal
+// @Accept json
+// @Produce json
+// @Param subscription_id path string true "Subscription ID"
+// @Param request body ReauthorizeSubscriptionRequest true "Replacement delegation"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security PortalSession
+// @Router /portal/subscriptions/{subscription_id}/reauthorize [post]
+func (h *CustomerPortalHandler) ReauthorizeSubscription(c *gin.Context) {
+	scope, subscriptionID, ok := h.portalSubscription(c)
+	if !ok {
+		return
+	}
+
+	var req ReauthorizeSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	caveatsJSON, err := json.Marshal(req.Delegation.Caveats)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid delegation caveats", err)
+		return
+	}
+
+	_, err = h.portalService.ReauthorizeSubscription(c.Request.Context(), params.ReauthorizeSubscriptionParams{
+		Scope:          scope,
+		SubscriptionID: subscriptionID,
+		Delegation: params.DelegationParams{
+			Delegate:  req.Delegation.Delegate,
+			Delegator: req.Delegation.Delegator,
+			Authority: req.Delegation.Authority,
+			Salt:      req.Delegation.Salt,
+			Signature: req.Delegation.Signature,
+			Caveats:   caveatsJSON,
+		},
+	})
+	if err != nil {
+		h.handlePortalActionError(c, err, "Failed to reauthorize subscription")
+		return
+	}
+
+	sendSuccessMessage(c, http.StatusOK, "Subscription reauthorized")
+}
+
+// portalScope builds the customer scope set by the customer auth middleware
+func (h *CustomerPortalHandler) portalScope(c *gin.Context) (params.CustomerPortalScope, bool) {
+	customerID, err := uuid.Parse(c.GetString("customerID"))
+	if err != nil {
+		sendError(c, http.StatusUnauthorized, "Customer authentication required", err)
+		return params.CustomerPortalScope{}, false
+	}
+
+	scope := params.CustomerPortalScope{CustomerID: customerID}
+	if c.GetString("authType") == constants.AuthTypePortalSession {
+		workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+		if err != nil {
+			sendError(c, http.StatusUnauthorized, "Invalid portal session", err)
+			return params.CustomerPortalScope{}, false
+		}
+		scope.WorkspaceID = &workspaceID
+		if sessionID, err := uuid.Parse(c.GetString("portalSessionID")); err == nil {
+			scope.SessionID = &sessionID
+		}
+	}
+
+	return scope, true
+}
+
+// portalSubscription returns the customer scope and the subscription ID from the path
+func (h *CustomerPortalHandler) portalSubscription(c *gin.Context) (params.CustomerPortalScope, uuid.UUID, bool) {
+	scope, ok := h.portalScope(c)
+	if !ok {
+		return scope, uuid.Nil, false
+	}
+
+	subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid subscription ID format", err)
+		return scope, uuid.Nil, false
+	}
+
+	return scope, subscriptionID, true
+}
+
+// handlePortalError maps customer portal service errors to HTTP responses
+func (h *CustomerPortalHandler) handlePortalError(c *gin.Context, err error, notFoundMsg string) {
+	switch {
+	case errors.Is(err, services.ErrCustomerPortalNotFound):
+		sendError(c, http.StatusNotFound, notFoundMsg, err)
+	case errors.Is(err, services.ErrCustomerPortalActionNotAllowed):
+		sendError(c, http.StatusForbidden, err.Error(), err)
+	default:
+		handleDBError(c, err, notFoundMsg)
+	}
+}
+
+// handlePortalActionError maps errors from subscription actions. Only errors the customer can act on are
+// shown to them; anything else is logged and reported as an internal error.
+func (h *CustomerPortalHandler) handlePortalActionError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrCustomerPortalNotFound):
+		sendError(c, http.StatusNotFound, "Subscription not found", err)
+	case errors.Is(err, services.ErrCustomerPortalActionNotAllowed):
+		sendError(c, http.StatusForbidden, err.Error(), err)
+	case errors.Is(err, services.ErrInvalidDelegation):
+		sendError(c, http.StatusBadRequest, err.Error(), err)
+	case errors.Is(err, services.ErrReauthorizationNotRequired), errors.Is(err, services.ErrInvalidSubscriptionState):
+		sendError(c, http.StatusConflict, err.Error(), err)
+	default:
+		sendError(c, http.StatusInternalServerError, message, err)
+	}
+}
+
+// toCustomerPortalSessionResponse converts a portal session to its API response
+func toCustomerPortalSessionResponse(session db.CustomerPortalSession) CustomerPortalSessionResponse {
+	return CustomerPortalSessionResponse{
+		ID:          session.ID.String(),
+		Object:      "customer_portal_session",
+		WorkspaceID: session.WorkspaceID.String(),
+		CustomerID:  session.CustomerID.String(),
+		ReturnURL:   session.ReturnUrl.String,
+		ExpiresAt:   session.ExpiresAt.Time.Unix(),
+		CreatedAt:   session.CreatedAt.Time.Unix(),
+	}
+}
+
+// toCustomerPortalSettingsResponse converts portal settings to their API response
+func toCustomerPortalSettingsResponse(settings db.CustomerPortalSetting) CustomerPortalSettingsResponse {
+	return CustomerPortalSettingsResponse{
+		Object:             "customer_portal_settings",
+		WorkspaceID:        settings.WorkspaceID.String(),
+		AllowCancel:        settings.AllowCancel,
+		AllowPause:         settings.AllowPause,
+		AllowResume:        settings.AllowResume,
+		AllowUpdateBilling: settings.AllowUpdateBilling,
+		AllowWalletSwitch:  settings.AllowWalletSwitch,
+		DefaultReturnURL:   settings.DefaultReturnUrl.String,
+		SessionTTLSeconds:  settings.SessionTtlSeconds,
+	}
+}
+
+// toCustomerBillingDetailsResponse extracts a customer's billing address
+func toCustomerBillingDetailsResponse(customer db.Customer) responses.CustomerBillingDetailsResponse {
+	return responses.CustomerBillingDetailsResponse{
+		Country:    customer.BillingCountry.String,
+		State:      customer.BillingState.String,
+		City:       customer.BillingCity.String,
+		PostalCode: customer.BillingPostalCode.String,
+	}
+}
+
+// toCustomerPortalSubscriptionResponse converts a portal subscription to its API response
+func toCustomerPortalSubscriptionResponse(sub business.CustomerPortalSubscription) CustomerPortalSubscriptionResponse {
+	row := sub.Subscription
+	response := CustomerPortalSubscriptionResponse{
+		ID:                 row.ID.String(),
+		Object:             "subscription",
+		WorkspaceID:        row.WorkspaceID.String(),
+		MerchantName:       row.MerchantName,
+		ProductName:        row.ProductName,
+		Status:             string(row.Status),
+		Currency:           row.Currency.String,
+		AmountInCents:      row.TotalAmountInCents,
+		CurrentPeriodStart: row.CurrentPeriodStart.Time.Unix(),
+		CurrentPeriodEnd:   row.CurrentPeriodEnd.Time.Unix(),
+		NextRedemptionDate: optionalUnix(row.NextRedemptionDate),
+		CancelAt:           optionalUnix(row.CancelAt),
+		PausedAt:           optionalUnix(row.PausedAt),
+		PauseEndsAt:        optionalUnix(row.PauseEndsAt),
+		WalletAddress:      row.WalletAddress.String,
+		Permissions:        sub.Permissions,
+		CreatedAt:          row.CreatedAt.Time.Unix(),
+	}
+	if row.CustomerWalletID.Valid {
+		response.CustomerWalletID = uuid.UUID(row.CustomerWalletID.Bytes).String()
+	}
+	if sub.Reauthorization != nil {
+		response.ReauthorizationReason = sub.Reauthorization.Reason
+	}
+	return response
+}
+
+// toCustomerPortalInvoiceResponse converts an invoice to the customer-facing view
+func toCustomerPortalInvoiceResponse(invoice db.Invoice) CustomerPortalInvoiceResponse {
+	response := CustomerPortalInvoiceResponse{
+		ID:               invoice.ID.String(),
+		Object:           "invoice",
+		WorkspaceID:      invoice.WorkspaceID.String(),
+		InvoiceNumber:    invoice.InvoiceNumber.String,
+		Status:           invoice.Status,
+		Currency:         invoice.Currency,
+		AmountDue:        invoice.AmountDue,
+		AmountPaid:       invoice.AmountPaid,
+		AmountRemaining:  invoice.AmountRemaining,
+		DueDate:          optionalUnix(invoice.DueDate),
+		PaidAt:           optionalUnix(invoice.PaidAt),
+		HostedInvoiceURL: invoice.HostedInvoiceUrl.String,
+		InvoicePDF:       invoice.InvoicePdf.String,
+		CreatedAt:        invoice.CreatedDate.Time.Unix(),
+	}
+	if invoice.SubscriptionID.Valid {
+		response.SubscriptionID = uuid.UUID(invoice.SubscriptionID.Bytes).String()
+	}
+	return response
+}
+
+// optionalUnix converts a nullable timestamp to an optional Unix time
+func optionalUnix(ts pgtype.Timestamptz) *int64 {
+	if !ts.Valid {
+		return nil
+	}
+	unix := ts.Time.Unix()
+	return &unix
+}
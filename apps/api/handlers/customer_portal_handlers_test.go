@@ -0,0 +1,210 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cyphera/cyphera-api/apps/api/handlers"
+	"github.com/cyphera/cyphera-api/libs/go/client/auth"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// setupCustomerPortalRouter serves the portal routes under test behind the customer auth middleware
+func setupCustomerPortalRouter(t *testing.T) (*gin.Engine, *mocks.MockCustomerPortalService) {
+	ctrl := gomock.NewController(t)
+	mockPortalService := mocks.NewMockCustomerPortalService(ctrl)
+
+	logger := zap.NewNop()
+	handler := handlers.NewCustomerPortalHandler(handlers.NewCommonServices(handlers.CommonServicesConfig{Logger: logger}), mockPortalService, logger)
+
+	router := gin.New()
+	portal := router.Group("/portal", (&auth.AuthClient{}).EnsureValidCustomerAuth(nil, mockPortalService))
+	portal.GET("/subscriptions", handler.ListSubscriptions)
+	portal.POST("/subscriptions/:subscription_id/wallet", handler.SwitchSubscriptionWallet)
+	portal.POST("/subscriptions/:subscription_id/reauthorize", handler.ReauthorizeSubscription)
+	return router, mockPortalService
+}
+
+func portalRequest(method, url, sessionToken string, body interface{}) *http.Request {
+	var req *http.Request
+	if body != nil {
+		jsonBody, _ := json.Marshal(body)
+		req = httptest.NewRequest(method, url, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(method, url, nil)
+	}
+	if sessionToken != "" {
+		req.Header.Set(auth.PortalSessionHeader, sessionToken)
+	}
+	return req
+}
+
+func TestCustomerPortalHandler_Session(t *testing.T) {
+	session := db.CustomerPortalSession{ID: uuid.New(), CustomerID: uuid.New(), WorkspaceID: uuid.New()}
+
+	t.Run("request without a session", func(t *testing.T) {
+		router, _ := setupCustomerPortalRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, portalRequest(http.MethodGet, "/portal/subscriptions", "", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("expired or revoked session", func(t *testing.T) {
+		router, mockPortalService := setupCustomerPortalRouter(t)
+		mockPortalService.EXPECT().AuthenticateSession(gomock.Any(), "cps_expired").
+			Return(db.CustomerPortalSession{}, services.ErrCustomerPortalSessionInvalid)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, portalRequest(http.MethodGet, "/portal/subscriptions", "cps_expired", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("session scopes requests to its customer and workspace", func(t *testing.T) {
+		router, mockPortalService := setupCustomerPortalRouter(t)
+		mockPortalService.EXPECT().AuthenticateSession(gomock.Any(), "cps_valid").Return(session, nil)
+		mockPortalService.EXPECT().ListSubscriptions(gomock.Any(), params.CustomerPortalScope{
+			CustomerID:  session.CustomerID,
+			WorkspaceID: &session.WorkspaceID,
+			SessionID:   &session.ID,
+		}).Return([]business.CustomerPortalSubscription{}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, portalRequest(http.MethodGet, "/portal/subscriptions", "cps_valid", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestCustomerPortalHandler_SwitchSubscriptionWallet(t *testing.T) {
+	session := db.CustomerPortalSession{ID: uuid.New(), CustomerID: uuid.New(), WorkspaceID: uuid.New()}
+	subscriptionID := uuid.New()
+	walletID := uuid.New()
+	body := handlers.SwitchSubscriptionWalletRequest{
+		CustomerWalletID: walletID.String(),
+		Delegation: business.DelegationStruct{
+			Delegate:  "0xdeadbeef",
+			Delegator: "0xabc0000000000000000000000000000000000001",
+			Authority: "0xffff",
+			Salt:      "1",
+			Signature: "0xsig",
+		},
+	}
+	url := "/portal/subscriptions/" + subscriptionID.String() + "/wallet"
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "switches the wallet", wantStatus: http.StatusOK},
+		{name: "subscription owned by another customer", serviceErr: services.ErrCustomerPortalNotFound, wantStatus: http.StatusNotFound},
+		{name: "action disabled by the merchant", serviceErr: services.ErrCustomerPortalActionNotAllowed, wantStatus: http.StatusForbidden},
+		{name: "delegation fails verification", serviceErr: services.ErrInvalidDelegation, wantStatus: http.StatusBadRequest},
+		{name: "subscription is not active", serviceErr: services.ErrInvalidSubscriptionState, wantStatus: http.StatusConflict},
+		{name: "unexpected failure", serviceErr: errors.New("connection reset"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockPortalService := setupCustomerPortalRouter(t)
+			mockPortalService.EXPECT().AuthenticateSession(gomock.Any(), "cps_valid").Return(session, nil)
+			mockPortalService.EXPECT().SwitchSubscriptionWallet(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, switchParams params.SwitchSubscriptionWalletParams) (db.Subscription, error) {
+					// The service checks ownership against the session's customer and workspace
+					assert.Equal(t, session.CustomerID, switchParams.Scope.CustomerID)
+					assert.Equal(t, &session.WorkspaceID, switchParams.Scope.WorkspaceID)
+					assert.Equal(t, subscriptionID, switchParams.SubscriptionID)
+					assert.Equal(t, walletID, switchParams.CustomerWalletID)
+					assert.Equal(t, body.Delegation.Delegator, switchParams.Delegation.Delegator)
+					assert.Equal(t, body.Delegation.Signature, switchParams.Delegation.Signature)
+					return db.Subscription{ID: subscriptionID}, tt.serviceErr
+				})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, portalRequest(http.MethodPost, url, "cps_valid", body))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	t.Run("invalid subscription ID", func(t *testing.T) {
+		router, mockPortalService := setupCustomerPortalRouter(t)
+		mockPortalService.EXPECT().AuthenticateSession(gomock.Any(), "cps_valid").Return(session, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, portalRequest(http.MethodPost, "/portal/subscriptions/not-a-uuid/wallet", "cps_valid", body))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCustomerPortalHandler_ReauthorizeSubscription(t *testing.T) {
+	session := db.CustomerPortalSession{ID: uuid.New(), CustomerID: uuid.New(), WorkspaceID: uuid.New()}
+	subscriptionID := uuid.New()
+	body := handlers.ReauthorizeSubscriptionRequest{
+		Delegation: business.DelegationStruct{
+			Delegate:  "0xdeadbeef",
+			Delegator: "0xabc0000000000000000000000000000000000001",
+			Authority: "0xffff",
+			Salt:      "1",
+			Signature: "0xsig",
+		},
+	}
+	url := "/portal/subscriptions/" + subscriptionID.String() + "/reauthorize"
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+		wantError  string
+	}{
+		{name: "reauthorizes the subscription", wantStatus: http.StatusOK},
+		{name: "delegation fails verification", serviceErr: services.ErrInvalidDelegation, wantStatus: http.StatusBadRequest, wantError: "invalid delegation"},
+		{name: "no reauthorization pending", serviceErr: services.ErrReauthorizationNotRequired, wantStatus: http.StatusConflict, wantError: services.ErrReauthorizationNotRequired.Error()},
+		{name: "unexpected failure is not exposed", serviceErr: errors.New("failed to store delegation: connection reset"), wantStatus: http.StatusInternalServerError, wantError: "Failed to reauthorize subscription"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockPortalService := setupCustomerPortalRouter(t)
+			mockPortalService.EXPECT().AuthenticateSession(gomock.Any(), "cps_valid").Return(session, nil)
+			mockPortalService.EXPECT().ReauthorizeSubscription(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, reauthParams params.ReauthorizeSubscriptionParams) (db.Subscription, error) {
+					assert.Equal(t, session.CustomerID, reauthParams.Scope.CustomerID)
+					assert.Equal(t, subscriptionID, reauthParams.SubscriptionID)
+					assert.Equal(t, body.Delegation.Signature, reauthParams.Delegation.Signature)
+					return db.Subscription{ID: subscriptionID}, tt.serviceErr
+				})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, portalRequest(http.MethodPost, url, "cps_valid", body))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				var response struct {
+					Error string `json:"error"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.wantError, response.Error)
+			}
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"strings"
+
+	"github.com/cyphera/cyphera-api/libs/go/client/circle"
 	"github.com/cyphera/cyphera-api/libs/go/client/coinmarketcap"
 	dsClient "github.com/cyphera/cyphera-api/libs/go/client/delegation_server"
 	"github.com/cyphera/cyphera-api/libs/go/client/payment_sync"
+	"github.com/cyphera/cyphera-api/libs/go/client/solana"
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/interfaces"
 	"github.com/cyphera/cyphera-api/libs/go/services"
@@ -38,12 +43,21 @@ type HandlerFactory struct {
 	tokenService                  interfaces.TokenService
 	networkService                interfaces.NetworkService
 	analyticsService              interfaces.AnalyticsService
+	analyticsExportService        interfaces.AnalyticsExportService
+	gasFeeService                 interfaces.GasFeeService
 	blockchainService             interfaces.BlockchainService
+	nameResolver                  interfaces.NameResolver
 	errorRecoveryService          interfaces.ErrorRecoveryService
 	subscriptionEventService      interfaces.SubscriptionEventService
 	paymentFailureMonitor         interfaces.PaymentFailureMonitor
 	paymentFailureDetector        interfaces.PaymentFailureDetector
 	APIKeyService                 interfaces.APIKeyService
+	customerPortalService         interfaces.CustomerPortalService
+	taxIDVerificationService      interfaces.TaxIDVerificationService
+	taxReportService              interfaces.TaxReportService
+	redemptionQueueService        interfaces.RedemptionQueueService
+	renewalReviewService          interfaces.RenewalReviewService
+	redemptionGasEstimator        *services.RedemptionGasEstimator
 
 	// External clients
 	cmcClient *coinmarketcap.Client
@@ -81,12 +95,16 @@ type HandlerFactoryConfig struct {
 	TokenService                  interfaces.TokenService
 	NetworkService                interfaces.NetworkService
 	AnalyticsService              interfaces.AnalyticsService
+	AnalyticsExportService        interfaces.AnalyticsExportService
+	GasFeeService                 interfaces.GasFeeService
 	BlockchainService             interfaces.BlockchainService
+	NameResolver                  interfaces.NameResolver
 	ErrorRecoveryService          interfaces.ErrorRecoveryService
 	SubscriptionEventService      interfaces.SubscriptionEventService
 	PaymentFailureMonitor         interfaces.PaymentFailureMonitor
 	PaymentFailureDetector        interfaces.PaymentFailureDetector
 	APIKeyService                 interfaces.APIKeyService
+	CustomerPortalService         interfaces.CustomerPortalService
 	TaxService                    interfaces.TaxService
 	DiscountService               interfaces.DiscountService
 	CurrencyService               interfaces.CurrencyService
@@ -97,6 +115,7 @@ type HandlerFactoryConfig struct {
 
 	// Configuration
 	CypheraSmartWalletAddress string
+	SolanaDelegateAddress     string
 	CMCAPIKey                 string
 	PaymentLinkBaseURL        string
 
@@ -114,6 +133,7 @@ func NewHandlerFactory(config HandlerFactoryConfig) *HandlerFactory {
 	commonServices := NewCommonServices(CommonServicesConfig{
 		DB:                        config.DB,
 		CypheraSmartWalletAddress: config.CypheraSmartWalletAddress,
+		SolanaDelegateAddress:     config.SolanaDelegateAddress,
 		CMCClient:                 config.CMCClient,
 		CMCAPIKey:                 config.CMCAPIKey,
 		APIKeyService:             config.APIKeyService,
@@ -146,12 +166,16 @@ func NewHandlerFactory(config HandlerFactoryConfig) *HandlerFactory {
 		tokenService:                  config.TokenService,
 		networkService:                config.NetworkService,
 		analyticsService:              config.AnalyticsService,
+		analyticsExportService:        config.AnalyticsExportService,
+		gasFeeService:                 config.GasFeeService,
 		blockchainService:             config.BlockchainService,
+		nameResolver:                  config.NameResolver,
 		errorRecoveryService:          config.ErrorRecoveryService,
 		subscriptionEventService:      config.SubscriptionEventService,
 		paymentFailureMonitor:         config.PaymentFailureMonitor,
 		paymentFailureDetector:        config.PaymentFailureDetector,
 		APIKeyService:                 config.APIKeyService,
+		customerPortalService:         config.CustomerPortalService,
 		cmcClient:                     config.CMCClient,
 		cypheraSmartWalletAddress:     config.CypheraSmartWalletAddress,
 		cmcAPIKey:                     config.CMCAPIKey,
@@ -173,19 +197,45 @@ func CreateDefaultFactory(
 	fromName string,
 	baseURL string,
 	rpcAPIKey string,
+	rpcConfig services.RPCPoolConfig,
+	solanaDelegate *solana.Keypair,
 	delegationClient *dsClient.DelegationClient,
 	paymentSyncClient *payment_sync.PaymentSyncClient,
+	taxProvider interfaces.TaxProvider,
+	taxIDRegistry interfaces.TaxIDRegistry,
+	exportStorage services.ExportStorage,
+	nameResolverConfig services.NameResolverConfig,
+	walletNameConfig services.WalletNameConfig,
+	delegationConfig services.DelegationMonitorConfig,
 ) *HandlerFactory {
 	logger := zap.L()
 
 	// Create all concrete services
 	emailService := services.NewEmailService(resendAPIKey, fromEmail, fromName, logger)
-	paymentService := services.NewPaymentService(db, cmcAPIKey)
 	currencyService := services.NewCurrencyService(db)
 	exchangeRateService := services.NewExchangeRateService(db, cmcAPIKey)
-	taxService := services.NewTaxService(db)
+
+	// Gas fees are estimated from live network data when RPC connections are available
+	blockchainService := services.NewBlockchainService(db, rpcAPIKey).WithRPCPoolConfig(rpcConfig)
+	if rpcAPIKey != "" || len(rpcConfig.Endpoints) > 0 {
+		// No background health checks here: an endpoint taken out of rotation is tried again once its cooldown passes
+		if err := blockchainService.Initialize(context.Background()); err != nil {
+			logger.Warn("Failed to connect to network RPCs, gas fees will use static estimates", zap.Error(err))
+		}
+	}
+	if solanaDelegate != nil {
+		blockchainService = blockchainService.WithSolanaDelegate(solanaDelegate)
+	}
+	gasFeeOracle := services.NewGasFeeOracle(blockchainService)
+	nameResolver := services.NewNameResolver(db, blockchainService, nameResolverConfig)
+	gasFeeService := services.NewGasFeeServiceWithOracle(db, exchangeRateService, gasFeeOracle)
+	taxIDVerificationService := services.NewTaxIDVerificationService(db, taxIDRegistry)
+	taxService := services.NewTaxServiceWithDependencies(db, taxProvider, taxIDVerificationService).WithTransactions(dbPool)
+	paymentService := services.NewPaymentServiceWithFeeOracle(db, cmcAPIKey, gasFeeOracle).WithTaxService(taxService)
+	taxReportService := services.NewTaxReportService(db)
 	discountService := services.NewDiscountService(db)
 	gasSponsorshipService := services.NewGasSponsorshipService(db)
+	redemptionGasEstimator := services.NewRedemptionGasEstimator(gasFeeService, delegationConfig.DelegationManager)
 
 	// Create services that depend on other services
 	subscriptionManagementService := services.NewSubscriptionManagementService(db, paymentService, emailService)
@@ -195,8 +245,10 @@ func CreateDefaultFactory(
 	paymentLinkService := services.NewPaymentLinkService(db, logger, baseURL)
 	invoiceService := services.NewInvoiceService(db, logger, taxService, discountService, gasSponsorshipService, currencyService, exchangeRateService)
 	productService := services.NewProductService(db)
-	customerService := services.NewCustomerService(db)
-	subscriptionService := services.NewSubscriptionService(db, delegationClient, paymentService, customerService, invoiceService)
+	customerService := services.NewCustomerService(db).WithNameResolver(nameResolver, walletNameConfig)
+	subscriptionService := services.NewSubscriptionService(db, delegationClient, paymentService, customerService, invoiceService).
+		WithSplPayments(blockchainService).
+		WithGasEstimates(redemptionGasEstimator)
 	workspaceService := services.NewWorkspaceService(db)
 	accountService := services.NewAccountService(db)
 	userService := services.NewUserService(db)
@@ -204,12 +256,27 @@ func CreateDefaultFactory(
 	tokenService := services.NewTokenService(db, cmcClient)
 	networkService := services.NewNetworkService(db)
 	analyticsService := services.NewAnalyticsService(db, dbPool)
-	blockchainService := services.NewBlockchainService(db, rpcAPIKey)
+	analyticsExportService := services.NewAnalyticsExportService(db, exportStorage, exchangeRateService, emailService)
 	errorRecoveryService := services.NewErrorRecoveryService(db, logger, paymentSyncClient)
 	subscriptionEventService := services.NewSubscriptionEventService(db)
 	paymentFailureMonitor := services.NewPaymentFailureMonitor(db, logger, dunningService)
 	paymentFailureDetector := services.NewPaymentFailureDetector(db, logger, dunningService)
 	apiKeyService := services.NewAPIKeyService(db)
+	// Delegations customers sign in the portal are verified as on subscription creation
+	portalDelegations := services.PortalDelegationConfig{
+		DelegateAddress:       cypheraSmartWalletAddress,
+		SolanaDelegateAddress: blockchainService.SolanaDelegateAddress(),
+		SplPayments:           blockchainService,
+		Enforcers:             delegationConfig.Enforcers,
+	}
+	if delegationClient != nil {
+		portalDelegations.Simulator = delegationClient
+	}
+	customerPortalService := services.NewCustomerPortalService(db, subscriptionManagementService, strings.TrimRight(baseURL, "/")+"/portal").
+		WithDelegationVerification(portalDelegations).
+		WithTransactions(dbPool)
+	redemptionQueueService := services.NewRedemptionQueueService(db)
+	renewalReviewService := services.NewRenewalReviewService(db)
 
 	// Also update the factory to include DBPool in the config for CommonServices
 	return &HandlerFactory{
@@ -218,6 +285,7 @@ func CreateDefaultFactory(
 			DB:                        db,
 			DBPool:                    dbPool, // Add the missing dbPool here
 			CypheraSmartWalletAddress: cypheraSmartWalletAddress,
+			SolanaDelegateAddress:     blockchainService.SolanaDelegateAddress(),
 			CMCClient:                 cmcClient,
 			CMCAPIKey:                 cmcAPIKey,
 			APIKeyService:             apiKeyService,
@@ -246,12 +314,21 @@ func CreateDefaultFactory(
 		tokenService:                  tokenService,
 		networkService:                networkService,
 		analyticsService:              analyticsService,
+		analyticsExportService:        analyticsExportService,
+		gasFeeService:                 gasFeeService,
 		blockchainService:             blockchainService,
+		nameResolver:                  nameResolver,
 		errorRecoveryService:          errorRecoveryService,
 		subscriptionEventService:      subscriptionEventService,
 		paymentFailureMonitor:         paymentFailureMonitor,
 		paymentFailureDetector:        paymentFailureDetector,
 		APIKeyService:                 apiKeyService,
+		customerPortalService:         customerPortalService,
+		taxIDVerificationService:      taxIDVerificationService,
+		taxReportService:              taxReportService,
+		redemptionQueueService:        redemptionQueueService,
+		renewalReviewService:          renewalReviewService,
+		redemptionGasEstimator:        redemptionGasEstimator,
 		cmcClient:                     cmcClient,
 		cypheraSmartWalletAddress:     cypheraSmartWalletAddress,
 		cmcAPIKey:                     cmcAPIKey,
@@ -326,6 +403,15 @@ func (f *HandlerFactory) NewProductHandler(delegationClient *dsClient.Delegation
 	)
 }
 
+// NewPortfolioHandler creates a portfolio handler that reads Circle wallets through the given Circle client
+func (f *HandlerFactory) NewPortfolioHandler(circleClient circle.CircleClientInterface, config services.PortfolioConfig) *PortfolioHandler {
+	return NewPortfolioHandler(
+		f.commonServices,
+		services.NewPortfolioService(f.db, circleClient, f.blockchainService, f.commonServices.GetExchangeRateService(), config),
+		f.logger,
+	)
+}
+
 // NewSubscriptionHandler creates a new subscription handler
 func (f *HandlerFactory) NewSubscriptionHandler(delegationClient *dsClient.DelegationClient) *SubscriptionHandler {
 	return NewSubscriptionHandler(
@@ -345,6 +431,51 @@ func (f *HandlerFactory) NewAPIKeyHandler() *APIKeyHandler {
 	)
 }
 
+// NewCustomerPortalHandler creates a new customer portal handler
+func (f *HandlerFactory) NewCustomerPortalHandler() *CustomerPortalHandler {
+	return NewCustomerPortalHandler(
+		f.commonServices,
+		f.customerPortalService,
+		f.logger,
+	)
+}
+
+// NewTaxHandler creates a new tax handler
+func (f *HandlerFactory) NewTaxHandler() *TaxHandler {
+	return NewTaxHandler(
+		f.commonServices,
+		f.taxIDVerificationService,
+		f.logger,
+	)
+}
+
+// NewTaxReportHandler creates a new tax report handler
+func (f *HandlerFactory) NewTaxReportHandler() *TaxReportHandler {
+	return NewTaxReportHandler(
+		f.commonServices,
+		f.taxReportService,
+		f.logger,
+	)
+}
+
+// NewRedemptionQueueHandler creates a new redemption queue handler
+func (f *HandlerFactory) NewRedemptionQueueHandler() *RedemptionQueueHandler {
+	return NewRedemptionQueueHandler(
+		f.commonServices,
+		f.redemptionQueueService,
+		f.logger,
+	)
+}
+
+// NewSubscriptionRenewalHandler creates a new subscription renewal handler
+func (f *HandlerFactory) NewSubscriptionRenewalHandler() *SubscriptionRenewalHandler {
+	return NewSubscriptionRenewalHandler(
+		f.commonServices,
+		f.renewalReviewService,
+		f.logger,
+	)
+}
+
 // NewAccountHandler creates a new account handler
 func (f *HandlerFactory) NewAccountHandler() *AccountHandler {
 	return NewAccountHandler(
@@ -359,6 +490,16 @@ func (f *HandlerFactory) NewAnalyticsHandler() *AnalyticsHandler {
 	return NewAnalyticsHandler(
 		f.commonServices,
 		f.analyticsService,
+		f.gasFeeService,
+		f.logger,
+	)
+}
+
+// NewAnalyticsExportHandler creates a new analytics export handler
+func (f *HandlerFactory) NewAnalyticsExportHandler() *AnalyticsExportHandler {
+	return NewAnalyticsExportHandler(
+		f.commonServices,
+		f.analyticsExportService,
 		f.logger,
 	)
 }
@@ -449,6 +590,7 @@ func (f *HandlerFactory) NewWalletHandler() *WalletHandler {
 	return NewWalletHandler(
 		f.commonServices,
 		f.walletService,
+		f.nameResolver,
 	)
 }
 
@@ -477,12 +619,17 @@ func (f *HandlerFactory) GetDB() db.Querier {
 	return f.db
 }
 
+// GetCustomerPortalService returns the customer portal service
+func (f *HandlerFactory) GetCustomerPortalService() interfaces.CustomerPortalService {
+	return f.customerPortalService
+}
+
 // GetLogger returns the logger
 func (f *HandlerFactory) GetLogger() *zap.Logger {
 	return f.logger
 }
 
-// CreateRedemptionProcessor creates a redemption processor
-func (f *HandlerFactory) CreateRedemptionProcessor(delegationClient *dsClient.DelegationClient, workerCount int, bufferSize int) *services.RedemptionProcessor {
-	return services.NewRedemptionProcessor(f.db, delegationClient, f.paymentService, workerCount, bufferSize)
+// CreateRedemptionProcessor creates a redemption processor working the durable redemption queue
+func (f *HandlerFactory) CreateRedemptionProcessor(delegationClient *dsClient.DelegationClient, workerCount int, config services.RedemptionQueueConfig) *services.RedemptionProcessor {
+	return services.NewRedemptionProcessor(f.db, delegationClient, f.paymentService, f.redemptionGasEstimator, workerCount, config)
 }
@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"math/big"
 	"net/http"
 
 	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
 	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/requests"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
 	"github.com/cyphera/cyphera-api/libs/go/types/business"
@@ -43,6 +48,11 @@ func NewGasSponsorshipHandler(
 // Use types from the centralized packages
 type GasSponsorshipConfigRequest = requests.GasSponsorshipConfigRequest
 type GasSponsorshipConfigResponse = responses.GasSponsorshipConfigResponse
+type GasSponsorshipRuleRequest = requests.GasSponsorshipRuleRequest
+type GasSponsorshipRuleResponse = responses.GasSponsorshipRuleResponse
+type SimulateGasSponsorshipRequest = requests.SimulateGasSponsorshipRequest
+type GasSponsorshipSimulationResponse = responses.GasSponsorshipSimulationResponse
+type GasSponsorshipLedgerEntryResponse = responses.GasSponsorshipLedgerEntryResponse
 
 // GetGasSponsorshipConfig retrieves gas sponsorship configuration
 // @Summary Get gas sponsorship configuration
@@ -110,6 +120,8 @@ func (h *GasSponsorshipHandler) GetGasSponsorshipConfig(c *gin.Context) {
 		SponsorForCustomers:    customers,
 		SponsorForTiers:        tiers,
 		CurrentMonthSpentCents: config.CurrentMonthSpentCents.Int64,
+
+		CurrentMonthReservedCents: config.CurrentMonthReservedCents,
 	}
 
 	if config.SponsorThresholdUsdCents.Valid {
@@ -120,10 +132,15 @@ func (h *GasSponsorshipHandler) GetGasSponsorshipConfig(c *gin.Context) {
 	if config.MonthlyBudgetUsdCents.Valid {
 		budget := config.MonthlyBudgetUsdCents.Int64
 		response.MonthlyBudgetUsdCents = &budget
-		remaining := budget - config.CurrentMonthSpentCents.Int64
+		remaining := budget - config.CurrentMonthSpentCents.Int64 - config.CurrentMonthReservedCents
 		response.RemainingBudgetCents = &remaining
 	}
 
+	if config.PerCustomerMonthlyCapUsdCents.Valid {
+		capCents := config.PerCustomerMonthlyCapUsdCents.Int64
+		response.PerCustomerMonthlyCapUsdCents = &capCents
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -192,13 +209,14 @@ func (h *GasSponsorshipHandler) UpdateGasSponsorshipConfig(c *gin.Context) {
 
 	// Convert to service update type
 	updates := business.SponsorshipConfigUpdates{
-		SponsorshipEnabled:       &req.SponsorshipEnabled,
-		SponsorCustomerGas:       &req.SponsorCustomerGas,
-		MonthlyBudgetUSDCents:    req.MonthlyBudgetUsdCents,
-		SponsorThresholdUSDCents: req.SponsorThresholdUsdCents,
-		SponsorForProducts:       &req.SponsorForProducts,
-		SponsorForCustomers:      &req.SponsorForCustomers,
-		SponsorForTiers:          &req.SponsorForTiers,
+		SponsorshipEnabled:            &req.SponsorshipEnabled,
+		SponsorCustomerGas:            &req.SponsorCustomerGas,
+		MonthlyBudgetUSDCents:         req.MonthlyBudgetUsdCents,
+		SponsorThresholdUSDCents:      req.SponsorThresholdUsdCents,
+		SponsorForProducts:            &req.SponsorForProducts,
+		PerCustomerMonthlyCapUSDCents: req.PerCustomerMonthlyCapUsdCents,
+		SponsorForCustomers:           &req.SponsorForCustomers,
+		SponsorForTiers:               &req.SponsorForTiers,
 	}
 
 	// Update configuration
@@ -244,3 +262,344 @@ func (h *GasSponsorshipHandler) GetGasSponsorshipBudgetStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, status)
 }
+
+// ListGasSponsorshipRules lists the sponsorship policy rules
+// @Summary List gas sponsorship rules
+// @Description List the workspace's gas sponsorship rules in evaluation order. The first active rule whose conditions all match a transaction decides its sponsorship.
+// @Tags Gas Sponsorship
+// @Produce json
+// @Success 200 {array} GasSponsorshipRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /gas-sponsorship/rules [get]
+func (h *GasSponsorshipHandler) ListGasSponsorshipRules(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	rules, err := h.service.ListSponsorshipRules(c.Request.Context(), workspaceID)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list sponsorship rules", err)
+		return
+	}
+
+	items := make([]GasSponsorshipRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		items = append(items, toGasSponsorshipRuleResponse(rule))
+	}
+	sendList(c, items)
+}
+
+// CreateGasSponsorshipRule adds a sponsorship policy rule
+// @Summary Create a gas sponsorship rule
+// @Description Add a rule combining conditions (network, token, product, customer tier, transaction type, gas price ceiling, time window) with an action (sponsor fully, sponsor up to an amount, or split a percentage)
+// @Tags Gas Sponsorship
+// @Accept json
+// @Produce json
+// @Param rule body GasSponsorshipRuleRequest true "Rule"
+// @Success 201 {object} GasSponsorshipRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /gas-sponsorship/rules [post]
+func (h *GasSponsorshipHandler) CreateGasSponsorshipRule(c *gin.Context) {
+	ruleParams, ok := h.parseRuleRequest(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.service.CreateSponsorshipRule(c.Request.Context(), ruleParams)
+	if err != nil {
+		h.handleRuleError(c, err)
+		return
+	}
+
+	sendSuccess(c, http.StatusCreated, toGasSponsorshipRuleResponse(*rule))
+}
+
+// UpdateGasSponsorshipRule replaces a sponsorship policy rule
+// @Summary Update a gas sponsorship rule
+// @Description Replace a gas sponsorship rule's name, priority, conditions and action
+// @Tags Gas Sponsorship
+// @Accept json
+// @Produce json
+// @Param rule_id path string true "Rule ID"
+// @Param rule body GasSponsorshipRuleRequest true "Rule"
+// @Success 200 {object} GasSponsorshipRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /gas-sponsorship/rules/{rule_id} [put]
+func (h *GasSponsorshipHandler) UpdateGasSponsorshipRule(c *gin.Context) {
+	ruleID, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid rule ID format", err)
+		return
+	}
+
+	ruleParams, ok := h.parseRuleRequest(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.service.UpdateSponsorshipRule(c.Request.Context(), ruleID, ruleParams)
+	if err != nil {
+		h.handleRuleError(c, err)
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toGasSponsorshipRuleResponse(*rule))
+}
+
+// DeleteGasSponsorshipRule removes a sponsorship policy rule
+// @Summary Delete a gas sponsorship rule
+// @Description Remove a rule from the workspace's gas sponsorship policy
+// @Tags Gas Sponsorship
+// @Param rule_id path string true "Rule ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /gas-sponsorship/rules/{rule_id} [delete]
+func (h *GasSponsorshipHandler) DeleteGasSponsorshipRule(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+	ruleID, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid rule ID format", err)
+		return
+	}
+
+	if err := h.service.DeleteSponsorshipRule(c.Request.Context(), workspaceID, ruleID); err != nil {
+		handleDBError(c, err, "Sponsorship rule not found")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SimulateGasSponsorship shows which rule would fire for a transaction
+// @Summary Simulate gas sponsorship
+// @Description Run a hypothetical transaction through the sponsorship policy without recording anything. Shows the decision, the sponsored and customer amounts, and why each rule did or did not match.
+// @Tags Gas Sponsorship
+// @Accept json
+// @Produce json
+// @Param transaction body SimulateGasSponsorshipRequest true "Transaction"
+// @Success 200 {object} GasSponsorshipSimulationResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /gas-sponsorship/simulate [post]
+func (h *GasSponsorshipHandler) SimulateGasSponsorship(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	var req SimulateGasSponsorshipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	checkParams := params.SponsorshipCheckParams{
+		WorkspaceID:     workspaceID,
+		GasCostUSDCents: req.GasCostUsdCents,
+		TransactionType: req.TransactionType,
+		CustomerTier:    req.CustomerTier,
+	}
+	ids := []struct {
+		value string
+		field string
+		dest  *uuid.UUID
+	}{
+		{req.CustomerID, "customer_id", &checkParams.CustomerID},
+		{req.ProductID, "product_id", &checkParams.ProductID},
+		{req.NetworkID, "network_id", &checkParams.NetworkID},
+		{req.TokenID, "token_id", &checkParams.TokenID},
+	}
+	for _, id := range ids {
+		if id.value == "" {
+			continue
+		}
+		parsed, err := uuid.Parse(id.value)
+		if err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid "+id.field+" format", err)
+			return
+		}
+		*id.dest = parsed
+	}
+	if req.GasPriceGwei != nil {
+		if *req.GasPriceGwei < 0 {
+			sendError(c, http.StatusBadRequest, "gas_price_gwei cannot be negative", nil)
+			return
+		}
+		checkParams.GasPriceWei, _ = new(big.Float).Mul(big.NewFloat(*req.GasPriceGwei), big.NewFloat(1e9)).Int(nil)
+	}
+	if req.At != nil {
+		checkParams.At = *req.At
+	}
+
+	simulation, err := h.service.SimulateSponsorship(c.Request.Context(), checkParams)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to simulate sponsorship", err)
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toGasSponsorshipSimulationResponse(req.GasCostUsdCents, simulation))
+}
+
+// ListGasSponsorshipLedger lists the sponsorship budget ledger
+// @Summary List the gas sponsorship ledger
+// @Description List every reservation, settlement and release of the workspace's sponsorship budget, newest first, with the budget after each entry
+// @Tags Gas Sponsorship
+// @Produce json
+// @Param limit query int false "Number of entries per page (max 100)"
+// @Param page query int false "Page number"
+// @Success 200 {object} PaginatedResponse{data=[]GasSponsorshipLedgerEntryResponse}
+// @Failure 400 {object} ErrorResponse
+// @Router /gas-sponsorship/ledger [get]
+func (h *GasSponsorshipHandler) ListGasSponsorshipLedger(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	pageParams, err := helpers.ParsePaginationParams(c)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	entries, total, err := h.service.ListSponsorshipLedger(c.Request.Context(), workspaceID, pageParams.Limit, pageParams.Offset)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list sponsorship ledger", err)
+		return
+	}
+
+	items := make([]GasSponsorshipLedgerEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, toGasSponsorshipLedgerEntryResponse(entry))
+	}
+	response := sendPaginatedSuccess(c, http.StatusOK, items, int(pageParams.Page), int(pageParams.Limit), int(total))
+	c.JSON(http.StatusOK, response)
+}
+
+// parseRuleRequest binds a rule request to service parameters, writing the error response when it is invalid
+func (h *GasSponsorshipHandler) parseRuleRequest(c *gin.Context) (params.SponsorshipRuleParams, bool) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return params.SponsorshipRuleParams{}, false
+	}
+
+	var req GasSponsorshipRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return params.SponsorshipRuleParams{}, false
+	}
+
+	return params.SponsorshipRuleParams{
+		WorkspaceID:         workspaceID,
+		Name:                req.Name,
+		Priority:            req.Priority,
+		IsActive:            req.IsActive == nil || *req.IsActive,
+		Conditions:          req.Conditions,
+		Action:              business.SponsorshipAction(req.Action),
+		SponsorUpToUSDCents: req.SponsorUpToUsdCents,
+		SponsorPercentage:   req.SponsorPercentage,
+	}, true
+}
+
+// handleRuleError maps sponsorship rule validation errors to HTTP responses
+func (h *GasSponsorshipHandler) handleRuleError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidSponsorshipRule) {
+		sendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	handleDBError(c, err, "Sponsorship rule not found")
+}
+
+// toGasSponsorshipRuleResponse converts a sponsorship rule to a response
+func toGasSponsorshipRuleResponse(rule business.SponsorshipRule) GasSponsorshipRuleResponse {
+	resp := GasSponsorshipRuleResponse{
+		ID:         rule.ID.String(),
+		Object:     "gas_sponsorship_rule",
+		Name:       rule.Name,
+		Priority:   rule.Priority,
+		IsActive:   rule.IsActive,
+		Conditions: rule.Conditions,
+		Action:     string(rule.Action),
+		CreatedAt:  rule.CreatedAt.Unix(),
+		UpdatedAt:  rule.UpdatedAt.Unix(),
+	}
+	switch rule.Action {
+	case business.SponsorshipActionUpTo:
+		upTo := rule.SponsorUpToUSDCents
+		resp.SponsorUpToUsdCents = &upTo
+	case business.SponsorshipActionSplitPercentage:
+		percentage := rule.SponsorPercentage
+		resp.SponsorPercentage = &percentage
+	}
+	return resp
+}
+
+// toGasSponsorshipSimulationResponse converts a sponsorship simulation to a response
+func toGasSponsorshipSimulationResponse(gasCostCents int64, simulation *business.SponsorshipSimulation) GasSponsorshipSimulationResponse {
+	decision := simulation.Decision
+	resp := GasSponsorshipSimulationResponse{
+		ShouldSponsor:              decision.ShouldSponsor,
+		Reason:                     decision.Reason,
+		GasCostUsdCents:            gasCostCents,
+		SponsoredAmountUsdCents:    decision.SponsoredAmountCents,
+		CustomerAmountUsdCents:     gasCostCents - decision.SponsoredAmountCents,
+		RuleName:                   decision.RuleName,
+		RemainingBudgetCents:       decision.RemainingBudget,
+		CustomerMonthSpentUsdCents: simulation.CustomerMonthSpentCents,
+		CustomerMonthlyCapUsdCents: simulation.CustomerMonthlyCapCents,
+		Rules:                      make([]responses.GasSponsorshipRuleEvaluationResponse, 0, len(simulation.Evaluations)),
+	}
+	if decision.RuleID != uuid.Nil {
+		ruleID := decision.RuleID.String()
+		resp.RuleID = &ruleID
+	}
+	for _, evaluation := range simulation.Evaluations {
+		failed := evaluation.FailedConditions
+		if failed == nil {
+			failed = []string{}
+		}
+		resp.Rules = append(resp.Rules, responses.GasSponsorshipRuleEvaluationResponse{
+			RuleID:           evaluation.RuleID.String(),
+			Name:             evaluation.RuleName,
+			Priority:         evaluation.Priority,
+			Matched:          evaluation.Matched,
+			Fired:            evaluation.Fired,
+			FailedConditions: failed,
+		})
+	}
+	return resp
+}
+
+func toGasSponsorshipLedgerEntryResponse(entry business.SponsorshipLedgerEntry) GasSponsorshipLedgerEntryResponse {
+	response := GasSponsorshipLedgerEntryResponse{
+		ID:                    entry.ID.String(),
+		Object:                "gas_sponsorship_ledger_entry",
+		ReservationID:         entry.ReservationID.String(),
+		EntryType:             entry.EntryType,
+		AmountUsdCents:        entry.AmountCents,
+		BudgetSpentCents:      entry.BudgetSpentCents,
+		BudgetReservedCents:   entry.BudgetReservedCents,
+		MonthlyBudgetUsdCents: entry.MonthlyBudgetCents,
+		Reason:                entry.Reason,
+		CreatedAt:             entry.CreatedAt.Unix(),
+	}
+	if entry.CustomerID != uuid.Nil {
+		customerID := entry.CustomerID.String()
+		response.CustomerID = &customerID
+	}
+	if entry.PaymentID != uuid.Nil {
+		paymentID := entry.PaymentID.String()
+		response.PaymentID = &paymentID
+	}
+	return response
+}
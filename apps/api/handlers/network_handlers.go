@@ -91,17 +91,19 @@ func (h *NetworkHandler) CreateNetwork(c *gin.Context) {
 	}
 
 	networkCreateParams := params.CreateNetworkParams{
-		Name:              req.Name,
-		Type:              req.Type,
-		NetworkType:       req.NetworkType,
-		CircleNetworkType: req.CircleNetworkType,
-		BlockExplorerURL:  req.BlockExplorerURL,
-		ChainID:           req.ChainID,
-		IsTestnet:         req.IsTestnet,
-		Active:            req.Active,
-		LogoURL:           req.LogoURL,
-		DisplayName:       req.DisplayName,
-		ChainNamespace:    req.ChainNamespace,
+		Name:               req.Name,
+		Type:               req.Type,
+		NetworkType:        req.NetworkType,
+		CircleNetworkType:  req.CircleNetworkType,
+		BlockExplorerURL:   req.BlockExplorerURL,
+		ChainID:            req.ChainID,
+		IsTestnet:          req.IsTestnet,
+		Active:             req.Active,
+		LogoURL:            req.LogoURL,
+		DisplayName:        req.DisplayName,
+		ChainNamespace:     req.ChainNamespace,
+		ConfirmationBlocks: req.ConfirmationBlocks,
+		FinalityBlocks:     req.FinalityBlocks,
 	}
 
 	if req.GasConfig != nil {
@@ -150,18 +152,20 @@ func (h *NetworkHandler) UpdateNetwork(c *gin.Context) {
 	}
 
 	networkUpdateParams := params.UpdateNetworkParams{
-		ID:                parsedUUID,
-		Name:              req.Name,
-		Type:              req.Type,
-		NetworkType:       req.NetworkType,
-		CircleNetworkType: req.CircleNetworkType,
-		BlockExplorerURL:  req.BlockExplorerURL,
-		ChainID:           req.ChainID,
-		IsTestnet:         req.IsTestnet,
-		Active:            req.Active,
-		LogoURL:           req.LogoURL,
-		DisplayName:       req.DisplayName,
-		ChainNamespace:    req.ChainNamespace,
+		ID:                 parsedUUID,
+		Name:               req.Name,
+		Type:               req.Type,
+		NetworkType:        req.NetworkType,
+		CircleNetworkType:  req.CircleNetworkType,
+		BlockExplorerURL:   req.BlockExplorerURL,
+		ChainID:            req.ChainID,
+		IsTestnet:          req.IsTestnet,
+		Active:             req.Active,
+		LogoURL:            req.LogoURL,
+		DisplayName:        req.DisplayName,
+		ChainNamespace:     req.ChainNamespace,
+		ConfirmationBlocks: req.ConfirmationBlocks,
+		FinalityBlocks:     req.FinalityBlocks,
 	}
 
 	if req.GasConfig != nil {
@@ -333,6 +333,26 @@ func (h *PaymentsHandler) convertToPaymentResponse(ctx context.Context, payment
 		}
 	}
 
+	// Get confirmation progress if the payment's transaction is tracked
+	if payment.TransactionHash.Valid {
+		confirmation, err := h.common.GetDB().GetPaymentConfirmation(ctx, payment.ID)
+		if err == nil {
+			response.Confirmation = &responses.PaymentConfirmationBasic{
+				Status:        confirmation.Status,
+				Confirmations: confirmation.Confirmations,
+			}
+			if confirmation.BlockNumber.Valid {
+				response.Confirmation.BlockNumber = &confirmation.BlockNumber.Int64
+			}
+			if confirmation.ConfirmedAt.Valid {
+				response.Confirmation.ConfirmedAt = &confirmation.ConfirmedAt.Time
+			}
+			if confirmation.FinalizedAt.Valid {
+				response.Confirmation.FinalizedAt = &confirmation.FinalizedAt.Time
+			}
+		}
+	}
+
 	// Get token info if available
 	if payment.TokenID.Valid {
 		token, err := h.common.GetDB().GetToken(ctx, payment.TokenID.Bytes)
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PortfolioHandler manages wallet balance and portfolio endpoints
+type PortfolioHandler struct {
+	common  *CommonServices
+	service interfaces.PortfolioService
+	logger  *zap.Logger
+}
+
+// NewPortfolioHandler creates a handler with interface dependency
+func NewPortfolioHandler(
+	common *CommonServices,
+	service interfaces.PortfolioService,
+	logger *zap.Logger,
+) *PortfolioHandler {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &PortfolioHandler{
+		common:  common,
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Use types from the centralized packages
+type PortfolioResponse = responses.PortfolioResponse
+type PortfolioWalletResponse = responses.PortfolioWalletResponse
+type WalletBalanceResponse = responses.WalletBalanceResponse
+type PortfolioSnapshotResponse = responses.PortfolioSnapshotResponse
+type PortfolioHoldingResponse = responses.PortfolioHoldingResponse
+
+// GetPortfolio returns the workspace's wallet balances
+// @Summary Get the portfolio
+// @Description Balances of every token held by the workspace's wallets on each active network, valued in the workspace's default currency. Circle wallets are read through Circle and other wallets from the chain. Balances are cached and refreshed once they are older than the freshness window.
+// @Tags Portfolio
+// @Produce json
+// @Param wallet_id query string false "Only this wallet"
+// @Param refresh query bool false "Read balances now instead of serving cached ones"
+// @Success 200 {object} PortfolioResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /portfolio [get]
+func (h *PortfolioHandler) GetPortfolio(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	var walletID *uuid.UUID
+	if value := c.Query("wallet_id"); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid wallet ID format", err)
+			return
+		}
+		walletID = &parsed
+	}
+
+	refresh := false
+	if value := c.Query("refresh"); value != "" {
+		if refresh, err = strconv.ParseBool(value); err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid refresh parameter", err)
+			return
+		}
+	}
+
+	portfolio, err := h.service.GetPortfolio(c.Request.Context(), workspaceID, walletID, refresh)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to get portfolio", err)
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toPortfolioResponse(portfolio))
+}
+
+// GetPortfolioHistory returns the workspace's portfolio snapshots
+// @Summary Get portfolio history
+// @Description Value of the workspace's wallets over time, one snapshot per period, oldest first
+// @Tags Portfolio
+// @Produce json
+// @Param days query int false "Number of days to include (default: 30, max: 366)"
+// @Success 200 {array} PortfolioSnapshotResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /portfolio/history [get]
+func (h *PortfolioHandler) GetPortfolioHistory(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		sendError(c, http.StatusBadRequest, "Invalid days parameter", err)
+		return
+	}
+
+	end := time.Now()
+	snapshots, err := h.service.ListSnapshots(c.Request.Context(), workspaceID, end.AddDate(0, 0, -days), end)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPortfolioRange) {
+			sendError(c, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		sendError(c, http.StatusInternalServerError, "Failed to get portfolio history", err)
+		return
+	}
+
+	items := make([]PortfolioSnapshotResponse, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		items = append(items, toPortfolioSnapshotResponse(snapshot))
+	}
+	sendList(c, items)
+}
+
+// toPortfolioResponse converts a portfolio to a response
+func toPortfolioResponse(portfolio *business.Portfolio) PortfolioResponse {
+	wallets := make([]PortfolioWalletResponse, 0, len(portfolio.Wallets))
+	for _, wallet := range portfolio.Wallets {
+		balances := make([]WalletBalanceResponse, 0, len(wallet.Balances))
+		for _, balance := range wallet.Balances {
+			balances = append(balances, WalletBalanceResponse{
+				Object:         "wallet_balance",
+				NetworkID:      balance.NetworkID.String(),
+				NetworkName:    balance.NetworkName,
+				ChainID:        balance.ChainID,
+				TokenID:        balance.TokenID.String(),
+				TokenSymbol:    balance.TokenSymbol,
+				TokenName:      balance.TokenName,
+				TokenAddress:   balance.TokenAddress,
+				Balance:        balance.Balance,
+				Source:         balance.Source,
+				ExchangeRate:   balance.ExchangeRate,
+				FiatValueCents: balance.FiatValueCents,
+				FetchedAt:      unixOrNil(balance.FetchedAt),
+				Error:          balance.Error,
+			})
+		}
+		wallets = append(wallets, PortfolioWalletResponse{
+			WalletID:        wallet.WalletID.String(),
+			WalletAddress:   wallet.WalletAddress,
+			WalletType:      wallet.WalletType,
+			Nickname:        wallet.Nickname,
+			TotalValueCents: wallet.TotalValueCents,
+			Balances:        balances,
+		})
+	}
+
+	return PortfolioResponse{
+		Object:          "portfolio",
+		FiatCurrency:    portfolio.FiatCurrency,
+		TotalValueCents: portfolio.TotalValueCents,
+		AsOf:            unixOrNil(portfolio.AsOf),
+		Stale:           portfolio.Stale,
+		Wallets:         wallets,
+	}
+}
+
+// toPortfolioSnapshotResponse converts a portfolio snapshot to a response
+func toPortfolioSnapshotResponse(snapshot business.PortfolioSnapshot) PortfolioSnapshotResponse {
+	holdings := make([]PortfolioHoldingResponse, 0, len(snapshot.Holdings))
+	for _, holding := range snapshot.Holdings {
+		holdings = append(holdings, PortfolioHoldingResponse{
+			NetworkID:   holding.NetworkID.String(),
+			TokenID:     holding.TokenID.String(),
+			TokenSymbol: holding.TokenSymbol,
+			Balance:     holding.Balance,
+			ValueCents:  holding.ValueCents,
+		})
+	}
+	return PortfolioSnapshotResponse{
+		Object:          "portfolio_snapshot",
+		SnapshotAt:      snapshot.SnapshotAt.Unix(),
+		FiatCurrency:    snapshot.FiatCurrency,
+		TotalValueCents: snapshot.TotalValueCents,
+		Holdings:        holdings,
+	}
+}
@@ -621,6 +621,7 @@ func (h *ProductHandler) SubscribeToProductByID(c *gin.Context) {
 			Caveats:   caveatsJSON,
 		},
 		CypheraSmartWalletAddress: h.common.GetCypheraSmartWalletAddress(),
+		SolanaDelegateAddress:     h.common.GetSolanaDelegateAddress(),
 	}); err != nil {
 		h.common.HandleError(c, err, err.Error(), http.StatusBadRequest, h.common.GetLogger())
 		return
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/requests"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RedemptionQueueHandler lets admins inspect the redemption queue and requeue tasks
+type RedemptionQueueHandler struct {
+	common  *CommonServices
+	service interfaces.RedemptionQueueService
+	logger  *zap.Logger
+}
+
+// NewRedemptionQueueHandler creates a new redemption queue handler
+func NewRedemptionQueueHandler(common *CommonServices, service interfaces.RedemptionQueueService, logger *zap.Logger) *RedemptionQueueHandler {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &RedemptionQueueHandler{
+		common:  common,
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Use types from the centralized packages
+type RequeueRedemptionTaskRequest = requests.RequeueRedemptionTaskRequest
+type RedemptionTaskResponse = responses.RedemptionTaskResponse
+type RedemptionQueueStatsResponse = responses.RedemptionQueueStatsResponse
+
+// ListRedemptionTasks godoc
+// @Summary List redemption tasks
+// @Description Lists queued redemption tasks newest first, optionally filtered by status
+// @Tags exclude
+// @Produce json
+// @Param status query string false "pending, processing, succeeded or dead_lettered"
+// @Param limit query int false "Number of tasks per page (max 100)"
+// @Param page query int false "Page number"
+// @Success 200 {object} PaginatedResponse{data=[]RedemptionTaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Router /admin/redemption-tasks [get]
+func (h *RedemptionQueueHandler) ListRedemptionTasks(c *gin.Context) {
+	pageParams, err := helpers.ParsePaginationParams(c)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	tasks, total, err := h.service.ListTasks(c.Request.Context(), c.Query("status"), pageParams.Limit, pageParams.Offset)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRedemptionTaskStatus) {
+			sendError(c, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		sendError(c, http.StatusInternalServerError, "Failed to list redemption tasks", err)
+		return
+	}
+
+	items := make([]RedemptionTaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		items = append(items, toRedemptionTaskResponse(task))
+	}
+	response := sendPaginatedSuccess(c, http.StatusOK, items, int(pageParams.Page), int(pageParams.Limit), int(total))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRedemptionQueueStats godoc
+// @Summary Get redemption queue stats
+// @Description Counts redemption tasks per status and reports the circuit breaker shared by all redemption workers
+// @Tags exclude
+// @Produce json
+// @Success 200 {object} RedemptionQueueStatsResponse
+// @Router /admin/redemption-tasks/stats [get]
+func (h *RedemptionQueueHandler) GetRedemptionQueueStats(c *gin.Context) {
+	stats, err := h.service.GetQueueStats(c.Request.Context())
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to get redemption queue stats", err)
+		return
+	}
+
+	resp := RedemptionQueueStatsResponse{
+		Statuses: make([]responses.RedemptionQueueStatusCountResponse, 0, len(stats.Statuses)),
+		CircuitBreaker: responses.CircuitBreakerResponse{
+			State:               stats.CircuitState,
+			ConsecutiveFailures: stats.ConsecutiveFailures,
+		},
+	}
+	if stats.CircuitChangedAt != nil {
+		changedAt := stats.CircuitChangedAt.Unix()
+		resp.CircuitBreaker.StateChangedAt = &changedAt
+	}
+	for _, count := range stats.Statuses {
+		item := responses.RedemptionQueueStatusCountResponse{
+			Status:     count.Status,
+			TaskCount:  count.TaskCount,
+			ReadyCount: count.ReadyCount,
+		}
+		if count.OldestAt != nil {
+			oldest := count.OldestAt.Unix()
+			item.OldestCreatedAt = &oldest
+		}
+		resp.Statuses = append(resp.Statuses, item)
+	}
+
+	sendSuccess(c, http.StatusOK, resp)
+}
+
+// GetRedemptionTask godoc
+// @Summary Get a redemption task
+// @Description Gets a redemption task with its attempts, last error and transaction
+// @Tags exclude
+// @Produce json
+// @Param task_id path string true "Redemption task ID"
+// @Success 200 {object} RedemptionTaskResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/redemption-tasks/{task_id} [get]
+func (h *RedemptionQueueHandler) GetRedemptionTask(c *gin.Context) {
+	taskID, err := uuid.Parse(c.Param("task_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid redemption task ID format", err)
+		return
+	}
+
+	task, err := h.service.GetTask(c.Request.Context(), taskID)
+	if err != nil {
+		handleDBError(c, err, "Redemption task not found")
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toRedemptionTaskResponse(*task))
+}
+
+// RequeueRedemptionTask godoc
+// @Summary Requeue a redemption task
+// @Description Makes a pending or dead-lettered task claimable straight away with a fresh set of attempts. A task dead-lettered because its redemption was interrupted is redeemed again, so check its transaction on chain first.
+// @Tags exclude
+// @Accept json
+// @Produce json
+// @Param task_id path string true "Redemption task ID"
+// @Param request body RequeueRedemptionTaskRequest false "New priority"
+// @Success 200 {object} RedemptionTaskResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/redemption-tasks/{task_id}/requeue [post]
+func (h *RedemptionQueueHandler) RequeueRedemptionTask(c *gin.Context) {
+	taskID, err := uuid.Parse(c.Param("task_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid redemption task ID format", err)
+		return
+	}
+
+	var req RequeueRedemptionTaskRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	task, err := h.service.RequeueTask(c.Request.Context(), taskID, req.Priority)
+	if err != nil {
+		if errors.Is(err, services.ErrRedemptionTaskNotRequeueable) {
+			sendError(c, http.StatusConflict, err.Error(), err)
+			return
+		}
+		handleDBError(c, err, "Redemption task not found")
+		return
+	}
+
+	h.logger.Info("Redemption task requeued",
+		zap.String("task_id", task.ID.String()),
+		zap.String("subscription_id", task.SubscriptionID.String()),
+	)
+	sendSuccess(c, http.StatusOK, toRedemptionTaskResponse(*task))
+}
+
+// toRedemptionTaskResponse converts a redemption task to a response
+func toRedemptionTaskResponse(task db.RedemptionTask) RedemptionTaskResponse {
+	resp := RedemptionTaskResponse{
+		ID:                  task.ID.String(),
+		Object:              "redemption_task",
+		SubscriptionID:      task.SubscriptionID.String(),
+		DelegationID:        task.DelegationID.String(),
+		ProductID:           task.ProductID.String(),
+		ProductTokenID:      task.ProductTokenID.String(),
+		AmountInCents:       task.AmountInCents,
+		IdempotencyKey:      task.IdempotencyKey.String,
+		Priority:            task.Priority,
+		Status:              task.Status,
+		Attempts:            task.Attempts,
+		MaxAttempts:         task.MaxAttempts,
+		VisibleAt:           task.VisibleAt.Time.Unix(),
+		LockedBy:            task.LockedBy.String,
+		RedemptionStartedAt: optionalUnix(task.RedemptionStartedAt),
+		TransactionHash:     task.TransactionHash.String,
+		LastError:           task.LastError.String,
+		CompletedAt:         optionalUnix(task.CompletedAt),
+		DeadLetteredAt:      optionalUnix(task.DeadLetteredAt),
+		CreatedAt:           task.CreatedAt.Time.Unix(),
+		UpdatedAt:           task.UpdatedAt.Time.Unix(),
+	}
+	if len(task.Metadata) > 0 && json.Valid(task.Metadata) {
+		resp.Metadata = json.RawMessage(task.Metadata)
+	}
+	return resp
+}
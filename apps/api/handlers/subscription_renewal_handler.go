@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/requests"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SubscriptionRenewalHandler lets admins review subscription renewals parked by the renewal engine
+type SubscriptionRenewalHandler struct {
+	common  *CommonServices
+	service interfaces.RenewalReviewService
+	logger  *zap.Logger
+}
+
+// NewSubscriptionRenewalHandler creates a new subscription renewal handler
+func NewSubscriptionRenewalHandler(common *CommonServices, service interfaces.RenewalReviewService, logger *zap.Logger) *SubscriptionRenewalHandler {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &SubscriptionRenewalHandler{
+		common:  common,
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Use types from the centralized packages
+type ResolveSubscriptionRenewalRequest = requests.ResolveSubscriptionRenewalRequest
+type SubscriptionRenewalResponse = responses.SubscriptionRenewalResponse
+
+// ListSubscriptionRenewals godoc
+// @Summary List subscription renewals
+// @Description Lists subscription renewals most recently updated first, optionally filtered by status. Filter by interrupted to find the renewals awaiting review.
+// @Tags exclude
+// @Produce json
+// @Param status query string false "claimed, redeeming, redeemed, succeeded, failed or interrupted"
+// @Param limit query int false "Number of renewals per page (max 100)"
+// @Param page query int false "Page number"
+// @Success 200 {object} PaginatedResponse{data=[]SubscriptionRenewalResponse}
+// @Failure 400 {object} ErrorResponse
+// @Router /admin/subscription-renewals [get]
+func (h *SubscriptionRenewalHandler) ListSubscriptionRenewals(c *gin.Context) {
+	pageParams, err := helpers.ParsePaginationParams(c)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	renewals, total, err := h.service.ListRenewals(c.Request.Context(), c.Query("status"), pageParams.Limit, pageParams.Offset)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSubscriptionRenewalStatus) {
+			sendError(c, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		sendError(c, http.StatusInternalServerError, "Failed to list subscription renewals", err)
+		return
+	}
+
+	items := make([]SubscriptionRenewalResponse, 0, len(renewals))
+	for _, renewal := range renewals {
+		items = append(items, toSubscriptionRenewalResponse(renewal))
+	}
+	response := sendPaginatedSuccess(c, http.StatusOK, items, int(pageParams.Page), int(pageParams.Limit), int(total))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSubscriptionRenewal godoc
+// @Summary Get a subscription renewal
+// @Description Gets a subscription renewal with its attempts, last error and transaction
+// @Tags exclude
+// @Produce json
+// @Param renewal_id path string true "Subscription renewal ID"
+// @Success 200 {object} SubscriptionRenewalResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/subscription-renewals/{renewal_id} [get]
+func (h *SubscriptionRenewalHandler) GetSubscriptionRenewal(c *gin.Context) {
+	renewalID, err := uuid.Parse(c.Param("renewal_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid subscription renewal ID format", err)
+		return
+	}
+
+	renewal, err := h.service.GetRenewal(c.Request.Context(), renewalID)
+	if err != nil {
+		handleDBError(c, err, "Subscription renewal not found")
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toSubscriptionRenewalResponse(*renewal))
+}
+
+// RequeueSubscriptionRenewal godoc
+// @Summary Requeue an interrupted subscription renewal
+// @Description Hands an interrupted renewal back to the renewal engine, which charges the period again on its next run. Only requeue once the interrupted redemption is known not to have gone through on chain.
+// @Tags exclude
+// @Produce json
+// @Param renewal_id path string true "Subscription renewal ID"
+// @Success 200 {object} SubscriptionRenewalResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/subscription-renewals/{renewal_id}/requeue [post]
+func (h *SubscriptionRenewalHandler) RequeueSubscriptionRenewal(c *gin.Context) {
+	renewalID, err := uuid.Parse(c.Param("renewal_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid subscription renewal ID format", err)
+		return
+	}
+
+	renewal, err := h.service.RequeueRenewal(c.Request.Context(), renewalID)
+	if err != nil {
+		if errors.Is(err, services.ErrSubscriptionRenewalNotInterrupted) {
+			sendError(c, http.StatusConflict, err.Error(), err)
+			return
+		}
+		handleDBError(c, err, "Subscription renewal not found")
+		return
+	}
+
+	h.logger.Info("Subscription renewal requeued",
+		zap.String("renewal_id", renewal.ID.String()),
+		zap.String("subscription_id", renewal.SubscriptionID.String()),
+	)
+	sendSuccess(c, http.StatusOK, toSubscriptionRenewalResponse(*renewal))
+}
+
+// ResolveSubscriptionRenewal godoc
+// @Summary Resolve an interrupted subscription renewal
+// @Description Records the transaction an interrupted renewal's redemption went through with. The renewal engine records the payment on its next run without charging the customer again.
+// @Tags exclude
+// @Accept json
+// @Produce json
+// @Param renewal_id path string true "Subscription renewal ID"
+// @Param request body ResolveSubscriptionRenewalRequest true "Transaction the redemption went through with"
+// @Success 200 {object} SubscriptionRenewalResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/subscription-renewals/{renewal_id}/resolve [post]
+func (h *SubscriptionRenewalHandler) ResolveSubscriptionRenewal(c *gin.Context) {
+	renewalID, err := uuid.Parse(c.Param("renewal_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid subscription renewal ID format", err)
+		return
+	}
+
+	var req ResolveSubscriptionRenewalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	renewal, err := h.service.ResolveRenewal(c.Request.Context(), renewalID, req.TransactionHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTransactionHashRequired):
+			sendError(c, http.StatusBadRequest, err.Error(), err)
+		case errors.Is(err, services.ErrSubscriptionRenewalNotInterrupted):
+			sendError(c, http.StatusConflict, err.Error(), err)
+		default:
+			handleDBError(c, err, "Subscription renewal not found")
+		}
+		return
+	}
+
+	h.logger.Info("Subscription renewal resolved",
+		zap.String("renewal_id", renewal.ID.String()),
+		zap.String("subscription_id", renewal.SubscriptionID.String()),
+		zap.String("tx_hash", renewal.TransactionHash.String),
+	)
+	sendSuccess(c, http.StatusOK, toSubscriptionRenewalResponse(*renewal))
+}
+
+// toSubscriptionRenewalResponse converts a subscription renewal to a response
+func toSubscriptionRenewalResponse(renewal db.SubscriptionRenewal) SubscriptionRenewalResponse {
+	return SubscriptionRenewalResponse{
+		ID:              renewal.ID.String(),
+		Object:          "subscription_renewal",
+		SubscriptionID:  renewal.SubscriptionID.String(),
+		IdempotencyKey:  renewal.IdempotencyKey,
+		PeriodDueAt:     renewal.PeriodDueAt.Time.Unix(),
+		Status:          renewal.Status,
+		Attempts:        renewal.Attempts,
+		LeaseOwner:      renewal.LeaseOwner.String,
+		LeaseExpiresAt:  optionalUnix(renewal.LeaseExpiresAt),
+		TransactionHash: renewal.TransactionHash.String,
+		ErrorMessage:    renewal.ErrorMessage.String,
+		CompletedAt:     optionalUnix(renewal.CompletedAt),
+		CreatedAt:       renewal.CreatedAt.Time.Unix(),
+		UpdatedAt:       renewal.UpdatedAt.Time.Unix(),
+	}
+}
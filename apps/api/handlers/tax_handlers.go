@@ -0,0 +1,495 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/client/vies"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/requests"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TaxHandler manages the tax jurisdiction and rate tables used by tax calculation, and the
+// verification of customer tax IDs
+type TaxHandler struct {
+	common        *CommonServices
+	taxIDVerifier interfaces.TaxIDVerificationService
+	logger        *zap.Logger
+}
+
+// NewTaxHandler creates a new tax handler
+func NewTaxHandler(common *CommonServices, taxIDVerifier interfaces.TaxIDVerificationService, logger *zap.Logger) *TaxHandler {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &TaxHandler{
+		common:        common,
+		taxIDVerifier: taxIDVerifier,
+		logger:        logger,
+	}
+}
+
+// Use types from the centralized packages
+type CreateTaxJurisdictionRequest = requests.CreateTaxJurisdictionRequest
+type UpdateTaxJurisdictionRequest = requests.UpdateTaxJurisdictionRequest
+type CreateTaxRateRequest = requests.CreateTaxRateRequest
+type EndTaxRateRequest = requests.EndTaxRateRequest
+type TaxJurisdictionResponse = responses.TaxJurisdictionResponse
+type TaxRateResponse = responses.TaxRateResponse
+type TaxIDVerificationResponse = responses.TaxIDVerificationResponse
+
+// ListJurisdictions godoc
+// @Summary List tax jurisdictions
+// @Description Lists tax jurisdictions, optionally filtered by country
+// @Tags exclude
+// @Produce json
+// @Param country query string false "ISO country code"
+// @Param limit query int false "Number of items to return"
+// @Param offset query int false "Number of items to skip"
+// @Success 200 {array} TaxJurisdictionResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /admin/tax/jurisdictions [get]
+func (h *TaxHandler) ListJurisdictions(c *gin.Context) {
+	pagination, err := helpers.ParsePaginationParams(c)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	jurisdictions, err := h.common.GetTaxService().ListJurisdictions(c.Request.Context(), c.Query("country"), pagination.Limit, pagination.Offset)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list tax jurisdictions", err)
+		return
+	}
+
+	data := make([]TaxJurisdictionResponse, len(jurisdictions))
+	for i, jurisdiction := range jurisdictions {
+		data[i] = toTaxJurisdictionResponse(jurisdiction, nil)
+	}
+	sendList(c, data)
+}
+
+// GetJurisdiction godoc
+// @Summary Get a tax jurisdiction
+// @Description Gets a tax jurisdiction with all of its past, current and scheduled rates
+// @Tags exclude
+// @Produce json
+// @Param jurisdiction_id path string true "Jurisdiction ID"
+// @Success 200 {object} TaxJurisdictionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/tax/jurisdictions/{jurisdiction_id} [get]
+func (h *TaxHandler) GetJurisdiction(c *gin.Context) {
+	jurisdictionID, err := uuid.Parse(c.Param("jurisdiction_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid jurisdiction ID format", err)
+		return
+	}
+
+	taxService := h.common.GetTaxService()
+	jurisdiction, err := taxService.GetJurisdiction(c.Request.Context(), jurisdictionID)
+	if err != nil {
+		handleDBError(c, err, "Tax jurisdiction not found")
+		return
+	}
+
+	rates, err := taxService.ListRates(c.Request.Context(), jurisdictionID)
+	if err != nil {
+		handleDBError(c, err, "Tax jurisdiction not found")
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toTaxJurisdictionResponse(*jurisdiction, rates))
+}
+
+// CreateJurisdiction godoc
+// @Summary Create a tax jurisdiction
+// @Description Creates a country, state, province or local tax jurisdiction
+// @Tags exclude
+// @Accept json
+// @Produce json
+// @Param request body CreateTaxJurisdictionRequest true "Jurisdiction details"
+// @Success 201 {object} TaxJurisdictionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/tax/jurisdictions [post]
+func (h *TaxHandler) CreateJurisdiction(c *gin.Context) {
+	var req CreateTaxJurisdictionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	createParams := params.CreateTaxJurisdictionParams{
+		Code:             req.Code,
+		Name:             req.Name,
+		JurisdictionType: req.JurisdictionType,
+		CountryCode:      req.CountryCode,
+		SubdivisionCode:  req.SubdivisionCode,
+		Locality:         req.Locality,
+		TaxType:          req.TaxType,
+		IsActive:         req.IsActive == nil || *req.IsActive,
+	}
+	if req.ParentID != "" {
+		parentID, err := uuid.Parse(req.ParentID)
+		if err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid parent ID format", err)
+			return
+		}
+		createParams.ParentID = &parentID
+	}
+	if req.Locality != "" && (req.SubdivisionCode == "" || createParams.ParentID == nil) {
+		sendError(c, http.StatusBadRequest, "Local jurisdictions require a subdivision code and a parent jurisdiction", nil)
+		return
+	}
+
+	jurisdiction, err := h.common.GetTaxService().CreateJurisdiction(c.Request.Context(), createParams)
+	if err != nil {
+		handleDBError(c, err, "Parent jurisdiction not found")
+		return
+	}
+
+	sendSuccess(c, http.StatusCreated, toTaxJurisdictionResponse(*jurisdiction, nil))
+}
+
+// UpdateJurisdiction godoc
+// @Summary Update a tax jurisdiction
+// @Description Renames a tax jurisdiction, changes its tax type or activates and deactivates it
+// @Tags exclude
+// @Accept json
+// @Produce json
+// @Param jurisdiction_id path string true "Jurisdiction ID"
+// @Param request body UpdateTaxJurisdictionRequest true "Fields to update"
+// @Success 200 {object} TaxJurisdictionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/tax/jurisdictions/{jurisdiction_id} [patch]
+func (h *TaxHandler) UpdateJurisdiction(c *gin.Context) {
+	jurisdictionID, err := uuid.Parse(c.Param("jurisdiction_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid jurisdiction ID format", err)
+		return
+	}
+
+	var req UpdateTaxJurisdictionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	jurisdiction, err := h.common.GetTaxService().UpdateJurisdiction(c.Request.Context(), params.UpdateTaxJurisdictionParams{
+		ID:       jurisdictionID,
+		Name:     req.Name,
+		TaxType:  req.TaxType,
+		IsActive: req.IsActive,
+	})
+	if err != nil {
+		handleDBError(c, err, "Tax jurisdiction not found")
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toTaxJurisdictionResponse(*jurisdiction, nil))
+}
+
+// CreateRate godoc
+// @Summary Add a tax rate
+// @Description Adds a versioned rate to a jurisdiction. An open-ended rate ends the open-ended rate in force for the same product type when it takes effect; other overlaps are rejected.
+// @Tags exclude
+// @Accept json
+// @Produce json
+// @Param jurisdiction_id path string true "Jurisdiction ID"
+// @Param request body CreateTaxRateRequest true "Rate details"
+// @Success 201 {object} TaxRateResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/tax/jurisdictions/{jurisdiction_id}/rates [post]
+func (h *TaxHandler) CreateRate(c *gin.Context) {
+	jurisdictionID, err := uuid.Parse(c.Param("jurisdiction_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid jurisdiction ID format", err)
+		return
+	}
+
+	var req CreateTaxRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	createParams := params.CreateTaxRateParams{
+		JurisdictionID: jurisdictionID,
+		ProductType:    req.ProductType,
+		Rate:           req.Rate,
+		ThresholdCents: req.ThresholdCents,
+		EffectiveFrom:  time.Unix(req.EffectiveFrom, 0),
+		RulesVersion:   req.RulesVersion,
+		Description:    req.Description,
+	}
+	if req.EffectiveTo != nil {
+		effectiveTo := time.Unix(*req.EffectiveTo, 0)
+		createParams.EffectiveTo = &effectiveTo
+	}
+
+	rate, err := h.common.GetTaxService().CreateRate(c.Request.Context(), createParams)
+	if err != nil {
+		h.handleTaxRateError(c, err, "Tax jurisdiction not found")
+		return
+	}
+
+	sendSuccess(c, http.StatusCreated, toTaxRateResponse(*rate))
+}
+
+// EndRate godoc
+// @Summary End a tax rate
+// @Description Sets or brings forward the date a tax rate stops applying
+// @Tags exclude
+// @Accept json
+// @Produce json
+// @Param rate_id path string true "Tax rate ID"
+// @Param request body EndTaxRateRequest true "End date"
+// @Success 200 {object} TaxRateResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/tax/rates/{rate_id}/end [post]
+func (h *TaxHandler) EndRate(c *gin.Context) {
+	rateID, err := uuid.Parse(c.Param("rate_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid tax rate ID format", err)
+		return
+	}
+
+	var req EndTaxRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	rate, err := h.common.GetTaxService().EndRate(c.Request.Context(), rateID, time.Unix(req.EffectiveTo, 0))
+	if err != nil {
+		h.handleTaxRateError(c, err, "Tax rate not found")
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toTaxRateResponse(*rate))
+}
+
+// DeleteRate godoc
+// @Summary Delete a scheduled tax rate
+// @Description Deletes a tax rate that has not taken effect yet. Rates already used in calculations must be ended instead.
+// @Tags exclude
+// @Produce json
+// @Param rate_id path string true "Tax rate ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/tax/rates/{rate_id} [delete]
+func (h *TaxHandler) DeleteRate(c *gin.Context) {
+	rateID, err := uuid.Parse(c.Param("rate_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid tax rate ID format", err)
+		return
+	}
+
+	if err := h.common.GetTaxService().DeleteScheduledRate(c.Request.Context(), rateID); err != nil {
+		h.handleTaxRateError(c, err, "Tax rate not found")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// VerifyCustomerTaxID godoc
+// @Summary Verify a customer's tax ID
+// @Description Checks the customer's stored tax ID with the official registry (VIES for EU VAT numbers) and records the result as evidence
+// @Tags customers
+// @Produce json
+// @Param customer_id path string true "Customer ID"
+// @Success 200 {object} TaxIDVerificationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /customers/{customer_id}/tax-id/verify [post]
+func (h *TaxHandler) VerifyCustomerTaxID(c *gin.Context) {
+	customerID, ok := h.workspaceCustomerID(c)
+	if !ok {
+		return
+	}
+
+	verification, err := h.taxIDVerifier.VerifyCustomerTaxID(c.Request.Context(), customerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCustomerHasNoTaxID),
+			errors.Is(err, services.ErrInvalidTaxIDFormat),
+			errors.Is(err, services.ErrTaxIDNotSupported):
+			sendError(c, http.StatusBadRequest, err.Error(), err)
+		case errors.Is(err, vies.ErrUnavailable):
+			sendError(c, http.StatusServiceUnavailable, "Tax ID registry is unavailable, try again later", err)
+		default:
+			handleDBError(c, err, "Customer not found")
+		}
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toTaxIDVerificationResponse(*verification))
+}
+
+// ListCustomerTaxIDVerifications godoc
+// @Summary List a customer's tax ID verifications
+// @Description Lists the registry checks made for a customer's tax ID, newest first
+// @Tags customers
+// @Produce json
+// @Param customer_id path string true "Customer ID"
+// @Param limit query int false "Number of items to return"
+// @Success 200 {array} TaxIDVerificationResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /customers/{customer_id}/tax-id/verifications [get]
+func (h *TaxHandler) ListCustomerTaxIDVerifications(c *gin.Context) {
+	customerID, ok := h.workspaceCustomerID(c)
+	if !ok {
+		return
+	}
+
+	pagination, err := helpers.ParsePaginationParams(c)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	verifications, err := h.taxIDVerifier.ListCustomerVerifications(c.Request.Context(), customerID, pagination.Limit)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list tax ID verifications", err)
+		return
+	}
+
+	data := make([]TaxIDVerificationResponse, len(verifications))
+	for i, verification := range verifications {
+		data[i] = toTaxIDVerificationResponse(verification)
+	}
+	sendList(c, data)
+}
+
+// workspaceCustomerID parses the customer ID path parameter and checks that the customer belongs
+// to the current workspace, writing the error response when it does not
+func (h *TaxHandler) workspaceCustomerID(c *gin.Context) (uuid.UUID, bool) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return uuid.Nil, false
+	}
+	customerID, err := uuid.Parse(c.Param("customer_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid customer ID format", err)
+		return uuid.Nil, false
+	}
+
+	isMember, err := h.common.GetDB().IsCustomerInWorkspace(c.Request.Context(), db.IsCustomerInWorkspaceParams{
+		WorkspaceID: workspaceID,
+		CustomerID:  customerID,
+	})
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to check customer", err)
+		return uuid.Nil, false
+	}
+	if !isMember {
+		sendError(c, http.StatusNotFound, "Customer not found", nil)
+		return uuid.Nil, false
+	}
+	return customerID, true
+}
+
+// handleTaxRateError maps tax rate validation errors to HTTP responses
+func (h *TaxHandler) handleTaxRateError(c *gin.Context, err error, notFoundMsg string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTaxRate):
+		sendError(c, http.StatusBadRequest, err.Error(), err)
+	case errors.Is(err, services.ErrTaxRateOverlap), errors.Is(err, services.ErrTaxRateInEffect):
+		sendError(c, http.StatusConflict, err.Error(), err)
+	default:
+		handleDBError(c, err, notFoundMsg)
+	}
+}
+
+// toTaxJurisdictionResponse converts a tax jurisdiction, and optionally its rates, to a response
+func toTaxJurisdictionResponse(jurisdiction db.TaxJurisdiction, rates []db.TaxRate) TaxJurisdictionResponse {
+	resp := TaxJurisdictionResponse{
+		ID:               jurisdiction.ID.String(),
+		Object:           "tax_jurisdiction",
+		Code:             jurisdiction.Code,
+		Name:             jurisdiction.Name,
+		JurisdictionType: jurisdiction.JurisdictionType,
+		CountryCode:      jurisdiction.CountryCode,
+		SubdivisionCode:  jurisdiction.SubdivisionCode.String,
+		Locality:         jurisdiction.Locality.String,
+		TaxType:          jurisdiction.TaxType,
+		IsActive:         jurisdiction.IsActive,
+		CreatedAt:        jurisdiction.CreatedAt.Time.Unix(),
+		UpdatedAt:        jurisdiction.UpdatedAt.Time.Unix(),
+	}
+	if jurisdiction.ParentID.Valid {
+		resp.ParentID = uuid.UUID(jurisdiction.ParentID.Bytes).String()
+	}
+	if rates != nil {
+		resp.Rates = make([]TaxRateResponse, len(rates))
+		for i, rate := range rates {
+			resp.Rates[i] = toTaxRateResponse(rate)
+		}
+	}
+	return resp
+}
+
+// toTaxRateResponse converts a tax rate to a response
+func toTaxRateResponse(rate db.TaxRate) TaxRateResponse {
+	resp := TaxRateResponse{
+		ID:             rate.ID.String(),
+		Object:         "tax_rate",
+		JurisdictionID: rate.JurisdictionID.String(),
+		ProductType:    rate.ProductType,
+		Rate:           helpers.GetNumericFloat(rate.Rate),
+		ThresholdCents: rate.ThresholdCents,
+		EffectiveFrom:  rate.EffectiveFrom.Time.Unix(),
+		RulesVersion:   rate.RulesVersion,
+		Description:    rate.Description.String,
+		CreatedAt:      rate.CreatedAt.Time.Unix(),
+	}
+	if rate.EffectiveTo.Valid {
+		effectiveTo := rate.EffectiveTo.Time.Unix()
+		resp.EffectiveTo = &effectiveTo
+	}
+	return resp
+}
+
+// toTaxIDVerificationResponse converts a tax ID verification to a response
+func toTaxIDVerificationResponse(verification db.TaxIDVerification) TaxIDVerificationResponse {
+	resp := TaxIDVerificationResponse{
+		ID:                 verification.ID.String(),
+		Object:             "tax_id_verification",
+		CountryCode:        verification.CountryCode,
+		TaxID:              verification.TaxID,
+		IsValid:            verification.IsValid,
+		RegisteredName:     verification.RegisteredName.String,
+		RegisteredAddress:  verification.RegisteredAddress.String,
+		ConsultationNumber: verification.ConsultationNumber.String,
+		Source:             verification.Source,
+		CheckedAt:          verification.CheckedAt.Time.Unix(),
+		ExpiresAt:          verification.ExpiresAt.Time.Unix(),
+		CreatedAt:          verification.CreatedAt.Time.Unix(),
+	}
+	if verification.CustomerID.Valid {
+		resp.CustomerID = uuid.UUID(verification.CustomerID.Bytes).String()
+	}
+	return resp
+}
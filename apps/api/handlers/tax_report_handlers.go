@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	reportFormatJSON = "json"
+	reportFormatCSV  = "csv"
+)
+
+// TaxReportHandler exports the tax summaries merchants need to file their tax returns
+type TaxReportHandler struct {
+	common        *CommonServices
+	reportService interfaces.TaxReportService
+	logger        *zap.Logger
+}
+
+// NewTaxReportHandler creates a new tax report handler
+func NewTaxReportHandler(common *CommonServices, reportService interfaces.TaxReportService, logger *zap.Logger) *TaxReportHandler {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &TaxReportHandler{
+		common:        common,
+		reportService: reportService,
+		logger:        logger,
+	}
+}
+
+// Use types from the centralized packages
+type TaxSummaryReport = responses.TaxSummaryReport
+type OSSReport = responses.OSSReport
+type ReverseChargeReport = responses.ReverseChargeReport
+type USStateTaxReport = responses.USStateTaxReport
+
+// GetTaxSummary godoc
+// @Summary Get tax summary report
+// @Description Aggregates tax by jurisdiction and rate: invoiced, voided, refunded, net and collected
+// @Tags reports
+// @Produce json,text/csv
+// @Param start_date query string true "Start date (YYYY-MM-DD), inclusive"
+// @Param end_date query string true "End date (YYYY-MM-DD), inclusive"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} TaxSummaryReport
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reports/tax/summary [get]
+func (h *TaxReportHandler) GetTaxSummary(c *gin.Context) {
+	workspaceID, start, end, format, ok := h.parseReportRequest(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetTaxSummary(c.Request.Context(), workspaceID, start, end)
+	if err != nil {
+		h.handleReportError(c, err)
+		return
+	}
+
+	if format == reportFormatJSON {
+		sendSuccess(c, http.StatusOK, report)
+		return
+	}
+	rows := make([][]string, 0, len(report.Lines))
+	for _, line := range report.Lines {
+		rows = append(rows, []string{
+			line.Jurisdiction, line.TaxType, formatRate(line.Rate), strconv.FormatBool(line.ReverseCharge), line.Currency,
+			strconv.Itoa(line.InvoiceCount), formatCents(line.TaxableAmountCents), formatCents(line.TaxInvoicedCents),
+			formatCents(line.TaxVoidedCents), formatCents(line.TaxRefundedCents), formatCents(line.NetTaxCents),
+			formatCents(line.TaxCollectedCents),
+		})
+	}
+	h.sendCSV(c, fmt.Sprintf("tax-summary-%s-%s.csv", start.Format("20060102"), end.AddDate(0, 0, -1).Format("20060102")),
+		[]string{"jurisdiction", "tax_type", "rate", "reverse_charge", "currency", "invoice_count", "taxable_amount",
+			"tax_invoiced", "tax_voided", "tax_refunded", "net_tax", "tax_collected"},
+		rows)
+}
+
+// GetOSSReport godoc
+// @Summary Get EU One-Stop-Shop VAT report
+// @Description Quarterly OSS summary of VAT per member state of consumption and rate, with corrections to earlier quarters for voided and refunded invoices
+// @Tags reports
+// @Produce json,text/csv
+// @Param year query int true "Year"
+// @Param quarter query int true "Quarter (1-4)"
+// @Param home_country query string false "Member state of establishment, whose domestic supplies are left out"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} OSSReport
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reports/tax/oss [get]
+func (h *TaxReportHandler) GetOSSReport(c *gin.Context) {
+	workspaceID, ok := h.parseWorkspaceID(c)
+	if !ok {
+		return
+	}
+	format, ok := h.parseFormat(c)
+	if !ok {
+		return
+	}
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid year", err)
+		return
+	}
+	quarter, err := strconv.Atoi(c.Query("quarter"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid quarter, expected 1-4", err)
+		return
+	}
+
+	report, err := h.reportService.GetOSSReport(c.Request.Context(), workspaceID, year, quarter, c.Query("home_country"))
+	if err != nil {
+		h.handleReportError(c, err)
+		return
+	}
+
+	if format == reportFormatJSON {
+		sendSuccess(c, http.StatusOK, report)
+		return
+	}
+	// Corrections follow the supplies of the quarter, identified by the period they correct
+	rows := make([][]string, 0, len(report.Lines)+len(report.Corrections))
+	for _, line := range report.Lines {
+		rows = append(rows, []string{
+			report.Period, "supply", line.CountryCode, formatRate(line.Rate), line.Currency,
+			formatCents(line.TaxableAmountCents), formatCents(line.VATAmountCents),
+		})
+	}
+	for _, correction := range report.Corrections {
+		rows = append(rows, []string{
+			correction.CorrectedPeriod, "correction", correction.CountryCode, "", correction.Currency,
+			"", formatCents(correction.VATAmountCents),
+		})
+	}
+	h.sendCSV(c, fmt.Sprintf("oss-%s.csv", report.Period),
+		[]string{"period", "type", "country_code", "rate", "currency", "taxable_amount", "vat_amount"},
+		rows)
+}
+
+// GetReverseChargeReport godoc
+// @Summary Get reverse-charge B2B listing
+// @Description Lists supplies under reverse charge per customer VAT number, as needed for an EC Sales List
+// @Tags reports
+// @Produce json,text/csv
+// @Param start_date query string true "Start date (YYYY-MM-DD), inclusive"
+// @Param end_date query string true "End date (YYYY-MM-DD), inclusive"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} ReverseChargeReport
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reports/tax/reverse-charge [get]
+func (h *TaxReportHandler) GetReverseChargeReport(c *gin.Context) {
+	workspaceID, start, end, format, ok := h.parseReportRequest(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetReverseChargeReport(c.Request.Context(), workspaceID, start, end)
+	if err != nil {
+		h.handleReportError(c, err)
+		return
+	}
+
+	if format == reportFormatJSON {
+		sendSuccess(c, http.StatusOK, report)
+		return
+	}
+	rows := make([][]string, 0, len(report.Lines))
+	for _, line := range report.Lines {
+		rows = append(rows, []string{
+			line.CountryCode, line.VATNumber, line.CustomerName, line.Currency, strconv.Itoa(line.InvoiceCount),
+			formatCents(line.AmountCents), line.ConsultationNumber,
+		})
+	}
+	h.sendCSV(c, fmt.Sprintf("reverse-charge-%s-%s.csv", start.Format("20060102"), end.AddDate(0, 0, -1).Format("20060102")),
+		[]string{"country_code", "vat_number", "customer_name", "currency", "invoice_count", "amount", "consultation_number"},
+		rows)
+}
+
+// GetUSStateTaxReport godoc
+// @Summary Get US sales tax report by state
+// @Description Summarizes US sales tax per state, including local jurisdictions, with transaction counts for nexus tracking
+// @Tags reports
+// @Produce json,text/csv
+// @Param start_date query string true "Start date (YYYY-MM-DD), inclusive"
+// @Param end_date query string true "End date (YYYY-MM-DD), inclusive"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} USStateTaxReport
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reports/tax/us-states [get]
+func (h *TaxReportHandler) GetUSStateTaxReport(c *gin.Context) {
+	workspaceID, start, end, format, ok := h.parseReportRequest(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetUSStateTaxReport(c.Request.Context(), workspaceID, start, end)
+	if err != nil {
+		h.handleReportError(c, err)
+		return
+	}
+
+	if format == reportFormatJSON {
+		sendSuccess(c, http.StatusOK, report)
+		return
+	}
+	rows := make([][]string, 0, len(report.Lines))
+	for _, line := range report.Lines {
+		rows = append(rows, []string{
+			line.State, line.Currency, strconv.Itoa(line.TransactionCount), formatCents(line.GrossSalesCents),
+			formatCents(line.TaxableSalesCents), formatCents(line.StateTaxCents), formatCents(line.LocalTaxCents),
+			formatCents(line.NetTaxCents), formatCents(line.TaxCollectedCents),
+		})
+	}
+	h.sendCSV(c, fmt.Sprintf("us-sales-tax-%s-%s.csv", start.Format("20060102"), end.AddDate(0, 0, -1).Format("20060102")),
+		[]string{"state", "currency", "transaction_count", "gross_sales", "taxable_sales", "state_tax", "local_tax",
+			"net_tax", "tax_collected"},
+		rows)
+}
+
+// parseReportRequest reads the workspace, date range and format of a report request, writing the
+// error response when they are invalid. The returned end is exclusive.
+func (h *TaxReportHandler) parseReportRequest(c *gin.Context) (uuid.UUID, time.Time, time.Time, string, bool) {
+	workspaceID, ok := h.parseWorkspaceID(c)
+	if !ok {
+		return uuid.Nil, time.Time{}, time.Time{}, "", false
+	}
+	format, ok := h.parseFormat(c)
+	if !ok {
+		return uuid.Nil, time.Time{}, time.Time{}, "", false
+	}
+
+	start, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid start_date format, expected YYYY-MM-DD", err)
+		return uuid.Nil, time.Time{}, time.Time{}, "", false
+	}
+	end, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid end_date format, expected YYYY-MM-DD", err)
+		return uuid.Nil, time.Time{}, time.Time{}, "", false
+	}
+	if end.Before(start) {
+		sendError(c, http.StatusBadRequest, "end_date must not be before start_date", nil)
+		return uuid.Nil, time.Time{}, time.Time{}, "", false
+	}
+
+	return workspaceID, start, end.AddDate(0, 0, 1), format, true
+}
+
+// parseWorkspaceID reads the current workspace, writing the error response when it is invalid
+func (h *TaxReportHandler) parseWorkspaceID(c *gin.Context) (uuid.UUID, bool) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return uuid.Nil, false
+	}
+	return workspaceID, true
+}
+
+// parseFormat reads the requested export format, writing the error response when it is unknown
+func (h *TaxReportHandler) parseFormat(c *gin.Context) (string, bool) {
+	format := c.DefaultQuery("format", reportFormatJSON)
+	if format != reportFormatJSON && format != reportFormatCSV {
+		sendError(c, http.StatusBadRequest, "Invalid format, expected json or csv", nil)
+		return "", false
+	}
+	return format, true
+}
+
+// handleReportError maps report errors to HTTP responses
+func (h *TaxReportHandler) handleReportError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidReportPeriod) {
+		sendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	sendError(c, http.StatusInternalServerError, "Failed to build tax report", err)
+}
+
+// sendCSV writes a report as a CSV attachment
+func (h *TaxReportHandler) sendCSV(c *gin.Context, filename string, header []string, rows [][]string) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	if err := writer.Write(header); err != nil {
+		h.logger.Error("Failed to write tax report CSV", zap.Error(err))
+		return
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		h.logger.Error("Failed to write tax report CSV", zap.Error(err))
+	}
+}
+
+// formatCents formats an amount in the smallest currency unit as a decimal, e.g. -1234 as "-12.34"
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// formatRate formats a tax rate as a percentage, e.g. 0.19 as "19"
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(math.Round(rate*1e6)/1e4, 'f', -1, 64)
+}
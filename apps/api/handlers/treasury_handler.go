@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/requests"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TreasuryHandler manages treasury sweep endpoints
+type TreasuryHandler struct {
+	common  *CommonServices
+	service interfaces.TreasuryService
+	logger  *zap.Logger
+}
+
+// NewTreasuryHandler creates a handler with interface dependency
+func NewTreasuryHandler(
+	common *CommonServices,
+	service interfaces.TreasuryService,
+	logger *zap.Logger,
+) *TreasuryHandler {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &TreasuryHandler{
+		common:  common,
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Use types from the centralized packages
+type TreasurySweepRuleRequest = requests.TreasurySweepRuleRequest
+type TreasurySweepRuleResponse = responses.TreasurySweepRuleResponse
+type TreasuryMovementResponse = responses.TreasuryMovementResponse
+
+// ListTreasurySweepRules lists the treasury sweep rules
+// @Summary List treasury sweep rules
+// @Description List the workspace's rules for sweeping Circle wallet balances into treasury wallets, with the outcome of each rule's last run
+// @Tags Treasury
+// @Produce json
+// @Success 200 {array} TreasurySweepRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /treasury/rules [get]
+func (h *TreasuryHandler) ListTreasurySweepRules(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	rules, err := h.service.ListSweepRules(c.Request.Context(), workspaceID)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list treasury sweep rules", err)
+		return
+	}
+
+	items := make([]TreasurySweepRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		items = append(items, toTreasurySweepRuleResponse(rule))
+	}
+	sendList(c, items)
+}
+
+// CreateTreasurySweepRule adds a treasury sweep rule
+// @Summary Create a treasury sweep rule
+// @Description Sweep a Circle wallet's balance of a token into a treasury wallet on the same network once it exceeds a threshold, leaving a retained amount behind. Each sweep is a transfer challenge the merchant approves with their PIN.
+// @Tags Treasury
+// @Accept json
+// @Produce json
+// @Param rule body TreasurySweepRuleRequest true "Rule"
+// @Success 201 {object} TreasurySweepRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /treasury/rules [post]
+func (h *TreasuryHandler) CreateTreasurySweepRule(c *gin.Context) {
+	ruleParams, ok := h.parseRuleRequest(c, true)
+	if !ok {
+		return
+	}
+
+	rule, err := h.service.CreateSweepRule(c.Request.Context(), ruleParams)
+	if err != nil {
+		h.handleRuleError(c, err)
+		return
+	}
+
+	sendSuccess(c, http.StatusCreated, toTreasurySweepRuleResponse(*rule))
+}
+
+// UpdateTreasurySweepRule replaces a treasury sweep rule
+// @Summary Update a treasury sweep rule
+// @Description Replace a sweep rule's name, destination wallet, amounts, fee level and schedule. The source wallet and token cannot be changed.
+// @Tags Treasury
+// @Accept json
+// @Produce json
+// @Param rule_id path string true "Rule ID"
+// @Param rule body TreasurySweepRuleRequest true "Rule"
+// @Success 200 {object} TreasurySweepRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /treasury/rules/{rule_id} [put]
+func (h *TreasuryHandler) UpdateTreasurySweepRule(c *gin.Context) {
+	ruleID, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid rule ID format", err)
+		return
+	}
+
+	ruleParams, ok := h.parseRuleRequest(c, false)
+	if !ok {
+		return
+	}
+
+	rule, err := h.service.UpdateSweepRule(c.Request.Context(), ruleID, ruleParams)
+	if err != nil {
+		h.handleRuleError(c, err)
+		return
+	}
+
+	sendSuccess(c, http.StatusOK, toTreasurySweepRuleResponse(*rule))
+}
+
+// DeleteTreasurySweepRule removes a treasury sweep rule
+// @Summary Delete a treasury sweep rule
+// @Description Stop sweeping with a rule. Sweeps already awaiting approval are still tracked in the ledger.
+// @Tags Treasury
+// @Param rule_id path string true "Rule ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /treasury/rules/{rule_id} [delete]
+func (h *TreasuryHandler) DeleteTreasurySweepRule(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+	ruleID, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid rule ID format", err)
+		return
+	}
+
+	if err := h.service.DeleteSweepRule(c.Request.Context(), workspaceID, ruleID); err != nil {
+		handleDBError(c, err, "Treasury sweep rule not found")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListTreasuryMovements lists the treasury ledger
+// @Summary List treasury movements
+// @Description List the workspace's sweeps, newest first, with their Circle transfer and on-chain outcome
+// @Tags Treasury
+// @Produce json
+// @Param rule_id query string false "Only sweeps of this rule"
+// @Param status query string false "awaiting_approval, submitted, completed, failed or expired"
+// @Param limit query int false "Number of movements per page (max 100)"
+// @Param page query int false "Page number"
+// @Success 200 {object} PaginatedResponse{data=[]TreasuryMovementResponse}
+// @Failure 400 {object} ErrorResponse
+// @Router /treasury/movements [get]
+func (h *TreasuryHandler) ListTreasuryMovements(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return
+	}
+
+	pageParams, err := helpers.ParsePaginationParams(c)
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	listParams := params.ListTreasuryMovementsParams{
+		WorkspaceID: workspaceID,
+		Status:      c.Query("status"),
+		Limit:       pageParams.Limit,
+		Offset:      pageParams.Offset,
+	}
+	switch listParams.Status {
+	case "", business.TreasuryMovementAwaitingApproval, business.TreasuryMovementSubmitted,
+		business.TreasuryMovementCompleted, business.TreasuryMovementFailed, business.TreasuryMovementExpired:
+	default:
+		sendError(c, http.StatusBadRequest, "Invalid status filter", nil)
+		return
+	}
+	if value := c.Query("rule_id"); value != "" {
+		ruleID, err := uuid.Parse(value)
+		if err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid rule ID format", err)
+			return
+		}
+		listParams.RuleID = &ruleID
+	}
+
+	movements, total, err := h.service.ListMovements(c.Request.Context(), listParams)
+	if err != nil {
+		sendError(c, http.StatusInternalServerError, "Failed to list treasury movements", err)
+		return
+	}
+
+	items := make([]TreasuryMovementResponse, 0, len(movements))
+	for _, movement := range movements {
+		items = append(items, toTreasuryMovementResponse(movement))
+	}
+	response := sendPaginatedSuccess(c, http.StatusOK, items, int(pageParams.Page), int(pageParams.Limit), int(total))
+	c.JSON(http.StatusOK, response)
+}
+
+// parseRuleRequest binds a rule request to service parameters, writing the error response when it is invalid.
+// The source wallet and token are only read when creating a rule.
+func (h *TreasuryHandler) parseRuleRequest(c *gin.Context, create bool) (params.TreasurySweepRuleParams, bool) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid workspace ID format", err)
+		return params.TreasurySweepRuleParams{}, false
+	}
+
+	var req TreasurySweepRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return params.TreasurySweepRuleParams{}, false
+	}
+
+	ruleParams := params.TreasurySweepRuleParams{
+		WorkspaceID:          workspaceID,
+		Name:                 req.Name,
+		ThresholdAmount:      req.ThresholdAmount,
+		RetainedAmount:       req.RetainedAmount,
+		FeeLevel:             req.FeeLevel,
+		SweepIntervalMinutes: req.SweepIntervalMinutes,
+		IsActive:             req.IsActive == nil || *req.IsActive,
+	}
+	ids := []struct {
+		value    string
+		field    string
+		dest     *uuid.UUID
+		required bool
+	}{
+		{req.DestinationWalletID, "destination_wallet_id", &ruleParams.DestinationWalletID, true},
+		{req.SourceWalletID, "source_wallet_id", &ruleParams.SourceWalletID, create},
+		{req.TokenID, "token_id", &ruleParams.TokenID, create},
+	}
+	for _, id := range ids {
+		if !id.required {
+			continue
+		}
+		parsed, err := uuid.Parse(id.value)
+		if err != nil {
+			sendError(c, http.StatusBadRequest, "Invalid "+id.field+" format", err)
+			return params.TreasurySweepRuleParams{}, false
+		}
+		*id.dest = parsed
+	}
+	return ruleParams, true
+}
+
+// handleRuleError maps sweep rule validation errors to HTTP responses
+func (h *TreasuryHandler) handleRuleError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidTreasuryRule) {
+		sendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	handleDBError(c, err, "Treasury sweep rule not found")
+}
+
+// toTreasurySweepRuleResponse converts a sweep rule to a response
+func toTreasurySweepRuleResponse(rule business.TreasurySweepRule) TreasurySweepRuleResponse {
+	return TreasurySweepRuleResponse{
+		ID:                   rule.ID.String(),
+		Object:               "treasury_sweep_rule",
+		Name:                 rule.Name,
+		SourceWalletID:       rule.SourceWalletID.String(),
+		DestinationWalletID:  rule.DestinationWalletID.String(),
+		TokenID:              rule.TokenID.String(),
+		ThresholdAmount:      rule.ThresholdAmount,
+		RetainedAmount:       rule.RetainedAmount,
+		FeeLevel:             rule.FeeLevel,
+		SweepIntervalMinutes: rule.SweepIntervalMinutes,
+		IsActive:             rule.IsActive,
+		LastRunAt:            unixOrNil(rule.LastRunAt),
+		LastRunResult:        rule.LastRunResult,
+		CreatedAt:            rule.CreatedAt.Unix(),
+		UpdatedAt:            rule.UpdatedAt.Unix(),
+	}
+}
+
+// toTreasuryMovementResponse converts a treasury movement to a response
+func toTreasuryMovementResponse(movement business.TreasuryMovement) TreasuryMovementResponse {
+	return TreasuryMovementResponse{
+		ID:                  movement.ID.String(),
+		Object:              "treasury_movement",
+		RuleID:              movement.RuleID.String(),
+		NetworkID:           movement.NetworkID.String(),
+		TokenID:             movement.TokenID.String(),
+		SourceWalletID:      movement.SourceWalletID.String(),
+		DestinationWalletID: movement.DestinationWalletID.String(),
+		Amount:              movement.Amount,
+		SourceBalance:       movement.SourceBalance,
+		EstimatedFee:        movement.EstimatedFee,
+		Status:              movement.Status,
+		ChallengeID:         movement.ChallengeID,
+		CircleTransactionID: movement.CircleTransactionID,
+		CircleState:         movement.CircleState,
+		TransactionHash:     movement.TransactionHash,
+		NetworkFee:          movement.NetworkFee,
+		ErrorMessage:        movement.ErrorMessage,
+		ReconciledAt:        unixOrNil(movement.ReconciledAt),
+		CompletedAt:         unixOrNil(movement.CompletedAt),
+		CreatedAt:           movement.CreatedAt.Unix(),
+		UpdatedAt:           movement.UpdatedAt.Unix(),
+	}
+}
+
+// unixOrNil converts an optional time to an optional Unix timestamp
+func unixOrNil(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	unix := t.Unix()
+	return &unix
+}
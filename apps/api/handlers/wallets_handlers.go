@@ -2,12 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/cyphera/cyphera-api/apps/api/constants"
 	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
 	"github.com/cyphera/cyphera-api/libs/go/interfaces"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/services"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/requests"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
@@ -22,6 +26,7 @@ import (
 type WalletHandler struct {
 	common        *CommonServices
 	walletService interfaces.WalletService
+	nameResolver  interfaces.NameResolver
 }
 
 // Use types from the centralized packages
@@ -35,10 +40,12 @@ type WalletListResponse = responses.WalletListResponse
 func NewWalletHandler(
 	common *CommonServices,
 	walletService interfaces.WalletService,
+	nameResolver interfaces.NameResolver,
 ) *WalletHandler {
 	return &WalletHandler{
 		common:        common,
 		walletService: walletService,
+		nameResolver:  nameResolver,
 	}
 }
 
@@ -591,15 +598,16 @@ func (h *WalletHandler) DeleteWallet(c *gin.Context) {
 
 // GetWalletsByAddress godoc
 // @Summary Get all wallets for a specific address
-// @Description Get all wallets associated with a given wallet address across different networks
+// @Description Get all wallets associated with a given wallet address across different networks. The address may be given in any casing, or as an ENS or Basenames name that is resolved to the address it points at.
 // @Tags wallets
 // @Accept json
 // @Produce json
-// @Param address path string true "Wallet address"
+// @Param address path string true "Wallet address or ENS/Basenames name"
 // @Success 200 {object} WalletsByAddressResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
 // @Security ApiKeyAuth
 // @Router /wallets/address/{address} [get]
 func (h *WalletHandler) GetWalletsByAddress(c *gin.Context) {
@@ -612,17 +620,43 @@ func (h *WalletHandler) GetWalletsByAddress(c *gin.Context) {
 	}
 
 	// Get wallet address from URL parameter
-	walletAddress := c.Param("address")
+	walletAddress := strings.TrimSpace(c.Param("address"))
 	if walletAddress == "" {
 		sendError(c, http.StatusBadRequest, "Wallet address is required", nil)
 		return
 	}
 
+	// Resolve ENS and Basenames names to the address they point at
+	var walletName string
+	if services.IsWalletName(walletAddress) {
+		if h.nameResolver == nil {
+			sendError(c, http.StatusServiceUnavailable, "Name resolution is not available", nil)
+			return
+		}
+		resolved, err := h.nameResolver.ResolveName(c.Request.Context(), walletAddress)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidWalletName):
+				sendError(c, http.StatusBadRequest, "Invalid wallet name", err)
+			case errors.Is(err, services.ErrWalletNameNotFound):
+				sendError(c, http.StatusNotFound, "No address found for this name", err)
+			case errors.Is(err, services.ErrNameResolutionUnavailable):
+				sendError(c, http.StatusServiceUnavailable, "Name resolution is not available", err)
+			default:
+				sendError(c, http.StatusInternalServerError, "Failed to resolve wallet name", err)
+			}
+			return
+		}
+		walletName = strings.ToLower(walletAddress)
+		walletAddress = resolved
+	}
+
 	// Validate the address format (basic Ethereum address validation)
-	if len(walletAddress) != 42 || walletAddress[:2] != "0x" {
+	if !helpers.IsAddressValid(walletAddress) {
 		sendError(c, http.StatusBadRequest, "Invalid wallet address format", nil)
 		return
 	}
+	walletAddress = helpers.NormalizeWalletAddress(walletAddress, string(db.NetworkTypeEvm))
 
 	// Get all wallets for this address
 	wallets, err := h.common.db.ListWalletsByAddress(c.Request.Context(), db.ListWalletsByAddressParams{
@@ -731,12 +765,14 @@ func (h *WalletHandler) GetWalletsByAddress(c *gin.Context) {
 	// Response structure
 	type WalletsByAddressResponse struct {
 		Address string              `json:"address"`
+		Name    string              `json:"name,omitempty"`
 		Wallets []WalletWithNetwork `json:"wallets"`
 		Count   int                 `json:"count"`
 	}
 
 	sendSuccess(c, http.StatusOK, WalletsByAddressResponse{
 		Address: walletAddress,
+		Name:    walletName,
 		Wallets: walletsWithNetworks,
 		Count:   len(walletsWithNetworks),
 	})
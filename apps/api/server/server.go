@@ -17,8 +17,12 @@ import (
 	"github.com/cyphera/cyphera-api/libs/go/client/coinmarketcap" // Import CMC client
 	dsClient "github.com/cyphera/cyphera-api/libs/go/client/delegation_server"
 	"github.com/cyphera/cyphera-api/libs/go/client/payment_sync"
+	"github.com/cyphera/cyphera-api/libs/go/client/solana"
+	"github.com/cyphera/cyphera-api/libs/go/client/tax_provider"
+	"github.com/cyphera/cyphera-api/libs/go/client/vies"
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/helpers" // Import helpers
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
 	"github.com/cyphera/cyphera-api/libs/go/middleware"
 	"github.com/cyphera/cyphera-api/libs/go/services"
@@ -54,14 +58,25 @@ var (
 	currencyHandler               *handlers.CurrencyHandler
 	analyticsHandler              *handlers.AnalyticsHandler
 	gasSponsorshipHandler         *handlers.GasSponsorshipHandler
+	treasuryHandler               *handlers.TreasuryHandler
+	portfolioHandler              *handlers.PortfolioHandler
+	analyticsExportHandler        *handlers.AnalyticsExportHandler
 	invoiceHandler                *handlers.InvoiceHandler
 	paymentLinkHandler            *handlers.PaymentLinkHandler
 	paymentPageHandler            *handlers.PaymentPageHandler
 	dunningHandler                *handlers.DunningHandler
+	customerPortalHandler         *handlers.CustomerPortalHandler
+	taxHandler                    *handlers.TaxHandler
+	taxReportHandler              *handlers.TaxReportHandler
+	redemptionQueueHandler        *handlers.RedemptionQueueHandler
+	subscriptionRenewalHandler    *handlers.SubscriptionRenewalHandler
 
 	// Database
 	dbQueries *db.Queries
 
+	// Rate limiting
+	quotaRateLimiter *middleware.QuotaRateLimiter
+
 	// Clients
 	authClient *auth.AuthClient
 
@@ -218,6 +233,10 @@ func InitializeHandlers() {
 	if err != nil || paymentSyncEncryptionKey == "" {
 		logger.Fatal("Failed to get Payment Sync Encryption Key", zap.Error(err))
 	}
+	paymentSyncKeys, err := payment_sync.LoadKeyProvider(ctx, secretsClient, paymentSyncEncryptionKey)
+	if err != nil {
+		logger.Fatal("Failed to initialize Payment Sync key provider", zap.Error(err))
+	}
 
 	// --- Resend API Key ---
 	resendAPIKey, err := secretsClient.GetSecretString(ctx, "RESEND_API_KEY_ARN", "RESEND_API_KEY")
@@ -228,6 +247,16 @@ func InitializeHandlers() {
 		logger.Log.Info("Successfully retrieved Resend API Key")
 	}
 
+	// --- External Tax Provider (optional) ---
+	var taxProvider interfaces.TaxProvider
+	taxProviderAPIKey, err := secretsClient.GetSecretString(ctx, "TAX_PROVIDER_API_KEY_ARN", "TAX_PROVIDER_API_KEY")
+	if err != nil || taxProviderAPIKey == "" {
+		logger.Log.Info("No tax provider API key configured. Tax will be calculated from internal rate tables.")
+	} else {
+		taxProvider = tax_provider.NewClient(taxProviderAPIKey, os.Getenv("TAX_PROVIDER_URL"))
+		logger.Log.Info("External tax provider enabled", zap.String("provider", taxProvider.Name()))
+	}
+
 	// --- Database Pool Initialization ---
 	// Parse the DSN configuration first
 	poolConfig, err := pgxpool.ParseConfig(dsn)
@@ -250,6 +279,27 @@ func InitializeHandlers() {
 	// Create queries instance with the connection pool
 	dbQueries = db.New(dbpool)
 
+	// --- Rate Limiting Backend ---
+	// Deployed stages run many Lambda instances, so counters must live in the shared database
+	rateLimitBackend := os.Getenv("RATE_LIMIT_BACKEND")
+	if rateLimitBackend == "" {
+		rateLimitBackend = "memory"
+		if stage == helpers.StageProd || stage == helpers.StageDev {
+			rateLimitBackend = "postgres"
+		}
+	}
+	var rateLimitStore middleware.RateLimitStore
+	switch rateLimitBackend {
+	case "postgres":
+		rateLimitStore = middleware.NewPostgresRateLimitStore(dbQueries)
+	case "memory":
+		rateLimitStore = middleware.NewMemoryRateLimitStore()
+	default:
+		logger.Fatal("Invalid RATE_LIMIT_BACKEND, must be 'memory' or 'postgres'", zap.String("backend", rateLimitBackend))
+	}
+	quotaRateLimiter = middleware.NewQuotaRateLimiter(rateLimitStore, dbQueries)
+	logger.Info("Rate limiting configured", zap.String("backend", rateLimitBackend))
+
 	cypheraSmartWalletAddress := os.Getenv("CYPHERA_SMART_WALLET_ADDRESS")
 	if cypheraSmartWalletAddress == "" {
 		logger.Fatal("CYPHERA_SMART_WALLET_ADDRESS environment variable is required")
@@ -308,7 +358,7 @@ func InitializeHandlers() {
 	}
 
 	// Initialize PaymentSyncClient with encryption key
-	paymentSyncClient := payment_sync.NewPaymentSyncClient(dbQueries, logger.Log, paymentSyncEncryptionKey)
+	paymentSyncClient := payment_sync.NewPaymentSyncClientWithKeyProvider(dbQueries, logger.Log, paymentSyncEncryptionKey, paymentSyncKeys)
 
 	// Get additional configurations
 	fromEmail := os.Getenv("EMAIL_FROM_ADDRESS")
@@ -327,6 +377,46 @@ func InitializeHandlers() {
 	if rpcAPIKey == "" {
 		logger.Warn("RPC_API_KEY not set, blockchain service functionality may be limited")
 	}
+	rpcConfig, err := services.RPCPoolConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid RPC endpoint configuration", zap.Error(err))
+	}
+
+	// The Solana payment delegate is optional; without it Solana subscriptions cannot be charged
+	var solanaDelegate *solana.Keypair
+	if solanaDelegateSecret, err := secretsClient.GetSecretString(ctx, "SOLANA_DELEGATE_KEYPAIR_ARN", "SOLANA_DELEGATE_KEYPAIR"); err != nil || solanaDelegateSecret == "" {
+		logger.Info("Solana delegate keypair not configured, Solana subscriptions are disabled")
+	} else if solanaDelegate, err = solana.ParseKeypair(solanaDelegateSecret); err != nil {
+		logger.Fatal("Invalid Solana delegate keypair", zap.Error(err))
+	}
+
+	// Analytics exports must be stored in S3 in deployed stages; the local filesystem is for local development
+	exportStorageConfig := services.ExportStorageConfigFromEnv()
+	if stage != helpers.StageLocal && exportStorageConfig.Backend != services.ExportStorageS3 {
+		logger.Fatal("Deployed stages must store analytics exports in S3, set EXPORT_STORAGE_BACKEND=s3",
+			zap.String("backend", exportStorageConfig.Backend))
+	}
+	exportStorage, err := services.NewExportStorage(ctx, exportStorageConfig)
+	if err != nil {
+		logger.Fatal("Failed to configure analytics export storage", zap.Error(err))
+	}
+
+	// Customer wallets and address search resolve ENS and Basenames names through the network RPCs
+	nameResolverConfig, err := services.NameResolverConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid name resolver configuration", zap.Error(err))
+	}
+	walletNameConfig, err := services.WalletNameConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid wallet name configuration", zap.Error(err))
+	}
+
+	// Redemptions are simulated against the DelegationManager to estimate their network fee, and delegations
+	// signed in the customer portal are checked against the caveat enforcers
+	delegationConfig, err := services.DelegationMonitorConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid delegation configuration", zap.Error(err))
+	}
 
 	// Create the handler factory with all dependencies
 	handlerFactory = handlers.CreateDefaultFactory(
@@ -340,8 +430,16 @@ func InitializeHandlers() {
 		fromName,
 		baseURL,
 		rpcAPIKey,
+		rpcConfig,
+		solanaDelegate,
 		delegationClient,
 		paymentSyncClient,
+		taxProvider,
+		vies.NewClient(os.Getenv("VIES_URL"), os.Getenv("VIES_REQUESTER_VAT_NUMBER")),
+		exportStorage,
+		nameResolverConfig,
+		walletNameConfig,
+		delegationConfig,
 	)
 
 	// Get common services from factory
@@ -375,6 +473,7 @@ func InitializeHandlers() {
 
 	// Analytics handler
 	analyticsHandler = handlerFactory.NewAnalyticsHandler()
+	analyticsExportHandler = handlerFactory.NewAnalyticsExportHandler()
 	// Gas sponsorship handler
 	gasSponsorshipHandler = handlerFactory.NewGasSponsorshipHandler()
 	// Invoice handler
@@ -390,8 +489,31 @@ func InitializeHandlers() {
 	// Initialize subscription management handler
 	subscriptionManagementHandler = handlerFactory.NewSubscriptionManagementHandler()
 
+	// Customer self-service portal handler
+	customerPortalHandler = handlerFactory.NewCustomerPortalHandler()
+
+	// Tax jurisdiction and rate management handler
+	taxHandler = handlerFactory.NewTaxHandler()
+	taxReportHandler = handlerFactory.NewTaxReportHandler()
+	redemptionQueueHandler = handlerFactory.NewRedemptionQueueHandler()
+	subscriptionRenewalHandler = handlerFactory.NewSubscriptionRenewalHandler()
+
 	// 3rd party handlers
 	circleHandler = handlers.NewCircleHandler(commonServices, circleClient)
+
+	// Treasury sweeps move funds out of merchants' Circle wallets
+	treasuryConfig, err := services.TreasuryConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid treasury configuration", zap.Error(err))
+	}
+	treasuryHandler = handlers.NewTreasuryHandler(commonServices, services.NewTreasuryService(dbQueries, circleClient, treasuryConfig), logger.Log)
+
+	// The portfolio reads Circle wallets through Circle and every other wallet from the chain
+	portfolioConfig, err := services.PortfolioConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid portfolio configuration", zap.Error(err))
+	}
+	portfolioHandler = handlerFactory.NewPortfolioHandler(circleClient, portfolioConfig)
 }
 
 func InitializeRoutes(router *gin.Engine) {
@@ -404,7 +526,7 @@ func InitializeRoutes(router *gin.Engine) {
 	router.Use(middleware.CorrelationIDMiddleware())
 
 	// Apply rate limiting middleware globally
-	// This provides a default rate limit for all endpoints
+	// This provides a per-instance flood guard; tenant quotas are applied per route class below
 	router.Use(middleware.DefaultRateLimiter.Middleware())
 
 	// Add enhanced logging in development mode
@@ -432,8 +554,8 @@ func InitializeRoutes(router *gin.Engine) {
 		})
 	})
 
-	// Initialize and start the redemption processor with 3 workers and a buffer size of 100
-	redemptionProcessor = handlerFactory.CreateRedemptionProcessor(delegationClient, 3, 100)
+	// Initialize and start the redemption processor with 3 workers polling the durable redemption queue
+	redemptionProcessor = handlerFactory.CreateRedemptionProcessor(delegationClient, 3, services.DefaultRedemptionQueueConfig())
 	redemptionProcessor.Start()
 
 	// Ensure we gracefully stop the redemption processor when the server shuts down
@@ -454,24 +576,52 @@ func InitializeRoutes(router *gin.Engine) {
 	{
 		// Public routes (no authentication required)
 		// Payment link by slug - public endpoint for customers to view payment links
-		v1.GET("/payment-links/slug/:slug", paymentLinkHandler.GetPaymentLinkBySlug)
+		publicRateLimit := quotaRateLimiter.Middleware(middleware.RouteClassPublic)
+		v1.GET("/payment-links/slug/:slug", publicRateLimit, paymentLinkHandler.GetPaymentLinkBySlug)
 
 		// Payment page endpoints - public endpoints for payment processing
-		v1.GET("/payment-pages/:slug", paymentPageHandler.GetPaymentPageData)
-		v1.POST("/payment-pages/:slug/intent", paymentPageHandler.CreatePaymentIntent)
+		v1.GET("/payment-pages/:slug", publicRateLimit, paymentPageHandler.GetPaymentPageData)
+		v1.POST("/payment-pages/:slug/intent", publicRateLimit, paymentPageHandler.CreatePaymentIntent)
+
+		authAdapter := handlers.NewAuthServicesAdapter(commonServices)
+
+		// Customer portal routes (portal session link or the customer's own Web3Auth token)
+		portal := v1.Group("/portal")
+		portal.Use(publicRateLimit, authClient.EnsureValidCustomerAuth(authAdapter, handlerFactory.GetCustomerPortalService()))
+		{
+			portal.GET("/me", customerPortalHandler.GetMe)
+			portal.PATCH("/me/billing", customerPortalHandler.UpdateBillingDetails)
+			portal.GET("/wallets", customerPortalHandler.ListWallets)
+			portal.GET("/subscriptions", customerPortalHandler.ListSubscriptions)
+			portal.POST("/subscriptions/:subscription_id/cancel", customerPortalHandler.CancelSubscription)
+			portal.POST("/subscriptions/:subscription_id/preview-cancel", customerPortalHandler.PreviewCancellation)
+			portal.POST("/subscriptions/:subscription_id/pause", customerPortalHandler.PauseSubscription)
+			portal.POST("/subscriptions/:subscription_id/resume", customerPortalHandler.ResumeSubscription)
+			portal.POST("/subscriptions/:subscription_id/wallet", customerPortalHandler.SwitchSubscriptionWallet)
+			portal.POST("/subscriptions/:subscription_id/reauthorize", customerPortalHandler.ReauthorizeSubscription)
+			portal.GET("/invoices", customerPortalHandler.ListInvoices)
+			portal.GET("/payments", customerPortalHandler.ListPayments)
+		}
+
+		// Admin sign-in routes only count against the stricter auth budget, not the workspace's API quota
+		adminSignIn := v1.Group("/admin")
+		adminSignIn.Use(authClient.EnsureValidAPIKeyOrToken(authAdapter), authClient.RequireRoles("admin"))
+		{
+			authRateLimit := quotaRateLimiter.Middleware(middleware.RouteClassAuth)
+			adminSignIn.POST("/accounts/signin", authRateLimit, accountHandler.SignInRegisterAccount)
+			adminSignIn.POST("/customers/signin", authRateLimit, customerHandler.SignInRegisterCustomer)
+		}
 
 		// Protected routes (authentication required)
 		protected := v1.Group("/")
-		authAdapter := handlers.NewAuthServicesAdapter(commonServices)
 		protected.Use(authClient.EnsureValidAPIKeyOrToken(authAdapter))
+		// Per-workspace and per-API-key quotas (needs the auth context set above)
+		protected.Use(quotaRateLimiter.Middleware(middleware.RouteClassAuthenticated))
 		{
 			// Admin-only routes
 			admin := protected.Group("/admin")
 			admin.Use(authClient.RequireRoles("admin"))
 			{
-				// Apply stricter rate limiting for auth endpoints
-				admin.POST("/accounts/signin", middleware.StrictRateLimiter.Middleware(), accountHandler.SignInRegisterAccount)
-				admin.POST("/customers/signin", middleware.StrictRateLimiter.Middleware(), customerHandler.SignInRegisterCustomer)
 				admin.GET("/products/:product_id", productHandler.GetPublicProductByID)
 
 				// subscribe to a product
@@ -505,6 +655,36 @@ func InitializeRoutes(router *gin.Engine) {
 				admin.PUT("/networks/:network_id", networkHandler.UpdateNetwork)
 				admin.DELETE("/networks/:network_id", networkHandler.DeleteNetwork)
 
+				// Tax jurisdictions and rates
+				tax := admin.Group("/tax")
+				{
+					tax.GET("/jurisdictions", taxHandler.ListJurisdictions)
+					tax.POST("/jurisdictions", taxHandler.CreateJurisdiction)
+					tax.GET("/jurisdictions/:jurisdiction_id", taxHandler.GetJurisdiction)
+					tax.PATCH("/jurisdictions/:jurisdiction_id", taxHandler.UpdateJurisdiction)
+					tax.POST("/jurisdictions/:jurisdiction_id/rates", taxHandler.CreateRate)
+					tax.POST("/rates/:rate_id/end", taxHandler.EndRate)
+					tax.DELETE("/rates/:rate_id", taxHandler.DeleteRate)
+				}
+
+				// Durable redemption queue
+				redemptionTasks := admin.Group("/redemption-tasks")
+				{
+					redemptionTasks.GET("", redemptionQueueHandler.ListRedemptionTasks)
+					redemptionTasks.GET("/stats", redemptionQueueHandler.GetRedemptionQueueStats)
+					redemptionTasks.GET("/:task_id", redemptionQueueHandler.GetRedemptionTask)
+					redemptionTasks.POST("/:task_id/requeue", redemptionQueueHandler.RequeueRedemptionTask)
+				}
+
+				// Subscription renewals parked for review
+				subscriptionRenewals := admin.Group("/subscription-renewals")
+				{
+					subscriptionRenewals.GET("", subscriptionRenewalHandler.ListSubscriptionRenewals)
+					subscriptionRenewals.GET("/:renewal_id", subscriptionRenewalHandler.GetSubscriptionRenewal)
+					subscriptionRenewals.POST("/:renewal_id/requeue", subscriptionRenewalHandler.RequeueSubscriptionRenewal)
+					subscriptionRenewals.POST("/:renewal_id/resolve", subscriptionRenewalHandler.ResolveSubscriptionRenewal)
+				}
+
 				// Circle API endpoints
 				circle := admin.Group("/circle")
 				{
@@ -583,6 +763,19 @@ func InitializeRoutes(router *gin.Engine) {
 
 				// Customer subscriptions
 				customers.GET("/:customer_id/subscriptions", subscriptionHandler.ListSubscriptionsByCustomer)
+
+				// Customer tax ID verification
+				customers.POST("/:customer_id/tax-id/verify", taxHandler.VerifyCustomerTaxID)
+				customers.GET("/:customer_id/tax-id/verifications", taxHandler.ListCustomerTaxIDVerifications)
+			}
+
+			// Customer portal links and settings
+			customerPortal := protected.Group("/customer-portal")
+			{
+				customerPortal.POST("/sessions", customerPortalHandler.CreatePortalSession)
+				customerPortal.DELETE("/sessions/:session_id", customerPortalHandler.RevokePortalSession)
+				customerPortal.GET("/settings", customerPortalHandler.GetPortalSettings)
+				customerPortal.PUT("/settings", customerPortalHandler.UpdatePortalSettings)
 			}
 
 			// API Keys
@@ -594,6 +787,8 @@ func InitializeRoutes(router *gin.Engine) {
 				apiKeys.GET("/:api_key_id", apiKeyHandler.GetAPIKeyByID)
 				apiKeys.PUT("/:api_key_id", middleware.ValidateInput(middleware.CreateAPIKeyValidation), apiKeyHandler.UpdateAPIKey)
 				apiKeys.DELETE("/:api_key_id", apiKeyHandler.DeleteAPIKey)
+				apiKeys.POST("/:api_key_id/rotate", apiKeyHandler.RotateAPIKey)
+				apiKeys.GET("/:api_key_id/usage", apiKeyHandler.GetAPIKeyUsage)
 			}
 
 			// Products
@@ -761,15 +956,38 @@ func InitializeRoutes(router *gin.Engine) {
 				analytics.GET("/customer-chart", analyticsHandler.GetCustomerChart)
 				analytics.GET("/subscription-chart", analyticsHandler.GetSubscriptionChart)
 				analytics.GET("/mrr-chart", analyticsHandler.GetMRRChart)
+				analytics.GET("/mrr-movements", analyticsHandler.GetMRRMovements)
 
 				// Metrics endpoints
 				analytics.GET("/payment-metrics", analyticsHandler.GetPaymentMetrics)
 				analytics.GET("/network-breakdown", analyticsHandler.GetNetworkBreakdown)
 				analytics.GET("/gas-fee-pie", analyticsHandler.GetGasFeePieChart)
+				analytics.GET("/gas-fee-accuracy", analyticsHandler.GetGasFeeEstimateAccuracy)
 				analytics.GET("/hourly", analyticsHandler.GetHourlyMetrics)
+				analytics.GET("/cohorts", analyticsHandler.GetCohortAnalysis)
 
 				// Refresh metrics
 				analytics.POST("/refresh", analyticsHandler.RefreshMetrics)
+
+				// Exports and scheduled reports
+				analytics.POST("/exports", analyticsExportHandler.CreateAnalyticsExport)
+				analytics.GET("/exports", analyticsExportHandler.ListAnalyticsExports)
+				analytics.GET("/exports/:export_id", analyticsExportHandler.GetAnalyticsExport)
+				analytics.GET("/exports/:export_id/files/:dataset", analyticsExportHandler.DownloadAnalyticsExportFile)
+				analytics.GET("/report-schedules", analyticsExportHandler.ListAnalyticsReportSchedules)
+				analytics.POST("/report-schedules", analyticsExportHandler.CreateAnalyticsReportSchedule)
+				analytics.GET("/report-schedules/:schedule_id", analyticsExportHandler.GetAnalyticsReportSchedule)
+				analytics.PUT("/report-schedules/:schedule_id", analyticsExportHandler.UpdateAnalyticsReportSchedule)
+				analytics.DELETE("/report-schedules/:schedule_id", analyticsExportHandler.DeleteAnalyticsReportSchedule)
+			}
+
+			// Tax reports, as JSON or CSV
+			taxReports := protected.Group("/reports/tax")
+			{
+				taxReports.GET("/summary", taxReportHandler.GetTaxSummary)
+				taxReports.GET("/oss", taxReportHandler.GetOSSReport)
+				taxReports.GET("/reverse-charge", taxReportHandler.GetReverseChargeReport)
+				taxReports.GET("/us-states", taxReportHandler.GetUSStateTaxReport)
 			}
 
 			// Gas sponsorship routes
@@ -781,6 +999,31 @@ func InitializeRoutes(router *gin.Engine) {
 
 				// Budget status
 				gasSponsorship.GET("/budget-status", gasSponsorshipHandler.GetGasSponsorshipBudgetStatus)
+				gasSponsorship.GET("/ledger", gasSponsorshipHandler.ListGasSponsorshipLedger)
+
+				// Policy rules
+				gasSponsorship.GET("/rules", gasSponsorshipHandler.ListGasSponsorshipRules)
+				gasSponsorship.POST("/rules", gasSponsorshipHandler.CreateGasSponsorshipRule)
+				gasSponsorship.PUT("/rules/:rule_id", gasSponsorshipHandler.UpdateGasSponsorshipRule)
+				gasSponsorship.DELETE("/rules/:rule_id", gasSponsorshipHandler.DeleteGasSponsorshipRule)
+				gasSponsorship.POST("/simulate", gasSponsorshipHandler.SimulateGasSponsorship)
+			}
+
+			// Treasury sweep routes
+			treasury := protected.Group("/treasury")
+			{
+				treasury.GET("/rules", treasuryHandler.ListTreasurySweepRules)
+				treasury.POST("/rules", treasuryHandler.CreateTreasurySweepRule)
+				treasury.PUT("/rules/:rule_id", treasuryHandler.UpdateTreasurySweepRule)
+				treasury.DELETE("/rules/:rule_id", treasuryHandler.DeleteTreasurySweepRule)
+				treasury.GET("/movements", treasuryHandler.ListTreasuryMovements)
+			}
+
+			// Portfolio routes
+			portfolio := protected.Group("/portfolio")
+			{
+				portfolio.GET("", portfolioHandler.GetPortfolio)
+				portfolio.GET("/history", portfolioHandler.GetPortfolioHistory)
 			}
 
 			// Invoice routes
@@ -886,7 +1129,7 @@ func configureCORS() gin.HandlerFunc {
 	// Get allowed headers from environment variable
 	headersEnv := os.Getenv("CORS_ALLOWED_HEADERS")
 	if headersEnv == "" {
-		corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "X-Workspace-ID", "X-Account-ID", "X-Correlation-ID"}
+		corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "X-Workspace-ID", "X-Account-ID", "X-Correlation-ID", "X-Portal-Session"}
 	} else {
 		headers := strings.Split(headersEnv, ",")
 		for i, header := range headers {
@@ -906,6 +1149,10 @@ func configureCORS() gin.HandlerFunc {
 	} else {
 		// Default exposed headers including rate limit headers
 		corsConfig.ExposeHeaders = []string{
+			"RateLimit-Limit",
+			"RateLimit-Remaining",
+			"RateLimit-Reset",
+			"RateLimit-Policy",
 			"X-RateLimit-Limit",
 			"X-RateLimit-Remaining",
 			"X-RateLimit-Reset",
@@ -0,0 +1,146 @@
+/**
+ * Error codes of the delegation.ErrorCode proto enum. The proto is loaded with enums as strings,
+ * so responses carry the enum value names.
+ */
+export const ErrorCode = {
+  UNSPECIFIED: 'ERROR_CODE_UNSPECIFIED',
+  INVALID_REQUEST: 'ERROR_CODE_INVALID_REQUEST',
+  INVALID_DELEGATION: 'ERROR_CODE_INVALID_DELEGATION',
+  EXECUTION_REVERTED: 'ERROR_CODE_EXECUTION_REVERTED',
+  NETWORK_ERROR: 'ERROR_CODE_NETWORK_ERROR',
+  SMART_ACCOUNT_ERROR: 'ERROR_CODE_SMART_ACCOUNT_ERROR',
+  USER_OPERATION_FAILED: 'ERROR_CODE_USER_OPERATION_FAILED',
+  CONFIRMATION_TIMEOUT: 'ERROR_CODE_CONFIRMATION_TIMEOUT',
+  TRANSACTION_NOT_FOUND: 'ERROR_CODE_TRANSACTION_NOT_FOUND',
+  INTERNAL: 'ERROR_CODE_INTERNAL'
+} as const;
+
+export type ErrorCodeValue = typeof ErrorCode[keyof typeof ErrorCode];
+
+/**
+ * Structured error matching the delegation.RedemptionError proto message
+ */
+export interface StructuredError {
+  code: ErrorCodeValue;
+  message: string;
+  revert_reason: string;
+  retryable: boolean;
+}
+
+/**
+ * Error thrown for requests that are missing fields or have invalid values
+ */
+export class InvalidRequestError extends Error {
+  constructor(message: string) {
+    super(message);
+    this.name = 'InvalidRequestError';
+  }
+}
+
+/**
+ * Error thrown when a transaction is not known to the network
+ */
+export class TransactionNotFoundError extends Error {
+  constructor(transactionHash: string) {
+    super(`Transaction ${transactionHash} not found`);
+    this.name = 'TransactionNotFoundError';
+  }
+}
+
+/**
+ * Error codes for the RedemptionErrorType values of the shared delegation library. Errors are matched
+ * by name and type rather than instanceof so that this module does not load the chain libraries.
+ */
+const redemptionErrorCodes: Record<string, ErrorCodeValue> = {
+  VALIDATION_ERROR: ErrorCode.INVALID_REQUEST,
+  NETWORK_ERROR: ErrorCode.NETWORK_ERROR,
+  SMART_ACCOUNT_ERROR: ErrorCode.SMART_ACCOUNT_ERROR,
+  USER_OPERATION_ERROR: ErrorCode.USER_OPERATION_FAILED,
+  DELEGATION_ERROR: ErrorCode.INVALID_DELEGATION,
+  UNKNOWN_ERROR: ErrorCode.INTERNAL
+};
+
+/**
+ * Walks an error and its causes, including the originalError kept in RedemptionError details
+ */
+function errorChain(error: unknown): unknown[] {
+  const chain: unknown[] = [];
+  let current: unknown = error;
+  while (current && chain.length < 10 && !chain.includes(current)) {
+    chain.push(current);
+    const withCause = current as { cause?: unknown; details?: unknown };
+    const details = withCause.details as { originalError?: unknown } | undefined;
+    current = details?.originalError ?? (details instanceof Error ? details : undefined) ?? withCause.cause;
+  }
+  return chain;
+}
+
+/**
+ * Extracts the decoded revert reason from a viem contract or UserOperation error
+ * @param error The error thrown by a chain call
+ * @returns The revert reason, or an empty string if the chain did not revert
+ */
+export function extractRevertReason(error: unknown): string {
+  for (const item of errorChain(error)) {
+    const candidate = item as { reason?: unknown; name?: string; shortMessage?: string };
+    if (typeof candidate.reason === 'string' && candidate.reason) {
+      return candidate.reason;
+    }
+    if (candidate.name === 'ContractFunctionRevertedError' || candidate.name === 'ExecutionRevertedError') {
+      return candidate.shortMessage || '';
+    }
+  }
+  return '';
+}
+
+/**
+ * Whether the error chain contains a revert from the chain
+ */
+function isExecutionReverted(error: unknown): boolean {
+  return errorChain(error).some(item => {
+    const name = (item as { name?: string }).name;
+    return name === 'ContractFunctionRevertedError' ||
+      name === 'ExecutionRevertedError' ||
+      name === 'UserOperationRevertedError';
+  });
+}
+
+/**
+ * Whether a UserOperation was sent but its receipt did not arrive in time, so it may still be mined
+ */
+function isConfirmationTimeout(error: unknown): boolean {
+  return errorChain(error).some(item =>
+    (item as { name?: string }).name === 'WaitForUserOperationReceiptTimeoutError'
+  );
+}
+
+/**
+ * Converts any error thrown while serving a request into a structured error
+ * @param error The error to convert
+ * @returns The structured error to return to the client
+ */
+export function toStructuredError(error: unknown): StructuredError {
+  const message = error instanceof Error ? error.message : String(error);
+  const chain = errorChain(error);
+  const revertReason = extractRevertReason(error);
+
+  if (chain.some(item => item instanceof InvalidRequestError)) {
+    return { code: ErrorCode.INVALID_REQUEST, message, revert_reason: '', retryable: false };
+  }
+  if (chain.some(item => item instanceof TransactionNotFoundError)) {
+    return { code: ErrorCode.TRANSACTION_NOT_FOUND, message, revert_reason: '', retryable: true };
+  }
+  if (isConfirmationTimeout(error)) {
+    return { code: ErrorCode.CONFIRMATION_TIMEOUT, message, revert_reason: revertReason, retryable: false };
+  }
+  if (revertReason || isExecutionReverted(error)) {
+    return { code: ErrorCode.EXECUTION_REVERTED, message, revert_reason: revertReason, retryable: false };
+  }
+
+  const redemptionError = chain.find(item => (item as { name?: string }).name === 'RedemptionError') as { type?: string } | undefined;
+  if (redemptionError) {
+    const code = redemptionErrorCodes[redemptionError.type ?? ''] ?? ErrorCode.INTERNAL;
+    return { code, message, revert_reason: '', retryable: code === ErrorCode.NETWORK_ERROR };
+  }
+  return { code: ErrorCode.INTERNAL, message, revert_reason: '', retryable: false };
+}
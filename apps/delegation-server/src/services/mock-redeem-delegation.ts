@@ -6,6 +6,12 @@
 import { logger } from '../utils/utils'
 import { parseDelegation, validateDelegation } from '../utils/delegation-helpers'
 
+/**
+ * Generates a random mock transaction hash
+ */
+const mockTransactionHash = (): string =>
+  '0x' + [...Array(64)].map(() => Math.floor(Math.random() * 16).toString(16)).join('')
+
 /**
  * Mock implementation of the redeemDelegation function
  * @param delegationData The serialized delegation data (signature)
@@ -92,7 +98,7 @@ export const redeemDelegation = async (
     await new Promise(resolve => setTimeout(resolve, 1000))
     
     // Generate a mock transaction hash
-    const mockTxHash = '0x' + [...Array(64)].map(() => Math.floor(Math.random() * 16).toString(16)).join('')
+    const mockTxHash = mockTransactionHash()
     
     logger.info("[MOCK] Transaction confirmed:", mockTxHash)
     
@@ -101,4 +107,104 @@ export const redeemDelegation = async (
     logger.error("[MOCK] Error redeeming delegation:", error)
     throw error
   }
-} 
\ No newline at end of file
+} 
+
+/**
+ * Mock implementation of the simulateRedemption function
+ * Validates the request like a redemption and reports a fixed gas estimate
+ * @returns A mock gas estimate
+ */
+export const simulateRedemption = async (
+  delegationData: Uint8Array,
+  merchantAddress: string,
+  tokenContractAddress: string,
+  tokenAmount: number,
+  tokenDecimals: number,
+  chainId: number,
+  networkName: string
+): Promise<bigint> => {
+  if (!delegationData || delegationData.length === 0) {
+    throw new Error("Delegation data is required");
+  }
+  if (!merchantAddress || !tokenContractAddress || !tokenAmount || !tokenDecimals) {
+    throw new Error("Merchant address, token contract address, token amount and decimals are required");
+  }
+  if (!chainId || chainId <= 0 || !networkName) {
+    throw new Error("Valid chain ID and network name are required");
+  }
+
+  const delegation = parseDelegation(delegationData)
+  if (!delegation.delegator || !delegation.delegate || !delegation.signature) {
+    throw new Error('Invalid delegation: missing delegator, delegate or signature')
+  }
+
+  logger.info("[MOCK] Redemption simulated successfully")
+  return 120000n
+}
+
+/**
+ * Mock implementation of the batchRedeemDelegations function
+ * Redemptions with an invalid delegation are left out; the rest share one mock transaction hash
+ * @returns A mock batch result
+ */
+export const batchRedeemDelegations = async (
+  chainId: number,
+  networkName: string,
+  redemptions: Array<{
+    referenceId: string
+    delegationData: Uint8Array
+    merchantAddress: string
+    tokenContractAddress: string
+    tokenAmount: number
+    tokenDecimals: number
+  }>
+): Promise<{ transactionHash: string; results: Array<{ referenceId: string; transactionHash: string; error?: unknown }> }> => {
+  const results: Array<{ referenceId: string; transactionHash: string; error?: unknown }> = []
+  for (const redemption of redemptions) {
+    try {
+      await simulateRedemption(
+        redemption.delegationData,
+        redemption.merchantAddress,
+        redemption.tokenContractAddress,
+        redemption.tokenAmount,
+        redemption.tokenDecimals,
+        chainId,
+        networkName
+      )
+      results.push({ referenceId: redemption.referenceId, transactionHash: '' })
+    } catch (error) {
+      results.push({ referenceId: redemption.referenceId, transactionHash: '', error })
+    }
+  }
+
+  if (results.every(result => result.error)) {
+    return { transactionHash: '', results }
+  }
+
+  const transactionHash = mockTransactionHash()
+  for (const result of results) {
+    if (!result.error) {
+      result.transactionHash = transactionHash
+    }
+  }
+
+  logger.info(`[MOCK] Batch of ${redemptions.length} redemptions confirmed:`, transactionHash)
+  return { transactionHash, results }
+}
+
+/**
+ * Mock implementation of the getRedemptionStatus function
+ * Every transaction is reported as mined
+ * @returns A mock mined status
+ */
+export const getRedemptionStatus = async (
+  transactionHash: string,
+  chainId: number,
+  networkName: string
+): Promise<{ status: 'PENDING' | 'MINED' | 'FAILED'; confirmations: bigint; blockNumber: bigint; gasUsed: bigint }> => {
+  if (!transactionHash || !chainId || !networkName) {
+    throw new Error("Transaction hash, chain ID and network name are required");
+  }
+
+  return { status: 'MINED', confirmations: 12n, blockNumber: 1000n, gasUsed: 90000n }
+}
@@ -1,7 +1,7 @@
-import { type Address } from "viem";
+import { type Address, type Hex } from "viem";
 import { isAddressEqual } from "viem";
-import { 
-  parseDelegation, 
+import {
+  parseDelegation,
   validateDelegation,
   // Import from shared library
   validateRedemptionInputs,
@@ -18,11 +18,84 @@ import {
 import { getNetworkConfig } from "../config/config";
 import { logger } from "../utils/utils";
 import { getSecretValue } from "../utils/secrets_manager";
+import { TransactionNotFoundError } from "./errors";
+
+/**
+ * A redemption within a batch
+ */
+export interface BatchRedemptionInput {
+  referenceId: string;
+  delegationData: Uint8Array;
+  merchantAddress: string;
+  tokenContractAddress: string;
+  tokenAmount: number;
+  tokenDecimals: number;
+}
+
+/**
+ * Outcome of one redemption within a batch; error is set when the redemption failed
+ */
+export interface BatchRedemptionOutcome {
+  referenceId: string;
+  transactionHash: string;
+  error?: unknown;
+}
+
+/**
+ * Result of a batch redemption; transactionHash is empty when no redemption was sent
+ */
+export interface BatchRedemptionResult {
+  transactionHash: string;
+  results: BatchRedemptionOutcome[];
+}
+
+/**
+ * On-chain state of a redemption transaction
+ */
+export interface RedemptionStatusResult {
+  status: 'PENDING' | 'MINED' | 'FAILED';
+  confirmations: bigint;
+  blockNumber: bigint;
+  gasUsed: bigint;
+}
+
+/**
+ * Rethrows an error with the operation name, keeping the original error as the cause so its type
+ * and revert reason can still be reported to the client
+ */
+function rethrow(operation: string, error: unknown): never {
+  if (error instanceof Error) {
+    throw Object.assign(new Error(`${operation} failed: ${error.message}`), { cause: error });
+  }
+  throw new Error(`${operation} failed due to an unknown error.`);
+}
+
+/**
+ * Creates the blockchain clients for a network
+ */
+async function getBlockchainClients(chainId: number, networkName: string) {
+  const { rpcUrl, bundlerUrl } = await getNetworkConfig(networkName, chainId);
+  const chain = getChainById(chainId);
+  const networkConfig = createNetworkConfigFromUrls(networkName, chainId, rpcUrl, bundlerUrl);
+  return initializeBlockchainClients(networkConfig, chain);
+}
+
+/**
+ * Creates the redeemer smart account
+ */
+async function getRedeemer(publicClient: Awaited<ReturnType<typeof getBlockchainClients>>["publicClient"]) {
+  const privateKey = await getSecretValue('PRIVATE_KEY_ARN', "PRIVATE_KEY");
+  const redeemerConfig: RedeemerConfig = {
+    privateKey,
+    deploySalt: "0x" as `0x${string}`
+  };
+  return getOrCreateRedeemerAccount(publicClient, redeemerConfig);
+}
 
 /**
  * Redeems a delegation, executing actions on behalf of the delegator
  * This implementation uses the shared delegation library for common functionality
- * 
+ *
  * @param delegationData - The serialized delegation data
  * @param merchantAddress - The address of the merchant
  * @param tokenContractAddress - The address of the token contract
@@ -53,19 +126,16 @@ export const redeemDelegation = async (
       networkName
     };
     validateRedemptionInputs(validationInputs);
-    
+
     logger.info(`Starting delegation redemption for chainId: ${chainId}, network: ${networkName}`);
 
     // 2. Get network configuration and create blockchain clients
-    const { rpcUrl, bundlerUrl } = await getNetworkConfig(networkName, chainId);
-    const chain = getChainById(chainId);
-    const networkConfig = createNetworkConfigFromUrls(networkName, chainId, rpcUrl, bundlerUrl);
-    const { publicClient, bundlerClient, pimlicoClient } = await initializeBlockchainClients(networkConfig, chain);
-    
+    const { publicClient, bundlerClient, pimlicoClient } = await getBlockchainClients(chainId, networkName);
+
     // 3. Parse and validate the delegation
     const delegation = parseDelegation(delegationData);
     await validateDelegation(delegation, publicClient);
-    
+
     logger.info("Redeeming delegation...");
     logger.debug("Delegation details for redemption:", {
       delegate: delegation.delegate,
@@ -79,12 +149,7 @@ export const redeemDelegation = async (
     });
 
     // 4. Get the redeemer configuration and create smart account
-    const privateKey = await getSecretValue('PRIVATE_KEY_ARN', "PRIVATE_KEY");
-    const redeemerConfig: RedeemerConfig = {
-      privateKey,
-      deploySalt: "0x" as `0x${string}`
-    };
-    const redeemer = await getOrCreateRedeemerAccount(publicClient, redeemerConfig);
+    const redeemer = await getRedeemer(publicClient);
     logger.info(`Target Smart Account address: ${redeemer.address}`);
 
     // 5. Validate delegate matches redeemer
@@ -99,7 +164,7 @@ export const redeemDelegation = async (
       tokenDecimals,
       redeemer.address
     );
-    
+
     // 7. Send and confirm the UserOperation
     const transactionHash = await sendAndConfirmUserOperation(
       bundlerClient,
@@ -116,15 +181,224 @@ export const redeemDelegation = async (
 
   } catch (error) {
     // Centralized error handling
-    logger.error("Critical error in redeemDelegation service:", { 
-      message: (error as Error)?.message, 
+    logger.error("Critical error in redeemDelegation service:", {
+      message: (error as Error)?.message,
       stack: (error as Error)?.stack,
-      errorObject: error 
+      errorObject: error
+    });
+
+    rethrow('RedeemDelegation', error);
+  }
+};
+
+/**
+ * Dry-runs a redemption without sending a transaction. Once the redeemer smart account is deployed the
+ * redemption calls are run with eth_call from it; before that the bundler estimates the UserOperation,
+ * which also simulates the account deployment.
+ *
+ * @returns The estimated gas of the redemption
+ * @throws The error the redemption would fail with, including the chain's revert reason
+ */
+export const simulateRedemption = async (
+  delegationData: Uint8Array,
+  merchantAddress: string,
+  tokenContractAddress: string,
+  tokenAmount: number,
+  tokenDecimals: number,
+  chainId: number,
+  networkName: string
+): Promise<bigint> => {
+  try {
+    validateRedemptionInputs({
+      delegationData,
+      merchantAddress: merchantAddress as Address,
+      tokenContractAddress: tokenContractAddress as Address,
+      tokenAmount,
+      tokenDecimals,
+      chainId,
+      networkName
+    });
+
+    const { publicClient, bundlerClient } = await getBlockchainClients(chainId, networkName);
+
+    const delegation = parseDelegation(delegationData);
+    await validateDelegation(delegation, publicClient);
+
+    const redeemer = await getRedeemer(publicClient);
+    validateDelegateMatch(redeemer.address, delegation.delegate);
+
+    const calls = prepareRedemptionUserOperationPayload(
+      delegation,
+      merchantAddress,
+      tokenContractAddress,
+      tokenAmount,
+      tokenDecimals,
+      redeemer.address
+    );
+
+    if (await redeemer.isDeployed()) {
+      let gasEstimate = 0n;
+      for (const call of calls) {
+        const request = { account: redeemer.address, to: call.to as Address, data: call.data as Hex };
+        await publicClient.call(request);
+        gasEstimate += await publicClient.estimateGas(request);
+      }
+      return gasEstimate;
+    }
+
+    const estimate = await (bundlerClient as { estimateUserOperationGas: (params: unknown) => Promise<{ callGasLimit?: bigint; verificationGasLimit?: bigint; preVerificationGas?: bigint }> }).estimateUserOperationGas({
+      account: redeemer,
+      calls
+    });
+    return (estimate.callGasLimit || 0n) + (estimate.verificationGasLimit || 0n) + (estimate.preVerificationGas || 0n);
+  } catch (error) {
+    logger.warn("Redemption simulation failed:", { message: (error as Error)?.message });
+    rethrow('SimulateRedemption', error);
+  }
+};
+
+/**
+ * Redeems several delegations on one network in a single UserOperation. Each redemption is simulated
+ * first and left out of the transaction if it would fail, so one bad delegation does not revert the batch.
+ *
+ * @returns The batch transaction hash and one outcome per redemption, in request order
+ */
+export const batchRedeemDelegations = async (
+  chainId: number,
+  networkName: string,
+  redemptions: BatchRedemptionInput[]
+): Promise<BatchRedemptionResult> => {
+  const results: BatchRedemptionOutcome[] = redemptions.map(redemption => ({
+    referenceId: redemption.referenceId,
+    transactionHash: ''
+  }));
+
+  try {
+    logger.info(`Starting batch redemption of ${redemptions.length} delegations for chainId: ${chainId}, network: ${networkName}`);
+
+    const { publicClient, bundlerClient, pimlicoClient } = await getBlockchainClients(chainId, networkName);
+    const redeemer = await getRedeemer(publicClient);
+    const deployed = await redeemer.isDeployed();
+
+    // Simulate each redemption on its own and keep the ones that would succeed
+    const included: number[] = [];
+    const calls: ReturnType<typeof prepareRedemptionUserOperationPayload> = [];
+    for (const [index, redemption] of redemptions.entries()) {
+      try {
+        validateRedemptionInputs({
+          delegationData: redemption.delegationData,
+          merchantAddress: redemption.merchantAddress as Address,
+          tokenContractAddress: redemption.tokenContractAddress as Address,
+          tokenAmount: redemption.tokenAmount,
+          tokenDecimals: redemption.tokenDecimals,
+          chainId,
+          networkName
+        });
+
+        const delegation = parseDelegation(redemption.delegationData);
+        await validateDelegation(delegation, publicClient);
+        validateDelegateMatch(redeemer.address, delegation.delegate);
+
+        const redemptionCalls = prepareRedemptionUserOperationPayload(
+          delegation,
+          redemption.merchantAddress,
+          redemption.tokenContractAddress,
+          redemption.tokenAmount,
+          redemption.tokenDecimals,
+          redeemer.address
+        );
+
+        // Without a deployed redeemer the calls cannot be run on their own; the bundler simulates the batch
+        if (deployed) {
+          for (const call of redemptionCalls) {
+            await publicClient.call({ account: redeemer.address, to: call.to as Address, data: call.data as Hex });
+          }
+        }
+
+        included.push(index);
+        calls.push(...redemptionCalls);
+      } catch (error) {
+        logger.warn(`Leaving redemption ${redemption.referenceId} out of the batch:`, { message: (error as Error)?.message });
+        results[index].error = error;
+      }
+    }
+
+    if (included.length === 0) {
+      return { transactionHash: '', results };
+    }
+
+    const transactionHash = await sendAndConfirmUserOperation(
+      bundlerClient,
+      pimlicoClient,
+      redeemer,
+      calls,
+      publicClient,
+      {
+        onStatusUpdate: (status) => logger.info(status)
+      }
+    );
+
+    for (const index of included) {
+      results[index].transactionHash = transactionHash;
+    }
+    return { transactionHash, results };
+  } catch (error) {
+    logger.error("Critical error in batchRedeemDelegations service:", {
+      message: (error as Error)?.message,
+      stack: (error as Error)?.stack
     });
-    
-    if (error instanceof Error) {
-      throw new Error(`RedeemDelegation failed: ${error.message}`);
+
+    // Every redemption that was not already left out shares the failure of the batch
+    for (const result of results) {
+      if (!result.error) {
+        result.error = error;
+      }
+    }
+    return { transactionHash: '', results };
+  }
+};
+
+/**
+ * Looks up the on-chain status of a redemption transaction
+ *
+ * @param transactionHash - The transaction hash returned by a redemption
+ * @param chainId - The blockchain chain ID
+ * @param networkName - The network name
+ * @returns The status of the transaction and its confirmations once mined
+ * @throws TransactionNotFoundError if the network does not know the transaction
+ */
+export const getRedemptionStatus = async (
+  transactionHash: string,
+  chainId: number,
+  networkName: string
+): Promise<RedemptionStatusResult> => {
+  const { publicClient } = await getBlockchainClients(chainId, networkName);
+  const hash = transactionHash as Hex;
+
+  try {
+    const receipt = await publicClient.getTransactionReceipt({ hash });
+    const latestBlock = await publicClient.getBlockNumber();
+
+    return {
+      status: receipt.status === 'success' ? 'MINED' : 'FAILED',
+      confirmations: latestBlock >= receipt.blockNumber ? latestBlock - receipt.blockNumber + 1n : 1n,
+      blockNumber: receipt.blockNumber,
+      gasUsed: receipt.gasUsed
+    };
+  } catch (error) {
+    if ((error as { name?: string })?.name !== 'TransactionReceiptNotFoundError') {
+      rethrow('GetRedemptionStatus', error);
+    }
+  }
+
+  // Without a receipt the transaction is either still pending or unknown
+  try {
+    await publicClient.getTransaction({ hash });
+  } catch (error) {
+    if ((error as { name?: string })?.name === 'TransactionNotFoundError') {
+      throw new TransactionNotFoundError(transactionHash);
     }
-    throw new Error(`RedeemDelegation failed due to an unknown error.`);
+    rethrow('GetRedemptionStatus', error);
   }
-};
\ No newline at end of file
+  return { status: 'PENDING', confirmations: 0n, blockNumber: 0n, gasUsed: 0n };
+};
@@ -18,17 +18,24 @@ jest.mock('../config', () => ({
 
 // Mock the redeem-delegation module
 jest.mock('./redeem-delegation', () => ({
-  redeemDelegation: jest.fn()
+  redeemDelegation: jest.fn(),
+  simulateRedemption: jest.fn(),
+  batchRedeemDelegations: jest.fn(),
+  getRedemptionStatus: jest.fn()
 }));
 
 // Mock the mock-redeem-delegation module
 jest.mock('./mock-redeem-delegation', () => ({
-  redeemDelegation: jest.fn()
+  redeemDelegation: jest.fn(),
+  simulateRedemption: jest.fn(),
+  batchRedeemDelegations: jest.fn(),
+  getRedemptionStatus: jest.fn()
 }));
 
 // Import after mocks are set up
 import { delegationService } from './service';
 import { logger } from '../utils/utils';
+import { TransactionNotFoundError } from './errors';
 
 describe('DelegationService', () => {
   let mockCall: Partial<ServerUnaryCall<any, any>>;
@@ -139,7 +146,13 @@ describe('DelegationService', () => {
         transactionHash: '',
         success: false,
         error_message: 'Missing or invalid chain_id in request',
-        errorMessage: 'Missing or invalid chain_id in request'
+        errorMessage: 'Missing or invalid chain_id in request',
+        error: {
+          code: 'ERROR_CODE_INVALID_REQUEST',
+          message: 'Missing or invalid chain_id in request',
+          revert_reason: '',
+          retryable: false
+        }
       });
     });
 
@@ -156,7 +169,13 @@ describe('DelegationService', () => {
         transactionHash: '',
         success: false,
         error_message: 'Missing or invalid chain_id in request',
-        errorMessage: 'Missing or invalid chain_id in request'
+        errorMessage: 'Missing or invalid chain_id in request',
+        error: {
+          code: 'ERROR_CODE_INVALID_REQUEST',
+          message: 'Missing or invalid chain_id in request',
+          revert_reason: '',
+          retryable: false
+        }
       });
     });
 
@@ -173,7 +192,13 @@ describe('DelegationService', () => {
         transactionHash: '',
         success: false,
         error_message: 'Missing network_name in request',
-        errorMessage: 'Missing network_name in request'
+        errorMessage: 'Missing network_name in request',
+        error: {
+          code: 'ERROR_CODE_INVALID_REQUEST',
+          message: 'Missing network_name in request',
+          revert_reason: '',
+          retryable: false
+        }
       });
     });
 
@@ -190,7 +215,13 @@ describe('DelegationService', () => {
         transactionHash: '',
         success: false,
         error_message: 'Missing or invalid token_amount in request',
-        errorMessage: 'Missing or invalid token_amount in request'
+        errorMessage: 'Missing or invalid token_amount in request',
+        error: {
+          code: 'ERROR_CODE_INVALID_REQUEST',
+          message: 'Missing or invalid token_amount in request',
+          revert_reason: '',
+          retryable: false
+        }
       });
     });
 
@@ -207,7 +238,13 @@ describe('DelegationService', () => {
         transactionHash: '',
         success: false,
         error_message: 'Missing or invalid token_decimals in request',
-        errorMessage: 'Missing or invalid token_decimals in request'
+        errorMessage: 'Missing or invalid token_decimals in request',
+        error: {
+          code: 'ERROR_CODE_INVALID_REQUEST',
+          message: 'Missing or invalid token_decimals in request',
+          revert_reason: '',
+          retryable: false
+        }
       });
     });
 
@@ -224,7 +261,13 @@ describe('DelegationService', () => {
         transactionHash: '',
         success: false,
         error_message: 'Blockchain error',
-        errorMessage: 'Blockchain error'
+        errorMessage: 'Blockchain error',
+        error: {
+          code: 'ERROR_CODE_INTERNAL',
+          message: 'Blockchain error',
+          revert_reason: '',
+          retryable: false
+        }
       });
 
       expect(logger.error).toHaveBeenCalledWith(
@@ -246,9 +289,241 @@ describe('DelegationService', () => {
         transactionHash: '',
         success: false,
         error_message: 'String error',
-        errorMessage: 'String error'
+        errorMessage: 'String error',
+        error: {
+          code: 'ERROR_CODE_INTERNAL',
+          message: 'String error',
+          revert_reason: '',
+          retryable: false
+        }
       });
     });
+    it('should report retryable network errors', async () => {
+      const networkError = Object.assign(new Error('Failed to initialize blockchain clients'), {
+        name: 'RedemptionError',
+        type: 'NETWORK_ERROR'
+      });
+      mockRedeemDelegation.mockRejectedValue(
+        Object.assign(new Error('RedeemDelegation failed: Failed to initialize blockchain clients'), { cause: networkError })
+      );
+
+      await delegationService.redeemDelegation(
+        mockCall as ServerUnaryCall<any, any>,
+        mockCallback
+      );
+
+      expect(mockCallback).toHaveBeenCalledWith(null, expect.objectContaining({
+        success: false,
+        error: expect.objectContaining({ code: 'ERROR_CODE_NETWORK_ERROR', retryable: true })
+      }));
+    });
+
+    it('should report a UserOperation that was not confirmed in time', async () => {
+      const timeoutError = Object.assign(new Error('Timed out while waiting for User Operation'), {
+        name: 'WaitForUserOperationReceiptTimeoutError'
+      });
+      const userOperationError = Object.assign(new Error('Error during UserOperation'), {
+        name: 'RedemptionError',
+        type: 'USER_OPERATION_ERROR',
+        details: { originalError: timeoutError, userOpHash: '0xuserop' }
+      });
+      mockRedeemDelegation.mockRejectedValue(
+        Object.assign(new Error('RedeemDelegation failed: Error during UserOperation'), { cause: userOperationError })
+      );
+
+      await delegationService.redeemDelegation(
+        mockCall as ServerUnaryCall<any, any>,
+        mockCallback
+      );
+
+      expect(mockCallback).toHaveBeenCalledWith(null, expect.objectContaining({
+        success: false,
+        error: expect.objectContaining({ code: 'ERROR_CODE_CONFIRMATION_TIMEOUT', retryable: false })
+      }));
+    });
+  });
+
+  describe('simulateRedemption', () => {
+    let mockSimulateRedemption: jest.Mock;
+
+    beforeEach(() => {
+      mockSimulateRedemption = require('./redeem-delegation').simulateRedemption;
+    });
+
+    it('should return the gas estimate of a passing simulation', async () => {
+      mockSimulateRedemption.mockResolvedValue(120000n);
+
+      await delegationService.simulateRedemption(
+        mockCall as ServerUnaryCall<any, any>,
+        mockCallback
+      );
+
+      expect(mockCallback).toHaveBeenCalledWith(null, {
+        success: true,
+        gas_estimate: '120000'
+      });
+    });
+
+    it('should return the revert reason of a failing simulation', async () => {
+      const revertError = Object.assign(new Error('Execution reverted'), {
+        name: 'ContractFunctionRevertedError',
+        reason: 'ERC20: transfer amount exceeds balance'
+      });
+      mockSimulateRedemption.mockRejectedValue(
+        Object.assign(new Error('SimulateRedemption failed: Execution reverted'), { cause: revertError })
+      );
+
+      await delegationService.simulateRedemption(
+        mockCall as ServerUnaryCall<any, any>,
+        mockCallback
+      );
+
+      expect(mockCallback).toHaveBeenCalledWith(null, {
+        success: false,
+        gas_estimate: '0',
+        error: {
+          code: 'ERROR_CODE_EXECUTION_REVERTED',
+          message: 'SimulateRedemption failed: Execution reverted',
+          revert_reason: 'ERC20: transfer amount exceeds balance',
+          retryable: false
+        }
+      });
+    });
+
+    it('should validate the request before simulating', async () => {
+      mockCall.request!.chain_id = 0;
+
+      await delegationService.simulateRedemption(
+        mockCall as ServerUnaryCall<any, any>,
+        mockCallback
+      );
+
+      expect(mockSimulateRedemption).not.toHaveBeenCalled();
+      expect(mockCallback).toHaveBeenCalledWith(null, expect.objectContaining({
+        success: false,
+        error: expect.objectContaining({ code: 'ERROR_CODE_INVALID_REQUEST' })
+      }));
+    });
+  });
+
+  describe('batchRedeemDelegations', () => {
+    let mockBatchRedeemDelegations: jest.Mock;
+    let batchCall: Partial<ServerUnaryCall<any, any>>;
+
+    beforeEach(() => {
+      mockBatchRedeemDelegations = require('./redeem-delegation').batchRedeemDelegations;
+      const redemption = {
+        signature: Buffer.from('test-signature'),
+        merchant_address: '0x1234567890123456789012345678901234567890',
+        token_contract_address: '0x0987654321098765432109876543210987654321',
+        token_amount: '1000000',
+        token_decimals: 6
+      };
+      batchCall = {
+        request: {
+          chain_id: 1,
+          network_name: 'mainnet',
+          redemptions: [
+            { ...redemption, reference_id: 'a' },
+            { ...redemption, reference_id: 'b' }
+          ]
+        }
+      };
+    });
+
+    it('should return one result per redemption', async () => {
+      mockBatchRedeemDelegations.mockResolvedValue({
+        transactionHash: '0xbatch',
+        results: [
+          { referenceId: 'a', transactionHash: '0xbatch' },
+          { referenceId: 'b', transactionHash: '', error: new Error('Delegation signature invalid') }
+        ]
+      });
+
+      await delegationService.batchRedeemDelegations(
+        batchCall as ServerUnaryCall<any, any>,
+        mockCallback
+      );
+
+      expect(mockBatchRedeemDelegations).toHaveBeenCalledWith(1, 'mainnet', [
+        expect.objectContaining({ referenceId: 'a', tokenAmount: 1000000, tokenDecimals: 6 }),
+        expect.objectContaining({ referenceId: 'b' })
+      ]);
+      expect(mockCallback).toHaveBeenCalledWith(null, {
+        transaction_hash: '0xbatch',
+        results: [
+          { reference_id: 'a', success: true, transaction_hash: '0xbatch', error: null },
+          {
+            reference_id: 'b',
+            success: false,
+            transaction_hash: '',
+            error: expect.objectContaining({ code: 'ERROR_CODE_INTERNAL', message: 'Delegation signature invalid' })
+          }
+        ]
+      });
+    });
+
+    it('should reject duplicate reference IDs', async () => {
+      batchCall.request!.redemptions[1].reference_id = 'a';
+
+      await delegationService.batchRedeemDelegations(
+        batchCall as ServerUnaryCall<any, any>,
+        mockCallback
+      );
+
+      expect(mockBatchRedeemDelegations).not.toHaveBeenCalled();
+      const response = mockCallback.mock.calls[0][1];
+      expect(response.results).toHaveLength(2);
+      expect(response.results[0].error.code).toBe('ERROR_CODE_INVALID_REQUEST');
+    });
+  });
+
+  describe('getRedemptionStatus', () => {
+    let mockGetRedemptionStatus: jest.Mock;
+    let statusCall: Partial<ServerUnaryCall<any, any>>;
+
+    beforeEach(() => {
+      mockGetRedemptionStatus = require('./redeem-delegation').getRedemptionStatus;
+      statusCall = {
+        request: { transaction_hash: '0xabc', chain_id: 1, network_name: 'mainnet' }
+      };
+    });
+
+    it('should return the status of a mined transaction', async () => {
+      mockGetRedemptionStatus.mockResolvedValue({
+        status: 'MINED',
+        confirmations: 12n,
+        blockNumber: 1000n,
+        gasUsed: 90000n
+      });
+
+      await delegationService.getRedemptionStatus(
+        statusCall as ServerUnaryCall<any, any>,
+        mockCallback
+      );
+
+      expect(mockGetRedemptionStatus).toHaveBeenCalledWith('0xabc', 1, 'mainnet');
+      expect(mockCallback).toHaveBeenCalledWith(null, {
+        status: 'REDEMPTION_STATUS_MINED',
+        confirmations: '12',
+        block_number: '1000',
+        gas_used: '90000'
+      });
+    });
+
+    it('should report unknown transactions', async () => {
+      mockGetRedemptionStatus.mockRejectedValue(new TransactionNotFoundError('0xabc'));
+
+      await delegationService.getRedemptionStatus(
+        statusCall as ServerUnaryCall<any, any>,
+        mockCallback
+      );
+
+      expect(mockCallback).toHaveBeenCalledWith(null, expect.objectContaining({
+        status: 'REDEMPTION_STATUS_UNSPECIFIED',
+        error: expect.objectContaining({ code: 'ERROR_CODE_TRANSACTION_NOT_FOUND' })
+      }));
+    });
   });
 
   describe('Mock Mode', () => {
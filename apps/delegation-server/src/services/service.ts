@@ -1,6 +1,19 @@
 import { ServerUnaryCall, sendUnaryData } from '@grpc/grpc-js'
 import { logger } from '../utils/utils'
 import config from '../config'
+import { InvalidRequestError, toStructuredError } from './errors'
+
+/**
+ * A redemption within a batch request
+ */
+interface BatchRedemptionInput {
+  referenceId: string;
+  delegationData: Uint8Array;
+  merchantAddress: string;
+  tokenContractAddress: string;
+  tokenAmount: number;
+  tokenDecimals: number;
+}
 
 // Conditionally import real or mock blockchain service based on MOCK_MODE
 let redeemDelegation: (
@@ -13,6 +26,28 @@ let redeemDelegation: (
   networkName: string
 ) => Promise<string>;
 
+let simulateRedemption: (
+  delegationData: Uint8Array,
+  merchantAddress: string,
+  tokenContractAddress: string,
+  tokenAmount: number,
+  tokenDecimals: number,
+  chainId: number,
+  networkName: string
+) => Promise<bigint>;
+
+let batchRedeemDelegations: (
+  chainId: number,
+  networkName: string,
+  redemptions: BatchRedemptionInput[]
+) => Promise<{ transactionHash: string; results: Array<{ referenceId: string; transactionHash: string; error?: unknown }> }>;
+
+let getRedemptionStatus: (
+  transactionHash: string,
+  chainId: number,
+  networkName: string
+) => Promise<{ status: 'PENDING' | 'MINED' | 'FAILED'; confirmations: bigint; blockNumber: bigint; gasUsed: bigint }>;
+
 // Create a promise that resolves when the implementation is loaded
 let implementationReady: Promise<void>;
 
@@ -24,6 +59,9 @@ if (config.mockMode) {
   logger.info('SERVICE.TS: Running in MOCK MODE - using mock blockchain service')
   implementationReady = import('./mock-redeem-delegation').then(module => {
     redeemDelegation = module.redeemDelegation;
+    simulateRedemption = module.simulateRedemption;
+    batchRedeemDelegations = module.batchRedeemDelegations;
+    getRedemptionStatus = module.getRedemptionStatus;
     logger.info('SERVICE.TS: Successfully loaded MOCK blockchain service')
   }).catch(error => {
     logger.error('SERVICE.TS: Failed to load mock blockchain service:', error);
@@ -33,6 +71,9 @@ if (config.mockMode) {
   logger.info('SERVICE.TS: Running in REAL MODE - using real blockchain service')
   implementationReady = import('./redeem-delegation').then(module => {
     redeemDelegation = module.redeemDelegation;
+    simulateRedemption = module.simulateRedemption;
+    batchRedeemDelegations = module.batchRedeemDelegations;
+    getRedemptionStatus = module.getRedemptionStatus;
     logger.info('SERVICE.TS: Successfully loaded REAL blockchain service')
   }).catch(error => {
     logger.error('SERVICE.TS: Failed to load real blockchain service:', error);
@@ -57,30 +98,16 @@ export const delegationService = {
       // Wait for the implementation to be ready
       await implementationReady;
 
-      // Extract request parameters
-      const signature = call.request.signature;
-      const merchantAddress = call.request.merchant_address || call.request.merchantAddress;
-      const tokenContractAddress = call.request.token_contract_address || call.request.tokenContractAddress;
-      const tokenAmount = call.request.token_amount;
-      const tokenDecimals = call.request.token_decimals;
-      const chainId = call.request.chain_id;
-      const networkName = call.request.network_name;
-
-      console.log("call.request", call.request);
-
-      // Basic validation for new parameters
-      if (chainId === undefined || chainId === null || chainId <= 0) {
-        throw new Error('Missing or invalid chain_id in request');
-      }
-      if (!networkName) {
-        throw new Error('Missing network_name in request');
-      }
-      if (!tokenAmount || tokenAmount <= 0) {
-        throw new Error('Missing or invalid token_amount in request');
-      }
-      if (!tokenDecimals || tokenDecimals <= 0) {
-        throw new Error('Missing or invalid token_decimals in request');
-      }
+      // Extract and validate request parameters
+      const {
+        signature,
+        merchantAddress,
+        tokenContractAddress,
+        tokenAmount,
+        tokenDecimals,
+        chainId,
+        networkName
+      } = readRedemptionRequest(call.request);
 
       logger.info('Request parameters:', {
         signatureLength: signature ? signature.length : 0,
@@ -118,14 +145,230 @@ export const delegationService = {
       const errorMessage = error instanceof Error ? error.message : String(error);
       logger.error('Error in redeemDelegation:', errorMessage);
       
-      // Send error response with both snake_case and camelCase fields for compatibility
+      // Send error response with both snake_case and camelCase fields for compatibility;
+      // error_message is kept for clients that do not read the structured error yet
       callback(null, {
         transaction_hash: "",
         transactionHash: "",
         success: false,
         error_message: errorMessage,
-        errorMessage: errorMessage
+        errorMessage: errorMessage,
+        error: toStructuredError(error)
+      });
+    }
+  },
+
+  /**
+   * Dry-runs a redemption without sending a transaction
+   *
+   * @param call - The gRPC call containing the same request as a redemption
+   * @param callback - The gRPC callback to return the gas estimate or the reason the redemption would fail
+   */
+  async simulateRedemption(call: ServerUnaryCall<any, any>, callback: sendUnaryData<any>) {
+    try {
+      logger.info('Received SimulateRedemption request');
+
+      await implementationReady;
+
+      const {
+        signature,
+        merchantAddress,
+        tokenContractAddress,
+        tokenAmount,
+        tokenDecimals,
+        chainId,
+        networkName
+      } = readRedemptionRequest(call.request);
+
+      const gasEstimate = await simulateRedemption(
+        signature,
+        merchantAddress,
+        tokenContractAddress,
+        tokenAmount,
+        tokenDecimals,
+        chainId,
+        networkName
+      );
+
+      logger.info(`Simulation successful, gas estimate: ${gasEstimate}`);
+
+      callback(null, {
+        success: true,
+        gas_estimate: gasEstimate.toString()
+      });
+    } catch (error) {
+      const errorMessage = error instanceof Error ? error.message : String(error);
+      logger.warn('Simulated redemption would fail:', errorMessage);
+
+      callback(null, {
+        success: false,
+        gas_estimate: '0',
+        error: toStructuredError(error)
+      });
+    }
+  },
+
+  /**
+   * Redeems several delegations on one network in a single transaction
+   *
+   * @param call - The gRPC call containing the network and the redemptions
+   * @param callback - The gRPC callback to return one result per redemption, in request order
+   */
+  async batchRedeemDelegations(call: ServerUnaryCall<any, any>, callback: sendUnaryData<any>) {
+    const redemptions: any[] = call.request.redemptions || [];
+
+    try {
+      logger.info(`Received BatchRedeemDelegations request with ${redemptions.length} redemptions`);
+
+      await implementationReady;
+
+      const chainId = call.request.chain_id;
+      const networkName = call.request.network_name;
+      if (chainId === undefined || chainId === null || chainId <= 0) {
+        throw new InvalidRequestError('Missing or invalid chain_id in request');
+      }
+      if (!networkName) {
+        throw new InvalidRequestError('Missing network_name in request');
+      }
+      if (redemptions.length === 0) {
+        throw new InvalidRequestError('Missing redemptions in request');
+      }
+
+      const referenceIds = new Set<string>();
+      for (const redemption of redemptions) {
+        if (!redemption.reference_id || referenceIds.has(redemption.reference_id)) {
+          throw new InvalidRequestError('Every redemption needs a unique reference_id');
+        }
+        referenceIds.add(redemption.reference_id);
+      }
+
+      const { transactionHash, results } = await batchRedeemDelegations(
+        chainId,
+        networkName,
+        redemptions.map(redemption => ({
+          referenceId: redemption.reference_id,
+          delegationData: redemption.signature,
+          merchantAddress: redemption.merchant_address,
+          tokenContractAddress: redemption.token_contract_address,
+          tokenAmount: Number(redemption.token_amount),
+          tokenDecimals: redemption.token_decimals
+        }))
+      );
+
+      logger.info(`Batch redemption finished, transaction hash: ${transactionHash || 'none'}`);
+
+      callback(null, {
+        transaction_hash: transactionHash,
+        results: results.map(result => ({
+          reference_id: result.referenceId,
+          success: !result.error,
+          transaction_hash: result.error ? '' : result.transactionHash,
+          error: result.error ? toStructuredError(result.error) : null
+        }))
+      });
+    } catch (error) {
+      const errorMessage = error instanceof Error ? error.message : String(error);
+      logger.error('Error in batchRedeemDelegations:', errorMessage);
+
+      // Nothing was sent, so every redemption fails with the same error
+      const structuredError = toStructuredError(error);
+      callback(null, {
+        transaction_hash: '',
+        results: redemptions.map(redemption => ({
+          reference_id: redemption.reference_id || '',
+          success: false,
+          transaction_hash: '',
+          error: structuredError
+        }))
+      });
+    }
+  },
+
+  /**
+   * Looks up the on-chain status of a redemption transaction
+   *
+   * @param call - The gRPC call containing the transaction hash and its network
+   * @param callback - The gRPC callback to return the status and confirmations
+   */
+  async getRedemptionStatus(call: ServerUnaryCall<any, any>, callback: sendUnaryData<any>) {
+    try {
+      logger.info('Received GetRedemptionStatus request');
+
+      await implementationReady;
+
+      const transactionHash = call.request.transaction_hash;
+      const chainId = call.request.chain_id;
+      const networkName = call.request.network_name;
+      if (!transactionHash) {
+        throw new InvalidRequestError('Missing transaction_hash in request');
+      }
+      if (chainId === undefined || chainId === null || chainId <= 0) {
+        throw new InvalidRequestError('Missing or invalid chain_id in request');
+      }
+      if (!networkName) {
+        throw new InvalidRequestError('Missing network_name in request');
+      }
+
+      const result = await getRedemptionStatus(transactionHash, chainId, networkName);
+
+      callback(null, {
+        status: `REDEMPTION_STATUS_${result.status}`,
+        confirmations: result.confirmations.toString(),
+        block_number: result.blockNumber.toString(),
+        gas_used: result.gasUsed.toString()
+      });
+    } catch (error) {
+      const errorMessage = error instanceof Error ? error.message : String(error);
+      logger.error('Error in getRedemptionStatus:', errorMessage);
+
+      callback(null, {
+        status: 'REDEMPTION_STATUS_UNSPECIFIED',
+        confirmations: '0',
+        block_number: '0',
+        gas_used: '0',
+        error: toStructuredError(error)
       });
     }
   }
 };
+
+/**
+ * Reads and validates the fields shared by redemption and simulation requests
+ *
+ * @param request - The RedeemDelegationRequest
+ * @returns The request fields, accepting camelCase addresses for compatibility
+ * @throws InvalidRequestError if a field is missing or invalid
+ */
+function readRedemptionRequest(request: any) {
+  const signature = request.signature;
+  const merchantAddress = request.merchant_address || request.merchantAddress;
+  const tokenContractAddress = request.token_contract_address || request.tokenContractAddress;
+  const tokenAmount = request.token_amount;
+  const tokenDecimals = request.token_decimals;
+  const chainId = request.chain_id;
+  const networkName = request.network_name;
+
+  // Basic validation for new parameters
+  if (chainId === undefined || chainId === null || chainId <= 0) {
+    throw new InvalidRequestError('Missing or invalid chain_id in request');
+  }
+  if (!networkName) {
+    throw new InvalidRequestError('Missing network_name in request');
+  }
+  if (!tokenAmount || tokenAmount <= 0) {
+    throw new InvalidRequestError('Missing or invalid token_amount in request');
+  }
+  if (!tokenDecimals || tokenDecimals <= 0) {
+    throw new InvalidRequestError('Missing or invalid token_decimals in request');
+  }
+
+  return {
+    signature,
+    merchantAddress,
+    tokenContractAddress,
+    tokenAmount,
+    tokenDecimals,
+    chainId,
+    networkName
+  };
+}
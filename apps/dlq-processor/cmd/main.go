@@ -360,9 +360,13 @@ func main() {
 	if err != nil || paymentSyncEncryptionKey == "" {
 		logger.Fatal("Failed to get Payment Sync Encryption Key", zap.Error(err))
 	}
+	paymentSyncKeys, err := payment_sync.LoadKeyProvider(ctx, secretsClient, paymentSyncEncryptionKey)
+	if err != nil {
+		logger.Fatal("Failed to initialize Payment Sync key provider", zap.Error(err))
+	}
 
 	// Initialize Payment Sync Client
-	paymentSyncClient := payment_sync.NewPaymentSyncClient(dbQueries, logger.Log, paymentSyncEncryptionKey)
+	paymentSyncClient := payment_sync.NewPaymentSyncClientWithKeyProvider(dbQueries, logger.Log, paymentSyncEncryptionKey, paymentSyncKeys)
 
 	// Get configuration from environment
 	maxRetries := 5
@@ -6,14 +6,21 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cyphera/cyphera-api/apps/subscription-processor/internal/processor"
 	awsclient "github.com/cyphera/cyphera-api/libs/go/client/aws"
+	"github.com/cyphera/cyphera-api/libs/go/client/circle"
 	dsClient "github.com/cyphera/cyphera-api/libs/go/client/delegation_server"
+	"github.com/cyphera/cyphera-api/libs/go/client/payment_sync"
+	"github.com/cyphera/cyphera-api/libs/go/client/solana"
+	"github.com/cyphera/cyphera-api/libs/go/client/tax_provider"
+	"github.com/cyphera/cyphera-api/libs/go/client/vies"
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
 	"github.com/cyphera/cyphera-api/libs/go/services"
 
@@ -32,6 +39,305 @@ type Application struct {
 	failureDetector    *services.PaymentFailureDetector
 	dunningService     *services.DunningService
 	dunningRetryEngine *services.DunningRetryEngine
+	apiKeyService      *services.APIKeyService
+	emailService       *services.EmailService
+	// apiKeyUnusedDays is how long an API key may go unused before its owner is notified (0 disables)
+	apiKeyUnusedDays int
+	// paymentSyncClient re-encrypts provider credentials after a key rotation (nil if no key is configured)
+	paymentSyncClient *payment_sync.PaymentSyncClient
+	// customerPortalService purges expired customer portal sessions
+	customerPortalService *services.CustomerPortalService
+	// taxIDVerificationService re-verifies stored customer tax IDs with VIES
+	taxIDVerificationService *services.TaxIDVerificationService
+	// gasSponsorshipService releases sponsorship budget held for transactions that never completed
+	gasSponsorshipService *services.GasSponsorshipService
+	// mrrMovementService converts MRR movements recorded in another currency to the reporting currency
+	mrrMovementService *services.MRRMovementService
+	// analyticsExportService runs queued analytics exports and delivers scheduled reports
+	analyticsExportService *services.AnalyticsExportService
+	// delegationMonitorService flags delegations that can no longer be redeemed and asks customers to re-sign them
+	delegationMonitorService *services.DelegationMonitorService
+	// transactionConfirmationService follows payment transactions to finality (nil if network RPCs are unavailable)
+	transactionConfirmationService *services.TransactionConfirmationService
+	// treasuryService sweeps merchants' Circle wallets into their treasury wallets (nil if Circle is not configured)
+	treasuryService *services.TreasuryService
+	// portfolioService snapshots the value of workspaces' wallets (nil if neither Circle nor network RPCs are available)
+	portfolioService *services.PortfolioService
+	// walletNameService refreshes the ENS and Basenames names of customer wallets (nil if network RPCs are unavailable)
+	walletNameService *services.CustomerService
+	// blockchainService has its RPC endpoints probed at the start of each run (nil if network RPCs are unavailable)
+	blockchainService *services.BlockchainService
+}
+
+// customerPortalSessionRetention is how long expired portal sessions are kept for auditing
+const customerPortalSessionRetention = 7 * 24 * time.Hour
+
+const (
+	// taxIDReverificationInterval is how old a customer's last tax ID check may get before it is repeated
+	taxIDReverificationInterval = 30 * 24 * time.Hour
+	// taxIDReverificationBatchSize caps the registry checks made per run
+	taxIDReverificationBatchSize = 50
+)
+
+// checkRPCHealth probes the network RPC endpoints before a run so renewals start on endpoints that answer, and
+// ones that recovered since the last invocation are back in rotation
+func (app *Application) checkRPCHealth(ctx context.Context) {
+	if app.blockchainService == nil {
+		return
+	}
+	app.blockchainService.CheckRPCHealth(ctx)
+}
+
+// purgeExpiredPortalSessions deletes customer portal sessions that expired more than the retention period ago
+func (app *Application) purgeExpiredPortalSessions(ctx context.Context) {
+	if app.customerPortalService == nil {
+		return
+	}
+
+	deleted, err := app.customerPortalService.DeleteExpiredSessions(ctx, time.Now().Add(-customerPortalSessionRetention))
+	if err != nil {
+		logger.Error("Error purging expired customer portal sessions", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		logger.Info("Purged expired customer portal sessions", zap.Int64("deleted", deleted))
+	}
+}
+
+// reverifyCustomerTaxIDs checks again the tax IDs of customers whose last verification is out of date
+func (app *Application) reverifyCustomerTaxIDs(ctx context.Context) {
+	if app.taxIDVerificationService == nil {
+		return
+	}
+
+	checked, err := app.taxIDVerificationService.ReverifyCustomerTaxIDs(ctx, time.Now().Add(-taxIDReverificationInterval), taxIDReverificationBatchSize)
+	if err != nil {
+		logger.Error("Error re-verifying customer tax IDs", zap.Error(err))
+		return
+	}
+	if checked > 0 {
+		logger.Info("Re-verified customer tax IDs", zap.Int("checked", checked))
+	}
+}
+
+// releaseExpiredGasSponsorships gives budget held for sponsored transactions that neither confirmed
+// nor failed in time back to their workspaces
+func (app *Application) releaseExpiredGasSponsorships(ctx context.Context) {
+	if app.gasSponsorshipService == nil {
+		return
+	}
+
+	released, err := app.gasSponsorshipService.ReleaseExpiredSponsorships(ctx)
+	if err != nil {
+		logger.Error("Error releasing expired gas sponsorships", zap.Error(err))
+		return
+	}
+	if released > 0 {
+		logger.Info("Released expired gas sponsorships", zap.Int("released", released))
+	}
+}
+
+// mrrMovementNormalizationBatchSize caps the MRR movements converted per run
+const mrrMovementNormalizationBatchSize = 500
+
+// normalizeMRRMovements converts pending MRR movements to their workspace's reporting currency
+func (app *Application) normalizeMRRMovements(ctx context.Context) {
+	if app.mrrMovementService == nil {
+		return
+	}
+
+	normalized, err := app.mrrMovementService.NormalizePendingMovements(ctx, mrrMovementNormalizationBatchSize)
+	if err != nil {
+		logger.Error("Error normalizing MRR movements", zap.Error(err))
+		return
+	}
+	if normalized > 0 {
+		logger.Info("Normalized MRR movements", zap.Int("normalized", normalized))
+	}
+}
+
+// analyticsExportBatchSize caps the analytics exports and scheduled reports run per invocation
+const analyticsExportBatchSize = 10
+
+// processAnalyticsExports runs exports left queued or abandoned by the API, then delivers scheduled reports that are due
+func (app *Application) processAnalyticsExports(ctx context.Context) {
+	if app.analyticsExportService == nil {
+		return
+	}
+
+	completed, err := app.analyticsExportService.ProcessPendingExports(ctx, analyticsExportBatchSize)
+	if err != nil {
+		logger.Error("Error processing analytics exports", zap.Error(err))
+	} else if completed > 0 {
+		logger.Info("Processed analytics exports", zap.Int("completed", completed))
+	}
+
+	delivered, err := app.analyticsExportService.DeliverDueReports(ctx, time.Now(), analyticsExportBatchSize)
+	if err != nil {
+		logger.Error("Error delivering scheduled analytics reports", zap.Error(err))
+		return
+	}
+	if delivered > 0 {
+		logger.Info("Delivered scheduled analytics reports", zap.Int("delivered", delivered))
+	}
+}
+
+// checkDelegations re-checks subscription delegations against their caveats and on-chain state, then
+// reminds customers with outstanding reauthorizations
+func (app *Application) checkDelegations(ctx context.Context) {
+	if app.delegationMonitorService == nil {
+		return
+	}
+
+	result, err := app.delegationMonitorService.CheckDelegations(ctx, time.Now())
+	if err != nil {
+		logger.Error("Error checking subscription delegations", zap.Error(err))
+	} else if result.Checked > 0 {
+		logger.Info("Checked subscription delegations",
+			zap.Int("checked", result.Checked),
+			zap.Int("needs_reauthorization", result.NeedsReauthorization),
+			zap.Int("failed", result.Failed))
+	}
+
+	notified, err := app.delegationMonitorService.NotifyPendingReauthorizations(ctx, time.Now())
+	if err != nil {
+		logger.Error("Error sending delegation reauthorization reminders", zap.Error(err))
+		return
+	}
+	if notified > 0 {
+		logger.Info("Sent delegation reauthorization reminders", zap.Int("notified", notified))
+	}
+}
+
+// checkPaymentConfirmations follows payment transactions until they are final and rolls back payments
+// whose transactions were dropped, replaced or reverted
+func (app *Application) checkPaymentConfirmations(ctx context.Context) {
+	if app.transactionConfirmationService == nil {
+		return
+	}
+
+	result, err := app.transactionConfirmationService.CheckConfirmations(ctx, time.Now())
+	if err != nil {
+		logger.Error("Error checking payment confirmations", zap.Error(err))
+		return
+	}
+	if result.Checked > 0 || result.Failed > 0 {
+		logger.Info("Checked payment confirmations",
+			zap.Int64("tracked", result.Tracked),
+			zap.Int("checked", result.Checked),
+			zap.Int("confirmed", result.Confirmed),
+			zap.Int("finalized", result.Finalized),
+			zap.Int("rolled_back", result.RolledBack),
+			zap.Int("failed", result.Failed))
+	}
+}
+
+// sweepTreasury creates transfer challenges for the sweep rules that are due and reconciles earlier sweeps
+// against Circle's transactions
+func (app *Application) sweepTreasury(ctx context.Context) {
+	if app.treasuryService == nil {
+		return
+	}
+
+	now := time.Now()
+	swept, err := app.treasuryService.RunSweeps(ctx, now)
+	if err != nil {
+		logger.Error("Error running treasury sweeps", zap.Error(err))
+	} else if swept.Checked > 0 {
+		logger.Info("Ran treasury sweeps",
+			zap.Int("checked", swept.Checked),
+			zap.Int("challenged", swept.Challenged),
+			zap.Int("skipped", swept.Skipped),
+			zap.Int("failed", swept.Failed))
+	}
+
+	reconciled, err := app.treasuryService.ReconcileMovements(ctx, now)
+	if err != nil {
+		logger.Error("Error reconciling treasury movements", zap.Error(err))
+		return
+	}
+	if reconciled.Checked > 0 || reconciled.Errors > 0 {
+		logger.Info("Reconciled treasury movements",
+			zap.Int("checked", reconciled.Checked),
+			zap.Int("completed", reconciled.Completed),
+			zap.Int("failed", reconciled.Failed),
+			zap.Int("expired", reconciled.Expired),
+			zap.Int("errors", reconciled.Errors))
+	}
+}
+
+// snapshotPortfolios refreshes the wallet balances of workspaces without a portfolio snapshot for the current
+// period and records one for each
+func (app *Application) snapshotPortfolios(ctx context.Context) {
+	if app.portfolioService == nil {
+		return
+	}
+
+	result, err := app.portfolioService.RecordSnapshots(ctx, time.Now())
+	if err != nil {
+		logger.Error("Error snapshotting portfolios", zap.Error(err))
+		return
+	}
+	if result.Checked > 0 {
+		logger.Info("Snapshotted portfolios",
+			zap.Int("checked", result.Checked),
+			zap.Int("snapshots", result.Snapshots),
+			zap.Int("balances", result.Balances),
+			zap.Int("errors", result.Errors),
+			zap.Int("failed", result.Failed))
+	}
+}
+
+// refreshWalletNames looks up the ENS and Basenames names of customer wallets that were never looked up or are
+// due a refresh
+func (app *Application) refreshWalletNames(ctx context.Context) {
+	if app.walletNameService == nil {
+		return
+	}
+
+	result, err := app.walletNameService.RefreshWalletNames(ctx, time.Now())
+	if err != nil {
+		logger.Error("Error refreshing wallet names", zap.Error(err))
+		return
+	}
+	if result.Checked > 0 {
+		logger.Info("Refreshed wallet names",
+			zap.Int("checked", result.Checked),
+			zap.Int("changed", result.Changed),
+			zap.Int("errors", result.Errors))
+	}
+}
+
+// reencryptProviderCredentials moves stored provider credentials onto the current encryption key
+func (app *Application) reencryptProviderCredentials(ctx context.Context) {
+	if app.paymentSyncClient == nil {
+		return
+	}
+
+	logger.Info("Re-encrypting provider credentials...", zap.String("key_version", app.paymentSyncClient.CurrentKeyVersion()))
+	result, err := app.paymentSyncClient.ReencryptConfigurations(ctx, payment_sync.DefaultReencryptionBatchSize)
+	if err != nil {
+		logger.Error("Error re-encrypting provider credentials", zap.Error(err))
+		return
+	}
+	if result.Failed > 0 {
+		logger.Warn("Some provider credentials could not be re-encrypted", zap.Int("failed", result.Failed))
+	}
+}
+
+// notifyUnusedAPIKeys emails workspace owners about API keys that have gone unused
+func (app *Application) notifyUnusedAPIKeys(ctx context.Context) {
+	if app.emailService == nil || app.apiKeyUnusedDays <= 0 {
+		return
+	}
+
+	logger.Info("Checking for unused API keys...", zap.Int("unused_days", app.apiKeyUnusedDays))
+	notified, err := app.apiKeyService.NotifyUnusedAPIKeys(ctx, app.emailService, time.Duration(app.apiKeyUnusedDays)*24*time.Hour)
+	if err != nil {
+		logger.Error("Error notifying unused API keys", zap.Error(err))
+		return
+	}
+	logger.Info("Unused API key notifications sent", zap.Int("keys_notified", notified))
 }
 
 // HandleRequest is the actual Lambda handler function
@@ -39,6 +345,9 @@ type Application struct {
 func (app *Application) HandleRequest(ctx context.Context /*, event MyEvent - if you have a specific event type */) error {
 	logger.Info("Entering HandleRequest for subscription processing") // Using structured logger
 
+	// --- Check Network RPC Endpoints ---
+	app.checkRPCHealth(ctx)
+
 	// --- Process Subscriptions ---
 	logger.Info("Starting subscription processing...")
 	results, err := app.subscriptionProcessor.ProcessDueSubscriptions(ctx)
@@ -51,6 +360,7 @@ func (app *Application) HandleRequest(ctx context.Context /*, event MyEvent - if
 		zap.Int("total", results.Total),
 		zap.Int("succeeded", results.Succeeded),
 		zap.Int("failed", results.Failed),
+		zap.Int("deferred", results.Deferred),
 	)
 
 	// --- Detect Failed Payments and Create Dunning Campaigns ---
@@ -99,6 +409,42 @@ func (app *Application) HandleRequest(ctx context.Context /*, event MyEvent - if
 		app.scheduledChangesProcessor.ProcessChanges()
 	}
 
+	// --- Notify Owners of Unused API Keys ---
+	app.notifyUnusedAPIKeys(ctx)
+
+	// --- Re-encrypt Provider Credentials Under the Current Key ---
+	app.reencryptProviderCredentials(ctx)
+
+	// --- Purge Expired Customer Portal Sessions ---
+	app.purgeExpiredPortalSessions(ctx)
+
+	// --- Re-verify Customer Tax IDs ---
+	app.reverifyCustomerTaxIDs(ctx)
+
+	// --- Release Expired Gas Sponsorship Holds ---
+	app.releaseExpiredGasSponsorships(ctx)
+
+	// --- Convert MRR Movements to Reporting Currencies ---
+	app.normalizeMRRMovements(ctx)
+
+	// --- Run Analytics Exports and Scheduled Reports ---
+	app.processAnalyticsExports(ctx)
+
+	// --- Check Delegations and Request Reauthorization ---
+	app.checkDelegations(ctx)
+
+	// --- Track Payment Confirmations and Roll Back Dropped Transactions ---
+	app.checkPaymentConfirmations(ctx)
+
+	// --- Sweep Merchant Treasuries ---
+	app.sweepTreasury(ctx)
+
+	// --- Snapshot Workspace Portfolios ---
+	app.snapshotPortfolios(ctx)
+
+	// --- Refresh Customer Wallet Names ---
+	app.refreshWalletNames(ctx)
+
 	logger.Info("Subscription processing finished successfully in HandleRequest.")
 	return nil // Indicate successful execution to Lambda runtime
 }
@@ -106,6 +452,9 @@ func (app *Application) HandleRequest(ctx context.Context /*, event MyEvent - if
 func (a *Application) LocalHandleRequest(ctx context.Context) error {
 	logger.Info("Entering LocalHandleRequest for subscription processing")
 
+	// --- Check Network RPC Endpoints ---
+	a.checkRPCHealth(ctx)
+
 	// --- Process Subscriptions ---
 	logger.Info("Starting subscription processing...")
 	results, err := a.subscriptionProcessor.ProcessDueSubscriptions(ctx)
@@ -118,6 +467,7 @@ func (a *Application) LocalHandleRequest(ctx context.Context) error {
 		zap.Int("total", results.Total),
 		zap.Int("succeeded", results.Succeeded),
 		zap.Int("failed", results.Failed),
+		zap.Int("deferred", results.Deferred),
 	)
 
 	// --- Detect Failed Payments and Create Dunning Campaigns ---
@@ -166,6 +516,42 @@ func (a *Application) LocalHandleRequest(ctx context.Context) error {
 		a.scheduledChangesProcessor.ProcessChanges()
 	}
 
+	// --- Notify Owners of Unused API Keys ---
+	a.notifyUnusedAPIKeys(ctx)
+
+	// --- Re-encrypt Provider Credentials Under the Current Key ---
+	a.reencryptProviderCredentials(ctx)
+
+	// --- Purge Expired Customer Portal Sessions ---
+	a.purgeExpiredPortalSessions(ctx)
+
+	// --- Re-verify Customer Tax IDs ---
+	a.reverifyCustomerTaxIDs(ctx)
+
+	// --- Release Expired Gas Sponsorship Holds ---
+	a.releaseExpiredGasSponsorships(ctx)
+
+	// --- Convert MRR Movements to Reporting Currencies ---
+	a.normalizeMRRMovements(ctx)
+
+	// --- Run Analytics Exports and Scheduled Reports ---
+	a.processAnalyticsExports(ctx)
+
+	// --- Check Delegations and Request Reauthorization ---
+	a.checkDelegations(ctx)
+
+	// --- Track Payment Confirmations and Roll Back Dropped Transactions ---
+	a.checkPaymentConfirmations(ctx)
+
+	// --- Sweep Merchant Treasuries ---
+	a.sweepTreasury(ctx)
+
+	// --- Snapshot Workspace Portfolios ---
+	a.snapshotPortfolios(ctx)
+
+	// --- Refresh Customer Wallet Names ---
+	a.refreshWalletNames(ctx)
+
 	logger.Info("Subscription processing finished successfully in LocalHandleRequest.")
 	return nil // Indicate successful execution to Lambda runtime
 }
@@ -365,23 +751,58 @@ func main() {
 		logger.Warn("Email service not available, dunning retry engine will not be initialized")
 	}
 
+	// Payments and invoices share one tax service
+	var taxProvider interfaces.TaxProvider
+	if taxProviderAPIKey := os.Getenv("TAX_PROVIDER_API_KEY"); taxProviderAPIKey != "" {
+		taxProvider = tax_provider.NewClient(taxProviderAPIKey, os.Getenv("TAX_PROVIDER_URL"))
+		logger.Info("External tax provider enabled for invoices and payments")
+	}
+	taxIDVerificationService := services.NewTaxIDVerificationService(dbQueries, vies.NewClient(os.Getenv("VIES_URL"), os.Getenv("VIES_REQUESTER_VAT_NUMBER")))
+	taxService := services.NewTaxServiceWithDependencies(dbQueries, taxProvider, taxIDVerificationService)
+
 	// Initialize payment service for subscription management
 	cmcApiKey := os.Getenv("CMC_API_KEY")
-	paymentService := services.NewPaymentService(dbQueries, cmcApiKey)
+	paymentService := services.NewPaymentService(dbQueries, cmcApiKey).WithTaxService(taxService)
 
 	// Initialize customer service
 	customerService := services.NewCustomerService(dbQueries)
 
 	// Initialize services for invoice creation
-	taxService := services.NewTaxService(dbQueries)
 	discountService := services.NewDiscountService(dbQueries)
 	gasSponsorshipService := services.NewGasSponsorshipService(dbQueries)
 	currencyService := services.NewCurrencyService(dbQueries)
 	exchangeRateService := services.NewExchangeRateService(dbQueries, "")
 	invoiceService := services.NewInvoiceService(dbQueries, logger.Log, taxService, discountService, gasSponsorshipService, currencyService, exchangeRateService)
 
-	// Initialize subscription service
-	subscriptionService := services.NewSubscriptionService(dbQueries, delegationClient, paymentService, customerService, invoiceService)
+	// Initialize subscription service; renewal leases must outlast a redemption and no renewal
+	// should start that could not finish before the Lambda times out
+	renewalConfig := services.DefaultSubscriptionRenewalConfig()
+	if minLease := 2*rpcTimeout + time.Minute; renewalConfig.LeaseDuration < minLease {
+		renewalConfig.LeaseDuration = minLease
+	}
+	renewalConfig.DeadlineMargin = rpcTimeout + 30*time.Second
+	if workersStr := os.Getenv("SUBSCRIPTION_RENEWAL_WORKERS"); workersStr != "" {
+		if parsed, err := strconv.Atoi(workersStr); err == nil && parsed > 0 {
+			renewalConfig.WorkerCount = parsed
+		} else {
+			logger.Warn("Invalid SUBSCRIPTION_RENEWAL_WORKERS, using default", zap.String("value", workersStr), zap.Int("default", renewalConfig.WorkerCount))
+		}
+	}
+	if batchSizeStr := os.Getenv("SUBSCRIPTION_BATCH_SIZE"); batchSizeStr != "" {
+		if parsed, err := strconv.ParseInt(batchSizeStr, 10, 32); err == nil && parsed > 0 {
+			renewalConfig.BatchSize = int32(parsed)
+		} else {
+			logger.Warn("Invalid SUBSCRIPTION_BATCH_SIZE, using default", zap.String("value", batchSizeStr), zap.Int32("default", renewalConfig.BatchSize))
+		}
+	}
+	if redemptionBatchStr := os.Getenv("SUBSCRIPTION_REDEMPTION_BATCH_SIZE"); redemptionBatchStr != "" {
+		if parsed, err := strconv.Atoi(redemptionBatchStr); err == nil && parsed > 0 {
+			renewalConfig.RedemptionBatchSize = parsed
+		} else {
+			logger.Warn("Invalid SUBSCRIPTION_REDEMPTION_BATCH_SIZE, using default", zap.String("value", redemptionBatchStr), zap.Int("default", renewalConfig.RedemptionBatchSize))
+		}
+	}
+	subscriptionService := services.NewSubscriptionService(dbQueries, delegationClient, paymentService, customerService, invoiceService).WithRenewalConfig(renewalConfig)
 
 	// Create the scheduled changes processor
 	var scheduledChangesProcessor *processor.ScheduledChangesProcessor
@@ -389,6 +810,163 @@ func main() {
 		scheduledChangesProcessor = processor.NewScheduledChangesProcessor(dbQueries, paymentService, emailService, 5*time.Minute)
 	}
 
+	// Read how long API keys may go unused before owners are notified
+	apiKeyUnusedDays := 90
+	if unusedDaysStr := os.Getenv("API_KEY_UNUSED_NOTIFY_DAYS"); unusedDaysStr != "" {
+		if parsed, err := strconv.Atoi(unusedDaysStr); err == nil && parsed >= 0 {
+			apiKeyUnusedDays = parsed
+		} else {
+			logger.Warn("Invalid API_KEY_UNUSED_NOTIFY_DAYS, using default", zap.String("value", unusedDaysStr), zap.Int("default", apiKeyUnusedDays))
+		}
+	}
+
+	// Initialize the payment sync client used to re-encrypt provider credentials
+	var paymentSyncClient *payment_sync.PaymentSyncClient
+	paymentSyncEncryptionKey, err := secretsClient.GetSecretString(ctx, "PAYMENT_SYNC_ENCRYPTION_KEY_ARN", "PAYMENT_SYNC_ENCRYPTION_KEY")
+	if err != nil || paymentSyncEncryptionKey == "" {
+		logger.Warn("Payment Sync Encryption Key not available, credential re-encryption disabled", zap.Error(err))
+	} else {
+		paymentSyncKeys, err := payment_sync.LoadKeyProvider(ctx, secretsClient, paymentSyncEncryptionKey)
+		if err != nil {
+			logger.Fatal("Failed to initialize Payment Sync key provider", zap.Error(err))
+		}
+		paymentSyncClient = payment_sync.NewPaymentSyncClientWithKeyProvider(dbQueries, logger.Log, paymentSyncEncryptionKey, paymentSyncKeys)
+	}
+
+	// Initialize analytics exports, which must be stored in S3 in deployed stages; scheduled reports are only
+	// emailed when the email service is available
+	exportStorageConfig := services.ExportStorageConfigFromEnv()
+	if stage != helpers.StageLocal && exportStorageConfig.Backend != services.ExportStorageS3 {
+		logger.Fatal("Deployed stages must store analytics exports in S3, set EXPORT_STORAGE_BACKEND=s3",
+			zap.String("backend", exportStorageConfig.Backend))
+	}
+	exportStorage, err := services.NewExportStorage(ctx, exportStorageConfig)
+	if err != nil {
+		logger.Fatal("Failed to configure analytics export storage", zap.Error(err))
+	}
+	var reportEmailService services.IEmailService
+	if emailService != nil {
+		reportEmailService = emailService
+	}
+	analyticsExportService := services.NewAnalyticsExportService(dbQueries, exportStorage, services.NewExchangeRateService(dbQueries, cmcApiKey), reportEmailService)
+
+	// Initialize delegation monitoring; on-chain revocation and allowance checks need network RPCs
+	delegationMonitorConfig, err := services.DelegationMonitorConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid delegation monitor configuration", zap.Error(err))
+	}
+	rpcConfig, err := services.RPCPoolConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid RPC endpoint configuration", zap.Error(err))
+	}
+	var blockchainService *services.BlockchainService
+	if rpcAPIKey := os.Getenv("RPC_API_KEY"); rpcAPIKey != "" || len(rpcConfig.Endpoints) > 0 {
+		blockchainService = services.NewBlockchainService(dbQueries, rpcAPIKey).WithRPCPoolConfig(rpcConfig)
+		if err := blockchainService.Initialize(ctx); err != nil {
+			logger.Warn("Failed to connect to network RPCs, delegations will only be checked against their caveats and payment confirmations will not be tracked", zap.Error(err))
+			blockchainService = nil
+		}
+	}
+	var delegationChain services.DelegationStateReader
+	if blockchainService != nil {
+		delegationChain = blockchainService
+
+		// The Solana payment delegate is optional; without it Solana renewals cannot be charged
+		solanaDelegateSecret, err := secretsClient.GetSecretString(ctx, "SOLANA_DELEGATE_KEYPAIR_ARN", "SOLANA_DELEGATE_KEYPAIR")
+		if err != nil || solanaDelegateSecret == "" {
+			logger.Info("Solana delegate keypair not configured, Solana renewals will fail")
+		} else {
+			solanaDelegate, err := solana.ParseKeypair(solanaDelegateSecret)
+			if err != nil {
+				logger.Fatal("Invalid Solana delegate keypair", zap.Error(err))
+			}
+			blockchainService = blockchainService.WithSolanaDelegate(solanaDelegate)
+		}
+		subscriptionService = subscriptionService.WithSplPayments(blockchainService)
+
+		// Renewal redemptions are simulated against the DelegationManager to estimate their network fee
+		gasFeeService := services.NewGasFeeServiceWithOracle(dbQueries, services.NewExchangeRateService(dbQueries, cmcApiKey), services.NewGasFeeOracle(blockchainService))
+		subscriptionService = subscriptionService.WithGasEstimates(services.NewRedemptionGasEstimator(gasFeeService, delegationMonitorConfig.DelegationManager))
+	}
+	var reauthorizationEmailService services.IEmailService
+	if emailService != nil {
+		reauthorizationEmailService = emailService
+	}
+	reauthorizationPortalService := services.NewCustomerPortalService(dbQueries, nil, strings.TrimRight(os.Getenv("BASE_URL"), "/")+"/portal")
+	delegationMonitorService := services.NewDelegationMonitorService(dbQueries, delegationChain, reauthorizationEmailService, reauthorizationPortalService, delegationMonitorConfig)
+	if blockchainService != nil {
+		delegationMonitorService = delegationMonitorService.WithSplApprovals(blockchainService)
+	}
+
+	// Initialize payment confirmation tracking; it reads transactions from the network RPCs
+	var transactionConfirmationService *services.TransactionConfirmationService
+	if blockchainService != nil {
+		confirmationConfig := services.DefaultTransactionConfirmationConfig()
+		if batchSizeStr := os.Getenv("PAYMENT_CONFIRMATION_BATCH_SIZE"); batchSizeStr != "" {
+			if parsed, err := strconv.ParseInt(batchSizeStr, 10, 32); err == nil && parsed > 0 {
+				confirmationConfig.BatchSize = int32(parsed)
+			} else {
+				logger.Warn("Invalid PAYMENT_CONFIRMATION_BATCH_SIZE, using default", zap.String("value", batchSizeStr), zap.Int32("default", confirmationConfig.BatchSize))
+			}
+		}
+		if droppedAfterStr := os.Getenv("PAYMENT_CONFIRMATION_DROPPED_AFTER_MINUTES"); droppedAfterStr != "" {
+			if parsed, err := strconv.Atoi(droppedAfterStr); err == nil && parsed > 0 {
+				confirmationConfig.DroppedAfter = time.Duration(parsed) * time.Minute
+			} else {
+				logger.Warn("Invalid PAYMENT_CONFIRMATION_DROPPED_AFTER_MINUTES, using default", zap.String("value", droppedAfterStr), zap.Duration("default", confirmationConfig.DroppedAfter))
+			}
+		}
+		transactionConfirmationService = services.NewTransactionConfirmationService(dbQueries, connPool, blockchainService, confirmationConfig)
+	}
+
+	// Initialize treasury sweeps; they move funds out of merchants' Circle wallets
+	var treasuryService *services.TreasuryService
+	var circleClient circle.CircleClientInterface
+	circleAPIKey, err := secretsClient.GetSecretString(ctx, "CIRCLE_API_KEY_ARN", "CIRCLE_API_KEY")
+	if err != nil || circleAPIKey == "" {
+		logger.Warn("Circle API key not available, treasury sweeps disabled", zap.Error(err))
+	} else {
+		treasuryConfig, err := services.TreasuryConfigFromEnv()
+		if err != nil {
+			logger.Fatal("Invalid treasury configuration", zap.Error(err))
+		}
+		circleClient = circle.NewCircleClient(circleAPIKey)
+		treasuryService = services.NewTreasuryService(dbQueries, circleClient, treasuryConfig)
+	}
+
+	// Initialize portfolio snapshots; Circle wallets are read through Circle and other wallets from the chain
+	var portfolioService *services.PortfolioService
+	if circleClient == nil && blockchainService == nil {
+		logger.Warn("Neither Circle nor network RPCs are available, portfolio snapshots disabled")
+	} else {
+		portfolioConfig, err := services.PortfolioConfigFromEnv()
+		if err != nil {
+			logger.Fatal("Invalid portfolio configuration", zap.Error(err))
+		}
+		var chain interfaces.BlockchainService
+		if blockchainService != nil {
+			chain = blockchainService
+		}
+		portfolioService = services.NewPortfolioService(dbQueries, circleClient, chain, services.NewExchangeRateService(dbQueries, cmcApiKey), portfolioConfig)
+	}
+
+	// Initialize customer wallet name refreshes; names are resolved through the network RPCs
+	var walletNameService *services.CustomerService
+	if blockchainService == nil {
+		logger.Warn("Network RPCs are unavailable, wallet name refresh disabled")
+	} else {
+		nameResolverConfig, err := services.NameResolverConfigFromEnv()
+		if err != nil {
+			logger.Fatal("Invalid name resolver configuration", zap.Error(err))
+		}
+		walletNameConfig, err := services.WalletNameConfigFromEnv()
+		if err != nil {
+			logger.Fatal("Invalid wallet name configuration", zap.Error(err))
+		}
+		nameResolver := services.NewNameResolver(dbQueries, blockchainService, nameResolverConfig)
+		walletNameService = customerService.WithNameResolver(nameResolver, walletNameConfig)
+	}
+
 	// Create the subscription processor using the subscription service
 	app := &Application{
 		subscriptionProcessor:     processor.NewSubscriptionProcessor(subscriptionService),
@@ -396,6 +974,22 @@ func main() {
 		failureDetector:           failureDetector,
 		dunningService:            dunningService,
 		dunningRetryEngine:        dunningRetryEngine,
+		apiKeyService:             services.NewAPIKeyService(dbQueries),
+		emailService:              emailService,
+		apiKeyUnusedDays:          apiKeyUnusedDays,
+		paymentSyncClient:         paymentSyncClient,
+		// Portal actions are not used here, so no subscription management service is needed
+		customerPortalService:          services.NewCustomerPortalService(dbQueries, nil, ""),
+		taxIDVerificationService:       taxIDVerificationService,
+		gasSponsorshipService:          gasSponsorshipService,
+		mrrMovementService:             services.NewMRRMovementServiceWithExchangeRates(dbQueries, services.NewExchangeRateService(dbQueries, cmcApiKey)),
+		analyticsExportService:         analyticsExportService,
+		delegationMonitorService:       delegationMonitorService,
+		transactionConfirmationService: transactionConfirmationService,
+		treasuryService:                treasuryService,
+		portfolioService:               portfolioService,
+		walletNameService:              walletNameService,
+		blockchainService:              blockchainService,
 		// Store connPool and delegationClient in App struct if HandleRequest needs to close them,
 		// though typically you don't close them between warm invocations.
 	}
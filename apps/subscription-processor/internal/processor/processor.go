@@ -31,6 +31,7 @@ func (p *SubscriptionProcessor) ProcessDueSubscriptions(ctx context.Context) (*P
 		Total:     result.ProcessedCount,
 		Succeeded: result.SuccessfulCount,
 		Failed:    result.FailedCount,
+		Deferred:  result.DeferredCount,
 	}, nil
 }
 
@@ -39,4 +40,5 @@ type ProcessingResults struct {
 	Total     int
 	Succeeded int
 	Failed    int
+	Deferred  int // Claimed but left for the next run
 }
@@ -2,6 +2,7 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -29,6 +30,7 @@ type ScheduledChangesProcessor struct {
 	wg             sync.WaitGroup
 	paymentService interfaces.PaymentService
 	emailService   interfaces.EmailService
+	mrrMovements   *services.MRRMovementService
 }
 
 // emailServiceAdapter adapts interfaces.EmailService to services.IEmailService
@@ -65,8 +67,8 @@ func (a *paymentServiceAdapter) GetPayment(ctx context.Context, params params.Ge
 	return a.service.GetPayment(ctx, params)
 }
 
-func (a *paymentServiceAdapter) GetPaymentByTransactionHash(ctx context.Context, txHash string) (*db.Payment, error) {
-	return a.service.GetPaymentByTransactionHash(ctx, txHash)
+func (a *paymentServiceAdapter) GetPaymentByTransactionHash(ctx context.Context, txHash string, subscriptionEventID *uuid.UUID) (*db.Payment, error) {
+	return a.service.GetPaymentByTransactionHash(ctx, txHash, subscriptionEventID)
 }
 
 func (a *paymentServiceAdapter) ListPayments(ctx context.Context, params params.ListPaymentsParams) ([]db.Payment, error) {
@@ -104,6 +106,7 @@ func NewScheduledChangesProcessor(
 		stopCh:         make(chan struct{}),
 		paymentService: paymentService,
 		emailService:   emailService,
+		mrrMovements:   services.NewMRRMovementService(dbQueries),
 	}
 }
 
@@ -230,6 +233,20 @@ func (p *ScheduledChangesProcessor) processCancellations(ctx context.Context) er
 				zap.Error(err))
 		}
 
+		// Record the churned MRR
+		_, err = p.mrrMovements.RecordMovement(ctx, params.RecordMRRMovementParams{
+			Subscription:    sub,
+			Source:          business.MRRSourceCancel,
+			FromAmountCents: int64(sub.TotalAmountInCents),
+			ToAmountCents:   0,
+			OccurredAt:      time.Now(),
+		})
+		if err != nil && !errors.Is(err, services.ErrNoMRRMovement) {
+			p.logger.Error("Failed to record cancellation MRR movement",
+				zap.String("subscription_id", sub.ID.String()),
+				zap.Error(err))
+		}
+
 		// Send cancellation email
 		if p.emailService != nil {
 			err := p.sendCancellationEmail(ctx, sub)
@@ -814,10 +814,14 @@ func main() {
 	if err != nil || paymentSyncEncryptionKey == "" {
 		logger.Fatal("Failed to get Payment Sync Encryption Key", zap.Error(err))
 	}
+	paymentSyncKeys, err := payment_sync.LoadKeyProvider(ctx, secretsClient, paymentSyncEncryptionKey)
+	if err != nil {
+		logger.Fatal("Failed to initialize Payment Sync key provider", zap.Error(err))
+	}
 
 	// --- Initialize Payment Sync Client ---
 	// Note: No global Stripe service needed - workspace-specific services are created dynamically
-	paymentSyncClient := payment_sync.NewPaymentSyncClient(dbQueries, logger.Log, paymentSyncEncryptionKey)
+	paymentSyncClient := payment_sync.NewPaymentSyncClientWithKeyProvider(dbQueries, logger.Log, paymentSyncEncryptionKey, paymentSyncKeys)
 
 	// --- Create Application Instance ---
 	app := &Application{
@@ -14,6 +14,7 @@ import (
 
 	awsclient "github.com/cyphera/cyphera-api/libs/go/client/aws"
 	"github.com/cyphera/cyphera-api/libs/go/client/payment_sync"
+	"github.com/cyphera/cyphera-api/libs/go/client/payment_sync/stripe"
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/helpers"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
@@ -23,6 +24,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
@@ -34,8 +37,15 @@ type Application struct {
 	sqsClient         *sqs.Client
 	sqsQueueURL       string
 	dbQueries         *db.Queries
+	// verifiers checks provider signatures before a webhook is trusted
+	verifiers *payment_sync.WebhookVerifierRegistry
+	// replayTTL is how long received event IDs are remembered to reject replays
+	replayTTL time.Duration
 }
 
+// defaultWebhookReplayTTL covers Stripe's retry schedule of up to three days
+const defaultWebhookReplayTTL = 72 * time.Hour
+
 // HandleAPIGatewayRequest processes incoming webhook requests from API Gateway
 // @godoc HandleAPIGatewayRequest processes payment provider webhook requests via API Gateway
 func (app *Application) HandleAPIGatewayRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
@@ -81,15 +91,9 @@ func (app *Application) HandleAPIGatewayRequest(ctx context.Context, request eve
 		}, nil
 	}
 
-	// Get signature header (provider-specific)
-	var signatureHeader string
-	switch provider {
-	case "stripe":
-		signatureHeader = request.Headers["stripe-signature"]
-		if signatureHeader == "" {
-			signatureHeader = request.Headers["Stripe-Signature"] // Try capitalized version
-		}
-	default:
+	// Look up the signature verifier for this provider
+	verifier, ok := app.verifiers.Get(provider)
+	if !ok {
 		logger.Error("Unsupported provider", zap.String("provider", provider))
 		return events.APIGatewayProxyResponse{
 			StatusCode: 400,
@@ -100,6 +104,7 @@ func (app *Application) HandleAPIGatewayRequest(ctx context.Context, request eve
 		}, nil
 	}
 
+	signatureHeader := payment_sync.SignatureFromHeaders(verifier, request.Headers)
 	if signatureHeader == "" {
 		logger.Error("Missing signature header",
 			zap.String("provider", provider))
@@ -128,13 +133,82 @@ func (app *Application) HandleAPIGatewayRequest(ctx context.Context, request eve
 		}, nil
 	}
 
-	// Get the provider service configured for this workspace
-	providerService, err := app.paymentSyncClient.GetProviderService(ctx, workspaceID, provider)
+	// Verify the signature against the current secret and any secret still in its rotation grace period
+	secrets, err := app.paymentSyncClient.GetWebhookSecrets(ctx, workspaceID, provider)
+	if err != nil {
+		logger.Error("Failed to load webhook secrets",
+			zap.String("provider", provider),
+			zap.String("workspace_id", workspaceID),
+			zap.Error(err))
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Body:       `{"error": "provider service not available"}`,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+		}, nil
+	}
+
+	verified, err := verifier.Verify([]byte(request.Body), signatureHeader, secrets, time.Now())
+	if err != nil {
+		reason := payment_sync.WebhookRejectionReason(err)
+		logger.Warn("Rejected webhook with invalid signature",
+			zap.String("provider", provider),
+			zap.String("workspace_id", workspaceID),
+			zap.String("reason", reason),
+			zap.Error(err))
+		app.logRejectedWebhook(ctx, workspaceID, provider, "", reason, false, err)
+		return events.APIGatewayProxyResponse{
+			StatusCode: 400,
+			Body:       `{"error": "webhook validation failed"}`,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+		}, nil
+	}
+
+	// Remember the event ID before enqueueing so a replayed delivery is never processed twice
+	firstDelivery, err := app.recordWebhookDelivery(ctx, workspaceID, provider, verified.EventID)
+	if err != nil {
+		logger.Error("Failed to record webhook in replay cache",
+			zap.String("provider", provider),
+			zap.String("workspace_id", workspaceID),
+			zap.String("event_id", verified.EventID),
+			zap.Error(err))
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Body:       `{"error": "failed to queue event"}`,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+		}, nil
+	}
+	if !firstDelivery {
+		logger.Warn("Rejected replayed webhook",
+			zap.String("provider", provider),
+			zap.String("workspace_id", workspaceID),
+			zap.String("event_id", verified.EventID),
+			zap.String("reason", payment_sync.WebhookRejectReplayed))
+		// The signature checked out; the delivery is rejected only because the event was already received
+		app.logRejectedWebhook(ctx, workspaceID, provider, verified.EventID, payment_sync.WebhookRejectReplayed, true, nil)
+		// Acknowledge so the provider stops retrying an event we already have
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Body:       `{"status": "duplicate"}`,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+		}, nil
+	}
+
+	// Get the provider service configured with the secret that matched
+	providerService, err := app.paymentSyncClient.GetProviderServiceForWebhook(ctx, workspaceID, provider, verified.Secret, verifier.Tolerance())
 	if err != nil {
 		logger.Error("Failed to get provider service",
 			zap.String("provider", provider),
 			zap.String("workspace_id", workspaceID),
 			zap.Error(err))
+		app.releaseWebhookDelivery(ctx, workspaceID, provider, verified.EventID)
 		return events.APIGatewayProxyResponse{
 			StatusCode: 500,
 			Body:       `{"error": "provider service not available"}`,
@@ -144,7 +218,7 @@ func (app *Application) HandleAPIGatewayRequest(ctx context.Context, request eve
 		}, nil
 	}
 
-	// Validate and parse webhook using the provider service
+	// Parse the webhook using the provider service
 	webhookEvent, err := providerService.HandleWebhook(
 		ctx,
 		[]byte(request.Body),
@@ -155,6 +229,7 @@ func (app *Application) HandleAPIGatewayRequest(ctx context.Context, request eve
 			zap.String("provider", provider),
 			zap.String("workspace_id", workspaceID),
 			zap.Error(err))
+		app.releaseWebhookDelivery(ctx, workspaceID, provider, verified.EventID)
 		return events.APIGatewayProxyResponse{
 			StatusCode: 400,
 			Body:       `{"error": "webhook validation failed"}`,
@@ -183,6 +258,8 @@ func (app *Application) HandleAPIGatewayRequest(ctx context.Context, request eve
 			zap.String("workspace_id", workspaceID),
 			zap.String("event_id", webhookEvent.ProviderEventID),
 			zap.Error(err))
+		// Release the event ID so the provider's retry is accepted
+		app.releaseWebhookDelivery(ctx, workspaceID, provider, verified.EventID)
 		return events.APIGatewayProxyResponse{
 			StatusCode: 500,
 			Body:       `{"error": "failed to queue event"}`,
@@ -269,6 +346,80 @@ func (app *Application) resolveWorkspaceFromStripeWebhook(ctx context.Context, b
 	return providerAccount.WorkspaceID.String(), nil
 }
 
+// recordWebhookDelivery adds an event ID to the replay cache, returning false if it was already there
+func (app *Application) recordWebhookDelivery(ctx context.Context, workspaceID, provider, eventID string) (bool, error) {
+	wsID, err := uuid.Parse(workspaceID)
+	if err != nil {
+		return false, fmt.Errorf("invalid workspace ID: %w", err)
+	}
+
+	rows, err := app.dbQueries.InsertWebhookReplayEntry(ctx, db.InsertWebhookReplayEntryParams{
+		WorkspaceID:     wsID,
+		ProviderName:    provider,
+		ProviderEventID: eventID,
+		ExpiresAt:       pgtype.Timestamptz{Time: time.Now().Add(app.replayTTL), Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// releaseWebhookDelivery removes an event ID from the replay cache after the event could not be queued
+func (app *Application) releaseWebhookDelivery(ctx context.Context, workspaceID, provider, eventID string) {
+	wsID, err := uuid.Parse(workspaceID)
+	if err != nil {
+		return
+	}
+
+	err = app.dbQueries.DeleteWebhookReplayEntry(ctx, db.DeleteWebhookReplayEntryParams{
+		WorkspaceID:     wsID,
+		ProviderName:    provider,
+		ProviderEventID: eventID,
+	})
+	if err != nil {
+		logger.Error("Failed to release webhook from replay cache",
+			zap.String("provider", provider),
+			zap.String("workspace_id", workspaceID),
+			zap.String("event_id", eventID),
+			zap.Error(err))
+	}
+}
+
+// logRejectedWebhook stores a rejected webhook in payment_sync_events along with the rejection reason and
+// whether its signature was valid
+func (app *Application) logRejectedWebhook(ctx context.Context, workspaceID, provider, eventID, reason string, signatureValid bool, cause error) {
+	wsID, err := uuid.Parse(workspaceID)
+	if err != nil {
+		return
+	}
+
+	details := map[string]string{"reason": reason}
+	message := fmt.Sprintf("Webhook rejected: %s", reason)
+	if cause != nil {
+		details["error"] = cause.Error()
+		message = cause.Error()
+	}
+	detailsJSON, _ := json.Marshal(details)
+
+	_, err = app.dbQueries.LogWebhookRejected(ctx, db.LogWebhookRejectedParams{
+		WorkspaceID:    wsID,
+		ProviderName:   provider,
+		EventMessage:   pgtype.Text{String: message, Valid: true},
+		EventDetails:   detailsJSON,
+		WebhookEventID: pgtype.Text{String: eventID, Valid: eventID != ""},
+		SignatureValid: pgtype.Bool{Bool: signatureValid, Valid: true},
+	})
+	if err != nil {
+		logger.Error("Failed to log rejected webhook",
+			zap.String("provider", provider),
+			zap.String("workspace_id", workspaceID),
+			zap.String("reason", reason),
+			zap.Error(err))
+	}
+}
+
 // queueWebhookEvent sends the webhook event to SQS for processing
 func (app *Application) queueWebhookEvent(ctx context.Context, webhookEvent payment_sync.WebhookEvent, workspaceID string) error {
 	// Serialize webhook event
@@ -356,14 +507,12 @@ func (app *Application) LocalHandleRequest(ctx context.Context) error {
 		}
 
 		// Get signature header
-		var signatureHeader string
-		switch provider {
-		case "stripe":
-			signatureHeader = r.Header.Get("Stripe-Signature")
-		default:
+		verifier, ok := app.verifiers.Get(provider)
+		if !ok {
 			http.Error(w, "Unsupported provider", http.StatusBadRequest)
 			return
 		}
+		signatureHeader := r.Header.Get(verifier.SignatureHeader())
 
 		// Convert HTTP request to API Gateway event format
 		apiGatewayEvent := events.APIGatewayProxyRequest{
@@ -373,8 +522,7 @@ func (app *Application) LocalHandleRequest(ctx context.Context) error {
 				"provider": provider,
 			},
 			Headers: map[string]string{
-				"stripe-signature": signatureHeader,
-				"Stripe-Signature": signatureHeader,
+				verifier.SignatureHeader(): signatureHeader,
 			},
 			Body: string(body),
 		}
@@ -509,10 +657,44 @@ func main() {
 	if err != nil || paymentSyncEncryptionKey == "" {
 		logger.Fatal("Failed to get Payment Sync Encryption Key", zap.Error(err))
 	}
+	paymentSyncKeys, err := payment_sync.LoadKeyProvider(ctx, secretsClient, paymentSyncEncryptionKey)
+	if err != nil {
+		logger.Fatal("Failed to initialize Payment Sync key provider", zap.Error(err))
+	}
 
 	// --- Initialize Payment Sync Client ---
-	// Note: No global Stripe service needed - webhooks are routed by workspace resolution
-	paymentSyncClient := payment_sync.NewPaymentSyncClient(dbQueries, logger.Log, paymentSyncEncryptionKey)
+	// Providers are configured per workspace when a webhook is resolved
+	paymentSyncClient := payment_sync.NewPaymentSyncClientWithKeyProvider(dbQueries, logger.Log, paymentSyncEncryptionKey, paymentSyncKeys)
+	paymentSyncClient.RegisterProvider("stripe", func() payment_sync.PaymentSyncService {
+		return stripe.NewStripeService(logger.Log, dbQueries)
+	})
+
+	// --- Initialize Webhook Signature Verifiers ---
+	signatureTolerance := payment_sync.DefaultWebhookTolerance
+	if toleranceStr := os.Getenv("WEBHOOK_SIGNATURE_TOLERANCE"); toleranceStr != "" {
+		signatureTolerance, err = time.ParseDuration(toleranceStr)
+		if err != nil {
+			logger.Fatal("Invalid WEBHOOK_SIGNATURE_TOLERANCE", zap.Error(err))
+		}
+	}
+	verifiers := payment_sync.NewWebhookVerifierRegistry()
+	verifiers.Register(stripe.NewStripeWebhookVerifier(signatureTolerance))
+
+	replayTTL := defaultWebhookReplayTTL
+	if ttlStr := os.Getenv("WEBHOOK_REPLAY_TTL"); ttlStr != "" {
+		replayTTL, err = time.ParseDuration(ttlStr)
+		if err != nil {
+			logger.Fatal("Invalid WEBHOOK_REPLAY_TTL", zap.Error(err))
+		}
+	}
+
+	// Purge expired replay entries and retired secrets once per cold start
+	if err := dbQueries.DeleteExpiredWebhookReplayEntries(ctx); err != nil {
+		logger.Warn("Failed to delete expired webhook replay entries", zap.Error(err))
+	}
+	if err := dbQueries.DeleteExpiredWorkspaceWebhookSecrets(ctx); err != nil {
+		logger.Warn("Failed to delete expired webhook secrets", zap.Error(err))
+	}
 
 	// --- Initialize SQS Client (for deployed stages) ---
 	var sqsClient *sqs.Client
@@ -536,6 +718,8 @@ func main() {
 		sqsClient:         sqsClient,
 		sqsQueueURL:       sqsQueueURL,
 		dbQueries:         dbQueries,
+		verifiers:         verifiers,
+		replayTTL:         replayTTL,
 	}
 
 	if stage == helpers.StageLocal {
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/cyphera/cyphera-api/libs/go/constants"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+
+	"github.com/gin-gonic/gin"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// PortalSessionHeader carries a customer portal session token
+const PortalSessionHeader = "X-Portal-Session"
+
+// EnsureValidCustomerAuth is a middleware for customer portal routes. It accepts either a
+// merchant-issued portal session token, which scopes the request to one workspace, or the
+// customer's own Web3Auth token, which covers the customer's subscriptions with every merchant.
+func (ac *AuthClient) EnsureValidCustomerAuth(services interfaces.CommonServicesInterface, portal interfaces.CustomerPortalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		sessionToken := c.GetHeader(PortalSessionHeader)
+		if sessionToken == "" && strings.HasPrefix(authHeader, "Bearer cps_") {
+			sessionToken = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+
+		if sessionToken != "" {
+			session, err := portal.AuthenticateSession(c.Request.Context(), sessionToken)
+			if err != nil {
+				logger.Log.Debug("Customer portal session validation failed", zap.Error(err))
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired portal session"})
+				c.Abort()
+				return
+			}
+
+			c.Set("customerID", session.CustomerID.String())
+			c.Set("workspaceID", session.WorkspaceID.String())
+			c.Set("portalSessionID", session.ID.String())
+			c.Set("authType", constants.AuthTypePortalSession)
+			c.Next()
+			return
+		}
+
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "No authentication provided"})
+			c.Abort()
+			return
+		}
+
+		claims, err := ac.validateWeb3AuthToken(authHeader)
+		if err != nil {
+			logger.Log.Debug("Customer Web3Auth token validation failed", zap.Error(err))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": ErrInvalidToken.Error()})
+			c.Abort()
+			return
+		}
+
+		web3AuthID := claims.UserId
+		if web3AuthID == "" {
+			web3AuthID = claims.VerifierId
+		}
+		if web3AuthID == "" {
+			web3AuthID = claims.Subject
+		}
+		if web3AuthID == "" {
+			web3AuthID = claims.Email
+		}
+
+		customer, err := services.GetDB().GetCustomerByWeb3AuthID(c.Request.Context(), pgtype.Text{String: web3AuthID, Valid: web3AuthID != ""})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Customer not found"})
+			} else {
+				logger.Log.Error("Failed to get customer by Web3Auth ID", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate customer"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("customerID", customer.ID.String())
+		c.Set("authType", constants.AuthTypeCustomerJWT)
+		c.Next()
+	}
+}
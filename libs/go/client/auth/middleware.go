@@ -17,6 +17,7 @@ import (
 	"github.com/cyphera/cyphera-api/libs/go/helpers"
 	"github.com/cyphera/cyphera-api/libs/go/interfaces"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 
 	"github.com/MicahParks/keyfunc/v2"
 	"github.com/gin-gonic/gin"
@@ -32,6 +33,16 @@ var (
 	ErrInvalidToken = errors.New("invalid token")
 )
 
+const (
+	// maxAPIKeyUsageWrites caps the API key usage writes in flight; usage beyond it is dropped rather than queued
+	maxAPIKeyUsageWrites = 64
+	// apiKeyUsageWriteTimeout bounds a single API key usage write
+	apiKeyUsageWriteTimeout = 5 * time.Second
+)
+
+// apiKeyUsageWrites holds a slot for every API key usage write in flight
+var apiKeyUsageWrites = make(chan struct{}, maxAPIKeyUsageWrites)
+
 // Context keys for storing values (moved from handlers/middleware.go)
 const (
 	RequestIDKey = "request_id"
@@ -143,6 +154,7 @@ func (ac *AuthClient) EnsureValidAPIKeyOrToken(services interfaces.CommonService
 			c.Set("workspaceID", workspace.ID.String())
 			c.Set("accountID", account.ID.String())
 			c.Set("accountType", string(account.AccountType))
+			c.Set("apiKeyID", key.ID.String())
 			c.Set("apiKeyLevel", string(key.AccessLevel))
 			c.Set("authType", constants.AuthTypeAPIKey)
 			c.Next()
@@ -276,6 +288,33 @@ func (ac *AuthClient) EnsureValidAPIKeyOrToken(services interfaces.CommonService
 	}
 }
 
+// recordAPIKeyUsage writes API key usage in the background. The write outlives the request, so it runs on a
+// context that is not cancelled with it, and failures are only logged.
+func recordAPIKeyUsage(ctx context.Context, apiKeys interfaces.APIKeyService, usage params.RecordAPIKeyUsageParams) {
+	select {
+	case apiKeyUsageWrites <- struct{}{}:
+	default:
+		logger.Log.Warn("Dropping API key usage, too many usage writes in flight",
+			zap.String("key_id", usage.APIKeyID.String()),
+		)
+		return
+	}
+
+	go func() {
+		defer func() { <-apiKeyUsageWrites }()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), apiKeyUsageWriteTimeout)
+		defer cancel()
+
+		if err := apiKeys.RecordAPIKeyUsage(ctx, usage); err != nil {
+			logger.Log.Warn("Failed to record API key usage",
+				zap.String("key_id", usage.APIKeyID.String()),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
 // validateAPIKey validates the API key and returns workspace and account information
 func (ac *AuthClient) validateAPIKey(c *gin.Context, services interfaces.CommonServicesInterface, apiKey string) (db.Workspace, db.Account, db.ApiKey, error) {
 	// Log the API key being validated (first few characters for security)
@@ -325,15 +364,14 @@ func (ac *AuthClient) validateAPIKey(c *gin.Context, services interfaces.CommonS
 		return db.Workspace{}, db.Account{}, db.ApiKey{}, fmt.Errorf("invalid API key")
 	}
 
-	// Update last used timestamp
-	go func() {
-		if err := services.GetDB().UpdateAPIKeyLastUsed(c.Request.Context(), key.ID); err != nil {
-			logger.Log.Warn("Failed to update API key last used timestamp",
-				zap.String("key_id", key.ID.String()),
-				zap.Error(err),
-			)
-		}
-	}()
+	// Usage is recorded in the background, so the write never slows down or fails the request
+	recordAPIKeyUsage(c.Request.Context(), services.GetAPIKeyService(), params.RecordAPIKeyUsageParams{
+		APIKeyID:    key.ID,
+		WorkspaceID: key.WorkspaceID,
+		ClientIP:    c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+		UsedAt:      time.Now(),
+	})
 
 	logger.Log.Debug("API key found",
 		zap.String("key_id", key.ID.String()),
@@ -947,7 +985,7 @@ func (ac *AuthClient) createSmartAccountWallet(ctx context.Context, queries *db.
 	wallet, err := queries.CreateWallet(ctx, db.CreateWalletParams{
 		WorkspaceID:   workspaceID,
 		WalletType:    "web3auth",
-		WalletAddress: smartAccountAddress,
+		WalletAddress: helpers.NormalizeWalletAddress(smartAccountAddress, string(db.NetworkTypeEvm)),
 		NetworkType:   db.NetworkTypeEvm, // Default network
 		Nickname:      pgtype.Text{String: "Web3Auth Smart Account", Valid: true},
 		IsPrimary:     pgtype.Bool{Bool: true, Valid: true},
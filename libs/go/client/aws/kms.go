@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// kmsRequestTimeout bounds a single KMS call so a slow endpoint cannot stall credential reads
+const kmsRequestTimeout = 10 * time.Second
+
+// KMSClient is a minimal AWS KMS client for wrapping and unwrapping data keys.
+// It speaks the KMS JSON protocol directly with SigV4 signing, so it also works against
+// KMS-compatible endpoints such as LocalStack.
+type KMSClient struct {
+	cfg        aws.Config
+	endpoint   string
+	httpClient aws.HTTPClient
+	signer     *v4.Signer
+}
+
+// NewKMSClient creates a KMS client using the default AWS configuration chain.
+// An empty endpoint uses the regional AWS endpoint.
+func NewKMSClient(ctx context.Context, endpoint string) (*KMSClient, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS SDK config: %w", err)
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("AWS region is required for KMS")
+	}
+
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", cfg.Region)
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: kmsRequestTimeout}
+	}
+
+	return &KMSClient{
+		cfg:        cfg,
+		endpoint:   endpoint,
+		httpClient: httpClient,
+		signer:     v4.NewSigner(),
+	}, nil
+}
+
+// Encrypt encrypts up to 4 KB of plaintext (typically a data key) under the given KMS key
+func (c *KMSClient) Encrypt(ctx context.Context, keyID string, plaintext []byte, encryptionContext map[string]string) ([]byte, error) {
+	input := struct {
+		KeyId             string            `json:"KeyId"`
+		Plaintext         []byte            `json:"Plaintext"`
+		EncryptionContext map[string]string `json:"EncryptionContext,omitempty"`
+	}{
+		KeyId:             keyID,
+		Plaintext:         plaintext,
+		EncryptionContext: encryptionContext,
+	}
+	var output struct {
+		CiphertextBlob []byte `json:"CiphertextBlob"`
+	}
+
+	if err := c.call(ctx, "TrentService.Encrypt", input, &output); err != nil {
+		return nil, err
+	}
+	return output.CiphertextBlob, nil
+}
+
+// Decrypt decrypts a ciphertext blob produced by Encrypt.
+// The encryption context must match the one used to encrypt.
+func (c *KMSClient) Decrypt(ctx context.Context, keyID string, ciphertext []byte, encryptionContext map[string]string) ([]byte, error) {
+	input := struct {
+		KeyId             string            `json:"KeyId,omitempty"`
+		CiphertextBlob    []byte            `json:"CiphertextBlob"`
+		EncryptionContext map[string]string `json:"EncryptionContext,omitempty"`
+	}{
+		KeyId:             keyID,
+		CiphertextBlob:    ciphertext,
+		EncryptionContext: encryptionContext,
+	}
+	var output struct {
+		Plaintext []byte `json:"Plaintext"`
+	}
+
+	if err := c.call(ctx, "TrentService.Decrypt", input, &output); err != nil {
+		return nil, err
+	}
+	return output.Plaintext, nil
+}
+
+// call sends a signed KMS JSON request and decodes the response into output
+func (c *KMSClient) call(ctx context.Context, target string, input interface{}, output interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, kmsRequestTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal KMS request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create KMS request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", target)
+
+	credentials, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	payloadHash := sha256.Sum256(body)
+	err = c.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(payloadHash[:]), "kms", c.cfg.Region, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to sign KMS request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("KMS request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read KMS response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var kmsErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &kmsErr)
+		return fmt.Errorf("KMS %s failed with status %d: %s %s", target, resp.StatusCode, kmsErr.Type, kmsErr.Message)
+	}
+
+	if err := json.Unmarshal(respBody, output); err != nil {
+		return fmt.Errorf("failed to decode KMS response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// s3RequestTimeout bounds a single object upload or download request
+const s3RequestTimeout = 2 * time.Minute
+
+// unsignedPayload is the payload hash used for requests without a body to hash
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// ErrS3ObjectNotFound is returned when an object does not exist
+var ErrS3ObjectNotFound = errors.New("s3 object not found")
+
+// S3ClientConfig configures an S3Client
+type S3ClientConfig struct {
+	Bucket string
+	// Endpoint is the base URL of an S3-compatible service such as MinIO or R2.
+	// Empty uses the regional AWS endpoint.
+	Endpoint string
+	// Region overrides the region from the default AWS configuration chain
+	Region string
+	// UsePathStyle addresses objects as endpoint/bucket/key instead of bucket.endpoint/key.
+	// Most S3-compatible services need it.
+	UsePathStyle bool
+}
+
+// S3Client is a minimal S3 client for storing and reading whole objects.
+// It speaks the S3 REST protocol directly with SigV4 signing, so it also works against
+// S3-compatible services.
+type S3Client struct {
+	cfg        aws.Config
+	bucket     string
+	baseURL    *url.URL
+	pathStyle  bool
+	httpClient aws.HTTPClient
+	signer     *v4.Signer
+}
+
+// NewS3Client creates an S3 client using the default AWS configuration chain
+func NewS3Client(ctx context.Context, clientConfig S3ClientConfig) (*S3Client, error) {
+	if clientConfig.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket is required")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS SDK config: %w", err)
+	}
+	if clientConfig.Region != "" {
+		cfg.Region = clientConfig.Region
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("AWS region is required for S3")
+	}
+
+	endpoint := clientConfig.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	baseURL, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: s3RequestTimeout}
+	}
+
+	return &S3Client{
+		cfg:        cfg,
+		bucket:     clientConfig.Bucket,
+		baseURL:    baseURL,
+		pathStyle:  clientConfig.UsePathStyle,
+		httpClient: httpClient,
+		signer:     v4.NewSigner(),
+	}, nil
+}
+
+// PutObject stores data under key, replacing any existing object
+func (c *S3Client) PutObject(ctx context.Context, key, contentType string, data []byte) error {
+	payloadHash := sha256.Sum256(data)
+	resp, err := c.do(ctx, http.MethodPut, key, bytes.NewReader(data), hex.EncodeToString(payloadHash[:]), func(req *http.Request) {
+		req.ContentLength = int64(len(data))
+		req.Header.Set("Content-Type", contentType)
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(http.MethodPut, key, resp)
+	}
+	return nil
+}
+
+// GetObject opens the object stored under key. The caller must close the returned body.
+func (c *S3Client) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, unsignedPayload, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrS3ObjectNotFound, key)
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error(http.MethodGet, key, resp)
+	}
+}
+
+// do sends a signed request for an object
+func (c *S3Client) do(ctx context.Context, method, key string, body io.Reader, payloadHash string, prepare func(*http.Request)) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.objectURL(key), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	if prepare != nil {
+		prepare(req)
+	}
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	credentials, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	if err := c.signer.SignHTTP(ctx, credentials, req, payloadHash, "s3", c.cfg.Region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign S3 request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// objectURL returns the URL of an object in path-style or virtual-hosted-style addressing
+func (c *S3Client) objectURL(key string) string {
+	u := *c.baseURL
+	escapedKey := escapeS3Key(key)
+	if c.pathStyle {
+		u.Path = u.Path + "/" + c.bucket + "/" + key
+		u.RawPath = u.Path[:len(u.Path)-len(key)] + escapedKey
+	} else {
+		u.Host = c.bucket + "." + u.Host
+		u.Path = u.Path + "/" + key
+		u.RawPath = u.Path[:len(u.Path)-len(key)] + escapedKey
+	}
+	return u.String()
+}
+
+// escapeS3Key escapes each segment of an object key, keeping the separators
+func escapeS3Key(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// s3Error builds an error from an S3 error response
+func s3Error(method, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("S3 %s %s failed with status %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
@@ -42,6 +44,86 @@ type DelegationData struct {
 	Signature string          `json:"signature"`
 }
 
+// ErrUnsupportedByServer is returned when the delegation server is older than the RPC being called
+var ErrUnsupportedByServer = errors.New("delegation server does not support this call")
+
+// RedemptionError is a structured error reported by the delegation server.
+// Use errors.As to inspect the code of a failed redemption.
+type RedemptionError struct {
+	Code         proto.ErrorCode
+	Message      string
+	RevertReason string
+	Retryable    bool
+}
+
+// Error implements the error interface
+func (e *RedemptionError) Error() string {
+	message := e.Message
+	if e.RevertReason != "" && !strings.Contains(message, e.RevertReason) {
+		message = fmt.Sprintf("%s: %s", message, e.RevertReason)
+	}
+	if e.Code == proto.ErrorCode_ERROR_CODE_UNSPECIFIED {
+		return message
+	}
+	return fmt.Sprintf("%s (%s)", message, e.Code)
+}
+
+// OutcomeUnknown reports whether the redemption was sent but not confirmed, so it may still be executed
+func (e *RedemptionError) OutcomeUnknown() bool {
+	return e.Code == proto.ErrorCode_ERROR_CODE_CONFIRMATION_TIMEOUT
+}
+
+// newRedemptionError converts a structured server error, falling back to the legacy message for older servers
+func newRedemptionError(pbErr *proto.RedemptionError, fallbackMessage string) *RedemptionError {
+	if pbErr == nil {
+		return &RedemptionError{Code: proto.ErrorCode_ERROR_CODE_UNSPECIFIED, Message: fallbackMessage}
+	}
+	message := pbErr.GetMessage()
+	if message == "" {
+		message = fallbackMessage
+	}
+	return &RedemptionError{
+		Code:         pbErr.GetCode(),
+		Message:      message,
+		RevertReason: pbErr.GetRevertReason(),
+		Retryable:    pbErr.GetRetryable(),
+	}
+}
+
+// SimulationResult is the outcome of a redemption dry run
+type SimulationResult struct {
+	// WouldSucceed is true when the redemption call did not revert
+	WouldSucceed bool
+	// GasEstimate is the estimated gas for the redemption call
+	GasEstimate uint64
+	// Error explains why the redemption would fail
+	Error *RedemptionError
+}
+
+// BatchRedemption is one redemption within a batch. All redemptions in a batch must share a network.
+type BatchRedemption struct {
+	// ReferenceID identifies the redemption in the results, e.g. a subscription renewal ID
+	ReferenceID string
+	Signature   []byte
+	Execution   ExecutionObject
+}
+
+// BatchRedemptionResult is the outcome of one redemption within a batch
+type BatchRedemptionResult struct {
+	ReferenceID     string
+	TransactionHash string
+	// Error is set when the redemption was not executed
+	Error *RedemptionError
+}
+
+// RedemptionStatus is the on-chain state of a redemption transaction
+type RedemptionStatus struct {
+	Status        proto.RedemptionStatus
+	Confirmations uint64
+	BlockNumber   uint64
+	GasUsed       uint64
+}
+
 // DelegationClient handles communication with the gRPC delegation service.
 // It provides methods to redeem delegations and manage the gRPC connection.
 type DelegationClient struct {
@@ -153,14 +235,20 @@ func NewDelegationClient(config DelegationClientConfig) (*DelegationClient, erro
 		return nil, fmt.Errorf("failed to connect to delegation gRPC server: %w", err)
 	}
 
-	// Create a client stub
-	client := proto.NewDelegationServiceClient(conn)
+	return NewDelegationClientWithConn(conn, timeout), nil
+}
 
+// NewDelegationClientWithConn creates a client over an existing gRPC connection, such as one to a FakeServer.
+// The client takes ownership of the connection and closes it in Close.
+func NewDelegationClientWithConn(conn *grpc.ClientConn, rpcTimeout time.Duration) *DelegationClient {
+	if rpcTimeout == 0 {
+		rpcTimeout = 3 * time.Minute
+	}
 	return &DelegationClient{
 		conn:       conn,
-		client:     client,
-		rpcTimeout: timeout,
-	}, nil
+		client:     proto.NewDelegationServiceClient(conn),
+		rpcTimeout: rpcTimeout,
+	}
 }
 
 // RedeemDelegation redeems a delegation using details from the ExecutionObject.
@@ -197,6 +285,148 @@ func (c *DelegationClient) RedeemDelegation(ctx context.Context, signature []byt
 	return c.processRedemptionResponse(res)
 }
 
+// SimulateRedemption dry-runs a redemption without sending a transaction. A redemption that would revert is
+// reported through the result's Error; the returned error is only set when the simulation could not run.
+func (c *DelegationClient) SimulateRedemption(ctx context.Context, signature []byte, executionObject ExecutionObject) (*SimulationResult, error) {
+	if err := c.validateRedemptionInputs(signature, executionObject); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.rpcTimeout)
+	defer cancel()
+
+	res, err := c.client.SimulateRedemption(ctx, &proto.RedeemDelegationRequest{
+		Signature:            signature,
+		MerchantAddress:      executionObject.MerchantAddress,
+		TokenContractAddress: executionObject.TokenContractAddress,
+		TokenAmount:          executionObject.TokenAmount,
+		TokenDecimals:        executionObject.TokenDecimals,
+		ChainId:              executionObject.ChainID,
+		NetworkName:          executionObject.NetworkName,
+	})
+	if err != nil {
+		return nil, c.rpcError("simulate redemption", err)
+	}
+
+	result := &SimulationResult{
+		WouldSucceed: res.GetSuccess(),
+		GasEstimate:  res.GetGasEstimate(),
+	}
+	if !result.WouldSucceed {
+		result.Error = newRedemptionError(res.GetError(), "redemption simulation failed")
+	}
+	return result, nil
+}
+
+// BatchRedeemDelegations redeems several delegations on one network in a single transaction.
+// Redemptions that would fail are left out of the transaction and reported in their result, so the
+// returned error is only set when the batch as a whole could not be processed.
+func (c *DelegationClient) BatchRedeemDelegations(ctx context.Context, redemptions []BatchRedemption) ([]BatchRedemptionResult, error) {
+	if len(redemptions) == 0 {
+		return nil, nil
+	}
+
+	chainID := redemptions[0].Execution.ChainID
+	networkName := redemptions[0].Execution.NetworkName
+	req := &proto.BatchRedeemDelegationsRequest{
+		ChainId:     chainID,
+		NetworkName: networkName,
+		Redemptions: make([]*proto.BatchRedemption, 0, len(redemptions)),
+	}
+	seen := make(map[string]bool, len(redemptions))
+	for _, redemption := range redemptions {
+		if err := c.validateRedemptionInputs(redemption.Signature, redemption.Execution); err != nil {
+			return nil, fmt.Errorf("invalid redemption %s: %w", redemption.ReferenceID, err)
+		}
+		if redemption.Execution.ChainID != chainID || redemption.Execution.NetworkName != networkName {
+			return nil, fmt.Errorf("all redemptions in a batch must be on the same network")
+		}
+		if redemption.ReferenceID == "" || seen[redemption.ReferenceID] {
+			return nil, fmt.Errorf("each redemption in a batch needs a unique reference ID")
+		}
+		seen[redemption.ReferenceID] = true
+
+		req.Redemptions = append(req.Redemptions, &proto.BatchRedemption{
+			ReferenceId:          redemption.ReferenceID,
+			Signature:            redemption.Signature,
+			MerchantAddress:      redemption.Execution.MerchantAddress,
+			TokenContractAddress: redemption.Execution.TokenContractAddress,
+			TokenAmount:          redemption.Execution.TokenAmount,
+			TokenDecimals:        redemption.Execution.TokenDecimals,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.rpcTimeout)
+	defer cancel()
+
+	res, err := c.client.BatchRedeemDelegations(ctx, req)
+	if err != nil {
+		return nil, c.rpcError("batch redeem delegations", err)
+	}
+
+	byReference := make(map[string]*proto.BatchRedemptionResult, len(res.GetResults()))
+	for _, result := range res.GetResults() {
+		byReference[result.GetReferenceId()] = result
+	}
+
+	// Results are returned in request order; a redemption the server did not report on has an unknown outcome
+	results := make([]BatchRedemptionResult, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		result := BatchRedemptionResult{ReferenceID: redemption.ReferenceID}
+		pbResult, ok := byReference[redemption.ReferenceID]
+		switch {
+		case !ok:
+			result.Error = &RedemptionError{
+				Code:    proto.ErrorCode_ERROR_CODE_CONFIRMATION_TIMEOUT,
+				Message: "delegation server returned no result for the redemption",
+			}
+		case pbResult.GetSuccess() && pbResult.GetTransactionHash() != "":
+			result.TransactionHash = pbResult.GetTransactionHash()
+		case pbResult.GetSuccess():
+			result.Error = &RedemptionError{
+				Code:    proto.ErrorCode_ERROR_CODE_CONFIRMATION_TIMEOUT,
+				Message: "delegation server reported success without a transaction hash",
+			}
+		default:
+			result.Error = newRedemptionError(pbResult.GetError(), "delegation redemption failed")
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// GetRedemptionStatus looks up the on-chain status of a redemption transaction
+func (c *DelegationClient) GetRedemptionStatus(ctx context.Context, transactionHash string, chainID uint32, networkName string) (*RedemptionStatus, error) {
+	if transactionHash == "" {
+		return nil, fmt.Errorf("transaction hash is required")
+	}
+	if chainID == 0 || networkName == "" {
+		return nil, fmt.Errorf("chain ID and network name are required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.rpcTimeout)
+	defer cancel()
+
+	res, err := c.client.GetRedemptionStatus(ctx, &proto.GetRedemptionStatusRequest{
+		TransactionHash: transactionHash,
+		ChainId:         chainID,
+		NetworkName:     networkName,
+	})
+	if err != nil {
+		return nil, c.rpcError("get redemption status", err)
+	}
+	if res.GetError() != nil {
+		return nil, fmt.Errorf("failed to get redemption status: %w", newRedemptionError(res.GetError(), "redemption status unavailable"))
+	}
+
+	return &RedemptionStatus{
+		Status:        res.GetStatus(),
+		Confirmations: res.GetConfirmations(),
+		BlockNumber:   res.GetBlockNumber(),
+		GasUsed:       res.GetGasUsed(),
+	}, nil
+}
+
 // validateRedemptionInputs validates the inputs for redemption, including network info
 func (c *DelegationClient) validateRedemptionInputs(signature []byte, executionObject ExecutionObject) error {
 	if len(signature) == 0 {
@@ -233,12 +463,30 @@ func (c *DelegationClient) formatRPCError(err error) error {
 	return fmt.Errorf("failed to redeem delegation: %v", err)
 }
 
+// rpcError formats errors from RPCs other than RedeemDelegation, flagging RPCs the server does not implement
+func (c *DelegationClient) rpcError(operation string, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("failed to %s: %v", operation, err)
+	}
+	if st.Code() == codes.Unimplemented {
+		return fmt.Errorf("failed to %s: %w", operation, ErrUnsupportedByServer)
+	}
+	return fmt.Errorf("failed to %s: %s", operation, st.Message())
+}
+
 // processRedemptionResponse processes the response from the delegation server
 func (c *DelegationClient) processRedemptionResponse(res *proto.RedeemDelegationResponse) (string, error) {
 	// Check if the operation was successful based on the success field
 	if !res.GetSuccess() {
-		errorMsg := c.extractErrorMessage(res)
-		return "", fmt.Errorf("delegation redemption failed: %s", errorMsg)
+		var redemptionErr *RedemptionError
+		if res.GetError() != nil {
+			redemptionErr = newRedemptionError(res.GetError(), "")
+		} else {
+			// Servers older than the structured error only set the error message
+			redemptionErr = newRedemptionError(nil, c.extractErrorMessage(res))
+		}
+		return "", fmt.Errorf("delegation redemption failed: %w", redemptionErr)
 	}
 
 	txHash := res.GetTransactionHash()
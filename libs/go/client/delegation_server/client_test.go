@@ -0,0 +1,232 @@
+package delegation_server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cyphera/cyphera-api/libs/go/proto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newFakeClient(t *testing.T) (*FakeServer, *DelegationClient) {
+	t.Helper()
+
+	server := NewFakeServer()
+	t.Cleanup(server.Close)
+
+	client, err := server.Client()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return server, client
+}
+
+func testExecution() ExecutionObject {
+	return ExecutionObject{
+		MerchantAddress:      "0x1234567890123456789012345678901234567890",
+		TokenContractAddress: "0x0987654321098765432109876543210987654321",
+		TokenAmount:          1000000,
+		TokenDecimals:        6,
+		ChainID:              8453,
+		NetworkName:          "Base",
+	}
+}
+
+func TestDelegationClient_RedeemDelegation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the transaction hash", func(t *testing.T) {
+		server, client := newFakeClient(t)
+
+		txHash, err := client.RedeemDelegation(ctx, []byte(`{"delegate":"0x1"}`), testExecution())
+		require.NoError(t, err)
+		assert.Len(t, txHash, 66)
+
+		requests := server.RedeemRequests()
+		require.Len(t, requests, 1)
+		assert.Equal(t, uint32(8453), requests[0].GetChainId())
+	})
+
+	t.Run("surfaces structured errors", func(t *testing.T) {
+		server, client := newFakeClient(t)
+		server.RedeemHandler = func(*proto.RedeemDelegationRequest) (*proto.RedeemDelegationResponse, error) {
+			return &proto.RedeemDelegationResponse{Error: &proto.RedemptionError{
+				Code:         proto.ErrorCode_ERROR_CODE_EXECUTION_REVERTED,
+				Message:      "redemption reverted",
+				RevertReason: "ERC20PeriodTransferEnforcer:transfer-amount-exceeded",
+			}}, nil
+		}
+
+		_, err := client.RedeemDelegation(ctx, []byte(`{}`), testExecution())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "delegation redemption failed")
+
+		var redemptionErr *RedemptionError
+		require.True(t, errors.As(err, &redemptionErr))
+		assert.Equal(t, proto.ErrorCode_ERROR_CODE_EXECUTION_REVERTED, redemptionErr.Code)
+		assert.Equal(t, "ERC20PeriodTransferEnforcer:transfer-amount-exceeded", redemptionErr.RevertReason)
+		assert.False(t, redemptionErr.OutcomeUnknown())
+	})
+
+	t.Run("falls back to the error message of older servers", func(t *testing.T) {
+		server, client := newFakeClient(t)
+		server.RedeemHandler = func(*proto.RedeemDelegationRequest) (*proto.RedeemDelegationResponse, error) {
+			return &proto.RedeemDelegationResponse{ErrorMessage: "Blockchain error"}, nil
+		}
+
+		_, err := client.RedeemDelegation(ctx, []byte(`{}`), testExecution())
+		require.Error(t, err)
+		assert.Equal(t, "delegation redemption failed: Blockchain error", err.Error())
+
+		var redemptionErr *RedemptionError
+		require.True(t, errors.As(err, &redemptionErr))
+		assert.Equal(t, proto.ErrorCode_ERROR_CODE_UNSPECIFIED, redemptionErr.Code)
+	})
+}
+
+func TestDelegationClient_SimulateRedemption(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports a passing simulation with its gas estimate", func(t *testing.T) {
+		_, client := newFakeClient(t)
+
+		result, err := client.SimulateRedemption(ctx, []byte(`{}`), testExecution())
+		require.NoError(t, err)
+		assert.True(t, result.WouldSucceed)
+		assert.Equal(t, uint64(120000), result.GasEstimate)
+		assert.Nil(t, result.Error)
+	})
+
+	t.Run("reports the revert reason of a failing simulation", func(t *testing.T) {
+		server, client := newFakeClient(t)
+		server.SimulateHandler = func(*proto.RedeemDelegationRequest) (*proto.SimulateRedemptionResponse, error) {
+			return &proto.SimulateRedemptionResponse{Error: &proto.RedemptionError{
+				Code:         proto.ErrorCode_ERROR_CODE_EXECUTION_REVERTED,
+				Message:      "redemption would revert",
+				RevertReason: "ERC20: transfer amount exceeds balance",
+			}}, nil
+		}
+
+		result, err := client.SimulateRedemption(ctx, []byte(`{}`), testExecution())
+		require.NoError(t, err)
+		assert.False(t, result.WouldSucceed)
+		require.NotNil(t, result.Error)
+		assert.Equal(t, "ERC20: transfer amount exceeds balance", result.Error.RevertReason)
+	})
+
+	t.Run("flags servers without the RPC", func(t *testing.T) {
+		server, client := newFakeClient(t)
+		server.SimulateHandler = func(*proto.RedeemDelegationRequest) (*proto.SimulateRedemptionResponse, error) {
+			return nil, status.Error(codes.Unimplemented, "unknown method SimulateRedemption")
+		}
+
+		_, err := client.SimulateRedemption(ctx, []byte(`{}`), testExecution())
+		assert.ErrorIs(t, err, ErrUnsupportedByServer)
+	})
+
+	t.Run("validates inputs before calling the server", func(t *testing.T) {
+		server, client := newFakeClient(t)
+		execution := testExecution()
+		execution.ChainID = 0
+
+		_, err := client.SimulateRedemption(ctx, []byte(`{}`), execution)
+		assert.Error(t, err)
+		assert.Empty(t, server.SimulateRequests())
+	})
+}
+
+func TestDelegationClient_BatchRedeemDelegations(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns results in request order", func(t *testing.T) {
+		server, client := newFakeClient(t)
+		server.BatchRedeemHandler = func(req *proto.BatchRedeemDelegationsRequest) (*proto.BatchRedeemDelegationsResponse, error) {
+			assert.Equal(t, uint32(8453), req.GetChainId())
+			assert.Equal(t, "Base", req.GetNetworkName())
+			return &proto.BatchRedeemDelegationsResponse{
+				TransactionHash: "0xbatch",
+				Results: []*proto.BatchRedemptionResult{
+					{ReferenceId: "b", Error: &proto.RedemptionError{Code: proto.ErrorCode_ERROR_CODE_EXECUTION_REVERTED, Message: "reverted"}},
+					{ReferenceId: "a", Success: true, TransactionHash: "0xbatch"},
+				},
+			}, nil
+		}
+
+		results, err := client.BatchRedeemDelegations(ctx, []BatchRedemption{
+			{ReferenceID: "a", Signature: []byte(`{}`), Execution: testExecution()},
+			{ReferenceID: "b", Signature: []byte(`{}`), Execution: testExecution()},
+			{ReferenceID: "c", Signature: []byte(`{}`), Execution: testExecution()},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		assert.Equal(t, "0xbatch", results[0].TransactionHash)
+		assert.Nil(t, results[0].Error)
+
+		require.NotNil(t, results[1].Error)
+		assert.Equal(t, proto.ErrorCode_ERROR_CODE_EXECUTION_REVERTED, results[1].Error.Code)
+
+		// A redemption the server did not report on may still have been executed
+		require.NotNil(t, results[2].Error)
+		assert.True(t, results[2].Error.OutcomeUnknown())
+	})
+
+	t.Run("rejects batches spanning networks", func(t *testing.T) {
+		server, client := newFakeClient(t)
+		other := testExecution()
+		other.ChainID = 1
+		other.NetworkName = "Ethereum Mainnet"
+
+		_, err := client.BatchRedeemDelegations(ctx, []BatchRedemption{
+			{ReferenceID: "a", Signature: []byte(`{}`), Execution: testExecution()},
+			{ReferenceID: "b", Signature: []byte(`{}`), Execution: other},
+		})
+		assert.Error(t, err)
+		assert.Empty(t, server.BatchRequests())
+	})
+
+	t.Run("rejects duplicate reference IDs", func(t *testing.T) {
+		_, client := newFakeClient(t)
+
+		_, err := client.BatchRedeemDelegations(ctx, []BatchRedemption{
+			{ReferenceID: "a", Signature: []byte(`{}`), Execution: testExecution()},
+			{ReferenceID: "a", Signature: []byte(`{}`), Execution: testExecution()},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestDelegationClient_GetRedemptionStatus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the transaction status", func(t *testing.T) {
+		server, client := newFakeClient(t)
+		server.StatusHandler = func(req *proto.GetRedemptionStatusRequest) (*proto.GetRedemptionStatusResponse, error) {
+			assert.Equal(t, "0xabc", req.GetTransactionHash())
+			return &proto.GetRedemptionStatusResponse{Status: proto.RedemptionStatus_REDEMPTION_STATUS_PENDING}, nil
+		}
+
+		redemptionStatus, err := client.GetRedemptionStatus(ctx, "0xabc", 8453, "Base")
+		require.NoError(t, err)
+		assert.Equal(t, proto.RedemptionStatus_REDEMPTION_STATUS_PENDING, redemptionStatus.Status)
+	})
+
+	t.Run("returns structured lookup errors", func(t *testing.T) {
+		server, client := newFakeClient(t)
+		server.StatusHandler = func(*proto.GetRedemptionStatusRequest) (*proto.GetRedemptionStatusResponse, error) {
+			return &proto.GetRedemptionStatusResponse{Error: &proto.RedemptionError{
+				Code:    proto.ErrorCode_ERROR_CODE_TRANSACTION_NOT_FOUND,
+				Message: "transaction not found",
+			}}, nil
+		}
+
+		_, err := client.GetRedemptionStatus(ctx, "0xabc", 8453, "Base")
+		var redemptionErr *RedemptionError
+		require.True(t, errors.As(err, &redemptionErr))
+		assert.Equal(t, proto.ErrorCode_ERROR_CODE_TRANSACTION_NOT_FOUND, redemptionErr.Code)
+	})
+}
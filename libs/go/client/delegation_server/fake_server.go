@@ -0,0 +1,189 @@
+package delegation_server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// FakeServer is an in-memory delegation server for Go tests. It serves the delegation gRPC contract over
+// a bufconn listener, answers with the handlers set on it and records the requests it receives.
+//
+// Handlers must be set before the client makes calls. A nil handler answers like a healthy server:
+// redemptions succeed with a generated transaction hash, simulations pass and transactions are mined.
+type FakeServer struct {
+	proto.UnimplementedDelegationServiceServer
+
+	RedeemHandler      func(*proto.RedeemDelegationRequest) (*proto.RedeemDelegationResponse, error)
+	SimulateHandler    func(*proto.RedeemDelegationRequest) (*proto.SimulateRedemptionResponse, error)
+	BatchRedeemHandler func(*proto.BatchRedeemDelegationsRequest) (*proto.BatchRedeemDelegationsResponse, error)
+	StatusHandler      func(*proto.GetRedemptionStatusRequest) (*proto.GetRedemptionStatusResponse, error)
+
+	listener *bufconn.Listener
+	server   *grpc.Server
+
+	mu               sync.Mutex
+	txCount          int
+	healthChecks     int
+	redeemRequests   []*proto.RedeemDelegationRequest
+	simulateRequests []*proto.RedeemDelegationRequest
+	batchRequests    []*proto.BatchRedeemDelegationsRequest
+	statusRequests   []*proto.GetRedemptionStatusRequest
+}
+
+// NewFakeServer starts a fake delegation server; stop it with Close
+func NewFakeServer() *FakeServer {
+	f := &FakeServer{
+		listener: bufconn.Listen(1024 * 1024),
+		server:   grpc.NewServer(),
+	}
+	proto.RegisterDelegationServiceServer(f.server, f)
+	go func() {
+		_ = f.server.Serve(f.listener)
+	}()
+	return f
+}
+
+// Client returns a DelegationClient connected to the fake server
+func (f *FakeServer) Client() (*DelegationClient, error) {
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return f.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to fake delegation server: %w", err)
+	}
+	return NewDelegationClientWithConn(conn, 10*time.Second), nil
+}
+
+// Close stops the fake server
+func (f *FakeServer) Close() {
+	f.server.Stop()
+}
+
+// RedeemRequests returns the redemptions received, leaving out the empty requests sent by health checks
+func (f *FakeServer) RedeemRequests() []*proto.RedeemDelegationRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*proto.RedeemDelegationRequest(nil), f.redeemRequests...)
+}
+
+// HealthChecks returns how many health checks were received
+func (f *FakeServer) HealthChecks() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.healthChecks
+}
+
+// SimulateRequests returns the simulations received
+func (f *FakeServer) SimulateRequests() []*proto.RedeemDelegationRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*proto.RedeemDelegationRequest(nil), f.simulateRequests...)
+}
+
+// BatchRequests returns the batch redemptions received
+func (f *FakeServer) BatchRequests() []*proto.BatchRedeemDelegationsRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*proto.BatchRedeemDelegationsRequest(nil), f.batchRequests...)
+}
+
+// StatusRequests returns the status lookups received
+func (f *FakeServer) StatusRequests() []*proto.GetRedemptionStatusRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*proto.GetRedemptionStatusRequest(nil), f.statusRequests...)
+}
+
+// RedeemDelegation implements proto.DelegationServiceServer
+func (f *FakeServer) RedeemDelegation(_ context.Context, req *proto.RedeemDelegationRequest) (*proto.RedeemDelegationResponse, error) {
+	// Health checks send an empty request that a real server rejects
+	if len(req.GetSignature()) == 0 {
+		f.mu.Lock()
+		f.healthChecks++
+		f.mu.Unlock()
+		return &proto.RedeemDelegationResponse{Error: &proto.RedemptionError{
+			Code:    proto.ErrorCode_ERROR_CODE_INVALID_REQUEST,
+			Message: "signature is required",
+		}}, nil
+	}
+
+	f.mu.Lock()
+	f.redeemRequests = append(f.redeemRequests, req)
+	f.mu.Unlock()
+
+	if f.RedeemHandler != nil {
+		return f.RedeemHandler(req)
+	}
+	return &proto.RedeemDelegationResponse{Success: true, TransactionHash: f.nextTransactionHash()}, nil
+}
+
+// SimulateRedemption implements proto.DelegationServiceServer
+func (f *FakeServer) SimulateRedemption(_ context.Context, req *proto.RedeemDelegationRequest) (*proto.SimulateRedemptionResponse, error) {
+	f.mu.Lock()
+	f.simulateRequests = append(f.simulateRequests, req)
+	f.mu.Unlock()
+
+	if f.SimulateHandler != nil {
+		return f.SimulateHandler(req)
+	}
+	return &proto.SimulateRedemptionResponse{Success: true, GasEstimate: 120000}, nil
+}
+
+// BatchRedeemDelegations implements proto.DelegationServiceServer
+func (f *FakeServer) BatchRedeemDelegations(_ context.Context, req *proto.BatchRedeemDelegationsRequest) (*proto.BatchRedeemDelegationsResponse, error) {
+	f.mu.Lock()
+	f.batchRequests = append(f.batchRequests, req)
+	f.mu.Unlock()
+
+	if f.BatchRedeemHandler != nil {
+		return f.BatchRedeemHandler(req)
+	}
+
+	txHash := f.nextTransactionHash()
+	res := &proto.BatchRedeemDelegationsResponse{TransactionHash: txHash}
+	for _, redemption := range req.GetRedemptions() {
+		res.Results = append(res.Results, &proto.BatchRedemptionResult{
+			ReferenceId:     redemption.GetReferenceId(),
+			Success:         true,
+			TransactionHash: txHash,
+		})
+	}
+	return res, nil
+}
+
+// GetRedemptionStatus implements proto.DelegationServiceServer
+func (f *FakeServer) GetRedemptionStatus(_ context.Context, req *proto.GetRedemptionStatusRequest) (*proto.GetRedemptionStatusResponse, error) {
+	f.mu.Lock()
+	f.statusRequests = append(f.statusRequests, req)
+	f.mu.Unlock()
+
+	if f.StatusHandler != nil {
+		return f.StatusHandler(req)
+	}
+	return &proto.GetRedemptionStatusResponse{
+		Status:        proto.RedemptionStatus_REDEMPTION_STATUS_MINED,
+		Confirmations: 12,
+		BlockNumber:   1000,
+		GasUsed:       90000,
+	}, nil
+}
+
+// nextTransactionHash returns a distinct, well-formed transaction hash
+func (f *FakeServer) nextTransactionHash() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.txCount++
+	return fmt.Sprintf("0x%064x", f.txCount)
+}
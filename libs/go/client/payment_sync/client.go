@@ -2,14 +2,11 @@ package payment_sync
 
 import (
 	"context"
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
+	"time"
 
 	"github.com/cyphera/cyphera-api/libs/go/db"
 
@@ -18,13 +15,20 @@ import (
 	"go.uber.org/zap"
 )
 
+// DefaultWebhookSecretGracePeriod is how long a replaced webhook secret keeps verifying deliveries
+const DefaultWebhookSecretGracePeriod = 24 * time.Hour
+
 // PaymentSyncClient manages workspace-specific payment provider configurations
 // and provides the top-level interface for payment synchronization operations
 type PaymentSyncClient struct {
-	db            *db.Queries
-	logger        *zap.Logger
+	db     db.Querier
+	logger *zap.Logger
+	// encryptionKey is the legacy static key, still used to read rows written before envelope encryption
 	encryptionKey []byte
-	providers     map[string]PaymentSyncService // Registry of available payment providers
+	// keys wraps the per-value data keys of envelope-encrypted credentials
+	keys      KeyEncryptionKeyProvider
+	dataKeys  dataKeyCache
+	providers map[string]func() PaymentSyncService // Registry of available payment providers
 }
 
 // PaymentProviderConfig represents the configuration for a payment provider
@@ -53,8 +57,16 @@ type WorkspacePaymentConfig struct {
 	UpdatedAt          int64                  `json:"updated_at"`
 }
 
-// NewPaymentSyncClient creates a new workspace payment client
+// NewPaymentSyncClient creates a new workspace payment client.
+// Credentials are envelope-encrypted with the static key acting as local key version "v1".
 func NewPaymentSyncClient(dbQueries *db.Queries, logger *zap.Logger, encryptionKey string) *PaymentSyncClient {
+	return NewPaymentSyncClientWithKeyProvider(dbQueries, logger, encryptionKey, nil)
+}
+
+// NewPaymentSyncClientWithKeyProvider creates a workspace payment client that wraps data keys with
+// the given key provider (see LoadKeyProvider). encryptionKey is the legacy static key, kept to
+// read rows that have not been re-encrypted yet.
+func NewPaymentSyncClientWithKeyProvider(dbQueries *db.Queries, logger *zap.Logger, encryptionKey string, keys KeyEncryptionKeyProvider) *PaymentSyncClient {
 	// Convert hex encryption key to bytes
 	// The encryption key is expected to be hex-encoded (64 hex characters for 32 bytes)
 	key, err := hex.DecodeString(encryptionKey)
@@ -65,24 +77,50 @@ func NewPaymentSyncClient(dbQueries *db.Queries, logger *zap.Logger, encryptionK
 		logger.Fatal("Encryption key must be 32 bytes for AES-256", zap.Int("length", len(key)))
 	}
 
+	if keys == nil {
+		keys, err = legacyKeyring(encryptionKey)
+		if err != nil {
+			logger.Fatal("Failed to create keyring from encryption key", zap.Error(err))
+		}
+	}
+
 	return &PaymentSyncClient{
 		db:            dbQueries,
 		logger:        logger,
 		encryptionKey: key,
-		providers:     make(map[string]PaymentSyncService),
+		keys:          keys,
+		providers:     make(map[string]func() PaymentSyncService),
 	}
 }
 
-// RegisterProvider registers a payment provider service
-func (c *PaymentSyncClient) RegisterProvider(providerName string, service PaymentSyncService) {
-	c.providers[providerName] = service
+// RegisterProvider registers a payment provider. newService builds an unconfigured service; each call to
+// GetProviderService configures a fresh one, so concurrent requests for different workspaces never share
+// credentials or webhook secrets.
+func (c *PaymentSyncClient) RegisterProvider(providerName string, newService func() PaymentSyncService) {
+	c.providers[providerName] = newService
 	c.logger.Info("Registered payment provider", zap.String("provider", providerName))
 }
 
 // GetProviderService returns a configured payment provider service for a workspace
 func (c *PaymentSyncClient) GetProviderService(ctx context.Context, workspaceID, providerName string) (PaymentSyncService, error) {
+	return c.configureProviderService(ctx, workspaceID, providerName, nil)
+}
+
+// GetProviderServiceForWebhook returns a provider service configured to parse a webhook that has
+// already been verified. The secret that matched is used instead of the current one, so deliveries
+// signed with a secret that is being rotated out are still accepted, and the verifier's tolerance
+// is passed through so the provider does not apply a stricter window of its own.
+func (c *PaymentSyncClient) GetProviderServiceForWebhook(ctx context.Context, workspaceID, providerName, webhookSecret string, tolerance time.Duration) (PaymentSyncService, error) {
+	return c.configureProviderService(ctx, workspaceID, providerName, map[string]string{
+		"webhook_secret":    webhookSecret,
+		"webhook_tolerance": tolerance.String(),
+	})
+}
+
+// configureProviderService configures a registered provider with workspace settings plus any overrides
+func (c *PaymentSyncClient) configureProviderService(ctx context.Context, workspaceID, providerName string, overrides map[string]string) (PaymentSyncService, error) {
 	// Get the provider service
-	service, exists := c.providers[providerName]
+	newService, exists := c.providers[providerName]
 	if !exists {
 		return nil, fmt.Errorf("provider %s not registered", providerName)
 	}
@@ -106,7 +144,13 @@ func (c *PaymentSyncClient) GetProviderService(ctx context.Context, workspaceID,
 	if config.Configuration.BaseURL != "" {
 		configMap["base_url"] = config.Configuration.BaseURL
 	}
+	for key, value := range overrides {
+		if value != "" {
+			configMap[key] = value
+		}
+	}
 
+	service := newService()
 	err = service.Configure(ctx, configMap)
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure provider service: %w", err)
@@ -115,6 +159,68 @@ func (c *PaymentSyncClient) GetProviderService(ctx context.Context, workspaceID,
 	return service, nil
 }
 
+// GetWebhookSecrets returns the current webhook secret for a workspace and provider, followed by
+// any previous secrets that are still inside their rotation grace period
+func (c *PaymentSyncClient) GetWebhookSecrets(ctx context.Context, workspaceID, providerName string) ([]string, error) {
+	config, err := c.GetConfiguration(ctx, workspaceID, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]string, 0, 2)
+	if config.Configuration.WebhookSecret != "" {
+		secrets = append(secrets, config.Configuration.WebhookSecret)
+	}
+
+	wsID, err := uuid.Parse(workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace ID: %w", err)
+	}
+
+	previousSecrets, err := c.db.ListActiveWorkspaceWebhookSecrets(ctx, db.ListActiveWorkspaceWebhookSecretsParams{
+		WorkspaceID:  wsID,
+		ProviderName: providerName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list previous webhook secrets: %w", err)
+	}
+
+	for _, previous := range previousSecrets {
+		if secret := c.decryptWebhookSecret(previous.WebhookSecretKey); secret != "" && secret != config.Configuration.WebhookSecret {
+			secrets = append(secrets, secret)
+		}
+	}
+
+	return secrets, nil
+}
+
+// RetireWebhookSecret keeps a replaced webhook secret valid for the grace period so deliveries
+// signed before the provider switched secrets are still accepted
+func (c *PaymentSyncClient) RetireWebhookSecret(ctx context.Context, workspaceID, providerName, webhookSecret string, gracePeriod time.Duration) error {
+	wsID, err := uuid.Parse(workspaceID)
+	if err != nil {
+		return fmt.Errorf("invalid workspace ID: %w", err)
+	}
+
+	encryptedSecret := c.encryptWebhookSecret(webhookSecret)
+	if encryptedSecret == "" {
+		return fmt.Errorf("failed to encrypt webhook secret")
+	}
+
+	_, err = c.db.CreateWorkspaceWebhookSecret(ctx, db.CreateWorkspaceWebhookSecretParams{
+		WorkspaceID:          wsID,
+		ProviderName:         providerName,
+		WebhookSecretKey:     encryptedSecret,
+		ExpiresAt:            pgtype.Timestamptz{Time: time.Now().Add(gracePeriod), Valid: true},
+		EncryptionKeyVersion: pgtype.Text{String: c.keys.CurrentKeyVersion(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store previous webhook secret: %w", err)
+	}
+
+	return nil
+}
+
 // CreateConfiguration creates a new payment provider configuration for a workspace
 func (c *PaymentSyncClient) CreateConfiguration(ctx context.Context, config WorkspacePaymentConfig) (*WorkspacePaymentConfig, error) {
 	wsID, err := uuid.Parse(config.WorkspaceID)
@@ -144,15 +250,16 @@ func (c *PaymentSyncClient) CreateConfiguration(ctx context.Context, config Work
 
 	// Create in database
 	dbConfig, err := c.db.CreateWorkspacePaymentConfiguration(ctx, db.CreateWorkspacePaymentConfigurationParams{
-		WorkspaceID:        wsID,
-		ProviderName:       config.ProviderName,
-		IsActive:           config.IsActive,
-		IsTestMode:         config.IsTestMode,
-		Configuration:      encryptedConfig,
-		WebhookEndpointUrl: pgtype.Text{String: config.WebhookEndpointURL, Valid: config.WebhookEndpointURL != ""},
-		WebhookSecretKey:   pgtype.Text{String: c.encryptWebhookSecret(config.Configuration.WebhookSecret), Valid: config.Configuration.WebhookSecret != ""},
-		ConnectedAccountID: pgtype.Text{String: config.ConnectedAccountID, Valid: config.ConnectedAccountID != ""},
-		Metadata:           metadata,
+		WorkspaceID:          wsID,
+		ProviderName:         config.ProviderName,
+		IsActive:             config.IsActive,
+		IsTestMode:           config.IsTestMode,
+		Configuration:        encryptedConfig,
+		WebhookEndpointUrl:   pgtype.Text{String: config.WebhookEndpointURL, Valid: config.WebhookEndpointURL != ""},
+		WebhookSecretKey:     pgtype.Text{String: c.encryptWebhookSecret(config.Configuration.WebhookSecret), Valid: config.Configuration.WebhookSecret != ""},
+		ConnectedAccountID:   pgtype.Text{String: config.ConnectedAccountID, Valid: config.ConnectedAccountID != ""},
+		Metadata:             metadata,
+		EncryptionKeyVersion: pgtype.Text{String: c.keys.CurrentKeyVersion(), Valid: true},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create workspace payment configuration: %w", err)
@@ -241,6 +348,16 @@ func (c *PaymentSyncClient) UpdateConfiguration(ctx context.Context, workspaceID
 		return nil, fmt.Errorf("invalid configuration ID: %w", err)
 	}
 
+	// Keep the old webhook secret valid for a grace period when it is being replaced
+	if updates.Configuration.WebhookSecret != "" {
+		existing, err := c.GetConfigurationByID(ctx, workspaceID, configID)
+		if err == nil && existing.Configuration.WebhookSecret != "" && existing.Configuration.WebhookSecret != updates.Configuration.WebhookSecret {
+			if err := c.RetireWebhookSecret(ctx, workspaceID, existing.ProviderName, existing.Configuration.WebhookSecret, DefaultWebhookSecretGracePeriod); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Encrypt the configuration
 	encryptedConfig, err := c.encryptConfiguration(updates.Configuration)
 	if err != nil {
@@ -258,15 +375,16 @@ func (c *PaymentSyncClient) UpdateConfiguration(ctx context.Context, workspaceID
 
 	// Update in database
 	dbConfig, err := c.db.UpdateWorkspacePaymentConfiguration(ctx, db.UpdateWorkspacePaymentConfigurationParams{
-		ID:                 cfgID,
-		WorkspaceID:        wsID,
-		IsActive:           updates.IsActive,
-		IsTestMode:         updates.IsTestMode,
-		Configuration:      encryptedConfig,
-		WebhookEndpointUrl: pgtype.Text{String: updates.WebhookEndpointURL, Valid: updates.WebhookEndpointURL != ""},
-		WebhookSecretKey:   pgtype.Text{String: c.encryptWebhookSecret(updates.Configuration.WebhookSecret), Valid: updates.Configuration.WebhookSecret != ""},
-		ConnectedAccountID: pgtype.Text{String: updates.ConnectedAccountID, Valid: updates.ConnectedAccountID != ""},
-		Metadata:           metadata,
+		ID:                   cfgID,
+		WorkspaceID:          wsID,
+		IsActive:             updates.IsActive,
+		IsTestMode:           updates.IsTestMode,
+		Configuration:        encryptedConfig,
+		WebhookEndpointUrl:   pgtype.Text{String: updates.WebhookEndpointURL, Valid: updates.WebhookEndpointURL != ""},
+		WebhookSecretKey:     pgtype.Text{String: c.encryptWebhookSecret(updates.Configuration.WebhookSecret), Valid: updates.Configuration.WebhookSecret != ""},
+		ConnectedAccountID:   pgtype.Text{String: updates.ConnectedAccountID, Valid: updates.ConnectedAccountID != ""},
+		Metadata:             metadata,
+		EncryptionKeyVersion: pgtype.Text{String: c.keys.CurrentKeyVersion(), Valid: true},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to update configuration: %w", err)
@@ -395,6 +513,7 @@ func (c *PaymentSyncClient) mapDBConfigToService(dbConfig db.WorkspacePaymentCon
 	return result
 }
 
+// encryptConfiguration envelope-encrypts a provider configuration under the current key version
 func (c *PaymentSyncClient) encryptConfiguration(config PaymentProviderConfig) ([]byte, error) {
 	// Convert config to JSON
 	plaintext, err := json.Marshal(config)
@@ -402,56 +521,31 @@ func (c *PaymentSyncClient) encryptConfiguration(config PaymentProviderConfig) (
 		return nil, fmt.Errorf("failed to marshal configuration: %w", err)
 	}
 
-	// Create AES cipher
-	block, err := aes.NewCipher(c.encryptionKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create cipher: %w", err)
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyOperationTimeout)
+	defer cancel()
 
-	// Create GCM mode
-	gcm, err := cipher.NewGCM(block)
+	envelope, err := c.sealEnvelope(ctx, plaintext)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create GCM: %w", err)
-	}
-
-	// Create nonce
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return nil, fmt.Errorf("failed to create nonce: %w", err)
+		return nil, fmt.Errorf("failed to encrypt configuration: %w", err)
 	}
-
-	// Encrypt
-	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
-	return ciphertext, nil
+	return envelope, nil
 }
 
+// decryptConfiguration decrypts a configuration stored as an envelope or with the legacy static key
 func (c *PaymentSyncClient) decryptConfiguration(ciphertext []byte) (PaymentProviderConfig, error) {
 	var config PaymentProviderConfig
 
-	// Create AES cipher
-	block, err := aes.NewCipher(c.encryptionKey)
-	if err != nil {
-		return config, fmt.Errorf("failed to create cipher: %w", err)
+	var plaintext []byte
+	var err error
+	if envelope, ok := parseEnvelope(ciphertext); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), keyOperationTimeout)
+		defer cancel()
+		plaintext, err = c.openEnvelope(ctx, envelope)
+	} else {
+		plaintext, err = openAESGCM(c.encryptionKey, ciphertext)
 	}
-
-	// Create GCM mode
-	gcm, err := cipher.NewGCM(block)
 	if err != nil {
-		return config, fmt.Errorf("failed to create GCM: %w", err)
-	}
-
-	if len(ciphertext) < gcm.NonceSize() {
-		return config, fmt.Errorf("ciphertext too short")
-	}
-
-	// Extract nonce and ciphertext
-	nonce := ciphertext[:gcm.NonceSize()]
-	ciphertext = ciphertext[gcm.NonceSize():]
-
-	// Decrypt
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return config, fmt.Errorf("failed to decrypt: %w", err)
+		return config, err
 	}
 
 	// Unmarshal JSON
@@ -462,76 +556,49 @@ func (c *PaymentSyncClient) decryptConfiguration(ciphertext []byte) (PaymentProv
 	return config, nil
 }
 
-// encryptWebhookSecret encrypts a webhook secret using the same encryption as configuration
+// encryptWebhookSecret envelope-encrypts a webhook secret under the current key version
 func (c *PaymentSyncClient) encryptWebhookSecret(webhookSecret string) string {
 	if webhookSecret == "" {
 		return ""
 	}
 
-	// Create AES cipher
-	block, err := aes.NewCipher(c.encryptionKey)
-	if err != nil {
-		c.logger.Error("Failed to create cipher for webhook secret encryption", zap.Error(err))
-		return ""
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyOperationTimeout)
+	defer cancel()
 
-	// Create GCM mode
-	gcm, err := cipher.NewGCM(block)
+	envelope, err := c.sealEnvelope(ctx, []byte(webhookSecret))
 	if err != nil {
-		c.logger.Error("Failed to create GCM for webhook secret encryption", zap.Error(err))
-		return ""
-	}
-
-	// Create nonce
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		c.logger.Error("Failed to create nonce for webhook secret encryption", zap.Error(err))
+		c.logger.Error("Failed to encrypt webhook secret", zap.Error(err))
 		return ""
 	}
-
-	// Encrypt
-	ciphertext := gcm.Seal(nonce, nonce, []byte(webhookSecret), nil)
-	return base64.StdEncoding.EncodeToString(ciphertext)
+	return string(envelope)
 }
 
-// decryptWebhookSecret decrypts a webhook secret using the same decryption as configuration
+// decryptWebhookSecret decrypts a webhook secret stored as an envelope or as legacy base64 ciphertext
 func (c *PaymentSyncClient) decryptWebhookSecret(encryptedSecret string) string {
 	if encryptedSecret == "" {
 		return ""
 	}
 
-	// Decode base64
-	ciphertext, err := base64.StdEncoding.DecodeString(encryptedSecret)
-	if err != nil {
-		c.logger.Error("Failed to decode webhook secret", zap.Error(err))
-		return ""
-	}
+	if envelope, ok := parseEnvelope([]byte(encryptedSecret)); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), keyOperationTimeout)
+		defer cancel()
 
-	// Create AES cipher
-	block, err := aes.NewCipher(c.encryptionKey)
-	if err != nil {
-		c.logger.Error("Failed to create cipher for webhook secret decryption", zap.Error(err))
-		return ""
+		plaintext, err := c.openEnvelope(ctx, envelope)
+		if err != nil {
+			c.logger.Error("Failed to decrypt webhook secret", zap.Error(err))
+			return ""
+		}
+		return string(plaintext)
 	}
 
-	// Create GCM mode
-	gcm, err := cipher.NewGCM(block)
+	// Decode base64
+	ciphertext, err := base64.StdEncoding.DecodeString(encryptedSecret)
 	if err != nil {
-		c.logger.Error("Failed to create GCM for webhook secret decryption", zap.Error(err))
-		return ""
-	}
-
-	if len(ciphertext) < gcm.NonceSize() {
-		c.logger.Error("Webhook secret ciphertext too short")
+		c.logger.Error("Failed to decode webhook secret", zap.Error(err))
 		return ""
 	}
 
-	// Extract nonce and ciphertext
-	nonce := ciphertext[:gcm.NonceSize()]
-	ciphertext = ciphertext[gcm.NonceSize():]
-
-	// Decrypt
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := openAESGCM(c.encryptionKey, ciphertext)
 	if err != nil {
 		c.logger.Error("Failed to decrypt webhook secret", zap.Error(err))
 		return ""
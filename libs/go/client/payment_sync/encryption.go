@@ -0,0 +1,430 @@
+package payment_sync
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	awsclient "github.com/cyphera/cyphera-api/libs/go/client/aws"
+)
+
+// Key version prefixes identify which provider wrapped a data key
+const (
+	localKeyVersionPrefix = "local:"
+	kmsKeyVersionPrefix   = "kms:"
+)
+
+// legacyKeyVersion labels rows encrypted directly with the static key, before envelope encryption
+const legacyKeyVersion = "legacy"
+
+// dataKeyCacheTTL is how long unwrapped data keys are reused, so reads do not call KMS every time
+const dataKeyCacheTTL = 10 * time.Minute
+
+// keyOperationTimeout bounds key wrapping calls made from code paths without a request context
+const keyOperationTimeout = 15 * time.Second
+
+// KeyEncryptionKeyProvider wraps and unwraps the data keys used to encrypt stored credentials.
+// Each row records the key version its data keys were wrapped with, so older versions must stay
+// available until every row has been re-encrypted under the current one.
+type KeyEncryptionKeyProvider interface {
+	// CurrentKeyVersion returns the version new data keys are wrapped with
+	CurrentKeyVersion() string
+	// HasKeyVersion reports whether data keys wrapped under keyVersion can be unwrapped
+	HasKeyVersion(keyVersion string) bool
+	// WrapDataKey encrypts a data key under the current key version
+	WrapDataKey(ctx context.Context, dataKey []byte) (wrappedKey []byte, keyVersion string, err error)
+	// UnwrapDataKey decrypts a data key that was wrapped under keyVersion
+	UnwrapDataKey(ctx context.Context, wrappedKey []byte, keyVersion string) ([]byte, error)
+}
+
+// LocalKeyring holds AES-256 key-encryption keys in process memory, keyed by version
+type LocalKeyring struct {
+	current string
+	keys    map[string][]byte
+}
+
+// NewLocalKeyring creates a keyring that wraps new data keys with currentVersion
+func NewLocalKeyring(currentVersion string, keys map[string][]byte) (*LocalKeyring, error) {
+	if _, ok := keys[currentVersion]; !ok {
+		return nil, fmt.Errorf("current key version %s not in keyring", currentVersion)
+	}
+	for version, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key version %s must be 32 bytes for AES-256, got %d", version, len(key))
+		}
+	}
+
+	return &LocalKeyring{current: currentVersion, keys: keys}, nil
+}
+
+// ParseLocalKeyring parses a keyring spec of comma-separated "version:hexkey" pairs.
+// The first entry is the current version; the rest are kept for unwrapping older rows.
+func ParseLocalKeyring(spec string) (*LocalKeyring, error) {
+	keys := make(map[string][]byte)
+	current := ""
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		version, hexKey, ok := strings.Cut(entry, ":")
+		if !ok || version == "" {
+			return nil, fmt.Errorf("invalid keyring entry, expected version:hexkey")
+		}
+		key, err := hex.DecodeString(hexKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key for version %s - expected hex: %w", version, err)
+		}
+		if _, exists := keys[version]; exists {
+			return nil, fmt.Errorf("duplicate key version %s in keyring", version)
+		}
+
+		keys[version] = key
+		if current == "" {
+			current = version
+		}
+	}
+
+	if current == "" {
+		return nil, fmt.Errorf("keyring is empty")
+	}
+
+	return NewLocalKeyring(current, keys)
+}
+
+// CurrentKeyVersion returns the current local key version
+func (k *LocalKeyring) CurrentKeyVersion() string {
+	return localKeyVersionPrefix + k.current
+}
+
+// HasKeyVersion reports whether the keyring holds the given version
+func (k *LocalKeyring) HasKeyVersion(keyVersion string) bool {
+	version, ok := strings.CutPrefix(keyVersion, localKeyVersionPrefix)
+	if !ok {
+		return false
+	}
+	_, exists := k.keys[version]
+	return exists
+}
+
+// WrapDataKey encrypts a data key with the current local key
+func (k *LocalKeyring) WrapDataKey(_ context.Context, dataKey []byte) ([]byte, string, error) {
+	wrapped, err := sealAESGCM(k.keys[k.current], dataKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return wrapped, k.CurrentKeyVersion(), nil
+}
+
+// UnwrapDataKey decrypts a data key with the local key of the given version
+func (k *LocalKeyring) UnwrapDataKey(_ context.Context, wrappedKey []byte, keyVersion string) ([]byte, error) {
+	if !k.HasKeyVersion(keyVersion) {
+		return nil, fmt.Errorf("key version %s not in local keyring", keyVersion)
+	}
+	key := k.keys[strings.TrimPrefix(keyVersion, localKeyVersionPrefix)]
+
+	dataKey, err := openAESGCM(key, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// KMSAPI is the subset of KMS used to wrap data keys, implemented by awsclient.KMSClient
+type KMSAPI interface {
+	Encrypt(ctx context.Context, keyID string, plaintext []byte, encryptionContext map[string]string) ([]byte, error)
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte, encryptionContext map[string]string) ([]byte, error)
+}
+
+// kmsEncryptionContext binds wrapped data keys to this use, so they cannot be decrypted for another purpose
+var kmsEncryptionContext = map[string]string{"purpose": "payment_sync_credentials"}
+
+// KMSKeyProvider wraps data keys with an AWS KMS key.
+// Rotating to a new KMS key only requires changing the key ID; rows wrapped with the old key
+// remain readable as long as the caller may still decrypt with it.
+type KMSKeyProvider struct {
+	client KMSAPI
+	keyID  string
+}
+
+// NewKMSKeyProvider creates a KMS-backed provider for the given key ID or ARN
+func NewKMSKeyProvider(client KMSAPI, keyID string) *KMSKeyProvider {
+	return &KMSKeyProvider{client: client, keyID: keyID}
+}
+
+// CurrentKeyVersion returns the version string for the configured KMS key
+func (p *KMSKeyProvider) CurrentKeyVersion() string {
+	return kmsKeyVersionPrefix + p.keyID
+}
+
+// HasKeyVersion reports whether the version was wrapped by KMS
+func (p *KMSKeyProvider) HasKeyVersion(keyVersion string) bool {
+	return strings.HasPrefix(keyVersion, kmsKeyVersionPrefix)
+}
+
+// WrapDataKey encrypts a data key with the configured KMS key
+func (p *KMSKeyProvider) WrapDataKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	wrapped, err := p.client.Encrypt(ctx, p.keyID, dataKey, kmsEncryptionContext)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to wrap data key with KMS: %w", err)
+	}
+	return wrapped, p.CurrentKeyVersion(), nil
+}
+
+// UnwrapDataKey decrypts a data key with the KMS key recorded in its version
+func (p *KMSKeyProvider) UnwrapDataKey(ctx context.Context, wrappedKey []byte, keyVersion string) ([]byte, error) {
+	keyID, ok := strings.CutPrefix(keyVersion, kmsKeyVersionPrefix)
+	if !ok {
+		return nil, fmt.Errorf("key version %s was not wrapped by KMS", keyVersion)
+	}
+
+	dataKey, err := p.client.Decrypt(ctx, keyID, wrappedKey, kmsEncryptionContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with KMS: %w", err)
+	}
+	return dataKey, nil
+}
+
+// KeyProviderChain wraps with its primary provider and unwraps with whichever provider holds
+// the row's key version. It is used while moving between providers, e.g. local keyring to KMS.
+type KeyProviderChain struct {
+	providers []KeyEncryptionKeyProvider
+}
+
+// NewKeyProviderChain creates a chain that wraps with primary and can also unwrap with the fallbacks
+func NewKeyProviderChain(primary KeyEncryptionKeyProvider, fallbacks ...KeyEncryptionKeyProvider) *KeyProviderChain {
+	return &KeyProviderChain{providers: append([]KeyEncryptionKeyProvider{primary}, fallbacks...)}
+}
+
+// CurrentKeyVersion returns the primary provider's current version
+func (c *KeyProviderChain) CurrentKeyVersion() string {
+	return c.providers[0].CurrentKeyVersion()
+}
+
+// HasKeyVersion reports whether any provider in the chain holds the version
+func (c *KeyProviderChain) HasKeyVersion(keyVersion string) bool {
+	return c.providerFor(keyVersion) != nil
+}
+
+// WrapDataKey wraps a data key with the primary provider
+func (c *KeyProviderChain) WrapDataKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	return c.providers[0].WrapDataKey(ctx, dataKey)
+}
+
+// UnwrapDataKey unwraps a data key with the first provider holding its version
+func (c *KeyProviderChain) UnwrapDataKey(ctx context.Context, wrappedKey []byte, keyVersion string) ([]byte, error) {
+	provider := c.providerFor(keyVersion)
+	if provider == nil {
+		return nil, fmt.Errorf("no key provider holds key version %s", keyVersion)
+	}
+	return provider.UnwrapDataKey(ctx, wrappedKey, keyVersion)
+}
+
+func (c *KeyProviderChain) providerFor(keyVersion string) KeyEncryptionKeyProvider {
+	for _, provider := range c.providers {
+		if provider.HasKeyVersion(keyVersion) {
+			return provider
+		}
+	}
+	return nil
+}
+
+// LoadKeyProvider builds the key-encryption-key provider from the environment:
+//   - PAYMENT_SYNC_KEYRING(_ARN): local keyring spec, current version first
+//   - PAYMENT_SYNC_KMS_KEY_ID: wrap new data keys with this KMS key instead (KMS_ENDPOINT overrides the endpoint)
+//
+// Without a keyring the legacy static key becomes local version "v1". The local keyring stays
+// in the chain behind KMS so rows written before switching to KMS remain readable.
+func LoadKeyProvider(ctx context.Context, secretsClient *awsclient.SecretsManagerClient, legacyKey string) (KeyEncryptionKeyProvider, error) {
+	var keyring *LocalKeyring
+	var err error
+
+	if os.Getenv("PAYMENT_SYNC_KEYRING_ARN") != "" || os.Getenv("PAYMENT_SYNC_KEYRING") != "" {
+		spec, err := secretsClient.GetSecretString(ctx, "PAYMENT_SYNC_KEYRING_ARN", "PAYMENT_SYNC_KEYRING")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get payment sync keyring: %w", err)
+		}
+		keyring, err = ParseLocalKeyring(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payment sync keyring: %w", err)
+		}
+	} else {
+		keyring, err = legacyKeyring(legacyKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	kmsKeyID := os.Getenv("PAYMENT_SYNC_KMS_KEY_ID")
+	if kmsKeyID == "" {
+		return keyring, nil
+	}
+
+	kmsClient, err := awsclient.NewKMSClient(ctx, os.Getenv("KMS_ENDPOINT"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize KMS client: %w", err)
+	}
+
+	return NewKeyProviderChain(NewKMSKeyProvider(kmsClient, kmsKeyID), keyring), nil
+}
+
+// legacyKeyring turns the hex static encryption key into a single-version local keyring
+func legacyKeyring(encryptionKey string) (*LocalKeyring, error) {
+	key, err := hex.DecodeString(encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key format - expected hex: %w", err)
+	}
+	return NewLocalKeyring("v1", map[string][]byte{"v1": key})
+}
+
+// encryptedEnvelope is the stored form of an envelope-encrypted value.
+// It is JSON so it fits both the JSONB configuration column and the text webhook secret column.
+type encryptedEnvelope struct {
+	KeyVersion string `json:"key_version"`
+	DataKey    []byte `json:"data_key"`   // Data key wrapped by the key-encryption key
+	Ciphertext []byte `json:"ciphertext"` // Nonce followed by the AES-256-GCM ciphertext
+}
+
+// parseEnvelope reports whether data is an envelope rather than a legacy ciphertext
+func parseEnvelope(data []byte) (encryptedEnvelope, bool) {
+	var envelope encryptedEnvelope
+	if len(data) == 0 || data[0] != '{' {
+		return envelope, false
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.KeyVersion == "" {
+		return envelope, false
+	}
+	return envelope, true
+}
+
+// cachedDataKey is an unwrapped data key kept for dataKeyCacheTTL
+type cachedDataKey struct {
+	key       []byte
+	expiresAt time.Time
+}
+
+// dataKeyCache caches unwrapped data keys by their wrapped form
+type dataKeyCache struct {
+	entries sync.Map
+}
+
+func (c *dataKeyCache) get(wrappedKey []byte) ([]byte, bool) {
+	value, ok := c.entries.Load(string(wrappedKey))
+	if !ok {
+		return nil, false
+	}
+	entry := value.(*cachedDataKey)
+	if time.Now().After(entry.expiresAt) {
+		c.entries.Delete(string(wrappedKey))
+		return nil, false
+	}
+	return entry.key, true
+}
+
+func (c *dataKeyCache) put(wrappedKey, key []byte) {
+	now := time.Now()
+	c.entries.Store(string(wrappedKey), &cachedDataKey{key: key, expiresAt: now.Add(dataKeyCacheTTL)})
+
+	// Drop expired entries so the cache does not grow with every rewritten row
+	c.entries.Range(func(k, value interface{}) bool {
+		if now.After(value.(*cachedDataKey).expiresAt) {
+			c.entries.Delete(k)
+		}
+		return true
+	})
+}
+
+// sealEnvelope encrypts plaintext with a fresh data key wrapped by the key provider
+func (c *PaymentSyncClient) sealEnvelope(ctx context.Context, plaintext []byte) ([]byte, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	wrappedKey, keyVersion, err := c.keys.WrapDataKey(ctx, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := sealAESGCM(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(encryptedEnvelope{
+		KeyVersion: keyVersion,
+		DataKey:    wrappedKey,
+		Ciphertext: ciphertext,
+	})
+}
+
+// openEnvelope decrypts an envelope, unwrapping its data key through the cache
+func (c *PaymentSyncClient) openEnvelope(ctx context.Context, envelope encryptedEnvelope) ([]byte, error) {
+	dataKey, ok := c.dataKeys.get(envelope.DataKey)
+	if !ok {
+		var err error
+		dataKey, err = c.keys.UnwrapDataKey(ctx, envelope.DataKey, envelope.KeyVersion)
+		if err != nil {
+			return nil, err
+		}
+		c.dataKeys.put(envelope.DataKey, dataKey)
+	}
+
+	return openAESGCM(dataKey, envelope.Ciphertext)
+}
+
+// sealAESGCM encrypts plaintext with AES-256-GCM, prefixing the random nonce
+func sealAESGCM(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to create nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// openAESGCM decrypts a nonce-prefixed AES-256-GCM ciphertext
+func openAESGCM(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	nonce := ciphertext[:gcm.NonceSize()]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,210 @@
+package payment_sync
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomHexKey(t *testing.T) string {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return hex.EncodeToString(key)
+}
+
+// fakeKMS "wraps" data keys by prefixing the key ID, so tests can tell which key was used
+type fakeKMS struct {
+	calls int
+}
+
+func (f *fakeKMS) Encrypt(_ context.Context, keyID string, plaintext []byte, _ map[string]string) ([]byte, error) {
+	f.calls++
+	return append([]byte(keyID+"|"), plaintext...), nil
+}
+
+func (f *fakeKMS) Decrypt(_ context.Context, keyID string, ciphertext []byte, _ map[string]string) ([]byte, error) {
+	f.calls++
+	prefix := []byte(keyID + "|")
+	if !bytes.HasPrefix(ciphertext, prefix) {
+		return nil, fmt.Errorf("ciphertext not wrapped with %s", keyID)
+	}
+	return ciphertext[len(prefix):], nil
+}
+
+func TestParseLocalKeyring(t *testing.T) {
+	v1, v2 := randomHexKey(t), randomHexKey(t)
+
+	testCases := []struct {
+		name            string
+		spec            string
+		expectErr       bool
+		expectedCurrent string
+	}{
+		{
+			name:            "First entry is current",
+			spec:            "v2:" + v2 + ", v1:" + v1,
+			expectedCurrent: "local:v2",
+		},
+		{
+			name:      "Invalid hex",
+			spec:      "v1:not-hex",
+			expectErr: true,
+		},
+		{
+			name:      "Wrong key length",
+			spec:      "v1:abcd",
+			expectErr: true,
+		},
+		{
+			name:      "Duplicate version",
+			spec:      "v1:" + v1 + ",v1:" + v2,
+			expectErr: true,
+		},
+		{
+			name:      "Empty keyring",
+			spec:      " , ",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keyring, err := ParseLocalKeyring(tc.spec)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCurrent, keyring.CurrentKeyVersion())
+			assert.True(t, keyring.HasKeyVersion("local:v1"))
+			assert.False(t, keyring.HasKeyVersion("local:v3"))
+			assert.False(t, keyring.HasKeyVersion("kms:v1"))
+		})
+	}
+}
+
+func TestEnvelopeEncryptionAcrossKeyRotation(t *testing.T) {
+	legacyKey := randomHexKey(t)
+	v1, v2 := randomHexKey(t), randomHexKey(t)
+
+	oldKeyring, err := ParseLocalKeyring("v1:" + v1)
+	require.NoError(t, err)
+	oldClient := NewPaymentSyncClientWithKeyProvider(nil, zap.NewNop(), legacyKey, oldKeyring)
+
+	config := PaymentProviderConfig{APIKey: "sk_test_123", WebhookSecret: "whsec_123", Environment: "test"}
+	encryptedConfig, err := oldClient.encryptConfiguration(config)
+	require.NoError(t, err)
+	encryptedSecret := oldClient.encryptWebhookSecret("whsec_123")
+
+	// Stored values are JSON envelopes recording the key version
+	envelope, ok := parseEnvelope(encryptedConfig)
+	require.True(t, ok)
+	assert.Equal(t, "local:v1", envelope.KeyVersion)
+	assert.True(t, strings.HasPrefix(encryptedSecret, "{"))
+
+	// After rotating to v2, rows wrapped with v1 stay readable
+	newKeyring, err := ParseLocalKeyring("v2:" + v2 + ",v1:" + v1)
+	require.NoError(t, err)
+	newClient := NewPaymentSyncClientWithKeyProvider(nil, zap.NewNop(), legacyKey, newKeyring)
+
+	decrypted, err := newClient.decryptConfiguration(encryptedConfig)
+	require.NoError(t, err)
+	assert.Equal(t, config, decrypted)
+	assert.Equal(t, "whsec_123", newClient.decryptWebhookSecret(encryptedSecret))
+
+	reencrypted, err := newClient.encryptConfiguration(decrypted)
+	require.NoError(t, err)
+	envelope, ok = parseEnvelope(reencrypted)
+	require.True(t, ok)
+	assert.Equal(t, "local:v2", envelope.KeyVersion)
+
+	// Once v1 is removed, only re-encrypted rows can be read
+	retiredKeyring, err := ParseLocalKeyring("v2:" + v2)
+	require.NoError(t, err)
+	retiredClient := NewPaymentSyncClientWithKeyProvider(nil, zap.NewNop(), legacyKey, retiredKeyring)
+
+	_, err = retiredClient.decryptConfiguration(encryptedConfig)
+	assert.Error(t, err)
+	_, err = retiredClient.decryptConfiguration(reencrypted)
+	assert.NoError(t, err)
+}
+
+func TestLegacyCiphertextStillDecrypts(t *testing.T) {
+	legacyKey := randomHexKey(t)
+	key, err := hex.DecodeString(legacyKey)
+	require.NoError(t, err)
+
+	// Values written before envelope encryption were sealed directly with the static key
+	legacyConfig, err := sealAESGCM(key, []byte(`{"api_key":"sk_live_legacy","webhook_secret":"","environment":"live"}`))
+	require.NoError(t, err)
+	legacySecret, err := sealAESGCM(key, []byte("whsec_legacy"))
+	require.NoError(t, err)
+
+	client := NewPaymentSyncClient(nil, zap.NewNop(), legacyKey)
+
+	config, err := client.decryptConfiguration(legacyConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "sk_live_legacy", config.APIKey)
+	assert.Equal(t, "whsec_legacy", client.decryptWebhookSecret(base64.StdEncoding.EncodeToString(legacySecret)))
+}
+
+func TestKeyProviderChain(t *testing.T) {
+	keyring, err := ParseLocalKeyring("v1:" + randomHexKey(t))
+	require.NoError(t, err)
+	kms := &fakeKMS{}
+	chain := NewKeyProviderChain(NewKMSKeyProvider(kms, "alias/payment-sync"), keyring)
+
+	assert.Equal(t, "kms:alias/payment-sync", chain.CurrentKeyVersion())
+	assert.True(t, chain.HasKeyVersion("local:v1"))
+	assert.True(t, chain.HasKeyVersion("kms:alias/old"))
+	assert.False(t, chain.HasKeyVersion("local:v2"))
+
+	ctx := context.Background()
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+
+	// New data keys are wrapped by KMS
+	wrapped, version, err := chain.WrapDataKey(ctx, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, "kms:alias/payment-sync", version)
+	unwrapped, err := chain.UnwrapDataKey(ctx, wrapped, version)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	// Data keys from the local keyring are still unwrapped locally
+	localWrapped, localVersion, err := keyring.WrapDataKey(ctx, dataKey)
+	require.NoError(t, err)
+	calls := kms.calls
+	unwrapped, err = chain.UnwrapDataKey(ctx, localWrapped, localVersion)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+	assert.Equal(t, calls, kms.calls, "local versions should not call KMS")
+
+	_, err = chain.UnwrapDataKey(ctx, localWrapped, "local:v2")
+	assert.Error(t, err)
+}
+
+func TestDataKeyCacheAvoidsRepeatedUnwraps(t *testing.T) {
+	kms := &fakeKMS{}
+	client := NewPaymentSyncClientWithKeyProvider(nil, zap.NewNop(), randomHexKey(t), NewKMSKeyProvider(kms, "alias/payment-sync"))
+
+	encrypted := client.encryptWebhookSecret("whsec_cached")
+	require.NotEmpty(t, encrypted)
+	wrapCalls := kms.calls
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "whsec_cached", client.decryptWebhookSecret(encrypted))
+	}
+	assert.Equal(t, wrapCalls+1, kms.calls, "data key should be unwrapped once and then cached")
+}
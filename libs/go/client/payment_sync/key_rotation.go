@@ -0,0 +1,269 @@
+package payment_sync
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// DefaultReencryptionBatchSize is how many rows are re-encrypted per query
+const DefaultReencryptionBatchSize = 100
+
+// ReencryptionResult summarizes a re-encryption run
+type ReencryptionResult struct {
+	Scanned     int `json:"scanned"`
+	Reencrypted int `json:"reencrypted"`
+	// Skipped rows were updated while being re-encrypted; the next run picks them up
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// KeyVersionCount is the number of stored credentials that use a key version
+type KeyVersionCount struct {
+	KeyVersion string `json:"key_version"`
+	// Count is the total of Configurations and WebhookSecrets
+	Count          int64 `json:"count"`
+	Configurations int64 `json:"configurations"`
+	// WebhookSecrets counts unexpired retired webhook secrets
+	WebhookSecrets int64 `json:"webhook_secrets"`
+	// Available reports whether the configured keys can still decrypt this version
+	Available bool `json:"available"`
+}
+
+// CurrentKeyVersion returns the key version new credentials are encrypted under
+func (c *PaymentSyncClient) CurrentKeyVersion() string {
+	return c.keys.CurrentKeyVersion()
+}
+
+// KeyVersionCounts reports how many configurations and retired webhook secrets use each key version.
+// A key version can be retired once neither uses it.
+func (c *PaymentSyncClient) KeyVersionCounts(ctx context.Context) ([]KeyVersionCount, error) {
+	configRows, err := c.db.CountWorkspacePaymentConfigurationsByKeyVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count configurations by key version: %w", err)
+	}
+
+	secretRows, err := c.db.CountWorkspaceWebhookSecretsByKeyVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count webhook secrets by key version: %w", err)
+	}
+
+	byVersion := make(map[string]*KeyVersionCount)
+	countFor := func(keyVersion string) *KeyVersionCount {
+		count, ok := byVersion[keyVersion]
+		if !ok {
+			count = &KeyVersionCount{
+				KeyVersion: keyVersion,
+				Available:  keyVersion == legacyKeyVersion || c.keys.HasKeyVersion(keyVersion),
+			}
+			byVersion[keyVersion] = count
+		}
+		return count
+	}
+
+	for _, row := range configRows {
+		count := countFor(row.KeyVersion)
+		count.Configurations += row.ConfigCount
+		count.Count += row.ConfigCount
+	}
+	for _, row := range secretRows {
+		count := countFor(row.KeyVersion)
+		count.WebhookSecrets += row.SecretCount
+		count.Count += row.SecretCount
+	}
+
+	counts := make([]KeyVersionCount, 0, len(byVersion))
+	for _, count := range byVersion {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].KeyVersion < counts[j].KeyVersion
+	})
+
+	return counts, nil
+}
+
+// ReencryptConfigurations re-encrypts every configuration and unexpired retired webhook secret
+// not yet under the current key version. Rows are read and written one at a time with an
+// optimistic check, so it is safe to run while the API and webhook handlers are live.
+// Rows that fail are logged and left for the next run.
+func (c *PaymentSyncClient) ReencryptConfigurations(ctx context.Context, batchSize int) (ReencryptionResult, error) {
+	var result ReencryptionResult
+	if batchSize <= 0 {
+		batchSize = DefaultReencryptionBatchSize
+	}
+
+	currentVersion := c.keys.CurrentKeyVersion()
+	if err := c.reencryptConfigurations(ctx, currentVersion, batchSize, &result); err != nil {
+		return result, err
+	}
+	if err := c.reencryptWebhookSecrets(ctx, currentVersion, batchSize, &result); err != nil {
+		return result, err
+	}
+
+	c.logger.Info("Re-encrypted provider credentials",
+		zap.String("key_version", currentVersion),
+		zap.Int("scanned", result.Scanned),
+		zap.Int("reencrypted", result.Reencrypted),
+		zap.Int("skipped", result.Skipped),
+		zap.Int("failed", result.Failed))
+
+	return result, nil
+}
+
+// reencryptConfigurations pages through configurations under older key versions
+func (c *PaymentSyncClient) reencryptConfigurations(ctx context.Context, currentVersion string, batchSize int, result *ReencryptionResult) error {
+	afterID := uuid.Nil
+
+	for {
+		rows, err := c.db.ListWorkspacePaymentConfigurationsForReencryption(ctx, db.ListWorkspacePaymentConfigurationsForReencryptionParams{
+			CurrentKeyVersion: currentVersion,
+			AfterID:           afterID,
+			BatchSize:         int32(batchSize),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list configurations for re-encryption: %w", err)
+		}
+
+		for _, row := range rows {
+			result.Scanned++
+			afterID = row.ID
+
+			updated, err := c.reencryptConfiguration(ctx, row)
+			switch {
+			case err != nil:
+				result.Failed++
+				c.logger.Error("Failed to re-encrypt payment configuration",
+					zap.String("config_id", row.ID.String()),
+					zap.String("workspace_id", row.WorkspaceID.String()),
+					zap.String("key_version", row.EncryptionKeyVersion.String),
+					zap.Error(err))
+			case updated:
+				result.Reencrypted++
+			default:
+				result.Skipped++
+			}
+		}
+
+		if len(rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+// reencryptWebhookSecrets pages through unexpired retired webhook secrets under older key versions
+func (c *PaymentSyncClient) reencryptWebhookSecrets(ctx context.Context, currentVersion string, batchSize int, result *ReencryptionResult) error {
+	afterID := uuid.Nil
+
+	for {
+		rows, err := c.db.ListWorkspaceWebhookSecretsForReencryption(ctx, db.ListWorkspaceWebhookSecretsForReencryptionParams{
+			CurrentKeyVersion: currentVersion,
+			AfterID:           afterID,
+			BatchSize:         int32(batchSize),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list webhook secrets for re-encryption: %w", err)
+		}
+
+		for _, row := range rows {
+			result.Scanned++
+			afterID = row.ID
+
+			updated, err := c.reencryptWebhookSecret(ctx, row)
+			switch {
+			case err != nil:
+				result.Failed++
+				c.logger.Error("Failed to re-encrypt retired webhook secret",
+					zap.String("secret_id", row.ID.String()),
+					zap.String("workspace_id", row.WorkspaceID.String()),
+					zap.String("key_version", row.EncryptionKeyVersion.String),
+					zap.Error(err))
+			case updated:
+				result.Reencrypted++
+			default:
+				result.Skipped++
+			}
+		}
+
+		if len(rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+// reencryptConfiguration rewrites one configuration under the current key version.
+// It returns false if the row changed after it was read.
+func (c *PaymentSyncClient) reencryptConfiguration(ctx context.Context, row db.WorkspacePaymentConfiguration) (bool, error) {
+	config, err := c.decryptConfiguration(row.Configuration)
+	if err != nil {
+		return false, fmt.Errorf("failed to decrypt configuration: %w", err)
+	}
+
+	webhookSecret := ""
+	if row.WebhookSecretKey.Valid && row.WebhookSecretKey.String != "" {
+		webhookSecret = c.decryptWebhookSecret(row.WebhookSecretKey.String)
+		if webhookSecret == "" {
+			return false, fmt.Errorf("failed to decrypt webhook secret")
+		}
+	}
+
+	encryptedConfig, err := c.encryptConfiguration(config)
+	if err != nil {
+		return false, err
+	}
+
+	encryptedSecret := pgtype.Text{}
+	if webhookSecret != "" {
+		encryptedSecret.String = c.encryptWebhookSecret(webhookSecret)
+		if encryptedSecret.String == "" {
+			return false, fmt.Errorf("failed to encrypt webhook secret")
+		}
+		encryptedSecret.Valid = true
+	}
+
+	updated, err := c.db.ReencryptWorkspacePaymentConfiguration(ctx, db.ReencryptWorkspacePaymentConfigurationParams{
+		Configuration:        encryptedConfig,
+		WebhookSecretKey:     encryptedSecret,
+		EncryptionKeyVersion: pgtype.Text{String: c.keys.CurrentKeyVersion(), Valid: true},
+		ID:                   row.ID,
+		WorkspaceID:          row.WorkspaceID,
+		UpdatedAt:            row.UpdatedAt,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to store re-encrypted configuration: %w", err)
+	}
+
+	return updated > 0, nil
+}
+
+// reencryptWebhookSecret rewrites one retired webhook secret under the current key version.
+// It returns false if the secret changed after it was read.
+func (c *PaymentSyncClient) reencryptWebhookSecret(ctx context.Context, row db.WorkspaceWebhookSecret) (bool, error) {
+	webhookSecret := c.decryptWebhookSecret(row.WebhookSecretKey)
+	if webhookSecret == "" {
+		return false, fmt.Errorf("failed to decrypt webhook secret")
+	}
+
+	encryptedSecret := c.encryptWebhookSecret(webhookSecret)
+	if encryptedSecret == "" {
+		return false, fmt.Errorf("failed to encrypt webhook secret")
+	}
+
+	updated, err := c.db.ReencryptWorkspaceWebhookSecret(ctx, db.ReencryptWorkspaceWebhookSecretParams{
+		WebhookSecretKey:         encryptedSecret,
+		EncryptionKeyVersion:     pgtype.Text{String: c.keys.CurrentKeyVersion(), Valid: true},
+		ID:                       row.ID,
+		PreviousWebhookSecretKey: row.WebhookSecretKey,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to store re-encrypted webhook secret: %w", err)
+	}
+
+	return updated > 0, nil
+}
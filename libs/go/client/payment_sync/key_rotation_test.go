@@ -0,0 +1,184 @@
+package payment_sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keyRotationQuerier serves the key rotation queries from in-memory rows.
+// Any other query panics through the embedded nil interface.
+type keyRotationQuerier struct {
+	db.Querier
+	configs []db.WorkspacePaymentConfiguration
+	secrets []db.WorkspaceWebhookSecret
+}
+
+func (q *keyRotationQuerier) ListWorkspacePaymentConfigurationsForReencryption(_ context.Context, arg db.ListWorkspacePaymentConfigurationsForReencryptionParams) ([]db.WorkspacePaymentConfiguration, error) {
+	var rows []db.WorkspacePaymentConfiguration
+	for _, row := range q.configs {
+		if row.EncryptionKeyVersion.String != arg.CurrentKeyVersion && row.ID.String() > arg.AfterID.String() {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (q *keyRotationQuerier) ReencryptWorkspacePaymentConfiguration(_ context.Context, arg db.ReencryptWorkspacePaymentConfigurationParams) (int64, error) {
+	for i := range q.configs {
+		if q.configs[i].ID == arg.ID {
+			q.configs[i].Configuration = arg.Configuration
+			q.configs[i].WebhookSecretKey = arg.WebhookSecretKey
+			q.configs[i].EncryptionKeyVersion = arg.EncryptionKeyVersion
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (q *keyRotationQuerier) ListWorkspaceWebhookSecretsForReencryption(_ context.Context, arg db.ListWorkspaceWebhookSecretsForReencryptionParams) ([]db.WorkspaceWebhookSecret, error) {
+	var rows []db.WorkspaceWebhookSecret
+	for _, row := range q.secrets {
+		if row.EncryptionKeyVersion.String != arg.CurrentKeyVersion && row.ID.String() > arg.AfterID.String() {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (q *keyRotationQuerier) ReencryptWorkspaceWebhookSecret(_ context.Context, arg db.ReencryptWorkspaceWebhookSecretParams) (int64, error) {
+	for i := range q.secrets {
+		if q.secrets[i].ID == arg.ID && q.secrets[i].WebhookSecretKey == arg.PreviousWebhookSecretKey {
+			q.secrets[i].WebhookSecretKey = arg.WebhookSecretKey
+			q.secrets[i].EncryptionKeyVersion = arg.EncryptionKeyVersion
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (q *keyRotationQuerier) CountWorkspacePaymentConfigurationsByKeyVersion(context.Context) ([]db.CountWorkspacePaymentConfigurationsByKeyVersionRow, error) {
+	counts := map[string]int64{}
+	for _, row := range q.configs {
+		counts[keyVersionLabel(row.EncryptionKeyVersion)]++
+	}
+	var rows []db.CountWorkspacePaymentConfigurationsByKeyVersionRow
+	for version, count := range counts {
+		rows = append(rows, db.CountWorkspacePaymentConfigurationsByKeyVersionRow{KeyVersion: version, ConfigCount: count})
+	}
+	return rows, nil
+}
+
+func (q *keyRotationQuerier) CountWorkspaceWebhookSecretsByKeyVersion(context.Context) ([]db.CountWorkspaceWebhookSecretsByKeyVersionRow, error) {
+	counts := map[string]int64{}
+	for _, row := range q.secrets {
+		counts[keyVersionLabel(row.EncryptionKeyVersion)]++
+	}
+	var rows []db.CountWorkspaceWebhookSecretsByKeyVersionRow
+	for version, count := range counts {
+		rows = append(rows, db.CountWorkspaceWebhookSecretsByKeyVersionRow{KeyVersion: version, SecretCount: count})
+	}
+	return rows, nil
+}
+
+// newTestClient creates a client reading and writing rows through querier
+func newTestClient(querier db.Querier, logger *zap.Logger, encryptionKey string, keys KeyEncryptionKeyProvider) *PaymentSyncClient {
+	client := NewPaymentSyncClientWithKeyProvider(nil, logger, encryptionKey, keys)
+	client.db = querier
+	return client
+}
+
+func keyVersionLabel(version pgtype.Text) string {
+	if !version.Valid {
+		return legacyKeyVersion
+	}
+	return version.String
+}
+
+func TestReencryptConfigurationsIncludesRetiredWebhookSecrets(t *testing.T) {
+	legacyKey := randomHexKey(t)
+	v1, v2 := randomHexKey(t), randomHexKey(t)
+
+	oldKeyring, err := ParseLocalKeyring("v1:" + v1)
+	require.NoError(t, err)
+	oldClient := NewPaymentSyncClientWithKeyProvider(nil, zap.NewNop(), legacyKey, oldKeyring)
+
+	config := PaymentProviderConfig{APIKey: "sk_test_123", WebhookSecret: "whsec_current", Environment: "test"}
+	encryptedConfig, err := oldClient.encryptConfiguration(config)
+	require.NoError(t, err)
+
+	v1Version := pgtype.Text{String: "local:v1", Valid: true}
+	querier := &keyRotationQuerier{
+		configs: []db.WorkspacePaymentConfiguration{{
+			ID:                   uuid.New(),
+			WorkspaceID:          uuid.New(),
+			Configuration:        encryptedConfig,
+			EncryptionKeyVersion: v1Version,
+		}},
+		secrets: []db.WorkspaceWebhookSecret{{
+			ID:                   uuid.New(),
+			WorkspaceID:          uuid.New(),
+			ProviderName:         "stripe",
+			WebhookSecretKey:     oldClient.encryptWebhookSecret("whsec_previous"),
+			EncryptionKeyVersion: v1Version,
+		}},
+	}
+
+	newKeyring, err := ParseLocalKeyring("v2:" + v2 + ",v1:" + v1)
+	require.NoError(t, err)
+	client := newTestClient(querier, zap.NewNop(), legacyKey, newKeyring)
+
+	// Before re-encryption the retired secret keeps v1 in use
+	counts, err := client.KeyVersionCounts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	assert.Equal(t, KeyVersionCount{KeyVersion: "local:v1", Count: 2, Configurations: 1, WebhookSecrets: 1, Available: true}, counts[0])
+
+	result, err := client.ReencryptConfigurations(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, ReencryptionResult{Scanned: 2, Reencrypted: 2}, result)
+
+	counts, err = client.KeyVersionCounts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	assert.Equal(t, KeyVersionCount{KeyVersion: "local:v2", Count: 2, Configurations: 1, WebhookSecrets: 1, Available: true}, counts[0])
+
+	// With v1 removed, the retired secret is still readable
+	retiredKeyring, err := ParseLocalKeyring("v2:" + v2)
+	require.NoError(t, err)
+	retiredClient := newTestClient(querier, zap.NewNop(), legacyKey, retiredKeyring)
+	assert.Equal(t, "whsec_previous", retiredClient.decryptWebhookSecret(querier.secrets[0].WebhookSecretKey))
+}
+
+func TestKeyVersionCountsMergesTables(t *testing.T) {
+	keyring, err := ParseLocalKeyring("v2:" + randomHexKey(t))
+	require.NoError(t, err)
+
+	querier := &keyRotationQuerier{
+		configs: []db.WorkspacePaymentConfiguration{
+			{ID: uuid.New(), EncryptionKeyVersion: pgtype.Text{String: "local:v2", Valid: true}},
+		},
+		secrets: []db.WorkspaceWebhookSecret{
+			{ID: uuid.New(), EncryptionKeyVersion: pgtype.Text{String: "local:v1", Valid: true}},
+			{ID: uuid.New()},
+		},
+	}
+	client := newTestClient(querier, zap.NewNop(), randomHexKey(t), keyring)
+
+	counts, err := client.KeyVersionCounts(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []KeyVersionCount{
+		{KeyVersion: "legacy", Count: 1, WebhookSecrets: 1, Available: true},
+		{KeyVersion: "local:v1", Count: 1, WebhookSecrets: 1, Available: false},
+		{KeyVersion: "local:v2", Count: 1, Configurations: 1, Available: true},
+	}, counts)
+}
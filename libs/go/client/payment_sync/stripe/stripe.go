@@ -24,8 +24,10 @@ var _ ps.PaymentSyncService = (*StripeService)(nil)
 type StripeService struct {
 	client        *stripe.Client
 	webhookSecret string
-	logger        *zap.Logger
-	db            *db.Queries
+	// webhookTolerance overrides the SDK's default signature tolerance when set
+	webhookTolerance time.Duration
+	logger           *zap.Logger
+	db               *db.Queries
 }
 
 // NewStripeService creates a new instance of StripeService.
@@ -61,6 +63,16 @@ func (s *StripeService) Configure(ctx context.Context, config map[string]string)
 	s.client = stripe.NewClient(apiKey, nil)
 	s.webhookSecret = webhookSecret
 
+	// Optional signature tolerance (e.g. "5m0s"), passed in by the webhook receiver
+	s.webhookTolerance = 0
+	if toleranceStr, ok := config["webhook_tolerance"]; ok && toleranceStr != "" {
+		tolerance, err := time.ParseDuration(toleranceStr)
+		if err != nil {
+			return fmt.Errorf("invalid webhook tolerance %q: %w", toleranceStr, err)
+		}
+		s.webhookTolerance = tolerance
+	}
+
 	return nil
 }
 
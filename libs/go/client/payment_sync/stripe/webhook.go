@@ -20,7 +20,9 @@ func (s *StripeService) HandleWebhook(ctx context.Context, requestBody []byte, s
 		return ps.WebhookEvent{}, fmt.Errorf("stripe service not configured for webhooks (client or secret missing)")
 	}
 
-	event, err := webhook.ConstructEvent(requestBody, signatureHeader, s.webhookSecret)
+	event, err := webhook.ConstructEventWithOptions(requestBody, signatureHeader, s.webhookSecret, webhook.ConstructEventOptions{
+		Tolerance: s.webhookTolerance, // Zero falls back to webhook.DefaultTolerance
+	})
 	if err != nil {
 		s.logger.Error("Webhook signature verification failed", zap.Error(err))
 		return ps.WebhookEvent{SignatureValid: false, RawData: requestBody}, fmt.Errorf("webhook signature verification failed: %w", err)
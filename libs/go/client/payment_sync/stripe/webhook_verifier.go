@@ -0,0 +1,145 @@
+package stripe
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ps "github.com/cyphera/cyphera-api/libs/go/client/payment_sync"
+	"github.com/cyphera/cyphera-api/libs/go/constants"
+
+	"github.com/stripe/stripe-go/v82/webhook"
+)
+
+// Ensure StripeWebhookVerifier implements WebhookVerifier interface
+var _ ps.WebhookVerifier = (*StripeWebhookVerifier)(nil)
+
+// StripeSignatureHeader is the header Stripe uses to sign webhook deliveries
+const StripeSignatureHeader = "Stripe-Signature"
+
+// StripeWebhookVerifier verifies Stripe-Signature headers ("t=<unix>,v1=<hex>,...").
+// Unlike webhook.ConstructEvent it accepts several secrets and rejects timestamps too far in the future.
+type StripeWebhookVerifier struct {
+	tolerance time.Duration
+}
+
+// NewStripeWebhookVerifier creates a Stripe verifier; a zero tolerance uses ps.DefaultWebhookTolerance
+func NewStripeWebhookVerifier(tolerance time.Duration) *StripeWebhookVerifier {
+	if tolerance <= 0 {
+		tolerance = ps.DefaultWebhookTolerance
+	}
+	return &StripeWebhookVerifier{tolerance: tolerance}
+}
+
+// Provider returns the provider name handled by this verifier
+func (v *StripeWebhookVerifier) Provider() string {
+	return constants.StripeProvider
+}
+
+// SignatureHeader returns the name of the Stripe signature header
+func (v *StripeWebhookVerifier) SignatureHeader() string {
+	return StripeSignatureHeader
+}
+
+// Tolerance returns the maximum accepted age of a signed timestamp
+func (v *StripeWebhookVerifier) Tolerance() time.Duration {
+	return v.tolerance
+}
+
+// Verify checks the Stripe signature against each secret and enforces the tolerance window
+func (v *StripeWebhookVerifier) Verify(payload []byte, signatureHeader string, secrets []string, now time.Time) (ps.VerifiedWebhook, error) {
+	if signatureHeader == "" {
+		return ps.VerifiedWebhook{}, ps.NewWebhookVerificationError(ps.WebhookRejectMissingSignature, nil)
+	}
+	if len(secrets) == 0 {
+		return ps.VerifiedWebhook{}, ps.NewWebhookVerificationError(ps.WebhookRejectNoSecrets, nil)
+	}
+
+	signedAt, signatures, err := parseStripeSignatureHeader(signatureHeader)
+	if err != nil {
+		return ps.VerifiedWebhook{}, ps.NewWebhookVerificationError(ps.WebhookRejectMalformedSignature, err)
+	}
+
+	// Try each active secret; Stripe may also send several v1 signatures while its own secret rolls
+	matchedSecret := ""
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		expected := webhook.ComputeSignature(signedAt, payload, secret)
+		for _, signature := range signatures {
+			if hmac.Equal(expected, signature) {
+				matchedSecret = secret
+				break
+			}
+		}
+		if matchedSecret != "" {
+			break
+		}
+	}
+	if matchedSecret == "" {
+		return ps.VerifiedWebhook{}, ps.NewWebhookVerificationError(ps.WebhookRejectSignatureMismatch, nil)
+	}
+
+	// Reject stale deliveries and timestamps from the future alike
+	drift := now.Sub(signedAt)
+	if drift > v.tolerance || drift < -v.tolerance {
+		return ps.VerifiedWebhook{}, ps.NewWebhookVerificationError(ps.WebhookRejectTimestampTolerance,
+			fmt.Errorf("signed at %s, %s outside tolerance of %s", signedAt.UTC().Format(time.RFC3339), drift, v.tolerance))
+	}
+
+	var event struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil || event.ID == "" {
+		return ps.VerifiedWebhook{}, ps.NewWebhookVerificationError(ps.WebhookRejectMissingEventID, err)
+	}
+
+	return ps.VerifiedWebhook{
+		EventID:  event.ID,
+		SignedAt: signedAt,
+		Secret:   matchedSecret,
+	}, nil
+}
+
+// parseStripeSignatureHeader extracts the timestamp and v1 signatures from a Stripe-Signature header
+func parseStripeSignatureHeader(header string) (time.Time, [][]byte, error) {
+	var signedAt time.Time
+	var signatures [][]byte
+
+	for _, pair := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return time.Time{}, nil, errors.New("invalid signature header pair")
+		}
+
+		switch key {
+		case "t":
+			timestamp, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return time.Time{}, nil, fmt.Errorf("invalid signature timestamp: %w", err)
+			}
+			signedAt = time.Unix(timestamp, 0)
+		case "v1":
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				continue // Ignore invalid signatures, as Stripe's SDK does
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+
+	if signedAt.IsZero() {
+		return time.Time{}, nil, errors.New("signature header has no timestamp")
+	}
+	if len(signatures) == 0 {
+		return time.Time{}, nil, errors.New("signature header has no v1 signatures")
+	}
+
+	return signedAt, signatures, nil
+}
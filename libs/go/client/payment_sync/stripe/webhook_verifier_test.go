@@ -0,0 +1,129 @@
+package stripe
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	ps "github.com/cyphera/cyphera-api/libs/go/client/payment_sync"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v82/webhook"
+)
+
+func signStripePayload(payload []byte, secret string, signedAt time.Time) string {
+	signature := webhook.ComputeSignature(signedAt, payload, secret)
+	return fmt.Sprintf("t=%d,v1=%s", signedAt.Unix(), hex.EncodeToString(signature))
+}
+
+func TestStripeWebhookVerifier_Verify(t *testing.T) {
+	verifier := NewStripeWebhookVerifier(5 * time.Minute)
+	payload := []byte(`{"id":"evt_test_123","type":"invoice.paid"}`)
+	now := time.Unix(1_750_000_000, 0)
+
+	testCases := []struct {
+		name           string
+		payload        []byte
+		header         string
+		secrets        []string
+		expectedReason string
+		expectedSecret string
+	}{
+		{
+			name:           "Valid signature with current secret",
+			payload:        payload,
+			header:         signStripePayload(payload, "whsec_current", now),
+			secrets:        []string{"whsec_current", "whsec_previous"},
+			expectedSecret: "whsec_current",
+		},
+		{
+			name:           "Previous secret accepted during rotation",
+			payload:        payload,
+			header:         signStripePayload(payload, "whsec_previous", now.Add(-time.Minute)),
+			secrets:        []string{"whsec_current", "whsec_previous"},
+			expectedSecret: "whsec_previous",
+		},
+		{
+			name:           "Unknown secret rejected",
+			payload:        payload,
+			header:         signStripePayload(payload, "whsec_other", now),
+			secrets:        []string{"whsec_current"},
+			expectedReason: ps.WebhookRejectSignatureMismatch,
+		},
+		{
+			name:           "Stale timestamp rejected",
+			payload:        payload,
+			header:         signStripePayload(payload, "whsec_current", now.Add(-10*time.Minute)),
+			secrets:        []string{"whsec_current"},
+			expectedReason: ps.WebhookRejectTimestampTolerance,
+		},
+		{
+			name:           "Future timestamp rejected",
+			payload:        payload,
+			header:         signStripePayload(payload, "whsec_current", now.Add(10*time.Minute)),
+			secrets:        []string{"whsec_current"},
+			expectedReason: ps.WebhookRejectTimestampTolerance,
+		},
+		{
+			name:           "Malformed header rejected",
+			payload:        payload,
+			header:         "v1=deadbeef",
+			secrets:        []string{"whsec_current"},
+			expectedReason: ps.WebhookRejectMalformedSignature,
+		},
+		{
+			name:           "Missing header rejected",
+			payload:        payload,
+			header:         "",
+			secrets:        []string{"whsec_current"},
+			expectedReason: ps.WebhookRejectMissingSignature,
+		},
+		{
+			name:           "No secrets configured",
+			payload:        payload,
+			header:         signStripePayload(payload, "whsec_current", now),
+			secrets:        nil,
+			expectedReason: ps.WebhookRejectNoSecrets,
+		},
+		{
+			name:           "Payload without event ID rejected",
+			payload:        []byte(`{"type":"invoice.paid"}`),
+			header:         signStripePayload([]byte(`{"type":"invoice.paid"}`), "whsec_current", now),
+			secrets:        []string{"whsec_current"},
+			expectedReason: ps.WebhookRejectMissingEventID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verified, err := verifier.Verify(tc.payload, tc.header, tc.secrets, now)
+
+			if tc.expectedReason != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedReason, ps.WebhookRejectionReason(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "evt_test_123", verified.EventID)
+			assert.Equal(t, tc.expectedSecret, verified.Secret)
+		})
+	}
+}
+
+func TestWebhookVerifierRegistry(t *testing.T) {
+	registry := ps.NewWebhookVerifierRegistry()
+	registry.Register(NewStripeWebhookVerifier(0))
+
+	verifier, ok := registry.Get("stripe")
+	require.True(t, ok)
+	assert.Equal(t, ps.DefaultWebhookTolerance, verifier.Tolerance())
+
+	_, ok = registry.Get("paddle")
+	assert.False(t, ok)
+
+	headers := map[string]string{"stripe-signature": "t=1,v1=ab"}
+	assert.Equal(t, "t=1,v1=ab", ps.SignatureFromHeaders(verifier, headers))
+}
@@ -0,0 +1,131 @@
+package payment_sync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookTolerance is how far a webhook's signed timestamp may drift from our clock
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// Webhook rejection reasons, stored with rejected events in payment_sync_events
+const (
+	WebhookRejectMissingSignature   = "missing_signature"
+	WebhookRejectMalformedSignature = "malformed_signature"
+	WebhookRejectTimestampTolerance = "timestamp_outside_tolerance"
+	WebhookRejectSignatureMismatch  = "signature_mismatch"
+	WebhookRejectNoSecrets          = "no_webhook_secrets"
+	WebhookRejectMissingEventID     = "missing_event_id"
+	WebhookRejectReplayed           = "replayed_event"
+)
+
+// WebhookVerificationError describes why a webhook was rejected
+type WebhookVerificationError struct {
+	Reason string
+	Err    error
+}
+
+func (e *WebhookVerificationError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("webhook rejected (%s): %v", e.Reason, e.Err)
+	}
+	return fmt.Sprintf("webhook rejected (%s)", e.Reason)
+}
+
+func (e *WebhookVerificationError) Unwrap() error {
+	return e.Err
+}
+
+// NewWebhookVerificationError creates a verification error for the given reason
+func NewWebhookVerificationError(reason string, err error) *WebhookVerificationError {
+	return &WebhookVerificationError{Reason: reason, Err: err}
+}
+
+// WebhookRejectionReason returns the rejection reason of a verification error, or "" for other errors
+func WebhookRejectionReason(err error) string {
+	var verificationErr *WebhookVerificationError
+	if errors.As(err, &verificationErr) {
+		return verificationErr.Reason
+	}
+	return ""
+}
+
+// VerifiedWebhook is the result of a successful signature check
+type VerifiedWebhook struct {
+	// EventID is the provider's event ID, used as the replay cache key
+	EventID string
+	// SignedAt is the timestamp covered by the signature
+	SignedAt time.Time
+	// Secret is the webhook secret that produced a matching signature
+	Secret string
+}
+
+// WebhookVerifier checks provider webhook signatures before a payload is trusted.
+// Implementations must be safe for concurrent use.
+type WebhookVerifier interface {
+	// Provider returns the provider name the verifier handles (e.g. "stripe")
+	Provider() string
+	// SignatureHeader returns the name of the HTTP header carrying the signature
+	SignatureHeader() string
+	// Tolerance returns the maximum accepted age of a signed timestamp
+	Tolerance() time.Duration
+	// Verify checks the payload against each secret in turn, accepting the first match.
+	// Multiple secrets allow the previous secret to keep working while a new one is rolled out.
+	Verify(payload []byte, signatureHeader string, secrets []string, now time.Time) (VerifiedWebhook, error)
+}
+
+// WebhookVerifierRegistry maps provider names to signature verifiers
+type WebhookVerifierRegistry struct {
+	mu        sync.RWMutex
+	verifiers map[string]WebhookVerifier
+}
+
+// NewWebhookVerifierRegistry creates an empty verifier registry
+func NewWebhookVerifierRegistry() *WebhookVerifierRegistry {
+	return &WebhookVerifierRegistry{
+		verifiers: make(map[string]WebhookVerifier),
+	}
+}
+
+// Register adds or replaces the verifier for its provider
+func (r *WebhookVerifierRegistry) Register(verifier WebhookVerifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.verifiers[verifier.Provider()] = verifier
+}
+
+// Get returns the verifier for a provider
+func (r *WebhookVerifierRegistry) Get(provider string) (WebhookVerifier, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	verifier, ok := r.verifiers[provider]
+	return verifier, ok
+}
+
+// Providers returns the names of all registered providers
+func (r *WebhookVerifierRegistry) Providers() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	providers := make([]string, 0, len(r.verifiers))
+	for name := range r.verifiers {
+		providers = append(providers, name)
+	}
+	return providers
+}
+
+// SignatureFromHeaders returns the verifier's signature header from a header map, ignoring case
+func SignatureFromHeaders(verifier WebhookVerifier, headers map[string]string) string {
+	name := verifier.SignatureHeader()
+	if value, ok := headers[name]; ok {
+		return value
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
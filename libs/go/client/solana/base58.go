@@ -0,0 +1,67 @@
+package solana
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Solana encodes public keys, signatures and blockhashes with the Bitcoin base58 alphabet
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58Indexes = func() [256]int {
+	var indexes [256]int
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		indexes[base58Alphabet[i]] = i
+	}
+	return indexes
+}()
+
+var bigRadix = big.NewInt(58)
+
+// EncodeBase58 encodes bytes as base58, keeping leading zero bytes as leading '1's
+func EncodeBase58(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	value := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+	encoded := make([]byte, 0, len(data)*138/100+1)
+	for value.Sign() > 0 {
+		value.DivMod(value, bigRadix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		encoded = append(encoded, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+// DecodeBase58 decodes a base58 string
+func DecodeBase58(encoded string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(encoded) && encoded[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	value := new(big.Int)
+	for i := 0; i < len(encoded); i++ {
+		digit := base58Indexes[encoded[i]]
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q at position %d", encoded[i], i)
+		}
+		value.Mul(value, bigRadix)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+
+	decoded := value.Bytes()
+	return append(make([]byte, zeros, zeros+len(decoded)), decoded...), nil
+}
@@ -0,0 +1,482 @@
+package solana
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	httpClient "github.com/cyphera/cyphera-api/libs/go/client/http"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+
+	// CommitmentConfirmed is the commitment reads use: voted on by a supermajority of the cluster, and
+	// in practice never rolled back
+	CommitmentConfirmed = "confirmed"
+)
+
+// JSON-RPC error codes the client maps to ErrNotFound
+const (
+	rpcErrorBlockSkipped               = -32007
+	rpcErrorBlockNotAvailable          = -32004
+	rpcErrorLongTermStorageSlotSkipped = -32009
+)
+
+// ErrNotFound is returned when the cluster does not know a transaction, account or block
+var ErrNotFound = errors.New("not found")
+
+// RPCError is an error the RPC node returned for a request
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("solana RPC error %d: %s", e.Code, e.Message)
+}
+
+// Client calls a Solana cluster's JSON-RPC API
+type Client struct {
+	httpClient *httpClient.HTTPClient
+	rpcURL     string
+	requestID  atomic.Uint64
+}
+
+// NewClient creates a client for the RPC node at rpcURL
+func NewClient(rpcURL string) *Client {
+	return &Client{
+		httpClient: httpClient.NewHTTPClient(
+			httpClient.WithTimeout(defaultTimeout),
+			httpClient.WithRetryConfig(&httpClient.RetryConfig{MaxRetries: 0}),
+		),
+		rpcURL: rpcURL,
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// call sends a JSON-RPC request and decodes its result. A null result is returned as ErrNotFound.
+func (c *Client) call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	req := rpcRequest{
+		JSONRPC: "2.0",
+		ID:      c.requestID.Add(1),
+		Method:  method,
+		Params:  params,
+	}
+
+	resp, err := c.httpClient.Post(ctx, c.rpcURL, req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", method, err)
+	}
+
+	var body rpcResponse
+	if err := c.httpClient.ProcessJSONResponse(resp, &body); err != nil {
+		return fmt.Errorf("%s request failed: %w", method, err)
+	}
+	if body.Error != nil {
+		switch body.Error.Code {
+		case rpcErrorBlockSkipped, rpcErrorBlockNotAvailable, rpcErrorLongTermStorageSlotSkipped:
+			return fmt.Errorf("%w: %s", ErrNotFound, body.Error.Message)
+		}
+		return body.Error
+	}
+	if len(body.Result) == 0 || string(body.Result) == "null" {
+		return ErrNotFound
+	}
+	if err := json.Unmarshal(body.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+// contextValue wraps results that carry the slot they were read at
+type contextValue[T any] struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value *T `json:"value"`
+}
+
+// GetSlot returns the latest confirmed slot
+func (c *Client) GetSlot(ctx context.Context) (uint64, error) {
+	var slot uint64
+	if err := c.call(ctx, "getSlot", &slot, map[string]interface{}{"commitment": CommitmentConfirmed}); err != nil {
+		return 0, err
+	}
+	return slot, nil
+}
+
+// GetBalance returns an account's balance in lamports
+func (c *Client) GetBalance(ctx context.Context, address string) (uint64, error) {
+	var result contextValue[uint64]
+	if err := c.call(ctx, "getBalance", &result, address, map[string]interface{}{"commitment": CommitmentConfirmed}); err != nil {
+		return 0, err
+	}
+	if result.Value == nil {
+		return 0, nil
+	}
+	return *result.Value, nil
+}
+
+// GetBlockhash returns the hash of the block produced in a slot
+func (c *Client) GetBlockhash(ctx context.Context, slot uint64) (string, error) {
+	var block struct {
+		Blockhash string `json:"blockhash"`
+	}
+	err := c.call(ctx, "getBlock", &block, slot, map[string]interface{}{
+		"commitment":                     CommitmentConfirmed,
+		"transactionDetails":             "none",
+		"rewards":                        false,
+		"maxSupportedTransactionVersion": 0,
+	})
+	if err != nil {
+		return "", err
+	}
+	return block.Blockhash, nil
+}
+
+// LatestBlockhash is a blockhash to build transactions with and the last block height they can land in
+type LatestBlockhash struct {
+	Blockhash            PublicKey
+	LastValidBlockHeight uint64
+}
+
+// GetLatestBlockhash returns a recent blockhash for a new transaction
+func (c *Client) GetLatestBlockhash(ctx context.Context) (*LatestBlockhash, error) {
+	var result contextValue[struct {
+		Blockhash            string `json:"blockhash"`
+		LastValidBlockHeight uint64 `json:"lastValidBlockHeight"`
+	}]
+	if err := c.call(ctx, "getLatestBlockhash", &result, map[string]interface{}{"commitment": CommitmentConfirmed}); err != nil {
+		return nil, err
+	}
+	if result.Value == nil {
+		return nil, ErrNotFound
+	}
+
+	blockhash, err := ParsePublicKey(result.Value.Blockhash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blockhash: %w", err)
+	}
+	return &LatestBlockhash{Blockhash: blockhash, LastValidBlockHeight: result.Value.LastValidBlockHeight}, nil
+}
+
+// GetFeeForMessage returns the fee in lamports the cluster would charge for a message
+func (c *Client) GetFeeForMessage(ctx context.Context, message Message) (uint64, error) {
+	var result contextValue[uint64]
+	encoded := base64.StdEncoding.EncodeToString(message.Serialize())
+	if err := c.call(ctx, "getFeeForMessage", &result, encoded, map[string]interface{}{"commitment": CommitmentConfirmed}); err != nil {
+		return 0, err
+	}
+	if result.Value == nil {
+		return 0, fmt.Errorf("fee unavailable: the message's blockhash has expired")
+	}
+	return *result.Value, nil
+}
+
+// ConfirmedTransaction is a transaction the cluster has included in a block
+type ConfirmedTransaction struct {
+	Signature            string
+	Slot                 uint64
+	BlockTime            int64
+	FeePayer             string
+	Fee                  uint64
+	ComputeUnitsConsumed uint64
+	// Err is the transaction's execution error, or nil when it succeeded
+	Err json.RawMessage
+	// Signers are the accounts that signed the transaction, fee payer first
+	Signers []string
+	// Instructions are the transaction's top-level instructions as the cluster parsed them
+	Instructions []ParsedInstruction
+}
+
+// ParsedInstruction is an instruction of a program the cluster can parse, such as SPL Token or Memo
+type ParsedInstruction struct {
+	Program   string
+	ProgramID string
+	// Type and Info are set for SPL Token instructions, e.g. "approve" and its accounts and amount
+	Type string
+	Info json.RawMessage
+	// Memo is set for Memo program instructions
+	Memo string
+}
+
+// Succeeded reports whether the transaction executed without error
+func (t *ConfirmedTransaction) Succeeded() bool {
+	return len(t.Err) == 0 || string(t.Err) == "null"
+}
+
+type transactionResult struct {
+	Slot      uint64 `json:"slot"`
+	BlockTime *int64 `json:"blockTime"`
+	Meta      *struct {
+		Err                  json.RawMessage `json:"err"`
+		Fee                  uint64          `json:"fee"`
+		ComputeUnitsConsumed *uint64         `json:"computeUnitsConsumed"`
+	} `json:"meta"`
+	Transaction struct {
+		Signatures []string `json:"signatures"`
+		Message    struct {
+			AccountKeys []struct {
+				Pubkey string `json:"pubkey"`
+				Signer bool   `json:"signer"`
+			} `json:"accountKeys"`
+			Instructions []struct {
+				Program   string          `json:"program"`
+				ProgramID string          `json:"programId"`
+				Parsed    json.RawMessage `json:"parsed"`
+			} `json:"instructions"`
+		} `json:"message"`
+	} `json:"transaction"`
+}
+
+// GetTransaction returns a confirmed transaction by its signature, or ErrNotFound when it is not in a block
+func (c *Client) GetTransaction(ctx context.Context, signature string) (*ConfirmedTransaction, error) {
+	var result transactionResult
+	err := c.call(ctx, "getTransaction", &result, signature, map[string]interface{}{
+		"commitment":                     CommitmentConfirmed,
+		"encoding":                       "jsonParsed",
+		"maxSupportedTransactionVersion": 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &ConfirmedTransaction{
+		Signature: signature,
+		Slot:      result.Slot,
+	}
+	if result.BlockTime != nil {
+		tx.BlockTime = *result.BlockTime
+	}
+	if result.Meta != nil {
+		tx.Fee = result.Meta.Fee
+		tx.Err = result.Meta.Err
+		if result.Meta.ComputeUnitsConsumed != nil {
+			tx.ComputeUnitsConsumed = *result.Meta.ComputeUnitsConsumed
+		}
+	}
+	if keys := result.Transaction.Message.AccountKeys; len(keys) > 0 {
+		tx.FeePayer = keys[0].Pubkey
+	}
+	for _, key := range result.Transaction.Message.AccountKeys {
+		if key.Signer {
+			tx.Signers = append(tx.Signers, key.Pubkey)
+		}
+	}
+	for _, raw := range result.Transaction.Message.Instructions {
+		instruction := ParsedInstruction{Program: raw.Program, ProgramID: raw.ProgramID}
+		// Memo instructions parse to their text, token instructions to their type and info
+		var parsed struct {
+			Type string          `json:"type"`
+			Info json.RawMessage `json:"info"`
+		}
+		if err := json.Unmarshal(raw.Parsed, &instruction.Memo); err != nil && json.Unmarshal(raw.Parsed, &parsed) == nil {
+			instruction.Type = parsed.Type
+			instruction.Info = parsed.Info
+		}
+		tx.Instructions = append(tx.Instructions, instruction)
+	}
+	return tx, nil
+}
+
+// SignatureStatus is how far the cluster has processed a transaction
+type SignatureStatus struct {
+	Slot uint64 `json:"slot"`
+	// Confirmations is nil once the transaction's block is rooted
+	Confirmations      *uint64         `json:"confirmations"`
+	Err                json.RawMessage `json:"err"`
+	ConfirmationStatus string          `json:"confirmationStatus"`
+}
+
+// Succeeded reports whether the transaction executed without error
+func (s *SignatureStatus) Succeeded() bool {
+	return len(s.Err) == 0 || string(s.Err) == "null"
+}
+
+// GetSignatureStatus returns the status of a transaction the cluster has processed, or ErrNotFound when
+// it has not seen it or it expired before landing
+func (c *Client) GetSignatureStatus(ctx context.Context, signature string) (*SignatureStatus, error) {
+	var result contextValue[[]*SignatureStatus]
+	if err := c.call(ctx, "getSignatureStatuses", &result, []string{signature}, map[string]interface{}{"searchTransactionHistory": true}); err != nil {
+		return nil, err
+	}
+	if result.Value == nil || len(*result.Value) == 0 || (*result.Value)[0] == nil {
+		return nil, ErrNotFound
+	}
+	return (*result.Value)[0], nil
+}
+
+// TokenAccount is an SPL token account's balance and delegate approval
+type TokenAccount struct {
+	Address   string
+	ProgramID string
+	Mint      string
+	Owner     string
+	State     string
+	Amount    uint64
+	Decimals  uint8
+	// Delegate may transfer up to DelegatedAmount from the account on the owner's behalf
+	Delegate        string
+	DelegatedAmount uint64
+}
+
+type tokenAmount struct {
+	Amount   string `json:"amount"`
+	Decimals uint8  `json:"decimals"`
+}
+
+type parsedAccount struct {
+	Owner string `json:"owner"`
+	Data  struct {
+		Program string `json:"program"`
+		Parsed  struct {
+			Type string `json:"type"`
+			Info struct {
+				Mint            string       `json:"mint"`
+				Owner           string       `json:"owner"`
+				State           string       `json:"state"`
+				TokenAmount     tokenAmount  `json:"tokenAmount"`
+				Delegate        string       `json:"delegate"`
+				DelegatedAmount *tokenAmount `json:"delegatedAmount"`
+			} `json:"info"`
+		} `json:"parsed"`
+	} `json:"data"`
+}
+
+// tokenAccount converts a jsonParsed account into a TokenAccount
+func (a *parsedAccount) tokenAccount(address string) (*TokenAccount, error) {
+	if a.Data.Parsed.Type != "account" {
+		return nil, fmt.Errorf("%s is not a token account", address)
+	}
+	info := a.Data.Parsed.Info
+
+	amount, err := strconv.ParseUint(info.TokenAmount.Amount, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token amount for %s: %w", address, err)
+	}
+
+	account := &TokenAccount{
+		Address:   address,
+		ProgramID: a.Owner,
+		Mint:      info.Mint,
+		Owner:     info.Owner,
+		State:     info.State,
+		Amount:    amount,
+		Decimals:  info.TokenAmount.Decimals,
+		Delegate:  info.Delegate,
+	}
+	if info.DelegatedAmount != nil {
+		if account.DelegatedAmount, err = strconv.ParseUint(info.DelegatedAmount.Amount, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid delegated amount for %s: %w", address, err)
+		}
+	}
+	return account, nil
+}
+
+// GetTokenAccount returns an SPL token account, or ErrNotFound when the account does not exist
+func (c *Client) GetTokenAccount(ctx context.Context, address string) (*TokenAccount, error) {
+	var result contextValue[parsedAccount]
+	err := c.call(ctx, "getAccountInfo", &result, address, map[string]interface{}{
+		"commitment": CommitmentConfirmed,
+		"encoding":   "jsonParsed",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Value == nil {
+		return nil, ErrNotFound
+	}
+	return result.Value.tokenAccount(address)
+}
+
+// GetTokenAccountsByOwner returns the accounts an owner holds a mint in
+func (c *Client) GetTokenAccountsByOwner(ctx context.Context, owner, mint string) ([]TokenAccount, error) {
+	var result contextValue[[]struct {
+		Pubkey  string        `json:"pubkey"`
+		Account parsedAccount `json:"account"`
+	}]
+	err := c.call(ctx, "getTokenAccountsByOwner", &result, owner, map[string]string{"mint": mint}, map[string]interface{}{
+		"commitment": CommitmentConfirmed,
+		"encoding":   "jsonParsed",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Value == nil {
+		return nil, nil
+	}
+
+	accounts := make([]TokenAccount, 0, len(*result.Value))
+	for _, entry := range *result.Value {
+		account, err := entry.Account.tokenAccount(entry.Pubkey)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+	return accounts, nil
+}
+
+// SimulationResult is the outcome of running a transaction against the latest state without sending it
+type SimulationResult struct {
+	Err           json.RawMessage `json:"err"`
+	Logs          []string        `json:"logs"`
+	UnitsConsumed uint64          `json:"unitsConsumed"`
+}
+
+// Succeeded reports whether the simulated transaction executed without error
+func (s *SimulationResult) Succeeded() bool {
+	return len(s.Err) == 0 || string(s.Err) == "null"
+}
+
+// SimulateTransaction runs a transaction without sending it. Signatures are not checked and the
+// blockhash is replaced with the latest one, so the transaction may be unsigned.
+func (c *Client) SimulateTransaction(ctx context.Context, tx *Transaction) (*SimulationResult, error) {
+	var result contextValue[SimulationResult]
+	encoded := base64.StdEncoding.EncodeToString(tx.Serialize())
+	err := c.call(ctx, "simulateTransaction", &result, encoded, map[string]interface{}{
+		"commitment":             CommitmentConfirmed,
+		"encoding":               "base64",
+		"sigVerify":              false,
+		"replaceRecentBlockhash": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Value == nil {
+		return nil, ErrNotFound
+	}
+	return result.Value, nil
+}
+
+// SendTransaction submits a signed transaction and returns its signature
+func (c *Client) SendTransaction(ctx context.Context, tx *Transaction) (string, error) {
+	var signature string
+	encoded := base64.StdEncoding.EncodeToString(tx.Serialize())
+	err := c.call(ctx, "sendTransaction", &signature, encoded, map[string]interface{}{
+		"encoding":            "base64",
+		"preflightCommitment": CommitmentConfirmed,
+	})
+	if err != nil {
+		return "", err
+	}
+	return signature, nil
+}
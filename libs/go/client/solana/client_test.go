@@ -0,0 +1,257 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	customerAddress      = "4GfkX2JkJmHVoSaJRMeRo97rkGjHMd6CHw9aXt2uPk5X"
+	merchantAddress      = "DtvyPCcVE9mQXNR4LBX9fQgrrzT9hzsF6YCDvhLMGnRG"
+	delegateAddress      = "UtHvQFRZn2TuLrsUfS3h92fPWWJzhLQov92L321ZctB"
+	customerTokenAccount = "EWPRzu9UWUvcrmdc4DzU4E6jgYsegPz1qQttjT9HRDwt"
+	merchantTokenAccount = "ANJoyyXc4G3UJXbHuPZE2c3Hbd94jgVihDKgD2cb1MCt"
+	devnetUSDCMint       = "4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDU"
+	transferSignature    = "5xXmhPX8jHDqCeEuAyzT7TAQKNhroyiFNMjzKyiyTYuY36g1TU7JUrfJbhMzx1D4UK4rSxe2FdLpZf7kNyCtjsXG"
+	approvalSignature    = "3nYbN6o3pA9CkwVnNwR1dZsKzYQd7vTqLbMqy8zfKQ3dHh1pLgE9wH4y5rWmVx2eUcX7sPq8JtN6fRk2GzAaBmCd"
+)
+
+func init() {
+	logger.InitLogger("test")
+}
+
+// newFixtureServer stands in for an RPC node, answering each method with the response recorded in
+// testdata/<method>.json unless fixtures names another file for it
+func newFixtureServer(t *testing.T, fixtures map[string]string, requests *[]rpcRequest) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "2.0", req.JSONRPC)
+		if requests != nil {
+			*requests = append(*requests, req)
+		}
+
+		name := req.Method
+		if fixture, ok := fixtures[req.Method]; ok {
+			name = fixture
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_GetTransaction(t *testing.T) {
+	t.Run("confirmed transfer", func(t *testing.T) {
+		var requests []rpcRequest
+		server := newFixtureServer(t, nil, &requests)
+
+		tx, err := NewClient(server.URL).GetTransaction(context.Background(), transferSignature)
+		require.NoError(t, err)
+
+		assert.True(t, tx.Succeeded())
+		assert.Equal(t, uint64(312456789), tx.Slot)
+		assert.Equal(t, int64(1773489600), tx.BlockTime)
+		assert.Equal(t, delegateAddress, tx.FeePayer)
+		assert.Equal(t, uint64(5000), tx.Fee)
+		assert.Equal(t, uint64(6200), tx.ComputeUnitsConsumed)
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "getTransaction", requests[0].Method)
+		assert.Equal(t, transferSignature, requests[0].Params[0])
+		assert.Equal(t, "jsonParsed", requests[0].Params[1].(map[string]interface{})["encoding"])
+	})
+
+	t.Run("approval with a memo", func(t *testing.T) {
+		server := newFixtureServer(t, map[string]string{"getTransaction": "getTransaction_approve"}, nil)
+
+		tx, err := NewClient(server.URL).GetTransaction(context.Background(), approvalSignature)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{customerAddress}, tx.Signers)
+		require.Len(t, tx.Instructions, 2)
+		assert.Equal(t, "spl-token", tx.Instructions[0].Program)
+		assert.Equal(t, "approveChecked", tx.Instructions[0].Type)
+		assert.Contains(t, string(tx.Instructions[0].Info), customerTokenAccount)
+		assert.Empty(t, tx.Instructions[0].Memo)
+		assert.Equal(t, "spl-memo", tx.Instructions[1].Program)
+		assert.Equal(t, "cyphera:3f9a1c52-8b7e-4d21-9c6a-0e5f7d2b4a18", tx.Instructions[1].Memo)
+		assert.Empty(t, tx.Instructions[1].Type)
+	})
+
+	t.Run("failed transfer", func(t *testing.T) {
+		server := newFixtureServer(t, map[string]string{"getTransaction": "getTransaction_failed"}, nil)
+
+		tx, err := NewClient(server.URL).GetTransaction(context.Background(), transferSignature)
+		require.NoError(t, err)
+
+		assert.False(t, tx.Succeeded())
+		assert.JSONEq(t, `{"InstructionError": [0, {"Custom": 1}]}`, string(tx.Err))
+		assert.Equal(t, uint64(5000), tx.Fee)
+	})
+
+	t.Run("unknown signature", func(t *testing.T) {
+		server := newFixtureServer(t, map[string]string{"getTransaction": "getTransaction_missing"}, nil)
+
+		_, err := NewClient(server.URL).GetTransaction(context.Background(), transferSignature)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestClient_GetSignatureStatus(t *testing.T) {
+	t.Run("processed transaction", func(t *testing.T) {
+		server := newFixtureServer(t, nil, nil)
+
+		status, err := NewClient(server.URL).GetSignatureStatus(context.Background(), transferSignature)
+		require.NoError(t, err)
+
+		assert.True(t, status.Succeeded())
+		assert.Equal(t, uint64(312456789), status.Slot)
+		assert.Equal(t, "confirmed", status.ConfirmationStatus)
+		require.NotNil(t, status.Confirmations)
+		assert.Equal(t, uint64(12), *status.Confirmations)
+	})
+
+	t.Run("expired transaction", func(t *testing.T) {
+		server := newFixtureServer(t, map[string]string{"getSignatureStatuses": "getSignatureStatuses_missing"}, nil)
+
+		_, err := NewClient(server.URL).GetSignatureStatus(context.Background(), transferSignature)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestClient_GetTokenAccount(t *testing.T) {
+	t.Run("approved delegate", func(t *testing.T) {
+		server := newFixtureServer(t, nil, nil)
+
+		account, err := NewClient(server.URL).GetTokenAccount(context.Background(), customerTokenAccount)
+		require.NoError(t, err)
+
+		assert.Equal(t, customerTokenAccount, account.Address)
+		assert.Equal(t, TokenProgramID.String(), account.ProgramID)
+		assert.Equal(t, devnetUSDCMint, account.Mint)
+		assert.Equal(t, customerAddress, account.Owner)
+		assert.Equal(t, "initialized", account.State)
+		assert.Equal(t, uint64(250000000), account.Amount)
+		assert.Equal(t, uint8(6), account.Decimals)
+		assert.Equal(t, delegateAddress, account.Delegate)
+		assert.Equal(t, uint64(180000000), account.DelegatedAmount)
+	})
+
+	t.Run("closed account", func(t *testing.T) {
+		server := newFixtureServer(t, map[string]string{"getAccountInfo": "getAccountInfo_missing"}, nil)
+
+		_, err := NewClient(server.URL).GetTokenAccount(context.Background(), customerTokenAccount)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestClient_GetTokenAccountsByOwner(t *testing.T) {
+	var requests []rpcRequest
+	server := newFixtureServer(t, nil, &requests)
+
+	accounts, err := NewClient(server.URL).GetTokenAccountsByOwner(context.Background(), merchantAddress, devnetUSDCMint)
+	require.NoError(t, err)
+
+	require.Len(t, accounts, 1)
+	assert.Equal(t, merchantTokenAccount, accounts[0].Address)
+	assert.Equal(t, merchantAddress, accounts[0].Owner)
+	assert.Equal(t, uint64(1045000000), accounts[0].Amount)
+	assert.Empty(t, accounts[0].Delegate)
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, map[string]interface{}{"mint": devnetUSDCMint}, requests[0].Params[1])
+}
+
+func TestClient_Blocks(t *testing.T) {
+	server := newFixtureServer(t, nil, nil)
+	client := NewClient(server.URL)
+
+	slot, err := client.GetSlot(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(312456830), slot)
+
+	blockhash, err := client.GetBlockhash(context.Background(), 312456789)
+	require.NoError(t, err)
+	assert.Equal(t, "BK38Hn8DEtwrz7KNGGysV9z91kGQjS7KFgDNMyxf9sG4", blockhash)
+
+	latest, err := client.GetLatestBlockhash(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "6oYGZKMGuE2TSgMxEMytSsD3fArnzpgvUnuU2JkB4g8H", latest.Blockhash.String())
+	assert.Equal(t, uint64(290113467), latest.LastValidBlockHeight)
+
+	balance, err := client.GetBalance(context.Background(), delegateAddress)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(994995000), balance)
+
+	t.Run("skipped slot", func(t *testing.T) {
+		server := newFixtureServer(t, map[string]string{"getBlock": "getBlock_skipped"}, nil)
+
+		_, err := NewClient(server.URL).GetBlockhash(context.Background(), 312456790)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestClient_Transactions(t *testing.T) {
+	delegate := NewKeypairFromSeed(seed("delegate"))
+	transfer := NewTransferCheckedInstruction(TokenProgramID, MustParsePublicKey(customerTokenAccount), MustParsePublicKey(devnetUSDCMint),
+		MustParsePublicKey(merchantTokenAccount), delegate.PublicKey(), 15000000, 6)
+	tx, err := NewTransaction(delegate.PublicKey(), MustParsePublicKey("6oYGZKMGuE2TSgMxEMytSsD3fArnzpgvUnuU2JkB4g8H"), transfer)
+	require.NoError(t, err)
+
+	t.Run("fee", func(t *testing.T) {
+		server := newFixtureServer(t, nil, nil)
+
+		fee, err := NewClient(server.URL).GetFeeForMessage(context.Background(), tx.Message)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5000), fee)
+	})
+
+	t.Run("simulation that would fail", func(t *testing.T) {
+		server := newFixtureServer(t, map[string]string{"simulateTransaction": "simulateTransaction_failed"}, nil)
+
+		result, err := NewClient(server.URL).SimulateTransaction(context.Background(), tx)
+		require.NoError(t, err)
+		assert.False(t, result.Succeeded())
+		assert.Contains(t, result.Logs, "Program log: Error: insufficient funds")
+	})
+
+	t.Run("send", func(t *testing.T) {
+		require.NoError(t, tx.Sign(delegate))
+		var requests []rpcRequest
+		server := newFixtureServer(t, nil, &requests)
+
+		signature, err := NewClient(server.URL).SendTransaction(context.Background(), tx)
+		require.NoError(t, err)
+		assert.Equal(t, transferSignature, signature)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "base64", requests[0].Params[1].(map[string]interface{})["encoding"])
+	})
+
+	t.Run("send with an expired blockhash", func(t *testing.T) {
+		server := newFixtureServer(t, map[string]string{"sendTransaction": "sendTransaction_blockhash_expired"}, nil)
+
+		_, err := NewClient(server.URL).SendTransaction(context.Background(), tx)
+		var rpcErr *RPCError
+		require.True(t, errors.As(err, &rpcErr))
+		assert.Equal(t, -32002, rpcErr.Code)
+		assert.Contains(t, rpcErr.Message, "Blockhash not found")
+	})
+}
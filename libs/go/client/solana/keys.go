@@ -0,0 +1,119 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PublicKeyLength is the length in bytes of a Solana account address
+const PublicKeyLength = 32
+
+// PublicKey is a Solana account address
+type PublicKey [PublicKeyLength]byte
+
+// Programs the payment flow builds instructions for or reads accounts of
+var (
+	TokenProgramID     = MustParsePublicKey("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	Token2022ProgramID = MustParsePublicKey("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
+)
+
+// ParsePublicKey parses a base58 account address
+func ParsePublicKey(address string) (PublicKey, error) {
+	var key PublicKey
+	decoded, err := DecodeBase58(strings.TrimSpace(address))
+	if err != nil {
+		return key, fmt.Errorf("invalid Solana address %q: %w", address, err)
+	}
+	if len(decoded) != PublicKeyLength {
+		return key, fmt.Errorf("invalid Solana address %q: decodes to %d bytes, want %d", address, len(decoded), PublicKeyLength)
+	}
+	copy(key[:], decoded)
+	return key, nil
+}
+
+// MustParsePublicKey parses a base58 account address known to be valid
+func MustParsePublicKey(address string) PublicKey {
+	key, err := ParsePublicKey(address)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// IsValidAddress reports whether address is a base58 encoded 32-byte Solana address
+func IsValidAddress(address string) bool {
+	_, err := ParsePublicKey(address)
+	return err == nil
+}
+
+// String returns the base58 encoding of the key
+func (k PublicKey) String() string {
+	return EncodeBase58(k[:])
+}
+
+// IsZero reports whether the key is unset
+func (k PublicKey) IsZero() bool {
+	return k == PublicKey{}
+}
+
+// Keypair signs transactions for an account whose private key the service holds
+type Keypair struct {
+	privateKey ed25519.PrivateKey
+}
+
+// ParseKeypair parses a secret key either as base58, as wallets export it, or as the JSON byte array
+// solana-keygen writes
+func ParseKeypair(secret string) (*Keypair, error) {
+	secret = strings.TrimSpace(secret)
+
+	var raw []byte
+	if strings.HasPrefix(secret, "[") {
+		var values []int
+		if err := json.Unmarshal([]byte(secret), &values); err != nil {
+			return nil, fmt.Errorf("invalid Solana keypair: %w", err)
+		}
+		raw = make([]byte, len(values))
+		for i, value := range values {
+			if value < 0 || value > 255 {
+				return nil, fmt.Errorf("invalid Solana keypair: byte %d out of range", i)
+			}
+			raw[i] = byte(value)
+		}
+	} else {
+		decoded, err := DecodeBase58(secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Solana keypair: %w", err)
+		}
+		raw = decoded
+	}
+
+	if len(raw) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid Solana keypair: %d bytes, want %d", len(raw), ed25519.PrivateKeySize)
+	}
+
+	privateKey := ed25519.PrivateKey(raw)
+	derived := ed25519.NewKeyFromSeed(privateKey.Seed())
+	if !derived.Equal(privateKey) {
+		return nil, fmt.Errorf("invalid Solana keypair: public key does not match secret key")
+	}
+	return &Keypair{privateKey: privateKey}, nil
+}
+
+// NewKeypairFromSeed derives a keypair from a 32-byte seed
+func NewKeypairFromSeed(seed []byte) *Keypair {
+	return &Keypair{privateKey: ed25519.NewKeyFromSeed(seed)}
+}
+
+// PublicKey returns the keypair's account address
+func (k *Keypair) PublicKey() PublicKey {
+	var key PublicKey
+	copy(key[:], k.privateKey.Public().(ed25519.PublicKey))
+	return key
+}
+
+// Sign signs a serialized message
+func (k *Keypair) Sign(message []byte) []byte {
+	return ed25519.Sign(k.privateKey, message)
+}
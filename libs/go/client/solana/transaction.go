@@ -0,0 +1,233 @@
+package solana
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// SPL Token instruction indexes
+const (
+	tokenInstructionTransferChecked = 12
+)
+
+// AccountMeta is an account an instruction reads or writes
+type AccountMeta struct {
+	PublicKey  PublicKey
+	IsSigner   bool
+	IsWritable bool
+}
+
+// Instruction is a single program call in a transaction
+type Instruction struct {
+	ProgramID PublicKey
+	Accounts  []AccountMeta
+	Data      []byte
+}
+
+// NewTransferCheckedInstruction transfers amount base units of mint from source to destination. The
+// authority may be the source account's owner or a delegate the owner approved for at least amount.
+func NewTransferCheckedInstruction(tokenProgram, source, mint, destination, authority PublicKey, amount uint64, decimals uint8) Instruction {
+	data := make([]byte, 10)
+	data[0] = tokenInstructionTransferChecked
+	binary.LittleEndian.PutUint64(data[1:9], amount)
+	data[9] = decimals
+
+	return Instruction{
+		ProgramID: tokenProgram,
+		Accounts: []AccountMeta{
+			{PublicKey: source, IsWritable: true},
+			{PublicKey: mint},
+			{PublicKey: destination, IsWritable: true},
+			{PublicKey: authority, IsSigner: true},
+		},
+		Data: data,
+	}
+}
+
+// MessageHeader counts the signing and read-only accounts at the front and back of a message's account keys
+type MessageHeader struct {
+	NumRequiredSignatures       uint8
+	NumReadonlySignedAccounts   uint8
+	NumReadonlyUnsignedAccounts uint8
+}
+
+// CompiledInstruction is an instruction whose accounts are indexes into the message's account keys
+type CompiledInstruction struct {
+	ProgramIDIndex uint8
+	Accounts       []uint8
+	Data           []byte
+}
+
+// Message is the signed part of a legacy transaction
+type Message struct {
+	Header          MessageHeader
+	AccountKeys     []PublicKey
+	RecentBlockhash PublicKey
+	Instructions    []CompiledInstruction
+}
+
+// Transaction is a legacy transaction and the signatures of its signing accounts
+type Transaction struct {
+	Signatures [][]byte
+	Message    Message
+}
+
+// NewTransaction compiles instructions into an unsigned transaction paid for by feePayer. recentBlockhash
+// bounds how long the network accepts the transaction.
+func NewTransaction(feePayer PublicKey, recentBlockhash PublicKey, instructions ...Instruction) (*Transaction, error) {
+	if len(instructions) == 0 {
+		return nil, fmt.Errorf("transaction has no instructions")
+	}
+
+	// Merge every account's flags, keeping the order accounts first appear in with the fee payer first
+	order := []PublicKey{feePayer}
+	metas := map[PublicKey]*AccountMeta{feePayer: {PublicKey: feePayer, IsSigner: true, IsWritable: true}}
+	add := func(meta AccountMeta) {
+		existing, ok := metas[meta.PublicKey]
+		if !ok {
+			copied := meta
+			metas[meta.PublicKey] = &copied
+			order = append(order, meta.PublicKey)
+			return
+		}
+		existing.IsSigner = existing.IsSigner || meta.IsSigner
+		existing.IsWritable = existing.IsWritable || meta.IsWritable
+	}
+	for _, instruction := range instructions {
+		for _, account := range instruction.Accounts {
+			add(account)
+		}
+		add(AccountMeta{PublicKey: instruction.ProgramID})
+	}
+
+	// Accounts are laid out as writable signers, read-only signers, writable non-signers, then read-only
+	// non-signers
+	var keys []PublicKey
+	var header MessageHeader
+	for _, group := range []struct{ signer, writable bool }{{true, true}, {true, false}, {false, true}, {false, false}} {
+		for _, key := range order {
+			meta := metas[key]
+			if meta.IsSigner != group.signer || meta.IsWritable != group.writable {
+				continue
+			}
+			keys = append(keys, key)
+			switch {
+			case group.signer && group.writable:
+				header.NumRequiredSignatures++
+			case group.signer:
+				header.NumRequiredSignatures++
+				header.NumReadonlySignedAccounts++
+			case !group.writable:
+				header.NumReadonlyUnsignedAccounts++
+			}
+		}
+	}
+	if len(keys) > 256 {
+		return nil, fmt.Errorf("transaction references %d accounts, more than a legacy message allows", len(keys))
+	}
+
+	index := make(map[PublicKey]uint8, len(keys))
+	for i, key := range keys {
+		index[key] = uint8(i)
+	}
+
+	compiled := make([]CompiledInstruction, len(instructions))
+	for i, instruction := range instructions {
+		accounts := make([]uint8, len(instruction.Accounts))
+		for j, account := range instruction.Accounts {
+			accounts[j] = index[account.PublicKey]
+		}
+		compiled[i] = CompiledInstruction{
+			ProgramIDIndex: index[instruction.ProgramID],
+			Accounts:       accounts,
+			Data:           instruction.Data,
+		}
+	}
+
+	return &Transaction{
+		Signatures: make([][]byte, header.NumRequiredSignatures),
+		Message: Message{
+			Header:          header,
+			AccountKeys:     keys,
+			RecentBlockhash: recentBlockhash,
+			Instructions:    compiled,
+		},
+	}, nil
+}
+
+// Serialize encodes the message in the wire format signatures are made over
+func (m Message) Serialize() []byte {
+	buf := []byte{m.Header.NumRequiredSignatures, m.Header.NumReadonlySignedAccounts, m.Header.NumReadonlyUnsignedAccounts}
+	buf = appendCompactU16(buf, len(m.AccountKeys))
+	for _, key := range m.AccountKeys {
+		buf = append(buf, key[:]...)
+	}
+	buf = append(buf, m.RecentBlockhash[:]...)
+	buf = appendCompactU16(buf, len(m.Instructions))
+	for _, instruction := range m.Instructions {
+		buf = append(buf, instruction.ProgramIDIndex)
+		buf = appendCompactU16(buf, len(instruction.Accounts))
+		buf = append(buf, instruction.Accounts...)
+		buf = appendCompactU16(buf, len(instruction.Data))
+		buf = append(buf, instruction.Data...)
+	}
+	return buf
+}
+
+// Sign signs the transaction with each of its signing accounts' keypairs
+func (tx *Transaction) Sign(signers ...*Keypair) error {
+	message := tx.Message.Serialize()
+	for _, signer := range signers {
+		key := signer.PublicKey()
+		position := -1
+		for i := 0; i < int(tx.Message.Header.NumRequiredSignatures); i++ {
+			if tx.Message.AccountKeys[i] == key {
+				position = i
+				break
+			}
+		}
+		if position < 0 {
+			return fmt.Errorf("%s is not a signer of the transaction", key)
+		}
+		tx.Signatures[position] = signer.Sign(message)
+	}
+
+	for i, signature := range tx.Signatures {
+		if len(signature) == 0 {
+			return fmt.Errorf("transaction is missing the signature of %s", tx.Message.AccountKeys[i])
+		}
+	}
+	return nil
+}
+
+// Signature returns the fee payer's signature, which identifies the transaction on the network
+func (tx *Transaction) Signature() string {
+	if len(tx.Signatures) == 0 || len(tx.Signatures[0]) == 0 {
+		return ""
+	}
+	return EncodeBase58(tx.Signatures[0])
+}
+
+// Serialize encodes the signed transaction in the wire format sendTransaction accepts
+func (tx *Transaction) Serialize() []byte {
+	buf := appendCompactU16(nil, len(tx.Signatures))
+	for _, signature := range tx.Signatures {
+		if len(signature) == 0 {
+			signature = make([]byte, 64)
+		}
+		buf = append(buf, signature...)
+	}
+	return append(buf, tx.Message.Serialize()...)
+}
+
+// appendCompactU16 appends a length in Solana's compact-u16 encoding
+func appendCompactU16(buf []byte, value int) []byte {
+	for {
+		b := byte(value & 0x7f)
+		value >>= 7
+		if value == 0 {
+			return append(buf, b)
+		}
+		buf = append(buf, b|0x80)
+	}
+}
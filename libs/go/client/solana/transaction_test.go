@@ -0,0 +1,110 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seed pads a label into a deterministic 32-byte keypair seed
+func seed(label string) []byte {
+	s := make([]byte, 32)
+	copy(s, label)
+	return s
+}
+
+func TestBase58(t *testing.T) {
+	for _, data := range [][]byte{{}, {0}, {0, 0, 1}, []byte("hello world"), TokenProgramID[:]} {
+		decoded, err := DecodeBase58(EncodeBase58(data))
+		require.NoError(t, err)
+		assert.Equal(t, data, decoded)
+	}
+
+	assert.Equal(t, "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA", TokenProgramID.String())
+
+	_, err := DecodeBase58("0OIl")
+	assert.Error(t, err)
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key, err := ParsePublicKey(" " + delegateAddress + " ")
+	require.NoError(t, err)
+	assert.Equal(t, delegateAddress, key.String())
+
+	assert.True(t, IsValidAddress(devnetUSDCMint))
+	assert.False(t, IsValidAddress("0x1c7D4B196Cb0C7B01d743Fbc6116a902379C7238"))
+	assert.False(t, IsValidAddress("3yZe7d"))
+}
+
+func TestParseKeypair(t *testing.T) {
+	keypair := NewKeypairFromSeed(seed("delegate"))
+	secret := []byte(keypair.privateKey)
+
+	fromBase58, err := ParseKeypair(EncodeBase58(secret))
+	require.NoError(t, err)
+	assert.Equal(t, delegateAddress, fromBase58.PublicKey().String())
+
+	array := "["
+	for i, b := range secret {
+		if i > 0 {
+			array += ","
+		}
+		array += strconv.Itoa(int(b))
+	}
+	fromArray, err := ParseKeypair(array + "]")
+	require.NoError(t, err)
+	assert.Equal(t, delegateAddress, fromArray.PublicKey().String())
+
+	tampered := append([]byte{}, secret...)
+	tampered[40] ^= 0xff
+	_, err = ParseKeypair(EncodeBase58(tampered))
+	assert.Error(t, err)
+
+	_, err = ParseKeypair(EncodeBase58(secret[:32]))
+	assert.Error(t, err)
+}
+
+func TestNewTransaction_TransferChecked(t *testing.T) {
+	delegate := NewKeypairFromSeed(seed("delegate"))
+	source := MustParsePublicKey(customerTokenAccount)
+	destination := MustParsePublicKey(merchantTokenAccount)
+	mint := MustParsePublicKey(devnetUSDCMint)
+	blockhash := MustParsePublicKey("6oYGZKMGuE2TSgMxEMytSsD3fArnzpgvUnuU2JkB4g8H")
+
+	tx, err := NewTransaction(delegate.PublicKey(), blockhash,
+		NewTransferCheckedInstruction(TokenProgramID, source, mint, destination, delegate.PublicKey(), 15000000, 6))
+	require.NoError(t, err)
+
+	// The delegate pays the fee and signs for the transfer, the token accounts are written, and the mint
+	// and token program are only read
+	assert.Equal(t, MessageHeader{NumRequiredSignatures: 1, NumReadonlySignedAccounts: 0, NumReadonlyUnsignedAccounts: 2}, tx.Message.Header)
+	assert.Equal(t, []PublicKey{delegate.PublicKey(), source, destination, mint, TokenProgramID}, tx.Message.AccountKeys)
+
+	require.Len(t, tx.Message.Instructions, 1)
+	instruction := tx.Message.Instructions[0]
+	assert.Equal(t, uint8(4), instruction.ProgramIDIndex)
+	assert.Equal(t, []uint8{1, 3, 2, 0}, instruction.Accounts)
+	require.Len(t, instruction.Data, 10)
+	assert.Equal(t, byte(12), instruction.Data[0])
+	assert.Equal(t, uint64(15000000), binary.LittleEndian.Uint64(instruction.Data[1:9]))
+	assert.Equal(t, byte(6), instruction.Data[9])
+
+	assert.Empty(t, tx.Signature())
+	assert.Error(t, tx.Sign(NewKeypairFromSeed(seed("customer"))))
+	require.NoError(t, tx.Sign(delegate))
+
+	message := tx.Message.Serialize()
+	publicKey := delegate.PublicKey()
+	assert.True(t, ed25519.Verify(publicKey[:], message, tx.Signatures[0]))
+	assert.Equal(t, EncodeBase58(tx.Signatures[0]), tx.Signature())
+
+	wire := tx.Serialize()
+	assert.Equal(t, byte(1), wire[0])
+	assert.Equal(t, message, wire[1+64:])
+	// 3 header bytes, 5 keys, a blockhash and one 4-account instruction with 10 bytes of data
+	assert.Len(t, message, 3+1+5*32+32+1+1+1+4+1+10)
+}
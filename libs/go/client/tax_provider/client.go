@@ -0,0 +1,362 @@
+package tax_provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	httpClient "github.com/cyphera/cyphera-api/libs/go/client/http"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+)
+
+const (
+	// ProviderName identifies calculations made by this adapter
+	ProviderName = "taxjar"
+
+	defaultBaseURL = "https://api.taxjar.com"
+	defaultTimeout = 5 * time.Second
+
+	// digitalGoodsTaxCode is the provider's product tax code for digital goods and SaaS
+	digitalGoodsTaxCode = "31000"
+)
+
+// ErrUnavailable is returned when the provider cannot be reached or fails on its side.
+// Callers are expected to fall back to another tax engine.
+var ErrUnavailable = errors.New("tax provider unavailable")
+
+// Client calculates sales tax and VAT through a TaxJar-compatible API
+type Client struct {
+	apiKey     string
+	httpClient *httpClient.HTTPClient
+}
+
+// NewClient creates a tax provider client. An empty baseURL uses the provider's production API.
+func NewClient(apiKey, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	// Invoices wait on the calculation, so fail fast and let the caller fall back
+	// instead of retrying an outage.
+	return &Client{
+		apiKey: apiKey,
+		httpClient: httpClient.NewHTTPClient(
+			httpClient.WithBaseURL(baseURL),
+			httpClient.WithTimeout(defaultTimeout),
+			httpClient.WithRetryConfig(&httpClient.RetryConfig{MaxRetries: 0}),
+		),
+	}
+}
+
+// --- API request and response structs ---
+
+type lineItem struct {
+	ID             string  `json:"id"`
+	Quantity       int     `json:"quantity"`
+	ProductTaxCode string  `json:"product_tax_code,omitempty"`
+	UnitPrice      float64 `json:"unit_price"`
+	SalesTax       float64 `json:"sales_tax,omitempty"`
+}
+
+type taxRequest struct {
+	FromCountry string     `json:"from_country,omitempty"`
+	FromZip     string     `json:"from_zip,omitempty"`
+	FromState   string     `json:"from_state,omitempty"`
+	FromCity    string     `json:"from_city,omitempty"`
+	FromStreet  string     `json:"from_street,omitempty"`
+	ToCountry   string     `json:"to_country"`
+	ToZip       string     `json:"to_zip,omitempty"`
+	ToState     string     `json:"to_state,omitempty"`
+	ToCity      string     `json:"to_city,omitempty"`
+	ToStreet    string     `json:"to_street,omitempty"`
+	Amount      float64    `json:"amount"`
+	Shipping    float64    `json:"shipping"`
+	LineItems   []lineItem `json:"line_items"`
+}
+
+type orderRequest struct {
+	TransactionID   string     `json:"transaction_id"`
+	TransactionDate string     `json:"transaction_date"`
+	ToCountry       string     `json:"to_country"`
+	ToZip           string     `json:"to_zip,omitempty"`
+	ToState         string     `json:"to_state,omitempty"`
+	ToCity          string     `json:"to_city,omitempty"`
+	ToStreet        string     `json:"to_street,omitempty"`
+	Amount          float64    `json:"amount"`
+	Shipping        float64    `json:"shipping"`
+	SalesTax        float64    `json:"sales_tax"`
+	LineItems       []lineItem `json:"line_items"`
+}
+
+type taxBreakdown struct {
+	StateTaxRate                  float64 `json:"state_tax_rate"`
+	StateTaxCollectable           float64 `json:"state_tax_collectable"`
+	CountyTaxRate                 float64 `json:"county_tax_rate"`
+	CountyTaxCollectable          float64 `json:"county_tax_collectable"`
+	CityTaxRate                   float64 `json:"city_tax_rate"`
+	CityTaxCollectable            float64 `json:"city_tax_collectable"`
+	SpecialDistrictTaxRate        float64 `json:"special_tax_rate"`
+	SpecialDistrictTaxCollectable float64 `json:"special_district_tax_collectable"`
+	CountryTaxRate                float64 `json:"country_tax_rate"`
+	CountryTaxCollectable         float64 `json:"country_tax_collectable"`
+	GSTRate                       float64 `json:"gst"`
+	GSTCollectable                float64 `json:"gst_collectable"`
+	PSTRate                       float64 `json:"pst"`
+	PSTCollectable                float64 `json:"pst_collectable"`
+}
+
+type taxJurisdictions struct {
+	Country string `json:"country"`
+	State   string `json:"state"`
+	County  string `json:"county"`
+	City    string `json:"city"`
+}
+
+type taxResult struct {
+	OrderTotalAmount float64           `json:"order_total_amount"`
+	TaxableAmount    float64           `json:"taxable_amount"`
+	AmountToCollect  float64           `json:"amount_to_collect"`
+	Rate             float64           `json:"rate"`
+	HasNexus         bool              `json:"has_nexus"`
+	TaxSource        string            `json:"tax_source"`
+	Jurisdictions    *taxJurisdictions `json:"jurisdictions"`
+	Breakdown        *taxBreakdown     `json:"breakdown"`
+}
+
+type taxResponse struct {
+	Tax taxResult `json:"tax"`
+}
+
+type orderResponse struct {
+	Order struct {
+		TransactionID string `json:"transaction_id"`
+	} `json:"order"`
+}
+
+// Name returns the provider name recorded on calculations and invoices
+func (c *Client) Name() string {
+	return ProviderName
+}
+
+// CalculateTax asks the provider for the tax on a transaction. It only quotes: nothing is recorded
+// with the provider until CommitTransaction is called for the document the tax ends up on.
+func (c *Client) CalculateTax(ctx context.Context, params params.TaxCalculationParams) (*responses.TaxCalculationResult, error) {
+	req, err := newTaxRequest(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var taxResp taxResponse
+	if err := c.post(ctx, "/v2/taxes", req, &taxResp); err != nil {
+		return nil, err
+	}
+	return c.toResult(params, taxResp.Tax), nil
+}
+
+// CommitTransaction records a calculation with the provider as an order under the params'
+// transaction reference, once the invoice it was made for exists, and returns the provider's
+// transaction ID
+func (c *Client) CommitTransaction(ctx context.Context, params params.TaxCalculationParams, calculation *responses.TaxCalculationResult) (string, error) {
+	if params.TransactionReference == "" {
+		return "", fmt.Errorf("tax provider transactions require a transaction reference")
+	}
+	req, err := newTaxRequest(params)
+	if err != nil {
+		return "", err
+	}
+
+	item := req.LineItems[0]
+	item.SalesTax = centsToAmount(calculation.TotalTaxCents)
+	order := orderRequest{
+		TransactionID:   params.TransactionReference,
+		TransactionDate: calculation.CalculatedAt.Format(time.RFC3339),
+		ToCountry:       req.ToCountry,
+		ToZip:           req.ToZip,
+		ToState:         req.ToState,
+		ToCity:          req.ToCity,
+		ToStreet:        req.ToStreet,
+		Amount:          req.Amount,
+		SalesTax:        item.SalesTax,
+		LineItems:       []lineItem{item},
+	}
+
+	var orderResp orderResponse
+	if err := c.post(ctx, "/v2/transactions/orders", order, &orderResp); err != nil {
+		return "", err
+	}
+	return orderResp.Order.TransactionID, nil
+}
+
+// newTaxRequest describes a transaction to the provider, shipped to the customer's address or,
+// without one, the business's
+func newTaxRequest(params params.TaxCalculationParams) (taxRequest, error) {
+	to := params.CustomerAddress
+	if to == nil {
+		to = params.BusinessAddress
+	}
+	if to == nil {
+		return taxRequest{}, fmt.Errorf("tax provider requires a customer or business address")
+	}
+
+	item := lineItem{
+		ID:             "1",
+		Quantity:       1,
+		ProductTaxCode: productTaxCode(params.ProductType),
+		UnitPrice:      centsToAmount(params.AmountCents),
+	}
+	req := taxRequest{
+		ToCountry: strings.ToUpper(to.Country),
+		ToZip:     to.PostalCode,
+		ToState:   strings.ToUpper(to.State),
+		ToCity:    to.City,
+		ToStreet:  to.Street1,
+		Amount:    item.UnitPrice,
+		LineItems: []lineItem{item},
+	}
+	if from := params.BusinessAddress; from != nil {
+		req.FromCountry = strings.ToUpper(from.Country)
+		req.FromZip = from.PostalCode
+		req.FromState = strings.ToUpper(from.State)
+		req.FromCity = from.City
+		req.FromStreet = from.Street1
+	}
+	return req, nil
+}
+
+// post sends a JSON request and decodes the response, marking outages with ErrUnavailable
+func (c *Client) post(ctx context.Context, path string, body interface{}, target interface{}) error {
+	resp, err := c.httpClient.Post(ctx, path, body, httpClient.WithBearerToken(c.apiKey))
+	if err != nil {
+		var httpErr *httpClient.HTTPError
+		if errors.As(err, &httpErr) {
+			if httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests {
+				return fmt.Errorf("%w: %v", ErrUnavailable, err)
+			}
+			return fmt.Errorf("tax provider rejected request: %w", err)
+		}
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	if err := c.httpClient.ProcessJSONResponse(resp, target); err != nil {
+		return fmt.Errorf("%w: failed to decode response: %v", ErrUnavailable, err)
+	}
+	return nil
+}
+
+// toResult converts a provider response into a calculation result
+func (c *Client) toResult(params params.TaxCalculationParams, tax taxResult) *responses.TaxCalculationResult {
+	totalTax := amountToCents(tax.AmountToCollect)
+	taxType := "sales"
+	if tax.Breakdown != nil && tax.Breakdown.CountryTaxCollectable > 0 {
+		taxType = "vat"
+	}
+	if tax.Breakdown != nil && tax.Breakdown.GSTCollectable > 0 {
+		taxType = "gst"
+	}
+
+	country, state := "", ""
+	if tax.Jurisdictions != nil {
+		country = strings.ToUpper(tax.Jurisdictions.Country)
+		state = strings.ToUpper(tax.Jurisdictions.State)
+	}
+	code := country
+	if state != "" {
+		code = country + "-" + state
+	}
+
+	result := &responses.TaxCalculationResult{
+		SubtotalCents:    params.AmountCents,
+		TotalTaxCents:    totalTax,
+		TotalAmountCents: params.AmountCents + totalTax,
+		TaxBreakdown:     []business.TaxLineItem{},
+		CalculatedAt:     time.Now(),
+		Confidence:       1.0,
+		Provider:         ProviderName,
+		AuditTrail: business.TaxAuditTrail{
+			RulesVersion:     ProviderName,
+			DetectedLocation: params.CustomerAddress,
+			AppliedRules:     []string{},
+			Notes:            []string{},
+		},
+	}
+	if code != "" {
+		result.AppliedJurisdictions = []string{code}
+	}
+	if !tax.HasNexus {
+		result.AuditTrail.Notes = append(result.AuditTrail.Notes, "No nexus in destination jurisdiction")
+	}
+
+	taxable := amountToCents(tax.TaxableAmount)
+	add := func(jurisdiction, description string, rate, collectable float64) {
+		if collectable == 0 {
+			return
+		}
+		result.TaxBreakdown = append(result.TaxBreakdown, business.TaxLineItem{
+			TaxType:        taxType,
+			Jurisdiction:   jurisdiction,
+			Rate:           rate,
+			TaxableAmount:  taxable,
+			TaxAmountCents: amountToCents(collectable),
+			Description:    description,
+		})
+		result.AuditTrail.AppliedRules = append(result.AuditTrail.AppliedRules, fmt.Sprintf("%s: %.4f%%", jurisdiction, rate*100))
+	}
+
+	if b := tax.Breakdown; b != nil {
+		add(code, "State tax", b.StateTaxRate, b.StateTaxCollectable)
+		if tax.Jurisdictions != nil && tax.Jurisdictions.County != "" {
+			add(code+"-"+strings.ToUpper(tax.Jurisdictions.County), "County tax", b.CountyTaxRate, b.CountyTaxCollectable)
+		} else {
+			add(code, "County tax", b.CountyTaxRate, b.CountyTaxCollectable)
+		}
+		if tax.Jurisdictions != nil && tax.Jurisdictions.City != "" {
+			add(code+"-"+strings.ToUpper(tax.Jurisdictions.City), "City tax", b.CityTaxRate, b.CityTaxCollectable)
+		} else {
+			add(code, "City tax", b.CityTaxRate, b.CityTaxCollectable)
+		}
+		add(code, "Special district tax", b.SpecialDistrictTaxRate, b.SpecialDistrictTaxCollectable)
+		add(country, "VAT", b.CountryTaxRate, b.CountryTaxCollectable)
+		add(country, "GST", b.GSTRate, b.GSTCollectable)
+		add(code, "PST", b.PSTRate, b.PSTCollectable)
+	}
+
+	// Providers that only return a total get a single line so the breakdown still adds up
+	if len(result.TaxBreakdown) == 0 && totalTax > 0 {
+		result.TaxBreakdown = append(result.TaxBreakdown, business.TaxLineItem{
+			TaxType:        taxType,
+			Jurisdiction:   code,
+			Rate:           tax.Rate,
+			TaxableAmount:  taxable,
+			TaxAmountCents: totalTax,
+			Description:    "Tax",
+		})
+	}
+
+	return result
+}
+
+// productTaxCode maps a product type to the provider's product tax code
+func productTaxCode(productType string) string {
+	switch productType {
+	case "digital", "digital_goods", "software", "subscription", "saas":
+		return digitalGoodsTaxCode
+	default:
+		return ""
+	}
+}
+
+// centsToAmount converts cents to the decimal amounts the provider works in
+func centsToAmount(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+// amountToCents converts a decimal provider amount back to cents
+func amountToCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,184 @@
+package tax_provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	logger.InitLogger("test")
+}
+
+// newTestServer stands in for the provider's API
+func newTestServer(t *testing.T, orders *[]orderRequest) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/taxes", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var req taxRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "US", req.ToCountry)
+		assert.Equal(t, "CA", req.ToState)
+		assert.Equal(t, 100.0, req.Amount)
+		require.Len(t, req.LineItems, 1)
+		assert.Equal(t, digitalGoodsTaxCode, req.LineItems[0].ProductTaxCode)
+
+		_ = json.NewEncoder(w).Encode(taxResponse{Tax: taxResult{
+			TaxableAmount:   100,
+			AmountToCollect: 9.5,
+			Rate:            0.095,
+			HasNexus:        true,
+			Jurisdictions:   &taxJurisdictions{Country: "US", State: "CA", County: "LOS ANGELES", City: "LOS ANGELES"},
+			Breakdown: &taxBreakdown{
+				StateTaxRate:                  0.0625,
+				StateTaxCollectable:           6.25,
+				CountyTaxRate:                 0.01,
+				CountyTaxCollectable:          1,
+				SpecialDistrictTaxRate:        0.0225,
+				SpecialDistrictTaxCollectable: 2.25,
+			},
+		}})
+	})
+	mux.HandleFunc("/v2/transactions/orders", func(w http.ResponseWriter, r *http.Request) {
+		var order orderRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&order))
+		*orders = append(*orders, order)
+
+		var resp orderResponse
+		resp.Order.TransactionID = order.TransactionID
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func testParams() params.TaxCalculationParams {
+	return params.TaxCalculationParams{
+		AmountCents:     10000,
+		Currency:        "USD",
+		CustomerAddress: &business.Address{Street1: "1 Main St", City: "Los Angeles", State: "ca", PostalCode: "90002", Country: "us"},
+		ProductType:     "digital",
+	}
+}
+
+func TestClient_CalculateTax(t *testing.T) {
+	var orders []orderRequest
+	server := newTestServer(t, &orders)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+
+	t.Run("converts the provider breakdown", func(t *testing.T) {
+		result, err := client.CalculateTax(context.Background(), testParams())
+		require.NoError(t, err)
+
+		assert.Equal(t, ProviderName, result.Provider)
+		assert.Equal(t, int64(950), result.TotalTaxCents)
+		assert.Equal(t, int64(10950), result.TotalAmountCents)
+		assert.Equal(t, []string{"US-CA"}, result.AppliedJurisdictions)
+		require.Len(t, result.TaxBreakdown, 3)
+		assert.Equal(t, int64(625), result.TaxBreakdown[0].TaxAmountCents)
+		assert.Equal(t, "US-CA-LOS ANGELES", result.TaxBreakdown[1].Jurisdiction)
+		assert.Equal(t, "sales", result.TaxBreakdown[2].TaxType)
+		assert.Empty(t, result.ProviderTransactionID)
+		assert.Empty(t, orders)
+	})
+
+	t.Run("only quotes when given a reference", func(t *testing.T) {
+		p := testParams()
+		p.TransactionReference = "INV-2025-0042"
+
+		result, err := client.CalculateTax(context.Background(), p)
+		require.NoError(t, err)
+		assert.Empty(t, result.ProviderTransactionID)
+		assert.Empty(t, orders)
+	})
+
+	t.Run("requires an address", func(t *testing.T) {
+		p := testParams()
+		p.CustomerAddress = nil
+
+		_, err := client.CalculateTax(context.Background(), p)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrUnavailable)
+	})
+}
+
+func TestClient_CommitTransaction(t *testing.T) {
+	var orders []orderRequest
+	server := newTestServer(t, &orders)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL)
+	p := testParams()
+	p.TransactionReference = "INV-2025-0042"
+
+	calculation, err := client.CalculateTax(context.Background(), p)
+	require.NoError(t, err)
+
+	transactionID, err := client.CommitTransaction(context.Background(), p, calculation)
+	require.NoError(t, err)
+	assert.Equal(t, "INV-2025-0042", transactionID)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "INV-2025-0042", orders[0].TransactionID)
+	assert.Equal(t, 9.5, orders[0].SalesTax)
+	assert.Equal(t, 100.0, orders[0].Amount)
+
+	p.TransactionReference = ""
+	_, err = client.CommitTransaction(context.Background(), p, calculation)
+	assert.Error(t, err)
+	assert.Len(t, orders, 1)
+}
+
+func TestClient_Outages(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		wantUnavailable bool
+	}{
+		{name: "server error", status: http.StatusServiceUnavailable, wantUnavailable: true},
+		{name: "rate limited", status: http.StatusTooManyRequests, wantUnavailable: true},
+		{name: "bad request", status: http.StatusBadRequest, wantUnavailable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			_, err := NewClient("test-key", server.URL).CalculateTax(context.Background(), testParams())
+			require.Error(t, err)
+			assert.Equal(t, tt.wantUnavailable, errors.Is(err, ErrUnavailable))
+		})
+	}
+
+	t.Run("unreachable provider", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		_, err := NewClient("test-key", server.URL).CalculateTax(context.Background(), testParams())
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+}
+
+func TestAmountConversion(t *testing.T) {
+	assert.Equal(t, 12.34, centsToAmount(1234))
+	assert.Equal(t, int64(1234), amountToCents(12.34))
+	assert.Equal(t, int64(1), amountToCents(0.005))
+}
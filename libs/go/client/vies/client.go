@@ -0,0 +1,169 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	httpClient "github.com/cyphera/cyphera-api/libs/go/client/http"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+)
+
+const (
+	// SourceName identifies results that came from VIES
+	SourceName = "vies"
+
+	defaultBaseURL = "https://ec.europa.eu/taxation_customs/vies/rest-api"
+	defaultTimeout = 10 * time.Second
+)
+
+// ErrUnavailable is returned when VIES or the member state registry behind it cannot answer.
+// The tax ID is neither valid nor invalid; the check should be retried later.
+var ErrUnavailable = errors.New("VIES unavailable")
+
+// User errors VIES reports when it could not reach a verdict
+var unavailableErrors = map[string]bool{
+	"SERVICE_UNAVAILABLE":            true,
+	"MS_UNAVAILABLE":                 true,
+	"TIMEOUT":                        true,
+	"GLOBAL_MAX_CONCURRENT_REQ":      true,
+	"MS_MAX_CONCURRENT_REQ":          true,
+	"VAT_BLOCKED":                    true,
+	"IP_BLOCKED":                     true,
+	"GLOBAL_MAX_CONCURRENT_REQ_TIME": true,
+	"MS_MAX_CONCURRENT_REQ_TIME":     true,
+}
+
+// Client checks EU VAT numbers with the European Commission's VIES REST API
+type Client struct {
+	httpClient *httpClient.HTTPClient
+	// requesterCountry and requesterNumber identify the merchant's own VAT registration.
+	// When set, VIES returns a consultation number that proves the check took place.
+	requesterCountry string
+	requesterNumber  string
+}
+
+// NewClient creates a VIES client. An empty baseURL uses the public VIES endpoint; requesterVATNumber
+// is the merchant's own VAT number including its country prefix and may be empty.
+func NewClient(baseURL, requesterVATNumber string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	client := &Client{
+		httpClient: httpClient.NewHTTPClient(
+			httpClient.WithBaseURL(baseURL),
+			httpClient.WithTimeout(defaultTimeout),
+			httpClient.WithRetryConfig(&httpClient.RetryConfig{MaxRetries: 0}),
+		),
+	}
+
+	requester := strings.ToUpper(strings.ReplaceAll(requesterVATNumber, " ", ""))
+	if len(requester) > 2 {
+		client.requesterCountry = requester[:2]
+		client.requesterNumber = requester[2:]
+	}
+	return client
+}
+
+type checkVATRequest struct {
+	CountryCode              string `json:"countryCode"`
+	VATNumber                string `json:"vatNumber"`
+	RequesterMemberStateCode string `json:"requesterMemberStateCode,omitempty"`
+	RequesterNumber          string `json:"requesterNumber,omitempty"`
+}
+
+type checkVATResponse struct {
+	CountryCode       string `json:"countryCode"`
+	VATNumber         string `json:"vatNumber"`
+	RequestDate       string `json:"requestDate"`
+	Valid             bool   `json:"valid"`
+	RequestIdentifier string `json:"requestIdentifier"`
+	Name              string `json:"name"`
+	Address           string `json:"address"`
+	UserError         string `json:"userError"`
+	ActionSucceed     *bool  `json:"actionSucceed"`
+	ErrorWrappers     []struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	} `json:"errorWrappers"`
+}
+
+// Name returns the registry name recorded with each verification
+func (c *Client) Name() string {
+	return SourceName
+}
+
+// CheckTaxID asks VIES whether a VAT number is registered. countryCode is the VIES member state
+// code ("EL" for Greece, "XI" for Northern Ireland) and taxID the number without its prefix.
+func (c *Client) CheckTaxID(ctx context.Context, countryCode, taxID string) (*business.TaxIDCheckResult, error) {
+	req := checkVATRequest{
+		CountryCode:              countryCode,
+		VATNumber:                taxID,
+		RequesterMemberStateCode: c.requesterCountry,
+		RequesterNumber:          c.requesterNumber,
+	}
+
+	resp, err := c.httpClient.Post(ctx, "/check-vat-number", req)
+	if err != nil {
+		var httpErr *httpClient.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode < http.StatusInternalServerError && httpErr.StatusCode != http.StatusTooManyRequests {
+			return nil, fmt.Errorf("VIES rejected request: %w", err)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	var body checkVATResponse
+	if err := c.httpClient.ProcessJSONResponse(resp, &body); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode response: %v", ErrUnavailable, err)
+	}
+
+	if body.ActionSucceed != nil && !*body.ActionSucceed {
+		for _, wrapper := range body.ErrorWrappers {
+			if wrapper.Error == "INVALID_INPUT" {
+				return c.result(countryCode, taxID, body, false), nil
+			}
+		}
+		code := ""
+		if len(body.ErrorWrappers) > 0 {
+			code = body.ErrorWrappers[0].Error
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnavailable, code)
+	}
+	if unavailableErrors[body.UserError] {
+		return nil, fmt.Errorf("%w: %s", ErrUnavailable, body.UserError)
+	}
+
+	return c.result(countryCode, taxID, body, body.Valid && body.UserError != "INVALID_INPUT"), nil
+}
+
+// result converts a VIES response into a check result
+func (c *Client) result(countryCode, taxID string, body checkVATResponse, valid bool) *business.TaxIDCheckResult {
+	checkedAt := time.Now()
+	if parsed, err := time.Parse(time.RFC3339, body.RequestDate); err == nil {
+		checkedAt = parsed
+	}
+
+	return &business.TaxIDCheckResult{
+		CountryCode:        countryCode,
+		TaxID:              taxID,
+		Valid:              valid,
+		Name:               cleanField(body.Name),
+		Address:            cleanField(body.Address),
+		ConsultationNumber: body.RequestIdentifier,
+		CheckedAt:          checkedAt,
+		Source:             SourceName,
+	}
+}
+
+// cleanField drops the "---" VIES returns for details a member state does not share
+func cleanField(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "---" {
+		return ""
+	}
+	return value
+}
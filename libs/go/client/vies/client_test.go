@@ -0,0 +1,136 @@
+package vies
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	logger.InitLogger("test")
+}
+
+// newTestServer stands in for VIES, answering every check with the given response
+func newTestServer(t *testing.T, status int, response map[string]interface{}, requests *[]checkVATRequest) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/check-vat-number", r.URL.Path)
+
+		var req checkVATRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if requests != nil {
+			*requests = append(*requests, req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if response != nil {
+			_ = json.NewEncoder(w).Encode(response)
+		}
+	}))
+}
+
+func TestClient_CheckTaxID(t *testing.T) {
+	t.Run("registered number", func(t *testing.T) {
+		var requests []checkVATRequest
+		server := newTestServer(t, http.StatusOK, map[string]interface{}{
+			"countryCode":       "DE",
+			"vatNumber":         "123456789",
+			"requestDate":       "2025-03-04T10:15:00.000Z",
+			"valid":             true,
+			"requestIdentifier": "WAPIAAAAY1234567",
+			"name":              "ACME GmbH",
+			"address":           "---",
+		}, &requests)
+		defer server.Close()
+
+		result, err := NewClient(server.URL, "fr 40303265045").CheckTaxID(context.Background(), "DE", "123456789")
+		require.NoError(t, err)
+
+		assert.True(t, result.Valid)
+		assert.Equal(t, "ACME GmbH", result.Name)
+		assert.Empty(t, result.Address)
+		assert.Equal(t, "WAPIAAAAY1234567", result.ConsultationNumber)
+		assert.Equal(t, SourceName, result.Source)
+		assert.Equal(t, time.Date(2025, 3, 4, 10, 15, 0, 0, time.UTC), result.CheckedAt.UTC())
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "DE", requests[0].CountryCode)
+		assert.Equal(t, "FR", requests[0].RequesterMemberStateCode)
+		assert.Equal(t, "40303265045", requests[0].RequesterNumber)
+	})
+
+	t.Run("unregistered number", func(t *testing.T) {
+		server := newTestServer(t, http.StatusOK, map[string]interface{}{
+			"countryCode": "DE",
+			"vatNumber":   "999999999",
+			"valid":       false,
+			"userError":   "INVALID",
+		}, nil)
+		defer server.Close()
+
+		result, err := NewClient(server.URL, "").CheckTaxID(context.Background(), "DE", "999999999")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("rejected input counts as invalid", func(t *testing.T) {
+		server := newTestServer(t, http.StatusOK, map[string]interface{}{
+			"actionSucceed": false,
+			"errorWrappers": []map[string]string{{"error": "INVALID_INPUT"}},
+		}, nil)
+		defer server.Close()
+
+		result, err := NewClient(server.URL, "").CheckTaxID(context.Background(), "DE", "12")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+}
+
+func TestClient_Outages(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		response        map[string]interface{}
+		wantUnavailable bool
+	}{
+		{
+			name:            "member state unavailable",
+			status:          http.StatusOK,
+			response:        map[string]interface{}{"valid": false, "userError": "MS_UNAVAILABLE"},
+			wantUnavailable: true,
+		},
+		{
+			name:   "too many concurrent requests",
+			status: http.StatusOK,
+			response: map[string]interface{}{
+				"actionSucceed": false,
+				"errorWrappers": []map[string]string{{"error": "GLOBAL_MAX_CONCURRENT_REQ"}},
+			},
+			wantUnavailable: true,
+		},
+		{name: "server error", status: http.StatusInternalServerError, wantUnavailable: true},
+		{name: "bad request", status: http.StatusBadRequest, wantUnavailable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.status, tt.response, nil)
+			defer server.Close()
+
+			_, err := NewClient(server.URL, "").CheckTaxID(context.Background(), "DE", "123456789")
+			require.Error(t, err)
+			assert.Equal(t, tt.wantUnavailable, errors.Is(err, ErrUnavailable))
+		})
+	}
+}
@@ -24,4 +24,7 @@ const (
 const (
 	AuthTypeAPIKey = "api_key"
 	AuthTypeJWT    = "jwt"
+	// Customer portal auth: a merchant-issued portal link or the customer's own Web3Auth login
+	AuthTypePortalSession = "portal_session"
+	AuthTypeCustomerJWT   = "customer_jwt"
 )
@@ -24,7 +24,7 @@ INSERT INTO api_keys (
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7
 )
-RETURNING id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at, rotated_from_id, rotated_at, unused_notified_at
 `
 
 type CreateAPIKeyParams struct {
@@ -61,6 +61,9 @@ func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (Api
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RotatedFromID,
+		&i.RotatedAt,
+		&i.UnusedNotifiedAt,
 	)
 	return i, err
 }
@@ -82,7 +85,7 @@ func (q *Queries) DeleteAPIKey(ctx context.Context, arg DeleteAPIKeyParams) erro
 }
 
 const getAPIKey = `-- name: GetAPIKey :one
-SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at FROM api_keys
+SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at, rotated_from_id, rotated_at, unused_notified_at FROM api_keys
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL LIMIT 1
 `
 
@@ -107,12 +110,15 @@ func (q *Queries) GetAPIKey(ctx context.Context, arg GetAPIKeyParams) (ApiKey, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RotatedFromID,
+		&i.RotatedAt,
+		&i.UnusedNotifiedAt,
 	)
 	return i, err
 }
 
 const getAPIKeyByKey = `-- name: GetAPIKeyByKey :one
-SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at FROM api_keys
+SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at, rotated_from_id, rotated_at, unused_notified_at FROM api_keys
 WHERE key_hash = $1 AND deleted_at IS NULL LIMIT 1
 `
 
@@ -132,6 +138,9 @@ func (q *Queries) GetAPIKeyByKey(ctx context.Context, keyHash string) (ApiKey, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RotatedFromID,
+		&i.RotatedAt,
+		&i.UnusedNotifiedAt,
 	)
 	return i, err
 }
@@ -149,7 +158,7 @@ func (q *Queries) GetActiveAPIKeysCount(ctx context.Context, workspaceID uuid.UU
 }
 
 const getAllAPIKeys = `-- name: GetAllAPIKeys :many
-SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at FROM api_keys
+SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at, rotated_from_id, rotated_at, unused_notified_at FROM api_keys
 ORDER BY created_at DESC
 `
 
@@ -175,6 +184,9 @@ func (q *Queries) GetAllAPIKeys(ctx context.Context) ([]ApiKey, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RotatedFromID,
+			&i.RotatedAt,
+			&i.UnusedNotifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -187,7 +199,7 @@ func (q *Queries) GetAllAPIKeys(ctx context.Context) ([]ApiKey, error) {
 }
 
 const getExpiredAPIKeys = `-- name: GetExpiredAPIKeys :many
-SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at FROM api_keys
+SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at, rotated_from_id, rotated_at, unused_notified_at FROM api_keys
 WHERE expires_at < CURRENT_TIMESTAMP AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -214,6 +226,9 @@ func (q *Queries) GetExpiredAPIKeys(ctx context.Context) ([]ApiKey, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RotatedFromID,
+			&i.RotatedAt,
+			&i.UnusedNotifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -226,7 +241,7 @@ func (q *Queries) GetExpiredAPIKeys(ctx context.Context) ([]ApiKey, error) {
 }
 
 const listAPIKeys = `-- name: ListAPIKeys :many
-SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at FROM api_keys
+SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at, rotated_from_id, rotated_at, unused_notified_at FROM api_keys
 WHERE workspace_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -253,6 +268,9 @@ func (q *Queries) ListAPIKeys(ctx context.Context, workspaceID uuid.UUID) ([]Api
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RotatedFromID,
+			&i.RotatedAt,
+			&i.UnusedNotifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -264,6 +282,84 @@ func (q *Queries) ListAPIKeys(ctx context.Context, workspaceID uuid.UUID) ([]Api
 	return items, nil
 }
 
+const rotateAPIKey = `-- name: RotateAPIKey :one
+WITH old_key AS (
+    UPDATE api_keys
+    SET
+        expires_at = LEAST(api_keys.expires_at, $1::timestamptz),
+        rotated_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE api_keys.id = $2
+        AND api_keys.workspace_id = $3
+        AND api_keys.deleted_at IS NULL
+        AND api_keys.rotated_at IS NULL
+        AND (api_keys.expires_at IS NULL OR api_keys.expires_at > CURRENT_TIMESTAMP)
+    RETURNING api_keys.id, api_keys.workspace_id, api_keys.name, api_keys.access_level, api_keys.metadata
+)
+INSERT INTO api_keys (
+    workspace_id,
+    name,
+    key_hash,
+    key_prefix,
+    access_level,
+    expires_at,
+    metadata,
+    rotated_from_id
+)
+SELECT
+    old_key.workspace_id,
+    old_key.name,
+    $4::text,
+    $5::text,
+    old_key.access_level,
+    $6::timestamptz,
+    old_key.metadata,
+    old_key.id
+FROM old_key
+RETURNING id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at, rotated_from_id, rotated_at, unused_notified_at
+`
+
+type RotateAPIKeyParams struct {
+	GraceExpiresAt pgtype.Timestamptz `json:"grace_expires_at"`
+	ID             uuid.UUID          `json:"id"`
+	WorkspaceID    uuid.UUID          `json:"workspace_id"`
+	KeyHash        string             `json:"key_hash"`
+	KeyPrefix      pgtype.Text        `json:"key_prefix"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+}
+
+// Issues a replacement key and caps the old key's validity at the grace expiry in a single statement.
+// Keys that are deleted, expired or already rotated are not rotated again.
+func (q *Queries) RotateAPIKey(ctx context.Context, arg RotateAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, rotateAPIKey,
+		arg.GraceExpiresAt,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.KeyHash,
+		arg.KeyPrefix,
+		arg.ExpiresAt,
+	)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Name,
+		&i.KeyHash,
+		&i.KeyPrefix,
+		&i.AccessLevel,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.RotatedFromID,
+		&i.RotatedAt,
+		&i.UnusedNotifiedAt,
+	)
+	return i, err
+}
+
 const updateAPIKey = `-- name: UpdateAPIKey :one
 UPDATE api_keys
 SET
@@ -273,7 +369,7 @@ SET
     metadata = COALESCE($6, metadata),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at, rotated_from_id, rotated_at, unused_notified_at
 `
 
 type UpdateAPIKeyParams struct {
@@ -308,6 +404,9 @@ func (q *Queries) UpdateAPIKey(ctx context.Context, arg UpdateAPIKeyParams) (Api
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RotatedFromID,
+		&i.RotatedAt,
+		&i.UnusedNotifiedAt,
 	)
 	return i, err
 }
@@ -12,7 +12,7 @@ import (
 )
 
 const getAllActiveAPIKeys = `-- name: GetAllActiveAPIKeys :many
-SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at FROM api_keys
+SELECT id, workspace_id, name, key_hash, key_prefix, access_level, expires_at, last_used_at, metadata, created_at, updated_at, deleted_at, rotated_from_id, rotated_at, unused_notified_at FROM api_keys
 WHERE deleted_at IS NULL 
   AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
 ORDER BY created_at DESC
@@ -41,6 +41,9 @@ func (q *Queries) GetAllActiveAPIKeys(ctx context.Context) ([]ApiKey, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RotatedFromID,
+			&i.RotatedAt,
+			&i.UnusedNotifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -45,21 +45,23 @@ INSERT INTO customer_wallets (
     ens,
     is_primary,
     verified,
-    metadata
+    metadata,
+    ens_resolved_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
-) RETURNING id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
 `
 
 type CreateCustomerWalletParams struct {
-	CustomerID    uuid.UUID   `json:"customer_id"`
-	WalletAddress string      `json:"wallet_address"`
-	NetworkType   NetworkType `json:"network_type"`
-	Nickname      pgtype.Text `json:"nickname"`
-	Ens           pgtype.Text `json:"ens"`
-	IsPrimary     pgtype.Bool `json:"is_primary"`
-	Verified      pgtype.Bool `json:"verified"`
-	Metadata      []byte      `json:"metadata"`
+	CustomerID    uuid.UUID          `json:"customer_id"`
+	WalletAddress string             `json:"wallet_address"`
+	NetworkType   NetworkType        `json:"network_type"`
+	Nickname      pgtype.Text        `json:"nickname"`
+	Ens           pgtype.Text        `json:"ens"`
+	IsPrimary     pgtype.Bool        `json:"is_primary"`
+	Verified      pgtype.Bool        `json:"verified"`
+	Metadata      []byte             `json:"metadata"`
+	EnsResolvedAt pgtype.Timestamptz `json:"ens_resolved_at"`
 }
 
 func (q *Queries) CreateCustomerWallet(ctx context.Context, arg CreateCustomerWalletParams) (CustomerWallet, error) {
@@ -72,6 +74,7 @@ func (q *Queries) CreateCustomerWallet(ctx context.Context, arg CreateCustomerWa
 		arg.IsPrimary,
 		arg.Verified,
 		arg.Metadata,
+		arg.EnsResolvedAt,
 	)
 	var i CustomerWallet
 	err := row.Scan(
@@ -81,6 +84,7 @@ func (q *Queries) CreateCustomerWallet(ctx context.Context, arg CreateCustomerWa
 		&i.NetworkType,
 		&i.Nickname,
 		&i.Ens,
+		&i.EnsResolvedAt,
 		&i.IsPrimary,
 		&i.Verified,
 		&i.LastUsedAt,
@@ -128,7 +132,7 @@ func (q *Queries) GetCustomerIdForWallet(ctx context.Context, id uuid.UUID) (uui
 }
 
 const getCustomerWallet = `-- name: GetCustomerWallet :one
-SELECT id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
+SELECT id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -142,6 +146,7 @@ func (q *Queries) GetCustomerWallet(ctx context.Context, id uuid.UUID) (Customer
 		&i.NetworkType,
 		&i.Nickname,
 		&i.Ens,
+		&i.EnsResolvedAt,
 		&i.IsPrimary,
 		&i.Verified,
 		&i.LastUsedAt,
@@ -154,8 +159,11 @@ func (q *Queries) GetCustomerWallet(ctx context.Context, id uuid.UUID) (Customer
 }
 
 const getCustomerWalletByAddress = `-- name: GetCustomerWalletByAddress :one
-SELECT id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
-WHERE customer_id = $1 AND wallet_address = $2 AND network_type = $3 AND deleted_at IS NULL
+SELECT id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
+WHERE customer_id = $1
+    AND (wallet_address = $2 OR (network_type = 'evm' AND LOWER(wallet_address) = LOWER($2)))
+    AND network_type = $3
+    AND deleted_at IS NULL
 `
 
 type GetCustomerWalletByAddressParams struct {
@@ -164,6 +172,7 @@ type GetCustomerWalletByAddressParams struct {
 	NetworkType   NetworkType `json:"network_type"`
 }
 
+// EVM addresses match whatever their casing
 func (q *Queries) GetCustomerWalletByAddress(ctx context.Context, arg GetCustomerWalletByAddressParams) (CustomerWallet, error) {
 	row := q.db.QueryRow(ctx, getCustomerWalletByAddress, arg.CustomerID, arg.WalletAddress, arg.NetworkType)
 	var i CustomerWallet
@@ -174,6 +183,7 @@ func (q *Queries) GetCustomerWalletByAddress(ctx context.Context, arg GetCustome
 		&i.NetworkType,
 		&i.Nickname,
 		&i.Ens,
+		&i.EnsResolvedAt,
 		&i.IsPrimary,
 		&i.Verified,
 		&i.LastUsedAt,
@@ -188,9 +198,11 @@ func (q *Queries) GetCustomerWalletByAddress(ctx context.Context, arg GetCustome
 const getCustomersByWalletAddress = `-- name: GetCustomersByWalletAddress :many
 SELECT c.id, c.num_id, c.web3auth_id, c.external_id, c.email, c.name, c.phone, c.description, c.metadata, c.finished_onboarding, c.payment_sync_status, c.payment_synced_at, c.payment_sync_version, c.payment_provider, c.created_at, c.updated_at, c.deleted_at, c.tax_jurisdiction_id, c.tax_id, c.tax_id_type, c.tax_id_verified, c.tax_id_verified_at, c.is_business, c.business_name, c.billing_country, c.billing_state, c.billing_city, c.billing_postal_code FROM customers c
 JOIN customer_wallets cw ON c.id = cw.customer_id
-WHERE cw.wallet_address = $1 AND c.deleted_at IS NULL AND cw.deleted_at IS NULL
+WHERE (cw.wallet_address = $1 OR (cw.network_type = 'evm' AND LOWER(cw.wallet_address) = LOWER($1)))
+    AND c.deleted_at IS NULL AND cw.deleted_at IS NULL
 `
 
+// EVM addresses match whatever their casing
 func (q *Queries) GetCustomersByWalletAddress(ctx context.Context, walletAddress string) ([]Customer, error) {
 	rows, err := q.db.Query(ctx, getCustomersByWalletAddress, walletAddress)
 	if err != nil {
@@ -241,7 +253,7 @@ func (q *Queries) GetCustomersByWalletAddress(ctx context.Context, walletAddress
 }
 
 const getPrimaryCustomerWallet = `-- name: GetPrimaryCustomerWallet :one
-SELECT id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
+SELECT id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
 WHERE customer_id = $1 AND is_primary = true AND deleted_at IS NULL
 LIMIT 1
 `
@@ -256,6 +268,7 @@ func (q *Queries) GetPrimaryCustomerWallet(ctx context.Context, customerID uuid.
 		&i.NetworkType,
 		&i.Nickname,
 		&i.Ens,
+		&i.EnsResolvedAt,
 		&i.IsPrimary,
 		&i.Verified,
 		&i.LastUsedAt,
@@ -268,7 +281,7 @@ func (q *Queries) GetPrimaryCustomerWallet(ctx context.Context, customerID uuid.
 }
 
 const getWalletsWithSimilarAddress = `-- name: GetWalletsWithSimilarAddress :many
-SELECT id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
+SELECT id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
 WHERE wallet_address ILIKE $1 AND deleted_at IS NULL
 ORDER BY customer_id, is_primary DESC
 LIMIT $2
@@ -295,6 +308,7 @@ func (q *Queries) GetWalletsWithSimilarAddress(ctx context.Context, arg GetWalle
 			&i.NetworkType,
 			&i.Nickname,
 			&i.Ens,
+			&i.EnsResolvedAt,
 			&i.IsPrimary,
 			&i.Verified,
 			&i.LastUsedAt,
@@ -314,7 +328,7 @@ func (q *Queries) GetWalletsWithSimilarAddress(ctx context.Context, arg GetWalle
 }
 
 const listCustomerWallets = `-- name: ListCustomerWallets :many
-SELECT id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
+SELECT id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
 WHERE customer_id = $1 AND deleted_at IS NULL
 ORDER BY is_primary DESC, created_at DESC
 `
@@ -335,6 +349,57 @@ func (q *Queries) ListCustomerWallets(ctx context.Context, customerID uuid.UUID)
 			&i.NetworkType,
 			&i.Nickname,
 			&i.Ens,
+			&i.EnsResolvedAt,
+			&i.IsPrimary,
+			&i.Verified,
+			&i.LastUsedAt,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCustomerWalletsDueNameRefresh = `-- name: ListCustomerWalletsDueNameRefresh :many
+SELECT id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
+WHERE network_type = 'evm'
+    AND deleted_at IS NULL
+    AND (ens_resolved_at IS NULL OR ens_resolved_at < $1::timestamptz)
+ORDER BY ens_resolved_at NULLS FIRST, id
+LIMIT $2
+`
+
+type ListCustomerWalletsDueNameRefreshParams struct {
+	ResolvedBefore pgtype.Timestamptz `json:"resolved_before"`
+	BatchSize      int32              `json:"batch_size"`
+}
+
+// EVM wallets whose ENS or Basenames name has never been looked up or was last looked up before the cutoff
+func (q *Queries) ListCustomerWalletsDueNameRefresh(ctx context.Context, arg ListCustomerWalletsDueNameRefreshParams) ([]CustomerWallet, error) {
+	rows, err := q.db.Query(ctx, listCustomerWalletsDueNameRefresh, arg.ResolvedBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CustomerWallet{}
+	for rows.Next() {
+		var i CustomerWallet
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.WalletAddress,
+			&i.NetworkType,
+			&i.Nickname,
+			&i.Ens,
+			&i.EnsResolvedAt,
 			&i.IsPrimary,
 			&i.Verified,
 			&i.LastUsedAt,
@@ -354,7 +419,7 @@ func (q *Queries) ListCustomerWallets(ctx context.Context, customerID uuid.UUID)
 }
 
 const listPrimaryCustomerWallets = `-- name: ListPrimaryCustomerWallets :many
-SELECT id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
+SELECT id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at FROM customer_wallets
 WHERE is_primary = true AND deleted_at IS NULL
 ORDER BY customer_id
 `
@@ -375,6 +440,7 @@ func (q *Queries) ListPrimaryCustomerWallets(ctx context.Context) ([]CustomerWal
 			&i.NetworkType,
 			&i.Nickname,
 			&i.Ens,
+			&i.EnsResolvedAt,
 			&i.IsPrimary,
 			&i.Verified,
 			&i.LastUsedAt,
@@ -397,7 +463,7 @@ const markCustomerWalletAsPrimary = `-- name: MarkCustomerWalletAsPrimary :one
 UPDATE customer_wallets
 SET is_primary = true, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
 `
 
 // Set a specific customer wallet as primary
@@ -411,6 +477,7 @@ func (q *Queries) MarkCustomerWalletAsPrimary(ctx context.Context, id uuid.UUID)
 		&i.NetworkType,
 		&i.Nickname,
 		&i.Ens,
+		&i.EnsResolvedAt,
 		&i.IsPrimary,
 		&i.Verified,
 		&i.LastUsedAt,
@@ -450,7 +517,7 @@ UPDATE customer_wallets SET
     metadata = $6,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
 `
 
 type UpdateCustomerWalletParams struct {
@@ -479,6 +546,42 @@ func (q *Queries) UpdateCustomerWallet(ctx context.Context, arg UpdateCustomerWa
 		&i.NetworkType,
 		&i.Nickname,
 		&i.Ens,
+		&i.EnsResolvedAt,
+		&i.IsPrimary,
+		&i.Verified,
+		&i.LastUsedAt,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateCustomerWalletEns = `-- name: UpdateCustomerWalletEns :one
+UPDATE customer_wallets
+SET ens = $2, ens_resolved_at = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
+`
+
+type UpdateCustomerWalletEnsParams struct {
+	ID            uuid.UUID          `json:"id"`
+	Ens           pgtype.Text        `json:"ens"`
+	EnsResolvedAt pgtype.Timestamptz `json:"ens_resolved_at"`
+}
+
+func (q *Queries) UpdateCustomerWalletEns(ctx context.Context, arg UpdateCustomerWalletEnsParams) (CustomerWallet, error) {
+	row := q.db.QueryRow(ctx, updateCustomerWalletEns, arg.ID, arg.Ens, arg.EnsResolvedAt)
+	var i CustomerWallet
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.WalletAddress,
+		&i.NetworkType,
+		&i.Nickname,
+		&i.Ens,
+		&i.EnsResolvedAt,
 		&i.IsPrimary,
 		&i.Verified,
 		&i.LastUsedAt,
@@ -494,7 +597,7 @@ const updateCustomerWalletUsageTime = `-- name: UpdateCustomerWalletUsageTime :o
 UPDATE customer_wallets
 SET last_used_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
 `
 
 func (q *Queries) UpdateCustomerWalletUsageTime(ctx context.Context, id uuid.UUID) (CustomerWallet, error) {
@@ -507,6 +610,7 @@ func (q *Queries) UpdateCustomerWalletUsageTime(ctx context.Context, id uuid.UUI
 		&i.NetworkType,
 		&i.Nickname,
 		&i.Ens,
+		&i.EnsResolvedAt,
 		&i.IsPrimary,
 		&i.Verified,
 		&i.LastUsedAt,
@@ -522,7 +626,7 @@ const verifyCustomerWallet = `-- name: VerifyCustomerWallet :one
 UPDATE customer_wallets
 SET verified = true, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, customer_id, wallet_address, network_type, nickname, ens, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, customer_id, wallet_address, network_type, nickname, ens, ens_resolved_at, is_primary, verified, last_used_at, metadata, created_at, updated_at, deleted_at
 `
 
 func (q *Queries) VerifyCustomerWallet(ctx context.Context, id uuid.UUID) (CustomerWallet, error) {
@@ -535,6 +639,7 @@ func (q *Queries) VerifyCustomerWallet(ctx context.Context, id uuid.UUID) (Custo
 		&i.NetworkType,
 		&i.Nickname,
 		&i.Ens,
+		&i.EnsResolvedAt,
 		&i.IsPrimary,
 		&i.Verified,
 		&i.LastUsedAt,
@@ -1241,6 +1241,78 @@ func (q *Queries) UpdateCustomerBillingAddress(ctx context.Context, arg UpdateCu
 	return i, err
 }
 
+const updateCustomerBillingDetails = `-- name: UpdateCustomerBillingDetails :one
+UPDATE customers
+SET
+    name = COALESCE($1, name),
+    email = COALESCE($2, email),
+    phone = COALESCE($3, phone),
+    billing_country = COALESCE($4, billing_country),
+    billing_state = COALESCE($5, billing_state),
+    billing_city = COALESCE($6, billing_city),
+    billing_postal_code = COALESCE($7, billing_postal_code),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $8 AND deleted_at IS NULL
+RETURNING id, num_id, web3auth_id, external_id, email, name, phone, description, metadata, finished_onboarding, payment_sync_status, payment_synced_at, payment_sync_version, payment_provider, created_at, updated_at, deleted_at, tax_jurisdiction_id, tax_id, tax_id_type, tax_id_verified, tax_id_verified_at, is_business, business_name, billing_country, billing_state, billing_city, billing_postal_code
+`
+
+type UpdateCustomerBillingDetailsParams struct {
+	Name              pgtype.Text `json:"name"`
+	Email             pgtype.Text `json:"email"`
+	Phone             pgtype.Text `json:"phone"`
+	BillingCountry    pgtype.Text `json:"billing_country"`
+	BillingState      pgtype.Text `json:"billing_state"`
+	BillingCity       pgtype.Text `json:"billing_city"`
+	BillingPostalCode pgtype.Text `json:"billing_postal_code"`
+	ID                uuid.UUID   `json:"id"`
+}
+
+// Updates only the billing fields that are provided
+func (q *Queries) UpdateCustomerBillingDetails(ctx context.Context, arg UpdateCustomerBillingDetailsParams) (Customer, error) {
+	row := q.db.QueryRow(ctx, updateCustomerBillingDetails,
+		arg.Name,
+		arg.Email,
+		arg.Phone,
+		arg.BillingCountry,
+		arg.BillingState,
+		arg.BillingCity,
+		arg.BillingPostalCode,
+		arg.ID,
+	)
+	var i Customer
+	err := row.Scan(
+		&i.ID,
+		&i.NumID,
+		&i.Web3authID,
+		&i.ExternalID,
+		&i.Email,
+		&i.Name,
+		&i.Phone,
+		&i.Description,
+		&i.Metadata,
+		&i.FinishedOnboarding,
+		&i.PaymentSyncStatus,
+		&i.PaymentSyncedAt,
+		&i.PaymentSyncVersion,
+		&i.PaymentProvider,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.TaxJurisdictionID,
+		&i.TaxID,
+		&i.TaxIDType,
+		&i.TaxIDVerified,
+		&i.TaxIDVerifiedAt,
+		&i.IsBusiness,
+		&i.BusinessName,
+		&i.BillingCountry,
+		&i.BillingState,
+		&i.BillingCity,
+		&i.BillingPostalCode,
+	)
+	return i, err
+}
+
 const updateCustomerOnboardingStatus = `-- name: UpdateCustomerOnboardingStatus :one
 UPDATE customers
 SET
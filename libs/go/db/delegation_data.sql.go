@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countDelegations = `-- name: CountDelegations :one
@@ -47,7 +48,7 @@ INSERT INTO delegation_data (
 ) VALUES (
     $1, $2, $3, $4, $5, $6
 )
-RETURNING id, delegate, delegator, authority, caveats, salt, signature, created_at, updated_at, deleted_at
+RETURNING id, delegate, delegator, authority, caveats, salt, signature, status, expires_at, last_checked_at, created_at, updated_at, deleted_at
 `
 
 type CreateDelegationDataParams struct {
@@ -77,6 +78,9 @@ func (q *Queries) CreateDelegationData(ctx context.Context, arg CreateDelegation
 		&i.Caveats,
 		&i.Salt,
 		&i.Signature,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.LastCheckedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -96,7 +100,7 @@ func (q *Queries) DeleteDelegationData(ctx context.Context, id uuid.UUID) error
 }
 
 const getDelegationData = `-- name: GetDelegationData :one
-SELECT id, delegate, delegator, authority, caveats, salt, signature, created_at, updated_at, deleted_at FROM delegation_data
+SELECT id, delegate, delegator, authority, caveats, salt, signature, status, expires_at, last_checked_at, created_at, updated_at, deleted_at FROM delegation_data
 WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
@@ -111,6 +115,9 @@ func (q *Queries) GetDelegationData(ctx context.Context, id uuid.UUID) (Delegati
 		&i.Caveats,
 		&i.Salt,
 		&i.Signature,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.LastCheckedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -119,7 +126,7 @@ func (q *Queries) GetDelegationData(ctx context.Context, id uuid.UUID) (Delegati
 }
 
 const getDelegationDataBySignature = `-- name: GetDelegationDataBySignature :one
-SELECT id, delegate, delegator, authority, caveats, salt, signature, created_at, updated_at, deleted_at FROM delegation_data
+SELECT id, delegate, delegator, authority, caveats, salt, signature, status, expires_at, last_checked_at, created_at, updated_at, deleted_at FROM delegation_data
 WHERE signature = $1 AND deleted_at IS NULL LIMIT 1
 `
 
@@ -134,6 +141,9 @@ func (q *Queries) GetDelegationDataBySignature(ctx context.Context, signature st
 		&i.Caveats,
 		&i.Salt,
 		&i.Signature,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.LastCheckedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -142,7 +152,7 @@ func (q *Queries) GetDelegationDataBySignature(ctx context.Context, signature st
 }
 
 const getDelegationsByDelegate = `-- name: GetDelegationsByDelegate :many
-SELECT id, delegate, delegator, authority, caveats, salt, signature, created_at, updated_at, deleted_at FROM delegation_data
+SELECT id, delegate, delegator, authority, caveats, salt, signature, status, expires_at, last_checked_at, created_at, updated_at, deleted_at FROM delegation_data
 WHERE delegate = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -164,6 +174,9 @@ func (q *Queries) GetDelegationsByDelegate(ctx context.Context, delegate string)
 			&i.Caveats,
 			&i.Salt,
 			&i.Signature,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.LastCheckedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
@@ -179,7 +192,7 @@ func (q *Queries) GetDelegationsByDelegate(ctx context.Context, delegate string)
 }
 
 const getDelegationsByDelegator = `-- name: GetDelegationsByDelegator :many
-SELECT id, delegate, delegator, authority, caveats, salt, signature, created_at, updated_at, deleted_at FROM delegation_data
+SELECT id, delegate, delegator, authority, caveats, salt, signature, status, expires_at, last_checked_at, created_at, updated_at, deleted_at FROM delegation_data
 WHERE delegator = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -201,6 +214,9 @@ func (q *Queries) GetDelegationsByDelegator(ctx context.Context, delegator strin
 			&i.Caveats,
 			&i.Salt,
 			&i.Signature,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.LastCheckedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
@@ -215,8 +231,95 @@ func (q *Queries) GetDelegationsByDelegator(ctx context.Context, delegator strin
 	return items, nil
 }
 
+const listDelegationsDueForCheck = `-- name: ListDelegationsDueForCheck :many
+SELECT
+    d.id AS delegation_id,
+    d.delegate,
+    d.delegator,
+    d.authority,
+    d.caveats,
+    d.salt,
+    d.expires_at,
+    s.id AS subscription_id,
+    s.workspace_id,
+    s.customer_id,
+    s.token_amount,
+    s.next_redemption_date,
+    pt.network_id,
+    n.network_type
+FROM delegation_data d
+JOIN subscriptions s ON s.delegation_id = d.id
+JOIN products_tokens pt ON pt.id = s.product_token_id
+JOIN networks n ON n.id = pt.network_id
+WHERE d.deleted_at IS NULL
+    AND d.status = 'active'
+    AND (d.last_checked_at IS NULL OR d.last_checked_at < $1)
+    AND s.deleted_at IS NULL
+    AND s.status IN ('active', 'trial', 'overdue')
+ORDER BY d.last_checked_at ASC NULLS FIRST, d.id
+LIMIT $2
+`
+
+type ListDelegationsDueForCheckParams struct {
+	CheckedBefore pgtype.Timestamptz `json:"checked_before"`
+	BatchSize     int32              `json:"batch_size"`
+}
+
+type ListDelegationsDueForCheckRow struct {
+	DelegationID       uuid.UUID          `json:"delegation_id"`
+	Delegate           string             `json:"delegate"`
+	Delegator          string             `json:"delegator"`
+	Authority          string             `json:"authority"`
+	Caveats            json.RawMessage    `json:"caveats"`
+	Salt               string             `json:"salt"`
+	ExpiresAt          pgtype.Timestamptz `json:"expires_at"`
+	SubscriptionID     uuid.UUID          `json:"subscription_id"`
+	WorkspaceID        uuid.UUID          `json:"workspace_id"`
+	CustomerID         uuid.UUID          `json:"customer_id"`
+	TokenAmount        int32              `json:"token_amount"`
+	NextRedemptionDate pgtype.Timestamptz `json:"next_redemption_date"`
+	NetworkID          uuid.UUID          `json:"network_id"`
+	NetworkType        NetworkType        `json:"network_type"`
+}
+
+// Active delegations behind live subscriptions that have not been checked since the cutoff, least recently checked first
+func (q *Queries) ListDelegationsDueForCheck(ctx context.Context, arg ListDelegationsDueForCheckParams) ([]ListDelegationsDueForCheckRow, error) {
+	rows, err := q.db.Query(ctx, listDelegationsDueForCheck, arg.CheckedBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDelegationsDueForCheckRow{}
+	for rows.Next() {
+		var i ListDelegationsDueForCheckRow
+		if err := rows.Scan(
+			&i.DelegationID,
+			&i.Delegate,
+			&i.Delegator,
+			&i.Authority,
+			&i.Caveats,
+			&i.Salt,
+			&i.ExpiresAt,
+			&i.SubscriptionID,
+			&i.WorkspaceID,
+			&i.CustomerID,
+			&i.TokenAmount,
+			&i.NextRedemptionDate,
+			&i.NetworkID,
+			&i.NetworkType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listDelegationsWithPagination = `-- name: ListDelegationsWithPagination :many
-SELECT id, delegate, delegator, authority, caveats, salt, signature, created_at, updated_at, deleted_at FROM delegation_data
+SELECT id, delegate, delegator, authority, caveats, salt, signature, status, expires_at, last_checked_at, created_at, updated_at, deleted_at FROM delegation_data
 WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
@@ -244,6 +347,9 @@ func (q *Queries) ListDelegationsWithPagination(ctx context.Context, arg ListDel
 			&i.Caveats,
 			&i.Salt,
 			&i.Signature,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.LastCheckedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
@@ -269,7 +375,7 @@ SET
     signature = COALESCE($7, signature),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, delegate, delegator, authority, caveats, salt, signature, created_at, updated_at, deleted_at
+RETURNING id, delegate, delegator, authority, caveats, salt, signature, status, expires_at, last_checked_at, created_at, updated_at, deleted_at
 `
 
 type UpdateDelegationDataParams struct {
@@ -301,6 +407,48 @@ func (q *Queries) UpdateDelegationData(ctx context.Context, arg UpdateDelegation
 		&i.Caveats,
 		&i.Salt,
 		&i.Signature,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.LastCheckedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateDelegationStatus = `-- name: UpdateDelegationStatus :one
+UPDATE delegation_data
+SET
+    status = $2,
+    expires_at = $3,
+    last_checked_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, delegate, delegator, authority, caveats, salt, signature, status, expires_at, last_checked_at, created_at, updated_at, deleted_at
+`
+
+type UpdateDelegationStatusParams struct {
+	ID        uuid.UUID          `json:"id"`
+	Status    string             `json:"status"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+// Records the outcome of a delegation check
+func (q *Queries) UpdateDelegationStatus(ctx context.Context, arg UpdateDelegationStatusParams) (DelegationDatum, error) {
+	row := q.db.QueryRow(ctx, updateDelegationStatus, arg.ID, arg.Status, arg.ExpiresAt)
+	var i DelegationDatum
+	err := row.Scan(
+		&i.ID,
+		&i.Delegate,
+		&i.Delegator,
+		&i.Authority,
+		&i.Caveats,
+		&i.Salt,
+		&i.Signature,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.LastCheckedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -68,9 +68,10 @@ INSERT INTO gas_sponsorship_configs (
     monthly_budget_usd_cents,
     sponsor_for_products,
     sponsor_for_customers,
-    sponsor_for_tiers
+    sponsor_for_tiers,
+    per_customer_monthly_cap_usd_cents
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 ON CONFLICT (workspace_id)
 DO UPDATE SET
@@ -81,19 +82,21 @@ DO UPDATE SET
     sponsor_for_products = EXCLUDED.sponsor_for_products,
     sponsor_for_customers = EXCLUDED.sponsor_for_customers,
     sponsor_for_tiers = EXCLUDED.sponsor_for_tiers,
+    per_customer_monthly_cap_usd_cents = EXCLUDED.per_customer_monthly_cap_usd_cents,
     updated_at = CURRENT_TIMESTAMP
-RETURNING id, workspace_id, sponsorship_enabled, sponsor_customer_gas, sponsor_threshold_usd_cents, monthly_budget_usd_cents, sponsor_for_products, sponsor_for_customers, sponsor_for_tiers, current_month_spent_cents, last_reset_date, created_at, updated_at
+RETURNING id, workspace_id, sponsorship_enabled, sponsor_customer_gas, sponsor_threshold_usd_cents, monthly_budget_usd_cents, per_customer_monthly_cap_usd_cents, sponsor_for_products, sponsor_for_customers, sponsor_for_tiers, current_month_spent_cents, current_month_reserved_cents, last_reset_date, created_at, updated_at
 `
 
 type CreateGasSponsorshipConfigParams struct {
-	WorkspaceID              uuid.UUID   `json:"workspace_id"`
-	SponsorshipEnabled       pgtype.Bool `json:"sponsorship_enabled"`
-	SponsorCustomerGas       pgtype.Bool `json:"sponsor_customer_gas"`
-	SponsorThresholdUsdCents pgtype.Int8 `json:"sponsor_threshold_usd_cents"`
-	MonthlyBudgetUsdCents    pgtype.Int8 `json:"monthly_budget_usd_cents"`
-	SponsorForProducts       []byte      `json:"sponsor_for_products"`
-	SponsorForCustomers      []byte      `json:"sponsor_for_customers"`
-	SponsorForTiers          []byte      `json:"sponsor_for_tiers"`
+	WorkspaceID                   uuid.UUID   `json:"workspace_id"`
+	SponsorshipEnabled            pgtype.Bool `json:"sponsorship_enabled"`
+	SponsorCustomerGas            pgtype.Bool `json:"sponsor_customer_gas"`
+	SponsorThresholdUsdCents      pgtype.Int8 `json:"sponsor_threshold_usd_cents"`
+	MonthlyBudgetUsdCents         pgtype.Int8 `json:"monthly_budget_usd_cents"`
+	SponsorForProducts            []byte      `json:"sponsor_for_products"`
+	SponsorForCustomers           []byte      `json:"sponsor_for_customers"`
+	SponsorForTiers               []byte      `json:"sponsor_for_tiers"`
+	PerCustomerMonthlyCapUsdCents pgtype.Int8 `json:"per_customer_monthly_cap_usd_cents"`
 }
 
 func (q *Queries) CreateGasSponsorshipConfig(ctx context.Context, arg CreateGasSponsorshipConfigParams) (GasSponsorshipConfig, error) {
@@ -106,6 +109,7 @@ func (q *Queries) CreateGasSponsorshipConfig(ctx context.Context, arg CreateGasS
 		arg.SponsorForProducts,
 		arg.SponsorForCustomers,
 		arg.SponsorForTiers,
+		arg.PerCustomerMonthlyCapUsdCents,
 	)
 	var i GasSponsorshipConfig
 	err := row.Scan(
@@ -115,10 +119,12 @@ func (q *Queries) CreateGasSponsorshipConfig(ctx context.Context, arg CreateGasS
 		&i.SponsorCustomerGas,
 		&i.SponsorThresholdUsdCents,
 		&i.MonthlyBudgetUsdCents,
+		&i.PerCustomerMonthlyCapUsdCents,
 		&i.SponsorForProducts,
 		&i.SponsorForCustomers,
 		&i.SponsorForTiers,
 		&i.CurrentMonthSpentCents,
+		&i.CurrentMonthReservedCents,
 		&i.LastResetDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -127,7 +133,7 @@ func (q *Queries) CreateGasSponsorshipConfig(ctx context.Context, arg CreateGasS
 }
 
 const getActiveGasSponsorships = `-- name: GetActiveGasSponsorships :many
-SELECT id, workspace_id, sponsorship_enabled, sponsor_customer_gas, sponsor_threshold_usd_cents, monthly_budget_usd_cents, sponsor_for_products, sponsor_for_customers, sponsor_for_tiers, current_month_spent_cents, last_reset_date, created_at, updated_at FROM gas_sponsorship_configs
+SELECT id, workspace_id, sponsorship_enabled, sponsor_customer_gas, sponsor_threshold_usd_cents, monthly_budget_usd_cents, per_customer_monthly_cap_usd_cents, sponsor_for_products, sponsor_for_customers, sponsor_for_tiers, current_month_spent_cents, current_month_reserved_cents, last_reset_date, created_at, updated_at FROM gas_sponsorship_configs
 WHERE sponsorship_enabled = true
     AND sponsor_customer_gas = true
     AND (monthly_budget_usd_cents IS NULL OR current_month_spent_cents < monthly_budget_usd_cents)
@@ -149,10 +155,12 @@ func (q *Queries) GetActiveGasSponsorships(ctx context.Context) ([]GasSponsorshi
 			&i.SponsorCustomerGas,
 			&i.SponsorThresholdUsdCents,
 			&i.MonthlyBudgetUsdCents,
+			&i.PerCustomerMonthlyCapUsdCents,
 			&i.SponsorForProducts,
 			&i.SponsorForCustomers,
 			&i.SponsorForTiers,
 			&i.CurrentMonthSpentCents,
+			&i.CurrentMonthReservedCents,
 			&i.LastResetDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
@@ -168,7 +176,7 @@ func (q *Queries) GetActiveGasSponsorships(ctx context.Context) ([]GasSponsorshi
 }
 
 const getGasSponsorshipConfig = `-- name: GetGasSponsorshipConfig :one
-SELECT id, workspace_id, sponsorship_enabled, sponsor_customer_gas, sponsor_threshold_usd_cents, monthly_budget_usd_cents, sponsor_for_products, sponsor_for_customers, sponsor_for_tiers, current_month_spent_cents, last_reset_date, created_at, updated_at FROM gas_sponsorship_configs
+SELECT id, workspace_id, sponsorship_enabled, sponsor_customer_gas, sponsor_threshold_usd_cents, monthly_budget_usd_cents, per_customer_monthly_cap_usd_cents, sponsor_for_products, sponsor_for_customers, sponsor_for_tiers, current_month_spent_cents, current_month_reserved_cents, last_reset_date, created_at, updated_at FROM gas_sponsorship_configs
 WHERE workspace_id = $1
 `
 
@@ -182,10 +190,12 @@ func (q *Queries) GetGasSponsorshipConfig(ctx context.Context, workspaceID uuid.
 		&i.SponsorCustomerGas,
 		&i.SponsorThresholdUsdCents,
 		&i.MonthlyBudgetUsdCents,
+		&i.PerCustomerMonthlyCapUsdCents,
 		&i.SponsorForProducts,
 		&i.SponsorForCustomers,
 		&i.SponsorForTiers,
 		&i.CurrentMonthSpentCents,
+		&i.CurrentMonthReservedCents,
 		&i.LastResetDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -252,7 +262,7 @@ func (q *Queries) GetGasSponsorshipsByProduct(ctx context.Context, arg GetGasSpo
 }
 
 const getSponsorshipConfigsNeedingReset = `-- name: GetSponsorshipConfigsNeedingReset :many
-SELECT id, workspace_id, sponsorship_enabled, sponsor_customer_gas, sponsor_threshold_usd_cents, monthly_budget_usd_cents, sponsor_for_products, sponsor_for_customers, sponsor_for_tiers, current_month_spent_cents, last_reset_date, created_at, updated_at FROM gas_sponsorship_configs
+SELECT id, workspace_id, sponsorship_enabled, sponsor_customer_gas, sponsor_threshold_usd_cents, monthly_budget_usd_cents, per_customer_monthly_cap_usd_cents, sponsor_for_products, sponsor_for_customers, sponsor_for_tiers, current_month_spent_cents, current_month_reserved_cents, last_reset_date, created_at, updated_at FROM gas_sponsorship_configs
 WHERE sponsorship_enabled = true
     AND (last_reset_date IS NULL 
         OR last_reset_date < date_trunc('month', $1::date))
@@ -275,10 +285,12 @@ func (q *Queries) GetSponsorshipConfigsNeedingReset(ctx context.Context, dollar_
 			&i.SponsorCustomerGas,
 			&i.SponsorThresholdUsdCents,
 			&i.MonthlyBudgetUsdCents,
+			&i.PerCustomerMonthlyCapUsdCents,
 			&i.SponsorForProducts,
 			&i.SponsorForCustomers,
 			&i.SponsorForTiers,
 			&i.CurrentMonthSpentCents,
+			&i.CurrentMonthReservedCents,
 			&i.LastResetDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
@@ -350,20 +362,22 @@ SET
     sponsor_for_products = COALESCE($6, sponsor_for_products),
     sponsor_for_customers = COALESCE($7, sponsor_for_customers),
     sponsor_for_tiers = COALESCE($8, sponsor_for_tiers),
+    per_customer_monthly_cap_usd_cents = COALESCE($9, per_customer_monthly_cap_usd_cents),
     updated_at = CURRENT_TIMESTAMP
 WHERE workspace_id = $1
-RETURNING id, workspace_id, sponsorship_enabled, sponsor_customer_gas, sponsor_threshold_usd_cents, monthly_budget_usd_cents, sponsor_for_products, sponsor_for_customers, sponsor_for_tiers, current_month_spent_cents, last_reset_date, created_at, updated_at
+RETURNING id, workspace_id, sponsorship_enabled, sponsor_customer_gas, sponsor_threshold_usd_cents, monthly_budget_usd_cents, per_customer_monthly_cap_usd_cents, sponsor_for_products, sponsor_for_customers, sponsor_for_tiers, current_month_spent_cents, current_month_reserved_cents, last_reset_date, created_at, updated_at
 `
 
 type UpdateGasSponsorshipConfigParams struct {
-	WorkspaceID              uuid.UUID   `json:"workspace_id"`
-	SponsorshipEnabled       pgtype.Bool `json:"sponsorship_enabled"`
-	SponsorCustomerGas       pgtype.Bool `json:"sponsor_customer_gas"`
-	SponsorThresholdUsdCents pgtype.Int8 `json:"sponsor_threshold_usd_cents"`
-	MonthlyBudgetUsdCents    pgtype.Int8 `json:"monthly_budget_usd_cents"`
-	SponsorForProducts       []byte      `json:"sponsor_for_products"`
-	SponsorForCustomers      []byte      `json:"sponsor_for_customers"`
-	SponsorForTiers          []byte      `json:"sponsor_for_tiers"`
+	WorkspaceID                   uuid.UUID   `json:"workspace_id"`
+	SponsorshipEnabled            pgtype.Bool `json:"sponsorship_enabled"`
+	SponsorCustomerGas            pgtype.Bool `json:"sponsor_customer_gas"`
+	SponsorThresholdUsdCents      pgtype.Int8 `json:"sponsor_threshold_usd_cents"`
+	MonthlyBudgetUsdCents         pgtype.Int8 `json:"monthly_budget_usd_cents"`
+	SponsorForProducts            []byte      `json:"sponsor_for_products"`
+	SponsorForCustomers           []byte      `json:"sponsor_for_customers"`
+	SponsorForTiers               []byte      `json:"sponsor_for_tiers"`
+	PerCustomerMonthlyCapUsdCents pgtype.Int8 `json:"per_customer_monthly_cap_usd_cents"`
 }
 
 func (q *Queries) UpdateGasSponsorshipConfig(ctx context.Context, arg UpdateGasSponsorshipConfigParams) (GasSponsorshipConfig, error) {
@@ -376,6 +390,7 @@ func (q *Queries) UpdateGasSponsorshipConfig(ctx context.Context, arg UpdateGasS
 		arg.SponsorForProducts,
 		arg.SponsorForCustomers,
 		arg.SponsorForTiers,
+		arg.PerCustomerMonthlyCapUsdCents,
 	)
 	var i GasSponsorshipConfig
 	err := row.Scan(
@@ -385,10 +400,12 @@ func (q *Queries) UpdateGasSponsorshipConfig(ctx context.Context, arg UpdateGasS
 		&i.SponsorCustomerGas,
 		&i.SponsorThresholdUsdCents,
 		&i.MonthlyBudgetUsdCents,
+		&i.PerCustomerMonthlyCapUsdCents,
 		&i.SponsorForProducts,
 		&i.SponsorForCustomers,
 		&i.SponsorForTiers,
 		&i.CurrentMonthSpentCents,
+		&i.CurrentMonthReservedCents,
 		&i.LastResetDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
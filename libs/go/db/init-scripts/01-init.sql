@@ -11,7 +11,7 @@ CREATE TYPE interval_type AS ENUM ('1min', '5mins', 'daily', 'week', 'month', 'y
 CREATE TYPE network_type AS ENUM ('evm', 'solana', 'cosmos', 'bitcoin', 'polkadot');
 -- Currency enum removed - using fiat_currencies table instead
 CREATE TYPE wallet_type AS ENUM ('wallet', 'circle_wallet', 'web3auth');
-CREATE TYPE circle_network_type AS ENUM ('ARB', 'ARB-SEPOLIA', 'ETH', 'ETH-SEPOLIA', 'MATIC', 'MATIC-AMOY', 'OP', 'OP-SEPOLIA', 'BASE', 'BASE-SEPOLIA', 'UNI', 'UNI-SEPOLIA');
+CREATE TYPE circle_network_type AS ENUM ('ARB', 'ARB-SEPOLIA', 'ETH', 'ETH-SEPOLIA', 'MATIC', 'MATIC-AMOY', 'OP', 'OP-SEPOLIA', 'BASE', 'BASE-SEPOLIA', 'UNI', 'UNI-SEPOLIA', 'SOL', 'SOL-DEVNET');
 CREATE TYPE subscription_status AS ENUM ('active', 'canceled', 'expired', 'overdue', 'suspended', 'failed', 'completed', 'trial');
 CREATE TYPE subscription_event_type AS ENUM (
     'create', 
@@ -234,9 +234,13 @@ CREATE TABLE networks (
     -- Network performance
     average_block_time_ms INTEGER DEFAULT 2000,
     peak_hours_multiplier DECIMAL(4,2) DEFAULT 1.5,
+    -- Confirmation tracking
+    confirmation_blocks INTEGER NOT NULL DEFAULT 3, -- Blocks a payment's transaction needs before it counts as confirmed
+    finality_blocks INTEGER NOT NULL DEFAULT 12, -- Blocks after which a payment is no longer expected to be reorged out
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    deleted_at TIMESTAMP WITH TIME ZONE
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT check_confirmation_blocks CHECK (confirmation_blocks > 0 AND finality_blocks >= confirmation_blocks)
 );
 
 -- Wallets table (depends on workspaces, networks)
@@ -287,6 +291,7 @@ CREATE TABLE customer_wallets (
     network_type network_type NOT NULL,
     nickname TEXT,
     ens TEXT,
+    ens_resolved_at TIMESTAMP WITH TIME ZONE, -- When the ENS or Basenames primary name was last looked up
     is_primary BOOLEAN DEFAULT false,
     verified BOOLEAN DEFAULT false,
     last_used_at TIMESTAMP WITH TIME ZONE,
@@ -417,11 +422,28 @@ CREATE TABLE delegation_data (
     caveats JSONB NOT NULL DEFAULT '[]'::jsonb,
     salt TEXT NOT NULL,
     signature TEXT NOT NULL,
+    -- Lifecycle state kept up to date by the delegation monitor
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'revoked', 'expired', 'exhausted')),
+    expires_at TIMESTAMP WITH TIME ZONE, -- From a timestamp caveat, if the delegation has one
+    last_checked_at TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP WITH TIME ZONE
 );
 
+-- Solana token account approvals verified on-chain (depends on delegation_data, products_tokens). Every merchant
+-- shares the payment delegate, so an approval is bound to the product token named in its memo and backs one delegation
+CREATE TABLE spl_approvals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    signature TEXT NOT NULL UNIQUE, -- The approval transaction
+    delegation_id UUID NOT NULL UNIQUE REFERENCES delegation_data(id),
+    product_token_id UUID NOT NULL REFERENCES products_tokens(id),
+    token_account TEXT NOT NULL,
+    owner TEXT NOT NULL,
+    slot BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Subscriptions table (depends on customers, products, products_tokens, delegation_data, customer_wallets) - WITH PAYMENT SYNC COLUMNS
 CREATE TABLE subscriptions (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -468,6 +490,85 @@ CREATE TABLE subscription_events (
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Subscription Renewals table (depends on subscriptions)
+-- One row per billing period claimed by the renewal engine. The idempotency key stops a period from being
+-- redeemed twice, and the lease keeps overlapping processor runs from working on the same period
+CREATE TABLE subscription_renewals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id),
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE, -- renewal:<subscription_id>:<period_due_at unix seconds>
+    period_due_at TIMESTAMP WITH TIME ZONE NOT NULL, -- The next_redemption_date being renewed
+    status VARCHAR(20) NOT NULL DEFAULT 'claimed' CHECK (status IN ('claimed', 'redeeming', 'redeemed', 'succeeded', 'failed', 'interrupted')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    lease_owner VARCHAR(255),
+    lease_expires_at TIMESTAMP WITH TIME ZONE,
+    transaction_hash TEXT, -- Set once the redemption went through, before the payment is recorded
+    error_message TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Redemption Tasks table (depends on subscriptions and delegation_data)
+-- Durable queue for the redemption processor. Workers claim visible tasks with FOR UPDATE SKIP LOCKED and hide
+-- them for the visibility timeout; a task whose worker stopped becomes visible again once it runs out
+CREATE TABLE redemption_tasks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id),
+    delegation_id UUID NOT NULL REFERENCES delegation_data(id),
+    product_id UUID NOT NULL REFERENCES products(id),
+    product_token_id UUID NOT NULL REFERENCES products_tokens(id),
+    amount_in_cents INTEGER NOT NULL,
+    metadata JSONB DEFAULT '{}'::jsonb,
+    idempotency_key VARCHAR(255) UNIQUE, -- Enqueuing the same key again returns the existing task
+    priority INTEGER NOT NULL DEFAULT 0, -- Higher priorities are claimed first
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'succeeded', 'dead_lettered')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    visible_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP, -- Not claimed before this time
+    locked_by VARCHAR(255),
+    redemption_started_at TIMESTAMP WITH TIME ZONE, -- Set before the delegation is redeemed
+    transaction_hash TEXT,
+    last_error TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    dead_lettered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Circuit Breakers table
+-- Breaker state shared by every worker and process calling an external dependency such as the delegation server
+CREATE TABLE circuit_breakers (
+    name VARCHAR(100) PRIMARY KEY,
+    state VARCHAR(20) NOT NULL DEFAULT 'closed' CHECK (state IN ('closed', 'open', 'half_open')),
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    failure_threshold INTEGER NOT NULL,
+    last_failure_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    state_changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Subscription Reauthorizations table (depends on subscriptions, workspaces, customers and delegation_data)
+-- Opened by the delegation monitor when a subscription's delegation can no longer pay for it. The customer is
+-- emailed a link to sign a replacement delegation, which completes the reauthorization
+CREATE TABLE subscription_reauthorizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    customer_id UUID NOT NULL REFERENCES customers(id),
+    delegation_id UUID NOT NULL REFERENCES delegation_data(id), -- The delegation that needs replacing
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('revoked', 'expired', 'expiring', 'allowance_exhausted')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'completed')),
+    replacement_delegation_id UUID REFERENCES delegation_data(id),
+    notification_count INTEGER NOT NULL DEFAULT 0,
+    notified_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Subscription Line Items table
 -- Tracks individual line items within a subscription (base product + addons)
 CREATE TABLE subscription_line_items (
@@ -762,6 +863,7 @@ CREATE INDEX idx_networks_active ON networks(active) WHERE deleted_at IS NULL;
 -- wallets
 CREATE INDEX idx_wallets_workspace_id ON wallets(workspace_id);
 CREATE INDEX idx_wallets_address ON wallets(wallet_address);
+CREATE INDEX idx_wallets_lower_wallet_address ON wallets(LOWER(wallet_address)) WHERE network_type = 'evm';
 CREATE INDEX idx_wallets_network_type ON wallets(network_type);
 CREATE INDEX idx_wallets_is_primary ON wallets(is_primary) WHERE deleted_at IS NULL;
 CREATE INDEX idx_wallets_network_id ON wallets(network_id);
@@ -782,7 +884,10 @@ CREATE INDEX idx_customer_wallets_wallet_address ON customer_wallets(wallet_addr
 CREATE INDEX idx_customer_wallets_network_type ON customer_wallets(network_type);
 CREATE INDEX idx_customer_wallets_is_primary ON customer_wallets(is_primary) WHERE deleted_at IS NULL;
 CREATE INDEX idx_customer_wallets_verified ON customer_wallets(verified) WHERE deleted_at IS NULL;
-CREATE UNIQUE INDEX customer_wallets_customer_address_network_unique_idx ON customer_wallets(customer_id, wallet_address, network_type) WHERE deleted_at IS NULL;
+-- EVM addresses are unique whatever their casing; other networks' addresses are case-sensitive
+CREATE UNIQUE INDEX customer_wallets_customer_address_network_unique_idx ON customer_wallets(customer_id, (CASE WHEN network_type = 'evm' THEN LOWER(wallet_address) ELSE wallet_address END), network_type) WHERE deleted_at IS NULL;
+CREATE INDEX idx_customer_wallets_lower_wallet_address ON customer_wallets(LOWER(wallet_address)) WHERE network_type = 'evm';
+CREATE INDEX idx_customer_wallets_ens_resolved_at ON customer_wallets(ens_resolved_at NULLS FIRST) WHERE network_type = 'evm' AND deleted_at IS NULL;
 
 -- products
 CREATE INDEX idx_products_workspace_id ON products(workspace_id);
@@ -828,6 +933,7 @@ CREATE INDEX idx_product_addon_required ON product_addon_relationships(is_requir
 -- delegation_data
 CREATE INDEX idx_delegation_data_delegator ON delegation_data(delegator);
 CREATE INDEX idx_delegation_data_delegate ON delegation_data(delegate);
+CREATE INDEX idx_delegation_data_last_checked ON delegation_data(last_checked_at NULLS FIRST) WHERE status = 'active' AND deleted_at IS NULL;
 
 -- subscriptions
 CREATE INDEX idx_subscriptions_customer_id ON subscriptions(customer_id);
@@ -843,6 +949,20 @@ CREATE INDEX idx_subscriptions_payment_sync_status ON subscriptions(payment_sync
 CREATE INDEX idx_subscriptions_external_id ON subscriptions(external_id) WHERE deleted_at IS NULL;
 CREATE INDEX idx_subscriptions_num_id ON subscriptions(num_id);
 
+-- subscription_renewals
+CREATE INDEX idx_subscription_renewals_subscription ON subscription_renewals(subscription_id, period_due_at DESC);
+CREATE INDEX idx_subscription_renewals_redeemed ON subscription_renewals(period_due_at) WHERE status = 'redeemed';
+CREATE INDEX idx_subscription_renewals_status ON subscription_renewals(status, updated_at DESC);
+
+-- redemption_tasks
+CREATE INDEX idx_redemption_tasks_claimable ON redemption_tasks(priority DESC, visible_at) WHERE status IN ('pending', 'processing');
+CREATE INDEX idx_redemption_tasks_status ON redemption_tasks(status, created_at DESC);
+CREATE INDEX idx_redemption_tasks_subscription_id ON redemption_tasks(subscription_id);
+
+-- subscription_reauthorizations
+CREATE UNIQUE INDEX idx_subscription_reauthorizations_pending ON subscription_reauthorizations(subscription_id) WHERE status = 'pending';
+CREATE INDEX idx_subscription_reauthorizations_customer ON subscription_reauthorizations(customer_id, status);
+
 -- subscription_events
 CREATE INDEX idx_subscription_events_subscription_id ON subscription_events(subscription_id);
 CREATE INDEX idx_subscription_events_event_type ON subscription_events(event_type);
@@ -949,13 +1069,26 @@ VALUES
     ('Unichain Mainnet', 'Mainnet', 'evm', 'unichain-mainnet', 'UNI', 130, false, false, 'https://unichain.io', 'https://cryptologos.cc/logos/uniswap-uni-logo.png', 'Unichain', 'eip155', 1.2, 1.1, '500000', '100000', true, 2000, '{"slow":{"max_fee_per_gas":"50000000","max_priority_fee_per_gas":"50000000"},"standard":{"max_fee_per_gas":"100000000","max_priority_fee_per_gas":"100000000"},"fast":{"max_fee_per_gas":"200000000","max_priority_fee_per_gas":"150000000"}}')
 ON CONFLICT DO NOTHING;
 
+-- Solana clusters use the chain IDs of the Solana token list (101 mainnet-beta, 103 devnet). A slot is
+-- confirmed once a supermajority has voted on it and rooted after 32 more.
+INSERT INTO networks (name, type, network_type, rpc_id, circle_network_type, chain_id, is_testnet, active, block_explorer_url, logo_url, display_name, chain_namespace, supports_eip1559, average_block_time_ms, confirmation_blocks, finality_blocks, gas_priority_levels)
+VALUES
+    ('Solana Devnet', 'Devnet', 'solana', 'solana-devnet', 'SOL-DEVNET', 103, true, false, 'https://explorer.solana.com/?cluster=devnet', 'https://cryptologos.cc/logos/solana-sol-logo.png', 'Solana Devnet', 'solana', false, 400, 1, 32, '{}'),
+    ('Solana Mainnet', 'Mainnet', 'solana', 'solana-mainnet', 'SOL', 101, false, false, 'https://explorer.solana.com', 'https://cryptologos.cc/logos/solana-sol-logo.png', 'Solana', 'solana', false, 400, 1, 32, '{}')
+ON CONFLICT DO NOTHING;
+
 INSERT INTO tokens (network_id, name, symbol, contract_address, gas_token, active, decimals)
 VALUES 
     -- Ethereum Sepolia tokens
     ((SELECT id FROM networks WHERE chain_id = 11155111 AND deleted_at IS NULL), 'USD Coin', 'USDC', '0x1c7D4B196Cb0C7B01d743Fbc6116a902379C7238', false, true, 6),
     ((SELECT id FROM networks WHERE chain_id = 11155111 AND deleted_at IS NULL), 'Ethereum', 'ETH', '0xd38E5c25935291fFD51C9d66C3B7384494bb099A', true, true, 18),
     ((SELECT id FROM networks WHERE chain_id = 84532 AND deleted_at IS NULL), 'USD Coin', 'USDC', '0x036CbD53842c5426634e7929541eC2318f3dCF7e', false, true, 6),
-    ((SELECT id FROM networks WHERE chain_id = 84532 AND deleted_at IS NULL), 'Ethereum', 'ETH', '0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee', true, true, 18)
+    ((SELECT id FROM networks WHERE chain_id = 84532 AND deleted_at IS NULL), 'Ethereum', 'ETH', '0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee', true, true, 18),
+    -- Solana SPL tokens, by mint address. SOL is listed by the wrapped SOL mint.
+    ((SELECT id FROM networks WHERE chain_id = 103 AND deleted_at IS NULL), 'USD Coin', 'USDC', '4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDU', false, true, 6),
+    ((SELECT id FROM networks WHERE chain_id = 103 AND deleted_at IS NULL), 'Solana', 'SOL', 'So11111111111111111111111111111111111111112', true, true, 9),
+    ((SELECT id FROM networks WHERE chain_id = 101 AND deleted_at IS NULL), 'USD Coin', 'USDC', 'EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v', false, true, 6),
+    ((SELECT id FROM networks WHERE chain_id = 101 AND deleted_at IS NULL), 'Solana', 'SOL', 'So11111111111111111111111111111111111111112', true, true, 9)
 ON CONFLICT DO NOTHING;
 
 
@@ -1039,6 +1172,26 @@ CREATE TRIGGER set_subscription_events_updated_at
     FOR EACH ROW
     EXECUTE FUNCTION trigger_set_updated_at();
 
+CREATE TRIGGER set_subscription_renewals_updated_at
+    BEFORE UPDATE ON subscription_renewals
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TRIGGER set_redemption_tasks_updated_at
+    BEFORE UPDATE ON redemption_tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TRIGGER set_circuit_breakers_updated_at
+    BEFORE UPDATE ON circuit_breakers
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TRIGGER set_subscription_reauthorizations_updated_at
+    BEFORE UPDATE ON subscription_reauthorizations
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
 CREATE TRIGGER set_subscription_line_items_updated_at
     BEFORE UPDATE ON subscription_line_items
     FOR EACH ROW
@@ -1125,6 +1278,7 @@ CREATE TABLE payments (
     initiated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     completed_at TIMESTAMP WITH TIME ZONE,
     failed_at TIMESTAMP WITH TIME ZONE,
+    refunded_at TIMESTAMP WITH TIME ZONE,
     
     -- Metadata
     error_message TEXT,
@@ -1133,7 +1287,8 @@ CREATE TABLE payments (
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     
-    CONSTRAINT unique_transaction_hash UNIQUE(transaction_hash),
+    -- Batched redemptions pay several subscriptions in one transaction, so a hash is unique per subscription event
+    CONSTRAINT unique_transaction_hash UNIQUE(transaction_hash, subscription_event),
     CONSTRAINT unique_external_payment UNIQUE(workspace_id, external_payment_id, payment_provider),
     CONSTRAINT check_amount_breakdown CHECK (
         amount_in_cents = product_amount_cents + tax_amount_cents + gas_amount_cents - discount_amount_cents
@@ -1144,7 +1299,37 @@ CREATE TABLE payments (
 CREATE INDEX idx_payments_workspace_status ON payments(workspace_id, status);
 CREATE INDEX idx_payments_customer ON payments(customer_id);
 CREATE INDEX idx_payments_completed_at ON payments(workspace_id, completed_at);
+CREATE INDEX idx_payments_refunded_at ON payments(workspace_id, refunded_at) WHERE refunded_at IS NOT NULL;
 CREATE INDEX idx_payments_transaction_hash ON payments(transaction_hash) WHERE transaction_hash IS NOT NULL;
+-- NULLs are distinct in unique_transaction_hash, so payments without a subscription event are deduplicated here
+CREATE UNIQUE INDEX idx_payments_unique_transaction_hash_without_event ON payments(transaction_hash)
+    WHERE transaction_hash IS NOT NULL AND subscription_event IS NULL;
+
+-- Payment Confirmations table (depends on payments, networks)
+-- Follows a crypto payment's transaction until it reaches the network's finality depth. Payments are recorded as
+-- soon as the redemption returns a hash; a transaction that is dropped, replaced or reverted rolls the payment back
+CREATE TABLE payment_confirmations (
+    payment_id UUID PRIMARY KEY REFERENCES payments(id),
+    network_id UUID NOT NULL REFERENCES networks(id),
+    transaction_hash VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'confirmed', 'finalized', 'dropped', 'replaced', 'reverted')),
+    sender_address TEXT, -- Recorded when the transaction is first seen, to tell a replaced transaction from a dropped one
+    nonce BIGINT,
+    block_number BIGINT,
+    block_hash TEXT,
+    confirmations INTEGER NOT NULL DEFAULT 0,
+    reorg_count INTEGER NOT NULL DEFAULT 0, -- Times the transaction left the block it was seen in
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP, -- Last time the transaction was mined or in the mempool
+    checked_at TIMESTAMP WITH TIME ZONE,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    finalized_at TIMESTAMP WITH TIME ZONE,
+    rolled_back_at TIMESTAMP WITH TIME ZONE,
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_confirmations_unsettled ON payment_confirmations(checked_at NULLS FIRST) WHERE status IN ('pending', 'confirmed');
 
 -- Invoice Line Items table
 CREATE TABLE invoice_line_items (
@@ -1272,6 +1457,78 @@ CREATE INDEX idx_metrics_workspace_date ON dashboard_metrics(workspace_id, metri
 CREATE INDEX idx_metrics_hourly ON dashboard_metrics(workspace_id, metric_date, metric_hour) WHERE metric_type = 'hourly';
 CREATE INDEX idx_metrics_currency ON dashboard_metrics(workspace_id, fiat_currency, metric_date DESC);
 
+-- Cohort metrics table (precomputed logo and net revenue retention cohorts)
+CREATE TABLE cohort_metrics (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    cohort_by VARCHAR(20) NOT NULL CHECK (cohort_by IN ('month', 'product', 'network')),
+    lookback_months INTEGER NOT NULL CHECK (lookback_months > 0),
+    fiat_currency VARCHAR(3) NOT NULL REFERENCES fiat_currencies(code),
+    cohort_key TEXT NOT NULL,
+    cohort_label TEXT NOT NULL,
+    period_offset INTEGER NOT NULL CHECK (period_offset >= 0),
+    eligible_customers BIGINT NOT NULL DEFAULT 0,
+    active_customers BIGINT NOT NULL DEFAULT 0,
+    revenue_cents BIGINT NOT NULL DEFAULT 0,
+    starting_revenue_cents BIGINT NOT NULL DEFAULT 0,
+    calculated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, cohort_by, lookback_months, fiat_currency, cohort_key, period_offset)
+);
+
+-- Analytics report schedules (weekly or monthly exports emailed to a list of recipients)
+CREATE TABLE analytics_report_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    name VARCHAR(255) NOT NULL,
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('weekly', 'monthly')),
+    datasets TEXT[] NOT NULL, -- payments, invoices, subscriptions, mrr_movements, gas_fees
+    format VARCHAR(10) NOT NULL DEFAULT 'csv' CHECK (format IN ('csv', 'parquet')),
+    fiat_currency VARCHAR(3) REFERENCES fiat_currencies(code), -- NULL reports in the workspace default currency
+    recipients TEXT[] NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_analytics_report_schedules_workspace ON analytics_report_schedules(workspace_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_analytics_report_schedules_due ON analytics_report_schedules(next_run_at) WHERE active = true AND deleted_at IS NULL;
+
+-- Analytics export jobs (CSV or Parquet files of the underlying data, built asynchronously)
+CREATE TABLE analytics_export_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    report_schedule_id UUID REFERENCES analytics_report_schedules(id),
+    datasets TEXT[] NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'parquet')),
+    fiat_currency VARCHAR(3) NOT NULL REFERENCES fiat_currencies(code), -- Amounts are also converted to this currency
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE NOT NULL, -- Exclusive
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    files JSONB NOT NULL DEFAULT '[]', -- [{dataset, storage_key, content_type, row_count, size_bytes}]
+    error_message TEXT,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date > start_date)
+);
+
+CREATE INDEX idx_analytics_export_jobs_workspace ON analytics_export_jobs(workspace_id, created_at DESC);
+CREATE INDEX idx_analytics_export_jobs_queue ON analytics_export_jobs(created_at) WHERE status IN ('pending', 'running');
+
+CREATE TRIGGER set_analytics_report_schedules_updated_at
+    BEFORE UPDATE ON analytics_report_schedules
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TRIGGER set_analytics_export_jobs_updated_at
+    BEFORE UPDATE ON analytics_export_jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
 -- Payment Links table
 CREATE TABLE payment_links (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -1360,6 +1617,32 @@ CREATE TABLE gas_fee_payments (
 CREATE INDEX idx_gas_fee_payments_sponsor ON gas_fee_payments(sponsor_type, sponsor_id);
 CREATE INDEX idx_gas_fee_payments_created ON gas_fee_payments(created_at);
 
+-- Gas fee estimates made before a payment's transaction was sent, kept to measure estimate accuracy
+-- against the actual fees in gas_fee_payments
+CREATE TABLE gas_fee_estimates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    payment_id UUID REFERENCES payments(id) ON DELETE CASCADE UNIQUE,
+    network_id UUID NOT NULL REFERENCES networks(id),
+
+    -- 'fee_history' when estimated from live eth_feeHistory data, 'static' for the per-network fallback
+    estimate_source VARCHAR(20) NOT NULL CHECK (estimate_source IN ('fee_history', 'static')),
+    estimated_gas_units BIGINT NOT NULL,
+    gas_units_simulated BOOLEAN NOT NULL DEFAULT FALSE, -- Gas units came from eth_estimateGas
+    gas_price_wei TEXT NOT NULL, -- Expected effective gas price
+    base_fee_wei TEXT, -- Next block base fee for EIP-1559 networks
+    priority_fee_wei TEXT, -- Median priority fee for EIP-1559 networks
+    max_fee_per_gas_wei TEXT, -- Fee cap covering base fee growth
+    l1_data_fee_wei TEXT, -- L1 data fee for OP-stack networks
+    estimated_cost_wei TEXT NOT NULL,
+    estimated_cost_usd_cents BIGINT NOT NULL,
+    confidence DOUBLE PRECISION NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_gas_fee_estimates_workspace ON gas_fee_estimates(workspace_id, created_at);
+
 -- Gas Sponsorship Configs table
 CREATE TABLE gas_sponsorship_configs (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -1370,6 +1653,7 @@ CREATE TABLE gas_sponsorship_configs (
     sponsor_customer_gas BOOLEAN DEFAULT FALSE, -- Merchant sponsors customer gas
     sponsor_threshold_usd_cents BIGINT, -- Max sponsorship per transaction
     monthly_budget_usd_cents BIGINT, -- Monthly sponsorship budget
+    per_customer_monthly_cap_usd_cents BIGINT, -- Monthly sponsorship cap per customer
     
     -- Rules
     sponsor_for_products JSONB DEFAULT '[]'::jsonb, -- Array of product IDs
@@ -1378,6 +1662,7 @@ CREATE TABLE gas_sponsorship_configs (
     
     -- Tracking
     current_month_spent_cents BIGINT DEFAULT 0,
+    current_month_reserved_cents BIGINT NOT NULL DEFAULT 0, -- Held for in-flight sponsored transactions
     last_reset_date DATE,
     
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -1386,6 +1671,117 @@ CREATE TABLE gas_sponsorship_configs (
     UNIQUE(workspace_id)
 );
 
+-- Ordered sponsorship policy rules; the first active rule whose conditions all match decides.
+-- Workspaces without rules fall back to the product/customer/tier lists on gas_sponsorship_configs.
+CREATE TABLE gas_sponsorship_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    name VARCHAR(255) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0, -- Lower priorities are evaluated first
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    
+    -- Conditions, all of which must match; an absent condition matches everything
+    -- Format: {network_ids, token_ids, product_ids, customer_tiers, transaction_types, max_gas_price_gwei, time_window}
+    conditions JSONB NOT NULL DEFAULT '{}'::jsonb,
+    
+    -- Action
+    action_type VARCHAR(20) NOT NULL CHECK (action_type IN ('sponsor_full', 'sponsor_up_to', 'split_percentage')),
+    sponsor_up_to_usd_cents BIGINT, -- Per-transaction ceiling for sponsor_up_to
+    sponsor_percentage INTEGER CHECK (sponsor_percentage BETWEEN 1 AND 100), -- Merchant share for split_percentage
+    
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_gas_sponsorship_rules_workspace ON gas_sponsorship_rules(workspace_id, priority) WHERE deleted_at IS NULL;
+
+-- Sponsored gas per customer and calendar month (UTC), for per-customer caps
+CREATE TABLE gas_sponsorship_customer_spending (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    customer_id UUID NOT NULL REFERENCES customers(id),
+    period_start DATE NOT NULL, -- First day of the month
+    spent_usd_cents BIGINT NOT NULL DEFAULT 0,
+    reserved_usd_cents BIGINT NOT NULL DEFAULT 0, -- Held for in-flight sponsored transactions
+    sponsored_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (workspace_id, customer_id, period_start)
+);
+
+-- Budget held for a sponsored transaction from the sponsorship decision until the transaction
+-- confirms (settled with the actual gas cost) or fails (released)
+CREATE TABLE gas_sponsorship_reservations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    customer_id UUID REFERENCES customers(id),
+    payment_id UUID REFERENCES payments(id),
+    rule_id UUID REFERENCES gas_sponsorship_rules(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'reserved' CHECK (status IN ('reserved', 'settled', 'released')),
+    
+    reserved_usd_cents BIGINT NOT NULL CHECK (reserved_usd_cents > 0), -- Most the transaction may draw from the budget
+    settled_usd_cents BIGINT, -- Sponsored share of the actual gas cost
+    sponsor_percentage INTEGER NOT NULL DEFAULT 100 CHECK (sponsor_percentage BETWEEN 1 AND 100),
+    customer_reserved BOOLEAN NOT NULL DEFAULT FALSE, -- Whether the hold also counts against the customer's month
+    period_start DATE NOT NULL, -- Customer spending month the hold belongs to
+    release_reason TEXT,
+    
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    settled_at TIMESTAMP WITH TIME ZONE,
+    released_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_gas_sponsorship_reservations_payment ON gas_sponsorship_reservations(payment_id) WHERE payment_id IS NOT NULL;
+CREATE INDEX idx_gas_sponsorship_reservations_expiry ON gas_sponsorship_reservations(expires_at) WHERE status = 'reserved';
+
+-- Append-only audit trail of every movement of a workspace's sponsorship budget
+CREATE TABLE gas_sponsorship_ledger (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    reservation_id UUID NOT NULL REFERENCES gas_sponsorship_reservations(id),
+    customer_id UUID REFERENCES customers(id),
+    payment_id UUID REFERENCES payments(id),
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('reserve', 'settle', 'release')),
+    amount_usd_cents BIGINT NOT NULL,
+    
+    -- Workspace budget after the entry
+    budget_spent_cents BIGINT NOT NULL,
+    budget_reserved_cents BIGINT NOT NULL,
+    monthly_budget_usd_cents BIGINT,
+    
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_gas_sponsorship_ledger_workspace ON gas_sponsorship_ledger(workspace_id, created_at DESC);
+
+CREATE OR REPLACE FUNCTION prevent_gas_sponsorship_ledger_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'gas_sponsorship_ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER gas_sponsorship_ledger_append_only
+    BEFORE UPDATE OR DELETE ON gas_sponsorship_ledger
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_gas_sponsorship_ledger_changes();
+
+-- Budget thresholds already alerted on, at most once per workspace, month and threshold
+CREATE TABLE gas_sponsorship_budget_alerts (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    period_start DATE NOT NULL,
+    threshold_percent INTEGER NOT NULL,
+    spent_usd_cents BIGINT NOT NULL,
+    monthly_budget_usd_cents BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (workspace_id, period_start, threshold_percent)
+);
+
 -- Update invoice_line_items to add foreign key for gas_fee_payments
 ALTER TABLE invoice_line_items 
 ADD CONSTRAINT fk_gas_fee_payment 
@@ -1412,7 +1808,7 @@ ADD COLUMN IF NOT EXISTS qr_code_data TEXT,
 ADD COLUMN IF NOT EXISTS tax_amount_cents BIGINT NOT NULL DEFAULT 0,
 ADD COLUMN IF NOT EXISTS tax_details JSONB DEFAULT '[]'::jsonb, -- Array of tax calculations
 ADD COLUMN IF NOT EXISTS customer_tax_id VARCHAR(255),
-ADD COLUMN IF NOT EXISTS customer_jurisdiction_id UUID, -- Will reference tax_jurisdictions(id) when created
+ADD COLUMN IF NOT EXISTS customer_jurisdiction_id UUID, -- References tax_jurisdictions(id), constraint added with that table
 ADD COLUMN IF NOT EXISTS reverse_charge_applies BOOLEAN DEFAULT FALSE;
 
 -- Add unique constraint for invoice numbers per workspace
@@ -1426,7 +1822,7 @@ ADD COLUMN IF NOT EXISTS currency VARCHAR(3) REFERENCES fiat_currencies(code);
 
 -- Update customers table for tax support
 ALTER TABLE customers 
-ADD COLUMN IF NOT EXISTS tax_jurisdiction_id UUID, -- Will reference tax_jurisdictions(id) when created
+ADD COLUMN IF NOT EXISTS tax_jurisdiction_id UUID, -- References tax_jurisdictions(id), constraint added with that table
 ADD COLUMN IF NOT EXISTS tax_id VARCHAR(255), -- VAT number, EIN, etc.
 ADD COLUMN IF NOT EXISTS tax_id_type VARCHAR(50), -- 'vat', 'ein', 'gst', etc.
 ADD COLUMN IF NOT EXISTS tax_id_verified BOOLEAN DEFAULT FALSE,
@@ -1447,6 +1843,11 @@ CREATE TRIGGER set_payments_updated_at
     FOR EACH ROW
     EXECUTE FUNCTION trigger_set_updated_at();
 
+CREATE TRIGGER set_payment_confirmations_updated_at
+    BEFORE UPDATE ON payment_confirmations
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
 CREATE TRIGGER set_invoice_line_items_updated_at
     BEFORE UPDATE ON invoice_line_items
     FOR EACH ROW
@@ -1467,6 +1868,16 @@ CREATE TRIGGER set_gas_sponsorship_configs_updated_at
     FOR EACH ROW
     EXECUTE FUNCTION trigger_set_updated_at();
 
+CREATE TRIGGER set_gas_sponsorship_rules_updated_at
+    BEFORE UPDATE ON gas_sponsorship_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TRIGGER set_gas_sponsorship_reservations_updated_at
+    BEFORE UPDATE ON gas_sponsorship_reservations
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
 -- ============================================================================
 -- DUNNING MANAGEMENT TABLES
 -- ============================================================================
@@ -1787,6 +2198,35 @@ CREATE INDEX idx_prorations_schedule_change ON subscription_prorations(schedule_
 CREATE INDEX idx_state_history_subscription ON subscription_state_history(subscription_id, occurred_at DESC);
 CREATE INDEX idx_state_history_schedule_change ON subscription_state_history(schedule_change_id);
 
+-- MRR movements ledger: one row per subscription change that moves monthly recurring revenue.
+-- Amounts are normalized to a monthly value in the subscription's currency, then converted to the
+-- workspace's reporting currency; reporting_mrr_delta_cents is NULL until an exchange rate is known.
+CREATE TABLE mrr_movements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id),
+    customer_id UUID NOT NULL REFERENCES customers(id),
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('new', 'expansion', 'contraction', 'churn', 'reactivation')),
+    source VARCHAR(20) NOT NULL CHECK (source IN ('create', 'upgrade', 'downgrade', 'cancel', 'pause', 'resume')),
+    interval_type interval_type,
+    currency VARCHAR(3) NOT NULL REFERENCES fiat_currencies(code),
+    from_amount_cents BIGINT NOT NULL,
+    to_amount_cents BIGINT NOT NULL,
+    from_mrr_cents BIGINT NOT NULL,
+    to_mrr_cents BIGINT NOT NULL,
+    mrr_delta_cents BIGINT NOT NULL,
+    reporting_currency VARCHAR(3) NOT NULL REFERENCES fiat_currencies(code),
+    exchange_rate DOUBLE PRECISION,
+    reporting_mrr_delta_cents BIGINT,
+    schedule_change_id UUID REFERENCES subscription_schedule_changes(id),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_mrr_movements_workspace ON mrr_movements(workspace_id, reporting_currency, occurred_at DESC);
+CREATE INDEX idx_mrr_movements_subscription ON mrr_movements(subscription_id, occurred_at DESC);
+CREATE INDEX idx_mrr_movements_pending ON mrr_movements(created_at) WHERE reporting_mrr_delta_cents IS NULL;
+
 -- Subscription management triggers
 CREATE TRIGGER set_subscription_schedule_changes_updated_at
     BEFORE UPDATE ON subscription_schedule_changes
@@ -1819,3 +2259,479 @@ ADD COLUMN IF NOT EXISTS notes TEXT,
 ADD COLUMN IF NOT EXISTS terms TEXT,
 ADD COLUMN IF NOT EXISTS footer TEXT;
 
+
+-- =====================================================
+-- RATE LIMITING TABLES
+-- =====================================================
+
+-- Rate limit quotas per workspace, optionally narrowed to a single API key and route class.
+-- The plan selects default budgets defined in code; explicit limits override the plan.
+CREATE TABLE rate_limit_quotas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    api_key_id UUID REFERENCES api_keys(id), -- NULL applies to every key in the workspace
+    plan VARCHAR(50) NOT NULL DEFAULT 'standard', -- free, standard, enterprise
+    route_class VARCHAR(50), -- public, auth, authenticated, admin; NULL applies to every class
+    
+    -- Overrides (NULL falls back to the plan default)
+    requests_per_window INTEGER,
+    window_seconds INTEGER,
+    
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Shared fixed-window counters for the Postgres rate limit backend.
+-- Unlogged because counters are short-lived and can be lost on crash without harm.
+CREATE UNLOGGED TABLE rate_limit_counters (
+    bucket_key TEXT NOT NULL,
+    window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    request_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (bucket_key, window_start)
+);
+
+-- Indexes for rate limiting tables
+CREATE UNIQUE INDEX idx_rate_limit_quotas_scope ON rate_limit_quotas(
+    workspace_id,
+    COALESCE(api_key_id, '00000000-0000-0000-0000-000000000000'::uuid),
+    COALESCE(route_class, '')
+);
+CREATE INDEX idx_rate_limit_counters_expires_at ON rate_limit_counters(expires_at);
+
+CREATE TRIGGER set_rate_limit_quotas_updated_at
+    BEFORE UPDATE ON rate_limit_quotas
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+
+-- =====================================================
+-- API KEY ROTATION AND USAGE TABLES
+-- =====================================================
+
+-- Track rotation lineage and unused-key notifications on API keys
+ALTER TABLE api_keys
+ADD COLUMN IF NOT EXISTS rotated_from_id UUID REFERENCES api_keys(id), -- Key this key replaced
+ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMP WITH TIME ZONE, -- When this key was replaced by a newer key
+ADD COLUMN IF NOT EXISTS unused_notified_at TIMESTAMP WITH TIME ZONE; -- Last unused-key notification
+
+-- Daily request counters per API key, written asynchronously by the auth middleware
+CREATE TABLE api_key_usage_daily (
+    api_key_id UUID NOT NULL REFERENCES api_keys(id),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    usage_date DATE NOT NULL,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    last_ip VARCHAR(45),
+    last_user_agent TEXT,
+    last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (api_key_id, usage_date)
+);
+
+-- Indexes for API key rotation and usage tables
+CREATE INDEX idx_api_keys_rotated_from_id ON api_keys(rotated_from_id) WHERE rotated_from_id IS NOT NULL;
+CREATE INDEX idx_api_key_usage_daily_workspace_date ON api_key_usage_daily(workspace_id, usage_date);
+
+
+-- =====================================================
+-- WEBHOOK SECURITY TABLES
+-- =====================================================
+
+-- Previous webhook signing secrets that remain valid during rotation.
+-- The current secret lives on workspace_payment_configurations; rows here expire after the grace window.
+CREATE TABLE workspace_webhook_secrets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    provider_name VARCHAR(50) NOT NULL, -- 'stripe', 'chargebee', etc.
+    webhook_secret_key TEXT NOT NULL, -- Encrypted webhook signing secret
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Provider event IDs accepted by the webhook receiver, used to reject replayed deliveries
+CREATE TABLE webhook_replay_cache (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    provider_name VARCHAR(50) NOT NULL,
+    provider_event_id VARCHAR(255) NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (workspace_id, provider_name, provider_event_id)
+);
+
+-- Indexes for webhook security tables
+CREATE INDEX idx_workspace_webhook_secrets_lookup ON workspace_webhook_secrets(workspace_id, provider_name, expires_at);
+CREATE INDEX idx_webhook_replay_cache_expires_at ON webhook_replay_cache(expires_at);
+
+
+-- =====================================================
+-- CREDENTIAL ENVELOPE ENCRYPTION
+-- =====================================================
+
+-- Version of the key-encryption key that wrapped this row's data keys, e.g. 'local:v2' or 'kms:<key id>'.
+-- NULL marks rows still encrypted directly with the legacy static key.
+ALTER TABLE workspace_payment_configurations
+    ADD COLUMN encryption_key_version TEXT;
+
+CREATE INDEX idx_workspace_payment_configurations_key_version ON workspace_payment_configurations(encryption_key_version) WHERE deleted_at IS NULL;
+
+-- Retired webhook secrets are encrypted with the same keys and must be re-encrypted before a key is removed
+ALTER TABLE workspace_webhook_secrets
+    ADD COLUMN encryption_key_version TEXT;
+
+
+-- =====================================================
+-- CUSTOMER PORTAL TABLES
+-- =====================================================
+
+-- Per-workspace controls for what customers may do from the self-service portal.
+-- Workspaces without a row use the column defaults.
+CREATE TABLE customer_portal_settings (
+    workspace_id UUID PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    allow_cancel BOOLEAN NOT NULL DEFAULT true,
+    allow_pause BOOLEAN NOT NULL DEFAULT false,
+    allow_resume BOOLEAN NOT NULL DEFAULT true,
+    allow_update_billing BOOLEAN NOT NULL DEFAULT true,
+    allow_wallet_switch BOOLEAN NOT NULL DEFAULT true,
+    default_return_url TEXT,
+    session_ttl_seconds INTEGER NOT NULL DEFAULT 3600 CHECK (session_ttl_seconds BETWEEN 300 AND 86400),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Short-lived portal links a merchant generates for one of its customers.
+-- Only the SHA-256 hash of the session token is stored.
+CREATE TABLE customer_portal_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    return_url TEXT,
+    created_by_api_key_id UUID REFERENCES api_keys(id),
+    created_by_user_id UUID REFERENCES users(id),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes for customer portal tables
+CREATE INDEX idx_customer_portal_sessions_customer ON customer_portal_sessions(workspace_id, customer_id);
+CREATE INDEX idx_customer_portal_sessions_expires_at ON customer_portal_sessions(expires_at);
+
+-- =====================================================
+-- TAX JURISDICTIONS AND RATES
+-- =====================================================
+
+-- Places that levy tax. Codes follow the format used in tax calculations
+-- ('US-CA', 'CA-ON', 'EU-DE', 'UK'); local jurisdictions hang off their state.
+CREATE TABLE IF NOT EXISTS tax_jurisdictions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    jurisdiction_type VARCHAR(20) NOT NULL CHECK (jurisdiction_type IN ('country', 'state', 'province', 'county', 'city')),
+    country_code VARCHAR(2) NOT NULL, -- ISO country code
+    subdivision_code VARCHAR(10), -- State or province; NULL for country-level jurisdictions
+    locality VARCHAR(255), -- Upper-cased city or county name; NULL above local level
+    parent_id UUID REFERENCES tax_jurisdictions(id),
+    tax_type VARCHAR(20) NOT NULL DEFAULT 'tax', -- 'sales', 'vat', 'gst', 'tax'
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_tax_jurisdiction_locality CHECK (locality IS NULL OR (subdivision_code IS NOT NULL AND parent_id IS NOT NULL))
+);
+
+-- Versioned rates. A rate applies from effective_from until effective_to (exclusive);
+-- product_type 'default' applies to product types without their own rate.
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    jurisdiction_id UUID NOT NULL REFERENCES tax_jurisdictions(id) ON DELETE CASCADE,
+    product_type VARCHAR(50) NOT NULL DEFAULT 'default',
+    rate NUMERIC(9,6) NOT NULL CHECK (rate >= 0 AND rate < 1), -- 0.0725 for 7.25%
+    threshold_cents BIGINT NOT NULL DEFAULT 0, -- No tax below this amount
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    effective_to TIMESTAMP WITH TIME ZONE,
+    rules_version VARCHAR(50) NOT NULL, -- Recorded in the audit trail of every calculation using this rate
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_tax_rate_effective_period CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+-- Indexes for tax tables
+CREATE UNIQUE INDEX idx_tax_jurisdictions_location ON tax_jurisdictions(country_code, COALESCE(subdivision_code, ''), COALESCE(locality, ''));
+CREATE INDEX idx_tax_jurisdictions_parent ON tax_jurisdictions(parent_id) WHERE parent_id IS NOT NULL;
+CREATE INDEX idx_tax_rates_lookup ON tax_rates(jurisdiction_id, product_type, effective_from DESC);
+
+CREATE TRIGGER set_tax_jurisdictions_updated_at
+    BEFORE UPDATE ON tax_jurisdictions
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TRIGGER set_tax_rates_updated_at
+    BEFORE UPDATE ON tax_rates
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+-- Customers and invoices have referenced jurisdictions since the tax columns were added
+ALTER TABLE customers
+ADD CONSTRAINT fk_customers_tax_jurisdiction FOREIGN KEY (tax_jurisdiction_id) REFERENCES tax_jurisdictions(id);
+
+ALTER TABLE invoices
+ADD CONSTRAINT fk_invoices_customer_jurisdiction FOREIGN KEY (customer_jurisdiction_id) REFERENCES tax_jurisdictions(id);
+
+-- Invoices taxed by an external provider keep the provider's transaction for reconciliation
+ALTER TABLE invoices
+ADD COLUMN IF NOT EXISTS tax_provider VARCHAR(50),
+ADD COLUMN IF NOT EXISTS tax_provider_transaction_id VARCHAR(255);
+
+-- Seed jurisdictions: US and Canadian rates carried over from the previous built-in tables,
+-- UK VAT and the standard VAT rate of every EU member state
+INSERT INTO tax_jurisdictions (code, name, jurisdiction_type, country_code, subdivision_code, tax_type) VALUES
+    ('US', 'United States', 'country', 'US', NULL, 'sales'),
+    ('US-CA', 'United States - CA', 'state', 'US', 'CA', 'sales'),
+    ('US-NY', 'United States - NY', 'state', 'US', 'NY', 'sales'),
+    ('US-TX', 'United States - TX', 'state', 'US', 'TX', 'sales'),
+    ('US-FL', 'United States - FL', 'state', 'US', 'FL', 'sales'),
+    ('CA', 'Canada', 'country', 'CA', NULL, 'gst'),
+    ('CA-ON', 'Canada - ON', 'province', 'CA', 'ON', 'gst'),
+    ('CA-BC', 'Canada - BC', 'province', 'CA', 'BC', 'gst'),
+    ('CA-AB', 'Canada - AB', 'province', 'CA', 'AB', 'gst'),
+    ('CA-QC', 'Canada - QC', 'province', 'CA', 'QC', 'gst'),
+    ('UK', 'United Kingdom', 'country', 'GB', NULL, 'vat'),
+    ('EU-AT', 'Austria', 'country', 'AT', NULL, 'vat'),
+    ('EU-BE', 'Belgium', 'country', 'BE', NULL, 'vat'),
+    ('EU-BG', 'Bulgaria', 'country', 'BG', NULL, 'vat'),
+    ('EU-HR', 'Croatia', 'country', 'HR', NULL, 'vat'),
+    ('EU-CY', 'Cyprus', 'country', 'CY', NULL, 'vat'),
+    ('EU-CZ', 'Czech Republic', 'country', 'CZ', NULL, 'vat'),
+    ('EU-DK', 'Denmark', 'country', 'DK', NULL, 'vat'),
+    ('EU-EE', 'Estonia', 'country', 'EE', NULL, 'vat'),
+    ('EU-FI', 'Finland', 'country', 'FI', NULL, 'vat'),
+    ('EU-FR', 'France', 'country', 'FR', NULL, 'vat'),
+    ('EU-DE', 'Germany', 'country', 'DE', NULL, 'vat'),
+    ('EU-GR', 'Greece', 'country', 'GR', NULL, 'vat'),
+    ('EU-HU', 'Hungary', 'country', 'HU', NULL, 'vat'),
+    ('EU-IE', 'Ireland', 'country', 'IE', NULL, 'vat'),
+    ('EU-IT', 'Italy', 'country', 'IT', NULL, 'vat'),
+    ('EU-LV', 'Latvia', 'country', 'LV', NULL, 'vat'),
+    ('EU-LT', 'Lithuania', 'country', 'LT', NULL, 'vat'),
+    ('EU-LU', 'Luxembourg', 'country', 'LU', NULL, 'vat'),
+    ('EU-MT', 'Malta', 'country', 'MT', NULL, 'vat'),
+    ('EU-NL', 'Netherlands', 'country', 'NL', NULL, 'vat'),
+    ('EU-PL', 'Poland', 'country', 'PL', NULL, 'vat'),
+    ('EU-PT', 'Portugal', 'country', 'PT', NULL, 'vat'),
+    ('EU-RO', 'Romania', 'country', 'RO', NULL, 'vat'),
+    ('EU-SK', 'Slovakia', 'country', 'SK', NULL, 'vat'),
+    ('EU-SI', 'Slovenia', 'country', 'SI', NULL, 'vat'),
+    ('EU-ES', 'Spain', 'country', 'ES', NULL, 'vat'),
+    ('EU-SE', 'Sweden', 'country', 'SE', NULL, 'vat')
+ON CONFLICT (code) DO NOTHING;
+
+UPDATE tax_jurisdictions child
+SET parent_id = parent.id
+FROM tax_jurisdictions parent
+WHERE child.subdivision_code IS NOT NULL
+    AND child.parent_id IS NULL
+    AND parent.country_code = child.country_code
+    AND parent.subdivision_code IS NULL;
+
+INSERT INTO tax_rates (jurisdiction_id, rate, effective_from, effective_to, rules_version)
+SELECT j.id, v.rate, v.effective_from::timestamptz, v.effective_to::timestamptz, v.rules_version
+FROM (VALUES
+    ('US', 0.0, '2024-01-01', NULL, 'v2024.1'),
+    ('US-CA', 0.0725, '2024-01-01', NULL, 'v2024.1'),
+    ('US-NY', 0.08, '2024-01-01', NULL, 'v2024.1'),
+    ('US-TX', 0.0625, '2024-01-01', NULL, 'v2024.1'),
+    ('US-FL', 0.06, '2024-01-01', NULL, 'v2024.1'),
+    ('CA', 0.05, '2024-01-01', NULL, 'v2024.1'),
+    ('CA-ON', 0.13, '2024-01-01', NULL, 'v2024.1'),
+    ('CA-BC', 0.12, '2024-01-01', NULL, 'v2024.1'),
+    ('CA-AB', 0.05, '2024-01-01', NULL, 'v2024.1'),
+    ('CA-QC', 0.14975, '2024-01-01', NULL, 'v2024.1'),
+    ('UK', 0.20, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-AT', 0.20, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-BE', 0.21, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-BG', 0.20, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-HR', 0.25, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-CY', 0.19, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-CZ', 0.21, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-DK', 0.25, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-EE', 0.22, '2024-01-01', '2025-07-01', 'v2024.1'),
+    ('EU-EE', 0.24, '2025-07-01', NULL, 'v2025.2'),
+    ('EU-FI', 0.24, '2024-01-01', '2024-09-01', 'v2024.1'),
+    ('EU-FI', 0.255, '2024-09-01', NULL, 'v2024.2'),
+    ('EU-FR', 0.20, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-DE', 0.19, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-GR', 0.24, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-HU', 0.27, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-IE', 0.23, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-IT', 0.22, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-LV', 0.21, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-LT', 0.21, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-LU', 0.17, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-MT', 0.18, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-NL', 0.21, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-PL', 0.23, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-PT', 0.23, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-RO', 0.19, '2024-01-01', '2025-08-01', 'v2024.1'),
+    ('EU-RO', 0.21, '2025-08-01', NULL, 'v2025.2'),
+    ('EU-SK', 0.20, '2024-01-01', '2025-01-01', 'v2024.1'),
+    ('EU-SK', 0.23, '2025-01-01', NULL, 'v2025.1'),
+    ('EU-SI', 0.22, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-ES', 0.21, '2024-01-01', NULL, 'v2024.1'),
+    ('EU-SE', 0.25, '2024-01-01', NULL, 'v2024.1')
+) AS v(code, rate, effective_from, effective_to, rules_version)
+JOIN tax_jurisdictions j ON j.code = v.code
+WHERE NOT EXISTS (SELECT 1 FROM tax_rates r WHERE r.jurisdiction_id = j.id);
+
+-- =====================================================
+-- TAX ID VERIFICATIONS
+-- =====================================================
+
+-- Results of checking tax IDs against an official registry (VIES for EU VAT numbers).
+-- Rows double as the verification cache until expires_at and as audit evidence for invoices.
+CREATE TABLE IF NOT EXISTS tax_id_verifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    country_code VARCHAR(2) NOT NULL, -- Registry country code ('EL' for Greece in VIES)
+    tax_id VARCHAR(255) NOT NULL, -- Normalized, without the country prefix
+    is_valid BOOLEAN NOT NULL,
+    registered_name TEXT,
+    registered_address TEXT,
+    consultation_number VARCHAR(255), -- Registry request identifier, proof the check took place
+    source VARCHAR(50) NOT NULL DEFAULT 'vies',
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tax_id_verifications_lookup ON tax_id_verifications(country_code, tax_id, checked_at DESC);
+CREATE INDEX idx_tax_id_verifications_customer ON tax_id_verifications(customer_id) WHERE customer_id IS NOT NULL;
+
+-- Invoices keep the verification that justified their tax treatment
+ALTER TABLE invoices
+ADD COLUMN IF NOT EXISTS tax_id_verification_id UUID REFERENCES tax_id_verifications(id);
+
+-- =====================================================
+-- TREASURY TABLES
+-- =====================================================
+
+-- Treasury sweep rules (depends on workspaces, wallets, tokens)
+-- Move a token balance above a threshold from a Circle wallet into the workspace's treasury wallet on the
+-- same network, leaving a retained float behind. Gas is paid in the network's native token, so sweeps of
+-- other tokens are skipped when the wallet cannot cover the fee.
+CREATE TABLE treasury_sweep_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    name VARCHAR(255) NOT NULL,
+    source_wallet_id UUID NOT NULL REFERENCES wallets(id), -- Circle wallet that receives payments
+    destination_wallet_id UUID NOT NULL REFERENCES wallets(id), -- Treasury wallet
+    token_id UUID NOT NULL REFERENCES tokens(id),
+    threshold_amount NUMERIC(36,18) NOT NULL CHECK (threshold_amount >= 0), -- Sweep once the balance exceeds this
+    retained_amount NUMERIC(36,18) NOT NULL DEFAULT 0 CHECK (retained_amount >= 0), -- Left in the source wallet
+    fee_level VARCHAR(10) NOT NULL DEFAULT 'MEDIUM' CHECK (fee_level IN ('LOW', 'MEDIUM', 'HIGH')),
+    sweep_interval_minutes INTEGER NOT NULL DEFAULT 60 CHECK (sweep_interval_minutes >= 5),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_run_result TEXT, -- Why the last run did or did not sweep
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT check_retained_below_threshold CHECK (retained_amount <= threshold_amount),
+    CONSTRAINT check_distinct_wallets CHECK (source_wallet_id <> destination_wallet_id)
+);
+
+CREATE INDEX idx_treasury_sweep_rules_workspace ON treasury_sweep_rules(workspace_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_treasury_sweep_rules_due ON treasury_sweep_rules(last_run_at NULLS FIRST) WHERE is_active = TRUE AND deleted_at IS NULL;
+
+-- Treasury movements (depends on treasury_sweep_rules)
+-- Ledger of sweeps. Circle wallets are user-controlled, so each sweep is a transfer challenge the merchant
+-- approves with their PIN; the movement is matched to the resulting Circle transaction by its ref ID.
+CREATE TABLE treasury_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    rule_id UUID NOT NULL REFERENCES treasury_sweep_rules(id),
+    network_id UUID NOT NULL REFERENCES networks(id),
+    token_id UUID NOT NULL REFERENCES tokens(id),
+    source_wallet_id UUID NOT NULL REFERENCES wallets(id),
+    destination_wallet_id UUID NOT NULL REFERENCES wallets(id),
+    amount NUMERIC(36,18) NOT NULL CHECK (amount > 0),
+    source_balance NUMERIC(36,18) NOT NULL, -- Balance the sweep was sized from
+    estimated_fee NUMERIC(36,18), -- In the native token
+    status VARCHAR(20) NOT NULL DEFAULT 'awaiting_approval' CHECK (status IN ('awaiting_approval', 'submitted', 'completed', 'failed', 'expired')),
+    challenge_id TEXT, -- Circle challenge the merchant approves
+    circle_transaction_id TEXT UNIQUE,
+    circle_state TEXT,
+    transaction_hash TEXT,
+    network_fee NUMERIC(36,18), -- Fee Circle reports once the transfer is sent
+    error_message TEXT,
+    reconciled_at TIMESTAMP WITH TIME ZONE, -- Last time the movement was checked against Circle
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_treasury_movements_workspace ON treasury_movements(workspace_id, created_at DESC);
+-- A rule has at most one sweep in flight
+CREATE UNIQUE INDEX idx_treasury_movements_open_rule ON treasury_movements(rule_id) WHERE status IN ('awaiting_approval', 'submitted');
+
+CREATE TRIGGER set_treasury_sweep_rules_updated_at
+    BEFORE UPDATE ON treasury_sweep_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TRIGGER set_treasury_movements_updated_at
+    BEFORE UPDATE ON treasury_movements
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+-- =====================================================
+-- PORTFOLIO TABLES
+-- =====================================================
+
+-- Wallet balances (depends on wallets, networks, tokens)
+-- Last balance read for each active token held by a workspace wallet: from Circle for Circle wallets and
+-- from the chain otherwise. Fiat values are in the workspace's default currency when the balance was read.
+CREATE TABLE wallet_balances (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    network_id UUID NOT NULL REFERENCES networks(id),
+    token_id UUID NOT NULL REFERENCES tokens(id),
+    balance NUMERIC(78,18), -- In whole tokens; NULL until the balance is first read
+    source VARCHAR(10) NOT NULL CHECK (source IN ('circle', 'rpc')),
+    fiat_currency VARCHAR(3) NOT NULL,
+    exchange_rate NUMERIC(36,18), -- NULL when no rate was available for the token
+    fiat_value_cents BIGINT,
+    fetched_at TIMESTAMP WITH TIME ZONE, -- When the balance was last read successfully
+    attempted_at TIMESTAMP WITH TIME ZONE NOT NULL, -- When a refresh last tried to read the balance
+    error_message TEXT, -- Why the latest refresh could not read the balance
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_wallet_token_balance UNIQUE (wallet_id, network_id, token_id)
+);
+
+CREATE INDEX idx_wallet_balances_workspace ON wallet_balances(workspace_id);
+
+-- Portfolio snapshots (depends on workspaces)
+-- Total value of a workspace's wallets once per snapshot period, for balance history charts
+CREATE TABLE portfolio_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    snapshot_at TIMESTAMP WITH TIME ZONE NOT NULL, -- Start of the snapshot period
+    fiat_currency VARCHAR(3) NOT NULL,
+    total_value_cents BIGINT NOT NULL DEFAULT 0,
+    holdings JSONB NOT NULL DEFAULT '[]'::jsonb, -- Balance and value per network and token
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_portfolio_snapshot_period UNIQUE (workspace_id, snapshot_at)
+);
+
+CREATE TRIGGER set_wallet_balances_updated_at
+    BEFORE UPDATE ON wallet_balances
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TRIGGER set_portfolio_snapshots_updated_at
+    BEFORE UPDATE ON portfolio_snapshots
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
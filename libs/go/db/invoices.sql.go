@@ -158,7 +158,7 @@ INSERT INTO invoices (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
     $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
     $21, $22, $23, $24, $25, $26, $27, $28, $29, $30
-) RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+) RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type CreateInvoiceParams struct {
@@ -279,6 +279,9 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (I
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -310,7 +313,7 @@ INSERT INTO invoices (
     notes
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $7, $8, $9, $10, $11, $12, $13, CURRENT_TIMESTAMP, $14, $15, $16, $17, $18, $19, $20, $21
-) RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+) RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type CreateInvoiceWithDetailsParams struct {
@@ -413,6 +416,9 @@ func (q *Queries) CreateInvoiceWithDetails(ctx context.Context, arg CreateInvoic
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -435,7 +441,7 @@ func (q *Queries) DeleteInvoice(ctx context.Context, arg DeleteInvoiceParams) er
 }
 
 const getInvoiceByExternalID = `-- name: GetInvoiceByExternalID :one
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE external_id = $1 AND workspace_id = $2 AND payment_provider = $3 AND deleted_at IS NULL
 `
 
@@ -499,12 +505,15 @@ func (q *Queries) GetInvoiceByExternalID(ctx context.Context, arg GetInvoiceByEx
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
 
 const getInvoiceByID = `-- name: GetInvoiceByID :one
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
 `
 
@@ -567,12 +576,15 @@ func (q *Queries) GetInvoiceByID(ctx context.Context, arg GetInvoiceByIDParams)
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
 
 const getInvoiceByNumber = `-- name: GetInvoiceByNumber :one
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices
 WHERE workspace_id = $1 AND invoice_number = $2 AND deleted_at IS NULL
 `
 
@@ -635,6 +647,9 @@ func (q *Queries) GetInvoiceByNumber(ctx context.Context, arg GetInvoiceByNumber
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -693,7 +708,7 @@ func (q *Queries) GetInvoiceStatsByWorkspace(ctx context.Context, arg GetInvoice
 
 const getInvoiceWithLineItems = `-- name: GetInvoiceWithLineItems :one
 SELECT 
-    i.id, i.workspace_id, i.customer_id, i.subscription_id, i.external_id, i.external_customer_id, i.external_subscription_id, i.status, i.collection_method, i.amount_due, i.amount_paid, i.amount_remaining, i.currency, i.due_date, i.paid_at, i.created_date, i.invoice_pdf, i.hosted_invoice_url, i.charge_id, i.payment_intent_id, i.line_items, i.tax_amount, i.total_tax_amounts, i.billing_reason, i.paid_out_of_band, i.payment_provider, i.payment_sync_status, i.payment_synced_at, i.attempt_count, i.next_payment_attempt, i.metadata, i.created_at, i.updated_at, i.deleted_at, i.invoice_number, i.subtotal_cents, i.discount_cents, i.payment_link_id, i.delegation_address, i.qr_code_data, i.tax_amount_cents, i.tax_details, i.customer_tax_id, i.customer_jurisdiction_id, i.reverse_charge_applies, i.reminder_sent_at, i.reminder_count, i.notes, i.terms, i.footer,
+    i.id, i.workspace_id, i.customer_id, i.subscription_id, i.external_id, i.external_customer_id, i.external_subscription_id, i.status, i.collection_method, i.amount_due, i.amount_paid, i.amount_remaining, i.currency, i.due_date, i.paid_at, i.created_date, i.invoice_pdf, i.hosted_invoice_url, i.charge_id, i.payment_intent_id, i.line_items, i.tax_amount, i.total_tax_amounts, i.billing_reason, i.paid_out_of_band, i.payment_provider, i.payment_sync_status, i.payment_synced_at, i.attempt_count, i.next_payment_attempt, i.metadata, i.created_at, i.updated_at, i.deleted_at, i.invoice_number, i.subtotal_cents, i.discount_cents, i.payment_link_id, i.delegation_address, i.qr_code_data, i.tax_amount_cents, i.tax_details, i.customer_tax_id, i.customer_jurisdiction_id, i.reverse_charge_applies, i.reminder_sent_at, i.reminder_count, i.notes, i.terms, i.footer, i.tax_provider, i.tax_provider_transaction_id, i.tax_id_verification_id,
     COALESCE(
         (SELECT json_agg(ili.* ORDER BY ili.created_at)
          FROM invoice_line_items ili
@@ -710,57 +725,60 @@ type GetInvoiceWithLineItemsParams struct {
 }
 
 type GetInvoiceWithLineItemsRow struct {
-	ID                     uuid.UUID          `json:"id"`
-	WorkspaceID            uuid.UUID          `json:"workspace_id"`
-	CustomerID             pgtype.UUID        `json:"customer_id"`
-	SubscriptionID         pgtype.UUID        `json:"subscription_id"`
-	ExternalID             string             `json:"external_id"`
-	ExternalCustomerID     pgtype.Text        `json:"external_customer_id"`
-	ExternalSubscriptionID pgtype.Text        `json:"external_subscription_id"`
-	Status                 string             `json:"status"`
-	CollectionMethod       pgtype.Text        `json:"collection_method"`
-	AmountDue              int32              `json:"amount_due"`
-	AmountPaid             int32              `json:"amount_paid"`
-	AmountRemaining        int32              `json:"amount_remaining"`
-	Currency               string             `json:"currency"`
-	DueDate                pgtype.Timestamptz `json:"due_date"`
-	PaidAt                 pgtype.Timestamptz `json:"paid_at"`
-	CreatedDate            pgtype.Timestamptz `json:"created_date"`
-	InvoicePdf             pgtype.Text        `json:"invoice_pdf"`
-	HostedInvoiceUrl       pgtype.Text        `json:"hosted_invoice_url"`
-	ChargeID               pgtype.Text        `json:"charge_id"`
-	PaymentIntentID        pgtype.Text        `json:"payment_intent_id"`
-	LineItems              []byte             `json:"line_items"`
-	TaxAmount              pgtype.Int4        `json:"tax_amount"`
-	TotalTaxAmounts        []byte             `json:"total_tax_amounts"`
-	BillingReason          pgtype.Text        `json:"billing_reason"`
-	PaidOutOfBand          pgtype.Bool        `json:"paid_out_of_band"`
-	PaymentProvider        pgtype.Text        `json:"payment_provider"`
-	PaymentSyncStatus      pgtype.Text        `json:"payment_sync_status"`
-	PaymentSyncedAt        pgtype.Timestamptz `json:"payment_synced_at"`
-	AttemptCount           pgtype.Int4        `json:"attempt_count"`
-	NextPaymentAttempt     pgtype.Timestamptz `json:"next_payment_attempt"`
-	Metadata               []byte             `json:"metadata"`
-	CreatedAt              pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt              pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt              pgtype.Timestamptz `json:"deleted_at"`
-	InvoiceNumber          pgtype.Text        `json:"invoice_number"`
-	SubtotalCents          pgtype.Int8        `json:"subtotal_cents"`
-	DiscountCents          pgtype.Int8        `json:"discount_cents"`
-	PaymentLinkID          pgtype.UUID        `json:"payment_link_id"`
-	DelegationAddress      pgtype.Text        `json:"delegation_address"`
-	QrCodeData             pgtype.Text        `json:"qr_code_data"`
-	TaxAmountCents         int64              `json:"tax_amount_cents"`
-	TaxDetails             []byte             `json:"tax_details"`
-	CustomerTaxID          pgtype.Text        `json:"customer_tax_id"`
-	CustomerJurisdictionID pgtype.UUID        `json:"customer_jurisdiction_id"`
-	ReverseChargeApplies   pgtype.Bool        `json:"reverse_charge_applies"`
-	ReminderSentAt         pgtype.Timestamptz `json:"reminder_sent_at"`
-	ReminderCount          pgtype.Int4        `json:"reminder_count"`
-	Notes                  pgtype.Text        `json:"notes"`
-	Terms                  pgtype.Text        `json:"terms"`
-	Footer                 pgtype.Text        `json:"footer"`
-	LineItemsDetail        interface{}        `json:"line_items_detail"`
+	ID                       uuid.UUID          `json:"id"`
+	WorkspaceID              uuid.UUID          `json:"workspace_id"`
+	CustomerID               pgtype.UUID        `json:"customer_id"`
+	SubscriptionID           pgtype.UUID        `json:"subscription_id"`
+	ExternalID               string             `json:"external_id"`
+	ExternalCustomerID       pgtype.Text        `json:"external_customer_id"`
+	ExternalSubscriptionID   pgtype.Text        `json:"external_subscription_id"`
+	Status                   string             `json:"status"`
+	CollectionMethod         pgtype.Text        `json:"collection_method"`
+	AmountDue                int32              `json:"amount_due"`
+	AmountPaid               int32              `json:"amount_paid"`
+	AmountRemaining          int32              `json:"amount_remaining"`
+	Currency                 string             `json:"currency"`
+	DueDate                  pgtype.Timestamptz `json:"due_date"`
+	PaidAt                   pgtype.Timestamptz `json:"paid_at"`
+	CreatedDate              pgtype.Timestamptz `json:"created_date"`
+	InvoicePdf               pgtype.Text        `json:"invoice_pdf"`
+	HostedInvoiceUrl         pgtype.Text        `json:"hosted_invoice_url"`
+	ChargeID                 pgtype.Text        `json:"charge_id"`
+	PaymentIntentID          pgtype.Text        `json:"payment_intent_id"`
+	LineItems                []byte             `json:"line_items"`
+	TaxAmount                pgtype.Int4        `json:"tax_amount"`
+	TotalTaxAmounts          []byte             `json:"total_tax_amounts"`
+	BillingReason            pgtype.Text        `json:"billing_reason"`
+	PaidOutOfBand            pgtype.Bool        `json:"paid_out_of_band"`
+	PaymentProvider          pgtype.Text        `json:"payment_provider"`
+	PaymentSyncStatus        pgtype.Text        `json:"payment_sync_status"`
+	PaymentSyncedAt          pgtype.Timestamptz `json:"payment_synced_at"`
+	AttemptCount             pgtype.Int4        `json:"attempt_count"`
+	NextPaymentAttempt       pgtype.Timestamptz `json:"next_payment_attempt"`
+	Metadata                 []byte             `json:"metadata"`
+	CreatedAt                pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt                pgtype.Timestamptz `json:"deleted_at"`
+	InvoiceNumber            pgtype.Text        `json:"invoice_number"`
+	SubtotalCents            pgtype.Int8        `json:"subtotal_cents"`
+	DiscountCents            pgtype.Int8        `json:"discount_cents"`
+	PaymentLinkID            pgtype.UUID        `json:"payment_link_id"`
+	DelegationAddress        pgtype.Text        `json:"delegation_address"`
+	QrCodeData               pgtype.Text        `json:"qr_code_data"`
+	TaxAmountCents           int64              `json:"tax_amount_cents"`
+	TaxDetails               []byte             `json:"tax_details"`
+	CustomerTaxID            pgtype.Text        `json:"customer_tax_id"`
+	CustomerJurisdictionID   pgtype.UUID        `json:"customer_jurisdiction_id"`
+	ReverseChargeApplies     pgtype.Bool        `json:"reverse_charge_applies"`
+	ReminderSentAt           pgtype.Timestamptz `json:"reminder_sent_at"`
+	ReminderCount            pgtype.Int4        `json:"reminder_count"`
+	Notes                    pgtype.Text        `json:"notes"`
+	Terms                    pgtype.Text        `json:"terms"`
+	Footer                   pgtype.Text        `json:"footer"`
+	TaxProvider              pgtype.Text        `json:"tax_provider"`
+	TaxProviderTransactionID pgtype.Text        `json:"tax_provider_transaction_id"`
+	TaxIDVerificationID      pgtype.UUID        `json:"tax_id_verification_id"`
+	LineItemsDetail          interface{}        `json:"line_items_detail"`
 }
 
 func (q *Queries) GetInvoiceWithLineItems(ctx context.Context, arg GetInvoiceWithLineItemsParams) (GetInvoiceWithLineItemsRow, error) {
@@ -817,13 +835,16 @@ func (q *Queries) GetInvoiceWithLineItems(ctx context.Context, arg GetInvoiceWit
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 		&i.LineItemsDetail,
 	)
 	return i, err
 }
 
 const getInvoicesByExternalCustomerID = `-- name: GetInvoicesByExternalCustomerID :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 AND external_customer_id = $2 AND payment_provider = $3 AND deleted_at IS NULL
 ORDER BY created_date DESC
 `
@@ -894,6 +915,9 @@ func (q *Queries) GetInvoicesByExternalCustomerID(ctx context.Context, arg GetIn
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -906,7 +930,7 @@ func (q *Queries) GetInvoicesByExternalCustomerID(ctx context.Context, arg GetIn
 }
 
 const getInvoicesByExternalSubscriptionID = `-- name: GetInvoicesByExternalSubscriptionID :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 AND external_subscription_id = $2 AND payment_provider = $3 AND deleted_at IS NULL
 ORDER BY created_date DESC
 `
@@ -977,6 +1001,9 @@ func (q *Queries) GetInvoicesByExternalSubscriptionID(ctx context.Context, arg G
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -989,7 +1016,7 @@ func (q *Queries) GetInvoicesByExternalSubscriptionID(ctx context.Context, arg G
 }
 
 const getInvoicesByPaymentLink = `-- name: GetInvoicesByPaymentLink :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices
 WHERE workspace_id = $1 AND payment_link_id = $2 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -1059,6 +1086,9 @@ func (q *Queries) GetInvoicesByPaymentLink(ctx context.Context, arg GetInvoicesB
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -1087,7 +1117,7 @@ func (q *Queries) GetNextInvoiceNumber(ctx context.Context, workspaceID uuid.UUI
 }
 
 const getOverdueInvoices = `-- name: GetOverdueInvoices :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 
     AND status IN ('open') 
     AND due_date < CURRENT_TIMESTAMP 
@@ -1162,6 +1192,9 @@ func (q *Queries) GetOverdueInvoices(ctx context.Context, arg GetOverdueInvoices
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -1236,7 +1269,7 @@ func (q *Queries) GetPendingInvoicesForGeneration(ctx context.Context, nextRedem
 }
 
 const getRecentInvoices = `-- name: GetRecentInvoices :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 
     AND created_date >= $2 
     AND deleted_at IS NULL
@@ -1316,6 +1349,9 @@ func (q *Queries) GetRecentInvoices(ctx context.Context, arg GetRecentInvoicesPa
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -1637,7 +1673,7 @@ func (q *Queries) GetSubscriptionsForBulkInvoicing(ctx context.Context, arg GetS
 }
 
 const getUnpaidInvoices = `-- name: GetUnpaidInvoices :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 
     AND status IN ('open', 'draft') 
     AND amount_remaining > 0 
@@ -1712,6 +1748,9 @@ func (q *Queries) GetUnpaidInvoices(ctx context.Context, arg GetUnpaidInvoicesPa
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -1728,7 +1767,7 @@ UPDATE invoices SET
     payment_link_id = $3,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type LinkInvoiceToPaymentLinkParams struct {
@@ -1791,12 +1830,15 @@ func (q *Queries) LinkInvoiceToPaymentLink(ctx context.Context, arg LinkInvoiceT
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
 
 const listInvoicesByCustomer = `-- name: ListInvoicesByCustomer :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 AND customer_id = $2 AND deleted_at IS NULL
 ORDER BY created_date DESC
 LIMIT $3 OFFSET $4
@@ -1874,6 +1916,9 @@ func (q *Queries) ListInvoicesByCustomer(ctx context.Context, arg ListInvoicesBy
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -1886,7 +1931,7 @@ func (q *Queries) ListInvoicesByCustomer(ctx context.Context, arg ListInvoicesBy
 }
 
 const listInvoicesByProvider = `-- name: ListInvoicesByProvider :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 AND payment_provider = $2 AND deleted_at IS NULL
 ORDER BY created_date DESC
 LIMIT $3 OFFSET $4
@@ -1964,6 +2009,9 @@ func (q *Queries) ListInvoicesByProvider(ctx context.Context, arg ListInvoicesBy
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -1976,7 +2024,7 @@ func (q *Queries) ListInvoicesByProvider(ctx context.Context, arg ListInvoicesBy
 }
 
 const listInvoicesByStatus = `-- name: ListInvoicesByStatus :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 AND status = $2 AND deleted_at IS NULL
 ORDER BY created_date DESC
 LIMIT $3 OFFSET $4
@@ -2054,6 +2102,9 @@ func (q *Queries) ListInvoicesByStatus(ctx context.Context, arg ListInvoicesBySt
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -2066,7 +2117,7 @@ func (q *Queries) ListInvoicesByStatus(ctx context.Context, arg ListInvoicesBySt
 }
 
 const listInvoicesBySubscription = `-- name: ListInvoicesBySubscription :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 AND subscription_id = $2 AND deleted_at IS NULL
 ORDER BY created_date DESC
 LIMIT $3 OFFSET $4
@@ -2144,6 +2195,9 @@ func (q *Queries) ListInvoicesBySubscription(ctx context.Context, arg ListInvoic
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -2156,7 +2210,7 @@ func (q *Queries) ListInvoicesBySubscription(ctx context.Context, arg ListInvoic
 }
 
 const listInvoicesBySyncStatus = `-- name: ListInvoicesBySyncStatus :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 AND payment_sync_status = $2 AND deleted_at IS NULL
 ORDER BY created_date DESC
 LIMIT $3 OFFSET $4
@@ -2234,6 +2288,9 @@ func (q *Queries) ListInvoicesBySyncStatus(ctx context.Context, arg ListInvoices
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -2246,7 +2303,7 @@ func (q *Queries) ListInvoicesBySyncStatus(ctx context.Context, arg ListInvoices
 }
 
 const listInvoicesByWorkspace = `-- name: ListInvoicesByWorkspace :many
-SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer FROM invoices 
+SELECT id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id FROM invoices 
 WHERE workspace_id = $1 AND deleted_at IS NULL
 ORDER BY created_date DESC
 LIMIT $2 OFFSET $3
@@ -2318,6 +2375,9 @@ func (q *Queries) ListInvoicesByWorkspace(ctx context.Context, arg ListInvoicesB
 			&i.Notes,
 			&i.Terms,
 			&i.Footer,
+			&i.TaxProvider,
+			&i.TaxProviderTransactionID,
+			&i.TaxIDVerificationID,
 		); err != nil {
 			return nil, err
 		}
@@ -2339,7 +2399,7 @@ UPDATE invoices SET
 WHERE id = $1 AND workspace_id = $2 
 AND status = 'open'
 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type MarkInvoicePaidParams struct {
@@ -2401,6 +2461,89 @@ func (q *Queries) MarkInvoicePaid(ctx context.Context, arg MarkInvoicePaidParams
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
+	)
+	return i, err
+}
+
+const reopenPaidInvoice = `-- name: ReopenPaidInvoice :one
+UPDATE invoices SET
+    status = 'open',
+    amount_paid = 0,
+    amount_remaining = amount_due,
+    paid_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2
+AND status = 'paid'
+AND deleted_at IS NULL
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
+`
+
+type ReopenPaidInvoiceParams struct {
+	ID          uuid.UUID `json:"id"`
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+}
+
+// Undoes MarkInvoicePaid when the payment behind it is rolled back
+func (q *Queries) ReopenPaidInvoice(ctx context.Context, arg ReopenPaidInvoiceParams) (Invoice, error) {
+	row := q.db.QueryRow(ctx, reopenPaidInvoice, arg.ID, arg.WorkspaceID)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.CustomerID,
+		&i.SubscriptionID,
+		&i.ExternalID,
+		&i.ExternalCustomerID,
+		&i.ExternalSubscriptionID,
+		&i.Status,
+		&i.CollectionMethod,
+		&i.AmountDue,
+		&i.AmountPaid,
+		&i.AmountRemaining,
+		&i.Currency,
+		&i.DueDate,
+		&i.PaidAt,
+		&i.CreatedDate,
+		&i.InvoicePdf,
+		&i.HostedInvoiceUrl,
+		&i.ChargeID,
+		&i.PaymentIntentID,
+		&i.LineItems,
+		&i.TaxAmount,
+		&i.TotalTaxAmounts,
+		&i.BillingReason,
+		&i.PaidOutOfBand,
+		&i.PaymentProvider,
+		&i.PaymentSyncStatus,
+		&i.PaymentSyncedAt,
+		&i.AttemptCount,
+		&i.NextPaymentAttempt,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.InvoiceNumber,
+		&i.SubtotalCents,
+		&i.DiscountCents,
+		&i.PaymentLinkID,
+		&i.DelegationAddress,
+		&i.QrCodeData,
+		&i.TaxAmountCents,
+		&i.TaxDetails,
+		&i.CustomerTaxID,
+		&i.CustomerJurisdictionID,
+		&i.ReverseChargeApplies,
+		&i.ReminderSentAt,
+		&i.ReminderCount,
+		&i.Notes,
+		&i.Terms,
+		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -2431,7 +2574,7 @@ UPDATE invoices SET
     metadata = $24,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type UpdateInvoiceParams struct {
@@ -2540,6 +2683,9 @@ func (q *Queries) UpdateInvoice(ctx context.Context, arg UpdateInvoiceParams) (I
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -2556,7 +2702,7 @@ UPDATE invoices SET
     reverse_charge_applies = $10,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type UpdateInvoiceDetailsParams struct {
@@ -2637,6 +2783,9 @@ func (q *Queries) UpdateInvoiceDetails(ctx context.Context, arg UpdateInvoiceDet
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -2646,7 +2795,7 @@ UPDATE invoices SET
     metadata = metadata || $1::jsonb,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type UpdateInvoiceMetadataParams struct {
@@ -2708,6 +2857,9 @@ func (q *Queries) UpdateInvoiceMetadata(ctx context.Context, arg UpdateInvoiceMe
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -2717,7 +2869,7 @@ UPDATE invoices SET
     notes = $2,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type UpdateInvoiceNotesParams struct {
@@ -2779,6 +2931,9 @@ func (q *Queries) UpdateInvoiceNotes(ctx context.Context, arg UpdateInvoiceNotes
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -2788,7 +2943,7 @@ UPDATE invoices SET
     invoice_number = $3,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type UpdateInvoiceNumberParams struct {
@@ -2851,6 +3006,9 @@ func (q *Queries) UpdateInvoiceNumber(ctx context.Context, arg UpdateInvoiceNumb
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -2860,7 +3018,7 @@ UPDATE invoices SET
     qr_code_data = $3,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type UpdateInvoiceQRCodeParams struct {
@@ -2923,6 +3081,9 @@ func (q *Queries) UpdateInvoiceQRCode(ctx context.Context, arg UpdateInvoiceQRCo
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -2932,7 +3093,7 @@ UPDATE invoices SET
     status = $3,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type UpdateInvoiceStatusParams struct {
@@ -2995,6 +3156,9 @@ func (q *Queries) UpdateInvoiceStatus(ctx context.Context, arg UpdateInvoiceStat
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -3008,7 +3172,7 @@ UPDATE invoices SET
     END,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type UpdateInvoiceSyncStatusParams struct {
@@ -3071,6 +3235,166 @@ func (q *Queries) UpdateInvoiceSyncStatus(ctx context.Context, arg UpdateInvoice
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
+	)
+	return i, err
+}
+
+const updateInvoiceTaxIDVerification = `-- name: UpdateInvoiceTaxIDVerification :one
+UPDATE invoices SET
+    tax_id_verification_id = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
+`
+
+type UpdateInvoiceTaxIDVerificationParams struct {
+	ID                  uuid.UUID   `json:"id"`
+	WorkspaceID         uuid.UUID   `json:"workspace_id"`
+	TaxIDVerificationID pgtype.UUID `json:"tax_id_verification_id"`
+}
+
+func (q *Queries) UpdateInvoiceTaxIDVerification(ctx context.Context, arg UpdateInvoiceTaxIDVerificationParams) (Invoice, error) {
+	row := q.db.QueryRow(ctx, updateInvoiceTaxIDVerification, arg.ID, arg.WorkspaceID, arg.TaxIDVerificationID)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.CustomerID,
+		&i.SubscriptionID,
+		&i.ExternalID,
+		&i.ExternalCustomerID,
+		&i.ExternalSubscriptionID,
+		&i.Status,
+		&i.CollectionMethod,
+		&i.AmountDue,
+		&i.AmountPaid,
+		&i.AmountRemaining,
+		&i.Currency,
+		&i.DueDate,
+		&i.PaidAt,
+		&i.CreatedDate,
+		&i.InvoicePdf,
+		&i.HostedInvoiceUrl,
+		&i.ChargeID,
+		&i.PaymentIntentID,
+		&i.LineItems,
+		&i.TaxAmount,
+		&i.TotalTaxAmounts,
+		&i.BillingReason,
+		&i.PaidOutOfBand,
+		&i.PaymentProvider,
+		&i.PaymentSyncStatus,
+		&i.PaymentSyncedAt,
+		&i.AttemptCount,
+		&i.NextPaymentAttempt,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.InvoiceNumber,
+		&i.SubtotalCents,
+		&i.DiscountCents,
+		&i.PaymentLinkID,
+		&i.DelegationAddress,
+		&i.QrCodeData,
+		&i.TaxAmountCents,
+		&i.TaxDetails,
+		&i.CustomerTaxID,
+		&i.CustomerJurisdictionID,
+		&i.ReverseChargeApplies,
+		&i.ReminderSentAt,
+		&i.ReminderCount,
+		&i.Notes,
+		&i.Terms,
+		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
+	)
+	return i, err
+}
+
+const updateInvoiceTaxProvider = `-- name: UpdateInvoiceTaxProvider :one
+UPDATE invoices SET
+    tax_provider = $3,
+    tax_provider_transaction_id = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
+`
+
+type UpdateInvoiceTaxProviderParams struct {
+	ID                       uuid.UUID   `json:"id"`
+	WorkspaceID              uuid.UUID   `json:"workspace_id"`
+	TaxProvider              pgtype.Text `json:"tax_provider"`
+	TaxProviderTransactionID pgtype.Text `json:"tax_provider_transaction_id"`
+}
+
+func (q *Queries) UpdateInvoiceTaxProvider(ctx context.Context, arg UpdateInvoiceTaxProviderParams) (Invoice, error) {
+	row := q.db.QueryRow(ctx, updateInvoiceTaxProvider,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.TaxProvider,
+		arg.TaxProviderTransactionID,
+	)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.CustomerID,
+		&i.SubscriptionID,
+		&i.ExternalID,
+		&i.ExternalCustomerID,
+		&i.ExternalSubscriptionID,
+		&i.Status,
+		&i.CollectionMethod,
+		&i.AmountDue,
+		&i.AmountPaid,
+		&i.AmountRemaining,
+		&i.Currency,
+		&i.DueDate,
+		&i.PaidAt,
+		&i.CreatedDate,
+		&i.InvoicePdf,
+		&i.HostedInvoiceUrl,
+		&i.ChargeID,
+		&i.PaymentIntentID,
+		&i.LineItems,
+		&i.TaxAmount,
+		&i.TotalTaxAmounts,
+		&i.BillingReason,
+		&i.PaidOutOfBand,
+		&i.PaymentProvider,
+		&i.PaymentSyncStatus,
+		&i.PaymentSyncedAt,
+		&i.AttemptCount,
+		&i.NextPaymentAttempt,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.InvoiceNumber,
+		&i.SubtotalCents,
+		&i.DiscountCents,
+		&i.PaymentLinkID,
+		&i.DelegationAddress,
+		&i.QrCodeData,
+		&i.TaxAmountCents,
+		&i.TaxDetails,
+		&i.CustomerTaxID,
+		&i.CustomerJurisdictionID,
+		&i.ReverseChargeApplies,
+		&i.ReminderSentAt,
+		&i.ReminderCount,
+		&i.Notes,
+		&i.Terms,
+		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -3140,7 +3464,7 @@ DO UPDATE SET
     next_payment_attempt = EXCLUDED.next_payment_attempt,
     metadata = EXCLUDED.metadata,
     updated_at = CURRENT_TIMESTAMP
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type UpsertInvoiceParams struct {
@@ -3261,6 +3585,9 @@ func (q *Queries) UpsertInvoice(ctx context.Context, arg UpsertInvoiceParams) (I
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -3272,7 +3599,7 @@ UPDATE invoices SET
 WHERE id = $1 AND workspace_id = $2 
 AND status IN ('draft', 'open')
 AND deleted_at IS NULL
-RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer
+RETURNING id, workspace_id, customer_id, subscription_id, external_id, external_customer_id, external_subscription_id, status, collection_method, amount_due, amount_paid, amount_remaining, currency, due_date, paid_at, created_date, invoice_pdf, hosted_invoice_url, charge_id, payment_intent_id, line_items, tax_amount, total_tax_amounts, billing_reason, paid_out_of_band, payment_provider, payment_sync_status, payment_synced_at, attempt_count, next_payment_attempt, metadata, created_at, updated_at, deleted_at, invoice_number, subtotal_cents, discount_cents, payment_link_id, delegation_address, qr_code_data, tax_amount_cents, tax_details, customer_tax_id, customer_jurisdiction_id, reverse_charge_applies, reminder_sent_at, reminder_count, notes, terms, footer, tax_provider, tax_provider_transaction_id, tax_id_verification_id
 `
 
 type VoidInvoiceParams struct {
@@ -3334,6 +3661,9 @@ func (q *Queries) VoidInvoice(ctx context.Context, arg VoidInvoiceParams) (Invoi
 		&i.Notes,
 		&i.Terms,
 		&i.Footer,
+		&i.TaxProvider,
+		&i.TaxProviderTransactionID,
+		&i.TaxIDVerificationID,
 	)
 	return i, err
 }
@@ -113,6 +113,8 @@ const (
 	CircleNetworkTypeBASESEPOLIA CircleNetworkType = "BASE-SEPOLIA"
 	CircleNetworkTypeUNI         CircleNetworkType = "UNI"
 	CircleNetworkTypeUNISEPOLIA  CircleNetworkType = "UNI-SEPOLIA"
+	CircleNetworkTypeSOL         CircleNetworkType = "SOL"
+	CircleNetworkTypeSOLDEVNET   CircleNetworkType = "SOL-DEVNET"
 )
 
 func (e *CircleNetworkType) Scan(src interface{}) error {
@@ -585,19 +587,68 @@ type Account struct {
 	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type AnalyticsExportJob struct {
+	ID               uuid.UUID          `json:"id"`
+	WorkspaceID      uuid.UUID          `json:"workspace_id"`
+	ReportScheduleID pgtype.UUID        `json:"report_schedule_id"`
+	Datasets         []string           `json:"datasets"`
+	Format           string             `json:"format"`
+	FiatCurrency     string             `json:"fiat_currency"`
+	StartDate        pgtype.Timestamptz `json:"start_date"`
+	EndDate          pgtype.Timestamptz `json:"end_date"`
+	Status           string             `json:"status"`
+	Attempts         int32              `json:"attempts"`
+	Files            []byte             `json:"files"`
+	ErrorMessage     pgtype.Text        `json:"error_message"`
+	StartedAt        pgtype.Timestamptz `json:"started_at"`
+	CompletedAt      pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+}
+
+type AnalyticsReportSchedule struct {
+	ID           uuid.UUID          `json:"id"`
+	WorkspaceID  uuid.UUID          `json:"workspace_id"`
+	Name         string             `json:"name"`
+	Frequency    string             `json:"frequency"`
+	Datasets     []string           `json:"datasets"`
+	Format       string             `json:"format"`
+	FiatCurrency pgtype.Text        `json:"fiat_currency"`
+	Recipients   []string           `json:"recipients"`
+	Active       bool               `json:"active"`
+	NextRunAt    pgtype.Timestamptz `json:"next_run_at"`
+	LastRunAt    pgtype.Timestamptz `json:"last_run_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
+}
+
 type ApiKey struct {
-	ID          uuid.UUID          `json:"id"`
-	WorkspaceID uuid.UUID          `json:"workspace_id"`
-	Name        string             `json:"name"`
-	KeyHash     string             `json:"key_hash"`
-	KeyPrefix   pgtype.Text        `json:"key_prefix"`
-	AccessLevel ApiKeyLevel        `json:"access_level"`
-	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
-	LastUsedAt  pgtype.Timestamptz `json:"last_used_at"`
-	Metadata    []byte             `json:"metadata"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	ID               uuid.UUID          `json:"id"`
+	WorkspaceID      uuid.UUID          `json:"workspace_id"`
+	Name             string             `json:"name"`
+	KeyHash          string             `json:"key_hash"`
+	KeyPrefix        pgtype.Text        `json:"key_prefix"`
+	AccessLevel      ApiKeyLevel        `json:"access_level"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt       pgtype.Timestamptz `json:"last_used_at"`
+	Metadata         []byte             `json:"metadata"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	RotatedFromID    pgtype.UUID        `json:"rotated_from_id"`
+	RotatedAt        pgtype.Timestamptz `json:"rotated_at"`
+	UnusedNotifiedAt pgtype.Timestamptz `json:"unused_notified_at"`
+}
+
+type ApiKeyUsageDaily struct {
+	ApiKeyID      uuid.UUID          `json:"api_key_id"`
+	WorkspaceID   uuid.UUID          `json:"workspace_id"`
+	UsageDate     pgtype.Date        `json:"usage_date"`
+	RequestCount  int64              `json:"request_count"`
+	LastIp        pgtype.Text        `json:"last_ip"`
+	LastUserAgent pgtype.Text        `json:"last_user_agent"`
+	LastUsedAt    pgtype.Timestamptz `json:"last_used_at"`
 }
 
 type CircleUser struct {
@@ -623,6 +674,33 @@ type CircleWallet struct {
 	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type CohortMetric struct {
+	WorkspaceID          uuid.UUID          `json:"workspace_id"`
+	CohortBy             string             `json:"cohort_by"`
+	LookbackMonths       int32              `json:"lookback_months"`
+	FiatCurrency         string             `json:"fiat_currency"`
+	CohortKey            string             `json:"cohort_key"`
+	CohortLabel          string             `json:"cohort_label"`
+	PeriodOffset         int32              `json:"period_offset"`
+	EligibleCustomers    int64              `json:"eligible_customers"`
+	ActiveCustomers      int64              `json:"active_customers"`
+	RevenueCents         int64              `json:"revenue_cents"`
+	StartingRevenueCents int64              `json:"starting_revenue_cents"`
+	CalculatedAt         pgtype.Timestamptz `json:"calculated_at"`
+}
+
+type CircuitBreaker struct {
+	Name                string             `json:"name"`
+	State               string             `json:"state"`
+	ConsecutiveFailures int32              `json:"consecutive_failures"`
+	FailureThreshold    int32              `json:"failure_threshold"`
+	LastFailureAt       pgtype.Timestamptz `json:"last_failure_at"`
+	LastError           pgtype.Text        `json:"last_error"`
+	StateChangedAt      pgtype.Timestamptz `json:"state_changed_at"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Customer struct {
 	ID                 uuid.UUID          `json:"id"`
 	NumID              int64              `json:"num_id"`
@@ -654,6 +732,33 @@ type Customer struct {
 	BillingPostalCode  pgtype.Text        `json:"billing_postal_code"`
 }
 
+type CustomerPortalSession struct {
+	ID                uuid.UUID          `json:"id"`
+	WorkspaceID       uuid.UUID          `json:"workspace_id"`
+	CustomerID        uuid.UUID          `json:"customer_id"`
+	TokenHash         string             `json:"token_hash"`
+	ReturnUrl         pgtype.Text        `json:"return_url"`
+	CreatedByApiKeyID pgtype.UUID        `json:"created_by_api_key_id"`
+	CreatedByUserID   pgtype.UUID        `json:"created_by_user_id"`
+	ExpiresAt         pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt        pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt         pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+}
+
+type CustomerPortalSetting struct {
+	WorkspaceID        uuid.UUID          `json:"workspace_id"`
+	AllowCancel        bool               `json:"allow_cancel"`
+	AllowPause         bool               `json:"allow_pause"`
+	AllowResume        bool               `json:"allow_resume"`
+	AllowUpdateBilling bool               `json:"allow_update_billing"`
+	AllowWalletSwitch  bool               `json:"allow_wallet_switch"`
+	DefaultReturnUrl   pgtype.Text        `json:"default_return_url"`
+	SessionTtlSeconds  int32              `json:"session_ttl_seconds"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+}
+
 type CustomerWallet struct {
 	ID            uuid.UUID          `json:"id"`
 	CustomerID    uuid.UUID          `json:"customer_id"`
@@ -661,6 +766,7 @@ type CustomerWallet struct {
 	NetworkType   NetworkType        `json:"network_type"`
 	Nickname      pgtype.Text        `json:"nickname"`
 	Ens           pgtype.Text        `json:"ens"`
+	EnsResolvedAt pgtype.Timestamptz `json:"ens_resolved_at"`
 	IsPrimary     pgtype.Bool        `json:"is_primary"`
 	Verified      pgtype.Bool        `json:"verified"`
 	LastUsedAt    pgtype.Timestamptz `json:"last_used_at"`
@@ -716,16 +822,19 @@ type DashboardMetric struct {
 }
 
 type DelegationDatum struct {
-	ID        uuid.UUID          `json:"id"`
-	Delegate  string             `json:"delegate"`
-	Delegator string             `json:"delegator"`
-	Authority string             `json:"authority"`
-	Caveats   json.RawMessage    `json:"caveats"`
-	Salt      string             `json:"salt"`
-	Signature string             `json:"signature"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	ID            uuid.UUID          `json:"id"`
+	Delegate      string             `json:"delegate"`
+	Delegator     string             `json:"delegator"`
+	Authority     string             `json:"authority"`
+	Caveats       json.RawMessage    `json:"caveats"`
+	Salt          string             `json:"salt"`
+	Signature     string             `json:"signature"`
+	Status        string             `json:"status"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+	LastCheckedAt pgtype.Timestamptz `json:"last_checked_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
 }
 
 type DunningAnalytic struct {
@@ -867,6 +976,25 @@ type FiatCurrency struct {
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
 
+type GasFeeEstimate struct {
+	ID                    uuid.UUID          `json:"id"`
+	WorkspaceID           uuid.UUID          `json:"workspace_id"`
+	PaymentID             pgtype.UUID        `json:"payment_id"`
+	NetworkID             uuid.UUID          `json:"network_id"`
+	EstimateSource        string             `json:"estimate_source"`
+	EstimatedGasUnits     int64              `json:"estimated_gas_units"`
+	GasUnitsSimulated     bool               `json:"gas_units_simulated"`
+	GasPriceWei           string             `json:"gas_price_wei"`
+	BaseFeeWei            pgtype.Text        `json:"base_fee_wei"`
+	PriorityFeeWei        pgtype.Text        `json:"priority_fee_wei"`
+	MaxFeePerGasWei       pgtype.Text        `json:"max_fee_per_gas_wei"`
+	L1DataFeeWei          pgtype.Text        `json:"l1_data_fee_wei"`
+	EstimatedCostWei      string             `json:"estimated_cost_wei"`
+	EstimatedCostUsdCents int64              `json:"estimated_cost_usd_cents"`
+	Confidence            float64            `json:"confidence"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+}
+
 type GasFeePayment struct {
 	ID                 uuid.UUID          `json:"id"`
 	PaymentID          uuid.UUID          `json:"payment_id"`
@@ -891,73 +1019,148 @@ type GasFeePayment struct {
 	CreatedAt          pgtype.Timestamptz `json:"created_at"`
 }
 
+type GasSponsorshipBudgetAlert struct {
+	WorkspaceID           uuid.UUID          `json:"workspace_id"`
+	PeriodStart           pgtype.Date        `json:"period_start"`
+	ThresholdPercent      int32              `json:"threshold_percent"`
+	SpentUsdCents         int64              `json:"spent_usd_cents"`
+	MonthlyBudgetUsdCents int64              `json:"monthly_budget_usd_cents"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+}
+
 type GasSponsorshipConfig struct {
+	ID                            uuid.UUID          `json:"id"`
+	WorkspaceID                   uuid.UUID          `json:"workspace_id"`
+	SponsorshipEnabled            pgtype.Bool        `json:"sponsorship_enabled"`
+	SponsorCustomerGas            pgtype.Bool        `json:"sponsor_customer_gas"`
+	SponsorThresholdUsdCents      pgtype.Int8        `json:"sponsor_threshold_usd_cents"`
+	MonthlyBudgetUsdCents         pgtype.Int8        `json:"monthly_budget_usd_cents"`
+	PerCustomerMonthlyCapUsdCents pgtype.Int8        `json:"per_customer_monthly_cap_usd_cents"`
+	SponsorForProducts            []byte             `json:"sponsor_for_products"`
+	SponsorForCustomers           []byte             `json:"sponsor_for_customers"`
+	SponsorForTiers               []byte             `json:"sponsor_for_tiers"`
+	CurrentMonthSpentCents        pgtype.Int8        `json:"current_month_spent_cents"`
+	CurrentMonthReservedCents     int64              `json:"current_month_reserved_cents"`
+	LastResetDate                 pgtype.Date        `json:"last_reset_date"`
+	CreatedAt                     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type GasSponsorshipCustomerSpending struct {
+	WorkspaceID      uuid.UUID          `json:"workspace_id"`
+	CustomerID       uuid.UUID          `json:"customer_id"`
+	PeriodStart      pgtype.Date        `json:"period_start"`
+	SpentUsdCents    int64              `json:"spent_usd_cents"`
+	ReservedUsdCents int64              `json:"reserved_usd_cents"`
+	SponsoredCount   int32              `json:"sponsored_count"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+}
+
+type GasSponsorshipLedger struct {
+	ID                    uuid.UUID          `json:"id"`
+	WorkspaceID           uuid.UUID          `json:"workspace_id"`
+	ReservationID         uuid.UUID          `json:"reservation_id"`
+	CustomerID            pgtype.UUID        `json:"customer_id"`
+	PaymentID             pgtype.UUID        `json:"payment_id"`
+	EntryType             string             `json:"entry_type"`
+	AmountUsdCents        int64              `json:"amount_usd_cents"`
+	BudgetSpentCents      int64              `json:"budget_spent_cents"`
+	BudgetReservedCents   int64              `json:"budget_reserved_cents"`
+	MonthlyBudgetUsdCents pgtype.Int8        `json:"monthly_budget_usd_cents"`
+	Reason                pgtype.Text        `json:"reason"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+}
+
+type GasSponsorshipReservation struct {
+	ID                uuid.UUID          `json:"id"`
+	WorkspaceID       uuid.UUID          `json:"workspace_id"`
+	CustomerID        pgtype.UUID        `json:"customer_id"`
+	PaymentID         pgtype.UUID        `json:"payment_id"`
+	RuleID            pgtype.UUID        `json:"rule_id"`
+	Status            string             `json:"status"`
+	ReservedUsdCents  int64              `json:"reserved_usd_cents"`
+	SettledUsdCents   pgtype.Int8        `json:"settled_usd_cents"`
+	SponsorPercentage int32              `json:"sponsor_percentage"`
+	CustomerReserved  bool               `json:"customer_reserved"`
+	PeriodStart       pgtype.Date        `json:"period_start"`
+	ReleaseReason     pgtype.Text        `json:"release_reason"`
+	ExpiresAt         pgtype.Timestamptz `json:"expires_at"`
+	SettledAt         pgtype.Timestamptz `json:"settled_at"`
+	ReleasedAt        pgtype.Timestamptz `json:"released_at"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+}
+
+type GasSponsorshipRule struct {
+	ID                  uuid.UUID          `json:"id"`
+	WorkspaceID         uuid.UUID          `json:"workspace_id"`
+	Name                string             `json:"name"`
+	Priority            int32              `json:"priority"`
+	IsActive            bool               `json:"is_active"`
+	Conditions          []byte             `json:"conditions"`
+	ActionType          string             `json:"action_type"`
+	SponsorUpToUsdCents pgtype.Int8        `json:"sponsor_up_to_usd_cents"`
+	SponsorPercentage   pgtype.Int4        `json:"sponsor_percentage"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt           pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type Invoice struct {
 	ID                       uuid.UUID          `json:"id"`
 	WorkspaceID              uuid.UUID          `json:"workspace_id"`
-	SponsorshipEnabled       pgtype.Bool        `json:"sponsorship_enabled"`
-	SponsorCustomerGas       pgtype.Bool        `json:"sponsor_customer_gas"`
-	SponsorThresholdUsdCents pgtype.Int8        `json:"sponsor_threshold_usd_cents"`
-	MonthlyBudgetUsdCents    pgtype.Int8        `json:"monthly_budget_usd_cents"`
-	SponsorForProducts       []byte             `json:"sponsor_for_products"`
-	SponsorForCustomers      []byte             `json:"sponsor_for_customers"`
-	SponsorForTiers          []byte             `json:"sponsor_for_tiers"`
-	CurrentMonthSpentCents   pgtype.Int8        `json:"current_month_spent_cents"`
-	LastResetDate            pgtype.Date        `json:"last_reset_date"`
+	CustomerID               pgtype.UUID        `json:"customer_id"`
+	SubscriptionID           pgtype.UUID        `json:"subscription_id"`
+	ExternalID               string             `json:"external_id"`
+	ExternalCustomerID       pgtype.Text        `json:"external_customer_id"`
+	ExternalSubscriptionID   pgtype.Text        `json:"external_subscription_id"`
+	Status                   string             `json:"status"`
+	CollectionMethod         pgtype.Text        `json:"collection_method"`
+	AmountDue                int32              `json:"amount_due"`
+	AmountPaid               int32              `json:"amount_paid"`
+	AmountRemaining          int32              `json:"amount_remaining"`
+	Currency                 string             `json:"currency"`
+	DueDate                  pgtype.Timestamptz `json:"due_date"`
+	PaidAt                   pgtype.Timestamptz `json:"paid_at"`
+	CreatedDate              pgtype.Timestamptz `json:"created_date"`
+	InvoicePdf               pgtype.Text        `json:"invoice_pdf"`
+	HostedInvoiceUrl         pgtype.Text        `json:"hosted_invoice_url"`
+	ChargeID                 pgtype.Text        `json:"charge_id"`
+	PaymentIntentID          pgtype.Text        `json:"payment_intent_id"`
+	LineItems                []byte             `json:"line_items"`
+	TaxAmount                pgtype.Int4        `json:"tax_amount"`
+	TotalTaxAmounts          []byte             `json:"total_tax_amounts"`
+	BillingReason            pgtype.Text        `json:"billing_reason"`
+	PaidOutOfBand            pgtype.Bool        `json:"paid_out_of_band"`
+	PaymentProvider          pgtype.Text        `json:"payment_provider"`
+	PaymentSyncStatus        pgtype.Text        `json:"payment_sync_status"`
+	PaymentSyncedAt          pgtype.Timestamptz `json:"payment_synced_at"`
+	AttemptCount             pgtype.Int4        `json:"attempt_count"`
+	NextPaymentAttempt       pgtype.Timestamptz `json:"next_payment_attempt"`
+	Metadata                 []byte             `json:"metadata"`
 	CreatedAt                pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt                pgtype.Timestamptz `json:"updated_at"`
-}
-
-type Invoice struct {
-	ID                     uuid.UUID          `json:"id"`
-	WorkspaceID            uuid.UUID          `json:"workspace_id"`
-	CustomerID             pgtype.UUID        `json:"customer_id"`
-	SubscriptionID         pgtype.UUID        `json:"subscription_id"`
-	ExternalID             string             `json:"external_id"`
-	ExternalCustomerID     pgtype.Text        `json:"external_customer_id"`
-	ExternalSubscriptionID pgtype.Text        `json:"external_subscription_id"`
-	Status                 string             `json:"status"`
-	CollectionMethod       pgtype.Text        `json:"collection_method"`
-	AmountDue              int32              `json:"amount_due"`
-	AmountPaid             int32              `json:"amount_paid"`
-	AmountRemaining        int32              `json:"amount_remaining"`
-	Currency               string             `json:"currency"`
-	DueDate                pgtype.Timestamptz `json:"due_date"`
-	PaidAt                 pgtype.Timestamptz `json:"paid_at"`
-	CreatedDate            pgtype.Timestamptz `json:"created_date"`
-	InvoicePdf             pgtype.Text        `json:"invoice_pdf"`
-	HostedInvoiceUrl       pgtype.Text        `json:"hosted_invoice_url"`
-	ChargeID               pgtype.Text        `json:"charge_id"`
-	PaymentIntentID        pgtype.Text        `json:"payment_intent_id"`
-	LineItems              []byte             `json:"line_items"`
-	TaxAmount              pgtype.Int4        `json:"tax_amount"`
-	TotalTaxAmounts        []byte             `json:"total_tax_amounts"`
-	BillingReason          pgtype.Text        `json:"billing_reason"`
-	PaidOutOfBand          pgtype.Bool        `json:"paid_out_of_band"`
-	PaymentProvider        pgtype.Text        `json:"payment_provider"`
-	PaymentSyncStatus      pgtype.Text        `json:"payment_sync_status"`
-	PaymentSyncedAt        pgtype.Timestamptz `json:"payment_synced_at"`
-	AttemptCount           pgtype.Int4        `json:"attempt_count"`
-	NextPaymentAttempt     pgtype.Timestamptz `json:"next_payment_attempt"`
-	Metadata               []byte             `json:"metadata"`
-	CreatedAt              pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt              pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt              pgtype.Timestamptz `json:"deleted_at"`
-	InvoiceNumber          pgtype.Text        `json:"invoice_number"`
-	SubtotalCents          pgtype.Int8        `json:"subtotal_cents"`
-	DiscountCents          pgtype.Int8        `json:"discount_cents"`
-	PaymentLinkID          pgtype.UUID        `json:"payment_link_id"`
-	DelegationAddress      pgtype.Text        `json:"delegation_address"`
-	QrCodeData             pgtype.Text        `json:"qr_code_data"`
-	TaxAmountCents         int64              `json:"tax_amount_cents"`
-	TaxDetails             []byte             `json:"tax_details"`
-	CustomerTaxID          pgtype.Text        `json:"customer_tax_id"`
-	CustomerJurisdictionID pgtype.UUID        `json:"customer_jurisdiction_id"`
-	ReverseChargeApplies   pgtype.Bool        `json:"reverse_charge_applies"`
-	ReminderSentAt         pgtype.Timestamptz `json:"reminder_sent_at"`
-	ReminderCount          pgtype.Int4        `json:"reminder_count"`
-	Notes                  pgtype.Text        `json:"notes"`
-	Terms                  pgtype.Text        `json:"terms"`
-	Footer                 pgtype.Text        `json:"footer"`
+	DeletedAt                pgtype.Timestamptz `json:"deleted_at"`
+	InvoiceNumber            pgtype.Text        `json:"invoice_number"`
+	SubtotalCents            pgtype.Int8        `json:"subtotal_cents"`
+	DiscountCents            pgtype.Int8        `json:"discount_cents"`
+	PaymentLinkID            pgtype.UUID        `json:"payment_link_id"`
+	DelegationAddress        pgtype.Text        `json:"delegation_address"`
+	QrCodeData               pgtype.Text        `json:"qr_code_data"`
+	TaxAmountCents           int64              `json:"tax_amount_cents"`
+	TaxDetails               []byte             `json:"tax_details"`
+	CustomerTaxID            pgtype.Text        `json:"customer_tax_id"`
+	CustomerJurisdictionID   pgtype.UUID        `json:"customer_jurisdiction_id"`
+	ReverseChargeApplies     pgtype.Bool        `json:"reverse_charge_applies"`
+	ReminderSentAt           pgtype.Timestamptz `json:"reminder_sent_at"`
+	ReminderCount            pgtype.Int4        `json:"reminder_count"`
+	Notes                    pgtype.Text        `json:"notes"`
+	Terms                    pgtype.Text        `json:"terms"`
+	Footer                   pgtype.Text        `json:"footer"`
+	TaxProvider              pgtype.Text        `json:"tax_provider"`
+	TaxProviderTransactionID pgtype.Text        `json:"tax_provider_transaction_id"`
+	TaxIDVerificationID      pgtype.UUID        `json:"tax_id_verification_id"`
 }
 
 type InvoiceActivity struct {
@@ -1002,6 +1205,28 @@ type InvoiceLineItem struct {
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
 
+type MrrMovement struct {
+	ID                     uuid.UUID          `json:"id"`
+	WorkspaceID            uuid.UUID          `json:"workspace_id"`
+	SubscriptionID         uuid.UUID          `json:"subscription_id"`
+	CustomerID             uuid.UUID          `json:"customer_id"`
+	MovementType           string             `json:"movement_type"`
+	Source                 string             `json:"source"`
+	IntervalType           NullIntervalType   `json:"interval_type"`
+	Currency               string             `json:"currency"`
+	FromAmountCents        int64              `json:"from_amount_cents"`
+	ToAmountCents          int64              `json:"to_amount_cents"`
+	FromMrrCents           int64              `json:"from_mrr_cents"`
+	ToMrrCents             int64              `json:"to_mrr_cents"`
+	MrrDeltaCents          int64              `json:"mrr_delta_cents"`
+	ReportingCurrency      string             `json:"reporting_currency"`
+	ExchangeRate           pgtype.Float8      `json:"exchange_rate"`
+	ReportingMrrDeltaCents pgtype.Int8        `json:"reporting_mrr_delta_cents"`
+	ScheduleChangeID       pgtype.UUID        `json:"schedule_change_id"`
+	OccurredAt             pgtype.Timestamptz `json:"occurred_at"`
+	CreatedAt              pgtype.Timestamptz `json:"created_at"`
+}
+
 type Network struct {
 	ID                    uuid.UUID          `json:"id"`
 	Name                  string             `json:"name"`
@@ -1026,6 +1251,8 @@ type Network struct {
 	GasPriorityLevels     []byte             `json:"gas_priority_levels"`
 	AverageBlockTimeMs    pgtype.Int4        `json:"average_block_time_ms"`
 	PeakHoursMultiplier   pgtype.Numeric     `json:"peak_hours_multiplier"`
+	ConfirmationBlocks    int32              `json:"confirmation_blocks"`
+	FinalityBlocks        int32              `json:"finality_blocks"`
 	CreatedAt             pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt             pgtype.Timestamptz `json:"deleted_at"`
@@ -1059,12 +1286,34 @@ type Payment struct {
 	InitiatedAt         pgtype.Timestamptz `json:"initiated_at"`
 	CompletedAt         pgtype.Timestamptz `json:"completed_at"`
 	FailedAt            pgtype.Timestamptz `json:"failed_at"`
+	RefundedAt          pgtype.Timestamptz `json:"refunded_at"`
 	ErrorMessage        pgtype.Text        `json:"error_message"`
 	Metadata            []byte             `json:"metadata"`
 	CreatedAt           pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
 }
 
+type PaymentConfirmation struct {
+	PaymentID       uuid.UUID          `json:"payment_id"`
+	NetworkID       uuid.UUID          `json:"network_id"`
+	TransactionHash string             `json:"transaction_hash"`
+	Status          string             `json:"status"`
+	SenderAddress   pgtype.Text        `json:"sender_address"`
+	Nonce           pgtype.Int8        `json:"nonce"`
+	BlockNumber     pgtype.Int8        `json:"block_number"`
+	BlockHash       pgtype.Text        `json:"block_hash"`
+	Confirmations   int32              `json:"confirmations"`
+	ReorgCount      int32              `json:"reorg_count"`
+	LastSeenAt      pgtype.Timestamptz `json:"last_seen_at"`
+	CheckedAt       pgtype.Timestamptz `json:"checked_at"`
+	ConfirmedAt     pgtype.Timestamptz `json:"confirmed_at"`
+	FinalizedAt     pgtype.Timestamptz `json:"finalized_at"`
+	RolledBackAt    pgtype.Timestamptz `json:"rolled_back_at"`
+	ErrorMessage    pgtype.Text        `json:"error_message"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
 type PaymentLink struct {
 	ID              uuid.UUID          `json:"id"`
 	WorkspaceID     uuid.UUID          `json:"workspace_id"`
@@ -1124,6 +1373,17 @@ type PaymentSyncSession struct {
 	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type PortfolioSnapshot struct {
+	ID              uuid.UUID          `json:"id"`
+	WorkspaceID     uuid.UUID          `json:"workspace_id"`
+	SnapshotAt      pgtype.Timestamptz `json:"snapshot_at"`
+	FiatCurrency    string             `json:"fiat_currency"`
+	TotalValueCents int64              `json:"total_value_cents"`
+	Holdings        []byte             `json:"holdings"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Product struct {
 	ID                  uuid.UUID          `json:"id"`
 	WorkspaceID         uuid.UUID          `json:"workspace_id"`
@@ -1177,6 +1437,60 @@ type ProductsToken struct {
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type RateLimitCounter struct {
+	BucketKey    string             `json:"bucket_key"`
+	WindowStart  pgtype.Timestamptz `json:"window_start"`
+	RequestCount int32              `json:"request_count"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+type RateLimitQuota struct {
+	ID                uuid.UUID          `json:"id"`
+	WorkspaceID       uuid.UUID          `json:"workspace_id"`
+	ApiKeyID          pgtype.UUID        `json:"api_key_id"`
+	Plan              string             `json:"plan"`
+	RouteClass        pgtype.Text        `json:"route_class"`
+	RequestsPerWindow pgtype.Int4        `json:"requests_per_window"`
+	WindowSeconds     pgtype.Int4        `json:"window_seconds"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+}
+
+type RedemptionTask struct {
+	ID                  uuid.UUID          `json:"id"`
+	SubscriptionID      uuid.UUID          `json:"subscription_id"`
+	DelegationID        uuid.UUID          `json:"delegation_id"`
+	ProductID           uuid.UUID          `json:"product_id"`
+	ProductTokenID      uuid.UUID          `json:"product_token_id"`
+	AmountInCents       int32              `json:"amount_in_cents"`
+	Metadata            []byte             `json:"metadata"`
+	IdempotencyKey      pgtype.Text        `json:"idempotency_key"`
+	Priority            int32              `json:"priority"`
+	Status              string             `json:"status"`
+	Attempts            int32              `json:"attempts"`
+	MaxAttempts         int32              `json:"max_attempts"`
+	VisibleAt           pgtype.Timestamptz `json:"visible_at"`
+	LockedBy            pgtype.Text        `json:"locked_by"`
+	RedemptionStartedAt pgtype.Timestamptz `json:"redemption_started_at"`
+	TransactionHash     pgtype.Text        `json:"transaction_hash"`
+	LastError           pgtype.Text        `json:"last_error"`
+	CompletedAt         pgtype.Timestamptz `json:"completed_at"`
+	DeadLetteredAt      pgtype.Timestamptz `json:"dead_lettered_at"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+}
+
+type SplApproval struct {
+	ID             uuid.UUID          `json:"id"`
+	Signature      string             `json:"signature"`
+	DelegationID   uuid.UUID          `json:"delegation_id"`
+	ProductTokenID uuid.UUID          `json:"product_token_id"`
+	TokenAccount   string             `json:"token_account"`
+	Owner          string             `json:"owner"`
+	Slot           int64              `json:"slot"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
 type Subscription struct {
 	ID                 uuid.UUID          `json:"id"`
 	NumID              int64              `json:"num_id"`
@@ -1260,6 +1574,38 @@ type SubscriptionProration struct {
 	CreatedAt           pgtype.Timestamptz `json:"created_at"`
 }
 
+type SubscriptionReauthorization struct {
+	ID                      uuid.UUID          `json:"id"`
+	SubscriptionID          uuid.UUID          `json:"subscription_id"`
+	WorkspaceID             uuid.UUID          `json:"workspace_id"`
+	CustomerID              uuid.UUID          `json:"customer_id"`
+	DelegationID            uuid.UUID          `json:"delegation_id"`
+	Reason                  string             `json:"reason"`
+	Status                  string             `json:"status"`
+	ReplacementDelegationID pgtype.UUID        `json:"replacement_delegation_id"`
+	NotificationCount       int32              `json:"notification_count"`
+	NotifiedAt              pgtype.Timestamptz `json:"notified_at"`
+	CompletedAt             pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt               pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt               pgtype.Timestamptz `json:"updated_at"`
+}
+
+type SubscriptionRenewal struct {
+	ID              uuid.UUID          `json:"id"`
+	SubscriptionID  uuid.UUID          `json:"subscription_id"`
+	IdempotencyKey  string             `json:"idempotency_key"`
+	PeriodDueAt     pgtype.Timestamptz `json:"period_due_at"`
+	Status          string             `json:"status"`
+	Attempts        int32              `json:"attempts"`
+	LeaseOwner      pgtype.Text        `json:"lease_owner"`
+	LeaseExpiresAt  pgtype.Timestamptz `json:"lease_expires_at"`
+	TransactionHash pgtype.Text        `json:"transaction_hash"`
+	ErrorMessage    pgtype.Text        `json:"error_message"`
+	CompletedAt     pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
 type SubscriptionScheduleChange struct {
 	ID                   uuid.UUID              `json:"id"`
 	SubscriptionID       uuid.UUID              `json:"subscription_id"`
@@ -1293,6 +1639,50 @@ type SubscriptionStateHistory struct {
 	OccurredAt        pgtype.Timestamptz     `json:"occurred_at"`
 }
 
+type TaxIDVerification struct {
+	ID                 uuid.UUID          `json:"id"`
+	CustomerID         pgtype.UUID        `json:"customer_id"`
+	CountryCode        string             `json:"country_code"`
+	TaxID              string             `json:"tax_id"`
+	IsValid            bool               `json:"is_valid"`
+	RegisteredName     pgtype.Text        `json:"registered_name"`
+	RegisteredAddress  pgtype.Text        `json:"registered_address"`
+	ConsultationNumber pgtype.Text        `json:"consultation_number"`
+	Source             string             `json:"source"`
+	CheckedAt          pgtype.Timestamptz `json:"checked_at"`
+	ExpiresAt          pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+}
+
+type TaxJurisdiction struct {
+	ID               uuid.UUID          `json:"id"`
+	Code             string             `json:"code"`
+	Name             string             `json:"name"`
+	JurisdictionType string             `json:"jurisdiction_type"`
+	CountryCode      string             `json:"country_code"`
+	SubdivisionCode  pgtype.Text        `json:"subdivision_code"`
+	Locality         pgtype.Text        `json:"locality"`
+	ParentID         pgtype.UUID        `json:"parent_id"`
+	TaxType          string             `json:"tax_type"`
+	IsActive         bool               `json:"is_active"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+}
+
+type TaxRate struct {
+	ID             uuid.UUID          `json:"id"`
+	JurisdictionID uuid.UUID          `json:"jurisdiction_id"`
+	ProductType    string             `json:"product_type"`
+	Rate           pgtype.Numeric     `json:"rate"`
+	ThresholdCents int64              `json:"threshold_cents"`
+	EffectiveFrom  pgtype.Timestamptz `json:"effective_from"`
+	EffectiveTo    pgtype.Timestamptz `json:"effective_to"`
+	RulesVersion   string             `json:"rules_version"`
+	Description    pgtype.Text        `json:"description"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Token struct {
 	ID              uuid.UUID          `json:"id"`
 	NetworkID       uuid.UUID          `json:"network_id"`
@@ -1307,6 +1697,49 @@ type Token struct {
 	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type TreasuryMovement struct {
+	ID                  uuid.UUID          `json:"id"`
+	WorkspaceID         uuid.UUID          `json:"workspace_id"`
+	RuleID              uuid.UUID          `json:"rule_id"`
+	NetworkID           uuid.UUID          `json:"network_id"`
+	TokenID             uuid.UUID          `json:"token_id"`
+	SourceWalletID      uuid.UUID          `json:"source_wallet_id"`
+	DestinationWalletID uuid.UUID          `json:"destination_wallet_id"`
+	Amount              pgtype.Numeric     `json:"amount"`
+	SourceBalance       pgtype.Numeric     `json:"source_balance"`
+	EstimatedFee        pgtype.Numeric     `json:"estimated_fee"`
+	Status              string             `json:"status"`
+	ChallengeID         pgtype.Text        `json:"challenge_id"`
+	CircleTransactionID pgtype.Text        `json:"circle_transaction_id"`
+	CircleState         pgtype.Text        `json:"circle_state"`
+	TransactionHash     pgtype.Text        `json:"transaction_hash"`
+	NetworkFee          pgtype.Numeric     `json:"network_fee"`
+	ErrorMessage        pgtype.Text        `json:"error_message"`
+	ReconciledAt        pgtype.Timestamptz `json:"reconciled_at"`
+	CompletedAt         pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+}
+
+type TreasurySweepRule struct {
+	ID                   uuid.UUID          `json:"id"`
+	WorkspaceID          uuid.UUID          `json:"workspace_id"`
+	Name                 string             `json:"name"`
+	SourceWalletID       uuid.UUID          `json:"source_wallet_id"`
+	DestinationWalletID  uuid.UUID          `json:"destination_wallet_id"`
+	TokenID              uuid.UUID          `json:"token_id"`
+	ThresholdAmount      pgtype.Numeric     `json:"threshold_amount"`
+	RetainedAmount       pgtype.Numeric     `json:"retained_amount"`
+	FeeLevel             string             `json:"fee_level"`
+	SweepIntervalMinutes int32              `json:"sweep_interval_minutes"`
+	IsActive             bool               `json:"is_active"`
+	LastRunAt            pgtype.Timestamptz `json:"last_run_at"`
+	LastRunResult        pgtype.Text        `json:"last_run_result"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt            pgtype.Timestamptz `json:"deleted_at"`
+}
+
 type User struct {
 	ID                 uuid.UUID          `json:"id"`
 	Web3authID         pgtype.Text        `json:"web3auth_id"`
@@ -1361,6 +1794,32 @@ type Wallet struct {
 	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type WalletBalance struct {
+	ID             uuid.UUID          `json:"id"`
+	WorkspaceID    uuid.UUID          `json:"workspace_id"`
+	WalletID       uuid.UUID          `json:"wallet_id"`
+	NetworkID      uuid.UUID          `json:"network_id"`
+	TokenID        uuid.UUID          `json:"token_id"`
+	Balance        pgtype.Numeric     `json:"balance"`
+	Source         string             `json:"source"`
+	FiatCurrency   string             `json:"fiat_currency"`
+	ExchangeRate   pgtype.Numeric     `json:"exchange_rate"`
+	FiatValueCents pgtype.Int8        `json:"fiat_value_cents"`
+	FetchedAt      pgtype.Timestamptz `json:"fetched_at"`
+	AttemptedAt    pgtype.Timestamptz `json:"attempted_at"`
+	ErrorMessage   pgtype.Text        `json:"error_message"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type WebhookReplayCache struct {
+	WorkspaceID     uuid.UUID          `json:"workspace_id"`
+	ProviderName    string             `json:"provider_name"`
+	ProviderEventID string             `json:"provider_event_id"`
+	ReceivedAt      pgtype.Timestamptz `json:"received_at"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+}
+
 type Workspace struct {
 	ID                  uuid.UUID          `json:"id"`
 	AccountID           uuid.UUID          `json:"account_id"`
@@ -1390,21 +1849,22 @@ type WorkspaceCustomer struct {
 }
 
 type WorkspacePaymentConfiguration struct {
-	ID                 uuid.UUID          `json:"id"`
-	WorkspaceID        uuid.UUID          `json:"workspace_id"`
-	ProviderName       string             `json:"provider_name"`
-	IsActive           bool               `json:"is_active"`
-	IsTestMode         bool               `json:"is_test_mode"`
-	Configuration      json.RawMessage    `json:"configuration"`
-	WebhookEndpointUrl pgtype.Text        `json:"webhook_endpoint_url"`
-	WebhookSecretKey   pgtype.Text        `json:"webhook_secret_key"`
-	ConnectedAccountID pgtype.Text        `json:"connected_account_id"`
-	LastSyncAt         pgtype.Timestamptz `json:"last_sync_at"`
-	LastWebhookAt      pgtype.Timestamptz `json:"last_webhook_at"`
-	Metadata           []byte             `json:"metadata"`
-	CreatedAt          pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
+	ID                   uuid.UUID          `json:"id"`
+	WorkspaceID          uuid.UUID          `json:"workspace_id"`
+	ProviderName         string             `json:"provider_name"`
+	IsActive             bool               `json:"is_active"`
+	IsTestMode           bool               `json:"is_test_mode"`
+	Configuration        json.RawMessage    `json:"configuration"`
+	WebhookEndpointUrl   pgtype.Text        `json:"webhook_endpoint_url"`
+	WebhookSecretKey     pgtype.Text        `json:"webhook_secret_key"`
+	ConnectedAccountID   pgtype.Text        `json:"connected_account_id"`
+	LastSyncAt           pgtype.Timestamptz `json:"last_sync_at"`
+	LastWebhookAt        pgtype.Timestamptz `json:"last_webhook_at"`
+	Metadata             []byte             `json:"metadata"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt            pgtype.Timestamptz `json:"deleted_at"`
+	EncryptionKeyVersion pgtype.Text        `json:"encryption_key_version"`
 }
 
 type WorkspaceProviderAccount struct {
@@ -1421,3 +1881,13 @@ type WorkspaceProviderAccount struct {
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
 }
+
+type WorkspaceWebhookSecret struct {
+	ID                   uuid.UUID          `json:"id"`
+	WorkspaceID          uuid.UUID          `json:"workspace_id"`
+	ProviderName         string             `json:"provider_name"`
+	WebhookSecretKey     string             `json:"webhook_secret_key"`
+	ExpiresAt            pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	EncryptionKeyVersion pgtype.Text        `json:"encryption_key_version"`
+}
@@ -18,7 +18,7 @@ SET
     active = true,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, created_at, updated_at, deleted_at
+RETURNING id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, created_at, updated_at, deleted_at
 `
 
 func (q *Queries) ActivateNetwork(ctx context.Context, id uuid.UUID) (Network, error) {
@@ -48,6 +48,8 @@ func (q *Queries) ActivateNetwork(ctx context.Context, id uuid.UUID) (Network, e
 		&i.GasPriorityLevels,
 		&i.AverageBlockTimeMs,
 		&i.PeakHoursMultiplier,
+		&i.ConfirmationBlocks,
+		&i.FinalityBlocks,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -77,11 +79,13 @@ INSERT INTO networks (
     gas_refresh_interval_ms,
     gas_priority_levels,
     average_block_time_ms,
-    peak_hours_multiplier
+    peak_hours_multiplier,
+    confirmation_blocks,
+    finality_blocks
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
 )
-RETURNING id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, created_at, updated_at, deleted_at
+RETURNING id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, created_at, updated_at, deleted_at
 `
 
 type CreateNetworkParams struct {
@@ -106,6 +110,8 @@ type CreateNetworkParams struct {
 	GasPriorityLevels     []byte            `json:"gas_priority_levels"`
 	AverageBlockTimeMs    pgtype.Int4       `json:"average_block_time_ms"`
 	PeakHoursMultiplier   pgtype.Numeric    `json:"peak_hours_multiplier"`
+	ConfirmationBlocks    int32             `json:"confirmation_blocks"`
+	FinalityBlocks        int32             `json:"finality_blocks"`
 }
 
 func (q *Queries) CreateNetwork(ctx context.Context, arg CreateNetworkParams) (Network, error) {
@@ -131,6 +137,8 @@ func (q *Queries) CreateNetwork(ctx context.Context, arg CreateNetworkParams) (N
 		arg.GasPriorityLevels,
 		arg.AverageBlockTimeMs,
 		arg.PeakHoursMultiplier,
+		arg.ConfirmationBlocks,
+		arg.FinalityBlocks,
 	)
 	var i Network
 	err := row.Scan(
@@ -157,6 +165,8 @@ func (q *Queries) CreateNetwork(ctx context.Context, arg CreateNetworkParams) (N
 		&i.GasPriorityLevels,
 		&i.AverageBlockTimeMs,
 		&i.PeakHoursMultiplier,
+		&i.ConfirmationBlocks,
+		&i.FinalityBlocks,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -170,7 +180,7 @@ SET
     active = false,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, created_at, updated_at, deleted_at
+RETURNING id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, created_at, updated_at, deleted_at
 `
 
 func (q *Queries) DeactivateNetwork(ctx context.Context, id uuid.UUID) (Network, error) {
@@ -200,6 +210,8 @@ func (q *Queries) DeactivateNetwork(ctx context.Context, id uuid.UUID) (Network,
 		&i.GasPriorityLevels,
 		&i.AverageBlockTimeMs,
 		&i.PeakHoursMultiplier,
+		&i.ConfirmationBlocks,
+		&i.FinalityBlocks,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -219,7 +231,7 @@ func (q *Queries) DeleteNetwork(ctx context.Context, id uuid.UUID) error {
 }
 
 const getNetwork = `-- name: GetNetwork :one
-SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, created_at, updated_at, deleted_at FROM networks
+SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, created_at, updated_at, deleted_at FROM networks
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -250,6 +262,8 @@ func (q *Queries) GetNetwork(ctx context.Context, id uuid.UUID) (Network, error)
 		&i.GasPriorityLevels,
 		&i.AverageBlockTimeMs,
 		&i.PeakHoursMultiplier,
+		&i.ConfirmationBlocks,
+		&i.FinalityBlocks,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -258,7 +272,7 @@ func (q *Queries) GetNetwork(ctx context.Context, id uuid.UUID) (Network, error)
 }
 
 const getNetworkByChainID = `-- name: GetNetworkByChainID :one
-SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, created_at, updated_at, deleted_at FROM networks
+SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, created_at, updated_at, deleted_at FROM networks
 WHERE chain_id = $1 AND deleted_at IS NULL
 `
 
@@ -289,6 +303,8 @@ func (q *Queries) GetNetworkByChainID(ctx context.Context, chainID int32) (Netwo
 		&i.GasPriorityLevels,
 		&i.AverageBlockTimeMs,
 		&i.PeakHoursMultiplier,
+		&i.ConfirmationBlocks,
+		&i.FinalityBlocks,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -297,7 +313,7 @@ func (q *Queries) GetNetworkByChainID(ctx context.Context, chainID int32) (Netwo
 }
 
 const getNetworkByCircleNetworkType = `-- name: GetNetworkByCircleNetworkType :one
-SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, created_at, updated_at, deleted_at FROM networks
+SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, created_at, updated_at, deleted_at FROM networks
 WHERE circle_network_type = $1 AND deleted_at IS NULL
 LIMIT 1
 `
@@ -329,6 +345,8 @@ func (q *Queries) GetNetworkByCircleNetworkType(ctx context.Context, circleNetwo
 		&i.GasPriorityLevels,
 		&i.AverageBlockTimeMs,
 		&i.PeakHoursMultiplier,
+		&i.ConfirmationBlocks,
+		&i.FinalityBlocks,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -337,7 +355,7 @@ func (q *Queries) GetNetworkByCircleNetworkType(ctx context.Context, circleNetwo
 }
 
 const listActiveCircleNetworks = `-- name: ListActiveCircleNetworks :many
-SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, created_at, updated_at, deleted_at FROM networks
+SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, created_at, updated_at, deleted_at FROM networks
 WHERE active = true 
   AND circle_network_type IS NOT NULL
   AND deleted_at IS NULL
@@ -377,6 +395,8 @@ func (q *Queries) ListActiveCircleNetworks(ctx context.Context) ([]Network, erro
 			&i.GasPriorityLevels,
 			&i.AverageBlockTimeMs,
 			&i.PeakHoursMultiplier,
+			&i.ConfirmationBlocks,
+			&i.FinalityBlocks,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
@@ -392,7 +412,7 @@ func (q *Queries) ListActiveCircleNetworks(ctx context.Context) ([]Network, erro
 }
 
 const listActiveNetworks = `-- name: ListActiveNetworks :many
-SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, created_at, updated_at, deleted_at FROM networks
+SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, created_at, updated_at, deleted_at FROM networks
 WHERE active = true AND deleted_at IS NULL
 ORDER BY name
 `
@@ -430,6 +450,8 @@ func (q *Queries) ListActiveNetworks(ctx context.Context) ([]Network, error) {
 			&i.GasPriorityLevels,
 			&i.AverageBlockTimeMs,
 			&i.PeakHoursMultiplier,
+			&i.ConfirmationBlocks,
+			&i.FinalityBlocks,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
@@ -445,7 +467,7 @@ func (q *Queries) ListActiveNetworks(ctx context.Context) ([]Network, error) {
 }
 
 const listNetworks = `-- name: ListNetworks :many
-SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, created_at, updated_at, deleted_at FROM networks
+SELECT id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, created_at, updated_at, deleted_at FROM networks
 WHERE deleted_at IS NULL
     AND CASE WHEN $1::boolean IS NOT NULL THEN is_testnet = $1::boolean ELSE TRUE END
     AND CASE WHEN $2::boolean IS NOT NULL THEN active = $2::boolean ELSE TRUE END
@@ -490,6 +512,8 @@ func (q *Queries) ListNetworks(ctx context.Context, arg ListNetworksParams) ([]N
 			&i.GasPriorityLevels,
 			&i.AverageBlockTimeMs,
 			&i.PeakHoursMultiplier,
+			&i.ConfirmationBlocks,
+			&i.FinalityBlocks,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
@@ -528,9 +552,11 @@ SET
     gas_priority_levels = COALESCE($20, gas_priority_levels),
     average_block_time_ms = COALESCE($21, average_block_time_ms),
     peak_hours_multiplier = COALESCE($22, peak_hours_multiplier),
+    confirmation_blocks = COALESCE($23, confirmation_blocks),
+    finality_blocks = COALESCE($24, finality_blocks),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, created_at, updated_at, deleted_at
+RETURNING id, name, type, network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, created_at, updated_at, deleted_at
 `
 
 type UpdateNetworkParams struct {
@@ -556,6 +582,8 @@ type UpdateNetworkParams struct {
 	GasPriorityLevels     []byte            `json:"gas_priority_levels"`
 	AverageBlockTimeMs    pgtype.Int4       `json:"average_block_time_ms"`
 	PeakHoursMultiplier   pgtype.Numeric    `json:"peak_hours_multiplier"`
+	ConfirmationBlocks    pgtype.Int4       `json:"confirmation_blocks"`
+	FinalityBlocks        pgtype.Int4       `json:"finality_blocks"`
 }
 
 func (q *Queries) UpdateNetwork(ctx context.Context, arg UpdateNetworkParams) (Network, error) {
@@ -582,6 +610,8 @@ func (q *Queries) UpdateNetwork(ctx context.Context, arg UpdateNetworkParams) (N
 		arg.GasPriorityLevels,
 		arg.AverageBlockTimeMs,
 		arg.PeakHoursMultiplier,
+		arg.ConfirmationBlocks,
+		arg.FinalityBlocks,
 	)
 	var i Network
 	err := row.Scan(
@@ -608,6 +638,8 @@ func (q *Queries) UpdateNetwork(ctx context.Context, arg UpdateNetworkParams) (N
 		&i.GasPriorityLevels,
 		&i.AverageBlockTimeMs,
 		&i.PeakHoursMultiplier,
+		&i.ConfirmationBlocks,
+		&i.FinalityBlocks,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -54,7 +54,7 @@ INSERT INTO payments (
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25
 )
-RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at
+RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at
 `
 
 type CreatePaymentParams struct {
@@ -142,6 +142,7 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -161,7 +162,7 @@ type CreatePaymentBatchParams struct {
 }
 
 const getPayment = `-- name: GetPayment :one
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE id = $1 AND workspace_id = $2
 `
 
@@ -201,6 +202,7 @@ func (q *Queries) GetPayment(ctx context.Context, arg GetPaymentParams) (Payment
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -210,7 +212,7 @@ func (q *Queries) GetPayment(ctx context.Context, arg GetPaymentParams) (Payment
 }
 
 const getPaymentBySubscriptionEvent = `-- name: GetPaymentBySubscriptionEvent :one
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE subscription_event = $1
 LIMIT 1
 `
@@ -246,6 +248,7 @@ func (q *Queries) GetPaymentBySubscriptionEvent(ctx context.Context, subscriptio
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -255,12 +258,19 @@ func (q *Queries) GetPaymentBySubscriptionEvent(ctx context.Context, subscriptio
 }
 
 const getPaymentByTransactionHash = `-- name: GetPaymentByTransactionHash :one
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE transaction_hash = $1
+    AND subscription_event IS NOT DISTINCT FROM $2
 `
 
-func (q *Queries) GetPaymentByTransactionHash(ctx context.Context, transactionHash pgtype.Text) (Payment, error) {
-	row := q.db.QueryRow(ctx, getPaymentByTransactionHash, transactionHash)
+type GetPaymentByTransactionHashParams struct {
+	TransactionHash   pgtype.Text `json:"transaction_hash"`
+	SubscriptionEvent pgtype.UUID `json:"subscription_event"`
+}
+
+// A batched redemption's hash is shared by one payment per subscription event, so the event is matched too
+func (q *Queries) GetPaymentByTransactionHash(ctx context.Context, arg GetPaymentByTransactionHashParams) (Payment, error) {
+	row := q.db.QueryRow(ctx, getPaymentByTransactionHash, arg.TransactionHash, arg.SubscriptionEvent)
 	var i Payment
 	err := row.Scan(
 		&i.ID,
@@ -290,6 +300,7 @@ func (q *Queries) GetPaymentByTransactionHash(ctx context.Context, transactionHa
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -358,6 +369,9 @@ WHERE workspace_id = $1
     AND completed_at >= $2
     AND completed_at < $3
     AND currency = $4
+    AND (payments.transaction_hash IS NULL OR payments.network_id IS NULL OR EXISTS (
+        SELECT 1 FROM payment_confirmations pc WHERE pc.payment_id = payments.id AND pc.status = 'finalized'
+    ))
 `
 
 type GetPaymentVolumeParams struct {
@@ -372,6 +386,7 @@ type GetPaymentVolumeRow struct {
 	PaymentCount     int64 `json:"payment_count"`
 }
 
+// Crypto payments are only counted once their transaction is finalized, since until then they can be rolled back
 func (q *Queries) GetPaymentVolume(ctx context.Context, arg GetPaymentVolumeParams) (GetPaymentVolumeRow, error) {
 	row := q.db.QueryRow(ctx, getPaymentVolume,
 		arg.WorkspaceID,
@@ -386,7 +401,7 @@ func (q *Queries) GetPaymentVolume(ctx context.Context, arg GetPaymentVolumePara
 
 const getPaymentWithGasDetails = `-- name: GetPaymentWithGasDetails :one
 SELECT 
-    p.id, p.workspace_id, p.invoice_id, p.subscription_id, p.subscription_event, p.customer_id, p.amount_in_cents, p.currency, p.status, p.payment_method, p.transaction_hash, p.network_id, p.token_id, p.crypto_amount, p.exchange_rate, p.has_gas_fee, p.gas_fee_usd_cents, p.gas_sponsored, p.external_payment_id, p.payment_provider, p.product_amount_cents, p.tax_amount_cents, p.gas_amount_cents, p.discount_amount_cents, p.initiated_at, p.completed_at, p.failed_at, p.error_message, p.metadata, p.created_at, p.updated_at,
+    p.id, p.workspace_id, p.invoice_id, p.subscription_id, p.subscription_event, p.customer_id, p.amount_in_cents, p.currency, p.status, p.payment_method, p.transaction_hash, p.network_id, p.token_id, p.crypto_amount, p.exchange_rate, p.has_gas_fee, p.gas_fee_usd_cents, p.gas_sponsored, p.external_payment_id, p.payment_provider, p.product_amount_cents, p.tax_amount_cents, p.gas_amount_cents, p.discount_amount_cents, p.initiated_at, p.completed_at, p.failed_at, p.refunded_at, p.error_message, p.metadata, p.created_at, p.updated_at,
     gfp.id as gas_fee_payment_id,
     gfp.gas_fee_wei,
     gfp.gas_price_gwei,
@@ -440,6 +455,7 @@ type GetPaymentWithGasDetailsRow struct {
 	InitiatedAt           pgtype.Timestamptz `json:"initiated_at"`
 	CompletedAt           pgtype.Timestamptz `json:"completed_at"`
 	FailedAt              pgtype.Timestamptz `json:"failed_at"`
+	RefundedAt            pgtype.Timestamptz `json:"refunded_at"`
 	ErrorMessage          pgtype.Text        `json:"error_message"`
 	Metadata              []byte             `json:"metadata"`
 	CreatedAt             pgtype.Timestamptz `json:"created_at"`
@@ -492,6 +508,7 @@ func (q *Queries) GetPaymentWithGasDetails(ctx context.Context, arg GetPaymentWi
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -516,7 +533,7 @@ func (q *Queries) GetPaymentWithGasDetails(ctx context.Context, arg GetPaymentWi
 }
 
 const getPaymentsByCustomer = `-- name: GetPaymentsByCustomer :many
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE customer_id = $1 AND workspace_id = $2
 ORDER BY created_at DESC
 LIMIT $3 OFFSET $4
@@ -571,6 +588,7 @@ func (q *Queries) GetPaymentsByCustomer(ctx context.Context, arg GetPaymentsByCu
 			&i.InitiatedAt,
 			&i.CompletedAt,
 			&i.FailedAt,
+			&i.RefundedAt,
 			&i.ErrorMessage,
 			&i.Metadata,
 			&i.CreatedAt,
@@ -588,7 +606,7 @@ func (q *Queries) GetPaymentsByCustomer(ctx context.Context, arg GetPaymentsByCu
 
 const getPaymentsByCustomerWithGas = `-- name: GetPaymentsByCustomerWithGas :many
 SELECT 
-    p.id, p.workspace_id, p.invoice_id, p.subscription_id, p.subscription_event, p.customer_id, p.amount_in_cents, p.currency, p.status, p.payment_method, p.transaction_hash, p.network_id, p.token_id, p.crypto_amount, p.exchange_rate, p.has_gas_fee, p.gas_fee_usd_cents, p.gas_sponsored, p.external_payment_id, p.payment_provider, p.product_amount_cents, p.tax_amount_cents, p.gas_amount_cents, p.discount_amount_cents, p.initiated_at, p.completed_at, p.failed_at, p.error_message, p.metadata, p.created_at, p.updated_at,
+    p.id, p.workspace_id, p.invoice_id, p.subscription_id, p.subscription_event, p.customer_id, p.amount_in_cents, p.currency, p.status, p.payment_method, p.transaction_hash, p.network_id, p.token_id, p.crypto_amount, p.exchange_rate, p.has_gas_fee, p.gas_fee_usd_cents, p.gas_sponsored, p.external_payment_id, p.payment_provider, p.product_amount_cents, p.tax_amount_cents, p.gas_amount_cents, p.discount_amount_cents, p.initiated_at, p.completed_at, p.failed_at, p.refunded_at, p.error_message, p.metadata, p.created_at, p.updated_at,
     CASE 
         WHEN p.has_gas_fee THEN gfp.gas_fee_wei
         ELSE NULL
@@ -639,6 +657,7 @@ type GetPaymentsByCustomerWithGasRow struct {
 	InitiatedAt         pgtype.Timestamptz `json:"initiated_at"`
 	CompletedAt         pgtype.Timestamptz `json:"completed_at"`
 	FailedAt            pgtype.Timestamptz `json:"failed_at"`
+	RefundedAt          pgtype.Timestamptz `json:"refunded_at"`
 	ErrorMessage        pgtype.Text        `json:"error_message"`
 	Metadata            []byte             `json:"metadata"`
 	CreatedAt           pgtype.Timestamptz `json:"created_at"`
@@ -689,6 +708,7 @@ func (q *Queries) GetPaymentsByCustomerWithGas(ctx context.Context, arg GetPayme
 			&i.InitiatedAt,
 			&i.CompletedAt,
 			&i.FailedAt,
+			&i.RefundedAt,
 			&i.ErrorMessage,
 			&i.Metadata,
 			&i.CreatedAt,
@@ -707,7 +727,7 @@ func (q *Queries) GetPaymentsByCustomerWithGas(ctx context.Context, arg GetPayme
 }
 
 const getPaymentsByDateRange = `-- name: GetPaymentsByDateRange :many
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE workspace_id = $1
     AND created_at >= $2
     AND created_at < $3
@@ -764,6 +784,7 @@ func (q *Queries) GetPaymentsByDateRange(ctx context.Context, arg GetPaymentsByD
 			&i.InitiatedAt,
 			&i.CompletedAt,
 			&i.FailedAt,
+			&i.RefundedAt,
 			&i.ErrorMessage,
 			&i.Metadata,
 			&i.CreatedAt,
@@ -780,7 +801,7 @@ func (q *Queries) GetPaymentsByDateRange(ctx context.Context, arg GetPaymentsByD
 }
 
 const getPaymentsByExternalId = `-- name: GetPaymentsByExternalId :one
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE workspace_id = $1
     AND external_payment_id = $2
     AND payment_provider = $3
@@ -823,6 +844,7 @@ func (q *Queries) GetPaymentsByExternalId(ctx context.Context, arg GetPaymentsBy
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -832,7 +854,7 @@ func (q *Queries) GetPaymentsByExternalId(ctx context.Context, arg GetPaymentsBy
 }
 
 const getPaymentsByInvoice = `-- name: GetPaymentsByInvoice :many
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE invoice_id = $1 AND workspace_id = $2
 ORDER BY created_at DESC
 `
@@ -879,6 +901,7 @@ func (q *Queries) GetPaymentsByInvoice(ctx context.Context, arg GetPaymentsByInv
 			&i.InitiatedAt,
 			&i.CompletedAt,
 			&i.FailedAt,
+			&i.RefundedAt,
 			&i.ErrorMessage,
 			&i.Metadata,
 			&i.CreatedAt,
@@ -895,7 +918,7 @@ func (q *Queries) GetPaymentsByInvoice(ctx context.Context, arg GetPaymentsByInv
 }
 
 const getPaymentsByStatus = `-- name: GetPaymentsByStatus :many
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE workspace_id = $1 AND status = $2
 ORDER BY created_at DESC
 LIMIT $3 OFFSET $4
@@ -950,6 +973,7 @@ func (q *Queries) GetPaymentsByStatus(ctx context.Context, arg GetPaymentsByStat
 			&i.InitiatedAt,
 			&i.CompletedAt,
 			&i.FailedAt,
+			&i.RefundedAt,
 			&i.ErrorMessage,
 			&i.Metadata,
 			&i.CreatedAt,
@@ -966,7 +990,7 @@ func (q *Queries) GetPaymentsByStatus(ctx context.Context, arg GetPaymentsByStat
 }
 
 const getPaymentsBySubscription = `-- name: GetPaymentsBySubscription :many
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE subscription_id = $1 AND workspace_id = $2
 ORDER BY created_at DESC
 LIMIT $3 OFFSET $4
@@ -1021,6 +1045,7 @@ func (q *Queries) GetPaymentsBySubscription(ctx context.Context, arg GetPayments
 			&i.InitiatedAt,
 			&i.CompletedAt,
 			&i.FailedAt,
+			&i.RefundedAt,
 			&i.ErrorMessage,
 			&i.Metadata,
 			&i.CreatedAt,
@@ -1037,7 +1062,7 @@ func (q *Queries) GetPaymentsBySubscription(ctx context.Context, arg GetPayments
 }
 
 const getPaymentsByTransactionHash = `-- name: GetPaymentsByTransactionHash :many
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE transaction_hash = $1
 `
 
@@ -1078,6 +1103,7 @@ func (q *Queries) GetPaymentsByTransactionHash(ctx context.Context, transactionH
 			&i.InitiatedAt,
 			&i.CompletedAt,
 			&i.FailedAt,
+			&i.RefundedAt,
 			&i.ErrorMessage,
 			&i.Metadata,
 			&i.CreatedAt,
@@ -1094,7 +1120,7 @@ func (q *Queries) GetPaymentsByTransactionHash(ctx context.Context, transactionH
 }
 
 const getPaymentsByWorkspace = `-- name: GetPaymentsByWorkspace :many
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE workspace_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -1143,6 +1169,7 @@ func (q *Queries) GetPaymentsByWorkspace(ctx context.Context, arg GetPaymentsByW
 			&i.InitiatedAt,
 			&i.CompletedAt,
 			&i.FailedAt,
+			&i.RefundedAt,
 			&i.ErrorMessage,
 			&i.Metadata,
 			&i.CreatedAt,
@@ -1159,7 +1186,7 @@ func (q *Queries) GetPaymentsByWorkspace(ctx context.Context, arg GetPaymentsByW
 }
 
 const getUnreconciledPayments = `-- name: GetUnreconciledPayments :many
-SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at FROM payments
+SELECT id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at FROM payments
 WHERE workspace_id = $1
     AND status = 'completed'
     AND invoice_id IS NULL
@@ -1217,6 +1244,7 @@ func (q *Queries) GetUnreconciledPayments(ctx context.Context, arg GetUnreconcil
 			&i.InitiatedAt,
 			&i.CompletedAt,
 			&i.FailedAt,
+			&i.RefundedAt,
 			&i.ErrorMessage,
 			&i.Metadata,
 			&i.CreatedAt,
@@ -1260,7 +1288,7 @@ SET
     invoice_id = $3,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2
-RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at
+RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at
 `
 
 type LinkPaymentToInvoiceParams struct {
@@ -1300,6 +1328,7 @@ func (q *Queries) LinkPaymentToInvoice(ctx context.Context, arg LinkPaymentToInv
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -1312,9 +1341,10 @@ const refundPayment = `-- name: RefundPayment :one
 UPDATE payments
 SET 
     status = 'refunded',
+    refunded_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND status = 'completed'
-RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at
+RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at
 `
 
 type RefundPaymentParams struct {
@@ -1353,6 +1383,7 @@ func (q *Queries) RefundPayment(ctx context.Context, arg RefundPaymentParams) (P
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -1370,7 +1401,7 @@ SET
     gas_amount_cents = CASE WHEN $5 = false THEN $4 ELSE 0 END,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2
-RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at
+RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at
 `
 
 type UpdatePaymentGasDetailsParams struct {
@@ -1418,6 +1449,7 @@ func (q *Queries) UpdatePaymentGasDetails(ctx context.Context, arg UpdatePayment
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -1432,7 +1464,7 @@ SET
     gas_sponsored = $3,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2
-RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at
+RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at
 `
 
 type UpdatePaymentGasSponsorshipParams struct {
@@ -1472,6 +1504,7 @@ func (q *Queries) UpdatePaymentGasSponsorship(ctx context.Context, arg UpdatePay
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -1485,7 +1518,7 @@ UPDATE payments
 SET invoice_id = $2,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at
+RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at
 `
 
 type UpdatePaymentInvoiceIDParams struct {
@@ -1524,6 +1557,7 @@ func (q *Queries) UpdatePaymentInvoiceID(ctx context.Context, arg UpdatePaymentI
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -1538,10 +1572,11 @@ SET
     status = $3,
     completed_at = CASE WHEN $3 = 'completed' THEN CURRENT_TIMESTAMP ELSE completed_at END,
     failed_at = CASE WHEN $3 = 'failed' THEN CURRENT_TIMESTAMP ELSE failed_at END,
+    refunded_at = CASE WHEN $3 = 'refunded' THEN COALESCE(refunded_at, CURRENT_TIMESTAMP) ELSE refunded_at END,
     error_message = $4,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2
-RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at
+RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at
 `
 
 type UpdatePaymentStatusParams struct {
@@ -1587,6 +1622,7 @@ func (q *Queries) UpdatePaymentStatus(ctx context.Context, arg UpdatePaymentStat
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
@@ -1602,7 +1638,7 @@ SET
     gas_fee_usd_cents = $4,
     updated_at = COALESCE($5, CURRENT_TIMESTAMP)
 WHERE id = $1 AND workspace_id = $2
-RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, error_message, metadata, created_at, updated_at
+RETURNING id, workspace_id, invoice_id, subscription_id, subscription_event, customer_id, amount_in_cents, currency, status, payment_method, transaction_hash, network_id, token_id, crypto_amount, exchange_rate, has_gas_fee, gas_fee_usd_cents, gas_sponsored, external_payment_id, payment_provider, product_amount_cents, tax_amount_cents, gas_amount_cents, discount_amount_cents, initiated_at, completed_at, failed_at, refunded_at, error_message, metadata, created_at, updated_at
 `
 
 type UpdatePaymentWithBlockchainDataParams struct {
@@ -1650,6 +1686,7 @@ func (q *Queries) UpdatePaymentWithBlockchainData(ctx context.Context, arg Updat
 		&i.InitiatedAt,
 		&i.CompletedAt,
 		&i.FailedAt,
+		&i.RefundedAt,
 		&i.ErrorMessage,
 		&i.Metadata,
 		&i.CreatedAt,
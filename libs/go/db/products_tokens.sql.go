@@ -343,7 +343,7 @@ func (q *Queries) GetActiveProductTokensByProduct(ctx context.Context, productID
 
 const getProductNetworks = `-- name: GetProductNetworks :many
 SELECT DISTINCT
-    n.id, n.name, n.type, n.network_type, n.circle_network_type, n.rpc_id, n.block_explorer_url, n.chain_id, n.is_testnet, n.active, n.logo_url, n.display_name, n.chain_namespace, n.base_fee_multiplier, n.priority_fee_multiplier, n.deployment_gas_limit, n.token_transfer_gas_limit, n.supports_eip1559, n.gas_oracle_url, n.gas_refresh_interval_ms, n.gas_priority_levels, n.average_block_time_ms, n.peak_hours_multiplier, n.created_at, n.updated_at, n.deleted_at,
+    n.id, n.name, n.type, n.network_type, n.circle_network_type, n.rpc_id, n.block_explorer_url, n.chain_id, n.is_testnet, n.active, n.logo_url, n.display_name, n.chain_namespace, n.base_fee_multiplier, n.priority_fee_multiplier, n.deployment_gas_limit, n.token_transfer_gas_limit, n.supports_eip1559, n.gas_oracle_url, n.gas_refresh_interval_ms, n.gas_priority_levels, n.average_block_time_ms, n.peak_hours_multiplier, n.confirmation_blocks, n.finality_blocks, n.created_at, n.updated_at, n.deleted_at,
     (
         SELECT COUNT(*) 
         FROM products_tokens pt2 
@@ -387,6 +387,8 @@ type GetProductNetworksRow struct {
 	GasPriorityLevels     []byte             `json:"gas_priority_levels"`
 	AverageBlockTimeMs    pgtype.Int4        `json:"average_block_time_ms"`
 	PeakHoursMultiplier   pgtype.Numeric     `json:"peak_hours_multiplier"`
+	ConfirmationBlocks    int32              `json:"confirmation_blocks"`
+	FinalityBlocks        int32              `json:"finality_blocks"`
 	CreatedAt             pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt             pgtype.Timestamptz `json:"deleted_at"`
@@ -426,6 +428,8 @@ func (q *Queries) GetProductNetworks(ctx context.Context, productID uuid.UUID) (
 			&i.GasPriorityLevels,
 			&i.AverageBlockTimeMs,
 			&i.PeakHoursMultiplier,
+			&i.ConfirmationBlocks,
+			&i.FinalityBlocks,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
@@ -20,9 +20,11 @@ type Querier interface {
 	ActivateSubscriptionLineItem(ctx context.Context, id uuid.UUID) (SubscriptionLineItem, error)
 	ActivateToken(ctx context.Context, id uuid.UUID) (Token, error)
 	AddCustomerToWorkspace(ctx context.Context, arg AddCustomerToWorkspaceParams) (WorkspaceCustomer, error)
+	AddGasSponsorshipCustomerSpending(ctx context.Context, arg AddGasSponsorshipCustomerSpendingParams) (GasSponsorshipCustomerSpending, error)
 	AddWorkspaceSupportedCurrency(ctx context.Context, arg AddWorkspaceSupportedCurrencyParams) error
 	ApplyProrationToInvoice(ctx context.Context, arg ApplyProrationToInvoiceParams) (SubscriptionProration, error)
 	ApplyProrationToPayment(ctx context.Context, arg ApplyProrationToPaymentParams) (SubscriptionProration, error)
+	AttachGasSponsorshipReservationPayment(ctx context.Context, arg AttachGasSponsorshipReservationPaymentParams) (GasSponsorshipReservation, error)
 	BatchCreateSubscriptionLineItems(ctx context.Context, arg []BatchCreateSubscriptionLineItemsParams) (int64, error)
 	BulkCreateInvoiceLineItemsFromSubscription(ctx context.Context, arg []BulkCreateInvoiceLineItemsFromSubscriptionParams) (int64, error)
 	// Bulk Operations for Initial Sync
@@ -31,6 +33,12 @@ type Querier interface {
 	BulkUpdateProductSyncStatus(ctx context.Context, arg BulkUpdateProductSyncStatusParams) error
 	// BulkUpdatePriceSyncStatus removed - pricing is now in products table
 	BulkUpdateSubscriptionSyncStatus(ctx context.Context, arg BulkUpdateSubscriptionSyncStatusParams) error
+	// Builds the cohort grid for customers who first subscribed from cohorts_from onwards. Cohorts are the
+	// month of a customer's first subscription, or the product or network they subscribed to. A customer
+	// is active in a month when one of their subscriptions in the cohort ended the month active or
+	// overdue, or they paid for it that month. Subscriptions without state history count as active from
+	// creation until their last update, or until now while they are active.
+	CalculateCohortMetrics(ctx context.Context, arg CalculateCohortMetricsParams) ([]CalculateCohortMetricsRow, error)
 	CalculateSubscriptionTotal(ctx context.Context, subscriptionID uuid.UUID) (interface{}, error)
 	CancelScheduledChange(ctx context.Context, id uuid.UUID) (SubscriptionScheduleChange, error)
 	CancelSubscription(ctx context.Context, id uuid.UUID) (Subscription, error)
@@ -42,27 +50,58 @@ type Querier interface {
 	CheckSlugExists(ctx context.Context, slug string) (bool, error)
 	// Validation and utility queries
 	CheckWorkspaceHasPaymentProvider(ctx context.Context, arg CheckWorkspaceHasPaymentProviderParams) (bool, error)
+	// Claims one job for processing; a running job whose worker stopped before stale_before can be claimed again
+	ClaimAnalyticsExportJob(ctx context.Context, arg ClaimAnalyticsExportJobParams) (AnalyticsExportJob, error)
+	// Claims the oldest pending jobs, and running jobs whose worker stopped before stale_before
+	ClaimAnalyticsExportJobs(ctx context.Context, arg ClaimAnalyticsExportJobsParams) ([]AnalyticsExportJob, error)
+	// Moves due schedules to their next run before delivery, so each period is reported once
+	ClaimDueAnalyticsReportSchedules(ctx context.Context, arg ClaimDueAnalyticsReportSchedulesParams) ([]ClaimDueAnalyticsReportSchedulesRow, error)
+	// Leases due subscriptions to one worker by opening (or re-opening) the renewal of their current period.
+	// Periods that already renewed, need review or are leased to another worker are skipped, so overlapping
+	// runs never renew the same period twice
+	ClaimDueSubscriptionRenewals(ctx context.Context, arg ClaimDueSubscriptionRenewalsParams) ([]SubscriptionRenewal, error)
+	// Leases renewals whose redemption went through but whose worker stopped before recording it,
+	// whether or not the subscription is still due
+	ClaimRedeemedSubscriptionRenewals(ctx context.Context, arg ClaimRedeemedSubscriptionRenewalsParams) ([]SubscriptionRenewal, error)
+	// Claims active rules whose interval has elapsed and that have no sweep in flight by starting their run at now,
+	// so overlapping runs never check the same rule. Returns what a run needs to size and submit the transfer
+	ClaimDueTreasurySweepRules(ctx context.Context, arg ClaimDueTreasurySweepRulesParams) ([]ClaimDueTreasurySweepRulesRow, error)
+	// Claims the next visible tasks, highest priority first, and hides them from other workers until visible_until.
+	// Processing tasks become visible again when their worker stops before finishing them
+	ClaimRedemptionTasks(ctx context.Context, arg ClaimRedemptionTasksParams) ([]RedemptionTask, error)
+	CompleteAnalyticsExportJob(ctx context.Context, arg CompleteAnalyticsExportJobParams) (AnalyticsExportJob, error)
+	CompleteRedemptionTask(ctx context.Context, arg CompleteRedemptionTaskParams) (RedemptionTask, error)
 	CompleteSubscription(ctx context.Context, id uuid.UUID) (Subscription, error)
+	CompleteSubscriptionReauthorization(ctx context.Context, arg CompleteSubscriptionReauthorizationParams) (SubscriptionReauthorization, error)
+	CompleteSubscriptionRenewal(ctx context.Context, arg CompleteSubscriptionRenewalParams) (SubscriptionRenewal, error)
 	CountActiveSubscriptions(ctx context.Context) (int64, error)
+	CountAnalyticsExportJobs(ctx context.Context, workspaceID uuid.UUID) (int64, error)
 	CountCustomerWallets(ctx context.Context, customerID uuid.UUID) (int64, error)
 	CountCustomers(ctx context.Context) (int64, error)
 	CountDelegations(ctx context.Context) (int64, error)
 	CountDelegationsByDelegator(ctx context.Context, delegator string) (int64, error)
 	CountFailedSubscriptionAttempts(ctx context.Context) (int64, error)
 	CountFailedSubscriptionAttemptsByErrorType(ctx context.Context, errorType SubscriptionEventType) (int64, error)
+	CountGasSponsorshipLedgerEntries(ctx context.Context, workspaceID uuid.UUID) (int64, error)
 	CountInvoicesByProvider(ctx context.Context, arg CountInvoicesByProviderParams) (int64, error)
 	CountInvoicesByStatus(ctx context.Context, arg CountInvoicesByStatusParams) (int64, error)
 	CountInvoicesByWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	CountMRRMovements(ctx context.Context, arg CountMRRMovementsParams) (int64, error)
+	// Rates whose period overlaps a new one. An open-ended rate supersedes the open-ended
+	// rate that started before it, so that one is not counted.
+	CountOverlappingTaxRates(ctx context.Context, arg CountOverlappingTaxRatesParams) (int64, error)
 	CountPaymentsByWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error)
 	CountProductAddons(ctx context.Context, baseProductID uuid.UUID) (int64, error)
 	CountProducts(ctx context.Context, workspaceID uuid.UUID) (int64, error)
 	CountProviderAccountsByProvider(ctx context.Context, providerName string) (int64, error)
 	CountProviderAccountsByWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	CountRedemptionTasks(ctx context.Context, status pgtype.Text) (int64, error)
 	CountSubscriptionEventDetails(ctx context.Context, workspaceID uuid.UUID) (int64, error)
 	CountSubscriptionEvents(ctx context.Context) (int64, error)
 	CountSubscriptionEventsBySubscription(ctx context.Context, subscriptionID uuid.UUID) (int64, error)
 	CountSubscriptionEventsByType(ctx context.Context, eventType SubscriptionEventType) (int64, error)
 	CountSubscriptionLineItems(ctx context.Context, subscriptionID uuid.UUID) (int64, error)
+	CountSubscriptionRenewals(ctx context.Context, status pgtype.Text) (int64, error)
 	CountSubscriptions(ctx context.Context) (int64, error)
 	CountSubscriptionsByStatus(ctx context.Context, status SubscriptionStatus) (int64, error)
 	CountSuccessfulAttempts(ctx context.Context, campaignID uuid.UUID) (int64, error)
@@ -71,16 +110,23 @@ type Querier interface {
 	CountSyncEventsBySessionAndType(ctx context.Context, arg CountSyncEventsBySessionAndTypeParams) (int64, error)
 	CountSyncSessions(ctx context.Context, workspaceID uuid.UUID) (int64, error)
 	CountSyncSessionsByProvider(ctx context.Context, arg CountSyncSessionsByProviderParams) (int64, error)
+	CountTreasuryMovements(ctx context.Context, arg CountTreasuryMovementsParams) (int64, error)
 	CountVerifiedCustomerWallets(ctx context.Context, customerID uuid.UUID) (int64, error)
 	// NEW: Count webhook events for a provider
 	CountWebhookEventsByProvider(ctx context.Context, arg CountWebhookEventsByProviderParams) (int64, error)
 	CountWorkspaceCustomers(ctx context.Context, workspaceID uuid.UUID) (int64, error)
 	CountWorkspacePaymentConfigurations(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	CountWorkspacePaymentConfigurationsByKeyVersion(ctx context.Context) ([]CountWorkspacePaymentConfigurationsByKeyVersionRow, error)
+	// Expired secrets are never decrypted again, so they do not keep a key version in use
+	CountWorkspaceWebhookSecretsByKeyVersion(ctx context.Context) ([]CountWorkspaceWebhookSecretsByKeyVersionRow, error)
 	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAnalyticsExportJob(ctx context.Context, arg CreateAnalyticsExportJobParams) (AnalyticsExportJob, error)
+	CreateAnalyticsReportSchedule(ctx context.Context, arg CreateAnalyticsReportScheduleParams) (AnalyticsReportSchedule, error)
 	CreateCircleUser(ctx context.Context, arg CreateCircleUserParams) (CircleUser, error)
 	CreateCircleWalletEntry(ctx context.Context, arg CreateCircleWalletEntryParams) (CircleWallet, error)
 	CreateCustomer(ctx context.Context, arg CreateCustomerParams) (Customer, error)
+	CreateCustomerPortalSession(ctx context.Context, arg CreateCustomerPortalSessionParams) (CustomerPortalSession, error)
 	CreateCustomerWallet(ctx context.Context, arg CreateCustomerWalletParams) (CustomerWallet, error)
 	CreateCustomerWithSync(ctx context.Context, arg CreateCustomerWithSyncParams) (Customer, error)
 	CreateCustomerWithWeb3Auth(ctx context.Context, arg CreateCustomerWithWeb3AuthParams) (Customer, error)
@@ -92,14 +138,17 @@ type Querier interface {
 	CreateDunningEmailTemplate(ctx context.Context, arg CreateDunningEmailTemplateParams) (DunningEmailTemplate, error)
 	CreateFailedRedemptionEvent(ctx context.Context, arg CreateFailedRedemptionEventParams) (SubscriptionEvent, error)
 	CreateFailedSubscriptionAttempt(ctx context.Context, arg CreateFailedSubscriptionAttemptParams) (FailedSubscriptionAttempt, error)
+	CreateGasFeeEstimate(ctx context.Context, arg CreateGasFeeEstimateParams) (GasFeeEstimate, error)
 	CreateGasFeePayment(ctx context.Context, arg CreateGasFeePaymentParams) (GasFeePayment, error)
 	CreateGasSponsorshipConfig(ctx context.Context, arg CreateGasSponsorshipConfigParams) (GasSponsorshipConfig, error)
+	CreateGasSponsorshipRule(ctx context.Context, arg CreateGasSponsorshipRuleParams) (GasSponsorshipRule, error)
 	CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error)
 	CreateInvoiceActivity(ctx context.Context, arg CreateInvoiceActivityParams) (InvoiceActivity, error)
 	CreateInvoiceLineItem(ctx context.Context, arg CreateInvoiceLineItemParams) (InvoiceLineItem, error)
 	CreateInvoiceLineItemBatch(ctx context.Context, arg []CreateInvoiceLineItemBatchParams) (int64, error)
 	CreateInvoiceLineItemFromSubscription(ctx context.Context, arg CreateInvoiceLineItemFromSubscriptionParams) (InvoiceLineItem, error)
 	CreateInvoiceWithDetails(ctx context.Context, arg CreateInvoiceWithDetailsParams) (Invoice, error)
+	CreateMRRMovement(ctx context.Context, arg CreateMRRMovementParams) (MrrMovement, error)
 	CreateNetwork(ctx context.Context, arg CreateNetworkParams) (Network, error)
 	CreateOrUpdateDunningAnalytics(ctx context.Context, arg CreateOrUpdateDunningAnalyticsParams) (DunningAnalytic, error)
 	CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error)
@@ -112,6 +161,7 @@ type Querier interface {
 	CreateProrationRecord(ctx context.Context, arg CreateProrationRecordParams) (SubscriptionProration, error)
 	CreateRedemptionEvent(ctx context.Context, arg CreateRedemptionEventParams) (SubscriptionEvent, error)
 	CreateScheduleChange(ctx context.Context, arg CreateScheduleChangeParams) (SubscriptionScheduleChange, error)
+	CreateSplApproval(ctx context.Context, arg CreateSplApprovalParams) (SplApproval, error)
 	CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error)
 	CreateSubscriptionEvent(ctx context.Context, arg CreateSubscriptionEventParams) (SubscriptionEvent, error)
 	CreateSubscriptionLineItem(ctx context.Context, arg CreateSubscriptionLineItemParams) (SubscriptionLineItem, error)
@@ -120,7 +170,12 @@ type Querier interface {
 	CreateSyncEvent(ctx context.Context, arg CreateSyncEventParams) (PaymentSyncEvent, error)
 	// Payment Sync Sessions Queries
 	CreateSyncSession(ctx context.Context, arg CreateSyncSessionParams) (PaymentSyncSession, error)
+	CreateTaxIDVerification(ctx context.Context, arg CreateTaxIDVerificationParams) (TaxIDVerification, error)
+	CreateTaxJurisdiction(ctx context.Context, arg CreateTaxJurisdictionParams) (TaxJurisdiction, error)
+	CreateTaxRate(ctx context.Context, arg CreateTaxRateParams) (TaxRate, error)
 	CreateToken(ctx context.Context, arg CreateTokenParams) (Token, error)
+	CreateTreasuryMovement(ctx context.Context, arg CreateTreasuryMovementParams) (TreasuryMovement, error)
+	CreateTreasurySweepRule(ctx context.Context, arg CreateTreasurySweepRuleParams) (TreasurySweepRule, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateWallet(ctx context.Context, arg CreateWalletParams) (Wallet, error)
 	// NEW: Create webhook-specific sync event with all webhook fields
@@ -131,6 +186,8 @@ type Querier interface {
 	// workspace_provider_accounts.sql
 	// SQLC queries for generic provider account mapping (Stripe, Chargebee, PayPal, etc.)
 	CreateWorkspaceProviderAccount(ctx context.Context, arg CreateWorkspaceProviderAccountParams) (WorkspaceProviderAccount, error)
+	// Keep a rotated-out webhook secret valid until the grace window ends
+	CreateWorkspaceWebhookSecret(ctx context.Context, arg CreateWorkspaceWebhookSecretParams) (WorkspaceWebhookSecret, error)
 	DeactivateAllProductTokens(ctx context.Context, productID uuid.UUID) error
 	DeactivateAllProductTokensForNetwork(ctx context.Context, arg DeactivateAllProductTokensForNetworkParams) error
 	DeactivateFiatCurrency(ctx context.Context, code string) error
@@ -143,10 +200,15 @@ type Querier interface {
 	DeactivateToken(ctx context.Context, id uuid.UUID) (Token, error)
 	DeactivateWorkspacePaymentConfiguration(ctx context.Context, arg DeactivateWorkspacePaymentConfigurationParams) (WorkspacePaymentConfiguration, error)
 	DeactivateWorkspaceProviderAccount(ctx context.Context, arg DeactivateWorkspaceProviderAccountParams) (WorkspaceProviderAccount, error)
+	// Stops retrying a task; it stays in the queue until an admin requeues it
+	DeadLetterRedemptionTask(ctx context.Context, arg DeadLetterRedemptionTaskParams) (RedemptionTask, error)
+	// Hands a task back without counting the attempt, for when it could not be tried at all
+	DeferRedemptionTask(ctx context.Context, arg DeferRedemptionTaskParams) (RedemptionTask, error)
 	DeleteAPIKey(ctx context.Context, arg DeleteAPIKeyParams) error
 	DeleteAccount(ctx context.Context, id uuid.UUID) error
 	DeleteAllAddonsForProduct(ctx context.Context, baseProductID uuid.UUID) error
 	DeleteAllSubscriptionLineItems(ctx context.Context, subscriptionID uuid.UUID) error
+	DeleteAnalyticsReportSchedule(ctx context.Context, arg DeleteAnalyticsReportScheduleParams) error
 	DeleteCircleUser(ctx context.Context, id uuid.UUID) error
 	DeleteCircleUserByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error
 	DeleteCustomer(ctx context.Context, id uuid.UUID) error
@@ -155,7 +217,12 @@ type Querier interface {
 	DeleteDelegationData(ctx context.Context, id uuid.UUID) error
 	DeleteDunningConfiguration(ctx context.Context, id uuid.UUID) (DunningConfiguration, error)
 	DeleteDunningEmailTemplate(ctx context.Context, id uuid.UUID) (DunningEmailTemplate, error)
+	DeleteExpiredCustomerPortalSessions(ctx context.Context, expiresAt pgtype.Timestamptz) (int64, error)
+	DeleteExpiredRateLimitCounters(ctx context.Context) error
+	DeleteExpiredWebhookReplayEntries(ctx context.Context) error
+	DeleteExpiredWorkspaceWebhookSecrets(ctx context.Context) error
 	DeleteFailedSubscriptionAttempt(ctx context.Context, id uuid.UUID) error
+	DeleteGasSponsorshipRule(ctx context.Context, arg DeleteGasSponsorshipRuleParams) (int64, error)
 	DeleteInvoice(ctx context.Context, arg DeleteInvoiceParams) error
 	DeleteInvoiceLineItem(ctx context.Context, id uuid.UUID) error
 	DeleteInvoiceLineItems(ctx context.Context, invoiceID uuid.UUID) error
@@ -168,17 +235,38 @@ type Querier interface {
 	DeleteProductToken(ctx context.Context, id uuid.UUID) error
 	DeleteProductTokenByIds(ctx context.Context, arg DeleteProductTokenByIdsParams) error
 	DeleteProductTokensByProduct(ctx context.Context, productID uuid.UUID) error
+	// Only rates that have not taken effect yet can be deleted; others must be ended
+	DeleteScheduledTaxRate(ctx context.Context, id uuid.UUID) (int64, error)
+	// Removes cells of a calculation that the latest calculation no longer produced
+	DeleteStaleCohortMetrics(ctx context.Context, arg DeleteStaleCohortMetricsParams) error
 	DeleteSubscription(ctx context.Context, id uuid.UUID) error
 	DeleteSubscriptionLineItem(ctx context.Context, id uuid.UUID) error
 	DeleteSyncEventsBySession(ctx context.Context, sessionID uuid.UUID) error
 	DeleteSyncSession(ctx context.Context, arg DeleteSyncSessionParams) error
 	DeleteToken(ctx context.Context, id uuid.UUID) error
+	DeleteTreasurySweepRule(ctx context.Context, arg DeleteTreasurySweepRuleParams) (int64, error)
 	DeleteUser(ctx context.Context, id uuid.UUID) error
+	// Releases an event ID so the provider's retry is accepted (e.g. after a failed enqueue)
+	DeleteWebhookReplayEntry(ctx context.Context, arg DeleteWebhookReplayEntryParams) error
 	DeleteWorkspace(ctx context.Context, id uuid.UUID) error
 	DeleteWorkspacePaymentConfiguration(ctx context.Context, arg DeleteWorkspacePaymentConfigurationParams) (WorkspacePaymentConfiguration, error)
 	DeleteWorkspaceProviderAccount(ctx context.Context, arg DeleteWorkspaceProviderAccountParams) error
+	EndTaxRate(ctx context.Context, arg EndTaxRateParams) (TaxRate, error)
+	// Enqueuing a task with an idempotency key that is already queued returns the existing task
+	EnqueueRedemptionTask(ctx context.Context, arg EnqueueRedemptionTaskParams) (RedemptionTask, error)
 	ExpirePaymentLinks(ctx context.Context) error
+	// Returns the job to the queue until it has used max_attempts
+	FailAnalyticsExportJob(ctx context.Context, arg FailAnalyticsExportJobParams) (AnalyticsExportJob, error)
 	FailDunningCampaign(ctx context.Context, arg FailDunningCampaignParams) (DunningCampaign, error)
+	// Fails a redeemed renewal whose transaction reverted on-chain, so the customer was not charged. The lease is kept,
+	// so the period is retried once it runs out
+	FailRevertedSubscriptionRenewal(ctx context.Context, arg FailRevertedSubscriptionRenewalParams) (int64, error)
+	// Only renewals that never recorded a redemption fail; a redeemed renewal keeps its transaction for the next run.
+	// The lease is kept, so the period is retried once it runs out
+	FailSubscriptionRenewal(ctx context.Context, arg FailSubscriptionRenewalParams) (int64, error)
+	FailTreasuryMovement(ctx context.Context, arg FailTreasuryMovementParams) error
+	// Most specific active state, province or country jurisdiction for a location
+	FindTaxJurisdiction(ctx context.Context, arg FindTaxJurisdictionParams) (TaxJurisdiction, error)
 	GetAPIKey(ctx context.Context, arg GetAPIKeyParams) (ApiKey, error)
 	GetAPIKeyByKey(ctx context.Context, keyHash string) (ApiKey, error)
 	GetAccount(ctx context.Context, id uuid.UUID) (Account, error)
@@ -187,6 +275,7 @@ type Querier interface {
 	GetAccountOwner(ctx context.Context, accountID uuid.UUID) (User, error)
 	GetAccountUsers(ctx context.Context, accountID uuid.UUID) ([]GetAccountUsersRow, error)
 	GetActiveAPIKeysCount(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	GetActiveCustomerPortalSessionByTokenHash(ctx context.Context, tokenHash string) (CustomerPortalSession, error)
 	GetActiveDunningCampaignForPayment(ctx context.Context, paymentID pgtype.UUID) (DunningCampaign, error)
 	GetActiveDunningCampaignForSubscription(ctx context.Context, subscriptionID pgtype.UUID) (DunningCampaign, error)
 	GetActiveGasSponsorships(ctx context.Context) ([]GasSponsorshipConfig, error)
@@ -194,6 +283,9 @@ type Querier interface {
 	GetActiveProductTokensByNetwork(ctx context.Context, arg GetActiveProductTokensByNetworkParams) ([]GetActiveProductTokensByNetworkRow, error)
 	GetActiveProductTokensByProduct(ctx context.Context, productID uuid.UUID) ([]GetActiveProductTokensByProductRow, error)
 	GetActiveProductsByWalletID(ctx context.Context, walletID uuid.UUID) ([]Product, error)
+	// A token account has a single delegate approval, so a new approval on an account that already pays for a
+	// subscription replaces the approval that subscription renews with
+	GetActiveSplApprovalByTokenAccount(ctx context.Context, arg GetActiveSplApprovalByTokenAccountParams) (SplApproval, error)
 	GetActiveSyncSessionsByProvider(ctx context.Context, arg GetActiveSyncSessionsByProviderParams) ([]PaymentSyncSession, error)
 	GetAddonProducts(ctx context.Context, workspaceID uuid.UUID) ([]Product, error)
 	GetAllAPIKeys(ctx context.Context) ([]ApiKey, error)
@@ -202,15 +294,20 @@ type Querier interface {
 	GetAllActiveAPIKeys(ctx context.Context) ([]ApiKey, error)
 	GetAllCustomers(ctx context.Context) ([]Customer, error)
 	GetAllWorkspaces(ctx context.Context) ([]Workspace, error)
+	GetAnalyticsExportJob(ctx context.Context, arg GetAnalyticsExportJobParams) (AnalyticsExportJob, error)
+	GetAnalyticsReportSchedule(ctx context.Context, arg GetAnalyticsReportScheduleParams) (AnalyticsReportSchedule, error)
 	GetAttemptsByType(ctx context.Context, campaignID uuid.UUID) ([]GetAttemptsByTypeRow, error)
 	GetBaseProducts(ctx context.Context, workspaceID uuid.UUID) ([]Product, error)
 	GetBusinessCustomers(ctx context.Context, arg GetBusinessCustomersParams) ([]Customer, error)
+	// Latest registry answer for a tax ID that has not expired yet
+	GetCachedTaxIDVerification(ctx context.Context, arg GetCachedTaxIDVerificationParams) (TaxIDVerification, error)
 	GetCampaignsNeedingFinalAction(ctx context.Context) ([]GetCampaignsNeedingFinalActionRow, error)
 	GetCircleUserByID(ctx context.Context, id uuid.UUID) (CircleUser, error)
 	GetCircleUserByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (CircleUser, error)
 	GetCircleUserWithWallets(ctx context.Context, id uuid.UUID) (GetCircleUserWithWalletsRow, error)
 	GetCircleUserWithWalletsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (GetCircleUserWithWalletsByWorkspaceIDRow, error)
 	GetCircleWalletByCircleWalletID(ctx context.Context, circleWalletID string) (GetCircleWalletByCircleWalletIDRow, error)
+	GetCircuitBreaker(ctx context.Context, name string) (CircuitBreaker, error)
 	GetCurrentMRR(ctx context.Context, arg GetCurrentMRRParams) (GetCurrentMRRRow, error)
 	GetCustomer(ctx context.Context, id uuid.UUID) (Customer, error)
 	GetCustomerByEmail(ctx context.Context, email pgtype.Text) (Customer, error)
@@ -223,7 +320,9 @@ type Querier interface {
 	// Get the customer_id for a wallet
 	GetCustomerIdForWallet(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
 	GetCustomerMetricsTrend(ctx context.Context, arg GetCustomerMetricsTrendParams) ([]GetCustomerMetricsTrendRow, error)
+	GetCustomerPortalSettings(ctx context.Context, workspaceID uuid.UUID) (CustomerPortalSetting, error)
 	GetCustomerWallet(ctx context.Context, id uuid.UUID) (CustomerWallet, error)
+	// EVM addresses match whatever their casing
 	GetCustomerWalletByAddress(ctx context.Context, arg GetCustomerWalletByAddressParams) (CustomerWallet, error)
 	GetCustomersByBillingCountry(ctx context.Context, arg GetCustomersByBillingCountryParams) ([]Customer, error)
 	// Payment Sync Related Customer Queries
@@ -231,6 +330,7 @@ type Querier interface {
 	// Payment Sync Status Queries for Entities
 	GetCustomersByPaymentProvider(ctx context.Context, paymentProvider pgtype.Text) ([]Customer, error)
 	GetCustomersByPaymentSyncStatus(ctx context.Context, paymentSyncStatus pgtype.Text) ([]Customer, error)
+	// EVM addresses match whatever their casing
 	GetCustomersByWalletAddress(ctx context.Context, walletAddress string) ([]Customer, error)
 	GetCustomersNeedingSync(ctx context.Context) ([]Customer, error)
 	GetCustomersSyncedByProvider(ctx context.Context, paymentProvider pgtype.Text) ([]Customer, error)
@@ -271,6 +371,9 @@ type Querier interface {
 	GetFailedWebhooksForRetry(ctx context.Context, arg GetFailedWebhooksForRetryParams) ([]GetFailedWebhooksForRetryRow, error)
 	GetFiatCurrency(ctx context.Context, code string) (FiatCurrency, error)
 	GetFiatCurrencyByCode(ctx context.Context, code string) (FiatCurrency, error)
+	// Compares estimates with the actual fees of the same payments, per network and estimate source.
+	// Ratios above 1 mean the estimates were too high.
+	GetGasFeeEstimateAccuracy(ctx context.Context, arg GetGasFeeEstimateAccuracyParams) ([]GetGasFeeEstimateAccuracyRow, error)
 	GetGasFeeMetrics(ctx context.Context, arg GetGasFeeMetricsParams) (GetGasFeeMetricsRow, error)
 	GetGasFeePayment(ctx context.Context, id uuid.UUID) (GasFeePayment, error)
 	GetGasFeePaymentByPaymentId(ctx context.Context, paymentID uuid.UUID) (GasFeePayment, error)
@@ -279,6 +382,9 @@ type Querier interface {
 	GetGasFeesByNetwork(ctx context.Context, arg GetGasFeesByNetworkParams) ([]GetGasFeesByNetworkRow, error)
 	GetGasLineItemsByInvoice(ctx context.Context, invoiceID uuid.UUID) ([]GetGasLineItemsByInvoiceRow, error)
 	GetGasSponsorshipConfig(ctx context.Context, workspaceID uuid.UUID) (GasSponsorshipConfig, error)
+	GetGasSponsorshipCustomerSpending(ctx context.Context, arg GetGasSponsorshipCustomerSpendingParams) (GasSponsorshipCustomerSpending, error)
+	GetGasSponsorshipReservation(ctx context.Context, arg GetGasSponsorshipReservationParams) (GasSponsorshipReservation, error)
+	GetGasSponsorshipRule(ctx context.Context, arg GetGasSponsorshipRuleParams) (GasSponsorshipRule, error)
 	GetGasSponsorshipStats(ctx context.Context, arg GetGasSponsorshipStatsParams) (GetGasSponsorshipStatsRow, error)
 	GetGasSponsorshipsByCustomer(ctx context.Context, arg GetGasSponsorshipsByCustomerParams) (GetGasSponsorshipsByCustomerRow, error)
 	GetGasSponsorshipsByProduct(ctx context.Context, arg GetGasSponsorshipsByProductParams) (GetGasSponsorshipsByProductRow, error)
@@ -304,6 +410,7 @@ type Querier interface {
 	GetInvoicesByPaymentLink(ctx context.Context, arg GetInvoicesByPaymentLinkParams) ([]Invoice, error)
 	GetLatestAttemptForCampaign(ctx context.Context, campaignID uuid.UUID) (DunningAttempt, error)
 	GetLatestDashboardMetrics(ctx context.Context, arg GetLatestDashboardMetricsParams) (DashboardMetric, error)
+	GetLatestMRRMovement(ctx context.Context, subscriptionID uuid.UUID) (MrrMovement, error)
 	GetLatestStateChange(ctx context.Context, subscriptionID uuid.UUID) (SubscriptionStateHistory, error)
 	GetLatestSubscriptionEvent(ctx context.Context, subscriptionID uuid.UUID) (SubscriptionEvent, error)
 	GetLatestSubscriptionEventByType(ctx context.Context, arg GetLatestSubscriptionEventByTypeParams) ([]SubscriptionEvent, error)
@@ -311,17 +418,23 @@ type Querier interface {
 	GetLatestSyncSessionByProvider(ctx context.Context, arg GetLatestSyncSessionByProviderParams) (PaymentSyncSession, error)
 	GetLineItemsByCurrency(ctx context.Context, arg GetLineItemsByCurrencyParams) ([]InvoiceLineItem, error)
 	GetLineItemsByProduct(ctx context.Context, arg GetLineItemsByProductParams) ([]InvoiceLineItem, error)
+	// Net MRR change per movement type; pending movements are counted but not summed
+	GetMRRMovementTotals(ctx context.Context, arg GetMRRMovementTotalsParams) ([]GetMRRMovementTotalsRow, error)
+	GetMRRMovementsByPeriod(ctx context.Context, arg GetMRRMovementsByPeriodParams) ([]GetMRRMovementsByPeriodRow, error)
 	GetMonthlyMetrics(ctx context.Context, arg GetMonthlyMetricsParams) ([]DashboardMetric, error)
 	GetNetwork(ctx context.Context, id uuid.UUID) (Network, error)
 	GetNetworkByChainID(ctx context.Context, chainID int32) (Network, error)
 	GetNetworkByCircleNetworkType(ctx context.Context, circleNetworkType CircleNetworkType) (Network, error)
 	GetNetworkMetrics(ctx context.Context, arg GetNetworkMetricsParams) (GetNetworkMetricsRow, error)
 	GetNextInvoiceNumber(ctx context.Context, workspaceID uuid.UUID) (int32, error)
+	GetOpenGasSponsorshipReservationByPayment(ctx context.Context, arg GetOpenGasSponsorshipReservationByPaymentParams) (GasSponsorshipReservation, error)
 	GetOverdueInvoices(ctx context.Context, arg GetOverdueInvoicesParams) ([]Invoice, error)
 	GetOverdueSubscriptions(ctx context.Context) ([]Subscription, error)
 	GetPayment(ctx context.Context, arg GetPaymentParams) (Payment, error)
 	GetPaymentBySubscriptionEvent(ctx context.Context, subscriptionEvent pgtype.UUID) (Payment, error)
-	GetPaymentByTransactionHash(ctx context.Context, transactionHash pgtype.Text) (Payment, error)
+	// A batched redemption's hash is shared by one payment per subscription event, so the event is matched too
+	GetPaymentByTransactionHash(ctx context.Context, arg GetPaymentByTransactionHashParams) (Payment, error)
+	GetPaymentConfirmation(ctx context.Context, paymentID uuid.UUID) (PaymentConfirmation, error)
 	GetPaymentLink(ctx context.Context, arg GetPaymentLinkParams) (PaymentLink, error)
 	GetPaymentLinkBySlug(ctx context.Context, slug string) (PaymentLink, error)
 	GetPaymentLinkStats(ctx context.Context, workspaceID uuid.UUID) (GetPaymentLinkStatsRow, error)
@@ -329,6 +442,7 @@ type Querier interface {
 	GetPaymentLinksByWorkspace(ctx context.Context, arg GetPaymentLinksByWorkspaceParams) ([]PaymentLink, error)
 	GetPaymentMetrics(ctx context.Context, arg GetPaymentMetricsParams) (GetPaymentMetricsRow, error)
 	GetPaymentMetricsSummary(ctx context.Context, arg GetPaymentMetricsSummaryParams) (GetPaymentMetricsSummaryRow, error)
+	// Crypto payments are only counted once their transaction is finalized, since until then they can be rolled back
 	GetPaymentVolume(ctx context.Context, arg GetPaymentVolumeParams) (GetPaymentVolumeRow, error)
 	GetPaymentWithGasDetails(ctx context.Context, arg GetPaymentWithGasDetailsParams) (GetPaymentWithGasDetailsRow, error)
 	GetPaymentsByCustomer(ctx context.Context, arg GetPaymentsByCustomerParams) ([]Payment, error)
@@ -341,6 +455,7 @@ type Querier interface {
 	GetPaymentsByTransactionHash(ctx context.Context, transactionHash pgtype.Text) ([]Payment, error)
 	GetPaymentsByWorkspace(ctx context.Context, arg GetPaymentsByWorkspaceParams) ([]Payment, error)
 	GetPendingInvoicesForGeneration(ctx context.Context, nextRedemptionDate pgtype.Timestamptz) ([]GetPendingInvoicesForGenerationRow, error)
+	GetPendingSubscriptionReauthorization(ctx context.Context, subscriptionID uuid.UUID) (SubscriptionReauthorization, error)
 	GetPrimaryCustomerWallet(ctx context.Context, customerID uuid.UUID) (CustomerWallet, error)
 	GetProduct(ctx context.Context, arg GetProductParams) (Product, error)
 	GetProductAddonRelationship(ctx context.Context, id uuid.UUID) (ProductAddonRelationship, error)
@@ -367,14 +482,22 @@ type Querier interface {
 	// Get a specific provider account for a workspace
 	GetProviderAccountByWorkspace(ctx context.Context, arg GetProviderAccountByWorkspaceParams) (WorkspaceProviderAccount, error)
 	GetProviderSyncStatusByWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]GetProviderSyncStatusByWorkspaceRow, error)
+	// Returns the most specific quota for a workspace: key-level rows win over workspace-level
+	// rows, and class-specific rows win over rows that apply to every route class
+	GetRateLimitQuota(ctx context.Context, arg GetRateLimitQuotaParams) (RateLimitQuota, error)
 	GetRecentInvoiceActivities(ctx context.Context, arg GetRecentInvoiceActivitiesParams) ([]InvoiceActivity, error)
 	GetRecentInvoices(ctx context.Context, arg GetRecentInvoicesParams) ([]Invoice, error)
 	// Get recent webhook processing errors for monitoring
 	GetRecentWebhookErrors(ctx context.Context, arg GetRecentWebhookErrorsParams) ([]GetRecentWebhookErrorsRow, error)
 	GetRecentlyUsedWallets(ctx context.Context, arg GetRecentlyUsedWalletsParams) ([]Wallet, error)
 	GetRecentlyUsedWalletsWithCircleData(ctx context.Context, arg GetRecentlyUsedWalletsWithCircleDataParams) ([]GetRecentlyUsedWalletsWithCircleDataRow, error)
+	GetRedemptionTask(ctx context.Context, id uuid.UUID) (RedemptionTask, error)
+	// Number of tasks per status; ready_count only counts pending tasks that can be claimed now
+	GetRedemptionTaskStats(ctx context.Context, now pgtype.Timestamptz) ([]GetRedemptionTaskStatsRow, error)
 	GetRevenueGrowth(ctx context.Context, arg GetRevenueGrowthParams) (GetRevenueGrowthRow, error)
 	GetScheduleChange(ctx context.Context, id uuid.UUID) (SubscriptionScheduleChange, error)
+	GetSplApprovalByDelegationID(ctx context.Context, delegationID uuid.UUID) (SplApproval, error)
+	GetSplApprovalBySignature(ctx context.Context, signature string) (SplApproval, error)
 	GetSponsorshipConfigsNeedingReset(ctx context.Context, dollar_1 pgtype.Date) ([]GasSponsorshipConfig, error)
 	GetStateChangesByDateRange(ctx context.Context, arg GetStateChangesByDateRangeParams) ([]SubscriptionStateHistory, error)
 	GetStateChangesByScheduleChange(ctx context.Context, scheduleChangeID pgtype.UUID) ([]SubscriptionStateHistory, error)
@@ -391,6 +514,10 @@ type Querier interface {
 	// Get payment history for campaign strategy determination
 	GetSubscriptionPaymentHistory(ctx context.Context, subscriptionID uuid.UUID) ([]GetSubscriptionPaymentHistoryRow, error)
 	GetSubscriptionProrations(ctx context.Context, subscriptionID uuid.UUID) ([]SubscriptionProration, error)
+	// A batched redemption shares its transaction hash with other subscriptions, so the subscription is matched too
+	GetSubscriptionRedemptionEventByTransactionHash(ctx context.Context, arg GetSubscriptionRedemptionEventByTransactionHashParams) (SubscriptionEvent, error)
+	GetSubscriptionRenewal(ctx context.Context, id uuid.UUID) (SubscriptionRenewal, error)
+	GetSubscriptionRenewalByIdempotencyKey(ctx context.Context, idempotencyKey string) (SubscriptionRenewal, error)
 	GetSubscriptionScheduledChanges(ctx context.Context, subscriptionID uuid.UUID) ([]SubscriptionScheduleChange, error)
 	GetSubscriptionStateHistory(ctx context.Context, arg GetSubscriptionStateHistoryParams) ([]SubscriptionStateHistory, error)
 	GetSubscriptionWithCustomerDetails(ctx context.Context, id uuid.UUID) (GetSubscriptionWithCustomerDetailsRow, error)
@@ -416,10 +543,15 @@ type Querier interface {
 	GetSyncProgressByEntityType(ctx context.Context, sessionID uuid.UUID) ([]GetSyncProgressByEntityTypeRow, error)
 	GetSyncSession(ctx context.Context, arg GetSyncSessionParams) (PaymentSyncSession, error)
 	GetSyncSessionByProvider(ctx context.Context, arg GetSyncSessionByProviderParams) (PaymentSyncSession, error)
+	GetTaxIDVerification(ctx context.Context, id uuid.UUID) (TaxIDVerification, error)
+	GetTaxJurisdiction(ctx context.Context, id uuid.UUID) (TaxJurisdiction, error)
+	GetTaxJurisdictionByCode(ctx context.Context, code string) (TaxJurisdiction, error)
+	GetTaxRate(ctx context.Context, id uuid.UUID) (TaxRate, error)
 	GetToken(ctx context.Context, id uuid.UUID) (Token, error)
 	GetTokenByAddress(ctx context.Context, arg GetTokenByAddressParams) (Token, error)
 	GetTopPaymentLinks(ctx context.Context, arg GetTopPaymentLinksParams) ([]GetTopPaymentLinksRow, error)
 	GetTotalAmountBySubscription(ctx context.Context, subscriptionID uuid.UUID) (interface{}, error)
+	GetTreasurySweepRule(ctx context.Context, arg GetTreasurySweepRuleParams) (TreasurySweepRule, error)
 	GetUnappliedProrations(ctx context.Context, subscriptionID uuid.UUID) ([]SubscriptionProration, error)
 	GetUnpaidInvoices(ctx context.Context, arg GetUnpaidInvoicesParams) ([]Invoice, error)
 	GetUnreconciledPayments(ctx context.Context, arg GetUnreconciledPaymentsParams) ([]Payment, error)
@@ -473,14 +605,24 @@ type Querier interface {
 	GetWorkspaceSyncSummary(ctx context.Context, id uuid.UUID) (GetWorkspaceSyncSummaryRow, error)
 	GetWorkspacesByProvider(ctx context.Context, metadata []byte) ([]Workspace, error)
 	GetWorkspacesNeedingReset(ctx context.Context) ([]uuid.UUID, error)
+	// Lets exactly one caller probe the dependency once the breaker has been open for the reset timeout.
+	// A probe that never reports back is given up after the same timeout
+	HalfOpenCircuitBreaker(ctx context.Context, arg HalfOpenCircuitBreakerParams) (CircuitBreaker, error)
 	HardDeleteAccount(ctx context.Context, id uuid.UUID) error
 	HardDeleteWorkspace(ctx context.Context, id uuid.UUID) error
 	HasPaymentsAfterDate(ctx context.Context, arg HasPaymentsAfterDateParams) (bool, error)
 	IncrementPaymentLinkUsage(ctx context.Context, arg IncrementPaymentLinkUsageParams) (PaymentLink, error)
+	// Atomically counts a request against a fixed window and returns the new total
+	IncrementRateLimitCounter(ctx context.Context, arg IncrementRateLimitCounterParams) (int32, error)
 	IncrementSubscriptionRedemption(ctx context.Context, arg IncrementSubscriptionRedemptionParams) (Subscription, error)
+	// Records a provider event ID; zero affected rows means the event was already received
+	InsertWebhookReplayEntry(ctx context.Context, arg InsertWebhookReplayEntryParams) (int64, error)
+	// Parks a renewal whose redemption outcome is unknown, or that ran out of attempts, so it is not redeemed again without review
+	InterruptSubscriptionRenewal(ctx context.Context, arg InterruptSubscriptionRenewalParams) (SubscriptionRenewal, error)
 	IsCustomerInWorkspace(ctx context.Context, arg IsCustomerInWorkspaceParams) (bool, error)
 	LinkInvoiceToPaymentLink(ctx context.Context, arg LinkInvoiceToPaymentLinkParams) (Invoice, error)
 	LinkPaymentToInvoice(ctx context.Context, arg LinkPaymentToInvoiceParams) (Payment, error)
+	ListAPIKeyUsage(ctx context.Context, arg ListAPIKeyUsageParams) ([]ApiKeyUsageDaily, error)
 	ListAPIKeys(ctx context.Context, workspaceID uuid.UUID) ([]ApiKey, error)
 	ListAccounts(ctx context.Context) ([]Account, error)
 	ListAccountsByType(ctx context.Context, accountType AccountType) ([]Account, error)
@@ -488,6 +630,7 @@ type Querier interface {
 	ListActiveCircleNetworks(ctx context.Context) ([]Network, error)
 	ListActiveDunningEmailTemplates(ctx context.Context, workspaceID uuid.UUID) ([]DunningEmailTemplate, error)
 	ListActiveFiatCurrencies(ctx context.Context) ([]FiatCurrency, error)
+	ListActiveGasSponsorshipRules(ctx context.Context, workspaceID uuid.UUID) ([]GasSponsorshipRule, error)
 	ListActiveNetworks(ctx context.Context) ([]Network, error)
 	ListActiveProducts(ctx context.Context, workspaceID uuid.UUID) ([]Product, error)
 	// Get list of active providers for a workspace
@@ -496,15 +639,31 @@ type Querier interface {
 	ListActiveSubscriptions(ctx context.Context) ([]Subscription, error)
 	ListActiveTokensByNetwork(ctx context.Context, networkID uuid.UUID) ([]Token, error)
 	ListActiveWorkspacePaymentConfigurations(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePaymentConfiguration, error)
+	ListActiveWorkspaceWebhookSecrets(ctx context.Context, arg ListActiveWorkspaceWebhookSecretsParams) ([]WorkspaceWebhookSecret, error)
 	ListAllFiatCurrencies(ctx context.Context) ([]FiatCurrency, error)
+	ListAnalyticsExportJobs(ctx context.Context, arg ListAnalyticsExportJobsParams) ([]AnalyticsExportJob, error)
+	ListAnalyticsReportSchedules(ctx context.Context, workspaceID uuid.UUID) ([]AnalyticsReportSchedule, error)
 	ListBaseProductsForAddon(ctx context.Context, addonProductID uuid.UUID) ([]ListBaseProductsForAddonRow, error)
 	ListCircleUsers(ctx context.Context) ([]CircleUser, error)
 	ListCircleWalletsByCircleUserID(ctx context.Context, circleUserID uuid.UUID) ([]ListCircleWalletsByCircleUserIDRow, error)
 	ListCircleWalletsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]ListCircleWalletsByWorkspaceIDRow, error)
+	ListCohortMetrics(ctx context.Context, arg ListCohortMetricsParams) ([]CohortMetric, error)
+	// Finalized invoices of a customer across all merchants; drafts stay private to the merchant
+	ListCustomerPortalInvoices(ctx context.Context, arg ListCustomerPortalInvoicesParams) ([]Invoice, error)
+	ListCustomerPortalPayments(ctx context.Context, arg ListCustomerPortalPaymentsParams) ([]Payment, error)
+	// Subscriptions of a customer across all merchants, optionally limited to one workspace
+	ListCustomerPortalSubscriptions(ctx context.Context, arg ListCustomerPortalSubscriptionsParams) ([]ListCustomerPortalSubscriptionsRow, error)
 	ListCustomerWallets(ctx context.Context, customerID uuid.UUID) ([]CustomerWallet, error)
+	// EVM wallets whose ENS or Basenames name has never been looked up or was last looked up before the cutoff
+	ListCustomerWalletsDueNameRefresh(ctx context.Context, arg ListCustomerWalletsDueNameRefreshParams) ([]CustomerWallet, error)
 	ListCustomerWorkspaces(ctx context.Context, customerID uuid.UUID) ([]Workspace, error)
 	ListCustomers(ctx context.Context) ([]Customer, error)
+	// Customers in the given billing countries with a tax ID that was never verified or was
+	// last verified before the cutoff
+	ListCustomersDueForTaxIDVerification(ctx context.Context, arg ListCustomersDueForTaxIDVerificationParams) ([]Customer, error)
 	ListCustomersWithPagination(ctx context.Context, arg ListCustomersWithPaginationParams) ([]Customer, error)
+	// Active delegations behind live subscriptions that have not been checked since the cutoff, least recently checked first
+	ListDelegationsDueForCheck(ctx context.Context, arg ListDelegationsDueForCheckParams) ([]ListDelegationsDueForCheckRow, error)
 	ListDelegationsWithPagination(ctx context.Context, arg ListDelegationsWithPaginationParams) ([]DelegationDatum, error)
 	ListDunningAnalyticsByPeriod(ctx context.Context, arg ListDunningAnalyticsByPeriodParams) ([]DunningAnalytic, error)
 	ListDunningAttempts(ctx context.Context, campaignID uuid.UUID) ([]DunningAttempt, error)
@@ -512,6 +671,9 @@ type Querier interface {
 	ListDunningCampaignsForRetry(ctx context.Context, limit int32) ([]DunningCampaign, error)
 	ListDunningConfigurations(ctx context.Context, workspaceID uuid.UUID) ([]DunningConfiguration, error)
 	ListDunningEmailTemplates(ctx context.Context, workspaceID uuid.UUID) ([]DunningEmailTemplate, error)
+	// Rates of the given jurisdictions in force at a point in time
+	ListEffectiveTaxRates(ctx context.Context, arg ListEffectiveTaxRatesParams) ([]TaxRate, error)
+	ListExpiredGasSponsorshipReservations(ctx context.Context, arg ListExpiredGasSponsorshipReservationsParams) ([]GasSponsorshipReservation, error)
 	ListFailedSubscriptionAttempts(ctx context.Context) ([]FailedSubscriptionAttempt, error)
 	ListFailedSubscriptionAttemptsByCustomer(ctx context.Context, customerID pgtype.UUID) ([]FailedSubscriptionAttempt, error)
 	ListFailedSubscriptionAttemptsByErrorType(ctx context.Context, errorType SubscriptionEventType) ([]FailedSubscriptionAttempt, error)
@@ -521,13 +683,31 @@ type Querier interface {
 	ListFailedSubscriptionEvents(ctx context.Context) ([]SubscriptionEvent, error)
 	// NEW: List webhook events that failed processing
 	ListFailedWebhookEvents(ctx context.Context, arg ListFailedWebhookEventsParams) ([]PaymentSyncEvent, error)
+	ListGasFeesForExport(ctx context.Context, arg ListGasFeesForExportParams) ([]ListGasFeesForExportRow, error)
+	ListGasSponsorshipBudgetAlerts(ctx context.Context, arg ListGasSponsorshipBudgetAlertsParams) ([]GasSponsorshipBudgetAlert, error)
+	ListGasSponsorshipLedgerEntries(ctx context.Context, arg ListGasSponsorshipLedgerEntriesParams) ([]GasSponsorshipLedger, error)
+	ListGasSponsorshipRules(ctx context.Context, workspaceID uuid.UUID) ([]GasSponsorshipRule, error)
 	ListInvoicesByCustomer(ctx context.Context, arg ListInvoicesByCustomerParams) ([]Invoice, error)
 	ListInvoicesByProvider(ctx context.Context, arg ListInvoicesByProviderParams) ([]Invoice, error)
 	ListInvoicesByStatus(ctx context.Context, arg ListInvoicesByStatusParams) ([]Invoice, error)
 	ListInvoicesBySubscription(ctx context.Context, arg ListInvoicesBySubscriptionParams) ([]Invoice, error)
 	ListInvoicesBySyncStatus(ctx context.Context, arg ListInvoicesBySyncStatusParams) ([]Invoice, error)
 	ListInvoicesByWorkspace(ctx context.Context, arg ListInvoicesByWorkspaceParams) ([]Invoice, error)
+	ListInvoicesForExport(ctx context.Context, arg ListInvoicesForExportParams) ([]ListInvoicesForExportRow, error)
+	ListLocalTaxJurisdictions(ctx context.Context, arg ListLocalTaxJurisdictionsParams) ([]TaxJurisdiction, error)
+	ListMRRMovements(ctx context.Context, arg ListMRRMovementsParams) ([]ListMRRMovementsRow, error)
+	ListMRRMovementsForExport(ctx context.Context, arg ListMRRMovementsForExportParams) ([]ListMRRMovementsForExportRow, error)
 	ListNetworks(ctx context.Context, arg ListNetworksParams) ([]Network, error)
+	// Sweeps awaiting approval or confirmation, with the Circle wallet and user they were sent from
+	ListOpenTreasuryMovements(ctx context.Context, batchSize int32) ([]ListOpenTreasuryMovementsRow, error)
+	// Lists the transactions that have not reached finality and were not checked since checked_before,
+	// with the thresholds of their network and what is needed to roll their payment back
+	ListPaymentConfirmationsToCheck(ctx context.Context, arg ListPaymentConfirmationsToCheckParams) ([]ListPaymentConfirmationsToCheckRow, error)
+	ListPaymentsForExport(ctx context.Context, arg ListPaymentsForExportParams) ([]ListPaymentsForExportRow, error)
+	// Movements still waiting for an exchange rate to the reporting currency
+	ListPendingMRRMovements(ctx context.Context, limit int32) ([]MrrMovement, error)
+	ListPendingSubscriptionReauthorizationsByCustomer(ctx context.Context, customerID uuid.UUID) ([]SubscriptionReauthorization, error)
+	ListPortfolioSnapshots(ctx context.Context, arg ListPortfolioSnapshotsParams) ([]PortfolioSnapshot, error)
 	ListPrimaryCustomerWallets(ctx context.Context) ([]CustomerWallet, error)
 	ListPrimaryWalletsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]Wallet, error)
 	ListPrimaryWalletsWithCircleDataByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]ListPrimaryWalletsWithCircleDataByWorkspaceIDRow, error)
@@ -543,6 +723,7 @@ type Querier interface {
 	ListRecentFailedSubscriptionAttempts(ctx context.Context, occurredAt pgtype.Timestamptz) ([]FailedSubscriptionAttempt, error)
 	ListRecentSubscriptionEvents(ctx context.Context, occurredAt pgtype.Timestamptz) ([]SubscriptionEvent, error)
 	ListRecentSubscriptionEventsByType(ctx context.Context, arg ListRecentSubscriptionEventsByTypeParams) ([]SubscriptionEvent, error)
+	ListRedemptionTasks(ctx context.Context, arg ListRedemptionTasksParams) ([]RedemptionTask, error)
 	ListRequiredProductAddons(ctx context.Context, baseProductID uuid.UUID) ([]ListRequiredProductAddonsRow, error)
 	ListSubscriptionAddonLineItems(ctx context.Context, subscriptionID uuid.UUID) ([]ListSubscriptionAddonLineItemsRow, error)
 	ListSubscriptionDetailsWithPagination(ctx context.Context, arg ListSubscriptionDetailsWithPaginationParams) ([]ListSubscriptionDetailsWithPaginationRow, error)
@@ -552,10 +733,17 @@ type Querier interface {
 	ListSubscriptionEventsByType(ctx context.Context, eventType SubscriptionEventType) ([]SubscriptionEvent, error)
 	ListSubscriptionEventsWithPagination(ctx context.Context, arg ListSubscriptionEventsWithPaginationParams) ([]SubscriptionEvent, error)
 	ListSubscriptionLineItems(ctx context.Context, subscriptionID uuid.UUID) ([]ListSubscriptionLineItemsRow, error)
+	// Pending reauthorizations of live subscriptions whose customer has not been emailed since the cutoff and has
+	// not yet received every reminder, oldest first
+	ListSubscriptionReauthorizationsToNotify(ctx context.Context, arg ListSubscriptionReauthorizationsToNotifyParams) ([]ListSubscriptionReauthorizationsToNotifyRow, error)
+	ListSubscriptionRenewals(ctx context.Context, arg ListSubscriptionRenewalsParams) ([]SubscriptionRenewal, error)
+	ListSubscriptionRenewalsBySubscription(ctx context.Context, arg ListSubscriptionRenewalsBySubscriptionParams) ([]SubscriptionRenewal, error)
 	ListSubscriptions(ctx context.Context) ([]Subscription, error)
 	ListSubscriptionsByCustomer(ctx context.Context, arg ListSubscriptionsByCustomerParams) ([]Subscription, error)
 	ListSubscriptionsByProduct(ctx context.Context, arg ListSubscriptionsByProductParams) ([]Subscription, error)
 	ListSubscriptionsDueForRedemption(ctx context.Context, nextRedemptionDate pgtype.Timestamptz) ([]ListSubscriptionsDueForRedemptionRow, error)
+	// Subscriptions that existed at some point in the range
+	ListSubscriptionsForExport(ctx context.Context, arg ListSubscriptionsForExportParams) ([]ListSubscriptionsForExportRow, error)
 	ListSubscriptionsWithPagination(ctx context.Context, arg ListSubscriptionsWithPaginationParams) ([]Subscription, error)
 	ListSyncEventsByEntityType(ctx context.Context, arg ListSyncEventsByEntityTypeParams) ([]PaymentSyncEvent, error)
 	ListSyncEventsByEventType(ctx context.Context, arg ListSyncEventsByEventTypeParams) ([]PaymentSyncEvent, error)
@@ -564,10 +752,24 @@ type Querier interface {
 	ListSyncSessions(ctx context.Context, arg ListSyncSessionsParams) ([]PaymentSyncSession, error)
 	ListSyncSessionsByProvider(ctx context.Context, arg ListSyncSessionsByProviderParams) ([]PaymentSyncSession, error)
 	ListSyncSessionsByStatus(ctx context.Context, arg ListSyncSessionsByStatusParams) ([]PaymentSyncSession, error)
+	ListTaxIDVerificationsByCustomer(ctx context.Context, arg ListTaxIDVerificationsByCustomerParams) ([]TaxIDVerification, error)
+	ListTaxJurisdictions(ctx context.Context, arg ListTaxJurisdictionsParams) ([]TaxJurisdiction, error)
+	ListTaxRatesByJurisdiction(ctx context.Context, jurisdictionID uuid.UUID) ([]TaxRate, error)
+	// Invoices with a tax event in the period: issued, paid or voided. Drafts carry no tax liability.
+	ListTaxReportInvoices(ctx context.Context, arg ListTaxReportInvoicesParams) ([]ListTaxReportInvoicesRow, error)
+	// Invoice payments refunded in the period, with the tax details of the invoice they paid
+	ListTaxReportRefunds(ctx context.Context, arg ListTaxReportRefundsParams) ([]ListTaxReportRefundsRow, error)
 	ListTokens(ctx context.Context) ([]Token, error)
 	ListTokensByNetwork(ctx context.Context, networkID uuid.UUID) ([]Token, error)
+	ListTreasuryMovements(ctx context.Context, arg ListTreasuryMovementsParams) ([]TreasuryMovement, error)
+	ListTreasurySweepRules(ctx context.Context, workspaceID uuid.UUID) ([]TreasurySweepRule, error)
+	// Returns active keys idle since the cutoff that have not been notified since they were last used,
+	// along with the workspace owner to notify
+	ListUnusedAPIKeys(ctx context.Context, cutoff pgtype.Timestamptz) ([]ListUnusedAPIKeysRow, error)
 	ListUsers(ctx context.Context) ([]User, error)
 	ListUsersByAccount(ctx context.Context, accountID uuid.UUID) ([]User, error)
+	// Cached balances of a workspace's wallets, or of one of them, in active tokens on active networks
+	ListWalletBalancesByWorkspace(ctx context.Context, arg ListWalletBalancesByWorkspaceParams) ([]ListWalletBalancesByWorkspaceRow, error)
 	ListWalletsByAddress(ctx context.Context, arg ListWalletsByAddressParams) ([]ListWalletsByAddressRow, error)
 	ListWalletsByNetworkType(ctx context.Context, arg ListWalletsByNetworkTypeParams) ([]Wallet, error)
 	ListWalletsByWalletType(ctx context.Context, arg ListWalletsByWalletTypeParams) ([]Wallet, error)
@@ -582,69 +784,153 @@ type Querier interface {
 	ListWorkspaceCustomersWithRevenue(ctx context.Context, arg ListWorkspaceCustomersWithRevenueParams) ([]ListWorkspaceCustomersWithRevenueRow, error)
 	ListWorkspacePaymentConfigurations(ctx context.Context, arg ListWorkspacePaymentConfigurationsParams) ([]WorkspacePaymentConfiguration, error)
 	ListWorkspacePaymentConfigurationsByProvider(ctx context.Context, providerName string) ([]WorkspacePaymentConfiguration, error)
+	// Configurations whose credentials are not wrapped with the current key, paged by ID
+	ListWorkspacePaymentConfigurationsForReencryption(ctx context.Context, arg ListWorkspacePaymentConfigurationsForReencryptionParams) ([]WorkspacePaymentConfiguration, error)
 	ListWorkspaceSupportedCurrencies(ctx context.Context, id uuid.UUID) ([]FiatCurrency, error)
+	// Unexpired retired secrets not wrapped with the current key, paged by ID
+	ListWorkspaceWebhookSecretsForReencryption(ctx context.Context, arg ListWorkspaceWebhookSecretsForReencryptionParams) ([]WorkspaceWebhookSecret, error)
 	ListWorkspaces(ctx context.Context) ([]Workspace, error)
 	ListWorkspacesByAccountID(ctx context.Context, accountID uuid.UUID) ([]Workspace, error)
+	// Workspaces with wallets that have no snapshot for the current period yet
+	ListWorkspacesDuePortfolioSnapshot(ctx context.Context, arg ListWorkspacesDuePortfolioSnapshotParams) ([]uuid.UUID, error)
 	LockSubscriptionForProcessing(ctx context.Context, id uuid.UUID) (Subscription, error)
+	// Lock a jurisdiction so changes to its rate table are made one at a time
+	LockTaxJurisdiction(ctx context.Context, id uuid.UUID) (TaxJurisdiction, error)
 	// Log DLQ processing attempt
 	LogDLQProcessingAttempt(ctx context.Context, arg LogDLQProcessingAttemptParams) (PaymentSyncEvent, error)
 	// Log incoming webhook before processing
 	LogWebhookReceived(ctx context.Context, arg LogWebhookReceivedParams) (PaymentSyncEvent, error)
+	// Log a webhook rejected before processing (bad signature, stale timestamp or replay).
+	// Replays carry a valid signature, so signature_valid is set by the caller.
+	LogWebhookRejected(ctx context.Context, arg LogWebhookRejectedParams) (PaymentSyncEvent, error)
+	MarkAPIKeyUnusedNotified(ctx context.Context, id uuid.UUID) error
 	// Set a specific customer wallet as primary
 	MarkCustomerWalletAsPrimary(ctx context.Context, id uuid.UUID) (CustomerWallet, error)
 	MarkInvoicePaid(ctx context.Context, arg MarkInvoicePaidParams) (Invoice, error)
+	// Records that the delegation is about to be redeemed, so a task whose worker stops mid-redemption is not redeemed again
+	MarkRedemptionTaskRedeeming(ctx context.Context, arg MarkRedemptionTaskRedeemingParams) (RedemptionTask, error)
+	MarkSubscriptionReauthorizationNotified(ctx context.Context, id uuid.UUID) error
+	// Records the redemption transaction so an interrupted renewal resumes without redeeming again
+	MarkSubscriptionRenewalRedeemed(ctx context.Context, arg MarkSubscriptionRenewalRedeemedParams) (SubscriptionRenewal, error)
+	// Records that the redemption is about to be sent; fails if the lease was lost to another worker
+	MarkSubscriptionRenewalRedeeming(ctx context.Context, arg MarkSubscriptionRenewalRedeemingParams) (SubscriptionRenewal, error)
 	// Mark a webhook event for retry processing
 	MarkWebhookForRetry(ctx context.Context, id uuid.UUID) (PaymentSyncEvent, error)
+	// Opens a reauthorization for a subscription, or refreshes the reason of the one already pending
+	OpenSubscriptionReauthorization(ctx context.Context, arg OpenSubscriptionReauthorizationParams) (SubscriptionReauthorization, error)
 	PauseDunningCampaign(ctx context.Context, id uuid.UUID) (DunningCampaign, error)
 	PauseSubscription(ctx context.Context, arg PauseSubscriptionParams) (Subscription, error)
 	ReactivateScheduledCancellation(ctx context.Context, id uuid.UUID) (Subscription, error)
+	ReconcileTreasuryMovement(ctx context.Context, arg ReconcileTreasuryMovementParams) (TreasuryMovement, error)
+	// Adds a batch of requests to the key's daily counter and keeps the most recent client details
+	RecordAPIKeyUsage(ctx context.Context, arg RecordAPIKeyUsageParams) error
+	// Counts a failure and opens the breaker at the threshold, or straight away when a probe failed
+	RecordCircuitBreakerFailure(ctx context.Context, arg RecordCircuitBreakerFailureParams) (CircuitBreaker, error)
+	// Closes the breaker and resets its failure count; rows are only written when something changes
+	RecordCircuitBreakerSuccess(ctx context.Context, name string) (int64, error)
+	// Returns no row when the threshold was already alerted on for the month
+	RecordGasSponsorshipBudgetAlert(ctx context.Context, arg RecordGasSponsorshipBudgetAlertParams) (GasSponsorshipBudgetAlert, error)
 	RecordInvoiceCreation(ctx context.Context, arg RecordInvoiceCreationParams) (InvoiceActivity, error)
 	RecordInvoiceReminder(ctx context.Context, arg RecordInvoiceReminderParams) (InvoiceActivity, error)
 	RecordInvoiceStatusChange(ctx context.Context, arg RecordInvoiceStatusChangeParams) (InvoiceActivity, error)
 	RecordStateChange(ctx context.Context, arg RecordStateChangeParams) (SubscriptionStateHistory, error)
+	RecordTreasurySweepRuleRun(ctx context.Context, arg RecordTreasurySweepRuleRunParams) error
+	// Keeps the last balance read, flagging that the latest refresh could not read it. A balance that was never
+	// read is cached without an amount so the failure still shows up
+	RecordWalletBalanceError(ctx context.Context, arg RecordWalletBalanceErrorParams) error
 	RecoverDunningCampaign(ctx context.Context, arg RecoverDunningCampaignParams) (DunningCampaign, error)
+	// Only applies if the row has not been updated since it was read
+	ReencryptWorkspacePaymentConfiguration(ctx context.Context, arg ReencryptWorkspacePaymentConfigurationParams) (int64, error)
+	// Only applies if the secret has not been replaced since it was read
+	ReencryptWorkspaceWebhookSecret(ctx context.Context, arg ReencryptWorkspaceWebhookSecretParams) (int64, error)
 	RefundPayment(ctx context.Context, arg RefundPaymentParams) (Payment, error)
+	// Returns a hold to the workspace budget and the customer's month
+	ReleaseGasSponsorshipReservation(ctx context.Context, arg ReleaseGasSponsorshipReservationParams) (GasSponsorshipReservation, error)
+	ReleaseSubscriptionRenewalLease(ctx context.Context, arg ReleaseSubscriptionRenewalLeaseParams) error
 	RemoveCustomerFromWorkspace(ctx context.Context, arg RemoveCustomerFromWorkspaceParams) error
 	RemoveWorkspaceSupportedCurrency(ctx context.Context, arg RemoveWorkspaceSupportedCurrencyParams) error
+	// Undoes MarkInvoicePaid when the payment behind it is rolled back
+	ReopenPaidInvoice(ctx context.Context, arg ReopenPaidInvoiceParams) (Invoice, error)
+	// Fails the succeeded renewal that was paid by a transaction which did not make it on-chain, so the next
+	// claim renews the period again
+	ReopenSubscriptionRenewalByTransaction(ctx context.Context, arg ReopenSubscriptionRenewalByTransactionParams) (SubscriptionRenewal, error)
 	// Create a new event record for webhook replay
 	ReplayWebhookEvent(ctx context.Context, arg ReplayWebhookEventParams) (PaymentSyncEvent, error)
+	// Makes a pending or dead-lettered task claimable straight away with a fresh set of attempts
+	RequeueRedemptionTask(ctx context.Context, arg RequeueRedemptionTaskParams) (RedemptionTask, error)
+	// Returns an interrupted renewal whose redemption never went through to the renewal engine with a fresh set of
+	// attempts, so the next claim renews the period again
+	RequeueSubscriptionRenewal(ctx context.Context, arg RequeueSubscriptionRenewalParams) (SubscriptionRenewal, error)
+	// Holds budget for a sponsored transaction. The hold and its ledger entry are only written when
+	// the amount still fits the workspace's monthly budget alongside spending and other holds.
+	ReserveGasSponsorshipBudget(ctx context.Context, arg ReserveGasSponsorshipBudgetParams) (GasSponsorshipReservation, error)
+	// Counts a reservation against its customer's month, only when it fits the cap (if any)
+	ReserveGasSponsorshipCustomerSpending(ctx context.Context, arg ReserveGasSponsorshipCustomerSpendingParams) (GasSponsorshipReservation, error)
 	ResetGasSponsorshipMonthlySpending(ctx context.Context, arg ResetGasSponsorshipMonthlySpendingParams) error
+	// Records the transaction an interrupted renewal's redemption went through with, so the next run records the
+	// payment instead of redeeming again
+	ResolveSubscriptionRenewal(ctx context.Context, arg ResolveSubscriptionRenewalParams) (SubscriptionRenewal, error)
 	ResumeDunningCampaign(ctx context.Context, arg ResumeDunningCampaignParams) (DunningCampaign, error)
 	ResumeSubscription(ctx context.Context, arg ResumeSubscriptionParams) (Subscription, error)
 	// Resume a failed sync session by updating its status
 	ResumeSyncSession(ctx context.Context, arg ResumeSyncSessionParams) (PaymentSyncSession, error)
+	RetryRedemptionTask(ctx context.Context, arg RetryRedemptionTaskParams) (RedemptionTask, error)
+	// Undoes IncrementSubscriptionRedemption for a redemption whose transaction never made it on-chain. The period
+	// becomes due again from due_at and the subscription is overdue until it is redeemed
+	RevertSubscriptionRedemption(ctx context.Context, arg RevertSubscriptionRedemptionParams) (Subscription, error)
+	RevokeCustomerPortalSession(ctx context.Context, arg RevokeCustomerPortalSessionParams) (int64, error)
+	// Issues a replacement key and caps the old key's validity at the grace expiry in a single statement.
+	// Keys that are deleted, expired or already rotated are not rotated again.
+	RotateAPIKey(ctx context.Context, arg RotateAPIKeyParams) (ApiKey, error)
 	ScheduleSubscriptionCancellation(ctx context.Context, arg ScheduleSubscriptionCancellationParams) (Subscription, error)
 	SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error)
 	SearchWallets(ctx context.Context, arg SearchWalletsParams) ([]Wallet, error)
 	SearchWalletsWithCircleData(ctx context.Context, arg SearchWalletsWithCircleDataParams) ([]SearchWalletsWithCircleDataRow, error)
+	SetCustomerTaxIDVerified(ctx context.Context, arg SetCustomerTaxIDVerifiedParams) error
 	SetDefaultDunningConfiguration(ctx context.Context, arg SetDefaultDunningConfigurationParams) error
+	SetMRRMovementExchangeRate(ctx context.Context, arg SetMRRMovementExchangeRateParams) error
+	SetTreasuryMovementChallenge(ctx context.Context, arg SetTreasuryMovementChallengeParams) error
 	SetWalletAsPrimary(ctx context.Context, arg SetWalletAsPrimaryParams) (int64, error)
+	// Replaces a hold with the sponsored share of the actual gas cost, which never exceeds the hold
+	SettleGasSponsorshipReservation(ctx context.Context, arg SettleGasSponsorshipReservationParams) (GasSponsorshipReservation, error)
 	SoftDeleteWallet(ctx context.Context, id uuid.UUID) error
+	// Ends the open-ended rate that started before a new rate takes effect
+	SupersedeOpenTaxRate(ctx context.Context, arg SupersedeOpenTaxRateParams) (int64, error)
+	TouchCustomerPortalSession(ctx context.Context, id uuid.UUID) error
+	// Starts following the transactions of crypto payments recorded since created_after
+	TrackNewPaymentConfirmations(ctx context.Context, createdAfter pgtype.Timestamptz) (int64, error)
 	// Unset primary flag for all wallets of a customer except the specified wallet
 	UnsetPrimaryForCustomerWallets(ctx context.Context, arg UnsetPrimaryForCustomerWalletsParams) error
 	UpdateAPIKey(ctx context.Context, arg UpdateAPIKeyParams) (ApiKey, error)
 	// Updates the last_used_at timestamp after successful authentication
 	UpdateAPIKeyLastUsed(ctx context.Context, id uuid.UUID) error
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAnalyticsReportSchedule(ctx context.Context, arg UpdateAnalyticsReportScheduleParams) (AnalyticsReportSchedule, error)
 	UpdateCircleUser(ctx context.Context, arg UpdateCircleUserParams) (CircleUser, error)
 	UpdateCircleUserByWorkspaceID(ctx context.Context, arg UpdateCircleUserByWorkspaceIDParams) (CircleUser, error)
 	UpdateCircleWalletState(ctx context.Context, arg UpdateCircleWalletStateParams) (CircleWallet, error)
 	UpdateCustomer(ctx context.Context, arg UpdateCustomerParams) (Customer, error)
 	UpdateCustomerBillingAddress(ctx context.Context, arg UpdateCustomerBillingAddressParams) (Customer, error)
+	// Updates only the billing fields that are provided
+	UpdateCustomerBillingDetails(ctx context.Context, arg UpdateCustomerBillingDetailsParams) (Customer, error)
 	UpdateCustomerOnboardingStatus(ctx context.Context, arg UpdateCustomerOnboardingStatusParams) (Customer, error)
 	UpdateCustomerPaymentSyncStatus(ctx context.Context, arg UpdateCustomerPaymentSyncStatusParams) (Customer, error)
 	UpdateCustomerSyncStatus(ctx context.Context, arg UpdateCustomerSyncStatusParams) (Customer, error)
 	UpdateCustomerTaxInfo(ctx context.Context, arg UpdateCustomerTaxInfoParams) (Customer, error)
 	UpdateCustomerWallet(ctx context.Context, arg UpdateCustomerWalletParams) (CustomerWallet, error)
+	UpdateCustomerWalletEns(ctx context.Context, arg UpdateCustomerWalletEnsParams) (CustomerWallet, error)
 	UpdateCustomerWalletUsageTime(ctx context.Context, id uuid.UUID) (CustomerWallet, error)
 	UpdateCustomerWithSync(ctx context.Context, arg UpdateCustomerWithSyncParams) (Customer, error)
 	UpdateDelegationData(ctx context.Context, arg UpdateDelegationDataParams) (DelegationDatum, error)
+	// Records the outcome of a delegation check
+	UpdateDelegationStatus(ctx context.Context, arg UpdateDelegationStatusParams) (DelegationDatum, error)
 	UpdateDunningAttempt(ctx context.Context, arg UpdateDunningAttemptParams) (DunningAttempt, error)
 	UpdateDunningCampaign(ctx context.Context, arg UpdateDunningCampaignParams) (DunningCampaign, error)
 	UpdateDunningConfiguration(ctx context.Context, arg UpdateDunningConfigurationParams) (DunningConfiguration, error)
 	UpdateDunningEmailTemplate(ctx context.Context, arg UpdateDunningEmailTemplateParams) (DunningEmailTemplate, error)
 	UpdateFiatCurrency(ctx context.Context, arg UpdateFiatCurrencyParams) (FiatCurrency, error)
 	UpdateGasSponsorshipConfig(ctx context.Context, arg UpdateGasSponsorshipConfigParams) (GasSponsorshipConfig, error)
+	UpdateGasSponsorshipRule(ctx context.Context, arg UpdateGasSponsorshipRuleParams) (GasSponsorshipRule, error)
 	UpdateGasSponsorshipSpending(ctx context.Context, arg UpdateGasSponsorshipSpendingParams) error
 	UpdateInvoice(ctx context.Context, arg UpdateInvoiceParams) (Invoice, error)
 	UpdateInvoiceDetails(ctx context.Context, arg UpdateInvoiceDetailsParams) (Invoice, error)
@@ -655,11 +941,16 @@ type Querier interface {
 	UpdateInvoiceQRCode(ctx context.Context, arg UpdateInvoiceQRCodeParams) (Invoice, error)
 	UpdateInvoiceStatus(ctx context.Context, arg UpdateInvoiceStatusParams) (Invoice, error)
 	UpdateInvoiceSyncStatus(ctx context.Context, arg UpdateInvoiceSyncStatusParams) (Invoice, error)
+	UpdateInvoiceTaxIDVerification(ctx context.Context, arg UpdateInvoiceTaxIDVerificationParams) (Invoice, error)
+	UpdateInvoiceTaxProvider(ctx context.Context, arg UpdateInvoiceTaxProviderParams) (Invoice, error)
 	// Update the last webhook received time for a workspace configuration
 	UpdateLastWebhookTime(ctx context.Context, arg UpdateLastWebhookTimeParams) error
 	UpdateLineItemGasSponsorship(ctx context.Context, arg UpdateLineItemGasSponsorshipParams) (InvoiceLineItem, error)
 	UpdateMetricNetworkData(ctx context.Context, arg UpdateMetricNetworkDataParams) (DashboardMetric, error)
 	UpdateNetwork(ctx context.Context, arg UpdateNetworkParams) (Network, error)
+	// Records the outcome of a check. Only unsettled confirmations change, so a transaction that was already
+	// finalized or rolled back by another run is left alone
+	UpdatePaymentConfirmation(ctx context.Context, arg UpdatePaymentConfirmationParams) (PaymentConfirmation, error)
 	UpdatePaymentGasDetails(ctx context.Context, arg UpdatePaymentGasDetailsParams) (Payment, error)
 	UpdatePaymentGasSponsorship(ctx context.Context, arg UpdatePaymentGasSponsorshipParams) (Payment, error)
 	UpdatePaymentInvoiceID(ctx context.Context, arg UpdatePaymentInvoiceIDParams) (Payment, error)
@@ -679,6 +970,8 @@ type Querier interface {
 	UpdateSubscriptionForUpgrade(ctx context.Context, arg UpdateSubscriptionForUpgradeParams) (Subscription, error)
 	UpdateSubscriptionLineItemQuantity(ctx context.Context, arg UpdateSubscriptionLineItemQuantityParams) (SubscriptionLineItem, error)
 	UpdateSubscriptionPaymentSyncStatus(ctx context.Context, arg UpdateSubscriptionPaymentSyncStatusParams) (Subscription, error)
+	// Points a subscription at another customer wallet and the delegation signed by that wallet
+	UpdateSubscriptionPaymentWallet(ctx context.Context, arg UpdateSubscriptionPaymentWalletParams) (Subscription, error)
 	UpdateSubscriptionStatus(ctx context.Context, arg UpdateSubscriptionStatusParams) (Subscription, error)
 	// UpdatePriceSyncStatus removed - pricing is now in products table
 	UpdateSubscriptionSyncStatus(ctx context.Context, arg UpdateSubscriptionSyncStatusParams) (Subscription, error)
@@ -686,7 +979,9 @@ type Querier interface {
 	UpdateSyncSessionError(ctx context.Context, arg UpdateSyncSessionErrorParams) (PaymentSyncSession, error)
 	UpdateSyncSessionProgress(ctx context.Context, arg UpdateSyncSessionProgressParams) (PaymentSyncSession, error)
 	UpdateSyncSessionStatus(ctx context.Context, arg UpdateSyncSessionStatusParams) (PaymentSyncSession, error)
+	UpdateTaxJurisdiction(ctx context.Context, arg UpdateTaxJurisdictionParams) (TaxJurisdiction, error)
 	UpdateToken(ctx context.Context, arg UpdateTokenParams) (Token, error)
+	UpdateTreasurySweepRule(ctx context.Context, arg UpdateTreasurySweepRuleParams) (TreasurySweepRule, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
 	UpdateWallet(ctx context.Context, arg UpdateWalletParams) (Wallet, error)
@@ -705,7 +1000,11 @@ type Querier interface {
 	UpdateWorkspaceProviderAccount(ctx context.Context, arg UpdateWorkspaceProviderAccountParams) (WorkspaceProviderAccount, error)
 	UpdateWorkspaceProviderConfig(ctx context.Context, arg UpdateWorkspaceProviderConfigParams) (Workspace, error)
 	UpdateWorkspaceSupportedCurrencies(ctx context.Context, arg UpdateWorkspaceSupportedCurrenciesParams) error
+	UpsertCohortMetrics(ctx context.Context, arg UpsertCohortMetricsParams) error
+	UpsertCustomerPortalSettings(ctx context.Context, arg UpsertCustomerPortalSettingsParams) (CustomerPortalSetting, error)
 	UpsertInvoice(ctx context.Context, arg UpsertInvoiceParams) (Invoice, error)
+	UpsertPortfolioSnapshot(ctx context.Context, arg UpsertPortfolioSnapshotParams) (PortfolioSnapshot, error)
+	UpsertWalletBalance(ctx context.Context, arg UpsertWalletBalanceParams) (WalletBalance, error)
 	ValidateAddonForProduct(ctx context.Context, arg ValidateAddonForProductParams) (bool, error)
 	// Check if provider account ID already exists (for constraint validation)
 	ValidateProviderAccountUnique(ctx context.Context, arg ValidateProviderAccountUniqueParams) (int64, error)
@@ -0,0 +1,207 @@
+-- name: CreateAnalyticsExportJob :one
+INSERT INTO analytics_export_jobs (
+    workspace_id,
+    report_schedule_id,
+    datasets,
+    format,
+    fiat_currency,
+    start_date,
+    end_date
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetAnalyticsExportJob :one
+SELECT * FROM analytics_export_jobs
+WHERE id = $1 AND workspace_id = $2;
+
+-- name: ListAnalyticsExportJobs :many
+SELECT * FROM analytics_export_jobs
+WHERE workspace_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountAnalyticsExportJobs :one
+SELECT COUNT(*) FROM analytics_export_jobs
+WHERE workspace_id = $1;
+
+-- name: ClaimAnalyticsExportJob :one
+-- Claims one job for processing; a running job whose worker stopped before stale_before can be claimed again
+UPDATE analytics_export_jobs
+SET
+    status = 'running',
+    attempts = attempts + 1,
+    started_at = NOW(),
+    error_message = NULL
+WHERE id = @id
+    AND (status = 'pending' OR (status = 'running' AND started_at < @stale_before))
+RETURNING *;
+
+-- name: ClaimAnalyticsExportJobs :many
+-- Claims the oldest pending jobs, and running jobs whose worker stopped before stale_before
+UPDATE analytics_export_jobs
+SET
+    status = 'running',
+    attempts = attempts + 1,
+    started_at = NOW(),
+    error_message = NULL
+WHERE id IN (
+    SELECT j.id FROM analytics_export_jobs j
+    WHERE j.status = 'pending'
+        OR (j.status = 'running' AND j.started_at < @stale_before)
+    ORDER BY j.created_at
+    LIMIT @batch_size
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteAnalyticsExportJob :one
+UPDATE analytics_export_jobs
+SET
+    status = 'completed',
+    files = $2,
+    completed_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: FailAnalyticsExportJob :one
+-- Returns the job to the queue until it has used max_attempts
+UPDATE analytics_export_jobs
+SET
+    status = CASE WHEN attempts >= @max_attempts::int THEN 'failed' ELSE 'pending' END,
+    error_message = @error_message,
+    completed_at = CASE WHEN attempts >= @max_attempts::int THEN NOW() ELSE NULL END
+WHERE id = @id
+RETURNING *;
+
+-- name: ListPaymentsForExport :many
+SELECT
+    p.id,
+    p.created_at,
+    p.completed_at,
+    p.status,
+    p.payment_method,
+    p.customer_id,
+    c.email AS customer_email,
+    c.name AS customer_name,
+    p.subscription_id,
+    p.invoice_id,
+    p.currency,
+    p.amount_in_cents,
+    p.product_amount_cents,
+    p.tax_amount_cents,
+    p.discount_amount_cents,
+    p.gas_fee_usd_cents,
+    p.gas_sponsored,
+    n.name AS network_name,
+    p.transaction_hash
+FROM payments p
+LEFT JOIN customers c ON c.id = p.customer_id
+LEFT JOIN networks n ON n.id = p.network_id
+WHERE p.workspace_id = @workspace_id
+    AND p.created_at >= @start_date
+    AND p.created_at < @end_date
+ORDER BY p.created_at, p.id;
+
+-- name: ListInvoicesForExport :many
+SELECT
+    i.id,
+    i.invoice_number,
+    i.status,
+    i.customer_id,
+    c.email AS customer_email,
+    c.name AS customer_name,
+    i.subscription_id,
+    i.currency,
+    i.subtotal_cents,
+    i.discount_cents,
+    i.tax_amount_cents,
+    i.amount_due,
+    i.amount_paid,
+    i.amount_remaining,
+    i.reverse_charge_applies,
+    i.created_date,
+    i.due_date,
+    i.paid_at
+FROM invoices i
+LEFT JOIN customers c ON c.id = i.customer_id
+WHERE i.workspace_id = @workspace_id
+    AND i.deleted_at IS NULL
+    AND i.created_date >= @start_date
+    AND i.created_date < @end_date
+ORDER BY i.created_date, i.id;
+
+-- name: ListSubscriptionsForExport :many
+-- Subscriptions that existed at some point in the range
+SELECT
+    s.id,
+    s.status,
+    s.customer_id,
+    c.email AS customer_email,
+    c.name AS customer_name,
+    s.product_id,
+    p.name AS product_name,
+    p.interval_type,
+    p.currency,
+    s.total_amount_in_cents,
+    s.total_redemptions,
+    s.current_period_start,
+    s.current_period_end,
+    s.created_at,
+    s.updated_at
+FROM subscriptions s
+JOIN products p ON p.id = s.product_id
+LEFT JOIN customers c ON c.id = s.customer_id
+WHERE s.workspace_id = @workspace_id
+    AND s.deleted_at IS NULL
+    AND s.created_at < @end_date
+    AND (s.status NOT IN ('canceled', 'expired', 'completed', 'failed') OR s.updated_at >= @start_date)
+ORDER BY s.created_at, s.id;
+
+-- name: ListMRRMovementsForExport :many
+SELECT
+    m.id,
+    m.occurred_at,
+    m.subscription_id,
+    m.customer_id,
+    c.email AS customer_email,
+    c.name AS customer_name,
+    p.name AS product_name,
+    m.movement_type,
+    m.source,
+    m.interval_type,
+    m.currency,
+    m.from_mrr_cents,
+    m.to_mrr_cents,
+    m.mrr_delta_cents
+FROM mrr_movements m
+JOIN subscriptions s ON s.id = m.subscription_id
+JOIN products p ON p.id = s.product_id
+LEFT JOIN customers c ON c.id = m.customer_id
+WHERE m.workspace_id = @workspace_id
+    AND m.occurred_at >= @start_date
+    AND m.occurred_at < @end_date
+ORDER BY m.occurred_at, m.id;
+
+-- name: ListGasFeesForExport :many
+SELECT
+    g.id,
+    g.payment_id,
+    g.created_at,
+    n.name AS network_name,
+    p.transaction_hash,
+    g.block_number,
+    g.gas_units_used,
+    g.gas_price_gwei,
+    g.gas_fee_wei,
+    g.payment_method,
+    g.sponsor_type,
+    g.gas_fee_usd_cents
+FROM gas_fee_payments g
+JOIN payments p ON p.id = g.payment_id
+JOIN networks n ON n.id = g.network_id
+WHERE p.workspace_id = @workspace_id
+    AND g.created_at >= @start_date
+    AND g.created_at < @end_date
+ORDER BY g.created_at, g.id;
@@ -0,0 +1,66 @@
+-- name: CreateAnalyticsReportSchedule :one
+INSERT INTO analytics_report_schedules (
+    workspace_id,
+    name,
+    frequency,
+    datasets,
+    format,
+    fiat_currency,
+    recipients,
+    active,
+    next_run_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetAnalyticsReportSchedule :one
+SELECT * FROM analytics_report_schedules
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL;
+
+-- name: ListAnalyticsReportSchedules :many
+SELECT * FROM analytics_report_schedules
+WHERE workspace_id = $1 AND deleted_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: UpdateAnalyticsReportSchedule :one
+UPDATE analytics_report_schedules
+SET
+    name = $3,
+    frequency = $4,
+    datasets = $5,
+    format = $6,
+    fiat_currency = $7,
+    recipients = $8,
+    active = $9,
+    next_run_at = $10
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteAnalyticsReportSchedule :exec
+UPDATE analytics_report_schedules
+SET deleted_at = NOW(), active = false
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL;
+
+-- name: ClaimDueAnalyticsReportSchedules :many
+-- Moves due schedules to their next run before delivery, so each period is reported once
+WITH due AS (
+    SELECT id, next_run_at AS period_end
+    FROM analytics_report_schedules
+    WHERE active = true
+        AND deleted_at IS NULL
+        AND next_run_at <= @now
+    ORDER BY next_run_at
+    LIMIT @batch_size
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE analytics_report_schedules s
+SET
+    last_run_at = @now,
+    next_run_at = CASE s.frequency
+        WHEN 'weekly' THEN s.next_run_at + INTERVAL '1 week'
+        ELSE s.next_run_at + INTERVAL '1 month'
+    END
+FROM due
+WHERE s.id = due.id
+RETURNING s.id, s.workspace_id, s.name, s.frequency, s.datasets, s.format, s.fiat_currency, s.recipients, due.period_end;
@@ -0,0 +1,60 @@
+-- name: RecordAPIKeyUsage :exec
+-- Adds requests to the key's daily counter, keeps the most recent client details
+-- and bumps the key's last_used_at in the same statement
+WITH touched_key AS (
+    UPDATE api_keys
+    SET last_used_at = GREATEST(api_keys.last_used_at, $7)
+    WHERE api_keys.id = $1
+)
+INSERT INTO api_key_usage_daily (
+    api_key_id,
+    workspace_id,
+    usage_date,
+    request_count,
+    last_ip,
+    last_user_agent,
+    last_used_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (api_key_id, usage_date)
+DO UPDATE SET
+    request_count = api_key_usage_daily.request_count + EXCLUDED.request_count,
+    last_ip = EXCLUDED.last_ip,
+    last_user_agent = EXCLUDED.last_user_agent,
+    last_used_at = GREATEST(api_key_usage_daily.last_used_at, EXCLUDED.last_used_at);
+
+-- name: ListAPIKeyUsage :many
+SELECT * FROM api_key_usage_daily
+WHERE api_key_id = @api_key_id
+    AND workspace_id = @workspace_id
+    AND usage_date >= @start_date::date
+    AND usage_date <= @end_date::date
+ORDER BY usage_date DESC;
+
+-- name: ListUnusedAPIKeys :many
+-- Returns active keys idle since the cutoff that have not been notified since they were last used,
+-- along with the workspace owner to notify
+SELECT
+    k.id,
+    k.workspace_id,
+    k.name,
+    k.key_prefix,
+    k.expires_at,
+    k.last_used_at,
+    k.created_at,
+    w.name AS workspace_name,
+    u.email AS owner_email
+FROM api_keys k
+JOIN workspaces w ON w.id = k.workspace_id
+JOIN users u ON u.account_id = w.account_id AND u.is_account_owner = true AND u.deleted_at IS NULL
+WHERE k.deleted_at IS NULL
+    AND (k.expires_at IS NULL OR k.expires_at > CURRENT_TIMESTAMP)
+    AND COALESCE(k.last_used_at, k.created_at) < @cutoff::timestamptz
+    AND (k.unused_notified_at IS NULL OR k.unused_notified_at < COALESCE(k.last_used_at, k.created_at))
+ORDER BY k.workspace_id, k.created_at;
+
+-- name: MarkAPIKeyUnusedNotified :exec
+UPDATE api_keys
+SET unused_notified_at = CURRENT_TIMESTAMP
+WHERE id = $1;
@@ -52,4 +52,42 @@ WHERE workspace_id = $1 AND deleted_at IS NULL;
 -- name: GetExpiredAPIKeys :many
 SELECT * FROM api_keys
 WHERE expires_at < CURRENT_TIMESTAMP AND deleted_at IS NULL
-ORDER BY created_at DESC;
\ No newline at end of file
+ORDER BY created_at DESC;
+
+-- name: RotateAPIKey :one
+-- Issues a replacement key and caps the old key's validity at the grace expiry in a single statement.
+-- Keys that are deleted, expired or already rotated are not rotated again.
+WITH old_key AS (
+    UPDATE api_keys
+    SET
+        expires_at = LEAST(api_keys.expires_at, @grace_expires_at::timestamptz),
+        rotated_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE api_keys.id = @id
+        AND api_keys.workspace_id = @workspace_id
+        AND api_keys.deleted_at IS NULL
+        AND api_keys.rotated_at IS NULL
+        AND (api_keys.expires_at IS NULL OR api_keys.expires_at > CURRENT_TIMESTAMP)
+    RETURNING api_keys.id, api_keys.workspace_id, api_keys.name, api_keys.access_level, api_keys.metadata
+)
+INSERT INTO api_keys (
+    workspace_id,
+    name,
+    key_hash,
+    key_prefix,
+    access_level,
+    expires_at,
+    metadata,
+    rotated_from_id
+)
+SELECT
+    old_key.workspace_id,
+    old_key.name,
+    @key_hash::text,
+    sqlc.narg('key_prefix')::text,
+    old_key.access_level,
+    sqlc.narg('expires_at')::timestamptz,
+    old_key.metadata,
+    old_key.id
+FROM old_key
+RETURNING *;
@@ -0,0 +1,61 @@
+-- name: GetCircuitBreaker :one
+SELECT * FROM circuit_breakers
+WHERE name = $1;
+
+-- name: HalfOpenCircuitBreaker :one
+-- Lets exactly one caller probe the dependency once the breaker has been open for the reset timeout.
+-- A probe that never reports back is given up after the same timeout
+UPDATE circuit_breakers
+SET
+    state = 'half_open',
+    state_changed_at = NOW()
+WHERE name = @name
+    AND state IN ('open', 'half_open')
+    AND state_changed_at <= @reset_before
+RETURNING *;
+
+-- name: RecordCircuitBreakerFailure :one
+-- Counts a failure and opens the breaker at the threshold, or straight away when a probe failed
+INSERT INTO circuit_breakers (
+    name,
+    state,
+    consecutive_failures,
+    failure_threshold,
+    last_failure_at,
+    last_error
+) VALUES (
+    @name,
+    CASE WHEN @failure_threshold::int <= 1 THEN 'open' ELSE 'closed' END,
+    1,
+    @failure_threshold,
+    NOW(),
+    @last_error
+)
+ON CONFLICT (name) DO UPDATE
+SET
+    state = CASE
+        WHEN circuit_breakers.state = 'half_open'
+            OR circuit_breakers.consecutive_failures + 1 >= EXCLUDED.failure_threshold THEN 'open'
+        ELSE circuit_breakers.state
+    END,
+    state_changed_at = CASE
+        WHEN circuit_breakers.state <> 'open'
+            AND (circuit_breakers.state = 'half_open'
+                OR circuit_breakers.consecutive_failures + 1 >= EXCLUDED.failure_threshold) THEN NOW()
+        ELSE circuit_breakers.state_changed_at
+    END,
+    consecutive_failures = circuit_breakers.consecutive_failures + 1,
+    failure_threshold = EXCLUDED.failure_threshold,
+    last_failure_at = NOW(),
+    last_error = EXCLUDED.last_error
+RETURNING *;
+
+-- name: RecordCircuitBreakerSuccess :execrows
+-- Closes the breaker and resets its failure count; rows are only written when something changes
+UPDATE circuit_breakers
+SET
+    state = 'closed',
+    consecutive_failures = 0,
+    state_changed_at = CASE WHEN state <> 'closed' THEN NOW() ELSE state_changed_at END
+WHERE name = @name
+    AND (state <> 'closed' OR consecutive_failures > 0);
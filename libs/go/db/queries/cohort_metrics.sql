@@ -0,0 +1,177 @@
+-- name: CalculateCohortMetrics :many
+-- Builds the cohort grid for customers who first subscribed from cohorts_from onwards. Cohorts are the
+-- month of a customer's first subscription, or the product or network they subscribed to. A customer
+-- is active in a month when one of their subscriptions in the cohort ended the month active or
+-- overdue, or they paid for it that month. Subscriptions without state history count as active from
+-- creation until their last update, or until now while they are active.
+WITH months AS (
+    SELECT generate_series(
+        date_trunc('month', @cohorts_from::timestamptz),
+        date_trunc('month', @as_of::timestamptz),
+        interval '1 month'
+    ) AS month_start
+),
+subscription_segments AS (
+    SELECT
+        s.id AS subscription_id,
+        s.customer_id,
+        s.created_at,
+        s.updated_at,
+        s.status,
+        CASE @cohort_by::text
+            WHEN 'product' THEN s.product_id::text
+            WHEN 'network' THEN pt.network_id::text
+            ELSE ''
+        END AS segment,
+        CASE @cohort_by::text
+            WHEN 'product' THEN pr.name
+            WHEN 'network' THEN n.name
+            ELSE ''
+        END AS segment_label
+    FROM subscriptions s
+    JOIN products_tokens pt ON pt.id = s.product_token_id
+    JOIN products pr ON pr.id = s.product_id
+    JOIN networks n ON n.id = pt.network_id
+    WHERE s.workspace_id = @workspace_id
+        AND s.deleted_at IS NULL
+),
+members AS (
+    SELECT
+        customer_id,
+        segment,
+        segment_label,
+        date_trunc('month', MIN(created_at)) AS joined_month
+    FROM subscription_segments
+    GROUP BY customer_id, segment, segment_label
+),
+subscription_months AS (
+    SELECT DISTINCT ss.customer_id, ss.segment, m.month_start
+    FROM subscription_segments ss
+    JOIN months m ON m.month_start >= date_trunc('month', ss.created_at)
+    WHERE COALESCE(
+        (
+            SELECT h.to_status
+            FROM subscription_state_history h
+            WHERE h.subscription_id = ss.subscription_id
+                AND h.occurred_at < m.month_start + interval '1 month'
+            ORDER BY h.occurred_at DESC
+            LIMIT 1
+        ),
+        CASE
+            WHEN ss.status IN ('active', 'overdue') OR ss.updated_at >= m.month_start THEN 'active'::subscription_status
+            ELSE ss.status
+        END
+    ) IN ('active', 'overdue')
+),
+payment_months AS (
+    SELECT
+        ss.customer_id,
+        ss.segment,
+        date_trunc('month', p.completed_at) AS month_start,
+        SUM(p.product_amount_cents - COALESCE(p.discount_amount_cents, 0)) AS revenue_cents
+    FROM payments p
+    JOIN subscription_segments ss ON ss.subscription_id = p.subscription_id
+    WHERE p.workspace_id = @workspace_id
+        AND p.status = 'completed'
+        -- A crypto renewal counts once its transaction is finalized
+        AND (p.transaction_hash IS NULL OR p.network_id IS NULL OR EXISTS (
+            SELECT 1 FROM payment_confirmations pc WHERE pc.payment_id = p.id AND pc.status = 'finalized'
+        ))
+        AND p.currency = @currency
+        AND p.completed_at >= date_trunc('month', @cohorts_from::timestamptz)
+        AND p.completed_at < date_trunc('month', @as_of::timestamptz) + interval '1 month'
+    GROUP BY ss.customer_id, ss.segment, date_trunc('month', p.completed_at)
+),
+activity AS (
+    SELECT customer_id, segment, month_start, SUM(revenue_cents)::bigint AS revenue_cents
+    FROM (
+        SELECT customer_id, segment, month_start, 0::bigint AS revenue_cents FROM subscription_months
+        UNION ALL
+        SELECT customer_id, segment, month_start, revenue_cents FROM payment_months
+    ) combined
+    GROUP BY customer_id, segment, month_start
+),
+member_periods AS (
+    SELECT
+        mb.customer_id,
+        mb.segment,
+        CASE WHEN mb.segment = '' THEN to_char(mb.joined_month, 'YYYY-MM') ELSE mb.segment END AS cohort_key,
+        CASE WHEN mb.segment = '' THEN to_char(mb.joined_month, 'YYYY-MM') ELSE mb.segment_label END AS cohort_label,
+        mb.joined_month,
+        m.month_start,
+        ((EXTRACT(YEAR FROM m.month_start) - EXTRACT(YEAR FROM mb.joined_month)) * 12
+            + EXTRACT(MONTH FROM m.month_start) - EXTRACT(MONTH FROM mb.joined_month))::int AS period_offset
+    FROM members mb
+    JOIN months m ON m.month_start >= mb.joined_month
+    WHERE mb.joined_month >= date_trunc('month', @cohorts_from::timestamptz)
+)
+SELECT
+    mp.cohort_key::text AS cohort_key,
+    mp.cohort_label::text AS cohort_label,
+    mp.period_offset,
+    COUNT(*)::bigint AS eligible_customers,
+    COUNT(a.customer_id)::bigint AS active_customers,
+    COALESCE(SUM(a.revenue_cents), 0)::bigint AS revenue_cents,
+    COALESCE(SUM(a0.revenue_cents), 0)::bigint AS starting_revenue_cents
+FROM member_periods mp
+LEFT JOIN activity a ON a.customer_id = mp.customer_id
+    AND a.segment = mp.segment
+    AND a.month_start = mp.month_start
+LEFT JOIN activity a0 ON a0.customer_id = mp.customer_id
+    AND a0.segment = mp.segment
+    AND a0.month_start = mp.joined_month
+GROUP BY mp.cohort_key, mp.cohort_label, mp.period_offset
+ORDER BY mp.cohort_key, mp.period_offset;
+
+-- name: UpsertCohortMetrics :exec
+INSERT INTO cohort_metrics (
+    workspace_id,
+    cohort_by,
+    lookback_months,
+    fiat_currency,
+    cohort_key,
+    cohort_label,
+    period_offset,
+    eligible_customers,
+    active_customers,
+    revenue_cents,
+    starting_revenue_cents,
+    calculated_at
+)
+SELECT
+    @workspace_id,
+    @cohort_by,
+    @lookback_months,
+    @fiat_currency,
+    unnest(@cohort_keys::text[]),
+    unnest(@cohort_labels::text[]),
+    unnest(@period_offsets::int[]),
+    unnest(@eligible_customers::bigint[]),
+    unnest(@active_customers::bigint[]),
+    unnest(@revenue_cents::bigint[]),
+    unnest(@starting_revenue_cents::bigint[]),
+    @calculated_at
+ON CONFLICT (workspace_id, cohort_by, lookback_months, fiat_currency, cohort_key, period_offset) DO UPDATE SET
+    cohort_label = EXCLUDED.cohort_label,
+    eligible_customers = EXCLUDED.eligible_customers,
+    active_customers = EXCLUDED.active_customers,
+    revenue_cents = EXCLUDED.revenue_cents,
+    starting_revenue_cents = EXCLUDED.starting_revenue_cents,
+    calculated_at = EXCLUDED.calculated_at;
+
+-- name: DeleteStaleCohortMetrics :exec
+-- Removes cells of a calculation that the latest calculation no longer produced
+DELETE FROM cohort_metrics
+WHERE workspace_id = @workspace_id
+    AND cohort_by = @cohort_by
+    AND lookback_months = @lookback_months
+    AND fiat_currency = @fiat_currency
+    AND calculated_at < @calculated_at;
+
+-- name: ListCohortMetrics :many
+SELECT * FROM cohort_metrics
+WHERE workspace_id = @workspace_id
+    AND cohort_by = @cohort_by
+    AND lookback_months = @lookback_months
+    AND fiat_currency = @fiat_currency
+ORDER BY cohort_key, period_offset;
@@ -0,0 +1,106 @@
+-- name: GetCustomerPortalSettings :one
+SELECT * FROM customer_portal_settings
+WHERE workspace_id = $1;
+
+-- name: UpsertCustomerPortalSettings :one
+INSERT INTO customer_portal_settings (
+    workspace_id,
+    allow_cancel,
+    allow_pause,
+    allow_resume,
+    allow_update_billing,
+    allow_wallet_switch,
+    default_return_url,
+    session_ttl_seconds
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (workspace_id) DO UPDATE SET
+    allow_cancel = EXCLUDED.allow_cancel,
+    allow_pause = EXCLUDED.allow_pause,
+    allow_resume = EXCLUDED.allow_resume,
+    allow_update_billing = EXCLUDED.allow_update_billing,
+    allow_wallet_switch = EXCLUDED.allow_wallet_switch,
+    default_return_url = EXCLUDED.default_return_url,
+    session_ttl_seconds = EXCLUDED.session_ttl_seconds,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: CreateCustomerPortalSession :one
+INSERT INTO customer_portal_sessions (
+    workspace_id,
+    customer_id,
+    token_hash,
+    return_url,
+    created_by_api_key_id,
+    created_by_user_id,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetActiveCustomerPortalSessionByTokenHash :one
+SELECT * FROM customer_portal_sessions
+WHERE token_hash = $1
+    AND revoked_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP;
+
+-- name: TouchCustomerPortalSession :exec
+UPDATE customer_portal_sessions
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RevokeCustomerPortalSession :execrows
+UPDATE customer_portal_sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2 AND revoked_at IS NULL;
+
+-- name: DeleteExpiredCustomerPortalSessions :execrows
+DELETE FROM customer_portal_sessions
+WHERE expires_at <= $1;
+
+-- name: ListCustomerPortalSubscriptions :many
+-- Subscriptions of a customer across all merchants, optionally limited to one workspace
+SELECT
+    s.id,
+    s.workspace_id,
+    w.name AS merchant_name,
+    p.name AS product_name,
+    s.status,
+    s.currency,
+    s.total_amount_in_cents,
+    s.current_period_start,
+    s.current_period_end,
+    s.next_redemption_date,
+    s.cancel_at,
+    s.paused_at,
+    s.pause_ends_at,
+    s.customer_wallet_id,
+    cw.wallet_address,
+    s.created_at
+FROM subscriptions s
+JOIN workspaces w ON w.id = s.workspace_id
+JOIN products p ON p.id = s.product_id
+LEFT JOIN customer_wallets cw ON cw.id = s.customer_wallet_id
+WHERE s.customer_id = @customer_id
+    AND (sqlc.narg(workspace_id)::uuid IS NULL OR s.workspace_id = sqlc.narg(workspace_id))
+    AND s.deleted_at IS NULL
+ORDER BY s.created_at DESC;
+
+-- name: ListCustomerPortalInvoices :many
+-- Finalized invoices of a customer across all merchants; drafts stay private to the merchant
+SELECT * FROM invoices
+WHERE customer_id = @customer_id
+    AND (sqlc.narg(workspace_id)::uuid IS NULL OR workspace_id = sqlc.narg(workspace_id))
+    AND status <> 'draft'
+    AND deleted_at IS NULL
+ORDER BY created_date DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListCustomerPortalPayments :many
+SELECT * FROM payments
+WHERE customer_id = @customer_id
+    AND (sqlc.narg(workspace_id)::uuid IS NULL OR workspace_id = sqlc.narg(workspace_id))
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -7,9 +7,10 @@ INSERT INTO customer_wallets (
     ens,
     is_primary,
     verified,
-    metadata
+    metadata,
+    ens_resolved_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 ) RETURNING *;
 
 -- name: GetCustomerWallet :one
@@ -17,8 +18,12 @@ SELECT * FROM customer_wallets
 WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: GetCustomerWalletByAddress :one
+-- EVM addresses match whatever their casing
 SELECT * FROM customer_wallets
-WHERE customer_id = $1 AND wallet_address = $2 AND network_type = $3 AND deleted_at IS NULL;
+WHERE customer_id = $1
+    AND (wallet_address = $2 OR (network_type = 'evm' AND LOWER(wallet_address) = LOWER($2)))
+    AND network_type = $3
+    AND deleted_at IS NULL;
 
 -- name: ListCustomerWallets :many
 SELECT * FROM customer_wallets
@@ -36,9 +41,11 @@ WHERE customer_id = $1 AND is_primary = true AND deleted_at IS NULL
 LIMIT 1;
 
 -- name: GetCustomersByWalletAddress :many
+-- EVM addresses match whatever their casing
 SELECT c.* FROM customers c
 JOIN customer_wallets cw ON c.id = cw.customer_id
-WHERE cw.wallet_address = $1 AND c.deleted_at IS NULL AND cw.deleted_at IS NULL;
+WHERE (cw.wallet_address = $1 OR (cw.network_type = 'evm' AND LOWER(cw.wallet_address) = LOWER($1)))
+    AND c.deleted_at IS NULL AND cw.deleted_at IS NULL;
 
 -- name: UpdateCustomerWallet :one
 UPDATE customer_wallets SET
@@ -105,4 +112,19 @@ LIMIT $2;
 UPDATE customer_wallets
 SET last_used_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING *; 
\ No newline at end of file
+RETURNING *; 
+
+-- name: ListCustomerWalletsDueNameRefresh :many
+-- EVM wallets whose ENS or Basenames name has never been looked up or was last looked up before the cutoff
+SELECT * FROM customer_wallets
+WHERE network_type = 'evm'
+    AND deleted_at IS NULL
+    AND (ens_resolved_at IS NULL OR ens_resolved_at < @resolved_before::timestamptz)
+ORDER BY ens_resolved_at NULLS FIRST, id
+LIMIT @batch_size;
+
+-- name: UpdateCustomerWalletEns :one
+UPDATE customer_wallets
+SET ens = $2, ens_resolved_at = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
@@ -208,6 +208,21 @@ SET
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
+-- name: UpdateCustomerBillingDetails :one
+-- Updates only the billing fields that are provided
+UPDATE customers
+SET
+    name = COALESCE(sqlc.narg(name), name),
+    email = COALESCE(sqlc.narg(email), email),
+    phone = COALESCE(sqlc.narg(phone), phone),
+    billing_country = COALESCE(sqlc.narg(billing_country), billing_country),
+    billing_state = COALESCE(sqlc.narg(billing_state), billing_state),
+    billing_city = COALESCE(sqlc.narg(billing_city), billing_city),
+    billing_postal_code = COALESCE(sqlc.narg(billing_postal_code), billing_postal_code),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id AND deleted_at IS NULL
+RETURNING *;
+
 -- name: GetCustomersByBillingCountry :many
 SELECT * FROM customers
 WHERE billing_country = $1 AND deleted_at IS NULL
@@ -59,4 +59,43 @@ WHERE delegator = $1 AND deleted_at IS NULL;
 SELECT * FROM delegation_data
 WHERE deleted_at IS NULL
 ORDER BY created_at DESC
-LIMIT $1 OFFSET $2; 
\ No newline at end of file
+LIMIT $1 OFFSET $2; 
+-- name: ListDelegationsDueForCheck :many
+-- Active delegations behind live subscriptions that have not been checked since the cutoff, least recently checked first
+SELECT
+    d.id AS delegation_id,
+    d.delegate,
+    d.delegator,
+    d.authority,
+    d.caveats,
+    d.salt,
+    d.expires_at,
+    s.id AS subscription_id,
+    s.workspace_id,
+    s.customer_id,
+    s.token_amount,
+    s.next_redemption_date,
+    pt.network_id,
+    n.network_type
+FROM delegation_data d
+JOIN subscriptions s ON s.delegation_id = d.id
+JOIN products_tokens pt ON pt.id = s.product_token_id
+JOIN networks n ON n.id = pt.network_id
+WHERE d.deleted_at IS NULL
+    AND d.status = 'active'
+    AND (d.last_checked_at IS NULL OR d.last_checked_at < sqlc.arg(checked_before))
+    AND s.deleted_at IS NULL
+    AND s.status IN ('active', 'trial', 'overdue')
+ORDER BY d.last_checked_at ASC NULLS FIRST, d.id
+LIMIT sqlc.arg(batch_size);
+
+-- name: UpdateDelegationStatus :one
+-- Records the outcome of a delegation check
+UPDATE delegation_data
+SET
+    status = $2,
+    expires_at = $3,
+    last_checked_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
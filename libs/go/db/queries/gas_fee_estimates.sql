@@ -0,0 +1,43 @@
+-- name: CreateGasFeeEstimate :one
+INSERT INTO gas_fee_estimates (
+    workspace_id,
+    payment_id,
+    network_id,
+    estimate_source,
+    estimated_gas_units,
+    gas_units_simulated,
+    gas_price_wei,
+    base_fee_wei,
+    priority_fee_wei,
+    max_fee_per_gas_wei,
+    l1_data_fee_wei,
+    estimated_cost_wei,
+    estimated_cost_usd_cents,
+    confidence
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+)
+RETURNING *;
+
+-- name: GetGasFeeEstimateAccuracy :many
+-- Compares estimates with the actual fees of the same payments, per network and estimate source.
+-- Ratios above 1 mean the estimates were too high.
+SELECT
+    e.network_id,
+    n.name AS network_name,
+    e.estimate_source,
+    COUNT(*)::bigint AS sample_count,
+    COALESCE(AVG(e.estimated_cost_wei::numeric / NULLIF(gfp.gas_fee_wei::numeric, 0)), 0)::float8 AS avg_cost_ratio,
+    COALESCE(AVG(ABS(e.estimated_cost_wei::numeric - gfp.gas_fee_wei::numeric) / NULLIF(gfp.gas_fee_wei::numeric, 0)), 0)::float8 AS mean_abs_error_ratio,
+    COALESCE(AVG(e.estimated_gas_units::numeric / NULLIF(gfp.gas_units_used, 0)), 0)::float8 AS avg_gas_units_ratio,
+    COUNT(*) FILTER (WHERE e.estimated_cost_wei::numeric < gfp.gas_fee_wei::numeric)::bigint AS underestimated_count,
+    COALESCE(SUM(e.estimated_cost_usd_cents), 0)::bigint AS estimated_usd_cents,
+    COALESCE(SUM(gfp.gas_fee_usd_cents), 0)::bigint AS actual_usd_cents
+FROM gas_fee_estimates e
+JOIN gas_fee_payments gfp ON gfp.payment_id = e.payment_id
+JOIN networks n ON n.id = e.network_id
+WHERE e.workspace_id = @workspace_id
+    AND gfp.created_at >= @start_date
+    AND gfp.created_at < @end_date
+GROUP BY e.network_id, n.name, e.estimate_source
+ORDER BY n.name, e.estimate_source;
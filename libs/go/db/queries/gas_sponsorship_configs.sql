@@ -7,9 +7,10 @@ INSERT INTO gas_sponsorship_configs (
     monthly_budget_usd_cents,
     sponsor_for_products,
     sponsor_for_customers,
-    sponsor_for_tiers
+    sponsor_for_tiers,
+    per_customer_monthly_cap_usd_cents
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 ON CONFLICT (workspace_id)
 DO UPDATE SET
@@ -20,6 +21,7 @@ DO UPDATE SET
     sponsor_for_products = EXCLUDED.sponsor_for_products,
     sponsor_for_customers = EXCLUDED.sponsor_for_customers,
     sponsor_for_tiers = EXCLUDED.sponsor_for_tiers,
+    per_customer_monthly_cap_usd_cents = EXCLUDED.per_customer_monthly_cap_usd_cents,
     updated_at = CURRENT_TIMESTAMP
 RETURNING *;
 
@@ -37,6 +39,7 @@ SET
     sponsor_for_products = COALESCE($6, sponsor_for_products),
     sponsor_for_customers = COALESCE($7, sponsor_for_customers),
     sponsor_for_tiers = COALESCE($8, sponsor_for_tiers),
+    per_customer_monthly_cap_usd_cents = COALESCE($9, per_customer_monthly_cap_usd_cents),
     updated_at = CURRENT_TIMESTAMP
 WHERE workspace_id = $1
 RETURNING *;
@@ -0,0 +1,226 @@
+-- name: ReserveGasSponsorshipBudget :one
+-- Holds budget for a sponsored transaction. The hold and its ledger entry are only written when
+-- the amount still fits the workspace's monthly budget alongside spending and other holds.
+WITH budget AS (
+    UPDATE gas_sponsorship_configs
+    SET 
+        current_month_reserved_cents = current_month_reserved_cents + @reserved_usd_cents::bigint,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE workspace_id = @workspace_id
+        AND (monthly_budget_usd_cents IS NULL
+            OR COALESCE(current_month_spent_cents, 0) + current_month_reserved_cents + @reserved_usd_cents::bigint <= monthly_budget_usd_cents)
+    RETURNING workspace_id, current_month_spent_cents, current_month_reserved_cents, monthly_budget_usd_cents
+), reservation AS (
+    INSERT INTO gas_sponsorship_reservations (
+        workspace_id,
+        customer_id,
+        rule_id,
+        reserved_usd_cents,
+        sponsor_percentage,
+        period_start,
+        expires_at
+    )
+    SELECT workspace_id, sqlc.narg('customer_id'), sqlc.narg('rule_id'), @reserved_usd_cents, @sponsor_percentage, @period_start, @expires_at
+    FROM budget
+    RETURNING *
+), entry AS (
+    INSERT INTO gas_sponsorship_ledger (
+        workspace_id,
+        reservation_id,
+        customer_id,
+        entry_type,
+        amount_usd_cents,
+        budget_spent_cents,
+        budget_reserved_cents,
+        monthly_budget_usd_cents
+    )
+    SELECT r.workspace_id, r.id, r.customer_id, 'reserve', r.reserved_usd_cents, COALESCE(b.current_month_spent_cents, 0), b.current_month_reserved_cents, b.monthly_budget_usd_cents
+    FROM reservation r
+    JOIN budget b ON b.workspace_id = r.workspace_id
+)
+SELECT * FROM reservation;
+
+-- name: ReserveGasSponsorshipCustomerSpending :one
+-- Counts a reservation against its customer's month, only when it fits the cap (if any)
+WITH spending AS (
+    INSERT INTO gas_sponsorship_customer_spending AS cs (
+        workspace_id,
+        customer_id,
+        period_start,
+        reserved_usd_cents
+    )
+    SELECT r.workspace_id, r.customer_id, r.period_start, r.reserved_usd_cents
+    FROM gas_sponsorship_reservations r
+    WHERE r.id = @id
+        AND r.status = 'reserved'
+        AND NOT r.customer_reserved
+        AND r.customer_id IS NOT NULL
+        AND (sqlc.narg('cap_usd_cents')::bigint IS NULL OR r.reserved_usd_cents <= sqlc.narg('cap_usd_cents')::bigint)
+    ON CONFLICT (workspace_id, customer_id, period_start)
+    DO UPDATE SET
+        reserved_usd_cents = cs.reserved_usd_cents + EXCLUDED.reserved_usd_cents,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE sqlc.narg('cap_usd_cents')::bigint IS NULL
+        OR cs.spent_usd_cents + cs.reserved_usd_cents + EXCLUDED.reserved_usd_cents <= sqlc.narg('cap_usd_cents')::bigint
+    RETURNING cs.workspace_id
+)
+UPDATE gas_sponsorship_reservations
+SET 
+    customer_reserved = TRUE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id AND EXISTS (SELECT 1 FROM spending)
+RETURNING *;
+
+-- name: SettleGasSponsorshipReservation :one
+-- Replaces a hold with the sponsored share of the actual gas cost, which never exceeds the hold
+WITH reservation AS (
+    UPDATE gas_sponsorship_reservations
+    SET 
+        status = 'settled',
+        settled_usd_cents = LEAST(GREATEST(@settled_usd_cents::bigint, 0), reserved_usd_cents),
+        payment_id = COALESCE(sqlc.narg('payment_id'), payment_id),
+        settled_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = @id AND workspace_id = @workspace_id AND status = 'reserved'
+    RETURNING *
+), budget AS (
+    UPDATE gas_sponsorship_configs c
+    SET 
+        current_month_spent_cents = COALESCE(c.current_month_spent_cents, 0) + r.settled_usd_cents,
+        current_month_reserved_cents = GREATEST(c.current_month_reserved_cents - r.reserved_usd_cents, 0),
+        updated_at = CURRENT_TIMESTAMP
+    FROM reservation r
+    WHERE c.workspace_id = r.workspace_id
+    RETURNING c.workspace_id, c.current_month_spent_cents, c.current_month_reserved_cents, c.monthly_budget_usd_cents
+), spending AS (
+    UPDATE gas_sponsorship_customer_spending cs
+    SET 
+        spent_usd_cents = cs.spent_usd_cents + r.settled_usd_cents,
+        reserved_usd_cents = GREATEST(cs.reserved_usd_cents - r.reserved_usd_cents, 0),
+        sponsored_count = cs.sponsored_count + 1,
+        updated_at = CURRENT_TIMESTAMP
+    FROM reservation r
+    WHERE r.customer_reserved
+        AND cs.workspace_id = r.workspace_id
+        AND cs.customer_id = r.customer_id
+        AND cs.period_start = r.period_start
+    RETURNING cs.customer_id
+), entry AS (
+    INSERT INTO gas_sponsorship_ledger (
+        workspace_id,
+        reservation_id,
+        customer_id,
+        payment_id,
+        entry_type,
+        amount_usd_cents,
+        budget_spent_cents,
+        budget_reserved_cents,
+        monthly_budget_usd_cents
+    )
+    SELECT r.workspace_id, r.id, r.customer_id, r.payment_id, 'settle', r.settled_usd_cents, COALESCE(b.current_month_spent_cents, 0), b.current_month_reserved_cents, b.monthly_budget_usd_cents
+    FROM reservation r
+    JOIN budget b ON b.workspace_id = r.workspace_id
+)
+SELECT * FROM reservation;
+
+-- name: ReleaseGasSponsorshipReservation :one
+-- Returns a hold to the workspace budget and the customer's month
+WITH reservation AS (
+    UPDATE gas_sponsorship_reservations
+    SET 
+        status = 'released',
+        release_reason = @release_reason,
+        released_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = @id AND workspace_id = @workspace_id AND status = 'reserved'
+    RETURNING *
+), budget AS (
+    UPDATE gas_sponsorship_configs c
+    SET 
+        current_month_reserved_cents = GREATEST(c.current_month_reserved_cents - r.reserved_usd_cents, 0),
+        updated_at = CURRENT_TIMESTAMP
+    FROM reservation r
+    WHERE c.workspace_id = r.workspace_id
+    RETURNING c.workspace_id, c.current_month_spent_cents, c.current_month_reserved_cents, c.monthly_budget_usd_cents
+), spending AS (
+    UPDATE gas_sponsorship_customer_spending cs
+    SET 
+        reserved_usd_cents = GREATEST(cs.reserved_usd_cents - r.reserved_usd_cents, 0),
+        updated_at = CURRENT_TIMESTAMP
+    FROM reservation r
+    WHERE r.customer_reserved
+        AND cs.workspace_id = r.workspace_id
+        AND cs.customer_id = r.customer_id
+        AND cs.period_start = r.period_start
+    RETURNING cs.customer_id
+), entry AS (
+    INSERT INTO gas_sponsorship_ledger (
+        workspace_id,
+        reservation_id,
+        customer_id,
+        payment_id,
+        entry_type,
+        amount_usd_cents,
+        budget_spent_cents,
+        budget_reserved_cents,
+        monthly_budget_usd_cents,
+        reason
+    )
+    SELECT r.workspace_id, r.id, r.customer_id, r.payment_id, 'release', r.reserved_usd_cents, COALESCE(b.current_month_spent_cents, 0), b.current_month_reserved_cents, b.monthly_budget_usd_cents, r.release_reason
+    FROM reservation r
+    JOIN budget b ON b.workspace_id = r.workspace_id
+)
+SELECT * FROM reservation;
+
+-- name: AttachGasSponsorshipReservationPayment :one
+UPDATE gas_sponsorship_reservations
+SET 
+    payment_id = @payment_id,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id AND workspace_id = @workspace_id AND status = 'reserved'
+RETURNING *;
+
+-- name: GetGasSponsorshipReservation :one
+SELECT * FROM gas_sponsorship_reservations
+WHERE id = @id AND workspace_id = @workspace_id;
+
+-- name: GetOpenGasSponsorshipReservationByPayment :one
+SELECT * FROM gas_sponsorship_reservations
+WHERE payment_id = @payment_id AND workspace_id = @workspace_id AND status = 'reserved'
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListExpiredGasSponsorshipReservations :many
+SELECT * FROM gas_sponsorship_reservations
+WHERE status = 'reserved' AND expires_at < @expires_before
+ORDER BY expires_at
+LIMIT sqlc.arg('limit');
+
+-- name: ListGasSponsorshipLedgerEntries :many
+SELECT * FROM gas_sponsorship_ledger
+WHERE workspace_id = @workspace_id
+ORDER BY created_at DESC, id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountGasSponsorshipLedgerEntries :one
+SELECT COUNT(*) FROM gas_sponsorship_ledger
+WHERE workspace_id = @workspace_id;
+
+-- name: RecordGasSponsorshipBudgetAlert :one
+-- Returns no row when the threshold was already alerted on for the month
+INSERT INTO gas_sponsorship_budget_alerts (
+    workspace_id,
+    period_start,
+    threshold_percent,
+    spent_usd_cents,
+    monthly_budget_usd_cents
+) VALUES (
+    @workspace_id, @period_start, @threshold_percent, @spent_usd_cents, @monthly_budget_usd_cents
+)
+ON CONFLICT (workspace_id, period_start, threshold_percent) DO NOTHING
+RETURNING *;
+
+-- name: ListGasSponsorshipBudgetAlerts :many
+SELECT * FROM gas_sponsorship_budget_alerts
+WHERE workspace_id = @workspace_id AND period_start = @period_start
+ORDER BY threshold_percent;
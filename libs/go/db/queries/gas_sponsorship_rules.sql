@@ -0,0 +1,68 @@
+-- name: CreateGasSponsorshipRule :one
+INSERT INTO gas_sponsorship_rules (
+    workspace_id,
+    name,
+    priority,
+    is_active,
+    conditions,
+    action_type,
+    sponsor_up_to_usd_cents,
+    sponsor_percentage
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: GetGasSponsorshipRule :one
+SELECT * FROM gas_sponsorship_rules
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL;
+
+-- name: ListGasSponsorshipRules :many
+SELECT * FROM gas_sponsorship_rules
+WHERE workspace_id = $1 AND deleted_at IS NULL
+ORDER BY priority ASC, created_at ASC;
+
+-- name: ListActiveGasSponsorshipRules :many
+SELECT * FROM gas_sponsorship_rules
+WHERE workspace_id = $1 AND is_active = true AND deleted_at IS NULL
+ORDER BY priority ASC, created_at ASC;
+
+-- name: UpdateGasSponsorshipRule :one
+UPDATE gas_sponsorship_rules
+SET
+    name = $3,
+    priority = $4,
+    is_active = $5,
+    conditions = $6,
+    action_type = $7,
+    sponsor_up_to_usd_cents = $8,
+    sponsor_percentage = $9,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteGasSponsorshipRule :execrows
+UPDATE gas_sponsorship_rules
+SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL;
+
+-- name: GetGasSponsorshipCustomerSpending :one
+SELECT * FROM gas_sponsorship_customer_spending
+WHERE workspace_id = $1 AND customer_id = $2 AND period_start = $3;
+
+-- name: AddGasSponsorshipCustomerSpending :one
+INSERT INTO gas_sponsorship_customer_spending (
+    workspace_id,
+    customer_id,
+    period_start,
+    spent_usd_cents,
+    sponsored_count
+) VALUES (
+    $1, $2, $3, $4, 1
+)
+ON CONFLICT (workspace_id, customer_id, period_start)
+DO UPDATE SET
+    spent_usd_cents = gas_sponsorship_customer_spending.spent_usd_cents + EXCLUDED.spent_usd_cents,
+    sponsored_count = gas_sponsorship_customer_spending.sponsored_count + 1,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
@@ -436,6 +436,19 @@ AND status = 'open'
 AND deleted_at IS NULL
 RETURNING *;
 
+-- name: ReopenPaidInvoice :one
+-- Undoes MarkInvoicePaid when the payment behind it is rolled back
+UPDATE invoices SET
+    status = 'open',
+    amount_paid = 0,
+    amount_remaining = amount_due,
+    paid_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2
+AND status = 'paid'
+AND deleted_at IS NULL
+RETURNING *;
+
 -- name: GetPendingInvoicesForGeneration :many
 SELECT DISTINCT
     s.id as subscription_id,
@@ -473,6 +486,21 @@ UPDATE invoices SET
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
+-- name: UpdateInvoiceTaxProvider :one
+UPDATE invoices SET
+    tax_provider = $3,
+    tax_provider_transaction_id = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateInvoiceTaxIDVerification :one
+UPDATE invoices SET
+    tax_id_verification_id = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
 -- name: UpdateInvoiceMetadata :one
 UPDATE invoices SET
     metadata = metadata || sqlc.arg(metadata)::jsonb,
@@ -0,0 +1,111 @@
+-- name: CreateMRRMovement :one
+INSERT INTO mrr_movements (
+    workspace_id,
+    subscription_id,
+    customer_id,
+    movement_type,
+    source,
+    interval_type,
+    currency,
+    from_amount_cents,
+    to_amount_cents,
+    from_mrr_cents,
+    to_mrr_cents,
+    mrr_delta_cents,
+    reporting_currency,
+    exchange_rate,
+    reporting_mrr_delta_cents,
+    schedule_change_id,
+    occurred_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+)
+RETURNING *;
+
+-- name: ListPendingMRRMovements :many
+-- Movements still waiting for an exchange rate to the reporting currency
+SELECT * FROM mrr_movements
+WHERE reporting_mrr_delta_cents IS NULL
+ORDER BY created_at
+LIMIT $1;
+
+-- name: SetMRRMovementExchangeRate :exec
+UPDATE mrr_movements
+SET exchange_rate = @exchange_rate,
+    reporting_mrr_delta_cents = @reporting_mrr_delta_cents
+WHERE id = @id;
+
+-- name: ListMRRMovements :many
+SELECT
+    m.id,
+    m.subscription_id,
+    m.customer_id,
+    c.name AS customer_name,
+    c.email AS customer_email,
+    p.name AS product_name,
+    m.movement_type,
+    m.source,
+    m.interval_type,
+    m.currency,
+    m.from_amount_cents,
+    m.to_amount_cents,
+    m.from_mrr_cents,
+    m.to_mrr_cents,
+    m.mrr_delta_cents,
+    m.reporting_currency,
+    m.exchange_rate,
+    m.reporting_mrr_delta_cents,
+    m.occurred_at
+FROM mrr_movements m
+JOIN subscriptions s ON s.id = m.subscription_id
+JOIN customers c ON c.id = m.customer_id
+JOIN products p ON p.id = s.product_id
+WHERE m.workspace_id = @workspace_id
+    AND m.reporting_currency = @reporting_currency
+    AND m.occurred_at >= @start_date
+    AND m.occurred_at < @end_date
+    AND (sqlc.narg('movement_type')::text IS NULL OR m.movement_type = sqlc.narg('movement_type')::text)
+ORDER BY m.occurred_at DESC, m.id
+LIMIT @row_limit OFFSET @row_offset;
+
+-- name: CountMRRMovements :one
+SELECT COUNT(*) FROM mrr_movements
+WHERE workspace_id = @workspace_id
+    AND reporting_currency = @reporting_currency
+    AND occurred_at >= @start_date
+    AND occurred_at < @end_date
+    AND (sqlc.narg('movement_type')::text IS NULL OR movement_type = sqlc.narg('movement_type')::text);
+
+-- name: GetMRRMovementTotals :many
+-- Net MRR change per movement type; pending movements are counted but not summed
+SELECT
+    movement_type,
+    COUNT(*)::bigint AS movement_count,
+    COUNT(*) FILTER (WHERE reporting_mrr_delta_cents IS NULL)::bigint AS pending_count,
+    COALESCE(SUM(reporting_mrr_delta_cents), 0)::bigint AS mrr_delta_cents
+FROM mrr_movements
+WHERE workspace_id = @workspace_id
+    AND reporting_currency = @reporting_currency
+    AND occurred_at >= @start_date
+    AND occurred_at < @end_date
+GROUP BY movement_type
+ORDER BY movement_type;
+
+-- name: GetMRRMovementsByPeriod :many
+SELECT
+    date_trunc(@period::text, occurred_at)::timestamptz AS period_start,
+    movement_type,
+    COALESCE(SUM(reporting_mrr_delta_cents), 0)::bigint AS mrr_delta_cents
+FROM mrr_movements
+WHERE workspace_id = @workspace_id
+    AND reporting_currency = @reporting_currency
+    AND occurred_at >= @start_date
+    AND occurred_at < @end_date
+GROUP BY 1, movement_type
+ORDER BY 1, movement_type;
+
+-- name: GetLatestMRRMovement :one
+SELECT * FROM mrr_movements
+WHERE subscription_id = $1
+ORDER BY occurred_at DESC, created_at DESC
+LIMIT 1;
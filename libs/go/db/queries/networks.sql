@@ -35,9 +35,11 @@ INSERT INTO networks (
     gas_refresh_interval_ms,
     gas_priority_levels,
     average_block_time_ms,
-    peak_hours_multiplier
+    peak_hours_multiplier,
+    confirmation_blocks,
+    finality_blocks
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
 )
 RETURNING *;
 
@@ -65,6 +67,8 @@ SET
     gas_priority_levels = COALESCE($20, gas_priority_levels),
     average_block_time_ms = COALESCE($21, average_block_time_ms),
     peak_hours_multiplier = COALESCE($22, peak_hours_multiplier),
+    confirmation_blocks = COALESCE(sqlc.narg('confirmation_blocks'), confirmation_blocks),
+    finality_blocks = COALESCE(sqlc.narg('finality_blocks'), finality_blocks),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
@@ -0,0 +1,70 @@
+-- name: TrackNewPaymentConfirmations :execrows
+-- Starts following the transactions of crypto payments recorded since created_after
+INSERT INTO payment_confirmations (
+    payment_id,
+    network_id,
+    transaction_hash,
+    last_seen_at
+)
+SELECT p.id, p.network_id, p.transaction_hash, p.created_at
+FROM payments p
+WHERE p.transaction_hash IS NOT NULL
+    AND p.network_id IS NOT NULL
+    AND p.status = 'completed'
+    AND p.created_at >= @created_after
+ON CONFLICT (payment_id) DO NOTHING;
+
+-- name: ListPaymentConfirmationsToCheck :many
+-- Lists the transactions that have not reached finality and were not checked since checked_before,
+-- with the thresholds of their network and what is needed to roll their payment back
+SELECT
+    pc.payment_id,
+    pc.network_id,
+    pc.transaction_hash,
+    pc.status,
+    pc.sender_address,
+    pc.nonce,
+    pc.block_number,
+    pc.block_hash,
+    pc.last_seen_at,
+    p.workspace_id,
+    p.subscription_id,
+    p.invoice_id,
+    p.product_amount_cents,
+    p.initiated_at,
+    n.confirmation_blocks,
+    n.finality_blocks
+FROM payment_confirmations pc
+JOIN payments p ON p.id = pc.payment_id
+JOIN networks n ON n.id = pc.network_id
+WHERE pc.status IN ('pending', 'confirmed')
+    AND (pc.checked_at IS NULL OR pc.checked_at <= @checked_before)
+ORDER BY pc.checked_at NULLS FIRST
+LIMIT @batch_size;
+
+-- name: GetPaymentConfirmation :one
+SELECT * FROM payment_confirmations
+WHERE payment_id = $1;
+
+-- name: UpdatePaymentConfirmation :one
+-- Records the outcome of a check. Only unsettled confirmations change, so a transaction that was already
+-- finalized or rolled back by another run is left alone
+UPDATE payment_confirmations
+SET
+    status = @status,
+    sender_address = COALESCE(sqlc.narg('sender_address'), sender_address),
+    nonce = COALESCE(sqlc.narg('nonce'), nonce),
+    reorg_count = reorg_count + CASE WHEN block_hash IS NOT NULL AND block_hash IS DISTINCT FROM sqlc.narg('block_hash') THEN 1 ELSE 0 END,
+    block_number = sqlc.narg('block_number'),
+    block_hash = sqlc.narg('block_hash'),
+    confirmations = @confirmations,
+    last_seen_at = COALESCE(sqlc.narg('last_seen_at'), last_seen_at),
+    checked_at = NOW(),
+    confirmed_at = CASE WHEN @status IN ('confirmed', 'finalized') THEN COALESCE(confirmed_at, NOW()) ELSE NULL END,
+    finalized_at = CASE WHEN @status = 'finalized' THEN NOW() ELSE NULL END,
+    rolled_back_at = CASE WHEN @status IN ('dropped', 'replaced', 'reverted') THEN NOW() ELSE NULL END,
+    error_message = sqlc.narg('error_message')
+WHERE payment_id = @payment_id
+    AND status IN ('pending', 'confirmed')
+RETURNING *;
+
@@ -35,8 +35,10 @@ SELECT * FROM payments
 WHERE id = $1 AND workspace_id = $2;
 
 -- name: GetPaymentByTransactionHash :one
+-- A batched redemption's hash is shared by one payment per subscription event, so the event is matched too
 SELECT * FROM payments
-WHERE transaction_hash = $1;
+WHERE transaction_hash = @transaction_hash
+    AND subscription_event IS NOT DISTINCT FROM @subscription_event;
 
 -- name: GetPaymentsByTransactionHash :many
 SELECT * FROM payments
@@ -77,6 +79,7 @@ SET
     status = $3,
     completed_at = CASE WHEN $3 = 'completed' THEN CURRENT_TIMESTAMP ELSE completed_at END,
     failed_at = CASE WHEN $3 = 'failed' THEN CURRENT_TIMESTAMP ELSE failed_at END,
+    refunded_at = CASE WHEN $3 = 'refunded' THEN COALESCE(refunded_at, CURRENT_TIMESTAMP) ELSE refunded_at END,
     error_message = $4,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2
@@ -136,6 +139,7 @@ RETURNING *;
 UPDATE payments
 SET 
     status = 'refunded',
+    refunded_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND status = 'completed'
 RETURNING *;
@@ -145,6 +149,7 @@ SELECT COUNT(*) FROM payments
 WHERE workspace_id = $1;
 
 -- name: GetPaymentVolume :one
+-- Crypto payments are only counted once their transaction is finalized, since until then they can be rolled back
 SELECT 
     SUM(amount_in_cents) as total_volume_cents,
     COUNT(*) as payment_count
@@ -153,7 +158,10 @@ WHERE workspace_id = $1
     AND status = 'completed'
     AND completed_at >= $2
     AND completed_at < $3
-    AND currency = $4;
+    AND currency = $4
+    AND (payments.transaction_hash IS NULL OR payments.network_id IS NULL OR EXISTS (
+        SELECT 1 FROM payment_confirmations pc WHERE pc.payment_id = payments.id AND pc.status = 'finalized'
+    ));
 
 -- name: GetPaymentsByExternalId :one
 SELECT * FROM payments
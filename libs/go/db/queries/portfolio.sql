@@ -0,0 +1,118 @@
+-- name: UpsertWalletBalance :one
+INSERT INTO wallet_balances (
+    workspace_id,
+    wallet_id,
+    network_id,
+    token_id,
+    balance,
+    source,
+    fiat_currency,
+    exchange_rate,
+    fiat_value_cents,
+    fetched_at,
+    attempted_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10
+)
+ON CONFLICT (wallet_id, network_id, token_id) DO UPDATE SET
+    balance = EXCLUDED.balance,
+    source = EXCLUDED.source,
+    fiat_currency = EXCLUDED.fiat_currency,
+    exchange_rate = EXCLUDED.exchange_rate,
+    fiat_value_cents = EXCLUDED.fiat_value_cents,
+    fetched_at = EXCLUDED.fetched_at,
+    attempted_at = EXCLUDED.attempted_at,
+    error_message = NULL,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: RecordWalletBalanceError :exec
+-- Keeps the last balance read, flagging that the latest refresh could not read it. A balance that was never
+-- read is cached without an amount so the failure still shows up
+INSERT INTO wallet_balances (
+    workspace_id,
+    wallet_id,
+    network_id,
+    token_id,
+    source,
+    fiat_currency,
+    attempted_at,
+    error_message
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (wallet_id, network_id, token_id) DO UPDATE SET
+    attempted_at = EXCLUDED.attempted_at,
+    error_message = EXCLUDED.error_message,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: ListWalletBalancesByWorkspace :many
+-- Cached balances of a workspace's wallets, or of one of them, in active tokens on active networks
+SELECT
+    wb.id,
+    wb.wallet_id,
+    wb.network_id,
+    wb.token_id,
+    wb.balance,
+    wb.source,
+    wb.fiat_currency,
+    wb.exchange_rate,
+    wb.fiat_value_cents,
+    wb.fetched_at,
+    wb.attempted_at,
+    wb.error_message,
+    w.wallet_address,
+    w.wallet_type,
+    w.nickname,
+    n.name AS network_name,
+    n.chain_id,
+    t.symbol AS token_symbol,
+    t.name AS token_name,
+    t.contract_address AS token_address,
+    t.decimals AS token_decimals
+FROM wallet_balances wb
+JOIN wallets w ON w.id = wb.wallet_id AND w.deleted_at IS NULL
+JOIN networks n ON n.id = wb.network_id AND n.active = true AND n.deleted_at IS NULL
+JOIN tokens t ON t.id = wb.token_id AND t.active = true AND t.deleted_at IS NULL
+WHERE wb.workspace_id = @workspace_id
+    AND (sqlc.narg('wallet_id')::uuid IS NULL OR wb.wallet_id = sqlc.narg('wallet_id'))
+ORDER BY w.created_at, n.name, t.symbol;
+
+-- name: UpsertPortfolioSnapshot :one
+INSERT INTO portfolio_snapshots (
+    workspace_id,
+    snapshot_at,
+    fiat_currency,
+    total_value_cents,
+    holdings
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (workspace_id, snapshot_at) DO UPDATE SET
+    fiat_currency = EXCLUDED.fiat_currency,
+    total_value_cents = EXCLUDED.total_value_cents,
+    holdings = EXCLUDED.holdings,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListPortfolioSnapshots :many
+SELECT * FROM portfolio_snapshots
+WHERE workspace_id = @workspace_id
+    AND snapshot_at >= @start_time::timestamptz
+    AND snapshot_at <= @end_time::timestamptz
+ORDER BY snapshot_at ASC;
+
+-- name: ListWorkspacesDuePortfolioSnapshot :many
+-- Workspaces with wallets that have no snapshot for the current period yet
+SELECT ws.id FROM workspaces ws
+WHERE ws.deleted_at IS NULL
+    AND EXISTS (
+        SELECT 1 FROM wallets w
+        WHERE w.workspace_id = ws.id AND w.deleted_at IS NULL
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM portfolio_snapshots ps
+        WHERE ps.workspace_id = ws.id AND ps.snapshot_at = @snapshot_at::timestamptz
+    )
+ORDER BY ws.id
+LIMIT @batch_size;
@@ -0,0 +1,28 @@
+-- name: GetRateLimitQuota :one
+-- Returns the most specific quota for a workspace: key-level rows win over workspace-level
+-- rows, and class-specific rows win over rows that apply to every route class
+SELECT * FROM rate_limit_quotas
+WHERE workspace_id = @workspace_id
+    AND (api_key_id = sqlc.narg('api_key_id') OR api_key_id IS NULL)
+    AND (route_class = @route_class::text OR route_class IS NULL)
+ORDER BY api_key_id NULLS LAST, route_class NULLS LAST
+LIMIT 1;
+
+-- name: IncrementRateLimitCounter :one
+-- Atomically counts a request against a fixed window and returns the new total
+INSERT INTO rate_limit_counters (
+    bucket_key,
+    window_start,
+    request_count,
+    expires_at
+) VALUES (
+    $1, $2, 1, $3
+)
+ON CONFLICT (bucket_key, window_start)
+DO UPDATE SET
+    request_count = rate_limit_counters.request_count + 1
+RETURNING request_count;
+
+-- name: DeleteExpiredRateLimitCounters :exec
+DELETE FROM rate_limit_counters
+WHERE expires_at < CURRENT_TIMESTAMP;
@@ -0,0 +1,150 @@
+-- name: ClaimRedemptionTasks :many
+-- Claims the next visible tasks, highest priority first, and hides them from other workers until visible_until.
+-- Processing tasks become visible again when their worker stops before finishing them
+UPDATE redemption_tasks
+SET
+    status = 'processing',
+    attempts = attempts + 1,
+    locked_by = @locked_by,
+    visible_at = @visible_until
+WHERE id IN (
+    SELECT t.id FROM redemption_tasks t
+    WHERE t.status IN ('pending', 'processing')
+        AND t.visible_at <= @now
+    ORDER BY t.priority DESC, t.visible_at
+    LIMIT @batch_size
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteRedemptionTask :one
+UPDATE redemption_tasks
+SET
+    status = 'succeeded',
+    transaction_hash = @transaction_hash,
+    locked_by = NULL,
+    last_error = NULL,
+    completed_at = NOW()
+WHERE id = @id
+    AND locked_by = @locked_by
+    AND status = 'processing'
+RETURNING *;
+
+-- name: CountRedemptionTasks :one
+SELECT COUNT(*) FROM redemption_tasks
+WHERE (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status));
+
+-- name: DeadLetterRedemptionTask :one
+-- Stops retrying a task; it stays in the queue until an admin requeues it
+UPDATE redemption_tasks
+SET
+    status = 'dead_lettered',
+    last_error = @last_error,
+    locked_by = NULL,
+    dead_lettered_at = NOW()
+WHERE id = @id
+    AND locked_by = @locked_by
+    AND status = 'processing'
+RETURNING *;
+
+-- name: DeferRedemptionTask :one
+-- Hands a task back without counting the attempt, for when it could not be tried at all
+UPDATE redemption_tasks
+SET
+    status = 'pending',
+    attempts = GREATEST(attempts - 1, 0),
+    visible_at = @visible_at,
+    locked_by = NULL,
+    last_error = @last_error
+WHERE id = @id
+    AND locked_by = @locked_by
+    AND status = 'processing'
+RETURNING *;
+
+-- name: EnqueueRedemptionTask :one
+-- Enqueuing a task with an idempotency key that is already queued returns the existing task
+INSERT INTO redemption_tasks (
+    subscription_id,
+    delegation_id,
+    product_id,
+    product_token_id,
+    amount_in_cents,
+    metadata,
+    idempotency_key,
+    priority,
+    max_attempts,
+    visible_at
+) VALUES (
+    @subscription_id,
+    @delegation_id,
+    @product_id,
+    @product_token_id,
+    @amount_in_cents,
+    @metadata,
+    @idempotency_key,
+    @priority,
+    @max_attempts,
+    @visible_at
+)
+ON CONFLICT (idempotency_key) DO UPDATE
+SET idempotency_key = EXCLUDED.idempotency_key
+RETURNING *;
+
+-- name: GetRedemptionTask :one
+SELECT * FROM redemption_tasks
+WHERE id = $1;
+
+-- name: GetRedemptionTaskStats :many
+-- Number of tasks per status; ready_count only counts pending tasks that can be claimed now
+SELECT
+    status,
+    COUNT(*) AS task_count,
+    COUNT(*) FILTER (WHERE status = 'pending' AND visible_at <= @now) AS ready_count,
+    MIN(created_at)::timestamptz AS oldest_created_at
+FROM redemption_tasks
+GROUP BY status
+ORDER BY status;
+
+-- name: ListRedemptionTasks :many
+SELECT * FROM redemption_tasks
+WHERE (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: MarkRedemptionTaskRedeeming :one
+-- Records that the delegation is about to be redeemed, so a task whose worker stops mid-redemption is not redeemed again
+UPDATE redemption_tasks
+SET redemption_started_at = NOW()
+WHERE id = @id
+    AND locked_by = @locked_by
+    AND status = 'processing'
+RETURNING *;
+
+-- name: RequeueRedemptionTask :one
+-- Makes a pending or dead-lettered task claimable straight away with a fresh set of attempts
+UPDATE redemption_tasks
+SET
+    status = 'pending',
+    attempts = 0,
+    priority = COALESCE(sqlc.narg(priority), priority),
+    visible_at = NOW(),
+    locked_by = NULL,
+    redemption_started_at = NULL,
+    last_error = NULL,
+    dead_lettered_at = NULL
+WHERE id = @id
+    AND status IN ('pending', 'dead_lettered')
+RETURNING *;
+
+-- name: RetryRedemptionTask :one
+UPDATE redemption_tasks
+SET
+    status = 'pending',
+    visible_at = @visible_at,
+    locked_by = NULL,
+    redemption_started_at = NULL,
+    last_error = @last_error
+WHERE id = @id
+    AND locked_by = @locked_by
+    AND status = 'processing'
+RETURNING *;
@@ -0,0 +1,31 @@
+-- name: CreateSplApproval :one
+INSERT INTO spl_approvals (
+    signature,
+    delegation_id,
+    product_token_id,
+    token_account,
+    owner,
+    slot
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetSplApprovalBySignature :one
+SELECT * FROM spl_approvals
+WHERE signature = $1;
+
+-- name: GetSplApprovalByDelegationID :one
+SELECT * FROM spl_approvals
+WHERE delegation_id = $1;
+
+-- name: GetActiveSplApprovalByTokenAccount :one
+-- A token account has a single delegate approval, so a new approval on an account that already pays for a
+-- subscription replaces the approval that subscription renews with
+SELECT sa.* FROM spl_approvals sa
+JOIN subscriptions s ON s.delegation_id = sa.delegation_id
+WHERE sa.token_account = @token_account
+    AND s.status NOT IN ('canceled', 'expired', 'failed', 'completed')
+    AND s.deleted_at IS NULL
+    AND s.id IS DISTINCT FROM sqlc.narg('subscription_id')
+LIMIT 1;
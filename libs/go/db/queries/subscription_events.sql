@@ -6,6 +6,14 @@ WHERE id = $1 LIMIT 1;
 SELECT * FROM subscription_events
 WHERE transaction_hash = $1 LIMIT 1;
 
+-- name: GetSubscriptionRedemptionEventByTransactionHash :one
+-- A batched redemption shares its transaction hash with other subscriptions, so the subscription is matched too
+SELECT * FROM subscription_events
+WHERE subscription_id = @subscription_id
+    AND transaction_hash = @transaction_hash
+    AND event_type = 'redeem'
+LIMIT 1;
+
 -- name: ListSubscriptionEvents :many
 SELECT * FROM subscription_events
 ORDER BY occurred_at DESC;
@@ -0,0 +1,72 @@
+-- name: CompleteSubscriptionReauthorization :one
+UPDATE subscription_reauthorizations
+SET
+    status = 'completed',
+    replacement_delegation_id = $2,
+    completed_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: GetPendingSubscriptionReauthorization :one
+SELECT * FROM subscription_reauthorizations
+WHERE subscription_id = $1 AND status = 'pending';
+
+-- name: ListPendingSubscriptionReauthorizationsByCustomer :many
+SELECT * FROM subscription_reauthorizations
+WHERE customer_id = $1 AND status = 'pending'
+ORDER BY created_at DESC;
+
+-- name: ListSubscriptionReauthorizationsToNotify :many
+-- Pending reauthorizations of live subscriptions whose customer has not been emailed since the cutoff and has
+-- not yet received every reminder, oldest first
+SELECT
+    r.id,
+    r.subscription_id,
+    r.workspace_id,
+    r.customer_id,
+    r.reason,
+    r.notification_count,
+    c.email AS customer_email,
+    c.name AS customer_name,
+    w.name AS workspace_name,
+    p.name AS product_name
+FROM subscription_reauthorizations r
+JOIN subscriptions s ON s.id = r.subscription_id
+JOIN customers c ON c.id = r.customer_id
+JOIN workspaces w ON w.id = r.workspace_id
+JOIN products p ON p.id = s.product_id
+WHERE r.status = 'pending'
+    AND (r.notified_at IS NULL OR r.notified_at < sqlc.arg(notified_before))
+    AND r.notification_count < sqlc.arg(max_notifications)
+    AND s.deleted_at IS NULL
+    AND s.status IN ('active', 'trial', 'overdue')
+    AND c.email IS NOT NULL
+ORDER BY r.created_at ASC
+LIMIT sqlc.arg(batch_size);
+
+-- name: MarkSubscriptionReauthorizationNotified :exec
+UPDATE subscription_reauthorizations
+SET
+    notification_count = notification_count + 1,
+    notified_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: OpenSubscriptionReauthorization :one
+-- Opens a reauthorization for a subscription, or refreshes the reason of the one already pending
+INSERT INTO subscription_reauthorizations (
+    subscription_id,
+    workspace_id,
+    customer_id,
+    delegation_id,
+    reason
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (subscription_id) WHERE status = 'pending' DO UPDATE
+SET
+    delegation_id = EXCLUDED.delegation_id,
+    reason = EXCLUDED.reason,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
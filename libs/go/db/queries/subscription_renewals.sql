@@ -0,0 +1,209 @@
+-- name: ClaimDueSubscriptionRenewals :many
+-- Leases due subscriptions to one worker by opening (or re-opening) the renewal of their current period.
+-- Periods that already renewed, need review or are leased to another worker are skipped, so overlapping
+-- runs never renew the same period twice
+WITH due AS (
+    SELECT s.id, s.next_redemption_date
+    FROM subscriptions s
+    WHERE (s.status = 'active' OR s.status = 'overdue')
+        AND s.next_redemption_date <= @now
+        AND s.deleted_at IS NULL
+        AND NOT EXISTS (
+            SELECT 1 FROM subscription_renewals r
+            WHERE r.subscription_id = s.id
+                AND r.period_due_at = s.next_redemption_date
+                AND (r.status IN ('succeeded', 'interrupted') OR r.lease_expires_at > @now)
+        )
+    ORDER BY s.next_redemption_date
+    LIMIT @batch_size
+    FOR UPDATE OF s SKIP LOCKED
+)
+INSERT INTO subscription_renewals (
+    subscription_id,
+    idempotency_key,
+    period_due_at,
+    status,
+    attempts,
+    lease_owner,
+    lease_expires_at
+)
+SELECT
+    due.id,
+    'renewal:' || due.id::text || ':' || EXTRACT(EPOCH FROM due.next_redemption_date)::bigint::text,
+    due.next_redemption_date,
+    'claimed',
+    1,
+    @lease_owner,
+    @lease_expires_at
+FROM due
+ON CONFLICT (idempotency_key) DO UPDATE
+SET
+    status = CASE WHEN subscription_renewals.status = 'failed' THEN 'claimed' ELSE subscription_renewals.status END,
+    attempts = subscription_renewals.attempts + 1,
+    lease_owner = EXCLUDED.lease_owner,
+    lease_expires_at = EXCLUDED.lease_expires_at,
+    error_message = NULL
+WHERE subscription_renewals.status NOT IN ('succeeded', 'interrupted')
+    AND (subscription_renewals.lease_expires_at IS NULL OR subscription_renewals.lease_expires_at <= @now)
+RETURNING *;
+
+-- name: ClaimRedeemedSubscriptionRenewals :many
+-- Leases renewals whose redemption went through but whose worker stopped before recording it,
+-- whether or not the subscription is still due
+UPDATE subscription_renewals
+SET
+    attempts = attempts + 1,
+    lease_owner = @lease_owner,
+    lease_expires_at = @lease_expires_at
+WHERE id IN (
+    SELECT r.id FROM subscription_renewals r
+    WHERE r.status = 'redeemed'
+        AND (r.lease_expires_at IS NULL OR r.lease_expires_at <= @now)
+    ORDER BY r.period_due_at
+    LIMIT @batch_size
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: GetSubscriptionRenewalByIdempotencyKey :one
+SELECT * FROM subscription_renewals
+WHERE idempotency_key = $1;
+
+-- name: GetSubscriptionRenewal :one
+SELECT * FROM subscription_renewals
+WHERE id = $1;
+
+-- name: ListSubscriptionRenewals :many
+SELECT * FROM subscription_renewals
+WHERE (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+ORDER BY updated_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSubscriptionRenewals :one
+SELECT COUNT(*) FROM subscription_renewals
+WHERE (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status));
+
+-- name: ListSubscriptionRenewalsBySubscription :many
+SELECT * FROM subscription_renewals
+WHERE subscription_id = $1
+ORDER BY period_due_at DESC
+LIMIT $2;
+
+-- name: MarkSubscriptionRenewalRedeeming :one
+-- Records that the redemption is about to be sent; fails if the lease was lost to another worker
+UPDATE subscription_renewals
+SET status = 'redeeming'
+WHERE id = @id
+    AND lease_owner = @lease_owner
+    AND status = 'claimed'
+RETURNING *;
+
+-- name: MarkSubscriptionRenewalRedeemed :one
+-- Records the redemption transaction so an interrupted renewal resumes without redeeming again
+UPDATE subscription_renewals
+SET
+    status = 'redeemed',
+    transaction_hash = @transaction_hash
+WHERE id = @id
+    AND lease_owner = @lease_owner
+    AND status = 'redeeming'
+RETURNING *;
+
+-- name: CompleteSubscriptionRenewal :one
+UPDATE subscription_renewals
+SET
+    status = 'succeeded',
+    lease_owner = NULL,
+    lease_expires_at = NULL,
+    completed_at = NOW()
+WHERE id = @id
+    AND lease_owner = @lease_owner
+RETURNING *;
+
+-- name: FailSubscriptionRenewal :execrows
+-- Only renewals that never recorded a redemption fail; a redeemed renewal keeps its transaction for the next run.
+-- The lease is kept, so the period is retried once it runs out
+UPDATE subscription_renewals
+SET
+    status = 'failed',
+    error_message = @error_message
+WHERE id = @id
+    AND lease_owner = @lease_owner
+    AND status IN ('claimed', 'redeeming');
+
+-- name: InterruptSubscriptionRenewal :one
+-- Parks a renewal whose redemption outcome is unknown, or that ran out of attempts, so it is not redeemed again without review
+UPDATE subscription_renewals
+SET
+    status = 'interrupted',
+    error_message = @error_message,
+    lease_owner = NULL,
+    lease_expires_at = NULL
+WHERE id = @id
+    AND lease_owner = @lease_owner
+RETURNING *;
+
+-- name: FailRevertedSubscriptionRenewal :execrows
+-- Fails a redeemed renewal whose transaction reverted on-chain, so the customer was not charged. The lease is kept,
+-- so the period is retried once it runs out
+UPDATE subscription_renewals
+SET
+    status = 'failed',
+    error_message = @error_message,
+    transaction_hash = NULL
+WHERE id = @id
+    AND lease_owner = @lease_owner
+    AND status = 'redeemed';
+
+-- name: RequeueSubscriptionRenewal :one
+-- Returns an interrupted renewal whose redemption never went through to the renewal engine with a fresh set of
+-- attempts, so the next claim renews the period again
+UPDATE subscription_renewals
+SET
+    status = 'failed',
+    error_message = @error_message,
+    transaction_hash = NULL,
+    attempts = 0,
+    lease_owner = NULL,
+    lease_expires_at = NULL
+WHERE id = @id
+    AND status = 'interrupted'
+RETURNING *;
+
+-- name: ResolveSubscriptionRenewal :one
+-- Records the transaction an interrupted renewal's redemption went through with, so the next run records the
+-- payment instead of redeeming again
+UPDATE subscription_renewals
+SET
+    status = 'redeemed',
+    transaction_hash = @transaction_hash,
+    error_message = NULL,
+    lease_owner = NULL,
+    lease_expires_at = NULL
+WHERE id = @id
+    AND status = 'interrupted'
+RETURNING *;
+
+-- name: ReopenSubscriptionRenewalByTransaction :one
+-- Fails the succeeded renewal that was paid by a transaction which did not make it on-chain, so the next
+-- claim renews the period again
+UPDATE subscription_renewals
+SET
+    status = 'failed',
+    error_message = @error_message,
+    transaction_hash = NULL,
+    lease_owner = NULL,
+    lease_expires_at = NULL,
+    completed_at = NULL
+WHERE subscription_id = @subscription_id
+    AND transaction_hash = @transaction_hash
+    AND status = 'succeeded'
+RETURNING *;
+
+-- name: ReleaseSubscriptionRenewalLease :exec
+UPDATE subscription_renewals
+SET
+    lease_owner = NULL,
+    lease_expires_at = NULL
+WHERE id = @id
+    AND lease_owner = @lease_owner;
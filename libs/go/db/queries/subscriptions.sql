@@ -224,6 +224,16 @@ SET
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
+-- name: UpdateSubscriptionPaymentWallet :one
+-- Points a subscription at another customer wallet and the delegation signed by that wallet
+UPDATE subscriptions
+SET
+    customer_wallet_id = $3,
+    delegation_id = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND customer_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
 -- name: IncrementSubscriptionRedemption :one
 UPDATE subscriptions
 SET 
@@ -234,6 +244,19 @@ SET
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
+-- name: RevertSubscriptionRedemption :one
+-- Undoes IncrementSubscriptionRedemption for a redemption whose transaction never made it on-chain. The period
+-- becomes due again from due_at and the subscription is overdue until it is redeemed
+UPDATE subscriptions
+SET
+    total_redemptions = GREATEST(total_redemptions - 1, 0),
+    total_amount_in_cents = GREATEST(total_amount_in_cents - @amount_in_cents, 0),
+    next_redemption_date = LEAST(COALESCE(next_redemption_date, @due_at), @due_at),
+    status = CASE WHEN status IN ('active', 'completed') THEN 'overdue' ELSE status END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id AND deleted_at IS NULL
+RETURNING *;
+
 -- name: GetSubscriptionsByDelegation :many
 SELECT * FROM subscriptions
 WHERE delegation_id = $1 AND deleted_at IS NULL
@@ -0,0 +1,55 @@
+-- name: CreateTaxIDVerification :one
+INSERT INTO tax_id_verifications (
+    customer_id,
+    country_code,
+    tax_id,
+    is_valid,
+    registered_name,
+    registered_address,
+    consultation_number,
+    source,
+    checked_at,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: GetTaxIDVerification :one
+SELECT * FROM tax_id_verifications
+WHERE id = $1;
+
+-- name: GetCachedTaxIDVerification :one
+-- Latest registry answer for a tax ID that has not expired yet
+SELECT * FROM tax_id_verifications
+WHERE country_code = @country_code
+    AND tax_id = @tax_id
+    AND expires_at > @now
+ORDER BY checked_at DESC
+LIMIT 1;
+
+-- name: ListTaxIDVerificationsByCustomer :many
+SELECT * FROM tax_id_verifications
+WHERE customer_id = $1
+ORDER BY checked_at DESC
+LIMIT $2;
+
+-- name: ListCustomersDueForTaxIDVerification :many
+-- Customers in the given billing countries with a tax ID that was never verified or was
+-- last verified before the cutoff
+SELECT * FROM customers
+WHERE tax_id IS NOT NULL
+    AND tax_id <> ''
+    AND UPPER(billing_country) = ANY(@country_codes::text[])
+    AND deleted_at IS NULL
+    AND (tax_id_verified_at IS NULL OR tax_id_verified_at < @verified_before)
+ORDER BY tax_id_verified_at ASC NULLS FIRST
+LIMIT sqlc.arg('limit');
+
+-- name: SetCustomerTaxIDVerified :exec
+UPDATE customers
+SET
+    tax_id_verified = @tax_id_verified,
+    tax_id_verified_at = @tax_id_verified_at,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id AND deleted_at IS NULL;
@@ -0,0 +1,61 @@
+-- name: CreateTaxJurisdiction :one
+INSERT INTO tax_jurisdictions (
+    code,
+    name,
+    jurisdiction_type,
+    country_code,
+    subdivision_code,
+    locality,
+    parent_id,
+    tax_type,
+    is_active
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetTaxJurisdiction :one
+SELECT * FROM tax_jurisdictions
+WHERE id = $1;
+
+-- name: GetTaxJurisdictionByCode :one
+SELECT * FROM tax_jurisdictions
+WHERE code = $1;
+
+-- name: FindTaxJurisdiction :one
+-- Most specific active state, province or country jurisdiction for a location
+SELECT * FROM tax_jurisdictions
+WHERE country_code = @country_code
+    AND (subdivision_code IS NULL OR subdivision_code = sqlc.narg(subdivision_code))
+    AND locality IS NULL
+    AND is_active = true
+ORDER BY subdivision_code IS NULL, code
+LIMIT 1;
+
+-- name: ListLocalTaxJurisdictions :many
+SELECT * FROM tax_jurisdictions
+WHERE parent_id = $1
+    AND locality = $2
+    AND is_active = true
+ORDER BY code;
+
+-- name: ListTaxJurisdictions :many
+SELECT * FROM tax_jurisdictions
+WHERE (sqlc.narg(country_code)::varchar IS NULL OR country_code = sqlc.narg(country_code))
+ORDER BY country_code, code
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: LockTaxJurisdiction :one
+-- Lock a jurisdiction so changes to its rate table are made one at a time
+SELECT * FROM tax_jurisdictions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateTaxJurisdiction :one
+UPDATE tax_jurisdictions
+SET
+    name = COALESCE(sqlc.narg(name), name),
+    tax_type = COALESCE(sqlc.narg(tax_type), tax_type),
+    is_active = COALESCE(sqlc.narg(is_active), is_active)
+WHERE id = @id
+RETURNING *;
@@ -0,0 +1,61 @@
+-- name: CreateTaxRate :one
+INSERT INTO tax_rates (
+    jurisdiction_id,
+    product_type,
+    rate,
+    threshold_cents,
+    effective_from,
+    effective_to,
+    rules_version,
+    description
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: GetTaxRate :one
+SELECT * FROM tax_rates
+WHERE id = $1;
+
+-- name: ListTaxRatesByJurisdiction :many
+SELECT * FROM tax_rates
+WHERE jurisdiction_id = $1
+ORDER BY product_type, effective_from DESC;
+
+-- name: ListEffectiveTaxRates :many
+-- Rates of the given jurisdictions in force at a point in time
+SELECT * FROM tax_rates
+WHERE jurisdiction_id = ANY(@jurisdiction_ids::uuid[])
+    AND effective_from <= @effective_at
+    AND (effective_to IS NULL OR effective_to > @effective_at)
+ORDER BY jurisdiction_id, product_type;
+
+-- name: CountOverlappingTaxRates :one
+-- Rates whose period overlaps a new one. An open-ended rate supersedes the open-ended
+-- rate that started before it, so that one is not counted.
+SELECT COUNT(*) FROM tax_rates
+WHERE jurisdiction_id = @jurisdiction_id
+    AND product_type = @product_type
+    AND (sqlc.narg(effective_to)::timestamptz IS NULL OR effective_from < sqlc.narg(effective_to))
+    AND (effective_to IS NULL OR effective_to > @effective_from)
+    AND NOT (sqlc.narg(effective_to)::timestamptz IS NULL AND effective_to IS NULL AND effective_from < @effective_from);
+
+-- name: SupersedeOpenTaxRate :execrows
+-- Ends the open-ended rate that started before a new rate takes effect
+UPDATE tax_rates
+SET effective_to = @effective_from
+WHERE jurisdiction_id = @jurisdiction_id
+    AND product_type = @product_type
+    AND effective_to IS NULL
+    AND effective_from < @effective_from;
+
+-- name: EndTaxRate :one
+UPDATE tax_rates
+SET effective_to = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteScheduledTaxRate :execrows
+-- Only rates that have not taken effect yet can be deleted; others must be ended
+DELETE FROM tax_rates
+WHERE id = $1 AND effective_from > CURRENT_TIMESTAMP;
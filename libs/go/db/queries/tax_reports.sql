@@ -0,0 +1,72 @@
+-- name: ListTaxReportInvoices :many
+-- Invoices with a tax event in the period: issued, paid or voided. Drafts carry no tax liability.
+SELECT
+    i.id,
+    i.invoice_number,
+    i.customer_id,
+    i.status,
+    i.currency,
+    COALESCE(i.subtotal_cents, 0)::BIGINT AS subtotal_cents,
+    COALESCE(i.discount_cents, 0)::BIGINT AS discount_cents,
+    i.tax_amount_cents,
+    i.tax_details,
+    i.customer_tax_id,
+    COALESCE(i.reverse_charge_applies, false)::BOOLEAN AS reverse_charge_applies,
+    i.created_at AS issued_at,
+    i.paid_at,
+    v.voided_at::TIMESTAMPTZ AS voided_at,
+    c.name AS customer_name,
+    c.business_name AS customer_business_name,
+    c.billing_country AS customer_country,
+    tv.consultation_number
+FROM invoices i
+LEFT JOIN customers c ON c.id = i.customer_id
+LEFT JOIN tax_id_verifications tv ON tv.id = i.tax_id_verification_id
+LEFT JOIN LATERAL (
+    SELECT MAX(a.created_at) AS voided_at
+    FROM invoice_activities a
+    WHERE a.invoice_id = i.id AND a.to_status = 'void'
+) v ON true
+WHERE i.workspace_id = @workspace_id
+    AND i.status <> 'draft'
+    AND i.deleted_at IS NULL
+    AND (
+        (i.created_at >= @period_start AND i.created_at < @period_end)
+        OR (i.paid_at >= @period_start AND i.paid_at < @period_end)
+        OR (v.voided_at >= @period_start AND v.voided_at < @period_end)
+    )
+ORDER BY i.created_at, i.id;
+
+-- name: ListTaxReportRefunds :many
+-- Invoice payments refunded in the period, with the tax details of the invoice they paid
+SELECT
+    p.id AS payment_id,
+    p.amount_in_cents AS refunded_amount_cents,
+    COALESCE(p.tax_amount_cents, 0)::BIGINT AS refunded_tax_cents,
+    p.refunded_at,
+    i.id AS invoice_id,
+    i.invoice_number,
+    i.customer_id,
+    i.currency,
+    i.amount_due,
+    COALESCE(i.subtotal_cents, 0)::BIGINT AS subtotal_cents,
+    COALESCE(i.discount_cents, 0)::BIGINT AS discount_cents,
+    i.tax_amount_cents,
+    i.tax_details,
+    i.customer_tax_id,
+    COALESCE(i.reverse_charge_applies, false)::BOOLEAN AS reverse_charge_applies,
+    i.created_at AS issued_at,
+    c.name AS customer_name,
+    c.business_name AS customer_business_name,
+    c.billing_country AS customer_country,
+    tv.consultation_number
+FROM payments p
+JOIN invoices i ON i.id = p.invoice_id
+LEFT JOIN customers c ON c.id = i.customer_id
+LEFT JOIN tax_id_verifications tv ON tv.id = i.tax_id_verification_id
+WHERE p.workspace_id = @workspace_id
+    AND p.status = 'refunded'
+    AND i.status <> 'void'
+    AND p.refunded_at >= @period_start
+    AND p.refunded_at < @period_end
+ORDER BY p.refunded_at, p.id;
@@ -0,0 +1,163 @@
+-- name: ClaimDueTreasurySweepRules :many
+-- Claims active rules whose interval has elapsed and that have no sweep in flight by starting their run at now,
+-- so overlapping runs never check the same rule. Returns what a run needs to size and submit the transfer
+UPDATE treasury_sweep_rules r
+SET last_run_at = @now
+FROM circle_wallets cw, wallets dw, tokens t
+WHERE r.id IN (
+    SELECT due.id FROM treasury_sweep_rules due
+    JOIN circle_wallets dcw ON dcw.wallet_id = due.source_wallet_id AND dcw.deleted_at IS NULL
+    JOIN wallets sw ON sw.id = due.source_wallet_id AND sw.deleted_at IS NULL
+    JOIN wallets ddw ON ddw.id = due.destination_wallet_id AND ddw.deleted_at IS NULL
+    WHERE due.is_active = true
+        AND due.deleted_at IS NULL
+        AND (due.last_run_at IS NULL OR due.last_run_at + make_interval(mins => due.sweep_interval_minutes) <= @now::timestamptz)
+        AND NOT EXISTS (
+            SELECT 1 FROM treasury_movements m
+            WHERE m.rule_id = due.id AND m.status IN ('awaiting_approval', 'submitted')
+        )
+    ORDER BY due.workspace_id, due.last_run_at NULLS FIRST
+    LIMIT @batch_size
+    FOR UPDATE OF due SKIP LOCKED
+)
+    AND cw.wallet_id = r.source_wallet_id AND cw.deleted_at IS NULL
+    AND dw.id = r.destination_wallet_id AND dw.deleted_at IS NULL
+    AND t.id = r.token_id
+RETURNING
+    r.id,
+    r.workspace_id,
+    r.source_wallet_id,
+    r.destination_wallet_id,
+    r.token_id,
+    r.threshold_amount,
+    r.retained_amount,
+    r.fee_level,
+    cw.circle_wallet_id AS circle_wallet_id,
+    cw.circle_user_id,
+    dw.wallet_address AS destination_address,
+    t.network_id,
+    t.contract_address AS token_address,
+    t.symbol AS token_symbol,
+    t.decimals AS token_decimals,
+    t.gas_token;
+
+-- name: CreateTreasurySweepRule :one
+INSERT INTO treasury_sweep_rules (
+    workspace_id,
+    name,
+    source_wallet_id,
+    destination_wallet_id,
+    token_id,
+    threshold_amount,
+    retained_amount,
+    fee_level,
+    sweep_interval_minutes,
+    is_active
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: GetTreasurySweepRule :one
+SELECT * FROM treasury_sweep_rules
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL;
+
+-- name: ListTreasurySweepRules :many
+SELECT * FROM treasury_sweep_rules
+WHERE workspace_id = $1 AND deleted_at IS NULL
+ORDER BY created_at ASC;
+
+-- name: UpdateTreasurySweepRule :one
+UPDATE treasury_sweep_rules
+SET
+    name = $3,
+    destination_wallet_id = $4,
+    threshold_amount = $5,
+    retained_amount = $6,
+    fee_level = $7,
+    sweep_interval_minutes = $8,
+    is_active = $9,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteTreasurySweepRule :execrows
+UPDATE treasury_sweep_rules
+SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL;
+
+-- name: RecordTreasurySweepRuleRun :exec
+UPDATE treasury_sweep_rules
+SET last_run_at = $2, last_run_result = $3
+WHERE id = $1;
+
+-- name: CreateTreasuryMovement :one
+INSERT INTO treasury_movements (
+    workspace_id,
+    rule_id,
+    network_id,
+    token_id,
+    source_wallet_id,
+    destination_wallet_id,
+    amount,
+    source_balance,
+    estimated_fee
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: SetTreasuryMovementChallenge :exec
+UPDATE treasury_movements
+SET challenge_id = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: FailTreasuryMovement :exec
+UPDATE treasury_movements
+SET status = 'failed', error_message = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListOpenTreasuryMovements :many
+-- Sweeps awaiting approval or confirmation, with the Circle wallet and user they were sent from
+SELECT
+    m.id,
+    m.workspace_id,
+    m.amount,
+    m.status,
+    m.created_at,
+    cw.circle_wallet_id AS circle_wallet_id,
+    cw.circle_user_id
+FROM treasury_movements m
+JOIN circle_wallets cw ON cw.wallet_id = m.source_wallet_id
+WHERE m.status IN ('awaiting_approval', 'submitted')
+ORDER BY m.workspace_id, m.created_at ASC
+LIMIT @batch_size;
+
+-- name: ReconcileTreasuryMovement :one
+UPDATE treasury_movements
+SET
+    status = @status,
+    circle_transaction_id = COALESCE(sqlc.narg('circle_transaction_id'), circle_transaction_id),
+    circle_state = COALESCE(sqlc.narg('circle_state'), circle_state),
+    transaction_hash = COALESCE(sqlc.narg('transaction_hash'), transaction_hash),
+    network_fee = COALESCE(sqlc.narg('network_fee'), network_fee),
+    error_message = sqlc.narg('error_message'),
+    reconciled_at = @reconciled_at,
+    completed_at = CASE WHEN @status = 'completed' THEN @reconciled_at ELSE completed_at END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id
+RETURNING *;
+
+-- name: ListTreasuryMovements :many
+SELECT * FROM treasury_movements
+WHERE workspace_id = @workspace_id
+    AND (sqlc.narg('rule_id')::uuid IS NULL OR rule_id = sqlc.narg('rule_id'))
+    AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountTreasuryMovements :one
+SELECT COUNT(*) FROM treasury_movements
+WHERE workspace_id = @workspace_id
+    AND (sqlc.narg('rule_id')::uuid IS NULL OR rule_id = sqlc.narg('rule_id'))
+    AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'));
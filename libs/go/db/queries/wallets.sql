@@ -33,7 +33,9 @@ WHERE w.id = $1 AND w.workspace_id = $2 AND w.deleted_at IS NULL;
 -- name: GetWalletByAddressAndCircleNetworkType :one
 SELECT * FROM wallets as w
 LEFT JOIN networks as n ON w.network_id = n.id
-WHERE w.wallet_address = $1 AND n.circle_network_type = $2 AND w.deleted_at IS NULL;
+WHERE (w.wallet_address = $1 OR (w.network_type = 'evm' AND LOWER(w.wallet_address) = LOWER($1)))
+    AND n.circle_network_type = $2
+    AND w.deleted_at IS NULL;
 
 -- name: GetWalletWithCircleDataByAddress :one
 SELECT 
@@ -45,7 +47,9 @@ SELECT
     cw.state as circle_state
 FROM wallets w
 LEFT JOIN circle_wallets cw ON w.id = cw.wallet_id AND w.wallet_type = 'circle_wallet'
-WHERE w.wallet_address = $1 AND w.network_type = $2 AND w.deleted_at IS NULL;
+WHERE (w.wallet_address = $1 OR (w.network_type = 'evm' AND LOWER(w.wallet_address) = LOWER($1)))
+    AND w.network_type = $2
+    AND w.deleted_at IS NULL;
 
 -- name: ListWalletsByWorkspaceID :many
 SELECT * FROM wallets
@@ -320,7 +324,7 @@ SELECT
 FROM wallets w
 LEFT JOIN networks n ON w.network_id = n.id
 LEFT JOIN circle_wallets cw ON w.id = cw.wallet_id
-WHERE w.wallet_address = $1 
+WHERE (w.wallet_address = $1 OR (w.network_type = 'evm' AND LOWER(w.wallet_address) = LOWER($1)))
   AND w.workspace_id = $2
   AND w.deleted_at IS NULL
 ORDER BY n.name;
@@ -0,0 +1,91 @@
+-- name: CreateWorkspaceWebhookSecret :one
+-- Keep a rotated-out webhook secret valid until the grace window ends
+INSERT INTO workspace_webhook_secrets (
+    workspace_id,
+    provider_name,
+    webhook_secret_key,
+    expires_at,
+    encryption_key_version
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ListActiveWorkspaceWebhookSecrets :many
+SELECT * FROM workspace_webhook_secrets
+WHERE workspace_id = $1
+    AND provider_name = $2
+    AND expires_at > CURRENT_TIMESTAMP
+ORDER BY created_at DESC;
+
+-- name: DeleteExpiredWorkspaceWebhookSecrets :exec
+DELETE FROM workspace_webhook_secrets
+WHERE expires_at <= CURRENT_TIMESTAMP;
+
+-- name: ListWorkspaceWebhookSecretsForReencryption :many
+-- Unexpired retired secrets not wrapped with the current key, paged by ID
+SELECT * FROM workspace_webhook_secrets
+WHERE encryption_key_version IS DISTINCT FROM sqlc.arg(current_key_version)::text
+    AND id > sqlc.arg(after_id)::uuid
+    AND expires_at > CURRENT_TIMESTAMP
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: ReencryptWorkspaceWebhookSecret :execrows
+-- Only applies if the secret has not been replaced since it was read
+UPDATE workspace_webhook_secrets
+SET
+    webhook_secret_key = sqlc.arg(webhook_secret_key),
+    encryption_key_version = sqlc.arg(encryption_key_version)
+WHERE id = sqlc.arg(id)
+    AND webhook_secret_key = sqlc.arg(previous_webhook_secret_key);
+
+-- name: CountWorkspaceWebhookSecretsByKeyVersion :many
+-- Expired secrets are never decrypted again, so they do not keep a key version in use
+SELECT
+    COALESCE(encryption_key_version, 'legacy')::text AS key_version,
+    COUNT(*) AS secret_count
+FROM workspace_webhook_secrets
+WHERE expires_at > CURRENT_TIMESTAMP
+GROUP BY 1
+ORDER BY 1;
+
+-- name: InsertWebhookReplayEntry :execrows
+-- Records a provider event ID; zero affected rows means the event was already received
+INSERT INTO webhook_replay_cache (
+    workspace_id,
+    provider_name,
+    provider_event_id,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (workspace_id, provider_name, provider_event_id) DO NOTHING;
+
+-- name: DeleteWebhookReplayEntry :exec
+-- Releases an event ID so the provider's retry is accepted (e.g. after a failed enqueue)
+DELETE FROM webhook_replay_cache
+WHERE workspace_id = $1
+    AND provider_name = $2
+    AND provider_event_id = $3;
+
+-- name: DeleteExpiredWebhookReplayEntries :exec
+DELETE FROM webhook_replay_cache
+WHERE expires_at <= CURRENT_TIMESTAMP;
+
+-- name: LogWebhookRejected :one
+-- Log a webhook rejected before processing (bad signature, stale timestamp or replay).
+-- Replays carry a valid signature, so signature_valid is set by the caller.
+INSERT INTO payment_sync_events (
+    workspace_id,
+    provider_name,
+    entity_type,
+    event_type,
+    event_message,
+    event_details,
+    webhook_event_id,
+    provider_account_id,
+    signature_valid
+) VALUES (
+    $1, $2, 'webhook', 'webhook_rejected', $3, $4, $5, $6, $7
+) RETURNING *;
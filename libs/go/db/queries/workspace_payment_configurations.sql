@@ -10,9 +10,10 @@ INSERT INTO workspace_payment_configurations (
     webhook_endpoint_url,
     webhook_secret_key,
     connected_account_id,
-    metadata
+    metadata,
+    encryption_key_version
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) RETURNING *;
 
 -- name: GetWorkspacePaymentConfiguration :one
@@ -44,6 +45,7 @@ SET
     webhook_secret_key = $7,
     connected_account_id = $8,
     metadata = $9,
+    encryption_key_version = $10,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
 RETURNING *;
@@ -115,4 +117,34 @@ SET
     configuration = $3,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING *; 
\ No newline at end of file
+RETURNING *; 
+
+-- name: ListWorkspacePaymentConfigurationsForReencryption :many
+-- Configurations whose credentials are not wrapped with the current key, paged by ID
+SELECT * FROM workspace_payment_configurations
+WHERE encryption_key_version IS DISTINCT FROM sqlc.arg(current_key_version)::text
+    AND id > sqlc.arg(after_id)::uuid
+    AND deleted_at IS NULL
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: ReencryptWorkspacePaymentConfiguration :execrows
+-- Only applies if the row has not been updated since it was read
+UPDATE workspace_payment_configurations
+SET
+    configuration = sqlc.arg(configuration),
+    webhook_secret_key = sqlc.narg(webhook_secret_key),
+    encryption_key_version = sqlc.arg(encryption_key_version)
+WHERE id = sqlc.arg(id)
+    AND workspace_id = sqlc.arg(workspace_id)
+    AND updated_at = sqlc.arg(updated_at)
+    AND deleted_at IS NULL;
+
+-- name: CountWorkspacePaymentConfigurationsByKeyVersion :many
+SELECT
+    COALESCE(encryption_key_version, 'legacy')::text AS key_version,
+    COUNT(*) AS config_count
+FROM workspace_payment_configurations
+WHERE deleted_at IS NULL
+GROUP BY 1
+ORDER BY 1;
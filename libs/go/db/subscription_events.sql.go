@@ -318,6 +318,38 @@ func (q *Queries) GetSubscriptionEventByTransactionHash(ctx context.Context, tra
 	return i, err
 }
 
+const getSubscriptionRedemptionEventByTransactionHash = `-- name: GetSubscriptionRedemptionEventByTransactionHash :one
+SELECT id, subscription_id, event_type, transaction_hash, amount_in_cents, occurred_at, error_message, metadata, created_at, updated_at FROM subscription_events
+WHERE subscription_id = $1
+    AND transaction_hash = $2
+    AND event_type = 'redeem'
+LIMIT 1
+`
+
+type GetSubscriptionRedemptionEventByTransactionHashParams struct {
+	SubscriptionID  uuid.UUID   `json:"subscription_id"`
+	TransactionHash pgtype.Text `json:"transaction_hash"`
+}
+
+// A batched redemption shares its transaction hash with other subscriptions, so the subscription is matched too
+func (q *Queries) GetSubscriptionRedemptionEventByTransactionHash(ctx context.Context, arg GetSubscriptionRedemptionEventByTransactionHashParams) (SubscriptionEvent, error) {
+	row := q.db.QueryRow(ctx, getSubscriptionRedemptionEventByTransactionHash, arg.SubscriptionID, arg.TransactionHash)
+	var i SubscriptionEvent
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventType,
+		&i.TransactionHash,
+		&i.AmountInCents,
+		&i.OccurredAt,
+		&i.ErrorMessage,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getSuccessfulRedemptionCount = `-- name: GetSuccessfulRedemptionCount :one
 SELECT COUNT(*) 
 FROM subscription_events
@@ -1719,6 +1719,66 @@ func (q *Queries) LockSubscriptionForProcessing(ctx context.Context, id uuid.UUI
 	return i, err
 }
 
+const revertSubscriptionRedemption = `-- name: RevertSubscriptionRedemption :one
+UPDATE subscriptions
+SET
+    total_redemptions = GREATEST(total_redemptions - 1, 0),
+    total_amount_in_cents = GREATEST(total_amount_in_cents - $1, 0),
+    next_redemption_date = LEAST(COALESCE(next_redemption_date, $2), $2),
+    status = CASE WHEN status IN ('active', 'completed') THEN 'overdue' ELSE status END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $3 AND deleted_at IS NULL
+RETURNING id, num_id, customer_id, product_id, workspace_id, product_token_id, external_id, token_amount, delegation_id, customer_wallet_id, status, current_period_start, current_period_end, next_redemption_date, total_redemptions, total_amount_in_cents, metadata, payment_sync_status, payment_synced_at, payment_sync_version, payment_provider, created_at, updated_at, deleted_at, currency, cancel_at, cancelled_at, cancellation_reason, paused_at, pause_ends_at, trial_start, trial_end
+`
+
+type RevertSubscriptionRedemptionParams struct {
+	AmountInCents int32              `json:"amount_in_cents"`
+	DueAt         pgtype.Timestamptz `json:"due_at"`
+	ID            uuid.UUID          `json:"id"`
+}
+
+// Undoes IncrementSubscriptionRedemption for a redemption whose transaction never made it on-chain. The period
+// becomes due again from due_at and the subscription is overdue until it is redeemed
+func (q *Queries) RevertSubscriptionRedemption(ctx context.Context, arg RevertSubscriptionRedemptionParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, revertSubscriptionRedemption, arg.AmountInCents, arg.DueAt, arg.ID)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.NumID,
+		&i.CustomerID,
+		&i.ProductID,
+		&i.WorkspaceID,
+		&i.ProductTokenID,
+		&i.ExternalID,
+		&i.TokenAmount,
+		&i.DelegationID,
+		&i.CustomerWalletID,
+		&i.Status,
+		&i.CurrentPeriodStart,
+		&i.CurrentPeriodEnd,
+		&i.NextRedemptionDate,
+		&i.TotalRedemptions,
+		&i.TotalAmountInCents,
+		&i.Metadata,
+		&i.PaymentSyncStatus,
+		&i.PaymentSyncedAt,
+		&i.PaymentSyncVersion,
+		&i.PaymentProvider,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Currency,
+		&i.CancelAt,
+		&i.CancelledAt,
+		&i.CancellationReason,
+		&i.PausedAt,
+		&i.PauseEndsAt,
+		&i.TrialStart,
+		&i.TrialEnd,
+	)
+	return i, err
+}
+
 const updateSubscription = `-- name: UpdateSubscription :one
 UPDATE subscriptions
 SET
@@ -1878,6 +1938,69 @@ func (q *Queries) UpdateSubscriptionPaymentSyncStatus(ctx context.Context, arg U
 	return i, err
 }
 
+const updateSubscriptionPaymentWallet = `-- name: UpdateSubscriptionPaymentWallet :one
+UPDATE subscriptions
+SET
+    customer_wallet_id = $3,
+    delegation_id = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND customer_id = $2 AND deleted_at IS NULL
+RETURNING id, num_id, customer_id, product_id, workspace_id, product_token_id, external_id, token_amount, delegation_id, customer_wallet_id, status, current_period_start, current_period_end, next_redemption_date, total_redemptions, total_amount_in_cents, metadata, payment_sync_status, payment_synced_at, payment_sync_version, payment_provider, created_at, updated_at, deleted_at, currency, cancel_at, cancelled_at, cancellation_reason, paused_at, pause_ends_at, trial_start, trial_end
+`
+
+type UpdateSubscriptionPaymentWalletParams struct {
+	ID               uuid.UUID   `json:"id"`
+	CustomerID       uuid.UUID   `json:"customer_id"`
+	CustomerWalletID pgtype.UUID `json:"customer_wallet_id"`
+	DelegationID     uuid.UUID   `json:"delegation_id"`
+}
+
+// Points a subscription at another customer wallet and the delegation signed by that wallet
+func (q *Queries) UpdateSubscriptionPaymentWallet(ctx context.Context, arg UpdateSubscriptionPaymentWalletParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, updateSubscriptionPaymentWallet,
+		arg.ID,
+		arg.CustomerID,
+		arg.CustomerWalletID,
+		arg.DelegationID,
+	)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.NumID,
+		&i.CustomerID,
+		&i.ProductID,
+		&i.WorkspaceID,
+		&i.ProductTokenID,
+		&i.ExternalID,
+		&i.TokenAmount,
+		&i.DelegationID,
+		&i.CustomerWalletID,
+		&i.Status,
+		&i.CurrentPeriodStart,
+		&i.CurrentPeriodEnd,
+		&i.NextRedemptionDate,
+		&i.TotalRedemptions,
+		&i.TotalAmountInCents,
+		&i.Metadata,
+		&i.PaymentSyncStatus,
+		&i.PaymentSyncedAt,
+		&i.PaymentSyncVersion,
+		&i.PaymentProvider,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Currency,
+		&i.CancelAt,
+		&i.CancelledAt,
+		&i.CancellationReason,
+		&i.PausedAt,
+		&i.PauseEndsAt,
+		&i.TrialStart,
+		&i.TrialEnd,
+	)
+	return i, err
+}
+
 const updateSubscriptionStatus = `-- name: UpdateSubscriptionStatus :one
 UPDATE subscriptions
 SET 
@@ -338,9 +338,11 @@ func (q *Queries) GetRecentlyUsedWalletsWithCircleData(ctx context.Context, arg
 }
 
 const getWalletByAddressAndCircleNetworkType = `-- name: GetWalletByAddressAndCircleNetworkType :one
-SELECT w.id, workspace_id, wallet_type, wallet_address, w.network_type, network_id, nickname, ens, is_primary, verified, last_used_at, web3auth_user_id, smart_account_type, deployment_status, metadata, w.created_at, w.updated_at, w.deleted_at, n.id, name, type, n.network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, n.created_at, n.updated_at, n.deleted_at FROM wallets as w
+SELECT w.id, workspace_id, wallet_type, wallet_address, w.network_type, network_id, nickname, ens, is_primary, verified, last_used_at, web3auth_user_id, smart_account_type, deployment_status, metadata, w.created_at, w.updated_at, w.deleted_at, n.id, name, type, n.network_type, circle_network_type, rpc_id, block_explorer_url, chain_id, is_testnet, active, logo_url, display_name, chain_namespace, base_fee_multiplier, priority_fee_multiplier, deployment_gas_limit, token_transfer_gas_limit, supports_eip1559, gas_oracle_url, gas_refresh_interval_ms, gas_priority_levels, average_block_time_ms, peak_hours_multiplier, confirmation_blocks, finality_blocks, n.created_at, n.updated_at, n.deleted_at FROM wallets as w
 LEFT JOIN networks as n ON w.network_id = n.id
-WHERE w.wallet_address = $1 AND n.circle_network_type = $2 AND w.deleted_at IS NULL
+WHERE (w.wallet_address = $1 OR (w.network_type = 'evm' AND LOWER(w.wallet_address) = LOWER($1)))
+    AND n.circle_network_type = $2
+    AND w.deleted_at IS NULL
 `
 
 type GetWalletByAddressAndCircleNetworkTypeParams struct {
@@ -390,6 +392,8 @@ type GetWalletByAddressAndCircleNetworkTypeRow struct {
 	GasPriorityLevels     []byte                `json:"gas_priority_levels"`
 	AverageBlockTimeMs    pgtype.Int4           `json:"average_block_time_ms"`
 	PeakHoursMultiplier   pgtype.Numeric        `json:"peak_hours_multiplier"`
+	ConfirmationBlocks    pgtype.Int4           `json:"confirmation_blocks"`
+	FinalityBlocks        pgtype.Int4           `json:"finality_blocks"`
 	CreatedAt_2           pgtype.Timestamptz    `json:"created_at_2"`
 	UpdatedAt_2           pgtype.Timestamptz    `json:"updated_at_2"`
 	DeletedAt_2           pgtype.Timestamptz    `json:"deleted_at_2"`
@@ -440,6 +444,8 @@ func (q *Queries) GetWalletByAddressAndCircleNetworkType(ctx context.Context, ar
 		&i.GasPriorityLevels,
 		&i.AverageBlockTimeMs,
 		&i.PeakHoursMultiplier,
+		&i.ConfirmationBlocks,
+		&i.FinalityBlocks,
 		&i.CreatedAt_2,
 		&i.UpdatedAt_2,
 		&i.DeletedAt_2,
@@ -528,7 +534,9 @@ SELECT
     cw.state as circle_state
 FROM wallets w
 LEFT JOIN circle_wallets cw ON w.id = cw.wallet_id AND w.wallet_type = 'circle_wallet'
-WHERE w.wallet_address = $1 AND w.network_type = $2 AND w.deleted_at IS NULL
+WHERE (w.wallet_address = $1 OR (w.network_type = 'evm' AND LOWER(w.wallet_address) = LOWER($1)))
+    AND w.network_type = $2
+    AND w.deleted_at IS NULL
 `
 
 type GetWalletWithCircleDataByAddressParams struct {
@@ -1045,7 +1053,7 @@ SELECT
 FROM wallets w
 LEFT JOIN networks n ON w.network_id = n.id
 LEFT JOIN circle_wallets cw ON w.id = cw.wallet_id
-WHERE w.wallet_address = $1 
+WHERE (w.wallet_address = $1 OR (w.network_type = 'evm' AND LOWER(w.wallet_address) = LOWER($1)))
   AND w.workspace_id = $2
   AND w.deleted_at IS NULL
 ORDER BY n.name
@@ -384,6 +384,10 @@ INNER JOIN workspace_customers wc ON c.id = wc.customer_id
 LEFT JOIN payments p ON c.id = p.customer_id 
     AND p.workspace_id = $1 
     AND p.status = 'completed'
+    -- Crypto payments that can still be rolled back are left out
+    AND (p.transaction_hash IS NULL OR p.network_id IS NULL OR EXISTS (
+        SELECT 1 FROM payment_confirmations pc WHERE pc.payment_id = p.id AND pc.status = 'finalized'
+    ))
 WHERE wc.workspace_id = $1 AND wc.deleted_at IS NULL AND c.deleted_at IS NULL
 GROUP BY c.id, c.external_id, c.web3auth_id, c.email, c.name, c.phone, c.description, 
          c.metadata, c.finished_onboarding, c.payment_sync_status, c.payment_synced_at, 
@@ -46,6 +46,41 @@ func (q *Queries) CountWorkspacePaymentConfigurations(ctx context.Context, works
 	return count, err
 }
 
+const countWorkspacePaymentConfigurationsByKeyVersion = `-- name: CountWorkspacePaymentConfigurationsByKeyVersion :many
+SELECT
+    COALESCE(encryption_key_version, 'legacy')::text AS key_version,
+    COUNT(*) AS config_count
+FROM workspace_payment_configurations
+WHERE deleted_at IS NULL
+GROUP BY 1
+ORDER BY 1
+`
+
+type CountWorkspacePaymentConfigurationsByKeyVersionRow struct {
+	KeyVersion  string `json:"key_version"`
+	ConfigCount int64  `json:"config_count"`
+}
+
+func (q *Queries) CountWorkspacePaymentConfigurationsByKeyVersion(ctx context.Context) ([]CountWorkspacePaymentConfigurationsByKeyVersionRow, error) {
+	rows, err := q.db.Query(ctx, countWorkspacePaymentConfigurationsByKeyVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountWorkspacePaymentConfigurationsByKeyVersionRow{}
+	for rows.Next() {
+		var i CountWorkspacePaymentConfigurationsByKeyVersionRow
+		if err := rows.Scan(&i.KeyVersion, &i.ConfigCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createWorkspacePaymentConfiguration = `-- name: CreateWorkspacePaymentConfiguration :one
 
 INSERT INTO workspace_payment_configurations (
@@ -57,22 +92,24 @@ INSERT INTO workspace_payment_configurations (
     webhook_endpoint_url,
     webhook_secret_key,
     connected_account_id,
-    metadata
+    metadata,
+    encryption_key_version
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
-) RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version
 `
 
 type CreateWorkspacePaymentConfigurationParams struct {
-	WorkspaceID        uuid.UUID       `json:"workspace_id"`
-	ProviderName       string          `json:"provider_name"`
-	IsActive           bool            `json:"is_active"`
-	IsTestMode         bool            `json:"is_test_mode"`
-	Configuration      json.RawMessage `json:"configuration"`
-	WebhookEndpointUrl pgtype.Text     `json:"webhook_endpoint_url"`
-	WebhookSecretKey   pgtype.Text     `json:"webhook_secret_key"`
-	ConnectedAccountID pgtype.Text     `json:"connected_account_id"`
-	Metadata           []byte          `json:"metadata"`
+	WorkspaceID          uuid.UUID       `json:"workspace_id"`
+	ProviderName         string          `json:"provider_name"`
+	IsActive             bool            `json:"is_active"`
+	IsTestMode           bool            `json:"is_test_mode"`
+	Configuration        json.RawMessage `json:"configuration"`
+	WebhookEndpointUrl   pgtype.Text     `json:"webhook_endpoint_url"`
+	WebhookSecretKey     pgtype.Text     `json:"webhook_secret_key"`
+	ConnectedAccountID   pgtype.Text     `json:"connected_account_id"`
+	Metadata             []byte          `json:"metadata"`
+	EncryptionKeyVersion pgtype.Text     `json:"encryption_key_version"`
 }
 
 // Workspace Payment Configuration Queries
@@ -87,6 +124,7 @@ func (q *Queries) CreateWorkspacePaymentConfiguration(ctx context.Context, arg C
 		arg.WebhookSecretKey,
 		arg.ConnectedAccountID,
 		arg.Metadata,
+		arg.EncryptionKeyVersion,
 	)
 	var i WorkspacePaymentConfiguration
 	err := row.Scan(
@@ -105,6 +143,7 @@ func (q *Queries) CreateWorkspacePaymentConfiguration(ctx context.Context, arg C
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
@@ -115,7 +154,7 @@ SET
     is_active = false,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version
 `
 
 type DeactivateWorkspacePaymentConfigurationParams struct {
@@ -142,6 +181,7 @@ func (q *Queries) DeactivateWorkspacePaymentConfiguration(ctx context.Context, a
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
@@ -152,7 +192,7 @@ SET
     deleted_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version
 `
 
 type DeleteWorkspacePaymentConfigurationParams struct {
@@ -179,6 +219,7 @@ func (q *Queries) DeleteWorkspacePaymentConfiguration(ctx context.Context, arg D
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
@@ -209,7 +250,7 @@ func (q *Queries) GetWorkspaceActiveProviders(ctx context.Context, workspaceID u
 }
 
 const getWorkspacePaymentConfiguration = `-- name: GetWorkspacePaymentConfiguration :one
-SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at FROM workspace_payment_configurations 
+SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version FROM workspace_payment_configurations 
 WHERE workspace_id = $1 AND provider_name = $2 AND is_active = true AND deleted_at IS NULL
 `
 
@@ -237,12 +278,13 @@ func (q *Queries) GetWorkspacePaymentConfiguration(ctx context.Context, arg GetW
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
 
 const getWorkspacePaymentConfigurationByConnectedAccount = `-- name: GetWorkspacePaymentConfigurationByConnectedAccount :one
-SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at FROM workspace_payment_configurations 
+SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version FROM workspace_payment_configurations 
 WHERE connected_account_id = $1 AND provider_name = $2 AND is_active = true AND deleted_at IS NULL
 `
 
@@ -270,12 +312,13 @@ func (q *Queries) GetWorkspacePaymentConfigurationByConnectedAccount(ctx context
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
 
 const getWorkspacePaymentConfigurationByID = `-- name: GetWorkspacePaymentConfigurationByID :one
-SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at FROM workspace_payment_configurations 
+SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version FROM workspace_payment_configurations 
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
 `
 
@@ -303,12 +346,13 @@ func (q *Queries) GetWorkspacePaymentConfigurationByID(ctx context.Context, arg
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
 
 const getWorkspacePaymentConfigurationByWebhookURL = `-- name: GetWorkspacePaymentConfigurationByWebhookURL :one
-SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at FROM workspace_payment_configurations 
+SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version FROM workspace_payment_configurations 
 WHERE webhook_endpoint_url = $1 AND is_active = true AND deleted_at IS NULL
 `
 
@@ -331,12 +375,13 @@ func (q *Queries) GetWorkspacePaymentConfigurationByWebhookURL(ctx context.Conte
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
 
 const listActiveWorkspacePaymentConfigurations = `-- name: ListActiveWorkspacePaymentConfigurations :many
-SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at FROM workspace_payment_configurations 
+SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version FROM workspace_payment_configurations 
 WHERE workspace_id = $1 AND is_active = true AND deleted_at IS NULL
 ORDER BY provider_name
 `
@@ -366,6 +411,8 @@ func (q *Queries) ListActiveWorkspacePaymentConfigurations(ctx context.Context,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.EncryptionKeyVersion,
+			&i.EncryptionKeyVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -378,7 +425,7 @@ func (q *Queries) ListActiveWorkspacePaymentConfigurations(ctx context.Context,
 }
 
 const listWorkspacePaymentConfigurations = `-- name: ListWorkspacePaymentConfigurations :many
-SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at FROM workspace_payment_configurations 
+SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version FROM workspace_payment_configurations 
 WHERE workspace_id = $1 AND deleted_at IS NULL
 ORDER BY provider_name, created_at DESC
 LIMIT $2 OFFSET $3
@@ -415,6 +462,8 @@ func (q *Queries) ListWorkspacePaymentConfigurations(ctx context.Context, arg Li
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.EncryptionKeyVersion,
+			&i.EncryptionKeyVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -427,7 +476,7 @@ func (q *Queries) ListWorkspacePaymentConfigurations(ctx context.Context, arg Li
 }
 
 const listWorkspacePaymentConfigurationsByProvider = `-- name: ListWorkspacePaymentConfigurationsByProvider :many
-SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at FROM workspace_payment_configurations 
+SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version FROM workspace_payment_configurations 
 WHERE provider_name = $1 AND is_active = true AND deleted_at IS NULL
 ORDER BY workspace_id
 `
@@ -457,6 +506,61 @@ func (q *Queries) ListWorkspacePaymentConfigurationsByProvider(ctx context.Conte
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.EncryptionKeyVersion,
+			&i.EncryptionKeyVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorkspacePaymentConfigurationsForReencryption = `-- name: ListWorkspacePaymentConfigurationsForReencryption :many
+SELECT id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version FROM workspace_payment_configurations
+WHERE encryption_key_version IS DISTINCT FROM $1::text
+    AND id > $2::uuid
+    AND deleted_at IS NULL
+ORDER BY id
+LIMIT $3
+`
+
+type ListWorkspacePaymentConfigurationsForReencryptionParams struct {
+	CurrentKeyVersion string    `json:"current_key_version"`
+	AfterID           uuid.UUID `json:"after_id"`
+	BatchSize         int32     `json:"batch_size"`
+}
+
+// Configurations whose credentials are not wrapped with the current key, paged by ID
+func (q *Queries) ListWorkspacePaymentConfigurationsForReencryption(ctx context.Context, arg ListWorkspacePaymentConfigurationsForReencryptionParams) ([]WorkspacePaymentConfiguration, error) {
+	rows, err := q.db.Query(ctx, listWorkspacePaymentConfigurationsForReencryption, arg.CurrentKeyVersion, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WorkspacePaymentConfiguration{}
+	for rows.Next() {
+		var i WorkspacePaymentConfiguration
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.ProviderName,
+			&i.IsActive,
+			&i.IsTestMode,
+			&i.Configuration,
+			&i.WebhookEndpointUrl,
+			&i.WebhookSecretKey,
+			&i.ConnectedAccountID,
+			&i.LastSyncAt,
+			&i.LastWebhookAt,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.EncryptionKeyVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -468,6 +572,43 @@ func (q *Queries) ListWorkspacePaymentConfigurationsByProvider(ctx context.Conte
 	return items, nil
 }
 
+const reencryptWorkspacePaymentConfiguration = `-- name: ReencryptWorkspacePaymentConfiguration :execrows
+UPDATE workspace_payment_configurations
+SET
+    configuration = $1,
+    webhook_secret_key = $2,
+    encryption_key_version = $3
+WHERE id = $4
+    AND workspace_id = $5
+    AND updated_at = $6
+    AND deleted_at IS NULL
+`
+
+type ReencryptWorkspacePaymentConfigurationParams struct {
+	Configuration        json.RawMessage    `json:"configuration"`
+	WebhookSecretKey     pgtype.Text        `json:"webhook_secret_key"`
+	EncryptionKeyVersion pgtype.Text        `json:"encryption_key_version"`
+	ID                   uuid.UUID          `json:"id"`
+	WorkspaceID          uuid.UUID          `json:"workspace_id"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Only applies if the row has not been updated since it was read
+func (q *Queries) ReencryptWorkspacePaymentConfiguration(ctx context.Context, arg ReencryptWorkspacePaymentConfigurationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reencryptWorkspacePaymentConfiguration,
+		arg.Configuration,
+		arg.WebhookSecretKey,
+		arg.EncryptionKeyVersion,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateWorkspacePaymentConfiguration = `-- name: UpdateWorkspacePaymentConfiguration :one
 UPDATE workspace_payment_configurations 
 SET 
@@ -478,21 +619,23 @@ SET
     webhook_secret_key = $7,
     connected_account_id = $8,
     metadata = $9,
+    encryption_key_version = $10,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version
 `
 
 type UpdateWorkspacePaymentConfigurationParams struct {
-	ID                 uuid.UUID       `json:"id"`
-	WorkspaceID        uuid.UUID       `json:"workspace_id"`
-	IsActive           bool            `json:"is_active"`
-	IsTestMode         bool            `json:"is_test_mode"`
-	Configuration      json.RawMessage `json:"configuration"`
-	WebhookEndpointUrl pgtype.Text     `json:"webhook_endpoint_url"`
-	WebhookSecretKey   pgtype.Text     `json:"webhook_secret_key"`
-	ConnectedAccountID pgtype.Text     `json:"connected_account_id"`
-	Metadata           []byte          `json:"metadata"`
+	ID                   uuid.UUID       `json:"id"`
+	WorkspaceID          uuid.UUID       `json:"workspace_id"`
+	IsActive             bool            `json:"is_active"`
+	IsTestMode           bool            `json:"is_test_mode"`
+	Configuration        json.RawMessage `json:"configuration"`
+	WebhookEndpointUrl   pgtype.Text     `json:"webhook_endpoint_url"`
+	WebhookSecretKey     pgtype.Text     `json:"webhook_secret_key"`
+	ConnectedAccountID   pgtype.Text     `json:"connected_account_id"`
+	Metadata             []byte          `json:"metadata"`
+	EncryptionKeyVersion pgtype.Text     `json:"encryption_key_version"`
 }
 
 func (q *Queries) UpdateWorkspacePaymentConfiguration(ctx context.Context, arg UpdateWorkspacePaymentConfigurationParams) (WorkspacePaymentConfiguration, error) {
@@ -506,6 +649,7 @@ func (q *Queries) UpdateWorkspacePaymentConfiguration(ctx context.Context, arg U
 		arg.WebhookSecretKey,
 		arg.ConnectedAccountID,
 		arg.Metadata,
+		arg.EncryptionKeyVersion,
 	)
 	var i WorkspacePaymentConfiguration
 	err := row.Scan(
@@ -524,6 +668,7 @@ func (q *Queries) UpdateWorkspacePaymentConfiguration(ctx context.Context, arg U
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
@@ -534,7 +679,7 @@ SET
     configuration = $3,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version
 `
 
 type UpdateWorkspacePaymentConfigurationConfigParams struct {
@@ -562,6 +707,7 @@ func (q *Queries) UpdateWorkspacePaymentConfigurationConfig(ctx context.Context,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
@@ -572,7 +718,7 @@ SET
     last_sync_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version
 `
 
 type UpdateWorkspacePaymentConfigurationLastSyncParams struct {
@@ -599,6 +745,7 @@ func (q *Queries) UpdateWorkspacePaymentConfigurationLastSync(ctx context.Contex
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
@@ -609,7 +756,7 @@ SET
     last_webhook_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
-RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at
+RETURNING id, workspace_id, provider_name, is_active, is_test_mode, configuration, webhook_endpoint_url, webhook_secret_key, connected_account_id, last_sync_at, last_webhook_at, metadata, created_at, updated_at, deleted_at, encryption_key_version
 `
 
 type UpdateWorkspacePaymentConfigurationLastWebhookParams struct {
@@ -636,6 +783,7 @@ func (q *Queries) UpdateWorkspacePaymentConfigurationLastWebhook(ctx context.Con
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.EncryptionKeyVersion,
 	)
 	return i, err
 }
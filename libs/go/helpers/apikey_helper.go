@@ -8,6 +8,7 @@ import (
 
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -63,6 +64,17 @@ func ToAPIKeyResponse(a db.ApiKey) responses.APIKeyResponse {
 		lastUsedAt = &unix
 	}
 
+	var rotatedFrom string
+	if a.RotatedFromID.Valid {
+		rotatedFrom = uuid.UUID(a.RotatedFromID.Bytes).String()
+	}
+
+	var rotatedAt *int64
+	if a.RotatedAt.Valid {
+		unix := a.RotatedAt.Time.Unix()
+		rotatedAt = &unix
+	}
+
 	return responses.APIKeyResponse{
 		ID:          a.ID.String(),
 		Object:      "api_key",
@@ -74,5 +86,7 @@ func ToAPIKeyResponse(a db.ApiKey) responses.APIKeyResponse {
 		CreatedAt:   a.CreatedAt.Time.Unix(),
 		UpdatedAt:   a.UpdatedAt.Time.Unix(),
 		KeyPrefix:   a.KeyPrefix.String,
+		RotatedFrom: rotatedFrom,
+		RotatedAt:   rotatedAt,
 	}
 }
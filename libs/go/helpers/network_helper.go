@@ -69,22 +69,24 @@ func ToNetworkResponse(n db.Network) responses.NetworkResponse {
 	}
 
 	return responses.NetworkResponse{
-		ID:                n.ID.String(),
-		Object:            "network",
-		Name:              n.Name,
-		Type:              n.Type,
-		NetworkType:       string(n.NetworkType),
-		CircleNetworkType: string(n.CircleNetworkType),
-		BlockExplorerURL:  blockExplorerURL,
-		ChainID:           n.ChainID,
-		IsTestnet:         n.IsTestnet,
-		Active:            n.Active,
-		LogoURL:           logoURL,
-		DisplayName:       displayName,
-		ChainNamespace:    chainNamespace,
-		CreatedAt:         n.CreatedAt.Time.Unix(),
-		UpdatedAt:         n.UpdatedAt.Time.Unix(),
-		GasConfig:         gasConfig,
+		ID:                 n.ID.String(),
+		Object:             "network",
+		Name:               n.Name,
+		Type:               n.Type,
+		NetworkType:        string(n.NetworkType),
+		CircleNetworkType:  string(n.CircleNetworkType),
+		BlockExplorerURL:   blockExplorerURL,
+		ChainID:            n.ChainID,
+		IsTestnet:          n.IsTestnet,
+		Active:             n.Active,
+		LogoURL:            logoURL,
+		DisplayName:        displayName,
+		ChainNamespace:     chainNamespace,
+		CreatedAt:          n.CreatedAt.Time.Unix(),
+		UpdatedAt:          n.UpdatedAt.Time.Unix(),
+		GasConfig:          gasConfig,
+		ConfirmationBlocks: n.ConfirmationBlocks,
+		FinalityBlocks:     n.FinalityBlocks,
 	}
 }
 
@@ -8,10 +8,12 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/cyphera/cyphera-api/libs/go/client/solana"
 	"github.com/cyphera/cyphera-api/libs/go/constants"
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -341,10 +343,13 @@ func ValidateMetadata(metadata json.RawMessage) error {
 	return nil
 }
 
-// NormalizeWalletAddress normalizes wallet address based on network type
+// NormalizeWalletAddress normalizes wallet address based on network type. EVM addresses are returned in their
+// EIP-55 checksummed form so the same address is stored and matched the same way whatever its input casing;
+// other addresses are case-sensitive and only trimmed.
 func NormalizeWalletAddress(address, networkType string) string {
-	if networkType == string(db.NetworkTypeEvm) {
-		return strings.ToLower(address)
+	address = strings.TrimSpace(address)
+	if networkType == string(db.NetworkTypeEvm) && common.IsHexAddress(address) {
+		return common.HexToAddress(address).Hex()
 	}
 	return address
 }
@@ -525,6 +530,33 @@ func BuildUpdateParams(params params.UpdateProductParams, existingProduct db.Pro
 	return updateParams
 }
 
+// ValidateSplApprovalData validates the shape of a Solana token account approval. The delegator is the customer's
+// wallet, the authority the token account it approved the payment delegate on, and the signature that of the
+// approval transaction. The transaction itself is verified on-chain when the subscription is created.
+func ValidateSplApprovalData(delegation params.DelegationParams, delegateAddress string) error {
+	if delegateAddress == "" {
+		return fmt.Errorf("solana payments are not configured")
+	}
+	if delegation.Delegate != delegateAddress {
+		return fmt.Errorf("delegate address does not match the solana payment delegate, %s != %s", delegation.Delegate, delegateAddress)
+	}
+
+	if delegation.Delegator == "" || delegation.Authority == "" || delegation.Signature == "" {
+		return fmt.Errorf("incomplete approval data")
+	}
+	if _, err := solana.DecodeBase58(delegation.Signature); err != nil {
+		return fmt.Errorf("invalid approval signature: %s", delegation.Signature)
+	}
+	if !solana.IsValidAddress(delegation.Delegator) {
+		return fmt.Errorf("invalid delegator address: %s", delegation.Delegator)
+	}
+	if !solana.IsValidAddress(delegation.Authority) {
+		return fmt.Errorf("invalid token account address: %s", delegation.Authority)
+	}
+
+	return nil
+}
+
 // ValidateDelegationData validates the delegation data
 func ValidateDelegationData(delegation params.DelegationParams, cypheraAddress string) error {
 	if delegation.Delegate != cypheraAddress {
@@ -2,6 +2,8 @@ package interfaces
 
 import (
 	"context"
+	"io"
+	"math/big"
 	"time"
 
 	"github.com/cyphera/cyphera-api/libs/go/db"
@@ -21,7 +23,7 @@ type PaymentService interface {
 	CreatePaymentFromSubscriptionEvent(ctx context.Context, params params.CreatePaymentFromSubscriptionEventParams) (*db.Payment, error)
 	CreateComprehensivePayment(ctx context.Context, params params.CreateComprehensivePaymentParams) (*db.Payment, error)
 	GetPayment(ctx context.Context, params params.GetPaymentParams) (*db.Payment, error)
-	GetPaymentByTransactionHash(ctx context.Context, txHash string) (*db.Payment, error)
+	GetPaymentByTransactionHash(ctx context.Context, txHash string, subscriptionEventID *uuid.UUID) (*db.Payment, error)
 	ListPayments(ctx context.Context, params params.ListPaymentsParams) ([]db.Payment, error)
 	UpdatePaymentStatus(ctx context.Context, params params.UpdatePaymentStatusParams) (*db.Payment, error)
 	GetPaymentMetrics(ctx context.Context, workspaceID uuid.UUID, startTime, endTime time.Time, currency string) (*db.GetPaymentMetricsRow, error)
@@ -103,6 +105,41 @@ type GasSponsorshipService interface {
 	CreateDefaultSponsorshipConfig(ctx context.Context, workspaceID uuid.UUID) error
 	UpdateSponsorshipConfig(ctx context.Context, workspaceID uuid.UUID, updates business.SponsorshipConfigUpdates) error
 	GetSponsorshipAnalytics(ctx context.Context, workspaceID uuid.UUID, days int) (*business.SponsorshipAnalytics, error)
+	SimulateSponsorship(ctx context.Context, params params.SponsorshipCheckParams) (*business.SponsorshipSimulation, error)
+	ListSponsorshipRules(ctx context.Context, workspaceID uuid.UUID) ([]business.SponsorshipRule, error)
+	CreateSponsorshipRule(ctx context.Context, params params.SponsorshipRuleParams) (*business.SponsorshipRule, error)
+	UpdateSponsorshipRule(ctx context.Context, ruleID uuid.UUID, params params.SponsorshipRuleParams) (*business.SponsorshipRule, error)
+	DeleteSponsorshipRule(ctx context.Context, workspaceID, ruleID uuid.UUID) error
+	ReserveSponsorship(ctx context.Context, params params.SponsorshipCheckParams) (*business.SponsorshipDecision, error)
+	AttachSponsorshipPayment(ctx context.Context, workspaceID, reservationID, paymentID uuid.UUID) error
+	SettleSponsorship(ctx context.Context, settlement params.SponsorshipSettlementParams) (*business.SponsorshipReservation, error)
+	ReleaseSponsorship(ctx context.Context, release params.SponsorshipReleaseParams) (*business.SponsorshipReservation, error)
+	ReleaseExpiredSponsorships(ctx context.Context) (int, error)
+	ListSponsorshipLedger(ctx context.Context, workspaceID uuid.UUID, limit, offset int32) ([]business.SponsorshipLedgerEntry, int64, error)
+}
+
+// TreasuryService handles merchant treasury sweeps
+type TreasuryService interface {
+	ListSweepRules(ctx context.Context, workspaceID uuid.UUID) ([]business.TreasurySweepRule, error)
+	CreateSweepRule(ctx context.Context, params params.TreasurySweepRuleParams) (*business.TreasurySweepRule, error)
+	UpdateSweepRule(ctx context.Context, ruleID uuid.UUID, params params.TreasurySweepRuleParams) (*business.TreasurySweepRule, error)
+	DeleteSweepRule(ctx context.Context, workspaceID, ruleID uuid.UUID) error
+	ListMovements(ctx context.Context, params params.ListTreasuryMovementsParams) ([]business.TreasuryMovement, int64, error)
+	RunSweeps(ctx context.Context, now time.Time) (*business.TreasurySweepResult, error)
+	ReconcileMovements(ctx context.Context, now time.Time) (*business.TreasuryReconcileResult, error)
+}
+
+// PortfolioService reports the balances and value of a workspace's wallets
+type PortfolioService interface {
+	GetPortfolio(ctx context.Context, workspaceID uuid.UUID, walletID *uuid.UUID, refresh bool) (*business.Portfolio, error)
+	ListSnapshots(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) ([]business.PortfolioSnapshot, error)
+	RecordSnapshots(ctx context.Context, now time.Time) (*business.PortfolioSnapshotResult, error)
+}
+
+// NameResolver resolves ENS and Basenames names to EVM wallet addresses and wallet addresses to their primary names
+type NameResolver interface {
+	ResolveName(ctx context.Context, name string) (string, error)
+	LookupAddress(ctx context.Context, address string) (string, error)
 }
 
 // BlockchainService handles blockchain operations
@@ -110,6 +147,8 @@ type BlockchainService interface {
 	Initialize(ctx context.Context) error
 	GetTransactionData(ctx context.Context, txHash string, networkID uuid.UUID) (*business.TransactionData, error)
 	GetTransactionDataFromEvent(ctx context.Context, event *db.SubscriptionEvent) (*business.TransactionData, error)
+	GetNativeBalance(ctx context.Context, networkID uuid.UUID, account string) (*big.Int, error)
+	GetTokenBalance(ctx context.Context, networkID uuid.UUID, token, account string) (*big.Int, error)
 	Close()
 }
 
@@ -119,9 +158,51 @@ type ExchangeRateService interface {
 	ConvertAmount(ctx context.Context, amount float64, fromCurrency, toCurrency string) (float64, *responses.ExchangeRateResult, error)
 }
 
-// TaxService handles tax calculations
+// TaxService handles tax calculations and the jurisdiction and rate tables behind them
 type TaxService interface {
 	CalculateTax(ctx context.Context, params params.TaxCalculationParams) (*responses.TaxCalculationResult, error)
+	GetTaxRatesForJurisdiction(ctx context.Context, jurisdictionCode string) (*business.TaxJurisdiction, error)
+	ListJurisdictions(ctx context.Context, countryCode string, limit, offset int32) ([]db.TaxJurisdiction, error)
+	GetJurisdiction(ctx context.Context, id uuid.UUID) (*db.TaxJurisdiction, error)
+	CreateJurisdiction(ctx context.Context, params params.CreateTaxJurisdictionParams) (*db.TaxJurisdiction, error)
+	UpdateJurisdiction(ctx context.Context, params params.UpdateTaxJurisdictionParams) (*db.TaxJurisdiction, error)
+	ListRates(ctx context.Context, jurisdictionID uuid.UUID) ([]db.TaxRate, error)
+	CreateRate(ctx context.Context, params params.CreateTaxRateParams) (*db.TaxRate, error)
+	EndRate(ctx context.Context, id uuid.UUID, effectiveTo time.Time) (*db.TaxRate, error)
+	DeleteScheduledRate(ctx context.Context, id uuid.UUID) error
+}
+
+// TaxProvider calculates tax through an external engine. The tax service falls back to
+// its own rate tables when a provider is unavailable.
+type TaxProvider interface {
+	Name() string
+	// CalculateTax quotes the tax on a transaction without recording anything with the provider
+	CalculateTax(ctx context.Context, params params.TaxCalculationParams) (*responses.TaxCalculationResult, error)
+	// CommitTransaction records a quoted calculation once the invoice it taxes exists, returning the
+	// provider's transaction ID
+	CommitTransaction(ctx context.Context, params params.TaxCalculationParams, calculation *responses.TaxCalculationResult) (string, error)
+}
+
+// TaxIDRegistry checks tax IDs against an official registry such as VIES
+type TaxIDRegistry interface {
+	Name() string
+	CheckTaxID(ctx context.Context, countryCode, taxID string) (*business.TaxIDCheckResult, error)
+}
+
+// TaxIDVerificationService verifies customer tax IDs and keeps each result as audit evidence
+type TaxIDVerificationService interface {
+	VerifyTaxID(ctx context.Context, customerID *uuid.UUID, countryCode, taxID string) (*db.TaxIDVerification, error)
+	VerifyCustomerTaxID(ctx context.Context, customerID uuid.UUID) (*db.TaxIDVerification, error)
+	ListCustomerVerifications(ctx context.Context, customerID uuid.UUID, limit int32) ([]db.TaxIDVerification, error)
+	ReverifyCustomerTaxIDs(ctx context.Context, verifiedBefore time.Time, limit int32) (int, error)
+}
+
+// TaxReportService aggregates invoiced, collected and refunded tax for filing tax returns
+type TaxReportService interface {
+	GetTaxSummary(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*responses.TaxSummaryReport, error)
+	GetOSSReport(ctx context.Context, workspaceID uuid.UUID, year, quarter int, homeCountry string) (*responses.OSSReport, error)
+	GetReverseChargeReport(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*responses.ReverseChargeReport, error)
+	GetUSStateTaxReport(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*responses.USStateTaxReport, error)
 }
 
 // PaymentLinkService handles payment link operations
@@ -150,6 +231,8 @@ type PaymentFailureMonitor interface {
 type GasFeeService interface {
 	EstimateGasFee(ctx context.Context, estimateGasFeeParams params.EstimateGasFeeParams) (*responses.EstimateGasFeeResult, error)
 	GetCurrentGasPrice(ctx context.Context, networkID uuid.UUID) (int, error)
+	RecordGasFeeEstimate(ctx context.Context, workspaceID, paymentID, networkID uuid.UUID, estimate *responses.EstimateGasFeeResult) error
+	GetGasFeeEstimateAccuracy(ctx context.Context, workspaceID uuid.UUID, startDate, endDate time.Time) ([]business.GasFeeEstimateAccuracy, error)
 }
 
 // DunningRetryEngine handles payment retry logic
@@ -266,6 +349,31 @@ type APIKeyService interface {
 	UpdateAPIKey(ctx context.Context, params params.UpdateAPIKeyParams) (db.ApiKey, error)
 	DeleteAPIKey(ctx context.Context, id, workspaceID uuid.UUID) error
 	ListAPIKeys(ctx context.Context, workspaceID uuid.UUID) ([]db.ApiKey, error)
+	RotateAPIKey(ctx context.Context, params params.RotateAPIKeyParams) (db.ApiKey, string, error)
+	RecordAPIKeyUsage(ctx context.Context, params params.RecordAPIKeyUsageParams) error
+	ListAPIKeyUsage(ctx context.Context, id, workspaceID uuid.UUID, startDate, endDate time.Time) ([]db.ApiKeyUsageDaily, error)
+}
+
+// CustomerPortalService handles customer self-service portal operations
+type CustomerPortalService interface {
+	GetSettings(ctx context.Context, workspaceID uuid.UUID) (db.CustomerPortalSetting, error)
+	UpdateSettings(ctx context.Context, params params.UpdateCustomerPortalSettingsParams) (db.CustomerPortalSetting, error)
+	CreateSession(ctx context.Context, params params.CreateCustomerPortalSessionParams) (db.CustomerPortalSession, string, error)
+	SessionURL(token string) string
+	RevokeSession(ctx context.Context, sessionID, workspaceID uuid.UUID) error
+	AuthenticateSession(ctx context.Context, token string) (db.CustomerPortalSession, error)
+	DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error)
+	GetCustomer(ctx context.Context, scope params.CustomerPortalScope) (db.Customer, []db.CustomerWallet, error)
+	ListSubscriptions(ctx context.Context, scope params.CustomerPortalScope) ([]business.CustomerPortalSubscription, error)
+	ListInvoices(ctx context.Context, scope params.CustomerPortalScope, limit, offset int32) ([]db.Invoice, error)
+	ListPayments(ctx context.Context, scope params.CustomerPortalScope, limit, offset int32) ([]db.Payment, error)
+	UpdateBillingDetails(ctx context.Context, params params.UpdateCustomerBillingDetailsParams) (db.Customer, error)
+	CancelSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID, reason, feedback string) error
+	PauseSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID, pauseUntil *time.Time, reason string) error
+	ResumeSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID) error
+	PreviewCancellation(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID) (*business.ChangePreview, error)
+	SwitchSubscriptionWallet(ctx context.Context, params params.SwitchSubscriptionWalletParams) (db.Subscription, error)
+	ReauthorizeSubscription(ctx context.Context, params params.ReauthorizeSubscriptionParams) (db.Subscription, error)
 }
 
 // UserService handles user operations
@@ -337,9 +445,27 @@ type AnalyticsService interface {
 	GetMRRChart(ctx context.Context, workspaceID uuid.UUID, metric, period string, months int, currency string) (*business.ChartData, error)
 	GetGasFeePieChart(ctx context.Context, workspaceID uuid.UUID, days int, currency string) (*business.PieChartData, error)
 	GetHourlyMetrics(ctx context.Context, workspaceID uuid.UUID, currency string) (*business.HourlyMetrics, error)
+	GetCohortAnalysis(ctx context.Context, workspaceID uuid.UUID, cohortBy string, months int, currency string) (*business.CohortAnalysis, error)
+	GetMRRMovements(ctx context.Context, listParams params.ListMRRMovementsParams) (*business.MRRMovementReport, error)
 	TriggerMetricsRefresh(ctx context.Context, workspaceID uuid.UUID, date time.Time) error
 }
 
+// AnalyticsExportService handles asynchronous analytics exports and scheduled reports
+type AnalyticsExportService interface {
+	CreateExport(ctx context.Context, exportParams params.CreateAnalyticsExportParams) (*db.AnalyticsExportJob, error)
+	GetExport(ctx context.Context, workspaceID, exportID uuid.UUID) (*db.AnalyticsExportJob, error)
+	ListExports(ctx context.Context, workspaceID uuid.UUID, limit, offset int32) ([]db.AnalyticsExportJob, int64, error)
+	RunExport(ctx context.Context, exportID uuid.UUID) (*db.AnalyticsExportJob, error)
+	ProcessPendingExports(ctx context.Context, batchSize int32) (int, error)
+	OpenExportFile(ctx context.Context, workspaceID, exportID uuid.UUID, dataset string) (io.ReadCloser, *business.ExportFile, error)
+	CreateReportSchedule(ctx context.Context, scheduleParams params.AnalyticsReportScheduleParams) (*db.AnalyticsReportSchedule, error)
+	GetReportSchedule(ctx context.Context, workspaceID, scheduleID uuid.UUID) (*db.AnalyticsReportSchedule, error)
+	ListReportSchedules(ctx context.Context, workspaceID uuid.UUID) ([]db.AnalyticsReportSchedule, error)
+	UpdateReportSchedule(ctx context.Context, scheduleID uuid.UUID, scheduleParams params.AnalyticsReportScheduleParams) (*db.AnalyticsReportSchedule, error)
+	DeleteReportSchedule(ctx context.Context, workspaceID, scheduleID uuid.UUID) error
+	DeliverDueReports(ctx context.Context, now time.Time, batchSize int32) (int, error)
+}
+
 // BlockchainSyncService handles blockchain synchronization
 type BlockchainSyncService interface {
 	SyncTransactions(ctx context.Context, workspaceID uuid.UUID) error
@@ -359,6 +485,23 @@ type PaymentFailureDetector interface {
 	DetectAndCreateCampaigns(ctx context.Context, lookbackMinutes int) (*responses.DetectionResult, error)
 }
 
+// RedemptionQueueService manages the durable redemption task queue
+type RedemptionQueueService interface {
+	Enqueue(ctx context.Context, task business.RedemptionTask) (*db.RedemptionTask, error)
+	GetTask(ctx context.Context, id uuid.UUID) (*db.RedemptionTask, error)
+	ListTasks(ctx context.Context, status string, limit, offset int32) ([]db.RedemptionTask, int64, error)
+	RequeueTask(ctx context.Context, id uuid.UUID, priority *int32) (*db.RedemptionTask, error)
+	GetQueueStats(ctx context.Context) (*business.RedemptionQueueStats, error)
+}
+
+// RenewalReviewService reviews subscription renewals parked because their redemption outcome is unknown
+type RenewalReviewService interface {
+	GetRenewal(ctx context.Context, id uuid.UUID) (*db.SubscriptionRenewal, error)
+	ListRenewals(ctx context.Context, status string, limit, offset int32) ([]db.SubscriptionRenewal, int64, error)
+	RequeueRenewal(ctx context.Context, id uuid.UUID) (*db.SubscriptionRenewal, error)
+	ResolveRenewal(ctx context.Context, id uuid.UUID, transactionHash string) (*db.SubscriptionRenewal, error)
+}
+
 // CommonServicesInterface defines the interface for CommonServices
 // This allows for easier testing and mocking of the CommonServices struct
 type CommonServicesInterface interface {
@@ -2,86 +2,40 @@ package middleware
 
 import (
 	"fmt"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/cyphera/cyphera-api/libs/go/logger"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
-	"golang.org/x/time/rate"
 )
 
-// RateLimiter holds the configuration for rate limiting
+// RateLimiter applies a single rate limit policy to every client, keyed by API key, user or IP
 type RateLimiter struct {
-	// limiters stores rate limiters per IP/API key
-	limiters sync.Map
-	// rate is the number of requests per second allowed
-	rate int
-	// burst is the maximum burst size
-	burst int
-	// cleanupInterval is how often to clean up old limiters
-	cleanupInterval time.Duration
+	// store tracks request counts per client
+	store RateLimitStore
+	// policy is the limit applied to each client
+	policy RateLimitPolicy
 }
 
-// limiterEntry holds a rate limiter and its last access time
-type limiterEntry struct {
-	limiter    *rate.Limiter
-	lastAccess time.Time
-}
-
-// NewRateLimiter creates a new rate limiter with the specified rate and burst
+// NewRateLimiter creates a new in-memory rate limiter with the specified rate and burst
 func NewRateLimiter(requestsPerSecond, burst int) *RateLimiter {
-	rl := &RateLimiter{
-		rate:            requestsPerSecond,
-		burst:           burst,
-		cleanupInterval: 5 * time.Minute,
-	}
-
-	// Start cleanup goroutine
-	go rl.cleanup()
-
-	return rl
-}
-
-// cleanup removes old limiters that haven't been accessed recently
-func (rl *RateLimiter) cleanup() {
-	ticker := time.NewTicker(rl.cleanupInterval)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		now := time.Now()
-		rl.limiters.Range(func(key, value interface{}) bool {
-			if entry, ok := value.(*limiterEntry); ok {
-				// Remove limiters that haven't been accessed in 10 minutes
-				if now.Sub(entry.lastAccess) > 10*time.Minute {
-					rl.limiters.Delete(key)
-				}
-			}
-			return true
-		})
-	}
+	return NewRateLimiterWithStore(NewMemoryRateLimitStore(), RateLimitPolicy{
+		Name:   fmt.Sprintf("ip-%drps", requestsPerSecond),
+		Limit:  requestsPerSecond,
+		Window: time.Second,
+		Burst:  burst,
+	})
 }
 
-// getLimiter returns the rate limiter for a specific key
-func (rl *RateLimiter) getLimiter(key string) *rate.Limiter {
-	// Try to get existing limiter
-	if val, ok := rl.limiters.Load(key); ok {
-		entry := val.(*limiterEntry)
-		entry.lastAccess = time.Now()
-		return entry.limiter
-	}
-
-	// Create new limiter
-	limiter := rate.NewLimiter(rate.Limit(rl.rate), rl.burst)
-	entry := &limiterEntry{
-		limiter:    limiter,
-		lastAccess: time.Now(),
+// NewRateLimiterWithStore creates a rate limiter that counts requests in the given store
+func NewRateLimiterWithStore(store RateLimitStore, policy RateLimitPolicy) *RateLimiter {
+	return &RateLimiter{
+		store:  store,
+		policy: policy,
 	}
-
-	// Store it (handle race condition where another goroutine created it)
-	actual, _ := rl.limiters.LoadOrStore(key, entry)
-	return actual.(*limiterEntry).limiter
 }
 
 // getClientIdentifier returns a unique identifier for the client
@@ -125,11 +79,23 @@ func (rl *RateLimiter) Middleware() gin.HandlerFunc {
 		// Get client identifier
 		clientID := getClientIdentifier(c)
 
-		// Get the limiter for this client
-		limiter := rl.getLimiter(clientID)
+		result, err := rl.store.Take(c.Request.Context(), clientID, rl.policy)
+		if err != nil {
+			// Fail open - an unavailable backend should not take the API down with it
+			if logger.Log != nil {
+				logger.Log.Warn("Rate limit backend unavailable, allowing request",
+					zap.String("client_id", clientID),
+					zap.Error(err),
+				)
+			}
+			c.Next()
+			return
+		}
+
+		writeRateLimitHeaders(c, rl.policy, result)
 
 		// Check if request is allowed
-		if !limiter.Allow() {
+		if !result.Allowed {
 			if logger.Log != nil {
 				logger.Log.Warn("Rate limit exceeded",
 					zap.String("client_id", clientID),
@@ -139,40 +105,52 @@ func (rl *RateLimiter) Middleware() gin.HandlerFunc {
 				)
 			}
 
-			// Add rate limit headers
-			c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", rl.rate))
-			c.Header("X-RateLimit-Remaining", "0")
-			c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Second).Unix()))
-			c.Header("Retry-After", "1")
-
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":       "Too many requests. Please try again later.",
-				"retry_after": 1,
-			})
-			c.Abort()
+			abortRateLimited(c, result)
 			return
 		}
 
-		// Add rate limit headers for successful requests
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", rl.rate))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", limiter.Burst()-int(limiter.Tokens())))
-		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Second).Unix()))
-
 		c.Next()
 	}
 }
 
-// MiddlewareWithConfig returns a Gin middleware with custom configuration per endpoint
-func (rl *RateLimiter) MiddlewareWithConfig(customRate, customBurst int) gin.HandlerFunc {
-	// Create a new rate limiter with custom settings
-	customRL := &RateLimiter{
-		rate:            customRate,
-		burst:           customBurst,
-		cleanupInterval: rl.cleanupInterval,
+// writeRateLimitHeaders sets the IETF RateLimit headers along with the legacy X-RateLimit headers
+func writeRateLimitHeaders(c *gin.Context, policy RateLimitPolicy, result RateLimitResult) {
+	reset := int64(math.Ceil(result.ResetAfter.Seconds()))
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.FormatInt(reset, 10))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, int64(policy.Window.Seconds())))
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(result.ResetAfter).Unix(), 10))
+}
+
+// abortRateLimited responds with 429 and a Retry-After header
+func abortRateLimited(c *gin.Context, result RateLimitResult) {
+	retryAfter := int64(math.Ceil(result.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
 	}
 
-	// Start cleanup for custom limiter
-	go customRL.cleanup()
+	c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       "Too many requests. Please try again later.",
+		"retry_after": retryAfter,
+	})
+	c.Abort()
+}
+
+// MiddlewareWithConfig returns a Gin middleware with custom configuration per endpoint
+func (rl *RateLimiter) MiddlewareWithConfig(customRate, customBurst int) gin.HandlerFunc {
+	// Create a new rate limiter with custom settings sharing the same store
+	customRL := NewRateLimiterWithStore(rl.store, RateLimitPolicy{
+		Name:   fmt.Sprintf("ip-%drps", customRate),
+		Limit:  customRate,
+		Window: time.Second,
+		Burst:  customBurst,
+	})
 
 	return customRL.Middleware()
 }
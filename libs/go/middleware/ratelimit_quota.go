@@ -0,0 +1,238 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// RouteClass groups routes that share a rate limit budget
+type RouteClass string
+
+const (
+	// RouteClassPublic covers unauthenticated payment pages and payment links
+	RouteClassPublic RouteClass = "public"
+	// RouteClassAuth covers sign-in and registration endpoints
+	RouteClassAuth RouteClass = "auth"
+	// RouteClassAuthenticated covers merchant API and dashboard requests
+	RouteClassAuthenticated RouteClass = "authenticated"
+)
+
+// Rate limit plans
+const (
+	RateLimitPlanFree       = "free"
+	RateLimitPlanStandard   = "standard"
+	RateLimitPlanEnterprise = "enterprise"
+)
+
+// RateLimitPlans holds the default budget for each plan and route class.
+// Rows in rate_limit_quotas select a plan per workspace or API key and may override the limits.
+var RateLimitPlans = map[string]map[RouteClass]RateLimitPolicy{
+	RateLimitPlanFree: {
+		RouteClassPublic:        {Limit: 60, Window: time.Minute},
+		RouteClassAuth:          {Limit: 10, Window: time.Minute},
+		RouteClassAuthenticated: {Limit: 300, Window: time.Minute},
+	},
+	RateLimitPlanStandard: {
+		RouteClassPublic:        {Limit: 120, Window: time.Minute},
+		RouteClassAuth:          {Limit: 20, Window: time.Minute},
+		RouteClassAuthenticated: {Limit: 1200, Window: time.Minute},
+	},
+	RateLimitPlanEnterprise: {
+		RouteClassPublic:        {Limit: 600, Window: time.Minute},
+		RouteClassAuth:          {Limit: 60, Window: time.Minute},
+		RouteClassAuthenticated: {Limit: 6000, Window: time.Minute},
+	},
+}
+
+// quotaCacheTTL is how long resolved quotas are reused before re-reading the database
+const quotaCacheTTL = time.Minute
+
+// resolvedQuota is a cached quota lookup for a workspace/API key/route class combination
+type resolvedQuota struct {
+	bucket    string
+	policy    RateLimitPolicy
+	expiresAt time.Time
+}
+
+// QuotaRateLimiter enforces per-workspace and per-API-key quotas for a route class.
+// Unauthenticated requests are limited per client IP using the default plan.
+type QuotaRateLimiter struct {
+	store       RateLimitStore
+	queries     db.Querier
+	defaultPlan string
+	// quotas caches resolved quotas keyed by workspace, API key and route class
+	quotas sync.Map
+	// cleanupInterval is how often expired quotas are dropped from the cache
+	cleanupInterval time.Duration
+}
+
+// NewQuotaRateLimiter creates a quota-aware rate limiter counting requests in the given store
+func NewQuotaRateLimiter(store RateLimitStore, queries db.Querier) *QuotaRateLimiter {
+	rl := &QuotaRateLimiter{
+		store:           store,
+		queries:         queries,
+		defaultPlan:     RateLimitPlanStandard,
+		cleanupInterval: 5 * time.Minute,
+	}
+
+	// Start cleanup goroutine
+	go rl.cleanup()
+
+	return rl
+}
+
+// cleanup periodically drops cached quotas of workspaces and API keys that stopped making requests
+func (rl *QuotaRateLimiter) cleanup() {
+	ticker := time.NewTicker(rl.cleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		rl.evictExpired(time.Now())
+	}
+}
+
+// evictExpired removes cached quotas that expired before now. Quotas in use are re-read when they expire
+// anyway, so only idle ones stay expired until the next cleanup.
+func (rl *QuotaRateLimiter) evictExpired(now time.Time) {
+	rl.quotas.Range(func(key, value interface{}) bool {
+		if entry, ok := value.(*resolvedQuota); ok && now.After(entry.expiresAt) {
+			rl.quotas.CompareAndDelete(key, value)
+		}
+		return true
+	})
+}
+
+// Middleware returns a Gin middleware enforcing the budget for the given route class
+func (rl *QuotaRateLimiter) Middleware(class RouteClass) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bucket, policy := rl.resolve(c, class)
+
+		result, err := rl.store.Take(c.Request.Context(), bucket, policy)
+		if err != nil {
+			// Fail open - an unavailable backend should not take the API down with it
+			if logger.Log != nil {
+				logger.Log.Warn("Rate limit backend unavailable, allowing request",
+					zap.String("bucket", bucket),
+					zap.String("route_class", string(class)),
+					zap.Error(err),
+				)
+			}
+			c.Next()
+			return
+		}
+
+		writeRateLimitHeaders(c, policy, result)
+
+		if !result.Allowed {
+			if logger.Log != nil {
+				logger.Log.Warn("Rate limit quota exceeded",
+					zap.String("bucket", bucket),
+					zap.String("policy", policy.Name),
+					zap.String("path", c.Request.URL.Path),
+					zap.String("method", c.Request.Method),
+				)
+			}
+
+			abortRateLimited(c, result)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// resolve returns the bucket key and policy for the current request
+func (rl *QuotaRateLimiter) resolve(c *gin.Context, class RouteClass) (string, RateLimitPolicy) {
+	workspaceID, err := uuid.Parse(c.GetString("workspaceID"))
+	if err != nil {
+		// Unauthenticated request - limit per client IP on the default plan
+		policy := rl.planPolicy(rl.defaultPlan, class)
+		return fmt.Sprintf("%s:ip:%s", class, c.ClientIP()), policy
+	}
+
+	var apiKeyID pgtype.UUID
+	if parsed, err := uuid.Parse(c.GetString("apiKeyID")); err == nil {
+		apiKeyID = pgtype.UUID{Bytes: parsed, Valid: true}
+	}
+
+	cacheKey := fmt.Sprintf("%s:%s:%s", workspaceID, c.GetString("apiKeyID"), class)
+	if cached, ok := rl.quotas.Load(cacheKey); ok {
+		entry := cached.(*resolvedQuota)
+		if time.Now().Before(entry.expiresAt) {
+			return entry.bucket, entry.policy
+		}
+	}
+
+	bucket, policy := rl.lookupQuota(c.Request.Context(), workspaceID, apiKeyID, class)
+	rl.quotas.Store(cacheKey, &resolvedQuota{
+		bucket:    bucket,
+		policy:    policy,
+		expiresAt: time.Now().Add(quotaCacheTTL),
+	})
+
+	return bucket, policy
+}
+
+// lookupQuota reads the most specific quota row and merges it with the plan defaults.
+// Key-level quotas get their own bucket; otherwise all requests in the workspace share one.
+func (rl *QuotaRateLimiter) lookupQuota(ctx context.Context, workspaceID uuid.UUID, apiKeyID pgtype.UUID, class RouteClass) (string, RateLimitPolicy) {
+	workspaceBucket := fmt.Sprintf("%s:workspace:%s", class, workspaceID)
+
+	quota, err := rl.queries.GetRateLimitQuota(ctx, db.GetRateLimitQuotaParams{
+		WorkspaceID: workspaceID,
+		ApiKeyID:    apiKeyID,
+		RouteClass:  string(class),
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) && logger.Log != nil {
+			logger.Log.Warn("Failed to load rate limit quota, using default plan",
+				zap.String("workspace_id", workspaceID.String()),
+				zap.Error(err),
+			)
+		}
+		return workspaceBucket, rl.planPolicy(rl.defaultPlan, class)
+	}
+
+	policy := rl.planPolicy(quota.Plan, class)
+	if quota.RequestsPerWindow.Valid {
+		policy.Limit = int(quota.RequestsPerWindow.Int32)
+		policy.Name = fmt.Sprintf("custom-%s", class)
+	}
+	if quota.WindowSeconds.Valid && quota.WindowSeconds.Int32 > 0 {
+		policy.Window = time.Duration(quota.WindowSeconds.Int32) * time.Second
+		policy.Name = fmt.Sprintf("custom-%s", class)
+	}
+
+	if quota.ApiKeyID.Valid {
+		return fmt.Sprintf("%s:key:%s", class, uuid.UUID(quota.ApiKeyID.Bytes)), policy
+	}
+	return workspaceBucket, policy
+}
+
+// planPolicy returns the default policy for a plan and route class
+func (rl *QuotaRateLimiter) planPolicy(plan string, class RouteClass) RateLimitPolicy {
+	budgets, ok := RateLimitPlans[plan]
+	if !ok {
+		plan = rl.defaultPlan
+		budgets = RateLimitPlans[plan]
+	}
+
+	policy, ok := budgets[class]
+	if !ok {
+		policy = budgets[RouteClassAuthenticated]
+	}
+	policy.Name = fmt.Sprintf("%s-%s", plan, class)
+
+	return policy
+}
@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitPolicy describes how many requests a client may make within a window
+type RateLimitPolicy struct {
+	// Name identifies the policy in the RateLimit-Policy header and in bucket keys
+	Name string
+	// Limit is the number of requests allowed per window
+	Limit int
+	// Window is the length of the rate limit window
+	Window time.Duration
+	// Burst is the maximum burst size for token bucket backends (defaults to Limit)
+	Burst int
+}
+
+// burst returns the effective burst size for the policy
+func (p RateLimitPolicy) burst() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Limit
+}
+
+// RateLimitResult is the outcome of counting a single request against a policy
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimitStore is the backend that tracks request counts for rate limit keys.
+// Implementations must be safe for concurrent use.
+type RateLimitStore interface {
+	// Take counts one request for key under the given policy
+	Take(ctx context.Context, key string, policy RateLimitPolicy) (RateLimitResult, error)
+}
+
+// MemoryRateLimitStore is a process-local token bucket store.
+// Limits are enforced per process, so it is only suitable for local development and single instances.
+type MemoryRateLimitStore struct {
+	// limiters stores rate limiters per bucket key
+	limiters sync.Map
+	// mu guards the lastAccess time of every entry
+	mu sync.Mutex
+	// cleanupInterval is how often to clean up old limiters
+	cleanupInterval time.Duration
+}
+
+// limiterEntry holds a rate limiter and its last access time
+type limiterEntry struct {
+	limiter    *rate.Limiter
+	lastAccess time.Time
+}
+
+// NewMemoryRateLimitStore creates a new in-memory rate limit store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	store := &MemoryRateLimitStore{
+		cleanupInterval: 5 * time.Minute,
+	}
+
+	// Start cleanup goroutine
+	go store.cleanup()
+
+	return store
+}
+
+// cleanup removes old limiters that haven't been accessed recently
+func (s *MemoryRateLimitStore) cleanup() {
+	ticker := time.NewTicker(s.cleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.evictIdle(time.Now(), 10*time.Minute)
+	}
+}
+
+// evictIdle removes limiters that haven't been accessed within maxIdle of now
+func (s *MemoryRateLimitStore) evictIdle(now time.Time, maxIdle time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.limiters.Range(func(key, value interface{}) bool {
+		if entry, ok := value.(*limiterEntry); ok && now.Sub(entry.lastAccess) > maxIdle {
+			s.limiters.Delete(key)
+		}
+		return true
+	})
+}
+
+// getLimiter returns the token bucket for a key, creating it on first use
+func (s *MemoryRateLimitStore) getLimiter(key string, policy RateLimitPolicy) *rate.Limiter {
+	bucketKey := fmt.Sprintf("%s:%d:%s", key, policy.Limit, policy.Window)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Try to get existing limiter
+	if val, ok := s.limiters.Load(bucketKey); ok {
+		entry := val.(*limiterEntry)
+		entry.lastAccess = time.Now()
+		return entry.limiter
+	}
+
+	// Create new limiter refilling Limit tokens per Window
+	perSecond := float64(policy.Limit) / policy.Window.Seconds()
+	entry := &limiterEntry{
+		limiter:    rate.NewLimiter(rate.Limit(perSecond), policy.burst()),
+		lastAccess: time.Now(),
+	}
+
+	s.limiters.Store(bucketKey, entry)
+	return entry.limiter
+}
+
+// Take counts one request against the key's token bucket
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, policy RateLimitPolicy) (RateLimitResult, error) {
+	limiter := s.getLimiter(key, policy)
+	now := time.Now()
+
+	result := RateLimitResult{Limit: policy.Limit}
+
+	reservation := limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// Not allowed yet - give the token back and report when to retry
+		reservation.CancelAt(now)
+		result.RetryAfter = delay
+		result.ResetAfter = delay
+		return result, nil
+	}
+
+	tokens := limiter.TokensAt(now)
+	result.Allowed = true
+	result.Remaining = int(math.Max(0, math.Floor(tokens)))
+
+	// Time until the bucket is full again
+	if missing := float64(policy.burst()) - tokens; missing > 0 && limiter.Limit() > 0 {
+		result.ResetAfter = time.Duration(missing / float64(limiter.Limit()) * float64(time.Second))
+	}
+
+	return result, nil
+}
+
+// PostgresRateLimitStore is a shared fixed-window store backed by the rate_limit_counters table.
+// Every API instance counts against the same rows, so limits hold across Lambda invocations.
+type PostgresRateLimitStore struct {
+	queries db.Querier
+	// cleanupInterval is how often expired counters are purged
+	cleanupInterval time.Duration
+}
+
+// NewPostgresRateLimitStore creates a new Postgres-backed rate limit store
+func NewPostgresRateLimitStore(queries db.Querier) *PostgresRateLimitStore {
+	store := &PostgresRateLimitStore{
+		queries:         queries,
+		cleanupInterval: 5 * time.Minute,
+	}
+
+	// Start cleanup goroutine
+	go store.cleanup()
+
+	return store
+}
+
+// cleanup periodically deletes counters whose window has passed
+func (s *PostgresRateLimitStore) cleanup() {
+	ticker := time.NewTicker(s.cleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.queries.DeleteExpiredRateLimitCounters(context.Background()); err != nil && logger.Log != nil {
+			logger.Log.Warn("Failed to delete expired rate limit counters", zap.Error(err))
+		}
+	}
+}
+
+// Take counts one request against the key's current fixed window
+func (s *PostgresRateLimitStore) Take(ctx context.Context, key string, policy RateLimitPolicy) (RateLimitResult, error) {
+	now := time.Now().UTC()
+	windowStart := now.Truncate(policy.Window)
+	windowEnd := windowStart.Add(policy.Window)
+
+	count, err := s.queries.IncrementRateLimitCounter(ctx, db.IncrementRateLimitCounterParams{
+		BucketKey:   fmt.Sprintf("%s:%s", policy.Name, key),
+		WindowStart: pgtype.Timestamptz{Time: windowStart, Valid: true},
+		ExpiresAt:   pgtype.Timestamptz{Time: windowEnd, Valid: true},
+	})
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("failed to increment rate limit counter: %w", err)
+	}
+
+	result := RateLimitResult{
+		Allowed:    int(count) <= policy.Limit,
+		Limit:      policy.Limit,
+		Remaining:  policy.Limit - int(count),
+		ResetAfter: windowEnd.Sub(now),
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	if !result.Allowed {
+		result.RetryAfter = result.ResetAfter
+	}
+
+	return result, nil
+}
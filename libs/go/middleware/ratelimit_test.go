@@ -0,0 +1,481 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func init() {
+	logger.InitLogger("test")
+	gin.SetMode(gin.TestMode)
+}
+
+// recordingStore counts requests per key in a fixed budget and records the keys and policies it saw
+type recordingStore struct {
+	mu       sync.Mutex
+	counts   map[string]int
+	keys     []string
+	policies []RateLimitPolicy
+	err      error
+}
+
+func newRecordingStore() *recordingStore {
+	return &recordingStore{counts: make(map[string]int)}
+}
+
+func (s *recordingStore) Take(_ context.Context, key string, policy RateLimitPolicy) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = append(s.keys, key)
+	s.policies = append(s.policies, policy)
+	if s.err != nil {
+		return RateLimitResult{}, s.err
+	}
+
+	s.counts[key]++
+	remaining := policy.Limit - s.counts[key]
+	result := RateLimitResult{
+		Allowed:    remaining >= 0,
+		Limit:      policy.Limit,
+		Remaining:  max(remaining, 0),
+		ResetAfter: policy.Window,
+	}
+	if !result.Allowed {
+		result.RetryAfter = policy.Window
+	}
+	return result, nil
+}
+
+func TestMemoryRateLimitStore_Take(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        RateLimitPolicy
+		requests      int
+		wantAllowed   []bool
+		wantRemaining []int
+	}{
+		{
+			name:          "allows requests up to the limit",
+			policy:        RateLimitPolicy{Name: "test", Limit: 3, Window: time.Minute},
+			requests:      3,
+			wantAllowed:   []bool{true, true, true},
+			wantRemaining: []int{2, 1, 0},
+		},
+		{
+			name:          "rejects requests over the limit",
+			policy:        RateLimitPolicy{Name: "test", Limit: 2, Window: time.Minute},
+			requests:      4,
+			wantAllowed:   []bool{true, true, false, false},
+			wantRemaining: []int{1, 0, 0, 0},
+		},
+		{
+			name:          "burst overrides limit as bucket size",
+			policy:        RateLimitPolicy{Name: "test", Limit: 1, Window: time.Minute, Burst: 3},
+			requests:      4,
+			wantAllowed:   []bool{true, true, true, false},
+			wantRemaining: []int{2, 1, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &MemoryRateLimitStore{cleanupInterval: time.Minute}
+
+			for i := 0; i < tt.requests; i++ {
+				result, err := store.Take(context.Background(), "client", tt.policy)
+				require.NoError(t, err)
+
+				assert.Equal(t, tt.wantAllowed[i], result.Allowed, "request %d", i)
+				assert.Equal(t, tt.wantRemaining[i], result.Remaining, "request %d", i)
+				assert.Equal(t, tt.policy.Limit, result.Limit)
+				if !result.Allowed {
+					assert.Positive(t, result.RetryAfter)
+				}
+			}
+		})
+	}
+
+	t.Run("keys are limited independently", func(t *testing.T) {
+		store := &MemoryRateLimitStore{cleanupInterval: time.Minute}
+		policy := RateLimitPolicy{Name: "test", Limit: 1, Window: time.Minute}
+
+		first, err := store.Take(context.Background(), "a", policy)
+		require.NoError(t, err)
+		second, err := store.Take(context.Background(), "b", policy)
+		require.NoError(t, err)
+
+		assert.True(t, first.Allowed)
+		assert.True(t, second.Allowed)
+	})
+
+	t.Run("bucket refills after the window", func(t *testing.T) {
+		store := &MemoryRateLimitStore{cleanupInterval: time.Minute}
+		policy := RateLimitPolicy{Name: "test", Limit: 2, Window: 100 * time.Millisecond}
+
+		for i := 0; i < 2; i++ {
+			result, err := store.Take(context.Background(), "client", policy)
+			require.NoError(t, err)
+			require.True(t, result.Allowed)
+		}
+
+		result, err := store.Take(context.Background(), "client", policy)
+		require.NoError(t, err)
+		require.False(t, result.Allowed)
+		assert.LessOrEqual(t, result.RetryAfter, policy.Window)
+
+		time.Sleep(policy.Window + 20*time.Millisecond)
+
+		result, err = store.Take(context.Background(), "client", policy)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+
+	t.Run("idle limiters are evicted", func(t *testing.T) {
+		store := &MemoryRateLimitStore{cleanupInterval: time.Minute}
+		policy := RateLimitPolicy{Name: "test", Limit: 1, Window: time.Minute}
+
+		_, err := store.Take(context.Background(), "client", policy)
+		require.NoError(t, err)
+
+		store.evictIdle(time.Now().Add(time.Hour), 10*time.Minute)
+
+		result, err := store.Take(context.Background(), "client", policy)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+}
+
+func TestPostgresRateLimitStore_Take(t *testing.T) {
+	policy := RateLimitPolicy{Name: "standard-public", Limit: 3, Window: time.Minute}
+
+	tests := []struct {
+		name          string
+		count         int32
+		countErr      error
+		wantErr       bool
+		wantAllowed   bool
+		wantRemaining int
+	}{
+		{
+			name:          "first request in window",
+			count:         1,
+			wantAllowed:   true,
+			wantRemaining: 2,
+		},
+		{
+			name:          "last request in window",
+			count:         3,
+			wantAllowed:   true,
+			wantRemaining: 0,
+		},
+		{
+			name:          "over the limit",
+			count:         5,
+			wantAllowed:   false,
+			wantRemaining: 0,
+		},
+		{
+			name:     "counter error",
+			countErr: errors.New("connection refused"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			querier := mocks.NewMockQuerier(ctrl)
+			store := &PostgresRateLimitStore{queries: querier, cleanupInterval: time.Minute}
+
+			var params db.IncrementRateLimitCounterParams
+			querier.EXPECT().IncrementRateLimitCounter(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, arg db.IncrementRateLimitCounterParams) (int32, error) {
+					params = arg
+					return tt.count, tt.countErr
+				})
+
+			before := time.Now().UTC()
+			result, err := store.Take(context.Background(), "ip:10.0.0.1", policy)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "standard-public:ip:10.0.0.1", params.BucketKey)
+			assert.Equal(t, before.Truncate(time.Minute), params.WindowStart.Time)
+			assert.Equal(t, params.WindowStart.Time.Add(time.Minute), params.ExpiresAt.Time)
+
+			assert.Equal(t, tt.wantAllowed, result.Allowed)
+			assert.Equal(t, tt.wantRemaining, result.Remaining)
+			assert.Equal(t, policy.Limit, result.Limit)
+			assert.LessOrEqual(t, result.ResetAfter, time.Minute)
+			assert.Positive(t, result.ResetAfter)
+			if tt.wantAllowed {
+				assert.Zero(t, result.RetryAfter)
+			} else {
+				assert.Equal(t, result.ResetAfter, result.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestWriteRateLimitHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     RateLimitPolicy
+		result     RateLimitResult
+		wantLimit  string
+		wantRemain string
+		wantReset  string
+		wantPolicy string
+	}{
+		{
+			name:       "whole seconds",
+			policy:     RateLimitPolicy{Limit: 100, Window: time.Minute},
+			result:     RateLimitResult{Allowed: true, Limit: 100, Remaining: 42, ResetAfter: 30 * time.Second},
+			wantLimit:  "100",
+			wantRemain: "42",
+			wantReset:  "30",
+			wantPolicy: "100;w=60",
+		},
+		{
+			name:       "reset rounds up to the next second",
+			policy:     RateLimitPolicy{Limit: 10, Window: time.Second},
+			result:     RateLimitResult{Allowed: true, Limit: 10, Remaining: 9, ResetAfter: 100 * time.Millisecond},
+			wantLimit:  "10",
+			wantRemain: "9",
+			wantReset:  "1",
+			wantPolicy: "10;w=1",
+		},
+		{
+			name:       "exhausted",
+			policy:     RateLimitPolicy{Limit: 5, Window: time.Hour},
+			result:     RateLimitResult{Limit: 5, Remaining: 0, ResetAfter: 10 * time.Minute, RetryAfter: 10 * time.Minute},
+			wantLimit:  "5",
+			wantRemain: "0",
+			wantReset:  "600",
+			wantPolicy: "5;w=3600",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			writeRateLimitHeaders(c, tt.policy, tt.result)
+
+			assert.Equal(t, tt.wantLimit, w.Header().Get("RateLimit-Limit"))
+			assert.Equal(t, tt.wantRemain, w.Header().Get("RateLimit-Remaining"))
+			assert.Equal(t, tt.wantReset, w.Header().Get("RateLimit-Reset"))
+			assert.Equal(t, tt.wantPolicy, w.Header().Get("RateLimit-Policy"))
+
+			assert.Equal(t, tt.wantLimit, w.Header().Get("X-RateLimit-Limit"))
+			assert.Equal(t, tt.wantRemain, w.Header().Get("X-RateLimit-Remaining"))
+			reset, err := strconv.ParseInt(w.Header().Get("X-RateLimit-Reset"), 10, 64)
+			require.NoError(t, err)
+			assert.InDelta(t, time.Now().Add(tt.result.ResetAfter).Unix(), reset, 1)
+		})
+	}
+}
+
+// newQuotaRouter builds a router that authenticates every request as the given workspace and API key
+func newQuotaRouter(rl *QuotaRateLimiter, class RouteClass, workspaceID, apiKeyID string) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if workspaceID != "" {
+			c.Set("workspaceID", workspaceID)
+		}
+		if apiKeyID != "" {
+			c.Set("apiKeyID", apiKeyID)
+		}
+		c.Next()
+	})
+	router.Use(rl.Middleware(class))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestQuotaRateLimiter_Middleware(t *testing.T) {
+	workspaceID := uuid.New()
+	apiKeyID := uuid.New()
+
+	tests := []struct {
+		name        string
+		class       RouteClass
+		workspaceID string
+		apiKeyID    string
+		setupMocks  func(querier *mocks.MockQuerier)
+		requests    int
+		wantBucket  string
+		wantPolicy  RateLimitPolicy
+		wantStatus  []int
+	}{
+		{
+			name:       "unauthenticated requests are limited per IP on the default plan",
+			class:      RouteClassPublic,
+			setupMocks: func(querier *mocks.MockQuerier) {},
+			requests:   1,
+			wantBucket: "public:ip:192.0.2.1",
+			wantPolicy: RateLimitPolicy{Name: "standard-public", Limit: 120, Window: time.Minute},
+			wantStatus: []int{http.StatusOK},
+		},
+		{
+			name:        "workspace without a quota row uses the default plan",
+			class:       RouteClassAuthenticated,
+			workspaceID: workspaceID.String(),
+			setupMocks: func(querier *mocks.MockQuerier) {
+				querier.EXPECT().GetRateLimitQuota(gomock.Any(), db.GetRateLimitQuotaParams{
+					WorkspaceID: workspaceID,
+					RouteClass:  string(RouteClassAuthenticated),
+				}).Return(db.RateLimitQuota{}, pgx.ErrNoRows)
+			},
+			requests:   1,
+			wantBucket: "authenticated:workspace:" + workspaceID.String(),
+			wantPolicy: RateLimitPolicy{Name: "standard-authenticated", Limit: 1200, Window: time.Minute},
+			wantStatus: []int{http.StatusOK},
+		},
+		{
+			name:        "workspace plan selects plan budget",
+			class:       RouteClassAuthenticated,
+			workspaceID: workspaceID.String(),
+			setupMocks: func(querier *mocks.MockQuerier) {
+				querier.EXPECT().GetRateLimitQuota(gomock.Any(), gomock.Any()).
+					Return(db.RateLimitQuota{WorkspaceID: workspaceID, Plan: RateLimitPlanEnterprise}, nil)
+			},
+			requests:   1,
+			wantBucket: "authenticated:workspace:" + workspaceID.String(),
+			wantPolicy: RateLimitPolicy{Name: "enterprise-authenticated", Limit: 6000, Window: time.Minute},
+			wantStatus: []int{http.StatusOK},
+		},
+		{
+			name:        "API key quota overrides limits in its own bucket and is cached",
+			class:       RouteClassAuthenticated,
+			workspaceID: workspaceID.String(),
+			apiKeyID:    apiKeyID.String(),
+			setupMocks: func(querier *mocks.MockQuerier) {
+				querier.EXPECT().GetRateLimitQuota(gomock.Any(), db.GetRateLimitQuotaParams{
+					WorkspaceID: workspaceID,
+					ApiKeyID:    pgtype.UUID{Bytes: apiKeyID, Valid: true},
+					RouteClass:  string(RouteClassAuthenticated),
+				}).Return(db.RateLimitQuota{
+					WorkspaceID:       workspaceID,
+					ApiKeyID:          pgtype.UUID{Bytes: apiKeyID, Valid: true},
+					Plan:              RateLimitPlanFree,
+					RequestsPerWindow: pgtype.Int4{Int32: 2, Valid: true},
+					WindowSeconds:     pgtype.Int4{Int32: 10, Valid: true},
+				}, nil).Times(1)
+			},
+			requests:   3,
+			wantBucket: "authenticated:key:" + apiKeyID.String(),
+			wantPolicy: RateLimitPolicy{Name: "custom-authenticated", Limit: 2, Window: 10 * time.Second},
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:        "quota lookup failure falls back to the default plan",
+			class:       RouteClassAuth,
+			workspaceID: workspaceID.String(),
+			setupMocks: func(querier *mocks.MockQuerier) {
+				querier.EXPECT().GetRateLimitQuota(gomock.Any(), gomock.Any()).
+					Return(db.RateLimitQuota{}, errors.New("database unavailable"))
+			},
+			requests:   1,
+			wantBucket: "auth:workspace:" + workspaceID.String(),
+			wantPolicy: RateLimitPolicy{Name: "standard-auth", Limit: 20, Window: time.Minute},
+			wantStatus: []int{http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			querier := mocks.NewMockQuerier(ctrl)
+			tt.setupMocks(querier)
+
+			store := newRecordingStore()
+			router := newQuotaRouter(NewQuotaRateLimiter(store, querier), tt.class, tt.workspaceID, tt.apiKeyID)
+
+			for i := 0; i < tt.requests; i++ {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.RemoteAddr = "192.0.2.1:1234"
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.wantStatus[i], w.Code, "request %d", i)
+				assert.Equal(t, strconv.Itoa(tt.wantPolicy.Limit), w.Header().Get("RateLimit-Limit"))
+				if w.Code == http.StatusTooManyRequests {
+					assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+					assert.Equal(t, strconv.Itoa(int(tt.wantPolicy.Window.Seconds())), w.Header().Get("Retry-After"))
+				}
+			}
+
+			require.Len(t, store.keys, tt.requests)
+			for i := range store.keys {
+				assert.Equal(t, tt.wantBucket, store.keys[i])
+				assert.Equal(t, tt.wantPolicy, store.policies[i])
+			}
+		})
+	}
+
+	t.Run("backend errors fail open without headers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := newRecordingStore()
+		store.err = errors.New("backend unavailable")
+		router := newQuotaRouter(NewQuotaRateLimiter(store, mocks.NewMockQuerier(ctrl)), RouteClassPublic, "", "")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("expired quotas are evicted from the cache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		querier := mocks.NewMockQuerier(ctrl)
+		querier.EXPECT().GetRateLimitQuota(gomock.Any(), gomock.Any()).Return(db.RateLimitQuota{}, pgx.ErrNoRows)
+		rl := NewQuotaRateLimiter(newRecordingStore(), querier)
+		router := newQuotaRouter(rl, RouteClassAuthenticated, workspaceID.String(), "")
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		rl.evictExpired(time.Now())
+		cached := 0
+		rl.quotas.Range(func(_, _ interface{}) bool { cached++; return true })
+		assert.Equal(t, 1, cached)
+
+		rl.evictExpired(time.Now().Add(quotaCacheTTL + time.Second))
+		cached = 0
+		rl.quotas.Range(func(_, _ interface{}) bool { cached++; return true })
+		assert.Equal(t, 0, cached)
+	})
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: libs/go/db/querier.go
+// Source: db/querier.go
 //
 // Generated by this command:
 //
-//	mockgen -source=libs/go/db/querier.go -destination=libs/go/mocks/mock_querier.go -package=mocks
+//	mockgen -source=db/querier.go -destination=mocks/mock_querier.go -package=mocks
 //
 
 // Package mocks is a generated GoMock package.
@@ -148,6 +148,21 @@ func (mr *MockQuerierMockRecorder) AddCustomerToWorkspace(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCustomerToWorkspace", reflect.TypeOf((*MockQuerier)(nil).AddCustomerToWorkspace), ctx, arg)
 }
 
+// AddGasSponsorshipCustomerSpending mocks base method.
+func (m *MockQuerier) AddGasSponsorshipCustomerSpending(ctx context.Context, arg db.AddGasSponsorshipCustomerSpendingParams) (db.GasSponsorshipCustomerSpending, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGasSponsorshipCustomerSpending", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipCustomerSpending)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddGasSponsorshipCustomerSpending indicates an expected call of AddGasSponsorshipCustomerSpending.
+func (mr *MockQuerierMockRecorder) AddGasSponsorshipCustomerSpending(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGasSponsorshipCustomerSpending", reflect.TypeOf((*MockQuerier)(nil).AddGasSponsorshipCustomerSpending), ctx, arg)
+}
+
 // AddWorkspaceSupportedCurrency mocks base method.
 func (m *MockQuerier) AddWorkspaceSupportedCurrency(ctx context.Context, arg db.AddWorkspaceSupportedCurrencyParams) error {
 	m.ctrl.T.Helper()
@@ -192,6 +207,21 @@ func (mr *MockQuerierMockRecorder) ApplyProrationToPayment(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyProrationToPayment", reflect.TypeOf((*MockQuerier)(nil).ApplyProrationToPayment), ctx, arg)
 }
 
+// AttachGasSponsorshipReservationPayment mocks base method.
+func (m *MockQuerier) AttachGasSponsorshipReservationPayment(ctx context.Context, arg db.AttachGasSponsorshipReservationPaymentParams) (db.GasSponsorshipReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachGasSponsorshipReservationPayment", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachGasSponsorshipReservationPayment indicates an expected call of AttachGasSponsorshipReservationPayment.
+func (mr *MockQuerierMockRecorder) AttachGasSponsorshipReservationPayment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachGasSponsorshipReservationPayment", reflect.TypeOf((*MockQuerier)(nil).AttachGasSponsorshipReservationPayment), ctx, arg)
+}
+
 // BatchCreateSubscriptionLineItems mocks base method.
 func (m *MockQuerier) BatchCreateSubscriptionLineItems(ctx context.Context, arg []db.BatchCreateSubscriptionLineItemsParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -278,6 +308,21 @@ func (mr *MockQuerierMockRecorder) BulkUpdateSubscriptionSyncStatus(ctx, arg any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateSubscriptionSyncStatus", reflect.TypeOf((*MockQuerier)(nil).BulkUpdateSubscriptionSyncStatus), ctx, arg)
 }
 
+// CalculateCohortMetrics mocks base method.
+func (m *MockQuerier) CalculateCohortMetrics(ctx context.Context, arg db.CalculateCohortMetricsParams) ([]db.CalculateCohortMetricsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalculateCohortMetrics", ctx, arg)
+	ret0, _ := ret[0].([]db.CalculateCohortMetricsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalculateCohortMetrics indicates an expected call of CalculateCohortMetrics.
+func (mr *MockQuerierMockRecorder) CalculateCohortMetrics(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateCohortMetrics", reflect.TypeOf((*MockQuerier)(nil).CalculateCohortMetrics), ctx, arg)
+}
+
 // CalculateSubscriptionTotal mocks base method.
 func (m *MockQuerier) CalculateSubscriptionTotal(ctx context.Context, subscriptionID uuid.UUID) (any, error) {
 	m.ctrl.T.Helper()
@@ -413,6 +458,141 @@ func (mr *MockQuerierMockRecorder) CheckWorkspaceHasPaymentProvider(ctx, arg any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckWorkspaceHasPaymentProvider", reflect.TypeOf((*MockQuerier)(nil).CheckWorkspaceHasPaymentProvider), ctx, arg)
 }
 
+// ClaimAnalyticsExportJob mocks base method.
+func (m *MockQuerier) ClaimAnalyticsExportJob(ctx context.Context, arg db.ClaimAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimAnalyticsExportJob", ctx, arg)
+	ret0, _ := ret[0].(db.AnalyticsExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimAnalyticsExportJob indicates an expected call of ClaimAnalyticsExportJob.
+func (mr *MockQuerierMockRecorder) ClaimAnalyticsExportJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimAnalyticsExportJob", reflect.TypeOf((*MockQuerier)(nil).ClaimAnalyticsExportJob), ctx, arg)
+}
+
+// ClaimAnalyticsExportJobs mocks base method.
+func (m *MockQuerier) ClaimAnalyticsExportJobs(ctx context.Context, arg db.ClaimAnalyticsExportJobsParams) ([]db.AnalyticsExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimAnalyticsExportJobs", ctx, arg)
+	ret0, _ := ret[0].([]db.AnalyticsExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimAnalyticsExportJobs indicates an expected call of ClaimAnalyticsExportJobs.
+func (mr *MockQuerierMockRecorder) ClaimAnalyticsExportJobs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimAnalyticsExportJobs", reflect.TypeOf((*MockQuerier)(nil).ClaimAnalyticsExportJobs), ctx, arg)
+}
+
+// ClaimDueAnalyticsReportSchedules mocks base method.
+func (m *MockQuerier) ClaimDueAnalyticsReportSchedules(ctx context.Context, arg db.ClaimDueAnalyticsReportSchedulesParams) ([]db.ClaimDueAnalyticsReportSchedulesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueAnalyticsReportSchedules", ctx, arg)
+	ret0, _ := ret[0].([]db.ClaimDueAnalyticsReportSchedulesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueAnalyticsReportSchedules indicates an expected call of ClaimDueAnalyticsReportSchedules.
+func (mr *MockQuerierMockRecorder) ClaimDueAnalyticsReportSchedules(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueAnalyticsReportSchedules", reflect.TypeOf((*MockQuerier)(nil).ClaimDueAnalyticsReportSchedules), ctx, arg)
+}
+
+// ClaimDueSubscriptionRenewals mocks base method.
+func (m *MockQuerier) ClaimDueSubscriptionRenewals(ctx context.Context, arg db.ClaimDueSubscriptionRenewalsParams) ([]db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueSubscriptionRenewals", ctx, arg)
+	ret0, _ := ret[0].([]db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueSubscriptionRenewals indicates an expected call of ClaimDueSubscriptionRenewals.
+func (mr *MockQuerierMockRecorder) ClaimDueSubscriptionRenewals(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueSubscriptionRenewals", reflect.TypeOf((*MockQuerier)(nil).ClaimDueSubscriptionRenewals), ctx, arg)
+}
+
+// ClaimDueTreasurySweepRules mocks base method.
+func (m *MockQuerier) ClaimDueTreasurySweepRules(ctx context.Context, arg db.ClaimDueTreasurySweepRulesParams) ([]db.ClaimDueTreasurySweepRulesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueTreasurySweepRules", ctx, arg)
+	ret0, _ := ret[0].([]db.ClaimDueTreasurySweepRulesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueTreasurySweepRules indicates an expected call of ClaimDueTreasurySweepRules.
+func (mr *MockQuerierMockRecorder) ClaimDueTreasurySweepRules(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueTreasurySweepRules", reflect.TypeOf((*MockQuerier)(nil).ClaimDueTreasurySweepRules), ctx, arg)
+}
+
+// ClaimRedeemedSubscriptionRenewals mocks base method.
+func (m *MockQuerier) ClaimRedeemedSubscriptionRenewals(ctx context.Context, arg db.ClaimRedeemedSubscriptionRenewalsParams) ([]db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimRedeemedSubscriptionRenewals", ctx, arg)
+	ret0, _ := ret[0].([]db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimRedeemedSubscriptionRenewals indicates an expected call of ClaimRedeemedSubscriptionRenewals.
+func (mr *MockQuerierMockRecorder) ClaimRedeemedSubscriptionRenewals(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimRedeemedSubscriptionRenewals", reflect.TypeOf((*MockQuerier)(nil).ClaimRedeemedSubscriptionRenewals), ctx, arg)
+}
+
+// ClaimRedemptionTasks mocks base method.
+func (m *MockQuerier) ClaimRedemptionTasks(ctx context.Context, arg db.ClaimRedemptionTasksParams) ([]db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimRedemptionTasks", ctx, arg)
+	ret0, _ := ret[0].([]db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimRedemptionTasks indicates an expected call of ClaimRedemptionTasks.
+func (mr *MockQuerierMockRecorder) ClaimRedemptionTasks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimRedemptionTasks", reflect.TypeOf((*MockQuerier)(nil).ClaimRedemptionTasks), ctx, arg)
+}
+
+// CompleteAnalyticsExportJob mocks base method.
+func (m *MockQuerier) CompleteAnalyticsExportJob(ctx context.Context, arg db.CompleteAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteAnalyticsExportJob", ctx, arg)
+	ret0, _ := ret[0].(db.AnalyticsExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteAnalyticsExportJob indicates an expected call of CompleteAnalyticsExportJob.
+func (mr *MockQuerierMockRecorder) CompleteAnalyticsExportJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteAnalyticsExportJob", reflect.TypeOf((*MockQuerier)(nil).CompleteAnalyticsExportJob), ctx, arg)
+}
+
+// CompleteRedemptionTask mocks base method.
+func (m *MockQuerier) CompleteRedemptionTask(ctx context.Context, arg db.CompleteRedemptionTaskParams) (db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRedemptionTask", ctx, arg)
+	ret0, _ := ret[0].(db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteRedemptionTask indicates an expected call of CompleteRedemptionTask.
+func (mr *MockQuerierMockRecorder) CompleteRedemptionTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRedemptionTask", reflect.TypeOf((*MockQuerier)(nil).CompleteRedemptionTask), ctx, arg)
+}
+
 // CompleteSubscription mocks base method.
 func (m *MockQuerier) CompleteSubscription(ctx context.Context, id uuid.UUID) (db.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -428,6 +608,36 @@ func (mr *MockQuerierMockRecorder) CompleteSubscription(ctx, id any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteSubscription", reflect.TypeOf((*MockQuerier)(nil).CompleteSubscription), ctx, id)
 }
 
+// CompleteSubscriptionReauthorization mocks base method.
+func (m *MockQuerier) CompleteSubscriptionReauthorization(ctx context.Context, arg db.CompleteSubscriptionReauthorizationParams) (db.SubscriptionReauthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteSubscriptionReauthorization", ctx, arg)
+	ret0, _ := ret[0].(db.SubscriptionReauthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteSubscriptionReauthorization indicates an expected call of CompleteSubscriptionReauthorization.
+func (mr *MockQuerierMockRecorder) CompleteSubscriptionReauthorization(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteSubscriptionReauthorization", reflect.TypeOf((*MockQuerier)(nil).CompleteSubscriptionReauthorization), ctx, arg)
+}
+
+// CompleteSubscriptionRenewal mocks base method.
+func (m *MockQuerier) CompleteSubscriptionRenewal(ctx context.Context, arg db.CompleteSubscriptionRenewalParams) (db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteSubscriptionRenewal", ctx, arg)
+	ret0, _ := ret[0].(db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteSubscriptionRenewal indicates an expected call of CompleteSubscriptionRenewal.
+func (mr *MockQuerierMockRecorder) CompleteSubscriptionRenewal(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteSubscriptionRenewal", reflect.TypeOf((*MockQuerier)(nil).CompleteSubscriptionRenewal), ctx, arg)
+}
+
 // CountActiveSubscriptions mocks base method.
 func (m *MockQuerier) CountActiveSubscriptions(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -443,6 +653,21 @@ func (mr *MockQuerierMockRecorder) CountActiveSubscriptions(ctx any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveSubscriptions", reflect.TypeOf((*MockQuerier)(nil).CountActiveSubscriptions), ctx)
 }
 
+// CountAnalyticsExportJobs mocks base method.
+func (m *MockQuerier) CountAnalyticsExportJobs(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAnalyticsExportJobs", ctx, workspaceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAnalyticsExportJobs indicates an expected call of CountAnalyticsExportJobs.
+func (mr *MockQuerierMockRecorder) CountAnalyticsExportJobs(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAnalyticsExportJobs", reflect.TypeOf((*MockQuerier)(nil).CountAnalyticsExportJobs), ctx, workspaceID)
+}
+
 // CountCustomerWallets mocks base method.
 func (m *MockQuerier) CountCustomerWallets(ctx context.Context, customerID uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
@@ -533,6 +758,21 @@ func (mr *MockQuerierMockRecorder) CountFailedSubscriptionAttemptsByErrorType(ct
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFailedSubscriptionAttemptsByErrorType", reflect.TypeOf((*MockQuerier)(nil).CountFailedSubscriptionAttemptsByErrorType), ctx, errorType)
 }
 
+// CountGasSponsorshipLedgerEntries mocks base method.
+func (m *MockQuerier) CountGasSponsorshipLedgerEntries(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountGasSponsorshipLedgerEntries", ctx, workspaceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountGasSponsorshipLedgerEntries indicates an expected call of CountGasSponsorshipLedgerEntries.
+func (mr *MockQuerierMockRecorder) CountGasSponsorshipLedgerEntries(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountGasSponsorshipLedgerEntries", reflect.TypeOf((*MockQuerier)(nil).CountGasSponsorshipLedgerEntries), ctx, workspaceID)
+}
+
 // CountInvoicesByProvider mocks base method.
 func (m *MockQuerier) CountInvoicesByProvider(ctx context.Context, arg db.CountInvoicesByProviderParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -578,6 +818,36 @@ func (mr *MockQuerierMockRecorder) CountInvoicesByWorkspace(ctx, workspaceID any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountInvoicesByWorkspace", reflect.TypeOf((*MockQuerier)(nil).CountInvoicesByWorkspace), ctx, workspaceID)
 }
 
+// CountMRRMovements mocks base method.
+func (m *MockQuerier) CountMRRMovements(ctx context.Context, arg db.CountMRRMovementsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMRRMovements", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMRRMovements indicates an expected call of CountMRRMovements.
+func (mr *MockQuerierMockRecorder) CountMRRMovements(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMRRMovements", reflect.TypeOf((*MockQuerier)(nil).CountMRRMovements), ctx, arg)
+}
+
+// CountOverlappingTaxRates mocks base method.
+func (m *MockQuerier) CountOverlappingTaxRates(ctx context.Context, arg db.CountOverlappingTaxRatesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOverlappingTaxRates", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOverlappingTaxRates indicates an expected call of CountOverlappingTaxRates.
+func (mr *MockQuerierMockRecorder) CountOverlappingTaxRates(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOverlappingTaxRates", reflect.TypeOf((*MockQuerier)(nil).CountOverlappingTaxRates), ctx, arg)
+}
+
 // CountPaymentsByWorkspace mocks base method.
 func (m *MockQuerier) CountPaymentsByWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
@@ -653,6 +923,21 @@ func (mr *MockQuerierMockRecorder) CountProviderAccountsByWorkspace(ctx, workspa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountProviderAccountsByWorkspace", reflect.TypeOf((*MockQuerier)(nil).CountProviderAccountsByWorkspace), ctx, workspaceID)
 }
 
+// CountRedemptionTasks mocks base method.
+func (m *MockQuerier) CountRedemptionTasks(ctx context.Context, status pgtype.Text) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRedemptionTasks", ctx, status)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRedemptionTasks indicates an expected call of CountRedemptionTasks.
+func (mr *MockQuerierMockRecorder) CountRedemptionTasks(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRedemptionTasks", reflect.TypeOf((*MockQuerier)(nil).CountRedemptionTasks), ctx, status)
+}
+
 // CountSubscriptionEventDetails mocks base method.
 func (m *MockQuerier) CountSubscriptionEventDetails(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
@@ -728,6 +1013,21 @@ func (mr *MockQuerierMockRecorder) CountSubscriptionLineItems(ctx, subscriptionI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSubscriptionLineItems", reflect.TypeOf((*MockQuerier)(nil).CountSubscriptionLineItems), ctx, subscriptionID)
 }
 
+// CountSubscriptionRenewals mocks base method.
+func (m *MockQuerier) CountSubscriptionRenewals(ctx context.Context, status pgtype.Text) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSubscriptionRenewals", ctx, status)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSubscriptionRenewals indicates an expected call of CountSubscriptionRenewals.
+func (mr *MockQuerierMockRecorder) CountSubscriptionRenewals(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSubscriptionRenewals", reflect.TypeOf((*MockQuerier)(nil).CountSubscriptionRenewals), ctx, status)
+}
+
 // CountSubscriptions mocks base method.
 func (m *MockQuerier) CountSubscriptions(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -848,6 +1148,21 @@ func (mr *MockQuerierMockRecorder) CountSyncSessionsByProvider(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSyncSessionsByProvider", reflect.TypeOf((*MockQuerier)(nil).CountSyncSessionsByProvider), ctx, arg)
 }
 
+// CountTreasuryMovements mocks base method.
+func (m *MockQuerier) CountTreasuryMovements(ctx context.Context, arg db.CountTreasuryMovementsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTreasuryMovements", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTreasuryMovements indicates an expected call of CountTreasuryMovements.
+func (mr *MockQuerierMockRecorder) CountTreasuryMovements(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTreasuryMovements", reflect.TypeOf((*MockQuerier)(nil).CountTreasuryMovements), ctx, arg)
+}
+
 // CountVerifiedCustomerWallets mocks base method.
 func (m *MockQuerier) CountVerifiedCustomerWallets(ctx context.Context, customerID uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
@@ -908,6 +1223,36 @@ func (mr *MockQuerierMockRecorder) CountWorkspacePaymentConfigurations(ctx, work
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWorkspacePaymentConfigurations", reflect.TypeOf((*MockQuerier)(nil).CountWorkspacePaymentConfigurations), ctx, workspaceID)
 }
 
+// CountWorkspacePaymentConfigurationsByKeyVersion mocks base method.
+func (m *MockQuerier) CountWorkspacePaymentConfigurationsByKeyVersion(ctx context.Context) ([]db.CountWorkspacePaymentConfigurationsByKeyVersionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWorkspacePaymentConfigurationsByKeyVersion", ctx)
+	ret0, _ := ret[0].([]db.CountWorkspacePaymentConfigurationsByKeyVersionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWorkspacePaymentConfigurationsByKeyVersion indicates an expected call of CountWorkspacePaymentConfigurationsByKeyVersion.
+func (mr *MockQuerierMockRecorder) CountWorkspacePaymentConfigurationsByKeyVersion(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWorkspacePaymentConfigurationsByKeyVersion", reflect.TypeOf((*MockQuerier)(nil).CountWorkspacePaymentConfigurationsByKeyVersion), ctx)
+}
+
+// CountWorkspaceWebhookSecretsByKeyVersion mocks base method.
+func (m *MockQuerier) CountWorkspaceWebhookSecretsByKeyVersion(ctx context.Context) ([]db.CountWorkspaceWebhookSecretsByKeyVersionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWorkspaceWebhookSecretsByKeyVersion", ctx)
+	ret0, _ := ret[0].([]db.CountWorkspaceWebhookSecretsByKeyVersionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWorkspaceWebhookSecretsByKeyVersion indicates an expected call of CountWorkspaceWebhookSecretsByKeyVersion.
+func (mr *MockQuerierMockRecorder) CountWorkspaceWebhookSecretsByKeyVersion(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWorkspaceWebhookSecretsByKeyVersion", reflect.TypeOf((*MockQuerier)(nil).CountWorkspaceWebhookSecretsByKeyVersion), ctx)
+}
+
 // CreateAPIKey mocks base method.
 func (m *MockQuerier) CreateAPIKey(ctx context.Context, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
 	m.ctrl.T.Helper()
@@ -938,6 +1283,36 @@ func (mr *MockQuerierMockRecorder) CreateAccount(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockQuerier)(nil).CreateAccount), ctx, arg)
 }
 
+// CreateAnalyticsExportJob mocks base method.
+func (m *MockQuerier) CreateAnalyticsExportJob(ctx context.Context, arg db.CreateAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAnalyticsExportJob", ctx, arg)
+	ret0, _ := ret[0].(db.AnalyticsExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAnalyticsExportJob indicates an expected call of CreateAnalyticsExportJob.
+func (mr *MockQuerierMockRecorder) CreateAnalyticsExportJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnalyticsExportJob", reflect.TypeOf((*MockQuerier)(nil).CreateAnalyticsExportJob), ctx, arg)
+}
+
+// CreateAnalyticsReportSchedule mocks base method.
+func (m *MockQuerier) CreateAnalyticsReportSchedule(ctx context.Context, arg db.CreateAnalyticsReportScheduleParams) (db.AnalyticsReportSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAnalyticsReportSchedule", ctx, arg)
+	ret0, _ := ret[0].(db.AnalyticsReportSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAnalyticsReportSchedule indicates an expected call of CreateAnalyticsReportSchedule.
+func (mr *MockQuerierMockRecorder) CreateAnalyticsReportSchedule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnalyticsReportSchedule", reflect.TypeOf((*MockQuerier)(nil).CreateAnalyticsReportSchedule), ctx, arg)
+}
+
 // CreateCircleUser mocks base method.
 func (m *MockQuerier) CreateCircleUser(ctx context.Context, arg db.CreateCircleUserParams) (db.CircleUser, error) {
 	m.ctrl.T.Helper()
@@ -983,6 +1358,21 @@ func (mr *MockQuerierMockRecorder) CreateCustomer(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomer", reflect.TypeOf((*MockQuerier)(nil).CreateCustomer), ctx, arg)
 }
 
+// CreateCustomerPortalSession mocks base method.
+func (m *MockQuerier) CreateCustomerPortalSession(ctx context.Context, arg db.CreateCustomerPortalSessionParams) (db.CustomerPortalSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCustomerPortalSession", ctx, arg)
+	ret0, _ := ret[0].(db.CustomerPortalSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCustomerPortalSession indicates an expected call of CreateCustomerPortalSession.
+func (mr *MockQuerierMockRecorder) CreateCustomerPortalSession(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomerPortalSession", reflect.TypeOf((*MockQuerier)(nil).CreateCustomerPortalSession), ctx, arg)
+}
+
 // CreateCustomerWallet mocks base method.
 func (m *MockQuerier) CreateCustomerWallet(ctx context.Context, arg db.CreateCustomerWalletParams) (db.CustomerWallet, error) {
 	m.ctrl.T.Helper()
@@ -1148,6 +1538,21 @@ func (mr *MockQuerierMockRecorder) CreateFailedSubscriptionAttempt(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFailedSubscriptionAttempt", reflect.TypeOf((*MockQuerier)(nil).CreateFailedSubscriptionAttempt), ctx, arg)
 }
 
+// CreateGasFeeEstimate mocks base method.
+func (m *MockQuerier) CreateGasFeeEstimate(ctx context.Context, arg db.CreateGasFeeEstimateParams) (db.GasFeeEstimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGasFeeEstimate", ctx, arg)
+	ret0, _ := ret[0].(db.GasFeeEstimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGasFeeEstimate indicates an expected call of CreateGasFeeEstimate.
+func (mr *MockQuerierMockRecorder) CreateGasFeeEstimate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGasFeeEstimate", reflect.TypeOf((*MockQuerier)(nil).CreateGasFeeEstimate), ctx, arg)
+}
+
 // CreateGasFeePayment mocks base method.
 func (m *MockQuerier) CreateGasFeePayment(ctx context.Context, arg db.CreateGasFeePaymentParams) (db.GasFeePayment, error) {
 	m.ctrl.T.Helper()
@@ -1178,6 +1583,21 @@ func (mr *MockQuerierMockRecorder) CreateGasSponsorshipConfig(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGasSponsorshipConfig", reflect.TypeOf((*MockQuerier)(nil).CreateGasSponsorshipConfig), ctx, arg)
 }
 
+// CreateGasSponsorshipRule mocks base method.
+func (m *MockQuerier) CreateGasSponsorshipRule(ctx context.Context, arg db.CreateGasSponsorshipRuleParams) (db.GasSponsorshipRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGasSponsorshipRule", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGasSponsorshipRule indicates an expected call of CreateGasSponsorshipRule.
+func (mr *MockQuerierMockRecorder) CreateGasSponsorshipRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGasSponsorshipRule", reflect.TypeOf((*MockQuerier)(nil).CreateGasSponsorshipRule), ctx, arg)
+}
+
 // CreateInvoice mocks base method.
 func (m *MockQuerier) CreateInvoice(ctx context.Context, arg db.CreateInvoiceParams) (db.Invoice, error) {
 	m.ctrl.T.Helper()
@@ -1268,6 +1688,21 @@ func (mr *MockQuerierMockRecorder) CreateInvoiceWithDetails(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvoiceWithDetails", reflect.TypeOf((*MockQuerier)(nil).CreateInvoiceWithDetails), ctx, arg)
 }
 
+// CreateMRRMovement mocks base method.
+func (m *MockQuerier) CreateMRRMovement(ctx context.Context, arg db.CreateMRRMovementParams) (db.MrrMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMRRMovement", ctx, arg)
+	ret0, _ := ret[0].(db.MrrMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMRRMovement indicates an expected call of CreateMRRMovement.
+func (mr *MockQuerierMockRecorder) CreateMRRMovement(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMRRMovement", reflect.TypeOf((*MockQuerier)(nil).CreateMRRMovement), ctx, arg)
+}
+
 // CreateNetwork mocks base method.
 func (m *MockQuerier) CreateNetwork(ctx context.Context, arg db.CreateNetworkParams) (db.Network, error) {
 	m.ctrl.T.Helper()
@@ -1448,6 +1883,21 @@ func (mr *MockQuerierMockRecorder) CreateScheduleChange(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduleChange", reflect.TypeOf((*MockQuerier)(nil).CreateScheduleChange), ctx, arg)
 }
 
+// CreateSplApproval mocks base method.
+func (m *MockQuerier) CreateSplApproval(ctx context.Context, arg db.CreateSplApprovalParams) (db.SplApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSplApproval", ctx, arg)
+	ret0, _ := ret[0].(db.SplApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSplApproval indicates an expected call of CreateSplApproval.
+func (mr *MockQuerierMockRecorder) CreateSplApproval(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSplApproval", reflect.TypeOf((*MockQuerier)(nil).CreateSplApproval), ctx, arg)
+}
+
 // CreateSubscription mocks base method.
 func (m *MockQuerier) CreateSubscription(ctx context.Context, arg db.CreateSubscriptionParams) (db.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -1538,23 +1988,98 @@ func (mr *MockQuerierMockRecorder) CreateSyncSession(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSyncSession", reflect.TypeOf((*MockQuerier)(nil).CreateSyncSession), ctx, arg)
 }
 
-// CreateToken mocks base method.
-func (m *MockQuerier) CreateToken(ctx context.Context, arg db.CreateTokenParams) (db.Token, error) {
+// CreateTaxIDVerification mocks base method.
+func (m *MockQuerier) CreateTaxIDVerification(ctx context.Context, arg db.CreateTaxIDVerificationParams) (db.TaxIDVerification, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateToken", ctx, arg)
-	ret0, _ := ret[0].(db.Token)
+	ret := m.ctrl.Call(m, "CreateTaxIDVerification", ctx, arg)
+	ret0, _ := ret[0].(db.TaxIDVerification)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateToken indicates an expected call of CreateToken.
-func (mr *MockQuerierMockRecorder) CreateToken(ctx, arg any) *gomock.Call {
+// CreateTaxIDVerification indicates an expected call of CreateTaxIDVerification.
+func (mr *MockQuerierMockRecorder) CreateTaxIDVerification(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockQuerier)(nil).CreateToken), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaxIDVerification", reflect.TypeOf((*MockQuerier)(nil).CreateTaxIDVerification), ctx, arg)
 }
 
-// CreateUser mocks base method.
-func (m *MockQuerier) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+// CreateTaxJurisdiction mocks base method.
+func (m *MockQuerier) CreateTaxJurisdiction(ctx context.Context, arg db.CreateTaxJurisdictionParams) (db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaxJurisdiction", ctx, arg)
+	ret0, _ := ret[0].(db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaxJurisdiction indicates an expected call of CreateTaxJurisdiction.
+func (mr *MockQuerierMockRecorder) CreateTaxJurisdiction(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaxJurisdiction", reflect.TypeOf((*MockQuerier)(nil).CreateTaxJurisdiction), ctx, arg)
+}
+
+// CreateTaxRate mocks base method.
+func (m *MockQuerier) CreateTaxRate(ctx context.Context, arg db.CreateTaxRateParams) (db.TaxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaxRate", ctx, arg)
+	ret0, _ := ret[0].(db.TaxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaxRate indicates an expected call of CreateTaxRate.
+func (mr *MockQuerierMockRecorder) CreateTaxRate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaxRate", reflect.TypeOf((*MockQuerier)(nil).CreateTaxRate), ctx, arg)
+}
+
+// CreateToken mocks base method.
+func (m *MockQuerier) CreateToken(ctx context.Context, arg db.CreateTokenParams) (db.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToken", ctx, arg)
+	ret0, _ := ret[0].(db.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *MockQuerierMockRecorder) CreateToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockQuerier)(nil).CreateToken), ctx, arg)
+}
+
+// CreateTreasuryMovement mocks base method.
+func (m *MockQuerier) CreateTreasuryMovement(ctx context.Context, arg db.CreateTreasuryMovementParams) (db.TreasuryMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTreasuryMovement", ctx, arg)
+	ret0, _ := ret[0].(db.TreasuryMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTreasuryMovement indicates an expected call of CreateTreasuryMovement.
+func (mr *MockQuerierMockRecorder) CreateTreasuryMovement(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTreasuryMovement", reflect.TypeOf((*MockQuerier)(nil).CreateTreasuryMovement), ctx, arg)
+}
+
+// CreateTreasurySweepRule mocks base method.
+func (m *MockQuerier) CreateTreasurySweepRule(ctx context.Context, arg db.CreateTreasurySweepRuleParams) (db.TreasurySweepRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTreasurySweepRule", ctx, arg)
+	ret0, _ := ret[0].(db.TreasurySweepRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTreasurySweepRule indicates an expected call of CreateTreasurySweepRule.
+func (mr *MockQuerierMockRecorder) CreateTreasurySweepRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTreasurySweepRule", reflect.TypeOf((*MockQuerier)(nil).CreateTreasurySweepRule), ctx, arg)
+}
+
+// CreateUser mocks base method.
+func (m *MockQuerier) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
 	ret0, _ := ret[0].(db.User)
@@ -1643,6 +2168,21 @@ func (mr *MockQuerierMockRecorder) CreateWorkspaceProviderAccount(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspaceProviderAccount", reflect.TypeOf((*MockQuerier)(nil).CreateWorkspaceProviderAccount), ctx, arg)
 }
 
+// CreateWorkspaceWebhookSecret mocks base method.
+func (m *MockQuerier) CreateWorkspaceWebhookSecret(ctx context.Context, arg db.CreateWorkspaceWebhookSecretParams) (db.WorkspaceWebhookSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspaceWebhookSecret", ctx, arg)
+	ret0, _ := ret[0].(db.WorkspaceWebhookSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkspaceWebhookSecret indicates an expected call of CreateWorkspaceWebhookSecret.
+func (mr *MockQuerierMockRecorder) CreateWorkspaceWebhookSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspaceWebhookSecret", reflect.TypeOf((*MockQuerier)(nil).CreateWorkspaceWebhookSecret), ctx, arg)
+}
+
 // DeactivateAllProductTokens mocks base method.
 func (m *MockQuerier) DeactivateAllProductTokens(ctx context.Context, productID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -1819,6 +2359,36 @@ func (mr *MockQuerierMockRecorder) DeactivateWorkspaceProviderAccount(ctx, arg a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateWorkspaceProviderAccount", reflect.TypeOf((*MockQuerier)(nil).DeactivateWorkspaceProviderAccount), ctx, arg)
 }
 
+// DeadLetterRedemptionTask mocks base method.
+func (m *MockQuerier) DeadLetterRedemptionTask(ctx context.Context, arg db.DeadLetterRedemptionTaskParams) (db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetterRedemptionTask", ctx, arg)
+	ret0, _ := ret[0].(db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeadLetterRedemptionTask indicates an expected call of DeadLetterRedemptionTask.
+func (mr *MockQuerierMockRecorder) DeadLetterRedemptionTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetterRedemptionTask", reflect.TypeOf((*MockQuerier)(nil).DeadLetterRedemptionTask), ctx, arg)
+}
+
+// DeferRedemptionTask mocks base method.
+func (m *MockQuerier) DeferRedemptionTask(ctx context.Context, arg db.DeferRedemptionTaskParams) (db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeferRedemptionTask", ctx, arg)
+	ret0, _ := ret[0].(db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeferRedemptionTask indicates an expected call of DeferRedemptionTask.
+func (mr *MockQuerierMockRecorder) DeferRedemptionTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeferRedemptionTask", reflect.TypeOf((*MockQuerier)(nil).DeferRedemptionTask), ctx, arg)
+}
+
 // DeleteAPIKey mocks base method.
 func (m *MockQuerier) DeleteAPIKey(ctx context.Context, arg db.DeleteAPIKeyParams) error {
 	m.ctrl.T.Helper()
@@ -1875,6 +2445,20 @@ func (mr *MockQuerierMockRecorder) DeleteAllSubscriptionLineItems(ctx, subscript
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllSubscriptionLineItems", reflect.TypeOf((*MockQuerier)(nil).DeleteAllSubscriptionLineItems), ctx, subscriptionID)
 }
 
+// DeleteAnalyticsReportSchedule mocks base method.
+func (m *MockQuerier) DeleteAnalyticsReportSchedule(ctx context.Context, arg db.DeleteAnalyticsReportScheduleParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAnalyticsReportSchedule", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAnalyticsReportSchedule indicates an expected call of DeleteAnalyticsReportSchedule.
+func (mr *MockQuerierMockRecorder) DeleteAnalyticsReportSchedule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAnalyticsReportSchedule", reflect.TypeOf((*MockQuerier)(nil).DeleteAnalyticsReportSchedule), ctx, arg)
+}
+
 // DeleteCircleUser mocks base method.
 func (m *MockQuerier) DeleteCircleUser(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -1989,6 +2573,63 @@ func (mr *MockQuerierMockRecorder) DeleteDunningEmailTemplate(ctx, id any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDunningEmailTemplate", reflect.TypeOf((*MockQuerier)(nil).DeleteDunningEmailTemplate), ctx, id)
 }
 
+// DeleteExpiredCustomerPortalSessions mocks base method.
+func (m *MockQuerier) DeleteExpiredCustomerPortalSessions(ctx context.Context, expiresAt pgtype.Timestamptz) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredCustomerPortalSessions", ctx, expiresAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredCustomerPortalSessions indicates an expected call of DeleteExpiredCustomerPortalSessions.
+func (mr *MockQuerierMockRecorder) DeleteExpiredCustomerPortalSessions(ctx, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredCustomerPortalSessions", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredCustomerPortalSessions), ctx, expiresAt)
+}
+
+// DeleteExpiredRateLimitCounters mocks base method.
+func (m *MockQuerier) DeleteExpiredRateLimitCounters(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRateLimitCounters", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredRateLimitCounters indicates an expected call of DeleteExpiredRateLimitCounters.
+func (mr *MockQuerierMockRecorder) DeleteExpiredRateLimitCounters(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRateLimitCounters", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredRateLimitCounters), ctx)
+}
+
+// DeleteExpiredWebhookReplayEntries mocks base method.
+func (m *MockQuerier) DeleteExpiredWebhookReplayEntries(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredWebhookReplayEntries", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredWebhookReplayEntries indicates an expected call of DeleteExpiredWebhookReplayEntries.
+func (mr *MockQuerierMockRecorder) DeleteExpiredWebhookReplayEntries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredWebhookReplayEntries", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredWebhookReplayEntries), ctx)
+}
+
+// DeleteExpiredWorkspaceWebhookSecrets mocks base method.
+func (m *MockQuerier) DeleteExpiredWorkspaceWebhookSecrets(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredWorkspaceWebhookSecrets", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredWorkspaceWebhookSecrets indicates an expected call of DeleteExpiredWorkspaceWebhookSecrets.
+func (mr *MockQuerierMockRecorder) DeleteExpiredWorkspaceWebhookSecrets(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredWorkspaceWebhookSecrets", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredWorkspaceWebhookSecrets), ctx)
+}
+
 // DeleteFailedSubscriptionAttempt mocks base method.
 func (m *MockQuerier) DeleteFailedSubscriptionAttempt(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -2003,6 +2644,21 @@ func (mr *MockQuerierMockRecorder) DeleteFailedSubscriptionAttempt(ctx, id any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFailedSubscriptionAttempt", reflect.TypeOf((*MockQuerier)(nil).DeleteFailedSubscriptionAttempt), ctx, id)
 }
 
+// DeleteGasSponsorshipRule mocks base method.
+func (m *MockQuerier) DeleteGasSponsorshipRule(ctx context.Context, arg db.DeleteGasSponsorshipRuleParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGasSponsorshipRule", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteGasSponsorshipRule indicates an expected call of DeleteGasSponsorshipRule.
+func (mr *MockQuerierMockRecorder) DeleteGasSponsorshipRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGasSponsorshipRule", reflect.TypeOf((*MockQuerier)(nil).DeleteGasSponsorshipRule), ctx, arg)
+}
+
 // DeleteInvoice mocks base method.
 func (m *MockQuerier) DeleteInvoice(ctx context.Context, arg db.DeleteInvoiceParams) error {
 	m.ctrl.T.Helper()
@@ -2172,6 +2828,35 @@ func (mr *MockQuerierMockRecorder) DeleteProductTokensByProduct(ctx, productID a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProductTokensByProduct", reflect.TypeOf((*MockQuerier)(nil).DeleteProductTokensByProduct), ctx, productID)
 }
 
+// DeleteScheduledTaxRate mocks base method.
+func (m *MockQuerier) DeleteScheduledTaxRate(ctx context.Context, id uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledTaxRate", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteScheduledTaxRate indicates an expected call of DeleteScheduledTaxRate.
+func (mr *MockQuerierMockRecorder) DeleteScheduledTaxRate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledTaxRate", reflect.TypeOf((*MockQuerier)(nil).DeleteScheduledTaxRate), ctx, id)
+}
+
+// DeleteStaleCohortMetrics mocks base method.
+func (m *MockQuerier) DeleteStaleCohortMetrics(ctx context.Context, arg db.DeleteStaleCohortMetricsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleCohortMetrics", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStaleCohortMetrics indicates an expected call of DeleteStaleCohortMetrics.
+func (mr *MockQuerierMockRecorder) DeleteStaleCohortMetrics(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleCohortMetrics", reflect.TypeOf((*MockQuerier)(nil).DeleteStaleCohortMetrics), ctx, arg)
+}
+
 // DeleteSubscription mocks base method.
 func (m *MockQuerier) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -2242,6 +2927,21 @@ func (mr *MockQuerierMockRecorder) DeleteToken(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToken", reflect.TypeOf((*MockQuerier)(nil).DeleteToken), ctx, id)
 }
 
+// DeleteTreasurySweepRule mocks base method.
+func (m *MockQuerier) DeleteTreasurySweepRule(ctx context.Context, arg db.DeleteTreasurySweepRuleParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTreasurySweepRule", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTreasurySweepRule indicates an expected call of DeleteTreasurySweepRule.
+func (mr *MockQuerierMockRecorder) DeleteTreasurySweepRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTreasurySweepRule", reflect.TypeOf((*MockQuerier)(nil).DeleteTreasurySweepRule), ctx, arg)
+}
+
 // DeleteUser mocks base method.
 func (m *MockQuerier) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -2256,6 +2956,20 @@ func (mr *MockQuerierMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockQuerier)(nil).DeleteUser), ctx, id)
 }
 
+// DeleteWebhookReplayEntry mocks base method.
+func (m *MockQuerier) DeleteWebhookReplayEntry(ctx context.Context, arg db.DeleteWebhookReplayEntryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookReplayEntry", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookReplayEntry indicates an expected call of DeleteWebhookReplayEntry.
+func (mr *MockQuerierMockRecorder) DeleteWebhookReplayEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookReplayEntry", reflect.TypeOf((*MockQuerier)(nil).DeleteWebhookReplayEntry), ctx, arg)
+}
+
 // DeleteWorkspace mocks base method.
 func (m *MockQuerier) DeleteWorkspace(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -2299,6 +3013,36 @@ func (mr *MockQuerierMockRecorder) DeleteWorkspaceProviderAccount(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceProviderAccount", reflect.TypeOf((*MockQuerier)(nil).DeleteWorkspaceProviderAccount), ctx, arg)
 }
 
+// EndTaxRate mocks base method.
+func (m *MockQuerier) EndTaxRate(ctx context.Context, arg db.EndTaxRateParams) (db.TaxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndTaxRate", ctx, arg)
+	ret0, _ := ret[0].(db.TaxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EndTaxRate indicates an expected call of EndTaxRate.
+func (mr *MockQuerierMockRecorder) EndTaxRate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndTaxRate", reflect.TypeOf((*MockQuerier)(nil).EndTaxRate), ctx, arg)
+}
+
+// EnqueueRedemptionTask mocks base method.
+func (m *MockQuerier) EnqueueRedemptionTask(ctx context.Context, arg db.EnqueueRedemptionTaskParams) (db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueRedemptionTask", ctx, arg)
+	ret0, _ := ret[0].(db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueRedemptionTask indicates an expected call of EnqueueRedemptionTask.
+func (mr *MockQuerierMockRecorder) EnqueueRedemptionTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueRedemptionTask", reflect.TypeOf((*MockQuerier)(nil).EnqueueRedemptionTask), ctx, arg)
+}
+
 // ExpirePaymentLinks mocks base method.
 func (m *MockQuerier) ExpirePaymentLinks(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -2313,6 +3057,21 @@ func (mr *MockQuerierMockRecorder) ExpirePaymentLinks(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePaymentLinks", reflect.TypeOf((*MockQuerier)(nil).ExpirePaymentLinks), ctx)
 }
 
+// FailAnalyticsExportJob mocks base method.
+func (m *MockQuerier) FailAnalyticsExportJob(ctx context.Context, arg db.FailAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailAnalyticsExportJob", ctx, arg)
+	ret0, _ := ret[0].(db.AnalyticsExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailAnalyticsExportJob indicates an expected call of FailAnalyticsExportJob.
+func (mr *MockQuerierMockRecorder) FailAnalyticsExportJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailAnalyticsExportJob", reflect.TypeOf((*MockQuerier)(nil).FailAnalyticsExportJob), ctx, arg)
+}
+
 // FailDunningCampaign mocks base method.
 func (m *MockQuerier) FailDunningCampaign(ctx context.Context, arg db.FailDunningCampaignParams) (db.DunningCampaign, error) {
 	m.ctrl.T.Helper()
@@ -2328,6 +3087,65 @@ func (mr *MockQuerierMockRecorder) FailDunningCampaign(ctx, arg any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailDunningCampaign", reflect.TypeOf((*MockQuerier)(nil).FailDunningCampaign), ctx, arg)
 }
 
+// FailRevertedSubscriptionRenewal mocks base method.
+func (m *MockQuerier) FailRevertedSubscriptionRenewal(ctx context.Context, arg db.FailRevertedSubscriptionRenewalParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailRevertedSubscriptionRenewal", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailRevertedSubscriptionRenewal indicates an expected call of FailRevertedSubscriptionRenewal.
+func (mr *MockQuerierMockRecorder) FailRevertedSubscriptionRenewal(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailRevertedSubscriptionRenewal", reflect.TypeOf((*MockQuerier)(nil).FailRevertedSubscriptionRenewal), ctx, arg)
+}
+
+// FailSubscriptionRenewal mocks base method.
+func (m *MockQuerier) FailSubscriptionRenewal(ctx context.Context, arg db.FailSubscriptionRenewalParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailSubscriptionRenewal", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailSubscriptionRenewal indicates an expected call of FailSubscriptionRenewal.
+func (mr *MockQuerierMockRecorder) FailSubscriptionRenewal(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailSubscriptionRenewal", reflect.TypeOf((*MockQuerier)(nil).FailSubscriptionRenewal), ctx, arg)
+}
+
+// FailTreasuryMovement mocks base method.
+func (m *MockQuerier) FailTreasuryMovement(ctx context.Context, arg db.FailTreasuryMovementParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailTreasuryMovement", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailTreasuryMovement indicates an expected call of FailTreasuryMovement.
+func (mr *MockQuerierMockRecorder) FailTreasuryMovement(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailTreasuryMovement", reflect.TypeOf((*MockQuerier)(nil).FailTreasuryMovement), ctx, arg)
+}
+
+// FindTaxJurisdiction mocks base method.
+func (m *MockQuerier) FindTaxJurisdiction(ctx context.Context, arg db.FindTaxJurisdictionParams) (db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTaxJurisdiction", ctx, arg)
+	ret0, _ := ret[0].(db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTaxJurisdiction indicates an expected call of FindTaxJurisdiction.
+func (mr *MockQuerierMockRecorder) FindTaxJurisdiction(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTaxJurisdiction", reflect.TypeOf((*MockQuerier)(nil).FindTaxJurisdiction), ctx, arg)
+}
+
 // GetAPIKey mocks base method.
 func (m *MockQuerier) GetAPIKey(ctx context.Context, arg db.GetAPIKeyParams) (db.ApiKey, error) {
 	m.ctrl.T.Helper()
@@ -2448,6 +3266,21 @@ func (mr *MockQuerierMockRecorder) GetActiveAPIKeysCount(ctx, workspaceID any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveAPIKeysCount", reflect.TypeOf((*MockQuerier)(nil).GetActiveAPIKeysCount), ctx, workspaceID)
 }
 
+// GetActiveCustomerPortalSessionByTokenHash mocks base method.
+func (m *MockQuerier) GetActiveCustomerPortalSessionByTokenHash(ctx context.Context, tokenHash string) (db.CustomerPortalSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveCustomerPortalSessionByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(db.CustomerPortalSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveCustomerPortalSessionByTokenHash indicates an expected call of GetActiveCustomerPortalSessionByTokenHash.
+func (mr *MockQuerierMockRecorder) GetActiveCustomerPortalSessionByTokenHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveCustomerPortalSessionByTokenHash", reflect.TypeOf((*MockQuerier)(nil).GetActiveCustomerPortalSessionByTokenHash), ctx, tokenHash)
+}
+
 // GetActiveDunningCampaignForPayment mocks base method.
 func (m *MockQuerier) GetActiveDunningCampaignForPayment(ctx context.Context, paymentID pgtype.UUID) (db.DunningCampaign, error) {
 	m.ctrl.T.Helper()
@@ -2553,6 +3386,21 @@ func (mr *MockQuerierMockRecorder) GetActiveProductsByWalletID(ctx, walletID any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveProductsByWalletID", reflect.TypeOf((*MockQuerier)(nil).GetActiveProductsByWalletID), ctx, walletID)
 }
 
+// GetActiveSplApprovalByTokenAccount mocks base method.
+func (m *MockQuerier) GetActiveSplApprovalByTokenAccount(ctx context.Context, arg db.GetActiveSplApprovalByTokenAccountParams) (db.SplApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSplApprovalByTokenAccount", ctx, arg)
+	ret0, _ := ret[0].(db.SplApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSplApprovalByTokenAccount indicates an expected call of GetActiveSplApprovalByTokenAccount.
+func (mr *MockQuerierMockRecorder) GetActiveSplApprovalByTokenAccount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSplApprovalByTokenAccount", reflect.TypeOf((*MockQuerier)(nil).GetActiveSplApprovalByTokenAccount), ctx, arg)
+}
+
 // GetActiveSyncSessionsByProvider mocks base method.
 func (m *MockQuerier) GetActiveSyncSessionsByProvider(ctx context.Context, arg db.GetActiveSyncSessionsByProviderParams) ([]db.PaymentSyncSession, error) {
 	m.ctrl.T.Helper()
@@ -2658,6 +3506,36 @@ func (mr *MockQuerierMockRecorder) GetAllWorkspaces(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWorkspaces", reflect.TypeOf((*MockQuerier)(nil).GetAllWorkspaces), ctx)
 }
 
+// GetAnalyticsExportJob mocks base method.
+func (m *MockQuerier) GetAnalyticsExportJob(ctx context.Context, arg db.GetAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalyticsExportJob", ctx, arg)
+	ret0, _ := ret[0].(db.AnalyticsExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnalyticsExportJob indicates an expected call of GetAnalyticsExportJob.
+func (mr *MockQuerierMockRecorder) GetAnalyticsExportJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalyticsExportJob", reflect.TypeOf((*MockQuerier)(nil).GetAnalyticsExportJob), ctx, arg)
+}
+
+// GetAnalyticsReportSchedule mocks base method.
+func (m *MockQuerier) GetAnalyticsReportSchedule(ctx context.Context, arg db.GetAnalyticsReportScheduleParams) (db.AnalyticsReportSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalyticsReportSchedule", ctx, arg)
+	ret0, _ := ret[0].(db.AnalyticsReportSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnalyticsReportSchedule indicates an expected call of GetAnalyticsReportSchedule.
+func (mr *MockQuerierMockRecorder) GetAnalyticsReportSchedule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalyticsReportSchedule", reflect.TypeOf((*MockQuerier)(nil).GetAnalyticsReportSchedule), ctx, arg)
+}
+
 // GetAttemptsByType mocks base method.
 func (m *MockQuerier) GetAttemptsByType(ctx context.Context, campaignID uuid.UUID) ([]db.GetAttemptsByTypeRow, error) {
 	m.ctrl.T.Helper()
@@ -2703,6 +3581,21 @@ func (mr *MockQuerierMockRecorder) GetBusinessCustomers(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBusinessCustomers", reflect.TypeOf((*MockQuerier)(nil).GetBusinessCustomers), ctx, arg)
 }
 
+// GetCachedTaxIDVerification mocks base method.
+func (m *MockQuerier) GetCachedTaxIDVerification(ctx context.Context, arg db.GetCachedTaxIDVerificationParams) (db.TaxIDVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCachedTaxIDVerification", ctx, arg)
+	ret0, _ := ret[0].(db.TaxIDVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCachedTaxIDVerification indicates an expected call of GetCachedTaxIDVerification.
+func (mr *MockQuerierMockRecorder) GetCachedTaxIDVerification(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedTaxIDVerification", reflect.TypeOf((*MockQuerier)(nil).GetCachedTaxIDVerification), ctx, arg)
+}
+
 // GetCampaignsNeedingFinalAction mocks base method.
 func (m *MockQuerier) GetCampaignsNeedingFinalAction(ctx context.Context) ([]db.GetCampaignsNeedingFinalActionRow, error) {
 	m.ctrl.T.Helper()
@@ -2793,7 +3686,22 @@ func (mr *MockQuerierMockRecorder) GetCircleWalletByCircleWalletID(ctx, circleWa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCircleWalletByCircleWalletID", reflect.TypeOf((*MockQuerier)(nil).GetCircleWalletByCircleWalletID), ctx, circleWalletID)
 }
 
-// GetCurrentMRR mocks base method.
+// GetCircuitBreaker mocks base method.
+func (m *MockQuerier) GetCircuitBreaker(ctx context.Context, name string) (db.CircuitBreaker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCircuitBreaker", ctx, name)
+	ret0, _ := ret[0].(db.CircuitBreaker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCircuitBreaker indicates an expected call of GetCircuitBreaker.
+func (mr *MockQuerierMockRecorder) GetCircuitBreaker(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCircuitBreaker", reflect.TypeOf((*MockQuerier)(nil).GetCircuitBreaker), ctx, name)
+}
+
+// GetCurrentMRR mocks base method.
 func (m *MockQuerier) GetCurrentMRR(ctx context.Context, arg db.GetCurrentMRRParams) (db.GetCurrentMRRRow, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetCurrentMRR", ctx, arg)
@@ -2958,6 +3866,21 @@ func (mr *MockQuerierMockRecorder) GetCustomerMetricsTrend(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerMetricsTrend", reflect.TypeOf((*MockQuerier)(nil).GetCustomerMetricsTrend), ctx, arg)
 }
 
+// GetCustomerPortalSettings mocks base method.
+func (m *MockQuerier) GetCustomerPortalSettings(ctx context.Context, workspaceID uuid.UUID) (db.CustomerPortalSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerPortalSettings", ctx, workspaceID)
+	ret0, _ := ret[0].(db.CustomerPortalSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerPortalSettings indicates an expected call of GetCustomerPortalSettings.
+func (mr *MockQuerierMockRecorder) GetCustomerPortalSettings(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerPortalSettings", reflect.TypeOf((*MockQuerier)(nil).GetCustomerPortalSettings), ctx, workspaceID)
+}
+
 // GetCustomerWallet mocks base method.
 func (m *MockQuerier) GetCustomerWallet(ctx context.Context, id uuid.UUID) (db.CustomerWallet, error) {
 	m.ctrl.T.Helper()
@@ -3558,6 +4481,21 @@ func (mr *MockQuerierMockRecorder) GetFiatCurrencyByCode(ctx, code any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFiatCurrencyByCode", reflect.TypeOf((*MockQuerier)(nil).GetFiatCurrencyByCode), ctx, code)
 }
 
+// GetGasFeeEstimateAccuracy mocks base method.
+func (m *MockQuerier) GetGasFeeEstimateAccuracy(ctx context.Context, arg db.GetGasFeeEstimateAccuracyParams) ([]db.GetGasFeeEstimateAccuracyRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGasFeeEstimateAccuracy", ctx, arg)
+	ret0, _ := ret[0].([]db.GetGasFeeEstimateAccuracyRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGasFeeEstimateAccuracy indicates an expected call of GetGasFeeEstimateAccuracy.
+func (mr *MockQuerierMockRecorder) GetGasFeeEstimateAccuracy(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGasFeeEstimateAccuracy", reflect.TypeOf((*MockQuerier)(nil).GetGasFeeEstimateAccuracy), ctx, arg)
+}
+
 // GetGasFeeMetrics mocks base method.
 func (m *MockQuerier) GetGasFeeMetrics(ctx context.Context, arg db.GetGasFeeMetricsParams) (db.GetGasFeeMetricsRow, error) {
 	m.ctrl.T.Helper()
@@ -3678,6 +4616,51 @@ func (mr *MockQuerierMockRecorder) GetGasSponsorshipConfig(ctx, workspaceID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGasSponsorshipConfig", reflect.TypeOf((*MockQuerier)(nil).GetGasSponsorshipConfig), ctx, workspaceID)
 }
 
+// GetGasSponsorshipCustomerSpending mocks base method.
+func (m *MockQuerier) GetGasSponsorshipCustomerSpending(ctx context.Context, arg db.GetGasSponsorshipCustomerSpendingParams) (db.GasSponsorshipCustomerSpending, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGasSponsorshipCustomerSpending", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipCustomerSpending)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGasSponsorshipCustomerSpending indicates an expected call of GetGasSponsorshipCustomerSpending.
+func (mr *MockQuerierMockRecorder) GetGasSponsorshipCustomerSpending(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGasSponsorshipCustomerSpending", reflect.TypeOf((*MockQuerier)(nil).GetGasSponsorshipCustomerSpending), ctx, arg)
+}
+
+// GetGasSponsorshipReservation mocks base method.
+func (m *MockQuerier) GetGasSponsorshipReservation(ctx context.Context, arg db.GetGasSponsorshipReservationParams) (db.GasSponsorshipReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGasSponsorshipReservation", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGasSponsorshipReservation indicates an expected call of GetGasSponsorshipReservation.
+func (mr *MockQuerierMockRecorder) GetGasSponsorshipReservation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGasSponsorshipReservation", reflect.TypeOf((*MockQuerier)(nil).GetGasSponsorshipReservation), ctx, arg)
+}
+
+// GetGasSponsorshipRule mocks base method.
+func (m *MockQuerier) GetGasSponsorshipRule(ctx context.Context, arg db.GetGasSponsorshipRuleParams) (db.GasSponsorshipRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGasSponsorshipRule", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGasSponsorshipRule indicates an expected call of GetGasSponsorshipRule.
+func (mr *MockQuerierMockRecorder) GetGasSponsorshipRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGasSponsorshipRule", reflect.TypeOf((*MockQuerier)(nil).GetGasSponsorshipRule), ctx, arg)
+}
+
 // GetGasSponsorshipStats mocks base method.
 func (m *MockQuerier) GetGasSponsorshipStats(ctx context.Context, arg db.GetGasSponsorshipStatsParams) (db.GetGasSponsorshipStatsRow, error) {
 	m.ctrl.T.Helper()
@@ -4053,6 +5036,21 @@ func (mr *MockQuerierMockRecorder) GetLatestDashboardMetrics(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestDashboardMetrics", reflect.TypeOf((*MockQuerier)(nil).GetLatestDashboardMetrics), ctx, arg)
 }
 
+// GetLatestMRRMovement mocks base method.
+func (m *MockQuerier) GetLatestMRRMovement(ctx context.Context, subscriptionID uuid.UUID) (db.MrrMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestMRRMovement", ctx, subscriptionID)
+	ret0, _ := ret[0].(db.MrrMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestMRRMovement indicates an expected call of GetLatestMRRMovement.
+func (mr *MockQuerierMockRecorder) GetLatestMRRMovement(ctx, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestMRRMovement", reflect.TypeOf((*MockQuerier)(nil).GetLatestMRRMovement), ctx, subscriptionID)
+}
+
 // GetLatestStateChange mocks base method.
 func (m *MockQuerier) GetLatestStateChange(ctx context.Context, subscriptionID uuid.UUID) (db.SubscriptionStateHistory, error) {
 	m.ctrl.T.Helper()
@@ -4158,6 +5156,36 @@ func (mr *MockQuerierMockRecorder) GetLineItemsByProduct(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLineItemsByProduct", reflect.TypeOf((*MockQuerier)(nil).GetLineItemsByProduct), ctx, arg)
 }
 
+// GetMRRMovementTotals mocks base method.
+func (m *MockQuerier) GetMRRMovementTotals(ctx context.Context, arg db.GetMRRMovementTotalsParams) ([]db.GetMRRMovementTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMRRMovementTotals", ctx, arg)
+	ret0, _ := ret[0].([]db.GetMRRMovementTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMRRMovementTotals indicates an expected call of GetMRRMovementTotals.
+func (mr *MockQuerierMockRecorder) GetMRRMovementTotals(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMRRMovementTotals", reflect.TypeOf((*MockQuerier)(nil).GetMRRMovementTotals), ctx, arg)
+}
+
+// GetMRRMovementsByPeriod mocks base method.
+func (m *MockQuerier) GetMRRMovementsByPeriod(ctx context.Context, arg db.GetMRRMovementsByPeriodParams) ([]db.GetMRRMovementsByPeriodRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMRRMovementsByPeriod", ctx, arg)
+	ret0, _ := ret[0].([]db.GetMRRMovementsByPeriodRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMRRMovementsByPeriod indicates an expected call of GetMRRMovementsByPeriod.
+func (mr *MockQuerierMockRecorder) GetMRRMovementsByPeriod(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMRRMovementsByPeriod", reflect.TypeOf((*MockQuerier)(nil).GetMRRMovementsByPeriod), ctx, arg)
+}
+
 // GetMonthlyMetrics mocks base method.
 func (m *MockQuerier) GetMonthlyMetrics(ctx context.Context, arg db.GetMonthlyMetricsParams) ([]db.DashboardMetric, error) {
 	m.ctrl.T.Helper()
@@ -4248,6 +5276,21 @@ func (mr *MockQuerierMockRecorder) GetNextInvoiceNumber(ctx, workspaceID any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextInvoiceNumber", reflect.TypeOf((*MockQuerier)(nil).GetNextInvoiceNumber), ctx, workspaceID)
 }
 
+// GetOpenGasSponsorshipReservationByPayment mocks base method.
+func (m *MockQuerier) GetOpenGasSponsorshipReservationByPayment(ctx context.Context, arg db.GetOpenGasSponsorshipReservationByPaymentParams) (db.GasSponsorshipReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenGasSponsorshipReservationByPayment", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenGasSponsorshipReservationByPayment indicates an expected call of GetOpenGasSponsorshipReservationByPayment.
+func (mr *MockQuerierMockRecorder) GetOpenGasSponsorshipReservationByPayment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenGasSponsorshipReservationByPayment", reflect.TypeOf((*MockQuerier)(nil).GetOpenGasSponsorshipReservationByPayment), ctx, arg)
+}
+
 // GetOverdueInvoices mocks base method.
 func (m *MockQuerier) GetOverdueInvoices(ctx context.Context, arg db.GetOverdueInvoicesParams) ([]db.Invoice, error) {
 	m.ctrl.T.Helper()
@@ -4309,18 +5352,33 @@ func (mr *MockQuerierMockRecorder) GetPaymentBySubscriptionEvent(ctx, subscripti
 }
 
 // GetPaymentByTransactionHash mocks base method.
-func (m *MockQuerier) GetPaymentByTransactionHash(ctx context.Context, transactionHash pgtype.Text) (db.Payment, error) {
+func (m *MockQuerier) GetPaymentByTransactionHash(ctx context.Context, arg db.GetPaymentByTransactionHashParams) (db.Payment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPaymentByTransactionHash", ctx, transactionHash)
+	ret := m.ctrl.Call(m, "GetPaymentByTransactionHash", ctx, arg)
 	ret0, _ := ret[0].(db.Payment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPaymentByTransactionHash indicates an expected call of GetPaymentByTransactionHash.
-func (mr *MockQuerierMockRecorder) GetPaymentByTransactionHash(ctx, transactionHash any) *gomock.Call {
+func (mr *MockQuerierMockRecorder) GetPaymentByTransactionHash(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentByTransactionHash", reflect.TypeOf((*MockQuerier)(nil).GetPaymentByTransactionHash), ctx, arg)
+}
+
+// GetPaymentConfirmation mocks base method.
+func (m *MockQuerier) GetPaymentConfirmation(ctx context.Context, paymentID uuid.UUID) (db.PaymentConfirmation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentConfirmation", ctx, paymentID)
+	ret0, _ := ret[0].(db.PaymentConfirmation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentConfirmation indicates an expected call of GetPaymentConfirmation.
+func (mr *MockQuerierMockRecorder) GetPaymentConfirmation(ctx, paymentID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentByTransactionHash", reflect.TypeOf((*MockQuerier)(nil).GetPaymentByTransactionHash), ctx, transactionHash)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentConfirmation", reflect.TypeOf((*MockQuerier)(nil).GetPaymentConfirmation), ctx, paymentID)
 }
 
 // GetPaymentLink mocks base method.
@@ -4608,6 +5666,21 @@ func (mr *MockQuerierMockRecorder) GetPendingInvoicesForGeneration(ctx, nextRede
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingInvoicesForGeneration", reflect.TypeOf((*MockQuerier)(nil).GetPendingInvoicesForGeneration), ctx, nextRedemptionDate)
 }
 
+// GetPendingSubscriptionReauthorization mocks base method.
+func (m *MockQuerier) GetPendingSubscriptionReauthorization(ctx context.Context, subscriptionID uuid.UUID) (db.SubscriptionReauthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingSubscriptionReauthorization", ctx, subscriptionID)
+	ret0, _ := ret[0].(db.SubscriptionReauthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingSubscriptionReauthorization indicates an expected call of GetPendingSubscriptionReauthorization.
+func (mr *MockQuerierMockRecorder) GetPendingSubscriptionReauthorization(ctx, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingSubscriptionReauthorization", reflect.TypeOf((*MockQuerier)(nil).GetPendingSubscriptionReauthorization), ctx, subscriptionID)
+}
+
 // GetPrimaryCustomerWallet mocks base method.
 func (m *MockQuerier) GetPrimaryCustomerWallet(ctx context.Context, customerID uuid.UUID) (db.CustomerWallet, error) {
 	m.ctrl.T.Helper()
@@ -4968,6 +6041,21 @@ func (mr *MockQuerierMockRecorder) GetProviderSyncStatusByWorkspace(ctx, workspa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProviderSyncStatusByWorkspace", reflect.TypeOf((*MockQuerier)(nil).GetProviderSyncStatusByWorkspace), ctx, workspaceID)
 }
 
+// GetRateLimitQuota mocks base method.
+func (m *MockQuerier) GetRateLimitQuota(ctx context.Context, arg db.GetRateLimitQuotaParams) (db.RateLimitQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRateLimitQuota", ctx, arg)
+	ret0, _ := ret[0].(db.RateLimitQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRateLimitQuota indicates an expected call of GetRateLimitQuota.
+func (mr *MockQuerierMockRecorder) GetRateLimitQuota(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRateLimitQuota", reflect.TypeOf((*MockQuerier)(nil).GetRateLimitQuota), ctx, arg)
+}
+
 // GetRecentInvoiceActivities mocks base method.
 func (m *MockQuerier) GetRecentInvoiceActivities(ctx context.Context, arg db.GetRecentInvoiceActivitiesParams) ([]db.InvoiceActivity, error) {
 	m.ctrl.T.Helper()
@@ -5043,6 +6131,36 @@ func (mr *MockQuerierMockRecorder) GetRecentlyUsedWalletsWithCircleData(ctx, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentlyUsedWalletsWithCircleData", reflect.TypeOf((*MockQuerier)(nil).GetRecentlyUsedWalletsWithCircleData), ctx, arg)
 }
 
+// GetRedemptionTask mocks base method.
+func (m *MockQuerier) GetRedemptionTask(ctx context.Context, id uuid.UUID) (db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedemptionTask", ctx, id)
+	ret0, _ := ret[0].(db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRedemptionTask indicates an expected call of GetRedemptionTask.
+func (mr *MockQuerierMockRecorder) GetRedemptionTask(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedemptionTask", reflect.TypeOf((*MockQuerier)(nil).GetRedemptionTask), ctx, id)
+}
+
+// GetRedemptionTaskStats mocks base method.
+func (m *MockQuerier) GetRedemptionTaskStats(ctx context.Context, now pgtype.Timestamptz) ([]db.GetRedemptionTaskStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedemptionTaskStats", ctx, now)
+	ret0, _ := ret[0].([]db.GetRedemptionTaskStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRedemptionTaskStats indicates an expected call of GetRedemptionTaskStats.
+func (mr *MockQuerierMockRecorder) GetRedemptionTaskStats(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedemptionTaskStats", reflect.TypeOf((*MockQuerier)(nil).GetRedemptionTaskStats), ctx, now)
+}
+
 // GetRevenueGrowth mocks base method.
 func (m *MockQuerier) GetRevenueGrowth(ctx context.Context, arg db.GetRevenueGrowthParams) (db.GetRevenueGrowthRow, error) {
 	m.ctrl.T.Helper()
@@ -5073,6 +6191,36 @@ func (mr *MockQuerierMockRecorder) GetScheduleChange(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduleChange", reflect.TypeOf((*MockQuerier)(nil).GetScheduleChange), ctx, id)
 }
 
+// GetSplApprovalByDelegationID mocks base method.
+func (m *MockQuerier) GetSplApprovalByDelegationID(ctx context.Context, delegationID uuid.UUID) (db.SplApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSplApprovalByDelegationID", ctx, delegationID)
+	ret0, _ := ret[0].(db.SplApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSplApprovalByDelegationID indicates an expected call of GetSplApprovalByDelegationID.
+func (mr *MockQuerierMockRecorder) GetSplApprovalByDelegationID(ctx, delegationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSplApprovalByDelegationID", reflect.TypeOf((*MockQuerier)(nil).GetSplApprovalByDelegationID), ctx, delegationID)
+}
+
+// GetSplApprovalBySignature mocks base method.
+func (m *MockQuerier) GetSplApprovalBySignature(ctx context.Context, signature string) (db.SplApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSplApprovalBySignature", ctx, signature)
+	ret0, _ := ret[0].(db.SplApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSplApprovalBySignature indicates an expected call of GetSplApprovalBySignature.
+func (mr *MockQuerierMockRecorder) GetSplApprovalBySignature(ctx, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSplApprovalBySignature", reflect.TypeOf((*MockQuerier)(nil).GetSplApprovalBySignature), ctx, signature)
+}
+
 // GetSponsorshipConfigsNeedingReset mocks base method.
 func (m *MockQuerier) GetSponsorshipConfigsNeedingReset(ctx context.Context, dollar_1 pgtype.Date) ([]db.GasSponsorshipConfig, error) {
 	m.ctrl.T.Helper()
@@ -5298,6 +6446,51 @@ func (mr *MockQuerierMockRecorder) GetSubscriptionProrations(ctx, subscriptionID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionProrations", reflect.TypeOf((*MockQuerier)(nil).GetSubscriptionProrations), ctx, subscriptionID)
 }
 
+// GetSubscriptionRedemptionEventByTransactionHash mocks base method.
+func (m *MockQuerier) GetSubscriptionRedemptionEventByTransactionHash(ctx context.Context, arg db.GetSubscriptionRedemptionEventByTransactionHashParams) (db.SubscriptionEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionRedemptionEventByTransactionHash", ctx, arg)
+	ret0, _ := ret[0].(db.SubscriptionEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionRedemptionEventByTransactionHash indicates an expected call of GetSubscriptionRedemptionEventByTransactionHash.
+func (mr *MockQuerierMockRecorder) GetSubscriptionRedemptionEventByTransactionHash(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionRedemptionEventByTransactionHash", reflect.TypeOf((*MockQuerier)(nil).GetSubscriptionRedemptionEventByTransactionHash), ctx, arg)
+}
+
+// GetSubscriptionRenewal mocks base method.
+func (m *MockQuerier) GetSubscriptionRenewal(ctx context.Context, id uuid.UUID) (db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionRenewal", ctx, id)
+	ret0, _ := ret[0].(db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionRenewal indicates an expected call of GetSubscriptionRenewal.
+func (mr *MockQuerierMockRecorder) GetSubscriptionRenewal(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionRenewal", reflect.TypeOf((*MockQuerier)(nil).GetSubscriptionRenewal), ctx, id)
+}
+
+// GetSubscriptionRenewalByIdempotencyKey mocks base method.
+func (m *MockQuerier) GetSubscriptionRenewalByIdempotencyKey(ctx context.Context, idempotencyKey string) (db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionRenewalByIdempotencyKey", ctx, idempotencyKey)
+	ret0, _ := ret[0].(db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionRenewalByIdempotencyKey indicates an expected call of GetSubscriptionRenewalByIdempotencyKey.
+func (mr *MockQuerierMockRecorder) GetSubscriptionRenewalByIdempotencyKey(ctx, idempotencyKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionRenewalByIdempotencyKey", reflect.TypeOf((*MockQuerier)(nil).GetSubscriptionRenewalByIdempotencyKey), ctx, idempotencyKey)
+}
+
 // GetSubscriptionScheduledChanges mocks base method.
 func (m *MockQuerier) GetSubscriptionScheduledChanges(ctx context.Context, subscriptionID uuid.UUID) ([]db.SubscriptionScheduleChange, error) {
 	m.ctrl.T.Helper()
@@ -5628,6 +6821,66 @@ func (mr *MockQuerierMockRecorder) GetSyncSessionByProvider(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncSessionByProvider", reflect.TypeOf((*MockQuerier)(nil).GetSyncSessionByProvider), ctx, arg)
 }
 
+// GetTaxIDVerification mocks base method.
+func (m *MockQuerier) GetTaxIDVerification(ctx context.Context, id uuid.UUID) (db.TaxIDVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxIDVerification", ctx, id)
+	ret0, _ := ret[0].(db.TaxIDVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxIDVerification indicates an expected call of GetTaxIDVerification.
+func (mr *MockQuerierMockRecorder) GetTaxIDVerification(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxIDVerification", reflect.TypeOf((*MockQuerier)(nil).GetTaxIDVerification), ctx, id)
+}
+
+// GetTaxJurisdiction mocks base method.
+func (m *MockQuerier) GetTaxJurisdiction(ctx context.Context, id uuid.UUID) (db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxJurisdiction", ctx, id)
+	ret0, _ := ret[0].(db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxJurisdiction indicates an expected call of GetTaxJurisdiction.
+func (mr *MockQuerierMockRecorder) GetTaxJurisdiction(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxJurisdiction", reflect.TypeOf((*MockQuerier)(nil).GetTaxJurisdiction), ctx, id)
+}
+
+// GetTaxJurisdictionByCode mocks base method.
+func (m *MockQuerier) GetTaxJurisdictionByCode(ctx context.Context, code string) (db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxJurisdictionByCode", ctx, code)
+	ret0, _ := ret[0].(db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxJurisdictionByCode indicates an expected call of GetTaxJurisdictionByCode.
+func (mr *MockQuerierMockRecorder) GetTaxJurisdictionByCode(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxJurisdictionByCode", reflect.TypeOf((*MockQuerier)(nil).GetTaxJurisdictionByCode), ctx, code)
+}
+
+// GetTaxRate mocks base method.
+func (m *MockQuerier) GetTaxRate(ctx context.Context, id uuid.UUID) (db.TaxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxRate", ctx, id)
+	ret0, _ := ret[0].(db.TaxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxRate indicates an expected call of GetTaxRate.
+func (mr *MockQuerierMockRecorder) GetTaxRate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxRate", reflect.TypeOf((*MockQuerier)(nil).GetTaxRate), ctx, id)
+}
+
 // GetToken mocks base method.
 func (m *MockQuerier) GetToken(ctx context.Context, id uuid.UUID) (db.Token, error) {
 	m.ctrl.T.Helper()
@@ -5688,6 +6941,21 @@ func (mr *MockQuerierMockRecorder) GetTotalAmountBySubscription(ctx, subscriptio
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalAmountBySubscription", reflect.TypeOf((*MockQuerier)(nil).GetTotalAmountBySubscription), ctx, subscriptionID)
 }
 
+// GetTreasurySweepRule mocks base method.
+func (m *MockQuerier) GetTreasurySweepRule(ctx context.Context, arg db.GetTreasurySweepRuleParams) (db.TreasurySweepRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreasurySweepRule", ctx, arg)
+	ret0, _ := ret[0].(db.TreasurySweepRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreasurySweepRule indicates an expected call of GetTreasurySweepRule.
+func (mr *MockQuerierMockRecorder) GetTreasurySweepRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreasurySweepRule", reflect.TypeOf((*MockQuerier)(nil).GetTreasurySweepRule), ctx, arg)
+}
+
 // GetUnappliedProrations mocks base method.
 func (m *MockQuerier) GetUnappliedProrations(ctx context.Context, subscriptionID uuid.UUID) ([]db.SubscriptionProration, error) {
 	m.ctrl.T.Helper()
@@ -6273,6 +7541,21 @@ func (mr *MockQuerierMockRecorder) GetWorkspacesNeedingReset(ctx any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacesNeedingReset", reflect.TypeOf((*MockQuerier)(nil).GetWorkspacesNeedingReset), ctx)
 }
 
+// HalfOpenCircuitBreaker mocks base method.
+func (m *MockQuerier) HalfOpenCircuitBreaker(ctx context.Context, arg db.HalfOpenCircuitBreakerParams) (db.CircuitBreaker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HalfOpenCircuitBreaker", ctx, arg)
+	ret0, _ := ret[0].(db.CircuitBreaker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HalfOpenCircuitBreaker indicates an expected call of HalfOpenCircuitBreaker.
+func (mr *MockQuerierMockRecorder) HalfOpenCircuitBreaker(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HalfOpenCircuitBreaker", reflect.TypeOf((*MockQuerier)(nil).HalfOpenCircuitBreaker), ctx, arg)
+}
+
 // HardDeleteAccount mocks base method.
 func (m *MockQuerier) HardDeleteAccount(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -6331,6 +7614,21 @@ func (mr *MockQuerierMockRecorder) IncrementPaymentLinkUsage(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementPaymentLinkUsage", reflect.TypeOf((*MockQuerier)(nil).IncrementPaymentLinkUsage), ctx, arg)
 }
 
+// IncrementRateLimitCounter mocks base method.
+func (m *MockQuerier) IncrementRateLimitCounter(ctx context.Context, arg db.IncrementRateLimitCounterParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementRateLimitCounter", ctx, arg)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementRateLimitCounter indicates an expected call of IncrementRateLimitCounter.
+func (mr *MockQuerierMockRecorder) IncrementRateLimitCounter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementRateLimitCounter", reflect.TypeOf((*MockQuerier)(nil).IncrementRateLimitCounter), ctx, arg)
+}
+
 // IncrementSubscriptionRedemption mocks base method.
 func (m *MockQuerier) IncrementSubscriptionRedemption(ctx context.Context, arg db.IncrementSubscriptionRedemptionParams) (db.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -6346,6 +7644,36 @@ func (mr *MockQuerierMockRecorder) IncrementSubscriptionRedemption(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementSubscriptionRedemption", reflect.TypeOf((*MockQuerier)(nil).IncrementSubscriptionRedemption), ctx, arg)
 }
 
+// InsertWebhookReplayEntry mocks base method.
+func (m *MockQuerier) InsertWebhookReplayEntry(ctx context.Context, arg db.InsertWebhookReplayEntryParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookReplayEntry", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookReplayEntry indicates an expected call of InsertWebhookReplayEntry.
+func (mr *MockQuerierMockRecorder) InsertWebhookReplayEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookReplayEntry", reflect.TypeOf((*MockQuerier)(nil).InsertWebhookReplayEntry), ctx, arg)
+}
+
+// InterruptSubscriptionRenewal mocks base method.
+func (m *MockQuerier) InterruptSubscriptionRenewal(ctx context.Context, arg db.InterruptSubscriptionRenewalParams) (db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InterruptSubscriptionRenewal", ctx, arg)
+	ret0, _ := ret[0].(db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InterruptSubscriptionRenewal indicates an expected call of InterruptSubscriptionRenewal.
+func (mr *MockQuerierMockRecorder) InterruptSubscriptionRenewal(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InterruptSubscriptionRenewal", reflect.TypeOf((*MockQuerier)(nil).InterruptSubscriptionRenewal), ctx, arg)
+}
+
 // IsCustomerInWorkspace mocks base method.
 func (m *MockQuerier) IsCustomerInWorkspace(ctx context.Context, arg db.IsCustomerInWorkspaceParams) (bool, error) {
 	m.ctrl.T.Helper()
@@ -6391,6 +7719,21 @@ func (mr *MockQuerierMockRecorder) LinkPaymentToInvoice(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkPaymentToInvoice", reflect.TypeOf((*MockQuerier)(nil).LinkPaymentToInvoice), ctx, arg)
 }
 
+// ListAPIKeyUsage mocks base method.
+func (m *MockQuerier) ListAPIKeyUsage(ctx context.Context, arg db.ListAPIKeyUsageParams) ([]db.ApiKeyUsageDaily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeyUsage", ctx, arg)
+	ret0, _ := ret[0].([]db.ApiKeyUsageDaily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeyUsage indicates an expected call of ListAPIKeyUsage.
+func (mr *MockQuerierMockRecorder) ListAPIKeyUsage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeyUsage", reflect.TypeOf((*MockQuerier)(nil).ListAPIKeyUsage), ctx, arg)
+}
+
 // ListAPIKeys mocks base method.
 func (m *MockQuerier) ListAPIKeys(ctx context.Context, workspaceID uuid.UUID) ([]db.ApiKey, error) {
 	m.ctrl.T.Helper()
@@ -6496,6 +7839,21 @@ func (mr *MockQuerierMockRecorder) ListActiveFiatCurrencies(ctx any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveFiatCurrencies", reflect.TypeOf((*MockQuerier)(nil).ListActiveFiatCurrencies), ctx)
 }
 
+// ListActiveGasSponsorshipRules mocks base method.
+func (m *MockQuerier) ListActiveGasSponsorshipRules(ctx context.Context, workspaceID uuid.UUID) ([]db.GasSponsorshipRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveGasSponsorshipRules", ctx, workspaceID)
+	ret0, _ := ret[0].([]db.GasSponsorshipRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveGasSponsorshipRules indicates an expected call of ListActiveGasSponsorshipRules.
+func (mr *MockQuerierMockRecorder) ListActiveGasSponsorshipRules(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveGasSponsorshipRules", reflect.TypeOf((*MockQuerier)(nil).ListActiveGasSponsorshipRules), ctx, workspaceID)
+}
+
 // ListActiveNetworks mocks base method.
 func (m *MockQuerier) ListActiveNetworks(ctx context.Context) ([]db.Network, error) {
 	m.ctrl.T.Helper()
@@ -6601,11 +7959,26 @@ func (mr *MockQuerierMockRecorder) ListActiveWorkspacePaymentConfigurations(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveWorkspacePaymentConfigurations", reflect.TypeOf((*MockQuerier)(nil).ListActiveWorkspacePaymentConfigurations), ctx, workspaceID)
 }
 
-// ListAllFiatCurrencies mocks base method.
-func (m *MockQuerier) ListAllFiatCurrencies(ctx context.Context) ([]db.FiatCurrency, error) {
+// ListActiveWorkspaceWebhookSecrets mocks base method.
+func (m *MockQuerier) ListActiveWorkspaceWebhookSecrets(ctx context.Context, arg db.ListActiveWorkspaceWebhookSecretsParams) ([]db.WorkspaceWebhookSecret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAllFiatCurrencies", ctx)
-	ret0, _ := ret[0].([]db.FiatCurrency)
+	ret := m.ctrl.Call(m, "ListActiveWorkspaceWebhookSecrets", ctx, arg)
+	ret0, _ := ret[0].([]db.WorkspaceWebhookSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveWorkspaceWebhookSecrets indicates an expected call of ListActiveWorkspaceWebhookSecrets.
+func (mr *MockQuerierMockRecorder) ListActiveWorkspaceWebhookSecrets(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveWorkspaceWebhookSecrets", reflect.TypeOf((*MockQuerier)(nil).ListActiveWorkspaceWebhookSecrets), ctx, arg)
+}
+
+// ListAllFiatCurrencies mocks base method.
+func (m *MockQuerier) ListAllFiatCurrencies(ctx context.Context) ([]db.FiatCurrency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllFiatCurrencies", ctx)
+	ret0, _ := ret[0].([]db.FiatCurrency)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -6616,6 +7989,36 @@ func (mr *MockQuerierMockRecorder) ListAllFiatCurrencies(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllFiatCurrencies", reflect.TypeOf((*MockQuerier)(nil).ListAllFiatCurrencies), ctx)
 }
 
+// ListAnalyticsExportJobs mocks base method.
+func (m *MockQuerier) ListAnalyticsExportJobs(ctx context.Context, arg db.ListAnalyticsExportJobsParams) ([]db.AnalyticsExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAnalyticsExportJobs", ctx, arg)
+	ret0, _ := ret[0].([]db.AnalyticsExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAnalyticsExportJobs indicates an expected call of ListAnalyticsExportJobs.
+func (mr *MockQuerierMockRecorder) ListAnalyticsExportJobs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnalyticsExportJobs", reflect.TypeOf((*MockQuerier)(nil).ListAnalyticsExportJobs), ctx, arg)
+}
+
+// ListAnalyticsReportSchedules mocks base method.
+func (m *MockQuerier) ListAnalyticsReportSchedules(ctx context.Context, workspaceID uuid.UUID) ([]db.AnalyticsReportSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAnalyticsReportSchedules", ctx, workspaceID)
+	ret0, _ := ret[0].([]db.AnalyticsReportSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAnalyticsReportSchedules indicates an expected call of ListAnalyticsReportSchedules.
+func (mr *MockQuerierMockRecorder) ListAnalyticsReportSchedules(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnalyticsReportSchedules", reflect.TypeOf((*MockQuerier)(nil).ListAnalyticsReportSchedules), ctx, workspaceID)
+}
+
 // ListBaseProductsForAddon mocks base method.
 func (m *MockQuerier) ListBaseProductsForAddon(ctx context.Context, addonProductID uuid.UUID) ([]db.ListBaseProductsForAddonRow, error) {
 	m.ctrl.T.Helper()
@@ -6676,6 +8079,66 @@ func (mr *MockQuerierMockRecorder) ListCircleWalletsByWorkspaceID(ctx, workspace
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCircleWalletsByWorkspaceID", reflect.TypeOf((*MockQuerier)(nil).ListCircleWalletsByWorkspaceID), ctx, workspaceID)
 }
 
+// ListCohortMetrics mocks base method.
+func (m *MockQuerier) ListCohortMetrics(ctx context.Context, arg db.ListCohortMetricsParams) ([]db.CohortMetric, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCohortMetrics", ctx, arg)
+	ret0, _ := ret[0].([]db.CohortMetric)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCohortMetrics indicates an expected call of ListCohortMetrics.
+func (mr *MockQuerierMockRecorder) ListCohortMetrics(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCohortMetrics", reflect.TypeOf((*MockQuerier)(nil).ListCohortMetrics), ctx, arg)
+}
+
+// ListCustomerPortalInvoices mocks base method.
+func (m *MockQuerier) ListCustomerPortalInvoices(ctx context.Context, arg db.ListCustomerPortalInvoicesParams) ([]db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCustomerPortalInvoices", ctx, arg)
+	ret0, _ := ret[0].([]db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCustomerPortalInvoices indicates an expected call of ListCustomerPortalInvoices.
+func (mr *MockQuerierMockRecorder) ListCustomerPortalInvoices(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomerPortalInvoices", reflect.TypeOf((*MockQuerier)(nil).ListCustomerPortalInvoices), ctx, arg)
+}
+
+// ListCustomerPortalPayments mocks base method.
+func (m *MockQuerier) ListCustomerPortalPayments(ctx context.Context, arg db.ListCustomerPortalPaymentsParams) ([]db.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCustomerPortalPayments", ctx, arg)
+	ret0, _ := ret[0].([]db.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCustomerPortalPayments indicates an expected call of ListCustomerPortalPayments.
+func (mr *MockQuerierMockRecorder) ListCustomerPortalPayments(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomerPortalPayments", reflect.TypeOf((*MockQuerier)(nil).ListCustomerPortalPayments), ctx, arg)
+}
+
+// ListCustomerPortalSubscriptions mocks base method.
+func (m *MockQuerier) ListCustomerPortalSubscriptions(ctx context.Context, arg db.ListCustomerPortalSubscriptionsParams) ([]db.ListCustomerPortalSubscriptionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCustomerPortalSubscriptions", ctx, arg)
+	ret0, _ := ret[0].([]db.ListCustomerPortalSubscriptionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCustomerPortalSubscriptions indicates an expected call of ListCustomerPortalSubscriptions.
+func (mr *MockQuerierMockRecorder) ListCustomerPortalSubscriptions(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomerPortalSubscriptions", reflect.TypeOf((*MockQuerier)(nil).ListCustomerPortalSubscriptions), ctx, arg)
+}
+
 // ListCustomerWallets mocks base method.
 func (m *MockQuerier) ListCustomerWallets(ctx context.Context, customerID uuid.UUID) ([]db.CustomerWallet, error) {
 	m.ctrl.T.Helper()
@@ -6691,6 +8154,21 @@ func (mr *MockQuerierMockRecorder) ListCustomerWallets(ctx, customerID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomerWallets", reflect.TypeOf((*MockQuerier)(nil).ListCustomerWallets), ctx, customerID)
 }
 
+// ListCustomerWalletsDueNameRefresh mocks base method.
+func (m *MockQuerier) ListCustomerWalletsDueNameRefresh(ctx context.Context, arg db.ListCustomerWalletsDueNameRefreshParams) ([]db.CustomerWallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCustomerWalletsDueNameRefresh", ctx, arg)
+	ret0, _ := ret[0].([]db.CustomerWallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCustomerWalletsDueNameRefresh indicates an expected call of ListCustomerWalletsDueNameRefresh.
+func (mr *MockQuerierMockRecorder) ListCustomerWalletsDueNameRefresh(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomerWalletsDueNameRefresh", reflect.TypeOf((*MockQuerier)(nil).ListCustomerWalletsDueNameRefresh), ctx, arg)
+}
+
 // ListCustomerWorkspaces mocks base method.
 func (m *MockQuerier) ListCustomerWorkspaces(ctx context.Context, customerID uuid.UUID) ([]db.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -6721,6 +8199,21 @@ func (mr *MockQuerierMockRecorder) ListCustomers(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomers", reflect.TypeOf((*MockQuerier)(nil).ListCustomers), ctx)
 }
 
+// ListCustomersDueForTaxIDVerification mocks base method.
+func (m *MockQuerier) ListCustomersDueForTaxIDVerification(ctx context.Context, arg db.ListCustomersDueForTaxIDVerificationParams) ([]db.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCustomersDueForTaxIDVerification", ctx, arg)
+	ret0, _ := ret[0].([]db.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCustomersDueForTaxIDVerification indicates an expected call of ListCustomersDueForTaxIDVerification.
+func (mr *MockQuerierMockRecorder) ListCustomersDueForTaxIDVerification(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomersDueForTaxIDVerification", reflect.TypeOf((*MockQuerier)(nil).ListCustomersDueForTaxIDVerification), ctx, arg)
+}
+
 // ListCustomersWithPagination mocks base method.
 func (m *MockQuerier) ListCustomersWithPagination(ctx context.Context, arg db.ListCustomersWithPaginationParams) ([]db.Customer, error) {
 	m.ctrl.T.Helper()
@@ -6736,6 +8229,21 @@ func (mr *MockQuerierMockRecorder) ListCustomersWithPagination(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomersWithPagination", reflect.TypeOf((*MockQuerier)(nil).ListCustomersWithPagination), ctx, arg)
 }
 
+// ListDelegationsDueForCheck mocks base method.
+func (m *MockQuerier) ListDelegationsDueForCheck(ctx context.Context, arg db.ListDelegationsDueForCheckParams) ([]db.ListDelegationsDueForCheckRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDelegationsDueForCheck", ctx, arg)
+	ret0, _ := ret[0].([]db.ListDelegationsDueForCheckRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDelegationsDueForCheck indicates an expected call of ListDelegationsDueForCheck.
+func (mr *MockQuerierMockRecorder) ListDelegationsDueForCheck(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDelegationsDueForCheck", reflect.TypeOf((*MockQuerier)(nil).ListDelegationsDueForCheck), ctx, arg)
+}
+
 // ListDelegationsWithPagination mocks base method.
 func (m *MockQuerier) ListDelegationsWithPagination(ctx context.Context, arg db.ListDelegationsWithPaginationParams) ([]db.DelegationDatum, error) {
 	m.ctrl.T.Helper()
@@ -6841,6 +8349,36 @@ func (mr *MockQuerierMockRecorder) ListDunningEmailTemplates(ctx, workspaceID an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDunningEmailTemplates", reflect.TypeOf((*MockQuerier)(nil).ListDunningEmailTemplates), ctx, workspaceID)
 }
 
+// ListEffectiveTaxRates mocks base method.
+func (m *MockQuerier) ListEffectiveTaxRates(ctx context.Context, arg db.ListEffectiveTaxRatesParams) ([]db.TaxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEffectiveTaxRates", ctx, arg)
+	ret0, _ := ret[0].([]db.TaxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEffectiveTaxRates indicates an expected call of ListEffectiveTaxRates.
+func (mr *MockQuerierMockRecorder) ListEffectiveTaxRates(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEffectiveTaxRates", reflect.TypeOf((*MockQuerier)(nil).ListEffectiveTaxRates), ctx, arg)
+}
+
+// ListExpiredGasSponsorshipReservations mocks base method.
+func (m *MockQuerier) ListExpiredGasSponsorshipReservations(ctx context.Context, arg db.ListExpiredGasSponsorshipReservationsParams) ([]db.GasSponsorshipReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredGasSponsorshipReservations", ctx, arg)
+	ret0, _ := ret[0].([]db.GasSponsorshipReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredGasSponsorshipReservations indicates an expected call of ListExpiredGasSponsorshipReservations.
+func (mr *MockQuerierMockRecorder) ListExpiredGasSponsorshipReservations(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredGasSponsorshipReservations", reflect.TypeOf((*MockQuerier)(nil).ListExpiredGasSponsorshipReservations), ctx, arg)
+}
+
 // ListFailedSubscriptionAttempts mocks base method.
 func (m *MockQuerier) ListFailedSubscriptionAttempts(ctx context.Context) ([]db.FailedSubscriptionAttempt, error) {
 	m.ctrl.T.Helper()
@@ -6961,6 +8499,66 @@ func (mr *MockQuerierMockRecorder) ListFailedWebhookEvents(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailedWebhookEvents", reflect.TypeOf((*MockQuerier)(nil).ListFailedWebhookEvents), ctx, arg)
 }
 
+// ListGasFeesForExport mocks base method.
+func (m *MockQuerier) ListGasFeesForExport(ctx context.Context, arg db.ListGasFeesForExportParams) ([]db.ListGasFeesForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGasFeesForExport", ctx, arg)
+	ret0, _ := ret[0].([]db.ListGasFeesForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGasFeesForExport indicates an expected call of ListGasFeesForExport.
+func (mr *MockQuerierMockRecorder) ListGasFeesForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGasFeesForExport", reflect.TypeOf((*MockQuerier)(nil).ListGasFeesForExport), ctx, arg)
+}
+
+// ListGasSponsorshipBudgetAlerts mocks base method.
+func (m *MockQuerier) ListGasSponsorshipBudgetAlerts(ctx context.Context, arg db.ListGasSponsorshipBudgetAlertsParams) ([]db.GasSponsorshipBudgetAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGasSponsorshipBudgetAlerts", ctx, arg)
+	ret0, _ := ret[0].([]db.GasSponsorshipBudgetAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGasSponsorshipBudgetAlerts indicates an expected call of ListGasSponsorshipBudgetAlerts.
+func (mr *MockQuerierMockRecorder) ListGasSponsorshipBudgetAlerts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGasSponsorshipBudgetAlerts", reflect.TypeOf((*MockQuerier)(nil).ListGasSponsorshipBudgetAlerts), ctx, arg)
+}
+
+// ListGasSponsorshipLedgerEntries mocks base method.
+func (m *MockQuerier) ListGasSponsorshipLedgerEntries(ctx context.Context, arg db.ListGasSponsorshipLedgerEntriesParams) ([]db.GasSponsorshipLedger, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGasSponsorshipLedgerEntries", ctx, arg)
+	ret0, _ := ret[0].([]db.GasSponsorshipLedger)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGasSponsorshipLedgerEntries indicates an expected call of ListGasSponsorshipLedgerEntries.
+func (mr *MockQuerierMockRecorder) ListGasSponsorshipLedgerEntries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGasSponsorshipLedgerEntries", reflect.TypeOf((*MockQuerier)(nil).ListGasSponsorshipLedgerEntries), ctx, arg)
+}
+
+// ListGasSponsorshipRules mocks base method.
+func (m *MockQuerier) ListGasSponsorshipRules(ctx context.Context, workspaceID uuid.UUID) ([]db.GasSponsorshipRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGasSponsorshipRules", ctx, workspaceID)
+	ret0, _ := ret[0].([]db.GasSponsorshipRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGasSponsorshipRules indicates an expected call of ListGasSponsorshipRules.
+func (mr *MockQuerierMockRecorder) ListGasSponsorshipRules(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGasSponsorshipRules", reflect.TypeOf((*MockQuerier)(nil).ListGasSponsorshipRules), ctx, workspaceID)
+}
+
 // ListInvoicesByCustomer mocks base method.
 func (m *MockQuerier) ListInvoicesByCustomer(ctx context.Context, arg db.ListInvoicesByCustomerParams) ([]db.Invoice, error) {
 	m.ctrl.T.Helper()
@@ -7051,6 +8649,66 @@ func (mr *MockQuerierMockRecorder) ListInvoicesByWorkspace(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvoicesByWorkspace", reflect.TypeOf((*MockQuerier)(nil).ListInvoicesByWorkspace), ctx, arg)
 }
 
+// ListInvoicesForExport mocks base method.
+func (m *MockQuerier) ListInvoicesForExport(ctx context.Context, arg db.ListInvoicesForExportParams) ([]db.ListInvoicesForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvoicesForExport", ctx, arg)
+	ret0, _ := ret[0].([]db.ListInvoicesForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvoicesForExport indicates an expected call of ListInvoicesForExport.
+func (mr *MockQuerierMockRecorder) ListInvoicesForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvoicesForExport", reflect.TypeOf((*MockQuerier)(nil).ListInvoicesForExport), ctx, arg)
+}
+
+// ListLocalTaxJurisdictions mocks base method.
+func (m *MockQuerier) ListLocalTaxJurisdictions(ctx context.Context, arg db.ListLocalTaxJurisdictionsParams) ([]db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocalTaxJurisdictions", ctx, arg)
+	ret0, _ := ret[0].([]db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocalTaxJurisdictions indicates an expected call of ListLocalTaxJurisdictions.
+func (mr *MockQuerierMockRecorder) ListLocalTaxJurisdictions(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocalTaxJurisdictions", reflect.TypeOf((*MockQuerier)(nil).ListLocalTaxJurisdictions), ctx, arg)
+}
+
+// ListMRRMovements mocks base method.
+func (m *MockQuerier) ListMRRMovements(ctx context.Context, arg db.ListMRRMovementsParams) ([]db.ListMRRMovementsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMRRMovements", ctx, arg)
+	ret0, _ := ret[0].([]db.ListMRRMovementsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMRRMovements indicates an expected call of ListMRRMovements.
+func (mr *MockQuerierMockRecorder) ListMRRMovements(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMRRMovements", reflect.TypeOf((*MockQuerier)(nil).ListMRRMovements), ctx, arg)
+}
+
+// ListMRRMovementsForExport mocks base method.
+func (m *MockQuerier) ListMRRMovementsForExport(ctx context.Context, arg db.ListMRRMovementsForExportParams) ([]db.ListMRRMovementsForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMRRMovementsForExport", ctx, arg)
+	ret0, _ := ret[0].([]db.ListMRRMovementsForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMRRMovementsForExport indicates an expected call of ListMRRMovementsForExport.
+func (mr *MockQuerierMockRecorder) ListMRRMovementsForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMRRMovementsForExport", reflect.TypeOf((*MockQuerier)(nil).ListMRRMovementsForExport), ctx, arg)
+}
+
 // ListNetworks mocks base method.
 func (m *MockQuerier) ListNetworks(ctx context.Context, arg db.ListNetworksParams) ([]db.Network, error) {
 	m.ctrl.T.Helper()
@@ -7066,6 +8724,96 @@ func (mr *MockQuerierMockRecorder) ListNetworks(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNetworks", reflect.TypeOf((*MockQuerier)(nil).ListNetworks), ctx, arg)
 }
 
+// ListOpenTreasuryMovements mocks base method.
+func (m *MockQuerier) ListOpenTreasuryMovements(ctx context.Context, batchSize int32) ([]db.ListOpenTreasuryMovementsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenTreasuryMovements", ctx, batchSize)
+	ret0, _ := ret[0].([]db.ListOpenTreasuryMovementsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenTreasuryMovements indicates an expected call of ListOpenTreasuryMovements.
+func (mr *MockQuerierMockRecorder) ListOpenTreasuryMovements(ctx, batchSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenTreasuryMovements", reflect.TypeOf((*MockQuerier)(nil).ListOpenTreasuryMovements), ctx, batchSize)
+}
+
+// ListPaymentConfirmationsToCheck mocks base method.
+func (m *MockQuerier) ListPaymentConfirmationsToCheck(ctx context.Context, arg db.ListPaymentConfirmationsToCheckParams) ([]db.ListPaymentConfirmationsToCheckRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentConfirmationsToCheck", ctx, arg)
+	ret0, _ := ret[0].([]db.ListPaymentConfirmationsToCheckRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentConfirmationsToCheck indicates an expected call of ListPaymentConfirmationsToCheck.
+func (mr *MockQuerierMockRecorder) ListPaymentConfirmationsToCheck(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentConfirmationsToCheck", reflect.TypeOf((*MockQuerier)(nil).ListPaymentConfirmationsToCheck), ctx, arg)
+}
+
+// ListPaymentsForExport mocks base method.
+func (m *MockQuerier) ListPaymentsForExport(ctx context.Context, arg db.ListPaymentsForExportParams) ([]db.ListPaymentsForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentsForExport", ctx, arg)
+	ret0, _ := ret[0].([]db.ListPaymentsForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentsForExport indicates an expected call of ListPaymentsForExport.
+func (mr *MockQuerierMockRecorder) ListPaymentsForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentsForExport", reflect.TypeOf((*MockQuerier)(nil).ListPaymentsForExport), ctx, arg)
+}
+
+// ListPendingMRRMovements mocks base method.
+func (m *MockQuerier) ListPendingMRRMovements(ctx context.Context, limit int32) ([]db.MrrMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingMRRMovements", ctx, limit)
+	ret0, _ := ret[0].([]db.MrrMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingMRRMovements indicates an expected call of ListPendingMRRMovements.
+func (mr *MockQuerierMockRecorder) ListPendingMRRMovements(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingMRRMovements", reflect.TypeOf((*MockQuerier)(nil).ListPendingMRRMovements), ctx, limit)
+}
+
+// ListPendingSubscriptionReauthorizationsByCustomer mocks base method.
+func (m *MockQuerier) ListPendingSubscriptionReauthorizationsByCustomer(ctx context.Context, customerID uuid.UUID) ([]db.SubscriptionReauthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingSubscriptionReauthorizationsByCustomer", ctx, customerID)
+	ret0, _ := ret[0].([]db.SubscriptionReauthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingSubscriptionReauthorizationsByCustomer indicates an expected call of ListPendingSubscriptionReauthorizationsByCustomer.
+func (mr *MockQuerierMockRecorder) ListPendingSubscriptionReauthorizationsByCustomer(ctx, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingSubscriptionReauthorizationsByCustomer", reflect.TypeOf((*MockQuerier)(nil).ListPendingSubscriptionReauthorizationsByCustomer), ctx, customerID)
+}
+
+// ListPortfolioSnapshots mocks base method.
+func (m *MockQuerier) ListPortfolioSnapshots(ctx context.Context, arg db.ListPortfolioSnapshotsParams) ([]db.PortfolioSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPortfolioSnapshots", ctx, arg)
+	ret0, _ := ret[0].([]db.PortfolioSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPortfolioSnapshots indicates an expected call of ListPortfolioSnapshots.
+func (mr *MockQuerierMockRecorder) ListPortfolioSnapshots(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPortfolioSnapshots", reflect.TypeOf((*MockQuerier)(nil).ListPortfolioSnapshots), ctx, arg)
+}
+
 // ListPrimaryCustomerWallets mocks base method.
 func (m *MockQuerier) ListPrimaryCustomerWallets(ctx context.Context) ([]db.CustomerWallet, error) {
 	m.ctrl.T.Helper()
@@ -7246,6 +8994,21 @@ func (mr *MockQuerierMockRecorder) ListRecentSubscriptionEventsByType(ctx, arg a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecentSubscriptionEventsByType", reflect.TypeOf((*MockQuerier)(nil).ListRecentSubscriptionEventsByType), ctx, arg)
 }
 
+// ListRedemptionTasks mocks base method.
+func (m *MockQuerier) ListRedemptionTasks(ctx context.Context, arg db.ListRedemptionTasksParams) ([]db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRedemptionTasks", ctx, arg)
+	ret0, _ := ret[0].([]db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRedemptionTasks indicates an expected call of ListRedemptionTasks.
+func (mr *MockQuerierMockRecorder) ListRedemptionTasks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRedemptionTasks", reflect.TypeOf((*MockQuerier)(nil).ListRedemptionTasks), ctx, arg)
+}
+
 // ListRequiredProductAddons mocks base method.
 func (m *MockQuerier) ListRequiredProductAddons(ctx context.Context, baseProductID uuid.UUID) ([]db.ListRequiredProductAddonsRow, error) {
 	m.ctrl.T.Helper()
@@ -7381,6 +9144,51 @@ func (mr *MockQuerierMockRecorder) ListSubscriptionLineItems(ctx, subscriptionID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptionLineItems", reflect.TypeOf((*MockQuerier)(nil).ListSubscriptionLineItems), ctx, subscriptionID)
 }
 
+// ListSubscriptionReauthorizationsToNotify mocks base method.
+func (m *MockQuerier) ListSubscriptionReauthorizationsToNotify(ctx context.Context, arg db.ListSubscriptionReauthorizationsToNotifyParams) ([]db.ListSubscriptionReauthorizationsToNotifyRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptionReauthorizationsToNotify", ctx, arg)
+	ret0, _ := ret[0].([]db.ListSubscriptionReauthorizationsToNotifyRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptionReauthorizationsToNotify indicates an expected call of ListSubscriptionReauthorizationsToNotify.
+func (mr *MockQuerierMockRecorder) ListSubscriptionReauthorizationsToNotify(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptionReauthorizationsToNotify", reflect.TypeOf((*MockQuerier)(nil).ListSubscriptionReauthorizationsToNotify), ctx, arg)
+}
+
+// ListSubscriptionRenewals mocks base method.
+func (m *MockQuerier) ListSubscriptionRenewals(ctx context.Context, arg db.ListSubscriptionRenewalsParams) ([]db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptionRenewals", ctx, arg)
+	ret0, _ := ret[0].([]db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptionRenewals indicates an expected call of ListSubscriptionRenewals.
+func (mr *MockQuerierMockRecorder) ListSubscriptionRenewals(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptionRenewals", reflect.TypeOf((*MockQuerier)(nil).ListSubscriptionRenewals), ctx, arg)
+}
+
+// ListSubscriptionRenewalsBySubscription mocks base method.
+func (m *MockQuerier) ListSubscriptionRenewalsBySubscription(ctx context.Context, arg db.ListSubscriptionRenewalsBySubscriptionParams) ([]db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptionRenewalsBySubscription", ctx, arg)
+	ret0, _ := ret[0].([]db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptionRenewalsBySubscription indicates an expected call of ListSubscriptionRenewalsBySubscription.
+func (mr *MockQuerierMockRecorder) ListSubscriptionRenewalsBySubscription(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptionRenewalsBySubscription", reflect.TypeOf((*MockQuerier)(nil).ListSubscriptionRenewalsBySubscription), ctx, arg)
+}
+
 // ListSubscriptions mocks base method.
 func (m *MockQuerier) ListSubscriptions(ctx context.Context) ([]db.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -7441,6 +9249,21 @@ func (mr *MockQuerierMockRecorder) ListSubscriptionsDueForRedemption(ctx, nextRe
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptionsDueForRedemption", reflect.TypeOf((*MockQuerier)(nil).ListSubscriptionsDueForRedemption), ctx, nextRedemptionDate)
 }
 
+// ListSubscriptionsForExport mocks base method.
+func (m *MockQuerier) ListSubscriptionsForExport(ctx context.Context, arg db.ListSubscriptionsForExportParams) ([]db.ListSubscriptionsForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptionsForExport", ctx, arg)
+	ret0, _ := ret[0].([]db.ListSubscriptionsForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptionsForExport indicates an expected call of ListSubscriptionsForExport.
+func (mr *MockQuerierMockRecorder) ListSubscriptionsForExport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptionsForExport", reflect.TypeOf((*MockQuerier)(nil).ListSubscriptionsForExport), ctx, arg)
+}
+
 // ListSubscriptionsWithPagination mocks base method.
 func (m *MockQuerier) ListSubscriptionsWithPagination(ctx context.Context, arg db.ListSubscriptionsWithPaginationParams) ([]db.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -7561,6 +9384,81 @@ func (mr *MockQuerierMockRecorder) ListSyncSessionsByStatus(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSyncSessionsByStatus", reflect.TypeOf((*MockQuerier)(nil).ListSyncSessionsByStatus), ctx, arg)
 }
 
+// ListTaxIDVerificationsByCustomer mocks base method.
+func (m *MockQuerier) ListTaxIDVerificationsByCustomer(ctx context.Context, arg db.ListTaxIDVerificationsByCustomerParams) ([]db.TaxIDVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaxIDVerificationsByCustomer", ctx, arg)
+	ret0, _ := ret[0].([]db.TaxIDVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaxIDVerificationsByCustomer indicates an expected call of ListTaxIDVerificationsByCustomer.
+func (mr *MockQuerierMockRecorder) ListTaxIDVerificationsByCustomer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaxIDVerificationsByCustomer", reflect.TypeOf((*MockQuerier)(nil).ListTaxIDVerificationsByCustomer), ctx, arg)
+}
+
+// ListTaxJurisdictions mocks base method.
+func (m *MockQuerier) ListTaxJurisdictions(ctx context.Context, arg db.ListTaxJurisdictionsParams) ([]db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaxJurisdictions", ctx, arg)
+	ret0, _ := ret[0].([]db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaxJurisdictions indicates an expected call of ListTaxJurisdictions.
+func (mr *MockQuerierMockRecorder) ListTaxJurisdictions(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaxJurisdictions", reflect.TypeOf((*MockQuerier)(nil).ListTaxJurisdictions), ctx, arg)
+}
+
+// ListTaxRatesByJurisdiction mocks base method.
+func (m *MockQuerier) ListTaxRatesByJurisdiction(ctx context.Context, jurisdictionID uuid.UUID) ([]db.TaxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaxRatesByJurisdiction", ctx, jurisdictionID)
+	ret0, _ := ret[0].([]db.TaxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaxRatesByJurisdiction indicates an expected call of ListTaxRatesByJurisdiction.
+func (mr *MockQuerierMockRecorder) ListTaxRatesByJurisdiction(ctx, jurisdictionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaxRatesByJurisdiction", reflect.TypeOf((*MockQuerier)(nil).ListTaxRatesByJurisdiction), ctx, jurisdictionID)
+}
+
+// ListTaxReportInvoices mocks base method.
+func (m *MockQuerier) ListTaxReportInvoices(ctx context.Context, arg db.ListTaxReportInvoicesParams) ([]db.ListTaxReportInvoicesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaxReportInvoices", ctx, arg)
+	ret0, _ := ret[0].([]db.ListTaxReportInvoicesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaxReportInvoices indicates an expected call of ListTaxReportInvoices.
+func (mr *MockQuerierMockRecorder) ListTaxReportInvoices(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaxReportInvoices", reflect.TypeOf((*MockQuerier)(nil).ListTaxReportInvoices), ctx, arg)
+}
+
+// ListTaxReportRefunds mocks base method.
+func (m *MockQuerier) ListTaxReportRefunds(ctx context.Context, arg db.ListTaxReportRefundsParams) ([]db.ListTaxReportRefundsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaxReportRefunds", ctx, arg)
+	ret0, _ := ret[0].([]db.ListTaxReportRefundsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaxReportRefunds indicates an expected call of ListTaxReportRefunds.
+func (mr *MockQuerierMockRecorder) ListTaxReportRefunds(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaxReportRefunds", reflect.TypeOf((*MockQuerier)(nil).ListTaxReportRefunds), ctx, arg)
+}
+
 // ListTokens mocks base method.
 func (m *MockQuerier) ListTokens(ctx context.Context) ([]db.Token, error) {
 	m.ctrl.T.Helper()
@@ -7591,6 +9489,51 @@ func (mr *MockQuerierMockRecorder) ListTokensByNetwork(ctx, networkID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTokensByNetwork", reflect.TypeOf((*MockQuerier)(nil).ListTokensByNetwork), ctx, networkID)
 }
 
+// ListTreasuryMovements mocks base method.
+func (m *MockQuerier) ListTreasuryMovements(ctx context.Context, arg db.ListTreasuryMovementsParams) ([]db.TreasuryMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTreasuryMovements", ctx, arg)
+	ret0, _ := ret[0].([]db.TreasuryMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTreasuryMovements indicates an expected call of ListTreasuryMovements.
+func (mr *MockQuerierMockRecorder) ListTreasuryMovements(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTreasuryMovements", reflect.TypeOf((*MockQuerier)(nil).ListTreasuryMovements), ctx, arg)
+}
+
+// ListTreasurySweepRules mocks base method.
+func (m *MockQuerier) ListTreasurySweepRules(ctx context.Context, workspaceID uuid.UUID) ([]db.TreasurySweepRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTreasurySweepRules", ctx, workspaceID)
+	ret0, _ := ret[0].([]db.TreasurySweepRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTreasurySweepRules indicates an expected call of ListTreasurySweepRules.
+func (mr *MockQuerierMockRecorder) ListTreasurySweepRules(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTreasurySweepRules", reflect.TypeOf((*MockQuerier)(nil).ListTreasurySweepRules), ctx, workspaceID)
+}
+
+// ListUnusedAPIKeys mocks base method.
+func (m *MockQuerier) ListUnusedAPIKeys(ctx context.Context, cutoff pgtype.Timestamptz) ([]db.ListUnusedAPIKeysRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnusedAPIKeys", ctx, cutoff)
+	ret0, _ := ret[0].([]db.ListUnusedAPIKeysRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnusedAPIKeys indicates an expected call of ListUnusedAPIKeys.
+func (mr *MockQuerierMockRecorder) ListUnusedAPIKeys(ctx, cutoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnusedAPIKeys", reflect.TypeOf((*MockQuerier)(nil).ListUnusedAPIKeys), ctx, cutoff)
+}
+
 // ListUsers mocks base method.
 func (m *MockQuerier) ListUsers(ctx context.Context) ([]db.User, error) {
 	m.ctrl.T.Helper()
@@ -7621,6 +9564,21 @@ func (mr *MockQuerierMockRecorder) ListUsersByAccount(ctx, accountID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsersByAccount", reflect.TypeOf((*MockQuerier)(nil).ListUsersByAccount), ctx, accountID)
 }
 
+// ListWalletBalancesByWorkspace mocks base method.
+func (m *MockQuerier) ListWalletBalancesByWorkspace(ctx context.Context, arg db.ListWalletBalancesByWorkspaceParams) ([]db.ListWalletBalancesByWorkspaceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletBalancesByWorkspace", ctx, arg)
+	ret0, _ := ret[0].([]db.ListWalletBalancesByWorkspaceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletBalancesByWorkspace indicates an expected call of ListWalletBalancesByWorkspace.
+func (mr *MockQuerierMockRecorder) ListWalletBalancesByWorkspace(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletBalancesByWorkspace", reflect.TypeOf((*MockQuerier)(nil).ListWalletBalancesByWorkspace), ctx, arg)
+}
+
 // ListWalletsByAddress mocks base method.
 func (m *MockQuerier) ListWalletsByAddress(ctx context.Context, arg db.ListWalletsByAddressParams) ([]db.ListWalletsByAddressRow, error) {
 	m.ctrl.T.Helper()
@@ -7816,6 +9774,21 @@ func (mr *MockQuerierMockRecorder) ListWorkspacePaymentConfigurationsByProvider(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspacePaymentConfigurationsByProvider", reflect.TypeOf((*MockQuerier)(nil).ListWorkspacePaymentConfigurationsByProvider), ctx, providerName)
 }
 
+// ListWorkspacePaymentConfigurationsForReencryption mocks base method.
+func (m *MockQuerier) ListWorkspacePaymentConfigurationsForReencryption(ctx context.Context, arg db.ListWorkspacePaymentConfigurationsForReencryptionParams) ([]db.WorkspacePaymentConfiguration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspacePaymentConfigurationsForReencryption", ctx, arg)
+	ret0, _ := ret[0].([]db.WorkspacePaymentConfiguration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspacePaymentConfigurationsForReencryption indicates an expected call of ListWorkspacePaymentConfigurationsForReencryption.
+func (mr *MockQuerierMockRecorder) ListWorkspacePaymentConfigurationsForReencryption(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspacePaymentConfigurationsForReencryption", reflect.TypeOf((*MockQuerier)(nil).ListWorkspacePaymentConfigurationsForReencryption), ctx, arg)
+}
+
 // ListWorkspaceSupportedCurrencies mocks base method.
 func (m *MockQuerier) ListWorkspaceSupportedCurrencies(ctx context.Context, id uuid.UUID) ([]db.FiatCurrency, error) {
 	m.ctrl.T.Helper()
@@ -7831,6 +9804,21 @@ func (mr *MockQuerierMockRecorder) ListWorkspaceSupportedCurrencies(ctx, id any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaceSupportedCurrencies", reflect.TypeOf((*MockQuerier)(nil).ListWorkspaceSupportedCurrencies), ctx, id)
 }
 
+// ListWorkspaceWebhookSecretsForReencryption mocks base method.
+func (m *MockQuerier) ListWorkspaceWebhookSecretsForReencryption(ctx context.Context, arg db.ListWorkspaceWebhookSecretsForReencryptionParams) ([]db.WorkspaceWebhookSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaceWebhookSecretsForReencryption", ctx, arg)
+	ret0, _ := ret[0].([]db.WorkspaceWebhookSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaceWebhookSecretsForReencryption indicates an expected call of ListWorkspaceWebhookSecretsForReencryption.
+func (mr *MockQuerierMockRecorder) ListWorkspaceWebhookSecretsForReencryption(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaceWebhookSecretsForReencryption", reflect.TypeOf((*MockQuerier)(nil).ListWorkspaceWebhookSecretsForReencryption), ctx, arg)
+}
+
 // ListWorkspaces mocks base method.
 func (m *MockQuerier) ListWorkspaces(ctx context.Context) ([]db.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -7861,6 +9849,21 @@ func (mr *MockQuerierMockRecorder) ListWorkspacesByAccountID(ctx, accountID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspacesByAccountID", reflect.TypeOf((*MockQuerier)(nil).ListWorkspacesByAccountID), ctx, accountID)
 }
 
+// ListWorkspacesDuePortfolioSnapshot mocks base method.
+func (m *MockQuerier) ListWorkspacesDuePortfolioSnapshot(ctx context.Context, arg db.ListWorkspacesDuePortfolioSnapshotParams) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspacesDuePortfolioSnapshot", ctx, arg)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspacesDuePortfolioSnapshot indicates an expected call of ListWorkspacesDuePortfolioSnapshot.
+func (mr *MockQuerierMockRecorder) ListWorkspacesDuePortfolioSnapshot(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspacesDuePortfolioSnapshot", reflect.TypeOf((*MockQuerier)(nil).ListWorkspacesDuePortfolioSnapshot), ctx, arg)
+}
+
 // LockSubscriptionForProcessing mocks base method.
 func (m *MockQuerier) LockSubscriptionForProcessing(ctx context.Context, id uuid.UUID) (db.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -7876,6 +9879,21 @@ func (mr *MockQuerierMockRecorder) LockSubscriptionForProcessing(ctx, id any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockSubscriptionForProcessing", reflect.TypeOf((*MockQuerier)(nil).LockSubscriptionForProcessing), ctx, id)
 }
 
+// LockTaxJurisdiction mocks base method.
+func (m *MockQuerier) LockTaxJurisdiction(ctx context.Context, id uuid.UUID) (db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockTaxJurisdiction", ctx, id)
+	ret0, _ := ret[0].(db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockTaxJurisdiction indicates an expected call of LockTaxJurisdiction.
+func (mr *MockQuerierMockRecorder) LockTaxJurisdiction(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockTaxJurisdiction", reflect.TypeOf((*MockQuerier)(nil).LockTaxJurisdiction), ctx, id)
+}
+
 // LogDLQProcessingAttempt mocks base method.
 func (m *MockQuerier) LogDLQProcessingAttempt(ctx context.Context, arg db.LogDLQProcessingAttemptParams) (db.PaymentSyncEvent, error) {
 	m.ctrl.T.Helper()
@@ -7906,6 +9924,35 @@ func (mr *MockQuerierMockRecorder) LogWebhookReceived(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogWebhookReceived", reflect.TypeOf((*MockQuerier)(nil).LogWebhookReceived), ctx, arg)
 }
 
+// LogWebhookRejected mocks base method.
+func (m *MockQuerier) LogWebhookRejected(ctx context.Context, arg db.LogWebhookRejectedParams) (db.PaymentSyncEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogWebhookRejected", ctx, arg)
+	ret0, _ := ret[0].(db.PaymentSyncEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LogWebhookRejected indicates an expected call of LogWebhookRejected.
+func (mr *MockQuerierMockRecorder) LogWebhookRejected(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogWebhookRejected", reflect.TypeOf((*MockQuerier)(nil).LogWebhookRejected), ctx, arg)
+}
+
+// MarkAPIKeyUnusedNotified mocks base method.
+func (m *MockQuerier) MarkAPIKeyUnusedNotified(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAPIKeyUnusedNotified", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAPIKeyUnusedNotified indicates an expected call of MarkAPIKeyUnusedNotified.
+func (mr *MockQuerierMockRecorder) MarkAPIKeyUnusedNotified(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAPIKeyUnusedNotified", reflect.TypeOf((*MockQuerier)(nil).MarkAPIKeyUnusedNotified), ctx, id)
+}
+
 // MarkCustomerWalletAsPrimary mocks base method.
 func (m *MockQuerier) MarkCustomerWalletAsPrimary(ctx context.Context, id uuid.UUID) (db.CustomerWallet, error) {
 	m.ctrl.T.Helper()
@@ -7936,6 +9983,65 @@ func (mr *MockQuerierMockRecorder) MarkInvoicePaid(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInvoicePaid", reflect.TypeOf((*MockQuerier)(nil).MarkInvoicePaid), ctx, arg)
 }
 
+// MarkRedemptionTaskRedeeming mocks base method.
+func (m *MockQuerier) MarkRedemptionTaskRedeeming(ctx context.Context, arg db.MarkRedemptionTaskRedeemingParams) (db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRedemptionTaskRedeeming", ctx, arg)
+	ret0, _ := ret[0].(db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRedemptionTaskRedeeming indicates an expected call of MarkRedemptionTaskRedeeming.
+func (mr *MockQuerierMockRecorder) MarkRedemptionTaskRedeeming(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRedemptionTaskRedeeming", reflect.TypeOf((*MockQuerier)(nil).MarkRedemptionTaskRedeeming), ctx, arg)
+}
+
+// MarkSubscriptionReauthorizationNotified mocks base method.
+func (m *MockQuerier) MarkSubscriptionReauthorizationNotified(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSubscriptionReauthorizationNotified", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSubscriptionReauthorizationNotified indicates an expected call of MarkSubscriptionReauthorizationNotified.
+func (mr *MockQuerierMockRecorder) MarkSubscriptionReauthorizationNotified(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSubscriptionReauthorizationNotified", reflect.TypeOf((*MockQuerier)(nil).MarkSubscriptionReauthorizationNotified), ctx, id)
+}
+
+// MarkSubscriptionRenewalRedeemed mocks base method.
+func (m *MockQuerier) MarkSubscriptionRenewalRedeemed(ctx context.Context, arg db.MarkSubscriptionRenewalRedeemedParams) (db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSubscriptionRenewalRedeemed", ctx, arg)
+	ret0, _ := ret[0].(db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkSubscriptionRenewalRedeemed indicates an expected call of MarkSubscriptionRenewalRedeemed.
+func (mr *MockQuerierMockRecorder) MarkSubscriptionRenewalRedeemed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSubscriptionRenewalRedeemed", reflect.TypeOf((*MockQuerier)(nil).MarkSubscriptionRenewalRedeemed), ctx, arg)
+}
+
+// MarkSubscriptionRenewalRedeeming mocks base method.
+func (m *MockQuerier) MarkSubscriptionRenewalRedeeming(ctx context.Context, arg db.MarkSubscriptionRenewalRedeemingParams) (db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSubscriptionRenewalRedeeming", ctx, arg)
+	ret0, _ := ret[0].(db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkSubscriptionRenewalRedeeming indicates an expected call of MarkSubscriptionRenewalRedeeming.
+func (mr *MockQuerierMockRecorder) MarkSubscriptionRenewalRedeeming(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSubscriptionRenewalRedeeming", reflect.TypeOf((*MockQuerier)(nil).MarkSubscriptionRenewalRedeeming), ctx, arg)
+}
+
 // MarkWebhookForRetry mocks base method.
 func (m *MockQuerier) MarkWebhookForRetry(ctx context.Context, id uuid.UUID) (db.PaymentSyncEvent, error) {
 	m.ctrl.T.Helper()
@@ -7951,6 +10057,21 @@ func (mr *MockQuerierMockRecorder) MarkWebhookForRetry(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookForRetry", reflect.TypeOf((*MockQuerier)(nil).MarkWebhookForRetry), ctx, id)
 }
 
+// OpenSubscriptionReauthorization mocks base method.
+func (m *MockQuerier) OpenSubscriptionReauthorization(ctx context.Context, arg db.OpenSubscriptionReauthorizationParams) (db.SubscriptionReauthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenSubscriptionReauthorization", ctx, arg)
+	ret0, _ := ret[0].(db.SubscriptionReauthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSubscriptionReauthorization indicates an expected call of OpenSubscriptionReauthorization.
+func (mr *MockQuerierMockRecorder) OpenSubscriptionReauthorization(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSubscriptionReauthorization", reflect.TypeOf((*MockQuerier)(nil).OpenSubscriptionReauthorization), ctx, arg)
+}
+
 // PauseDunningCampaign mocks base method.
 func (m *MockQuerier) PauseDunningCampaign(ctx context.Context, id uuid.UUID) (db.DunningCampaign, error) {
 	m.ctrl.T.Helper()
@@ -7996,6 +10117,80 @@ func (mr *MockQuerierMockRecorder) ReactivateScheduledCancellation(ctx, id any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReactivateScheduledCancellation", reflect.TypeOf((*MockQuerier)(nil).ReactivateScheduledCancellation), ctx, id)
 }
 
+// ReconcileTreasuryMovement mocks base method.
+func (m *MockQuerier) ReconcileTreasuryMovement(ctx context.Context, arg db.ReconcileTreasuryMovementParams) (db.TreasuryMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileTreasuryMovement", ctx, arg)
+	ret0, _ := ret[0].(db.TreasuryMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileTreasuryMovement indicates an expected call of ReconcileTreasuryMovement.
+func (mr *MockQuerierMockRecorder) ReconcileTreasuryMovement(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileTreasuryMovement", reflect.TypeOf((*MockQuerier)(nil).ReconcileTreasuryMovement), ctx, arg)
+}
+
+// RecordAPIKeyUsage mocks base method.
+func (m *MockQuerier) RecordAPIKeyUsage(ctx context.Context, arg db.RecordAPIKeyUsageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAPIKeyUsage", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAPIKeyUsage indicates an expected call of RecordAPIKeyUsage.
+func (mr *MockQuerierMockRecorder) RecordAPIKeyUsage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAPIKeyUsage", reflect.TypeOf((*MockQuerier)(nil).RecordAPIKeyUsage), ctx, arg)
+}
+
+// RecordCircuitBreakerFailure mocks base method.
+func (m *MockQuerier) RecordCircuitBreakerFailure(ctx context.Context, arg db.RecordCircuitBreakerFailureParams) (db.CircuitBreaker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordCircuitBreakerFailure", ctx, arg)
+	ret0, _ := ret[0].(db.CircuitBreaker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordCircuitBreakerFailure indicates an expected call of RecordCircuitBreakerFailure.
+func (mr *MockQuerierMockRecorder) RecordCircuitBreakerFailure(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCircuitBreakerFailure", reflect.TypeOf((*MockQuerier)(nil).RecordCircuitBreakerFailure), ctx, arg)
+}
+
+// RecordCircuitBreakerSuccess mocks base method.
+func (m *MockQuerier) RecordCircuitBreakerSuccess(ctx context.Context, name string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordCircuitBreakerSuccess", ctx, name)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordCircuitBreakerSuccess indicates an expected call of RecordCircuitBreakerSuccess.
+func (mr *MockQuerierMockRecorder) RecordCircuitBreakerSuccess(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCircuitBreakerSuccess", reflect.TypeOf((*MockQuerier)(nil).RecordCircuitBreakerSuccess), ctx, name)
+}
+
+// RecordGasSponsorshipBudgetAlert mocks base method.
+func (m *MockQuerier) RecordGasSponsorshipBudgetAlert(ctx context.Context, arg db.RecordGasSponsorshipBudgetAlertParams) (db.GasSponsorshipBudgetAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordGasSponsorshipBudgetAlert", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipBudgetAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordGasSponsorshipBudgetAlert indicates an expected call of RecordGasSponsorshipBudgetAlert.
+func (mr *MockQuerierMockRecorder) RecordGasSponsorshipBudgetAlert(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordGasSponsorshipBudgetAlert", reflect.TypeOf((*MockQuerier)(nil).RecordGasSponsorshipBudgetAlert), ctx, arg)
+}
+
 // RecordInvoiceCreation mocks base method.
 func (m *MockQuerier) RecordInvoiceCreation(ctx context.Context, arg db.RecordInvoiceCreationParams) (db.InvoiceActivity, error) {
 	m.ctrl.T.Helper()
@@ -8056,6 +10251,34 @@ func (mr *MockQuerierMockRecorder) RecordStateChange(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordStateChange", reflect.TypeOf((*MockQuerier)(nil).RecordStateChange), ctx, arg)
 }
 
+// RecordTreasurySweepRuleRun mocks base method.
+func (m *MockQuerier) RecordTreasurySweepRuleRun(ctx context.Context, arg db.RecordTreasurySweepRuleRunParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTreasurySweepRuleRun", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordTreasurySweepRuleRun indicates an expected call of RecordTreasurySweepRuleRun.
+func (mr *MockQuerierMockRecorder) RecordTreasurySweepRuleRun(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTreasurySweepRuleRun", reflect.TypeOf((*MockQuerier)(nil).RecordTreasurySweepRuleRun), ctx, arg)
+}
+
+// RecordWalletBalanceError mocks base method.
+func (m *MockQuerier) RecordWalletBalanceError(ctx context.Context, arg db.RecordWalletBalanceErrorParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWalletBalanceError", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWalletBalanceError indicates an expected call of RecordWalletBalanceError.
+func (mr *MockQuerierMockRecorder) RecordWalletBalanceError(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWalletBalanceError", reflect.TypeOf((*MockQuerier)(nil).RecordWalletBalanceError), ctx, arg)
+}
+
 // RecoverDunningCampaign mocks base method.
 func (m *MockQuerier) RecoverDunningCampaign(ctx context.Context, arg db.RecoverDunningCampaignParams) (db.DunningCampaign, error) {
 	m.ctrl.T.Helper()
@@ -8071,6 +10294,36 @@ func (mr *MockQuerierMockRecorder) RecoverDunningCampaign(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverDunningCampaign", reflect.TypeOf((*MockQuerier)(nil).RecoverDunningCampaign), ctx, arg)
 }
 
+// ReencryptWorkspacePaymentConfiguration mocks base method.
+func (m *MockQuerier) ReencryptWorkspacePaymentConfiguration(ctx context.Context, arg db.ReencryptWorkspacePaymentConfigurationParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReencryptWorkspacePaymentConfiguration", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReencryptWorkspacePaymentConfiguration indicates an expected call of ReencryptWorkspacePaymentConfiguration.
+func (mr *MockQuerierMockRecorder) ReencryptWorkspacePaymentConfiguration(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReencryptWorkspacePaymentConfiguration", reflect.TypeOf((*MockQuerier)(nil).ReencryptWorkspacePaymentConfiguration), ctx, arg)
+}
+
+// ReencryptWorkspaceWebhookSecret mocks base method.
+func (m *MockQuerier) ReencryptWorkspaceWebhookSecret(ctx context.Context, arg db.ReencryptWorkspaceWebhookSecretParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReencryptWorkspaceWebhookSecret", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReencryptWorkspaceWebhookSecret indicates an expected call of ReencryptWorkspaceWebhookSecret.
+func (mr *MockQuerierMockRecorder) ReencryptWorkspaceWebhookSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReencryptWorkspaceWebhookSecret", reflect.TypeOf((*MockQuerier)(nil).ReencryptWorkspaceWebhookSecret), ctx, arg)
+}
+
 // RefundPayment mocks base method.
 func (m *MockQuerier) RefundPayment(ctx context.Context, arg db.RefundPaymentParams) (db.Payment, error) {
 	m.ctrl.T.Helper()
@@ -8083,50 +10336,169 @@ func (m *MockQuerier) RefundPayment(ctx context.Context, arg db.RefundPaymentPar
 // RefundPayment indicates an expected call of RefundPayment.
 func (mr *MockQuerierMockRecorder) RefundPayment(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundPayment", reflect.TypeOf((*MockQuerier)(nil).RefundPayment), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundPayment", reflect.TypeOf((*MockQuerier)(nil).RefundPayment), ctx, arg)
+}
+
+// ReleaseGasSponsorshipReservation mocks base method.
+func (m *MockQuerier) ReleaseGasSponsorshipReservation(ctx context.Context, arg db.ReleaseGasSponsorshipReservationParams) (db.GasSponsorshipReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseGasSponsorshipReservation", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseGasSponsorshipReservation indicates an expected call of ReleaseGasSponsorshipReservation.
+func (mr *MockQuerierMockRecorder) ReleaseGasSponsorshipReservation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseGasSponsorshipReservation", reflect.TypeOf((*MockQuerier)(nil).ReleaseGasSponsorshipReservation), ctx, arg)
+}
+
+// ReleaseSubscriptionRenewalLease mocks base method.
+func (m *MockQuerier) ReleaseSubscriptionRenewalLease(ctx context.Context, arg db.ReleaseSubscriptionRenewalLeaseParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseSubscriptionRenewalLease", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseSubscriptionRenewalLease indicates an expected call of ReleaseSubscriptionRenewalLease.
+func (mr *MockQuerierMockRecorder) ReleaseSubscriptionRenewalLease(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseSubscriptionRenewalLease", reflect.TypeOf((*MockQuerier)(nil).ReleaseSubscriptionRenewalLease), ctx, arg)
+}
+
+// RemoveCustomerFromWorkspace mocks base method.
+func (m *MockQuerier) RemoveCustomerFromWorkspace(ctx context.Context, arg db.RemoveCustomerFromWorkspaceParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCustomerFromWorkspace", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCustomerFromWorkspace indicates an expected call of RemoveCustomerFromWorkspace.
+func (mr *MockQuerierMockRecorder) RemoveCustomerFromWorkspace(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCustomerFromWorkspace", reflect.TypeOf((*MockQuerier)(nil).RemoveCustomerFromWorkspace), ctx, arg)
+}
+
+// RemoveWorkspaceSupportedCurrency mocks base method.
+func (m *MockQuerier) RemoveWorkspaceSupportedCurrency(ctx context.Context, arg db.RemoveWorkspaceSupportedCurrencyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWorkspaceSupportedCurrency", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWorkspaceSupportedCurrency indicates an expected call of RemoveWorkspaceSupportedCurrency.
+func (mr *MockQuerierMockRecorder) RemoveWorkspaceSupportedCurrency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWorkspaceSupportedCurrency", reflect.TypeOf((*MockQuerier)(nil).RemoveWorkspaceSupportedCurrency), ctx, arg)
+}
+
+// ReopenPaidInvoice mocks base method.
+func (m *MockQuerier) ReopenPaidInvoice(ctx context.Context, arg db.ReopenPaidInvoiceParams) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReopenPaidInvoice", ctx, arg)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReopenPaidInvoice indicates an expected call of ReopenPaidInvoice.
+func (mr *MockQuerierMockRecorder) ReopenPaidInvoice(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenPaidInvoice", reflect.TypeOf((*MockQuerier)(nil).ReopenPaidInvoice), ctx, arg)
+}
+
+// ReopenSubscriptionRenewalByTransaction mocks base method.
+func (m *MockQuerier) ReopenSubscriptionRenewalByTransaction(ctx context.Context, arg db.ReopenSubscriptionRenewalByTransactionParams) (db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReopenSubscriptionRenewalByTransaction", ctx, arg)
+	ret0, _ := ret[0].(db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReopenSubscriptionRenewalByTransaction indicates an expected call of ReopenSubscriptionRenewalByTransaction.
+func (mr *MockQuerierMockRecorder) ReopenSubscriptionRenewalByTransaction(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenSubscriptionRenewalByTransaction", reflect.TypeOf((*MockQuerier)(nil).ReopenSubscriptionRenewalByTransaction), ctx, arg)
+}
+
+// ReplayWebhookEvent mocks base method.
+func (m *MockQuerier) ReplayWebhookEvent(ctx context.Context, arg db.ReplayWebhookEventParams) (db.PaymentSyncEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayWebhookEvent", ctx, arg)
+	ret0, _ := ret[0].(db.PaymentSyncEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayWebhookEvent indicates an expected call of ReplayWebhookEvent.
+func (mr *MockQuerierMockRecorder) ReplayWebhookEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayWebhookEvent", reflect.TypeOf((*MockQuerier)(nil).ReplayWebhookEvent), ctx, arg)
+}
+
+// RequeueRedemptionTask mocks base method.
+func (m *MockQuerier) RequeueRedemptionTask(ctx context.Context, arg db.RequeueRedemptionTaskParams) (db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueRedemptionTask", ctx, arg)
+	ret0, _ := ret[0].(db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueRedemptionTask indicates an expected call of RequeueRedemptionTask.
+func (mr *MockQuerierMockRecorder) RequeueRedemptionTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueRedemptionTask", reflect.TypeOf((*MockQuerier)(nil).RequeueRedemptionTask), ctx, arg)
 }
 
-// RemoveCustomerFromWorkspace mocks base method.
-func (m *MockQuerier) RemoveCustomerFromWorkspace(ctx context.Context, arg db.RemoveCustomerFromWorkspaceParams) error {
+// RequeueSubscriptionRenewal mocks base method.
+func (m *MockQuerier) RequeueSubscriptionRenewal(ctx context.Context, arg db.RequeueSubscriptionRenewalParams) (db.SubscriptionRenewal, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveCustomerFromWorkspace", ctx, arg)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "RequeueSubscriptionRenewal", ctx, arg)
+	ret0, _ := ret[0].(db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// RemoveCustomerFromWorkspace indicates an expected call of RemoveCustomerFromWorkspace.
-func (mr *MockQuerierMockRecorder) RemoveCustomerFromWorkspace(ctx, arg any) *gomock.Call {
+// RequeueSubscriptionRenewal indicates an expected call of RequeueSubscriptionRenewal.
+func (mr *MockQuerierMockRecorder) RequeueSubscriptionRenewal(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCustomerFromWorkspace", reflect.TypeOf((*MockQuerier)(nil).RemoveCustomerFromWorkspace), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueSubscriptionRenewal", reflect.TypeOf((*MockQuerier)(nil).RequeueSubscriptionRenewal), ctx, arg)
 }
 
-// RemoveWorkspaceSupportedCurrency mocks base method.
-func (m *MockQuerier) RemoveWorkspaceSupportedCurrency(ctx context.Context, arg db.RemoveWorkspaceSupportedCurrencyParams) error {
+// ReserveGasSponsorshipBudget mocks base method.
+func (m *MockQuerier) ReserveGasSponsorshipBudget(ctx context.Context, arg db.ReserveGasSponsorshipBudgetParams) (db.GasSponsorshipReservation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveWorkspaceSupportedCurrency", ctx, arg)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ReserveGasSponsorshipBudget", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// RemoveWorkspaceSupportedCurrency indicates an expected call of RemoveWorkspaceSupportedCurrency.
-func (mr *MockQuerierMockRecorder) RemoveWorkspaceSupportedCurrency(ctx, arg any) *gomock.Call {
+// ReserveGasSponsorshipBudget indicates an expected call of ReserveGasSponsorshipBudget.
+func (mr *MockQuerierMockRecorder) ReserveGasSponsorshipBudget(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWorkspaceSupportedCurrency", reflect.TypeOf((*MockQuerier)(nil).RemoveWorkspaceSupportedCurrency), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveGasSponsorshipBudget", reflect.TypeOf((*MockQuerier)(nil).ReserveGasSponsorshipBudget), ctx, arg)
 }
 
-// ReplayWebhookEvent mocks base method.
-func (m *MockQuerier) ReplayWebhookEvent(ctx context.Context, arg db.ReplayWebhookEventParams) (db.PaymentSyncEvent, error) {
+// ReserveGasSponsorshipCustomerSpending mocks base method.
+func (m *MockQuerier) ReserveGasSponsorshipCustomerSpending(ctx context.Context, arg db.ReserveGasSponsorshipCustomerSpendingParams) (db.GasSponsorshipReservation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReplayWebhookEvent", ctx, arg)
-	ret0, _ := ret[0].(db.PaymentSyncEvent)
+	ret := m.ctrl.Call(m, "ReserveGasSponsorshipCustomerSpending", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipReservation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ReplayWebhookEvent indicates an expected call of ReplayWebhookEvent.
-func (mr *MockQuerierMockRecorder) ReplayWebhookEvent(ctx, arg any) *gomock.Call {
+// ReserveGasSponsorshipCustomerSpending indicates an expected call of ReserveGasSponsorshipCustomerSpending.
+func (mr *MockQuerierMockRecorder) ReserveGasSponsorshipCustomerSpending(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayWebhookEvent", reflect.TypeOf((*MockQuerier)(nil).ReplayWebhookEvent), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveGasSponsorshipCustomerSpending", reflect.TypeOf((*MockQuerier)(nil).ReserveGasSponsorshipCustomerSpending), ctx, arg)
 }
 
 // ResetGasSponsorshipMonthlySpending mocks base method.
@@ -8143,6 +10515,21 @@ func (mr *MockQuerierMockRecorder) ResetGasSponsorshipMonthlySpending(ctx, arg a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetGasSponsorshipMonthlySpending", reflect.TypeOf((*MockQuerier)(nil).ResetGasSponsorshipMonthlySpending), ctx, arg)
 }
 
+// ResolveSubscriptionRenewal mocks base method.
+func (m *MockQuerier) ResolveSubscriptionRenewal(ctx context.Context, arg db.ResolveSubscriptionRenewalParams) (db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveSubscriptionRenewal", ctx, arg)
+	ret0, _ := ret[0].(db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveSubscriptionRenewal indicates an expected call of ResolveSubscriptionRenewal.
+func (mr *MockQuerierMockRecorder) ResolveSubscriptionRenewal(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveSubscriptionRenewal", reflect.TypeOf((*MockQuerier)(nil).ResolveSubscriptionRenewal), ctx, arg)
+}
+
 // ResumeDunningCampaign mocks base method.
 func (m *MockQuerier) ResumeDunningCampaign(ctx context.Context, arg db.ResumeDunningCampaignParams) (db.DunningCampaign, error) {
 	m.ctrl.T.Helper()
@@ -8188,6 +10575,66 @@ func (mr *MockQuerierMockRecorder) ResumeSyncSession(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSyncSession", reflect.TypeOf((*MockQuerier)(nil).ResumeSyncSession), ctx, arg)
 }
 
+// RetryRedemptionTask mocks base method.
+func (m *MockQuerier) RetryRedemptionTask(ctx context.Context, arg db.RetryRedemptionTaskParams) (db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryRedemptionTask", ctx, arg)
+	ret0, _ := ret[0].(db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryRedemptionTask indicates an expected call of RetryRedemptionTask.
+func (mr *MockQuerierMockRecorder) RetryRedemptionTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryRedemptionTask", reflect.TypeOf((*MockQuerier)(nil).RetryRedemptionTask), ctx, arg)
+}
+
+// RevertSubscriptionRedemption mocks base method.
+func (m *MockQuerier) RevertSubscriptionRedemption(ctx context.Context, arg db.RevertSubscriptionRedemptionParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertSubscriptionRedemption", ctx, arg)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertSubscriptionRedemption indicates an expected call of RevertSubscriptionRedemption.
+func (mr *MockQuerierMockRecorder) RevertSubscriptionRedemption(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertSubscriptionRedemption", reflect.TypeOf((*MockQuerier)(nil).RevertSubscriptionRedemption), ctx, arg)
+}
+
+// RevokeCustomerPortalSession mocks base method.
+func (m *MockQuerier) RevokeCustomerPortalSession(ctx context.Context, arg db.RevokeCustomerPortalSessionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeCustomerPortalSession", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeCustomerPortalSession indicates an expected call of RevokeCustomerPortalSession.
+func (mr *MockQuerierMockRecorder) RevokeCustomerPortalSession(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeCustomerPortalSession", reflect.TypeOf((*MockQuerier)(nil).RevokeCustomerPortalSession), ctx, arg)
+}
+
+// RotateAPIKey mocks base method.
+func (m *MockQuerier) RotateAPIKey(ctx context.Context, arg db.RotateAPIKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateAPIKey", ctx, arg)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateAPIKey indicates an expected call of RotateAPIKey.
+func (mr *MockQuerierMockRecorder) RotateAPIKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateAPIKey", reflect.TypeOf((*MockQuerier)(nil).RotateAPIKey), ctx, arg)
+}
+
 // ScheduleSubscriptionCancellation mocks base method.
 func (m *MockQuerier) ScheduleSubscriptionCancellation(ctx context.Context, arg db.ScheduleSubscriptionCancellationParams) (db.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -8248,6 +10695,20 @@ func (mr *MockQuerierMockRecorder) SearchWalletsWithCircleData(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchWalletsWithCircleData", reflect.TypeOf((*MockQuerier)(nil).SearchWalletsWithCircleData), ctx, arg)
 }
 
+// SetCustomerTaxIDVerified mocks base method.
+func (m *MockQuerier) SetCustomerTaxIDVerified(ctx context.Context, arg db.SetCustomerTaxIDVerifiedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCustomerTaxIDVerified", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCustomerTaxIDVerified indicates an expected call of SetCustomerTaxIDVerified.
+func (mr *MockQuerierMockRecorder) SetCustomerTaxIDVerified(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCustomerTaxIDVerified", reflect.TypeOf((*MockQuerier)(nil).SetCustomerTaxIDVerified), ctx, arg)
+}
+
 // SetDefaultDunningConfiguration mocks base method.
 func (m *MockQuerier) SetDefaultDunningConfiguration(ctx context.Context, arg db.SetDefaultDunningConfigurationParams) error {
 	m.ctrl.T.Helper()
@@ -8262,6 +10723,34 @@ func (mr *MockQuerierMockRecorder) SetDefaultDunningConfiguration(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultDunningConfiguration", reflect.TypeOf((*MockQuerier)(nil).SetDefaultDunningConfiguration), ctx, arg)
 }
 
+// SetMRRMovementExchangeRate mocks base method.
+func (m *MockQuerier) SetMRRMovementExchangeRate(ctx context.Context, arg db.SetMRRMovementExchangeRateParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMRRMovementExchangeRate", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMRRMovementExchangeRate indicates an expected call of SetMRRMovementExchangeRate.
+func (mr *MockQuerierMockRecorder) SetMRRMovementExchangeRate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMRRMovementExchangeRate", reflect.TypeOf((*MockQuerier)(nil).SetMRRMovementExchangeRate), ctx, arg)
+}
+
+// SetTreasuryMovementChallenge mocks base method.
+func (m *MockQuerier) SetTreasuryMovementChallenge(ctx context.Context, arg db.SetTreasuryMovementChallengeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTreasuryMovementChallenge", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTreasuryMovementChallenge indicates an expected call of SetTreasuryMovementChallenge.
+func (mr *MockQuerierMockRecorder) SetTreasuryMovementChallenge(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTreasuryMovementChallenge", reflect.TypeOf((*MockQuerier)(nil).SetTreasuryMovementChallenge), ctx, arg)
+}
+
 // SetWalletAsPrimary mocks base method.
 func (m *MockQuerier) SetWalletAsPrimary(ctx context.Context, arg db.SetWalletAsPrimaryParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -8277,6 +10766,21 @@ func (mr *MockQuerierMockRecorder) SetWalletAsPrimary(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWalletAsPrimary", reflect.TypeOf((*MockQuerier)(nil).SetWalletAsPrimary), ctx, arg)
 }
 
+// SettleGasSponsorshipReservation mocks base method.
+func (m *MockQuerier) SettleGasSponsorshipReservation(ctx context.Context, arg db.SettleGasSponsorshipReservationParams) (db.GasSponsorshipReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleGasSponsorshipReservation", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SettleGasSponsorshipReservation indicates an expected call of SettleGasSponsorshipReservation.
+func (mr *MockQuerierMockRecorder) SettleGasSponsorshipReservation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleGasSponsorshipReservation", reflect.TypeOf((*MockQuerier)(nil).SettleGasSponsorshipReservation), ctx, arg)
+}
+
 // SoftDeleteWallet mocks base method.
 func (m *MockQuerier) SoftDeleteWallet(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -8291,6 +10795,50 @@ func (mr *MockQuerierMockRecorder) SoftDeleteWallet(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteWallet", reflect.TypeOf((*MockQuerier)(nil).SoftDeleteWallet), ctx, id)
 }
 
+// SupersedeOpenTaxRate mocks base method.
+func (m *MockQuerier) SupersedeOpenTaxRate(ctx context.Context, arg db.SupersedeOpenTaxRateParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SupersedeOpenTaxRate", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SupersedeOpenTaxRate indicates an expected call of SupersedeOpenTaxRate.
+func (mr *MockQuerierMockRecorder) SupersedeOpenTaxRate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupersedeOpenTaxRate", reflect.TypeOf((*MockQuerier)(nil).SupersedeOpenTaxRate), ctx, arg)
+}
+
+// TouchCustomerPortalSession mocks base method.
+func (m *MockQuerier) TouchCustomerPortalSession(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchCustomerPortalSession", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchCustomerPortalSession indicates an expected call of TouchCustomerPortalSession.
+func (mr *MockQuerierMockRecorder) TouchCustomerPortalSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchCustomerPortalSession", reflect.TypeOf((*MockQuerier)(nil).TouchCustomerPortalSession), ctx, id)
+}
+
+// TrackNewPaymentConfirmations mocks base method.
+func (m *MockQuerier) TrackNewPaymentConfirmations(ctx context.Context, createdAfter pgtype.Timestamptz) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrackNewPaymentConfirmations", ctx, createdAfter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrackNewPaymentConfirmations indicates an expected call of TrackNewPaymentConfirmations.
+func (mr *MockQuerierMockRecorder) TrackNewPaymentConfirmations(ctx, createdAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackNewPaymentConfirmations", reflect.TypeOf((*MockQuerier)(nil).TrackNewPaymentConfirmations), ctx, createdAfter)
+}
+
 // UnsetPrimaryForCustomerWallets mocks base method.
 func (m *MockQuerier) UnsetPrimaryForCustomerWallets(ctx context.Context, arg db.UnsetPrimaryForCustomerWalletsParams) error {
 	m.ctrl.T.Helper()
@@ -8349,6 +10897,21 @@ func (mr *MockQuerierMockRecorder) UpdateAccount(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockQuerier)(nil).UpdateAccount), ctx, arg)
 }
 
+// UpdateAnalyticsReportSchedule mocks base method.
+func (m *MockQuerier) UpdateAnalyticsReportSchedule(ctx context.Context, arg db.UpdateAnalyticsReportScheduleParams) (db.AnalyticsReportSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAnalyticsReportSchedule", ctx, arg)
+	ret0, _ := ret[0].(db.AnalyticsReportSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAnalyticsReportSchedule indicates an expected call of UpdateAnalyticsReportSchedule.
+func (mr *MockQuerierMockRecorder) UpdateAnalyticsReportSchedule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAnalyticsReportSchedule", reflect.TypeOf((*MockQuerier)(nil).UpdateAnalyticsReportSchedule), ctx, arg)
+}
+
 // UpdateCircleUser mocks base method.
 func (m *MockQuerier) UpdateCircleUser(ctx context.Context, arg db.UpdateCircleUserParams) (db.CircleUser, error) {
 	m.ctrl.T.Helper()
@@ -8424,6 +10987,21 @@ func (mr *MockQuerierMockRecorder) UpdateCustomerBillingAddress(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomerBillingAddress", reflect.TypeOf((*MockQuerier)(nil).UpdateCustomerBillingAddress), ctx, arg)
 }
 
+// UpdateCustomerBillingDetails mocks base method.
+func (m *MockQuerier) UpdateCustomerBillingDetails(ctx context.Context, arg db.UpdateCustomerBillingDetailsParams) (db.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCustomerBillingDetails", ctx, arg)
+	ret0, _ := ret[0].(db.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCustomerBillingDetails indicates an expected call of UpdateCustomerBillingDetails.
+func (mr *MockQuerierMockRecorder) UpdateCustomerBillingDetails(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomerBillingDetails", reflect.TypeOf((*MockQuerier)(nil).UpdateCustomerBillingDetails), ctx, arg)
+}
+
 // UpdateCustomerOnboardingStatus mocks base method.
 func (m *MockQuerier) UpdateCustomerOnboardingStatus(ctx context.Context, arg db.UpdateCustomerOnboardingStatusParams) (db.Customer, error) {
 	m.ctrl.T.Helper()
@@ -8499,6 +11077,21 @@ func (mr *MockQuerierMockRecorder) UpdateCustomerWallet(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomerWallet", reflect.TypeOf((*MockQuerier)(nil).UpdateCustomerWallet), ctx, arg)
 }
 
+// UpdateCustomerWalletEns mocks base method.
+func (m *MockQuerier) UpdateCustomerWalletEns(ctx context.Context, arg db.UpdateCustomerWalletEnsParams) (db.CustomerWallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCustomerWalletEns", ctx, arg)
+	ret0, _ := ret[0].(db.CustomerWallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCustomerWalletEns indicates an expected call of UpdateCustomerWalletEns.
+func (mr *MockQuerierMockRecorder) UpdateCustomerWalletEns(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomerWalletEns", reflect.TypeOf((*MockQuerier)(nil).UpdateCustomerWalletEns), ctx, arg)
+}
+
 // UpdateCustomerWalletUsageTime mocks base method.
 func (m *MockQuerier) UpdateCustomerWalletUsageTime(ctx context.Context, id uuid.UUID) (db.CustomerWallet, error) {
 	m.ctrl.T.Helper()
@@ -8544,6 +11137,21 @@ func (mr *MockQuerierMockRecorder) UpdateDelegationData(ctx, arg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelegationData", reflect.TypeOf((*MockQuerier)(nil).UpdateDelegationData), ctx, arg)
 }
 
+// UpdateDelegationStatus mocks base method.
+func (m *MockQuerier) UpdateDelegationStatus(ctx context.Context, arg db.UpdateDelegationStatusParams) (db.DelegationDatum, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelegationStatus", ctx, arg)
+	ret0, _ := ret[0].(db.DelegationDatum)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDelegationStatus indicates an expected call of UpdateDelegationStatus.
+func (mr *MockQuerierMockRecorder) UpdateDelegationStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelegationStatus", reflect.TypeOf((*MockQuerier)(nil).UpdateDelegationStatus), ctx, arg)
+}
+
 // UpdateDunningAttempt mocks base method.
 func (m *MockQuerier) UpdateDunningAttempt(ctx context.Context, arg db.UpdateDunningAttemptParams) (db.DunningAttempt, error) {
 	m.ctrl.T.Helper()
@@ -8634,6 +11242,21 @@ func (mr *MockQuerierMockRecorder) UpdateGasSponsorshipConfig(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGasSponsorshipConfig", reflect.TypeOf((*MockQuerier)(nil).UpdateGasSponsorshipConfig), ctx, arg)
 }
 
+// UpdateGasSponsorshipRule mocks base method.
+func (m *MockQuerier) UpdateGasSponsorshipRule(ctx context.Context, arg db.UpdateGasSponsorshipRuleParams) (db.GasSponsorshipRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGasSponsorshipRule", ctx, arg)
+	ret0, _ := ret[0].(db.GasSponsorshipRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGasSponsorshipRule indicates an expected call of UpdateGasSponsorshipRule.
+func (mr *MockQuerierMockRecorder) UpdateGasSponsorshipRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGasSponsorshipRule", reflect.TypeOf((*MockQuerier)(nil).UpdateGasSponsorshipRule), ctx, arg)
+}
+
 // UpdateGasSponsorshipSpending mocks base method.
 func (m *MockQuerier) UpdateGasSponsorshipSpending(ctx context.Context, arg db.UpdateGasSponsorshipSpendingParams) error {
 	m.ctrl.T.Helper()
@@ -8783,6 +11406,36 @@ func (mr *MockQuerierMockRecorder) UpdateInvoiceSyncStatus(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInvoiceSyncStatus", reflect.TypeOf((*MockQuerier)(nil).UpdateInvoiceSyncStatus), ctx, arg)
 }
 
+// UpdateInvoiceTaxIDVerification mocks base method.
+func (m *MockQuerier) UpdateInvoiceTaxIDVerification(ctx context.Context, arg db.UpdateInvoiceTaxIDVerificationParams) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInvoiceTaxIDVerification", ctx, arg)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInvoiceTaxIDVerification indicates an expected call of UpdateInvoiceTaxIDVerification.
+func (mr *MockQuerierMockRecorder) UpdateInvoiceTaxIDVerification(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInvoiceTaxIDVerification", reflect.TypeOf((*MockQuerier)(nil).UpdateInvoiceTaxIDVerification), ctx, arg)
+}
+
+// UpdateInvoiceTaxProvider mocks base method.
+func (m *MockQuerier) UpdateInvoiceTaxProvider(ctx context.Context, arg db.UpdateInvoiceTaxProviderParams) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInvoiceTaxProvider", ctx, arg)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInvoiceTaxProvider indicates an expected call of UpdateInvoiceTaxProvider.
+func (mr *MockQuerierMockRecorder) UpdateInvoiceTaxProvider(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInvoiceTaxProvider", reflect.TypeOf((*MockQuerier)(nil).UpdateInvoiceTaxProvider), ctx, arg)
+}
+
 // UpdateLastWebhookTime mocks base method.
 func (m *MockQuerier) UpdateLastWebhookTime(ctx context.Context, arg db.UpdateLastWebhookTimeParams) error {
 	m.ctrl.T.Helper()
@@ -8842,6 +11495,21 @@ func (mr *MockQuerierMockRecorder) UpdateNetwork(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNetwork", reflect.TypeOf((*MockQuerier)(nil).UpdateNetwork), ctx, arg)
 }
 
+// UpdatePaymentConfirmation mocks base method.
+func (m *MockQuerier) UpdatePaymentConfirmation(ctx context.Context, arg db.UpdatePaymentConfirmationParams) (db.PaymentConfirmation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentConfirmation", ctx, arg)
+	ret0, _ := ret[0].(db.PaymentConfirmation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentConfirmation indicates an expected call of UpdatePaymentConfirmation.
+func (mr *MockQuerierMockRecorder) UpdatePaymentConfirmation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentConfirmation", reflect.TypeOf((*MockQuerier)(nil).UpdatePaymentConfirmation), ctx, arg)
+}
+
 // UpdatePaymentGasDetails mocks base method.
 func (m *MockQuerier) UpdatePaymentGasDetails(ctx context.Context, arg db.UpdatePaymentGasDetailsParams) (db.Payment, error) {
 	m.ctrl.T.Helper()
@@ -9127,6 +11795,21 @@ func (mr *MockQuerierMockRecorder) UpdateSubscriptionPaymentSyncStatus(ctx, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscriptionPaymentSyncStatus", reflect.TypeOf((*MockQuerier)(nil).UpdateSubscriptionPaymentSyncStatus), ctx, arg)
 }
 
+// UpdateSubscriptionPaymentWallet mocks base method.
+func (m *MockQuerier) UpdateSubscriptionPaymentWallet(ctx context.Context, arg db.UpdateSubscriptionPaymentWalletParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubscriptionPaymentWallet", ctx, arg)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSubscriptionPaymentWallet indicates an expected call of UpdateSubscriptionPaymentWallet.
+func (mr *MockQuerierMockRecorder) UpdateSubscriptionPaymentWallet(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscriptionPaymentWallet", reflect.TypeOf((*MockQuerier)(nil).UpdateSubscriptionPaymentWallet), ctx, arg)
+}
+
 // UpdateSubscriptionStatus mocks base method.
 func (m *MockQuerier) UpdateSubscriptionStatus(ctx context.Context, arg db.UpdateSubscriptionStatusParams) (db.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -9217,6 +11900,21 @@ func (mr *MockQuerierMockRecorder) UpdateSyncSessionStatus(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSyncSessionStatus", reflect.TypeOf((*MockQuerier)(nil).UpdateSyncSessionStatus), ctx, arg)
 }
 
+// UpdateTaxJurisdiction mocks base method.
+func (m *MockQuerier) UpdateTaxJurisdiction(ctx context.Context, arg db.UpdateTaxJurisdictionParams) (db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaxJurisdiction", ctx, arg)
+	ret0, _ := ret[0].(db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaxJurisdiction indicates an expected call of UpdateTaxJurisdiction.
+func (mr *MockQuerierMockRecorder) UpdateTaxJurisdiction(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaxJurisdiction", reflect.TypeOf((*MockQuerier)(nil).UpdateTaxJurisdiction), ctx, arg)
+}
+
 // UpdateToken mocks base method.
 func (m *MockQuerier) UpdateToken(ctx context.Context, arg db.UpdateTokenParams) (db.Token, error) {
 	m.ctrl.T.Helper()
@@ -9232,6 +11930,21 @@ func (mr *MockQuerierMockRecorder) UpdateToken(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateToken", reflect.TypeOf((*MockQuerier)(nil).UpdateToken), ctx, arg)
 }
 
+// UpdateTreasurySweepRule mocks base method.
+func (m *MockQuerier) UpdateTreasurySweepRule(ctx context.Context, arg db.UpdateTreasurySweepRuleParams) (db.TreasurySweepRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTreasurySweepRule", ctx, arg)
+	ret0, _ := ret[0].(db.TreasurySweepRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTreasurySweepRule indicates an expected call of UpdateTreasurySweepRule.
+func (mr *MockQuerierMockRecorder) UpdateTreasurySweepRule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTreasurySweepRule", reflect.TypeOf((*MockQuerier)(nil).UpdateTreasurySweepRule), ctx, arg)
+}
+
 // UpdateUser mocks base method.
 func (m *MockQuerier) UpdateUser(ctx context.Context, arg db.UpdateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -9469,6 +12182,35 @@ func (mr *MockQuerierMockRecorder) UpdateWorkspaceSupportedCurrencies(ctx, arg a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceSupportedCurrencies", reflect.TypeOf((*MockQuerier)(nil).UpdateWorkspaceSupportedCurrencies), ctx, arg)
 }
 
+// UpsertCohortMetrics mocks base method.
+func (m *MockQuerier) UpsertCohortMetrics(ctx context.Context, arg db.UpsertCohortMetricsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCohortMetrics", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertCohortMetrics indicates an expected call of UpsertCohortMetrics.
+func (mr *MockQuerierMockRecorder) UpsertCohortMetrics(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCohortMetrics", reflect.TypeOf((*MockQuerier)(nil).UpsertCohortMetrics), ctx, arg)
+}
+
+// UpsertCustomerPortalSettings mocks base method.
+func (m *MockQuerier) UpsertCustomerPortalSettings(ctx context.Context, arg db.UpsertCustomerPortalSettingsParams) (db.CustomerPortalSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCustomerPortalSettings", ctx, arg)
+	ret0, _ := ret[0].(db.CustomerPortalSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCustomerPortalSettings indicates an expected call of UpsertCustomerPortalSettings.
+func (mr *MockQuerierMockRecorder) UpsertCustomerPortalSettings(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCustomerPortalSettings", reflect.TypeOf((*MockQuerier)(nil).UpsertCustomerPortalSettings), ctx, arg)
+}
+
 // UpsertInvoice mocks base method.
 func (m *MockQuerier) UpsertInvoice(ctx context.Context, arg db.UpsertInvoiceParams) (db.Invoice, error) {
 	m.ctrl.T.Helper()
@@ -9484,6 +12226,36 @@ func (mr *MockQuerierMockRecorder) UpsertInvoice(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertInvoice", reflect.TypeOf((*MockQuerier)(nil).UpsertInvoice), ctx, arg)
 }
 
+// UpsertPortfolioSnapshot mocks base method.
+func (m *MockQuerier) UpsertPortfolioSnapshot(ctx context.Context, arg db.UpsertPortfolioSnapshotParams) (db.PortfolioSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPortfolioSnapshot", ctx, arg)
+	ret0, _ := ret[0].(db.PortfolioSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertPortfolioSnapshot indicates an expected call of UpsertPortfolioSnapshot.
+func (mr *MockQuerierMockRecorder) UpsertPortfolioSnapshot(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPortfolioSnapshot", reflect.TypeOf((*MockQuerier)(nil).UpsertPortfolioSnapshot), ctx, arg)
+}
+
+// UpsertWalletBalance mocks base method.
+func (m *MockQuerier) UpsertWalletBalance(ctx context.Context, arg db.UpsertWalletBalanceParams) (db.WalletBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWalletBalance", ctx, arg)
+	ret0, _ := ret[0].(db.WalletBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWalletBalance indicates an expected call of UpsertWalletBalance.
+func (mr *MockQuerierMockRecorder) UpsertWalletBalance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWalletBalance", reflect.TypeOf((*MockQuerier)(nil).UpsertWalletBalance), ctx, arg)
+}
+
 // ValidateAddonForProduct mocks base method.
 func (m *MockQuerier) ValidateAddonForProduct(ctx context.Context, arg db.ValidateAddonForProductParams) (bool, error) {
 	m.ctrl.T.Helper()
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: libs/go/interfaces/services.go
+// Source: interfaces/services.go
 //
 // Generated by this command:
 //
-//	mockgen -source=libs/go/interfaces/services.go -destination=libs/go/mocks/mock_services.go -package=mocks
+//	mockgen -source=interfaces/services.go -destination=mocks/mock_services.go -package=mocks
 //
 
 // Package mocks is a generated GoMock package.
@@ -11,6 +11,8 @@ package mocks
 
 import (
 	context "context"
+	io "io"
+	big "math/big"
 	reflect "reflect"
 	time "time"
 
@@ -113,18 +115,18 @@ func (mr *MockPaymentServiceMockRecorder) GetPayment(ctx, arg1 any) *gomock.Call
 }
 
 // GetPaymentByTransactionHash mocks base method.
-func (m *MockPaymentService) GetPaymentByTransactionHash(ctx context.Context, txHash string) (*db.Payment, error) {
+func (m *MockPaymentService) GetPaymentByTransactionHash(ctx context.Context, txHash string, subscriptionEventID *uuid.UUID) (*db.Payment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPaymentByTransactionHash", ctx, txHash)
+	ret := m.ctrl.Call(m, "GetPaymentByTransactionHash", ctx, txHash, subscriptionEventID)
 	ret0, _ := ret[0].(*db.Payment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPaymentByTransactionHash indicates an expected call of GetPaymentByTransactionHash.
-func (mr *MockPaymentServiceMockRecorder) GetPaymentByTransactionHash(ctx, txHash any) *gomock.Call {
+func (mr *MockPaymentServiceMockRecorder) GetPaymentByTransactionHash(ctx, txHash, subscriptionEventID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentByTransactionHash", reflect.TypeOf((*MockPaymentService)(nil).GetPaymentByTransactionHash), ctx, txHash)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentByTransactionHash", reflect.TypeOf((*MockPaymentService)(nil).GetPaymentByTransactionHash), ctx, txHash, subscriptionEventID)
 }
 
 // GetPaymentMetrics mocks base method.
@@ -968,6 +970,20 @@ func (m *MockGasSponsorshipService) EXPECT() *MockGasSponsorshipServiceMockRecor
 	return m.recorder
 }
 
+// AttachSponsorshipPayment mocks base method.
+func (m *MockGasSponsorshipService) AttachSponsorshipPayment(ctx context.Context, workspaceID, reservationID, paymentID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachSponsorshipPayment", ctx, workspaceID, reservationID, paymentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachSponsorshipPayment indicates an expected call of AttachSponsorshipPayment.
+func (mr *MockGasSponsorshipServiceMockRecorder) AttachSponsorshipPayment(ctx, workspaceID, reservationID, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachSponsorshipPayment", reflect.TypeOf((*MockGasSponsorshipService)(nil).AttachSponsorshipPayment), ctx, workspaceID, reservationID, paymentID)
+}
+
 // CreateDefaultSponsorshipConfig mocks base method.
 func (m *MockGasSponsorshipService) CreateDefaultSponsorshipConfig(ctx context.Context, workspaceID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -982,6 +998,35 @@ func (mr *MockGasSponsorshipServiceMockRecorder) CreateDefaultSponsorshipConfig(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDefaultSponsorshipConfig", reflect.TypeOf((*MockGasSponsorshipService)(nil).CreateDefaultSponsorshipConfig), ctx, workspaceID)
 }
 
+// CreateSponsorshipRule mocks base method.
+func (m *MockGasSponsorshipService) CreateSponsorshipRule(ctx context.Context, arg1 params.SponsorshipRuleParams) (*business.SponsorshipRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSponsorshipRule", ctx, arg1)
+	ret0, _ := ret[0].(*business.SponsorshipRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSponsorshipRule indicates an expected call of CreateSponsorshipRule.
+func (mr *MockGasSponsorshipServiceMockRecorder) CreateSponsorshipRule(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSponsorshipRule", reflect.TypeOf((*MockGasSponsorshipService)(nil).CreateSponsorshipRule), ctx, arg1)
+}
+
+// DeleteSponsorshipRule mocks base method.
+func (m *MockGasSponsorshipService) DeleteSponsorshipRule(ctx context.Context, workspaceID, ruleID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSponsorshipRule", ctx, workspaceID, ruleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSponsorshipRule indicates an expected call of DeleteSponsorshipRule.
+func (mr *MockGasSponsorshipServiceMockRecorder) DeleteSponsorshipRule(ctx, workspaceID, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSponsorshipRule", reflect.TypeOf((*MockGasSponsorshipService)(nil).DeleteSponsorshipRule), ctx, workspaceID, ruleID)
+}
+
 // GetSponsorshipAnalytics mocks base method.
 func (m *MockGasSponsorshipService) GetSponsorshipAnalytics(ctx context.Context, workspaceID uuid.UUID, days int) (*business.SponsorshipAnalytics, error) {
 	m.ctrl.T.Helper()
@@ -1012,6 +1057,37 @@ func (mr *MockGasSponsorshipServiceMockRecorder) GetSponsorshipBudgetStatus(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSponsorshipBudgetStatus", reflect.TypeOf((*MockGasSponsorshipService)(nil).GetSponsorshipBudgetStatus), ctx, workspaceID)
 }
 
+// ListSponsorshipLedger mocks base method.
+func (m *MockGasSponsorshipService) ListSponsorshipLedger(ctx context.Context, workspaceID uuid.UUID, limit, offset int32) ([]business.SponsorshipLedgerEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSponsorshipLedger", ctx, workspaceID, limit, offset)
+	ret0, _ := ret[0].([]business.SponsorshipLedgerEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListSponsorshipLedger indicates an expected call of ListSponsorshipLedger.
+func (mr *MockGasSponsorshipServiceMockRecorder) ListSponsorshipLedger(ctx, workspaceID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSponsorshipLedger", reflect.TypeOf((*MockGasSponsorshipService)(nil).ListSponsorshipLedger), ctx, workspaceID, limit, offset)
+}
+
+// ListSponsorshipRules mocks base method.
+func (m *MockGasSponsorshipService) ListSponsorshipRules(ctx context.Context, workspaceID uuid.UUID) ([]business.SponsorshipRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSponsorshipRules", ctx, workspaceID)
+	ret0, _ := ret[0].([]business.SponsorshipRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSponsorshipRules indicates an expected call of ListSponsorshipRules.
+func (mr *MockGasSponsorshipServiceMockRecorder) ListSponsorshipRules(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSponsorshipRules", reflect.TypeOf((*MockGasSponsorshipService)(nil).ListSponsorshipRules), ctx, workspaceID)
+}
+
 // RecordSponsoredTransaction mocks base method.
 func (m *MockGasSponsorshipService) RecordSponsoredTransaction(ctx context.Context, record business.SponsorshipRecord) error {
 	m.ctrl.T.Helper()
@@ -1026,6 +1102,51 @@ func (mr *MockGasSponsorshipServiceMockRecorder) RecordSponsoredTransaction(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSponsoredTransaction", reflect.TypeOf((*MockGasSponsorshipService)(nil).RecordSponsoredTransaction), ctx, record)
 }
 
+// ReleaseExpiredSponsorships mocks base method.
+func (m *MockGasSponsorshipService) ReleaseExpiredSponsorships(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredSponsorships", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredSponsorships indicates an expected call of ReleaseExpiredSponsorships.
+func (mr *MockGasSponsorshipServiceMockRecorder) ReleaseExpiredSponsorships(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredSponsorships", reflect.TypeOf((*MockGasSponsorshipService)(nil).ReleaseExpiredSponsorships), ctx)
+}
+
+// ReleaseSponsorship mocks base method.
+func (m *MockGasSponsorshipService) ReleaseSponsorship(ctx context.Context, release params.SponsorshipReleaseParams) (*business.SponsorshipReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseSponsorship", ctx, release)
+	ret0, _ := ret[0].(*business.SponsorshipReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseSponsorship indicates an expected call of ReleaseSponsorship.
+func (mr *MockGasSponsorshipServiceMockRecorder) ReleaseSponsorship(ctx, release any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseSponsorship", reflect.TypeOf((*MockGasSponsorshipService)(nil).ReleaseSponsorship), ctx, release)
+}
+
+// ReserveSponsorship mocks base method.
+func (m *MockGasSponsorshipService) ReserveSponsorship(ctx context.Context, arg1 params.SponsorshipCheckParams) (*business.SponsorshipDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveSponsorship", ctx, arg1)
+	ret0, _ := ret[0].(*business.SponsorshipDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveSponsorship indicates an expected call of ReserveSponsorship.
+func (mr *MockGasSponsorshipServiceMockRecorder) ReserveSponsorship(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSponsorship", reflect.TypeOf((*MockGasSponsorshipService)(nil).ReserveSponsorship), ctx, arg1)
+}
+
 // ResetMonthlySponsorshipBudgets mocks base method.
 func (m *MockGasSponsorshipService) ResetMonthlySponsorshipBudgets(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -1040,6 +1161,21 @@ func (mr *MockGasSponsorshipServiceMockRecorder) ResetMonthlySponsorshipBudgets(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMonthlySponsorshipBudgets", reflect.TypeOf((*MockGasSponsorshipService)(nil).ResetMonthlySponsorshipBudgets), ctx)
 }
 
+// SettleSponsorship mocks base method.
+func (m *MockGasSponsorshipService) SettleSponsorship(ctx context.Context, settlement params.SponsorshipSettlementParams) (*business.SponsorshipReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleSponsorship", ctx, settlement)
+	ret0, _ := ret[0].(*business.SponsorshipReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SettleSponsorship indicates an expected call of SettleSponsorship.
+func (mr *MockGasSponsorshipServiceMockRecorder) SettleSponsorship(ctx, settlement any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleSponsorship", reflect.TypeOf((*MockGasSponsorshipService)(nil).SettleSponsorship), ctx, settlement)
+}
+
 // ShouldSponsorGas mocks base method.
 func (m *MockGasSponsorshipService) ShouldSponsorGas(ctx context.Context, arg1 params.SponsorshipCheckParams) (*business.SponsorshipDecision, error) {
 	m.ctrl.T.Helper()
@@ -1055,6 +1191,21 @@ func (mr *MockGasSponsorshipServiceMockRecorder) ShouldSponsorGas(ctx, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldSponsorGas", reflect.TypeOf((*MockGasSponsorshipService)(nil).ShouldSponsorGas), ctx, arg1)
 }
 
+// SimulateSponsorship mocks base method.
+func (m *MockGasSponsorshipService) SimulateSponsorship(ctx context.Context, arg1 params.SponsorshipCheckParams) (*business.SponsorshipSimulation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateSponsorship", ctx, arg1)
+	ret0, _ := ret[0].(*business.SponsorshipSimulation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulateSponsorship indicates an expected call of SimulateSponsorship.
+func (mr *MockGasSponsorshipServiceMockRecorder) SimulateSponsorship(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateSponsorship", reflect.TypeOf((*MockGasSponsorshipService)(nil).SimulateSponsorship), ctx, arg1)
+}
+
 // UpdateSponsorshipConfig mocks base method.
 func (m *MockGasSponsorshipService) UpdateSponsorshipConfig(ctx context.Context, workspaceID uuid.UUID, updates business.SponsorshipConfigUpdates) error {
 	m.ctrl.T.Helper()
@@ -1069,6 +1220,273 @@ func (mr *MockGasSponsorshipServiceMockRecorder) UpdateSponsorshipConfig(ctx, wo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSponsorshipConfig", reflect.TypeOf((*MockGasSponsorshipService)(nil).UpdateSponsorshipConfig), ctx, workspaceID, updates)
 }
 
+// UpdateSponsorshipRule mocks base method.
+func (m *MockGasSponsorshipService) UpdateSponsorshipRule(ctx context.Context, ruleID uuid.UUID, arg2 params.SponsorshipRuleParams) (*business.SponsorshipRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSponsorshipRule", ctx, ruleID, arg2)
+	ret0, _ := ret[0].(*business.SponsorshipRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSponsorshipRule indicates an expected call of UpdateSponsorshipRule.
+func (mr *MockGasSponsorshipServiceMockRecorder) UpdateSponsorshipRule(ctx, ruleID, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSponsorshipRule", reflect.TypeOf((*MockGasSponsorshipService)(nil).UpdateSponsorshipRule), ctx, ruleID, arg2)
+}
+
+// MockTreasuryService is a mock of TreasuryService interface.
+type MockTreasuryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTreasuryServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTreasuryServiceMockRecorder is the mock recorder for MockTreasuryService.
+type MockTreasuryServiceMockRecorder struct {
+	mock *MockTreasuryService
+}
+
+// NewMockTreasuryService creates a new mock instance.
+func NewMockTreasuryService(ctrl *gomock.Controller) *MockTreasuryService {
+	mock := &MockTreasuryService{ctrl: ctrl}
+	mock.recorder = &MockTreasuryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTreasuryService) EXPECT() *MockTreasuryServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateSweepRule mocks base method.
+func (m *MockTreasuryService) CreateSweepRule(ctx context.Context, arg1 params.TreasurySweepRuleParams) (*business.TreasurySweepRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSweepRule", ctx, arg1)
+	ret0, _ := ret[0].(*business.TreasurySweepRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSweepRule indicates an expected call of CreateSweepRule.
+func (mr *MockTreasuryServiceMockRecorder) CreateSweepRule(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSweepRule", reflect.TypeOf((*MockTreasuryService)(nil).CreateSweepRule), ctx, arg1)
+}
+
+// DeleteSweepRule mocks base method.
+func (m *MockTreasuryService) DeleteSweepRule(ctx context.Context, workspaceID, ruleID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSweepRule", ctx, workspaceID, ruleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSweepRule indicates an expected call of DeleteSweepRule.
+func (mr *MockTreasuryServiceMockRecorder) DeleteSweepRule(ctx, workspaceID, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSweepRule", reflect.TypeOf((*MockTreasuryService)(nil).DeleteSweepRule), ctx, workspaceID, ruleID)
+}
+
+// ListMovements mocks base method.
+func (m *MockTreasuryService) ListMovements(ctx context.Context, arg1 params.ListTreasuryMovementsParams) ([]business.TreasuryMovement, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMovements", ctx, arg1)
+	ret0, _ := ret[0].([]business.TreasuryMovement)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListMovements indicates an expected call of ListMovements.
+func (mr *MockTreasuryServiceMockRecorder) ListMovements(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMovements", reflect.TypeOf((*MockTreasuryService)(nil).ListMovements), ctx, arg1)
+}
+
+// ListSweepRules mocks base method.
+func (m *MockTreasuryService) ListSweepRules(ctx context.Context, workspaceID uuid.UUID) ([]business.TreasurySweepRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSweepRules", ctx, workspaceID)
+	ret0, _ := ret[0].([]business.TreasurySweepRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSweepRules indicates an expected call of ListSweepRules.
+func (mr *MockTreasuryServiceMockRecorder) ListSweepRules(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSweepRules", reflect.TypeOf((*MockTreasuryService)(nil).ListSweepRules), ctx, workspaceID)
+}
+
+// ReconcileMovements mocks base method.
+func (m *MockTreasuryService) ReconcileMovements(ctx context.Context, now time.Time) (*business.TreasuryReconcileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileMovements", ctx, now)
+	ret0, _ := ret[0].(*business.TreasuryReconcileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileMovements indicates an expected call of ReconcileMovements.
+func (mr *MockTreasuryServiceMockRecorder) ReconcileMovements(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileMovements", reflect.TypeOf((*MockTreasuryService)(nil).ReconcileMovements), ctx, now)
+}
+
+// RunSweeps mocks base method.
+func (m *MockTreasuryService) RunSweeps(ctx context.Context, now time.Time) (*business.TreasurySweepResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunSweeps", ctx, now)
+	ret0, _ := ret[0].(*business.TreasurySweepResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunSweeps indicates an expected call of RunSweeps.
+func (mr *MockTreasuryServiceMockRecorder) RunSweeps(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunSweeps", reflect.TypeOf((*MockTreasuryService)(nil).RunSweeps), ctx, now)
+}
+
+// UpdateSweepRule mocks base method.
+func (m *MockTreasuryService) UpdateSweepRule(ctx context.Context, ruleID uuid.UUID, arg2 params.TreasurySweepRuleParams) (*business.TreasurySweepRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSweepRule", ctx, ruleID, arg2)
+	ret0, _ := ret[0].(*business.TreasurySweepRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSweepRule indicates an expected call of UpdateSweepRule.
+func (mr *MockTreasuryServiceMockRecorder) UpdateSweepRule(ctx, ruleID, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSweepRule", reflect.TypeOf((*MockTreasuryService)(nil).UpdateSweepRule), ctx, ruleID, arg2)
+}
+
+// MockPortfolioService is a mock of PortfolioService interface.
+type MockPortfolioService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPortfolioServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPortfolioServiceMockRecorder is the mock recorder for MockPortfolioService.
+type MockPortfolioServiceMockRecorder struct {
+	mock *MockPortfolioService
+}
+
+// NewMockPortfolioService creates a new mock instance.
+func NewMockPortfolioService(ctrl *gomock.Controller) *MockPortfolioService {
+	mock := &MockPortfolioService{ctrl: ctrl}
+	mock.recorder = &MockPortfolioServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPortfolioService) EXPECT() *MockPortfolioServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPortfolio mocks base method.
+func (m *MockPortfolioService) GetPortfolio(ctx context.Context, workspaceID uuid.UUID, walletID *uuid.UUID, refresh bool) (*business.Portfolio, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPortfolio", ctx, workspaceID, walletID, refresh)
+	ret0, _ := ret[0].(*business.Portfolio)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPortfolio indicates an expected call of GetPortfolio.
+func (mr *MockPortfolioServiceMockRecorder) GetPortfolio(ctx, workspaceID, walletID, refresh any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortfolio", reflect.TypeOf((*MockPortfolioService)(nil).GetPortfolio), ctx, workspaceID, walletID, refresh)
+}
+
+// ListSnapshots mocks base method.
+func (m *MockPortfolioService) ListSnapshots(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) ([]business.PortfolioSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSnapshots", ctx, workspaceID, start, end)
+	ret0, _ := ret[0].([]business.PortfolioSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSnapshots indicates an expected call of ListSnapshots.
+func (mr *MockPortfolioServiceMockRecorder) ListSnapshots(ctx, workspaceID, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSnapshots", reflect.TypeOf((*MockPortfolioService)(nil).ListSnapshots), ctx, workspaceID, start, end)
+}
+
+// RecordSnapshots mocks base method.
+func (m *MockPortfolioService) RecordSnapshots(ctx context.Context, now time.Time) (*business.PortfolioSnapshotResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSnapshots", ctx, now)
+	ret0, _ := ret[0].(*business.PortfolioSnapshotResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordSnapshots indicates an expected call of RecordSnapshots.
+func (mr *MockPortfolioServiceMockRecorder) RecordSnapshots(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSnapshots", reflect.TypeOf((*MockPortfolioService)(nil).RecordSnapshots), ctx, now)
+}
+
+// MockNameResolver is a mock of NameResolver interface.
+type MockNameResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockNameResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockNameResolverMockRecorder is the mock recorder for MockNameResolver.
+type MockNameResolverMockRecorder struct {
+	mock *MockNameResolver
+}
+
+// NewMockNameResolver creates a new mock instance.
+func NewMockNameResolver(ctrl *gomock.Controller) *MockNameResolver {
+	mock := &MockNameResolver{ctrl: ctrl}
+	mock.recorder = &MockNameResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNameResolver) EXPECT() *MockNameResolverMockRecorder {
+	return m.recorder
+}
+
+// LookupAddress mocks base method.
+func (m *MockNameResolver) LookupAddress(ctx context.Context, address string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupAddress", ctx, address)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupAddress indicates an expected call of LookupAddress.
+func (mr *MockNameResolverMockRecorder) LookupAddress(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupAddress", reflect.TypeOf((*MockNameResolver)(nil).LookupAddress), ctx, address)
+}
+
+// ResolveName mocks base method.
+func (m *MockNameResolver) ResolveName(ctx context.Context, name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveName", ctx, name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveName indicates an expected call of ResolveName.
+func (mr *MockNameResolverMockRecorder) ResolveName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveName", reflect.TypeOf((*MockNameResolver)(nil).ResolveName), ctx, name)
+}
+
 // MockBlockchainService is a mock of BlockchainService interface.
 type MockBlockchainService struct {
 	ctrl     *gomock.Controller
@@ -1105,6 +1523,36 @@ func (mr *MockBlockchainServiceMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBlockchainService)(nil).Close))
 }
 
+// GetNativeBalance mocks base method.
+func (m *MockBlockchainService) GetNativeBalance(ctx context.Context, networkID uuid.UUID, account string) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNativeBalance", ctx, networkID, account)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNativeBalance indicates an expected call of GetNativeBalance.
+func (mr *MockBlockchainServiceMockRecorder) GetNativeBalance(ctx, networkID, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNativeBalance", reflect.TypeOf((*MockBlockchainService)(nil).GetNativeBalance), ctx, networkID, account)
+}
+
+// GetTokenBalance mocks base method.
+func (m *MockBlockchainService) GetTokenBalance(ctx context.Context, networkID uuid.UUID, token, account string) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenBalance", ctx, networkID, token, account)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenBalance indicates an expected call of GetTokenBalance.
+func (mr *MockBlockchainServiceMockRecorder) GetTokenBalance(ctx, networkID, token, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenBalance", reflect.TypeOf((*MockBlockchainService)(nil).GetTokenBalance), ctx, networkID, token, account)
+}
+
 // GetTransactionData mocks base method.
 func (m *MockBlockchainService) GetTransactionData(ctx context.Context, txHash string, networkID uuid.UUID) (*business.TransactionData, error) {
 	m.ctrl.T.Helper()
@@ -1216,31 +1664,454 @@ type MockTaxServiceMockRecorder struct {
 	mock *MockTaxService
 }
 
-// NewMockTaxService creates a new mock instance.
-func NewMockTaxService(ctrl *gomock.Controller) *MockTaxService {
-	mock := &MockTaxService{ctrl: ctrl}
-	mock.recorder = &MockTaxServiceMockRecorder{mock}
-	return mock
+// NewMockTaxService creates a new mock instance.
+func NewMockTaxService(ctrl *gomock.Controller) *MockTaxService {
+	mock := &MockTaxService{ctrl: ctrl}
+	mock.recorder = &MockTaxServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxService) EXPECT() *MockTaxServiceMockRecorder {
+	return m.recorder
+}
+
+// CalculateTax mocks base method.
+func (m *MockTaxService) CalculateTax(ctx context.Context, arg1 params.TaxCalculationParams) (*responses.TaxCalculationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalculateTax", ctx, arg1)
+	ret0, _ := ret[0].(*responses.TaxCalculationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalculateTax indicates an expected call of CalculateTax.
+func (mr *MockTaxServiceMockRecorder) CalculateTax(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateTax", reflect.TypeOf((*MockTaxService)(nil).CalculateTax), ctx, arg1)
+}
+
+// CreateJurisdiction mocks base method.
+func (m *MockTaxService) CreateJurisdiction(ctx context.Context, arg1 params.CreateTaxJurisdictionParams) (*db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJurisdiction", ctx, arg1)
+	ret0, _ := ret[0].(*db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJurisdiction indicates an expected call of CreateJurisdiction.
+func (mr *MockTaxServiceMockRecorder) CreateJurisdiction(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJurisdiction", reflect.TypeOf((*MockTaxService)(nil).CreateJurisdiction), ctx, arg1)
+}
+
+// CreateRate mocks base method.
+func (m *MockTaxService) CreateRate(ctx context.Context, arg1 params.CreateTaxRateParams) (*db.TaxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRate", ctx, arg1)
+	ret0, _ := ret[0].(*db.TaxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRate indicates an expected call of CreateRate.
+func (mr *MockTaxServiceMockRecorder) CreateRate(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRate", reflect.TypeOf((*MockTaxService)(nil).CreateRate), ctx, arg1)
+}
+
+// DeleteScheduledRate mocks base method.
+func (m *MockTaxService) DeleteScheduledRate(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledRate", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScheduledRate indicates an expected call of DeleteScheduledRate.
+func (mr *MockTaxServiceMockRecorder) DeleteScheduledRate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledRate", reflect.TypeOf((*MockTaxService)(nil).DeleteScheduledRate), ctx, id)
+}
+
+// EndRate mocks base method.
+func (m *MockTaxService) EndRate(ctx context.Context, id uuid.UUID, effectiveTo time.Time) (*db.TaxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndRate", ctx, id, effectiveTo)
+	ret0, _ := ret[0].(*db.TaxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EndRate indicates an expected call of EndRate.
+func (mr *MockTaxServiceMockRecorder) EndRate(ctx, id, effectiveTo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndRate", reflect.TypeOf((*MockTaxService)(nil).EndRate), ctx, id, effectiveTo)
+}
+
+// GetJurisdiction mocks base method.
+func (m *MockTaxService) GetJurisdiction(ctx context.Context, id uuid.UUID) (*db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJurisdiction", ctx, id)
+	ret0, _ := ret[0].(*db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJurisdiction indicates an expected call of GetJurisdiction.
+func (mr *MockTaxServiceMockRecorder) GetJurisdiction(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJurisdiction", reflect.TypeOf((*MockTaxService)(nil).GetJurisdiction), ctx, id)
+}
+
+// GetTaxRatesForJurisdiction mocks base method.
+func (m *MockTaxService) GetTaxRatesForJurisdiction(ctx context.Context, jurisdictionCode string) (*business.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxRatesForJurisdiction", ctx, jurisdictionCode)
+	ret0, _ := ret[0].(*business.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxRatesForJurisdiction indicates an expected call of GetTaxRatesForJurisdiction.
+func (mr *MockTaxServiceMockRecorder) GetTaxRatesForJurisdiction(ctx, jurisdictionCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxRatesForJurisdiction", reflect.TypeOf((*MockTaxService)(nil).GetTaxRatesForJurisdiction), ctx, jurisdictionCode)
+}
+
+// ListJurisdictions mocks base method.
+func (m *MockTaxService) ListJurisdictions(ctx context.Context, countryCode string, limit, offset int32) ([]db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJurisdictions", ctx, countryCode, limit, offset)
+	ret0, _ := ret[0].([]db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJurisdictions indicates an expected call of ListJurisdictions.
+func (mr *MockTaxServiceMockRecorder) ListJurisdictions(ctx, countryCode, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJurisdictions", reflect.TypeOf((*MockTaxService)(nil).ListJurisdictions), ctx, countryCode, limit, offset)
+}
+
+// ListRates mocks base method.
+func (m *MockTaxService) ListRates(ctx context.Context, jurisdictionID uuid.UUID) ([]db.TaxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRates", ctx, jurisdictionID)
+	ret0, _ := ret[0].([]db.TaxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRates indicates an expected call of ListRates.
+func (mr *MockTaxServiceMockRecorder) ListRates(ctx, jurisdictionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRates", reflect.TypeOf((*MockTaxService)(nil).ListRates), ctx, jurisdictionID)
+}
+
+// UpdateJurisdiction mocks base method.
+func (m *MockTaxService) UpdateJurisdiction(ctx context.Context, arg1 params.UpdateTaxJurisdictionParams) (*db.TaxJurisdiction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJurisdiction", ctx, arg1)
+	ret0, _ := ret[0].(*db.TaxJurisdiction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJurisdiction indicates an expected call of UpdateJurisdiction.
+func (mr *MockTaxServiceMockRecorder) UpdateJurisdiction(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJurisdiction", reflect.TypeOf((*MockTaxService)(nil).UpdateJurisdiction), ctx, arg1)
+}
+
+// MockTaxProvider is a mock of TaxProvider interface.
+type MockTaxProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxProviderMockRecorder is the mock recorder for MockTaxProvider.
+type MockTaxProviderMockRecorder struct {
+	mock *MockTaxProvider
+}
+
+// NewMockTaxProvider creates a new mock instance.
+func NewMockTaxProvider(ctrl *gomock.Controller) *MockTaxProvider {
+	mock := &MockTaxProvider{ctrl: ctrl}
+	mock.recorder = &MockTaxProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxProvider) EXPECT() *MockTaxProviderMockRecorder {
+	return m.recorder
+}
+
+// CalculateTax mocks base method.
+func (m *MockTaxProvider) CalculateTax(ctx context.Context, arg1 params.TaxCalculationParams) (*responses.TaxCalculationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalculateTax", ctx, arg1)
+	ret0, _ := ret[0].(*responses.TaxCalculationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalculateTax indicates an expected call of CalculateTax.
+func (mr *MockTaxProviderMockRecorder) CalculateTax(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateTax", reflect.TypeOf((*MockTaxProvider)(nil).CalculateTax), ctx, arg1)
+}
+
+// CommitTransaction mocks base method.
+func (m *MockTaxProvider) CommitTransaction(ctx context.Context, arg1 params.TaxCalculationParams, calculation *responses.TaxCalculationResult) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitTransaction", ctx, arg1, calculation)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitTransaction indicates an expected call of CommitTransaction.
+func (mr *MockTaxProviderMockRecorder) CommitTransaction(ctx, arg1, calculation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitTransaction", reflect.TypeOf((*MockTaxProvider)(nil).CommitTransaction), ctx, arg1, calculation)
+}
+
+// Name mocks base method.
+func (m *MockTaxProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockTaxProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockTaxProvider)(nil).Name))
+}
+
+// MockTaxIDRegistry is a mock of TaxIDRegistry interface.
+type MockTaxIDRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxIDRegistryMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxIDRegistryMockRecorder is the mock recorder for MockTaxIDRegistry.
+type MockTaxIDRegistryMockRecorder struct {
+	mock *MockTaxIDRegistry
+}
+
+// NewMockTaxIDRegistry creates a new mock instance.
+func NewMockTaxIDRegistry(ctrl *gomock.Controller) *MockTaxIDRegistry {
+	mock := &MockTaxIDRegistry{ctrl: ctrl}
+	mock.recorder = &MockTaxIDRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxIDRegistry) EXPECT() *MockTaxIDRegistryMockRecorder {
+	return m.recorder
+}
+
+// CheckTaxID mocks base method.
+func (m *MockTaxIDRegistry) CheckTaxID(ctx context.Context, countryCode, taxID string) (*business.TaxIDCheckResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckTaxID", ctx, countryCode, taxID)
+	ret0, _ := ret[0].(*business.TaxIDCheckResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckTaxID indicates an expected call of CheckTaxID.
+func (mr *MockTaxIDRegistryMockRecorder) CheckTaxID(ctx, countryCode, taxID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTaxID", reflect.TypeOf((*MockTaxIDRegistry)(nil).CheckTaxID), ctx, countryCode, taxID)
+}
+
+// Name mocks base method.
+func (m *MockTaxIDRegistry) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockTaxIDRegistryMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockTaxIDRegistry)(nil).Name))
+}
+
+// MockTaxIDVerificationService is a mock of TaxIDVerificationService interface.
+type MockTaxIDVerificationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxIDVerificationServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxIDVerificationServiceMockRecorder is the mock recorder for MockTaxIDVerificationService.
+type MockTaxIDVerificationServiceMockRecorder struct {
+	mock *MockTaxIDVerificationService
+}
+
+// NewMockTaxIDVerificationService creates a new mock instance.
+func NewMockTaxIDVerificationService(ctrl *gomock.Controller) *MockTaxIDVerificationService {
+	mock := &MockTaxIDVerificationService{ctrl: ctrl}
+	mock.recorder = &MockTaxIDVerificationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxIDVerificationService) EXPECT() *MockTaxIDVerificationServiceMockRecorder {
+	return m.recorder
+}
+
+// ListCustomerVerifications mocks base method.
+func (m *MockTaxIDVerificationService) ListCustomerVerifications(ctx context.Context, customerID uuid.UUID, limit int32) ([]db.TaxIDVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCustomerVerifications", ctx, customerID, limit)
+	ret0, _ := ret[0].([]db.TaxIDVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCustomerVerifications indicates an expected call of ListCustomerVerifications.
+func (mr *MockTaxIDVerificationServiceMockRecorder) ListCustomerVerifications(ctx, customerID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomerVerifications", reflect.TypeOf((*MockTaxIDVerificationService)(nil).ListCustomerVerifications), ctx, customerID, limit)
+}
+
+// ReverifyCustomerTaxIDs mocks base method.
+func (m *MockTaxIDVerificationService) ReverifyCustomerTaxIDs(ctx context.Context, verifiedBefore time.Time, limit int32) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverifyCustomerTaxIDs", ctx, verifiedBefore, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverifyCustomerTaxIDs indicates an expected call of ReverifyCustomerTaxIDs.
+func (mr *MockTaxIDVerificationServiceMockRecorder) ReverifyCustomerTaxIDs(ctx, verifiedBefore, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverifyCustomerTaxIDs", reflect.TypeOf((*MockTaxIDVerificationService)(nil).ReverifyCustomerTaxIDs), ctx, verifiedBefore, limit)
+}
+
+// VerifyCustomerTaxID mocks base method.
+func (m *MockTaxIDVerificationService) VerifyCustomerTaxID(ctx context.Context, customerID uuid.UUID) (*db.TaxIDVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyCustomerTaxID", ctx, customerID)
+	ret0, _ := ret[0].(*db.TaxIDVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyCustomerTaxID indicates an expected call of VerifyCustomerTaxID.
+func (mr *MockTaxIDVerificationServiceMockRecorder) VerifyCustomerTaxID(ctx, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCustomerTaxID", reflect.TypeOf((*MockTaxIDVerificationService)(nil).VerifyCustomerTaxID), ctx, customerID)
+}
+
+// VerifyTaxID mocks base method.
+func (m *MockTaxIDVerificationService) VerifyTaxID(ctx context.Context, customerID *uuid.UUID, countryCode, taxID string) (*db.TaxIDVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTaxID", ctx, customerID, countryCode, taxID)
+	ret0, _ := ret[0].(*db.TaxIDVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTaxID indicates an expected call of VerifyTaxID.
+func (mr *MockTaxIDVerificationServiceMockRecorder) VerifyTaxID(ctx, customerID, countryCode, taxID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTaxID", reflect.TypeOf((*MockTaxIDVerificationService)(nil).VerifyTaxID), ctx, customerID, countryCode, taxID)
+}
+
+// MockTaxReportService is a mock of TaxReportService interface.
+type MockTaxReportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxReportServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxReportServiceMockRecorder is the mock recorder for MockTaxReportService.
+type MockTaxReportServiceMockRecorder struct {
+	mock *MockTaxReportService
+}
+
+// NewMockTaxReportService creates a new mock instance.
+func NewMockTaxReportService(ctrl *gomock.Controller) *MockTaxReportService {
+	mock := &MockTaxReportService{ctrl: ctrl}
+	mock.recorder = &MockTaxReportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxReportService) EXPECT() *MockTaxReportServiceMockRecorder {
+	return m.recorder
+}
+
+// GetOSSReport mocks base method.
+func (m *MockTaxReportService) GetOSSReport(ctx context.Context, workspaceID uuid.UUID, year, quarter int, homeCountry string) (*responses.OSSReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOSSReport", ctx, workspaceID, year, quarter, homeCountry)
+	ret0, _ := ret[0].(*responses.OSSReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOSSReport indicates an expected call of GetOSSReport.
+func (mr *MockTaxReportServiceMockRecorder) GetOSSReport(ctx, workspaceID, year, quarter, homeCountry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOSSReport", reflect.TypeOf((*MockTaxReportService)(nil).GetOSSReport), ctx, workspaceID, year, quarter, homeCountry)
+}
+
+// GetReverseChargeReport mocks base method.
+func (m *MockTaxReportService) GetReverseChargeReport(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*responses.ReverseChargeReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReverseChargeReport", ctx, workspaceID, start, end)
+	ret0, _ := ret[0].(*responses.ReverseChargeReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReverseChargeReport indicates an expected call of GetReverseChargeReport.
+func (mr *MockTaxReportServiceMockRecorder) GetReverseChargeReport(ctx, workspaceID, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReverseChargeReport", reflect.TypeOf((*MockTaxReportService)(nil).GetReverseChargeReport), ctx, workspaceID, start, end)
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockTaxService) EXPECT() *MockTaxServiceMockRecorder {
-	return m.recorder
+// GetTaxSummary mocks base method.
+func (m *MockTaxReportService) GetTaxSummary(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*responses.TaxSummaryReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxSummary", ctx, workspaceID, start, end)
+	ret0, _ := ret[0].(*responses.TaxSummaryReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CalculateTax mocks base method.
-func (m *MockTaxService) CalculateTax(ctx context.Context, arg1 params.TaxCalculationParams) (*responses.TaxCalculationResult, error) {
+// GetTaxSummary indicates an expected call of GetTaxSummary.
+func (mr *MockTaxReportServiceMockRecorder) GetTaxSummary(ctx, workspaceID, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxSummary", reflect.TypeOf((*MockTaxReportService)(nil).GetTaxSummary), ctx, workspaceID, start, end)
+}
+
+// GetUSStateTaxReport mocks base method.
+func (m *MockTaxReportService) GetUSStateTaxReport(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*responses.USStateTaxReport, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CalculateTax", ctx, arg1)
-	ret0, _ := ret[0].(*responses.TaxCalculationResult)
+	ret := m.ctrl.Call(m, "GetUSStateTaxReport", ctx, workspaceID, start, end)
+	ret0, _ := ret[0].(*responses.USStateTaxReport)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CalculateTax indicates an expected call of CalculateTax.
-func (mr *MockTaxServiceMockRecorder) CalculateTax(ctx, arg1 any) *gomock.Call {
+// GetUSStateTaxReport indicates an expected call of GetUSStateTaxReport.
+func (mr *MockTaxReportServiceMockRecorder) GetUSStateTaxReport(ctx, workspaceID, start, end any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateTax", reflect.TypeOf((*MockTaxService)(nil).CalculateTax), ctx, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUSStateTaxReport", reflect.TypeOf((*MockTaxReportService)(nil).GetUSStateTaxReport), ctx, workspaceID, start, end)
 }
 
 // MockPaymentLinkService is a mock of PaymentLinkService interface.
@@ -1515,6 +2386,35 @@ func (mr *MockGasFeeServiceMockRecorder) GetCurrentGasPrice(ctx, networkID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentGasPrice", reflect.TypeOf((*MockGasFeeService)(nil).GetCurrentGasPrice), ctx, networkID)
 }
 
+// GetGasFeeEstimateAccuracy mocks base method.
+func (m *MockGasFeeService) GetGasFeeEstimateAccuracy(ctx context.Context, workspaceID uuid.UUID, startDate, endDate time.Time) ([]business.GasFeeEstimateAccuracy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGasFeeEstimateAccuracy", ctx, workspaceID, startDate, endDate)
+	ret0, _ := ret[0].([]business.GasFeeEstimateAccuracy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGasFeeEstimateAccuracy indicates an expected call of GetGasFeeEstimateAccuracy.
+func (mr *MockGasFeeServiceMockRecorder) GetGasFeeEstimateAccuracy(ctx, workspaceID, startDate, endDate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGasFeeEstimateAccuracy", reflect.TypeOf((*MockGasFeeService)(nil).GetGasFeeEstimateAccuracy), ctx, workspaceID, startDate, endDate)
+}
+
+// RecordGasFeeEstimate mocks base method.
+func (m *MockGasFeeService) RecordGasFeeEstimate(ctx context.Context, workspaceID, paymentID, networkID uuid.UUID, estimate *responses.EstimateGasFeeResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordGasFeeEstimate", ctx, workspaceID, paymentID, networkID, estimate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordGasFeeEstimate indicates an expected call of RecordGasFeeEstimate.
+func (mr *MockGasFeeServiceMockRecorder) RecordGasFeeEstimate(ctx, workspaceID, paymentID, networkID, estimate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordGasFeeEstimate", reflect.TypeOf((*MockGasFeeService)(nil).RecordGasFeeEstimate), ctx, workspaceID, paymentID, networkID, estimate)
+}
+
 // MockDunningRetryEngine is a mock of DunningRetryEngine interface.
 type MockDunningRetryEngine struct {
 	ctrl     *gomock.Controller
@@ -2806,55 +3706,391 @@ func (m *MockAPIKeyService) GetAPIKey(ctx context.Context, id, workspaceID uuid.
 	return ret0, ret1
 }
 
-// GetAPIKey indicates an expected call of GetAPIKey.
-func (mr *MockAPIKeyServiceMockRecorder) GetAPIKey(ctx, id, workspaceID any) *gomock.Call {
+// GetAPIKey indicates an expected call of GetAPIKey.
+func (mr *MockAPIKeyServiceMockRecorder) GetAPIKey(ctx, id, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKey", reflect.TypeOf((*MockAPIKeyService)(nil).GetAPIKey), ctx, id, workspaceID)
+}
+
+// GetAllAPIKeys mocks base method.
+func (m *MockAPIKeyService) GetAllAPIKeys(ctx context.Context) ([]db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllAPIKeys", ctx)
+	ret0, _ := ret[0].([]db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllAPIKeys indicates an expected call of GetAllAPIKeys.
+func (mr *MockAPIKeyServiceMockRecorder) GetAllAPIKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAPIKeys", reflect.TypeOf((*MockAPIKeyService)(nil).GetAllAPIKeys), ctx)
+}
+
+// ListAPIKeyUsage mocks base method.
+func (m *MockAPIKeyService) ListAPIKeyUsage(ctx context.Context, id, workspaceID uuid.UUID, startDate, endDate time.Time) ([]db.ApiKeyUsageDaily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeyUsage", ctx, id, workspaceID, startDate, endDate)
+	ret0, _ := ret[0].([]db.ApiKeyUsageDaily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeyUsage indicates an expected call of ListAPIKeyUsage.
+func (mr *MockAPIKeyServiceMockRecorder) ListAPIKeyUsage(ctx, id, workspaceID, startDate, endDate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeyUsage", reflect.TypeOf((*MockAPIKeyService)(nil).ListAPIKeyUsage), ctx, id, workspaceID, startDate, endDate)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockAPIKeyService) ListAPIKeys(ctx context.Context, workspaceID uuid.UUID) ([]db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx, workspaceID)
+	ret0, _ := ret[0].([]db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockAPIKeyServiceMockRecorder) ListAPIKeys(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockAPIKeyService)(nil).ListAPIKeys), ctx, workspaceID)
+}
+
+// RecordAPIKeyUsage mocks base method.
+func (m *MockAPIKeyService) RecordAPIKeyUsage(ctx context.Context, arg1 params.RecordAPIKeyUsageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAPIKeyUsage", ctx, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAPIKeyUsage indicates an expected call of RecordAPIKeyUsage.
+func (mr *MockAPIKeyServiceMockRecorder) RecordAPIKeyUsage(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAPIKeyUsage", reflect.TypeOf((*MockAPIKeyService)(nil).RecordAPIKeyUsage), ctx, arg1)
+}
+
+// RotateAPIKey mocks base method.
+func (m *MockAPIKeyService) RotateAPIKey(ctx context.Context, arg1 params.RotateAPIKeyParams) (db.ApiKey, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateAPIKey", ctx, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RotateAPIKey indicates an expected call of RotateAPIKey.
+func (mr *MockAPIKeyServiceMockRecorder) RotateAPIKey(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateAPIKey", reflect.TypeOf((*MockAPIKeyService)(nil).RotateAPIKey), ctx, arg1)
+}
+
+// UpdateAPIKey mocks base method.
+func (m *MockAPIKeyService) UpdateAPIKey(ctx context.Context, arg1 params.UpdateAPIKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAPIKey", ctx, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAPIKey indicates an expected call of UpdateAPIKey.
+func (mr *MockAPIKeyServiceMockRecorder) UpdateAPIKey(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKey", reflect.TypeOf((*MockAPIKeyService)(nil).UpdateAPIKey), ctx, arg1)
+}
+
+// MockCustomerPortalService is a mock of CustomerPortalService interface.
+type MockCustomerPortalService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerPortalServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockCustomerPortalServiceMockRecorder is the mock recorder for MockCustomerPortalService.
+type MockCustomerPortalServiceMockRecorder struct {
+	mock *MockCustomerPortalService
+}
+
+// NewMockCustomerPortalService creates a new mock instance.
+func NewMockCustomerPortalService(ctrl *gomock.Controller) *MockCustomerPortalService {
+	mock := &MockCustomerPortalService{ctrl: ctrl}
+	mock.recorder = &MockCustomerPortalServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomerPortalService) EXPECT() *MockCustomerPortalServiceMockRecorder {
+	return m.recorder
+}
+
+// AuthenticateSession mocks base method.
+func (m *MockCustomerPortalService) AuthenticateSession(ctx context.Context, token string) (db.CustomerPortalSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateSession", ctx, token)
+	ret0, _ := ret[0].(db.CustomerPortalSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateSession indicates an expected call of AuthenticateSession.
+func (mr *MockCustomerPortalServiceMockRecorder) AuthenticateSession(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateSession", reflect.TypeOf((*MockCustomerPortalService)(nil).AuthenticateSession), ctx, token)
+}
+
+// CancelSubscription mocks base method.
+func (m *MockCustomerPortalService) CancelSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID, reason, feedback string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSubscription", ctx, scope, subscriptionID, reason, feedback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSubscription indicates an expected call of CancelSubscription.
+func (mr *MockCustomerPortalServiceMockRecorder) CancelSubscription(ctx, scope, subscriptionID, reason, feedback any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSubscription", reflect.TypeOf((*MockCustomerPortalService)(nil).CancelSubscription), ctx, scope, subscriptionID, reason, feedback)
+}
+
+// CreateSession mocks base method.
+func (m *MockCustomerPortalService) CreateSession(ctx context.Context, arg1 params.CreateCustomerPortalSessionParams) (db.CustomerPortalSession, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, arg1)
+	ret0, _ := ret[0].(db.CustomerPortalSession)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockCustomerPortalServiceMockRecorder) CreateSession(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockCustomerPortalService)(nil).CreateSession), ctx, arg1)
+}
+
+// DeleteExpiredSessions mocks base method.
+func (m *MockCustomerPortalService) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredSessions", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredSessions indicates an expected call of DeleteExpiredSessions.
+func (mr *MockCustomerPortalServiceMockRecorder) DeleteExpiredSessions(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSessions", reflect.TypeOf((*MockCustomerPortalService)(nil).DeleteExpiredSessions), ctx, before)
+}
+
+// GetCustomer mocks base method.
+func (m *MockCustomerPortalService) GetCustomer(ctx context.Context, scope params.CustomerPortalScope) (db.Customer, []db.CustomerWallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomer", ctx, scope)
+	ret0, _ := ret[0].(db.Customer)
+	ret1, _ := ret[1].([]db.CustomerWallet)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCustomer indicates an expected call of GetCustomer.
+func (mr *MockCustomerPortalServiceMockRecorder) GetCustomer(ctx, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomer", reflect.TypeOf((*MockCustomerPortalService)(nil).GetCustomer), ctx, scope)
+}
+
+// GetSettings mocks base method.
+func (m *MockCustomerPortalService) GetSettings(ctx context.Context, workspaceID uuid.UUID) (db.CustomerPortalSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", ctx, workspaceID)
+	ret0, _ := ret[0].(db.CustomerPortalSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockCustomerPortalServiceMockRecorder) GetSettings(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockCustomerPortalService)(nil).GetSettings), ctx, workspaceID)
+}
+
+// ListInvoices mocks base method.
+func (m *MockCustomerPortalService) ListInvoices(ctx context.Context, scope params.CustomerPortalScope, limit, offset int32) ([]db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvoices", ctx, scope, limit, offset)
+	ret0, _ := ret[0].([]db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvoices indicates an expected call of ListInvoices.
+func (mr *MockCustomerPortalServiceMockRecorder) ListInvoices(ctx, scope, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvoices", reflect.TypeOf((*MockCustomerPortalService)(nil).ListInvoices), ctx, scope, limit, offset)
+}
+
+// ListPayments mocks base method.
+func (m *MockCustomerPortalService) ListPayments(ctx context.Context, scope params.CustomerPortalScope, limit, offset int32) ([]db.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayments", ctx, scope, limit, offset)
+	ret0, _ := ret[0].([]db.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayments indicates an expected call of ListPayments.
+func (mr *MockCustomerPortalServiceMockRecorder) ListPayments(ctx, scope, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayments", reflect.TypeOf((*MockCustomerPortalService)(nil).ListPayments), ctx, scope, limit, offset)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockCustomerPortalService) ListSubscriptions(ctx context.Context, scope params.CustomerPortalScope) ([]business.CustomerPortalSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", ctx, scope)
+	ret0, _ := ret[0].([]business.CustomerPortalSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockCustomerPortalServiceMockRecorder) ListSubscriptions(ctx, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockCustomerPortalService)(nil).ListSubscriptions), ctx, scope)
+}
+
+// PauseSubscription mocks base method.
+func (m *MockCustomerPortalService) PauseSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID, pauseUntil *time.Time, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseSubscription", ctx, scope, subscriptionID, pauseUntil, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseSubscription indicates an expected call of PauseSubscription.
+func (mr *MockCustomerPortalServiceMockRecorder) PauseSubscription(ctx, scope, subscriptionID, pauseUntil, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseSubscription", reflect.TypeOf((*MockCustomerPortalService)(nil).PauseSubscription), ctx, scope, subscriptionID, pauseUntil, reason)
+}
+
+// PreviewCancellation mocks base method.
+func (m *MockCustomerPortalService) PreviewCancellation(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID) (*business.ChangePreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewCancellation", ctx, scope, subscriptionID)
+	ret0, _ := ret[0].(*business.ChangePreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewCancellation indicates an expected call of PreviewCancellation.
+func (mr *MockCustomerPortalServiceMockRecorder) PreviewCancellation(ctx, scope, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewCancellation", reflect.TypeOf((*MockCustomerPortalService)(nil).PreviewCancellation), ctx, scope, subscriptionID)
+}
+
+// ReauthorizeSubscription mocks base method.
+func (m *MockCustomerPortalService) ReauthorizeSubscription(ctx context.Context, arg1 params.ReauthorizeSubscriptionParams) (db.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReauthorizeSubscription", ctx, arg1)
+	ret0, _ := ret[0].(db.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReauthorizeSubscription indicates an expected call of ReauthorizeSubscription.
+func (mr *MockCustomerPortalServiceMockRecorder) ReauthorizeSubscription(ctx, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReauthorizeSubscription", reflect.TypeOf((*MockCustomerPortalService)(nil).ReauthorizeSubscription), ctx, arg1)
+}
+
+// ResumeSubscription mocks base method.
+func (m *MockCustomerPortalService) ResumeSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeSubscription", ctx, scope, subscriptionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeSubscription indicates an expected call of ResumeSubscription.
+func (mr *MockCustomerPortalServiceMockRecorder) ResumeSubscription(ctx, scope, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSubscription", reflect.TypeOf((*MockCustomerPortalService)(nil).ResumeSubscription), ctx, scope, subscriptionID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockCustomerPortalService) RevokeSession(ctx context.Context, sessionID, workspaceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, sessionID, workspaceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockCustomerPortalServiceMockRecorder) RevokeSession(ctx, sessionID, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockCustomerPortalService)(nil).RevokeSession), ctx, sessionID, workspaceID)
+}
+
+// SessionURL mocks base method.
+func (m *MockCustomerPortalService) SessionURL(token string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionURL", token)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SessionURL indicates an expected call of SessionURL.
+func (mr *MockCustomerPortalServiceMockRecorder) SessionURL(token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKey", reflect.TypeOf((*MockAPIKeyService)(nil).GetAPIKey), ctx, id, workspaceID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionURL", reflect.TypeOf((*MockCustomerPortalService)(nil).SessionURL), token)
 }
 
-// GetAllAPIKeys mocks base method.
-func (m *MockAPIKeyService) GetAllAPIKeys(ctx context.Context) ([]db.ApiKey, error) {
+// SwitchSubscriptionWallet mocks base method.
+func (m *MockCustomerPortalService) SwitchSubscriptionWallet(ctx context.Context, arg1 params.SwitchSubscriptionWalletParams) (db.Subscription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllAPIKeys", ctx)
-	ret0, _ := ret[0].([]db.ApiKey)
+	ret := m.ctrl.Call(m, "SwitchSubscriptionWallet", ctx, arg1)
+	ret0, _ := ret[0].(db.Subscription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllAPIKeys indicates an expected call of GetAllAPIKeys.
-func (mr *MockAPIKeyServiceMockRecorder) GetAllAPIKeys(ctx any) *gomock.Call {
+// SwitchSubscriptionWallet indicates an expected call of SwitchSubscriptionWallet.
+func (mr *MockCustomerPortalServiceMockRecorder) SwitchSubscriptionWallet(ctx, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAPIKeys", reflect.TypeOf((*MockAPIKeyService)(nil).GetAllAPIKeys), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwitchSubscriptionWallet", reflect.TypeOf((*MockCustomerPortalService)(nil).SwitchSubscriptionWallet), ctx, arg1)
 }
 
-// ListAPIKeys mocks base method.
-func (m *MockAPIKeyService) ListAPIKeys(ctx context.Context, workspaceID uuid.UUID) ([]db.ApiKey, error) {
+// UpdateBillingDetails mocks base method.
+func (m *MockCustomerPortalService) UpdateBillingDetails(ctx context.Context, arg1 params.UpdateCustomerBillingDetailsParams) (db.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAPIKeys", ctx, workspaceID)
-	ret0, _ := ret[0].([]db.ApiKey)
+	ret := m.ctrl.Call(m, "UpdateBillingDetails", ctx, arg1)
+	ret0, _ := ret[0].(db.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAPIKeys indicates an expected call of ListAPIKeys.
-func (mr *MockAPIKeyServiceMockRecorder) ListAPIKeys(ctx, workspaceID any) *gomock.Call {
+// UpdateBillingDetails indicates an expected call of UpdateBillingDetails.
+func (mr *MockCustomerPortalServiceMockRecorder) UpdateBillingDetails(ctx, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockAPIKeyService)(nil).ListAPIKeys), ctx, workspaceID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBillingDetails", reflect.TypeOf((*MockCustomerPortalService)(nil).UpdateBillingDetails), ctx, arg1)
 }
 
-// UpdateAPIKey mocks base method.
-func (m *MockAPIKeyService) UpdateAPIKey(ctx context.Context, arg1 params.UpdateAPIKeyParams) (db.ApiKey, error) {
+// UpdateSettings mocks base method.
+func (m *MockCustomerPortalService) UpdateSettings(ctx context.Context, arg1 params.UpdateCustomerPortalSettingsParams) (db.CustomerPortalSetting, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAPIKey", ctx, arg1)
-	ret0, _ := ret[0].(db.ApiKey)
+	ret := m.ctrl.Call(m, "UpdateSettings", ctx, arg1)
+	ret0, _ := ret[0].(db.CustomerPortalSetting)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAPIKey indicates an expected call of UpdateAPIKey.
-func (mr *MockAPIKeyServiceMockRecorder) UpdateAPIKey(ctx, arg1 any) *gomock.Call {
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockCustomerPortalServiceMockRecorder) UpdateSettings(ctx, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKey", reflect.TypeOf((*MockAPIKeyService)(nil).UpdateAPIKey), ctx, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockCustomerPortalService)(nil).UpdateSettings), ctx, arg1)
 }
 
 // MockUserService is a mock of UserService interface.
@@ -3532,6 +4768,21 @@ func (m *MockAnalyticsService) EXPECT() *MockAnalyticsServiceMockRecorder {
 	return m.recorder
 }
 
+// GetCohortAnalysis mocks base method.
+func (m *MockAnalyticsService) GetCohortAnalysis(ctx context.Context, workspaceID uuid.UUID, cohortBy string, months int, currency string) (*business.CohortAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCohortAnalysis", ctx, workspaceID, cohortBy, months, currency)
+	ret0, _ := ret[0].(*business.CohortAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCohortAnalysis indicates an expected call of GetCohortAnalysis.
+func (mr *MockAnalyticsServiceMockRecorder) GetCohortAnalysis(ctx, workspaceID, cohortBy, months, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCohortAnalysis", reflect.TypeOf((*MockAnalyticsService)(nil).GetCohortAnalysis), ctx, workspaceID, cohortBy, months, currency)
+}
+
 // GetCustomerChart mocks base method.
 func (m *MockAnalyticsService) GetCustomerChart(ctx context.Context, workspaceID uuid.UUID, metric, period string, days int, currency string) (*business.ChartData, error) {
 	m.ctrl.T.Helper()
@@ -3607,6 +4858,21 @@ func (mr *MockAnalyticsServiceMockRecorder) GetMRRChart(ctx, workspaceID, metric
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMRRChart", reflect.TypeOf((*MockAnalyticsService)(nil).GetMRRChart), ctx, workspaceID, metric, period, months, currency)
 }
 
+// GetMRRMovements mocks base method.
+func (m *MockAnalyticsService) GetMRRMovements(ctx context.Context, listParams params.ListMRRMovementsParams) (*business.MRRMovementReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMRRMovements", ctx, listParams)
+	ret0, _ := ret[0].(*business.MRRMovementReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMRRMovements indicates an expected call of GetMRRMovements.
+func (mr *MockAnalyticsServiceMockRecorder) GetMRRMovements(ctx, listParams any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMRRMovements", reflect.TypeOf((*MockAnalyticsService)(nil).GetMRRMovements), ctx, listParams)
+}
+
 // GetNetworkBreakdown mocks base method.
 func (m *MockAnalyticsService) GetNetworkBreakdown(ctx context.Context, workspaceID uuid.UUID, date time.Time, currency string) (*business.NetworkBreakdown, error) {
 	m.ctrl.T.Helper()
@@ -3681,6 +4947,211 @@ func (mr *MockAnalyticsServiceMockRecorder) TriggerMetricsRefresh(ctx, workspace
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TriggerMetricsRefresh", reflect.TypeOf((*MockAnalyticsService)(nil).TriggerMetricsRefresh), ctx, workspaceID, date)
 }
 
+// MockAnalyticsExportService is a mock of AnalyticsExportService interface.
+type MockAnalyticsExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalyticsExportServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAnalyticsExportServiceMockRecorder is the mock recorder for MockAnalyticsExportService.
+type MockAnalyticsExportServiceMockRecorder struct {
+	mock *MockAnalyticsExportService
+}
+
+// NewMockAnalyticsExportService creates a new mock instance.
+func NewMockAnalyticsExportService(ctrl *gomock.Controller) *MockAnalyticsExportService {
+	mock := &MockAnalyticsExportService{ctrl: ctrl}
+	mock.recorder = &MockAnalyticsExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalyticsExportService) EXPECT() *MockAnalyticsExportServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateExport mocks base method.
+func (m *MockAnalyticsExportService) CreateExport(ctx context.Context, exportParams params.CreateAnalyticsExportParams) (*db.AnalyticsExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExport", ctx, exportParams)
+	ret0, _ := ret[0].(*db.AnalyticsExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExport indicates an expected call of CreateExport.
+func (mr *MockAnalyticsExportServiceMockRecorder) CreateExport(ctx, exportParams any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExport", reflect.TypeOf((*MockAnalyticsExportService)(nil).CreateExport), ctx, exportParams)
+}
+
+// CreateReportSchedule mocks base method.
+func (m *MockAnalyticsExportService) CreateReportSchedule(ctx context.Context, scheduleParams params.AnalyticsReportScheduleParams) (*db.AnalyticsReportSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReportSchedule", ctx, scheduleParams)
+	ret0, _ := ret[0].(*db.AnalyticsReportSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReportSchedule indicates an expected call of CreateReportSchedule.
+func (mr *MockAnalyticsExportServiceMockRecorder) CreateReportSchedule(ctx, scheduleParams any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReportSchedule", reflect.TypeOf((*MockAnalyticsExportService)(nil).CreateReportSchedule), ctx, scheduleParams)
+}
+
+// DeleteReportSchedule mocks base method.
+func (m *MockAnalyticsExportService) DeleteReportSchedule(ctx context.Context, workspaceID, scheduleID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReportSchedule", ctx, workspaceID, scheduleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReportSchedule indicates an expected call of DeleteReportSchedule.
+func (mr *MockAnalyticsExportServiceMockRecorder) DeleteReportSchedule(ctx, workspaceID, scheduleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReportSchedule", reflect.TypeOf((*MockAnalyticsExportService)(nil).DeleteReportSchedule), ctx, workspaceID, scheduleID)
+}
+
+// DeliverDueReports mocks base method.
+func (m *MockAnalyticsExportService) DeliverDueReports(ctx context.Context, now time.Time, batchSize int32) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverDueReports", ctx, now, batchSize)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverDueReports indicates an expected call of DeliverDueReports.
+func (mr *MockAnalyticsExportServiceMockRecorder) DeliverDueReports(ctx, now, batchSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverDueReports", reflect.TypeOf((*MockAnalyticsExportService)(nil).DeliverDueReports), ctx, now, batchSize)
+}
+
+// GetExport mocks base method.
+func (m *MockAnalyticsExportService) GetExport(ctx context.Context, workspaceID, exportID uuid.UUID) (*db.AnalyticsExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExport", ctx, workspaceID, exportID)
+	ret0, _ := ret[0].(*db.AnalyticsExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExport indicates an expected call of GetExport.
+func (mr *MockAnalyticsExportServiceMockRecorder) GetExport(ctx, workspaceID, exportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExport", reflect.TypeOf((*MockAnalyticsExportService)(nil).GetExport), ctx, workspaceID, exportID)
+}
+
+// GetReportSchedule mocks base method.
+func (m *MockAnalyticsExportService) GetReportSchedule(ctx context.Context, workspaceID, scheduleID uuid.UUID) (*db.AnalyticsReportSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportSchedule", ctx, workspaceID, scheduleID)
+	ret0, _ := ret[0].(*db.AnalyticsReportSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportSchedule indicates an expected call of GetReportSchedule.
+func (mr *MockAnalyticsExportServiceMockRecorder) GetReportSchedule(ctx, workspaceID, scheduleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportSchedule", reflect.TypeOf((*MockAnalyticsExportService)(nil).GetReportSchedule), ctx, workspaceID, scheduleID)
+}
+
+// ListExports mocks base method.
+func (m *MockAnalyticsExportService) ListExports(ctx context.Context, workspaceID uuid.UUID, limit, offset int32) ([]db.AnalyticsExportJob, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExports", ctx, workspaceID, limit, offset)
+	ret0, _ := ret[0].([]db.AnalyticsExportJob)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListExports indicates an expected call of ListExports.
+func (mr *MockAnalyticsExportServiceMockRecorder) ListExports(ctx, workspaceID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExports", reflect.TypeOf((*MockAnalyticsExportService)(nil).ListExports), ctx, workspaceID, limit, offset)
+}
+
+// ListReportSchedules mocks base method.
+func (m *MockAnalyticsExportService) ListReportSchedules(ctx context.Context, workspaceID uuid.UUID) ([]db.AnalyticsReportSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReportSchedules", ctx, workspaceID)
+	ret0, _ := ret[0].([]db.AnalyticsReportSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReportSchedules indicates an expected call of ListReportSchedules.
+func (mr *MockAnalyticsExportServiceMockRecorder) ListReportSchedules(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReportSchedules", reflect.TypeOf((*MockAnalyticsExportService)(nil).ListReportSchedules), ctx, workspaceID)
+}
+
+// OpenExportFile mocks base method.
+func (m *MockAnalyticsExportService) OpenExportFile(ctx context.Context, workspaceID, exportID uuid.UUID, dataset string) (io.ReadCloser, *business.ExportFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenExportFile", ctx, workspaceID, exportID, dataset)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(*business.ExportFile)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenExportFile indicates an expected call of OpenExportFile.
+func (mr *MockAnalyticsExportServiceMockRecorder) OpenExportFile(ctx, workspaceID, exportID, dataset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenExportFile", reflect.TypeOf((*MockAnalyticsExportService)(nil).OpenExportFile), ctx, workspaceID, exportID, dataset)
+}
+
+// ProcessPendingExports mocks base method.
+func (m *MockAnalyticsExportService) ProcessPendingExports(ctx context.Context, batchSize int32) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessPendingExports", ctx, batchSize)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessPendingExports indicates an expected call of ProcessPendingExports.
+func (mr *MockAnalyticsExportServiceMockRecorder) ProcessPendingExports(ctx, batchSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessPendingExports", reflect.TypeOf((*MockAnalyticsExportService)(nil).ProcessPendingExports), ctx, batchSize)
+}
+
+// RunExport mocks base method.
+func (m *MockAnalyticsExportService) RunExport(ctx context.Context, exportID uuid.UUID) (*db.AnalyticsExportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunExport", ctx, exportID)
+	ret0, _ := ret[0].(*db.AnalyticsExportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunExport indicates an expected call of RunExport.
+func (mr *MockAnalyticsExportServiceMockRecorder) RunExport(ctx, exportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunExport", reflect.TypeOf((*MockAnalyticsExportService)(nil).RunExport), ctx, exportID)
+}
+
+// UpdateReportSchedule mocks base method.
+func (m *MockAnalyticsExportService) UpdateReportSchedule(ctx context.Context, scheduleID uuid.UUID, scheduleParams params.AnalyticsReportScheduleParams) (*db.AnalyticsReportSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReportSchedule", ctx, scheduleID, scheduleParams)
+	ret0, _ := ret[0].(*db.AnalyticsReportSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReportSchedule indicates an expected call of UpdateReportSchedule.
+func (mr *MockAnalyticsExportServiceMockRecorder) UpdateReportSchedule(ctx, scheduleID, scheduleParams any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReportSchedule", reflect.TypeOf((*MockAnalyticsExportService)(nil).UpdateReportSchedule), ctx, scheduleID, scheduleParams)
+}
+
 // MockBlockchainSyncService is a mock of BlockchainSyncService interface.
 type MockBlockchainSyncService struct {
 	ctrl     *gomock.Controller
@@ -3853,6 +5324,191 @@ func (mr *MockPaymentFailureDetectorMockRecorder) ProcessFailedPaymentWebhook(ct
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessFailedPaymentWebhook", reflect.TypeOf((*MockPaymentFailureDetector)(nil).ProcessFailedPaymentWebhook), ctx, workspaceID, subscriptionID, failureData)
 }
 
+// MockRedemptionQueueService is a mock of RedemptionQueueService interface.
+type MockRedemptionQueueService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedemptionQueueServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRedemptionQueueServiceMockRecorder is the mock recorder for MockRedemptionQueueService.
+type MockRedemptionQueueServiceMockRecorder struct {
+	mock *MockRedemptionQueueService
+}
+
+// NewMockRedemptionQueueService creates a new mock instance.
+func NewMockRedemptionQueueService(ctrl *gomock.Controller) *MockRedemptionQueueService {
+	mock := &MockRedemptionQueueService{ctrl: ctrl}
+	mock.recorder = &MockRedemptionQueueServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedemptionQueueService) EXPECT() *MockRedemptionQueueServiceMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method.
+func (m *MockRedemptionQueueService) Enqueue(ctx context.Context, task business.RedemptionTask) (*db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, task)
+	ret0, _ := ret[0].(*db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockRedemptionQueueServiceMockRecorder) Enqueue(ctx, task any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockRedemptionQueueService)(nil).Enqueue), ctx, task)
+}
+
+// GetQueueStats mocks base method.
+func (m *MockRedemptionQueueService) GetQueueStats(ctx context.Context) (*business.RedemptionQueueStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueueStats", ctx)
+	ret0, _ := ret[0].(*business.RedemptionQueueStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueueStats indicates an expected call of GetQueueStats.
+func (mr *MockRedemptionQueueServiceMockRecorder) GetQueueStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueueStats", reflect.TypeOf((*MockRedemptionQueueService)(nil).GetQueueStats), ctx)
+}
+
+// GetTask mocks base method.
+func (m *MockRedemptionQueueService) GetTask(ctx context.Context, id uuid.UUID) (*db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTask", ctx, id)
+	ret0, _ := ret[0].(*db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTask indicates an expected call of GetTask.
+func (mr *MockRedemptionQueueServiceMockRecorder) GetTask(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockRedemptionQueueService)(nil).GetTask), ctx, id)
+}
+
+// ListTasks mocks base method.
+func (m *MockRedemptionQueueService) ListTasks(ctx context.Context, status string, limit, offset int32) ([]db.RedemptionTask, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks", ctx, status, limit, offset)
+	ret0, _ := ret[0].([]db.RedemptionTask)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockRedemptionQueueServiceMockRecorder) ListTasks(ctx, status, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockRedemptionQueueService)(nil).ListTasks), ctx, status, limit, offset)
+}
+
+// RequeueTask mocks base method.
+func (m *MockRedemptionQueueService) RequeueTask(ctx context.Context, id uuid.UUID, priority *int32) (*db.RedemptionTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueTask", ctx, id, priority)
+	ret0, _ := ret[0].(*db.RedemptionTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueTask indicates an expected call of RequeueTask.
+func (mr *MockRedemptionQueueServiceMockRecorder) RequeueTask(ctx, id, priority any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueTask", reflect.TypeOf((*MockRedemptionQueueService)(nil).RequeueTask), ctx, id, priority)
+}
+
+// MockRenewalReviewService is a mock of RenewalReviewService interface.
+type MockRenewalReviewService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRenewalReviewServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRenewalReviewServiceMockRecorder is the mock recorder for MockRenewalReviewService.
+type MockRenewalReviewServiceMockRecorder struct {
+	mock *MockRenewalReviewService
+}
+
+// NewMockRenewalReviewService creates a new mock instance.
+func NewMockRenewalReviewService(ctrl *gomock.Controller) *MockRenewalReviewService {
+	mock := &MockRenewalReviewService{ctrl: ctrl}
+	mock.recorder = &MockRenewalReviewServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRenewalReviewService) EXPECT() *MockRenewalReviewServiceMockRecorder {
+	return m.recorder
+}
+
+// GetRenewal mocks base method.
+func (m *MockRenewalReviewService) GetRenewal(ctx context.Context, id uuid.UUID) (*db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRenewal", ctx, id)
+	ret0, _ := ret[0].(*db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRenewal indicates an expected call of GetRenewal.
+func (mr *MockRenewalReviewServiceMockRecorder) GetRenewal(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRenewal", reflect.TypeOf((*MockRenewalReviewService)(nil).GetRenewal), ctx, id)
+}
+
+// ListRenewals mocks base method.
+func (m *MockRenewalReviewService) ListRenewals(ctx context.Context, status string, limit, offset int32) ([]db.SubscriptionRenewal, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRenewals", ctx, status, limit, offset)
+	ret0, _ := ret[0].([]db.SubscriptionRenewal)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRenewals indicates an expected call of ListRenewals.
+func (mr *MockRenewalReviewServiceMockRecorder) ListRenewals(ctx, status, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRenewals", reflect.TypeOf((*MockRenewalReviewService)(nil).ListRenewals), ctx, status, limit, offset)
+}
+
+// RequeueRenewal mocks base method.
+func (m *MockRenewalReviewService) RequeueRenewal(ctx context.Context, id uuid.UUID) (*db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueRenewal", ctx, id)
+	ret0, _ := ret[0].(*db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueRenewal indicates an expected call of RequeueRenewal.
+func (mr *MockRenewalReviewServiceMockRecorder) RequeueRenewal(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueRenewal", reflect.TypeOf((*MockRenewalReviewService)(nil).RequeueRenewal), ctx, id)
+}
+
+// ResolveRenewal mocks base method.
+func (m *MockRenewalReviewService) ResolveRenewal(ctx context.Context, id uuid.UUID, transactionHash string) (*db.SubscriptionRenewal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveRenewal", ctx, id, transactionHash)
+	ret0, _ := ret[0].(*db.SubscriptionRenewal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveRenewal indicates an expected call of ResolveRenewal.
+func (mr *MockRenewalReviewServiceMockRecorder) ResolveRenewal(ctx, id, transactionHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRenewal", reflect.TypeOf((*MockRenewalReviewService)(nil).ResolveRenewal), ctx, id, transactionHash)
+}
+
 // MockCommonServicesInterface is a mock of CommonServicesInterface interface.
 type MockCommonServicesInterface struct {
 	ctrl     *gomock.Controller
@@ -20,7 +20,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DelegationService_RedeemDelegation_FullMethodName = "/delegation.DelegationService/RedeemDelegation"
+	DelegationService_RedeemDelegation_FullMethodName       = "/delegation.DelegationService/RedeemDelegation"
+	DelegationService_SimulateRedemption_FullMethodName     = "/delegation.DelegationService/SimulateRedemption"
+	DelegationService_BatchRedeemDelegations_FullMethodName = "/delegation.DelegationService/BatchRedeemDelegations"
+	DelegationService_GetRedemptionStatus_FullMethodName    = "/delegation.DelegationService/GetRedemptionStatus"
 )
 
 // DelegationServiceClient is the client API for DelegationService service.
@@ -29,6 +32,12 @@ const (
 type DelegationServiceClient interface {
 	// Redeems a delegation
 	RedeemDelegation(ctx context.Context, in *RedeemDelegationRequest, opts ...grpc.CallOption) (*RedeemDelegationResponse, error)
+	// Dry-runs a redemption with eth_call without sending a transaction
+	SimulateRedemption(ctx context.Context, in *RedeemDelegationRequest, opts ...grpc.CallOption) (*SimulateRedemptionResponse, error)
+	// Redeems several delegations on one network in a single transaction
+	BatchRedeemDelegations(ctx context.Context, in *BatchRedeemDelegationsRequest, opts ...grpc.CallOption) (*BatchRedeemDelegationsResponse, error)
+	// Looks up the on-chain status of a redemption transaction
+	GetRedemptionStatus(ctx context.Context, in *GetRedemptionStatusRequest, opts ...grpc.CallOption) (*GetRedemptionStatusResponse, error)
 }
 
 type delegationServiceClient struct {
@@ -49,12 +58,48 @@ func (c *delegationServiceClient) RedeemDelegation(ctx context.Context, in *Rede
 	return out, nil
 }
 
+func (c *delegationServiceClient) SimulateRedemption(ctx context.Context, in *RedeemDelegationRequest, opts ...grpc.CallOption) (*SimulateRedemptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimulateRedemptionResponse)
+	err := c.cc.Invoke(ctx, DelegationService_SimulateRedemption_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *delegationServiceClient) BatchRedeemDelegations(ctx context.Context, in *BatchRedeemDelegationsRequest, opts ...grpc.CallOption) (*BatchRedeemDelegationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchRedeemDelegationsResponse)
+	err := c.cc.Invoke(ctx, DelegationService_BatchRedeemDelegations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *delegationServiceClient) GetRedemptionStatus(ctx context.Context, in *GetRedemptionStatusRequest, opts ...grpc.CallOption) (*GetRedemptionStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRedemptionStatusResponse)
+	err := c.cc.Invoke(ctx, DelegationService_GetRedemptionStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DelegationServiceServer is the server API for DelegationService service.
 // All implementations must embed UnimplementedDelegationServiceServer
 // for forward compatibility.
 type DelegationServiceServer interface {
 	// Redeems a delegation
 	RedeemDelegation(context.Context, *RedeemDelegationRequest) (*RedeemDelegationResponse, error)
+	// Dry-runs a redemption with eth_call without sending a transaction
+	SimulateRedemption(context.Context, *RedeemDelegationRequest) (*SimulateRedemptionResponse, error)
+	// Redeems several delegations on one network in a single transaction
+	BatchRedeemDelegations(context.Context, *BatchRedeemDelegationsRequest) (*BatchRedeemDelegationsResponse, error)
+	// Looks up the on-chain status of a redemption transaction
+	GetRedemptionStatus(context.Context, *GetRedemptionStatusRequest) (*GetRedemptionStatusResponse, error)
 	mustEmbedUnimplementedDelegationServiceServer()
 }
 
@@ -68,6 +113,15 @@ type UnimplementedDelegationServiceServer struct{}
 func (UnimplementedDelegationServiceServer) RedeemDelegation(context.Context, *RedeemDelegationRequest) (*RedeemDelegationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RedeemDelegation not implemented")
 }
+func (UnimplementedDelegationServiceServer) SimulateRedemption(context.Context, *RedeemDelegationRequest) (*SimulateRedemptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateRedemption not implemented")
+}
+func (UnimplementedDelegationServiceServer) BatchRedeemDelegations(context.Context, *BatchRedeemDelegationsRequest) (*BatchRedeemDelegationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchRedeemDelegations not implemented")
+}
+func (UnimplementedDelegationServiceServer) GetRedemptionStatus(context.Context, *GetRedemptionStatusRequest) (*GetRedemptionStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRedemptionStatus not implemented")
+}
 func (UnimplementedDelegationServiceServer) mustEmbedUnimplementedDelegationServiceServer() {}
 func (UnimplementedDelegationServiceServer) testEmbeddedByValue()                           {}
 
@@ -107,6 +161,60 @@ func _DelegationService_RedeemDelegation_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DelegationService_SimulateRedemption_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeemDelegationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DelegationServiceServer).SimulateRedemption(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DelegationService_SimulateRedemption_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DelegationServiceServer).SimulateRedemption(ctx, req.(*RedeemDelegationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DelegationService_BatchRedeemDelegations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRedeemDelegationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DelegationServiceServer).BatchRedeemDelegations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DelegationService_BatchRedeemDelegations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DelegationServiceServer).BatchRedeemDelegations(ctx, req.(*BatchRedeemDelegationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DelegationService_GetRedemptionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRedemptionStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DelegationServiceServer).GetRedemptionStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DelegationService_GetRedemptionStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DelegationServiceServer).GetRedemptionStatus(ctx, req.(*GetRedemptionStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DelegationService_ServiceDesc is the grpc.ServiceDesc for DelegationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -118,6 +226,18 @@ var DelegationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RedeemDelegation",
 			Handler:    _DelegationService_RedeemDelegation_Handler,
 		},
+		{
+			MethodName: "SimulateRedemption",
+			Handler:    _DelegationService_SimulateRedemption_Handler,
+		},
+		{
+			MethodName: "BatchRedeemDelegations",
+			Handler:    _DelegationService_BatchRedeemDelegations_Handler,
+		},
+		{
+			MethodName: "GetRedemptionStatus",
+			Handler:    _DelegationService_GetRedemptionStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal/proto/delegation.proto",
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/parquet-go/parquet-go"
+	"go.uber.org/zap"
+)
+
+// Export rows are written as CSV and as Parquet with the same columns, named by the parquet tags.
+// Amounts are in the smallest unit of their currency; the reporting_* columns hold the same amount
+// converted to the export's currency and are empty when no exchange rate was available.
+
+type paymentExportRow struct {
+	PaymentID            string    `parquet:"payment_id"`
+	CreatedAt            time.Time `parquet:"created_at,timestamp(millisecond)"`
+	CompletedAt          time.Time `parquet:"completed_at,optional,timestamp(millisecond)"`
+	Status               string    `parquet:"status"`
+	PaymentMethod        string    `parquet:"payment_method"`
+	CustomerID           string    `parquet:"customer_id"`
+	CustomerEmail        string    `parquet:"customer_email"`
+	CustomerName         string    `parquet:"customer_name"`
+	SubscriptionID       string    `parquet:"subscription_id"`
+	InvoiceID            string    `parquet:"invoice_id"`
+	Network              string    `parquet:"network"`
+	TransactionHash      string    `parquet:"transaction_hash"`
+	Currency             string    `parquet:"currency"`
+	AmountCents          int64     `parquet:"amount_cents"`
+	ProductAmountCents   int64     `parquet:"product_amount_cents"`
+	TaxAmountCents       int64     `parquet:"tax_amount_cents"`
+	DiscountAmountCents  int64     `parquet:"discount_amount_cents"`
+	GasFeeUSDCents       int64     `parquet:"gas_fee_usd_cents"`
+	GasSponsored         bool      `parquet:"gas_sponsored"`
+	ReportingCurrency    string    `parquet:"reporting_currency"`
+	ExchangeRate         *float64  `parquet:"exchange_rate"`
+	ReportingAmountCents *int64    `parquet:"reporting_amount_cents"`
+}
+
+type invoiceExportRow struct {
+	InvoiceID            string    `parquet:"invoice_id"`
+	InvoiceNumber        string    `parquet:"invoice_number"`
+	Status               string    `parquet:"status"`
+	CustomerID           string    `parquet:"customer_id"`
+	CustomerEmail        string    `parquet:"customer_email"`
+	CustomerName         string    `parquet:"customer_name"`
+	SubscriptionID       string    `parquet:"subscription_id"`
+	Currency             string    `parquet:"currency"`
+	SubtotalCents        int64     `parquet:"subtotal_cents"`
+	DiscountCents        int64     `parquet:"discount_cents"`
+	TaxCents             int64     `parquet:"tax_cents"`
+	TotalCents           int64     `parquet:"total_cents"`
+	AmountPaidCents      int64     `parquet:"amount_paid_cents"`
+	AmountRemainingCents int64     `parquet:"amount_remaining_cents"`
+	ReverseCharge        bool      `parquet:"reverse_charge"`
+	CreatedAt            time.Time `parquet:"created_at,timestamp(millisecond)"`
+	DueAt                time.Time `parquet:"due_at,optional,timestamp(millisecond)"`
+	PaidAt               time.Time `parquet:"paid_at,optional,timestamp(millisecond)"`
+	ReportingCurrency    string    `parquet:"reporting_currency"`
+	ExchangeRate         *float64  `parquet:"exchange_rate"`
+	ReportingTotalCents  *int64    `parquet:"reporting_total_cents"`
+}
+
+type subscriptionExportRow struct {
+	SubscriptionID     string    `parquet:"subscription_id"`
+	Status             string    `parquet:"status"`
+	CustomerID         string    `parquet:"customer_id"`
+	CustomerEmail      string    `parquet:"customer_email"`
+	CustomerName       string    `parquet:"customer_name"`
+	ProductID          string    `parquet:"product_id"`
+	ProductName        string    `parquet:"product_name"`
+	Interval           string    `parquet:"interval"`
+	Currency           string    `parquet:"currency"`
+	AmountCents        int64     `parquet:"amount_cents"`
+	MRRCents           int64     `parquet:"mrr_cents"`
+	TotalRedemptions   int64     `parquet:"total_redemptions"`
+	CurrentPeriodStart time.Time `parquet:"current_period_start,timestamp(millisecond)"`
+	CurrentPeriodEnd   time.Time `parquet:"current_period_end,timestamp(millisecond)"`
+	CreatedAt          time.Time `parquet:"created_at,timestamp(millisecond)"`
+	ReportingCurrency  string    `parquet:"reporting_currency"`
+	ExchangeRate       *float64  `parquet:"exchange_rate"`
+	ReportingMRRCents  *int64    `parquet:"reporting_mrr_cents"`
+}
+
+type mrrMovementExportRow struct {
+	MovementID             string    `parquet:"movement_id"`
+	OccurredAt             time.Time `parquet:"occurred_at,timestamp(millisecond)"`
+	SubscriptionID         string    `parquet:"subscription_id"`
+	CustomerID             string    `parquet:"customer_id"`
+	CustomerEmail          string    `parquet:"customer_email"`
+	CustomerName           string    `parquet:"customer_name"`
+	ProductName            string    `parquet:"product_name"`
+	MovementType           string    `parquet:"movement_type"`
+	Source                 string    `parquet:"source"`
+	Interval               string    `parquet:"interval"`
+	Currency               string    `parquet:"currency"`
+	FromMRRCents           int64     `parquet:"from_mrr_cents"`
+	ToMRRCents             int64     `parquet:"to_mrr_cents"`
+	MRRDeltaCents          int64     `parquet:"mrr_delta_cents"`
+	ReportingCurrency      string    `parquet:"reporting_currency"`
+	ExchangeRate           *float64  `parquet:"exchange_rate"`
+	ReportingMRRDeltaCents *int64    `parquet:"reporting_mrr_delta_cents"`
+}
+
+type gasFeeExportRow struct {
+	GasFeeID             string    `parquet:"gas_fee_id"`
+	PaymentID            string    `parquet:"payment_id"`
+	CreatedAt            time.Time `parquet:"created_at,timestamp(millisecond)"`
+	Network              string    `parquet:"network"`
+	TransactionHash      string    `parquet:"transaction_hash"`
+	BlockNumber          *int64    `parquet:"block_number"`
+	GasUnitsUsed         int64     `parquet:"gas_units_used"`
+	GasPriceGwei         string    `parquet:"gas_price_gwei"`
+	GasFeeWei            string    `parquet:"gas_fee_wei"`
+	PaymentMethod        string    `parquet:"payment_method"`
+	SponsorType          string    `parquet:"sponsor_type"`
+	GasFeeUSDCents       *int64    `parquet:"gas_fee_usd_cents"`
+	ReportingCurrency    string    `parquet:"reporting_currency"`
+	ExchangeRate         *float64  `parquet:"exchange_rate"`
+	ReportingGasFeeCents *int64    `parquet:"reporting_gas_fee_cents"`
+}
+
+// buildDataset writes one dataset of an export job in the job's format
+func (s *AnalyticsExportService) buildDataset(ctx context.Context, job db.AnalyticsExportJob, dataset string, conv *exportConverter) ([]byte, int64, error) {
+	start, end := job.StartDate, job.EndDate
+
+	switch dataset {
+	case business.ExportDatasetPayments:
+		payments, err := s.queries.ListPaymentsForExport(ctx, db.ListPaymentsForExportParams{WorkspaceID: job.WorkspaceID, StartDate: start, EndDate: end})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list payments: %w", err)
+		}
+		rows := make([]paymentExportRow, len(payments))
+		for i, p := range payments {
+			currency := strings.ToUpper(p.Currency)
+			rate, converted := conv.convert(ctx, p.AmountInCents, currency)
+			rows[i] = paymentExportRow{
+				PaymentID:            p.ID.String(),
+				CreatedAt:            p.CreatedAt.Time.UTC(),
+				CompletedAt:          exportTime(p.CompletedAt),
+				Status:               p.Status,
+				PaymentMethod:        p.PaymentMethod,
+				CustomerID:           p.CustomerID.String(),
+				CustomerEmail:        p.CustomerEmail.String,
+				CustomerName:         p.CustomerName.String,
+				SubscriptionID:       exportUUID(p.SubscriptionID),
+				InvoiceID:            exportUUID(p.InvoiceID),
+				Network:              p.NetworkName.String,
+				TransactionHash:      p.TransactionHash.String,
+				Currency:             currency,
+				AmountCents:          p.AmountInCents,
+				ProductAmountCents:   p.ProductAmountCents,
+				TaxAmountCents:       p.TaxAmountCents.Int64,
+				DiscountAmountCents:  p.DiscountAmountCents.Int64,
+				GasFeeUSDCents:       p.GasFeeUsdCents.Int64,
+				GasSponsored:         p.GasSponsored.Bool,
+				ReportingCurrency:    conv.currency,
+				ExchangeRate:         rate,
+				ReportingAmountCents: converted,
+			}
+		}
+		return encodeExportRows(job.Format, rows)
+
+	case business.ExportDatasetInvoices:
+		invoices, err := s.queries.ListInvoicesForExport(ctx, db.ListInvoicesForExportParams{WorkspaceID: job.WorkspaceID, StartDate: start, EndDate: end})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list invoices: %w", err)
+		}
+		rows := make([]invoiceExportRow, len(invoices))
+		for i, inv := range invoices {
+			currency := strings.ToUpper(inv.Currency)
+			rate, converted := conv.convert(ctx, int64(inv.AmountDue), currency)
+			rows[i] = invoiceExportRow{
+				InvoiceID:            inv.ID.String(),
+				InvoiceNumber:        inv.InvoiceNumber.String,
+				Status:               inv.Status,
+				CustomerID:           exportUUID(inv.CustomerID),
+				CustomerEmail:        inv.CustomerEmail.String,
+				CustomerName:         inv.CustomerName.String,
+				SubscriptionID:       exportUUID(inv.SubscriptionID),
+				Currency:             currency,
+				SubtotalCents:        inv.SubtotalCents.Int64,
+				DiscountCents:        inv.DiscountCents.Int64,
+				TaxCents:             inv.TaxAmountCents,
+				TotalCents:           int64(inv.AmountDue),
+				AmountPaidCents:      int64(inv.AmountPaid),
+				AmountRemainingCents: int64(inv.AmountRemaining),
+				ReverseCharge:        inv.ReverseChargeApplies.Bool,
+				CreatedAt:            inv.CreatedDate.Time.UTC(),
+				DueAt:                exportTime(inv.DueDate),
+				PaidAt:               exportTime(inv.PaidAt),
+				ReportingCurrency:    conv.currency,
+				ExchangeRate:         rate,
+				ReportingTotalCents:  converted,
+			}
+		}
+		return encodeExportRows(job.Format, rows)
+
+	case business.ExportDatasetSubscriptions:
+		subscriptions, err := s.queries.ListSubscriptionsForExport(ctx, db.ListSubscriptionsForExportParams{WorkspaceID: job.WorkspaceID, StartDate: start, EndDate: end})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list subscriptions: %w", err)
+		}
+		rows := make([]subscriptionExportRow, len(subscriptions))
+		for i, sub := range subscriptions {
+			mrr := MonthlyRecurringCents(int64(sub.TotalAmountInCents), sub.IntervalType)
+			rate, converted := conv.convert(ctx, mrr, sub.Currency)
+			rows[i] = subscriptionExportRow{
+				SubscriptionID:     sub.ID.String(),
+				Status:             string(sub.Status),
+				CustomerID:         sub.CustomerID.String(),
+				CustomerEmail:      sub.CustomerEmail.String,
+				CustomerName:       sub.CustomerName.String,
+				ProductID:          sub.ProductID.String(),
+				ProductName:        sub.ProductName,
+				Interval:           exportInterval(sub.IntervalType),
+				Currency:           sub.Currency,
+				AmountCents:        int64(sub.TotalAmountInCents),
+				MRRCents:           mrr,
+				TotalRedemptions:   int64(sub.TotalRedemptions),
+				CurrentPeriodStart: sub.CurrentPeriodStart.Time.UTC(),
+				CurrentPeriodEnd:   sub.CurrentPeriodEnd.Time.UTC(),
+				CreatedAt:          sub.CreatedAt.Time.UTC(),
+				ReportingCurrency:  conv.currency,
+				ExchangeRate:       rate,
+				ReportingMRRCents:  converted,
+			}
+		}
+		return encodeExportRows(job.Format, rows)
+
+	case business.ExportDatasetMRRMovements:
+		movements, err := s.queries.ListMRRMovementsForExport(ctx, db.ListMRRMovementsForExportParams{WorkspaceID: job.WorkspaceID, StartDate: start, EndDate: end})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list MRR movements: %w", err)
+		}
+		rows := make([]mrrMovementExportRow, len(movements))
+		for i, m := range movements {
+			rate, converted := conv.convert(ctx, m.MrrDeltaCents, m.Currency)
+			rows[i] = mrrMovementExportRow{
+				MovementID:             m.ID.String(),
+				OccurredAt:             m.OccurredAt.Time.UTC(),
+				SubscriptionID:         m.SubscriptionID.String(),
+				CustomerID:             m.CustomerID.String(),
+				CustomerEmail:          m.CustomerEmail.String,
+				CustomerName:           m.CustomerName.String,
+				ProductName:            m.ProductName,
+				MovementType:           m.MovementType,
+				Source:                 m.Source,
+				Interval:               exportInterval(m.IntervalType),
+				Currency:               m.Currency,
+				FromMRRCents:           m.FromMrrCents,
+				ToMRRCents:             m.ToMrrCents,
+				MRRDeltaCents:          m.MrrDeltaCents,
+				ReportingCurrency:      conv.currency,
+				ExchangeRate:           rate,
+				ReportingMRRDeltaCents: converted,
+			}
+		}
+		return encodeExportRows(job.Format, rows)
+
+	case business.ExportDatasetGasFees:
+		fees, err := s.queries.ListGasFeesForExport(ctx, db.ListGasFeesForExportParams{WorkspaceID: job.WorkspaceID, StartDate: start, EndDate: end})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list gas fees: %w", err)
+		}
+		rows := make([]gasFeeExportRow, len(fees))
+		for i, fee := range fees {
+			row := gasFeeExportRow{
+				GasFeeID:          fee.ID.String(),
+				PaymentID:         fee.PaymentID.String(),
+				CreatedAt:         fee.CreatedAt.Time.UTC(),
+				Network:           fee.NetworkName,
+				TransactionHash:   fee.TransactionHash.String,
+				GasUnitsUsed:      fee.GasUnitsUsed,
+				GasPriceGwei:      fee.GasPriceGwei,
+				GasFeeWei:         fee.GasFeeWei,
+				PaymentMethod:     fee.PaymentMethod,
+				SponsorType:       fee.SponsorType,
+				ReportingCurrency: conv.currency,
+			}
+			if fee.BlockNumber.Valid {
+				row.BlockNumber = &fee.BlockNumber.Int64
+			}
+			if fee.GasFeeUsdCents.Valid {
+				row.GasFeeUSDCents = &fee.GasFeeUsdCents.Int64
+				row.ExchangeRate, row.ReportingGasFeeCents = conv.convert(ctx, fee.GasFeeUsdCents.Int64, "USD")
+			}
+			rows[i] = row
+		}
+		return encodeExportRows(job.Format, rows)
+
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown dataset %q", ErrInvalidExport, dataset)
+	}
+}
+
+// exportConverter converts amounts to an export's reporting currency, fetching each rate once per export
+type exportConverter struct {
+	exchangeRates   *ExchangeRateService
+	currencyService *CurrencyService
+	currency        string
+	rates           map[string]*float64
+	logger          *zap.Logger
+}
+
+func (s *AnalyticsExportService) newExportConverter(currency string) *exportConverter {
+	return &exportConverter{
+		exchangeRates:   s.exchangeRates,
+		currencyService: s.currencyService,
+		currency:        currency,
+		rates:           make(map[string]*float64),
+		logger:          s.logger,
+	}
+}
+
+// convert returns the exchange rate and the converted amount, or nils when no rate is available
+func (c *exportConverter) convert(ctx context.Context, cents int64, from string) (*float64, *int64) {
+	if from == "" {
+		return nil, nil
+	}
+
+	rate, ok := c.rates[from]
+	if !ok {
+		rate = c.fetchRate(ctx, from)
+		c.rates[from] = rate
+	}
+	if rate == nil {
+		return nil, nil
+	}
+
+	converted, err := c.currencyService.ConvertAmount(ctx, cents, from, c.currency, *rate)
+	if err != nil {
+		c.logger.Warn("Failed to convert export amount",
+			zap.String("from", from),
+			zap.String("to", c.currency),
+			zap.Error(err))
+		return rate, nil
+	}
+	return rate, &converted
+}
+
+func (c *exportConverter) fetchRate(ctx context.Context, from string) *float64 {
+	if from == c.currency {
+		one := 1.0
+		return &one
+	}
+	if c.exchangeRates == nil {
+		return nil
+	}
+	result, err := c.exchangeRates.GetExchangeRate(ctx, params.ExchangeRateParams{
+		FromSymbol: from,
+		ToSymbol:   c.currency,
+	})
+	if err != nil {
+		c.logger.Warn("Exchange rate unavailable for export",
+			zap.String("from", from),
+			zap.String("to", c.currency),
+			zap.Error(err))
+		return nil
+	}
+	return &result.Rate
+}
+
+// encodeExportRows writes rows as CSV or Parquet and returns the file and its row count
+func encodeExportRows[T any](format string, rows []T) ([]byte, int64, error) {
+	var buf bytes.Buffer
+
+	switch format {
+	case business.ExportFormatParquet:
+		writer := parquet.NewGenericWriter[T](&buf)
+		if _, err := writer.Write(rows); err != nil {
+			return nil, 0, fmt.Errorf("failed to write parquet rows: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, 0, fmt.Errorf("failed to write parquet file: %w", err)
+		}
+
+	case business.ExportFormatCSV:
+		var zero T
+		rowType := reflect.TypeOf(zero)
+		header := make([]string, rowType.NumField())
+		for i := range header {
+			header[i] = exportColumnName(rowType.Field(i))
+		}
+
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write(header); err != nil {
+			return nil, 0, fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		record := make([]string, len(header))
+		for _, row := range rows {
+			value := reflect.ValueOf(row)
+			for i := range record {
+				record[i] = formatExportValue(value.Field(i))
+			}
+			if err := writer.Write(record); err != nil {
+				return nil, 0, fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, 0, fmt.Errorf("failed to write CSV file: %w", err)
+		}
+
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown format %q", ErrInvalidExport, format)
+	}
+
+	return buf.Bytes(), int64(len(rows)), nil
+}
+
+// exportColumnName returns the column name from a row field's parquet tag
+func exportColumnName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("parquet"), ",")
+	return name
+}
+
+// formatExportValue formats a row field for CSV; nil pointers and zero times are empty cells
+func formatExportValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case string:
+		return value
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case time.Time:
+		if value.IsZero() {
+			return ""
+		}
+		return value.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// exportTime converts a nullable timestamp for an optional time column, where the zero time is null
+func exportTime(t pgtype.Timestamptz) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+	return t.Time.UTC()
+}
+
+func exportUUID(id pgtype.UUID) string {
+	if !id.Valid {
+		return ""
+	}
+	return uuid.UUID(id.Bytes).String()
+}
+
+func exportInterval(interval db.NullIntervalType) string {
+	if !interval.Valid {
+		return ""
+	}
+	return string(interval.IntervalType)
+}
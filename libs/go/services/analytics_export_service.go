@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	// exportJobMaxAttempts is how many times a job is run before it is marked failed
+	exportJobMaxAttempts = 3
+	// exportJobStaleAfter is how long a running job may go without finishing before another worker reclaims it
+	exportJobStaleAfter = 15 * time.Minute
+	// maxExportRangeDays bounds the date range of a single export
+	maxExportRangeDays = 366
+)
+
+var (
+	// ErrInvalidExport is returned when an export request has an unknown dataset, format or date range
+	ErrInvalidExport = errors.New("invalid analytics export")
+	// ErrExportNotReady is returned when files are requested from an export that has not completed
+	ErrExportNotReady = errors.New("analytics export has not completed")
+)
+
+// AnalyticsExportService writes analytics datasets to CSV or Parquet files and delivers scheduled reports
+type AnalyticsExportService struct {
+	queries         db.Querier
+	storage         ExportStorage
+	exchangeRates   *ExchangeRateService
+	currencyService *CurrencyService
+	emailService    IEmailService
+	logger          *zap.Logger
+}
+
+// NewAnalyticsExportService creates an analytics export service.
+// exchangeRates may be nil, in which case amounts in other currencies are exported without a reporting amount.
+// emailService may be nil, in which case scheduled reports are exported but not emailed.
+func NewAnalyticsExportService(queries db.Querier, storage ExportStorage, exchangeRates *ExchangeRateService, emailService IEmailService) *AnalyticsExportService {
+	return &AnalyticsExportService{
+		queries:         queries,
+		storage:         storage,
+		exchangeRates:   exchangeRates,
+		currencyService: NewCurrencyService(queries),
+		emailService:    emailService,
+		logger:          logger.Log,
+	}
+}
+
+// CreateExport validates and queues an export job. The job is run by RunExport or ProcessPendingExports.
+func (s *AnalyticsExportService) CreateExport(ctx context.Context, exportParams params.CreateAnalyticsExportParams) (*db.AnalyticsExportJob, error) {
+	datasets, format, err := validateExportContents(exportParams.Datasets, exportParams.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exportParams.EndDate.After(exportParams.StartDate) {
+		return nil, fmt.Errorf("%w: end date must be after start date", ErrInvalidExport)
+	}
+	if exportParams.EndDate.Sub(exportParams.StartDate) > maxExportRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: date range cannot exceed %d days", ErrInvalidExport, maxExportRangeDays)
+	}
+
+	currency, err := s.exportCurrency(ctx, exportParams.WorkspaceID, exportParams.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var scheduleID pgtype.UUID
+	if exportParams.ReportScheduleID != nil {
+		scheduleID = pgtype.UUID{Bytes: *exportParams.ReportScheduleID, Valid: true}
+	}
+
+	job, err := s.queries.CreateAnalyticsExportJob(ctx, db.CreateAnalyticsExportJobParams{
+		WorkspaceID:      exportParams.WorkspaceID,
+		ReportScheduleID: scheduleID,
+		Datasets:         datasets,
+		Format:           format,
+		FiatCurrency:     currency,
+		StartDate:        pgtype.Timestamptz{Time: exportParams.StartDate.UTC(), Valid: true},
+		EndDate:          pgtype.Timestamptz{Time: exportParams.EndDate.UTC(), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create analytics export: %w", err)
+	}
+
+	s.logger.Info("Queued analytics export",
+		zap.String("export_id", job.ID.String()),
+		zap.String("workspace_id", job.WorkspaceID.String()),
+		zap.Strings("datasets", job.Datasets),
+		zap.String("format", job.Format))
+
+	return &job, nil
+}
+
+// GetExport returns a workspace's export job
+func (s *AnalyticsExportService) GetExport(ctx context.Context, workspaceID, exportID uuid.UUID) (*db.AnalyticsExportJob, error) {
+	job, err := s.queries.GetAnalyticsExportJob(ctx, db.GetAnalyticsExportJobParams{
+		ID:          exportID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListExports returns a page of a workspace's export jobs, newest first, and the total count
+func (s *AnalyticsExportService) ListExports(ctx context.Context, workspaceID uuid.UUID, limit, offset int32) ([]db.AnalyticsExportJob, int64, error) {
+	jobs, err := s.queries.ListAnalyticsExportJobs(ctx, db.ListAnalyticsExportJobsParams{
+		WorkspaceID: workspaceID,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list analytics exports: %w", err)
+	}
+
+	total, err := s.queries.CountAnalyticsExportJobs(ctx, workspaceID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count analytics exports: %w", err)
+	}
+	return jobs, total, nil
+}
+
+// RunExport claims and runs a single export job. It returns nil without error when the job
+// is already being run by another worker or has finished.
+func (s *AnalyticsExportService) RunExport(ctx context.Context, exportID uuid.UUID) (*db.AnalyticsExportJob, error) {
+	job, err := s.queries.ClaimAnalyticsExportJob(ctx, db.ClaimAnalyticsExportJobParams{
+		ID:          exportID,
+		StaleBefore: pgtype.Timestamptz{Time: time.Now().Add(-exportJobStaleAfter), Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim analytics export: %w", err)
+	}
+	return s.processJob(ctx, job)
+}
+
+// ProcessPendingExports runs queued export jobs and jobs abandoned by a stopped worker.
+// It returns the number of jobs that completed.
+func (s *AnalyticsExportService) ProcessPendingExports(ctx context.Context, batchSize int32) (int, error) {
+	jobs, err := s.queries.ClaimAnalyticsExportJobs(ctx, db.ClaimAnalyticsExportJobsParams{
+		StaleBefore: pgtype.Timestamptz{Time: time.Now().Add(-exportJobStaleAfter), Valid: true},
+		BatchSize:   batchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim analytics exports: %w", err)
+	}
+
+	completed := 0
+	for _, job := range jobs {
+		if _, err := s.processJob(ctx, job); err != nil {
+			s.logger.Error("Analytics export failed",
+				zap.String("export_id", job.ID.String()),
+				zap.Int32("attempt", job.Attempts),
+				zap.Error(err))
+			continue
+		}
+		completed++
+	}
+	return completed, nil
+}
+
+// OpenExportFile opens one dataset file of a completed export. The caller must close the reader.
+func (s *AnalyticsExportService) OpenExportFile(ctx context.Context, workspaceID, exportID uuid.UUID, dataset string) (io.ReadCloser, *business.ExportFile, error) {
+	job, err := s.GetExport(ctx, workspaceID, exportID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != business.ExportJobStatusCompleted {
+		return nil, nil, ErrExportNotReady
+	}
+
+	files, err := ExportFiles(*job)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, file := range files {
+		if file.Dataset != dataset {
+			continue
+		}
+		body, err := s.storage.Get(ctx, file.StorageKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return body, &file, nil
+	}
+	return nil, nil, fmt.Errorf("%w: export has no %s dataset", ErrExportFileNotFound, dataset)
+}
+
+// ExportFiles decodes the files recorded on a completed export job
+func ExportFiles(job db.AnalyticsExportJob) ([]business.ExportFile, error) {
+	if len(job.Files) == 0 {
+		return []business.ExportFile{}, nil
+	}
+	var files []business.ExportFile
+	if err := json.Unmarshal(job.Files, &files); err != nil {
+		return nil, fmt.Errorf("failed to decode export files: %w", err)
+	}
+	return files, nil
+}
+
+// processJob writes every dataset of a claimed job to storage and records the result.
+// A failed job is returned to the queue until it has used its attempts.
+func (s *AnalyticsExportService) processJob(ctx context.Context, job db.AnalyticsExportJob) (*db.AnalyticsExportJob, error) {
+	files, contents, err := s.writeExportFiles(ctx, job)
+	if err != nil {
+		failed, failErr := s.queries.FailAnalyticsExportJob(ctx, db.FailAnalyticsExportJobParams{
+			MaxAttempts:  exportJobMaxAttempts,
+			ErrorMessage: pgtype.Text{String: err.Error(), Valid: true},
+			ID:           job.ID,
+		})
+		if failErr != nil {
+			s.logger.Error("Failed to record analytics export failure",
+				zap.String("export_id", job.ID.String()),
+				zap.Error(failErr))
+			return nil, err
+		}
+		return &failed, err
+	}
+
+	filesJSON, err := json.Marshal(files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export files: %w", err)
+	}
+	completed, err := s.queries.CompleteAnalyticsExportJob(ctx, db.CompleteAnalyticsExportJobParams{
+		ID:    job.ID,
+		Files: filesJSON,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete analytics export: %w", err)
+	}
+
+	s.logger.Info("Completed analytics export",
+		zap.String("export_id", job.ID.String()),
+		zap.String("workspace_id", job.WorkspaceID.String()),
+		zap.Int("files", len(files)))
+
+	if completed.ReportScheduleID.Valid {
+		s.deliverReport(ctx, completed, files, contents)
+	}
+	return &completed, nil
+}
+
+// writeExportFiles builds and stores each dataset of a job, returning the file records and their contents
+func (s *AnalyticsExportService) writeExportFiles(ctx context.Context, job db.AnalyticsExportJob) ([]business.ExportFile, [][]byte, error) {
+	conv := s.newExportConverter(job.FiatCurrency)
+	contentType := exportContentType(job.Format)
+
+	files := make([]business.ExportFile, 0, len(job.Datasets))
+	contents := make([][]byte, 0, len(job.Datasets))
+	for _, dataset := range job.Datasets {
+		data, rowCount, err := s.buildDataset(ctx, job, dataset, conv)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to export %s: %w", dataset, err)
+		}
+
+		filename := fmt.Sprintf("%s_%s_%s.%s", dataset,
+			job.StartDate.Time.UTC().Format("2006-01-02"),
+			job.EndDate.Time.UTC().AddDate(0, 0, -1).Format("2006-01-02"),
+			job.Format)
+		key := fmt.Sprintf("exports/%s/%s/%s.%s", job.WorkspaceID, job.ID, dataset, job.Format)
+		if err := s.storage.Put(ctx, key, contentType, data); err != nil {
+			return nil, nil, fmt.Errorf("failed to store %s export: %w", dataset, err)
+		}
+
+		files = append(files, business.ExportFile{
+			Dataset:     dataset,
+			StorageKey:  key,
+			ContentType: contentType,
+			Filename:    filename,
+			RowCount:    rowCount,
+			SizeBytes:   int64(len(data)),
+		})
+		contents = append(contents, data)
+	}
+	return files, contents, nil
+}
+
+// exportCurrency resolves an export's reporting currency, defaulting to the workspace's default currency
+func (s *AnalyticsExportService) exportCurrency(ctx context.Context, workspaceID uuid.UUID, requested string) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(requested))
+	if currency == "" {
+		defaultCurrency, err := s.currencyService.GetWorkspaceDefaultCurrency(ctx, workspaceID)
+		if err != nil {
+			return "USD", nil
+		}
+		return defaultCurrency.Code, nil
+	}
+
+	if _, err := s.queries.GetFiatCurrency(ctx, currency); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("%w: unsupported currency %q", ErrInvalidExport, currency)
+		}
+		return "", fmt.Errorf("failed to look up currency: %w", err)
+	}
+	return currency, nil
+}
+
+// validateExportContents checks the datasets and format of an export, removing duplicate datasets
+func validateExportContents(datasets []string, format string) ([]string, string, error) {
+	if len(datasets) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one dataset is required", ErrInvalidExport)
+	}
+
+	unique := make([]string, 0, len(datasets))
+	for _, dataset := range datasets {
+		if !slices.Contains(business.ExportDatasets, dataset) {
+			return nil, "", fmt.Errorf("%w: unknown dataset %q, must be one of %s",
+				ErrInvalidExport, dataset, strings.Join(business.ExportDatasets, ", "))
+		}
+		if !slices.Contains(unique, dataset) {
+			unique = append(unique, dataset)
+		}
+	}
+
+	switch format {
+	case "":
+		format = business.ExportFormatCSV
+	case business.ExportFormatCSV, business.ExportFormatParquet:
+	default:
+		return nil, "", fmt.Errorf("%w: format must be '%s' or '%s'",
+			ErrInvalidExport, business.ExportFormatCSV, business.ExportFormatParquet)
+	}
+	return unique, format, nil
+}
+
+func exportContentType(format string) string {
+	if format == business.ExportFormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv"
+}
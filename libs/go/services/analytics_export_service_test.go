@@ -0,0 +1,358 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func exportPayments(createdAt time.Time) []db.ListPaymentsForExportRow {
+	return []db.ListPaymentsForExportRow{
+		{
+			ID:            uuid.New(),
+			CreatedAt:     pgtype.Timestamptz{Time: createdAt, Valid: true},
+			Status:        "completed",
+			PaymentMethod: "crypto",
+			CustomerID:    uuid.New(),
+			CustomerEmail: pgtype.Text{String: "buyer@example.com", Valid: true},
+			Currency:      "usd",
+			AmountInCents: 2500,
+		},
+		{
+			ID:            uuid.New(),
+			CreatedAt:     pgtype.Timestamptz{Time: createdAt, Valid: true},
+			Status:        "completed",
+			PaymentMethod: "crypto",
+			CustomerID:    uuid.New(),
+			Currency:      "EUR",
+			AmountInCents: 1000,
+		},
+	}
+}
+
+func TestAnalyticsExportService_CreateExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewAnalyticsExportService(mockQuerier, services.NewLocalExportStorage(t.TempDir()), nil, nil)
+	ctx := context.Background()
+	workspaceID := uuid.New()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("defaults to CSV in the workspace currency", func(t *testing.T) {
+		mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(ctx, workspaceID).Return(db.FiatCurrency{Code: "EUR"}, nil)
+		mockQuerier.EXPECT().CreateAnalyticsExportJob(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CreateAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+				assert.Equal(t, []string{business.ExportDatasetPayments, business.ExportDatasetGasFees}, arg.Datasets)
+				assert.Equal(t, business.ExportFormatCSV, arg.Format)
+				assert.Equal(t, "EUR", arg.FiatCurrency)
+				assert.False(t, arg.ReportScheduleID.Valid)
+				return db.AnalyticsExportJob{ID: uuid.New(), Datasets: arg.Datasets}, nil
+			})
+
+		_, err := service.CreateExport(ctx, params.CreateAnalyticsExportParams{
+			WorkspaceID: workspaceID,
+			Datasets:    []string{business.ExportDatasetPayments, business.ExportDatasetGasFees, business.ExportDatasetPayments},
+			StartDate:   start,
+			EndDate:     start.AddDate(0, 1, 0),
+		})
+		require.NoError(t, err)
+	})
+
+	invalid := []struct {
+		name   string
+		params params.CreateAnalyticsExportParams
+	}{
+		{"no datasets", params.CreateAnalyticsExportParams{StartDate: start, EndDate: start.AddDate(0, 0, 1)}},
+		{"unknown dataset", params.CreateAnalyticsExportParams{Datasets: []string{"refunds"}, StartDate: start, EndDate: start.AddDate(0, 0, 1)}},
+		{"unknown format", params.CreateAnalyticsExportParams{Datasets: []string{business.ExportDatasetPayments}, Format: "xlsx", StartDate: start, EndDate: start.AddDate(0, 0, 1)}},
+		{"empty range", params.CreateAnalyticsExportParams{Datasets: []string{business.ExportDatasetPayments}, StartDate: start, EndDate: start}},
+		{"range too long", params.CreateAnalyticsExportParams{Datasets: []string{business.ExportDatasetPayments}, StartDate: start, EndDate: start.AddDate(2, 0, 0)}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.WorkspaceID = workspaceID
+			_, err := service.CreateExport(ctx, tt.params)
+			assert.ErrorIs(t, err, services.ErrInvalidExport)
+		})
+	}
+}
+
+func TestAnalyticsExportService_RunExport(t *testing.T) {
+	ctx := context.Background()
+	createdAt := time.Date(2024, 1, 15, 12, 30, 0, 0, time.UTC)
+
+	newJob := func(format string) db.AnalyticsExportJob {
+		return db.AnalyticsExportJob{
+			ID:           uuid.New(),
+			WorkspaceID:  uuid.New(),
+			Datasets:     []string{business.ExportDatasetPayments},
+			Format:       format,
+			FiatCurrency: "USD",
+			StartDate:    pgtype.Timestamptz{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+			EndDate:      pgtype.Timestamptz{Time: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+			Status:       business.ExportJobStatusRunning,
+			Attempts:     1,
+		}
+	}
+
+	// runExport runs job against mocked payments and returns the stored file
+	runExport := func(t *testing.T, job db.AnalyticsExportJob) []byte {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		service := services.NewAnalyticsExportService(mockQuerier, services.NewLocalExportStorage(t.TempDir()), nil, nil)
+
+		var stored []byte
+		mockQuerier.EXPECT().ClaimAnalyticsExportJob(ctx, gomock.Any()).Return(job, nil)
+		mockQuerier.EXPECT().ListPaymentsForExport(ctx, db.ListPaymentsForExportParams{
+			WorkspaceID: job.WorkspaceID,
+			StartDate:   job.StartDate,
+			EndDate:     job.EndDate,
+		}).Return(exportPayments(createdAt), nil)
+		mockQuerier.EXPECT().CompleteAnalyticsExportJob(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CompleteAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+				stored = arg.Files
+				completed := job
+				completed.Status = business.ExportJobStatusCompleted
+				completed.Files = arg.Files
+				return completed, nil
+			})
+
+		completed, err := service.RunExport(ctx, job.ID)
+		require.NoError(t, err)
+		require.NotNil(t, completed)
+
+		var files []business.ExportFile
+		require.NoError(t, json.Unmarshal(stored, &files))
+		require.Len(t, files, 1)
+		assert.Equal(t, int64(2), files[0].RowCount)
+		assert.Equal(t, "payments_2024-01-01_2024-01-31."+job.Format, files[0].Filename)
+
+		mockQuerier.EXPECT().GetAnalyticsExportJob(ctx, gomock.Any()).Return(*completed, nil)
+		body, file, err := service.OpenExportFile(ctx, job.WorkspaceID, job.ID, business.ExportDatasetPayments)
+		require.NoError(t, err)
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, file.SizeBytes, int64(len(data)))
+		return data
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		data := runExport(t, newJob(business.ExportFormatCSV))
+
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+
+		column := make(map[string]int, len(records[0]))
+		for i, name := range records[0] {
+			column[name] = i
+		}
+		assert.Equal(t, "2024-01-15T12:30:00Z", records[1][column["created_at"]])
+		assert.Equal(t, "", records[1][column["completed_at"]])
+		assert.Equal(t, "USD", records[1][column["currency"]])
+		assert.Equal(t, "buyer@example.com", records[1][column["customer_email"]])
+		assert.Equal(t, "2500", records[1][column["reporting_amount_cents"]])
+
+		// No exchange rate service, so the EUR payment has no reporting amount
+		assert.Equal(t, "EUR", records[2][column["currency"]])
+		assert.Equal(t, "", records[2][column["exchange_rate"]])
+		assert.Equal(t, "", records[2][column["reporting_amount_cents"]])
+	})
+
+	t.Run("Parquet", func(t *testing.T) {
+		data := runExport(t, newJob(business.ExportFormatParquet))
+
+		type paymentRow struct {
+			CreatedAt            time.Time `parquet:"created_at,timestamp(millisecond)"`
+			Currency             string    `parquet:"currency"`
+			AmountCents          int64     `parquet:"amount_cents"`
+			ReportingAmountCents *int64    `parquet:"reporting_amount_cents"`
+		}
+		rows, err := parquet.Read[paymentRow](bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		assert.True(t, createdAt.Equal(rows[0].CreatedAt))
+		assert.Equal(t, int64(2500), rows[0].AmountCents)
+		require.NotNil(t, rows[0].ReportingAmountCents)
+		assert.Equal(t, int64(2500), *rows[0].ReportingAmountCents)
+		assert.Nil(t, rows[1].ReportingAmountCents)
+	})
+
+	t.Run("failure returns the job to the queue", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		service := services.NewAnalyticsExportService(mockQuerier, services.NewLocalExportStorage(t.TempDir()), nil, nil)
+		job := newJob(business.ExportFormatCSV)
+
+		mockQuerier.EXPECT().ClaimAnalyticsExportJob(ctx, gomock.Any()).Return(job, nil)
+		mockQuerier.EXPECT().ListPaymentsForExport(ctx, gomock.Any()).Return(nil, assert.AnError)
+		mockQuerier.EXPECT().FailAnalyticsExportJob(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.FailAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+				assert.Equal(t, job.ID, arg.ID)
+				assert.Contains(t, arg.ErrorMessage.String, "payments")
+				return db.AnalyticsExportJob{ID: job.ID, Status: business.ExportJobStatusPending}, nil
+			})
+
+		failed, err := service.RunExport(ctx, job.ID)
+		assert.ErrorIs(t, err, assert.AnError)
+		require.NotNil(t, failed)
+		assert.Equal(t, business.ExportJobStatusPending, failed.Status)
+	})
+}
+
+func TestNextReportRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		frequency string
+		now       time.Time
+		expected  time.Time
+	}{
+		{"weekly midweek", business.ReportFrequencyWeekly, time.Date(2024, 1, 17, 9, 0, 0, 0, time.UTC), time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)},
+		{"weekly on monday", business.ReportFrequencyWeekly, time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC)},
+		{"monthly", business.ReportFrequencyMonthly, time.Date(2024, 1, 31, 23, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"monthly in december", business.ReportFrequencyMonthly, time.Date(2024, 12, 5, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, services.NextReportRun(tt.frequency, tt.now))
+		})
+	}
+}
+
+func TestLocalExportStorage(t *testing.T) {
+	ctx := context.Background()
+	storage := services.NewLocalExportStorage(t.TempDir())
+
+	require.NoError(t, storage.Put(ctx, "exports/ws/job/payments.csv", "text/csv", []byte("a,b\n")))
+	body, err := storage.Get(ctx, "exports/ws/job/payments.csv")
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, body.Close())
+	require.NoError(t, err)
+	assert.Equal(t, "a,b\n", string(data))
+
+	_, err = storage.Get(ctx, "exports/ws/job/missing.csv")
+	assert.ErrorIs(t, err, services.ErrExportFileNotFound)
+
+	assert.Error(t, storage.Put(ctx, "../escape.csv", "text/csv", []byte("x")))
+}
+
+func TestNewExportStorage(t *testing.T) {
+	ctx := context.Background()
+
+	// Storage is never chosen implicitly
+	_, err := services.NewExportStorage(ctx, services.ExportStorageConfig{})
+	assert.Error(t, err)
+	_, err = services.NewExportStorage(ctx, services.ExportStorageConfig{Backend: services.ExportStorageLocal})
+	assert.Error(t, err)
+	_, err = services.NewExportStorage(ctx, services.ExportStorageConfig{Backend: "gcs"})
+	assert.Error(t, err)
+
+	storage, err := services.NewExportStorage(ctx, services.ExportStorageConfig{Backend: services.ExportStorageLocal, Directory: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &services.LocalExportStorage{}, storage)
+}
+
+func TestAnalyticsExportService_DeliverDueReports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	mockEmail := mocks.NewMockEmailService(ctrl)
+	service := services.NewAnalyticsExportService(mockQuerier, services.NewLocalExportStorage(t.TempDir()), nil, mockEmail)
+	ctx := context.Background()
+
+	now := time.Date(2024, 2, 1, 0, 5, 0, 0, time.UTC)
+	periodEnd := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	schedule := db.AnalyticsReportSchedule{
+		ID:          uuid.New(),
+		WorkspaceID: uuid.New(),
+		Name:        "Monthly revenue",
+		Frequency:   business.ReportFrequencyMonthly,
+		Datasets:    []string{business.ExportDatasetPayments},
+		Format:      business.ExportFormatCSV,
+		Recipients:  []string{"finance@example.com"},
+	}
+
+	mockQuerier.EXPECT().ClaimDueAnalyticsReportSchedules(ctx, gomock.Any()).Return([]db.ClaimDueAnalyticsReportSchedulesRow{
+		{
+			ID:          schedule.ID,
+			WorkspaceID: schedule.WorkspaceID,
+			Name:        schedule.Name,
+			Frequency:   schedule.Frequency,
+			Datasets:    schedule.Datasets,
+			Format:      schedule.Format,
+			Recipients:  schedule.Recipients,
+			PeriodEnd:   pgtype.Timestamptz{Time: periodEnd, Valid: true},
+		},
+	}, nil)
+	mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(ctx, schedule.WorkspaceID).Return(db.FiatCurrency{Code: "USD"}, nil)
+
+	var job db.AnalyticsExportJob
+	mockQuerier.EXPECT().CreateAnalyticsExportJob(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.CreateAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+			assert.Equal(t, pgtype.UUID{Bytes: schedule.ID, Valid: true}, arg.ReportScheduleID)
+			assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), arg.StartDate.Time)
+			assert.Equal(t, periodEnd, arg.EndDate.Time)
+			job = db.AnalyticsExportJob{
+				ID:               uuid.New(),
+				WorkspaceID:      arg.WorkspaceID,
+				ReportScheduleID: arg.ReportScheduleID,
+				Datasets:         arg.Datasets,
+				Format:           arg.Format,
+				FiatCurrency:     arg.FiatCurrency,
+				StartDate:        arg.StartDate,
+				EndDate:          arg.EndDate,
+				Status:           business.ExportJobStatusPending,
+			}
+			return job, nil
+		})
+	mockQuerier.EXPECT().ClaimAnalyticsExportJob(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.ClaimAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+			job.Status = business.ExportJobStatusRunning
+			return job, nil
+		})
+	mockQuerier.EXPECT().ListPaymentsForExport(ctx, gomock.Any()).Return(exportPayments(periodEnd.AddDate(0, 0, -3)), nil)
+	mockQuerier.EXPECT().CompleteAnalyticsExportJob(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.CompleteAnalyticsExportJobParams) (db.AnalyticsExportJob, error) {
+			job.Status = business.ExportJobStatusCompleted
+			job.Files = arg.Files
+			return job, nil
+		})
+	mockQuerier.EXPECT().GetAnalyticsReportSchedule(ctx, gomock.Any()).Return(schedule, nil)
+	mockEmail.EXPECT().SendTransactionalEmail(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, email params.TransactionalEmailParams) error {
+			assert.Equal(t, schedule.Recipients, email.To)
+			assert.Equal(t, "Monthly revenue: Jan 1, 2024 to Jan 31, 2024", email.Subject)
+			require.Len(t, email.Attachments, 1)
+			assert.Equal(t, "payments_2024-01-01_2024-01-31.csv", email.Attachments[0].Filename)
+			assert.Equal(t, "text/csv", email.Attachments[0].ContentType)
+			assert.NotEmpty(t, email.Attachments[0].Content)
+			return nil
+		})
+
+	delivered, err := service.DeliverDueReports(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxReportAttachmentBytes is the largest total file size emailed as attachments.
+	// Larger reports are emailed without files and downloaded from the export instead.
+	maxReportAttachmentBytes = 20 * 1024 * 1024
+	// maxReportRecipients bounds the recipients of a scheduled report
+	maxReportRecipients = 20
+)
+
+// CreateReportSchedule creates a scheduled report, first delivered at the start of the next period
+func (s *AnalyticsExportService) CreateReportSchedule(ctx context.Context, scheduleParams params.AnalyticsReportScheduleParams) (*db.AnalyticsReportSchedule, error) {
+	columns, err := s.reportScheduleColumns(ctx, scheduleParams)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.queries.CreateAnalyticsReportSchedule(ctx, db.CreateAnalyticsReportScheduleParams{
+		WorkspaceID:  scheduleParams.WorkspaceID,
+		Name:         columns.name,
+		Frequency:    scheduleParams.Frequency,
+		Datasets:     columns.datasets,
+		Format:       columns.format,
+		FiatCurrency: columns.currency,
+		Recipients:   columns.recipients,
+		Active:       scheduleParams.Active,
+		NextRunAt:    pgtype.Timestamptz{Time: NextReportRun(scheduleParams.Frequency, time.Now()), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report schedule: %w", err)
+	}
+
+	s.logger.Info("Created analytics report schedule",
+		zap.String("schedule_id", schedule.ID.String()),
+		zap.String("workspace_id", schedule.WorkspaceID.String()),
+		zap.String("frequency", schedule.Frequency))
+
+	return &schedule, nil
+}
+
+// GetReportSchedule returns a workspace's scheduled report
+func (s *AnalyticsExportService) GetReportSchedule(ctx context.Context, workspaceID, scheduleID uuid.UUID) (*db.AnalyticsReportSchedule, error) {
+	schedule, err := s.queries.GetAnalyticsReportSchedule(ctx, db.GetAnalyticsReportScheduleParams{
+		ID:          scheduleID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// ListReportSchedules returns a workspace's scheduled reports
+func (s *AnalyticsExportService) ListReportSchedules(ctx context.Context, workspaceID uuid.UUID) ([]db.AnalyticsReportSchedule, error) {
+	schedules, err := s.queries.ListAnalyticsReportSchedules(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// UpdateReportSchedule replaces a scheduled report's settings. The next delivery moves to the
+// start of the next period for the new frequency.
+func (s *AnalyticsExportService) UpdateReportSchedule(ctx context.Context, scheduleID uuid.UUID, scheduleParams params.AnalyticsReportScheduleParams) (*db.AnalyticsReportSchedule, error) {
+	columns, err := s.reportScheduleColumns(ctx, scheduleParams)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.queries.UpdateAnalyticsReportSchedule(ctx, db.UpdateAnalyticsReportScheduleParams{
+		ID:           scheduleID,
+		WorkspaceID:  scheduleParams.WorkspaceID,
+		Name:         columns.name,
+		Frequency:    scheduleParams.Frequency,
+		Datasets:     columns.datasets,
+		Format:       columns.format,
+		FiatCurrency: columns.currency,
+		Recipients:   columns.recipients,
+		Active:       scheduleParams.Active,
+		NextRunAt:    pgtype.Timestamptz{Time: NextReportRun(scheduleParams.Frequency, time.Now()), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// DeleteReportSchedule removes a scheduled report
+func (s *AnalyticsExportService) DeleteReportSchedule(ctx context.Context, workspaceID, scheduleID uuid.UUID) error {
+	if _, err := s.GetReportSchedule(ctx, workspaceID, scheduleID); err != nil {
+		return err
+	}
+	return s.queries.DeleteAnalyticsReportSchedule(ctx, db.DeleteAnalyticsReportScheduleParams{
+		ID:          scheduleID,
+		WorkspaceID: workspaceID,
+	})
+}
+
+// DeliverDueReports exports the last period for every schedule due at now and emails the files
+// to its recipients. Each schedule is advanced before its export runs, so a period is reported once;
+// exports that fail are retried by ProcessPendingExports and emailed when they complete.
+// It returns the number of reports delivered.
+func (s *AnalyticsExportService) DeliverDueReports(ctx context.Context, now time.Time, batchSize int32) (int, error) {
+	schedules, err := s.queries.ClaimDueAnalyticsReportSchedules(ctx, db.ClaimDueAnalyticsReportSchedulesParams{
+		Now:       pgtype.Timestamptz{Time: now, Valid: true},
+		BatchSize: batchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due report schedules: %w", err)
+	}
+
+	delivered := 0
+	for _, schedule := range schedules {
+		periodEnd := schedule.PeriodEnd.Time.UTC()
+		scheduleID := schedule.ID
+
+		job, err := s.CreateExport(ctx, params.CreateAnalyticsExportParams{
+			WorkspaceID:      schedule.WorkspaceID,
+			ReportScheduleID: &scheduleID,
+			Datasets:         schedule.Datasets,
+			Format:           schedule.Format,
+			Currency:         schedule.FiatCurrency.String,
+			StartDate:        reportPeriodStart(schedule.Frequency, periodEnd),
+			EndDate:          periodEnd,
+		})
+		if err != nil {
+			s.logger.Error("Failed to queue scheduled report",
+				zap.String("schedule_id", schedule.ID.String()),
+				zap.Error(err))
+			continue
+		}
+
+		completed, err := s.RunExport(ctx, job.ID)
+		if err != nil {
+			s.logger.Error("Scheduled report export failed",
+				zap.String("schedule_id", schedule.ID.String()),
+				zap.String("export_id", job.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		if completed != nil {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// NextReportRun returns the first period boundary after now: the next Monday for weekly reports
+// and the first day of the next month for monthly reports, at midnight UTC
+func NextReportRun(frequency string, now time.Time) time.Time {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if frequency == business.ReportFrequencyWeekly {
+		days := (int(time.Monday) - int(midnight.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return midnight.AddDate(0, 0, days)
+	}
+	return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// reportPeriodStart returns the start of the period that ends at periodEnd
+func reportPeriodStart(frequency string, periodEnd time.Time) time.Time {
+	if frequency == business.ReportFrequencyWeekly {
+		return periodEnd.AddDate(0, 0, -7)
+	}
+	return periodEnd.AddDate(0, -1, 0)
+}
+
+// deliverReport emails a completed scheduled export to the schedule's recipients.
+// Delivery problems are logged; the export itself stays completed and downloadable.
+func (s *AnalyticsExportService) deliverReport(ctx context.Context, job db.AnalyticsExportJob, files []business.ExportFile, contents [][]byte) {
+	if s.emailService == nil {
+		s.logger.Warn("Email service not configured, scheduled report not emailed",
+			zap.String("export_id", job.ID.String()))
+		return
+	}
+
+	schedule, err := s.queries.GetAnalyticsReportSchedule(ctx, db.GetAnalyticsReportScheduleParams{
+		ID:          uuid.UUID(job.ReportScheduleID.Bytes),
+		WorkspaceID: job.WorkspaceID,
+	})
+	if err != nil {
+		s.logger.Warn("Report schedule not found, scheduled report not emailed",
+			zap.String("export_id", job.ID.String()),
+			zap.Error(err))
+		return
+	}
+
+	var totalBytes int64
+	for _, file := range files {
+		totalBytes += file.SizeBytes
+	}
+	attach := totalBytes <= maxReportAttachmentBytes
+
+	var attachments []params.EmailAttachment
+	if attach {
+		for i, file := range files {
+			attachments = append(attachments, params.EmailAttachment{
+				Filename:    file.Filename,
+				Content:     contents[i],
+				ContentType: file.ContentType,
+			})
+		}
+	}
+
+	period := fmt.Sprintf("%s to %s",
+		job.StartDate.Time.UTC().Format("Jan 2, 2006"),
+		job.EndDate.Time.UTC().AddDate(0, 0, -1).Format("Jan 2, 2006"))
+	htmlContent, textContent := reportEmailContent(schedule.Name, period, job, files, attach)
+
+	err = s.emailService.SendTransactionalEmail(ctx, params.TransactionalEmailParams{
+		WorkspaceID: job.WorkspaceID,
+		To:          schedule.Recipients,
+		Subject:     fmt.Sprintf("%s: %s", schedule.Name, period),
+		HTMLContent: htmlContent,
+		TextContent: textContent,
+		Attachments: attachments,
+		Tags: map[string]interface{}{
+			"category": "analytics_report",
+		},
+	})
+	if err != nil {
+		s.logger.Error("Failed to email scheduled report",
+			zap.String("schedule_id", schedule.ID.String()),
+			zap.String("export_id", job.ID.String()),
+			zap.Error(err))
+		return
+	}
+
+	s.logger.Info("Emailed scheduled report",
+		zap.String("schedule_id", schedule.ID.String()),
+		zap.String("export_id", job.ID.String()),
+		zap.Int("recipients", len(schedule.Recipients)),
+		zap.Bool("attached", attach))
+}
+
+// reportEmailContent builds the HTML and text bodies of a scheduled report email
+func reportEmailContent(name, period string, job db.AnalyticsExportJob, files []business.ExportFile, attached bool) (string, string) {
+	var htmlBody, textBody strings.Builder
+
+	fmt.Fprintf(&htmlBody, "<h2>%s</h2><p>Report period: %s (amounts reported in %s)</p><ul>",
+		html.EscapeString(name), html.EscapeString(period), html.EscapeString(job.FiatCurrency))
+	fmt.Fprintf(&textBody, "%s\n\nReport period: %s (amounts reported in %s)\n\n", name, period, job.FiatCurrency)
+
+	for _, file := range files {
+		fmt.Fprintf(&htmlBody, "<li>%s: %d rows</li>", html.EscapeString(file.Dataset), file.RowCount)
+		fmt.Fprintf(&textBody, "- %s: %d rows\n", file.Dataset, file.RowCount)
+	}
+	htmlBody.WriteString("</ul>")
+
+	if attached {
+		htmlBody.WriteString("<p>The report files are attached.</p>")
+		textBody.WriteString("\nThe report files are attached.\n")
+	} else {
+		fmt.Fprintf(&htmlBody, "<p>The report is too large to attach. Download it from export %s in the dashboard.</p>", job.ID)
+		fmt.Fprintf(&textBody, "\nThe report is too large to attach. Download it from export %s in the dashboard.\n", job.ID)
+	}
+	return htmlBody.String(), textBody.String()
+}
+
+type reportScheduleColumns struct {
+	name       string
+	datasets   []string
+	format     string
+	currency   pgtype.Text
+	recipients []string
+}
+
+// reportScheduleColumns validates a schedule's settings and normalizes them for storage
+func (s *AnalyticsExportService) reportScheduleColumns(ctx context.Context, scheduleParams params.AnalyticsReportScheduleParams) (reportScheduleColumns, error) {
+	var columns reportScheduleColumns
+
+	columns.name = strings.TrimSpace(scheduleParams.Name)
+	if columns.name == "" {
+		return columns, fmt.Errorf("%w: name is required", ErrInvalidExport)
+	}
+	if scheduleParams.Frequency != business.ReportFrequencyWeekly && scheduleParams.Frequency != business.ReportFrequencyMonthly {
+		return columns, fmt.Errorf("%w: frequency must be '%s' or '%s'",
+			ErrInvalidExport, business.ReportFrequencyWeekly, business.ReportFrequencyMonthly)
+	}
+
+	datasets, format, err := validateExportContents(scheduleParams.Datasets, scheduleParams.Format)
+	if err != nil {
+		return columns, err
+	}
+	columns.datasets = datasets
+	columns.format = format
+
+	if scheduleParams.Currency != "" {
+		currency, err := s.exportCurrency(ctx, scheduleParams.WorkspaceID, scheduleParams.Currency)
+		if err != nil {
+			return columns, err
+		}
+		columns.currency = pgtype.Text{String: currency, Valid: true}
+	}
+
+	if len(scheduleParams.Recipients) == 0 {
+		return columns, fmt.Errorf("%w: at least one recipient is required", ErrInvalidExport)
+	}
+	if len(scheduleParams.Recipients) > maxReportRecipients {
+		return columns, fmt.Errorf("%w: a report can have at most %d recipients", ErrInvalidExport, maxReportRecipients)
+	}
+	for _, recipient := range scheduleParams.Recipients {
+		address, err := mail.ParseAddress(strings.TrimSpace(recipient))
+		if err != nil {
+			return columns, fmt.Errorf("%w: invalid recipient %q", ErrInvalidExport, recipient)
+		}
+		columns.recipients = append(columns.recipients, address.Address)
+	}
+	return columns, nil
+}
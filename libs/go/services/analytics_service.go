@@ -9,6 +9,7 @@ import (
 	"github.com/cyphera/cyphera-api/libs/go/constants"
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/cyphera/cyphera-api/libs/go/types/business"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -479,14 +480,171 @@ func (s *AnalyticsService) GetMRRChart(ctx context.Context, workspaceID uuid.UUI
 		}
 	}
 
+	series, err := s.getMRRMovementSeries(ctx, workspaceID, metric, period, startDate, endDate, currency)
+	if err != nil {
+		return nil, err
+	}
+
 	return &business.ChartData{
 		ChartType: "line",
 		Title:     title,
 		Data:      chartData,
 		Period:    period,
+		Series:    series,
 	}, nil
 }
 
+// mrrMovementPeriods maps chart periods to the date_trunc units used to bucket MRR movements
+var mrrMovementPeriods = map[string]string{
+	"daily":   "day",
+	"weekly":  "week",
+	"monthly": "month",
+}
+
+// getMRRMovementSeries returns one series per MRR movement type, plus the net new MRR, bucketed by period
+func (s *AnalyticsService) getMRRMovementSeries(ctx context.Context, workspaceID uuid.UUID, metric, period string, startDate, endDate time.Time, currency string) ([]business.ChartSeries, error) {
+	unit, ok := mrrMovementPeriods[period]
+	if !ok {
+		unit = "month"
+	}
+
+	rows, err := s.queries.GetMRRMovementsByPeriod(ctx, db.GetMRRMovementsByPeriodParams{
+		Period:            unit,
+		WorkspaceID:       workspaceID,
+		ReportingCurrency: currency,
+		StartDate:         pgtype.Timestamptz{Time: startDate, Valid: true},
+		EndDate:           pgtype.Timestamptz{Time: endDate, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	multiplier := int64(1)
+	if metric == "arr" {
+		multiplier = 12
+	}
+
+	var dates []string
+	deltas := make(map[string]map[string]int64)
+	for _, row := range rows {
+		date := row.PeriodStart.Time.Format("2006-01-02")
+		if _, ok := deltas[date]; !ok {
+			dates = append(dates, date)
+			deltas[date] = make(map[string]int64)
+		}
+		deltas[date][row.MovementType] += row.MrrDeltaCents * multiplier
+	}
+
+	names := append(append([]string{}, business.MRRMovementTypes...), "net_new")
+	series := make([]business.ChartSeries, len(names))
+	for i, name := range names {
+		series[i] = business.ChartSeries{Name: name, Data: make([]business.ChartDataPoint, len(dates))}
+		for j, date := range dates {
+			var cents int64
+			if name == "net_new" {
+				for _, delta := range deltas[date] {
+					cents += delta
+				}
+			} else {
+				cents = deltas[date][name]
+			}
+			series[i].Data[j] = business.ChartDataPoint{
+				Date:  date,
+				Value: float64(cents) / 100.0,
+				Label: helpers.FormatMoney(cents, currency),
+			}
+		}
+	}
+
+	return series, nil
+}
+
+// GetMRRMovements returns the MRR movements ledger for a date range, with totals per movement type
+// and a page of individual movements in the reporting currency
+func (s *AnalyticsService) GetMRRMovements(ctx context.Context, listParams params.ListMRRMovementsParams) (*business.MRRMovementReport, error) {
+	currency := listParams.Currency
+	if currency == "" {
+		defaultCurrency, err := s.currencyService.GetWorkspaceDefaultCurrency(ctx, listParams.WorkspaceID)
+		if err != nil {
+			// Fallback to USD if no default currency is set
+			currency = constants.USDCurrency
+		} else {
+			currency = defaultCurrency.Code
+		}
+	}
+
+	startDate := pgtype.Timestamptz{Time: listParams.StartDate, Valid: true}
+	endDate := pgtype.Timestamptz{Time: listParams.EndDate, Valid: true}
+	movementType := pgtype.Text{String: listParams.MovementType, Valid: listParams.MovementType != ""}
+
+	totals, err := s.queries.GetMRRMovementTotals(ctx, db.GetMRRMovementTotalsParams{
+		WorkspaceID:       listParams.WorkspaceID,
+		ReportingCurrency: currency,
+		StartDate:         startDate,
+		EndDate:           endDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := s.queries.ListMRRMovements(ctx, db.ListMRRMovementsParams{
+		WorkspaceID:       listParams.WorkspaceID,
+		ReportingCurrency: currency,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		MovementType:      movementType,
+		RowLimit:          listParams.Limit,
+		RowOffset:         listParams.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.queries.CountMRRMovements(ctx, db.CountMRRMovementsParams{
+		WorkspaceID:       listParams.WorkspaceID,
+		ReportingCurrency: currency,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		MovementType:      movementType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &business.MRRMovementReport{
+		StartDate:  listParams.StartDate,
+		EndDate:    listParams.EndDate,
+		Currency:   currency,
+		Totals:     make([]business.MRRMovementTotal, 0, len(totals)),
+		Movements:  make([]business.MRRMovement, 0, len(movements)),
+		TotalItems: count,
+		Limit:      listParams.Limit,
+		Offset:     listParams.Offset,
+	}
+
+	var netNew int64
+	for _, total := range totals {
+		netNew += total.MrrDeltaCents
+		report.PendingCount += total.PendingCount
+		report.Totals = append(report.Totals, business.MRRMovementTotal{
+			MovementType: total.MovementType,
+			Count:        total.MovementCount,
+			PendingCount: total.PendingCount,
+			MRRDelta:     mrrMoneyAmount(total.MrrDeltaCents, currency),
+		})
+	}
+	report.NetNewMRR = mrrMoneyAmount(netNew, currency)
+
+	for _, m := range movements {
+		report.Movements = append(report.Movements, toBusinessMRRMovement(m))
+	}
+
+	return report, nil
+}
+
 // GetGasFeePieChart returns gas fee breakdown as a pie chart
 func (s *AnalyticsService) GetGasFeePieChart(ctx context.Context, workspaceID uuid.UUID, days int, currency string) (*business.PieChartData, error) {
 	// Get workspace default currency if not provided
@@ -586,6 +744,60 @@ func (s *AnalyticsService) GetHourlyMetrics(ctx context.Context, workspaceID uui
 	}, nil
 }
 
+// GetCohortAnalysis returns logo and net revenue retention matrices for customers who started in
+// the last `months` months, grouped by signup month, product or network. The scheduler's
+// precomputed window is served from storage; other windows are calculated on demand.
+func (s *AnalyticsService) GetCohortAnalysis(ctx context.Context, workspaceID uuid.UUID, cohortBy string, months int, currency string) (*business.CohortAnalysis, error) {
+	// Get workspace default currency if not provided
+	if currency == "" {
+		defaultCurrency, err := s.currencyService.GetWorkspaceDefaultCurrency(ctx, workspaceID)
+		if err != nil {
+			// Fallback to USD if no default currency is set
+			currency = constants.USDCurrency
+		} else {
+			currency = defaultCurrency.Code
+		}
+	}
+
+	if cohortBy == "" {
+		cohortBy = business.CohortByMonth
+	}
+	if !IsValidCohortBy(cohortBy) {
+		return nil, fmt.Errorf("invalid cohort dimension: %s", cohortBy)
+	}
+	if months <= 0 {
+		months = DefaultCohortLookbackMonths
+	}
+
+	if months != DefaultCohortLookbackMonths {
+		cells, err := calculateCohortMetrics(ctx, s.queries, workspaceID, cohortBy, months, currency, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return buildCohortAnalysis(cohortBy, months, currency, cells), nil
+	}
+
+	cells, err := s.queries.ListCohortMetrics(ctx, db.ListCohortMetricsParams{
+		WorkspaceID:    workspaceID,
+		CohortBy:       cohortBy,
+		LookbackMonths: int32(months),
+		FiatCurrency:   currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Not yet precomputed, e.g. a new workspace or a currency other than the default
+	if len(cells) == 0 {
+		cells, err = refreshCohortMetrics(ctx, s.queries, workspaceID, cohortBy, months, currency, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buildCohortAnalysis(cohortBy, months, currency, cells), nil
+}
+
 // TriggerMetricsRefresh triggers async metrics recalculation
 func (s *AnalyticsService) TriggerMetricsRefresh(ctx context.Context, workspaceID uuid.UUID, date time.Time) error {
 	// Get a connection from the pool
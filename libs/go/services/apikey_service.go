@@ -6,11 +6,17 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"html"
+	"strings"
+	"time"
 
 	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -21,6 +27,10 @@ const (
 	APIKeyPrefix = "cyk"
 	// BcryptCost is the cost factor for bcrypt hashing
 	BcryptCost = 10
+	// DefaultAPIKeyRotationGracePeriod is how long a rotated key keeps working when no grace period is given
+	DefaultAPIKeyRotationGracePeriod = 24 * time.Hour
+	// MaxAPIKeyRotationGracePeriod caps how long a rotated key may overlap with its replacement
+	MaxAPIKeyRotationGracePeriod = 30 * 24 * time.Hour
 )
 
 // APIKeyService handles business logic for API key operations
@@ -174,3 +184,179 @@ func (s *APIKeyService) DeleteAPIKey(ctx context.Context, id, workspaceID uuid.U
 		WorkspaceID: workspaceID,
 	})
 }
+
+// RotateAPIKey issues a replacement for an API key. The old key keeps working until the grace
+// period ends, so clients can switch over without downtime.
+// Returns the new key and its full secret (shown once to the user).
+func (s *APIKeyService) RotateAPIKey(ctx context.Context, rotateParams params.RotateAPIKeyParams) (db.ApiKey, string, error) {
+	if rotateParams.GracePeriod < 0 || rotateParams.GracePeriod > MaxAPIKeyRotationGracePeriod {
+		return db.ApiKey{}, "", fmt.Errorf("grace period must be between 0 and %s", MaxAPIKeyRotationGracePeriod)
+	}
+
+	// Generate the replacement key
+	fullKey, keyPrefix, err := s.generateAPIKey()
+	if err != nil {
+		return db.ApiKey{}, "", err
+	}
+
+	// Hash the key for storage
+	hashedKey, err := s.hashAPIKey(fullKey)
+	if err != nil {
+		return db.ApiKey{}, "", err
+	}
+
+	// Prepare expires_at for the new key
+	var expiresAt pgtype.Timestamptz
+	if rotateParams.ExpiresAt != nil {
+		expiresAt.Time = *rotateParams.ExpiresAt
+		expiresAt.Valid = true
+	}
+
+	// Shorten the old key and create the new one atomically
+	apiKey, err := s.db.RotateAPIKey(ctx, db.RotateAPIKeyParams{
+		GraceExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(rotateParams.GracePeriod), Valid: true},
+		ID:             rotateParams.ID,
+		WorkspaceID:    rotateParams.WorkspaceID,
+		KeyHash:        hashedKey,
+		KeyPrefix:      pgtype.Text{String: keyPrefix, Valid: true},
+		ExpiresAt:      expiresAt,
+	})
+	if err != nil {
+		return db.ApiKey{}, "", err
+	}
+
+	return apiKey, fullKey, nil
+}
+
+// RecordAPIKeyUsage counts a request made with an API key in its daily usage counter and
+// updates the key's last used timestamp with a single upsert
+func (s *APIKeyService) RecordAPIKeyUsage(ctx context.Context, usageParams params.RecordAPIKeyUsageParams) error {
+	usedAt := usageParams.UsedAt
+	if usedAt.IsZero() {
+		usedAt = time.Now()
+	}
+	usedAt = usedAt.UTC()
+
+	err := s.db.RecordAPIKeyUsage(ctx, db.RecordAPIKeyUsageParams{
+		ApiKeyID:      usageParams.APIKeyID,
+		WorkspaceID:   usageParams.WorkspaceID,
+		UsageDate:     pgtype.Date{Time: time.Date(usedAt.Year(), usedAt.Month(), usedAt.Day(), 0, 0, 0, 0, time.UTC), Valid: true},
+		RequestCount:  1,
+		LastIp:        pgtype.Text{String: usageParams.ClientIP, Valid: usageParams.ClientIP != ""},
+		LastUserAgent: pgtype.Text{String: usageParams.UserAgent, Valid: usageParams.UserAgent != ""},
+		LastUsedAt:    pgtype.Timestamptz{Time: usedAt, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record API key usage: %w", err)
+	}
+
+	return nil
+}
+
+// ListAPIKeyUsage retrieves the daily usage of an API key between two dates (inclusive)
+func (s *APIKeyService) ListAPIKeyUsage(ctx context.Context, id, workspaceID uuid.UUID, startDate, endDate time.Time) ([]db.ApiKeyUsageDaily, error) {
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("end date must not be before start date")
+	}
+
+	return s.db.ListAPIKeyUsage(ctx, db.ListAPIKeyUsageParams{
+		ApiKeyID:    id,
+		WorkspaceID: workspaceID,
+		StartDate:   pgtype.Date{Time: startDate, Valid: true},
+		EndDate:     pgtype.Date{Time: endDate, Valid: true},
+	})
+}
+
+// NotifyUnusedAPIKeys emails workspace owners about keys that have not been used for the given duration.
+// Each key is notified once per idle period; using the key again re-arms the notification.
+// Returns the number of keys notified.
+func (s *APIKeyService) NotifyUnusedAPIKeys(ctx context.Context, emailService interfaces.EmailService, unusedFor time.Duration) (int, error) {
+	unusedKeys, err := s.db.ListUnusedAPIKeys(ctx, pgtype.Timestamptz{Time: time.Now().Add(-unusedFor), Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list unused API keys: %w", err)
+	}
+
+	// Group keys per workspace so each owner gets a single email
+	var workspaceOrder []uuid.UUID
+	keysByWorkspace := make(map[uuid.UUID][]db.ListUnusedAPIKeysRow)
+	for _, key := range unusedKeys {
+		if _, ok := keysByWorkspace[key.WorkspaceID]; !ok {
+			workspaceOrder = append(workspaceOrder, key.WorkspaceID)
+		}
+		keysByWorkspace[key.WorkspaceID] = append(keysByWorkspace[key.WorkspaceID], key)
+	}
+
+	notified := 0
+	days := int(unusedFor.Hours() / 24)
+	for _, workspaceID := range workspaceOrder {
+		keys := keysByWorkspace[workspaceID]
+
+		err := emailService.SendTransactionalEmail(ctx, params.TransactionalEmailParams{
+			WorkspaceID: workspaceID,
+			To:          []string{keys[0].OwnerEmail},
+			Subject:     fmt.Sprintf("%d API key(s) in %s have not been used in %d days", len(keys), keys[0].WorkspaceName, days),
+			HTMLContent: buildUnusedAPIKeysEmailHTML(keys, days),
+			TextContent: buildUnusedAPIKeysEmailText(keys, days),
+			Tags: map[string]interface{}{
+				"category": "api_key_unused",
+			},
+		})
+		if err != nil {
+			logger.Log.Warn("Failed to send unused API key notification",
+				zap.String("workspace_id", workspaceID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		for _, key := range keys {
+			if err := s.db.MarkAPIKeyUnusedNotified(ctx, key.ID); err != nil {
+				logger.Log.Warn("Failed to mark API key as notified",
+					zap.String("api_key_id", key.ID.String()),
+					zap.Error(err),
+				)
+				continue
+			}
+			notified++
+		}
+	}
+
+	return notified, nil
+}
+
+// describeUnusedAPIKey returns a one-line summary of an unused key for notification emails
+func describeUnusedAPIKey(key db.ListUnusedAPIKeysRow) string {
+	lastUsed := "never used"
+	if key.LastUsedAt.Valid {
+		lastUsed = "last used " + key.LastUsedAt.Time.Format("2006-01-02")
+	}
+
+	description := fmt.Sprintf("%s (%s) - %s", key.Name, key.KeyPrefix.String, lastUsed)
+	if key.ExpiresAt.Valid {
+		description += ", expires " + key.ExpiresAt.Time.Format("2006-01-02")
+	}
+	return description
+}
+
+// buildUnusedAPIKeysEmailHTML renders the HTML body of an unused key notification
+func buildUnusedAPIKeysEmailHTML(keys []db.ListUnusedAPIKeysRow, days int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<p>The following API keys in <strong>%s</strong> have not been used in the last %d days:</p><ul>",
+		html.EscapeString(keys[0].WorkspaceName), days)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "<li>%s</li>", html.EscapeString(describeUnusedAPIKey(key)))
+	}
+	b.WriteString("</ul><p>If these keys are no longer needed, delete them from your dashboard to reduce the risk of a leaked credential.</p>")
+	return b.String()
+}
+
+// buildUnusedAPIKeysEmailText renders the plain text body of an unused key notification
+func buildUnusedAPIKeysEmailText(keys []db.ListUnusedAPIKeysRow, days int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The following API keys in %s have not been used in the last %d days:\n\n", keys[0].WorkspaceName, days)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "- %s\n", describeUnusedAPIKey(key))
+	}
+	b.WriteString("\nIf these keys are no longer needed, delete them from your dashboard to reduce the risk of a leaked credential.\n")
+	return b.String()
+}
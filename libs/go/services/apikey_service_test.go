@@ -15,6 +15,7 @@ import (
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/crypto/bcrypt"
@@ -574,6 +575,229 @@ func TestAPIKeyService_DeleteAPIKey(t *testing.T) {
 	}
 }
 
+func TestAPIKeyService_RotateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewAPIKeyService(mockQuerier)
+	ctx := context.Background()
+
+	apiKeyID := uuid.New()
+	workspaceID := uuid.New()
+
+	tests := []struct {
+		name        string
+		params      params.RotateAPIKeyParams
+		setupMocks  func()
+		wantErr     bool
+		errorString string
+	}{
+		{
+			name: "successfully rotates API key with grace period",
+			params: params.RotateAPIKeyParams{
+				ID:          apiKeyID,
+				WorkspaceID: workspaceID,
+				GracePeriod: time.Hour,
+			},
+			setupMocks: func() {
+				mockQuerier.EXPECT().RotateAPIKey(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, arg db.RotateAPIKeyParams) (db.ApiKey, error) {
+						assert.Equal(t, apiKeyID, arg.ID)
+						assert.Equal(t, workspaceID, arg.WorkspaceID)
+						assert.WithinDuration(t, time.Now().Add(time.Hour), arg.GraceExpiresAt.Time, time.Minute)
+						assert.True(t, strings.HasPrefix(arg.KeyHash, "$2a$"))
+						assert.True(t, strings.HasPrefix(arg.KeyPrefix.String, "cyk_"))
+						assert.False(t, arg.ExpiresAt.Valid)
+						return db.ApiKey{
+							ID:            uuid.New(),
+							WorkspaceID:   workspaceID,
+							KeyHash:       arg.KeyHash,
+							KeyPrefix:     arg.KeyPrefix,
+							RotatedFromID: pgtype.UUID{Bytes: apiKeyID, Valid: true},
+						}, nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects negative grace period",
+			params: params.RotateAPIKeyParams{
+				ID:          apiKeyID,
+				WorkspaceID: workspaceID,
+				GracePeriod: -time.Minute,
+			},
+			setupMocks:  func() {},
+			wantErr:     true,
+			errorString: "grace period must be between",
+		},
+		{
+			name: "rejects grace period above maximum",
+			params: params.RotateAPIKeyParams{
+				ID:          apiKeyID,
+				WorkspaceID: workspaceID,
+				GracePeriod: services.MaxAPIKeyRotationGracePeriod + time.Hour,
+			},
+			setupMocks:  func() {},
+			wantErr:     true,
+			errorString: "grace period must be between",
+		},
+		{
+			name: "API key not found or already rotated",
+			params: params.RotateAPIKeyParams{
+				ID:          apiKeyID,
+				WorkspaceID: workspaceID,
+				GracePeriod: time.Hour,
+			},
+			setupMocks: func() {
+				mockQuerier.EXPECT().RotateAPIKey(ctx, gomock.Any()).Return(db.ApiKey{}, pgx.ErrNoRows)
+			},
+			wantErr:     true,
+			errorString: "no rows",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			apiKey, fullKey, err := service.RotateAPIKey(ctx, tt.params)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errorString != "" {
+					assert.Contains(t, err.Error(), tt.errorString)
+				}
+				assert.Empty(t, fullKey)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, strings.HasPrefix(fullKey, "cyk_"))
+				assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(apiKey.KeyHash), []byte(fullKey)))
+				assert.Equal(t, apiKeyID, uuid.UUID(apiKey.RotatedFromID.Bytes))
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_RecordAPIKeyUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewAPIKeyService(mockQuerier)
+	ctx := context.Background()
+
+	apiKeyID := uuid.New()
+	workspaceID := uuid.New()
+	usedAt := time.Date(2025, 3, 4, 23, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	t.Run("writes one request to the UTC daily counter", func(t *testing.T) {
+		mockQuerier.EXPECT().RecordAPIKeyUsage(ctx, db.RecordAPIKeyUsageParams{
+			ApiKeyID:      apiKeyID,
+			WorkspaceID:   workspaceID,
+			UsageDate:     pgtype.Date{Time: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), Valid: true},
+			RequestCount:  1,
+			LastIp:        pgtype.Text{String: "203.0.113.7", Valid: true},
+			LastUserAgent: pgtype.Text{String: "sdk/1.0", Valid: true},
+			LastUsedAt:    pgtype.Timestamptz{Time: usedAt.UTC(), Valid: true},
+		}).Return(nil)
+
+		err := service.RecordAPIKeyUsage(ctx, params.RecordAPIKeyUsageParams{
+			APIKeyID:    apiKeyID,
+			WorkspaceID: workspaceID,
+			ClientIP:    "203.0.113.7",
+			UserAgent:   "sdk/1.0",
+			UsedAt:      usedAt,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("leaves missing client details null", func(t *testing.T) {
+		mockQuerier.EXPECT().RecordAPIKeyUsage(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, p db.RecordAPIKeyUsageParams) error {
+				assert.False(t, p.LastIp.Valid)
+				assert.False(t, p.LastUserAgent.Valid)
+				assert.True(t, p.LastUsedAt.Valid)
+				assert.WithinDuration(t, time.Now(), p.LastUsedAt.Time, time.Minute)
+				return nil
+			})
+
+		err := service.RecordAPIKeyUsage(ctx, params.RecordAPIKeyUsageParams{
+			APIKeyID:    apiKeyID,
+			WorkspaceID: workspaceID,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns error when the upsert fails", func(t *testing.T) {
+		mockQuerier.EXPECT().RecordAPIKeyUsage(ctx, gomock.Any()).Return(errors.New("db error"))
+
+		err := service.RecordAPIKeyUsage(ctx, params.RecordAPIKeyUsageParams{
+			APIKeyID:    apiKeyID,
+			WorkspaceID: workspaceID,
+			UsedAt:      usedAt,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to record API key usage")
+	})
+}
+
+func TestAPIKeyService_NotifyUnusedAPIKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	mockEmail := mocks.NewMockEmailService(ctrl)
+	service := services.NewAPIKeyService(mockQuerier)
+	ctx := context.Background()
+
+	workspaceA := uuid.New()
+	workspaceB := uuid.New()
+	keyA1 := db.ListUnusedAPIKeysRow{ID: uuid.New(), WorkspaceID: workspaceA, Name: "CI", WorkspaceName: "Acme", OwnerEmail: "owner@acme.test"}
+	keyA2 := db.ListUnusedAPIKeysRow{ID: uuid.New(), WorkspaceID: workspaceA, Name: "Legacy", WorkspaceName: "Acme", OwnerEmail: "owner@acme.test"}
+	keyB1 := db.ListUnusedAPIKeysRow{ID: uuid.New(), WorkspaceID: workspaceB, Name: "Backend", WorkspaceName: "Globex", OwnerEmail: "owner@globex.test"}
+
+	t.Run("sends one email per workspace and marks keys notified", func(t *testing.T) {
+		mockQuerier.EXPECT().ListUnusedAPIKeys(ctx, gomock.Any()).Return([]db.ListUnusedAPIKeysRow{keyA1, keyA2, keyB1}, nil)
+		mockEmail.EXPECT().SendTransactionalEmail(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, p params.TransactionalEmailParams) error {
+				assert.Equal(t, []string{"owner@acme.test"}, p.To)
+				assert.Contains(t, p.HTMLContent, "CI")
+				assert.Contains(t, p.HTMLContent, "Legacy")
+				return nil
+			})
+		mockEmail.EXPECT().SendTransactionalEmail(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, p params.TransactionalEmailParams) error {
+				assert.Equal(t, []string{"owner@globex.test"}, p.To)
+				return nil
+			})
+		mockQuerier.EXPECT().MarkAPIKeyUnusedNotified(ctx, keyA1.ID).Return(nil)
+		mockQuerier.EXPECT().MarkAPIKeyUnusedNotified(ctx, keyA2.ID).Return(nil)
+		mockQuerier.EXPECT().MarkAPIKeyUnusedNotified(ctx, keyB1.ID).Return(nil)
+
+		notified, err := service.NotifyUnusedAPIKeys(ctx, mockEmail, 90*24*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, notified)
+	})
+
+	t.Run("does not mark keys when email fails", func(t *testing.T) {
+		mockQuerier.EXPECT().ListUnusedAPIKeys(ctx, gomock.Any()).Return([]db.ListUnusedAPIKeysRow{keyB1}, nil)
+		mockEmail.EXPECT().SendTransactionalEmail(ctx, gomock.Any()).Return(errors.New("send failed"))
+
+		notified, err := service.NotifyUnusedAPIKeys(ctx, mockEmail, 90*24*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, notified)
+	})
+
+	t.Run("returns error when listing fails", func(t *testing.T) {
+		mockQuerier.EXPECT().ListUnusedAPIKeys(ctx, gomock.Any()).Return(nil, errors.New("db error"))
+
+		_, err := service.NotifyUnusedAPIKeys(ctx, mockEmail, 90*24*time.Hour)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list unused API keys")
+	})
+}
+
 func TestAPIKeyService_GetAllAPIKeys(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
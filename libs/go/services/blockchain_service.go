@@ -2,15 +2,17 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 
+	"github.com/cyphera/cyphera-api/libs/go/client/solana"
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
 	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -19,26 +21,49 @@ import (
 type BlockchainService struct {
 	queries   db.Querier
 	logger    *zap.Logger
-	clients   map[uuid.UUID]*ethclient.Client // networkID -> client
-	rpcURLs   map[uuid.UUID]string            // networkID -> RPC URL
+	adapters  map[uuid.UUID]ChainAdapter // networkID -> adapter
 	rpcAPIKey string
+	rpcConfig RPCPoolConfig
+	limiters  *RPCRateLimiters
+	// solanaDelegate is the key customers approve as delegate on their SPL token accounts
+	solanaDelegate *solana.Keypair
 }
 
 // NewBlockchainService creates a new blockchain service
 func NewBlockchainService(queries db.Querier, rpcAPIKey string) *BlockchainService {
+	config := DefaultRPCPoolConfig()
 	return &BlockchainService{
 		queries:   queries,
 		logger:    logger.Log,
-		clients:   make(map[uuid.UUID]*ethclient.Client),
-		rpcURLs:   make(map[uuid.UUID]string),
+		adapters:  make(map[uuid.UUID]ChainAdapter),
 		rpcAPIKey: rpcAPIKey,
+		rpcConfig: config,
+		limiters:  NewRPCRateLimiters(config),
 	}
 }
 
+// WithSolanaDelegate returns a copy of the service that makes SPL delegate transfers with keypair.
+// The copy shares the original's RPC connections.
+func (s *BlockchainService) WithSolanaDelegate(keypair *solana.Keypair) *BlockchainService {
+	clone := *s
+	clone.solanaDelegate = keypair
+	return &clone
+}
+
+// WithRPCPoolConfig returns a copy of the service that reaches networks through config's endpoints and
+// rate limits. Call it before Initialize; the copy does not share the original's RPC connections.
+func (s *BlockchainService) WithRPCPoolConfig(config RPCPoolConfig) *BlockchainService {
+	clone := *s
+	clone.rpcConfig = config
+	clone.limiters = NewRPCRateLimiters(config)
+	clone.adapters = make(map[uuid.UUID]ChainAdapter)
+	return &clone
+}
+
 // Initialize sets up RPC connections for all networks
 func (s *BlockchainService) Initialize(ctx context.Context) error {
 	// Validate API key
-	if s.rpcAPIKey == "" {
+	if s.rpcAPIKey == "" && len(s.rpcConfig.Endpoints) == 0 {
 		return fmt.Errorf("RPC API key not provided")
 	}
 
@@ -49,19 +74,20 @@ func (s *BlockchainService) Initialize(ctx context.Context) error {
 	}
 
 	for _, network := range networks {
-		// Check if network has RPC ID for Infura
-		if network.RpcID == "" {
-			s.logger.Warn("Network missing RPC ID, skipping",
+		endpoints := s.networkEndpoints(network)
+		if len(endpoints) == 0 {
+			s.logger.Warn("Network has no RPC endpoints, skipping",
 				zap.String("network", network.Name),
 			)
 			continue
 		}
 
-		// Construct Infura RPC URL
-		// Pattern: https://<rpc_id>.infura.io/v3/<api_key>
-		rpcURL := fmt.Sprintf("https://%s.infura.io/v3/%s", network.RpcID, s.rpcAPIKey)
-
-		client, err := ethclient.Dial(rpcURL)
+		var adapter ChainAdapter
+		if network.NetworkType == db.NetworkTypeSolana {
+			adapter, err = NewSolanaAdapter(network, endpoints, s.limiters, s.rpcConfig)
+		} else {
+			adapter, err = NewEVMAdapter(network, endpoints, s.limiters, s.rpcConfig)
+		}
 		if err != nil {
 			s.logger.Error("Failed to connect to network RPC",
 				zap.String("network", network.Name),
@@ -71,133 +97,270 @@ func (s *BlockchainService) Initialize(ctx context.Context) error {
 			continue
 		}
 
-		s.clients[network.ID] = client
-		s.rpcURLs[network.ID] = rpcURL
+		s.adapters[network.ID] = adapter
 
 		s.logger.Info("Connected to network RPC",
 			zap.String("network", network.Name),
 			zap.String("network_id", network.ID.String()),
+			zap.String("network_type", string(network.NetworkType)),
 			zap.String("rpc_id", network.RpcID),
+			zap.Int("endpoints", len(adapter.EndpointStatus())),
 		)
 	}
 
-	if len(s.clients) == 0 {
+	if len(s.adapters) == 0 {
 		return fmt.Errorf("no RPC connections established")
 	}
 
 	return nil
 }
 
-// GetTransactionData fetches complete transaction data from the blockchain
-func (s *BlockchainService) GetTransactionData(ctx context.Context, txHash string, networkID uuid.UUID) (*business.TransactionData, error) {
-	client, ok := s.clients[networkID]
+// networkEndpoints returns the endpoints a network is reached through, in order of preference: Infura
+// when an RPC API key is set, then the endpoints configured for its RPC ID
+func (s *BlockchainService) networkEndpoints(network db.Network) []RPCEndpointConfig {
+	if network.RpcID == "" {
+		return nil
+	}
+
+	var endpoints []RPCEndpointConfig
+	if s.rpcAPIKey != "" {
+		// Pattern: https://<rpc_id>.infura.io/v3/<api_key>
+		endpoints = append(endpoints, RPCEndpointConfig{
+			Provider: infuraProvider,
+			URL:      fmt.Sprintf("https://%s.infura.io/v3/%s", network.RpcID, s.rpcAPIKey),
+		})
+	}
+	return append(endpoints, s.rpcConfig.Endpoints[network.RpcID]...)
+}
+
+// Adapter returns the chain adapter for a network
+func (s *BlockchainService) Adapter(networkID uuid.UUID) (ChainAdapter, bool) {
+	adapter, ok := s.adapters[networkID]
+	return adapter, ok
+}
+
+// adapter returns the chain adapter for a network, or an error when it has none
+func (s *BlockchainService) adapter(networkID uuid.UUID) (ChainAdapter, error) {
+	adapter, ok := s.adapters[networkID]
 	if !ok {
 		return nil, fmt.Errorf("no RPC client for network %s", networkID)
 	}
+	return adapter, nil
+}
 
-	// Parse transaction hash
-	hash := common.HexToHash(txHash)
+// evmAdapter returns the adapter for an EVM network
+func (s *BlockchainService) evmAdapter(networkID uuid.UUID) (*EVMAdapter, error) {
+	adapter, ok := s.adapters[networkID].(*EVMAdapter)
+	if !ok {
+		return nil, fmt.Errorf("no RPC client for network %s", networkID)
+	}
+	return adapter, nil
+}
 
-	// Get transaction
-	tx, isPending, err := client.TransactionByHash(ctx, hash)
+// GetTransactionData fetches complete transaction data from the blockchain
+func (s *BlockchainService) GetTransactionData(ctx context.Context, txHash string, networkID uuid.UUID) (*business.TransactionData, error) {
+	adapter, err := s.adapter(networkID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction: %w", err)
+		return nil, err
 	}
+	return adapter.GetTransactionData(ctx, txHash)
+}
 
-	if isPending {
-		return nil, fmt.Errorf("transaction is still pending")
+// GetTransactionDataFromEvent fetches transaction data for a subscription event
+func (s *BlockchainService) GetTransactionDataFromEvent(ctx context.Context, event *db.SubscriptionEvent) (*business.TransactionData, error) {
+	if !event.TransactionHash.Valid || event.TransactionHash.String == "" {
+		return nil, fmt.Errorf("subscription event has no transaction hash")
 	}
 
-	// Get transaction receipt for gas usage and status
-	receipt, err := client.TransactionReceipt(ctx, hash)
+	// Get the network ID from the subscription's product token
+	subscription, err := s.queries.GetSubscription(ctx, event.SubscriptionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	// Get block for timestamp and base fee
-	block, err := client.BlockByNumber(ctx, big.NewInt(int64(receipt.BlockNumber.Uint64())))
+	productToken, err := s.queries.GetProductToken(ctx, subscription.ProductTokenID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get block: %w", err)
+		return nil, fmt.Errorf("failed to get product token: %w", err)
 	}
 
-	// Get sender address
-	chainID, err := client.NetworkID(ctx)
+	return s.GetTransactionData(ctx, event.TransactionHash.String, productToken.NetworkID)
+}
+
+// GasFeeClient returns the RPC client connected to a network, for gas fee estimation
+func (s *BlockchainService) GasFeeClient(networkID uuid.UUID) (GasFeeClient, bool) {
+	adapter, err := s.evmAdapter(networkID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+		return nil, false
 	}
+	return adapter, true
+}
+
+// ErrTransactionNotFound is returned when a network does not know a transaction, or has not mined it
+var ErrTransactionNotFound = errors.New("transaction not found")
 
-	signer := types.LatestSignerForChainID(chainID)
-	from, err := types.Sender(signer, tx)
+// GetTransactionReceipt returns the block and status of a mined transaction, or ErrTransactionNotFound
+// when it is not in a block
+func (s *BlockchainService) GetTransactionReceipt(ctx context.Context, networkID uuid.UUID, txHash string) (*business.MinedTransaction, error) {
+	adapter, err := s.adapter(networkID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sender: %w", err)
+		return nil, err
 	}
+	return adapter.GetTransactionReceipt(ctx, txHash)
+}
 
-	// Build transaction data
-	txData := &business.TransactionData{
-		Hash:           txHash,
-		BlockNumber:    receipt.BlockNumber.Uint64(),
-		BlockTimestamp: block.Time(),
-		Status:         receipt.Status,
-		From:           from.Hex(),
-		Value:          tx.Value(),
-		Input:          tx.Data(),
-		GasUsed:        receipt.GasUsed,
-		GasLimit:       tx.Gas(),
-		NetworkID:      networkID,
+// GetTransaction returns the sender and nonce of a mined or pending transaction, or ErrTransactionNotFound
+// when the network has never seen it or has dropped it from the mempool. Solana transactions have no
+// nonce, so their sender is left empty.
+func (s *BlockchainService) GetTransaction(ctx context.Context, networkID uuid.UUID, txHash string) (*business.SentTransaction, error) {
+	adapter, err := s.adapter(networkID)
+	if err != nil {
+		return nil, err
 	}
+	return adapter.GetTransaction(ctx, txHash)
+}
 
-	// Handle different transaction types
-	if tx.Type() == types.LegacyTxType {
-		// Legacy transaction
-		txData.GasPrice = tx.GasPrice()
-		txData.EffectiveGasPrice = tx.GasPrice()
-	} else if tx.Type() == types.DynamicFeeTxType {
-		// EIP-1559 transaction
-		txData.MaxFeePerGas = tx.GasFeeCap()
-		txData.MaxPriorityFeePerGas = tx.GasTipCap()
-		txData.BaseFeePerGas = block.BaseFee()
-		txData.EffectiveGasPrice = receipt.EffectiveGasPrice
-		txData.GasPrice = receipt.EffectiveGasPrice // For compatibility
+// GetBlockNumber returns the number of the latest block, or the latest confirmed slot on Solana
+func (s *BlockchainService) GetBlockNumber(ctx context.Context, networkID uuid.UUID) (uint64, error) {
+	adapter, err := s.adapter(networkID)
+	if err != nil {
+		return 0, err
 	}
+	return adapter.GetBlockNumber(ctx)
+}
+
+// ErrNoAccountNonce is returned for accounts on networks that do not order transactions by nonce
+var ErrNoAccountNonce = errors.New("network has no account nonces")
 
-	// Set To address (might be nil for contract creation)
-	if tx.To() != nil {
-		txData.To = tx.To().Hex()
+// GetNonce returns the number of transactions an account has had mined as of the latest block
+func (s *BlockchainService) GetNonce(ctx context.Context, networkID uuid.UUID, account string) (uint64, error) {
+	adapter, err := s.adapter(networkID)
+	if err != nil {
+		return 0, err
+	}
+	evm, ok := adapter.(*EVMAdapter)
+	if !ok {
+		return 0, ErrNoAccountNonce
 	}
 
-	// Calculate total gas cost
-	txData.TotalGasCostWei = new(big.Int).Mul(
-		new(big.Int).SetUint64(txData.GasUsed),
-		txData.EffectiveGasPrice,
-	)
+	nonce, err := evm.NonceAt(ctx, account, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	return nonce, nil
+}
 
-	return txData, nil
+// GetNativeBalance returns an account's balance of a network's native token, in its smallest unit
+func (s *BlockchainService) GetNativeBalance(ctx context.Context, networkID uuid.UUID, account string) (*big.Int, error) {
+	adapter, err := s.adapter(networkID)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetNativeBalance(ctx, account)
 }
 
-// GetTransactionDataFromEvent fetches transaction data for a subscription event
-func (s *BlockchainService) GetTransactionDataFromEvent(ctx context.Context, event *db.SubscriptionEvent) (*business.TransactionData, error) {
-	if !event.TransactionHash.Valid || event.TransactionHash.String == "" {
-		return nil, fmt.Errorf("subscription event has no transaction hash")
+// GetTokenBalance returns an account's balance of a token on a network, in the token's smallest unit
+func (s *BlockchainService) GetTokenBalance(ctx context.Context, networkID uuid.UUID, token, account string) (*big.Int, error) {
+	adapter, err := s.adapter(networkID)
+	if err != nil {
+		return nil, err
 	}
+	return adapter.GetTokenBalance(ctx, token, account)
+}
 
-	// Get the network ID from the subscription's product token
-	subscription, err := s.queries.GetSubscription(ctx, event.SubscriptionID)
+// EstimateFee prices a transaction that uses units of a network's fee unit: gas, or signatures on Solana
+func (s *BlockchainService) EstimateFee(ctx context.Context, networkID uuid.UUID, units uint64) (*business.FeeEstimate, error) {
+	adapter, err := s.adapter(networkID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get subscription: %w", err)
+		return nil, err
 	}
+	return adapter.EstimateFee(ctx, units)
+}
 
-	productToken, err := s.queries.GetProductToken(ctx, subscription.ProductTokenID)
+// CheckRPCHealth probes every network's endpoints, taking failing ones out of rotation and returning
+// recovered ones
+func (s *BlockchainService) CheckRPCHealth(ctx context.Context) {
+	for _, adapter := range s.adapters {
+		adapter.CheckHealth(ctx)
+	}
+}
+
+// RPCEndpointStatus returns the health of a network's endpoints
+func (s *BlockchainService) RPCEndpointStatus(networkID uuid.UUID) []business.RPCEndpointStatus {
+	adapter, ok := s.adapters[networkID]
+	if !ok {
+		return nil
+	}
+	return adapter.EndpointStatus()
+}
+
+// Selectors of the DelegationManager and caveat enforcer views read to check a delegation's state
+var (
+	disabledDelegationsSelector = crypto.Keccak256([]byte("disabledDelegations(bytes32)"))[:4]
+	spentMapSelector            = crypto.Keccak256([]byte("spentMap(address,bytes32)"))[:4]
+	callCountsSelector          = crypto.Keccak256([]byte("callCounts(address,bytes32)"))[:4]
+)
+
+// IsDelegationDisabled reports whether the delegator has disabled a delegation in the DelegationManager,
+// which is how delegations are revoked on-chain
+func (s *BlockchainService) IsDelegationDisabled(ctx context.Context, networkID uuid.UUID, delegationManager common.Address, delegationHash common.Hash) (bool, error) {
+	data := append(append([]byte{}, disabledDelegationsSelector...), delegationHash.Bytes()...)
+	value, err := s.callUint256(ctx, networkID, delegationManager, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get product token: %w", err)
+		return false, fmt.Errorf("failed to check whether delegation is disabled: %w", err)
 	}
+	return value.Sign() != 0, nil
+}
 
-	return s.GetTransactionData(ctx, event.TransactionHash.String, productToken.NetworkID)
+// GetTransferredAmount returns the token amount an ERC20 transfer amount enforcer has already let a delegation transfer
+func (s *BlockchainService) GetTransferredAmount(ctx context.Context, networkID uuid.UUID, enforcer, delegationManager common.Address, delegationHash common.Hash) (*big.Int, error) {
+	value, err := s.callUint256(ctx, networkID, enforcer, enforcerUsageCall(spentMapSelector, delegationManager, delegationHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transferred amount: %w", err)
+	}
+	return value, nil
+}
+
+// GetRedemptionCount returns how many times a limited calls enforcer has already let a delegation be redeemed
+func (s *BlockchainService) GetRedemptionCount(ctx context.Context, networkID uuid.UUID, enforcer, delegationManager common.Address, delegationHash common.Hash) (*big.Int, error) {
+	value, err := s.callUint256(ctx, networkID, enforcer, enforcerUsageCall(callCountsSelector, delegationManager, delegationHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get redemption count: %w", err)
+	}
+	return value, nil
+}
+
+// enforcerUsageCall encodes a call to an enforcer's (delegationManager, delegationHash) usage mapping
+func enforcerUsageCall(selector []byte, delegationManager common.Address, delegationHash common.Hash) []byte {
+	data := append([]byte{}, selector...)
+	data = append(data, common.LeftPadBytes(delegationManager.Bytes(), 32)...)
+	return append(data, delegationHash.Bytes()...)
+}
+
+// CallContract calls a read-only contract function on an EVM network at the latest block and returns its raw result
+func (s *BlockchainService) CallContract(ctx context.Context, networkID uuid.UUID, contract common.Address, data []byte) ([]byte, error) {
+	adapter, err := s.evmAdapter(networkID)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+}
+
+// callUint256 calls a read-only contract function that returns a single uint256
+func (s *BlockchainService) callUint256(ctx context.Context, networkID uuid.UUID, contract common.Address, data []byte) (*big.Int, error) {
+	result, err := s.CallContract(ctx, networkID, contract, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != 32 {
+		return nil, fmt.Errorf("unexpected result length %d from %s", len(result), contract.Hex())
+	}
+	return new(big.Int).SetBytes(result), nil
 }
 
 // Close closes all RPC connections
 func (s *BlockchainService) Close() {
-	for networkID, client := range s.clients {
-		client.Close()
+	for networkID, adapter := range s.adapters {
+		adapter.Close()
 		s.logger.Info("Closed RPC connection",
 			zap.String("network_id", networkID.String()),
 		)
@@ -206,12 +369,6 @@ func (s *BlockchainService) Close() {
 
 // TODO: Future blockchain service capabilities
 // - GetBlockData(blockNumber) - fetch block information
-// - GetTokenBalance(address, tokenAddress) - check ERC20 balances
-// - GetCurrentGasPrice() - get current gas price estimates
-// - SubscribeToEvents(contractAddress, eventSignature) - listen for events
 // - GetContractState(contractAddress, slot) - read contract storage
-// - EstimateGas(transaction) - estimate gas for a transaction
-// - GetENSName(address) / ResolveENS(name) - ENS resolution
 // - GetTransactionsByAddress(address) - transaction history
 // - VerifyDelegation(delegationData) - verify delegation signatures
-// - GetNonce(address) - get current nonce for an address
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/cyphera/cyphera-api/libs/go/client/solana"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Errors returned for SPL delegate transfers
+var (
+	// ErrSolanaDelegateNotConfigured is returned when no payment delegate keypair was provided
+	ErrSolanaDelegateNotConfigured = errors.New("solana payment delegate not configured")
+	// ErrTokenAccountNotFound is returned when a token account, or a wallet's account for a mint, does not exist
+	ErrTokenAccountNotFound = errors.New("token account not found")
+	// ErrSplApprovalMissing is returned when the customer's token account no longer approves the payment
+	// delegate for the transfer amount
+	ErrSplApprovalMissing = errors.New("spl delegate approval missing or insufficient")
+	// ErrSplApprovalUnverified is returned when an approval transaction does not exist on-chain, failed, or
+	// is not the approval the customer described
+	ErrSplApprovalUnverified = errors.New("spl delegate approval could not be verified")
+	// ErrSplTransferRejected is returned when the cluster rejected a transfer before it was sent
+	ErrSplTransferRejected = errors.New("spl transfer rejected")
+	// ErrTransferOutcomeUnknown is returned when a transfer may or may not have reached the cluster
+	ErrTransferOutcomeUnknown = errors.New("transfer outcome unknown")
+)
+
+const (
+	solanaNativeSymbol   = "SOL"
+	solanaNativeDecimals = 9
+
+	// splAccountFrozen is the state of a token account its mint's freeze authority has frozen
+	splAccountFrozen = "frozen"
+	// solanaCommitmentProcessed is the status of a transaction that has landed in a block not yet voted on
+	solanaCommitmentProcessed = "processed"
+
+	// splApprovalMemoPrefix starts the memo that binds an approval to the product token it was made for
+	splApprovalMemoPrefix = "cyphera:"
+)
+
+// SplApprovalMemo returns the memo a customer's approval transaction must carry to subscribe with a product
+// token. Every merchant shares the payment delegate, so the memo is what ties an approval to one merchant's
+// product; an approval made for another product cannot be reused.
+func SplApprovalMemo(productTokenID uuid.UUID) string {
+	return splApprovalMemoPrefix + productTokenID.String()
+}
+
+// SolanaDelegateAddress returns the address customers approve as delegate on their token accounts,
+// or an empty string when no delegate keypair is configured
+func (s *BlockchainService) SolanaDelegateAddress() string {
+	if s.solanaDelegate == nil {
+		return ""
+	}
+	return s.solanaDelegate.PublicKey().String()
+}
+
+// GetSplTokenAccount returns an SPL token account and its delegate approval, or ErrTokenAccountNotFound
+// when the account does not exist
+func (s *BlockchainService) GetSplTokenAccount(ctx context.Context, networkID uuid.UUID, address string) (*business.SplTokenAccount, error) {
+	adapter, err := s.solanaAdapter(networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := poolCall(ctx, adapter.pool, func(client *solana.Client) (*solana.TokenAccount, error) {
+		return client.GetTokenAccount(ctx, address)
+	})
+	if errors.Is(err, solana.ErrNotFound) {
+		return nil, ErrTokenAccountNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token account: %w", err)
+	}
+
+	return &business.SplTokenAccount{
+		Address:         account.Address,
+		ProgramID:       account.ProgramID,
+		Mint:            account.Mint,
+		Owner:           account.Owner,
+		State:           account.State,
+		Amount:          account.Amount,
+		Delegate:        account.Delegate,
+		DelegatedAmount: account.DelegatedAmount,
+	}, nil
+}
+
+// VerifySplApproval checks on-chain that the owner signed a successful transaction approving the payment
+// delegate on their token account, carrying the approval's memo. Returns ErrSplApprovalUnverified when it did not.
+func (s *BlockchainService) VerifySplApproval(ctx context.Context, networkID uuid.UUID, approval business.SplApproval) (*business.VerifiedSplApproval, error) {
+	if s.solanaDelegate == nil {
+		return nil, ErrSolanaDelegateNotConfigured
+	}
+	adapter, err := s.solanaAdapter(networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := poolCall(ctx, adapter.pool, func(client *solana.Client) (*solana.ConfirmedTransaction, error) {
+		return client.GetTransaction(ctx, approval.Signature)
+	})
+	if errors.Is(err, solana.ErrNotFound) {
+		return nil, fmt.Errorf("%w: transaction %s is not confirmed", ErrSplApprovalUnverified, approval.Signature)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval transaction: %w", err)
+	}
+	return verifySplApprovalTransaction(tx, approval, s.solanaDelegate.PublicKey().String())
+}
+
+// splApproveInfo is the parsed info of an SPL Token approve or approveChecked instruction
+type splApproveInfo struct {
+	Source      string `json:"source"`
+	Mint        string `json:"mint"`
+	Delegate    string `json:"delegate"`
+	Owner       string `json:"owner"`
+	Amount      string `json:"amount"`
+	TokenAmount *struct {
+		Amount string `json:"amount"`
+	} `json:"tokenAmount"`
+}
+
+// verifySplApprovalTransaction finds the approval of delegate on the customer's token account in a
+// confirmed transaction the owner signed, alongside the approval's memo
+func verifySplApprovalTransaction(tx *solana.ConfirmedTransaction, approval business.SplApproval, delegate string) (*business.VerifiedSplApproval, error) {
+	if !tx.Succeeded() {
+		return nil, fmt.Errorf("%w: transaction %s failed", ErrSplApprovalUnverified, approval.Signature)
+	}
+	if !slices.Contains(tx.Signers, approval.Owner) {
+		return nil, fmt.Errorf("%w: transaction %s is not signed by %s", ErrSplApprovalUnverified, approval.Signature, approval.Owner)
+	}
+
+	var verified *business.VerifiedSplApproval
+	hasMemo := false
+	for _, instruction := range tx.Instructions {
+		switch instruction.Program {
+		case "spl-memo":
+			hasMemo = hasMemo || instruction.Memo == approval.Memo
+		case "spl-token", "spl-token-2022":
+			if instruction.Type != "approve" && instruction.Type != "approveChecked" {
+				continue
+			}
+			var info splApproveInfo
+			if err := json.Unmarshal(instruction.Info, &info); err != nil {
+				continue
+			}
+			if info.Source != approval.TokenAccount || info.Delegate != delegate || info.Owner != approval.Owner {
+				continue
+			}
+			amount := info.Amount
+			if info.TokenAmount != nil {
+				amount = info.TokenAmount.Amount
+			}
+			delegated, err := strconv.ParseUint(amount, 10, 64)
+			if err != nil {
+				continue
+			}
+			verified = &business.VerifiedSplApproval{Mint: info.Mint, Amount: delegated, Slot: tx.Slot}
+		}
+	}
+
+	switch {
+	case verified == nil:
+		return nil, fmt.Errorf("%w: transaction %s does not approve the payment delegate on %s", ErrSplApprovalUnverified, approval.Signature, approval.TokenAccount)
+	case !hasMemo:
+		return nil, fmt.Errorf("%w: transaction %s is missing memo %q", ErrSplApprovalUnverified, approval.Signature, approval.Memo)
+	}
+	return verified, nil
+}
+
+// SimulateSplDelegateTransfer checks that a delegate transfer would succeed and returns its fee in lamports.
+// Returns ErrSplApprovalMissing or ErrSplTransferRejected when it would not.
+func (s *BlockchainService) SimulateSplDelegateTransfer(ctx context.Context, networkID uuid.UUID, transfer business.SplDelegateTransfer) (uint64, error) {
+	adapter, err := s.solanaAdapter(networkID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.buildSplDelegateTransfer(ctx, adapter, transfer)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := poolCall(ctx, adapter.pool, func(client *solana.Client) (*solana.SimulationResult, error) {
+		return client.SimulateTransaction(ctx, tx)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to simulate transfer: %w", err)
+	}
+	if !result.Succeeded() {
+		s.logger.Warn("Simulated SPL delegate transfer failed",
+			zap.String("source", transfer.SourceTokenAccount),
+			zap.String("error", string(result.Err)),
+			zap.Strings("logs", result.Logs),
+		)
+		return 0, fmt.Errorf("%w: simulation failed: %s", ErrSplTransferRejected, result.Err)
+	}
+
+	fee, err := poolCall(ctx, adapter.pool, func(client *solana.Client) (uint64, error) {
+		return client.GetFeeForMessage(ctx, tx.Message)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transfer fee: %w", err)
+	}
+	return fee, nil
+}
+
+// TransferAsDelegate sends a delegate transfer from a customer's token account to the merchant's account
+// for the same mint, and returns the transaction signature. Returns ErrSplTransferRejected when the
+// cluster refused the transaction, and ErrTransferOutcomeUnknown when it may have been sent.
+func (s *BlockchainService) TransferAsDelegate(ctx context.Context, networkID uuid.UUID, transfer business.SplDelegateTransfer) (string, error) {
+	adapter, err := s.solanaAdapter(networkID)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := s.buildSplDelegateTransfer(ctx, adapter, transfer)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Sign(s.solanaDelegate); err != nil {
+		return "", fmt.Errorf("failed to sign transfer: %w", err)
+	}
+
+	// Sending the same signed transaction to another endpoint cannot pay twice, so sends fail over too
+	signature, err := poolCall(ctx, adapter.pool, func(client *solana.Client) (string, error) {
+		return client.SendTransaction(ctx, tx)
+	})
+	if err != nil {
+		var rpcErr *solana.RPCError
+		if errors.As(err, &rpcErr) {
+			return "", fmt.Errorf("%w: %v", ErrSplTransferRejected, rpcErr)
+		}
+		return "", fmt.Errorf("%w: signature %s: %v", ErrTransferOutcomeUnknown, tx.Signature(), err)
+	}
+
+	s.logger.Info("Sent SPL delegate transfer",
+		zap.String("network_id", networkID.String()),
+		zap.String("signature", signature),
+		zap.String("source", transfer.SourceTokenAccount),
+		zap.Uint64("amount", transfer.Amount),
+	)
+	return signature, nil
+}
+
+// buildSplDelegateTransfer checks the customer's approval and compiles an unsigned TransferChecked from
+// their token account to the merchant's, paid for by the delegate
+func (s *BlockchainService) buildSplDelegateTransfer(ctx context.Context, adapter *SolanaAdapter, transfer business.SplDelegateTransfer) (*solana.Transaction, error) {
+	if s.solanaDelegate == nil {
+		return nil, ErrSolanaDelegateNotConfigured
+	}
+	delegate := s.solanaDelegate.PublicKey()
+
+	source, err := poolCall(ctx, adapter.pool, func(client *solana.Client) (*solana.TokenAccount, error) {
+		return client.GetTokenAccount(ctx, transfer.SourceTokenAccount)
+	})
+	if errors.Is(err, solana.ErrNotFound) {
+		return nil, fmt.Errorf("%w: source %s", ErrSplApprovalMissing, transfer.SourceTokenAccount)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source token account: %w", err)
+	}
+	switch {
+	case source.Owner != transfer.SourceOwner:
+		return nil, fmt.Errorf("%w: %s is owned by %s, not %s", ErrSplApprovalMissing, source.Address, source.Owner, transfer.SourceOwner)
+	case source.Mint != transfer.Mint:
+		return nil, fmt.Errorf("%w: %s holds %s, not %s", ErrSplApprovalMissing, source.Address, source.Mint, transfer.Mint)
+	case source.Delegate != delegate.String():
+		return nil, fmt.Errorf("%w: %s does not approve the payment delegate", ErrSplApprovalMissing, source.Address)
+	case source.DelegatedAmount < transfer.Amount:
+		return nil, fmt.Errorf("%w: %s approves %d of %d", ErrSplApprovalMissing, source.Address, source.DelegatedAmount, transfer.Amount)
+	case source.State == splAccountFrozen:
+		return nil, fmt.Errorf("%w: %s is frozen", ErrSplTransferRejected, source.Address)
+	}
+
+	destinations, err := poolCall(ctx, adapter.pool, func(client *solana.Client) ([]solana.TokenAccount, error) {
+		return client.GetTokenAccountsByOwner(ctx, transfer.DestinationOwner, transfer.Mint)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchant token accounts: %w", err)
+	}
+	var destination *solana.TokenAccount
+	for i := range destinations {
+		if destinations[i].ProgramID == source.ProgramID && destinations[i].State != splAccountFrozen {
+			destination = &destinations[i]
+			break
+		}
+	}
+	if destination == nil {
+		return nil, fmt.Errorf("%w: %s has no account for %s", ErrTokenAccountNotFound, transfer.DestinationOwner, transfer.Mint)
+	}
+
+	keys, err := parsePublicKeys(source.ProgramID, source.Address, transfer.Mint, destination.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	blockhash, err := poolCall(ctx, adapter.pool, func(client *solana.Client) (*solana.LatestBlockhash, error) {
+		return client.GetLatestBlockhash(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest blockhash: %w", err)
+	}
+
+	instruction := solana.NewTransferCheckedInstruction(keys[0], keys[1], keys[2], keys[3], delegate, transfer.Amount, transfer.Decimals)
+	return solana.NewTransaction(delegate, blockhash.Blockhash, instruction)
+}
+
+// solanaAdapter returns the adapter for a Solana cluster
+func (s *BlockchainService) solanaAdapter(networkID uuid.UUID) (*SolanaAdapter, error) {
+	adapter, ok := s.adapters[networkID].(*SolanaAdapter)
+	if !ok {
+		return nil, fmt.Errorf("no Solana RPC client for network %s", networkID)
+	}
+	return adapter, nil
+}
+
+// parsePublicKeys parses base58 addresses in order
+func parsePublicKeys(addresses ...string) ([]solana.PublicKey, error) {
+	keys := make([]solana.PublicKey, len(addresses))
+	for i, address := range addresses {
+		key, err := solana.ParsePublicKey(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", address, err)
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
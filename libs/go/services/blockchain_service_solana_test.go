@@ -0,0 +1,138 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cyphera/cyphera-api/libs/go/client/solana"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testSolanaOwner        = "4GfkX2JkJmHVoSaJRMeRo97rkGjHMd6CHw9aXt2uPk5X"
+	testSolanaTokenAccount = "EWPRzu9UWUvcrmdc4DzU4E6jgYsegPz1qQttjT9HRDwt"
+	testSolanaMint         = "4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDU"
+	testApprovalSignature  = "3nYbN6o3pA9CkwVnNwR1dZsKzYQd7vTqLbMqy8zfKQ3dHh1pLgE9wH4y5rWmVx2eUcX7sPq8JtN6fRk2GzAaBmCd"
+)
+
+// approvalTransaction is the getTransaction result of an approveChecked instruction followed by a memo
+func approvalTransaction(owner, delegate, memo string, failed bool) map[string]interface{} {
+	var txErr interface{}
+	if failed {
+		txErr = map[string]interface{}{"InstructionError": []interface{}{0, map[string]int{"Custom": 4}}}
+	}
+	return map[string]interface{}{
+		"slot": 312100456,
+		"meta": map[string]interface{}{"err": txErr, "fee": 5000},
+		"transaction": map[string]interface{}{
+			"signatures": []string{testApprovalSignature},
+			"message": map[string]interface{}{
+				"accountKeys": []map[string]interface{}{
+					{"pubkey": owner, "signer": true},
+					{"pubkey": testSolanaTokenAccount, "signer": false},
+				},
+				"instructions": []map[string]interface{}{
+					{
+						"program":   "spl-token",
+						"programId": "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA",
+						"parsed": map[string]interface{}{
+							"type": "approveChecked",
+							"info": map[string]interface{}{
+								"source":      testSolanaTokenAccount,
+								"mint":        testSolanaMint,
+								"delegate":    delegate,
+								"owner":       owner,
+								"tokenAmount": map[string]interface{}{"amount": "180000000", "decimals": 6},
+							},
+						},
+					},
+					{
+						"program":   "spl-memo",
+						"programId": "MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr",
+						"parsed":    memo,
+					},
+				},
+			},
+		},
+	}
+}
+
+// newSolanaApprovalService returns a blockchain service whose Solana cluster answers getTransaction with result
+func newSolanaApprovalService(t *testing.T, delegate *solana.Keypair, result interface{}) (*services.BlockchainService, db.Network) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int    `json:"id"`
+			Method string `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "getTransaction", req.Method)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	network := db.Network{ID: uuid.New(), Name: "Solana Devnet", RpcID: "solana-devnet", NetworkType: db.NetworkTypeSolana, Active: true}
+	ctrl := gomock.NewController(t)
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	mockQuerier.EXPECT().ListActiveNetworks(gomock.Any()).Return([]db.Network{network}, nil)
+
+	config := services.DefaultRPCPoolConfig()
+	config.Endpoints = map[string][]services.RPCEndpointConfig{network.RpcID: {{Provider: "test", URL: server.URL}}}
+	service := services.NewBlockchainService(mockQuerier, "").WithRPCPoolConfig(config).WithSolanaDelegate(delegate)
+	require.NoError(t, service.Initialize(context.Background()))
+	return service, network
+}
+
+func TestBlockchainService_VerifySplApproval(t *testing.T) {
+	delegate := solana.NewKeypairFromSeed(make([]byte, 32))
+	otherDelegate := solana.NewKeypairFromSeed(append(make([]byte, 31), 1))
+	productTokenID := uuid.New()
+	memo := services.SplApprovalMemo(productTokenID)
+
+	approval := business.SplApproval{
+		Signature:    testApprovalSignature,
+		TokenAccount: testSolanaTokenAccount,
+		Owner:        testSolanaOwner,
+		Memo:         memo,
+	}
+
+	tests := []struct {
+		name    string
+		result  interface{}
+		wantErr bool
+	}{
+		{name: "approval of the payment delegate made for the product token", result: approvalTransaction(testSolanaOwner, delegate.PublicKey().String(), memo, false)},
+		{name: "approval made for another product token", result: approvalTransaction(testSolanaOwner, delegate.PublicKey().String(), services.SplApprovalMemo(uuid.New()), false), wantErr: true},
+		{name: "approval of another delegate", result: approvalTransaction(testSolanaOwner, otherDelegate.PublicKey().String(), memo, false), wantErr: true},
+		{name: "approval signed by another wallet", result: approvalTransaction("DtvyPCcVE9mQXNR4LBX9fQgrrzT9hzsF6YCDvhLMGnRG", delegate.PublicKey().String(), memo, false), wantErr: true},
+		{name: "failed approval", result: approvalTransaction(testSolanaOwner, delegate.PublicKey().String(), memo, true), wantErr: true},
+		{name: "unknown transaction", result: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, network := newSolanaApprovalService(t, delegate, tt.result)
+
+			verified, err := service.VerifySplApproval(context.Background(), network.ID, approval)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, services.ErrSplApprovalUnverified)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testSolanaMint, verified.Mint)
+			assert.Equal(t, uint64(180000000), verified.Amount)
+			assert.Equal(t, uint64(312100456), verified.Slot)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/cyphera/cyphera-api/libs/go/constants"
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/cyphera/cyphera-api/libs/go/types/business"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -34,10 +36,15 @@ type ethPriceCache struct {
 
 // NewBlockchainSyncHelper creates a new blockchain sync helper
 func NewBlockchainSyncHelper(queries db.Querier, blockchainService *BlockchainService, cmcClient *coinmarketcap.Client, cmcAPIKey string) *BlockchainSyncHelper {
+	var feeOracle *GasFeeOracle
+	if blockchainService != nil {
+		feeOracle = NewGasFeeOracle(blockchainService)
+	}
+
 	return &BlockchainSyncHelper{
 		queries:              queries,
 		blockchainService:    blockchainService,
-		paymentHelper:        NewPaymentService(queries, cmcAPIKey),
+		paymentHelper:        NewPaymentServiceWithFeeOracle(queries, cmcAPIKey, feeOracle),
 		gasSponsorshipHelper: NewGasSponsorshipHelper(queries),
 		cmcClient:            cmcClient,
 		priceCache:           make(map[string]*ethPriceCache),
@@ -81,17 +88,19 @@ func (h *BlockchainSyncHelper) SyncSubscriptionEventWithBlockchain(ctx context.C
 
 // updatePaymentWithBlockchainData updates a payment record with blockchain data
 func (h *BlockchainSyncHelper) updatePaymentWithBlockchainData(ctx context.Context, payment *db.Payment, txData *business.TransactionData) error {
-	// Get ETH price at transaction time
-	ethUsdPrice, err := h.getETHPriceUSD(ctx, txData.BlockTimestamp)
+	// Get the native token's price at transaction time
+	symbol, decimals := nativeToken(txData)
+	ethUsdPrice, err := h.getNativePriceUSD(ctx, symbol, txData.BlockTimestamp)
 	if err != nil {
 		// Fall back to a default price if we can't fetch
-		logger.Log.Warn("Failed to fetch ETH price, using default",
+		logger.Log.Warn("Failed to fetch native token price, using default",
 			zap.Error(err),
+			zap.String("symbol", symbol),
 			zap.Uint64("block_timestamp", txData.BlockTimestamp),
 		)
-		ethUsdPrice = 2000.0 // Default fallback price
+		ethUsdPrice = fallbackNativePricesUSD[symbol] // Default fallback price
 	}
-	gasCostUsdCents := h.weiToUsdCents(txData.TotalGasCostWei, ethUsdPrice)
+	gasCostUsdCents := h.weiToUsdCents(txData.TotalGasCostWei, decimals, ethUsdPrice)
 
 	// Check if transaction was successful
 	status := "completed"
@@ -117,12 +126,13 @@ func (h *BlockchainSyncHelper) createGasFeePaymentRecord(ctx context.Context, pa
 	gasFeeWei := txData.TotalGasCostWei.String()
 	gasPriceGwei := new(big.Int).Div(txData.GasPrice, big.NewInt(1e9)).String()
 
-	// Get ETH price for gas fee calculation
-	ethUsdPrice, err := h.getETHPriceUSD(ctx, txData.BlockTimestamp)
+	// Get the native token's price for gas fee calculation
+	symbol, decimals := nativeToken(txData)
+	ethUsdPrice, err := h.getNativePriceUSD(ctx, symbol, txData.BlockTimestamp)
 	if err != nil {
-		ethUsdPrice = 2000.0 // Fallback price
+		ethUsdPrice = fallbackNativePricesUSD[symbol] // Fallback price
 	}
-	gasFeeUsdCents := h.weiToUsdCents(txData.TotalGasCostWei, ethUsdPrice)
+	gasFeeUsdCents := h.weiToUsdCents(txData.TotalGasCostWei, decimals, ethUsdPrice)
 
 	// Get gas sponsorship details using the gas sponsorship helper
 	sponsorType := "customer" // Default: customer pays
@@ -135,27 +145,29 @@ func (h *BlockchainSyncHelper) createGasFeePaymentRecord(ctx context.Context, pa
 		ID:          paymentID,
 		WorkspaceID: workspaceID,
 	})
-	if err == nil && payment.SubscriptionID.Valid {
-		// Get subscription and product info for sponsorship check
-		subscription, err := h.queries.GetSubscription(ctx, payment.SubscriptionID.Bytes)
-		if err == nil {
-			// Check sponsorship using the service
-			shouldSponsor, sponsorTypeResult, err := h.gasSponsorshipHelper.QuickSponsorshipCheck(
-				ctx,
-				workspaceID,
-				payment.CustomerID,
-				subscription.ProductID,
-				gasFeeUsdCents,
-			)
-
-			if err == nil && shouldSponsor {
-				sponsorType = sponsorTypeResult
+	if err == nil {
+		// Settle the sponsorship held when the payment was created
+		sponsored, err := h.settlePaymentSponsorship(ctx, payment, txData, gasFeeUsdCents)
+		switch {
+		case err == nil:
+			if sponsored {
+				sponsorType = constants.MerchantSponsorType
 				gasSponsored = true
 				sponsorWorkspaceID = pgtype.UUID{Bytes: workspaceID, Valid: true}
-
-				// Record the sponsorship
-				_ = h.gasSponsorshipHelper.RecordSponsorship(ctx, workspaceID, paymentID, gasFeeUsdCents)
 			}
+		case errors.Is(err, ErrSponsorshipReservationNotFound):
+			// Nothing was held for this payment, so decide the sponsorship now
+			if payment.SubscriptionID.Valid && txData.Status != 0 {
+				if decision := h.checkSubscriptionSponsorship(ctx, payment, txData, gasFeeUsdCents); decision != nil && decision.ShouldSponsor {
+					sponsorType = decision.SponsorType
+					gasSponsored = true
+					sponsorWorkspaceID = pgtype.UUID{Bytes: workspaceID, Valid: true}
+				}
+			}
+		default:
+			logger.Log.Warn("Failed to settle gas sponsorship",
+				zap.String("payment_id", paymentID.String()),
+				zap.Error(err))
 		}
 	}
 
@@ -183,7 +195,7 @@ func (h *BlockchainSyncHelper) createGasFeePaymentRecord(ctx context.Context, pa
 		MaxGasUnits:        int64(txData.GasLimit),
 		BaseFeeGwei:        baseFeeGwei,
 		PriorityFeeGwei:    priorityFeeGwei,
-		PaymentMethod:      "native", // ETH was used for gas
+		PaymentMethod:      "native", // The network's native token was used for gas
 		SponsorType:        sponsorType,
 		SponsorID:          sponsorID,
 		SponsorWorkspaceID: sponsorWorkspaceID,
@@ -212,10 +224,91 @@ func (h *BlockchainSyncHelper) createGasFeePaymentRecord(ctx context.Context, pa
 	return nil
 }
 
-// getETHPriceUSD fetches the ETH/USD price, using cache if available
-func (h *BlockchainSyncHelper) getETHPriceUSD(ctx context.Context, blockTimestamp uint64) (float64, error) {
+// settlePaymentSponsorship settles the sponsorship held for a payment with the transaction's actual
+// gas cost, or releases it when the transaction failed. It reports whether any gas was sponsored.
+func (h *BlockchainSyncHelper) settlePaymentSponsorship(ctx context.Context, payment db.Payment, txData *business.TransactionData, gasFeeUsdCents int64) (bool, error) {
+	if txData.Status == 0 {
+		_, err := h.paymentHelper.gasSponsorshipService.ReleaseSponsorship(ctx, params.SponsorshipReleaseParams{
+			WorkspaceID: payment.WorkspaceID,
+			PaymentID:   payment.ID,
+			Reason:      "transaction failed",
+		})
+		return false, err
+	}
+
+	gasParams := params.GasFeeCalculationParams{
+		NetworkID:       txData.NetworkID,
+		TransactionType: "legacy",
+		GasUsed:         new(big.Int).SetUint64(txData.GasUsed),
+		GasPriceWei:     txData.GasPrice,
+		Currency:        "USD",
+	}
+	if payment.TokenID.Valid {
+		gasParams.TokenID = payment.TokenID.Bytes
+	}
+	if txData.BaseFeePerGas != nil && txData.MaxPriorityFeePerGas != nil {
+		gasParams.TransactionType = "eip1559"
+		gasParams.BaseFeeWei = txData.BaseFeePerGas
+		gasParams.MaxPriorityFeeWei = txData.MaxPriorityFeePerGas
+	}
+
+	reservation, err := h.paymentHelper.SettleGasSponsorship(ctx, payment.WorkspaceID, payment.ID, gasParams, gasFeeUsdCents)
+	if err != nil {
+		return false, err
+	}
+	return reservation.SettledCents > 0, nil
+}
+
+// checkSubscriptionSponsorship decides and records the sponsorship of a confirmed subscription
+// payment that had nothing held for it
+func (h *BlockchainSyncHelper) checkSubscriptionSponsorship(ctx context.Context, payment db.Payment, txData *business.TransactionData, gasFeeUsdCents int64) *business.SponsorshipDecision {
+	// Get subscription and product info for sponsorship check
+	subscription, err := h.queries.GetSubscription(ctx, payment.SubscriptionID.Bytes)
+	if err != nil {
+		return nil
+	}
+
+	// Check and record sponsorship using the service
+	checkParams := params.SponsorshipCheckParams{
+		WorkspaceID:     payment.WorkspaceID,
+		CustomerID:      payment.CustomerID,
+		ProductID:       subscription.ProductID,
+		GasCostUSDCents: gasFeeUsdCents,
+		TransactionType: "subscription",
+		GasPriceWei:     txData.GasPrice,
+	}
+	if txData.BlockTimestamp > 0 {
+		checkParams.At = time.Unix(int64(txData.BlockTimestamp), 0)
+	}
+	if payment.NetworkID.Valid {
+		checkParams.NetworkID = payment.NetworkID.Bytes
+	}
+	if payment.TokenID.Valid {
+		checkParams.TokenID = payment.TokenID.Bytes
+	}
+
+	decision, _ := h.gasSponsorshipHelper.CheckAndRecordSponsorship(ctx, checkParams, payment.ID)
+	return decision
+}
+
+// fallbackNativePricesUSD are the prices used when a native token's price cannot be fetched
+var fallbackNativePricesUSD = map[string]float64{
+	"ETH": 2000.0,
+	"SOL": 150.0,
+}
+
+// nativeToken returns the symbol and decimals of the token a transaction's fee was paid in
+func nativeToken(txData *business.TransactionData) (string, int) {
+	if txData.NativeSymbol == "" {
+		return "ETH", 18
+	}
+	return txData.NativeSymbol, txData.NativeDecimals
+}
+
+// getNativePriceUSD fetches a native token's USD price, using cache if available
+func (h *BlockchainSyncHelper) getNativePriceUSD(ctx context.Context, symbol string, blockTimestamp uint64) (float64, error) {
 	// Check cache first (cache for 5 minutes)
-	cacheKey := "ETH"
+	cacheKey := symbol
 	if cached, ok := h.priceCache[cacheKey]; ok {
 		if time.Since(cached.fetchedAt) < 5*time.Minute {
 			return cached.price, nil
@@ -227,20 +320,20 @@ func (h *BlockchainSyncHelper) getETHPriceUSD(ctx context.Context, blockTimestam
 		return 0, fmt.Errorf("CoinMarketCap client not configured")
 	}
 
-	response, err := h.cmcClient.GetLatestQuotes([]string{"ETH"}, []string{"USD"})
+	response, err := h.cmcClient.GetLatestQuotes([]string{symbol}, []string{"USD"})
 	if err != nil {
-		return 0, fmt.Errorf("failed to fetch ETH price: %w", err)
+		return 0, fmt.Errorf("failed to fetch %s price: %w", symbol, err)
 	}
 
-	// Extract ETH price from response
-	ethData, ok := response.Data["ETH"]
-	if !ok || len(ethData) == 0 {
-		return 0, fmt.Errorf("ETH price data not found in response")
+	// Extract the price from response
+	tokenData, ok := response.Data[symbol]
+	if !ok || len(tokenData) == 0 {
+		return 0, fmt.Errorf("%s price data not found in response", symbol)
 	}
 
-	usdQuote, ok := ethData[0].Quote["USD"]
+	usdQuote, ok := tokenData[0].Quote["USD"]
 	if !ok {
-		return 0, fmt.Errorf("USD quote not found for ETH")
+		return 0, fmt.Errorf("USD quote not found for %s", symbol)
 	}
 
 	// Cache the price
@@ -265,11 +358,11 @@ func (h *BlockchainSyncHelper) floatToNumeric(f float64) pgtype.Numeric {
 	return num
 }
 
-// weiToUsdCents converts Wei to USD cents given an ETH/USD price
-func (h *BlockchainSyncHelper) weiToUsdCents(weiAmount *big.Int, ethUsdPrice float64) int64 {
-	// Convert Wei to ETH (1 ETH = 10^18 Wei)
+// weiToUsdCents converts a fee in the native token's smallest unit to USD cents given its USD price
+func (h *BlockchainSyncHelper) weiToUsdCents(weiAmount *big.Int, decimals int, ethUsdPrice float64) int64 {
+	// Convert to whole tokens (1 ETH = 10^18 Wei, 1 SOL = 10^9 lamports)
 	ethAmount := new(big.Float).SetInt(weiAmount)
-	ethAmount.Quo(ethAmount, big.NewFloat(1e18))
+	ethAmount.Quo(ethAmount, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
 
 	// Convert ETH to USD
 	usdAmount := new(big.Float).Mul(ethAmount, big.NewFloat(ethUsdPrice))
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+)
+
+// ErrLogSubscriptionUnsupported is returned by adapters for networks without contract logs to stream
+var ErrLogSubscriptionUnsupported = errors.New("log subscriptions are not supported on this network")
+
+// ChainAdapter reads a network through its RPC endpoints, failing over between them. Each network type
+// has its own implementation; BlockchainService keeps one per active network.
+type ChainAdapter interface {
+	// NetworkID returns the network the adapter reads
+	NetworkID() uuid.UUID
+	// GetTransactionData fetches a mined transaction's value, fee and outcome
+	GetTransactionData(ctx context.Context, txHash string) (*business.TransactionData, error)
+	// GetTransactionReceipt returns the block and status of a mined transaction, or ErrTransactionNotFound
+	GetTransactionReceipt(ctx context.Context, txHash string) (*business.MinedTransaction, error)
+	// GetTransaction returns a mined or pending transaction, or ErrTransactionNotFound
+	GetTransaction(ctx context.Context, txHash string) (*business.SentTransaction, error)
+	// GetBlockNumber returns the latest block, or slot
+	GetBlockNumber(ctx context.Context) (uint64, error)
+	// GetNativeBalance returns an account's balance of the network's native token, in its smallest unit
+	GetNativeBalance(ctx context.Context, account string) (*big.Int, error)
+	// GetTokenBalance returns an account's balance of a token, in the token's smallest unit
+	GetTokenBalance(ctx context.Context, token, account string) (*big.Int, error)
+	// EstimateFee prices a transaction that uses units of the network's fee unit
+	EstimateFee(ctx context.Context, units uint64) (*business.FeeEstimate, error)
+	// SubscribeLogs streams matching contract logs to logs until the subscription is cancelled or fails
+	SubscribeLogs(ctx context.Context, filter business.LogFilter, logs chan<- business.ChainLog) (ChainSubscription, error)
+	// CheckHealth probes every endpoint, taking failing ones out of rotation
+	CheckHealth(ctx context.Context)
+	// EndpointStatus returns the health of each endpoint, in order of preference
+	EndpointStatus() []business.RPCEndpointStatus
+	// Close closes the adapter's RPC connections
+	Close()
+}
+
+// ChainSubscription is a running log subscription
+type ChainSubscription interface {
+	// Unsubscribe stops the subscription and closes its error channel
+	Unsubscribe()
+	// Err receives the error that ended the subscription, if any
+	Err() <-chan error
+}
+
+// pollingSubscription is a log subscription that polls for new logs in the background
+type pollingSubscription struct {
+	cancel context.CancelFunc
+	errc   chan error
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newPollingSubscription(cancel context.CancelFunc) *pollingSubscription {
+	return &pollingSubscription{
+		cancel: cancel,
+		errc:   make(chan error, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// Unsubscribe stops polling and waits for the poller to exit
+func (s *pollingSubscription) Unsubscribe() {
+	s.once.Do(s.cancel)
+	<-s.done
+}
+
+// Err receives the error that stopped polling; it is closed once the poller exits
+func (s *pollingSubscription) Err() <-chan error {
+	return s.errc
+}
+
+// finish records why polling stopped; a nil error means it was cancelled
+func (s *pollingSubscription) finish(err error) {
+	if err != nil {
+		s.errc <- err
+	}
+	close(s.errc)
+	close(s.done)
+}
+
+// blockPollInterval returns how often to poll a network for new blocks
+func blockPollInterval(network db.Network) time.Duration {
+	if network.AverageBlockTimeMs.Valid && network.AverageBlockTimeMs.Int32 > 0 {
+		return time.Duration(network.AverageBlockTimeMs.Int32) * time.Millisecond
+	}
+	return 2 * time.Second
+}
@@ -0,0 +1,462 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	evmNativeSymbol   = "ETH"
+	evmNativeDecimals = 18
+
+	// evmLimitExceededCode is the JSON-RPC error providers return when a request is over a usage limit
+	evmLimitExceededCode = -32005
+	// maxLogBlockRange caps the blocks one eth_getLogs request spans, which most providers limit
+	maxLogBlockRange = 1000
+)
+
+var balanceOfSelector = crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+
+// EVMAdapter reads an EVM network through one or more JSON-RPC endpoints. It also serves as the network's
+// GasFeeClient, so gas estimation fails over between endpoints too.
+type EVMAdapter struct {
+	networkID    uuid.UUID
+	pool         *rpcPool[*ethclient.Client]
+	pollInterval time.Duration
+	logger       *zap.Logger
+}
+
+// NewEVMAdapter connects to an EVM network's endpoints. Endpoints are tried in order; limiters are shared
+// with the adapters of other networks on the same providers.
+func NewEVMAdapter(network db.Network, endpoints []RPCEndpointConfig, limiters *RPCRateLimiters, config RPCPoolConfig) (*EVMAdapter, error) {
+	log := logger.Log
+	if log == nil {
+		log = zap.NewNop()
+	}
+
+	pool, err := dialRPCPool(network.Name, endpoints, ethclient.Dial, evmShouldFailover, limiters, config, log)
+	if err != nil {
+		return nil, err
+	}
+	return &EVMAdapter{
+		networkID:    network.ID,
+		pool:         pool,
+		pollInterval: blockPollInterval(network),
+		logger:       log,
+	}, nil
+}
+
+// evmShouldFailover reports whether an error came from the endpoint rather than the node's answer. Not
+// found, reverts and invalid requests would get the same answer elsewhere.
+func evmShouldFailover(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == evmLimitExceededCode
+	}
+	// Transport errors: the endpoint could not be reached
+	return true
+}
+
+// NetworkID returns the network the adapter reads
+func (a *EVMAdapter) NetworkID() uuid.UUID {
+	return a.networkID
+}
+
+// GetTransactionData fetches complete transaction data from the blockchain
+func (a *EVMAdapter) GetTransactionData(ctx context.Context, txHash string) (*business.TransactionData, error) {
+	hash := common.HexToHash(txHash)
+
+	// Get transaction
+	var tx *types.Transaction
+	var isPending bool
+	err := a.pool.do(ctx, func(client *ethclient.Client) error {
+		var err error
+		tx, isPending, err = client.TransactionByHash(ctx, hash)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if isPending {
+		return nil, fmt.Errorf("transaction is still pending")
+	}
+
+	// Get transaction receipt for gas usage and status
+	receipt, err := poolCall(ctx, a.pool, func(client *ethclient.Client) (*types.Receipt, error) {
+		return client.TransactionReceipt(ctx, hash)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+	}
+
+	// Get block for timestamp and base fee
+	block, err := poolCall(ctx, a.pool, func(client *ethclient.Client) (*types.Block, error) {
+		return client.BlockByNumber(ctx, receipt.BlockNumber)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block: %w", err)
+	}
+
+	// Get sender address
+	chainID, err := poolCall(ctx, a.pool, func(client *ethclient.Client) (*big.Int, error) {
+		return client.NetworkID(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sender: %w", err)
+	}
+
+	// Build transaction data
+	txData := &business.TransactionData{
+		Hash:           txHash,
+		BlockNumber:    receipt.BlockNumber.Uint64(),
+		BlockTimestamp: block.Time(),
+		Status:         receipt.Status,
+		From:           from.Hex(),
+		Value:          tx.Value(),
+		Input:          tx.Data(),
+		GasUsed:        receipt.GasUsed,
+		GasLimit:       tx.Gas(),
+		NetworkID:      a.networkID,
+	}
+
+	// Handle different transaction types
+	if tx.Type() == types.LegacyTxType {
+		// Legacy transaction
+		txData.GasPrice = tx.GasPrice()
+		txData.EffectiveGasPrice = tx.GasPrice()
+	} else if tx.Type() == types.DynamicFeeTxType {
+		// EIP-1559 transaction
+		txData.MaxFeePerGas = tx.GasFeeCap()
+		txData.MaxPriorityFeePerGas = tx.GasTipCap()
+		txData.BaseFeePerGas = block.BaseFee()
+		txData.EffectiveGasPrice = receipt.EffectiveGasPrice
+		txData.GasPrice = receipt.EffectiveGasPrice // For compatibility
+	}
+
+	// Set To address (might be nil for contract creation)
+	if tx.To() != nil {
+		txData.To = tx.To().Hex()
+	}
+
+	// Calculate total gas cost
+	txData.TotalGasCostWei = new(big.Int).Mul(
+		new(big.Int).SetUint64(txData.GasUsed),
+		txData.EffectiveGasPrice,
+	)
+
+	return txData, nil
+}
+
+// GetTransactionReceipt returns the block and status of a mined transaction, or ErrTransactionNotFound
+// when it is not in a block
+func (a *EVMAdapter) GetTransactionReceipt(ctx context.Context, txHash string) (*business.MinedTransaction, error) {
+	receipt, err := poolCall(ctx, a.pool, func(client *ethclient.Client) (*types.Receipt, error) {
+		return client.TransactionReceipt(ctx, common.HexToHash(txHash))
+	})
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+	}
+
+	return &business.MinedTransaction{
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		BlockHash:   receipt.BlockHash.Hex(),
+		Status:      receipt.Status,
+	}, nil
+}
+
+// GetTransaction returns the sender and nonce of a mined or pending transaction, or ErrTransactionNotFound
+// when the network has never seen it or has dropped it from the mempool
+func (a *EVMAdapter) GetTransaction(ctx context.Context, txHash string) (*business.SentTransaction, error) {
+	var tx *types.Transaction
+	var isPending bool
+	err := a.pool.do(ctx, func(client *ethclient.Client) error {
+		var err error
+		tx, isPending, err = client.TransactionByHash(ctx, common.HexToHash(txHash))
+		return err
+	})
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sender: %w", err)
+	}
+
+	return &business.SentTransaction{
+		From:    from.Hex(),
+		Nonce:   tx.Nonce(),
+		Pending: isPending,
+	}, nil
+}
+
+// GetBlockNumber returns the number of the latest block
+func (a *EVMAdapter) GetBlockNumber(ctx context.Context) (uint64, error) {
+	number, err := poolCall(ctx, a.pool, func(client *ethclient.Client) (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block number: %w", err)
+	}
+	return number, nil
+}
+
+// NonceAt returns the number of transactions an account has had mined as of a block, or the latest
+// block when blockNumber is nil
+func (a *EVMAdapter) NonceAt(ctx context.Context, account string, blockNumber *big.Int) (uint64, error) {
+	return poolCall(ctx, a.pool, func(client *ethclient.Client) (uint64, error) {
+		return client.NonceAt(ctx, common.HexToAddress(account), blockNumber)
+	})
+}
+
+// GetNativeBalance returns an account's balance in wei
+func (a *EVMAdapter) GetNativeBalance(ctx context.Context, account string) (*big.Int, error) {
+	if !common.IsHexAddress(account) {
+		return nil, fmt.Errorf("invalid account address %q", account)
+	}
+
+	balance, err := poolCall(ctx, a.pool, func(client *ethclient.Client) (*big.Int, error) {
+		return client.BalanceAt(ctx, common.HexToAddress(account), nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	return balance, nil
+}
+
+// GetTokenBalance returns an account's balance of an ERC20 token
+func (a *EVMAdapter) GetTokenBalance(ctx context.Context, token, account string) (*big.Int, error) {
+	if !common.IsHexAddress(token) {
+		return nil, fmt.Errorf("invalid token address %q", token)
+	}
+	if !common.IsHexAddress(account) {
+		return nil, fmt.Errorf("invalid account address %q", account)
+	}
+
+	contract := common.HexToAddress(token)
+	data := append(append([]byte{}, balanceOfSelector...), common.LeftPadBytes(common.HexToAddress(account).Bytes(), 32)...)
+	result, err := a.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token balance: %w", err)
+	}
+	if len(result) != 32 {
+		return nil, fmt.Errorf("unexpected result length %d from %s", len(result), contract.Hex())
+	}
+	return new(big.Int).SetBytes(result), nil
+}
+
+// EstimateFee prices a transaction using units of gas at the suggested gas price
+func (a *EVMAdapter) EstimateFee(ctx context.Context, units uint64) (*business.FeeEstimate, error) {
+	gasPrice, err := a.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	return &business.FeeEstimate{
+		Units:          units,
+		PricePerUnit:   gasPrice,
+		Total:          new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(units)),
+		NativeSymbol:   evmNativeSymbol,
+		NativeDecimals: evmNativeDecimals,
+	}, nil
+}
+
+// SubscribeLogs polls for matching logs once per block time and sends them to logs in block order.
+// Polling rather than a websocket subscription lets it fail over between plain HTTP endpoints.
+func (a *EVMAdapter) SubscribeLogs(ctx context.Context, filter business.LogFilter, logs chan<- business.ChainLog) (ChainSubscription, error) {
+	query, err := evmFilterQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	next := filter.FromBlock
+	if next == 0 {
+		if next, err = a.GetBlockNumber(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	sub := newPollingSubscription(cancel)
+	go a.pollLogs(pollCtx, query, next, logs, sub)
+	return sub, nil
+}
+
+// pollLogs reads logs from block next onwards until the context is cancelled or reading fails
+func (a *EVMAdapter) pollLogs(ctx context.Context, query ethereum.FilterQuery, next uint64, logs chan<- business.ChainLog, sub *pollingSubscription) {
+	ticker := time.NewTicker(a.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		head, err := a.GetBlockNumber(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				sub.finish(nil)
+			} else {
+				sub.finish(err)
+			}
+			return
+		}
+
+		for next <= head {
+			to := min(head, next+maxLogBlockRange-1)
+			rangeQuery := query
+			rangeQuery.FromBlock = new(big.Int).SetUint64(next)
+			rangeQuery.ToBlock = new(big.Int).SetUint64(to)
+
+			found, err := poolCall(ctx, a.pool, func(client *ethclient.Client) ([]types.Log, error) {
+				return client.FilterLogs(ctx, rangeQuery)
+			})
+			if err != nil {
+				if ctx.Err() != nil {
+					sub.finish(nil)
+				} else {
+					sub.finish(fmt.Errorf("failed to get logs for blocks %d-%d: %w", next, to, err))
+				}
+				return
+			}
+
+			for _, log := range found {
+				select {
+				case logs <- chainLogFromEVM(log):
+				case <-ctx.Done():
+					sub.finish(nil)
+					return
+				}
+			}
+			next = to + 1
+		}
+
+		select {
+		case <-ctx.Done():
+			sub.finish(nil)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth probes every endpoint for the latest block number
+func (a *EVMAdapter) CheckHealth(ctx context.Context) {
+	a.pool.checkHealth(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		_, err := client.BlockNumber(ctx)
+		return err
+	})
+}
+
+// EndpointStatus returns the health of each endpoint, in order of preference
+func (a *EVMAdapter) EndpointStatus() []business.RPCEndpointStatus {
+	return a.pool.status()
+}
+
+// Close closes the adapter's RPC connections
+func (a *EVMAdapter) Close() {
+	a.pool.close(func(client *ethclient.Client) { client.Close() })
+}
+
+// FeeHistory returns recent base fees and priority fee percentiles
+func (a *EVMAdapter) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return poolCall(ctx, a.pool, func(client *ethclient.Client) (*ethereum.FeeHistory, error) {
+		return client.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+// SuggestGasPrice returns the node's suggested legacy gas price
+func (a *EVMAdapter) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, a.pool, func(client *ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasPrice(ctx)
+	})
+}
+
+// EstimateGas estimates the gas a call would use
+func (a *EVMAdapter) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return poolCall(ctx, a.pool, func(client *ethclient.Client) (uint64, error) {
+		return client.EstimateGas(ctx, call)
+	})
+}
+
+// CallContract runs a read-only contract call
+func (a *EVMAdapter) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, a.pool, func(client *ethclient.Client) ([]byte, error) {
+		return client.CallContract(ctx, call, blockNumber)
+	})
+}
+
+// evmFilterQuery converts a log filter to a go-ethereum query, checking its addresses and topics
+func evmFilterQuery(filter business.LogFilter) (ethereum.FilterQuery, error) {
+	var query ethereum.FilterQuery
+	for _, address := range filter.Addresses {
+		if !common.IsHexAddress(address) {
+			return query, fmt.Errorf("invalid contract address %q", address)
+		}
+		query.Addresses = append(query.Addresses, common.HexToAddress(address))
+	}
+
+	for _, position := range filter.Topics {
+		var topics []common.Hash
+		for _, topic := range position {
+			bytes, err := hexutil.Decode(topic)
+			if err != nil || len(bytes) != common.HashLength {
+				return query, fmt.Errorf("invalid topic %q", topic)
+			}
+			topics = append(topics, common.BytesToHash(bytes))
+		}
+		query.Topics = append(query.Topics, topics)
+	}
+	return query, nil
+}
+
+// chainLogFromEVM converts a go-ethereum log
+func chainLogFromEVM(log types.Log) business.ChainLog {
+	topics := make([]string, len(log.Topics))
+	for i, topic := range log.Topics {
+		topics[i] = topic.Hex()
+	}
+	return business.ChainLog{
+		Address:         log.Address.Hex(),
+		Topics:          topics,
+		Data:            log.Data,
+		BlockNumber:     log.BlockNumber,
+		BlockHash:       log.BlockHash.Hex(),
+		TransactionHash: log.TxHash.Hex(),
+		LogIndex:        log.Index,
+	}
+}
@@ -0,0 +1,243 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonRPCNode is a fake JSON-RPC endpoint that answers each method with a fixed result or error
+type jsonRPCNode struct {
+	status  atomic.Int32
+	results map[string]interface{}
+	errors  map[string]map[string]interface{}
+	calls   atomic.Int32
+}
+
+func (n *jsonRPCNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.calls.Add(1)
+	if status := n.status.Load(); status != 0 {
+		w.WriteHeader(int(status))
+		return
+	}
+
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if rpcErr, ok := n.errors[req.Method]; ok {
+		resp["error"] = rpcErr
+	} else {
+		resp["result"] = n.results[req.Method]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// newFailingNode returns a node that answers every request with an HTTP error status
+func newFailingNode(status int) *jsonRPCNode {
+	node := &jsonRPCNode{}
+	node.status.Store(int32(status))
+	return node
+}
+
+func newTestEVMAdapter(t *testing.T, config services.RPCPoolConfig, nodes ...*jsonRPCNode) *services.EVMAdapter {
+	t.Helper()
+
+	var endpoints []services.RPCEndpointConfig
+	for i, node := range nodes {
+		server := httptest.NewServer(node)
+		t.Cleanup(server.Close)
+		endpoints = append(endpoints, services.RPCEndpointConfig{Provider: []string{"primary", "fallback", "spare"}[i], URL: server.URL})
+	}
+
+	network := db.Network{
+		ID:                 uuid.New(),
+		Name:               "Test Network",
+		AverageBlockTimeMs: pgtype.Int4{Int32: 10, Valid: true},
+	}
+	adapter, err := services.NewEVMAdapter(network, endpoints, services.NewRPCRateLimiters(config), config)
+	require.NoError(t, err)
+	t.Cleanup(adapter.Close)
+	return adapter
+}
+
+func TestEVMAdapter_Failover(t *testing.T) {
+	config := services.DefaultRPCPoolConfig()
+	config.FailureThreshold = 2
+	config.Cooldown = time.Hour
+
+	t.Run("fails over from an unavailable endpoint and takes it out of rotation", func(t *testing.T) {
+		down := newFailingNode(http.StatusServiceUnavailable)
+		up := &jsonRPCNode{results: map[string]interface{}{"eth_blockNumber": "0x10"}}
+		adapter := newTestEVMAdapter(t, config, down, up)
+
+		for i := 0; i < 4; i++ {
+			number, err := adapter.GetBlockNumber(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, uint64(16), number)
+		}
+
+		assert.Equal(t, int32(2), down.calls.Load(), "endpoint should be skipped once it reaches the failure threshold")
+		assert.Equal(t, int32(4), up.calls.Load())
+
+		status := adapter.EndpointStatus()
+		require.Len(t, status, 2)
+		assert.Equal(t, "primary", status[0].Provider)
+		assert.False(t, status[0].Healthy)
+		assert.Equal(t, 2, status[0].ConsecutiveFailures)
+		assert.NotContains(t, status[0].LastError, "http://", "endpoint URLs must not leak into status")
+		assert.True(t, status[1].Healthy)
+	})
+
+	t.Run("does not fail over when the node rejects the request", func(t *testing.T) {
+		reverting := &jsonRPCNode{errors: map[string]map[string]interface{}{
+			"eth_call": {"code": -32000, "message": "execution reverted"},
+		}}
+		spare := &jsonRPCNode{results: map[string]interface{}{"eth_call": "0x"}}
+		adapter := newTestEVMAdapter(t, config, reverting, spare)
+
+		_, err := adapter.GetTokenBalance(context.Background(), "0x1c7D4B196Cb0C7B01d743Fbc6116a902379C7238", "0x742d35Cc6634C0532925a3b844Bc454e4438f44e")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "execution reverted")
+		assert.Equal(t, int32(0), spare.calls.Load())
+		assert.True(t, adapter.EndpointStatus()[0].Healthy)
+	})
+
+	t.Run("health checks return recovered endpoints to rotation", func(t *testing.T) {
+		flaky := newFailingNode(http.StatusBadGateway)
+		flaky.results = map[string]interface{}{"eth_blockNumber": "0x1"}
+		backup := &jsonRPCNode{results: map[string]interface{}{"eth_blockNumber": "0x1"}}
+		adapter := newTestEVMAdapter(t, config, flaky, backup)
+
+		adapter.CheckHealth(context.Background())
+		adapter.CheckHealth(context.Background())
+		assert.False(t, adapter.EndpointStatus()[0].Healthy)
+
+		flaky.status.Store(0)
+		adapter.CheckHealth(context.Background())
+		assert.True(t, adapter.EndpointStatus()[0].Healthy)
+		assert.Equal(t, 0, adapter.EndpointStatus()[0].ConsecutiveFailures)
+	})
+
+	t.Run("fails when every endpoint is unavailable", func(t *testing.T) {
+		adapter := newTestEVMAdapter(t, config,
+			newFailingNode(http.StatusTooManyRequests),
+			newFailingNode(http.StatusInternalServerError),
+		)
+
+		_, err := adapter.GetBlockNumber(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "all RPC endpoints for Test Network failed")
+	})
+}
+
+func TestEVMAdapter_GetTokenBalance(t *testing.T) {
+	node := &jsonRPCNode{results: map[string]interface{}{
+		"eth_call": "0x00000000000000000000000000000000000000000000000000000000000f4240",
+	}}
+	adapter := newTestEVMAdapter(t, services.DefaultRPCPoolConfig(), node)
+
+	balance, err := adapter.GetTokenBalance(context.Background(), "0x1c7D4B196Cb0C7B01d743Fbc6116a902379C7238", "0x742d35Cc6634C0532925a3b844Bc454e4438f44e")
+	require.NoError(t, err)
+	assert.Equal(t, "1000000", balance.String())
+
+	_, err = adapter.GetTokenBalance(context.Background(), "not-an-address", "0x742d35Cc6634C0532925a3b844Bc454e4438f44e")
+	assert.Error(t, err)
+}
+
+func TestEVMAdapter_SubscribeLogs(t *testing.T) {
+	transferTopic := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	node := &jsonRPCNode{results: map[string]interface{}{
+		"eth_blockNumber": "0x5",
+		"eth_getLogs": []map[string]interface{}{{
+			"address":          "0x1c7d4b196cb0c7b01d743fbc6116a902379c7238",
+			"topics":           []string{transferTopic},
+			"data":             "0x01",
+			"blockNumber":      "0x5",
+			"blockHash":        "0x" + "ab" + "00000000000000000000000000000000000000000000000000000000000000",
+			"transactionHash":  "0x" + "cd" + "00000000000000000000000000000000000000000000000000000000000000",
+			"transactionIndex": "0x0",
+			"logIndex":         "0x2",
+			"removed":          false,
+		}},
+	}}
+	adapter := newTestEVMAdapter(t, services.DefaultRPCPoolConfig(), node)
+
+	logs := make(chan business.ChainLog, 1)
+	sub, err := adapter.SubscribeLogs(context.Background(), business.LogFilter{
+		Addresses: []string{"0x1c7D4B196Cb0C7B01d743Fbc6116a902379C7238"},
+		Topics:    [][]string{{transferTopic}},
+		FromBlock: 5,
+	}, logs)
+	require.NoError(t, err)
+
+	select {
+	case log := <-logs:
+		assert.Equal(t, "0x1c7D4B196Cb0C7B01d743Fbc6116a902379C7238", log.Address)
+		assert.Equal(t, uint64(5), log.BlockNumber)
+		assert.Equal(t, uint(2), log.LogIndex)
+		assert.Equal(t, []string{transferTopic}, log.Topics)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no log received")
+	}
+
+	sub.Unsubscribe()
+	_, open := <-sub.Err()
+	assert.False(t, open, "error channel should close on unsubscribe")
+
+	_, err = adapter.SubscribeLogs(context.Background(), business.LogFilter{Topics: [][]string{{"0x1234"}}, FromBlock: 1}, logs)
+	assert.Error(t, err)
+}
+
+func TestParseRPCEndpoints(t *testing.T) {
+	endpoints, err := services.ParseRPCEndpoints("base-mainnet=alchemy:https://base.example/v2/key, base-mainnet=public:https://mainnet.base.org,polygon-mainnet=quicknode:https://polygon.example")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]services.RPCEndpointConfig{
+		"base-mainnet": {
+			{Provider: "alchemy", URL: "https://base.example/v2/key"},
+			{Provider: "public", URL: "https://mainnet.base.org"},
+		},
+		"polygon-mainnet": {
+			{Provider: "quicknode", URL: "https://polygon.example"},
+		},
+	}, endpoints)
+
+	empty, err := services.ParseRPCEndpoints("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	for _, invalid := range []string{"https://base.example", "base-mainnet=https://base.example", "base-mainnet=alchemy:ftp://base.example", "=alchemy:https://base.example"} {
+		_, err := services.ParseRPCEndpoints(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseRPCRateLimits(t *testing.T) {
+	limits, err := services.ParseRPCRateLimits("infura=10, alchemy=2.5")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"infura": 10, "alchemy": 2.5}, limits)
+
+	for _, invalid := range []string{"infura", "infura=fast", "infura=0", "=10"} {
+		_, err := services.ParseRPCRateLimits(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
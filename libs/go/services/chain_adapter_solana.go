@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	httpClient "github.com/cyphera/cyphera-api/libs/go/client/http"
+	"github.com/cyphera/cyphera-api/libs/go/client/solana"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// solanaLamportsPerSignature is the base fee of each signature on a transaction
+	solanaLamportsPerSignature = 5000
+	// solanaNodeUnhealthyCode is the JSON-RPC error a node that has fallen behind the cluster returns
+	solanaNodeUnhealthyCode = -32005
+)
+
+// SolanaAdapter reads a Solana cluster through one or more JSON-RPC endpoints
+type SolanaAdapter struct {
+	networkID uuid.UUID
+	pool      *rpcPool[*solana.Client]
+	logger    *zap.Logger
+}
+
+// NewSolanaAdapter creates clients for a Solana cluster's endpoints. Endpoints are tried in order; limiters
+// are shared with the adapters of other networks on the same providers.
+func NewSolanaAdapter(network db.Network, endpoints []RPCEndpointConfig, limiters *RPCRateLimiters, config RPCPoolConfig) (*SolanaAdapter, error) {
+	log := logger.Log
+	if log == nil {
+		log = zap.NewNop()
+	}
+
+	dial := func(rpcURL string) (*solana.Client, error) {
+		return solana.NewClient(rpcURL), nil
+	}
+	pool, err := dialRPCPool(network.Name, endpoints, dial, solanaShouldFailover, limiters, config, log)
+	if err != nil {
+		return nil, err
+	}
+	return &SolanaAdapter{
+		networkID: network.ID,
+		pool:      pool,
+		logger:    log,
+	}, nil
+}
+
+// solanaShouldFailover reports whether an error came from the endpoint rather than the cluster's answer
+func solanaShouldFailover(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, solana.ErrNotFound) {
+		return false
+	}
+
+	var rpcErr *solana.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == solanaNodeUnhealthyCode
+	}
+	var httpErr *httpClient.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	// Transport errors: the endpoint could not be reached
+	return true
+}
+
+// NetworkID returns the cluster the adapter reads
+func (a *SolanaAdapter) NetworkID() uuid.UUID {
+	return a.networkID
+}
+
+// GetTransactionData fetches a confirmed transaction's fee and outcome. The fee is paid in lamports,
+// so it is reported in TotalGasCostWei with the native token's decimals.
+func (a *SolanaAdapter) GetTransactionData(ctx context.Context, signature string) (*business.TransactionData, error) {
+	tx, err := poolCall(ctx, a.pool, func(client *solana.Client) (*solana.ConfirmedTransaction, error) {
+		return client.GetTransaction(ctx, signature)
+	})
+	if errors.Is(err, solana.ErrNotFound) {
+		return nil, fmt.Errorf("transaction is still pending")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	var status uint64
+	if tx.Succeeded() {
+		status = 1
+	}
+	return &business.TransactionData{
+		Hash:              signature,
+		BlockNumber:       tx.Slot,
+		BlockTimestamp:    uint64(tx.BlockTime),
+		Status:            status,
+		From:              tx.FeePayer,
+		Value:             big.NewInt(0),
+		GasUsed:           tx.ComputeUnitsConsumed,
+		GasPrice:          big.NewInt(0),
+		EffectiveGasPrice: big.NewInt(0),
+		TotalGasCostWei:   new(big.Int).SetUint64(tx.Fee),
+		NetworkID:         a.networkID,
+		NativeSymbol:      solanaNativeSymbol,
+		NativeDecimals:    solanaNativeDecimals,
+	}, nil
+}
+
+// GetTransactionReceipt returns the slot and block of a confirmed transaction
+func (a *SolanaAdapter) GetTransactionReceipt(ctx context.Context, signature string) (*business.MinedTransaction, error) {
+	tx, err := poolCall(ctx, a.pool, func(client *solana.Client) (*solana.ConfirmedTransaction, error) {
+		return client.GetTransaction(ctx, signature)
+	})
+	if errors.Is(err, solana.ErrNotFound) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	blockhash, err := poolCall(ctx, a.pool, func(client *solana.Client) (string, error) {
+		return client.GetBlockhash(ctx, tx.Slot)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block: %w", err)
+	}
+
+	mined := &business.MinedTransaction{
+		BlockNumber: tx.Slot,
+		BlockHash:   blockhash,
+	}
+	if tx.Succeeded() {
+		mined.Status = 1
+	}
+	return mined, nil
+}
+
+// GetTransaction reports whether the cluster has seen a transaction. Solana transactions have no nonce,
+// so their sender is left empty. Transactions whose blockhash expired before they landed are never found.
+func (a *SolanaAdapter) GetTransaction(ctx context.Context, signature string) (*business.SentTransaction, error) {
+	status, err := poolCall(ctx, a.pool, func(client *solana.Client) (*solana.SignatureStatus, error) {
+		return client.GetSignatureStatus(ctx, signature)
+	})
+	if errors.Is(err, solana.ErrNotFound) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signature status: %w", err)
+	}
+
+	return &business.SentTransaction{
+		Pending: status.ConfirmationStatus == solanaCommitmentProcessed,
+	}, nil
+}
+
+// GetBlockNumber returns the latest confirmed slot
+func (a *SolanaAdapter) GetBlockNumber(ctx context.Context) (uint64, error) {
+	slot, err := poolCall(ctx, a.pool, func(client *solana.Client) (uint64, error) {
+		return client.GetSlot(ctx)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get slot: %w", err)
+	}
+	return slot, nil
+}
+
+// GetNativeBalance returns an account's balance in lamports
+func (a *SolanaAdapter) GetNativeBalance(ctx context.Context, account string) (*big.Int, error) {
+	lamports, err := poolCall(ctx, a.pool, func(client *solana.Client) (uint64, error) {
+		return client.GetBalance(ctx, account)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	return new(big.Int).SetUint64(lamports), nil
+}
+
+// GetTokenBalance returns the total a wallet holds of a mint across its token accounts
+func (a *SolanaAdapter) GetTokenBalance(ctx context.Context, mint, owner string) (*big.Int, error) {
+	accounts, err := poolCall(ctx, a.pool, func(client *solana.Client) ([]solana.TokenAccount, error) {
+		return client.GetTokenAccountsByOwner(ctx, owner, mint)
+	})
+	if errors.Is(err, solana.ErrNotFound) {
+		return big.NewInt(0), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token accounts: %w", err)
+	}
+
+	total := new(big.Int)
+	for _, account := range accounts {
+		total.Add(total, new(big.Int).SetUint64(account.Amount))
+	}
+	return total, nil
+}
+
+// EstimateFee prices a transaction with units signatures at the base fee. Priority fees are not included.
+func (a *SolanaAdapter) EstimateFee(ctx context.Context, units uint64) (*business.FeeEstimate, error) {
+	units = max(units, 1)
+	price := big.NewInt(solanaLamportsPerSignature)
+	return &business.FeeEstimate{
+		Units:          units,
+		PricePerUnit:   price,
+		Total:          new(big.Int).Mul(price, new(big.Int).SetUint64(units)),
+		NativeSymbol:   solanaNativeSymbol,
+		NativeDecimals: solanaNativeDecimals,
+	}, nil
+}
+
+// SubscribeLogs is not supported on Solana, which has no contract logs
+func (a *SolanaAdapter) SubscribeLogs(ctx context.Context, filter business.LogFilter, logs chan<- business.ChainLog) (ChainSubscription, error) {
+	return nil, ErrLogSubscriptionUnsupported
+}
+
+// CheckHealth probes every endpoint for the latest slot
+func (a *SolanaAdapter) CheckHealth(ctx context.Context) {
+	a.pool.checkHealth(ctx, func(ctx context.Context, client *solana.Client) error {
+		_, err := client.GetSlot(ctx)
+		return err
+	})
+}
+
+// EndpointStatus returns the health of each endpoint, in order of preference
+func (a *SolanaAdapter) EndpointStatus() []business.RPCEndpointStatus {
+	return a.pool.status()
+}
+
+// Close is a no-op; Solana clients hold no connections of their own
+func (a *SolanaAdapter) Close() {}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Circuit breaker states stored on circuit_breakers
+const (
+	CircuitBreakerStateClosed   = "closed"
+	CircuitBreakerStateOpen     = "open"
+	CircuitBreakerStateHalfOpen = "half_open"
+)
+
+// DelegationServerCircuitBreaker names the breaker around the delegation server
+const DelegationServerCircuitBreaker = "delegation_server"
+
+// CircuitBreaker is a circuit breaker whose state lives in the database, so every worker and every process
+// calling the same dependency sees it open and close together
+type CircuitBreaker struct {
+	queries          db.Querier
+	name             string
+	failureThreshold int32
+	resetTimeout     time.Duration
+}
+
+// NewCircuitBreaker creates a circuit breaker that opens after failureThreshold consecutive failures and lets
+// a single probe through once it has been open for resetTimeout
+func NewCircuitBreaker(queries db.Querier, name string, failureThreshold int32, resetTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		queries:          queries,
+		name:             name,
+		failureThreshold: failureThreshold,
+		resetTimeout:     resetTimeout,
+	}
+}
+
+// Allow reports whether a call may go ahead. While the breaker is open it returns false, except for the one
+// caller that gets to probe the dependency once the reset timeout has passed.
+func (cb *CircuitBreaker) Allow(ctx context.Context) (bool, error) {
+	breaker, err := cb.queries.GetCircuitBreaker(ctx, cb.name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to get circuit breaker %s: %w", cb.name, err)
+	}
+	if breaker.State == CircuitBreakerStateClosed {
+		return true, nil
+	}
+
+	_, err = cb.queries.HalfOpenCircuitBreaker(ctx, db.HalfOpenCircuitBreakerParams{
+		Name:        cb.name,
+		ResetBefore: pgtype.Timestamptz{Time: time.Now().Add(-cb.resetTimeout), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Still cooling down, or another caller is probing
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to half-open circuit breaker %s: %w", cb.name, err)
+	}
+
+	logger.Info("Circuit breaker half-open, probing dependency", zap.String("circuit_breaker", cb.name))
+	return true, nil
+}
+
+// RecordFailure counts a failed call and opens the breaker once the threshold is reached
+func (cb *CircuitBreaker) RecordFailure(ctx context.Context, cause error) (*db.CircuitBreaker, error) {
+	var lastError pgtype.Text
+	if cause != nil {
+		lastError = helpers.StringToNullableText(cause.Error())
+	}
+
+	breaker, err := cb.queries.RecordCircuitBreakerFailure(ctx, db.RecordCircuitBreakerFailureParams{
+		Name:             cb.name,
+		FailureThreshold: cb.failureThreshold,
+		LastError:        lastError,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record circuit breaker failure: %w", err)
+	}
+
+	// Both timestamps come from the same NOW() when this failure opened the breaker
+	if breaker.State == CircuitBreakerStateOpen && breaker.StateChangedAt.Time.Equal(breaker.LastFailureAt.Time) {
+		logger.Warn("Opening circuit breaker due to consecutive failures",
+			zap.String("circuit_breaker", cb.name),
+			zap.Int32("failure_count", breaker.ConsecutiveFailures),
+			zap.Int32("threshold", breaker.FailureThreshold),
+		)
+	}
+	return &breaker, nil
+}
+
+// RecordSuccess closes the breaker and resets its failure count
+func (cb *CircuitBreaker) RecordSuccess(ctx context.Context) error {
+	changed, err := cb.queries.RecordCircuitBreakerSuccess(ctx, cb.name)
+	if err != nil {
+		return fmt.Errorf("failed to record circuit breaker success: %w", err)
+	}
+	if changed > 0 {
+		logger.Info("Circuit breaker closed, dependency is available", zap.String("circuit_breaker", cb.name))
+	}
+	return nil
+}
+
+// State returns the stored breaker, or a closed one if it has never recorded a failure
+func (cb *CircuitBreaker) State(ctx context.Context) (*db.CircuitBreaker, error) {
+	breaker, err := cb.queries.GetCircuitBreaker(ctx, cb.name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &db.CircuitBreaker{
+				Name:             cb.name,
+				State:            CircuitBreakerStateClosed,
+				FailureThreshold: cb.failureThreshold,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to get circuit breaker %s: %w", cb.name, err)
+	}
+	return &breaker, nil
+}
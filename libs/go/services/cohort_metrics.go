@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/constants"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// DefaultCohortLookbackMonths is the cohort window the metrics scheduler precomputes
+const DefaultCohortLookbackMonths = 12
+
+// cohortDimensions are the ways customers can be grouped into cohorts
+var cohortDimensions = []string{business.CohortByMonth, business.CohortByProduct, business.CohortByNetwork}
+
+// IsValidCohortBy reports whether customers can be grouped into cohorts by the given dimension
+func IsValidCohortBy(cohortBy string) bool {
+	for _, dimension := range cohortDimensions {
+		if dimension == cohortBy {
+			return true
+		}
+	}
+	return false
+}
+
+// CalculateCohortMetricsForWorkspace recalculates and stores the default cohort window for every
+// cohort dimension in the workspace's default currency
+func (s *DashboardMetricsService) CalculateCohortMetricsForWorkspace(ctx context.Context, workspaceID uuid.UUID, asOf time.Time) error {
+	defaultCurrency, err := s.currencyService.GetWorkspaceDefaultCurrency(ctx, workspaceID)
+	var currency string
+	if err != nil {
+		// Fallback to USD if no default currency is set
+		currency = constants.USDCurrency
+	} else {
+		currency = defaultCurrency.Code
+	}
+
+	for _, cohortBy := range cohortDimensions {
+		if _, err := refreshCohortMetrics(ctx, s.queries, workspaceID, cohortBy, DefaultCohortLookbackMonths, currency, asOf); err != nil {
+			return fmt.Errorf("failed to calculate %s cohorts: %w", cohortBy, err)
+		}
+	}
+
+	s.logger.Debug("Calculated cohort metrics",
+		zap.String("workspace_id", workspaceID.String()),
+		zap.String("currency", currency),
+	)
+	return nil
+}
+
+// calculateCohortMetrics builds the cohort grid for customers who started in the last `months` months
+func calculateCohortMetrics(ctx context.Context, queries db.Querier, workspaceID uuid.UUID, cohortBy string, months int, currency string, asOf time.Time) ([]db.CohortMetric, error) {
+	cohortsFrom := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, asOf.Location()).AddDate(0, -(months - 1), 0)
+
+	rows, err := queries.CalculateCohortMetrics(ctx, db.CalculateCohortMetricsParams{
+		CohortsFrom: pgtype.Timestamptz{Time: cohortsFrom, Valid: true},
+		AsOf:        pgtype.Timestamptz{Time: asOf, Valid: true},
+		CohortBy:    cohortBy,
+		WorkspaceID: workspaceID,
+		Currency:    currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	calculatedAt := pgtype.Timestamptz{Time: asOf, Valid: true}
+	cells := make([]db.CohortMetric, len(rows))
+	for i, row := range rows {
+		cells[i] = db.CohortMetric{
+			WorkspaceID:          workspaceID,
+			CohortBy:             cohortBy,
+			LookbackMonths:       int32(months),
+			FiatCurrency:         currency,
+			CohortKey:            row.CohortKey,
+			CohortLabel:          row.CohortLabel,
+			PeriodOffset:         row.PeriodOffset,
+			EligibleCustomers:    row.EligibleCustomers,
+			ActiveCustomers:      row.ActiveCustomers,
+			RevenueCents:         row.RevenueCents,
+			StartingRevenueCents: row.StartingRevenueCents,
+			CalculatedAt:         calculatedAt,
+		}
+	}
+	return cells, nil
+}
+
+// refreshCohortMetrics recalculates a cohort window and replaces its stored cells
+func refreshCohortMetrics(ctx context.Context, queries db.Querier, workspaceID uuid.UUID, cohortBy string, months int, currency string, asOf time.Time) ([]db.CohortMetric, error) {
+	cells, err := calculateCohortMetrics(ctx, queries, workspaceID, cohortBy, months, currency, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.UpsertCohortMetricsParams{
+		WorkspaceID:          workspaceID,
+		CohortBy:             cohortBy,
+		LookbackMonths:       int32(months),
+		FiatCurrency:         currency,
+		CohortKeys:           make([]string, len(cells)),
+		CohortLabels:         make([]string, len(cells)),
+		PeriodOffsets:        make([]int32, len(cells)),
+		EligibleCustomers:    make([]int64, len(cells)),
+		ActiveCustomers:      make([]int64, len(cells)),
+		RevenueCents:         make([]int64, len(cells)),
+		StartingRevenueCents: make([]int64, len(cells)),
+		CalculatedAt:         pgtype.Timestamptz{Time: asOf, Valid: true},
+	}
+	for i, cell := range cells {
+		params.CohortKeys[i] = cell.CohortKey
+		params.CohortLabels[i] = cell.CohortLabel
+		params.PeriodOffsets[i] = cell.PeriodOffset
+		params.EligibleCustomers[i] = cell.EligibleCustomers
+		params.ActiveCustomers[i] = cell.ActiveCustomers
+		params.RevenueCents[i] = cell.RevenueCents
+		params.StartingRevenueCents[i] = cell.StartingRevenueCents
+	}
+
+	if len(cells) > 0 {
+		if err := queries.UpsertCohortMetrics(ctx, params); err != nil {
+			return nil, fmt.Errorf("failed to store cohort metrics: %w", err)
+		}
+	}
+	if err := queries.DeleteStaleCohortMetrics(ctx, db.DeleteStaleCohortMetricsParams{
+		WorkspaceID:    workspaceID,
+		CohortBy:       cohortBy,
+		LookbackMonths: int32(months),
+		FiatCurrency:   currency,
+		CalculatedAt:   params.CalculatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to delete stale cohort metrics: %w", err)
+	}
+
+	return cells, nil
+}
+
+// buildCohortAnalysis arranges cohort cells, ordered by cohort and period, into retention matrices.
+// Logo retention is the share of the period's eligible customers still active; net revenue
+// retention is their revenue in the period relative to their first month's revenue.
+func buildCohortAnalysis(cohortBy string, months int, currency string, cells []db.CohortMetric) *business.CohortAnalysis {
+	analysis := &business.CohortAnalysis{
+		CohortBy:       cohortBy,
+		LookbackMonths: months,
+		Currency:       currency,
+		Cohorts:        []business.CohortRow{},
+	}
+
+	var row *business.CohortRow
+	for _, cell := range cells {
+		if cell.CalculatedAt.Valid && cell.CalculatedAt.Time.After(analysis.CalculatedAt) {
+			analysis.CalculatedAt = cell.CalculatedAt.Time
+		}
+
+		if row == nil || row.Key != cell.CohortKey {
+			analysis.Cohorts = append(analysis.Cohorts, business.CohortRow{
+				Key:   cell.CohortKey,
+				Label: cell.CohortLabel,
+			})
+			row = &analysis.Cohorts[len(analysis.Cohorts)-1]
+		}
+
+		if cell.PeriodOffset == 0 {
+			row.Customers = cell.EligibleCustomers
+			row.StartingRevenue = business.MoneyAmount{
+				AmountCents: cell.StartingRevenueCents,
+				Currency:    currency,
+				Formatted:   helpers.FormatMoney(cell.StartingRevenueCents, currency),
+			}
+		}
+
+		row.ActiveCustomers = append(row.ActiveCustomers, cell.ActiveCustomers)
+		row.RevenueCents = append(row.RevenueCents, cell.RevenueCents)
+		row.LogoRetention = append(row.LogoRetention, retentionPercentage(cell.ActiveCustomers, cell.EligibleCustomers))
+		row.NetRevenueRetention = append(row.NetRevenueRetention, retentionPercentage(cell.RevenueCents, cell.StartingRevenueCents))
+	}
+
+	return analysis
+}
+
+// retentionPercentage returns value as a percentage of base, rounded to two decimals
+func retentionPercentage(value, base int64) float64 {
+	if base <= 0 {
+		return 0
+	}
+	return math.Round(float64(value)/float64(base)*10000) / 100
+}
@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// sampleCohortRows has a January cohort observed for three months and a February cohort for two
+func sampleCohortRows() []db.CalculateCohortMetricsRow {
+	return []db.CalculateCohortMetricsRow{
+		{CohortKey: "2024-01", CohortLabel: "2024-01", PeriodOffset: 0, EligibleCustomers: 10, ActiveCustomers: 10, RevenueCents: 100000, StartingRevenueCents: 100000},
+		{CohortKey: "2024-01", CohortLabel: "2024-01", PeriodOffset: 1, EligibleCustomers: 10, ActiveCustomers: 8, RevenueCents: 95000, StartingRevenueCents: 100000},
+		{CohortKey: "2024-01", CohortLabel: "2024-01", PeriodOffset: 2, EligibleCustomers: 10, ActiveCustomers: 7, RevenueCents: 110000, StartingRevenueCents: 100000},
+		{CohortKey: "2024-02", CohortLabel: "2024-02", PeriodOffset: 0, EligibleCustomers: 4, ActiveCustomers: 4, RevenueCents: 0, StartingRevenueCents: 0},
+		{CohortKey: "2024-02", CohortLabel: "2024-02", PeriodOffset: 1, EligibleCustomers: 4, ActiveCustomers: 3, RevenueCents: 30000, StartingRevenueCents: 0},
+	}
+}
+
+func storedCohortMetrics(workspaceID uuid.UUID, cohortBy string) []db.CohortMetric {
+	calculatedAt := pgtype.Timestamptz{Time: time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), Valid: true}
+	rows := sampleCohortRows()
+	cells := make([]db.CohortMetric, len(rows))
+	for i, row := range rows {
+		cells[i] = db.CohortMetric{
+			WorkspaceID:          workspaceID,
+			CohortBy:             cohortBy,
+			LookbackMonths:       services.DefaultCohortLookbackMonths,
+			FiatCurrency:         "USD",
+			CohortKey:            row.CohortKey,
+			CohortLabel:          row.CohortLabel,
+			PeriodOffset:         row.PeriodOffset,
+			EligibleCustomers:    row.EligibleCustomers,
+			ActiveCustomers:      row.ActiveCustomers,
+			RevenueCents:         row.RevenueCents,
+			StartingRevenueCents: row.StartingRevenueCents,
+			CalculatedAt:         calculatedAt,
+		}
+	}
+	return cells
+}
+
+func TestAnalyticsService_GetCohortAnalysis(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier, service := createTestAnalyticsService(ctrl)
+	ctx := context.Background()
+	workspaceID := createTestWorkspaceID()
+
+	t.Run("builds retention matrices from precomputed cohorts", func(t *testing.T) {
+		mockQuerier.EXPECT().ListCohortMetrics(ctx, db.ListCohortMetricsParams{
+			WorkspaceID:    workspaceID,
+			CohortBy:       business.CohortByMonth,
+			LookbackMonths: services.DefaultCohortLookbackMonths,
+			FiatCurrency:   "USD",
+		}).Return(storedCohortMetrics(workspaceID, business.CohortByMonth), nil)
+
+		analysis, err := service.GetCohortAnalysis(ctx, workspaceID, "", 0, "USD")
+		require.NoError(t, err)
+
+		assert.Equal(t, business.CohortByMonth, analysis.CohortBy)
+		assert.Equal(t, services.DefaultCohortLookbackMonths, analysis.LookbackMonths)
+		assert.Equal(t, time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), analysis.CalculatedAt)
+		require.Len(t, analysis.Cohorts, 2)
+
+		january := analysis.Cohorts[0]
+		assert.Equal(t, "2024-01", january.Key)
+		assert.Equal(t, int64(10), january.Customers)
+		assert.Equal(t, int64(100000), january.StartingRevenue.AmountCents)
+		assert.Equal(t, []int64{10, 8, 7}, january.ActiveCustomers)
+		assert.Equal(t, []float64{100, 80, 70}, january.LogoRetention)
+		assert.Equal(t, []float64{100, 95, 110}, january.NetRevenueRetention)
+
+		// Without first-month revenue there is no revenue retention to report
+		february := analysis.Cohorts[1]
+		assert.Equal(t, []float64{100, 75}, february.LogoRetention)
+		assert.Equal(t, []float64{0, 0}, february.NetRevenueRetention)
+	})
+
+	t.Run("calculates and stores cohorts that have not been precomputed", func(t *testing.T) {
+		mockQuerier.EXPECT().ListCohortMetrics(ctx, gomock.Any()).Return([]db.CohortMetric{}, nil)
+		mockQuerier.EXPECT().CalculateCohortMetrics(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.CalculateCohortMetricsParams) ([]db.CalculateCohortMetricsRow, error) {
+				assert.Equal(t, business.CohortByProduct, arg.CohortBy)
+				assert.Equal(t, "EUR", arg.Currency)
+				assert.Equal(t, 1, arg.CohortsFrom.Time.Day())
+				return sampleCohortRows(), nil
+			})
+		mockQuerier.EXPECT().UpsertCohortMetrics(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.UpsertCohortMetricsParams) error {
+				assert.Equal(t, []string{"2024-01", "2024-01", "2024-01", "2024-02", "2024-02"}, arg.CohortKeys)
+				assert.Equal(t, []int32{0, 1, 2, 0, 1}, arg.PeriodOffsets)
+				assert.Equal(t, "EUR", arg.FiatCurrency)
+				return nil
+			})
+		mockQuerier.EXPECT().DeleteStaleCohortMetrics(ctx, gomock.Any()).Return(nil)
+
+		analysis, err := service.GetCohortAnalysis(ctx, workspaceID, business.CohortByProduct, services.DefaultCohortLookbackMonths, "EUR")
+		require.NoError(t, err)
+		assert.Len(t, analysis.Cohorts, 2)
+		assert.Equal(t, "EUR", analysis.Currency)
+	})
+
+	t.Run("calculates other windows on demand without storing them", func(t *testing.T) {
+		mockQuerier.EXPECT().CalculateCohortMetrics(ctx, gomock.Any()).Return(sampleCohortRows(), nil)
+
+		analysis, err := service.GetCohortAnalysis(ctx, workspaceID, business.CohortByNetwork, 24, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, 24, analysis.LookbackMonths)
+		assert.Len(t, analysis.Cohorts, 2)
+	})
+
+	t.Run("rejects unknown cohort dimensions", func(t *testing.T) {
+		_, err := service.GetCohortAnalysis(ctx, workspaceID, "region", 0, "USD")
+		assert.Error(t, err)
+	})
+}
+
+func TestDashboardMetricsService_CalculateCohortMetricsForWorkspace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewDashboardMetricsService(mockQuerier, nil)
+	ctx := context.Background()
+	workspaceID := uuid.New()
+
+	mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(ctx, workspaceID).Return(db.FiatCurrency{Code: "EUR"}, nil)
+
+	var dimensions []string
+	mockQuerier.EXPECT().CalculateCohortMetrics(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CalculateCohortMetricsParams) ([]db.CalculateCohortMetricsRow, error) {
+			dimensions = append(dimensions, arg.CohortBy)
+			return sampleCohortRows(), nil
+		}).Times(3)
+	mockQuerier.EXPECT().UpsertCohortMetrics(ctx, gomock.Any()).Return(nil).Times(3)
+	mockQuerier.EXPECT().DeleteStaleCohortMetrics(ctx, gomock.Any()).Return(nil).Times(3)
+
+	err := service.CalculateCohortMetricsForWorkspace(ctx, workspaceID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []string{business.CohortByMonth, business.CohortByProduct, business.CohortByNetwork}, dimensions)
+}
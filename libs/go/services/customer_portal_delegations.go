@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	dsClient "github.com/cyphera/cyphera-api/libs/go/client/delegation_server"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RedemptionSimulator dry-runs delegation redemptions. Implemented by the delegation server client.
+type RedemptionSimulator interface {
+	SimulateRedemption(ctx context.Context, signature []byte, executionObject dsClient.ExecutionObject) (*dsClient.SimulationResult, error)
+}
+
+// PortalDelegationConfig configures how the customer portal verifies the delegations customers sign to
+// switch wallets or reauthorize subscriptions
+type PortalDelegationConfig struct {
+	// DelegateAddress is the smart wallet delegations must be granted to
+	DelegateAddress string
+	// Simulator dry-runs the subscription's payment with each new delegation, which checks its signature on-chain
+	Simulator RedemptionSimulator
+	// Enforcers maps caveat enforcer addresses to the kind of caveat they enforce, so new delegations' limits
+	// can be checked against the subscription
+	Enforcers map[common.Address]string
+	// SolanaDelegateAddress is the payment delegate Solana customers approve on their token accounts
+	SolanaDelegateAddress string
+	// SplPayments verifies Solana approvals on-chain
+	SplPayments SplDelegatePayments
+}
+
+// WithDelegationVerification creates a new customer portal service that verifies new delegations as configured
+func (s *CustomerPortalService) WithDelegationVerification(config PortalDelegationConfig) *CustomerPortalService {
+	return &CustomerPortalService{
+		db:                            s.db,
+		subscriptionManagementService: s.subscriptionManagementService,
+		portalBaseURL:                 s.portalBaseURL,
+		delegations:                   config,
+		pool:                          s.pool,
+	}
+}
+
+// WithTransactions creates a new customer portal service that stores reauthorizations in a single transaction
+func (s *CustomerPortalService) WithTransactions(pool *pgxpool.Pool) *CustomerPortalService {
+	return &CustomerPortalService{
+		db:                            s.db,
+		subscriptionManagementService: s.subscriptionManagementService,
+		portalBaseURL:                 s.portalBaseURL,
+		delegations:                   s.delegations,
+		pool:                          pool,
+	}
+}
+
+// verifyDelegation checks, as subscription creation does, that a delegation a customer signed in the portal can
+// pay for the subscription. EVM delegations must be complete and granted to the payment delegate, their caveats
+// must allow the subscription's renewals, and a dry run of the subscription's payment must accept their
+// signature. Solana approvals are verified on-chain and returned so they can be bound to the new delegation.
+func (s *CustomerPortalService) verifyDelegation(ctx context.Context, sub db.Subscription, delegation params.DelegationParams) (*business.VerifiedSplApproval, error) {
+	productToken, err := s.db.GetProductToken(ctx, sub.ProductTokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product token: %w", err)
+	}
+
+	if productToken.NetworkType == string(db.NetworkTypeSolana) {
+		return s.verifySplApproval(ctx, sub, productToken, delegation)
+	}
+
+	if err := helpers.ValidateDelegationData(delegation, s.delegations.DelegateAddress); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDelegation, err)
+	}
+	if s.delegations.Simulator == nil {
+		return nil, fmt.Errorf("delegation verification is not configured")
+	}
+
+	product, err := s.db.GetProductWithoutWorkspaceId(ctx, sub.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if err := s.checkDelegationTerms(sub, product, productToken, delegation, time.Now()); err != nil {
+		return nil, err
+	}
+
+	execution, err := s.subscriptionExecution(ctx, sub, product, productToken)
+	if err != nil {
+		return nil, err
+	}
+
+	caveats := delegation.Caveats
+	if len(caveats) == 0 {
+		caveats = json.RawMessage("[]")
+	}
+	delegationBytes, err := json.Marshal(dsClient.DelegationData{
+		Delegate:  delegation.Delegate,
+		Delegator: delegation.Delegator,
+		Authority: delegation.Authority,
+		Caveats:   caveats,
+		Salt:      delegation.Salt,
+		Signature: delegation.Signature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal delegation: %w", err)
+	}
+
+	result, err := s.delegations.Simulator.SimulateRedemption(ctx, delegationBytes, execution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify delegation: %w", err)
+	}
+	if result.Error != nil {
+		if result.Error.Retryable {
+			return nil, fmt.Errorf("failed to verify delegation: %w", result.Error)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDelegation, result.Error)
+	}
+	return nil, nil
+}
+
+// verifySplApproval checks on-chain that a Solana approval was made by its owner for the subscription's product
+// token and covers its price, and that neither it nor its token account already backs another subscription
+func (s *CustomerPortalService) verifySplApproval(ctx context.Context, sub db.Subscription, productToken db.GetProductTokenRow, delegation params.DelegationParams) (*business.VerifiedSplApproval, error) {
+	if err := helpers.ValidateSplApprovalData(delegation, s.delegations.SolanaDelegateAddress); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDelegation, err)
+	}
+	if s.delegations.SplPayments == nil {
+		return nil, fmt.Errorf("solana payments are not configured")
+	}
+
+	if _, err := s.db.GetSplApprovalBySignature(ctx, delegation.Signature); err == nil {
+		return nil, fmt.Errorf("%w: approval %s already backs a subscription", ErrInvalidDelegation, delegation.Signature)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to look up approval: %w", err)
+	}
+	if err := checkSplTokenAccountUnbound(ctx, s.db, delegation.Authority, pgtype.UUID{Bytes: sub.ID, Valid: true}); err != nil {
+		if errors.Is(err, ErrSplApprovalUnverified) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDelegation, err)
+		}
+		return nil, err
+	}
+
+	approval, err := s.delegations.SplPayments.VerifySplApproval(ctx, productToken.NetworkID, business.SplApproval{
+		Signature:    delegation.Signature,
+		TokenAccount: delegation.Authority,
+		Owner:        delegation.Delegator,
+		Memo:         SplApprovalMemo(sub.ProductTokenID),
+	})
+	if err != nil {
+		if errors.Is(err, ErrSplApprovalUnverified) || errors.Is(err, ErrSplApprovalMissing) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDelegation, err)
+		}
+		return nil, fmt.Errorf("failed to verify approval: %w", err)
+	}
+
+	if approval.Mint != "" && approval.Mint != productToken.ContractAddress {
+		return nil, fmt.Errorf("%w: approval is for mint %s, not %s", ErrInvalidDelegation, approval.Mint, productToken.ContractAddress)
+	}
+	if approval.Amount < uint64(sub.TokenAmount) {
+		return nil, fmt.Errorf("%w: approval delegates %d of %d", ErrInvalidDelegation, approval.Amount, sub.TokenAmount)
+	}
+	return approval, nil
+}
+
+// checkDelegationTerms checks that a delegation's caveats let it pay for the subscription from its next renewal:
+// it must be redeemable then, its allowances must cover the subscription's price in its token, and its period
+// allowance must renew at least once per billing interval. Caveats whose enforcer is not configured are left to
+// the dry run.
+func (s *CustomerPortalService) checkDelegationTerms(sub db.Subscription, product db.Product, productToken db.GetProductTokenRow, delegation params.DelegationParams, now time.Time) error {
+	caveats, err := ParseDelegationCaveats(delegation.Caveats)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDelegation, err)
+	}
+	terms, err := ParseDelegationTerms(caveats, s.delegations.Enforcers)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDelegation, err)
+	}
+
+	nextRenewal := now
+	if sub.NextRedemptionDate.Valid && sub.NextRedemptionDate.Time.After(now) {
+		nextRenewal = sub.NextRedemptionDate.Time
+	}
+	if terms.ExpiresAt != nil && !terms.ExpiresAt.After(nextRenewal) {
+		return fmt.Errorf("%w: delegation expires at %s, before the next renewal", ErrInvalidDelegation, terms.ExpiresAt.Format(time.RFC3339))
+	}
+	if terms.NotBefore != nil && terms.NotBefore.After(nextRenewal) {
+		return fmt.Errorf("%w: delegation cannot be redeemed until %s, after the next renewal", ErrInvalidDelegation, terms.NotBefore.Format(time.RFC3339))
+	}
+
+	token := common.HexToAddress(productToken.ContractAddress)
+	price := big.NewInt(int64(sub.TokenAmount))
+	if allowance := terms.TransferAmount; allowance != nil {
+		if allowance.Token != token {
+			return fmt.Errorf("%w: delegation allows transfers of %s, not %s", ErrInvalidDelegation, allowance.Token.Hex(), token.Hex())
+		}
+		if allowance.MaxAmount.Cmp(price) < 0 {
+			return fmt.Errorf("%w: delegation allows transfers of %s, less than the price of %s", ErrInvalidDelegation, allowance.MaxAmount, price)
+		}
+	}
+	if allowance := terms.PeriodTransfer; allowance != nil {
+		if allowance.Token != token {
+			return fmt.Errorf("%w: delegation allows transfers of %s, not %s", ErrInvalidDelegation, allowance.Token.Hex(), token.Hex())
+		}
+		if allowance.PeriodAmount.Cmp(price) < 0 {
+			return fmt.Errorf("%w: delegation allows %s per period, less than the price of %s", ErrInvalidDelegation, allowance.PeriodAmount, price)
+		}
+
+		intervalType := ""
+		if product.IntervalType.Valid {
+			intervalType = string(product.IntervalType.IntervalType)
+		}
+		interval := helpers.CalculateNextRedemption(intervalType, nextRenewal).Sub(nextRenewal)
+		if allowance.PeriodDuration > interval {
+			return fmt.Errorf("%w: delegation period of %s is longer than the billing interval of %s", ErrInvalidDelegation, allowance.PeriodDuration, interval)
+		}
+	}
+	if limit := terms.CallLimit; limit != nil && limit.MaxCalls.Sign() == 0 {
+		return fmt.Errorf("%w: delegation allows no redemptions", ErrInvalidDelegation)
+	}
+	return nil
+}
+
+// subscriptionExecution builds the payment a subscription's renewals redeem its delegation for
+func (s *CustomerPortalService) subscriptionExecution(ctx context.Context, sub db.Subscription, product db.Product, productToken db.GetProductTokenRow) (dsClient.ExecutionObject, error) {
+	merchantWallet, err := s.db.GetWalletByID(ctx, db.GetWalletByIDParams{
+		ID:          product.WalletID,
+		WorkspaceID: product.WorkspaceID,
+	})
+	if err != nil {
+		return dsClient.ExecutionObject{}, fmt.Errorf("failed to get merchant wallet: %w", err)
+	}
+
+	return dsClient.ExecutionObject{
+		MerchantAddress:      merchantWallet.WalletAddress,
+		TokenContractAddress: productToken.ContractAddress,
+		TokenAmount:          int64(sub.TokenAmount),
+		TokenDecimals:        productToken.Decimals,
+		ChainID:              uint32(productToken.ChainID),
+		NetworkName:          productToken.NetworkName,
+	}, nil
+}
+
+// bindSplApproval records that a verified approval backs a subscription's new delegation, so renewals charge it
+func (s *CustomerPortalService) bindSplApproval(ctx context.Context, queries db.Querier, sub db.Subscription, delegationID uuid.UUID, delegation params.DelegationParams, approval *business.VerifiedSplApproval) error {
+	if approval == nil {
+		return nil
+	}
+	if _, err := queries.CreateSplApproval(ctx, db.CreateSplApprovalParams{
+		Signature:      delegation.Signature,
+		DelegationID:   delegationID,
+		ProductTokenID: sub.ProductTokenID,
+		TokenAccount:   delegation.Authority,
+		Owner:          delegation.Delegator,
+		Slot:           int64(approval.Slot),
+	}); err != nil {
+		return fmt.Errorf("failed to record approval: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,751 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	// CustomerPortalTokenPrefix is the prefix for all customer portal session tokens
+	CustomerPortalTokenPrefix = "cps"
+	// DefaultCustomerPortalSessionTTL is how long a portal link stays valid unless the workspace configures otherwise
+	DefaultCustomerPortalSessionTTL = time.Hour
+	// MinCustomerPortalSessionTTL and MaxCustomerPortalSessionTTL bound portal link lifetimes
+	MinCustomerPortalSessionTTL = 5 * time.Minute
+	MaxCustomerPortalSessionTTL = 24 * time.Hour
+
+	// customerPortalTokenLength is the number of random bytes in a portal session token
+	customerPortalTokenLength = 32
+)
+
+var (
+	// ErrCustomerPortalSessionInvalid is returned for unknown, revoked or expired portal session tokens
+	ErrCustomerPortalSessionInvalid = errors.New("customer portal session is invalid or expired")
+	// ErrCustomerPortalActionNotAllowed is returned when the merchant has disabled a portal action
+	ErrCustomerPortalActionNotAllowed = errors.New("this action is not enabled by the merchant")
+	// ErrCustomerPortalNotFound is returned when a resource does not exist or belongs to another customer
+	ErrCustomerPortalNotFound = errors.New("not found")
+	// ErrReauthorizationNotRequired is returned when a customer re-signs a subscription whose delegation is still usable
+	ErrReauthorizationNotRequired = errors.New("this subscription does not need a new authorization")
+	// ErrInvalidDelegation is returned when a delegation signed in the portal cannot pay for the subscription
+	ErrInvalidDelegation = errors.New("invalid delegation")
+)
+
+// CustomerPortalService handles customer self-service: portal sessions, merchant portal
+// settings and the subscription, invoice and payment views customers see.
+type CustomerPortalService struct {
+	db                            db.Querier
+	subscriptionManagementService interfaces.SubscriptionManagementService
+	portalBaseURL                 string
+	delegations                   PortalDelegationConfig
+	pool                          *pgxpool.Pool
+}
+
+// NewCustomerPortalService creates a new customer portal service.
+// portalBaseURL is the customer-facing portal page that portal links point to.
+func NewCustomerPortalService(
+	queries db.Querier,
+	subscriptionManagementService interfaces.SubscriptionManagementService,
+	portalBaseURL string,
+) *CustomerPortalService {
+	return &CustomerPortalService{
+		db:                            queries,
+		subscriptionManagementService: subscriptionManagementService,
+		portalBaseURL:                 strings.TrimRight(portalBaseURL, "/"),
+	}
+}
+
+// hashCustomerPortalToken returns the stored form of a portal session token.
+// Tokens carry 256 bits of randomness, so a fast hash is sufficient for lookups.
+func hashCustomerPortalToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateCustomerPortalToken generates a new random portal session token
+func generateCustomerPortalToken() (string, error) {
+	randomBytes := make([]byte, customerPortalTokenLength)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return fmt.Sprintf("%s_%s", CustomerPortalTokenPrefix, base64.RawURLEncoding.EncodeToString(randomBytes)), nil
+}
+
+// defaultCustomerPortalSettings mirrors the column defaults of customer_portal_settings
+func defaultCustomerPortalSettings(workspaceID uuid.UUID) db.CustomerPortalSetting {
+	return db.CustomerPortalSetting{
+		WorkspaceID:        workspaceID,
+		AllowCancel:        true,
+		AllowPause:         false,
+		AllowResume:        true,
+		AllowUpdateBilling: true,
+		AllowWalletSwitch:  true,
+		SessionTtlSeconds:  int32(DefaultCustomerPortalSessionTTL / time.Second),
+	}
+}
+
+// PermissionsFromSettings converts stored portal settings into the actions customers may take
+func PermissionsFromSettings(settings db.CustomerPortalSetting) business.CustomerPortalPermissions {
+	return business.CustomerPortalPermissions{
+		Cancel:        settings.AllowCancel,
+		Pause:         settings.AllowPause,
+		Resume:        settings.AllowResume,
+		UpdateBilling: settings.AllowUpdateBilling,
+		SwitchWallet:  settings.AllowWalletSwitch,
+	}
+}
+
+// GetSettings returns a workspace's portal settings, falling back to the defaults when none are stored
+func (s *CustomerPortalService) GetSettings(ctx context.Context, workspaceID uuid.UUID) (db.CustomerPortalSetting, error) {
+	settings, err := s.db.GetCustomerPortalSettings(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return defaultCustomerPortalSettings(workspaceID), nil
+		}
+		return db.CustomerPortalSetting{}, fmt.Errorf("failed to get customer portal settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings changes the portal actions and link defaults for a workspace
+func (s *CustomerPortalService) UpdateSettings(ctx context.Context, updateParams params.UpdateCustomerPortalSettingsParams) (db.CustomerPortalSetting, error) {
+	settings, err := s.GetSettings(ctx, updateParams.WorkspaceID)
+	if err != nil {
+		return db.CustomerPortalSetting{}, err
+	}
+
+	if updateParams.AllowCancel != nil {
+		settings.AllowCancel = *updateParams.AllowCancel
+	}
+	if updateParams.AllowPause != nil {
+		settings.AllowPause = *updateParams.AllowPause
+	}
+	if updateParams.AllowResume != nil {
+		settings.AllowResume = *updateParams.AllowResume
+	}
+	if updateParams.AllowUpdateBilling != nil {
+		settings.AllowUpdateBilling = *updateParams.AllowUpdateBilling
+	}
+	if updateParams.AllowWalletSwitch != nil {
+		settings.AllowWalletSwitch = *updateParams.AllowWalletSwitch
+	}
+	if updateParams.DefaultReturnURL != nil {
+		settings.DefaultReturnUrl = pgtype.Text{String: *updateParams.DefaultReturnURL, Valid: *updateParams.DefaultReturnURL != ""}
+	}
+	if updateParams.SessionTTL != nil {
+		if *updateParams.SessionTTL < MinCustomerPortalSessionTTL || *updateParams.SessionTTL > MaxCustomerPortalSessionTTL {
+			return db.CustomerPortalSetting{}, fmt.Errorf("session TTL must be between %s and %s", MinCustomerPortalSessionTTL, MaxCustomerPortalSessionTTL)
+		}
+		settings.SessionTtlSeconds = int32(*updateParams.SessionTTL / time.Second)
+	}
+
+	updated, err := s.db.UpsertCustomerPortalSettings(ctx, db.UpsertCustomerPortalSettingsParams{
+		WorkspaceID:        updateParams.WorkspaceID,
+		AllowCancel:        settings.AllowCancel,
+		AllowPause:         settings.AllowPause,
+		AllowResume:        settings.AllowResume,
+		AllowUpdateBilling: settings.AllowUpdateBilling,
+		AllowWalletSwitch:  settings.AllowWalletSwitch,
+		DefaultReturnUrl:   settings.DefaultReturnUrl,
+		SessionTtlSeconds:  settings.SessionTtlSeconds,
+	})
+	if err != nil {
+		return db.CustomerPortalSetting{}, fmt.Errorf("failed to update customer portal settings: %w", err)
+	}
+
+	return updated, nil
+}
+
+// CreateSession creates a short-lived portal link for one of the workspace's customers.
+// The returned token is only available here; only its hash is stored.
+func (s *CustomerPortalService) CreateSession(ctx context.Context, createParams params.CreateCustomerPortalSessionParams) (db.CustomerPortalSession, string, error) {
+	isMember, err := s.db.IsCustomerInWorkspace(ctx, db.IsCustomerInWorkspaceParams{
+		WorkspaceID: createParams.WorkspaceID,
+		CustomerID:  createParams.CustomerID,
+	})
+	if err != nil {
+		return db.CustomerPortalSession{}, "", fmt.Errorf("failed to check customer workspace: %w", err)
+	}
+	if !isMember {
+		return db.CustomerPortalSession{}, "", ErrCustomerPortalNotFound
+	}
+
+	settings, err := s.GetSettings(ctx, createParams.WorkspaceID)
+	if err != nil {
+		return db.CustomerPortalSession{}, "", err
+	}
+
+	ttl := createParams.TTL
+	if ttl == 0 {
+		ttl = time.Duration(settings.SessionTtlSeconds) * time.Second
+	}
+	if ttl < MinCustomerPortalSessionTTL || ttl > MaxCustomerPortalSessionTTL {
+		return db.CustomerPortalSession{}, "", fmt.Errorf("session TTL must be between %s and %s", MinCustomerPortalSessionTTL, MaxCustomerPortalSessionTTL)
+	}
+
+	returnURL := pgtype.Text{String: createParams.ReturnURL, Valid: createParams.ReturnURL != ""}
+	if !returnURL.Valid {
+		returnURL = settings.DefaultReturnUrl
+	}
+
+	token, err := generateCustomerPortalToken()
+	if err != nil {
+		return db.CustomerPortalSession{}, "", err
+	}
+
+	var createdByAPIKeyID, createdByUserID pgtype.UUID
+	if createParams.CreatedByAPIKeyID != nil {
+		createdByAPIKeyID = pgtype.UUID{Bytes: *createParams.CreatedByAPIKeyID, Valid: true}
+	}
+	if createParams.CreatedByUserID != nil {
+		createdByUserID = pgtype.UUID{Bytes: *createParams.CreatedByUserID, Valid: true}
+	}
+
+	session, err := s.db.CreateCustomerPortalSession(ctx, db.CreateCustomerPortalSessionParams{
+		WorkspaceID:       createParams.WorkspaceID,
+		CustomerID:        createParams.CustomerID,
+		TokenHash:         hashCustomerPortalToken(token),
+		ReturnUrl:         returnURL,
+		CreatedByApiKeyID: createdByAPIKeyID,
+		CreatedByUserID:   createdByUserID,
+		ExpiresAt:         pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return db.CustomerPortalSession{}, "", fmt.Errorf("failed to create customer portal session: %w", err)
+	}
+
+	return session, token, nil
+}
+
+// SessionURL returns the customer-facing link for a portal session token
+func (s *CustomerPortalService) SessionURL(token string) string {
+	if s.portalBaseURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s?session=%s", s.portalBaseURL, url.QueryEscape(token))
+}
+
+// RevokeSession ends a portal session before it expires
+func (s *CustomerPortalService) RevokeSession(ctx context.Context, sessionID, workspaceID uuid.UUID) error {
+	revoked, err := s.db.RevokeCustomerPortalSession(ctx, db.RevokeCustomerPortalSessionParams{
+		ID:          sessionID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke customer portal session: %w", err)
+	}
+	if revoked == 0 {
+		return ErrCustomerPortalNotFound
+	}
+	return nil
+}
+
+// AuthenticateSession resolves a portal session token to its session
+func (s *CustomerPortalService) AuthenticateSession(ctx context.Context, token string) (db.CustomerPortalSession, error) {
+	if !strings.HasPrefix(token, CustomerPortalTokenPrefix+"_") {
+		return db.CustomerPortalSession{}, ErrCustomerPortalSessionInvalid
+	}
+
+	session, err := s.db.GetActiveCustomerPortalSessionByTokenHash(ctx, hashCustomerPortalToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.CustomerPortalSession{}, ErrCustomerPortalSessionInvalid
+		}
+		return db.CustomerPortalSession{}, fmt.Errorf("failed to look up customer portal session: %w", err)
+	}
+
+	if err := s.db.TouchCustomerPortalSession(ctx, session.ID); err != nil && logger.Log != nil {
+		logger.Log.Warn("Failed to record customer portal session use",
+			zap.String("session_id", session.ID.String()),
+			zap.Error(err))
+	}
+
+	return session, nil
+}
+
+// DeleteExpiredSessions removes portal sessions that expired before the cutoff
+func (s *CustomerPortalService) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := s.db.DeleteExpiredCustomerPortalSessions(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired customer portal sessions: %w", err)
+	}
+	return deleted, nil
+}
+
+// GetCustomer returns the customer behind a portal request and their wallets
+func (s *CustomerPortalService) GetCustomer(ctx context.Context, scope params.CustomerPortalScope) (db.Customer, []db.CustomerWallet, error) {
+	customer, err := s.db.GetCustomer(ctx, scope.CustomerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Customer{}, nil, ErrCustomerPortalNotFound
+		}
+		return db.Customer{}, nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	wallets, err := s.db.ListCustomerWallets(ctx, scope.CustomerID)
+	if err != nil {
+		return db.Customer{}, nil, fmt.Errorf("failed to list customer wallets: %w", err)
+	}
+
+	return customer, wallets, nil
+}
+
+// ListSubscriptions lists the customer's subscriptions with the actions each merchant allows
+func (s *CustomerPortalService) ListSubscriptions(ctx context.Context, scope params.CustomerPortalScope) ([]business.CustomerPortalSubscription, error) {
+	rows, err := s.db.ListCustomerPortalSubscriptions(ctx, db.ListCustomerPortalSubscriptionsParams{
+		CustomerID:  scope.CustomerID,
+		WorkspaceID: scopeWorkspaceID(scope),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer subscriptions: %w", err)
+	}
+
+	reauths, err := s.db.ListPendingSubscriptionReauthorizationsByCustomer(ctx, scope.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending reauthorizations: %w", err)
+	}
+	reauthsBySubscription := make(map[uuid.UUID]db.SubscriptionReauthorization, len(reauths))
+	for _, reauth := range reauths {
+		reauthsBySubscription[reauth.SubscriptionID] = reauth
+	}
+
+	// Subscriptions usually span a handful of merchants, so load each merchant's settings once
+	permissions := make(map[uuid.UUID]business.CustomerPortalPermissions)
+	subscriptions := make([]business.CustomerPortalSubscription, 0, len(rows))
+	for _, row := range rows {
+		perms, ok := permissions[row.WorkspaceID]
+		if !ok {
+			settings, err := s.GetSettings(ctx, row.WorkspaceID)
+			if err != nil {
+				return nil, err
+			}
+			perms = PermissionsFromSettings(settings)
+			permissions[row.WorkspaceID] = perms
+		}
+		subscription := business.CustomerPortalSubscription{
+			Subscription: row,
+			Permissions:  perms,
+		}
+		if reauth, ok := reauthsBySubscription[row.ID]; ok {
+			subscription.Reauthorization = &reauth
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+// ListInvoices lists the customer's finalized invoices, newest first
+func (s *CustomerPortalService) ListInvoices(ctx context.Context, scope params.CustomerPortalScope, limit, offset int32) ([]db.Invoice, error) {
+	invoices, err := s.db.ListCustomerPortalInvoices(ctx, db.ListCustomerPortalInvoicesParams{
+		CustomerID:  pgtype.UUID{Bytes: scope.CustomerID, Valid: true},
+		WorkspaceID: scopeWorkspaceID(scope),
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// ListPayments lists the customer's payments, newest first
+func (s *CustomerPortalService) ListPayments(ctx context.Context, scope params.CustomerPortalScope, limit, offset int32) ([]db.Payment, error) {
+	payments, err := s.db.ListCustomerPortalPayments(ctx, db.ListCustomerPortalPaymentsParams{
+		CustomerID:  scope.CustomerID,
+		WorkspaceID: scopeWorkspaceID(scope),
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer payments: %w", err)
+	}
+	return payments, nil
+}
+
+// UpdateBillingDetails updates the customer's name, contact and billing address.
+// Merchant-issued sessions need the merchant to allow billing updates; customers signed in
+// with their own account always manage their own details.
+func (s *CustomerPortalService) UpdateBillingDetails(ctx context.Context, updateParams params.UpdateCustomerBillingDetailsParams) (db.Customer, error) {
+	if updateParams.Scope.WorkspaceID != nil {
+		if err := s.requirePermission(ctx, *updateParams.Scope.WorkspaceID, business.PortalActionUpdateBilling); err != nil {
+			return db.Customer{}, err
+		}
+	}
+
+	customer, err := s.db.UpdateCustomerBillingDetails(ctx, db.UpdateCustomerBillingDetailsParams{
+		Name:              optionalText(updateParams.Name),
+		Email:             optionalText(updateParams.Email),
+		Phone:             optionalText(updateParams.Phone),
+		BillingCountry:    optionalText(upperPtr(updateParams.BillingCountry)),
+		BillingState:      optionalText(updateParams.BillingState),
+		BillingCity:       optionalText(updateParams.BillingCity),
+		BillingPostalCode: optionalText(updateParams.BillingPostalCode),
+		ID:                updateParams.Scope.CustomerID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Customer{}, ErrCustomerPortalNotFound
+		}
+		return db.Customer{}, fmt.Errorf("failed to update billing details: %w", err)
+	}
+
+	return customer, nil
+}
+
+// CancelSubscription schedules cancellation of one of the customer's subscriptions
+func (s *CustomerPortalService) CancelSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID, reason, feedback string) error {
+	if _, err := s.authorizeSubscription(ctx, scope, subscriptionID, business.PortalActionCancel); err != nil {
+		return err
+	}
+	return s.subscriptionManagementService.CancelSubscription(ctx, subscriptionID, reason, feedback)
+}
+
+// PauseSubscription pauses one of the customer's subscriptions
+func (s *CustomerPortalService) PauseSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID, pauseUntil *time.Time, reason string) error {
+	if _, err := s.authorizeSubscription(ctx, scope, subscriptionID, business.PortalActionPause); err != nil {
+		return err
+	}
+	return s.subscriptionManagementService.PauseSubscription(ctx, subscriptionID, pauseUntil, reason)
+}
+
+// ResumeSubscription resumes one of the customer's paused subscriptions
+func (s *CustomerPortalService) ResumeSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID) error {
+	if _, err := s.authorizeSubscription(ctx, scope, subscriptionID, business.PortalActionResume); err != nil {
+		return err
+	}
+	return s.subscriptionManagementService.ResumeSubscription(ctx, subscriptionID)
+}
+
+// PreviewCancellation shows the customer what cancelling a subscription would do
+func (s *CustomerPortalService) PreviewCancellation(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID) (*business.ChangePreview, error) {
+	if _, err := s.authorizeSubscription(ctx, scope, subscriptionID, business.PortalActionCancel); err != nil {
+		return nil, err
+	}
+	return s.subscriptionManagementService.PreviewChange(ctx, subscriptionID, string(db.SubscriptionChangeTypeCancel), nil)
+}
+
+// SwitchSubscriptionWallet moves a subscription to another of the customer's wallets.
+// Payments are pulled through a delegation, so the customer must sign a new delegation
+// from the new wallet to the same delegate as the current one, verified as on subscription creation.
+func (s *CustomerPortalService) SwitchSubscriptionWallet(ctx context.Context, switchParams params.SwitchSubscriptionWalletParams) (db.Subscription, error) {
+	sub, err := s.authorizeSubscription(ctx, switchParams.Scope, switchParams.SubscriptionID, business.PortalActionSwitchWallet)
+	if err != nil {
+		return db.Subscription{}, err
+	}
+
+	wallet, err := s.db.GetCustomerWallet(ctx, switchParams.CustomerWalletID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Subscription{}, ErrCustomerPortalNotFound
+		}
+		return db.Subscription{}, fmt.Errorf("failed to get customer wallet: %w", err)
+	}
+	if wallet.CustomerID != switchParams.Scope.CustomerID {
+		return db.Subscription{}, ErrCustomerPortalNotFound
+	}
+
+	delegation := switchParams.Delegation
+	if !sameWalletAddress(wallet.NetworkType, delegation.Delegator, wallet.WalletAddress) {
+		return db.Subscription{}, fmt.Errorf("%w: delegation must be signed by wallet %s", ErrInvalidDelegation, wallet.WalletAddress)
+	}
+
+	currentDelegation, err := s.db.GetDelegationData(ctx, sub.DelegationID)
+	if err != nil {
+		return db.Subscription{}, fmt.Errorf("failed to get current delegation: %w", err)
+	}
+	if !strings.EqualFold(delegation.Delegate, currentDelegation.Delegate) {
+		return db.Subscription{}, fmt.Errorf("%w: delegation must be granted to %s", ErrInvalidDelegation, currentDelegation.Delegate)
+	}
+
+	approval, err := s.verifyDelegation(ctx, sub, delegation)
+	if err != nil {
+		return db.Subscription{}, err
+	}
+
+	caveats := delegation.Caveats
+	if len(caveats) == 0 {
+		caveats = json.RawMessage("[]")
+	}
+
+	// The new delegation, the wallet switch and any reauthorization it settles are stored together
+	var updated db.Subscription
+	var newDelegation db.DelegationDatum
+	err = s.inTransaction(ctx, func(queries db.Querier) error {
+		var err error
+		newDelegation, err = queries.CreateDelegationData(ctx, db.CreateDelegationDataParams{
+			Delegate:  delegation.Delegate,
+			Delegator: delegation.Delegator,
+			Authority: delegation.Authority,
+			Caveats:   caveats,
+			Salt:      delegation.Salt,
+			Signature: delegation.Signature,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store delegation: %w", err)
+		}
+		if err := s.bindSplApproval(ctx, queries, sub, newDelegation.ID, delegation, approval); err != nil {
+			return err
+		}
+
+		updated, err = queries.UpdateSubscriptionPaymentWallet(ctx, db.UpdateSubscriptionPaymentWalletParams{
+			ID:               sub.ID,
+			CustomerID:       switchParams.Scope.CustomerID,
+			CustomerWalletID: pgtype.UUID{Bytes: wallet.ID, Valid: true},
+			DelegationID:     newDelegation.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to switch subscription wallet: %w", err)
+		}
+
+		// A delegation from the new wallet also settles any reauthorization the old one was waiting on
+		return s.completeReauthorization(ctx, queries, sub.ID, newDelegation.ID)
+	})
+	if err != nil {
+		return db.Subscription{}, err
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Customer switched subscription wallet",
+			zap.String("subscription_id", sub.ID.String()),
+			zap.String("customer_id", switchParams.Scope.CustomerID.String()),
+			zap.String("customer_wallet_id", wallet.ID.String()),
+			zap.String("delegation_id", newDelegation.ID.String()))
+	}
+
+	return updated, nil
+}
+
+// ReauthorizeSubscription attaches a replacement delegation to a subscription whose delegation the delegation
+// monitor found revoked, expired or used up. The subscription keeps its plan, billing dates and history; the new
+// delegation must come from the same wallet and be granted to the same delegate as the one it replaces, and is
+// verified as on subscription creation. Merchants cannot disable this action, since the subscription cannot be
+// renewed without it.
+func (s *CustomerPortalService) ReauthorizeSubscription(ctx context.Context, reauthParams params.ReauthorizeSubscriptionParams) (db.Subscription, error) {
+	sub, err := s.customerSubscription(ctx, reauthParams.Scope, reauthParams.SubscriptionID)
+	if err != nil {
+		return db.Subscription{}, err
+	}
+
+	pending, err := s.db.GetPendingSubscriptionReauthorization(ctx, sub.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Subscription{}, ErrReauthorizationNotRequired
+		}
+		return db.Subscription{}, fmt.Errorf("failed to get pending reauthorization: %w", err)
+	}
+
+	currentDelegation, err := s.db.GetDelegationData(ctx, sub.DelegationID)
+	if err != nil {
+		return db.Subscription{}, fmt.Errorf("failed to get current delegation: %w", err)
+	}
+
+	delegation := reauthParams.Delegation
+	if !strings.EqualFold(delegation.Delegator, currentDelegation.Delegator) {
+		return db.Subscription{}, fmt.Errorf("%w: delegation must be signed by wallet %s; switch wallets to pay from another one", ErrInvalidDelegation, currentDelegation.Delegator)
+	}
+	if !strings.EqualFold(delegation.Delegate, currentDelegation.Delegate) {
+		return db.Subscription{}, fmt.Errorf("%w: delegation must be granted to %s", ErrInvalidDelegation, currentDelegation.Delegate)
+	}
+	if strings.EqualFold(delegation.Signature, currentDelegation.Signature) {
+		return db.Subscription{}, fmt.Errorf("%w: a new delegation must be signed", ErrInvalidDelegation)
+	}
+
+	approval, err := s.verifyDelegation(ctx, sub, delegation)
+	if err != nil {
+		return db.Subscription{}, err
+	}
+
+	caveats := delegation.Caveats
+	if len(caveats) == 0 {
+		caveats = json.RawMessage("[]")
+	}
+
+	// The delegation is only stored once it replaces the old one and settles the reauthorization
+	var updated db.Subscription
+	var newDelegation db.DelegationDatum
+	err = s.inTransaction(ctx, func(queries db.Querier) error {
+		var err error
+		newDelegation, err = queries.CreateDelegationData(ctx, db.CreateDelegationDataParams{
+			Delegate:  delegation.Delegate,
+			Delegator: delegation.Delegator,
+			Authority: delegation.Authority,
+			Caveats:   caveats,
+			Salt:      delegation.Salt,
+			Signature: delegation.Signature,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store delegation: %w", err)
+		}
+		if err := s.bindSplApproval(ctx, queries, sub, newDelegation.ID, delegation, approval); err != nil {
+			return err
+		}
+
+		updated, err = queries.UpdateSubscriptionPaymentWallet(ctx, db.UpdateSubscriptionPaymentWalletParams{
+			ID:               sub.ID,
+			CustomerID:       reauthParams.Scope.CustomerID,
+			CustomerWalletID: sub.CustomerWalletID,
+			DelegationID:     newDelegation.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to attach delegation: %w", err)
+		}
+
+		if _, err := queries.CompleteSubscriptionReauthorization(ctx, db.CompleteSubscriptionReauthorizationParams{
+			ID:                      pending.ID,
+			ReplacementDelegationID: pgtype.UUID{Bytes: newDelegation.ID, Valid: true},
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Another request settled the reauthorization first
+				return ErrReauthorizationNotRequired
+			}
+			return fmt.Errorf("failed to complete reauthorization: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return db.Subscription{}, err
+	}
+
+	if logger.Log != nil {
+		logger.Log.Info("Customer reauthorized subscription",
+			zap.String("subscription_id", sub.ID.String()),
+			zap.String("customer_id", reauthParams.Scope.CustomerID.String()),
+			zap.String("reason", pending.Reason),
+			zap.String("delegation_id", newDelegation.ID.String()))
+	}
+
+	return updated, nil
+}
+
+// inTransaction runs fn against a database transaction, or directly against the queries when no pool was given
+func (s *CustomerPortalService) inTransaction(ctx context.Context, fn func(queries db.Querier) error) error {
+	if s.pool == nil {
+		return fn(s.db)
+	}
+	return helpers.WithTransaction(ctx, s.pool, func(tx pgx.Tx) error {
+		return fn(db.New(tx))
+	})
+}
+
+// completeReauthorization settles a subscription's pending reauthorization, if it has one, with a new delegation
+func (s *CustomerPortalService) completeReauthorization(ctx context.Context, queries db.Querier, subscriptionID, delegationID uuid.UUID) error {
+	pending, err := queries.GetPendingSubscriptionReauthorization(ctx, subscriptionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to get pending reauthorization: %w", err)
+	}
+
+	if _, err := queries.CompleteSubscriptionReauthorization(ctx, db.CompleteSubscriptionReauthorizationParams{
+		ID:                      pending.ID,
+		ReplacementDelegationID: pgtype.UUID{Bytes: delegationID, Valid: true},
+	}); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to complete reauthorization: %w", err)
+	}
+	return nil
+}
+
+// authorizeSubscription loads a subscription the customer owns and checks that its merchant allows the action
+func (s *CustomerPortalService) authorizeSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID, action string) (db.Subscription, error) {
+	sub, err := s.customerSubscription(ctx, scope, subscriptionID)
+	if err != nil {
+		return db.Subscription{}, err
+	}
+
+	if err := s.requirePermission(ctx, sub.WorkspaceID, action); err != nil {
+		return db.Subscription{}, err
+	}
+
+	return sub, nil
+}
+
+// customerSubscription loads a subscription the customer owns
+func (s *CustomerPortalService) customerSubscription(ctx context.Context, scope params.CustomerPortalScope, subscriptionID uuid.UUID) (db.Subscription, error) {
+	sub, err := s.db.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Subscription{}, ErrCustomerPortalNotFound
+		}
+		return db.Subscription{}, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	// Report other customers' subscriptions as missing rather than forbidden
+	if sub.CustomerID != scope.CustomerID {
+		return db.Subscription{}, ErrCustomerPortalNotFound
+	}
+	if scope.WorkspaceID != nil && sub.WorkspaceID != *scope.WorkspaceID {
+		return db.Subscription{}, ErrCustomerPortalNotFound
+	}
+
+	return sub, nil
+}
+
+// requirePermission returns ErrCustomerPortalActionNotAllowed unless the workspace allows the action
+func (s *CustomerPortalService) requirePermission(ctx context.Context, workspaceID uuid.UUID, action string) error {
+	settings, err := s.GetSettings(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !PermissionsFromSettings(settings).Allows(action) {
+		return ErrCustomerPortalActionNotAllowed
+	}
+	return nil
+}
+
+// sameWalletAddress reports whether two addresses name the same wallet on a network of the given type.
+// EVM addresses are hex and only differ in their checksum casing; other networks' addresses are case-sensitive.
+func sameWalletAddress(networkType db.NetworkType, a, b string) bool {
+	if networkType == db.NetworkTypeEvm {
+		return common.IsHexAddress(a) && common.IsHexAddress(b) && common.HexToAddress(a) == common.HexToAddress(b)
+	}
+	return a == b
+}
+
+// scopeWorkspaceID converts a portal scope into the optional workspace filter used by portal queries
+func scopeWorkspaceID(scope params.CustomerPortalScope) pgtype.UUID {
+	if scope.WorkspaceID == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *scope.WorkspaceID, Valid: true}
+}
+
+// optionalText converts an optional string into a nullable text parameter
+func optionalText(value *string) pgtype.Text {
+	if value == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: strings.TrimSpace(*value), Valid: true}
+}
+
+// upperPtr upper-cases an optional string, used for ISO country codes
+func upperPtr(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	upper := strings.ToUpper(*value)
+	return &upper
+}
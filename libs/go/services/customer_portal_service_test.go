@@ -0,0 +1,584 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	dsClient "github.com/cyphera/cyphera-api/libs/go/client/delegation_server"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/proto"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCustomerPortalService_CreateSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewCustomerPortalService(mockQuerier, nil, "https://pay.example.com/portal/")
+	ctx := context.Background()
+
+	workspaceID := uuid.New()
+	customerID := uuid.New()
+	apiKeyID := uuid.New()
+
+	t.Run("customer outside workspace", func(t *testing.T) {
+		mockQuerier.EXPECT().IsCustomerInWorkspace(ctx, db.IsCustomerInWorkspaceParams{
+			WorkspaceID: workspaceID,
+			CustomerID:  customerID,
+		}).Return(false, nil)
+
+		_, _, err := service.CreateSession(ctx, params.CreateCustomerPortalSessionParams{
+			WorkspaceID: workspaceID,
+			CustomerID:  customerID,
+		})
+		assert.ErrorIs(t, err, services.ErrCustomerPortalNotFound)
+	})
+
+	t.Run("uses workspace defaults and stores only the token hash", func(t *testing.T) {
+		mockQuerier.EXPECT().IsCustomerInWorkspace(ctx, gomock.Any()).Return(true, nil)
+		mockQuerier.EXPECT().GetCustomerPortalSettings(ctx, workspaceID).Return(db.CustomerPortalSetting{
+			WorkspaceID:       workspaceID,
+			DefaultReturnUrl:  pgtype.Text{String: "https://merchant.example.com/account", Valid: true},
+			SessionTtlSeconds: 900,
+		}, nil)
+
+		var stored db.CreateCustomerPortalSessionParams
+		mockQuerier.EXPECT().CreateCustomerPortalSession(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.CreateCustomerPortalSessionParams) (db.CustomerPortalSession, error) {
+				stored = arg
+				return db.CustomerPortalSession{ID: uuid.New(), WorkspaceID: arg.WorkspaceID, CustomerID: arg.CustomerID}, nil
+			})
+
+		before := time.Now()
+		_, token, err := service.CreateSession(ctx, params.CreateCustomerPortalSessionParams{
+			WorkspaceID:       workspaceID,
+			CustomerID:        customerID,
+			CreatedByAPIKeyID: &apiKeyID,
+		})
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(token, "cps_"))
+		sum := sha256.Sum256([]byte(token))
+		assert.Equal(t, hex.EncodeToString(sum[:]), stored.TokenHash)
+		assert.NotContains(t, stored.TokenHash, token)
+		assert.Equal(t, "https://merchant.example.com/account", stored.ReturnUrl.String)
+		assert.Equal(t, pgtype.UUID{Bytes: apiKeyID, Valid: true}, stored.CreatedByApiKeyID)
+		assert.WithinDuration(t, before.Add(15*time.Minute), stored.ExpiresAt.Time, 5*time.Second)
+		assert.Equal(t, "https://pay.example.com/portal?session="+token, service.SessionURL(token))
+	})
+
+	t.Run("rejects TTL outside bounds", func(t *testing.T) {
+		mockQuerier.EXPECT().IsCustomerInWorkspace(ctx, gomock.Any()).Return(true, nil)
+		mockQuerier.EXPECT().GetCustomerPortalSettings(ctx, workspaceID).Return(db.CustomerPortalSetting{}, pgx.ErrNoRows)
+
+		_, _, err := service.CreateSession(ctx, params.CreateCustomerPortalSessionParams{
+			WorkspaceID: workspaceID,
+			CustomerID:  customerID,
+			TTL:         48 * time.Hour,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestCustomerPortalService_AuthenticateSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewCustomerPortalService(mockQuerier, nil, "")
+	ctx := context.Background()
+
+	t.Run("wrong prefix is rejected without a lookup", func(t *testing.T) {
+		_, err := service.AuthenticateSession(ctx, "cyk_not_a_portal_token")
+		assert.ErrorIs(t, err, services.ErrCustomerPortalSessionInvalid)
+	})
+
+	t.Run("unknown or expired token", func(t *testing.T) {
+		mockQuerier.EXPECT().GetActiveCustomerPortalSessionByTokenHash(ctx, gomock.Any()).Return(db.CustomerPortalSession{}, pgx.ErrNoRows)
+
+		_, err := service.AuthenticateSession(ctx, "cps_expired")
+		assert.ErrorIs(t, err, services.ErrCustomerPortalSessionInvalid)
+	})
+
+	t.Run("valid token records use", func(t *testing.T) {
+		session := db.CustomerPortalSession{ID: uuid.New(), WorkspaceID: uuid.New(), CustomerID: uuid.New()}
+		sum := sha256.Sum256([]byte("cps_valid"))
+		mockQuerier.EXPECT().GetActiveCustomerPortalSessionByTokenHash(ctx, hex.EncodeToString(sum[:])).Return(session, nil)
+		mockQuerier.EXPECT().TouchCustomerPortalSession(ctx, session.ID).Return(nil)
+
+		got, err := service.AuthenticateSession(ctx, "cps_valid")
+		require.NoError(t, err)
+		assert.Equal(t, session.ID, got.ID)
+	})
+}
+
+func TestCustomerPortalService_CancelSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	mockManagement := mocks.NewMockSubscriptionManagementService(ctrl)
+	service := services.NewCustomerPortalService(mockQuerier, mockManagement, "")
+	ctx := context.Background()
+
+	customerID := uuid.New()
+	workspaceID := uuid.New()
+	subscriptionID := uuid.New()
+	subscription := db.Subscription{ID: subscriptionID, CustomerID: customerID, WorkspaceID: workspaceID}
+
+	otherWorkspace := uuid.New()
+
+	tests := []struct {
+		name      string
+		scope     params.CustomerPortalScope
+		sub       db.Subscription
+		settings  *db.CustomerPortalSetting
+		expectErr error
+		cancels   bool
+	}{
+		{
+			name:      "another customer's subscription",
+			scope:     params.CustomerPortalScope{CustomerID: uuid.New()},
+			sub:       subscription,
+			expectErr: services.ErrCustomerPortalNotFound,
+		},
+		{
+			name:      "portal session for another merchant",
+			scope:     params.CustomerPortalScope{CustomerID: customerID, WorkspaceID: &otherWorkspace},
+			sub:       subscription,
+			expectErr: services.ErrCustomerPortalNotFound,
+		},
+		{
+			name:      "merchant disabled cancellation",
+			scope:     params.CustomerPortalScope{CustomerID: customerID, WorkspaceID: &workspaceID},
+			sub:       subscription,
+			settings:  &db.CustomerPortalSetting{WorkspaceID: workspaceID, AllowCancel: false},
+			expectErr: services.ErrCustomerPortalActionNotAllowed,
+		},
+		{
+			name:     "customer login cancels with any merchant",
+			scope:    params.CustomerPortalScope{CustomerID: customerID},
+			sub:      subscription,
+			settings: &db.CustomerPortalSetting{WorkspaceID: workspaceID, AllowCancel: true},
+			cancels:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuerier.EXPECT().GetSubscription(ctx, subscriptionID).Return(tt.sub, nil)
+			if tt.settings != nil {
+				mockQuerier.EXPECT().GetCustomerPortalSettings(ctx, workspaceID).Return(*tt.settings, nil)
+			}
+			if tt.cancels {
+				mockManagement.EXPECT().CancelSubscription(ctx, subscriptionID, "too expensive", "").Return(nil)
+			}
+
+			err := service.CancelSubscription(ctx, tt.scope, subscriptionID, "too expensive", "")
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// fakeRedemptionSimulator records the delegations it dry-runs and reports result for each
+type fakeRedemptionSimulator struct {
+	result      *dsClient.SimulationResult
+	delegations []dsClient.DelegationData
+	executions  []dsClient.ExecutionObject
+}
+
+func (f *fakeRedemptionSimulator) SimulateRedemption(_ context.Context, signature []byte, execution dsClient.ExecutionObject) (*dsClient.SimulationResult, error) {
+	var delegation dsClient.DelegationData
+	if err := json.Unmarshal(signature, &delegation); err != nil {
+		return nil, err
+	}
+	f.delegations = append(f.delegations, delegation)
+	f.executions = append(f.executions, execution)
+	return f.result, nil
+}
+
+func TestCustomerPortalService_SwitchSubscriptionWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	simulator := &fakeRedemptionSimulator{}
+	service := services.NewCustomerPortalService(mockQuerier, nil, "").WithDelegationVerification(services.PortalDelegationConfig{
+		DelegateAddress: "0xdeadbeef",
+		Simulator:       simulator,
+	})
+	ctx := context.Background()
+
+	customerID := uuid.New()
+	workspaceID := uuid.New()
+	subscription := db.Subscription{ID: uuid.New(), CustomerID: customerID, WorkspaceID: workspaceID, DelegationID: uuid.New(), ProductID: uuid.New(), ProductTokenID: uuid.New(), TokenAmount: 1000000}
+	wallet := db.CustomerWallet{ID: uuid.New(), CustomerID: customerID, WalletAddress: "0xAbC0000000000000000000000000000000000001", NetworkType: db.NetworkTypeEvm}
+	product := db.Product{ID: subscription.ProductID, WorkspaceID: workspaceID, WalletID: uuid.New()}
+	productToken := db.GetProductTokenRow{ID: subscription.ProductTokenID, ContractAddress: "0xToken", Decimals: 6, ChainID: 8453, NetworkName: "base", NetworkType: "evm"}
+	scope := params.CustomerPortalScope{CustomerID: customerID}
+	delegation := params.DelegationParams{
+		Delegate:  "0xdeadbeef",
+		Delegator: strings.ToLower(wallet.WalletAddress),
+		Authority: "0x" + strings.Repeat("f", 64),
+		Salt:      "1",
+		Signature: "0xsig",
+	}
+
+	expectAuthorized := func() {
+		mockQuerier.EXPECT().GetSubscription(ctx, subscription.ID).Return(subscription, nil)
+		mockQuerier.EXPECT().GetCustomerPortalSettings(ctx, workspaceID).Return(db.CustomerPortalSetting{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().GetCustomerWallet(ctx, wallet.ID).Return(wallet, nil)
+	}
+	expectVerified := func() {
+		mockQuerier.EXPECT().GetDelegationData(ctx, subscription.DelegationID).Return(db.DelegationDatum{Delegate: "0xDEADBEEF"}, nil)
+		mockQuerier.EXPECT().GetProductToken(ctx, subscription.ProductTokenID).Return(productToken, nil)
+		mockQuerier.EXPECT().GetProductWithoutWorkspaceId(ctx, subscription.ProductID).Return(product, nil)
+		mockQuerier.EXPECT().GetWalletByID(ctx, db.GetWalletByIDParams{ID: product.WalletID, WorkspaceID: workspaceID}).
+			Return(db.Wallet{WalletAddress: "0xMerchant"}, nil)
+	}
+
+	t.Run("delegation signed by another wallet", func(t *testing.T) {
+		expectAuthorized()
+
+		_, err := service.SwitchSubscriptionWallet(ctx, params.SwitchSubscriptionWalletParams{
+			Scope:            scope,
+			SubscriptionID:   subscription.ID,
+			CustomerWalletID: wallet.ID,
+			Delegation:       params.DelegationParams{Delegator: "0x0000000000000000000000000000000000000002"},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("non-EVM addresses must match exactly", func(t *testing.T) {
+		solanaWallet := db.CustomerWallet{ID: uuid.New(), CustomerID: customerID, WalletAddress: "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU", NetworkType: db.NetworkTypeSolana}
+		mockQuerier.EXPECT().GetSubscription(ctx, subscription.ID).Return(subscription, nil)
+		mockQuerier.EXPECT().GetCustomerPortalSettings(ctx, workspaceID).Return(db.CustomerPortalSetting{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().GetCustomerWallet(ctx, solanaWallet.ID).Return(solanaWallet, nil)
+
+		_, err := service.SwitchSubscriptionWallet(ctx, params.SwitchSubscriptionWalletParams{
+			Scope:            scope,
+			SubscriptionID:   subscription.ID,
+			CustomerWalletID: solanaWallet.ID,
+			Delegation:       params.DelegationParams{Delegator: strings.ToLower(solanaWallet.WalletAddress)},
+		})
+		assert.ErrorIs(t, err, services.ErrInvalidDelegation)
+	})
+
+	t.Run("incomplete delegation", func(t *testing.T) {
+		expectAuthorized()
+		mockQuerier.EXPECT().GetDelegationData(ctx, subscription.DelegationID).Return(db.DelegationDatum{Delegate: "0xDEADBEEF"}, nil)
+		mockQuerier.EXPECT().GetProductToken(ctx, subscription.ProductTokenID).Return(productToken, nil)
+
+		unsigned := delegation
+		unsigned.Signature = ""
+		_, err := service.SwitchSubscriptionWallet(ctx, params.SwitchSubscriptionWalletParams{
+			Scope:            scope,
+			SubscriptionID:   subscription.ID,
+			CustomerWalletID: wallet.ID,
+			Delegation:       unsigned,
+		})
+		assert.ErrorIs(t, err, services.ErrInvalidDelegation)
+	})
+
+	t.Run("delegation whose signature is rejected", func(t *testing.T) {
+		expectAuthorized()
+		expectVerified()
+		simulator.result = &dsClient.SimulationResult{Error: &dsClient.RedemptionError{
+			Code:    proto.ErrorCode_ERROR_CODE_INVALID_DELEGATION,
+			Message: "invalid signature",
+		}}
+
+		_, err := service.SwitchSubscriptionWallet(ctx, params.SwitchSubscriptionWalletParams{
+			Scope:            scope,
+			SubscriptionID:   subscription.ID,
+			CustomerWalletID: wallet.ID,
+			Delegation:       delegation,
+		})
+		assert.ErrorIs(t, err, services.ErrInvalidDelegation)
+	})
+
+	t.Run("stores the new delegation and moves the subscription", func(t *testing.T) {
+		expectAuthorized()
+		expectVerified()
+		simulator.result = &dsClient.SimulationResult{WouldSucceed: true}
+		simulator.delegations, simulator.executions = nil, nil
+
+		newDelegationID := uuid.New()
+		mockQuerier.EXPECT().CreateDelegationData(ctx, gomock.Any()).Return(db.DelegationDatum{ID: newDelegationID}, nil)
+		mockQuerier.EXPECT().UpdateSubscriptionPaymentWallet(ctx, db.UpdateSubscriptionPaymentWalletParams{
+			ID:               subscription.ID,
+			CustomerID:       customerID,
+			CustomerWalletID: pgtype.UUID{Bytes: wallet.ID, Valid: true},
+			DelegationID:     newDelegationID,
+		}).Return(db.Subscription{ID: subscription.ID, DelegationID: newDelegationID}, nil)
+		mockQuerier.EXPECT().GetPendingSubscriptionReauthorization(ctx, subscription.ID).Return(db.SubscriptionReauthorization{}, pgx.ErrNoRows)
+
+		updated, err := service.SwitchSubscriptionWallet(ctx, params.SwitchSubscriptionWalletParams{
+			Scope:            scope,
+			SubscriptionID:   subscription.ID,
+			CustomerWalletID: wallet.ID,
+			Delegation:       delegation,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, newDelegationID, updated.DelegationID)
+
+		// The new wallet's delegation was dry-run against the subscription's payment
+		require.Len(t, simulator.delegations, 1)
+		assert.Equal(t, delegation.Delegator, simulator.delegations[0].Delegator)
+		assert.Equal(t, "0xsig", simulator.delegations[0].Signature)
+		assert.Equal(t, dsClient.ExecutionObject{
+			MerchantAddress:      "0xMerchant",
+			TokenContractAddress: "0xToken",
+			TokenAmount:          1000000,
+			TokenDecimals:        6,
+			ChainID:              8453,
+			NetworkName:          "base",
+		}, simulator.executions[0])
+	})
+}
+
+func TestCustomerPortalService_ReauthorizeSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	simulator := &fakeRedemptionSimulator{result: &dsClient.SimulationResult{WouldSucceed: true}}
+	service := services.NewCustomerPortalService(mockQuerier, nil, "").WithDelegationVerification(services.PortalDelegationConfig{
+		DelegateAddress: "0xdeadbeef00000000000000000000000000000001",
+		Simulator:       simulator,
+		Enforcers:       testEnforcers(),
+	})
+	ctx := context.Background()
+
+	customerID := uuid.New()
+	workspaceID := uuid.New()
+	walletID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	nextRenewal := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+	subscription := db.Subscription{
+		ID:                 uuid.New(),
+		CustomerID:         customerID,
+		WorkspaceID:        workspaceID,
+		DelegationID:       uuid.New(),
+		CustomerWalletID:   walletID,
+		ProductID:          uuid.New(),
+		ProductTokenID:     uuid.New(),
+		TokenAmount:        1000000,
+		NextRedemptionDate: pgtype.Timestamptz{Time: nextRenewal, Valid: true},
+	}
+	product := db.Product{
+		ID:           subscription.ProductID,
+		WorkspaceID:  workspaceID,
+		WalletID:     uuid.New(),
+		IntervalType: db.NullIntervalType{IntervalType: db.IntervalTypeMonth, Valid: true},
+	}
+	productToken := db.GetProductTokenRow{ID: subscription.ProductTokenID, ContractAddress: usdcToken.Hex(), Decimals: 6, ChainID: 8453, NetworkName: "base", NetworkType: "evm"}
+	current := db.DelegationDatum{
+		ID:        subscription.DelegationID,
+		Delegate:  "0xDEADBEEF00000000000000000000000000000001",
+		Delegator: "0xAbC0000000000000000000000000000000000001",
+		Signature: "0xold",
+	}
+	scope := params.CustomerPortalScope{CustomerID: customerID}
+	pending := db.SubscriptionReauthorization{ID: uuid.New(), SubscriptionID: subscription.ID, Reason: services.ReauthorizationReasonRevoked, Status: "pending"}
+
+	delegationWith := func(caveats ...business.CaveatStruct) params.DelegationParams {
+		raw, err := json.Marshal(caveats)
+		require.NoError(t, err)
+		return params.DelegationParams{
+			Delegate:  strings.ToLower(current.Delegate),
+			Delegator: strings.ToLower(current.Delegator),
+			Authority: "0x" + strings.Repeat("f", 64),
+			Caveats:   raw,
+			Salt:      "1",
+			Signature: "0xnew",
+		}
+	}
+	expectPending := func() {
+		mockQuerier.EXPECT().GetSubscription(ctx, subscription.ID).Return(subscription, nil)
+		mockQuerier.EXPECT().GetPendingSubscriptionReauthorization(ctx, subscription.ID).Return(pending, nil)
+		mockQuerier.EXPECT().GetDelegationData(ctx, subscription.DelegationID).Return(current, nil)
+	}
+	expectTerms := func() {
+		mockQuerier.EXPECT().GetProductToken(ctx, subscription.ProductTokenID).Return(productToken, nil)
+		mockQuerier.EXPECT().GetProductWithoutWorkspaceId(ctx, subscription.ProductID).Return(product, nil)
+	}
+	expectMerchant := func() {
+		mockQuerier.EXPECT().GetWalletByID(ctx, db.GetWalletByIDParams{ID: product.WalletID, WorkspaceID: workspaceID}).
+			Return(db.Wallet{WalletAddress: "0xMerchant"}, nil)
+	}
+
+	t.Run("subscription whose delegation is still usable", func(t *testing.T) {
+		mockQuerier.EXPECT().GetSubscription(ctx, subscription.ID).Return(subscription, nil)
+		mockQuerier.EXPECT().GetPendingSubscriptionReauthorization(ctx, subscription.ID).Return(db.SubscriptionReauthorization{}, pgx.ErrNoRows)
+
+		_, err := service.ReauthorizeSubscription(ctx, params.ReauthorizeSubscriptionParams{Scope: scope, SubscriptionID: subscription.ID})
+		assert.ErrorIs(t, err, services.ErrReauthorizationNotRequired)
+	})
+
+	t.Run("delegation signed by another wallet", func(t *testing.T) {
+		expectPending()
+
+		_, err := service.ReauthorizeSubscription(ctx, params.ReauthorizeSubscriptionParams{
+			Scope:          scope,
+			SubscriptionID: subscription.ID,
+			Delegation:     params.DelegationParams{Delegate: current.Delegate, Delegator: "0x0000000000000000000000000000000000000002", Signature: "0xnew"},
+		})
+		assert.ErrorIs(t, err, services.ErrInvalidDelegation)
+	})
+
+	rejected := []struct {
+		name       string
+		delegation params.DelegationParams
+	}{
+		{"delegation that expires before the next renewal", delegationWith(timestampCaveat(0, nextRenewal.Add(-time.Hour).Unix()))},
+		{"delegation that allows less than the price", delegationWith(transferAmountCaveat(999999))},
+		{"delegation whose period allowance is below the price", delegationWith(periodTransferCaveat(500000, 30*24*3600, 0))},
+		{"delegation whose period is longer than the billing interval", delegationWith(periodTransferCaveat(1000000, 365*24*3600, 0))},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			expectPending()
+			expectTerms()
+
+			_, err := service.ReauthorizeSubscription(ctx, params.ReauthorizeSubscriptionParams{
+				Scope:          scope,
+				SubscriptionID: subscription.ID,
+				Delegation:     tt.delegation,
+			})
+			assert.ErrorIs(t, err, services.ErrInvalidDelegation)
+		})
+	}
+
+	t.Run("delegation whose signature is rejected", func(t *testing.T) {
+		expectPending()
+		expectTerms()
+		expectMerchant()
+		simulator.result = &dsClient.SimulationResult{Error: &dsClient.RedemptionError{
+			Code:    proto.ErrorCode_ERROR_CODE_INVALID_DELEGATION,
+			Message: "invalid signature",
+		}}
+		defer func() { simulator.result = &dsClient.SimulationResult{WouldSucceed: true} }()
+
+		_, err := service.ReauthorizeSubscription(ctx, params.ReauthorizeSubscriptionParams{
+			Scope:          scope,
+			SubscriptionID: subscription.ID,
+			Delegation:     delegationWith(),
+		})
+		assert.ErrorIs(t, err, services.ErrInvalidDelegation)
+	})
+
+	t.Run("reauthorization settled by another request", func(t *testing.T) {
+		expectPending()
+		expectTerms()
+		expectMerchant()
+		mockQuerier.EXPECT().CreateDelegationData(ctx, gomock.Any()).Return(db.DelegationDatum{ID: uuid.New()}, nil)
+		mockQuerier.EXPECT().UpdateSubscriptionPaymentWallet(ctx, gomock.Any()).Return(db.Subscription{ID: subscription.ID}, nil)
+		mockQuerier.EXPECT().CompleteSubscriptionReauthorization(ctx, gomock.Any()).Return(db.SubscriptionReauthorization{}, pgx.ErrNoRows)
+
+		_, err := service.ReauthorizeSubscription(ctx, params.ReauthorizeSubscriptionParams{
+			Scope:          scope,
+			SubscriptionID: subscription.ID,
+			Delegation:     delegationWith(),
+		})
+		assert.ErrorIs(t, err, services.ErrReauthorizationNotRequired)
+	})
+
+	t.Run("attaches the new delegation to the existing subscription", func(t *testing.T) {
+		newDelegationID := uuid.New()
+		delegation := delegationWith(
+			timestampCaveat(0, nextRenewal.Add(365*24*time.Hour).Unix()),
+			periodTransferCaveat(1000000, 30*24*3600, 0),
+		)
+		simulator.delegations, simulator.executions = nil, nil
+
+		expectPending()
+		expectTerms()
+		expectMerchant()
+		mockQuerier.EXPECT().CreateDelegationData(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CreateDelegationDataParams) (db.DelegationDatum, error) {
+				assert.Equal(t, "0xnew", arg.Signature)
+				assert.JSONEq(t, string(delegation.Caveats), string(arg.Caveats))
+				return db.DelegationDatum{ID: newDelegationID}, nil
+			})
+		mockQuerier.EXPECT().UpdateSubscriptionPaymentWallet(ctx, db.UpdateSubscriptionPaymentWalletParams{
+			ID:               subscription.ID,
+			CustomerID:       customerID,
+			CustomerWalletID: walletID,
+			DelegationID:     newDelegationID,
+		}).Return(db.Subscription{ID: subscription.ID, DelegationID: newDelegationID}, nil)
+		mockQuerier.EXPECT().CompleteSubscriptionReauthorization(ctx, db.CompleteSubscriptionReauthorizationParams{
+			ID:                      pending.ID,
+			ReplacementDelegationID: pgtype.UUID{Bytes: newDelegationID, Valid: true},
+		}).Return(db.SubscriptionReauthorization{ID: pending.ID, Status: "completed"}, nil)
+
+		updated, err := service.ReauthorizeSubscription(ctx, params.ReauthorizeSubscriptionParams{
+			Scope:          scope,
+			SubscriptionID: subscription.ID,
+			Delegation:     delegation,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, newDelegationID, updated.DelegationID)
+
+		// The new delegation was dry-run against the subscription's payment
+		require.Len(t, simulator.delegations, 1)
+		assert.Equal(t, "0xnew", simulator.delegations[0].Signature)
+		assert.Equal(t, int64(1000000), simulator.executions[0].TokenAmount)
+	})
+}
+
+func TestCustomerPortalService_UpdateBillingDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewCustomerPortalService(mockQuerier, nil, "")
+	ctx := context.Background()
+
+	customerID := uuid.New()
+	workspaceID := uuid.New()
+	country := "de"
+
+	t.Run("portal session needs merchant permission", func(t *testing.T) {
+		mockQuerier.EXPECT().GetCustomerPortalSettings(ctx, workspaceID).Return(db.CustomerPortalSetting{AllowUpdateBilling: false}, nil)
+
+		_, err := service.UpdateBillingDetails(ctx, params.UpdateCustomerBillingDetailsParams{
+			Scope:          params.CustomerPortalScope{CustomerID: customerID, WorkspaceID: &workspaceID},
+			BillingCountry: &country,
+		})
+		assert.True(t, errors.Is(err, services.ErrCustomerPortalActionNotAllowed))
+	})
+
+	t.Run("customer login updates only provided fields", func(t *testing.T) {
+		mockQuerier.EXPECT().UpdateCustomerBillingDetails(ctx, db.UpdateCustomerBillingDetailsParams{
+			BillingCountry: pgtype.Text{String: "DE", Valid: true},
+			ID:             customerID,
+		}).Return(db.Customer{ID: customerID}, nil)
+
+		customer, err := service.UpdateBillingDetails(ctx, params.UpdateCustomerBillingDetailsParams{
+			Scope:          params.CustomerPortalScope{CustomerID: customerID},
+			BillingCountry: &country,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, customerID, customer.ID)
+	})
+}
@@ -3,32 +3,84 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cyphera/cyphera-api/libs/go/db"
 	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
+// WalletNameConfig configures how the ENS and Basenames names of customer wallets are looked up
+type WalletNameConfig struct {
+	// LookupTimeout bounds a name lookup made while finding or creating a customer wallet
+	LookupTimeout time.Duration
+	// RefreshInterval is how long a looked up name is kept before it is looked up again
+	RefreshInterval time.Duration
+	// BatchSize caps the wallets refreshed per run
+	BatchSize int32
+}
+
+// DefaultWalletNameConfig returns the default wallet name configuration
+func DefaultWalletNameConfig() WalletNameConfig {
+	return WalletNameConfig{
+		LookupTimeout:   3 * time.Second,
+		RefreshInterval: 24 * time.Hour,
+		BatchSize:       100,
+	}
+}
+
+// WalletNameConfigFromEnv reads the refresh interval in hours from WALLET_NAME_REFRESH_HOURS on top of the defaults
+func WalletNameConfigFromEnv() (WalletNameConfig, error) {
+	config := DefaultWalletNameConfig()
+
+	if value := strings.TrimSpace(os.Getenv("WALLET_NAME_REFRESH_HOURS")); value != "" {
+		hours, err := strconv.Atoi(value)
+		if err != nil || hours <= 0 {
+			return config, fmt.Errorf("WALLET_NAME_REFRESH_HOURS must be a positive number of hours: %s", value)
+		}
+		config.RefreshInterval = time.Duration(hours) * time.Hour
+	}
+	return config, nil
+}
+
 // CustomerService handles business logic for customer operations
 type CustomerService struct {
-	queries db.Querier
-	logger  *zap.Logger
+	queries      db.Querier
+	nameResolver interfaces.NameResolver
+	nameConfig   WalletNameConfig
+	logger       *zap.Logger
 }
 
 // NewCustomerService creates a new customer service
 func NewCustomerService(queries db.Querier) *CustomerService {
 	return &CustomerService{
-		queries: queries,
-		logger:  logger.Log,
+		queries:    queries,
+		nameConfig: DefaultWalletNameConfig(),
+		logger:     logger.Log,
+	}
+}
+
+// WithNameResolver creates a new customer service instance that looks up the ENS and Basenames names of EVM
+// customer wallets and accepts names in place of their addresses
+func (s *CustomerService) WithNameResolver(resolver interfaces.NameResolver, config WalletNameConfig) *CustomerService {
+	return &CustomerService{
+		queries:      s.queries,
+		nameResolver: resolver,
+		nameConfig:   config,
+		logger:       s.logger,
 	}
 }
 
@@ -361,7 +413,7 @@ func (s *CustomerService) CreateCustomerWallet(ctx context.Context, createParams
 
 	wallet, err := s.queries.CreateCustomerWallet(ctx, db.CreateCustomerWalletParams{
 		CustomerID:    createParams.CustomerID,
-		WalletAddress: createParams.WalletAddress,
+		WalletAddress: helpers.NormalizeWalletAddress(createParams.WalletAddress, string(networkType)),
 		NetworkType:   networkType,
 		Nickname:      pgtype.Text{String: nickname, Valid: nickname != ""},
 		Ens:           pgtype.Text{String: ens, Valid: ens != ""},
@@ -402,7 +454,8 @@ func parseNetworkType(networkType string) (db.NetworkType, error) {
 	}
 }
 
-// ProcessCustomerAndWallet handles customer lookup/creation and wallet association
+// ProcessCustomerAndWallet handles customer lookup/creation and wallet association. The wallet's name is resolved
+// before the transaction is used so the lookups never hold it open.
 func (s *CustomerService) ProcessCustomerAndWallet(
 	ctx context.Context,
 	tx pgx.Tx,
@@ -416,6 +469,12 @@ func (s *CustomerService) ProcessCustomerAndWallet(
 		qtx = s.queries
 	}
 
+	wallet, err := s.resolveWallet(ctx, processParams.WalletAddress, processParams.NetworkType)
+	if err != nil {
+		return nil, nil, err
+	}
+	processParams.WalletAddress = wallet.address
+
 	customers, err := qtx.GetCustomersByWalletAddress(ctx, processParams.WalletAddress)
 	if err != nil {
 		s.logger.Error("Failed to check for existing customers",
@@ -425,12 +484,12 @@ func (s *CustomerService) ProcessCustomerAndWallet(
 	}
 
 	if len(customers) == 0 {
-		return s.CreateCustomerFromWallet(ctx, tx, params.CreateCustomerFromWalletParams{
+		return s.createCustomerFromWallet(ctx, qtx, params.CreateCustomerFromWalletParams{
 			WalletAddress: processParams.WalletAddress,
 			WorkspaceID:   processParams.WorkspaceID,
 			ProductID:     processParams.ProductID,
 			NetworkType:   processParams.NetworkType,
-		})
+		}, wallet)
 	}
 
 	customer := customers[0]
@@ -468,14 +527,14 @@ func (s *CustomerService) ProcessCustomerAndWallet(
 			zap.String("wallet_address", processParams.WalletAddress))
 	}
 
-	customerWallet, err := s.FindOrCreateCustomerWallet(ctx, tx, params.FindOrCreateWalletParams{
+	customerWallet, err := s.findOrCreateCustomerWallet(ctx, qtx, params.FindOrCreateWalletParams{
 		CustomerID:    customer.ID,
 		WalletAddress: processParams.WalletAddress,
 		NetworkType:   processParams.NetworkType,
 		ProductID:     processParams.ProductID,
 		IsPrimary:     true,
 		Verified:      true,
-	})
+	}, wallet)
 
 	return &customer, customerWallet, err
 }
@@ -493,6 +552,22 @@ func (s *CustomerService) CreateCustomerFromWallet(
 		qtx = s.queries
 	}
 
+	wallet, err := s.resolveWallet(ctx, params.WalletAddress, params.NetworkType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.createCustomerFromWallet(ctx, qtx, params, wallet)
+}
+
+// createCustomerFromWallet creates a customer for a wallet whose address and name have already been resolved
+func (s *CustomerService) createCustomerFromWallet(
+	ctx context.Context,
+	qtx db.Querier,
+	params params.CreateCustomerFromWalletParams,
+	wallet resolvedWallet,
+) (*db.Customer, *db.CustomerWallet, error) {
+	params.WalletAddress = wallet.address
+
 	s.logger.Info("Creating new customer for wallet address",
 		zap.String("wallet_address", params.WalletAddress),
 		zap.String("product_id", params.ProductID.String()))
@@ -563,6 +638,8 @@ func (s *CustomerService) CreateCustomerFromWallet(
 			String: "Subscription Wallet",
 			Valid:  true,
 		},
+		Ens:           wallet.name,
+		EnsResolvedAt: wallet.nameResolvedAt,
 		IsPrimary: pgtype.Bool{
 			Bool:  true,
 			Valid: true,
@@ -578,7 +655,8 @@ func (s *CustomerService) CreateCustomerFromWallet(
 	return &customer, &customerWallet, err
 }
 
-// FindOrCreateCustomerWallet finds an existing wallet or creates a new one
+// FindOrCreateCustomerWallet finds an existing wallet or creates a new one. The wallet may be given by an ENS or
+// Basenames name when a name resolver is configured; new EVM wallets are created with their name.
 func (s *CustomerService) FindOrCreateCustomerWallet(
 	ctx context.Context,
 	tx pgx.Tx,
@@ -591,13 +669,30 @@ func (s *CustomerService) FindOrCreateCustomerWallet(
 		qtx = s.queries
 	}
 
+	resolved, err := s.resolveWallet(ctx, params.WalletAddress, params.NetworkType)
+	if err != nil {
+		return nil, err
+	}
+	return s.findOrCreateCustomerWallet(ctx, qtx, params, resolved)
+}
+
+// findOrCreateCustomerWallet finds or creates a customer wallet whose address and name have already been resolved.
+// Existing wallets match on the network's own address rules, so EVM addresses match whatever their casing and
+// addresses on other networks only match exactly.
+func (s *CustomerService) findOrCreateCustomerWallet(
+	ctx context.Context,
+	qtx db.Querier,
+	params params.FindOrCreateWalletParams,
+	resolved resolvedWallet,
+) (*db.CustomerWallet, error) {
+	networkType := db.NetworkType(params.NetworkType)
 	wallets, err := qtx.ListCustomerWallets(ctx, params.CustomerID)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, wallet := range wallets {
-		if strings.EqualFold(wallet.WalletAddress, params.WalletAddress) {
+		if wallet.NetworkType == networkType && sameWalletAddress(networkType, wallet.WalletAddress, resolved.address) {
 			updatedWallet, err := qtx.UpdateCustomerWalletUsageTime(ctx, wallet.ID)
 			if err != nil {
 				s.logger.Warn("Failed to update wallet usage time",
@@ -626,12 +721,14 @@ func (s *CustomerService) FindOrCreateCustomerWallet(
 
 	createWalletParams := db.CreateCustomerWalletParams{
 		CustomerID:    params.CustomerID,
-		WalletAddress: params.WalletAddress,
-		NetworkType:   db.NetworkType(params.NetworkType),
+		WalletAddress: resolved.address,
+		NetworkType:   networkType,
 		Nickname: pgtype.Text{
 			String: nickname,
 			Valid:  true,
 		},
+		Ens:           resolved.name,
+		EnsResolvedAt: resolved.nameResolvedAt,
 		IsPrimary: pgtype.Bool{
 			Bool:  params.IsPrimary || len(wallets) == 0,
 			Valid: true,
@@ -646,3 +743,140 @@ func (s *CustomerService) FindOrCreateCustomerWallet(
 	wallet, err := qtx.CreateCustomerWallet(ctx, createWalletParams)
 	return &wallet, err
 }
+
+// resolvedWallet is a wallet address together with the name to store for it should the wallet be created
+type resolvedWallet struct {
+	address        string
+	name           pgtype.Text
+	nameResolvedAt pgtype.Timestamptz
+}
+
+// resolveWallet resolves a wallet given by address or name and looks up its name. It makes network calls, so
+// callers run it before touching their transaction.
+func (s *CustomerService) resolveWallet(ctx context.Context, address, networkType string) (resolvedWallet, error) {
+	walletAddress, walletName, err := s.resolveWalletAddress(ctx, address, networkType)
+	if err != nil {
+		return resolvedWallet{}, err
+	}
+	name, nameResolvedAt := s.lookupWalletName(ctx, walletAddress, networkType, walletName)
+	return resolvedWallet{address: walletAddress, name: name, nameResolvedAt: nameResolvedAt}, nil
+}
+
+// resolveWalletAddress returns the normalized address of a wallet given by address or, for EVM wallets, by ENS
+// or Basenames name, along with the name it was given by
+func (s *CustomerService) resolveWalletAddress(ctx context.Context, address, networkType string) (string, string, error) {
+	if networkType != string(db.NetworkTypeEvm) || !IsWalletName(address) {
+		return helpers.NormalizeWalletAddress(address, networkType), "", nil
+	}
+	if s.nameResolver == nil {
+		return "", "", fmt.Errorf("%w: %s", ErrNameResolutionUnavailable, address)
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, s.nameConfig.LookupTimeout)
+	defer cancel()
+	resolved, err := s.nameResolver.ResolveName(lookupCtx, address)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve wallet name %s: %w", address, err)
+	}
+	return helpers.NormalizeWalletAddress(resolved, networkType), strings.ToLower(strings.TrimSpace(address)), nil
+}
+
+// lookupWalletName returns the name to store for a new EVM customer wallet: the name it was given by, or else its
+// primary name. The lookup is best effort; when it fails the wallet is created without a name and picked up by
+// the next refresh.
+func (s *CustomerService) lookupWalletName(ctx context.Context, address, networkType, givenName string) (pgtype.Text, pgtype.Timestamptz) {
+	if givenName != "" {
+		return pgtype.Text{String: givenName, Valid: true}, pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	}
+	if s.nameResolver == nil || networkType != string(db.NetworkTypeEvm) {
+		return pgtype.Text{}, pgtype.Timestamptz{}
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, s.nameConfig.LookupTimeout)
+	defer cancel()
+	name, err := s.nameResolver.LookupAddress(lookupCtx, address)
+	if err != nil {
+		s.logger.Warn("Failed to look up wallet name",
+			zap.String("wallet_address", address),
+			zap.Error(err))
+		return pgtype.Text{}, pgtype.Timestamptz{}
+	}
+	return pgtype.Text{String: name, Valid: name != ""}, pgtype.Timestamptz{Time: time.Now(), Valid: true}
+}
+
+// RefreshWalletNames looks up the names of EVM customer wallets that have never been looked up or were last
+// looked up longer than the refresh interval ago. A wallet keeps a name it was given by while the name still
+// points at it, even when the wallet has no primary name.
+func (s *CustomerService) RefreshWalletNames(ctx context.Context, now time.Time) (*business.WalletNameRefreshResult, error) {
+	result := &business.WalletNameRefreshResult{}
+	if s.nameResolver == nil {
+		return result, nil
+	}
+
+	wallets, err := s.queries.ListCustomerWalletsDueNameRefresh(ctx, db.ListCustomerWalletsDueNameRefreshParams{
+		ResolvedBefore: pgtype.Timestamptz{Time: now.Add(-s.nameConfig.RefreshInterval), Valid: true},
+		BatchSize:      s.nameConfig.BatchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer wallets due a name refresh: %w", err)
+	}
+
+	for _, wallet := range wallets {
+		result.Checked++
+
+		name, err := s.currentWalletName(ctx, wallet)
+		if err != nil {
+			result.Errors++
+			if errors.Is(err, ErrNameResolutionUnavailable) {
+				// Nothing else in the batch can be looked up either
+				s.logger.Warn("Name resolution unavailable, skipping wallet name refresh", zap.Error(err))
+				return result, nil
+			}
+			s.logger.Warn("Failed to refresh wallet name",
+				zap.String("wallet_id", wallet.ID.String()),
+				zap.String("wallet_address", wallet.WalletAddress),
+				zap.Error(err))
+			continue
+		}
+
+		if _, err := s.queries.UpdateCustomerWalletEns(ctx, db.UpdateCustomerWalletEnsParams{
+			ID:            wallet.ID,
+			Ens:           pgtype.Text{String: name, Valid: name != ""},
+			EnsResolvedAt: pgtype.Timestamptz{Time: now, Valid: true},
+		}); err != nil {
+			result.Errors++
+			s.logger.Error("Failed to update wallet name",
+				zap.String("wallet_id", wallet.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		if name != wallet.Ens.String {
+			result.Changed++
+		}
+	}
+
+	return result, nil
+}
+
+// currentWalletName returns a wallet's primary name, or the name it already has if that still points at it
+func (s *CustomerService) currentWalletName(ctx context.Context, wallet db.CustomerWallet) (string, error) {
+	lookupCtx, cancel := context.WithTimeout(ctx, s.nameConfig.LookupTimeout)
+	defer cancel()
+
+	name, err := s.nameResolver.LookupAddress(lookupCtx, wallet.WalletAddress)
+	if err != nil || name != "" || !wallet.Ens.Valid || wallet.Ens.String == "" {
+		return name, err
+	}
+
+	resolved, err := s.nameResolver.ResolveName(lookupCtx, wallet.Ens.String)
+	if err != nil {
+		if errors.Is(err, ErrWalletNameNotFound) || errors.Is(err, ErrInvalidWalletName) {
+			return "", nil
+		}
+		return "", err
+	}
+	if strings.EqualFold(resolved, wallet.WalletAddress) {
+		return wallet.Ens.String, nil
+	}
+	return "", nil
+}
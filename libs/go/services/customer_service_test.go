@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/cyphera/cyphera-api/libs/go/db"
@@ -19,6 +21,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -1549,3 +1552,218 @@ func TestCustomerService_ParseNetworkType(t *testing.T) {
 // - ProcessCustomerAndWallet
 // - CreateCustomerFromWallet
 // - FindOrCreateCustomerWallet
+
+func TestNormalizeWalletAddress(t *testing.T) {
+	checksummed := "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+	assert.Equal(t, checksummed, helpers.NormalizeWalletAddress("0x742d35cc6634c0532925a3b844bc454e4438f44e", "evm"))
+	assert.Equal(t, checksummed, helpers.NormalizeWalletAddress(" 0x742D35CC6634C0532925A3B844BC454E4438F44E ", "evm"))
+	assert.Equal(t, "not-an-address", helpers.NormalizeWalletAddress("not-an-address", "evm"))
+	assert.Equal(t, "7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV", helpers.NormalizeWalletAddress("7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV", "solana"))
+}
+
+func TestCustomerService_FindOrCreateCustomerWallet_Names(t *testing.T) {
+	customerID := uuid.New()
+	checksummed := "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+	config := services.DefaultWalletNameConfig()
+
+	t.Run("matches an existing wallet whatever the address casing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		mockResolver := mocks.NewMockNameResolver(ctrl)
+
+		existing := db.CustomerWallet{ID: uuid.New(), CustomerID: customerID, WalletAddress: strings.ToLower(checksummed), NetworkType: db.NetworkTypeEvm}
+		mockResolver.EXPECT().LookupAddress(gomock.Any(), checksummed).Return("", nil)
+		mockQuerier.EXPECT().ListCustomerWallets(gomock.Any(), customerID).Return([]db.CustomerWallet{existing}, nil)
+		mockQuerier.EXPECT().UpdateCustomerWalletUsageTime(gomock.Any(), existing.ID).Return(existing, nil)
+
+		service := services.NewCustomerService(mockQuerier).WithNameResolver(mockResolver, config)
+		wallet, err := service.FindOrCreateCustomerWallet(context.Background(), nil, params.FindOrCreateWalletParams{
+			CustomerID:    customerID,
+			WalletAddress: "0x" + strings.ToUpper(checksummed[2:]),
+			NetworkType:   "evm",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, wallet.ID)
+	})
+
+	t.Run("creates new wallets checksummed with their primary name", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		mockResolver := mocks.NewMockNameResolver(ctrl)
+
+		// The name is looked up before the transaction is first used
+		gomock.InOrder(
+			mockResolver.EXPECT().LookupAddress(gomock.Any(), checksummed).Return("owner.eth", nil),
+			mockQuerier.EXPECT().ListCustomerWallets(gomock.Any(), customerID).Return(nil, nil),
+			mockQuerier.EXPECT().CreateCustomerWallet(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, arg db.CreateCustomerWalletParams) (db.CustomerWallet, error) {
+					assert.Equal(t, checksummed, arg.WalletAddress)
+					assert.Equal(t, pgtype.Text{String: "owner.eth", Valid: true}, arg.Ens)
+					assert.True(t, arg.EnsResolvedAt.Valid)
+					return db.CustomerWallet{ID: uuid.New(), WalletAddress: arg.WalletAddress, Ens: arg.Ens}, nil
+				}),
+		)
+
+		service := services.NewCustomerService(mockQuerier).WithNameResolver(mockResolver, config)
+		wallet, err := service.FindOrCreateCustomerWallet(context.Background(), nil, params.FindOrCreateWalletParams{
+			CustomerID:    customerID,
+			WalletAddress: strings.ToLower(checksummed),
+			NetworkType:   "evm",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "owner.eth", wallet.Ens.String)
+	})
+
+	t.Run("matches non-EVM addresses exactly", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+
+		solanaAddress := "7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV"
+		existing := db.CustomerWallet{ID: uuid.New(), CustomerID: customerID, WalletAddress: strings.ToLower(solanaAddress), NetworkType: db.NetworkTypeSolana}
+		mockQuerier.EXPECT().ListCustomerWallets(gomock.Any(), customerID).Return([]db.CustomerWallet{existing}, nil)
+		mockQuerier.EXPECT().CreateCustomerWallet(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CreateCustomerWalletParams) (db.CustomerWallet, error) {
+				assert.Equal(t, solanaAddress, arg.WalletAddress)
+				assert.Equal(t, db.NetworkTypeSolana, arg.NetworkType)
+				return db.CustomerWallet{ID: uuid.New(), WalletAddress: arg.WalletAddress, NetworkType: arg.NetworkType}, nil
+			})
+
+		service := services.NewCustomerService(mockQuerier)
+		wallet, err := service.FindOrCreateCustomerWallet(context.Background(), nil, params.FindOrCreateWalletParams{
+			CustomerID:    customerID,
+			WalletAddress: solanaAddress,
+			NetworkType:   "solana",
+		})
+		require.NoError(t, err)
+		assert.NotEqual(t, existing.ID, wallet.ID)
+	})
+
+	t.Run("does not match a wallet on another network", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+
+		existing := db.CustomerWallet{ID: uuid.New(), CustomerID: customerID, WalletAddress: "abc123", NetworkType: db.NetworkTypeCosmos}
+		mockQuerier.EXPECT().ListCustomerWallets(gomock.Any(), customerID).Return([]db.CustomerWallet{existing}, nil)
+		mockQuerier.EXPECT().CreateCustomerWallet(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CreateCustomerWalletParams) (db.CustomerWallet, error) {
+				assert.Equal(t, db.NetworkTypePolkadot, arg.NetworkType)
+				return db.CustomerWallet{ID: uuid.New(), WalletAddress: arg.WalletAddress, NetworkType: arg.NetworkType}, nil
+			})
+
+		service := services.NewCustomerService(mockQuerier)
+		wallet, err := service.FindOrCreateCustomerWallet(context.Background(), nil, params.FindOrCreateWalletParams{
+			CustomerID:    customerID,
+			WalletAddress: "abc123",
+			NetworkType:   "polkadot",
+		})
+		require.NoError(t, err)
+		assert.NotEqual(t, existing.ID, wallet.ID)
+	})
+
+	t.Run("creates wallets given by name at the address it points at", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		mockResolver := mocks.NewMockNameResolver(ctrl)
+
+		mockResolver.EXPECT().ResolveName(gomock.Any(), "Owner.base.eth").Return(checksummed, nil)
+		mockQuerier.EXPECT().ListCustomerWallets(gomock.Any(), customerID).Return(nil, nil)
+		mockQuerier.EXPECT().CreateCustomerWallet(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CreateCustomerWalletParams) (db.CustomerWallet, error) {
+				assert.Equal(t, checksummed, arg.WalletAddress)
+				assert.Equal(t, pgtype.Text{String: "owner.base.eth", Valid: true}, arg.Ens)
+				return db.CustomerWallet{ID: uuid.New(), WalletAddress: arg.WalletAddress, Ens: arg.Ens}, nil
+			})
+
+		service := services.NewCustomerService(mockQuerier).WithNameResolver(mockResolver, config)
+		_, err := service.FindOrCreateCustomerWallet(context.Background(), nil, params.FindOrCreateWalletParams{
+			CustomerID:    customerID,
+			WalletAddress: "Owner.base.eth",
+			NetworkType:   "evm",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("creates the wallet without a name when the lookup fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		mockResolver := mocks.NewMockNameResolver(ctrl)
+
+		mockQuerier.EXPECT().ListCustomerWallets(gomock.Any(), customerID).Return(nil, nil)
+		mockResolver.EXPECT().LookupAddress(gomock.Any(), checksummed).Return("", errors.New("rpc timeout"))
+		mockQuerier.EXPECT().CreateCustomerWallet(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CreateCustomerWalletParams) (db.CustomerWallet, error) {
+				assert.False(t, arg.Ens.Valid)
+				assert.False(t, arg.EnsResolvedAt.Valid)
+				return db.CustomerWallet{ID: uuid.New(), WalletAddress: arg.WalletAddress}, nil
+			})
+
+		service := services.NewCustomerService(mockQuerier).WithNameResolver(mockResolver, config)
+		_, err := service.FindOrCreateCustomerWallet(context.Background(), nil, params.FindOrCreateWalletParams{
+			CustomerID:    customerID,
+			WalletAddress: checksummed,
+			NetworkType:   "evm",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects names without a resolver", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+
+		service := services.NewCustomerService(mockQuerier)
+		_, err := service.FindOrCreateCustomerWallet(context.Background(), nil, params.FindOrCreateWalletParams{
+			CustomerID:    customerID,
+			WalletAddress: "owner.eth",
+			NetworkType:   "evm",
+		})
+		assert.ErrorIs(t, err, services.ErrNameResolutionUnavailable)
+	})
+}
+
+func TestCustomerService_RefreshWalletNames(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	config := services.DefaultWalletNameConfig()
+
+	named := db.CustomerWallet{ID: uuid.New(), WalletAddress: "0x742d35Cc6634C0532925a3b844Bc454e4438f44e", NetworkType: db.NetworkTypeEvm}
+	givenName := db.CustomerWallet{ID: uuid.New(), WalletAddress: "0x1111111111111111111111111111111111111111", NetworkType: db.NetworkTypeEvm, Ens: pgtype.Text{String: "treasury.eth", Valid: true}}
+	stale := db.CustomerWallet{ID: uuid.New(), WalletAddress: "0x2222222222222222222222222222222222222222", NetworkType: db.NetworkTypeEvm, Ens: pgtype.Text{String: "sold.eth", Valid: true}}
+	failing := db.CustomerWallet{ID: uuid.New(), WalletAddress: "0x3333333333333333333333333333333333333333", NetworkType: db.NetworkTypeEvm}
+
+	ctrl := gomock.NewController(t)
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	mockResolver := mocks.NewMockNameResolver(ctrl)
+
+	mockQuerier.EXPECT().ListCustomerWalletsDueNameRefresh(gomock.Any(), db.ListCustomerWalletsDueNameRefreshParams{
+		ResolvedBefore: pgtype.Timestamptz{Time: now.Add(-config.RefreshInterval), Valid: true},
+		BatchSize:      config.BatchSize,
+	}).Return([]db.CustomerWallet{named, givenName, stale, failing}, nil)
+
+	// A new primary name is picked up
+	mockResolver.EXPECT().LookupAddress(gomock.Any(), named.WalletAddress).Return("owner.eth", nil)
+	// A name the wallet was given by is kept while it still points at the wallet
+	mockResolver.EXPECT().LookupAddress(gomock.Any(), givenName.WalletAddress).Return("", nil)
+	mockResolver.EXPECT().ResolveName(gomock.Any(), "treasury.eth").Return(givenName.WalletAddress, nil)
+	// A name that now points elsewhere is cleared
+	mockResolver.EXPECT().LookupAddress(gomock.Any(), stale.WalletAddress).Return("", nil)
+	mockResolver.EXPECT().ResolveName(gomock.Any(), "sold.eth").Return("0x4444444444444444444444444444444444444444", nil)
+	// A failed lookup leaves the wallet for the next run
+	mockResolver.EXPECT().LookupAddress(gomock.Any(), failing.WalletAddress).Return("", errors.New("rpc timeout"))
+
+	resolvedAt := pgtype.Timestamptz{Time: now, Valid: true}
+	mockQuerier.EXPECT().UpdateCustomerWalletEns(gomock.Any(), db.UpdateCustomerWalletEnsParams{
+		ID: named.ID, Ens: pgtype.Text{String: "owner.eth", Valid: true}, EnsResolvedAt: resolvedAt,
+	}).Return(db.CustomerWallet{}, nil)
+	mockQuerier.EXPECT().UpdateCustomerWalletEns(gomock.Any(), db.UpdateCustomerWalletEnsParams{
+		ID: givenName.ID, Ens: pgtype.Text{String: "treasury.eth", Valid: true}, EnsResolvedAt: resolvedAt,
+	}).Return(db.CustomerWallet{}, nil)
+	mockQuerier.EXPECT().UpdateCustomerWalletEns(gomock.Any(), db.UpdateCustomerWalletEnsParams{
+		ID: stale.ID, Ens: pgtype.Text{}, EnsResolvedAt: resolvedAt,
+	}).Return(db.CustomerWallet{}, nil)
+
+	service := services.NewCustomerService(mockQuerier).WithNameResolver(mockResolver, config)
+	result, err := service.RefreshWalletNames(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Checked)
+	assert.Equal(t, 2, result.Changed)
+	assert.Equal(t, 1, result.Errors)
+}
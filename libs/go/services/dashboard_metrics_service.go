@@ -61,6 +61,12 @@ const (
 	MetricTypeYearly  MetricType = "yearly"
 )
 
+// finalizedPaymentCondition matches payments p that count as revenue: payments without an on-chain transaction,
+// and crypto payments whose transaction reached its network's finality depth
+const finalizedPaymentCondition = `(p.transaction_hash IS NULL OR p.network_id IS NULL OR EXISTS (
+			SELECT 1 FROM payment_confirmations pc WHERE pc.payment_id = p.id AND pc.status = 'finalized'
+		))`
+
 // CalculateMetricsOptions provides options for metric calculation
 type CalculateMetricsOptions struct {
 	WorkspaceID uuid.UUID
@@ -183,7 +189,8 @@ func (s *DashboardMetricsService) calculateRevenueMetrics(ctx context.Context, m
 		metrics.ArrCents = pgtype.Int8{Int64: mrrCents.Int64 * 12, Valid: true}
 	}
 
-	// Calculate total revenue for the period
+	// Calculate total revenue for the period. Crypto payments only count once their transaction is
+	// finalized, since a dropped or reorged transaction rolls the payment back.
 	revenueQuery := `
 		SELECT 
 			COALESCE(SUM(amount_in_cents), 0) as total_revenue,
@@ -194,6 +201,7 @@ func (s *DashboardMetricsService) calculateRevenueMetrics(ctx context.Context, m
 			AND p.status = 'completed'
 			AND p.created_at >= $3
 			AND p.created_at < $4
+			AND ` + finalizedPaymentCondition + `
 	`
 
 	var totalRevenue, newRevenue pgtype.Int8
@@ -209,7 +217,27 @@ func (s *DashboardMetricsService) calculateRevenueMetrics(ctx context.Context, m
 		metrics.NewRevenueCents = pgtype.Int8{Int64: newRevenue.Int64, Valid: true}
 	}
 
-	// TODO: Calculate expansion and contraction revenue by comparing subscription changes
+	// Expansion and contraction come from the MRR movements ledger
+	totals, err := s.queries.GetMRRMovementTotals(ctx, db.GetMRRMovementTotalsParams{
+		WorkspaceID:       workspaceID,
+		ReportingCurrency: currency,
+		StartDate:         pgtype.Timestamptz{Time: startDate, Valid: true},
+		EndDate:           pgtype.Timestamptz{Time: endDate, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get MRR movements: %w", err)
+	}
+	var expansion, contraction int64
+	for _, total := range totals {
+		switch total.MovementType {
+		case business.MRRMovementExpansion:
+			expansion = total.MrrDeltaCents
+		case business.MRRMovementContraction:
+			contraction = -total.MrrDeltaCents
+		}
+	}
+	metrics.ExpansionRevenueCents = pgtype.Int8{Int64: expansion, Valid: true}
+	metrics.ContractionRevenueCents = pgtype.Int8{Int64: contraction, Valid: true}
 
 	return nil
 }
@@ -347,9 +375,9 @@ func (s *DashboardMetricsService) calculatePaymentMetrics(ctx context.Context, m
 			COUNT(CASE WHEN status = 'completed' THEN 1 END) as successful,
 			COUNT(CASE WHEN status = 'failed' THEN 1 END) as failed,
 			COUNT(CASE WHEN status = 'pending' THEN 1 END) as pending,
-			SUM(CASE WHEN status = 'completed' THEN amount_in_cents ELSE 0 END) as total_volume,
+			SUM(CASE WHEN status = 'completed' AND ` + finalizedPaymentCondition + ` THEN amount_in_cents ELSE 0 END) as total_volume,
 			AVG(CASE WHEN status = 'completed' THEN amount_in_cents ELSE NULL END) as avg_payment_size
-		FROM payments
+		FROM payments p
 		WHERE workspace_id = $1
 			AND currency = $2
 			AND created_at >= $3
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Caveat enforcer kinds the delegation monitor understands. Enforcer contracts are deployed per
+// environment, so their addresses are configured rather than hard-coded (see ParseCaveatEnforcers).
+const (
+	// CaveatEnforcerTimestamp bounds when a delegation may be redeemed
+	CaveatEnforcerTimestamp = "timestamp"
+	// CaveatEnforcerERC20TransferAmount caps the tokens transferred over a delegation's lifetime
+	CaveatEnforcerERC20TransferAmount = "erc20_transfer_amount"
+	// CaveatEnforcerERC20PeriodTransfer caps the tokens transferred in each period
+	CaveatEnforcerERC20PeriodTransfer = "erc20_period_transfer"
+	// CaveatEnforcerLimitedCalls caps how many times a delegation may be redeemed
+	CaveatEnforcerLimitedCalls = "limited_calls"
+)
+
+var caveatEnforcerKinds = []string{
+	CaveatEnforcerTimestamp,
+	CaveatEnforcerERC20TransferAmount,
+	CaveatEnforcerERC20PeriodTransfer,
+	CaveatEnforcerLimitedCalls,
+}
+
+// ErrInvalidCaveatTerms is returned when a caveat's terms do not match its enforcer's encoding
+var ErrInvalidCaveatTerms = errors.New("invalid caveat terms")
+
+// Type hashes of the DelegationManager's EIP-712 structs, used to derive the delegation hash it keys
+// revocations and enforcer state by
+var (
+	delegationTypeHash = crypto.Keccak256Hash([]byte("Delegation(address delegate,address delegator,bytes32 authority,Caveat[] caveats,uint256 salt)Caveat(address enforcer,bytes terms)"))
+	caveatTypeHash     = crypto.Keccak256Hash([]byte("Caveat(address enforcer,bytes terms)"))
+)
+
+// TransferAllowance is the lifetime limit set by an ERC20 transfer amount caveat
+type TransferAllowance struct {
+	Enforcer  common.Address
+	Token     common.Address
+	MaxAmount *big.Int
+}
+
+// PeriodTransferAllowance is the per-period limit set by an ERC20 period transfer caveat
+type PeriodTransferAllowance struct {
+	Enforcer       common.Address
+	Token          common.Address
+	PeriodAmount   *big.Int
+	PeriodDuration time.Duration
+	StartDate      time.Time
+}
+
+// CallLimit is the redemption limit set by a limited calls caveat
+type CallLimit struct {
+	Enforcer common.Address
+	MaxCalls *big.Int
+}
+
+// DelegationTerms are the limits a delegation's caveats place on redemptions.
+// Nil fields mean the delegation has no caveat of that kind.
+type DelegationTerms struct {
+	NotBefore      *time.Time
+	ExpiresAt      *time.Time
+	TransferAmount *TransferAllowance
+	PeriodTransfer *PeriodTransferAllowance
+	CallLimit      *CallLimit
+	// Unrecognized counts caveats whose enforcer is not configured
+	Unrecognized int
+}
+
+// ParseCaveatEnforcers parses enforcer addresses configured as comma-separated kind=address pairs,
+// for example "timestamp=0x1046...,erc20_period_transfer=0x474e..."
+func ParseCaveatEnforcers(value string) (map[common.Address]string, error) {
+	enforcers := make(map[common.Address]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kind, address, ok := strings.Cut(pair, "=")
+		kind = strings.TrimSpace(kind)
+		address = strings.TrimSpace(address)
+		if !ok || !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid caveat enforcer %q, expected kind=address", pair)
+		}
+		if !slices.Contains(caveatEnforcerKinds, kind) {
+			return nil, fmt.Errorf("unknown caveat enforcer kind %q", kind)
+		}
+		enforcers[common.HexToAddress(address)] = kind
+	}
+	return enforcers, nil
+}
+
+// ParseDelegationCaveats decodes stored delegation caveats
+func ParseDelegationCaveats(raw json.RawMessage) ([]business.CaveatStruct, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var caveats []business.CaveatStruct
+	if err := json.Unmarshal(raw, &caveats); err != nil {
+		return nil, fmt.Errorf("failed to decode delegation caveats: %w", err)
+	}
+	return caveats, nil
+}
+
+// ParseDelegationTerms decodes the terms of the caveats whose enforcers are known.
+// When a delegation has several caveats of one kind the most restrictive one wins.
+func ParseDelegationTerms(caveats []business.CaveatStruct, enforcers map[common.Address]string) (*DelegationTerms, error) {
+	terms := &DelegationTerms{}
+	for _, caveat := range caveats {
+		if !common.IsHexAddress(caveat.Enforcer) {
+			return nil, fmt.Errorf("%w: invalid enforcer address %q", ErrInvalidCaveatTerms, caveat.Enforcer)
+		}
+		enforcer := common.HexToAddress(caveat.Enforcer)
+		kind, ok := enforcers[enforcer]
+		if !ok {
+			terms.Unrecognized++
+			continue
+		}
+
+		data, err := hexutil.Decode(caveat.Terms)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s terms are not hex: %v", ErrInvalidCaveatTerms, kind, err)
+		}
+
+		switch kind {
+		case CaveatEnforcerTimestamp:
+			// uint128 earliest timestamp followed by uint128 latest timestamp; zero means unbounded
+			if len(data) != 32 {
+				return nil, fmt.Errorf("%w: %s terms must be 32 bytes, got %d", ErrInvalidCaveatTerms, kind, len(data))
+			}
+			if after := new(big.Int).SetBytes(data[:16]); after.Sign() > 0 {
+				notBefore := time.Unix(after.Int64(), 0).UTC()
+				if terms.NotBefore == nil || notBefore.After(*terms.NotBefore) {
+					terms.NotBefore = &notBefore
+				}
+			}
+			if before := new(big.Int).SetBytes(data[16:]); before.Sign() > 0 {
+				expiresAt := time.Unix(before.Int64(), 0).UTC()
+				if terms.ExpiresAt == nil || expiresAt.Before(*terms.ExpiresAt) {
+					terms.ExpiresAt = &expiresAt
+				}
+			}
+		case CaveatEnforcerERC20TransferAmount:
+			// token address followed by the uint256 maximum amount
+			if len(data) != 52 {
+				return nil, fmt.Errorf("%w: %s terms must be 52 bytes, got %d", ErrInvalidCaveatTerms, kind, len(data))
+			}
+			allowance := &TransferAllowance{
+				Enforcer:  enforcer,
+				Token:     common.BytesToAddress(data[:20]),
+				MaxAmount: new(big.Int).SetBytes(data[20:52]),
+			}
+			if terms.TransferAmount == nil || allowance.MaxAmount.Cmp(terms.TransferAmount.MaxAmount) < 0 {
+				terms.TransferAmount = allowance
+			}
+		case CaveatEnforcerERC20PeriodTransfer:
+			// token address followed by the uint256 period amount, period duration and start date
+			if len(data) != 116 {
+				return nil, fmt.Errorf("%w: %s terms must be 116 bytes, got %d", ErrInvalidCaveatTerms, kind, len(data))
+			}
+			allowance := &PeriodTransferAllowance{
+				Enforcer:       enforcer,
+				Token:          common.BytesToAddress(data[:20]),
+				PeriodAmount:   new(big.Int).SetBytes(data[20:52]),
+				PeriodDuration: time.Duration(new(big.Int).SetBytes(data[52:84]).Int64()) * time.Second,
+				StartDate:      time.Unix(new(big.Int).SetBytes(data[84:116]).Int64(), 0).UTC(),
+			}
+			if terms.PeriodTransfer == nil || allowance.PeriodAmount.Cmp(terms.PeriodTransfer.PeriodAmount) < 0 {
+				terms.PeriodTransfer = allowance
+			}
+		case CaveatEnforcerLimitedCalls:
+			// uint256 maximum number of redemptions
+			if len(data) != 32 {
+				return nil, fmt.Errorf("%w: %s terms must be 32 bytes, got %d", ErrInvalidCaveatTerms, kind, len(data))
+			}
+			limit := &CallLimit{Enforcer: enforcer, MaxCalls: new(big.Int).SetBytes(data)}
+			if terms.CallLimit == nil || limit.MaxCalls.Cmp(terms.CallLimit.MaxCalls) < 0 {
+				terms.CallLimit = limit
+			}
+		}
+	}
+	return terms, nil
+}
+
+// DelegationHash returns the hash the DelegationManager identifies a delegation by: the EIP-712
+// struct hash of the delegation without its signature
+func DelegationHash(delegate, delegator, authority string, caveats []business.CaveatStruct, salt string) (common.Hash, error) {
+	if !common.IsHexAddress(delegate) || !common.IsHexAddress(delegator) {
+		return common.Hash{}, fmt.Errorf("delegate and delegator must be addresses")
+	}
+
+	authorityBytes, err := hexutil.Decode(authority)
+	if err != nil || len(authorityBytes) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("authority must be a 32 byte hex value")
+	}
+
+	saltValue, err := parseDelegationSalt(salt)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	caveatHashes := make([]byte, 0, len(caveats)*common.HashLength)
+	for _, caveat := range caveats {
+		if !common.IsHexAddress(caveat.Enforcer) {
+			return common.Hash{}, fmt.Errorf("invalid caveat enforcer address %q", caveat.Enforcer)
+		}
+		termsBytes, err := hexutil.Decode(caveat.Terms)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("caveat terms are not hex: %w", err)
+		}
+		caveatHash := crypto.Keccak256(
+			caveatTypeHash.Bytes(),
+			common.LeftPadBytes(common.HexToAddress(caveat.Enforcer).Bytes(), 32),
+			crypto.Keccak256(termsBytes),
+		)
+		caveatHashes = append(caveatHashes, caveatHash...)
+	}
+
+	return crypto.Keccak256Hash(
+		delegationTypeHash.Bytes(),
+		common.LeftPadBytes(common.HexToAddress(delegate).Bytes(), 32),
+		common.LeftPadBytes(common.HexToAddress(delegator).Bytes(), 32),
+		authorityBytes,
+		crypto.Keccak256(caveatHashes),
+		common.LeftPadBytes(saltValue.Bytes(), 32),
+	), nil
+}
+
+// parseDelegationSalt reads a salt stored as hex (as the delegation toolkit produces it) or as a decimal string
+func parseDelegationSalt(salt string) (*big.Int, error) {
+	salt = strings.TrimSpace(salt)
+	if hexDigits, ok := strings.CutPrefix(strings.ToLower(salt), "0x"); ok {
+		if hexDigits == "" {
+			return new(big.Int), nil
+		}
+		value, ok := new(big.Int).SetString(hexDigits, 16)
+		if !ok || value.BitLen() > 256 {
+			return nil, fmt.Errorf("invalid delegation salt %q", salt)
+		}
+		return value, nil
+	}
+
+	value, ok := new(big.Int).SetString(salt, 10)
+	if !ok || value.Sign() < 0 || value.BitLen() > 256 {
+		return nil, fmt.Errorf("invalid delegation salt %q", salt)
+	}
+	return value, nil
+}
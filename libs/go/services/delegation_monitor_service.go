@@ -0,0 +1,445 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"math/big"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Delegation statuses stored on delegation_data
+const (
+	DelegationStatusActive    = "active"
+	DelegationStatusRevoked   = "revoked"
+	DelegationStatusExpired   = "expired"
+	DelegationStatusExhausted = "exhausted"
+)
+
+// Reasons a subscription needs its customer to sign a replacement delegation
+const (
+	ReauthorizationReasonRevoked            = "revoked"
+	ReauthorizationReasonExpired            = "expired"
+	ReauthorizationReasonExpiring           = "expiring"
+	ReauthorizationReasonAllowanceExhausted = "allowance_exhausted"
+)
+
+// DelegationStateReader reads the on-chain state of delegations from the DelegationManager and caveat enforcers.
+// It is implemented by BlockchainService.
+type DelegationStateReader interface {
+	IsDelegationDisabled(ctx context.Context, networkID uuid.UUID, delegationManager common.Address, delegationHash common.Hash) (bool, error)
+	GetTransferredAmount(ctx context.Context, networkID uuid.UUID, enforcer, delegationManager common.Address, delegationHash common.Hash) (*big.Int, error)
+	GetRedemptionCount(ctx context.Context, networkID uuid.UUID, enforcer, delegationManager common.Address, delegationHash common.Hash) (*big.Int, error)
+}
+
+// DelegationMonitorConfig configures the delegation monitor
+type DelegationMonitorConfig struct {
+	// DelegationManager is the contract delegations are redeemed through; on-chain checks are skipped when unset
+	DelegationManager common.Address
+	// Enforcers maps caveat enforcer addresses to the kind of caveat they enforce
+	Enforcers map[common.Address]string
+	// CheckInterval is how long a delegation goes between checks
+	CheckInterval time.Duration
+	// BatchSize caps the delegations checked and the customers emailed per run
+	BatchSize int32
+	// ReminderInterval is how long to wait before emailing a customer again
+	ReminderInterval time.Duration
+	// MaxNotifications caps the emails sent for one reauthorization
+	MaxNotifications int32
+}
+
+// DefaultDelegationMonitorConfig returns the default delegation monitor configuration, without on-chain checks
+func DefaultDelegationMonitorConfig() DelegationMonitorConfig {
+	return DelegationMonitorConfig{
+		Enforcers:        map[common.Address]string{},
+		CheckInterval:    6 * time.Hour,
+		BatchSize:        200,
+		ReminderInterval: 72 * time.Hour,
+		MaxNotifications: 3,
+	}
+}
+
+// DelegationMonitorConfigFromEnv reads the DelegationManager address from DELEGATION_MANAGER_ADDRESS and the
+// caveat enforcer addresses from DELEGATION_CAVEAT_ENFORCERS on top of the defaults
+func DelegationMonitorConfigFromEnv() (DelegationMonitorConfig, error) {
+	config := DefaultDelegationMonitorConfig()
+
+	if manager := strings.TrimSpace(os.Getenv("DELEGATION_MANAGER_ADDRESS")); manager != "" {
+		if !common.IsHexAddress(manager) {
+			return config, fmt.Errorf("DELEGATION_MANAGER_ADDRESS is not a valid address: %s", manager)
+		}
+		config.DelegationManager = common.HexToAddress(manager)
+	}
+
+	enforcers, err := ParseCaveatEnforcers(os.Getenv("DELEGATION_CAVEAT_ENFORCERS"))
+	if err != nil {
+		return config, fmt.Errorf("invalid DELEGATION_CAVEAT_ENFORCERS: %w", err)
+	}
+	config.Enforcers = enforcers
+	return config, nil
+}
+
+// DelegationMonitorService watches the delegations that pay for live subscriptions. It reads each delegation's
+// caveats and on-chain state, and asks the customer to sign a replacement before a revoked, expired or used up
+// delegation makes a redemption fail.
+type DelegationMonitorService struct {
+	queries       db.Querier
+	chain         DelegationStateReader
+	splApprovals  SplApprovalReader
+	emailService  IEmailService
+	portalService *CustomerPortalService
+	config        DelegationMonitorConfig
+	logger        *zap.Logger
+}
+
+// NewDelegationMonitorService creates a delegation monitor. chain may be nil to only check caveats, and
+// customers are only emailed when both emailService and portalService are given.
+func NewDelegationMonitorService(
+	queries db.Querier,
+	chain DelegationStateReader,
+	emailService IEmailService,
+	portalService *CustomerPortalService,
+	config DelegationMonitorConfig,
+) *DelegationMonitorService {
+	log := logger.Log
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &DelegationMonitorService{
+		queries:       queries,
+		chain:         chain,
+		emailService:  emailService,
+		portalService: portalService,
+		config:        config,
+		logger:        log,
+	}
+}
+
+// WithSplApprovals returns a copy of the monitor that checks Solana subscriptions' token account approvals with reader
+func (s *DelegationMonitorService) WithSplApprovals(reader SplApprovalReader) *DelegationMonitorService {
+	clone := *s
+	clone.splApprovals = reader
+	return &clone
+}
+
+// CheckDelegations checks the delegations not checked within the check interval, records their status and
+// opens a reauthorization for every subscription whose delegation can no longer pay for its next renewal
+func (s *DelegationMonitorService) CheckDelegations(ctx context.Context, now time.Time) (*business.DelegationCheckResult, error) {
+	rows, err := s.queries.ListDelegationsDueForCheck(ctx, db.ListDelegationsDueForCheckParams{
+		CheckedBefore: pgtype.Timestamptz{Time: now.Add(-s.config.CheckInterval), Valid: true},
+		BatchSize:     s.config.BatchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delegations due for a check: %w", err)
+	}
+
+	result := &business.DelegationCheckResult{}
+	for _, row := range rows {
+		status, reason, expiresAt, err := s.evaluateDelegation(ctx, row, now)
+		if err != nil {
+			// Still record the check so that a delegation that cannot be checked does not hold up the
+			// rest of the queue; it is tried again after the check interval
+			result.Failed++
+			s.logger.Warn("Failed to check delegation",
+				zap.String("delegation_id", row.DelegationID.String()),
+				zap.String("subscription_id", row.SubscriptionID.String()),
+				zap.Error(err))
+			status, reason, expiresAt = DelegationStatusActive, "", row.ExpiresAt
+		}
+
+		if _, err := s.queries.UpdateDelegationStatus(ctx, db.UpdateDelegationStatusParams{
+			ID:        row.DelegationID,
+			Status:    status,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			return result, fmt.Errorf("failed to record delegation status: %w", err)
+		}
+		result.Checked++
+
+		if reason == "" {
+			continue
+		}
+
+		if _, err := s.queries.OpenSubscriptionReauthorization(ctx, db.OpenSubscriptionReauthorizationParams{
+			SubscriptionID: row.SubscriptionID,
+			WorkspaceID:    row.WorkspaceID,
+			CustomerID:     row.CustomerID,
+			DelegationID:   row.DelegationID,
+			Reason:         reason,
+		}); err != nil {
+			return result, fmt.Errorf("failed to open subscription reauthorization: %w", err)
+		}
+		result.NeedsReauthorization++
+
+		s.logger.Info("Subscription needs a new delegation",
+			zap.String("subscription_id", row.SubscriptionID.String()),
+			zap.String("delegation_id", row.DelegationID.String()),
+			zap.String("delegation_status", status),
+			zap.String("reason", reason))
+	}
+
+	return result, nil
+}
+
+// evaluateDelegation works out a delegation's status and, when the subscription cannot be renewed with it,
+// the reason it needs replacing
+func (s *DelegationMonitorService) evaluateDelegation(ctx context.Context, row db.ListDelegationsDueForCheckRow, now time.Time) (string, string, pgtype.Timestamptz, error) {
+	if row.NetworkType == db.NetworkTypeSolana {
+		status, reason, err := s.evaluateSplApproval(ctx, row)
+		return status, reason, row.ExpiresAt, err
+	}
+
+	caveats, err := ParseDelegationCaveats(row.Caveats)
+	if err != nil {
+		return "", "", row.ExpiresAt, err
+	}
+	terms, err := ParseDelegationTerms(caveats, s.config.Enforcers)
+	if err != nil {
+		return "", "", row.ExpiresAt, err
+	}
+
+	var expiresAt pgtype.Timestamptz
+	if terms.ExpiresAt != nil {
+		expiresAt = pgtype.Timestamptz{Time: *terms.ExpiresAt, Valid: true}
+	}
+
+	checkChain := s.chain != nil && s.config.DelegationManager != (common.Address{})
+	var delegationHash common.Hash
+	if checkChain {
+		delegationHash, err = DelegationHash(row.Delegate, row.Delegator, row.Authority, caveats, row.Salt)
+		if err != nil {
+			return "", "", expiresAt, fmt.Errorf("failed to compute delegation hash: %w", err)
+		}
+
+		disabled, err := s.chain.IsDelegationDisabled(ctx, row.NetworkID, s.config.DelegationManager, delegationHash)
+		if err != nil {
+			return "", "", expiresAt, err
+		}
+		if disabled {
+			return DelegationStatusRevoked, ReauthorizationReasonRevoked, expiresAt, nil
+		}
+	}
+
+	if terms.ExpiresAt != nil && !terms.ExpiresAt.After(now) {
+		return DelegationStatusExpired, ReauthorizationReasonExpired, expiresAt, nil
+	}
+
+	chargeAmount := big.NewInt(int64(row.TokenAmount))
+	exhausted, err := s.allowanceExhausted(ctx, row.NetworkID, delegationHash, checkChain, terms, chargeAmount)
+	if err != nil {
+		return "", "", expiresAt, err
+	}
+	if exhausted {
+		return DelegationStatusExhausted, ReauthorizationReasonAllowanceExhausted, expiresAt, nil
+	}
+
+	// Still usable, but it will have expired by the time the subscription renews
+	if terms.ExpiresAt != nil && row.NextRedemptionDate.Valid && !terms.ExpiresAt.After(row.NextRedemptionDate.Time) {
+		return DelegationStatusActive, ReauthorizationReasonExpiring, expiresAt, nil
+	}
+
+	return DelegationStatusActive, "", expiresAt, nil
+}
+
+// evaluateSplApproval checks the delegate approval on a Solana subscription's token account. The customer
+// revokes it by approving another delegate or none, and each transfer draws down the approved amount.
+func (s *DelegationMonitorService) evaluateSplApproval(ctx context.Context, row db.ListDelegationsDueForCheckRow) (string, string, error) {
+	if s.splApprovals == nil {
+		return DelegationStatusActive, "", nil
+	}
+
+	account, err := s.splApprovals.GetSplTokenAccount(ctx, row.NetworkID, row.Authority)
+	if errors.Is(err, ErrTokenAccountNotFound) {
+		return DelegationStatusRevoked, ReauthorizationReasonRevoked, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	if account.Owner != row.Delegator || account.Delegate != row.Delegate {
+		return DelegationStatusRevoked, ReauthorizationReasonRevoked, nil
+	}
+	if account.DelegatedAmount < uint64(row.TokenAmount) {
+		return DelegationStatusExhausted, ReauthorizationReasonAllowanceExhausted, nil
+	}
+	return DelegationStatusActive, "", nil
+}
+
+// allowanceExhausted reports whether the delegation's caveats leave too little to pay for another renewal.
+// Lifetime limits are compared with what the enforcers have already let through when on-chain checks are enabled.
+func (s *DelegationMonitorService) allowanceExhausted(
+	ctx context.Context,
+	networkID uuid.UUID,
+	delegationHash common.Hash,
+	checkChain bool,
+	terms *DelegationTerms,
+	chargeAmount *big.Int,
+) (bool, error) {
+	if terms.PeriodTransfer != nil && terms.PeriodTransfer.PeriodAmount.Cmp(chargeAmount) < 0 {
+		return true, nil
+	}
+
+	if terms.TransferAmount != nil {
+		remaining := new(big.Int).Set(terms.TransferAmount.MaxAmount)
+		if checkChain {
+			spent, err := s.chain.GetTransferredAmount(ctx, networkID, terms.TransferAmount.Enforcer, s.config.DelegationManager, delegationHash)
+			if err != nil {
+				return false, err
+			}
+			remaining.Sub(remaining, spent)
+		}
+		if remaining.Cmp(chargeAmount) < 0 {
+			return true, nil
+		}
+	}
+
+	if terms.CallLimit != nil {
+		if terms.CallLimit.MaxCalls.Sign() == 0 {
+			return true, nil
+		}
+		if checkChain {
+			calls, err := s.chain.GetRedemptionCount(ctx, networkID, terms.CallLimit.Enforcer, s.config.DelegationManager, delegationHash)
+			if err != nil {
+				return false, err
+			}
+			if calls.Cmp(terms.CallLimit.MaxCalls) >= 0 {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// NotifyPendingReauthorizations emails customers a link to sign a replacement delegation, reminding them
+// after the reminder interval until the maximum number of emails has been sent
+func (s *DelegationMonitorService) NotifyPendingReauthorizations(ctx context.Context, now time.Time) (int, error) {
+	if s.emailService == nil || s.portalService == nil {
+		return 0, nil
+	}
+
+	pending, err := s.queries.ListSubscriptionReauthorizationsToNotify(ctx, db.ListSubscriptionReauthorizationsToNotifyParams{
+		NotifiedBefore:   pgtype.Timestamptz{Time: now.Add(-s.config.ReminderInterval), Valid: true},
+		MaxNotifications: s.config.MaxNotifications,
+		BatchSize:        s.config.BatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list reauthorizations to notify: %w", err)
+	}
+
+	notified := 0
+	for _, reauth := range pending {
+		link, err := s.reauthorizationLink(ctx, reauth)
+		if err != nil {
+			s.logger.Warn("Failed to create reauthorization link",
+				zap.String("reauthorization_id", reauth.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		if link == "" {
+			return notified, fmt.Errorf("customer portal URL is not configured")
+		}
+
+		err = s.emailService.SendTransactionalEmail(ctx, params.TransactionalEmailParams{
+			WorkspaceID: reauth.WorkspaceID,
+			To:          []string{reauth.CustomerEmail.String},
+			Subject:     fmt.Sprintf("Action needed: re-authorize your %s subscription", reauth.ProductName),
+			HTMLContent: buildReauthorizationEmailHTML(reauth, link),
+			TextContent: buildReauthorizationEmailText(reauth, link),
+			Tags: map[string]interface{}{
+				"category": "subscription_reauthorization",
+			},
+		})
+		if err != nil {
+			s.logger.Warn("Failed to send reauthorization email",
+				zap.String("reauthorization_id", reauth.ID.String()),
+				zap.String("subscription_id", reauth.SubscriptionID.String()),
+				zap.Error(err))
+			continue
+		}
+
+		if err := s.queries.MarkSubscriptionReauthorizationNotified(ctx, reauth.ID); err != nil {
+			s.logger.Warn("Failed to mark reauthorization as notified",
+				zap.String("reauthorization_id", reauth.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		notified++
+	}
+
+	return notified, nil
+}
+
+// reauthorizationLink creates a portal link that opens straight onto re-signing the subscription's delegation
+func (s *DelegationMonitorService) reauthorizationLink(ctx context.Context, reauth db.ListSubscriptionReauthorizationsToNotifyRow) (string, error) {
+	_, token, err := s.portalService.CreateSession(ctx, params.CreateCustomerPortalSessionParams{
+		WorkspaceID: reauth.WorkspaceID,
+		CustomerID:  reauth.CustomerID,
+		TTL:         MaxCustomerPortalSessionTTL,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sessionURL := s.portalService.SessionURL(token)
+	if sessionURL == "" {
+		return "", nil
+	}
+	return sessionURL + "&reauthorize=" + url.QueryEscape(reauth.SubscriptionID.String()), nil
+}
+
+// describeReauthorizationReason explains to the customer why their delegation needs replacing
+func describeReauthorizationReason(reason string) string {
+	switch reason {
+	case ReauthorizationReasonRevoked:
+		return "the payment authorization for your wallet has been revoked"
+	case ReauthorizationReasonExpired:
+		return "the payment authorization you signed has expired"
+	case ReauthorizationReasonExpiring:
+		return "the payment authorization you signed expires before your next payment"
+	case ReauthorizationReasonAllowanceExhausted:
+		return "the payment authorization you signed does not cover your next payment"
+	default:
+		return "the payment authorization you signed can no longer be used"
+	}
+}
+
+// buildReauthorizationEmailHTML renders the HTML body of a reauthorization email
+func buildReauthorizationEmailHTML(reauth db.ListSubscriptionReauthorizationsToNotifyRow, link string) string {
+	var b strings.Builder
+	if reauth.CustomerName.Valid && reauth.CustomerName.String != "" {
+		fmt.Fprintf(&b, "<p>Hi %s,</p>", html.EscapeString(reauth.CustomerName.String))
+	}
+	fmt.Fprintf(&b, "<p>Your <strong>%s</strong> subscription with <strong>%s</strong> cannot be renewed because %s.</p>",
+		html.EscapeString(reauth.ProductName), html.EscapeString(reauth.WorkspaceName), describeReauthorizationReason(reauth.Reason))
+	fmt.Fprintf(&b, `<p><a href="%s">Sign a new authorization</a> to keep your subscription running. Your plan and billing dates stay the same.</p>`,
+		html.EscapeString(link))
+	fmt.Fprintf(&b, "<p>This link is valid for %d hours.</p>", int(MaxCustomerPortalSessionTTL.Hours()))
+	return b.String()
+}
+
+// buildReauthorizationEmailText renders the plain text body of a reauthorization email
+func buildReauthorizationEmailText(reauth db.ListSubscriptionReauthorizationsToNotifyRow, link string) string {
+	var b strings.Builder
+	if reauth.CustomerName.Valid && reauth.CustomerName.String != "" {
+		fmt.Fprintf(&b, "Hi %s,\n\n", reauth.CustomerName.String)
+	}
+	fmt.Fprintf(&b, "Your %s subscription with %s cannot be renewed because %s.\n\n",
+		reauth.ProductName, reauth.WorkspaceName, describeReauthorizationReason(reauth.Reason))
+	fmt.Fprintf(&b, "Sign a new authorization to keep your subscription running. Your plan and billing dates stay the same:\n%s\n\n", link)
+	fmt.Fprintf(&b, "This link is valid for %d hours.\n", int(MaxCustomerPortalSessionTTL.Hours()))
+	return b.String()
+}
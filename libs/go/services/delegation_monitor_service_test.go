@@ -0,0 +1,443 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	timestampEnforcer      = common.HexToAddress("0x1046bb45C8d673d4ea75321280DB34899413c069")
+	transferAmountEnforcer = common.HexToAddress("0xf100b0819427117EcF76Ed94B358B1A5b5C6D2Fc")
+	periodTransferEnforcer = common.HexToAddress("0x474e3Ae7E169e940607cC624Da8A15Eb120139aB")
+	delegationManager      = common.HexToAddress("0xdb9B1e94B5b69Df7e401DDbedE43491141047dB3")
+	usdcToken              = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+)
+
+func testEnforcers() map[common.Address]string {
+	return map[common.Address]string{
+		timestampEnforcer:      services.CaveatEnforcerTimestamp,
+		transferAmountEnforcer: services.CaveatEnforcerERC20TransferAmount,
+		periodTransferEnforcer: services.CaveatEnforcerERC20PeriodTransfer,
+	}
+}
+
+func word(value int64, size int) []byte {
+	return common.LeftPadBytes(big.NewInt(value).Bytes(), size)
+}
+
+func timestampCaveat(notBefore, expiresAt int64) business.CaveatStruct {
+	terms := append(word(notBefore, 16), word(expiresAt, 16)...)
+	return business.CaveatStruct{Enforcer: timestampEnforcer.Hex(), Terms: hexutil.Encode(terms)}
+}
+
+func transferAmountCaveat(maxAmount int64) business.CaveatStruct {
+	terms := append(usdcToken.Bytes(), word(maxAmount, 32)...)
+	return business.CaveatStruct{Enforcer: transferAmountEnforcer.Hex(), Terms: hexutil.Encode(terms)}
+}
+
+func periodTransferCaveat(periodAmount, periodSeconds, startDate int64) business.CaveatStruct {
+	terms := append(usdcToken.Bytes(), word(periodAmount, 32)...)
+	terms = append(terms, word(periodSeconds, 32)...)
+	terms = append(terms, word(startDate, 32)...)
+	return business.CaveatStruct{Enforcer: periodTransferEnforcer.Hex(), Terms: hexutil.Encode(terms)}
+}
+
+// fakeDelegationChain answers the delegation monitor's on-chain reads with fixed data
+type fakeDelegationChain struct {
+	disabled    bool
+	transferred *big.Int
+	err         error
+}
+
+func (c *fakeDelegationChain) IsDelegationDisabled(ctx context.Context, networkID uuid.UUID, manager common.Address, hash common.Hash) (bool, error) {
+	return c.disabled, c.err
+}
+
+func (c *fakeDelegationChain) GetTransferredAmount(ctx context.Context, networkID uuid.UUID, enforcer, manager common.Address, hash common.Hash) (*big.Int, error) {
+	if c.transferred == nil {
+		return new(big.Int), c.err
+	}
+	return c.transferred, c.err
+}
+
+func (c *fakeDelegationChain) GetRedemptionCount(ctx context.Context, networkID uuid.UUID, enforcer, manager common.Address, hash common.Hash) (*big.Int, error) {
+	return new(big.Int), c.err
+}
+
+func TestParseCaveatEnforcers(t *testing.T) {
+	enforcers, err := services.ParseCaveatEnforcers(" timestamp=" + timestampEnforcer.Hex() + ", erc20_period_transfer=" + periodTransferEnforcer.Hex() + ",")
+	require.NoError(t, err)
+	assert.Equal(t, services.CaveatEnforcerTimestamp, enforcers[timestampEnforcer])
+	assert.Equal(t, services.CaveatEnforcerERC20PeriodTransfer, enforcers[periodTransferEnforcer])
+
+	_, err = services.ParseCaveatEnforcers("allowed_targets=" + timestampEnforcer.Hex())
+	assert.Error(t, err)
+
+	_, err = services.ParseCaveatEnforcers("timestamp")
+	assert.Error(t, err)
+}
+
+func TestParseDelegationTerms(t *testing.T) {
+	t.Run("reads expiry and allowances, keeping the tightest limit", func(t *testing.T) {
+		caveats := []business.CaveatStruct{
+			timestampCaveat(1700000000, 1800000000),
+			timestampCaveat(0, 1750000000),
+			transferAmountCaveat(50_000_000),
+			periodTransferCaveat(10_000_000, 30*24*3600, 1700000000),
+			{Enforcer: "0x00000000000000000000000000000000000000aa", Terms: "0x"},
+		}
+
+		terms, err := services.ParseDelegationTerms(caveats, testEnforcers())
+		require.NoError(t, err)
+		require.NotNil(t, terms.NotBefore)
+		assert.Equal(t, int64(1700000000), terms.NotBefore.Unix())
+		require.NotNil(t, terms.ExpiresAt)
+		assert.Equal(t, int64(1750000000), terms.ExpiresAt.Unix())
+		require.NotNil(t, terms.TransferAmount)
+		assert.Equal(t, usdcToken, terms.TransferAmount.Token)
+		assert.Equal(t, big.NewInt(50_000_000), terms.TransferAmount.MaxAmount)
+		require.NotNil(t, terms.PeriodTransfer)
+		assert.Equal(t, 30*24*time.Hour, terms.PeriodTransfer.PeriodDuration)
+		assert.Equal(t, 1, terms.Unrecognized)
+	})
+
+	t.Run("rejects malformed terms", func(t *testing.T) {
+		_, err := services.ParseDelegationTerms([]business.CaveatStruct{{Enforcer: timestampEnforcer.Hex(), Terms: "0x01"}}, testEnforcers())
+		assert.ErrorIs(t, err, services.ErrInvalidCaveatTerms)
+	})
+}
+
+func TestDelegationHash(t *testing.T) {
+	delegate := "0x2222222222222222222222222222222222222222"
+	delegator := "0x1111111111111111111111111111111111111111"
+	authority := "0x" + strings.Repeat("f", 64)
+
+	hash, err := services.DelegationHash(delegate, delegator, authority, nil, "0x1a2b")
+	require.NoError(t, err)
+
+	decimalSalt, err := services.DelegationHash(delegate, delegator, authority, nil, "6699")
+	require.NoError(t, err)
+	assert.Equal(t, hash, decimalSalt, "hex and decimal salts of the same value hash alike")
+
+	withCaveat, err := services.DelegationHash(delegate, delegator, authority, []business.CaveatStruct{transferAmountCaveat(1)}, "0x1a2b")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, withCaveat)
+
+	_, err = services.DelegationHash(delegate, delegator, "0x01", nil, "0x1a2b")
+	assert.Error(t, err)
+	_, err = services.DelegationHash(delegate, delegator, authority, nil, "salt")
+	assert.Error(t, err)
+}
+
+func TestDelegationMonitorService_CheckDelegations(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	nextRedemption := now.Add(10 * 24 * time.Hour)
+
+	dueRow := func(caveats ...business.CaveatStruct) db.ListDelegationsDueForCheckRow {
+		raw, err := json.Marshal(caveats)
+		require.NoError(t, err)
+		return db.ListDelegationsDueForCheckRow{
+			DelegationID:       uuid.New(),
+			Delegate:           "0x2222222222222222222222222222222222222222",
+			Delegator:          "0x1111111111111111111111111111111111111111",
+			Authority:          "0x" + strings.Repeat("f", 64),
+			Caveats:            raw,
+			Salt:               "0x01",
+			SubscriptionID:     uuid.New(),
+			WorkspaceID:        uuid.New(),
+			CustomerID:         uuid.New(),
+			TokenAmount:        20_000_000,
+			NextRedemptionDate: pgtype.Timestamptz{Time: nextRedemption, Valid: true},
+			NetworkID:          uuid.New(),
+		}
+	}
+
+	config := services.DefaultDelegationMonitorConfig()
+	config.DelegationManager = delegationManager
+	config.Enforcers = testEnforcers()
+
+	tests := []struct {
+		name       string
+		row        db.ListDelegationsDueForCheckRow
+		chain      *fakeDelegationChain
+		wantStatus string
+		wantReason string
+	}{
+		{
+			name:       "usable delegation",
+			row:        dueRow(timestampCaveat(0, now.Add(90*24*time.Hour).Unix()), transferAmountCaveat(100_000_000)),
+			chain:      &fakeDelegationChain{transferred: big.NewInt(40_000_000)},
+			wantStatus: services.DelegationStatusActive,
+		},
+		{
+			name:       "revoked on chain",
+			row:        dueRow(),
+			chain:      &fakeDelegationChain{disabled: true},
+			wantStatus: services.DelegationStatusRevoked,
+			wantReason: services.ReauthorizationReasonRevoked,
+		},
+		{
+			name:       "expired",
+			row:        dueRow(timestampCaveat(0, now.Add(-time.Hour).Unix())),
+			chain:      &fakeDelegationChain{},
+			wantStatus: services.DelegationStatusExpired,
+			wantReason: services.ReauthorizationReasonExpired,
+		},
+		{
+			name:       "expires before the next renewal",
+			row:        dueRow(timestampCaveat(0, now.Add(5*24*time.Hour).Unix())),
+			chain:      &fakeDelegationChain{},
+			wantStatus: services.DelegationStatusActive,
+			wantReason: services.ReauthorizationReasonExpiring,
+		},
+		{
+			name:       "lifetime allowance used up",
+			row:        dueRow(transferAmountCaveat(100_000_000)),
+			chain:      &fakeDelegationChain{transferred: big.NewInt(90_000_000)},
+			wantStatus: services.DelegationStatusExhausted,
+			wantReason: services.ReauthorizationReasonAllowanceExhausted,
+		},
+		{
+			name:       "period allowance below the subscription price",
+			row:        dueRow(periodTransferCaveat(10_000_000, 30*24*3600, now.Unix())),
+			chain:      &fakeDelegationChain{},
+			wantStatus: services.DelegationStatusExhausted,
+			wantReason: services.ReauthorizationReasonAllowanceExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockQuerier := mocks.NewMockQuerier(ctrl)
+			service := services.NewDelegationMonitorService(mockQuerier, tt.chain, nil, nil, config)
+
+			mockQuerier.EXPECT().ListDelegationsDueForCheck(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, arg db.ListDelegationsDueForCheckParams) ([]db.ListDelegationsDueForCheckRow, error) {
+					assert.Equal(t, now.Add(-config.CheckInterval), arg.CheckedBefore.Time)
+					return []db.ListDelegationsDueForCheckRow{tt.row}, nil
+				})
+			mockQuerier.EXPECT().UpdateDelegationStatus(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, arg db.UpdateDelegationStatusParams) (db.DelegationDatum, error) {
+					assert.Equal(t, tt.row.DelegationID, arg.ID)
+					assert.Equal(t, tt.wantStatus, arg.Status)
+					return db.DelegationDatum{ID: arg.ID, Status: arg.Status}, nil
+				})
+			if tt.wantReason != "" {
+				mockQuerier.EXPECT().OpenSubscriptionReauthorization(gomock.Any(), db.OpenSubscriptionReauthorizationParams{
+					SubscriptionID: tt.row.SubscriptionID,
+					WorkspaceID:    tt.row.WorkspaceID,
+					CustomerID:     tt.row.CustomerID,
+					DelegationID:   tt.row.DelegationID,
+					Reason:         tt.wantReason,
+				}).Return(db.SubscriptionReauthorization{ID: uuid.New()}, nil)
+			}
+
+			result, err := service.CheckDelegations(context.Background(), now)
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.Checked)
+			if tt.wantReason != "" {
+				assert.Equal(t, 1, result.NeedsReauthorization)
+			} else {
+				assert.Zero(t, result.NeedsReauthorization)
+			}
+		})
+	}
+
+	t.Run("records the check when the chain cannot be read", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		service := services.NewDelegationMonitorService(mockQuerier, &fakeDelegationChain{err: errors.New("rpc unavailable")}, nil, nil, config)
+		row := dueRow()
+
+		mockQuerier.EXPECT().ListDelegationsDueForCheck(gomock.Any(), gomock.Any()).Return([]db.ListDelegationsDueForCheckRow{row}, nil)
+		mockQuerier.EXPECT().UpdateDelegationStatus(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.UpdateDelegationStatusParams) (db.DelegationDatum, error) {
+				assert.Equal(t, services.DelegationStatusActive, arg.Status)
+				return db.DelegationDatum{ID: arg.ID}, nil
+			})
+
+		result, err := service.CheckDelegations(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Failed)
+		assert.Zero(t, result.NeedsReauthorization)
+	})
+}
+
+// fakeSplApprovals answers the delegation monitor's token account reads with a fixed account
+type fakeSplApprovals struct {
+	account *business.SplTokenAccount
+	err     error
+}
+
+func (f *fakeSplApprovals) GetSplTokenAccount(ctx context.Context, networkID uuid.UUID, address string) (*business.SplTokenAccount, error) {
+	return f.account, f.err
+}
+
+func TestDelegationMonitorService_CheckSplApprovals(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	const (
+		delegate     = "UtHvQFRZn2TuLrsUfS3h92fPWWJzhLQov92L321ZctB"
+		customer     = "4GfkX2JkJmHVoSaJRMeRo97rkGjHMd6CHw9aXt2uPk5X"
+		tokenAccount = "EWPRzu9UWUvcrmdc4DzU4E6jgYsegPz1qQttjT9HRDwt"
+	)
+
+	row := db.ListDelegationsDueForCheckRow{
+		DelegationID:   uuid.New(),
+		Delegate:       delegate,
+		Delegator:      customer,
+		Authority:      tokenAccount,
+		Caveats:        json.RawMessage("[]"),
+		SubscriptionID: uuid.New(),
+		WorkspaceID:    uuid.New(),
+		CustomerID:     uuid.New(),
+		TokenAmount:    20_000_000,
+		NetworkID:      uuid.New(),
+		NetworkType:    db.NetworkTypeSolana,
+	}
+	approved := func(delegatedAmount uint64) *business.SplTokenAccount {
+		return &business.SplTokenAccount{Address: tokenAccount, Owner: customer, Delegate: delegate, DelegatedAmount: delegatedAmount}
+	}
+
+	tests := []struct {
+		name       string
+		approvals  *fakeSplApprovals
+		wantStatus string
+		wantReason string
+	}{
+		{
+			name:       "approval covers the next renewal",
+			approvals:  &fakeSplApprovals{account: approved(60_000_000)},
+			wantStatus: services.DelegationStatusActive,
+		},
+		{
+			name:       "approval moved to another delegate",
+			approvals:  &fakeSplApprovals{account: &business.SplTokenAccount{Address: tokenAccount, Owner: customer}},
+			wantStatus: services.DelegationStatusRevoked,
+			wantReason: services.ReauthorizationReasonRevoked,
+		},
+		{
+			name:       "token account closed",
+			approvals:  &fakeSplApprovals{err: services.ErrTokenAccountNotFound},
+			wantStatus: services.DelegationStatusRevoked,
+			wantReason: services.ReauthorizationReasonRevoked,
+		},
+		{
+			name:       "approved amount drawn down",
+			approvals:  &fakeSplApprovals{account: approved(10_000_000)},
+			wantStatus: services.DelegationStatusExhausted,
+			wantReason: services.ReauthorizationReasonAllowanceExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockQuerier := mocks.NewMockQuerier(ctrl)
+			config := services.DefaultDelegationMonitorConfig()
+			config.DelegationManager = delegationManager
+			// The EVM chain must not be consulted for Solana subscriptions
+			chain := &fakeDelegationChain{err: errors.New("unexpected EVM read")}
+			service := services.NewDelegationMonitorService(mockQuerier, chain, nil, nil, config).WithSplApprovals(tt.approvals)
+
+			mockQuerier.EXPECT().ListDelegationsDueForCheck(gomock.Any(), gomock.Any()).Return([]db.ListDelegationsDueForCheckRow{row}, nil)
+			mockQuerier.EXPECT().UpdateDelegationStatus(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, arg db.UpdateDelegationStatusParams) (db.DelegationDatum, error) {
+					assert.Equal(t, tt.wantStatus, arg.Status)
+					return db.DelegationDatum{ID: arg.ID, Status: arg.Status}, nil
+				})
+			if tt.wantReason != "" {
+				mockQuerier.EXPECT().OpenSubscriptionReauthorization(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, arg db.OpenSubscriptionReauthorizationParams) (db.SubscriptionReauthorization, error) {
+						assert.Equal(t, tt.wantReason, arg.Reason)
+						return db.SubscriptionReauthorization{ID: uuid.New()}, nil
+					})
+			}
+
+			result, err := service.CheckDelegations(context.Background(), now)
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.Checked)
+			assert.Zero(t, result.Failed)
+		})
+	}
+}
+
+func TestDelegationMonitorService_NotifyPendingReauthorizations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	mockEmail := mocks.NewMockEmailService(ctrl)
+	portal := services.NewCustomerPortalService(mockQuerier, nil, "https://pay.example.com/portal")
+	config := services.DefaultDelegationMonitorConfig()
+	service := services.NewDelegationMonitorService(mockQuerier, nil, mockEmail, portal, config)
+	ctx := context.Background()
+	now := time.Now()
+
+	reauth := db.ListSubscriptionReauthorizationsToNotifyRow{
+		ID:             uuid.New(),
+		SubscriptionID: uuid.New(),
+		WorkspaceID:    uuid.New(),
+		CustomerID:     uuid.New(),
+		Reason:         services.ReauthorizationReasonRevoked,
+		CustomerEmail:  pgtype.Text{String: "ada@example.com", Valid: true},
+		CustomerName:   pgtype.Text{String: "Ada", Valid: true},
+		WorkspaceName:  "Acme",
+		ProductName:    "Pro Plan",
+	}
+
+	mockQuerier.EXPECT().ListSubscriptionReauthorizationsToNotify(ctx, db.ListSubscriptionReauthorizationsToNotifyParams{
+		NotifiedBefore:   pgtype.Timestamptz{Time: now.Add(-config.ReminderInterval), Valid: true},
+		MaxNotifications: config.MaxNotifications,
+		BatchSize:        config.BatchSize,
+	}).Return([]db.ListSubscriptionReauthorizationsToNotifyRow{reauth}, nil)
+	mockQuerier.EXPECT().IsCustomerInWorkspace(ctx, db.IsCustomerInWorkspaceParams{
+		WorkspaceID: reauth.WorkspaceID,
+		CustomerID:  reauth.CustomerID,
+	}).Return(true, nil)
+	mockQuerier.EXPECT().GetCustomerPortalSettings(ctx, reauth.WorkspaceID).Return(db.CustomerPortalSetting{}, pgx.ErrNoRows)
+	mockQuerier.EXPECT().CreateCustomerPortalSession(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.CreateCustomerPortalSessionParams) (db.CustomerPortalSession, error) {
+			assert.WithinDuration(t, time.Now().Add(services.MaxCustomerPortalSessionTTL), arg.ExpiresAt.Time, time.Minute)
+			return db.CustomerPortalSession{ID: uuid.New()}, nil
+		})
+	mockEmail.EXPECT().SendTransactionalEmail(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, email params.TransactionalEmailParams) error {
+			assert.Equal(t, []string{"ada@example.com"}, email.To)
+			assert.Contains(t, email.Subject, "Pro Plan")
+			assert.Contains(t, email.TextContent, "https://pay.example.com/portal?session=cps_")
+			assert.Contains(t, email.TextContent, "&reauthorize="+reauth.SubscriptionID.String())
+			assert.Contains(t, email.HTMLContent, "has been revoked")
+			return nil
+		})
+	mockQuerier.EXPECT().MarkSubscriptionReauthorizationNotified(ctx, reauth.ID).Return(nil)
+
+	notified, err := service.NotifyPendingReauthorizations(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, notified)
+}
@@ -116,6 +116,14 @@ func (s *EmailService) SendTransactionalEmail(ctx context.Context, emailParams p
 		resendParams.ReplyTo = *emailParams.ReplyTo
 	}
 
+	for _, attachment := range emailParams.Attachments {
+		resendParams.Attachments = append(resendParams.Attachments, &resend.Attachment{
+			Content:     attachment.Content,
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+		})
+	}
+
 	// Convert tags if provided
 	if len(emailParams.Tags) > 0 {
 		resendParams.Tags = make([]resend.Tag, 0, len(emailParams.Tags))
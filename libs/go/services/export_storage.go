@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	awsclient "github.com/cyphera/cyphera-api/libs/go/client/aws"
+)
+
+// Export storage backends
+const (
+	ExportStorageLocal = "local"
+	ExportStorageS3    = "s3"
+)
+
+// ErrExportFileNotFound is returned when an export file is missing from storage
+var ErrExportFileNotFound = errors.New("export file not found")
+
+// ExportStorage stores the files written by analytics export jobs
+type ExportStorage interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// ExportStorageConfig selects and configures an export storage backend
+type ExportStorageConfig struct {
+	Backend string // local or s3; deployed stages use s3
+
+	// Local backend
+	Directory string
+
+	// S3 backend
+	S3 awsclient.S3ClientConfig
+	// S3Prefix is prepended to every object key
+	S3Prefix string
+}
+
+// ExportStorageConfigFromEnv reads the export storage configuration from EXPORT_STORAGE_BACKEND,
+// EXPORT_STORAGE_DIR and the EXPORT_S3_* variables
+func ExportStorageConfigFromEnv() ExportStorageConfig {
+	pathStyle, _ := strconv.ParseBool(os.Getenv("EXPORT_S3_FORCE_PATH_STYLE"))
+	return ExportStorageConfig{
+		Backend:   os.Getenv("EXPORT_STORAGE_BACKEND"),
+		Directory: os.Getenv("EXPORT_STORAGE_DIR"),
+		S3: awsclient.S3ClientConfig{
+			Bucket:       os.Getenv("EXPORT_S3_BUCKET"),
+			Endpoint:     os.Getenv("EXPORT_S3_ENDPOINT"),
+			Region:       os.Getenv("EXPORT_S3_REGION"),
+			UsePathStyle: pathStyle,
+		},
+		S3Prefix: os.Getenv("EXPORT_S3_PREFIX"),
+	}
+}
+
+// NewExportStorage creates the configured export storage backend. The backend must be set explicitly, and the
+// local backend needs a directory.
+func NewExportStorage(ctx context.Context, config ExportStorageConfig) (ExportStorage, error) {
+	switch config.Backend {
+	case "":
+		return nil, fmt.Errorf("export storage backend is not set, must be 'local' or 's3'")
+	case ExportStorageLocal:
+		if config.Directory == "" {
+			return nil, fmt.Errorf("export storage directory is required for the local backend")
+		}
+		return NewLocalExportStorage(config.Directory), nil
+	case ExportStorageS3:
+		client, err := awsclient.NewS3Client(ctx, config.S3)
+		if err != nil {
+			return nil, err
+		}
+		return NewS3ExportStorage(client, config.S3Prefix), nil
+	default:
+		return nil, fmt.Errorf("unsupported export storage backend %q, must be 'local' or 's3'", config.Backend)
+	}
+}
+
+// LocalExportStorage stores export files on the local filesystem
+type LocalExportStorage struct {
+	dir string
+}
+
+// NewLocalExportStorage creates a storage backend rooted at dir
+func NewLocalExportStorage(dir string) *LocalExportStorage {
+	return &LocalExportStorage{dir: dir}
+}
+
+// Put writes data to the file for key, creating directories as needed
+func (s *LocalExportStorage) Put(ctx context.Context, key, contentType string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial export
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	return nil
+}
+
+// Get opens the file for key
+func (s *LocalExportStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrExportFileNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open export file: %w", err)
+	}
+	return file, nil
+}
+
+// path maps a key to a file under the storage directory, rejecting keys that escape it
+func (s *LocalExportStorage) path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, filepath.Clean(s.dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid export storage key %q", key)
+	}
+	return path, nil
+}
+
+// S3ExportStorage stores export files in an S3 or S3-compatible bucket
+type S3ExportStorage struct {
+	client *awsclient.S3Client
+	prefix string
+}
+
+// NewS3ExportStorage creates a storage backend writing objects under prefix
+func NewS3ExportStorage(client *awsclient.S3Client, prefix string) *S3ExportStorage {
+	return &S3ExportStorage{client: client, prefix: strings.Trim(prefix, "/")}
+}
+
+// Put uploads data as the object for key
+func (s *S3ExportStorage) Put(ctx context.Context, key, contentType string, data []byte) error {
+	return s.client.PutObject(ctx, s.objectKey(key), contentType, data)
+}
+
+// Get downloads the object for key
+func (s *S3ExportStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, err := s.client.GetObject(ctx, s.objectKey(key))
+	if errors.Is(err, awsclient.ErrS3ObjectNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrExportFileNotFound, key)
+	}
+	return body, err
+}
+
+func (s *S3ExportStorage) objectKey(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
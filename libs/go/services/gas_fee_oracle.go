@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// gasFeeQuoteTTL is how long a network's fee quote is reused, about one Ethereum block
+	gasFeeQuoteTTL = 12 * time.Second
+	// feeHistoryBlocks is the number of recent blocks sampled for base fees and tips
+	feeHistoryBlocks = 20
+	// feeHistoryTipPercentile is the tip percentile, within each block, used as the priority fee
+	feeHistoryTipPercentile = 50
+)
+
+// ErrNoGasFeeClient is returned when the oracle has no RPC connection for a network
+var ErrNoGasFeeClient = errors.New("no RPC client for network")
+
+// opStackGasPriceOracle is the OP-stack predeploy that prices L1 data fees
+var opStackGasPriceOracle = common.HexToAddress("0x420000000000000000000000000000000000000F")
+
+// getL1FeeSelector is the selector of GasPriceOracle.getL1Fee(bytes)
+var getL1FeeSelector = crypto.Keccak256([]byte("getL1Fee(bytes)"))[:4]
+
+// opStackNetworkTypes are the networks that charge an L1 data fee on top of L2 execution gas.
+// Arbitrum folds its L1 cost into the gas units returned by eth_estimateGas instead.
+var opStackNetworkTypes = map[db.CircleNetworkType]bool{
+	db.CircleNetworkTypeOP:          true,
+	db.CircleNetworkTypeOPSEPOLIA:   true,
+	db.CircleNetworkTypeBASE:        true,
+	db.CircleNetworkTypeBASESEPOLIA: true,
+	db.CircleNetworkTypeUNI:         true,
+	db.CircleNetworkTypeUNISEPOLIA:  true,
+}
+
+// GasFeeClient is the part of an Ethereum RPC client the gas fee oracle uses
+type GasFeeClient interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// GasFeeClientProvider returns the RPC client connected to a network
+type GasFeeClientProvider interface {
+	GasFeeClient(networkID uuid.UUID) (GasFeeClient, bool)
+}
+
+// GasFeeOracle prices gas from live network data: base fees and tips from eth_feeHistory, gas units
+// from eth_estimateGas and L1 data fees from the OP-stack gas price oracle. Fee quotes are cached
+// per network.
+type GasFeeOracle struct {
+	clients GasFeeClientProvider
+	logger  *zap.Logger
+	ttl     time.Duration
+
+	mu     sync.Mutex
+	quotes map[uuid.UUID]*business.GasFeeQuote
+}
+
+// NewGasFeeOracle creates a gas fee oracle using the given networks' RPC clients
+func NewGasFeeOracle(clients GasFeeClientProvider) *GasFeeOracle {
+	return &GasFeeOracle{
+		clients: clients,
+		logger:  logger.Log,
+		ttl:     gasFeeQuoteTTL,
+		quotes:  make(map[uuid.UUID]*business.GasFeeQuote),
+	}
+}
+
+// Quote returns the network's current gas pricing, from cache when it is recent enough
+func (o *GasFeeOracle) Quote(ctx context.Context, networkID uuid.UUID) (*business.GasFeeQuote, error) {
+	o.mu.Lock()
+	cached, ok := o.quotes[networkID]
+	o.mu.Unlock()
+	if ok && time.Since(cached.FetchedAt) < o.ttl {
+		return cached, nil
+	}
+
+	client, ok := o.clients.GasFeeClient(networkID)
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrNoGasFeeClient, networkID)
+	}
+
+	quote, err := o.fetchQuote(ctx, client, networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	o.quotes[networkID] = quote
+	o.mu.Unlock()
+
+	return quote, nil
+}
+
+// EstimateGasUnits simulates a transaction with eth_estimateGas
+func (o *GasFeeOracle) EstimateGasUnits(ctx context.Context, networkID uuid.UUID, call params.GasEstimateCall) (uint64, error) {
+	client, ok := o.clients.GasFeeClient(networkID)
+	if !ok {
+		return 0, fmt.Errorf("%w %s", ErrNoGasFeeClient, networkID)
+	}
+
+	to := common.HexToAddress(call.To)
+	units, err := client.EstimateGas(ctx, ethereum.CallMsg{
+		From: common.HexToAddress(call.From),
+		To:   &to,
+		Data: call.Data,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	return units, nil
+}
+
+// EstimateL1DataFee returns the L1 data fee an OP-stack network charges for posting a transaction to
+// Ethereum, or nil for other networks
+func (o *GasFeeOracle) EstimateL1DataFee(ctx context.Context, network db.Network, call *params.GasEstimateCall, gasUnits uint64, quote *business.GasFeeQuote) (*big.Int, error) {
+	if !opStackNetworkTypes[network.CircleNetworkType] {
+		return nil, nil
+	}
+
+	client, ok := o.clients.GasFeeClient(network.ID)
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrNoGasFeeClient, network.ID)
+	}
+
+	// The oracle prices the unsigned transaction as it would be posted to L1
+	tx := &types.DynamicFeeTx{
+		ChainID:   big.NewInt(int64(network.ChainID)),
+		Gas:       gasUnits,
+		GasTipCap: quote.PriorityFeeWei,
+		GasFeeCap: quote.MaxFeePerGasWei,
+	}
+	if !quote.EIP1559 {
+		tx.GasTipCap = quote.GasPriceWei
+		tx.GasFeeCap = quote.GasPriceWei
+	}
+	if call != nil {
+		to := common.HexToAddress(call.To)
+		tx.To = &to
+		tx.Data = call.Data
+	}
+	encoded, err := types.NewTx(tx).MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &opStackGasPriceOracle,
+		Data: encodeGetL1FeeCall(encoded),
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L1 data fee: %w", err)
+	}
+	if len(result) < 32 {
+		return nil, fmt.Errorf("unexpected L1 data fee result length %d", len(result))
+	}
+	return new(big.Int).SetBytes(result[:32]), nil
+}
+
+// fetchQuote derives a quote from recent fee history, or from the node's suggested gas price on
+// networks without EIP-1559
+func (o *GasFeeOracle) fetchQuote(ctx context.Context, client GasFeeClient, networkID uuid.UUID) (*business.GasFeeQuote, error) {
+	history, err := client.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{feeHistoryTipPercentile})
+	if err == nil && len(history.BaseFee) > 0 && history.BaseFee[len(history.BaseFee)-1] != nil && history.BaseFee[len(history.BaseFee)-1].Sign() > 0 {
+		return quoteFromFeeHistory(networkID, history), nil
+	}
+	if err != nil {
+		o.logger.Debug("Fee history unavailable, using suggested gas price",
+			zap.String("network_id", networkID.String()),
+			zap.Error(err))
+	}
+
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	return &business.GasFeeQuote{
+		NetworkID:   networkID,
+		GasPriceWei: gasPrice,
+		FetchedAt:   time.Now(),
+	}, nil
+}
+
+// quoteFromFeeHistory prices the next block from eth_feeHistory. The result's last base fee is the
+// next block's; the tip is the median of the sampled blocks' tips, skipping empty blocks. The fee
+// cap is twice the base fee plus the tip, which holds through six full blocks of base fee growth.
+func quoteFromFeeHistory(networkID uuid.UUID, history *ethereum.FeeHistory) *business.GasFeeQuote {
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	tips := make([]*big.Int, 0, len(history.Reward))
+	for i, reward := range history.Reward {
+		if len(reward) == 0 || reward[0] == nil {
+			continue
+		}
+		if i < len(history.GasUsedRatio) && history.GasUsedRatio[i] == 0 {
+			continue
+		}
+		tips = append(tips, reward[0])
+	}
+	tip := medianWei(tips)
+
+	lowest, highest := baseFee, baseFee
+	for _, fee := range history.BaseFee {
+		if fee == nil || fee.Sign() <= 0 {
+			continue
+		}
+		if fee.Cmp(lowest) < 0 {
+			lowest = fee
+		}
+		if fee.Cmp(highest) > 0 {
+			highest = fee
+		}
+	}
+	volatility, _ := new(big.Float).Quo(new(big.Float).SetInt(highest), new(big.Float).SetInt(lowest)).Float64()
+
+	quote := &business.GasFeeQuote{
+		NetworkID:         networkID,
+		EIP1559:           true,
+		BaseFeeWei:        baseFee,
+		PriorityFeeWei:    tip,
+		MaxFeePerGasWei:   new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip),
+		GasPriceWei:       new(big.Int).Add(baseFee, tip),
+		BaseFeeVolatility: volatility - 1,
+		FetchedAt:         time.Now(),
+	}
+	if history.OldestBlock != nil {
+		quote.BlockNumber = history.OldestBlock.Uint64() + uint64(len(history.BaseFee)) - 1
+	}
+	return quote
+}
+
+// medianWei returns the median of the values, or zero when there are none
+func medianWei(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return new(big.Int)
+	}
+	sorted := make([]*big.Int, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return new(big.Int).Set(sorted[mid])
+	}
+	sum := new(big.Int).Add(sorted[mid-1], sorted[mid])
+	return sum.Div(sum, big.NewInt(2))
+}
+
+// encodeGetL1FeeCall ABI-encodes getL1Fee(bytes) for the given transaction
+func encodeGetL1FeeCall(tx []byte) []byte {
+	padded := (len(tx) + 31) / 32 * 32
+	data := make([]byte, 4+32+32+padded)
+	copy(data, getL1FeeSelector)
+	big.NewInt(32).FillBytes(data[4:36])
+	big.NewInt(int64(len(tx))).FillBytes(data[36:68])
+	copy(data[68:], tx)
+	return data
+}
@@ -0,0 +1,268 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeGasFeeClient answers the oracle's RPC calls with fixed data
+type fakeGasFeeClient struct {
+	feeHistory      *ethereum.FeeHistory
+	feeHistoryErr   error
+	gasPrice        *big.Int
+	gasUnits        uint64
+	l1Fee           *big.Int
+	feeHistoryCalls int
+	estimateCalls   []ethereum.CallMsg
+	contractCalls   []ethereum.CallMsg
+}
+
+func (c *fakeGasFeeClient) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	c.feeHistoryCalls++
+	return c.feeHistory, c.feeHistoryErr
+}
+
+func (c *fakeGasFeeClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	if c.gasPrice == nil {
+		return nil, errors.New("gas price unavailable")
+	}
+	return c.gasPrice, nil
+}
+
+func (c *fakeGasFeeClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	c.estimateCalls = append(c.estimateCalls, call)
+	return c.gasUnits, nil
+}
+
+func (c *fakeGasFeeClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.contractCalls = append(c.contractCalls, call)
+	return common.LeftPadBytes(c.l1Fee.Bytes(), 32), nil
+}
+
+// fakeGasFeeClients connects every network to the same client
+type fakeGasFeeClients struct {
+	client *fakeGasFeeClient
+}
+
+func (p fakeGasFeeClients) GasFeeClient(networkID uuid.UUID) (services.GasFeeClient, bool) {
+	if p.client == nil {
+		return nil, false
+	}
+	return p.client, true
+}
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9))
+}
+
+// sampleFeeHistory has base fees of 10 to 14 gwei and tips of 1 and 3 gwei, with one empty block
+func sampleFeeHistory() *ethereum.FeeHistory {
+	return &ethereum.FeeHistory{
+		OldestBlock:  big.NewInt(100),
+		BaseFee:      []*big.Int{gwei(10), gwei(12), gwei(11), gwei(14)},
+		Reward:       [][]*big.Int{{gwei(1)}, {big.NewInt(0)}, {gwei(3)}},
+		GasUsedRatio: []float64{0.6, 0, 0.7},
+	}
+}
+
+func TestGasFeeOracle_Quote(t *testing.T) {
+	ctx := context.Background()
+	networkID := uuid.New()
+
+	t.Run("prices the next block from fee history", func(t *testing.T) {
+		client := &fakeGasFeeClient{feeHistory: sampleFeeHistory()}
+		oracle := services.NewGasFeeOracle(fakeGasFeeClients{client: client})
+
+		quote, err := oracle.Quote(ctx, networkID)
+		require.NoError(t, err)
+
+		assert.True(t, quote.EIP1559)
+		assert.Equal(t, gwei(14), quote.BaseFeeWei)
+		// Median of the non-empty blocks' tips
+		assert.Equal(t, gwei(2), quote.PriorityFeeWei)
+		assert.Equal(t, gwei(30), quote.MaxFeePerGasWei)
+		assert.Equal(t, gwei(16), quote.GasPriceWei)
+		assert.InDelta(t, 0.4, quote.BaseFeeVolatility, 0.0001)
+		assert.Equal(t, uint64(103), quote.BlockNumber)
+	})
+
+	t.Run("caches quotes per network", func(t *testing.T) {
+		client := &fakeGasFeeClient{feeHistory: sampleFeeHistory()}
+		oracle := services.NewGasFeeOracle(fakeGasFeeClients{client: client})
+
+		_, err := oracle.Quote(ctx, networkID)
+		require.NoError(t, err)
+		_, err = oracle.Quote(ctx, networkID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, client.feeHistoryCalls)
+
+		_, err = oracle.Quote(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, 2, client.feeHistoryCalls)
+	})
+
+	t.Run("falls back to the suggested gas price without EIP-1559", func(t *testing.T) {
+		client := &fakeGasFeeClient{
+			feeHistoryErr: errors.New("method not found"),
+			gasPrice:      gwei(25),
+		}
+		oracle := services.NewGasFeeOracle(fakeGasFeeClients{client: client})
+
+		quote, err := oracle.Quote(ctx, networkID)
+		require.NoError(t, err)
+
+		assert.False(t, quote.EIP1559)
+		assert.Nil(t, quote.BaseFeeWei)
+		assert.Equal(t, gwei(25), quote.GasPriceWei)
+	})
+
+	t.Run("network without RPC client", func(t *testing.T) {
+		oracle := services.NewGasFeeOracle(fakeGasFeeClients{})
+
+		_, err := oracle.Quote(ctx, networkID)
+		assert.ErrorIs(t, err, services.ErrNoGasFeeClient)
+	})
+}
+
+func TestGasFeeService_EstimateGasFeeWithOracle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	exchangeRateService := services.NewExchangeRateService(mockQuerier, "")
+	ctx := context.Background()
+
+	call := &params.GasEstimateCall{
+		From: "0x1111111111111111111111111111111111111111",
+		To:   "0x2222222222222222222222222222222222222222",
+		Data: []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+
+	t.Run("simulates the call and adds the OP-stack L1 data fee", func(t *testing.T) {
+		network := db.Network{ID: uuid.New(), Name: "Base", ChainID: 8453, CircleNetworkType: db.CircleNetworkTypeBASE}
+		client := &fakeGasFeeClient{
+			feeHistory: sampleFeeHistory(),
+			gasUnits:   90000,
+			l1Fee:      big.NewInt(5e12),
+		}
+		service := services.NewGasFeeServiceWithOracle(mockQuerier, exchangeRateService, services.NewGasFeeOracle(fakeGasFeeClients{client: client}))
+
+		mockQuerier.EXPECT().GetNetwork(ctx, network.ID).Return(network, nil)
+
+		result, err := service.EstimateGasFee(ctx, params.EstimateGasFeeParams{
+			NetworkID:         network.ID,
+			TransactionType:   "delegation",
+			EstimatedGasLimit: 21000,
+			Currency:          "USD",
+			Call:              call,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, business.GasFeeEstimateSourceFeeHistory, result.EstimateSource)
+		assert.True(t, result.GasUnitsSimulated)
+		assert.Equal(t, uint64(90000), result.EstimatedGasUnits)
+		assert.Equal(t, gwei(16).String(), result.CurrentGasPriceWei)
+		assert.Equal(t, gwei(14).String(), result.BaseFeeWei)
+		assert.Equal(t, gwei(30).String(), result.MaxFeePerGasWei)
+		assert.Equal(t, "5000000000000", result.L1DataFeeWei)
+
+		// 90000 units at 16 gwei plus the L1 data fee
+		expected := new(big.Int).Add(new(big.Int).Mul(big.NewInt(90000), gwei(16)), big.NewInt(5e12))
+		assert.Equal(t, expected.String(), result.EstimatedCostWei)
+
+		require.Len(t, client.estimateCalls, 1)
+		assert.Equal(t, call.Data, client.estimateCalls[0].Data)
+		require.Len(t, client.contractCalls, 1)
+		assert.Equal(t, common.HexToAddress("0x420000000000000000000000000000000000000F"), *client.contractCalls[0].To)
+	})
+
+	t.Run("no L1 data fee outside the OP stack", func(t *testing.T) {
+		network := db.Network{ID: uuid.New(), Name: "Arbitrum", ChainID: 42161, CircleNetworkType: db.CircleNetworkTypeARB}
+		client := &fakeGasFeeClient{feeHistory: sampleFeeHistory(), gasUnits: 90000}
+		service := services.NewGasFeeServiceWithOracle(mockQuerier, exchangeRateService, services.NewGasFeeOracle(fakeGasFeeClients{client: client}))
+
+		mockQuerier.EXPECT().GetNetwork(ctx, network.ID).Return(network, nil)
+
+		result, err := service.EstimateGasFee(ctx, params.EstimateGasFeeParams{
+			NetworkID: network.ID,
+			Currency:  "USD",
+			Call:      call,
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, result.L1DataFeeWei)
+		assert.Empty(t, client.contractCalls)
+	})
+
+	t.Run("static estimate when the network cannot be reached", func(t *testing.T) {
+		network := db.Network{ID: uuid.New(), Name: "ethereum", ChainID: 1, CircleNetworkType: db.CircleNetworkTypeETH}
+		service := services.NewGasFeeServiceWithOracle(mockQuerier, exchangeRateService, services.NewGasFeeOracle(fakeGasFeeClients{}))
+
+		mockQuerier.EXPECT().GetNetwork(ctx, network.ID).Return(network, nil)
+
+		result, err := service.EstimateGasFee(ctx, params.EstimateGasFeeParams{
+			NetworkID:         network.ID,
+			TransactionType:   "contract_call",
+			EstimatedGasLimit: 100000,
+			Currency:          "USD",
+			Call:              call,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, business.GasFeeEstimateSourceStatic, result.EstimateSource)
+		assert.False(t, result.GasUnitsSimulated)
+		assert.Equal(t, uint64(120000), result.EstimatedGasUnits)
+		assert.Equal(t, "20000000000", result.CurrentGasPriceWei)
+	})
+}
+
+func TestGasFeeService_GetGasFeeEstimateAccuracy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewGasFeeService(mockQuerier, nil)
+	ctx := context.Background()
+
+	workspaceID := uuid.New()
+	networkID := uuid.New()
+
+	mockQuerier.EXPECT().GetGasFeeEstimateAccuracy(ctx, gomock.Any()).Return([]db.GetGasFeeEstimateAccuracyRow{
+		{
+			NetworkID:           networkID,
+			NetworkName:         "Base",
+			EstimateSource:      business.GasFeeEstimateSourceFeeHistory,
+			SampleCount:         40,
+			AvgCostRatio:        1.08,
+			MeanAbsErrorRatio:   0.12,
+			AvgGasUnitsRatio:    1.02,
+			UnderestimatedCount: 10,
+			EstimatedUsdCents:   540,
+			ActualUsdCents:      500,
+		},
+	}, nil)
+
+	accuracy, err := service.GetGasFeeEstimateAccuracy(ctx, workspaceID, time.Now().AddDate(0, 0, -30), time.Now())
+	require.NoError(t, err)
+
+	require.Len(t, accuracy, 1)
+	assert.Equal(t, "Base", accuracy[0].NetworkName)
+	assert.Equal(t, int64(40), accuracy[0].SampleCount)
+	assert.InDelta(t, 0.25, accuracy[0].UnderestimatedRate, 0.0001)
+	assert.Equal(t, int64(500), accuracy[0].ActualUSDCents)
+}
@@ -21,14 +21,22 @@ import (
 type GasFeeService struct {
 	queries             db.Querier
 	exchangeRateService *ExchangeRateService
+	feeOracle           *GasFeeOracle // Nil estimates from static per-network prices
 	logger              *zap.Logger
 }
 
 // NewGasFeeService creates a new gas fee service
 func NewGasFeeService(queries db.Querier, exchangeRateService *ExchangeRateService) *GasFeeService {
+	return NewGasFeeServiceWithOracle(queries, exchangeRateService, nil)
+}
+
+// NewGasFeeServiceWithOracle creates a gas fee service that estimates from live network fee data,
+// falling back to static per-network prices when a network cannot be reached
+func NewGasFeeServiceWithOracle(queries db.Querier, exchangeRateService *ExchangeRateService, feeOracle *GasFeeOracle) *GasFeeService {
 	return &GasFeeService{
 		queries:             queries,
 		exchangeRateService: exchangeRateService,
+		feeOracle:           feeOracle,
 		logger:              logger.Log,
 	}
 }
@@ -112,15 +120,8 @@ func (s *GasFeeService) EstimateGasFee(ctx context.Context, estimateGasFeeParams
 		return nil, fmt.Errorf("failed to get network: %w", err)
 	}
 
-	// Get current gas price estimate
-	gasPriceWei, confidence := s.estimateGasPrice(ctx, network)
-
-	// Adjust gas limit based on transaction type
-	adjustedGasLimit := s.adjustGasLimitByType(estimateGasFeeParams.EstimatedGasLimit, estimateGasFeeParams.TransactionType)
-
-	// Calculate estimated cost
-	estimatedCostWei := new(big.Int).Mul(big.NewInt(int64(adjustedGasLimit)), gasPriceWei)
-	estimatedCostETH := weiToEth(estimatedCostWei)
+	estimate := s.estimateNetworkFee(ctx, network, estimateGasFeeParams)
+	estimatedCostETH := weiToEth(estimate.costWei)
 
 	// Get exchange rate
 	exchangeRateResult, err := s.exchangeRateService.GetExchangeRate(ctx, params.ExchangeRateParams{
@@ -135,17 +136,87 @@ func (s *GasFeeService) EstimateGasFee(ctx context.Context, estimateGasFeeParams
 	estimatedCostUSD := estimatedCostETH * exchangeRateResult.Rate
 	estimatedCostCents := int64(estimatedCostUSD * 100)
 
-	return &responses.EstimateGasFeeResult{
+	result := &responses.EstimateGasFeeResult{
 		NetworkName:           network.Name,
 		TransactionType:       estimateGasFeeParams.TransactionType,
-		EstimatedGasUnits:     adjustedGasLimit,
-		CurrentGasPriceWei:    gasPriceWei.String(),
-		EstimatedCostWei:      estimatedCostWei.String(),
+		EstimatedGasUnits:     estimate.gasUnits,
+		CurrentGasPriceWei:    estimate.gasPriceWei.String(),
+		EstimatedCostWei:      estimate.costWei.String(),
 		EstimatedCostEth:      estimatedCostETH,
 		EstimatedCostUSD:      estimatedCostUSD,
 		EstimatedCostUSDCents: estimatedCostCents,
-		Confidence:            confidence,
-	}, nil
+		Confidence:            estimate.confidence,
+		EstimateSource:        estimate.source,
+		GasUnitsSimulated:     estimate.gasUnitsSimulated,
+	}
+	if quote := estimate.quote; quote != nil && quote.EIP1559 {
+		result.BaseFeeWei = quote.BaseFeeWei.String()
+		result.PriorityFeeWei = quote.PriorityFeeWei.String()
+		result.MaxFeePerGasWei = quote.MaxFeePerGasWei.String()
+	}
+	if estimate.l1DataFeeWei != nil {
+		result.L1DataFeeWei = estimate.l1DataFeeWei.String()
+	}
+
+	return result, nil
+}
+
+// RecordGasFeeEstimate stores the estimate made for a payment, so it can be compared with the
+// actual fee once the payment's transaction confirms
+func (s *GasFeeService) RecordGasFeeEstimate(ctx context.Context, workspaceID, paymentID, networkID uuid.UUID, estimate *responses.EstimateGasFeeResult) error {
+	_, err := s.queries.CreateGasFeeEstimate(ctx, db.CreateGasFeeEstimateParams{
+		WorkspaceID:           workspaceID,
+		PaymentID:             pgtype.UUID{Bytes: paymentID, Valid: paymentID != uuid.Nil},
+		NetworkID:             networkID,
+		EstimateSource:        estimate.EstimateSource,
+		EstimatedGasUnits:     int64(estimate.EstimatedGasUnits),
+		GasUnitsSimulated:     estimate.GasUnitsSimulated,
+		GasPriceWei:           estimate.CurrentGasPriceWei,
+		BaseFeeWei:            pgtype.Text{String: estimate.BaseFeeWei, Valid: estimate.BaseFeeWei != ""},
+		PriorityFeeWei:        pgtype.Text{String: estimate.PriorityFeeWei, Valid: estimate.PriorityFeeWei != ""},
+		MaxFeePerGasWei:       pgtype.Text{String: estimate.MaxFeePerGasWei, Valid: estimate.MaxFeePerGasWei != ""},
+		L1DataFeeWei:          pgtype.Text{String: estimate.L1DataFeeWei, Valid: estimate.L1DataFeeWei != ""},
+		EstimatedCostWei:      estimate.EstimatedCostWei,
+		EstimatedCostUsdCents: estimate.EstimatedCostUSDCents,
+		Confidence:            estimate.Confidence,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record gas fee estimate: %w", err)
+	}
+	return nil
+}
+
+// GetGasFeeEstimateAccuracy compares a workspace's gas fee estimates with the actual fees of payments
+// confirmed in the period, per network and estimate source
+func (s *GasFeeService) GetGasFeeEstimateAccuracy(ctx context.Context, workspaceID uuid.UUID, startDate, endDate time.Time) ([]business.GasFeeEstimateAccuracy, error) {
+	rows, err := s.queries.GetGasFeeEstimateAccuracy(ctx, db.GetGasFeeEstimateAccuracyParams{
+		WorkspaceID: workspaceID,
+		StartDate:   pgtype.Timestamptz{Time: startDate, Valid: true},
+		EndDate:     pgtype.Timestamptz{Time: endDate, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas fee estimate accuracy: %w", err)
+	}
+
+	accuracy := make([]business.GasFeeEstimateAccuracy, 0, len(rows))
+	for _, row := range rows {
+		entry := business.GasFeeEstimateAccuracy{
+			NetworkID:         row.NetworkID,
+			NetworkName:       row.NetworkName,
+			EstimateSource:    row.EstimateSource,
+			SampleCount:       row.SampleCount,
+			AvgCostRatio:      row.AvgCostRatio,
+			MeanAbsErrorRatio: row.MeanAbsErrorRatio,
+			AvgGasUnitsRatio:  row.AvgGasUnitsRatio,
+			EstimatedUSDCents: row.EstimatedUsdCents,
+			ActualUSDCents:    row.ActualUsdCents,
+		}
+		if row.SampleCount > 0 {
+			entry.UnderestimatedRate = float64(row.UnderestimatedCount) / float64(row.SampleCount)
+		}
+		accuracy = append(accuracy, entry)
+	}
+	return accuracy, nil
 }
 
 // CreateGasFeePaymentRecord creates a gas fee payment record
@@ -235,6 +306,84 @@ func weiToEth(wei *big.Int) float64 {
 	return ethValue
 }
 
+// networkFeeEstimate is a gas fee estimate in wei
+type networkFeeEstimate struct {
+	source            string
+	quote             *business.GasFeeQuote // Nil for static estimates
+	gasUnits          uint64
+	gasUnitsSimulated bool
+	gasPriceWei       *big.Int
+	l1DataFeeWei      *big.Int
+	costWei           *big.Int
+	confidence        float64
+}
+
+// estimateNetworkFee estimates a transaction's fee from live fee history and a simulation of the
+// transaction where possible, and from static per-network prices and transaction type heuristics
+// otherwise
+func (s *GasFeeService) estimateNetworkFee(ctx context.Context, network db.Network, estimateGasFeeParams params.EstimateGasFeeParams) networkFeeEstimate {
+	estimate := networkFeeEstimate{source: business.GasFeeEstimateSourceStatic}
+
+	var quote *business.GasFeeQuote
+	if s.feeOracle != nil {
+		var err error
+		quote, err = s.feeOracle.Quote(ctx, network.ID)
+		if err != nil {
+			s.logger.Warn("Failed to get live gas fees, using static estimate",
+				zap.String("network", network.Name),
+				zap.Error(err))
+		}
+	}
+	if quote == nil {
+		gasPriceWei, confidence := s.estimateGasPrice(ctx, network)
+		estimate.gasPriceWei = gasPriceWei
+		estimate.confidence = confidence
+		estimate.gasUnits = s.adjustGasLimitByType(estimateGasFeeParams.EstimatedGasLimit, estimateGasFeeParams.TransactionType)
+		estimate.costWei = new(big.Int).Mul(new(big.Int).SetUint64(estimate.gasUnits), gasPriceWei)
+		return estimate
+	}
+
+	estimate.source = business.GasFeeEstimateSourceFeeHistory
+	estimate.quote = quote
+	estimate.gasPriceWei = quote.GasPriceWei
+	estimate.gasUnits = s.adjustGasLimitByType(estimateGasFeeParams.EstimatedGasLimit, estimateGasFeeParams.TransactionType)
+	if call := estimateGasFeeParams.Call; call != nil {
+		units, err := s.feeOracle.EstimateGasUnits(ctx, network.ID, *call)
+		if err != nil {
+			s.logger.Warn("Failed to simulate transaction, using gas limit heuristic",
+				zap.String("network", network.Name),
+				zap.Error(err))
+		} else {
+			estimate.gasUnits = units
+			estimate.gasUnitsSimulated = true
+		}
+	}
+	estimate.costWei = new(big.Int).Mul(new(big.Int).SetUint64(estimate.gasUnits), quote.GasPriceWei)
+
+	l1DataFee, err := s.feeOracle.EstimateL1DataFee(ctx, network, estimateGasFeeParams.Call, estimate.gasUnits, quote)
+	if err != nil {
+		s.logger.Warn("Failed to estimate L1 data fee",
+			zap.String("network", network.Name),
+			zap.Error(err))
+	} else if l1DataFee != nil {
+		estimate.l1DataFeeWei = l1DataFee
+		estimate.costWei.Add(estimate.costWei, l1DataFee)
+	}
+
+	estimate.confidence = feeHistoryConfidence(quote, estimate.gasUnitsSimulated)
+	return estimate
+}
+
+// feeHistoryConfidence scores a live estimate: volatile base fees and unsimulated gas units make
+// the actual fee less predictable
+func feeHistoryConfidence(quote *business.GasFeeQuote, gasUnitsSimulated bool) float64 {
+	confidence := 0.95 - min(quote.BaseFeeVolatility, 0.5)/2
+	if !gasUnitsSimulated {
+		confidence -= 0.1
+	}
+	return confidence
+}
+
 // estimateGasPrice estimates current gas price for a network
 func (s *GasFeeService) estimateGasPrice(ctx context.Context, network db.Network) (*big.Int, float64) {
 	// This is a simplified implementation
@@ -280,8 +429,13 @@ func (s *GasFeeService) GetCurrentGasPrice(ctx context.Context, networkID uuid.U
 		return 0, fmt.Errorf("failed to get network: %w", err)
 	}
 
-	// Get current gas price estimate
+	// Get current gas price, live when the network can be reached
 	gasPriceWei, _ := s.estimateGasPrice(ctx, network)
+	if s.feeOracle != nil {
+		if quote, err := s.feeOracle.Quote(ctx, networkID); err == nil {
+			gasPriceWei = quote.GasPriceWei
+		}
+	}
 
 	// Convert Wei to Gwei for return (divide by 1e9)
 	gasPriceGwei := new(big.Int).Div(gasPriceWei, big.NewInt(1e9))
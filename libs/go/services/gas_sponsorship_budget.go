@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrSponsorshipBudgetExhausted is returned when a hold does not fit the workspace's monthly budget
+	ErrSponsorshipBudgetExhausted = errors.New("monthly sponsorship budget exhausted")
+	// ErrSponsorshipCustomerCapReached is returned when a hold does not fit the customer's monthly cap
+	ErrSponsorshipCustomerCapReached = errors.New("customer monthly sponsorship cap reached")
+	// ErrSponsorshipReservationNotFound is returned when there is no open reservation to settle or release
+	ErrSponsorshipReservationNotFound = errors.New("no open sponsorship reservation")
+)
+
+const (
+	// sponsorshipReservationTTL is how long a hold may wait for its transaction before it is released
+	sponsorshipReservationTTL = time.Hour
+	// expiredSponsorshipBatchSize bounds the holds released per ReleaseExpiredSponsorships call
+	expiredSponsorshipBatchSize = 500
+)
+
+// sponsorshipBudgetAlertThresholds are the shares of the monthly budget, in percent, alerted on
+// once per month when spending reaches them
+var sponsorshipBudgetAlertThresholds = []int32{50, 80, 100}
+
+// sponsorshipHold describes budget to hold for one sponsored transaction
+type sponsorshipHold struct {
+	workspaceID uuid.UUID
+	customerID  uuid.UUID
+	ruleID      uuid.UUID
+	amountCents int64
+	percentage  int32
+	customerCap pgtype.Int8
+	at          time.Time
+}
+
+// ReserveSponsorship decides whether a transaction's gas is sponsored and, if so, holds the
+// sponsored amount against the monthly budget and the customer's cap until the transaction is
+// settled or released. When a concurrent transaction used up the budget or cap first, the
+// decision is turned down rather than overspending.
+func (s *GasSponsorshipService) ReserveSponsorship(ctx context.Context, checkParams params.SponsorshipCheckParams) (*business.SponsorshipDecision, error) {
+	simulation, err := s.evaluateSponsorship(ctx, checkParams, false)
+	if err != nil {
+		return nil, err
+	}
+	decision := simulation.Decision
+	if !decision.ShouldSponsor || decision.SponsoredAmountCents <= 0 {
+		return decision, nil
+	}
+
+	hold := sponsorshipHold{
+		workspaceID: checkParams.WorkspaceID,
+		customerID:  checkParams.CustomerID,
+		ruleID:      decision.RuleID,
+		amountCents: decision.SponsoredAmountCents,
+		percentage:  decision.SponsorPercentage,
+		at:          sponsorshipTime(checkParams),
+	}
+	if simulation.CustomerMonthlyCapCents != nil {
+		hold.customerCap = pgtype.Int8{Int64: *simulation.CustomerMonthlyCapCents, Valid: true}
+	}
+
+	reservation, err := s.holdBudget(ctx, hold)
+	switch {
+	case errors.Is(err, ErrSponsorshipBudgetExhausted):
+		rejectSponsorship(decision, "Monthly sponsorship budget exhausted")
+		return decision, nil
+	case errors.Is(err, ErrSponsorshipCustomerCapReached):
+		rejectSponsorship(decision, "Customer monthly sponsorship cap reached")
+		return decision, nil
+	case err != nil:
+		return nil, err
+	}
+	decision.ReservationID = reservation.ID
+
+	s.logger.Info("Reserved gas sponsorship",
+		zap.String("workspace_id", checkParams.WorkspaceID.String()),
+		zap.String("reservation_id", reservation.ID.String()),
+		zap.Int64("reserved_cents", reservation.ReservedUsdCents))
+
+	return decision, nil
+}
+
+// AttachSponsorshipPayment links an open reservation to the payment its transaction belongs to,
+// so the reservation can be settled or released by payment
+func (s *GasSponsorshipService) AttachSponsorshipPayment(ctx context.Context, workspaceID, reservationID, paymentID uuid.UUID) error {
+	_, err := s.queries.AttachGasSponsorshipReservationPayment(ctx, db.AttachGasSponsorshipReservationPaymentParams{
+		PaymentID:   pgtype.UUID{Bytes: paymentID, Valid: true},
+		ID:          reservationID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSponsorshipReservationNotFound
+		}
+		return fmt.Errorf("failed to attach payment to sponsorship reservation: %w", err)
+	}
+	return nil
+}
+
+// SettleSponsorship replaces a hold with the sponsored share of the confirmed transaction's actual
+// gas cost. The hold is the most a transaction can draw from the budget; any cost above it is paid
+// by the customer.
+func (s *GasSponsorshipService) SettleSponsorship(ctx context.Context, settlement params.SponsorshipSettlementParams) (*business.SponsorshipReservation, error) {
+	reservation, err := s.openReservation(ctx, settlement.WorkspaceID, settlement.ReservationID, settlement.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	settled, err := s.settleReservation(ctx, reservation, settlement.PaymentID, settlement.ActualGasCostUSDCents)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toSponsorshipReservation(settled)
+	return &result, nil
+}
+
+// ReleaseSponsorship gives a hold back to the budget and the customer's cap, for transactions that
+// failed or were never sent
+func (s *GasSponsorshipService) ReleaseSponsorship(ctx context.Context, release params.SponsorshipReleaseParams) (*business.SponsorshipReservation, error) {
+	reservation, err := s.openReservation(ctx, release.WorkspaceID, release.ReservationID, release.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	released, err := s.releaseReservation(ctx, reservation, release.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toSponsorshipReservation(released)
+	return &result, nil
+}
+
+// ReleaseExpiredSponsorships releases holds whose transactions neither confirmed nor failed in time
+// and returns how many were released. It should be called periodically.
+func (s *GasSponsorshipService) ReleaseExpiredSponsorships(ctx context.Context) (int, error) {
+	expired, err := s.queries.ListExpiredGasSponsorshipReservations(ctx, db.ListExpiredGasSponsorshipReservationsParams{
+		ExpiresBefore: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		Limit:         expiredSponsorshipBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired sponsorship reservations: %w", err)
+	}
+
+	released := 0
+	for _, reservation := range expired {
+		if _, err := s.releaseReservation(ctx, reservation, "expired"); err != nil {
+			if errors.Is(err, ErrSponsorshipReservationNotFound) {
+				continue
+			}
+			s.logger.Error("Failed to release expired sponsorship reservation",
+				zap.String("reservation_id", reservation.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		released++
+	}
+
+	if released > 0 {
+		s.logger.Info("Released expired gas sponsorship reservations",
+			zap.Int("released_count", released),
+			zap.Int("expired_count", len(expired)))
+	}
+
+	return released, nil
+}
+
+// ListSponsorshipLedger returns a page of a workspace's sponsorship ledger, newest first, and the
+// total number of entries
+func (s *GasSponsorshipService) ListSponsorshipLedger(ctx context.Context, workspaceID uuid.UUID, limit, offset int32) ([]business.SponsorshipLedgerEntry, int64, error) {
+	rows, err := s.queries.ListGasSponsorshipLedgerEntries(ctx, db.ListGasSponsorshipLedgerEntriesParams{
+		WorkspaceID: workspaceID,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list sponsorship ledger: %w", err)
+	}
+
+	total, err := s.queries.CountGasSponsorshipLedgerEntries(ctx, workspaceID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count sponsorship ledger: %w", err)
+	}
+
+	entries := make([]business.SponsorshipLedgerEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := business.SponsorshipLedgerEntry{
+			ID:                  row.ID,
+			ReservationID:       row.ReservationID,
+			CustomerID:          uuid.UUID(row.CustomerID.Bytes),
+			PaymentID:           uuid.UUID(row.PaymentID.Bytes),
+			EntryType:           row.EntryType,
+			AmountCents:         row.AmountUsdCents,
+			BudgetSpentCents:    row.BudgetSpentCents,
+			BudgetReservedCents: row.BudgetReservedCents,
+			Reason:              row.Reason.String,
+			CreatedAt:           row.CreatedAt.Time,
+		}
+		if row.MonthlyBudgetUsdCents.Valid {
+			budget := row.MonthlyBudgetUsdCents.Int64
+			entry.MonthlyBudgetCents = &budget
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, nil
+}
+
+// holdBudget reserves an amount against the workspace budget and then the customer's month. Each
+// step is a single conditional statement; when the customer step does not fit, the workspace hold
+// is released again.
+func (s *GasSponsorshipService) holdBudget(ctx context.Context, hold sponsorshipHold) (db.GasSponsorshipReservation, error) {
+	reservation, err := s.queries.ReserveGasSponsorshipBudget(ctx, db.ReserveGasSponsorshipBudgetParams{
+		ReservedUsdCents:  hold.amountCents,
+		WorkspaceID:       hold.workspaceID,
+		CustomerID:        pgtype.UUID{Bytes: hold.customerID, Valid: hold.customerID != uuid.Nil},
+		RuleID:            pgtype.UUID{Bytes: hold.ruleID, Valid: hold.ruleID != uuid.Nil},
+		SponsorPercentage: hold.percentage,
+		PeriodStart:       sponsorshipMonth(hold.at),
+		ExpiresAt:         pgtype.Timestamptz{Time: time.Now().Add(sponsorshipReservationTTL), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return reservation, ErrSponsorshipBudgetExhausted
+		}
+		return reservation, fmt.Errorf("failed to reserve sponsorship budget: %w", err)
+	}
+	if hold.customerID == uuid.Nil {
+		return reservation, nil
+	}
+
+	held, err := s.queries.ReserveGasSponsorshipCustomerSpending(ctx, db.ReserveGasSponsorshipCustomerSpendingParams{
+		ID:          reservation.ID,
+		CapUsdCents: hold.customerCap,
+	})
+	if err == nil {
+		return held, nil
+	}
+
+	reason := "customer spending could not be reserved"
+	if errors.Is(err, pgx.ErrNoRows) {
+		reason = "customer monthly cap reached"
+		err = ErrSponsorshipCustomerCapReached
+	} else {
+		err = fmt.Errorf("failed to reserve customer sponsorship spending: %w", err)
+	}
+	if _, releaseErr := s.releaseReservation(ctx, reservation, reason); releaseErr != nil {
+		s.logger.Error("Failed to release sponsorship reservation",
+			zap.String("reservation_id", reservation.ID.String()),
+			zap.Error(releaseErr))
+	}
+	return db.GasSponsorshipReservation{}, err
+}
+
+// openReservation looks up a reservation by ID, or the open reservation of a payment when no ID is
+// given, and checks it is still held
+func (s *GasSponsorshipService) openReservation(ctx context.Context, workspaceID, reservationID, paymentID uuid.UUID) (db.GasSponsorshipReservation, error) {
+	var reservation db.GasSponsorshipReservation
+	var err error
+	if reservationID != uuid.Nil {
+		reservation, err = s.queries.GetGasSponsorshipReservation(ctx, db.GetGasSponsorshipReservationParams{
+			ID:          reservationID,
+			WorkspaceID: workspaceID,
+		})
+	} else {
+		reservation, err = s.queries.GetOpenGasSponsorshipReservationByPayment(ctx, db.GetOpenGasSponsorshipReservationByPaymentParams{
+			PaymentID:   pgtype.UUID{Bytes: paymentID, Valid: true},
+			WorkspaceID: workspaceID,
+		})
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return reservation, ErrSponsorshipReservationNotFound
+		}
+		return reservation, fmt.Errorf("failed to get sponsorship reservation: %w", err)
+	}
+	if reservation.Status != business.SponsorshipReservationReserved {
+		return reservation, fmt.Errorf("%w: reservation %s is %s", ErrSponsorshipReservationNotFound, reservation.ID, reservation.Status)
+	}
+	return reservation, nil
+}
+
+// settleReservation settles an open reservation and alerts on the budget thresholds it reaches
+func (s *GasSponsorshipService) settleReservation(ctx context.Context, reservation db.GasSponsorshipReservation, paymentID uuid.UUID, actualGasCostCents int64) (db.GasSponsorshipReservation, error) {
+	settled, err := s.queries.SettleGasSponsorshipReservation(ctx, db.SettleGasSponsorshipReservationParams{
+		SettledUsdCents: settledSponsorshipAmount(reservation, actualGasCostCents),
+		PaymentID:       pgtype.UUID{Bytes: paymentID, Valid: paymentID != uuid.Nil},
+		ID:              reservation.ID,
+		WorkspaceID:     reservation.WorkspaceID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Settled or released concurrently
+			return settled, ErrSponsorshipReservationNotFound
+		}
+		return settled, fmt.Errorf("failed to settle sponsorship reservation: %w", err)
+	}
+
+	s.logger.Info("Settled gas sponsorship",
+		zap.String("workspace_id", settled.WorkspaceID.String()),
+		zap.String("reservation_id", settled.ID.String()),
+		zap.Int64("reserved_cents", settled.ReservedUsdCents),
+		zap.Int64("actual_gas_cents", actualGasCostCents),
+		zap.Int64("settled_cents", settled.SettledUsdCents.Int64))
+
+	s.checkBudgetAlerts(ctx, settled.WorkspaceID)
+
+	return settled, nil
+}
+
+func (s *GasSponsorshipService) releaseReservation(ctx context.Context, reservation db.GasSponsorshipReservation, reason string) (db.GasSponsorshipReservation, error) {
+	released, err := s.queries.ReleaseGasSponsorshipReservation(ctx, db.ReleaseGasSponsorshipReservationParams{
+		ReleaseReason: pgtype.Text{String: reason, Valid: reason != ""},
+		ID:            reservation.ID,
+		WorkspaceID:   reservation.WorkspaceID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return released, ErrSponsorshipReservationNotFound
+		}
+		return released, fmt.Errorf("failed to release sponsorship reservation: %w", err)
+	}
+
+	s.logger.Info("Released gas sponsorship",
+		zap.String("workspace_id", released.WorkspaceID.String()),
+		zap.String("reservation_id", released.ID.String()),
+		zap.Int64("released_cents", released.ReservedUsdCents),
+		zap.String("reason", reason))
+
+	return released, nil
+}
+
+// checkBudgetAlerts alerts on every threshold of the monthly budget that spending has reached and
+// that was not alerted on yet this month. Alerting never fails the settlement that triggered it.
+func (s *GasSponsorshipService) checkBudgetAlerts(ctx context.Context, workspaceID uuid.UUID) {
+	config, err := s.queries.GetGasSponsorshipConfig(ctx, workspaceID)
+	if err != nil {
+		s.logger.Warn("Failed to get sponsorship config for budget alerts",
+			zap.String("workspace_id", workspaceID.String()),
+			zap.Error(err))
+		return
+	}
+	if !config.MonthlyBudgetUsdCents.Valid || config.MonthlyBudgetUsdCents.Int64 <= 0 {
+		return
+	}
+
+	budget := config.MonthlyBudgetUsdCents.Int64
+	spent := config.CurrentMonthSpentCents.Int64
+	for _, threshold := range sponsorshipBudgetAlertThresholds {
+		if spent*100 < budget*int64(threshold) {
+			break
+		}
+
+		_, err := s.queries.RecordGasSponsorshipBudgetAlert(ctx, db.RecordGasSponsorshipBudgetAlertParams{
+			WorkspaceID:           workspaceID,
+			PeriodStart:           sponsorshipMonth(time.Now()),
+			ThresholdPercent:      threshold,
+			SpentUsdCents:         spent,
+			MonthlyBudgetUsdCents: budget,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Already alerted this month
+				continue
+			}
+			s.logger.Warn("Failed to record sponsorship budget alert",
+				zap.String("workspace_id", workspaceID.String()),
+				zap.Error(err))
+			return
+		}
+
+		s.logger.Warn("Gas sponsorship budget threshold reached",
+			zap.String("workspace_id", workspaceID.String()),
+			zap.Int32("threshold_percent", threshold),
+			zap.Int64("spent_cents", spent),
+			zap.Int64("monthly_budget_cents", budget))
+	}
+}
+
+// settledSponsorshipAmount is the reservation's share of the actual gas cost, at most the hold
+func settledSponsorshipAmount(reservation db.GasSponsorshipReservation, actualGasCostCents int64) int64 {
+	share := max(actualGasCostCents, 0) * int64(reservation.SponsorPercentage) / 100
+	return min(share, reservation.ReservedUsdCents)
+}
+
+// remainingSponsorshipBudget is the monthly budget not yet spent or held
+func remainingSponsorshipBudget(config db.GasSponsorshipConfig) int64 {
+	return config.MonthlyBudgetUsdCents.Int64 - config.CurrentMonthSpentCents.Int64 - config.CurrentMonthReservedCents
+}
+
+func toSponsorshipReservation(row db.GasSponsorshipReservation) business.SponsorshipReservation {
+	return business.SponsorshipReservation{
+		ID:                row.ID,
+		WorkspaceID:       row.WorkspaceID,
+		CustomerID:        uuid.UUID(row.CustomerID.Bytes),
+		PaymentID:         uuid.UUID(row.PaymentID.Bytes),
+		RuleID:            uuid.UUID(row.RuleID.Bytes),
+		Status:            row.Status,
+		ReservedCents:     row.ReservedUsdCents,
+		SettledCents:      row.SettledUsdCents.Int64,
+		SponsorPercentage: row.SponsorPercentage,
+		ReleaseReason:     row.ReleaseReason.String,
+		ExpiresAt:         row.ExpiresAt.Time,
+		CreatedAt:         row.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,384 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGasSponsorshipService_ReserveSponsorship(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewGasSponsorshipService(mockQuerier)
+	ctx := context.Background()
+
+	workspaceID := uuid.New()
+	customerID := uuid.New()
+	at := time.Date(2025, 3, 18, 12, 0, 0, 0, time.UTC)
+	rules := []db.GasSponsorshipRule{
+		sponsorshipRuleRow(t, workspaceID, "Half of everything", business.SponsorshipRuleConditions{}, business.SponsorshipActionSplitPercentage, 50),
+	}
+
+	config := enabledSponsorshipConfig(workspaceID)
+	config.MonthlyBudgetUsdCents = pgtype.Int8{Int64: 10000, Valid: true}
+	config.CurrentMonthSpentCents = pgtype.Int8{Int64: 2000, Valid: true}
+	config.CurrentMonthReservedCents = 1000
+
+	checkParams := params.SponsorshipCheckParams{
+		WorkspaceID:     workspaceID,
+		CustomerID:      customerID,
+		GasCostUSDCents: 200,
+		At:              at,
+	}
+
+	t.Run("holds the sponsored share", func(t *testing.T) {
+		reservation := db.GasSponsorshipReservation{
+			ID:                uuid.New(),
+			WorkspaceID:       workspaceID,
+			Status:            business.SponsorshipReservationReserved,
+			ReservedUsdCents:  100,
+			SponsorPercentage: 50,
+		}
+
+		mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(config, nil)
+		mockQuerier.EXPECT().ListActiveGasSponsorshipRules(ctx, workspaceID).Return(rules, nil)
+		mockQuerier.EXPECT().ReserveGasSponsorshipBudget(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.ReserveGasSponsorshipBudgetParams) (db.GasSponsorshipReservation, error) {
+				assert.Equal(t, int64(100), arg.ReservedUsdCents)
+				assert.Equal(t, int32(50), arg.SponsorPercentage)
+				assert.Equal(t, customerID, uuid.UUID(arg.CustomerID.Bytes))
+				assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), arg.PeriodStart.Time)
+				assert.True(t, arg.ExpiresAt.Time.After(time.Now()))
+				return reservation, nil
+			})
+		mockQuerier.EXPECT().ReserveGasSponsorshipCustomerSpending(ctx, db.ReserveGasSponsorshipCustomerSpendingParams{
+			ID: reservation.ID,
+		}).Return(reservation, nil)
+
+		decision, err := service.ReserveSponsorship(ctx, checkParams)
+		require.NoError(t, err)
+
+		assert.True(t, decision.ShouldSponsor)
+		assert.Equal(t, int64(100), decision.SponsoredAmountCents)
+		assert.Equal(t, int32(50), decision.SponsorPercentage)
+		assert.Equal(t, reservation.ID, decision.ReservationID)
+		// Held amounts count against the remaining budget
+		assert.Equal(t, int64(7000), decision.RemainingBudget)
+	})
+
+	t.Run("budget used up by a concurrent hold", func(t *testing.T) {
+		mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(config, nil)
+		mockQuerier.EXPECT().ListActiveGasSponsorshipRules(ctx, workspaceID).Return(rules, nil)
+		mockQuerier.EXPECT().ReserveGasSponsorshipBudget(ctx, gomock.Any()).Return(db.GasSponsorshipReservation{}, pgx.ErrNoRows)
+
+		decision, err := service.ReserveSponsorship(ctx, checkParams)
+		require.NoError(t, err)
+
+		assert.False(t, decision.ShouldSponsor)
+		assert.Zero(t, decision.SponsoredAmountCents)
+		assert.Equal(t, uuid.Nil, decision.ReservationID)
+		assert.Equal(t, "Monthly sponsorship budget exhausted", decision.Reason)
+	})
+
+	t.Run("customer cap used up by a concurrent hold", func(t *testing.T) {
+		capped := config
+		capped.PerCustomerMonthlyCapUsdCents = pgtype.Int8{Int64: 500, Valid: true}
+		reservation := db.GasSponsorshipReservation{ID: uuid.New(), WorkspaceID: workspaceID, ReservedUsdCents: 100}
+
+		mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(capped, nil)
+		mockQuerier.EXPECT().ListActiveGasSponsorshipRules(ctx, workspaceID).Return(rules, nil)
+		mockQuerier.EXPECT().GetGasSponsorshipCustomerSpending(ctx, gomock.Any()).Return(db.GasSponsorshipCustomerSpending{SpentUsdCents: 300}, nil)
+		mockQuerier.EXPECT().ReserveGasSponsorshipBudget(ctx, gomock.Any()).Return(reservation, nil)
+		mockQuerier.EXPECT().ReserveGasSponsorshipCustomerSpending(ctx, db.ReserveGasSponsorshipCustomerSpendingParams{
+			ID:          reservation.ID,
+			CapUsdCents: pgtype.Int8{Int64: 500, Valid: true},
+		}).Return(db.GasSponsorshipReservation{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().ReleaseGasSponsorshipReservation(ctx, db.ReleaseGasSponsorshipReservationParams{
+			ReleaseReason: pgtype.Text{String: "customer monthly cap reached", Valid: true},
+			ID:            reservation.ID,
+			WorkspaceID:   workspaceID,
+		}).Return(reservation, nil)
+
+		decision, err := service.ReserveSponsorship(ctx, checkParams)
+		require.NoError(t, err)
+
+		assert.False(t, decision.ShouldSponsor)
+		assert.Equal(t, "Customer monthly sponsorship cap reached", decision.Reason)
+	})
+
+	t.Run("nothing held when not sponsored", func(t *testing.T) {
+		mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(db.GasSponsorshipConfig{}, pgx.ErrNoRows)
+
+		decision, err := service.ReserveSponsorship(ctx, checkParams)
+		require.NoError(t, err)
+
+		assert.False(t, decision.ShouldSponsor)
+		assert.Equal(t, uuid.Nil, decision.ReservationID)
+	})
+}
+
+func TestGasSponsorshipService_SettleSponsorship(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewGasSponsorshipService(mockQuerier)
+	ctx := context.Background()
+
+	workspaceID := uuid.New()
+	paymentID := uuid.New()
+	reservation := db.GasSponsorshipReservation{
+		ID:                uuid.New(),
+		WorkspaceID:       workspaceID,
+		Status:            business.SponsorshipReservationReserved,
+		ReservedUsdCents:  100,
+		SponsorPercentage: 50,
+	}
+	getReservation := db.GetGasSponsorshipReservationParams{ID: reservation.ID, WorkspaceID: workspaceID}
+
+	config := enabledSponsorshipConfig(workspaceID)
+	config.MonthlyBudgetUsdCents = pgtype.Int8{Int64: 10000, Valid: true}
+
+	tests := []struct {
+		name          string
+		actualCost    int64
+		spentCents    int64
+		wantSettled   int64
+		wantAlertedAt []int32
+	}{
+		{
+			name:        "settles the sponsored share of the actual cost",
+			actualCost:  120,
+			spentCents:  3000,
+			wantSettled: 60,
+		},
+		{
+			name:        "never settles more than the hold",
+			actualCost:  500,
+			spentCents:  3000,
+			wantSettled: 100,
+		},
+		{
+			name:          "alerts on every threshold reached",
+			actualCost:    120,
+			spentCents:    8500,
+			wantSettled:   60,
+			wantAlertedAt: []int32{50, 80},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settled := reservation
+			settled.Status = business.SponsorshipReservationSettled
+			settled.SettledUsdCents = pgtype.Int8{Int64: tt.wantSettled, Valid: true}
+			settled.PaymentID = pgtype.UUID{Bytes: paymentID, Valid: true}
+
+			spentConfig := config
+			spentConfig.CurrentMonthSpentCents = pgtype.Int8{Int64: tt.spentCents, Valid: true}
+
+			mockQuerier.EXPECT().GetGasSponsorshipReservation(ctx, getReservation).Return(reservation, nil)
+			mockQuerier.EXPECT().SettleGasSponsorshipReservation(ctx, db.SettleGasSponsorshipReservationParams{
+				SettledUsdCents: tt.wantSettled,
+				PaymentID:       pgtype.UUID{Bytes: paymentID, Valid: true},
+				ID:              reservation.ID,
+				WorkspaceID:     workspaceID,
+			}).Return(settled, nil)
+			mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(spentConfig, nil)
+
+			var alerted []int32
+			mockQuerier.EXPECT().RecordGasSponsorshipBudgetAlert(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, arg db.RecordGasSponsorshipBudgetAlertParams) (db.GasSponsorshipBudgetAlert, error) {
+					alerted = append(alerted, arg.ThresholdPercent)
+					return db.GasSponsorshipBudgetAlert{WorkspaceID: workspaceID, ThresholdPercent: arg.ThresholdPercent}, nil
+				}).Times(len(tt.wantAlertedAt))
+
+			result, err := service.SettleSponsorship(ctx, params.SponsorshipSettlementParams{
+				WorkspaceID:           workspaceID,
+				ReservationID:         reservation.ID,
+				PaymentID:             paymentID,
+				ActualGasCostUSDCents: tt.actualCost,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, business.SponsorshipReservationSettled, result.Status)
+			assert.Equal(t, tt.wantSettled, result.SettledCents)
+			assert.Equal(t, paymentID, result.PaymentID)
+			assert.Equal(t, tt.wantAlertedAt, alerted)
+		})
+	}
+
+	t.Run("thresholds alerted before are skipped", func(t *testing.T) {
+		settled := reservation
+		settled.Status = business.SponsorshipReservationSettled
+		spentConfig := config
+		spentConfig.CurrentMonthSpentCents = pgtype.Int8{Int64: 10000, Valid: true}
+
+		mockQuerier.EXPECT().GetGasSponsorshipReservation(ctx, getReservation).Return(reservation, nil)
+		mockQuerier.EXPECT().SettleGasSponsorshipReservation(ctx, gomock.Any()).Return(settled, nil)
+		mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(spentConfig, nil)
+		mockQuerier.EXPECT().RecordGasSponsorshipBudgetAlert(ctx, gomock.Any()).Return(db.GasSponsorshipBudgetAlert{}, pgx.ErrNoRows).Times(3)
+
+		_, err := service.SettleSponsorship(ctx, params.SponsorshipSettlementParams{
+			WorkspaceID:           workspaceID,
+			ReservationID:         reservation.ID,
+			ActualGasCostUSDCents: 100,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("reservation already released", func(t *testing.T) {
+		released := reservation
+		released.Status = business.SponsorshipReservationReleased
+		mockQuerier.EXPECT().GetGasSponsorshipReservation(ctx, getReservation).Return(released, nil)
+
+		_, err := service.SettleSponsorship(ctx, params.SponsorshipSettlementParams{
+			WorkspaceID:           workspaceID,
+			ReservationID:         reservation.ID,
+			ActualGasCostUSDCents: 100,
+		})
+		assert.ErrorIs(t, err, services.ErrSponsorshipReservationNotFound)
+	})
+
+	t.Run("no open reservation for payment", func(t *testing.T) {
+		mockQuerier.EXPECT().GetOpenGasSponsorshipReservationByPayment(ctx, db.GetOpenGasSponsorshipReservationByPaymentParams{
+			PaymentID:   pgtype.UUID{Bytes: paymentID, Valid: true},
+			WorkspaceID: workspaceID,
+		}).Return(db.GasSponsorshipReservation{}, pgx.ErrNoRows)
+
+		_, err := service.SettleSponsorship(ctx, params.SponsorshipSettlementParams{
+			WorkspaceID:           workspaceID,
+			PaymentID:             paymentID,
+			ActualGasCostUSDCents: 100,
+		})
+		assert.ErrorIs(t, err, services.ErrSponsorshipReservationNotFound)
+	})
+}
+
+func TestGasSponsorshipService_ReleaseSponsorship(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewGasSponsorshipService(mockQuerier)
+	ctx := context.Background()
+
+	workspaceID := uuid.New()
+	paymentID := uuid.New()
+	reservation := db.GasSponsorshipReservation{
+		ID:               uuid.New(),
+		WorkspaceID:      workspaceID,
+		Status:           business.SponsorshipReservationReserved,
+		ReservedUsdCents: 100,
+	}
+
+	released := reservation
+	released.Status = business.SponsorshipReservationReleased
+	released.ReleaseReason = pgtype.Text{String: "transaction failed", Valid: true}
+
+	mockQuerier.EXPECT().GetOpenGasSponsorshipReservationByPayment(ctx, gomock.Any()).Return(reservation, nil)
+	mockQuerier.EXPECT().ReleaseGasSponsorshipReservation(ctx, db.ReleaseGasSponsorshipReservationParams{
+		ReleaseReason: pgtype.Text{String: "transaction failed", Valid: true},
+		ID:            reservation.ID,
+		WorkspaceID:   workspaceID,
+	}).Return(released, nil)
+
+	result, err := service.ReleaseSponsorship(ctx, params.SponsorshipReleaseParams{
+		WorkspaceID: workspaceID,
+		PaymentID:   paymentID,
+		Reason:      "transaction failed",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, business.SponsorshipReservationReleased, result.Status)
+	assert.Equal(t, "transaction failed", result.ReleaseReason)
+}
+
+func TestGasSponsorshipService_ReleaseExpiredSponsorships(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewGasSponsorshipService(mockQuerier)
+	ctx := context.Background()
+
+	expired := []db.GasSponsorshipReservation{
+		{ID: uuid.New(), WorkspaceID: uuid.New()},
+		{ID: uuid.New(), WorkspaceID: uuid.New()},
+		{ID: uuid.New(), WorkspaceID: uuid.New()},
+	}
+
+	mockQuerier.EXPECT().ListExpiredGasSponsorshipReservations(ctx, gomock.Any()).Return(expired, nil)
+	mockQuerier.EXPECT().ReleaseGasSponsorshipReservation(ctx, gomock.Any()).Return(expired[0], nil)
+	// Settled after it was listed
+	mockQuerier.EXPECT().ReleaseGasSponsorshipReservation(ctx, gomock.Any()).Return(db.GasSponsorshipReservation{}, pgx.ErrNoRows)
+	mockQuerier.EXPECT().ReleaseGasSponsorshipReservation(ctx, gomock.Any()).Return(expired[2], nil)
+
+	released, err := service.ReleaseExpiredSponsorships(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, released)
+}
+
+func TestGasSponsorshipService_ListSponsorshipLedger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewGasSponsorshipService(mockQuerier)
+	ctx := context.Background()
+
+	workspaceID := uuid.New()
+	reservationID := uuid.New()
+	rows := []db.GasSponsorshipLedger{
+		{
+			ID:                    uuid.New(),
+			WorkspaceID:           workspaceID,
+			ReservationID:         reservationID,
+			EntryType:             "settle",
+			AmountUsdCents:        60,
+			BudgetSpentCents:      3060,
+			MonthlyBudgetUsdCents: pgtype.Int8{Int64: 10000, Valid: true},
+		},
+		{
+			ID:                  uuid.New(),
+			WorkspaceID:         workspaceID,
+			ReservationID:       reservationID,
+			EntryType:           "reserve",
+			AmountUsdCents:      100,
+			BudgetSpentCents:    3000,
+			BudgetReservedCents: 100,
+		},
+	}
+
+	mockQuerier.EXPECT().ListGasSponsorshipLedgerEntries(ctx, db.ListGasSponsorshipLedgerEntriesParams{
+		WorkspaceID: workspaceID,
+		Limit:       20,
+		Offset:      0,
+	}).Return(rows, nil)
+	mockQuerier.EXPECT().CountGasSponsorshipLedgerEntries(ctx, workspaceID).Return(int64(2), nil)
+
+	entries, total, err := service.ListSponsorshipLedger(ctx, workspaceID, 20, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), total)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "settle", entries[0].EntryType)
+	require.NotNil(t, entries[0].MonthlyBudgetCents)
+	assert.Equal(t, int64(10000), *entries[0].MonthlyBudgetCents)
+	assert.Nil(t, entries[1].MonthlyBudgetCents)
+	assert.Equal(t, int64(100), entries[1].BudgetReservedCents)
+}
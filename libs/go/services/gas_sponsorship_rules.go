@@ -0,0 +1,378 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidSponsorshipRule is returned when a sponsorship rule's conditions or action are out of range
+var ErrInvalidSponsorshipRule = errors.New("invalid sponsorship rule")
+
+// Condition names reported when a rule does not match a transaction
+const (
+	sponsorshipConditionNetwork         = "network"
+	sponsorshipConditionToken           = "token"
+	sponsorshipConditionProduct         = "product"
+	sponsorshipConditionCustomerTier    = "customer_tier"
+	sponsorshipConditionTransactionType = "transaction_type"
+	sponsorshipConditionGasPrice        = "max_gas_price"
+	sponsorshipConditionTimeWindow      = "time_window"
+)
+
+var sponsorshipWeekdays = map[string]bool{
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true,
+	"friday": true, "saturday": true, "sunday": true,
+}
+
+// ListSponsorshipRules returns a workspace's sponsorship rules in evaluation order
+func (s *GasSponsorshipService) ListSponsorshipRules(ctx context.Context, workspaceID uuid.UUID) ([]business.SponsorshipRule, error) {
+	rows, err := s.queries.ListGasSponsorshipRules(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sponsorship rules: %w", err)
+	}
+
+	rules := make([]business.SponsorshipRule, 0, len(rows))
+	for _, row := range rows {
+		rule, err := toSponsorshipRule(row)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// CreateSponsorshipRule adds a rule to a workspace's sponsorship policy
+func (s *GasSponsorshipService) CreateSponsorshipRule(ctx context.Context, ruleParams params.SponsorshipRuleParams) (*business.SponsorshipRule, error) {
+	conditions, upTo, percentage, err := sponsorshipRuleColumns(ruleParams)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.queries.CreateGasSponsorshipRule(ctx, db.CreateGasSponsorshipRuleParams{
+		WorkspaceID:         ruleParams.WorkspaceID,
+		Name:                strings.TrimSpace(ruleParams.Name),
+		Priority:            ruleParams.Priority,
+		IsActive:            ruleParams.IsActive,
+		Conditions:          conditions,
+		ActionType:          string(ruleParams.Action),
+		SponsorUpToUsdCents: upTo,
+		SponsorPercentage:   percentage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sponsorship rule: %w", err)
+	}
+
+	s.logger.Info("Created gas sponsorship rule",
+		zap.String("workspace_id", ruleParams.WorkspaceID.String()),
+		zap.String("rule_id", row.ID.String()),
+		zap.String("action", row.ActionType))
+
+	rule, err := toSponsorshipRule(row)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateSponsorshipRule replaces a sponsorship rule
+func (s *GasSponsorshipService) UpdateSponsorshipRule(ctx context.Context, ruleID uuid.UUID, ruleParams params.SponsorshipRuleParams) (*business.SponsorshipRule, error) {
+	conditions, upTo, percentage, err := sponsorshipRuleColumns(ruleParams)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.queries.UpdateGasSponsorshipRule(ctx, db.UpdateGasSponsorshipRuleParams{
+		ID:                  ruleID,
+		WorkspaceID:         ruleParams.WorkspaceID,
+		Name:                strings.TrimSpace(ruleParams.Name),
+		Priority:            ruleParams.Priority,
+		IsActive:            ruleParams.IsActive,
+		Conditions:          conditions,
+		ActionType:          string(ruleParams.Action),
+		SponsorUpToUsdCents: upTo,
+		SponsorPercentage:   percentage,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update sponsorship rule: %w", err)
+	}
+
+	rule, err := toSponsorshipRule(row)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteSponsorshipRule removes a rule from a workspace's sponsorship policy
+func (s *GasSponsorshipService) DeleteSponsorshipRule(ctx context.Context, workspaceID, ruleID uuid.UUID) error {
+	deleted, err := s.queries.DeleteGasSponsorshipRule(ctx, db.DeleteGasSponsorshipRuleParams{
+		ID:          ruleID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete sponsorship rule: %w", err)
+	}
+	if deleted == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// sponsorshipRuleColumns validates rule parameters and converts them to their database columns
+func sponsorshipRuleColumns(ruleParams params.SponsorshipRuleParams) ([]byte, pgtype.Int8, pgtype.Int4, error) {
+	var upTo pgtype.Int8
+	var percentage pgtype.Int4
+
+	if strings.TrimSpace(ruleParams.Name) == "" {
+		return nil, upTo, percentage, fmt.Errorf("%w: name is required", ErrInvalidSponsorshipRule)
+	}
+
+	switch ruleParams.Action {
+	case business.SponsorshipActionFull:
+	case business.SponsorshipActionUpTo:
+		if ruleParams.SponsorUpToUSDCents <= 0 {
+			return nil, upTo, percentage, fmt.Errorf("%w: sponsor_up_to requires a positive amount", ErrInvalidSponsorshipRule)
+		}
+		upTo = pgtype.Int8{Int64: ruleParams.SponsorUpToUSDCents, Valid: true}
+	case business.SponsorshipActionSplitPercentage:
+		if ruleParams.SponsorPercentage < 1 || ruleParams.SponsorPercentage > 100 {
+			return nil, upTo, percentage, fmt.Errorf("%w: split_percentage requires a percentage between 1 and 100", ErrInvalidSponsorshipRule)
+		}
+		percentage = pgtype.Int4{Int32: ruleParams.SponsorPercentage, Valid: true}
+	default:
+		return nil, upTo, percentage, fmt.Errorf("%w: unknown action %q", ErrInvalidSponsorshipRule, ruleParams.Action)
+	}
+
+	if err := validateSponsorshipConditions(ruleParams.Conditions); err != nil {
+		return nil, upTo, percentage, err
+	}
+
+	conditions, err := json.Marshal(ruleParams.Conditions)
+	if err != nil {
+		return nil, upTo, percentage, fmt.Errorf("failed to marshal rule conditions: %w", err)
+	}
+	return conditions, upTo, percentage, nil
+}
+
+// validateSponsorshipConditions rejects conditions that could never be evaluated
+func validateSponsorshipConditions(conditions business.SponsorshipRuleConditions) error {
+	if conditions.MaxGasPriceGwei != nil && *conditions.MaxGasPriceGwei <= 0 {
+		return fmt.Errorf("%w: max_gas_price_gwei must be positive", ErrInvalidSponsorshipRule)
+	}
+
+	window := conditions.TimeWindow
+	if window == nil {
+		return nil
+	}
+	if window.Timezone != "" {
+		if _, err := time.LoadLocation(window.Timezone); err != nil {
+			return fmt.Errorf("%w: unknown timezone %q", ErrInvalidSponsorshipRule, window.Timezone)
+		}
+	}
+	if window.StartsAt != nil && window.EndsAt != nil && !window.EndsAt.After(*window.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidSponsorshipRule)
+	}
+	for _, day := range window.Weekdays {
+		if !sponsorshipWeekdays[strings.ToLower(day)] {
+			return fmt.Errorf("%w: unknown weekday %q", ErrInvalidSponsorshipRule, day)
+		}
+	}
+	if (window.StartTime == "") != (window.EndTime == "") {
+		return fmt.Errorf("%w: start_time and end_time must be set together", ErrInvalidSponsorshipRule)
+	}
+	if window.StartTime != "" {
+		start, err := parseClockMinutes(window.StartTime)
+		if err != nil {
+			return fmt.Errorf("%w: start_time must be HH:MM", ErrInvalidSponsorshipRule)
+		}
+		end, err := parseClockMinutes(window.EndTime)
+		if err != nil {
+			return fmt.Errorf("%w: end_time must be HH:MM", ErrInvalidSponsorshipRule)
+		}
+		if start == end {
+			return fmt.Errorf("%w: start_time and end_time must differ", ErrInvalidSponsorshipRule)
+		}
+	}
+	return nil
+}
+
+// toSponsorshipRule converts a database rule to its business representation
+func toSponsorshipRule(row db.GasSponsorshipRule) (business.SponsorshipRule, error) {
+	rule := business.SponsorshipRule{
+		ID:                  row.ID,
+		WorkspaceID:         row.WorkspaceID,
+		Name:                row.Name,
+		Priority:            row.Priority,
+		IsActive:            row.IsActive,
+		Action:              business.SponsorshipAction(row.ActionType),
+		SponsorUpToUSDCents: row.SponsorUpToUsdCents.Int64,
+		SponsorPercentage:   row.SponsorPercentage.Int32,
+		CreatedAt:           row.CreatedAt.Time,
+		UpdatedAt:           row.UpdatedAt.Time,
+	}
+	if len(row.Conditions) > 0 {
+		if err := json.Unmarshal(row.Conditions, &rule.Conditions); err != nil {
+			return rule, fmt.Errorf("failed to parse conditions of sponsorship rule %s: %w", row.ID, err)
+		}
+	}
+	return rule, nil
+}
+
+// unmatchedSponsorshipConditions returns the names of the conditions a transaction does not meet
+func unmatchedSponsorshipConditions(conditions business.SponsorshipRuleConditions, params params.SponsorshipCheckParams, at time.Time) []string {
+	var failed []string
+
+	if len(conditions.NetworkIDs) > 0 && !containsUUID(conditions.NetworkIDs, params.NetworkID) {
+		failed = append(failed, sponsorshipConditionNetwork)
+	}
+	if len(conditions.TokenIDs) > 0 && !containsUUID(conditions.TokenIDs, params.TokenID) {
+		failed = append(failed, sponsorshipConditionToken)
+	}
+	if len(conditions.ProductIDs) > 0 && !containsUUID(conditions.ProductIDs, params.ProductID) {
+		failed = append(failed, sponsorshipConditionProduct)
+	}
+	if len(conditions.CustomerTiers) > 0 && !containsFold(conditions.CustomerTiers, params.CustomerTier) {
+		failed = append(failed, sponsorshipConditionCustomerTier)
+	}
+	if len(conditions.TransactionTypes) > 0 && !containsFold(conditions.TransactionTypes, params.TransactionType) {
+		failed = append(failed, sponsorshipConditionTransactionType)
+	}
+	if conditions.MaxGasPriceGwei != nil && !gasPriceAtMost(params.GasPriceWei, *conditions.MaxGasPriceGwei) {
+		failed = append(failed, sponsorshipConditionGasPrice)
+	}
+	if conditions.TimeWindow != nil && !timeWindowContains(*conditions.TimeWindow, at) {
+		failed = append(failed, sponsorshipConditionTimeWindow)
+	}
+	return failed
+}
+
+// sponsoredAmount applies a rule's action to a gas cost
+func sponsoredAmount(rule business.SponsorshipRule, gasCostCents int64) int64 {
+	switch rule.Action {
+	case business.SponsorshipActionFull:
+		return gasCostCents
+	case business.SponsorshipActionUpTo:
+		return min(gasCostCents, rule.SponsorUpToUSDCents)
+	case business.SponsorshipActionSplitPercentage:
+		return gasCostCents * int64(rule.SponsorPercentage) / 100
+	default:
+		return 0
+	}
+}
+
+// rejectSponsorship turns an approved decision back into the customer paying the gas
+func rejectSponsorship(decision *business.SponsorshipDecision, reason string) {
+	decision.ShouldSponsor = false
+	decision.SponsorType = "customer"
+	decision.SponsorID = uuid.Nil
+	decision.SponsoredAmountCents = 0
+	decision.Reason = reason
+}
+
+// gasPriceAtMost reports whether a known gas price is at or below a ceiling in gwei
+func gasPriceAtMost(gasPriceWei *big.Int, maxGwei float64) bool {
+	if gasPriceWei == nil {
+		return false
+	}
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(gasPriceWei), big.NewFloat(1e9)).Float64()
+	return gwei <= maxGwei
+}
+
+// timeWindowContains reports whether at falls inside a rule's time window
+func timeWindowContains(window business.SponsorshipTimeWindow, at time.Time) bool {
+	if window.StartsAt != nil && at.Before(*window.StartsAt) {
+		return false
+	}
+	if window.EndsAt != nil && !at.Before(*window.EndsAt) {
+		return false
+	}
+
+	loc := time.UTC
+	if window.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(window.Timezone); err != nil {
+			return false
+		}
+	}
+	local := at.In(loc)
+
+	if len(window.Weekdays) > 0 && !containsFold(window.Weekdays, local.Weekday().String()) {
+		return false
+	}
+
+	if window.StartTime != "" {
+		start, err := parseClockMinutes(window.StartTime)
+		if err != nil {
+			return false
+		}
+		end, err := parseClockMinutes(window.EndTime)
+		if err != nil {
+			return false
+		}
+		minute := local.Hour()*60 + local.Minute()
+		if start < end {
+			return minute >= start && minute < end
+		}
+		// The window wraps past midnight
+		return minute >= start || minute < end
+	}
+	return true
+}
+
+// parseClockMinutes parses "HH:MM" into minutes after midnight
+func parseClockMinutes(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// sponsorshipTime is the moment a sponsorship check is evaluated at
+func sponsorshipTime(params params.SponsorshipCheckParams) time.Time {
+	if params.At.IsZero() {
+		return time.Now()
+	}
+	return params.At
+}
+
+// sponsorshipMonth is the first day of the calendar month (UTC) per-customer caps are counted in
+func sponsorshipMonth(at time.Time) pgtype.Date {
+	at = at.UTC()
+	return pgtype.Date{Time: time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,353 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// sponsorshipRuleRow builds a stored sponsorship rule
+func sponsorshipRuleRow(t *testing.T, workspaceID uuid.UUID, name string, conditions business.SponsorshipRuleConditions, action business.SponsorshipAction, amount int64) db.GasSponsorshipRule {
+	t.Helper()
+
+	conditionsJSON, err := json.Marshal(conditions)
+	require.NoError(t, err)
+
+	row := db.GasSponsorshipRule{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		Name:        name,
+		IsActive:    true,
+		Conditions:  conditionsJSON,
+		ActionType:  string(action),
+	}
+	switch action {
+	case business.SponsorshipActionUpTo:
+		row.SponsorUpToUsdCents = pgtype.Int8{Int64: amount, Valid: true}
+	case business.SponsorshipActionSplitPercentage:
+		row.SponsorPercentage = pgtype.Int4{Int32: int32(amount), Valid: true}
+	}
+	return row
+}
+
+func enabledSponsorshipConfig(workspaceID uuid.UUID) db.GasSponsorshipConfig {
+	return db.GasSponsorshipConfig{
+		WorkspaceID:        workspaceID,
+		SponsorshipEnabled: pgtype.Bool{Bool: true, Valid: true},
+		SponsorCustomerGas: pgtype.Bool{Bool: true, Valid: true},
+	}
+}
+
+func TestGasSponsorshipService_RuleEvaluation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewGasSponsorshipService(mockQuerier)
+	ctx := context.Background()
+
+	workspaceID := uuid.New()
+	baseNetworkID := uuid.New()
+	usdcID := uuid.New()
+	maxGwei := 30.0
+	// Monday 10:00 in New York
+	weekdayMorning := time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC)
+
+	rules := []db.GasSponsorshipRule{
+		sponsorshipRuleRow(t, workspaceID, "Enterprise on Base", business.SponsorshipRuleConditions{
+			NetworkIDs:    []uuid.UUID{baseNetworkID},
+			CustomerTiers: []string{"enterprise"},
+		}, business.SponsorshipActionFull, 0),
+		sponsorshipRuleRow(t, workspaceID, "Cheap USDC subscriptions", business.SponsorshipRuleConditions{
+			TokenIDs:         []uuid.UUID{usdcID},
+			TransactionTypes: []string{"subscription"},
+			MaxGasPriceGwei:  &maxGwei,
+		}, business.SponsorshipActionUpTo, 50),
+		sponsorshipRuleRow(t, workspaceID, "Business hours", business.SponsorshipRuleConditions{
+			TimeWindow: &business.SponsorshipTimeWindow{
+				Weekdays:  []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+				StartTime: "09:00",
+				EndTime:   "17:00",
+				Timezone:  "America/New_York",
+			},
+		}, business.SponsorshipActionSplitPercentage, 25),
+	}
+
+	tests := []struct {
+		name          string
+		params        params.SponsorshipCheckParams
+		wantSponsor   bool
+		wantRule      string
+		wantSponsored int64
+	}{
+		{
+			name: "first matching rule wins",
+			params: params.SponsorshipCheckParams{
+				NetworkID:       baseNetworkID,
+				TokenID:         usdcID,
+				CustomerTier:    "Enterprise",
+				TransactionType: "subscription",
+				GasCostUSDCents: 120,
+				At:              weekdayMorning,
+			},
+			wantSponsor:   true,
+			wantRule:      "Enterprise on Base",
+			wantSponsored: 120,
+		},
+		{
+			name: "sponsor up to a fixed amount",
+			params: params.SponsorshipCheckParams{
+				TokenID:         usdcID,
+				TransactionType: "subscription",
+				GasPriceWei:     big.NewInt(20_000_000_000),
+				GasCostUSDCents: 120,
+				At:              weekdayMorning.Add(-12 * time.Hour),
+			},
+			wantSponsor:   true,
+			wantRule:      "Cheap USDC subscriptions",
+			wantSponsored: 50,
+		},
+		{
+			name: "gas price above the ceiling falls through to the time window",
+			params: params.SponsorshipCheckParams{
+				TokenID:         usdcID,
+				TransactionType: "subscription",
+				GasPriceWei:     big.NewInt(45_000_000_000),
+				GasCostUSDCents: 120,
+				At:              weekdayMorning,
+			},
+			wantSponsor:   true,
+			wantRule:      "Business hours",
+			wantSponsored: 30,
+		},
+		{
+			name: "no rule matches outside the window",
+			params: params.SponsorshipCheckParams{
+				GasCostUSDCents: 120,
+				At:              time.Date(2025, 3, 8, 15, 0, 0, 0, time.UTC), // Saturday
+			},
+			wantSponsor: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.WorkspaceID = workspaceID
+			mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(enabledSponsorshipConfig(workspaceID), nil)
+			mockQuerier.EXPECT().ListActiveGasSponsorshipRules(ctx, workspaceID).Return(rules, nil)
+
+			decision, err := service.ShouldSponsorGas(ctx, tt.params)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantSponsor, decision.ShouldSponsor)
+			assert.Equal(t, tt.wantSponsored, decision.SponsoredAmountCents)
+			if tt.wantSponsor {
+				assert.Equal(t, tt.wantRule, decision.RuleName)
+				assert.Equal(t, "merchant", decision.SponsorType)
+			} else {
+				assert.Equal(t, "No sponsorship rule matched", decision.Reason)
+			}
+		})
+	}
+}
+
+func TestGasSponsorshipService_CapsLimitSponsoredAmount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewGasSponsorshipService(mockQuerier)
+	ctx := context.Background()
+
+	workspaceID := uuid.New()
+	customerID := uuid.New()
+	at := time.Date(2025, 3, 18, 12, 0, 0, 0, time.UTC)
+	rules := []db.GasSponsorshipRule{
+		sponsorshipRuleRow(t, workspaceID, "Everything", business.SponsorshipRuleConditions{}, business.SponsorshipActionFull, 0),
+	}
+
+	config := enabledSponsorshipConfig(workspaceID)
+	config.MonthlyBudgetUsdCents = pgtype.Int8{Int64: 10000, Valid: true}
+	config.CurrentMonthSpentCents = pgtype.Int8{Int64: 9900, Valid: true}
+	config.PerCustomerMonthlyCapUsdCents = pgtype.Int8{Int64: 500, Valid: true}
+
+	t.Run("remaining budget and customer cap", func(t *testing.T) {
+		mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(config, nil)
+		mockQuerier.EXPECT().ListActiveGasSponsorshipRules(ctx, workspaceID).Return(rules, nil)
+		mockQuerier.EXPECT().GetGasSponsorshipCustomerSpending(ctx, db.GetGasSponsorshipCustomerSpendingParams{
+			WorkspaceID: workspaceID,
+			CustomerID:  customerID,
+			PeriodStart: pgtype.Date{Time: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		}).Return(db.GasSponsorshipCustomerSpending{SpentUsdCents: 440}, nil)
+
+		decision, err := service.ShouldSponsorGas(ctx, params.SponsorshipCheckParams{
+			WorkspaceID:     workspaceID,
+			CustomerID:      customerID,
+			GasCostUSDCents: 150,
+			At:              at,
+		})
+		require.NoError(t, err)
+
+		assert.True(t, decision.ShouldSponsor)
+		// Budget leaves 100, the customer cap 60
+		assert.Equal(t, int64(60), decision.SponsoredAmountCents)
+		assert.Equal(t, int64(100), decision.RemainingBudget)
+	})
+
+	t.Run("customer cap reached", func(t *testing.T) {
+		mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(config, nil)
+		mockQuerier.EXPECT().ListActiveGasSponsorshipRules(ctx, workspaceID).Return(rules, nil)
+		mockQuerier.EXPECT().GetGasSponsorshipCustomerSpending(ctx, gomock.Any()).Return(db.GasSponsorshipCustomerSpending{SpentUsdCents: 500}, nil)
+
+		decision, err := service.ShouldSponsorGas(ctx, params.SponsorshipCheckParams{
+			WorkspaceID:     workspaceID,
+			CustomerID:      customerID,
+			GasCostUSDCents: 150,
+			At:              at,
+		})
+		require.NoError(t, err)
+
+		assert.False(t, decision.ShouldSponsor)
+		assert.Zero(t, decision.SponsoredAmountCents)
+		assert.Equal(t, "Customer monthly sponsorship cap reached", decision.Reason)
+	})
+
+	t.Run("config lists respect the customer cap", func(t *testing.T) {
+		mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(config, nil)
+		mockQuerier.EXPECT().ListActiveGasSponsorshipRules(ctx, workspaceID).Return(nil, nil)
+		mockQuerier.EXPECT().GetGasSponsorshipCustomerSpending(ctx, gomock.Any()).Return(db.GasSponsorshipCustomerSpending{}, pgx.ErrNoRows)
+
+		decision, err := service.ShouldSponsorGas(ctx, params.SponsorshipCheckParams{
+			WorkspaceID:     workspaceID,
+			CustomerID:      customerID,
+			GasCostUSDCents: 80,
+			At:              at,
+		})
+		require.NoError(t, err)
+
+		assert.True(t, decision.ShouldSponsor)
+		assert.Equal(t, int64(80), decision.SponsoredAmountCents)
+		assert.Equal(t, uuid.Nil, decision.RuleID)
+	})
+}
+
+func TestGasSponsorshipService_SimulateSponsorship(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewGasSponsorshipService(mockQuerier)
+	ctx := context.Background()
+
+	workspaceID := uuid.New()
+	productID := uuid.New()
+	rules := []db.GasSponsorshipRule{
+		sponsorshipRuleRow(t, workspaceID, "Other product", business.SponsorshipRuleConditions{
+			ProductIDs: []uuid.UUID{uuid.New()},
+		}, business.SponsorshipActionFull, 0),
+		sponsorshipRuleRow(t, workspaceID, "Half for this product", business.SponsorshipRuleConditions{
+			ProductIDs: []uuid.UUID{productID},
+		}, business.SponsorshipActionSplitPercentage, 50),
+		sponsorshipRuleRow(t, workspaceID, "Catch-all", business.SponsorshipRuleConditions{}, business.SponsorshipActionUpTo, 10),
+	}
+
+	mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(enabledSponsorshipConfig(workspaceID), nil)
+	mockQuerier.EXPECT().ListActiveGasSponsorshipRules(ctx, workspaceID).Return(rules, nil)
+
+	simulation, err := service.SimulateSponsorship(ctx, params.SponsorshipCheckParams{
+		WorkspaceID:     workspaceID,
+		ProductID:       productID,
+		GasCostUSDCents: 101,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, simulation.Decision.ShouldSponsor)
+	assert.Equal(t, "Half for this product", simulation.Decision.RuleName)
+	assert.Equal(t, int64(50), simulation.Decision.SponsoredAmountCents)
+
+	require.Len(t, simulation.Evaluations, 3)
+	assert.False(t, simulation.Evaluations[0].Matched)
+	assert.Equal(t, []string{"product"}, simulation.Evaluations[0].FailedConditions)
+	assert.True(t, simulation.Evaluations[1].Fired)
+	assert.True(t, simulation.Evaluations[2].Matched)
+	assert.False(t, simulation.Evaluations[2].Fired)
+}
+
+func TestGasSponsorshipService_CreateSponsorshipRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewGasSponsorshipService(mockQuerier)
+	ctx := context.Background()
+	workspaceID := uuid.New()
+
+	t.Run("valid rule", func(t *testing.T) {
+		mockQuerier.EXPECT().CreateGasSponsorshipRule(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.CreateGasSponsorshipRuleParams) (db.GasSponsorshipRule, error) {
+				assert.Equal(t, "Split", arg.Name)
+				assert.Equal(t, "split_percentage", arg.ActionType)
+				assert.Equal(t, pgtype.Int4{Int32: 40, Valid: true}, arg.SponsorPercentage)
+				assert.False(t, arg.SponsorUpToUsdCents.Valid)
+				assert.JSONEq(t, `{"transaction_types":["subscription"]}`, string(arg.Conditions))
+				return db.GasSponsorshipRule{
+					ID:                uuid.New(),
+					WorkspaceID:       arg.WorkspaceID,
+					Name:              arg.Name,
+					IsActive:          arg.IsActive,
+					Conditions:        arg.Conditions,
+					ActionType:        arg.ActionType,
+					SponsorPercentage: arg.SponsorPercentage,
+				}, nil
+			})
+
+		rule, err := service.CreateSponsorshipRule(ctx, params.SponsorshipRuleParams{
+			WorkspaceID:       workspaceID,
+			Name:              " Split ",
+			IsActive:          true,
+			Conditions:        business.SponsorshipRuleConditions{TransactionTypes: []string{"subscription"}},
+			Action:            business.SponsorshipActionSplitPercentage,
+			SponsorPercentage: 40,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int32(40), rule.SponsorPercentage)
+		assert.Equal(t, []string{"subscription"}, rule.Conditions.TransactionTypes)
+	})
+
+	invalid := []struct {
+		name   string
+		params params.SponsorshipRuleParams
+	}{
+		{"missing name", params.SponsorshipRuleParams{Action: business.SponsorshipActionFull}},
+		{"unknown action", params.SponsorshipRuleParams{Name: "x", Action: "sponsor_some"}},
+		{"up to without amount", params.SponsorshipRuleParams{Name: "x", Action: business.SponsorshipActionUpTo}},
+		{"percentage out of range", params.SponsorshipRuleParams{Name: "x", Action: business.SponsorshipActionSplitPercentage, SponsorPercentage: 120}},
+		{"unknown timezone", params.SponsorshipRuleParams{Name: "x", Action: business.SponsorshipActionFull, Conditions: business.SponsorshipRuleConditions{
+			TimeWindow: &business.SponsorshipTimeWindow{Timezone: "Mars/Olympus"},
+		}}},
+		{"half-open hours", params.SponsorshipRuleParams{Name: "x", Action: business.SponsorshipActionFull, Conditions: business.SponsorshipRuleConditions{
+			TimeWindow: &business.SponsorshipTimeWindow{StartTime: "09:00"},
+		}}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.WorkspaceID = workspaceID
+			_, err := service.CreateSponsorshipRule(ctx, tt.params)
+			assert.ErrorIs(t, err, services.ErrInvalidSponsorshipRule)
+		})
+	}
+}
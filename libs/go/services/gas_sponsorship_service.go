@@ -40,19 +40,48 @@ func (s *GasSponsorshipService) ShouldSponsorGas(ctx context.Context, params par
 		zap.String("customer_tier", params.CustomerTier),
 		zap.String("transaction_type", params.TransactionType))
 
+	simulation, err := s.evaluateSponsorship(ctx, params, false)
+	if err != nil {
+		return nil, err
+	}
+	decision := simulation.Decision
+
+	if decision.ShouldSponsor {
+		s.logger.Info("Gas sponsorship approved",
+			zap.String("workspace_id", params.WorkspaceID.String()),
+			zap.String("reason", decision.Reason),
+			zap.String("rule_id", decision.RuleID.String()),
+			zap.Int64("sponsored_cents", decision.SponsoredAmountCents),
+			zap.Int64("remaining_budget", decision.RemainingBudget))
+	}
+
+	return decision, nil
+}
+
+// SimulateSponsorship evaluates the sponsorship policy for a hypothetical transaction without
+// recording anything, reporting which rule would fire and why the others did not
+func (s *GasSponsorshipService) SimulateSponsorship(ctx context.Context, params params.SponsorshipCheckParams) (*business.SponsorshipSimulation, error) {
+	return s.evaluateSponsorship(ctx, params, true)
+}
+
+// evaluateSponsorship runs the workspace's sponsorship policy. Workspaces with active rules are
+// decided by the first matching rule; workspaces without rules use the product, customer and tier
+// lists of their config. The monthly budget and per-customer cap then limit the sponsored amount.
+func (s *GasSponsorshipService) evaluateSponsorship(ctx context.Context, params params.SponsorshipCheckParams, explain bool) (*business.SponsorshipSimulation, error) {
 	// Default decision is no sponsorship
 	decision := &business.SponsorshipDecision{
 		ShouldSponsor: false,
 		SponsorType:   "customer",
 		Reason:        "No sponsorship configured",
 	}
+	simulation := &business.SponsorshipSimulation{Decision: decision}
 
 	// Get sponsorship configuration for the workspace
 	config, err := s.queries.GetGasSponsorshipConfig(ctx, params.WorkspaceID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			// No sponsorship config exists
-			return decision, nil
+			return simulation, nil
 		}
 		return nil, fmt.Errorf("failed to get sponsorship config: %w", err)
 	}
@@ -60,17 +89,77 @@ func (s *GasSponsorshipService) ShouldSponsorGas(ctx context.Context, params par
 	// Check if sponsorship is enabled
 	if !config.SponsorshipEnabled.Bool || !config.SponsorCustomerGas.Bool {
 		decision.Reason = "Sponsorship not enabled for workspace"
-		return decision, nil
+		return simulation, nil
+	}
+
+	ruleRows, err := s.queries.ListActiveGasSponsorshipRules(ctx, params.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sponsorship rules: %w", err)
+	}
+
+	if len(ruleRows) == 0 {
+		s.evaluateConfigLists(config, params, decision)
+	} else {
+		rules := make([]business.SponsorshipRule, 0, len(ruleRows))
+		for _, row := range ruleRows {
+			rule, err := toSponsorshipRule(row)
+			if err != nil {
+				// A rule that cannot be read must not sponsor anything
+				s.logger.Error("Skipping unreadable sponsorship rule",
+					zap.String("rule_id", row.ID.String()),
+					zap.Error(err))
+				continue
+			}
+			rules = append(rules, rule)
+		}
+		s.evaluateRules(rules, params, explain, simulation)
+	}
+	if !decision.ShouldSponsor {
+		return simulation, nil
+	}
+
+	// Rules sponsor at most the remaining monthly budget; the config lists check it above
+	if decision.RuleID != uuid.Nil && config.MonthlyBudgetUsdCents.Valid {
+		remainingBudget := remainingSponsorshipBudget(config)
+		decision.RemainingBudget = remainingBudget
+		if remainingBudget <= 0 {
+			rejectSponsorship(decision, "Monthly sponsorship budget exhausted")
+			return simulation, nil
+		}
+		decision.SponsoredAmountCents = min(decision.SponsoredAmountCents, remainingBudget)
+	}
+
+	// Per-customer monthly cap
+	if config.PerCustomerMonthlyCapUsdCents.Valid && params.CustomerID != uuid.Nil {
+		capCents := config.PerCustomerMonthlyCapUsdCents.Int64
+		spent, err := s.customerMonthSpent(ctx, params.WorkspaceID, params.CustomerID, sponsorshipTime(params))
+		if err != nil {
+			return nil, err
+		}
+		simulation.CustomerMonthSpentCents = spent
+		simulation.CustomerMonthlyCapCents = &capCents
+
+		if spent >= capCents {
+			rejectSponsorship(decision, "Customer monthly sponsorship cap reached")
+			return simulation, nil
+		}
+		decision.SponsoredAmountCents = min(decision.SponsoredAmountCents, capCents-spent)
 	}
 
+	return simulation, nil
+}
+
+// evaluateConfigLists applies the threshold and the product, customer and tier lists of the
+// sponsorship config, sponsoring the whole gas cost when all of them pass
+func (s *GasSponsorshipService) evaluateConfigLists(config db.GasSponsorshipConfig, params params.SponsorshipCheckParams, decision *business.SponsorshipDecision) {
 	// Check monthly budget
 	if config.MonthlyBudgetUsdCents.Valid {
-		remainingBudget := config.MonthlyBudgetUsdCents.Int64 - config.CurrentMonthSpentCents.Int64
+		remainingBudget := remainingSponsorshipBudget(config)
 		decision.RemainingBudget = remainingBudget
 
 		if remainingBudget < params.GasCostUSDCents {
 			decision.Reason = "Monthly sponsorship budget exhausted"
-			return decision, nil
+			return
 		}
 	}
 
@@ -78,7 +167,7 @@ func (s *GasSponsorshipService) ShouldSponsorGas(ctx context.Context, params par
 	if config.SponsorThresholdUsdCents.Valid && params.GasCostUSDCents > config.SponsorThresholdUsdCents.Int64 {
 		decision.Reason = fmt.Sprintf("Gas cost exceeds threshold (%d cents > %d cents)",
 			params.GasCostUSDCents, config.SponsorThresholdUsdCents.Int64)
-		return decision, nil
+		return
 	}
 
 	// Check product-specific rules
@@ -94,7 +183,7 @@ func (s *GasSponsorshipService) ShouldSponsorGas(ctx context.Context, params par
 			}
 			if !found {
 				decision.Reason = "Product not eligible for sponsorship"
-				return decision, nil
+				return
 			}
 		}
 	}
@@ -112,7 +201,7 @@ func (s *GasSponsorshipService) ShouldSponsorGas(ctx context.Context, params par
 			}
 			if !found {
 				decision.Reason = "Customer not eligible for sponsorship"
-				return decision, nil
+				return
 			}
 		}
 	}
@@ -130,7 +219,7 @@ func (s *GasSponsorshipService) ShouldSponsorGas(ctx context.Context, params par
 			}
 			if !found {
 				decision.Reason = "Customer tier not eligible for sponsorship"
-				return decision, nil
+				return
 			}
 		}
 	}
@@ -140,37 +229,103 @@ func (s *GasSponsorshipService) ShouldSponsorGas(ctx context.Context, params par
 	decision.SponsorType = constants.MerchantSponsorType
 	decision.SponsorID = params.WorkspaceID
 	decision.Reason = "Sponsorship approved"
-	decision.RemainingBudget = config.MonthlyBudgetUsdCents.Int64 - config.CurrentMonthSpentCents.Int64
+	decision.RemainingBudget = remainingSponsorshipBudget(config)
+	decision.SponsoredAmountCents = params.GasCostUSDCents
+	decision.SponsorPercentage = 100
+}
 
-	s.logger.Info("Gas sponsorship approved",
-		zap.String("workspace_id", params.WorkspaceID.String()),
-		zap.String("reason", decision.Reason),
-		zap.Int64("remaining_budget", decision.RemainingBudget))
+// evaluateRules decides by the first rule, in priority order, whose conditions all match. When
+// explaining, every rule is evaluated so the caller can see why the others did not match.
+func (s *GasSponsorshipService) evaluateRules(rules []business.SponsorshipRule, params params.SponsorshipCheckParams, explain bool, simulation *business.SponsorshipSimulation) {
+	decision := simulation.Decision
+	decision.Reason = "No sponsorship rule matched"
+	at := sponsorshipTime(params)
+
+	fired := false
+	for _, rule := range rules {
+		failed := unmatchedSponsorshipConditions(rule.Conditions, params, at)
+		matched := len(failed) == 0
+		if explain {
+			simulation.Evaluations = append(simulation.Evaluations, business.SponsorshipRuleEvaluation{
+				RuleID:           rule.ID,
+				RuleName:         rule.Name,
+				Priority:         rule.Priority,
+				Matched:          matched,
+				Fired:            matched && !fired,
+				FailedConditions: failed,
+			})
+		}
+		if !matched || fired {
+			continue
+		}
 
-	return decision, nil
+		fired = true
+		decision.RuleID = rule.ID
+		decision.RuleName = rule.Name
+		decision.SponsoredAmountCents = sponsoredAmount(rule, params.GasCostUSDCents)
+		decision.SponsorPercentage = 100
+		if rule.Action == business.SponsorshipActionSplitPercentage {
+			decision.SponsorPercentage = rule.SponsorPercentage
+		}
+		if decision.SponsoredAmountCents <= 0 {
+			decision.Reason = fmt.Sprintf("Rule %q sponsors nothing for this transaction", rule.Name)
+		} else {
+			decision.ShouldSponsor = true
+			decision.SponsorType = constants.MerchantSponsorType
+			decision.SponsorID = params.WorkspaceID
+			decision.Reason = fmt.Sprintf("Sponsorship approved by rule %q", rule.Name)
+		}
+		if !explain {
+			return
+		}
+	}
+}
+
+// customerMonthSpent returns the gas sponsored for a customer in the calendar month (UTC) of at,
+// including amounts held for transactions that have not settled yet
+func (s *GasSponsorshipService) customerMonthSpent(ctx context.Context, workspaceID, customerID uuid.UUID, at time.Time) (int64, error) {
+	spending, err := s.queries.GetGasSponsorshipCustomerSpending(ctx, db.GetGasSponsorshipCustomerSpendingParams{
+		WorkspaceID: workspaceID,
+		CustomerID:  customerID,
+		PeriodStart: sponsorshipMonth(at),
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get customer sponsorship spending: %w", err)
+	}
+	return spending.SpentUsdCents + spending.ReservedUsdCents, nil
 }
 
-// RecordSponsoredTransaction records a gas sponsorship transaction
+// RecordSponsoredTransaction records gas sponsored outside the reserve and settle flow, such as on
+// invoices, by holding the amount and settling it straight away
 func (s *GasSponsorshipService) RecordSponsoredTransaction(ctx context.Context, record business.SponsorshipRecord) error {
-	// First get current spending to calculate new total
 	config, err := s.queries.GetGasSponsorshipConfig(ctx, record.WorkspaceID)
 	if err != nil {
-		return fmt.Errorf("failed to get current spending: %w", err)
+		return fmt.Errorf("failed to get sponsorship config: %w", err)
+	}
+	if record.GasCostUSDCents <= 0 {
+		return nil
 	}
 
-	newTotal := config.CurrentMonthSpentCents.Int64 + record.GasCostUSDCents
-
-	// Update the current month's spending
-	err = s.queries.UpdateGasSponsorshipSpending(ctx, db.UpdateGasSponsorshipSpendingParams{
-		WorkspaceID:            record.WorkspaceID,
-		CurrentMonthSpentCents: pgtype.Int8{Int64: newTotal, Valid: true},
-		UpdatedAt:              pgtype.Timestamptz{Valid: false}, // Use CURRENT_TIMESTAMP
-	})
-
+	hold := sponsorshipHold{
+		workspaceID: record.WorkspaceID,
+		customerID:  record.CustomerID,
+		amountCents: record.GasCostUSDCents,
+		percentage:  100,
+		customerCap: config.PerCustomerMonthlyCapUsdCents,
+		at:          time.Now(),
+	}
+	reservation, err := s.holdBudget(ctx, hold)
 	if err != nil {
 		return fmt.Errorf("failed to update sponsorship spending: %w", err)
 	}
 
+	if _, err := s.settleReservation(ctx, reservation, record.PaymentID, record.GasCostUSDCents); err != nil {
+		return fmt.Errorf("failed to update sponsorship spending: %w", err)
+	}
+
 	s.logger.Info("Recorded sponsored gas transaction",
 		zap.String("workspace_id", record.WorkspaceID.String()),
 		zap.String("payment_id", record.PaymentID.String()),
@@ -198,12 +353,24 @@ func (s *GasSponsorshipService) GetSponsorshipBudgetStatus(ctx context.Context,
 	status := &business.BudgetStatus{
 		WorkspaceID:            workspaceID,
 		CurrentMonthSpentCents: config.CurrentMonthSpentCents.Int64,
+		ReservedCents:          config.CurrentMonthReservedCents,
 		SponsorshipEnabled:     config.SponsorshipEnabled.Bool,
 	}
 
 	if config.MonthlyBudgetUsdCents.Valid {
 		status.MonthlyBudgetCents = config.MonthlyBudgetUsdCents.Int64
-		status.RemainingBudgetCents = config.MonthlyBudgetUsdCents.Int64 - config.CurrentMonthSpentCents.Int64
+		status.RemainingBudgetCents = remainingSponsorshipBudget(config)
+
+		alerts, err := s.queries.ListGasSponsorshipBudgetAlerts(ctx, db.ListGasSponsorshipBudgetAlertsParams{
+			WorkspaceID: workspaceID,
+			PeriodStart: sponsorshipMonth(time.Now()),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sponsorship budget alerts: %w", err)
+		}
+		for _, alert := range alerts {
+			status.AlertedThresholds = append(status.AlertedThresholds, alert.ThresholdPercent)
+		}
 	}
 
 	if config.LastResetDate.Valid {
@@ -310,6 +477,12 @@ func (s *GasSponsorshipService) UpdateSponsorshipConfig(ctx context.Context, wor
 		params.SponsorThresholdUsdCents = existingConfig.SponsorThresholdUsdCents
 	}
 
+	if updates.PerCustomerMonthlyCapUSDCents != nil {
+		params.PerCustomerMonthlyCapUsdCents = pgtype.Int8{Int64: *updates.PerCustomerMonthlyCapUSDCents, Valid: true}
+	} else {
+		params.PerCustomerMonthlyCapUsdCents = existingConfig.PerCustomerMonthlyCapUsdCents
+	}
+
 	_, err = s.queries.UpdateGasSponsorshipConfig(ctx, params)
 	if err != nil {
 		return fmt.Errorf("failed to update sponsorship config: %w", err)
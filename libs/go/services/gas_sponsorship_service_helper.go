@@ -92,6 +92,31 @@ func (h *GasSponsorshipHelper) RecordSponsorship(
 	return h.service.RecordSponsoredTransaction(ctx, record)
 }
 
+// CheckAndRecordSponsorship checks whether a transaction's gas is sponsored and settles the
+// sponsored portion against the workspace budget and the customer's monthly cap straight away, for
+// transactions that already confirmed
+func (h *GasSponsorshipHelper) CheckAndRecordSponsorship(
+	ctx context.Context,
+	checkParams params.SponsorshipCheckParams,
+	paymentID uuid.UUID,
+) (*business.SponsorshipDecision, error) {
+	decision, err := h.service.ReserveSponsorship(ctx, checkParams)
+	if err != nil {
+		return nil, err
+	}
+	if !decision.ShouldSponsor {
+		return decision, nil
+	}
+
+	_, err = h.service.SettleSponsorship(ctx, params.SponsorshipSettlementParams{
+		WorkspaceID:           checkParams.WorkspaceID,
+		ReservationID:         decision.ReservationID,
+		PaymentID:             paymentID,
+		ActualGasCostUSDCents: checkParams.GasCostUSDCents,
+	})
+	return decision, err
+}
+
 // GetService returns the underlying gas sponsorship service
 func (h *GasSponsorshipHelper) GetService() *GasSponsorshipService {
 	return h.service
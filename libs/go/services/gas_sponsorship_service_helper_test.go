@@ -25,6 +25,8 @@ func TestGasSponsorshipHelper_QuickSponsorshipCheck(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	// The workspaces in these tests have no sponsorship rules
+	mockQuerier.EXPECT().ListActiveGasSponsorshipRules(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	helper := services.NewGasSponsorshipHelper(mockQuerier)
 	ctx := context.Background()
 
@@ -254,6 +256,8 @@ func TestGasSponsorshipHelper_ApplySponsorshipToPayment(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	// The workspaces in these tests have no sponsorship rules
+	mockQuerier.EXPECT().ListActiveGasSponsorshipRules(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	helper := services.NewGasSponsorshipHelper(mockQuerier)
 	ctx := context.Background()
 
@@ -404,20 +408,38 @@ func TestGasSponsorshipHelper_RecordSponsorship(t *testing.T) {
 			paymentID:    paymentID,
 			gasCostCents: 100,
 			setupMocks: func() {
-				// First get current config
 				config := db.GasSponsorshipConfig{
 					WorkspaceID:            workspaceID,
 					CurrentMonthSpentCents: pgtype.Int8{Int64: 50000, Valid: true},
 				}
-				mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(config, nil)
-
-				// Then update spending
-				updateParams := db.UpdateGasSponsorshipSpendingParams{
-					WorkspaceID:            workspaceID,
-					CurrentMonthSpentCents: pgtype.Int8{Int64: 50100, Valid: true}, // 50000 + 100
-					UpdatedAt:              pgtype.Timestamptz{Valid: false},
+				mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(config, nil).Times(2)
+
+				// Hold the amount, then settle it straight away
+				reservation := db.GasSponsorshipReservation{
+					ID:                uuid.New(),
+					WorkspaceID:       workspaceID,
+					Status:            "reserved",
+					ReservedUsdCents:  100,
+					SponsorPercentage: 100,
 				}
-				mockQuerier.EXPECT().UpdateGasSponsorshipSpending(ctx, updateParams).Return(nil)
+				mockQuerier.EXPECT().ReserveGasSponsorshipBudget(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, arg db.ReserveGasSponsorshipBudgetParams) (db.GasSponsorshipReservation, error) {
+						assert.Equal(t, int64(100), arg.ReservedUsdCents)
+						assert.Equal(t, workspaceID, arg.WorkspaceID)
+						return reservation, nil
+					})
+				mockQuerier.EXPECT().SettleGasSponsorshipReservation(ctx, db.SettleGasSponsorshipReservationParams{
+					SettledUsdCents: 100,
+					PaymentID:       pgtype.UUID{Bytes: paymentID, Valid: true},
+					ID:              reservation.ID,
+					WorkspaceID:     workspaceID,
+				}).Return(db.GasSponsorshipReservation{
+					ID:               reservation.ID,
+					WorkspaceID:      workspaceID,
+					Status:           "settled",
+					ReservedUsdCents: 100,
+					SettledUsdCents:  pgtype.Int8{Int64: 100, Valid: true},
+				}, nil)
 			},
 			wantErr: false,
 		},
@@ -430,7 +452,7 @@ func TestGasSponsorshipHelper_RecordSponsorship(t *testing.T) {
 				mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(db.GasSponsorshipConfig{}, errors.New("database error"))
 			},
 			wantErr:     true,
-			errorString: "failed to get current spending",
+			errorString: "failed to get sponsorship config",
 		},
 		{
 			name:         "database error updating spending",
@@ -443,50 +465,40 @@ func TestGasSponsorshipHelper_RecordSponsorship(t *testing.T) {
 					CurrentMonthSpentCents: pgtype.Int8{Int64: 50000, Valid: true},
 				}
 				mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(config, nil)
-				mockQuerier.EXPECT().UpdateGasSponsorshipSpending(ctx, gomock.Any()).Return(errors.New("update error"))
+				mockQuerier.EXPECT().ReserveGasSponsorshipBudget(ctx, gomock.Any()).Return(db.GasSponsorshipReservation{}, errors.New("update error"))
 			},
 			wantErr:     true,
 			errorString: "failed to update sponsorship spending",
 		},
 		{
-			name:         "zero gas cost sponsorship",
+			name:         "budget exhausted",
 			workspaceID:  workspaceID,
 			paymentID:    paymentID,
-			gasCostCents: 0,
+			gasCostCents: 999999,
 			setupMocks: func() {
 				config := db.GasSponsorshipConfig{
 					WorkspaceID:            workspaceID,
-					CurrentMonthSpentCents: pgtype.Int8{Int64: 50000, Valid: true},
+					MonthlyBudgetUsdCents:  pgtype.Int8{Int64: 100000, Valid: true},
+					CurrentMonthSpentCents: pgtype.Int8{Int64: 1000, Valid: true},
 				}
 				mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(config, nil)
-
-				updateParams := db.UpdateGasSponsorshipSpendingParams{
-					WorkspaceID:            workspaceID,
-					CurrentMonthSpentCents: pgtype.Int8{Int64: 50000, Valid: true}, // No change
-					UpdatedAt:              pgtype.Timestamptz{Valid: false},
-				}
-				mockQuerier.EXPECT().UpdateGasSponsorshipSpending(ctx, updateParams).Return(nil)
+				mockQuerier.EXPECT().ReserveGasSponsorshipBudget(ctx, gomock.Any()).Return(db.GasSponsorshipReservation{}, pgx.ErrNoRows)
 			},
-			wantErr: false,
+			wantErr:     true,
+			errorString: services.ErrSponsorshipBudgetExhausted.Error(),
 		},
 		{
-			name:         "large gas cost sponsorship",
+			name:         "zero gas cost sponsorship",
 			workspaceID:  workspaceID,
 			paymentID:    paymentID,
-			gasCostCents: 999999,
+			gasCostCents: 0,
 			setupMocks: func() {
 				config := db.GasSponsorshipConfig{
 					WorkspaceID:            workspaceID,
-					CurrentMonthSpentCents: pgtype.Int8{Int64: 1000, Valid: true},
+					CurrentMonthSpentCents: pgtype.Int8{Int64: 50000, Valid: true},
 				}
+				// Nothing to hold
 				mockQuerier.EXPECT().GetGasSponsorshipConfig(ctx, workspaceID).Return(config, nil)
-
-				updateParams := db.UpdateGasSponsorshipSpendingParams{
-					WorkspaceID:            workspaceID,
-					CurrentMonthSpentCents: pgtype.Int8{Int64: 1000999, Valid: true}, // 1000 + 999999
-					UpdatedAt:              pgtype.Timestamptz{Valid: false},
-				}
-				mockQuerier.EXPECT().UpdateGasSponsorshipSpending(ctx, updateParams).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -533,6 +545,8 @@ func TestGasSponsorshipHelper_EdgeCases(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	// The workspaces in these tests have no sponsorship rules
+	mockQuerier.EXPECT().ListActiveGasSponsorshipRules(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	helper := services.NewGasSponsorshipHelper(mockQuerier)
 	ctx := context.Background()
 
@@ -604,6 +618,8 @@ func TestGasSponsorshipHelper_ConcurrentAccess(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	// The workspaces in these tests have no sponsorship rules
+	mockQuerier.EXPECT().ListActiveGasSponsorshipRules(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	helper := services.NewGasSponsorshipHelper(mockQuerier)
 	ctx := context.Background()
 
@@ -653,6 +669,8 @@ func TestGasSponsorshipHelper_ComplexScenarios(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	// The workspaces in these tests have no sponsorship rules
+	mockQuerier.EXPECT().ListActiveGasSponsorshipRules(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	helper := services.NewGasSponsorshipHelper(mockQuerier)
 	ctx := context.Background()
 
@@ -711,6 +729,8 @@ func TestGasSponsorshipHelper_ComplexScenarios(t *testing.T) {
 // Helper function to create test gas sponsorship helper
 func createTestGasSponsorshipHelper(ctrl *gomock.Controller) (*mocks.MockQuerier, *services.GasSponsorshipHelper) {
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	// The workspaces in these tests have no sponsorship rules
+	mockQuerier.EXPECT().ListActiveGasSponsorshipRules(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	helper := services.NewGasSponsorshipHelper(mockQuerier)
 	return mockQuerier, helper
 }
@@ -28,6 +28,8 @@ func TestGasSponsorshipService_ShouldSponsorGas(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	// The workspaces in these tests have no sponsorship rules
+	mockQuerier.EXPECT().ListActiveGasSponsorshipRules(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	service := services.NewGasSponsorshipService(mockQuerier)
 	ctx := context.Background()
 
@@ -315,6 +317,7 @@ func TestGasSponsorshipService_RecordSponsoredTransaction(t *testing.T) {
 	ctx := context.Background()
 
 	workspaceID := uuid.New()
+	customerID := uuid.New()
 	paymentID := uuid.New()
 
 	tests := []struct {
@@ -328,6 +331,7 @@ func TestGasSponsorshipService_RecordSponsoredTransaction(t *testing.T) {
 			name: "successful transaction recording",
 			record: business.SponsorshipRecord{
 				WorkspaceID:     workspaceID,
+				CustomerID:      customerID,
 				PaymentID:       paymentID,
 				GasCostUSDCents: 150,
 				SponsorType:     "merchant",
@@ -335,20 +339,46 @@ func TestGasSponsorshipService_RecordSponsoredTransaction(t *testing.T) {
 			},
 			setupMocks: func() {
 				config := db.GasSponsorshipConfig{
-					WorkspaceID:            workspaceID,
-					CurrentMonthSpentCents: pgtype.Int8{Int64: 1000, Valid: true},
+					WorkspaceID:                   workspaceID,
+					CurrentMonthSpentCents:        pgtype.Int8{Int64: 1000, Valid: true},
+					PerCustomerMonthlyCapUsdCents: pgtype.Int8{Int64: 500, Valid: true},
 				}
 				mockQuerier.EXPECT().
 					GetGasSponsorshipConfig(ctx, workspaceID).
-					Return(config, nil)
+					Return(config, nil).
+					Times(2)
 
+				reservationID := uuid.New()
+				reservation := db.GasSponsorshipReservation{
+					ID:                reservationID,
+					WorkspaceID:       workspaceID,
+					CustomerID:        pgtype.UUID{Bytes: customerID, Valid: true},
+					Status:            "reserved",
+					ReservedUsdCents:  150,
+					SponsorPercentage: 100,
+				}
 				mockQuerier.EXPECT().
-					UpdateGasSponsorshipSpending(ctx, db.UpdateGasSponsorshipSpendingParams{
-						WorkspaceID:            workspaceID,
-						CurrentMonthSpentCents: pgtype.Int8{Int64: 1150, Valid: true},
-						UpdatedAt:              pgtype.Timestamptz{Valid: false},
+					ReserveGasSponsorshipBudget(ctx, gomock.Any()).
+					Return(reservation, nil)
+
+				// The customer's cap is held against as well
+				held := reservation
+				held.CustomerReserved = true
+				mockQuerier.EXPECT().
+					ReserveGasSponsorshipCustomerSpending(ctx, db.ReserveGasSponsorshipCustomerSpendingParams{
+						ID:          reservationID,
+						CapUsdCents: pgtype.Int8{Int64: 500, Valid: true},
 					}).
-					Return(nil)
+					Return(held, nil)
+
+				mockQuerier.EXPECT().
+					SettleGasSponsorshipReservation(ctx, db.SettleGasSponsorshipReservationParams{
+						SettledUsdCents: 150,
+						PaymentID:       pgtype.UUID{Bytes: paymentID, Valid: true},
+						ID:              reservationID,
+						WorkspaceID:     workspaceID,
+					}).
+					Return(db.GasSponsorshipReservation{ID: reservationID, WorkspaceID: workspaceID, Status: "settled"}, nil)
 			},
 			wantErr: false,
 		},
@@ -367,7 +397,44 @@ func TestGasSponsorshipService_RecordSponsoredTransaction(t *testing.T) {
 					Return(db.GasSponsorshipConfig{}, assert.AnError)
 			},
 			wantErr:     true,
-			errorString: "failed to get current spending",
+			errorString: "failed to get sponsorship config",
+		},
+		{
+			name: "customer cap reached releases the hold",
+			record: business.SponsorshipRecord{
+				WorkspaceID:     workspaceID,
+				CustomerID:      customerID,
+				PaymentID:       paymentID,
+				GasCostUSDCents: 150,
+				SponsorType:     "merchant",
+				SponsorID:       workspaceID,
+			},
+			setupMocks: func() {
+				config := db.GasSponsorshipConfig{
+					WorkspaceID:                   workspaceID,
+					PerCustomerMonthlyCapUsdCents: pgtype.Int8{Int64: 100, Valid: true},
+				}
+				mockQuerier.EXPECT().
+					GetGasSponsorshipConfig(ctx, workspaceID).
+					Return(config, nil)
+
+				reservation := db.GasSponsorshipReservation{ID: uuid.New(), WorkspaceID: workspaceID, Status: "reserved", ReservedUsdCents: 150}
+				mockQuerier.EXPECT().
+					ReserveGasSponsorshipBudget(ctx, gomock.Any()).
+					Return(reservation, nil)
+				mockQuerier.EXPECT().
+					ReserveGasSponsorshipCustomerSpending(ctx, gomock.Any()).
+					Return(db.GasSponsorshipReservation{}, pgx.ErrNoRows)
+				mockQuerier.EXPECT().
+					ReleaseGasSponsorshipReservation(ctx, db.ReleaseGasSponsorshipReservationParams{
+						ReleaseReason: pgtype.Text{String: "customer monthly cap reached", Valid: true},
+						ID:            reservation.ID,
+						WorkspaceID:   workspaceID,
+					}).
+					Return(db.GasSponsorshipReservation{ID: reservation.ID, WorkspaceID: workspaceID, Status: "released"}, nil)
+			},
+			wantErr:     true,
+			errorString: services.ErrSponsorshipCustomerCapReached.Error(),
 		},
 		{
 			name: "error updating spending",
@@ -388,8 +455,8 @@ func TestGasSponsorshipService_RecordSponsoredTransaction(t *testing.T) {
 					Return(config, nil)
 
 				mockQuerier.EXPECT().
-					UpdateGasSponsorshipSpending(ctx, gomock.Any()).
-					Return(assert.AnError)
+					ReserveGasSponsorshipBudget(ctx, gomock.Any()).
+					Return(db.GasSponsorshipReservation{}, assert.AnError)
 			},
 			wantErr:     true,
 			errorString: "failed to update sponsorship spending",
@@ -443,10 +510,15 @@ func TestGasSponsorshipService_GetSponsorshipBudgetStatus(t *testing.T) {
 					MonthlyBudgetUsdCents:  pgtype.Int8{Int64: 5000, Valid: true},
 					CurrentMonthSpentCents: pgtype.Int8{Int64: 2000, Valid: true},
 					LastResetDate:          pgtype.Date{Time: lastReset, Valid: true},
+
+					CurrentMonthReservedCents: 500,
 				}
 				mockQuerier.EXPECT().
 					GetGasSponsorshipConfig(ctx, workspaceID).
 					Return(config, nil)
+				mockQuerier.EXPECT().
+					ListGasSponsorshipBudgetAlerts(ctx, gomock.Any()).
+					Return([]db.GasSponsorshipBudgetAlert{{WorkspaceID: workspaceID, ThresholdPercent: 50}}, nil)
 			},
 			wantErr: false,
 			validateResult: func(status *business.BudgetStatus) {
@@ -454,7 +526,9 @@ func TestGasSponsorshipService_GetSponsorshipBudgetStatus(t *testing.T) {
 				assert.True(t, status.SponsorshipEnabled)
 				assert.Equal(t, int64(5000), status.MonthlyBudgetCents)
 				assert.Equal(t, int64(2000), status.CurrentMonthSpentCents)
-				assert.Equal(t, int64(3000), status.RemainingBudgetCents)
+				assert.Equal(t, int64(500), status.ReservedCents)
+				assert.Equal(t, []int32{50}, status.AlertedThresholds)
+				assert.Equal(t, int64(2500), status.RemainingBudgetCents)
 			},
 		},
 		{
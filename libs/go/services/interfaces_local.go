@@ -32,7 +32,7 @@ type IPaymentService interface {
 	CreatePaymentFromSubscriptionEvent(ctx context.Context, params params.CreatePaymentFromSubscriptionEventParams) (*db.Payment, error)
 	CreateComprehensivePayment(ctx context.Context, params params.CreateComprehensivePaymentParams) (*db.Payment, error)
 	GetPayment(ctx context.Context, params params.GetPaymentParams) (*db.Payment, error)
-	GetPaymentByTransactionHash(ctx context.Context, txHash string) (*db.Payment, error)
+	GetPaymentByTransactionHash(ctx context.Context, txHash string, subscriptionEventID *uuid.UUID) (*db.Payment, error)
 	ListPayments(ctx context.Context, params params.ListPaymentsParams) ([]db.Payment, error)
 	UpdatePaymentStatus(ctx context.Context, params params.UpdatePaymentStatusParams) (*db.Payment, error)
 	GetPaymentMetrics(ctx context.Context, workspaceID uuid.UUID, startTime, endTime time.Time, currency string) (*db.GetPaymentMetricsRow, error)
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/cyphera/cyphera-api/libs/go/db"
@@ -60,7 +61,7 @@ func (s *InvoiceService) CreateInvoice(ctx context.Context, invoiceParams params
 			periodStart = *invoiceParams.PeriodStart
 			periodEnd = *invoiceParams.PeriodEnd
 		}
-		
+
 		// Generate invoice from subscription
 		return s.GenerateInvoiceFromSubscription(ctx, *invoiceParams.SubscriptionID, periodStart, periodEnd, invoiceParams.Status == "draft")
 	}
@@ -105,13 +106,17 @@ func (s *InvoiceService) CreateInvoice(ctx context.Context, invoiceParams params
 
 	// Calculate tax
 	taxableAmount := subtotalCents - discountCents
-	taxCalculation, err := s.taxService.CalculateTax(ctx, params.TaxCalculationParams{
-		WorkspaceID:     invoiceParams.WorkspaceID,
-		CustomerID:      invoiceParams.CustomerID,
-		AmountCents:     taxableAmount,
-		Currency:        invoiceParams.Currency,
-		TransactionType: "subscription",
-	})
+	taxParams := params.TaxCalculationParams{
+		WorkspaceID:          invoiceParams.WorkspaceID,
+		CustomerID:           invoiceParams.CustomerID,
+		AmountCents:          taxableAmount,
+		Currency:             invoiceParams.Currency,
+		TransactionType:      "subscription",
+		TransactionReference: invoiceNumber,
+	}
+	applyCustomerTaxDetails(&taxParams, customer.IsBusiness, customer.TaxID,
+		customer.BillingCountry, customer.BillingState, customer.BillingCity, customer.BillingPostalCode)
+	taxCalculation, err := s.taxService.CalculateTax(ctx, taxParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate tax: %w", err)
 	}
@@ -146,13 +151,14 @@ func (s *InvoiceService) CreateInvoice(ctx context.Context, invoiceParams params
 		TaxDetails:             taxDetailsJSON,
 		DueDate:                timeToPgtype(invoiceParams.DueDate),
 		CustomerTaxID:          pgtype.Text{String: customer.TaxID.String, Valid: customer.TaxID.Valid},
-		CustomerJurisdictionID: pgtype.UUID{Valid: false},             // TODO: Convert jurisdiction to UUID
-		ReverseChargeApplies:   pgtype.Bool{Bool: false, Valid: true}, // TODO: Get from tax calculation
+		CustomerJurisdictionID: pgtype.UUID{Valid: false}, // TODO: Convert jurisdiction to UUID
+		ReverseChargeApplies:   pgtype.Bool{Bool: hasReverseCharge(taxCalculation.TaxBreakdown), Valid: true},
 		Metadata:               metadataJSON,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create invoice: %w", err)
 	}
+	s.recordTaxEvidence(ctx, &invoice, taxParams, taxCalculation)
 
 	// Create line items
 	var lineItems []db.InvoiceLineItem
@@ -227,7 +233,7 @@ func (s *InvoiceService) CreateInvoice(ctx context.Context, invoiceParams params
 		"has_discount":     discountCents > 0,
 		"has_tax":          taxCalculation.TotalTaxCents > 0,
 	})
-	
+
 	s.recordActivity(ctx, db.CreateInvoiceActivityParams{
 		InvoiceID:    invoice.ID,
 		WorkspaceID:  invoice.WorkspaceID,
@@ -343,7 +349,7 @@ func (s *InvoiceService) generateInvoiceFromSubscriptionWithNotes(ctx context.Co
 	}
 
 	if exists {
-		return nil, fmt.Errorf("invoice already exists for subscription %s for period %s to %s", 
+		return nil, fmt.Errorf("invoice already exists for subscription %s for period %s to %s",
 			subscriptionID, periodStart.Format("2006-01-02"), periodEnd.Format("2006-01-02"))
 	}
 
@@ -364,13 +370,17 @@ func (s *InvoiceService) generateInvoiceFromSubscriptionWithNotes(ctx context.Co
 	}
 
 	// Calculate tax
-	taxCalculation, err := s.taxService.CalculateTax(ctx, params.TaxCalculationParams{
-		WorkspaceID:     subscriptionDetails.WorkspaceID,
-		CustomerID:      subscriptionDetails.CustomerID,
-		AmountCents:     subtotalCents,
-		Currency:        currency,
-		TransactionType: "subscription",
-	})
+	taxParams := params.TaxCalculationParams{
+		WorkspaceID:          subscriptionDetails.WorkspaceID,
+		CustomerID:           subscriptionDetails.CustomerID,
+		AmountCents:          subtotalCents,
+		Currency:             currency,
+		TransactionType:      "subscription",
+		TransactionReference: invoiceNumber,
+	}
+	applyCustomerTaxDetails(&taxParams, subscriptionDetails.IsBusiness, subscriptionDetails.TaxID,
+		subscriptionDetails.BillingCountry, subscriptionDetails.BillingState, subscriptionDetails.BillingCity, subscriptionDetails.BillingPostalCode)
+	taxCalculation, err := s.taxService.CalculateTax(ctx, taxParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate tax: %w", err)
 	}
@@ -413,13 +423,14 @@ func (s *InvoiceService) generateInvoiceFromSubscriptionWithNotes(ctx context.Co
 		DueDate:                timeToPgtype(&dueDate),
 		CustomerTaxID:          pgtype.Text{String: subscriptionDetails.TaxID.String, Valid: subscriptionDetails.TaxID.Valid},
 		CustomerJurisdictionID: pgtype.UUID{Valid: false},
-		ReverseChargeApplies:   pgtype.Bool{Bool: false, Valid: true},
+		ReverseChargeApplies:   pgtype.Bool{Bool: hasReverseCharge(taxCalculation.TaxBreakdown), Valid: true},
 		Metadata:               []byte(`{"generated_from": "subscription"}`),
 		Notes:                  pgtype.Text{String: notes, Valid: notes != ""},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create invoice: %w", err)
 	}
+	s.recordTaxEvidence(ctx, &invoice, taxParams, taxCalculation)
 
 	// Create line items from subscription line items
 	for _, row := range subscriptionRows {
@@ -507,7 +518,7 @@ func (s *InvoiceService) generateInvoiceFromSubscriptionWithMetadata(ctx context
 	}
 
 	if exists {
-		return nil, fmt.Errorf("invoice already exists for subscription %s for period %s to %s", 
+		return nil, fmt.Errorf("invoice already exists for subscription %s for period %s to %s",
 			subscriptionID, periodStart.Format("2006-01-02"), periodEnd.Format("2006-01-02"))
 	}
 
@@ -528,13 +539,17 @@ func (s *InvoiceService) generateInvoiceFromSubscriptionWithMetadata(ctx context
 	}
 
 	// Calculate tax
-	taxCalculation, err := s.taxService.CalculateTax(ctx, params.TaxCalculationParams{
-		CustomerID:   subscriptionDetails.CustomerID,
-		WorkspaceID:  subscriptionDetails.WorkspaceID,
-		AmountCents:  subtotalCents,
-		Currency:     currency,
-		ProductID:    &subscriptionDetails.ProductID,
-	})
+	taxParams := params.TaxCalculationParams{
+		CustomerID:           subscriptionDetails.CustomerID,
+		WorkspaceID:          subscriptionDetails.WorkspaceID,
+		AmountCents:          subtotalCents,
+		Currency:             currency,
+		ProductID:            &subscriptionDetails.ProductID,
+		TransactionReference: invoiceNumber,
+	}
+	applyCustomerTaxDetails(&taxParams, subscriptionDetails.IsBusiness, subscriptionDetails.TaxID,
+		subscriptionDetails.BillingCountry, subscriptionDetails.BillingState, subscriptionDetails.BillingCity, subscriptionDetails.BillingPostalCode)
+	taxCalculation, err := s.taxService.CalculateTax(ctx, taxParams)
 	if err != nil {
 		// If tax calculation fails, log but continue with zero tax
 		s.logger.Warn("Failed to calculate tax for invoice, continuing with zero tax",
@@ -572,7 +587,7 @@ func (s *InvoiceService) generateInvoiceFromSubscriptionWithMetadata(ctx context
 		metadata = make(map[string]interface{})
 	}
 	metadata["generated_from"] = "subscription"
-	
+
 	metadataJSON, err := json.Marshal(metadata)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
@@ -595,13 +610,14 @@ func (s *InvoiceService) generateInvoiceFromSubscriptionWithMetadata(ctx context
 		DueDate:                timeToPgtype(&dueDate),
 		CustomerTaxID:          pgtype.Text{String: subscriptionDetails.TaxID.String, Valid: subscriptionDetails.TaxID.Valid},
 		CustomerJurisdictionID: pgtype.UUID{Valid: false},
-		ReverseChargeApplies:   pgtype.Bool{Bool: false, Valid: true},
+		ReverseChargeApplies:   pgtype.Bool{Bool: hasReverseCharge(taxCalculation.TaxBreakdown), Valid: true},
 		Metadata:               metadataJSON,
 		Notes:                  pgtype.Text{Valid: false},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create invoice: %w", err)
 	}
+	s.recordTaxEvidence(ctx, &invoice, taxParams, taxCalculation)
 
 	// Create line items from subscription line items
 	for _, row := range subscriptionRows {
@@ -925,7 +941,7 @@ func (s *InvoiceService) BulkGenerateInvoices(ctx context.Context, workspaceID u
 			s.logger.Error("Failed to generate invoice for subscription",
 				zap.String("subscription_id", sub.ID.String()),
 				zap.Error(genErr))
-			
+
 			result.Failed = append(result.Failed, responses.BulkInvoiceError{
 				SubscriptionID: sub.ID,
 				CustomerID:     sub.CustomerID,
@@ -1058,7 +1074,7 @@ func (s *InvoiceService) CreateInvoiceLineItemsFromSubscription(ctx context.Cont
 func (s *InvoiceService) GeneratePendingInvoices(ctx context.Context, lookAheadDays int) ([]uuid.UUID, error) {
 	// Calculate look-ahead date
 	lookAheadDate := time.Now().AddDate(0, 0, lookAheadDays)
-	
+
 	// Get pending subscriptions
 	pendingSubscriptions, err := s.queries.GetPendingInvoicesForGeneration(ctx, pgtype.Timestamptz{
 		Time:  lookAheadDate,
@@ -1084,7 +1100,7 @@ func (s *InvoiceService) GeneratePendingInvoices(ctx context.Context, lookAheadD
 		}
 
 		generatedInvoiceIDs = append(generatedInvoiceIDs, invoice.ID)
-		
+
 		s.logger.Info("Generated invoice for subscription",
 			zap.String("subscription_id", sub.SubscriptionID.String()),
 			zap.String("invoice_id", invoice.ID.String()),
@@ -1121,7 +1137,7 @@ func (s *InvoiceService) generateInvoiceNumber(ctx context.Context, workspaceID
 	for i := 1; i <= 10; i++ {
 		incrementedNumber := nextNumber + int32(i)
 		invoiceNumber := fmt.Sprintf("INV-%d-%04d", year, incrementedNumber)
-		
+
 		exists, err := s.checkInvoiceNumberExists(ctx, workspaceID, invoiceNumber)
 		if err != nil {
 			return "", fmt.Errorf("failed to check invoice number existence: %w", err)
@@ -1169,6 +1185,73 @@ func generateRandomString(length int) string {
 	return string(b)
 }
 
+// recordTaxEvidence stores on an invoice what its tax relied on: the external provider and its
+// transaction ID when tax was calculated outside the built-in rate tables, and the tax ID
+// verification behind a reverse charge. Provider calculations are only recorded with the provider
+// here, once the invoice exists. Failures are logged; the invoice stands.
+func (s *InvoiceService) recordTaxEvidence(ctx context.Context, invoice *db.Invoice, taxParams params.TaxCalculationParams, calculation *responses.TaxCalculationResult) {
+	if calculation.Provider != "" && calculation.Provider != InternalTaxProvider {
+		transactionID, err := s.taxService.CommitCalculation(ctx, taxParams, calculation)
+		if err != nil {
+			s.logger.Error("Failed to record tax calculation with provider",
+				zap.String("invoice_id", invoice.ID.String()),
+				zap.String("provider", calculation.Provider),
+				zap.Error(err))
+		}
+		calculation.ProviderTransactionID = transactionID
+
+		updated, err := s.queries.UpdateInvoiceTaxProvider(ctx, db.UpdateInvoiceTaxProviderParams{
+			ID:                       invoice.ID,
+			WorkspaceID:              invoice.WorkspaceID,
+			TaxProvider:              pgtype.Text{String: calculation.Provider, Valid: true},
+			TaxProviderTransactionID: pgtype.Text{String: calculation.ProviderTransactionID, Valid: calculation.ProviderTransactionID != ""},
+		})
+		if err != nil {
+			s.logger.Error("Failed to record tax provider transaction on invoice",
+				zap.String("invoice_id", invoice.ID.String()),
+				zap.String("provider", calculation.Provider),
+				zap.String("transaction_id", calculation.ProviderTransactionID),
+				zap.Error(err))
+		} else {
+			*invoice = updated
+		}
+	}
+
+	if evidence := calculation.AuditTrail.TaxIDVerification; evidence != nil {
+		updated, err := s.queries.UpdateInvoiceTaxIDVerification(ctx, db.UpdateInvoiceTaxIDVerificationParams{
+			ID:                  invoice.ID,
+			WorkspaceID:         invoice.WorkspaceID,
+			TaxIDVerificationID: pgtype.UUID{Bytes: evidence.VerificationID, Valid: true},
+		})
+		if err != nil {
+			s.logger.Error("Failed to record tax ID verification on invoice",
+				zap.String("invoice_id", invoice.ID.String()),
+				zap.String("verification_id", evidence.VerificationID.String()),
+				zap.Error(err))
+			return
+		}
+		*invoice = updated
+	}
+}
+
+// applyCustomerTaxDetails adds the customer's business status, tax ID and billing address to a tax
+// calculation so that location-based rates and B2B reverse charge can apply
+func applyCustomerTaxDetails(taxParams *params.TaxCalculationParams, isBusiness pgtype.Bool, taxID, country, state, city, postalCode pgtype.Text) {
+	taxParams.IsB2B = isBusiness.Bool
+	if taxID.Valid && strings.TrimSpace(taxID.String) != "" {
+		vatNumber := taxID.String
+		taxParams.CustomerVATNumber = &vatNumber
+	}
+	if country.Valid && country.String != "" {
+		taxParams.CustomerAddress = &business.Address{
+			City:       city.String,
+			State:      state.String,
+			PostalCode: postalCode.String,
+			Country:    country.String,
+		}
+	}
+}
+
 func (s *InvoiceService) createLineItem(ctx context.Context, invoiceID uuid.UUID, currency string, params params.LineItemCreateParams) (db.InvoiceLineItem, error) {
 	// Convert quantity to pgtype.Numeric
 	quantity := pgtype.Numeric{}
@@ -1209,9 +1292,12 @@ func (s *InvoiceService) addGasFeeLineItems(ctx context.Context, invoiceID uuid.
 			continue
 		}
 
+		amountCents := int64(item.Quantity * float64(item.UnitAmountCents))
+
 		// Check if this gas fee is sponsored
 		isSponsored := false
 		var sponsorType, sponsorName string
+		var sponsoredCents int64
 
 		if s.gasSponsorshipService != nil && item.GasFeePaymentID != nil {
 			// Check sponsorship eligibility
@@ -1220,7 +1306,7 @@ func (s *InvoiceService) addGasFeeLineItems(ctx context.Context, invoiceID uuid.
 				CustomerID:      invoiceParams.CustomerID,
 				ProductID:       uuid.Nil,   // Would come from product if applicable
 				CustomerTier:    "standard", // TODO: Get actual customer tier
-				GasCostUSDCents: item.UnitAmountCents,
+				GasCostUSDCents: amountCents,
 				TransactionType: "invoice",
 			})
 			if err != nil {
@@ -1231,6 +1317,7 @@ func (s *InvoiceService) addGasFeeLineItems(ctx context.Context, invoiceID uuid.
 				isSponsored = true
 				sponsorType = decision.SponsorType
 				sponsorName = decision.Reason
+				sponsoredCents = decision.SponsoredAmountCents
 			}
 		}
 
@@ -1240,8 +1327,6 @@ func (s *InvoiceService) addGasFeeLineItems(ctx context.Context, invoiceID uuid.
 			return 0, 0, fmt.Errorf("failed to convert quantity: %w", err)
 		}
 
-		amountCents := int64(item.Quantity * float64(item.UnitAmountCents))
-
 		// Convert metadata
 		metadataJSON, err := json.Marshal(item.Metadata)
 		if err != nil {
@@ -1269,15 +1354,16 @@ func (s *InvoiceService) addGasFeeLineItems(ctx context.Context, invoiceID uuid.
 		// Track totals
 		gasFeesTotal += amountCents
 		if isSponsored {
-			sponsoredGasFees += amountCents
+			sponsoredGasFees += sponsoredCents
 		}
 
 		// If sponsored, record sponsorship transaction
 		if isSponsored && s.gasSponsorshipService != nil {
 			if err := s.gasSponsorshipService.RecordSponsoredTransaction(ctx, business.SponsorshipRecord{
 				WorkspaceID:     invoiceParams.WorkspaceID,
+				CustomerID:      invoiceParams.CustomerID,
 				PaymentID:       uuid.Nil, // Would be set when payment is processed
-				GasCostUSDCents: amountCents,
+				GasCostUSDCents: sponsoredCents,
 				SponsorType:     sponsorType,
 				SponsorID:       invoiceParams.WorkspaceID, // Using workspace as sponsor for now
 			}); err != nil {
@@ -1322,6 +1408,16 @@ func convertTaxBreakdownToDetails(breakdown []business.TaxLineItem) []business.T
 	return result
 }
 
+// hasReverseCharge reports whether any tax line of a calculation is a reverse charge
+func hasReverseCharge(breakdown []business.TaxLineItem) bool {
+	for _, item := range breakdown {
+		if item.IsReversCharge {
+			return true
+		}
+	}
+	return false
+}
+
 func convertCryptoAmounts(amounts []db.GetInvoiceCryptoAmountsRow) []business.CryptoAmount {
 	var result []business.CryptoAmount
 	for _, a := range amounts {
@@ -202,6 +202,14 @@ func (s *MetricsScheduler) calculateDailyMetrics() {
 				zap.Error(err),
 			)
 		}
+
+		// Refresh retention cohorts, which change as months pass and customers pay or churn
+		if err := s.metricsService.CalculateCohortMetricsForWorkspace(ctx, workspace.ID, time.Now()); err != nil {
+			s.logger.Error("Failed to calculate cohort metrics",
+				zap.String("workspace_id", workspace.ID.String()),
+				zap.Error(err),
+			)
+		}
 	}
 
 	// Clean up old metrics (keep last 90 days of hourly, 1 year of daily, 3 years of monthly)
@@ -238,6 +246,13 @@ func (s *MetricsScheduler) calculateAllWorkspaceMetrics() {
 				zap.Error(err),
 			)
 		}
+
+		if err := s.metricsService.CalculateCohortMetricsForWorkspace(ctx, workspace.ID, now); err != nil {
+			s.logger.Error("Failed to calculate cohort metrics",
+				zap.String("workspace_id", workspace.ID.String()),
+				zap.Error(err),
+			)
+		}
 	}
 }
 
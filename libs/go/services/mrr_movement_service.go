@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/constants"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// ErrNoMRRMovement is returned when a subscription change does not move MRR
+var ErrNoMRRMovement = errors.New("subscription change does not move MRR")
+
+// MRRMovementService records the MRR movements ledger: one row per subscription change that moves
+// monthly recurring revenue, normalized to a monthly amount and to the workspace's reporting currency
+type MRRMovementService struct {
+	queries         db.Querier
+	exchangeRates   *ExchangeRateService
+	currencyService *CurrencyService
+	logger          *zap.Logger
+}
+
+// NewMRRMovementService creates a service that records MRR movements. Movements in a currency other
+// than the workspace's reporting currency are left pending until NormalizePendingMovements runs
+// with an exchange rate service.
+func NewMRRMovementService(queries db.Querier) *MRRMovementService {
+	return NewMRRMovementServiceWithExchangeRates(queries, nil)
+}
+
+// NewMRRMovementServiceWithExchangeRates creates a service that can also convert pending movements
+func NewMRRMovementServiceWithExchangeRates(queries db.Querier, exchangeRates *ExchangeRateService) *MRRMovementService {
+	return &MRRMovementService{
+		queries:         queries,
+		exchangeRates:   exchangeRates,
+		currencyService: NewCurrencyService(queries),
+		logger:          logger.Log,
+	}
+}
+
+// MonthlyRecurringCents normalizes a billing amount to its monthly value, using the same factors as
+// the dashboard MRR calculation. Intervals shorter than a day carry no MRR.
+func MonthlyRecurringCents(amountCents int64, intervalType db.NullIntervalType) int64 {
+	if !intervalType.Valid {
+		return 0
+	}
+	switch intervalType.IntervalType {
+	case db.IntervalTypeMonth:
+		return amountCents
+	case db.IntervalTypeYear:
+		return int64(math.Round(float64(amountCents) / 12))
+	case db.IntervalTypeWeek:
+		return int64(math.Round(float64(amountCents) * 4.33))
+	case db.IntervalTypeDaily:
+		return amountCents * 30
+	default:
+		return 0
+	}
+}
+
+// classifyMRRMovement names the movement between two monthly amounts. Pauses and cancellations
+// always churn and resumes always reactivate; other changes are classified by direction.
+func classifyMRRMovement(source string, fromMRR, toMRR int64) (string, error) {
+	switch {
+	case fromMRR == toMRR:
+		return "", ErrNoMRRMovement
+	case source == business.MRRSourceCancel || source == business.MRRSourcePause:
+		return business.MRRMovementChurn, nil
+	case source == business.MRRSourceResume:
+		return business.MRRMovementReactivation, nil
+	case fromMRR == 0:
+		return business.MRRMovementNew, nil
+	case toMRR == 0:
+		return business.MRRMovementChurn, nil
+	case toMRR > fromMRR:
+		return business.MRRMovementExpansion, nil
+	default:
+		return business.MRRMovementContraction, nil
+	}
+}
+
+// RecordMovement adds a subscription change to the ledger. It returns ErrNoMRRMovement, and records
+// nothing, when the change leaves the subscription's monthly value unchanged.
+func (s *MRRMovementService) RecordMovement(ctx context.Context, p params.RecordMRRMovementParams) (*db.MrrMovement, error) {
+	product := p.Product
+	if product == nil {
+		found, err := s.queries.GetProductWithoutWorkspaceId(ctx, p.Subscription.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subscription product: %w", err)
+		}
+		product = &found
+	}
+	if product.PriceType != db.PriceTypeRecurring {
+		return nil, ErrNoMRRMovement
+	}
+
+	fromMRR := MonthlyRecurringCents(p.FromAmountCents, product.IntervalType)
+	toMRR := MonthlyRecurringCents(p.ToAmountCents, product.IntervalType)
+	movementType, err := classifyMRRMovement(p.Source, fromMRR, toMRR)
+	if err != nil {
+		return nil, err
+	}
+
+	// A cancellation can be carried out both by its schedule change and by its cancel_at date;
+	// a subscription that already churned must reactivate before it can churn again
+	if movementType == business.MRRMovementChurn {
+		latest, err := s.queries.GetLatestMRRMovement(ctx, p.Subscription.ID)
+		if err == nil && latest.MovementType == business.MRRMovementChurn {
+			return nil, ErrNoMRRMovement
+		}
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get latest MRR movement: %w", err)
+		}
+	}
+
+	reportingCurrency := constants.USDCurrency
+	if defaultCurrency, err := s.currencyService.GetWorkspaceDefaultCurrency(ctx, p.Subscription.WorkspaceID); err == nil {
+		reportingCurrency = defaultCurrency.Code
+	}
+
+	delta := toMRR - fromMRR
+	exchangeRate := pgtype.Float8{}
+	reportingDelta := pgtype.Int8{}
+	if product.Currency == reportingCurrency {
+		exchangeRate = pgtype.Float8{Float64: 1, Valid: true}
+		reportingDelta = pgtype.Int8{Int64: delta, Valid: true}
+	}
+
+	scheduleChangeID := pgtype.UUID{}
+	if p.ScheduleChangeID != nil {
+		scheduleChangeID = pgtype.UUID{Bytes: *p.ScheduleChangeID, Valid: true}
+	}
+	occurredAt := p.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	movement, err := s.queries.CreateMRRMovement(ctx, db.CreateMRRMovementParams{
+		WorkspaceID:            p.Subscription.WorkspaceID,
+		SubscriptionID:         p.Subscription.ID,
+		CustomerID:             p.Subscription.CustomerID,
+		MovementType:           movementType,
+		Source:                 p.Source,
+		IntervalType:           product.IntervalType,
+		Currency:               product.Currency,
+		FromAmountCents:        p.FromAmountCents,
+		ToAmountCents:          p.ToAmountCents,
+		FromMrrCents:           fromMRR,
+		ToMrrCents:             toMRR,
+		MrrDeltaCents:          delta,
+		ReportingCurrency:      reportingCurrency,
+		ExchangeRate:           exchangeRate,
+		ReportingMrrDeltaCents: reportingDelta,
+		ScheduleChangeID:       scheduleChangeID,
+		OccurredAt:             pgtype.Timestamptz{Time: occurredAt, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record MRR movement: %w", err)
+	}
+
+	s.logger.Debug("Recorded MRR movement",
+		zap.String("subscription_id", p.Subscription.ID.String()),
+		zap.String("movement_type", movementType),
+		zap.Int64("mrr_delta_cents", delta),
+		zap.String("currency", product.Currency))
+
+	return &movement, nil
+}
+
+// NormalizePendingMovements converts up to limit pending movements to their reporting currency and
+// returns how many were converted. Movements whose rate cannot be fetched stay pending.
+func (s *MRRMovementService) NormalizePendingMovements(ctx context.Context, limit int32) (int, error) {
+	if s.exchangeRates == nil {
+		return 0, nil
+	}
+
+	pending, err := s.queries.ListPendingMRRMovements(ctx, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending MRR movements: %w", err)
+	}
+
+	normalized := 0
+	for _, movement := range pending {
+		rate, err := s.exchangeRates.GetExchangeRate(ctx, params.ExchangeRateParams{
+			FromSymbol: movement.Currency,
+			ToSymbol:   movement.ReportingCurrency,
+		})
+		if err != nil {
+			s.logger.Warn("Exchange rate unavailable for MRR movement",
+				zap.String("movement_id", movement.ID.String()),
+				zap.String("from", movement.Currency),
+				zap.String("to", movement.ReportingCurrency),
+				zap.Error(err))
+			continue
+		}
+
+		reportingDelta, err := s.currencyService.ConvertAmount(ctx, movement.MrrDeltaCents, movement.Currency, movement.ReportingCurrency, rate.Rate)
+		if err != nil {
+			s.logger.Warn("Failed to convert MRR movement",
+				zap.String("movement_id", movement.ID.String()),
+				zap.Error(err))
+			continue
+		}
+
+		if err := s.queries.SetMRRMovementExchangeRate(ctx, db.SetMRRMovementExchangeRateParams{
+			ExchangeRate:           pgtype.Float8{Float64: rate.Rate, Valid: true},
+			ReportingMrrDeltaCents: pgtype.Int8{Int64: reportingDelta, Valid: true},
+			ID:                     movement.ID,
+		}); err != nil {
+			return normalized, fmt.Errorf("failed to store MRR movement exchange rate: %w", err)
+		}
+		normalized++
+	}
+
+	return normalized, nil
+}
+
+// toBusinessMRRMovement converts a ledger row to its API representation
+func toBusinessMRRMovement(m db.ListMRRMovementsRow) business.MRRMovement {
+	movement := business.MRRMovement{
+		ID:             m.ID,
+		SubscriptionID: m.SubscriptionID,
+		CustomerID:     m.CustomerID,
+		CustomerName:   m.CustomerName.String,
+		CustomerEmail:  m.CustomerEmail.String,
+		ProductName:    m.ProductName,
+		MovementType:   m.MovementType,
+		Source:         m.Source,
+		FromMRR:        mrrMoneyAmount(m.FromMrrCents, m.Currency),
+		ToMRR:          mrrMoneyAmount(m.ToMrrCents, m.Currency),
+		MRRDelta:       mrrMoneyAmount(m.MrrDeltaCents, m.Currency),
+		OccurredAt:     m.OccurredAt.Time,
+	}
+	if m.IntervalType.Valid {
+		movement.IntervalType = string(m.IntervalType.IntervalType)
+	}
+	if m.ExchangeRate.Valid {
+		rate := m.ExchangeRate.Float64
+		movement.ExchangeRate = &rate
+	}
+	if m.ReportingMrrDeltaCents.Valid {
+		delta := mrrMoneyAmount(m.ReportingMrrDeltaCents.Int64, m.ReportingCurrency)
+		movement.ReportingMRRDelta = &delta
+	}
+	return movement
+}
+
+func mrrMoneyAmount(cents int64, currency string) business.MoneyAmount {
+	return business.MoneyAmount{
+		AmountCents: cents,
+		Currency:    currency,
+		Formatted:   helpers.FormatMoney(cents, currency),
+	}
+}
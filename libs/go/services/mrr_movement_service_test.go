@@ -0,0 +1,194 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMonthlyRecurringCents(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		interval db.NullIntervalType
+		expected int64
+	}{
+		{"monthly", 2000, db.NullIntervalType{IntervalType: db.IntervalTypeMonth, Valid: true}, 2000},
+		{"yearly", 12000, db.NullIntervalType{IntervalType: db.IntervalTypeYear, Valid: true}, 1000},
+		{"weekly", 1000, db.NullIntervalType{IntervalType: db.IntervalTypeWeek, Valid: true}, 4330},
+		{"daily", 100, db.NullIntervalType{IntervalType: db.IntervalTypeDaily, Valid: true}, 3000},
+		{"no interval", 2000, db.NullIntervalType{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, services.MonthlyRecurringCents(tt.amount, tt.interval))
+		})
+	}
+}
+
+func TestMRRMovementService_RecordMovement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewMRRMovementService(mockQuerier)
+	ctx := context.Background()
+
+	subscription := db.Subscription{
+		ID:          uuid.New(),
+		WorkspaceID: uuid.New(),
+		CustomerID:  uuid.New(),
+	}
+	yearly := &db.Product{
+		PriceType:    db.PriceTypeRecurring,
+		IntervalType: db.NullIntervalType{IntervalType: db.IntervalTypeYear, Valid: true},
+		Currency:     "USD",
+	}
+
+	t.Run("upgrade is an expansion of the monthly value", func(t *testing.T) {
+		mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(ctx, subscription.WorkspaceID).Return(db.FiatCurrency{Code: "USD"}, nil)
+		mockQuerier.EXPECT().CreateMRRMovement(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CreateMRRMovementParams) (db.MrrMovement, error) {
+				assert.Equal(t, business.MRRMovementExpansion, arg.MovementType)
+				assert.Equal(t, int64(1000), arg.FromMrrCents)
+				assert.Equal(t, int64(2000), arg.ToMrrCents)
+				assert.Equal(t, int64(1000), arg.MrrDeltaCents)
+				assert.Equal(t, pgtype.Int8{Int64: 1000, Valid: true}, arg.ReportingMrrDeltaCents)
+				return db.MrrMovement{MovementType: arg.MovementType}, nil
+			})
+
+		movement, err := service.RecordMovement(ctx, params.RecordMRRMovementParams{
+			Subscription:    subscription,
+			Product:         yearly,
+			Source:          business.MRRSourceUpgrade,
+			FromAmountCents: 12000,
+			ToAmountCents:   24000,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, business.MRRMovementExpansion, movement.MovementType)
+	})
+
+	t.Run("movement in another currency waits for an exchange rate", func(t *testing.T) {
+		mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(ctx, subscription.WorkspaceID).Return(db.FiatCurrency{Code: "EUR"}, nil)
+		mockQuerier.EXPECT().CreateMRRMovement(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CreateMRRMovementParams) (db.MrrMovement, error) {
+				assert.Equal(t, business.MRRMovementNew, arg.MovementType)
+				assert.Equal(t, "EUR", arg.ReportingCurrency)
+				assert.False(t, arg.ExchangeRate.Valid)
+				assert.False(t, arg.ReportingMrrDeltaCents.Valid)
+				return db.MrrMovement{}, nil
+			})
+
+		_, err := service.RecordMovement(ctx, params.RecordMRRMovementParams{
+			Subscription:  subscription,
+			Product:       yearly,
+			Source:        business.MRRSourceCreate,
+			ToAmountCents: 12000,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("cancellation churns once", func(t *testing.T) {
+		mockQuerier.EXPECT().GetLatestMRRMovement(ctx, subscription.ID).Return(db.MrrMovement{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(ctx, subscription.WorkspaceID).Return(db.FiatCurrency{Code: "USD"}, nil)
+		mockQuerier.EXPECT().CreateMRRMovement(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CreateMRRMovementParams) (db.MrrMovement, error) {
+				assert.Equal(t, business.MRRMovementChurn, arg.MovementType)
+				assert.Equal(t, int64(-1000), arg.MrrDeltaCents)
+				return db.MrrMovement{MovementType: arg.MovementType}, nil
+			})
+
+		cancel := params.RecordMRRMovementParams{
+			Subscription:    subscription,
+			Product:         yearly,
+			Source:          business.MRRSourceCancel,
+			FromAmountCents: 12000,
+		}
+		_, err := service.RecordMovement(ctx, cancel)
+		require.NoError(t, err)
+
+		mockQuerier.EXPECT().GetLatestMRRMovement(ctx, subscription.ID).Return(db.MrrMovement{MovementType: business.MRRMovementChurn}, nil)
+		_, err = service.RecordMovement(ctx, cancel)
+		assert.ErrorIs(t, err, services.ErrNoMRRMovement)
+	})
+
+	t.Run("one-off products do not move MRR", func(t *testing.T) {
+		_, err := service.RecordMovement(ctx, params.RecordMRRMovementParams{
+			Subscription:  subscription,
+			Product:       &db.Product{PriceType: db.PriceTypeOneTime, Currency: "USD"},
+			Source:        business.MRRSourceCreate,
+			ToAmountCents: 5000,
+		})
+		assert.ErrorIs(t, err, services.ErrNoMRRMovement)
+	})
+}
+
+func TestAnalyticsService_GetMRRMovements(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier, service := createTestAnalyticsService(ctrl)
+	workspaceID := createTestWorkspaceID()
+	ctx := context.Background()
+
+	endDate := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	startDate := endDate.AddDate(0, -1, 0)
+
+	mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(ctx, workspaceID).Return(db.FiatCurrency{Code: "USD"}, nil)
+	mockQuerier.EXPECT().GetMRRMovementTotals(ctx, gomock.Any()).Return([]db.GetMRRMovementTotalsRow{
+		{MovementType: business.MRRMovementChurn, MovementCount: 1, MrrDeltaCents: -1500},
+		{MovementType: business.MRRMovementExpansion, MovementCount: 2, MrrDeltaCents: 1000},
+		{MovementType: business.MRRMovementNew, MovementCount: 4, PendingCount: 1, MrrDeltaCents: 6000},
+	}, nil)
+	mockQuerier.EXPECT().ListMRRMovements(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.ListMRRMovementsParams) ([]db.ListMRRMovementsRow, error) {
+			assert.Equal(t, "USD", arg.ReportingCurrency)
+			assert.Equal(t, pgtype.Text{String: business.MRRMovementNew, Valid: true}, arg.MovementType)
+			assert.Equal(t, int32(10), arg.RowLimit)
+			return []db.ListMRRMovementsRow{
+				{
+					ID:                uuid.New(),
+					MovementType:      business.MRRMovementNew,
+					Currency:          "EUR",
+					IntervalType:      db.NullIntervalType{IntervalType: db.IntervalTypeMonth, Valid: true},
+					ToMrrCents:        2000,
+					MrrDeltaCents:     2000,
+					ReportingCurrency: "USD",
+				},
+			}, nil
+		})
+	mockQuerier.EXPECT().CountMRRMovements(ctx, gomock.Any()).Return(int64(4), nil)
+
+	report, err := service.GetMRRMovements(ctx, params.ListMRRMovementsParams{
+		WorkspaceID:  workspaceID,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		MovementType: business.MRRMovementNew,
+		Limit:        10,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "USD", report.Currency)
+	assert.Len(t, report.Totals, 3)
+	assert.Equal(t, int64(5500), report.NetNewMRR.AmountCents)
+	assert.Equal(t, int64(1), report.PendingCount)
+	assert.Equal(t, int64(4), report.TotalItems)
+
+	require.Len(t, report.Movements, 1)
+	assert.Equal(t, "month", report.Movements[0].IntervalType)
+	assert.Equal(t, int64(2000), report.Movements[0].MRRDelta.AmountCents)
+	assert.Nil(t, report.Movements[0].ReportingMRRDelta)
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// Errors returned by NameResolver
+var (
+	// ErrInvalidWalletName is returned when a name is not a dotted ENS-style name
+	ErrInvalidWalletName = errors.New("invalid wallet name")
+	// ErrWalletNameNotFound is returned when a name has no resolver or does not point at an address
+	ErrWalletNameNotFound = errors.New("wallet name not found")
+	// ErrNameResolutionUnavailable is returned when no active network hosts the registry a name or address is looked up in
+	ErrNameResolutionUnavailable = errors.New("name resolution unavailable")
+)
+
+// NameRegistry is an ENS-compatible registry and the names it resolves
+type NameRegistry struct {
+	Name    string         // Used in logs, e.g. "ens"
+	ChainID int32          // Network the registry is deployed on
+	Address common.Address // Registry contract
+	// Suffix is the parent of the names the registry resolves, e.g. "base.eth"; empty resolves every name
+	// no other registry claims
+	Suffix string
+	// ReverseNamespace is the parent of the registry's reverse records, e.g. "addr.reverse"
+	ReverseNamespace string
+}
+
+// NameResolverConfig configures name resolution
+type NameResolverConfig struct {
+	// Registries are tried in order for reverse lookups; forward lookups use the registry with the longest
+	// matching suffix
+	Registries []NameRegistry
+}
+
+// Well-known name registries
+var (
+	ensRegistry = NameRegistry{
+		Name:             "ens",
+		ChainID:          1,
+		Address:          common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"),
+		ReverseNamespace: "addr.reverse",
+	}
+	basenamesRegistry = NameRegistry{
+		Name:             "basenames",
+		ChainID:          8453,
+		Address:          common.HexToAddress("0xB94704422c2a1E396835A571837Aa5AE53285a95"),
+		Suffix:           "base.eth",
+		ReverseNamespace: evmReverseNamespace(8453),
+	}
+)
+
+// DefaultNameResolverConfig returns ENS on Ethereum mainnet and Basenames on Base
+func DefaultNameResolverConfig() NameResolverConfig {
+	return NameResolverConfig{
+		Registries: []NameRegistry{ensRegistry, basenamesRegistry},
+	}
+}
+
+// NameResolverConfigFromEnv reads the chain ID and registry address of ENS from ENS_CHAIN_ID and
+// ENS_REGISTRY_ADDRESS, and of Basenames from BASENAMES_CHAIN_ID and BASENAMES_REGISTRY_ADDRESS, on top of the
+// defaults. Testnet deployments point these at Sepolia and Base Sepolia.
+func NameResolverConfigFromEnv() (NameResolverConfig, error) {
+	ens, err := registryFromEnv(ensRegistry, "ENS")
+	if err != nil {
+		return NameResolverConfig{}, err
+	}
+	basenames, err := registryFromEnv(basenamesRegistry, "BASENAMES")
+	if err != nil {
+		return NameResolverConfig{}, err
+	}
+	// Basenames keeps reverse records per chain, so they move with it
+	basenames.ReverseNamespace = evmReverseNamespace(basenames.ChainID)
+	return NameResolverConfig{Registries: []NameRegistry{ens, basenames}}, nil
+}
+
+// registryFromEnv overrides a registry's chain ID and address from <prefix>_CHAIN_ID and <prefix>_REGISTRY_ADDRESS
+func registryFromEnv(registry NameRegistry, prefix string) (NameRegistry, error) {
+	if value := strings.TrimSpace(os.Getenv(prefix + "_CHAIN_ID")); value != "" {
+		chainID, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || chainID <= 0 {
+			return registry, fmt.Errorf("%s_CHAIN_ID must be a positive chain ID: %s", prefix, value)
+		}
+		registry.ChainID = int32(chainID)
+	}
+	if value := strings.TrimSpace(os.Getenv(prefix + "_REGISTRY_ADDRESS")); value != "" {
+		if !common.IsHexAddress(value) {
+			return registry, fmt.Errorf("%s_REGISTRY_ADDRESS must be an address: %s", prefix, value)
+		}
+		registry.Address = common.HexToAddress(value)
+	}
+	return registry, nil
+}
+
+// evmReverseNamespace returns the ENSIP-19 reverse namespace of an EVM chain, named after its ENSIP-11 coin type
+func evmReverseNamespace(chainID int32) string {
+	return fmt.Sprintf("%x.reverse", 0x80000000|uint32(chainID))
+}
+
+// ContractReader makes read-only contract calls on EVM networks. Implemented by BlockchainService.
+type ContractReader interface {
+	CallContract(ctx context.Context, networkID uuid.UUID, contract common.Address, data []byte) ([]byte, error)
+}
+
+// Selectors of the ENS registry and resolver functions used for resolution
+var (
+	registryResolverSelector = crypto.Keccak256([]byte("resolver(bytes32)"))[:4]
+	resolverAddrSelector     = crypto.Keccak256([]byte("addr(bytes32)"))[:4]
+	resolverNameSelector     = crypto.Keccak256([]byte("name(bytes32)"))[:4]
+)
+
+// NameResolver resolves ENS and Basenames names through their registries on the networks the platform already
+// has RPC access to. Names are only lowercased before hashing; names that need full ENSIP-15 normalization, such
+// as ones with emoji or non-ASCII characters, must be passed in normalized form.
+type NameResolver struct {
+	queries db.Querier
+	chain   ContractReader
+	config  NameResolverConfig
+	logger  *zap.Logger
+}
+
+// NewNameResolver creates a new name resolver
+func NewNameResolver(queries db.Querier, chain ContractReader, config NameResolverConfig) *NameResolver {
+	log := logger.Log
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &NameResolver{
+		queries: queries,
+		chain:   chain,
+		config:  config,
+		logger:  log,
+	}
+}
+
+// IsWalletName reports whether a value looks like an ENS-style name rather than an address
+func IsWalletName(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.Contains(value, ".") && !common.IsHexAddress(value)
+}
+
+// Namehash returns the EIP-137 namehash of a name
+func Namehash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = crypto.Keccak256Hash(node.Bytes(), crypto.Keccak256([]byte(labels[i])))
+	}
+	return node
+}
+
+// ResolveName returns the checksummed address a name points at
+func (r *NameResolver) ResolveName(ctx context.Context, name string) (string, error) {
+	name, err := normalizeWalletName(name)
+	if err != nil {
+		return "", err
+	}
+	registry, ok := r.registryFor(name)
+	if !ok {
+		return "", ErrNameResolutionUnavailable
+	}
+	networkID, err := r.network(ctx, registry)
+	if err != nil {
+		return "", err
+	}
+
+	node := Namehash(name)
+	resolver, err := r.resolver(ctx, networkID, registry, node)
+	if err != nil {
+		return "", err
+	}
+	if resolver == (common.Address{}) {
+		return "", ErrWalletNameNotFound
+	}
+
+	result, err := r.chain.CallContract(ctx, networkID, resolver, nodeCall(resolverAddrSelector, node))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s on %s: %w", name, registry.Name, err)
+	}
+	address := decodeAddress(result)
+	if address == (common.Address{}) {
+		return "", ErrWalletNameNotFound
+	}
+	return address.Hex(), nil
+}
+
+// LookupAddress returns the primary name of an address, or an empty string when it has none. A reverse record
+// only counts when the name resolves back to the address, since anyone can claim any name in their own reverse
+// record. Registries are tried in order and the first verified name wins.
+func (r *NameResolver) LookupAddress(ctx context.Context, address string) (string, error) {
+	if !common.IsHexAddress(address) {
+		return "", fmt.Errorf("invalid address: %s", address)
+	}
+	account := common.HexToAddress(address)
+	reverseLabel := strings.ToLower(strings.TrimPrefix(account.Hex(), "0x"))
+
+	var lastErr error
+	available := false
+	for _, registry := range r.config.Registries {
+		networkID, err := r.network(ctx, registry)
+		if err != nil {
+			if !errors.Is(err, ErrNameResolutionUnavailable) {
+				lastErr = err
+			}
+			continue
+		}
+		available = true
+
+		name, err := r.reverseName(ctx, networkID, registry, reverseLabel+"."+registry.ReverseNamespace)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if name == "" {
+			continue
+		}
+
+		resolved, err := r.ResolveName(ctx, name)
+		if err != nil {
+			if errors.Is(err, ErrWalletNameNotFound) || errors.Is(err, ErrInvalidWalletName) {
+				continue
+			}
+			lastErr = err
+			continue
+		}
+		if resolved == account.Hex() {
+			return name, nil
+		}
+		r.logger.Debug("Ignoring unverified reverse record",
+			zap.String("registry", registry.Name),
+			zap.String("address", account.Hex()),
+			zap.String("name", name),
+			zap.String("resolves_to", resolved))
+	}
+
+	if lastErr != nil {
+		return "", lastErr
+	}
+	if !available {
+		return "", ErrNameResolutionUnavailable
+	}
+	return "", nil
+}
+
+// reverseName reads the name a reverse record points at
+func (r *NameResolver) reverseName(ctx context.Context, networkID uuid.UUID, registry NameRegistry, reverseName string) (string, error) {
+	node := Namehash(reverseName)
+	resolver, err := r.resolver(ctx, networkID, registry, node)
+	if err != nil || resolver == (common.Address{}) {
+		return "", err
+	}
+
+	result, err := r.chain.CallContract(ctx, networkID, resolver, nodeCall(resolverNameSelector, node))
+	if err != nil {
+		return "", fmt.Errorf("failed to read reverse record on %s: %w", registry.Name, err)
+	}
+	name, err := decodeString(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode reverse record on %s: %w", registry.Name, err)
+	}
+	return strings.TrimSpace(name), nil
+}
+
+// resolver returns the resolver a registry has set for a node, or the zero address when there is none
+func (r *NameResolver) resolver(ctx context.Context, networkID uuid.UUID, registry NameRegistry, node common.Hash) (common.Address, error) {
+	result, err := r.chain.CallContract(ctx, networkID, registry.Address, nodeCall(registryResolverSelector, node))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to read resolver from %s: %w", registry.Name, err)
+	}
+	return decodeAddress(result), nil
+}
+
+// registryFor returns the registry with the longest suffix the name falls under
+func (r *NameResolver) registryFor(name string) (NameRegistry, bool) {
+	var match NameRegistry
+	found := false
+	for _, registry := range r.config.Registries {
+		if registry.Suffix != "" && !strings.HasSuffix(name, "."+registry.Suffix) {
+			continue
+		}
+		if !found || len(registry.Suffix) > len(match.Suffix) {
+			match, found = registry, true
+		}
+	}
+	return match, found
+}
+
+// network returns the active EVM network a registry is deployed on
+func (r *NameResolver) network(ctx context.Context, registry NameRegistry) (uuid.UUID, error) {
+	network, err := r.queries.GetNetworkByChainID(ctx, registry.ChainID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrNameResolutionUnavailable
+		}
+		return uuid.Nil, fmt.Errorf("failed to get network for %s: %w", registry.Name, err)
+	}
+	if !network.Active || network.NetworkType != db.NetworkTypeEvm {
+		return uuid.Nil, ErrNameResolutionUnavailable
+	}
+	return network.ID, nil
+}
+
+// normalizeWalletName lowercases a name and checks it has no empty labels
+func normalizeWalletName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !IsWalletName(name) {
+		return "", ErrInvalidWalletName
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" {
+			return "", ErrInvalidWalletName
+		}
+	}
+	return name, nil
+}
+
+// nodeCall encodes a call to a function taking a single node
+func nodeCall(selector []byte, node common.Hash) []byte {
+	return append(append([]byte{}, selector...), node.Bytes()...)
+}
+
+// decodeAddress decodes an ABI-encoded address, treating an empty result, such as a call to an account with
+// no code, as the zero address
+func decodeAddress(result []byte) common.Address {
+	if len(result) < 32 {
+		return common.Address{}
+	}
+	return common.BytesToAddress(result[12:32])
+}
+
+// decodeString decodes an ABI-encoded string return value
+func decodeString(result []byte) (string, error) {
+	if len(result) == 0 {
+		return "", nil
+	}
+	if len(result) < 64 {
+		return "", fmt.Errorf("unexpected result length %d", len(result))
+	}
+	offset := new(big.Int).SetBytes(result[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(result)-32) {
+		return "", fmt.Errorf("string offset out of range")
+	}
+	start := offset.Uint64() + 32
+	length := new(big.Int).SetBytes(result[start-32 : start])
+	if !length.IsUint64() || length.Uint64() > uint64(len(result))-start {
+		return "", fmt.Errorf("string length out of range")
+	}
+	return string(result[start : start+length.Uint64()]), nil
+}
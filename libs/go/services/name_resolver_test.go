@@ -0,0 +1,203 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeNameRegistry answers registry and resolver calls from records keyed by network, contract and calldata
+type fakeNameRegistry struct {
+	results map[string][]byte
+	err     error
+}
+
+func newFakeNameRegistry() *fakeNameRegistry {
+	return &fakeNameRegistry{results: map[string][]byte{}}
+}
+
+func (f *fakeNameRegistry) CallContract(ctx context.Context, networkID uuid.UUID, contract common.Address, data []byte) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if result, ok := f.results[nameCallKey(networkID, contract, data)]; ok {
+		return result, nil
+	}
+	return make([]byte, 32), nil
+}
+
+// setName points name at address through resolver on a registry
+func (f *fakeNameRegistry) setName(networkID uuid.UUID, registry, resolver common.Address, name string, address common.Address) {
+	node := services.Namehash(name)
+	f.results[nameCallKey(networkID, registry, nameCall("resolver(bytes32)", node))] = common.LeftPadBytes(resolver.Bytes(), 32)
+	f.results[nameCallKey(networkID, resolver, nameCall("addr(bytes32)", node))] = common.LeftPadBytes(address.Bytes(), 32)
+}
+
+// setReverse sets the reverse record of an address in a reverse namespace
+func (f *fakeNameRegistry) setReverse(networkID uuid.UUID, registry, resolver common.Address, namespace string, address common.Address, name string) {
+	node := services.Namehash(strings.ToLower(strings.TrimPrefix(address.Hex(), "0x")) + "." + namespace)
+	f.results[nameCallKey(networkID, registry, nameCall("resolver(bytes32)", node))] = common.LeftPadBytes(resolver.Bytes(), 32)
+	f.results[nameCallKey(networkID, resolver, nameCall("name(bytes32)", node))] = abiString(name)
+}
+
+func nameCallKey(networkID uuid.UUID, contract common.Address, data []byte) string {
+	return fmt.Sprintf("%s/%s/%x", networkID, contract.Hex(), data)
+}
+
+func nameCall(signature string, node common.Hash) []byte {
+	return append(crypto.Keccak256([]byte(signature))[:4], node.Bytes()...)
+}
+
+func abiString(value string) []byte {
+	data := common.LeftPadBytes([]byte{0x20}, 32)
+	data = append(data, common.LeftPadBytes([]byte{byte(len(value))}, 32)...)
+	return append(data, common.RightPadBytes([]byte(value), (len(value)+31)/32*32)...)
+}
+
+var (
+	testENSRegistry       = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+	testBasenamesRegistry = common.HexToAddress("0xB94704422c2a1E396835A571837Aa5AE53285a95")
+	testENSResolver       = common.HexToAddress("0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63")
+	testBaseResolver      = common.HexToAddress("0xC6d566A56A1aFf6508b41f6c90ff131615583BCD")
+)
+
+// expectNameNetworks serves Ethereum mainnet and Base as the networks hosting ENS and Basenames
+func expectNameNetworks(mockQuerier *mocks.MockQuerier, mainnet, base db.Network) {
+	mockQuerier.EXPECT().GetNetworkByChainID(gomock.Any(), int32(1)).Return(mainnet, nil).AnyTimes()
+	mockQuerier.EXPECT().GetNetworkByChainID(gomock.Any(), int32(8453)).Return(base, nil).AnyTimes()
+}
+
+func TestNamehash(t *testing.T) {
+	// Vectors from EIP-137
+	assert.Equal(t, common.Hash{}, services.Namehash(""))
+	assert.Equal(t, "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae", services.Namehash("eth").Hex())
+	assert.Equal(t, "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f", services.Namehash("foo.eth").Hex())
+}
+
+func TestNameResolver_ResolveName(t *testing.T) {
+	mainnet := db.Network{ID: uuid.New(), NetworkType: db.NetworkTypeEvm, ChainID: 1, Active: true}
+	base := db.Network{ID: uuid.New(), NetworkType: db.NetworkTypeEvm, ChainID: 8453, Active: true}
+	alice := common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+	bob := common.HexToAddress("0x849151d7D0bF1F34b70d5caD5149D28CC2308bf1")
+
+	registry := newFakeNameRegistry()
+	registry.setName(mainnet.ID, testENSRegistry, testENSResolver, "alice.eth", alice)
+	registry.setName(base.ID, testBasenamesRegistry, testBaseResolver, "bob.base.eth", bob)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "resolves ENS names on Ethereum", input: "Alice.eth", want: alice.Hex()},
+		{name: "resolves Basenames on Base", input: "bob.base.eth", want: bob.Hex()},
+		{name: "names without a resolver are not found", input: "nobody.eth", wantErr: services.ErrWalletNameNotFound},
+		{name: "rejects names with empty labels", input: "alice..eth", wantErr: services.ErrInvalidWalletName},
+		{name: "rejects addresses", input: alice.Hex(), wantErr: services.ErrInvalidWalletName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockQuerier := mocks.NewMockQuerier(ctrl)
+			expectNameNetworks(mockQuerier, mainnet, base)
+
+			resolver := services.NewNameResolver(mockQuerier, registry, services.DefaultNameResolverConfig())
+			got, err := resolver.ResolveName(context.Background(), tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("is unavailable when the registry's network is not active", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		mockQuerier.EXPECT().GetNetworkByChainID(gomock.Any(), int32(8453)).Return(db.Network{}, pgx.ErrNoRows)
+
+		resolver := services.NewNameResolver(mockQuerier, registry, services.DefaultNameResolverConfig())
+		_, err := resolver.ResolveName(context.Background(), "bob.base.eth")
+		assert.ErrorIs(t, err, services.ErrNameResolutionUnavailable)
+	})
+}
+
+func TestNameResolver_LookupAddress(t *testing.T) {
+	mainnet := db.Network{ID: uuid.New(), NetworkType: db.NetworkTypeEvm, ChainID: 1, Active: true}
+	base := db.Network{ID: uuid.New(), NetworkType: db.NetworkTypeEvm, ChainID: 8453, Active: true}
+	owner := common.HexToAddress("0x742d35Cc6634C0532925a3b844Bc454e4438f44e")
+	other := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	baseReverse := fmt.Sprintf("%x.reverse", 0x80000000|8453)
+
+	t.Run("returns the primary name when it resolves back to the address", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		expectNameNetworks(mockQuerier, mainnet, base)
+
+		registry := newFakeNameRegistry()
+		registry.setReverse(mainnet.ID, testENSRegistry, testENSResolver, "addr.reverse", owner, "owner.eth")
+		registry.setName(mainnet.ID, testENSRegistry, testENSResolver, "owner.eth", owner)
+
+		resolver := services.NewNameResolver(mockQuerier, registry, services.DefaultNameResolverConfig())
+		name, err := resolver.LookupAddress(context.Background(), strings.ToLower(owner.Hex()))
+		require.NoError(t, err)
+		assert.Equal(t, "owner.eth", name)
+	})
+
+	t.Run("falls back to the Basename when the ENS reverse record is not verified", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		expectNameNetworks(mockQuerier, mainnet, base)
+
+		registry := newFakeNameRegistry()
+		registry.setReverse(mainnet.ID, testENSRegistry, testENSResolver, "addr.reverse", owner, "alice.eth")
+		registry.setName(mainnet.ID, testENSRegistry, testENSResolver, "alice.eth", other)
+		registry.setReverse(base.ID, testBasenamesRegistry, testBaseResolver, baseReverse, owner, "owner.base.eth")
+		registry.setName(base.ID, testBasenamesRegistry, testBaseResolver, "owner.base.eth", owner)
+
+		resolver := services.NewNameResolver(mockQuerier, registry, services.DefaultNameResolverConfig())
+		name, err := resolver.LookupAddress(context.Background(), owner.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "owner.base.eth", name)
+	})
+
+	t.Run("returns no name when the address has no reverse record", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		expectNameNetworks(mockQuerier, mainnet, base)
+
+		resolver := services.NewNameResolver(mockQuerier, newFakeNameRegistry(), services.DefaultNameResolverConfig())
+		name, err := resolver.LookupAddress(context.Background(), owner.Hex())
+		require.NoError(t, err)
+		assert.Empty(t, name)
+	})
+
+	t.Run("returns the RPC error instead of no name", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		expectNameNetworks(mockQuerier, mainnet, base)
+
+		registry := newFakeNameRegistry()
+		registry.err = errors.New("connection refused")
+
+		resolver := services.NewNameResolver(mockQuerier, registry, services.DefaultNameResolverConfig())
+		_, err := resolver.LookupAddress(context.Background(), owner.Hex())
+		assert.Error(t, err)
+	})
+}
@@ -14,6 +14,12 @@ import (
 	"go.uber.org/zap"
 )
 
+// Confirmation thresholds of networks created without them, matching the networks table defaults
+const (
+	DefaultConfirmationBlocks int32 = 3
+	DefaultFinalityBlocks     int32 = 12
+)
+
 // NetworkService handles business logic for network operations
 type NetworkService struct {
 	queries db.Querier
@@ -63,17 +69,28 @@ func (s *NetworkService) GetNetworkByChainID(ctx context.Context, chainID int32)
 // CreateNetwork creates a new network
 func (s *NetworkService) CreateNetwork(ctx context.Context, params params.CreateNetworkParams) (*db.Network, error) {
 	dbParams := db.CreateNetworkParams{
-		Name:              params.Name,
-		Type:              params.Type,
-		NetworkType:       db.NetworkType(params.NetworkType),
-		CircleNetworkType: db.CircleNetworkType(params.CircleNetworkType),
-		BlockExplorerUrl:  nullableString(params.BlockExplorerURL),
-		ChainID:           params.ChainID,
-		IsTestnet:         params.IsTestnet,
-		Active:            params.Active,
-		LogoUrl:           nullableString(params.LogoURL),
-		DisplayName:       nullableString(params.DisplayName),
-		ChainNamespace:    nullableString(params.ChainNamespace),
+		Name:               params.Name,
+		Type:               params.Type,
+		NetworkType:        db.NetworkType(params.NetworkType),
+		CircleNetworkType:  db.CircleNetworkType(params.CircleNetworkType),
+		BlockExplorerUrl:   nullableString(params.BlockExplorerURL),
+		ChainID:            params.ChainID,
+		IsTestnet:          params.IsTestnet,
+		Active:             params.Active,
+		LogoUrl:            nullableString(params.LogoURL),
+		DisplayName:        nullableString(params.DisplayName),
+		ChainNamespace:     nullableString(params.ChainNamespace),
+		ConfirmationBlocks: params.ConfirmationBlocks,
+		FinalityBlocks:     params.FinalityBlocks,
+	}
+	if dbParams.ConfirmationBlocks <= 0 {
+		dbParams.ConfirmationBlocks = DefaultConfirmationBlocks
+	}
+	if dbParams.FinalityBlocks <= 0 {
+		dbParams.FinalityBlocks = DefaultFinalityBlocks
+	}
+	if dbParams.FinalityBlocks < dbParams.ConfirmationBlocks {
+		return nil, fmt.Errorf("finality_blocks must be at least confirmation_blocks")
 	}
 
 	// Set gas config if provided
@@ -135,6 +152,18 @@ func (s *NetworkService) UpdateNetwork(ctx context.Context, params params.Update
 	if params.Active != nil {
 		dbParams.Active = *params.Active
 	}
+	if params.ConfirmationBlocks != nil {
+		if *params.ConfirmationBlocks <= 0 {
+			return nil, fmt.Errorf("confirmation_blocks must be positive")
+		}
+		dbParams.ConfirmationBlocks = pgtype.Int4{Int32: *params.ConfirmationBlocks, Valid: true}
+	}
+	if params.FinalityBlocks != nil {
+		if *params.FinalityBlocks <= 0 {
+			return nil, fmt.Errorf("finality_blocks must be positive")
+		}
+		dbParams.FinalityBlocks = pgtype.Int4{Int32: *params.FinalityBlocks, Valid: true}
+	}
 
 	// Set gas config if provided
 	if params.GasConfig != nil {
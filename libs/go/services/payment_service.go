@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/cyphera/cyphera-api/libs/go/constants"
@@ -30,8 +32,14 @@ type PaymentService struct {
 
 // NewPaymentService creates a new payment service
 func NewPaymentService(queries db.Querier, cmcAPIKey string) *PaymentService {
+	return NewPaymentServiceWithFeeOracle(queries, cmcAPIKey, nil)
+}
+
+// NewPaymentServiceWithFeeOracle creates a payment service that estimates gas fees from live
+// network data
+func NewPaymentServiceWithFeeOracle(queries db.Querier, cmcAPIKey string, feeOracle *GasFeeOracle) *PaymentService {
 	exchangeRateService := NewExchangeRateService(queries, cmcAPIKey)
-	gasFeeService := NewGasFeeService(queries, exchangeRateService)
+	gasFeeService := NewGasFeeServiceWithOracle(queries, exchangeRateService, feeOracle)
 	taxService := NewTaxService(queries)
 	discountService := NewDiscountService(queries)
 	gasSponsorshipService := NewGasSponsorshipService(queries)
@@ -47,6 +55,13 @@ func NewPaymentService(queries db.Querier, cmcAPIKey string) *PaymentService {
 	}
 }
 
+// WithTaxService calculates payment tax with the given tax service, so that payments use the same external
+// provider and VAT number verification as invoices
+func (s *PaymentService) WithTaxService(taxService *TaxService) *PaymentService {
+	s.taxService = taxService
+	return s
+}
+
 // CreatePaymentFromSubscriptionEvent creates a payment record when a subscription redemption occurs
 func (s *PaymentService) CreatePaymentFromSubscriptionEvent(ctx context.Context, params params.CreatePaymentFromSubscriptionEventParams) (*db.Payment, error) {
 	event := params.SubscriptionEvent
@@ -258,7 +273,9 @@ func (s *PaymentService) CreateComprehensivePayment(ctx context.Context, payment
 
 	// Step 3: Calculate gas fees if this is a crypto transaction
 	var gasFeeResult *responses.GasFeeResult
+	var gasEstimate *responses.EstimateGasFeeResult
 	var gasCostCents int64 = 0
+	var gasPriceWei *big.Int
 
 	if paymentParams.PaymentMethod == "crypto" && paymentParams.NetworkID != nil {
 		// Estimate gas fee for the transaction
@@ -267,19 +284,26 @@ func (s *PaymentService) CreateComprehensivePayment(ctx context.Context, payment
 			TransactionType:   paymentParams.TransactionType,
 			EstimatedGasLimit: 21000, // Standard ETH transfer
 			Currency:          paymentParams.Currency,
+			Call:              paymentParams.GasEstimateCall,
 		}
 
-		gasEstimate, err := s.gasFeeService.EstimateGasFee(ctx, estimateParams)
+		estimate, err := s.gasFeeService.EstimateGasFee(ctx, estimateParams)
 		if err != nil {
 			s.logger.Warn("Failed to estimate gas fee", zap.Error(err))
 		} else {
-			gasCostCents = gasEstimate.EstimatedCostUSDCents
+			gasEstimate = estimate
+			gasCostCents = estimate.EstimatedCostUSDCents
+			if price, ok := new(big.Int).SetString(estimate.CurrentGasPriceWei, 10); ok {
+				gasPriceWei = price
+			}
 		}
 	}
 
 	// Step 4: Check gas sponsorship
 	var gasSponsored bool
 	var sponsorID *uuid.UUID
+	var sponsoredGasCents int64
+	var sponsorshipReservationID uuid.UUID
 
 	if gasCostCents > 0 && paymentParams.ProductID != nil {
 		sponsorshipParams := params.SponsorshipCheckParams{
@@ -288,14 +312,24 @@ func (s *PaymentService) CreateComprehensivePayment(ctx context.Context, payment
 			ProductID:       *paymentParams.ProductID,
 			GasCostUSDCents: gasCostCents,
 			TransactionType: paymentParams.TransactionType,
+			GasPriceWei:     gasPriceWei,
+		}
+		if paymentParams.NetworkID != nil {
+			sponsorshipParams.NetworkID = *paymentParams.NetworkID
+		}
+		if paymentParams.TokenID != nil {
+			sponsorshipParams.TokenID = *paymentParams.TokenID
 		}
 
-		sponsorshipDecision, err := s.gasSponsorshipService.ShouldSponsorGas(ctx, sponsorshipParams)
+		// Hold the sponsored amount until the transaction confirms or fails
+		sponsorshipDecision, err := s.gasSponsorshipService.ReserveSponsorship(ctx, sponsorshipParams)
 		if err != nil {
 			s.logger.Warn("Failed to check gas sponsorship", zap.Error(err))
 		} else if sponsorshipDecision.ShouldSponsor {
 			gasSponsored = true
 			sponsorID = &sponsorshipDecision.SponsorID
+			sponsoredGasCents = sponsorshipDecision.SponsoredAmountCents
+			sponsorshipReservationID = sponsorshipDecision.ReservationID
 		}
 	}
 
@@ -350,6 +384,13 @@ func (s *PaymentService) CreateComprehensivePayment(ctx context.Context, payment
 	// Create the payment
 	payment, err := s.queries.CreatePayment(ctx, paymentParamsObj)
 	if err != nil {
+		if sponsorshipReservationID != uuid.Nil {
+			s.releaseGasSponsorship(ctx, params.SponsorshipReleaseParams{
+				WorkspaceID:   paymentParams.WorkspaceID,
+				ReservationID: sponsorshipReservationID,
+				Reason:        "payment could not be created",
+			})
+		}
 		return nil, fmt.Errorf("failed to create payment: %w", err)
 	}
 
@@ -369,18 +410,20 @@ func (s *PaymentService) CreateComprehensivePayment(ctx context.Context, payment
 		}
 	}
 
-	// Record gas sponsorship if applicable
-	if gasSponsored && sponsorID != nil {
-		sponsorshipRecord := business.SponsorshipRecord{
-			WorkspaceID:     paymentParams.WorkspaceID,
-			PaymentID:       payment.ID,
-			GasCostUSDCents: gasCostCents,
-			SponsorType:     constants.MerchantSponsorType,
-			SponsorID:       *sponsorID,
+	// Keep the estimate to compare with the actual fee once the transaction confirms
+	if gasEstimate != nil {
+		if err := s.gasFeeService.RecordGasFeeEstimate(ctx, paymentParams.WorkspaceID, payment.ID, *paymentParams.NetworkID, gasEstimate); err != nil {
+			s.logger.Warn("Failed to record gas fee estimate", zap.Error(err))
 		}
+	}
 
-		if err := s.gasSponsorshipService.RecordSponsoredTransaction(ctx, sponsorshipRecord); err != nil {
-			s.logger.Warn("Failed to record sponsored transaction", zap.Error(err))
+	// Link the sponsorship hold to the payment so it settles with the actual gas cost on confirmation
+	if sponsorshipReservationID != uuid.Nil {
+		if err := s.gasSponsorshipService.AttachSponsorshipPayment(ctx, paymentParams.WorkspaceID, sponsorshipReservationID, payment.ID); err != nil {
+			s.logger.Warn("Failed to link gas sponsorship to payment",
+				zap.String("reservation_id", sponsorshipReservationID.String()),
+				zap.Int64("sponsored_gas_cents", sponsoredGasCents),
+				zap.Error(err))
 		}
 	}
 
@@ -410,16 +453,21 @@ func (s *PaymentService) GetPayment(ctx context.Context, params params.GetPaymen
 	return &payment, nil
 }
 
-// GetPaymentByTransactionHash retrieves a payment by blockchain transaction hash
-func (s *PaymentService) GetPaymentByTransactionHash(ctx context.Context, txHash string) (*db.Payment, error) {
+// GetPaymentByTransactionHash retrieves a payment by blockchain transaction hash. Subscription payments share
+// the hash of a batched redemption, so they are looked up with their subscription event; payments without
+// one pass nil.
+func (s *PaymentService) GetPaymentByTransactionHash(ctx context.Context, txHash string, subscriptionEventID *uuid.UUID) (*db.Payment, error) {
 	if txHash == "" {
 		return nil, fmt.Errorf("transaction hash is required")
 	}
 
-	payment, err := s.queries.GetPaymentByTransactionHash(ctx, pgtype.Text{
-		String: txHash,
-		Valid:  true,
-	})
+	lookup := db.GetPaymentByTransactionHashParams{
+		TransactionHash: pgtype.Text{String: txHash, Valid: true},
+	}
+	if subscriptionEventID != nil {
+		lookup.SubscriptionEvent = pgtype.UUID{Bytes: *subscriptionEventID, Valid: true}
+	}
+	payment, err := s.queries.GetPaymentByTransactionHash(ctx, lookup)
 	if err != nil {
 		s.logger.Error("Failed to get payment by transaction hash",
 			zap.String("tx_hash", txHash),
@@ -489,9 +537,49 @@ func (s *PaymentService) UpdatePaymentStatus(ctx context.Context, paymentParams
 		zap.String("payment_id", payment.ID.String()),
 		zap.String("status", payment.Status))
 
+	// A failed payment's transaction will never draw on the sponsorship budget
+	if payment.Status == constants.FailedStatus {
+		s.releaseGasSponsorship(ctx, params.SponsorshipReleaseParams{
+			WorkspaceID: payment.WorkspaceID,
+			PaymentID:   payment.ID,
+			Reason:      "payment failed",
+		})
+	}
+
 	return &payment, nil
 }
 
+// SettleGasSponsorship settles the gas sponsorship held for a payment with the actual gas cost of its
+// confirmed transaction. When the transaction cannot be priced, fallbackCostCents is used instead.
+func (s *PaymentService) SettleGasSponsorship(ctx context.Context, workspaceID, paymentID uuid.UUID, gasParams params.GasFeeCalculationParams, fallbackCostCents int64) (*business.SponsorshipReservation, error) {
+	actualCostCents := fallbackCostCents
+	gasFee, err := s.gasFeeService.CalculateActualGasFee(ctx, gasParams)
+	if err != nil {
+		s.logger.Warn("Failed to calculate actual gas fee, using fallback cost",
+			zap.String("payment_id", paymentID.String()),
+			zap.Int64("fallback_cost_cents", fallbackCostCents),
+			zap.Error(err))
+	} else {
+		actualCostCents = gasFee.TotalGasCostUSDCents
+	}
+
+	return s.gasSponsorshipService.SettleSponsorship(ctx, params.SponsorshipSettlementParams{
+		WorkspaceID:           workspaceID,
+		PaymentID:             paymentID,
+		ActualGasCostUSDCents: actualCostCents,
+	})
+}
+
+// releaseGasSponsorship gives a payment's sponsorship hold back to the budget, if it has one
+func (s *PaymentService) releaseGasSponsorship(ctx context.Context, release params.SponsorshipReleaseParams) {
+	if _, err := s.gasSponsorshipService.ReleaseSponsorship(ctx, release); err != nil && !errors.Is(err, ErrSponsorshipReservationNotFound) {
+		s.logger.Warn("Failed to release gas sponsorship",
+			zap.String("workspace_id", release.WorkspaceID.String()),
+			zap.String("payment_id", release.PaymentID.String()),
+			zap.Error(err))
+	}
+}
+
 // GetPaymentMetrics retrieves payment metrics for a workspace within a date range
 func (s *PaymentService) GetPaymentMetrics(ctx context.Context, workspaceID uuid.UUID, startTime, endTime time.Time, currency string) (*db.GetPaymentMetricsRow, error) {
 	metrics, err := s.queries.GetPaymentMetrics(ctx, db.GetPaymentMetricsParams{
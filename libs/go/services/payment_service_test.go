@@ -11,6 +11,7 @@ import (
 	"github.com/cyphera/cyphera-api/libs/go/services"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -287,10 +288,12 @@ func TestPaymentService_GetPaymentByTransactionHash(t *testing.T) {
 
 	txHash := "0x123abc"
 	paymentID := uuid.New()
+	eventID := uuid.New()
 
 	tests := []struct {
 		name       string
 		txHash     string
+		eventID    *uuid.UUID
 		setupMocks func()
 		wantErr    bool
 		errString  string
@@ -300,7 +303,9 @@ func TestPaymentService_GetPaymentByTransactionHash(t *testing.T) {
 			txHash: txHash,
 			setupMocks: func() {
 				mockQuerier.EXPECT().
-					GetPaymentByTransactionHash(ctx, pgtype.Text{String: txHash, Valid: true}).
+					GetPaymentByTransactionHash(ctx, db.GetPaymentByTransactionHashParams{
+						TransactionHash: pgtype.Text{String: txHash, Valid: true},
+					}).
 					Return(db.Payment{
 						ID:              paymentID,
 						AmountInCents:   1000,
@@ -310,6 +315,25 @@ func TestPaymentService_GetPaymentByTransactionHash(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:    "subscription payment in a batched transaction",
+			txHash:  txHash,
+			eventID: &eventID,
+			setupMocks: func() {
+				mockQuerier.EXPECT().
+					GetPaymentByTransactionHash(ctx, db.GetPaymentByTransactionHashParams{
+						TransactionHash:   pgtype.Text{String: txHash, Valid: true},
+						SubscriptionEvent: pgtype.UUID{Bytes: eventID, Valid: true},
+					}).
+					Return(db.Payment{
+						ID:                paymentID,
+						TransactionHash:   pgtype.Text{String: txHash, Valid: true},
+						SubscriptionEvent: pgtype.UUID{Bytes: eventID, Valid: true},
+					}, nil).
+					Times(1)
+			},
+			wantErr: false,
+		},
 		{
 			name:       "empty transaction hash",
 			txHash:     "",
@@ -322,7 +346,7 @@ func TestPaymentService_GetPaymentByTransactionHash(t *testing.T) {
 			txHash: txHash,
 			setupMocks: func() {
 				mockQuerier.EXPECT().
-					GetPaymentByTransactionHash(ctx, pgtype.Text{String: txHash, Valid: true}).
+					GetPaymentByTransactionHash(ctx, gomock.Any()).
 					Return(db.Payment{}, assert.AnError).
 					Times(1)
 			},
@@ -335,7 +359,7 @@ func TestPaymentService_GetPaymentByTransactionHash(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMocks()
 
-			result, err := service.GetPaymentByTransactionHash(ctx, tt.txHash)
+			result, err := service.GetPaymentByTransactionHash(ctx, tt.txHash, tt.eventID)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -562,6 +586,10 @@ func TestPaymentService_UpdatePaymentStatus(t *testing.T) {
 						Status:      "failed",
 					}, nil).
 					Times(1)
+				mockQuerier.EXPECT().
+					GetOpenGasSponsorshipReservationByPayment(ctx, gomock.Any()).
+					Return(db.GasSponsorshipReservation{}, pgx.ErrNoRows).
+					Times(1)
 			},
 			wantErr: false,
 		},
@@ -926,9 +954,23 @@ func TestPaymentService_CreateComprehensivePayment(t *testing.T) {
 					SponsorForTiers:          []byte("[]"),
 					CurrentMonthSpentCents:   pgtype.Int8{Int64: 0, Valid: true},
 				}, nil).AnyTimes()
+				mockQuerier.EXPECT().ListActiveGasSponsorshipRules(ctx, workspaceID).Return(nil, nil).AnyTimes()
 
-				// Mock gas sponsorship spending update
-				mockQuerier.EXPECT().UpdateGasSponsorshipSpending(ctx, gomock.Any()).Return(nil).AnyTimes()
+				// Mock gas sponsorship budget hold
+				reservation := db.GasSponsorshipReservation{ID: uuid.New(), WorkspaceID: workspaceID, Status: "reserved"}
+				mockQuerier.EXPECT().ReserveGasSponsorshipBudget(ctx, gomock.Any()).Return(reservation, nil).AnyTimes()
+				mockQuerier.EXPECT().ReserveGasSponsorshipCustomerSpending(ctx, gomock.Any()).Return(reservation, nil).AnyTimes()
+				mockQuerier.EXPECT().AttachGasSponsorshipReservationPayment(ctx, gomock.Any()).Return(reservation, nil).AnyTimes()
+
+				// Mock recording the gas fee estimate for accuracy tracking
+				mockQuerier.EXPECT().CreateGasFeeEstimate(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, params db.CreateGasFeeEstimateParams) (db.GasFeeEstimate, error) {
+						assert.Equal(t, workspaceID, params.WorkspaceID)
+						assert.Equal(t, paymentID, uuid.UUID(params.PaymentID.Bytes))
+						assert.Equal(t, networkID, params.NetworkID)
+						assert.Equal(t, "static", params.EstimateSource)
+						return db.GasFeeEstimate{ID: uuid.New()}, nil
+					})
 
 				// Mock successful payment creation with crypto parameters
 				mockQuerier.EXPECT().CreatePayment(ctx, gomock.Any()).DoAndReturn(
@@ -1010,6 +1052,11 @@ func TestPaymentService_CreateComprehensivePayment(t *testing.T) {
 				},
 			},
 			setupMocks: func() {
+				// Mock UK VAT jurisdiction lookup for tax calculation
+				ukJurisdiction := db.TaxJurisdiction{ID: uuid.New(), Code: "UK", Name: "United Kingdom", CountryCode: "GB", TaxType: "vat", IsActive: true}
+				mockQuerier.EXPECT().FindTaxJurisdiction(ctx, gomock.Any()).Return(ukJurisdiction, nil)
+				mockQuerier.EXPECT().ListEffectiveTaxRates(ctx, gomock.Any()).Return([]db.TaxRate{}, nil)
+
 				// Mock customer lookup for discount validation
 				mockQuerier.EXPECT().GetCustomer(ctx, customerID).Return(db.Customer{
 					ID:        customerID,
@@ -1017,6 +1064,9 @@ func TestPaymentService_CreateComprehensivePayment(t *testing.T) {
 					CreatedAt: pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, -1), Valid: true}, // 1 day old (new customer)
 				}, nil)
 
+				// Mock recording the gas fee estimate for accuracy tracking
+				mockQuerier.EXPECT().CreateGasFeeEstimate(ctx, gomock.Any()).Return(db.GasFeeEstimate{ID: uuid.New()}, nil)
+
 				// Mock successful payment creation with all fields
 				mockQuerier.EXPECT().CreatePayment(ctx, gomock.Any()).DoAndReturn(
 					func(ctx context.Context, params db.CreatePaymentParams) (db.Payment, error) {
@@ -0,0 +1,600 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/client/circle"
+	"github.com/cyphera/cyphera-api/libs/go/constants"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidPortfolioRange is returned when a portfolio history range is empty or too long
+var ErrInvalidPortfolioRange = errors.New("invalid portfolio history range")
+
+// maxPortfolioHistory caps the range of snapshots returned at once
+const maxPortfolioHistory = 366 * 24 * time.Hour
+
+// PortfolioConfig configures wallet balance caching and portfolio snapshots
+type PortfolioConfig struct {
+	// MaxAge is how long after a refresh attempt cached balances are served before a portfolio read refreshes them
+	MaxAge time.Duration
+	// SnapshotInterval is the length of a snapshot period; each workspace gets one snapshot per period
+	SnapshotInterval time.Duration
+	// BatchSize caps the workspaces snapshotted per run
+	BatchSize int32
+}
+
+// DefaultPortfolioConfig returns the default portfolio configuration
+func DefaultPortfolioConfig() PortfolioConfig {
+	return PortfolioConfig{
+		MaxAge:           5 * time.Minute,
+		SnapshotInterval: time.Hour,
+		BatchSize:        50,
+	}
+}
+
+// PortfolioConfigFromEnv reads the balance freshness window in seconds from PORTFOLIO_MAX_AGE_SECONDS and the
+// snapshot period in minutes from PORTFOLIO_SNAPSHOT_INTERVAL_MINUTES on top of the defaults
+func PortfolioConfigFromEnv() (PortfolioConfig, error) {
+	config := DefaultPortfolioConfig()
+
+	if value := strings.TrimSpace(os.Getenv("PORTFOLIO_MAX_AGE_SECONDS")); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return config, fmt.Errorf("PORTFOLIO_MAX_AGE_SECONDS must be a positive number of seconds: %s", value)
+		}
+		config.MaxAge = time.Duration(seconds) * time.Second
+	}
+	if value := strings.TrimSpace(os.Getenv("PORTFOLIO_SNAPSHOT_INTERVAL_MINUTES")); value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes <= 0 {
+			return config, fmt.Errorf("PORTFOLIO_SNAPSHOT_INTERVAL_MINUTES must be a positive number of minutes: %s", value)
+		}
+		config.SnapshotInterval = time.Duration(minutes) * time.Minute
+	}
+	return config, nil
+}
+
+// PortfolioService values a workspace's wallets across the networks they are used on. Circle wallets are read
+// through Circle and every other wallet from the chain; balances are cached so portfolio reads stay cheap, and
+// snapshotted once per period for history charts.
+type PortfolioService struct {
+	queries db.Querier
+	circle  circle.CircleClientInterface
+	chain   interfaces.BlockchainService
+	rates   interfaces.ExchangeRateService
+	config  PortfolioConfig
+	logger  *zap.Logger
+}
+
+// NewPortfolioService creates a new portfolio service. The Circle client and blockchain service are optional;
+// Circle wallets fall back to the chain when Circle is not configured.
+func NewPortfolioService(
+	queries db.Querier,
+	circleClient circle.CircleClientInterface,
+	chain interfaces.BlockchainService,
+	rates interfaces.ExchangeRateService,
+	config PortfolioConfig,
+) *PortfolioService {
+	log := logger.Log
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &PortfolioService{
+		queries: queries,
+		circle:  circleClient,
+		chain:   chain,
+		rates:   rates,
+		config:  config,
+		logger:  log,
+	}
+}
+
+// GetPortfolio returns the balances of a workspace's wallets, or of one of them, valued in the workspace's
+// default currency. Cached balances are refreshed first when asked to, or when any was last attempted before
+// the freshness window or is valued in another currency. Balances the last refresh could not read are served
+// as they are, flagged, and retried by the next snapshot rather than on every read.
+func (s *PortfolioService) GetPortfolio(ctx context.Context, workspaceID uuid.UUID, walletID *uuid.UUID, refresh bool) (*business.Portfolio, error) {
+	now := time.Now()
+	currency := s.workspaceCurrency(ctx, workspaceID)
+
+	rows, err := s.listBalances(ctx, workspaceID, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if refresh || s.needsRefresh(rows, currency, now) {
+		if _, err := s.refresh(ctx, workspaceID, walletID, currency, now); err != nil {
+			return nil, err
+		}
+		if rows, err = s.listBalances(ctx, workspaceID, walletID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.toPortfolio(workspaceID, currency, rows, now), nil
+}
+
+// ListSnapshots returns a workspace's portfolio snapshots taken between start and end, oldest first
+func (s *PortfolioService) ListSnapshots(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) ([]business.PortfolioSnapshot, error) {
+	if !end.After(start) || end.Sub(start) > maxPortfolioHistory {
+		return nil, fmt.Errorf("%w: the range must end after it starts and span at most 366 days", ErrInvalidPortfolioRange)
+	}
+
+	rows, err := s.queries.ListPortfolioSnapshots(ctx, db.ListPortfolioSnapshotsParams{
+		WorkspaceID: workspaceID,
+		StartTime:   pgtype.Timestamptz{Time: start, Valid: true},
+		EndTime:     pgtype.Timestamptz{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list portfolio snapshots: %w", err)
+	}
+
+	snapshots := make([]business.PortfolioSnapshot, 0, len(rows))
+	for _, row := range rows {
+		holdings := []business.PortfolioHolding{}
+		if len(row.Holdings) > 0 {
+			if err := json.Unmarshal(row.Holdings, &holdings); err != nil {
+				return nil, fmt.Errorf("invalid holdings in portfolio snapshot %s: %w", row.ID, err)
+			}
+		}
+		snapshots = append(snapshots, business.PortfolioSnapshot{
+			SnapshotAt:      row.SnapshotAt.Time,
+			FiatCurrency:    row.FiatCurrency,
+			TotalValueCents: row.TotalValueCents,
+			Holdings:        holdings,
+		})
+	}
+	return snapshots, nil
+}
+
+// RecordSnapshots refreshes the balances of workspaces without a snapshot for the current period and records
+// one for each
+func (s *PortfolioService) RecordSnapshots(ctx context.Context, now time.Time) (*business.PortfolioSnapshotResult, error) {
+	period := now.Truncate(s.config.SnapshotInterval)
+	workspaceIDs, err := s.queries.ListWorkspacesDuePortfolioSnapshot(ctx, db.ListWorkspacesDuePortfolioSnapshotParams{
+		SnapshotAt: pgtype.Timestamptz{Time: period, Valid: true},
+		BatchSize:  s.config.BatchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces due a portfolio snapshot: %w", err)
+	}
+
+	result := &business.PortfolioSnapshotResult{Checked: len(workspaceIDs)}
+	for _, workspaceID := range workspaceIDs {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		if err := s.snapshot(ctx, workspaceID, period, now, result); err != nil {
+			result.Failed++
+			s.logger.Error("Failed to snapshot portfolio",
+				zap.String("workspace_id", workspaceID.String()),
+				zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+func (s *PortfolioService) snapshot(ctx context.Context, workspaceID uuid.UUID, period, now time.Time, result *business.PortfolioSnapshotResult) error {
+	currency := s.workspaceCurrency(ctx, workspaceID)
+	stats, err := s.refresh(ctx, workspaceID, nil, currency, now)
+	if err != nil {
+		return err
+	}
+	result.Balances += stats.updated
+	result.Errors += stats.errors
+
+	rows, err := s.listBalances(ctx, workspaceID, nil)
+	if err != nil {
+		return err
+	}
+
+	total, holdings := portfolioHoldings(rows, currency)
+	encoded, err := json.Marshal(holdings)
+	if err != nil {
+		return fmt.Errorf("failed to encode portfolio holdings: %w", err)
+	}
+
+	if _, err := s.queries.UpsertPortfolioSnapshot(ctx, db.UpsertPortfolioSnapshotParams{
+		WorkspaceID:     workspaceID,
+		SnapshotAt:      pgtype.Timestamptz{Time: period, Valid: true},
+		FiatCurrency:    currency,
+		TotalValueCents: total,
+		Holdings:        encoded,
+	}); err != nil {
+		return fmt.Errorf("failed to record portfolio snapshot: %w", err)
+	}
+	result.Snapshots++
+	return nil
+}
+
+// portfolioRefresh caches what a refresh looks up repeatedly across a workspace's wallets
+type portfolioRefresh struct {
+	currency   string
+	now        time.Time
+	tokens     map[uuid.UUID][]db.Token
+	rates      map[string]*big.Rat
+	userTokens map[uuid.UUID]string
+	updated    int
+	errors     int
+}
+
+// refresh reads the balance of every active token held by a workspace's wallets, or by one of them, on each
+// active network the wallet is used on, and caches it with its value in the given currency
+func (s *PortfolioService) refresh(ctx context.Context, workspaceID uuid.UUID, walletID *uuid.UUID, currency string, now time.Time) (*portfolioRefresh, error) {
+	wallets, err := s.queries.ListWalletsWithCircleDataByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+	networks, err := s.queries.ListActiveNetworks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active networks: %w", err)
+	}
+
+	run := &portfolioRefresh{
+		currency:   currency,
+		now:        now,
+		tokens:     map[uuid.UUID][]db.Token{},
+		rates:      map[string]*big.Rat{},
+		userTokens: map[uuid.UUID]string{},
+	}
+	for _, wallet := range wallets {
+		if walletID != nil && wallet.ID != *walletID {
+			continue
+		}
+		for _, network := range walletNetworks(wallet, networks) {
+			if err := s.refreshWallet(ctx, run, wallet, network); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.logger.Debug("Refreshed wallet balances",
+		zap.String("workspace_id", workspaceID.String()),
+		zap.Int("updated", run.updated),
+		zap.Int("errors", run.errors))
+	return run, nil
+}
+
+// walletNetworks returns the active networks a wallet is used on: its own network, or every active network
+// of its type for wallets that are not tied to one
+func walletNetworks(wallet db.ListWalletsWithCircleDataByWorkspaceIDRow, networks []db.Network) []db.Network {
+	var matched []db.Network
+	for _, network := range networks {
+		if wallet.NetworkID.Valid {
+			if network.ID == uuid.UUID(wallet.NetworkID.Bytes) {
+				return []db.Network{network}
+			}
+		} else if network.NetworkType == wallet.NetworkType {
+			matched = append(matched, network)
+		}
+	}
+	return matched
+}
+
+func (s *PortfolioService) refreshWallet(ctx context.Context, run *portfolioRefresh, wallet db.ListWalletsWithCircleDataByWorkspaceIDRow, network db.Network) error {
+	tokens, ok := run.tokens[network.ID]
+	if !ok {
+		var err error
+		if tokens, err = s.queries.ListActiveTokensByNetwork(ctx, network.ID); err != nil {
+			return fmt.Errorf("failed to list tokens on %s: %w", network.Name, err)
+		}
+		run.tokens[network.ID] = tokens
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	read, source, err := s.balanceReader(ctx, run, wallet, network)
+	if err != nil {
+		// Nothing can be read for this wallet on this network; keep the last balances, flagged
+		for _, token := range tokens {
+			run.errors++
+			s.recordBalanceError(ctx, run, wallet, network, token, source, err)
+		}
+		return nil
+	}
+
+	for _, token := range tokens {
+		balance, err := read(ctx, token)
+		if err != nil {
+			run.errors++
+			s.recordBalanceError(ctx, run, wallet, network, token, source, err)
+			continue
+		}
+
+		rate := s.exchangeRate(ctx, run, token)
+		upsert := db.UpsertWalletBalanceParams{
+			WorkspaceID:  wallet.WorkspaceID,
+			WalletID:     wallet.ID,
+			NetworkID:    network.ID,
+			TokenID:      token.ID,
+			Balance:      ratToNumeric(balance),
+			Source:       source,
+			FiatCurrency: run.currency,
+			FetchedAt:    pgtype.Timestamptz{Time: run.now, Valid: true},
+		}
+		if rate != nil {
+			upsert.ExchangeRate = ratToNumeric(rate)
+			upsert.FiatValueCents = pgtype.Int8{Int64: fiatCents(balance, rate), Valid: true}
+		}
+		if _, err := s.queries.UpsertWalletBalance(ctx, upsert); err != nil {
+			return fmt.Errorf("failed to store wallet balance: %w", err)
+		}
+		run.updated++
+	}
+	return nil
+}
+
+// tokenBalanceReader reads a wallet's balance of a token in whole token units
+type tokenBalanceReader func(ctx context.Context, token db.Token) (*big.Rat, error)
+
+// balanceReader picks where a wallet's balances are read from: Circle for Circle wallets when Circle is
+// configured, and the chain otherwise
+func (s *PortfolioService) balanceReader(ctx context.Context, run *portfolioRefresh, wallet db.ListWalletsWithCircleDataByWorkspaceIDRow, network db.Network) (tokenBalanceReader, string, error) {
+	if s.circle != nil && wallet.CircleID.Valid && wallet.CircleUserID.Valid {
+		read, err := s.circleBalances(ctx, run, wallet)
+		return read, business.WalletBalanceSourceCircle, err
+	}
+	if s.chain == nil {
+		return nil, business.WalletBalanceSourceRPC, errors.New("no balance source is configured for this wallet")
+	}
+
+	return func(ctx context.Context, token db.Token) (*big.Rat, error) {
+		var units *big.Int
+		var err error
+		if token.GasToken {
+			units, err = s.chain.GetNativeBalance(ctx, network.ID, wallet.WalletAddress)
+		} else {
+			units, err = s.chain.GetTokenBalance(ctx, network.ID, token.ContractAddress, wallet.WalletAddress)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s balance: %w", token.Symbol, err)
+		}
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(token.Decimals)), nil)
+		return new(big.Rat).SetFrac(units, scale), nil
+	}, business.WalletBalanceSourceRPC, nil
+}
+
+// circleBalances fetches a Circle wallet's balances once; tokens Circle does not report are held at zero
+func (s *PortfolioService) circleBalances(ctx context.Context, run *portfolioRefresh, wallet db.ListWalletsWithCircleDataByWorkspaceIDRow) (tokenBalanceReader, error) {
+	circleUserID := uuid.UUID(wallet.CircleUserID.Bytes)
+	userToken, ok := run.userTokens[circleUserID]
+	if !ok {
+		resp, err := s.circle.CreateUserToken(ctx, circleUserID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Circle user token: %w", err)
+		}
+		userToken = resp.Data.UserToken
+		run.userTokens[circleUserID] = userToken
+	}
+
+	balances, err := s.circle.GetWalletBalance(ctx, wallet.CircleID.String, userToken, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Circle wallet balance: %w", err)
+	}
+
+	return func(_ context.Context, token db.Token) (*big.Rat, error) {
+		for _, balance := range balances.Data.TokenBalances {
+			if (token.GasToken && balance.Token.IsNative) || (!token.GasToken && strings.EqualFold(balance.Token.TokenAddress, token.ContractAddress)) {
+				amount, err := parseDecimalAmount(balance.Amount)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s balance from Circle: %w", token.Symbol, err)
+				}
+				return amount, nil
+			}
+		}
+		return new(big.Rat), nil
+	}, nil
+}
+
+func (s *PortfolioService) recordBalanceError(ctx context.Context, run *portfolioRefresh, wallet db.ListWalletsWithCircleDataByWorkspaceIDRow, network db.Network, token db.Token, source string, cause error) {
+	s.logger.Warn("Failed to read wallet balance",
+		zap.String("wallet_id", wallet.ID.String()),
+		zap.String("network", network.Name),
+		zap.String("token", token.Symbol),
+		zap.Error(cause))
+
+	if err := s.queries.RecordWalletBalanceError(ctx, db.RecordWalletBalanceErrorParams{
+		WorkspaceID:  wallet.WorkspaceID,
+		WalletID:     wallet.ID,
+		NetworkID:    network.ID,
+		TokenID:      token.ID,
+		Source:       source,
+		FiatCurrency: run.currency,
+		AttemptedAt:  pgtype.Timestamptz{Time: run.now, Valid: true},
+		ErrorMessage: pgtype.Text{String: cause.Error(), Valid: true},
+	}); err != nil {
+		s.logger.Error("Failed to record wallet balance error", zap.String("wallet_id", wallet.ID.String()), zap.Error(err))
+	}
+}
+
+// exchangeRate returns a token's rate to the refresh currency, or nil when none is available
+func (s *PortfolioService) exchangeRate(ctx context.Context, run *portfolioRefresh, token db.Token) *big.Rat {
+	symbol := strings.ToUpper(token.Symbol)
+	if rate, ok := run.rates[symbol]; ok {
+		return rate
+	}
+
+	var rate *big.Rat
+	if s.rates != nil {
+		result, err := s.rates.GetExchangeRate(ctx, params.ExchangeRateParams{
+			FromSymbol: symbol,
+			ToSymbol:   run.currency,
+			TokenID:    &token.ID,
+			NetworkID:  &token.NetworkID,
+		})
+		if err != nil {
+			s.logger.Debug("No exchange rate for token", zap.String("token", symbol), zap.String("currency", run.currency), zap.Error(err))
+		} else if result.Rate > 0 {
+			rate = new(big.Rat).SetFloat64(result.Rate)
+		}
+	}
+	run.rates[symbol] = rate
+	return rate
+}
+
+// workspaceCurrency returns the workspace's default currency, falling back to USD when none is set
+func (s *PortfolioService) workspaceCurrency(ctx context.Context, workspaceID uuid.UUID) string {
+	currency, err := s.queries.GetWorkspaceDefaultCurrency(ctx, workspaceID)
+	if err != nil {
+		return constants.USDCurrency
+	}
+	return currency.Code
+}
+
+func (s *PortfolioService) listBalances(ctx context.Context, workspaceID uuid.UUID, walletID *uuid.UUID) ([]db.ListWalletBalancesByWorkspaceRow, error) {
+	arg := db.ListWalletBalancesByWorkspaceParams{WorkspaceID: workspaceID}
+	if walletID != nil {
+		arg.WalletID = pgtype.UUID{Bytes: *walletID, Valid: true}
+	}
+	rows, err := s.queries.ListWalletBalancesByWorkspace(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet balances: %w", err)
+	}
+	return rows, nil
+}
+
+// needsRefresh reports whether cached balances are missing, valued in another currency than the workspace's
+// default, or were last attempted before the freshness window. Freshness follows the last attempt rather than
+// the last successful read, so a source that keeps failing is not hit again on every read.
+func (s *PortfolioService) needsRefresh(rows []db.ListWalletBalancesByWorkspaceRow, currency string, now time.Time) bool {
+	if len(rows) == 0 {
+		return true
+	}
+	for _, row := range rows {
+		if row.FiatCurrency != currency {
+			return true
+		}
+		if row.ErrorMessage.Valid {
+			// Left to the processor's snapshot; the cached balance is served flagged until then
+			continue
+		}
+		if now.Sub(row.AttemptedAt.Time) > s.config.MaxAge {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PortfolioService) toPortfolio(workspaceID uuid.UUID, currency string, rows []db.ListWalletBalancesByWorkspaceRow, now time.Time) *business.Portfolio {
+	portfolio := &business.Portfolio{
+		WorkspaceID:  workspaceID,
+		FiatCurrency: currency,
+		Wallets:      []business.WalletHoldings{},
+	}
+
+	byWallet := map[uuid.UUID]int{}
+	for _, row := range rows {
+		i, ok := byWallet[row.WalletID]
+		if !ok {
+			i = len(portfolio.Wallets)
+			byWallet[row.WalletID] = i
+			portfolio.Wallets = append(portfolio.Wallets, business.WalletHoldings{
+				WalletID:      row.WalletID,
+				WalletAddress: row.WalletAddress,
+				WalletType:    row.WalletType,
+				Nickname:      row.Nickname.String,
+				Balances:      []business.WalletBalance{},
+			})
+		}
+
+		balance := business.WalletBalance{
+			NetworkID:    row.NetworkID,
+			NetworkName:  row.NetworkName,
+			ChainID:      row.ChainID,
+			TokenID:      row.TokenID,
+			TokenSymbol:  row.TokenSymbol,
+			TokenName:    row.TokenName,
+			TokenAddress: row.TokenAddress,
+			Source:       row.Source,
+			Error:        row.ErrorMessage.String,
+		}
+		if row.Balance.Valid {
+			balance.Balance = formatDecimal(numericToRat(row.Balance))
+		}
+		if row.FetchedAt.Valid {
+			fetchedAt := row.FetchedAt.Time
+			balance.FetchedAt = &fetchedAt
+		}
+		if row.ExchangeRate.Valid {
+			balance.ExchangeRate = formatDecimal(numericToRat(row.ExchangeRate))
+		}
+		if row.FiatValueCents.Valid && row.FiatCurrency == currency {
+			value := row.FiatValueCents.Int64
+			balance.FiatValueCents = &value
+			portfolio.Wallets[i].TotalValueCents += value
+			portfolio.TotalValueCents += value
+		}
+		portfolio.Wallets[i].Balances = append(portfolio.Wallets[i].Balances, balance)
+
+		if balance.FetchedAt != nil && (portfolio.AsOf == nil || balance.FetchedAt.Before(*portfolio.AsOf)) {
+			portfolio.AsOf = balance.FetchedAt
+		}
+		if row.ErrorMessage.Valid || !row.FetchedAt.Valid || now.Sub(row.FetchedAt.Time) > s.config.MaxAge {
+			portfolio.Stale = true
+		}
+	}
+	return portfolio
+}
+
+// portfolioHoldings totals cached balances per network and token, largest value first
+func portfolioHoldings(rows []db.ListWalletBalancesByWorkspaceRow, currency string) (int64, []business.PortfolioHolding) {
+	type holdingKey struct{ network, token uuid.UUID }
+	var order []holdingKey
+	amounts := map[holdingKey]*big.Rat{}
+	holdings := map[holdingKey]*business.PortfolioHolding{}
+
+	var total int64
+	for _, row := range rows {
+		if !row.Balance.Valid {
+			// Never read, so there is nothing to add to the snapshot
+			continue
+		}
+		key := holdingKey{row.NetworkID, row.TokenID}
+		if _, ok := holdings[key]; !ok {
+			order = append(order, key)
+			amounts[key] = new(big.Rat)
+			holdings[key] = &business.PortfolioHolding{NetworkID: row.NetworkID, TokenID: row.TokenID, TokenSymbol: row.TokenSymbol}
+		}
+		amounts[key].Add(amounts[key], numericToRat(row.Balance))
+		if row.FiatValueCents.Valid && row.FiatCurrency == currency {
+			holdings[key].ValueCents += row.FiatValueCents.Int64
+			total += row.FiatValueCents.Int64
+		}
+	}
+
+	result := make([]business.PortfolioHolding, 0, len(order))
+	for _, key := range order {
+		holding := *holdings[key]
+		holding.Balance = formatDecimal(amounts[key])
+		result = append(result, holding)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ValueCents > result[j].ValueCents })
+	return total, result
+}
+
+// fiatCents values a token balance at an exchange rate, rounded to the nearest cent
+func fiatCents(balance, rate *big.Rat) int64 {
+	value := new(big.Rat).Mul(balance, rate)
+	value.Mul(value, big.NewRat(100, 1))
+	cents, _ := strconv.ParseInt(value.FloatString(0), 10, 64)
+	return cents
+}
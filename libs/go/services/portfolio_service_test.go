@@ -0,0 +1,318 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/client/circle"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPortfolioService_GetPortfolio(t *testing.T) {
+	workspaceID := uuid.New()
+	base := db.Network{ID: uuid.New(), Name: "Base", NetworkType: db.NetworkTypeEvm, ChainID: 8453}
+	polygon := db.Network{ID: uuid.New(), Name: "Polygon", NetworkType: db.NetworkTypeEvm, ChainID: 137}
+	solana := db.Network{ID: uuid.New(), Name: "Solana", NetworkType: db.NetworkTypeSolana}
+	baseETH := db.Token{ID: uuid.New(), NetworkID: base.ID, GasToken: true, Symbol: "ETH", Decimals: 18}
+	baseUSDC := db.Token{ID: uuid.New(), NetworkID: base.ID, Symbol: "USDC", Decimals: 6, ContractAddress: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"}
+	polygonUSDC := db.Token{ID: uuid.New(), NetworkID: polygon.ID, Symbol: "USDC", Decimals: 6, ContractAddress: "0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359"}
+
+	t.Run("refreshes balances from Circle and the chain across the wallet's networks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		mockCircle := mocks.NewMockCircleClientInterface(ctrl)
+		mockChain := mocks.NewMockBlockchainService(ctrl)
+		mockRates := mocks.NewMockExchangeRateService(ctrl)
+
+		eoa := db.ListWalletsWithCircleDataByWorkspaceIDRow{
+			ID:            uuid.New(),
+			WorkspaceID:   workspaceID,
+			WalletType:    "wallet",
+			WalletAddress: "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+			NetworkType:   db.NetworkTypeEvm,
+		}
+		circleWallet := db.ListWalletsWithCircleDataByWorkspaceIDRow{
+			ID:            uuid.New(),
+			WorkspaceID:   workspaceID,
+			WalletType:    "circle_wallet",
+			WalletAddress: "0x1111111111111111111111111111111111111111",
+			NetworkType:   db.NetworkTypeEvm,
+			NetworkID:     pgtype.UUID{Bytes: base.ID, Valid: true},
+			CircleUserID:  pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			CircleID:      pgtype.Text{String: "circle-wallet-1", Valid: true},
+		}
+
+		mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(gomock.Any(), workspaceID).Return(db.FiatCurrency{Code: "EUR"}, nil)
+		mockQuerier.EXPECT().ListWalletsWithCircleDataByWorkspaceID(gomock.Any(), workspaceID).
+			Return([]db.ListWalletsWithCircleDataByWorkspaceIDRow{eoa, circleWallet}, nil)
+		mockQuerier.EXPECT().ListActiveNetworks(gomock.Any()).Return([]db.Network{base, polygon, solana}, nil)
+		mockQuerier.EXPECT().ListActiveTokensByNetwork(gomock.Any(), base.ID).Return([]db.Token{baseETH, baseUSDC}, nil)
+		mockQuerier.EXPECT().ListActiveTokensByNetwork(gomock.Any(), polygon.ID).Return([]db.Token{polygonUSDC}, nil)
+
+		mockChain.EXPECT().GetNativeBalance(gomock.Any(), base.ID, eoa.WalletAddress).
+			Return(new(big.Int).Mul(big.NewInt(15), big.NewInt(1e17)), nil)
+		mockChain.EXPECT().GetTokenBalance(gomock.Any(), base.ID, baseUSDC.ContractAddress, eoa.WalletAddress).Return(big.NewInt(2_500_000), nil)
+		mockChain.EXPECT().GetTokenBalance(gomock.Any(), polygon.ID, polygonUSDC.ContractAddress, eoa.WalletAddress).Return(nil, errors.New("rpc unavailable"))
+		mockQuerier.EXPECT().RecordWalletBalanceError(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.RecordWalletBalanceErrorParams) error {
+				assert.Equal(t, workspaceID, arg.WorkspaceID)
+				assert.Equal(t, eoa.ID, arg.WalletID)
+				assert.Equal(t, polygonUSDC.ID, arg.TokenID)
+				assert.Equal(t, business.WalletBalanceSourceRPC, arg.Source)
+				assert.Equal(t, "EUR", arg.FiatCurrency)
+				assert.True(t, arg.AttemptedAt.Valid)
+				assert.Contains(t, arg.ErrorMessage.String, "rpc unavailable")
+				return nil
+			})
+
+		userToken := &circle.UserTokenResponse{}
+		userToken.Data.UserToken = "user-token"
+		mockCircle.EXPECT().CreateUserToken(gomock.Any(), uuid.UUID(circleWallet.CircleUserID.Bytes).String()).Return(userToken, nil)
+		mockCircle.EXPECT().GetWalletBalance(gomock.Any(), "circle-wallet-1", "user-token", nil).Return(circleBalances(
+			circle.TokenBalance{Amount: "0.25", Token: circle.TokenInfo{IsNative: true, Symbol: "ETH", Decimals: 18}},
+			circle.TokenBalance{Amount: "100", Token: circle.TokenInfo{Symbol: "USDC", Decimals: 6, TokenAddress: "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"}},
+		), nil)
+
+		// Each token is priced once per refresh
+		mockRates.EXPECT().GetExchangeRate(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg params.ExchangeRateParams) (*responses.ExchangeRateResult, error) {
+				assert.Equal(t, "EUR", arg.ToSymbol)
+				rates := map[string]float64{"ETH": 2000.5, "USDC": 0.9}
+				return &responses.ExchangeRateResult{Rate: rates[arg.FromSymbol]}, nil
+			}).Times(2)
+
+		var stored []db.ListWalletBalancesByWorkspaceRow
+		mockQuerier.EXPECT().UpsertWalletBalance(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.UpsertWalletBalanceParams) (db.WalletBalance, error) {
+				assert.Equal(t, "EUR", arg.FiatCurrency)
+				stored = append(stored, db.ListWalletBalancesByWorkspaceRow{
+					WalletID:       arg.WalletID,
+					NetworkID:      arg.NetworkID,
+					TokenID:        arg.TokenID,
+					Balance:        arg.Balance,
+					Source:         arg.Source,
+					FiatCurrency:   arg.FiatCurrency,
+					ExchangeRate:   arg.ExchangeRate,
+					FiatValueCents: arg.FiatValueCents,
+					FetchedAt:      arg.FetchedAt,
+					AttemptedAt:    arg.FetchedAt,
+				})
+				return db.WalletBalance{}, nil
+			}).Times(4)
+
+		// Nothing is cached before the refresh
+		gomock.InOrder(
+			mockQuerier.EXPECT().ListWalletBalancesByWorkspace(gomock.Any(), db.ListWalletBalancesByWorkspaceParams{WorkspaceID: workspaceID}).
+				Return([]db.ListWalletBalancesByWorkspaceRow{}, nil),
+			mockQuerier.EXPECT().ListWalletBalancesByWorkspace(gomock.Any(), db.ListWalletBalancesByWorkspaceParams{WorkspaceID: workspaceID}).
+				DoAndReturn(func(context.Context, db.ListWalletBalancesByWorkspaceParams) ([]db.ListWalletBalancesByWorkspaceRow, error) {
+					return stored, nil
+				}),
+		)
+
+		service := services.NewPortfolioService(mockQuerier, mockCircle, mockChain, mockRates, services.DefaultPortfolioConfig())
+		portfolio, err := service.GetPortfolio(context.Background(), workspaceID, nil, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, "EUR", portfolio.FiatCurrency)
+		require.Len(t, portfolio.Wallets, 2)
+
+		onChain := portfolio.Wallets[0]
+		assert.Equal(t, eoa.ID, onChain.WalletID)
+		require.Len(t, onChain.Balances, 2)
+		assert.Equal(t, "1.5", onChain.Balances[0].Balance)
+		assert.Equal(t, business.WalletBalanceSourceRPC, onChain.Balances[0].Source)
+		assert.Equal(t, int64(300075), *onChain.Balances[0].FiatValueCents)
+		assert.Equal(t, "2.5", onChain.Balances[1].Balance)
+		assert.Equal(t, int64(225), *onChain.Balances[1].FiatValueCents)
+		assert.Equal(t, int64(300300), onChain.TotalValueCents)
+
+		custodial := portfolio.Wallets[1]
+		assert.Equal(t, circleWallet.ID, custodial.WalletID)
+		require.Len(t, custodial.Balances, 2)
+		assert.Equal(t, business.WalletBalanceSourceCircle, custodial.Balances[0].Source)
+		assert.Equal(t, "0.25", custodial.Balances[0].Balance)
+		assert.Equal(t, int64(50013), *custodial.Balances[0].FiatValueCents, "values round to the nearest cent")
+		assert.Equal(t, int64(9000), *custodial.Balances[1].FiatValueCents)
+
+		assert.Equal(t, int64(359313), portfolio.TotalValueCents)
+		require.NotNil(t, portfolio.AsOf)
+		assert.False(t, portfolio.Stale)
+	})
+
+	t.Run("serves cached balances without refreshing, flagging the ones that failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		walletID := uuid.New()
+		attemptedAt := time.Now().Add(-time.Minute)
+		lastReadAt := time.Now().Add(-time.Hour)
+
+		mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(gomock.Any(), workspaceID).Return(db.FiatCurrency{Code: "USD"}, nil)
+		mockQuerier.EXPECT().ListWalletBalancesByWorkspace(gomock.Any(), db.ListWalletBalancesByWorkspaceParams{
+			WorkspaceID: workspaceID,
+			WalletID:    pgtype.UUID{Bytes: walletID, Valid: true},
+		}).Return([]db.ListWalletBalancesByWorkspaceRow{
+			{
+				WalletID:       walletID,
+				NetworkID:      base.ID,
+				TokenID:        baseUSDC.ID,
+				TokenSymbol:    "USDC",
+				Balance:        treasuryNumeric(t, "42.5"),
+				Source:         business.WalletBalanceSourceRPC,
+				FiatCurrency:   "USD",
+				FiatValueCents: pgtype.Int8{Int64: 4250, Valid: true},
+				FetchedAt:      pgtype.Timestamptz{Time: attemptedAt, Valid: true},
+				AttemptedAt:    pgtype.Timestamptz{Time: attemptedAt, Valid: true},
+			},
+			{
+				// Its last read and failed refresh are both past the freshness window; retrying it is left to the snapshot
+				WalletID:     walletID,
+				NetworkID:    base.ID,
+				TokenID:      uuid.New(),
+				TokenSymbol:  "DEGEN",
+				Balance:      treasuryNumeric(t, "1000"),
+				Source:       business.WalletBalanceSourceRPC,
+				FiatCurrency: "USD",
+				FetchedAt:    pgtype.Timestamptz{Time: lastReadAt, Valid: true},
+				AttemptedAt:  pgtype.Timestamptz{Time: lastReadAt, Valid: true},
+				ErrorMessage: pgtype.Text{String: "failed to read DEGEN balance", Valid: true},
+			},
+			{
+				WalletID:     walletID,
+				NetworkID:    base.ID,
+				TokenID:      baseETH.ID,
+				TokenSymbol:  "ETH",
+				Source:       business.WalletBalanceSourceRPC,
+				FiatCurrency: "USD",
+				AttemptedAt:  pgtype.Timestamptz{Time: attemptedAt, Valid: true},
+				ErrorMessage: pgtype.Text{String: "failed to read ETH balance", Valid: true},
+			},
+		}, nil)
+
+		service := services.NewPortfolioService(mockQuerier, nil, nil, nil, services.DefaultPortfolioConfig())
+		portfolio, err := service.GetPortfolio(context.Background(), workspaceID, &walletID, false)
+		require.NoError(t, err)
+
+		require.Len(t, portfolio.Wallets, 1)
+		balances := portfolio.Wallets[0].Balances
+		require.Len(t, balances, 3)
+		assert.Equal(t, int64(4250), portfolio.TotalValueCents)
+		assert.Nil(t, balances[1].FiatValueCents, "tokens without a rate have no value")
+		assert.Equal(t, "", balances[2].Balance, "a balance that was never read has no amount")
+		assert.Nil(t, balances[2].FetchedAt)
+		assert.Equal(t, "failed to read ETH balance", balances[2].Error)
+		assert.Equal(t, lastReadAt, *portfolio.AsOf)
+		assert.True(t, portfolio.Stale, "a balance that failed to refresh makes the portfolio stale")
+	})
+
+	t.Run("refreshes when a balance was last attempted before the freshness window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		walletID := uuid.New()
+		cached := db.ListWalletBalancesByWorkspaceRow{
+			WalletID:     walletID,
+			NetworkID:    base.ID,
+			TokenID:      baseUSDC.ID,
+			TokenSymbol:  "USDC",
+			Balance:      treasuryNumeric(t, "42.5"),
+			Source:       business.WalletBalanceSourceRPC,
+			FiatCurrency: "USD",
+			FetchedAt:    pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+			AttemptedAt:  pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+		}
+
+		mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(gomock.Any(), workspaceID).Return(db.FiatCurrency{Code: "USD"}, nil)
+		mockQuerier.EXPECT().ListWalletBalancesByWorkspace(gomock.Any(), gomock.Any()).
+			Return([]db.ListWalletBalancesByWorkspaceRow{cached}, nil).Times(2)
+		mockQuerier.EXPECT().ListWalletsWithCircleDataByWorkspaceID(gomock.Any(), workspaceID).Return(nil, nil)
+		mockQuerier.EXPECT().ListActiveNetworks(gomock.Any()).Return(nil, nil)
+
+		service := services.NewPortfolioService(mockQuerier, nil, nil, nil, services.DefaultPortfolioConfig())
+		portfolio, err := service.GetPortfolio(context.Background(), workspaceID, &walletID, false)
+		require.NoError(t, err)
+		assert.True(t, portfolio.Stale)
+	})
+}
+
+func TestPortfolioService_RecordSnapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	now := time.Date(2026, 3, 2, 12, 34, 0, 0, time.UTC)
+	workspaceID := uuid.New()
+	networkID := uuid.New()
+	usdcID := uuid.New()
+	ethID := uuid.New()
+
+	mockQuerier.EXPECT().ListWorkspacesDuePortfolioSnapshot(gomock.Any(), db.ListWorkspacesDuePortfolioSnapshotParams{
+		SnapshotAt: pgtype.Timestamptz{Time: time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), Valid: true},
+		BatchSize:  services.DefaultPortfolioConfig().BatchSize,
+	}).Return([]uuid.UUID{workspaceID}, nil)
+	mockQuerier.EXPECT().GetWorkspaceDefaultCurrency(gomock.Any(), workspaceID).Return(db.FiatCurrency{}, errors.New("no rows"))
+	mockQuerier.EXPECT().ListWalletsWithCircleDataByWorkspaceID(gomock.Any(), workspaceID).Return(nil, nil)
+	mockQuerier.EXPECT().ListActiveNetworks(gomock.Any()).Return(nil, nil)
+
+	balance := func(tokenID uuid.UUID, symbol, amount string, cents int64) db.ListWalletBalancesByWorkspaceRow {
+		return db.ListWalletBalancesByWorkspaceRow{
+			WalletID:       uuid.New(),
+			NetworkID:      networkID,
+			TokenID:        tokenID,
+			TokenSymbol:    symbol,
+			Balance:        treasuryNumeric(t, amount),
+			FiatCurrency:   "USD",
+			FiatValueCents: pgtype.Int8{Int64: cents, Valid: true},
+			FetchedAt:      pgtype.Timestamptz{Time: now, Valid: true},
+		}
+	}
+	mockQuerier.EXPECT().ListWalletBalancesByWorkspace(gomock.Any(), db.ListWalletBalancesByWorkspaceParams{WorkspaceID: workspaceID}).
+		Return([]db.ListWalletBalancesByWorkspaceRow{
+			balance(usdcID, "USDC", "100.5", 10050),
+			balance(ethID, "ETH", "0.5", 150000),
+			balance(usdcID, "USDC", "20", 2000),
+			{WalletID: uuid.New(), NetworkID: networkID, TokenID: uuid.New(), TokenSymbol: "DEGEN", FiatCurrency: "USD", ErrorMessage: pgtype.Text{String: "rpc unavailable", Valid: true}},
+		}, nil)
+
+	mockQuerier.EXPECT().UpsertPortfolioSnapshot(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.UpsertPortfolioSnapshotParams) (db.PortfolioSnapshot, error) {
+			assert.Equal(t, workspaceID, arg.WorkspaceID)
+			assert.Equal(t, "USD", arg.FiatCurrency, "workspaces without a default currency are valued in USD")
+			assert.Equal(t, int64(162050), arg.TotalValueCents)
+
+			var holdings []business.PortfolioHolding
+			require.NoError(t, json.Unmarshal(arg.Holdings, &holdings))
+			assert.Equal(t, []business.PortfolioHolding{
+				{NetworkID: networkID, TokenID: ethID, TokenSymbol: "ETH", Balance: "0.5", ValueCents: 150000},
+				{NetworkID: networkID, TokenID: usdcID, TokenSymbol: "USDC", Balance: "120.5", ValueCents: 12050},
+			}, holdings)
+			return db.PortfolioSnapshot{}, nil
+		})
+
+	service := services.NewPortfolioService(mockQuerier, nil, nil, nil, services.DefaultPortfolioConfig())
+	result, err := service.RecordSnapshots(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, &business.PortfolioSnapshotResult{Checked: 1, Snapshots: 1}, result)
+}
+
+func TestPortfolioService_ListSnapshots(t *testing.T) {
+	service := services.NewPortfolioService(nil, nil, nil, nil, services.DefaultPortfolioConfig())
+	now := time.Now()
+
+	_, err := service.ListSnapshots(context.Background(), uuid.New(), now, now.Add(-time.Hour))
+	assert.ErrorIs(t, err, services.ErrInvalidPortfolioRange)
+
+	_, err = service.ListSnapshots(context.Background(), uuid.New(), now.AddDate(-2, 0, 0), now)
+	assert.ErrorIs(t, err, services.ErrInvalidPortfolioRange)
+}
@@ -577,7 +577,8 @@ func (s *ProductService) ValidateSubscriptionRequest(ctx context.Context, params
 		return fmt.Errorf("invalid product ID format")
 	}
 
-	if _, err := uuid.Parse(params.ProductTokenID); err != nil {
+	productTokenID, err := uuid.Parse(params.ProductTokenID)
+	if err != nil {
 		return fmt.Errorf("invalid product token ID format")
 	}
 
@@ -590,6 +591,19 @@ func (s *ProductService) ValidateSubscriptionRequest(ctx context.Context, params
 		return fmt.Errorf("token amount must be greater than zero")
 	}
 
+	productToken, err := s.queries.GetProductToken(ctx, productTokenID)
+	if err != nil {
+		return fmt.Errorf("product token not found")
+	}
+
+	// Solana customers approve the payment delegate on their token account instead of signing a delegation
+	if productToken.NetworkType == string(db.NetworkTypeSolana) {
+		if err := helpers.ValidateSplApprovalData(params.Delegation, params.SolanaDelegateAddress); err != nil {
+			return fmt.Errorf("approval validation failed: %w", err)
+		}
+		return nil
+	}
+
 	// Validate delegation data
 	if err := helpers.ValidateDelegationData(params.Delegation, params.CypheraSmartWalletAddress); err != nil {
 		return fmt.Errorf("delegation validation failed: %w", err)
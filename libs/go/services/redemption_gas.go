@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	dsClient "github.com/cyphera/cyphera-api/libs/go/client/delegation_server"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// redemptionGasLimit is the gas limit assumed for a redemption when its call cannot be simulated
+const redemptionGasLimit = 150000
+
+var (
+	// redeemDelegationsSelector is the selector of DelegationManager.redeemDelegations(bytes[],bytes32[],bytes[])
+	redeemDelegationsSelector = crypto.Keccak256([]byte("redeemDelegations(bytes[],bytes32[],bytes[])"))[:4]
+	// erc20TransferSelector is the selector of transfer(address,uint256)
+	erc20TransferSelector = crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
+
+	redeemDelegationsArguments = abi.Arguments{
+		{Type: mustNewABIType("bytes[]", nil)},
+		{Type: mustNewABIType("bytes32[]", nil)},
+		{Type: mustNewABIType("bytes[]", nil)},
+	}
+	// permissionContextArguments encodes the delegation chain a redemption is authorized by
+	permissionContextArguments = abi.Arguments{
+		{Type: mustNewABIType("tuple[]", []abi.ArgumentMarshaling{
+			{Name: "delegate", Type: "address"},
+			{Name: "delegator", Type: "address"},
+			{Name: "authority", Type: "bytes32"},
+			{Name: "caveats", Type: "tuple[]", Components: []abi.ArgumentMarshaling{
+				{Name: "enforcer", Type: "address"},
+				{Name: "terms", Type: "bytes"},
+				{Name: "args", Type: "bytes"},
+			}},
+			{Name: "salt", Type: "uint256"},
+			{Name: "signature", Type: "bytes"},
+		})},
+	}
+)
+
+// abiDelegation and abiCaveat mirror the DelegationManager's Delegation and Caveat structs
+type abiDelegation struct {
+	Delegate  common.Address
+	Delegator common.Address
+	Authority [32]byte
+	Caveats   []abiCaveat
+	Salt      *big.Int
+	Signature []byte
+}
+
+type abiCaveat struct {
+	Enforcer common.Address
+	Terms    []byte
+	Args     []byte
+}
+
+func mustNewABIType(t string, components []abi.ArgumentMarshaling) abi.Type {
+	abiType, err := abi.NewType(t, "", components)
+	if err != nil {
+		panic(err)
+	}
+	return abiType
+}
+
+// RedemptionCall builds the DelegationManager.redeemDelegations call that redeems a delegation for an
+// execution: the delegate redeems a single-delegation chain for one ERC-20 transfer to the merchant
+func RedemptionCall(delegationManager common.Address, delegationJSON []byte, execution dsClient.ExecutionObject) (*params.GasEstimateCall, error) {
+	var delegation dsClient.DelegationData
+	if err := json.Unmarshal(delegationJSON, &delegation); err != nil {
+		return nil, fmt.Errorf("failed to decode delegation: %w", err)
+	}
+	if !common.IsHexAddress(delegation.Delegate) || !common.IsHexAddress(delegation.Delegator) {
+		return nil, fmt.Errorf("delegate and delegator must be addresses")
+	}
+	if !common.IsHexAddress(execution.TokenContractAddress) || !common.IsHexAddress(execution.MerchantAddress) {
+		return nil, fmt.Errorf("token and merchant must be addresses")
+	}
+
+	authority, err := hexutil.Decode(delegation.Authority)
+	if err != nil || len(authority) != common.HashLength {
+		return nil, fmt.Errorf("authority must be a 32 byte hex value")
+	}
+	salt, err := parseDelegationSalt(delegation.Salt)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := hexutil.Decode(delegation.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("signature is not hex: %w", err)
+	}
+
+	caveats, err := ParseDelegationCaveats(delegation.Caveats)
+	if err != nil {
+		return nil, err
+	}
+	abiCaveats := make([]abiCaveat, 0, len(caveats))
+	for _, caveat := range caveats {
+		if !common.IsHexAddress(caveat.Enforcer) {
+			return nil, fmt.Errorf("invalid caveat enforcer address %q", caveat.Enforcer)
+		}
+		terms, err := hexutil.Decode(caveat.Terms)
+		if err != nil {
+			return nil, fmt.Errorf("caveat terms are not hex: %w", err)
+		}
+		abiCaveats = append(abiCaveats, abiCaveat{Enforcer: common.HexToAddress(caveat.Enforcer), Terms: terms, Args: []byte{}})
+	}
+
+	permissionContext, err := permissionContextArguments.Pack([]abiDelegation{{
+		Delegate:  common.HexToAddress(delegation.Delegate),
+		Delegator: common.HexToAddress(delegation.Delegator),
+		Authority: [32]byte(authority),
+		Caveats:   abiCaveats,
+		Salt:      salt,
+		Signature: signature,
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode delegation: %w", err)
+	}
+
+	// A single execution is packed as target, value and calldata; the default mode is a single call
+	transfer := append(append(append([]byte{}, erc20TransferSelector...),
+		common.LeftPadBytes(common.HexToAddress(execution.MerchantAddress).Bytes(), 32)...),
+		common.LeftPadBytes(big.NewInt(execution.TokenAmount).Bytes(), 32)...)
+	executionCallData := append(append(common.HexToAddress(execution.TokenContractAddress).Bytes(),
+		make([]byte, 32)...), transfer...)
+
+	arguments, err := redeemDelegationsArguments.Pack([][]byte{permissionContext}, [][32]byte{{}}, [][]byte{executionCallData})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode redemption: %w", err)
+	}
+
+	return &params.GasEstimateCall{
+		From: common.HexToAddress(delegation.Delegate).Hex(),
+		To:   delegationManager.Hex(),
+		Data: append(append([]byte{}, redeemDelegationsSelector...), arguments...),
+	}, nil
+}
+
+// RedemptionGasEstimator estimates the network fee of delegation redemptions by simulating the call they send,
+// and records the estimate against the payment the redemption creates. Estimates are best effort: a nil
+// estimator, or one without a DelegationManager address, estimates nothing.
+type RedemptionGasEstimator struct {
+	gasFees           interfaces.GasFeeService
+	delegationManager common.Address
+	logger            *zap.Logger
+}
+
+// NewRedemptionGasEstimator creates an estimator simulating redemptions through the given DelegationManager
+func NewRedemptionGasEstimator(gasFees interfaces.GasFeeService, delegationManager common.Address) *RedemptionGasEstimator {
+	return &RedemptionGasEstimator{
+		gasFees:           gasFees,
+		delegationManager: delegationManager,
+		logger:            logger.Log,
+	}
+}
+
+// Estimate estimates the fee of redeeming a delegation for an execution. It must run before the redemption is
+// sent, since the delegation's caveats reject the same call once the period's allowance is spent.
+func (e *RedemptionGasEstimator) Estimate(ctx context.Context, networkID uuid.UUID, delegation []byte, execution dsClient.ExecutionObject) *responses.EstimateGasFeeResult {
+	if e == nil || e.gasFees == nil || e.delegationManager == (common.Address{}) {
+		return nil
+	}
+
+	call, err := RedemptionCall(e.delegationManager, delegation, execution)
+	if err != nil {
+		e.logger.Warn("Could not build redemption call for gas estimate", zap.Error(err))
+		return nil
+	}
+
+	estimate, err := e.gasFees.EstimateGasFee(ctx, params.EstimateGasFeeParams{
+		NetworkID:         networkID,
+		TransactionType:   "delegation",
+		EstimatedGasLimit: redemptionGasLimit,
+		Currency:          "USD",
+		Call:              call,
+	})
+	if err != nil {
+		e.logger.Warn("Failed to estimate redemption gas fee",
+			zap.String("network_id", networkID.String()),
+			zap.Error(err))
+		return nil
+	}
+	return estimate
+}
+
+// Record stores a redemption's estimate against the payment it created, so it can be compared with the actual
+// fee once the transaction confirms
+func (e *RedemptionGasEstimator) Record(ctx context.Context, payment *db.Payment, networkID uuid.UUID, estimate *responses.EstimateGasFeeResult) {
+	if e == nil || payment == nil || estimate == nil {
+		return
+	}
+	if err := e.gasFees.RecordGasFeeEstimate(ctx, payment.WorkspaceID, payment.ID, networkID, estimate); err != nil {
+		e.logger.Warn("Failed to record redemption gas estimate",
+			zap.String("payment_id", payment.ID.String()),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,148 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	dsClient "github.com/cyphera/cyphera-api/libs/go/client/delegation_server"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const redeemDelegationsABI = `[{"type":"function","name":"redeemDelegations","inputs":[
+	{"name":"permissionContexts","type":"bytes[]"},
+	{"name":"modes","type":"bytes32[]"},
+	{"name":"executionCallDatas","type":"bytes[]"}]}]`
+
+var (
+	testDelegationManager = common.HexToAddress("0xdb9B1e94B5b69Df7e401DDbedE43491141047dB3")
+	testDelegate          = "0x1111111111111111111111111111111111111111"
+	testDelegator         = "0x2222222222222222222222222222222222222222"
+	testEnforcer          = "0x3333333333333333333333333333333333333333"
+	testMerchant          = "0x4444444444444444444444444444444444444444"
+	testToken             = "0x5555555555555555555555555555555555555555"
+)
+
+func testRedemptionDelegation(t *testing.T) []byte {
+	delegation, err := json.Marshal(dsClient.DelegationData{
+		Delegate:  testDelegate,
+		Delegator: testDelegator,
+		Authority: "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		Caveats:   json.RawMessage(`[{"enforcer":"` + testEnforcer + `","terms":"0x0102"}]`),
+		Salt:      "0x01",
+		Signature: "0xabcdef",
+	})
+	require.NoError(t, err)
+	return delegation
+}
+
+func testRedemptionExecution() dsClient.ExecutionObject {
+	return dsClient.ExecutionObject{
+		MerchantAddress:      testMerchant,
+		TokenContractAddress: testToken,
+		TokenAmount:          2500000,
+		TokenDecimals:        6,
+		ChainID:              8453,
+		NetworkName:          "base",
+	}
+}
+
+func TestRedemptionCall(t *testing.T) {
+	call, err := services.RedemptionCall(testDelegationManager, testRedemptionDelegation(t), testRedemptionExecution())
+	require.NoError(t, err)
+
+	// The delegate redeems through the DelegationManager
+	assert.Equal(t, testDelegationManager.Hex(), call.To)
+	assert.Equal(t, common.HexToAddress(testDelegate).Hex(), call.From)
+
+	managerABI, err := abi.JSON(strings.NewReader(redeemDelegationsABI))
+	require.NoError(t, err)
+	method, err := managerABI.MethodById(call.Data[:4])
+	require.NoError(t, err)
+	assert.Equal(t, "redeemDelegations", method.Name)
+
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	require.NoError(t, err)
+	require.Len(t, args, 3)
+	permissionContexts := args[0].([][]byte)
+	modes := args[1].([][32]byte)
+	executionCallDatas := args[2].([][]byte)
+	require.Len(t, permissionContexts, 1)
+	assert.Equal(t, [][32]byte{{}}, modes)
+	require.Len(t, executionCallDatas, 1)
+
+	// The permission context carries the signed delegation
+	assert.Contains(t, string(permissionContexts[0]), string(common.HexToAddress(testDelegator).Bytes()))
+	assert.Contains(t, string(permissionContexts[0]), string([]byte{0xab, 0xcd, 0xef}))
+
+	// The execution transfers the subscription's amount of the token to the merchant
+	execution := executionCallDatas[0]
+	require.Len(t, execution, 20+32+4+32+32)
+	assert.Equal(t, common.HexToAddress(testToken).Bytes(), execution[:20])
+	assert.Equal(t, make([]byte, 32), execution[20:52])
+	assert.Equal(t, crypto.Keccak256([]byte("transfer(address,uint256)"))[:4], execution[52:56])
+	assert.Equal(t, common.HexToAddress(testMerchant), common.BytesToAddress(execution[56:88]))
+	assert.Equal(t, big.NewInt(2500000), new(big.Int).SetBytes(execution[88:120]))
+}
+
+func TestRedemptionGasEstimator(t *testing.T) {
+	ctx := context.Background()
+	networkID := uuid.New()
+	estimate := &responses.EstimateGasFeeResult{EstimatedGasUnits: 98000, GasUnitsSimulated: true}
+
+	t.Run("estimates against the redemption call", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		gasFees := mocks.NewMockGasFeeService(ctrl)
+		estimator := services.NewRedemptionGasEstimator(gasFees, testDelegationManager)
+
+		wantCall, err := services.RedemptionCall(testDelegationManager, testRedemptionDelegation(t), testRedemptionExecution())
+		require.NoError(t, err)
+
+		gasFees.EXPECT().EstimateGasFee(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, estimateParams params.EstimateGasFeeParams) (*responses.EstimateGasFeeResult, error) {
+				assert.Equal(t, networkID, estimateParams.NetworkID)
+				assert.Equal(t, wantCall, estimateParams.Call)
+				return estimate, nil
+			})
+
+		assert.Equal(t, estimate, estimator.Estimate(ctx, networkID, testRedemptionDelegation(t), testRedemptionExecution()))
+	})
+
+	t.Run("records the estimate with the redemption's payment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		gasFees := mocks.NewMockGasFeeService(ctrl)
+		estimator := services.NewRedemptionGasEstimator(gasFees, testDelegationManager)
+		payment := &db.Payment{ID: uuid.New(), WorkspaceID: uuid.New()}
+
+		gasFees.EXPECT().RecordGasFeeEstimate(ctx, payment.WorkspaceID, payment.ID, networkID, estimate).Return(nil)
+
+		estimator.Record(ctx, payment, networkID, estimate)
+	})
+
+	t.Run("skips estimates without a DelegationManager", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		estimator := services.NewRedemptionGasEstimator(mocks.NewMockGasFeeService(ctrl), common.Address{})
+
+		assert.Nil(t, estimator.Estimate(ctx, networkID, testRedemptionDelegation(t), testRedemptionExecution()))
+	})
+
+	t.Run("nil estimator does nothing", func(t *testing.T) {
+		var estimator *services.RedemptionGasEstimator
+
+		assert.Nil(t, estimator.Estimate(ctx, networkID, testRedemptionDelegation(t), testRedemptionExecution()))
+		estimator.Record(ctx, &db.Payment{ID: uuid.New()}, networkID, estimate)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -13,8 +14,11 @@ import (
 	"github.com/cyphera/cyphera-api/libs/go/helpers"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
 	"github.com/cyphera/cyphera-api/libs/go/types/business"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
@@ -23,49 +27,115 @@ type PaymentServiceInterface interface {
 	CreatePaymentFromSubscriptionEvent(ctx context.Context, params params.CreatePaymentFromSubscriptionEventParams) (*db.Payment, error)
 }
 
-// RedemptionProcessor processes redemption tasks from a queue
+// RedemptionQueueConfig controls how the redemption processor works the redemption queue
+type RedemptionQueueConfig struct {
+	// PollInterval is how long an idle worker waits before looking for tasks again
+	PollInterval time.Duration
+	// VisibilityTimeout hides a claimed task from other workers; it must outlast a redemption.
+	// A task whose worker stopped is claimed again once it runs out.
+	VisibilityTimeout time.Duration
+	// MaxAttempts is how many times a task is tried before it is dead-lettered
+	MaxAttempts int32
+	// RetryBackoff is the delay before the first retry of a failed task; it doubles with every attempt
+	RetryBackoff time.Duration
+	// FailureThreshold is how many consecutive delegation server health check failures open the circuit breaker
+	FailureThreshold int32
+	// ResetTimeout is how long the circuit breaker stays open before the delegation server is probed again
+	ResetTimeout time.Duration
+}
+
+// DefaultRedemptionQueueConfig returns the redemption queue settings used when none are configured
+func DefaultRedemptionQueueConfig() RedemptionQueueConfig {
+	return RedemptionQueueConfig{
+		PollInterval:      5 * time.Second,
+		VisibilityTimeout: 5 * time.Minute,
+		MaxAttempts:       5,
+		RetryBackoff:      30 * time.Second,
+		FailureThreshold:  3,
+		ResetTimeout:      5 * time.Minute,
+	}
+}
+
+// maxRedemptionRetryBackoff caps the delay between retries of a failed task
+const maxRedemptionRetryBackoff = time.Hour
+
+// errDelegationServerUnavailable marks tasks that were not tried because the delegation server is down
+var errDelegationServerUnavailable = errors.New("delegation server unavailable")
+
+// errRedemptionOutcomeUnknown marks redemptions that may have been broadcast, so retrying could charge the
+// customer twice
+var errRedemptionOutcomeUnknown = errors.New("redemption outcome unknown")
+
+// RedemptionProcessor processes redemption tasks from the durable redemption queue. Tasks are claimed with
+// FOR UPDATE SKIP LOCKED and hidden for a visibility timeout, so they survive restarts and are shared by every
+// processor; the circuit breaker around the delegation server is shared the same way.
 type RedemptionProcessor struct {
-	tasks            chan business.RedemptionTask
 	dbQueries        db.Querier
 	delegationClient *dsClient.DelegationClient
 	paymentService   PaymentServiceInterface
+	gasEstimates     *RedemptionGasEstimator
+	queue            *RedemptionQueueService
+	circuitBreaker   *CircuitBreaker
+	config           RedemptionQueueConfig
+	workerID         pgtype.Text
 	workerCount      int
 	wg               sync.WaitGroup
 	ctx              context.Context
 	cancel           context.CancelFunc
-
-	// Circuit breaker pattern to handle delegation server downtime
-	mu                  sync.Mutex
-	circuitOpen         bool
-	consecutiveFailures int
-	failureThreshold    int
-	resetTimeout        time.Duration
-	lastFailureTime     time.Time
-	pendingTasks        []business.RedemptionTask
 }
 
-// NewRedemptionProcessor creates a new redemption processor with the given number of workers
-// and queue buffer size
+// NewRedemptionProcessor creates a new redemption processor with the given number of workers. gasEstimates
+// estimates each redemption's network fee before it is sent and may be nil.
 func NewRedemptionProcessor(
 	dbQueries db.Querier,
 	delegationClient *dsClient.DelegationClient,
 	paymentService PaymentServiceInterface,
+	gasEstimates *RedemptionGasEstimator,
 	workerCount int,
-	bufferSize int,
+	config RedemptionQueueConfig,
 ) *RedemptionProcessor {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	defaults := DefaultRedemptionQueueConfig()
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.VisibilityTimeout <= 0 {
+		config.VisibilityTimeout = defaults.VisibilityTimeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaults.RetryBackoff
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaults.FailureThreshold
+	}
+	if config.ResetTimeout <= 0 {
+		config.ResetTimeout = defaults.ResetTimeout
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "redemption-processor"
+	}
+
+	queue := NewRedemptionQueueService(dbQueries)
+	queue.maxAttempts = config.MaxAttempts
+
 	rp := &RedemptionProcessor{
-		tasks:            make(chan business.RedemptionTask, bufferSize),
 		dbQueries:        dbQueries,
 		delegationClient: delegationClient,
 		paymentService:   paymentService,
+		gasEstimates:     gasEstimates,
+		queue:            queue,
+		circuitBreaker:   NewCircuitBreaker(dbQueries, DelegationServerCircuitBreaker, config.FailureThreshold, config.ResetTimeout),
+		config:           config,
+		workerID:         pgtype.Text{String: fmt.Sprintf("%s:%s", hostname, uuid.New().String()), Valid: true},
 		workerCount:      workerCount,
 		ctx:              ctx,
 		cancel:           cancel,
-		failureThreshold: 3,
-		resetTimeout:     5 * time.Minute,
-		pendingTasks:     make([]business.RedemptionTask, 0),
 	}
 
 	return rp
@@ -73,7 +143,10 @@ func NewRedemptionProcessor(
 
 // Start starts the redemption processor
 func (rp *RedemptionProcessor) Start() {
-	logger.Info("Starting redemption processor with workers", zap.Int("worker_count", rp.workerCount))
+	logger.Info("Starting redemption processor with workers",
+		zap.Int("worker_count", rp.workerCount),
+		zap.String("worker_id", rp.workerID.String),
+	)
 
 	// Start a separate goroutine to monitor the delegation server health
 	go rp.monitorDelegationServerHealth()
@@ -88,25 +161,23 @@ func (rp *RedemptionProcessor) Start() {
 			logger.Debug("Redemption worker started", zap.Int("worker_id", workerID))
 
 			for {
+				// Keep claiming while there is work, and wait for the poll interval once the queue is empty
+				if rp.processNext() {
+					continue
+				}
 				select {
 				case <-rp.ctx.Done():
 					logger.Debug("Redemption worker stopped", zap.Int("worker_id", workerID))
 					return
-				case task := <-rp.tasks:
-					err := rp.processRedemption(task)
-					if err != nil {
-						logger.Error("Failed to process redemption",
-							zap.Error(err),
-							zap.String("subscription_id", task.SubscriptionID.String()),
-						)
-					}
+				case <-time.After(rp.config.PollInterval):
 				}
 			}
 		}()
 	}
 }
 
-// Stop stops the redemption processor
+// Stop stops the redemption processor. Tasks still being processed become visible to other
+// processors once their visibility timeout runs out.
 func (rp *RedemptionProcessor) Stop() {
 	logger.Info("Stopping redemption processor")
 	rp.cancel()
@@ -114,67 +185,181 @@ func (rp *RedemptionProcessor) Stop() {
 	logger.Info("Redemption processor stopped")
 }
 
-// QueueRedemption adds a redemption task to the queue
+// QueueRedemption adds a redemption task to the durable queue
 func (rp *RedemptionProcessor) QueueRedemption(task business.RedemptionTask) error {
-	// Check if circuit breaker is open
-	rp.mu.Lock()
-	defer rp.mu.Unlock()
+	queued, err := rp.queue.Enqueue(rp.ctx, task)
+	if err != nil {
+		return err
+	}
 
-	if rp.circuitOpen {
-		// Store task for later processing when the circuit breaker resets
-		logger.Info("Circuit breaker open, storing task for later",
-			zap.String("subscription_id", task.SubscriptionID.String()),
-		)
-		rp.pendingTasks = append(rp.pendingTasks, task)
-		return nil
+	logger.Debug("Redemption task queued",
+		zap.String("task_id", queued.ID.String()),
+		zap.String("subscription_id", task.SubscriptionID.String()),
+	)
+	return nil
+}
+
+// processNext claims and processes one task. It returns false when no task was processed, either because
+// the queue is empty or because the circuit breaker is open.
+func (rp *RedemptionProcessor) processNext() bool {
+	if rp.ctx.Err() != nil {
+		return false
 	}
 
-	// Try to add the task to the queue, with a timeout to avoid blocking
-	select {
-	case rp.tasks <- task:
-		logger.Debug("Redemption task queued",
-			zap.String("subscription_id", task.SubscriptionID.String()),
+	breaker, err := rp.circuitBreaker.State(rp.ctx)
+	if err != nil {
+		logger.Error("Failed to check delegation server circuit breaker", zap.Error(err))
+		return false
+	}
+	if breaker.State != CircuitBreakerStateClosed {
+		return false
+	}
+
+	now := time.Now()
+	tasks, err := rp.dbQueries.ClaimRedemptionTasks(rp.ctx, db.ClaimRedemptionTasksParams{
+		LockedBy:     rp.workerID,
+		VisibleUntil: pgtype.Timestamptz{Time: now.Add(rp.config.VisibilityTimeout), Valid: true},
+		Now:          pgtype.Timestamptz{Time: now, Valid: true},
+		BatchSize:    1,
+	})
+	if err != nil {
+		if rp.ctx.Err() == nil {
+			logger.Error("Failed to claim redemption tasks", zap.Error(err))
+		}
+		return false
+	}
+	if len(tasks) == 0 {
+		return false
+	}
+
+	rp.handleTask(tasks[0])
+	return true
+}
+
+// handleTask processes a claimed task and settles it as succeeded, retried, deferred or dead-lettered
+func (rp *RedemptionProcessor) handleTask(task db.RedemptionTask) {
+	logFields := []zap.Field{
+		zap.String("task_id", task.ID.String()),
+		zap.String("subscription_id", task.SubscriptionID.String()),
+		zap.Int32("attempt", task.Attempts),
+	}
+
+	// A worker stopped while this redemption was in flight, so the customer may have been charged
+	if task.RedemptionStartedAt.Valid {
+		logger.Warn("Redemption task was interrupted during redemption, dead-lettering it for review", logFields...)
+		rp.deadLetter(task, "interrupted while the redemption was in flight; check the transaction before requeuing")
+		return
+	}
+
+	// Claiming counts an attempt, including reclaims of tasks whose worker stopped before settling them
+	if task.Attempts > task.MaxAttempts {
+		logger.Warn("Redemption task was reclaimed after running out of attempts, dead-lettering it", logFields...)
+		rp.deadLetter(task, fmt.Sprintf("ran out of attempts after %d claims; last error: %s", task.Attempts-1, task.LastError.String))
+		return
+	}
+
+	completed, err := rp.processRedemption(task)
+	if err == nil {
+		return
+	}
+	if completed {
+		// The redemption went through and the task succeeded; only its bookkeeping failed
+		logger.Error("Redemption succeeded but was not fully recorded", append(logFields, zap.Error(err))...)
+		return
+	}
+
+	logger.Error("Failed to process redemption", append(logFields, zap.Error(err))...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	switch {
+	case errors.Is(err, errDelegationServerUnavailable):
+		// Not the task's fault, so the attempt is not counted
+		_, settleErr := rp.dbQueries.DeferRedemptionTask(ctx, db.DeferRedemptionTaskParams{
+			VisibleAt: pgtype.Timestamptz{Time: time.Now().Add(rp.config.RetryBackoff), Valid: true},
+			LastError: helpers.StringToNullableText(err.Error()),
+			ID:        task.ID,
+			LockedBy:  rp.workerID,
+		})
+		if settleErr != nil {
+			logger.Error("Failed to defer redemption task", append(logFields, zap.Error(settleErr))...)
+		}
+	case errors.Is(err, errRedemptionOutcomeUnknown):
+		logger.Warn("Redemption may have been sent, dead-lettering it for review", logFields...)
+		rp.deadLetter(task, fmt.Sprintf("%s; check the transaction before requeuing", err))
+	case task.Attempts >= task.MaxAttempts:
+		logger.Warn("Redemption task ran out of attempts, dead-lettering it", logFields...)
+		rp.deadLetter(task, err.Error())
+	default:
+		_, settleErr := rp.dbQueries.RetryRedemptionTask(ctx, db.RetryRedemptionTaskParams{
+			VisibleAt: pgtype.Timestamptz{Time: time.Now().Add(rp.retryBackoff(task.Attempts)), Valid: true},
+			LastError: helpers.StringToNullableText(err.Error()),
+			ID:        task.ID,
+			LockedBy:  rp.workerID,
+		})
+		if settleErr != nil {
+			logger.Error("Failed to schedule redemption task retry", append(logFields, zap.Error(settleErr))...)
+		}
+	}
+}
+
+// deadLetter stops retrying a task until an admin requeues it
+func (rp *RedemptionProcessor) deadLetter(task db.RedemptionTask, reason string) {
+	// Settled on a fresh context so that tasks are still settled once the processor is stopping
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := rp.dbQueries.DeadLetterRedemptionTask(ctx, db.DeadLetterRedemptionTaskParams{
+		LastError: helpers.StringToNullableText(reason),
+		ID:        task.ID,
+		LockedBy:  rp.workerID,
+	}); err != nil {
+		logger.Error("Failed to dead-letter redemption task",
+			zap.String("task_id", task.ID.String()),
+			zap.Error(err),
 		)
-		return nil
-	case <-time.After(5 * time.Second):
-		return errors.New("redemption queue is full, try again later")
 	}
 }
 
-// processRedemption processes a redemption task
-func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) error {
+// retryBackoff doubles the retry delay with every attempt, up to maxRedemptionRetryBackoff
+func (rp *RedemptionProcessor) retryBackoff(attempts int32) time.Duration {
+	backoff := rp.config.RetryBackoff
+	for i := int32(1); i < attempts && backoff < maxRedemptionRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRedemptionRetryBackoff)
+}
+
+// processRedemption processes a claimed redemption task. completed reports whether the delegation was redeemed
+// and the task marked as succeeded, after which the task must not be retried whatever the error.
+func (rp *RedemptionProcessor) processRedemption(task db.RedemptionTask) (completed bool, err error) {
 	ctx, cancel := context.WithTimeout(rp.ctx, 60*time.Second)
 	defer cancel()
 
 	// Check if the delegation server is available before attempting redemption
-	err := rp.delegationClient.HealthCheck(ctx)
+	err = rp.delegationClient.HealthCheck(ctx)
 	if err != nil {
 		logger.Warn("Delegation server unavailable, incrementing failure counter",
 			zap.Error(err),
 			zap.String("subscription_id", task.SubscriptionID.String()),
 		)
 
-		// Increment consecutive failures and consider opening circuit breaker
-		rp.mu.Lock()
-		rp.consecutiveFailures++
-		rp.lastFailureTime = time.Now()
-
-		if rp.consecutiveFailures >= rp.failureThreshold && !rp.circuitOpen {
-			logger.Warn("Opening circuit breaker due to consecutive failures",
-				zap.Int("failure_count", rp.consecutiveFailures),
-				zap.Int("threshold", rp.failureThreshold),
-			)
-			rp.circuitOpen = true
+		// Count the failure on the shared circuit breaker, which opens it for every worker at the threshold
+		breaker, cbErr := rp.circuitBreaker.RecordFailure(ctx, err)
+		if cbErr != nil {
+			logger.Error("Failed to record delegation server failure", zap.Error(cbErr))
 		}
 
-		// Store task for later processing
-		rp.pendingTasks = append(rp.pendingTasks, task)
-		rp.mu.Unlock()
+		retryCount := int32(0)
+		if breaker != nil {
+			retryCount = breaker.ConsecutiveFailures
+		}
 
 		// Log failed redemption event
 		metadataBytes, _ := json.Marshal(map[string]interface{}{
 			"error":       err.Error(),
-			"retry_count": rp.consecutiveFailures,
+			"retry_count": retryCount,
 		})
 
 		_, dbErr := rp.dbQueries.CreateFailedRedemptionEvent(ctx, db.CreateFailedRedemptionEventParams{
@@ -191,16 +376,13 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			)
 		}
 
-		return fmt.Errorf("delegation server unavailable: %w", err)
+		return false, fmt.Errorf("%w: %w", errDelegationServerUnavailable, err)
 	}
 
 	// Reset consecutive failures counter since server is available
-	rp.mu.Lock()
-	if rp.consecutiveFailures > 0 {
-		rp.consecutiveFailures = 0
-		logger.Info("Reset consecutive failures counter, delegation server is available")
+	if err := rp.circuitBreaker.RecordSuccess(ctx); err != nil {
+		logger.Error("Failed to reset delegation server failure counter", zap.Error(err))
 	}
-	rp.mu.Unlock()
 
 	// Get delegation data from database
 	delegationData, err := rp.dbQueries.GetDelegationData(ctx, task.DelegationID)
@@ -209,7 +391,7 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			zap.Error(err),
 			zap.String("delegation_id", task.DelegationID.String()),
 		)
-		return fmt.Errorf("failed to get delegation data: %w", err)
+		return false, fmt.Errorf("failed to get delegation data: %w", err)
 	}
 
 	subscription, err := rp.dbQueries.GetSubscription(ctx, task.SubscriptionID)
@@ -227,7 +409,7 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			zap.Error(err),
 			zap.String("product_id", task.ProductID.String()),
 		)
-		return fmt.Errorf("failed to get product details: %w", err)
+		return false, fmt.Errorf("failed to get product details: %w", err)
 	}
 
 	// get the merchant's wallet details
@@ -240,7 +422,7 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			zap.Error(err),
 			zap.String("delegation_id", task.DelegationID.String()),
 		)
-		return fmt.Errorf("failed to get merchant wallet details: %w", err)
+		return false, fmt.Errorf("failed to get merchant wallet details: %w", err)
 	}
 
 	// get the product token details
@@ -250,7 +432,7 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			zap.Error(err),
 			zap.String("product_token_id", task.ProductTokenID.String()),
 		)
-		return fmt.Errorf("failed to get product token details: %w", err)
+		return false, fmt.Errorf("failed to get product token details: %w", err)
 	}
 
 	// get the token details
@@ -260,7 +442,7 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			zap.Error(err),
 			zap.String("token_id", productToken.TokenID.String()),
 		)
-		return fmt.Errorf("failed to get token details: %w", err)
+		return false, fmt.Errorf("failed to get token details: %w", err)
 	}
 
 	// get the network details
@@ -270,7 +452,7 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			zap.Error(err),
 			zap.String("network_id", token.NetworkID.String()),
 		)
-		return fmt.Errorf("failed to get network details: %w", err)
+		return false, fmt.Errorf("failed to get network details: %w", err)
 	}
 
 	executionObject := dsClient.ExecutionObject{
@@ -297,7 +479,17 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			zap.Error(err),
 			zap.String("delegation_id", task.DelegationID.String()),
 		)
-		return fmt.Errorf("failed to marshal delegation data: %w", err)
+		return false, fmt.Errorf("failed to marshal delegation data: %w", err)
+	}
+
+	gasEstimate := rp.gasEstimates.Estimate(ctx, network.ID, delegationJSON, executionObject)
+
+	// Record that the redemption is in flight, so the task is not redeemed again if this worker stops
+	if _, err := rp.dbQueries.MarkRedemptionTaskRedeeming(ctx, db.MarkRedemptionTaskRedeemingParams{
+		ID:       task.ID,
+		LockedBy: rp.workerID,
+	}); err != nil {
+		return false, fmt.Errorf("failed to mark redemption task as redeeming: %w", err)
 	}
 
 	// Call delegation service to redeem delegation
@@ -333,7 +525,13 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			)
 		}
 
-		return fmt.Errorf("failed to redeem delegation: %w", err)
+		// Only an error the delegation server reported for a redemption it did not send is safe to retry.
+		// Anything else, such as a timeout waiting for the server, may come after the transaction went out.
+		var redemptionErr *dsClient.RedemptionError
+		if !errors.As(err, &redemptionErr) || redemptionErr.OutcomeUnknown() {
+			return false, fmt.Errorf("failed to redeem delegation: %v: %w", err, errRedemptionOutcomeUnknown)
+		}
+		return false, fmt.Errorf("failed to redeem delegation: %w", err)
 	}
 
 	// Log success
@@ -342,13 +540,25 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 		zap.String("tx_hash", txHash),
 	)
 
+	// Settle the task before the bookkeeping, which must not cause the delegation to be redeemed again
+	if _, err := rp.dbQueries.CompleteRedemptionTask(ctx, db.CompleteRedemptionTaskParams{
+		TransactionHash: helpers.StringToNullableText(txHash),
+		ID:              task.ID,
+		LockedBy:        rp.workerID,
+	}); err != nil {
+		logger.Error("Failed to mark redemption task as succeeded",
+			zap.Error(err),
+			zap.String("task_id", task.ID.String()),
+			zap.String("tx_hash", txHash),
+		)
+	}
+
 	// Record successful redemption in database
-	metadataBytes, _ := json.Marshal(task.Metadata)
 	event, err := rp.dbQueries.CreateRedemptionEvent(ctx, db.CreateRedemptionEventParams{
 		SubscriptionID:  task.SubscriptionID,
 		TransactionHash: helpers.StringToNullableText(txHash),
 		AmountInCents:   task.AmountInCents,
-		Metadata:        metadataBytes,
+		Metadata:        task.Metadata,
 	})
 
 	if err != nil {
@@ -357,11 +567,11 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			zap.String("subscription_id", task.SubscriptionID.String()),
 			zap.String("tx_hash", txHash),
 		)
-		return fmt.Errorf("failed to record redemption event: %w", err)
+		return true, fmt.Errorf("failed to record redemption event: %w", err)
 	}
 
 	// Create payment record for this successful redemption
-	err = rp.createPaymentFromRedemption(ctx, event, subscription, product, token, network, txHash)
+	err = rp.createPaymentFromRedemption(ctx, event, subscription, product, token, network, txHash, gasEstimate)
 	if err != nil {
 		logger.Error("Failed to create payment record from redemption",
 			zap.Error(err),
@@ -383,14 +593,14 @@ func (rp *RedemptionProcessor) processRedemption(task business.RedemptionTask) e
 			zap.Error(err),
 			zap.String("subscription_id", task.SubscriptionID.String()),
 		)
-		return fmt.Errorf("failed to update subscription next redemption date: %w", err)
+		return true, fmt.Errorf("failed to update subscription next redemption date: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
-// monitorDelegationServerHealth periodically checks if the delegation server is available
-// and resets the circuit breaker if it becomes available again
+// monitorDelegationServerHealth periodically checks if the delegation server is available while the shared
+// circuit breaker is open, and closes it once the server is back. Only one processor probes at a time.
 func (rp *RedemptionProcessor) monitorDelegationServerHealth() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
@@ -400,55 +610,45 @@ func (rp *RedemptionProcessor) monitorDelegationServerHealth() {
 		case <-rp.ctx.Done():
 			return
 		case <-ticker.C:
-			// Only check health if circuit breaker is open
-			rp.mu.Lock()
-			if !rp.circuitOpen {
-				rp.mu.Unlock()
-				continue
-			}
-
-			// Check if we need to attempt reset based on timeout
-			if time.Since(rp.lastFailureTime) < rp.resetTimeout {
-				rp.mu.Unlock()
-				continue
-			}
+			ctx, cancel := context.WithTimeout(rp.ctx, 10*time.Second)
+			rp.probeDelegationServer(ctx)
+			cancel()
+		}
+	}
+}
 
-			rp.mu.Unlock()
+// probeDelegationServer checks the delegation server if the circuit breaker is open and its reset timeout has passed
+func (rp *RedemptionProcessor) probeDelegationServer(ctx context.Context) {
+	// Only check health if circuit breaker is open
+	breaker, err := rp.circuitBreaker.State(ctx)
+	if err != nil || breaker.State == CircuitBreakerStateClosed {
+		return
+	}
 
-			// Check if server is available
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			err := rp.delegationClient.HealthCheck(ctx)
-			cancel()
+	allowed, err := rp.circuitBreaker.Allow(ctx)
+	if err != nil {
+		logger.Error("Failed to check delegation server circuit breaker", zap.Error(err))
+		return
+	}
+	if !allowed {
+		return
+	}
 
-			if err == nil {
-				// Server is available, reset circuit breaker
-				rp.mu.Lock()
-				if rp.circuitOpen {
-					logger.Info("Delegation server is available, resetting circuit breaker")
-					rp.circuitOpen = false
-					rp.consecutiveFailures = 0
-
-					// Process any pending tasks
-					pendingTasks := rp.pendingTasks
-					rp.pendingTasks = make([]business.RedemptionTask, 0)
-					rp.mu.Unlock()
-
-					// Queue pending tasks
-					for _, task := range pendingTasks {
-						logger.Info("Requeuing pending task after circuit breaker reset",
-							zap.String("subscription_id", task.SubscriptionID.String()),
-						)
-						_ = rp.QueueRedemption(task)
-					}
-				} else {
-					rp.mu.Unlock()
-				}
-			}
+	if err := rp.delegationClient.HealthCheck(ctx); err != nil {
+		if _, cbErr := rp.circuitBreaker.RecordFailure(ctx, err); cbErr != nil {
+			logger.Error("Failed to record delegation server failure", zap.Error(cbErr))
 		}
+		return
+	}
+
+	// Server is available, reset circuit breaker; deferred tasks are picked up as they become visible
+	if err := rp.circuitBreaker.RecordSuccess(ctx); err != nil {
+		logger.Error("Failed to reset delegation server circuit breaker", zap.Error(err))
 	}
 }
 
-// createPaymentFromRedemption creates a payment record from a successful subscription redemption
+// createPaymentFromRedemption creates a payment record from a successful subscription redemption, with the gas
+// estimate made before the redemption was sent
 func (rp *RedemptionProcessor) createPaymentFromRedemption(
 	ctx context.Context,
 	event db.SubscriptionEvent,
@@ -457,6 +657,7 @@ func (rp *RedemptionProcessor) createPaymentFromRedemption(
 	token db.Token,
 	network db.Network,
 	txHash string,
+	gasEstimate *responses.EstimateGasFeeResult,
 ) error {
 	// Get the customer associated with this subscription
 	customer, err := rp.dbQueries.GetCustomer(ctx, subscription.CustomerID)
@@ -489,6 +690,7 @@ func (rp *RedemptionProcessor) createPaymentFromRedemption(
 	if err != nil {
 		return fmt.Errorf("failed to create payment from subscription event: %w", err)
 	}
+	rp.gasEstimates.Record(ctx, payment, network.ID, gasEstimate)
 
 	logger.Info("Payment created successfully from redemption",
 		zap.String("payment_id", payment.ID.String()),
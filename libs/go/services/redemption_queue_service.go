@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Redemption task statuses stored on redemption_tasks
+const (
+	RedemptionTaskStatusPending      = "pending"
+	RedemptionTaskStatusProcessing   = "processing"
+	RedemptionTaskStatusSucceeded    = "succeeded"
+	RedemptionTaskStatusDeadLettered = "dead_lettered"
+)
+
+var redemptionTaskStatuses = []string{
+	RedemptionTaskStatusPending,
+	RedemptionTaskStatusProcessing,
+	RedemptionTaskStatusSucceeded,
+	RedemptionTaskStatusDeadLettered,
+}
+
+var (
+	// ErrInvalidRedemptionTaskStatus is returned when tasks are filtered by an unknown status
+	ErrInvalidRedemptionTaskStatus = errors.New("invalid redemption task status")
+	// ErrRedemptionTaskNotRequeueable is returned when a task that is being processed or has succeeded is requeued
+	ErrRedemptionTaskNotRequeueable = errors.New("only pending or dead-lettered redemption tasks can be requeued")
+)
+
+// RedemptionQueueService manages the durable queue of redemption tasks worked by the RedemptionProcessor
+type RedemptionQueueService struct {
+	queries        db.Querier
+	circuitBreaker *CircuitBreaker
+	maxAttempts    int32
+}
+
+// NewRedemptionQueueService creates a redemption queue service
+func NewRedemptionQueueService(queries db.Querier) *RedemptionQueueService {
+	config := DefaultRedemptionQueueConfig()
+	return &RedemptionQueueService{
+		queries:        queries,
+		circuitBreaker: NewCircuitBreaker(queries, DelegationServerCircuitBreaker, config.FailureThreshold, config.ResetTimeout),
+		maxAttempts:    config.MaxAttempts,
+	}
+}
+
+// Enqueue stores a redemption task so that it survives restarts until a worker has processed it.
+// A task with an idempotency key that is already queued is returned instead of being queued again.
+func (s *RedemptionQueueService) Enqueue(ctx context.Context, task business.RedemptionTask) (*db.RedemptionTask, error) {
+	metadata, err := json.Marshal(task.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal redemption task metadata: %w", err)
+	}
+
+	var idempotencyKey pgtype.Text
+	if task.IdempotencyKey != "" {
+		idempotencyKey = helpers.StringToNullableText(task.IdempotencyKey)
+	}
+
+	queued, err := s.queries.EnqueueRedemptionTask(ctx, db.EnqueueRedemptionTaskParams{
+		SubscriptionID: task.SubscriptionID,
+		DelegationID:   task.DelegationID,
+		ProductID:      task.ProductID,
+		ProductTokenID: task.ProductTokenID,
+		AmountInCents:  task.AmountInCents,
+		Metadata:       metadata,
+		IdempotencyKey: idempotencyKey,
+		Priority:       task.Priority,
+		MaxAttempts:    s.maxAttempts,
+		VisibleAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue redemption task: %w", err)
+	}
+	return &queued, nil
+}
+
+// GetTask returns a queued redemption task
+func (s *RedemptionQueueService) GetTask(ctx context.Context, id uuid.UUID) (*db.RedemptionTask, error) {
+	task, err := s.queries.GetRedemptionTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListTasks lists redemption tasks newest first, optionally filtered by status, with the total count
+func (s *RedemptionQueueService) ListTasks(ctx context.Context, status string, limit, offset int32) ([]db.RedemptionTask, int64, error) {
+	var statusFilter pgtype.Text
+	if status != "" {
+		if !slices.Contains(redemptionTaskStatuses, status) {
+			return nil, 0, fmt.Errorf("%w: %s", ErrInvalidRedemptionTaskStatus, status)
+		}
+		statusFilter = helpers.StringToNullableText(status)
+	}
+
+	tasks, err := s.queries.ListRedemptionTasks(ctx, db.ListRedemptionTasksParams{
+		Status: statusFilter,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list redemption tasks: %w", err)
+	}
+
+	total, err := s.queries.CountRedemptionTasks(ctx, statusFilter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count redemption tasks: %w", err)
+	}
+	return tasks, total, nil
+}
+
+// RequeueTask makes a pending or dead-lettered task claimable straight away with a fresh set of attempts,
+// optionally at a new priority. A dead-lettered task whose redemption outcome was unknown is redeemed again,
+// so its transaction should be checked on chain first.
+func (s *RedemptionQueueService) RequeueTask(ctx context.Context, id uuid.UUID, priority *int32) (*db.RedemptionTask, error) {
+	var priorityParam pgtype.Int4
+	if priority != nil {
+		priorityParam = pgtype.Int4{Int32: *priority, Valid: true}
+	}
+
+	task, err := s.queries.RequeueRedemptionTask(ctx, db.RequeueRedemptionTaskParams{
+		Priority: priorityParam,
+		ID:       id,
+	})
+	if err == nil {
+		return &task, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to requeue redemption task: %w", err)
+	}
+
+	// Tell a missing task apart from one in a status that cannot be requeued
+	if _, getErr := s.queries.GetRedemptionTask(ctx, id); getErr != nil {
+		return nil, getErr
+	}
+	return nil, ErrRedemptionTaskNotRequeueable
+}
+
+// GetQueueStats counts the tasks in each status and reports the delegation server circuit breaker
+func (s *RedemptionQueueService) GetQueueStats(ctx context.Context) (*business.RedemptionQueueStats, error) {
+	rows, err := s.queries.GetRedemptionTaskStats(ctx, pgtype.Timestamptz{Time: time.Now(), Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get redemption queue stats: %w", err)
+	}
+
+	breaker, err := s.circuitBreaker.State(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &business.RedemptionQueueStats{
+		Statuses:            make([]business.RedemptionQueueStatusCount, 0, len(rows)),
+		CircuitState:        breaker.State,
+		ConsecutiveFailures: breaker.ConsecutiveFailures,
+	}
+	if breaker.StateChangedAt.Valid {
+		changedAt := breaker.StateChangedAt.Time
+		stats.CircuitChangedAt = &changedAt
+	}
+	for _, row := range rows {
+		count := business.RedemptionQueueStatusCount{
+			Status:     row.Status,
+			TaskCount:  row.TaskCount,
+			ReadyCount: row.ReadyCount,
+		}
+		if row.OldestCreatedAt.Valid {
+			oldest := row.OldestCreatedAt.Time
+			count.OldestAt = &oldest
+		}
+		stats.Statuses = append(stats.Statuses, count)
+	}
+	return stats, nil
+}
@@ -0,0 +1,243 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRedemptionQueueService_Enqueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewRedemptionQueueService(mockQuerier)
+	ctx := context.Background()
+
+	task := business.RedemptionTask{
+		SubscriptionID: uuid.New(),
+		DelegationID:   uuid.New(),
+		ProductID:      uuid.New(),
+		ProductTokenID: uuid.New(),
+		AmountInCents:  1999,
+		Metadata:       map[string]interface{}{"source": "test"},
+		IdempotencyKey: "redeem:period-1",
+		Priority:       10,
+	}
+
+	t.Run("stores the task with its idempotency key and priority", func(t *testing.T) {
+		mockQuerier.EXPECT().EnqueueRedemptionTask(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.EnqueueRedemptionTaskParams) (db.RedemptionTask, error) {
+				assert.Equal(t, task.SubscriptionID, arg.SubscriptionID)
+				assert.Equal(t, pgtype.Text{String: "redeem:period-1", Valid: true}, arg.IdempotencyKey)
+				assert.Equal(t, int32(10), arg.Priority)
+				assert.Equal(t, services.DefaultRedemptionQueueConfig().MaxAttempts, arg.MaxAttempts)
+				assert.True(t, arg.VisibleAt.Valid)
+
+				var metadata map[string]interface{}
+				require.NoError(t, json.Unmarshal(arg.Metadata, &metadata))
+				assert.Equal(t, "test", metadata["source"])
+
+				return db.RedemptionTask{ID: uuid.New(), Status: services.RedemptionTaskStatusPending}, nil
+			})
+
+		queued, err := service.Enqueue(ctx, task)
+		require.NoError(t, err)
+		assert.Equal(t, services.RedemptionTaskStatusPending, queued.Status)
+	})
+
+	t.Run("leaves the idempotency key empty when none is given", func(t *testing.T) {
+		withoutKey := task
+		withoutKey.IdempotencyKey = ""
+
+		mockQuerier.EXPECT().EnqueueRedemptionTask(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.EnqueueRedemptionTaskParams) (db.RedemptionTask, error) {
+				assert.False(t, arg.IdempotencyKey.Valid)
+				return db.RedemptionTask{ID: uuid.New()}, nil
+			})
+
+		_, err := service.Enqueue(ctx, withoutKey)
+		require.NoError(t, err)
+	})
+
+	t.Run("returns database errors", func(t *testing.T) {
+		mockQuerier.EXPECT().EnqueueRedemptionTask(ctx, gomock.Any()).Return(db.RedemptionTask{}, errors.New("connection refused"))
+
+		_, err := service.Enqueue(ctx, task)
+		assert.Error(t, err)
+	})
+}
+
+func TestRedemptionQueueService_ListTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewRedemptionQueueService(mockQuerier)
+	ctx := context.Background()
+
+	t.Run("filters by status", func(t *testing.T) {
+		status := pgtype.Text{String: services.RedemptionTaskStatusDeadLettered, Valid: true}
+		mockQuerier.EXPECT().ListRedemptionTasks(ctx, db.ListRedemptionTasksParams{Status: status, Limit: 20, Offset: 40}).
+			Return([]db.RedemptionTask{{ID: uuid.New()}}, nil)
+		mockQuerier.EXPECT().CountRedemptionTasks(ctx, status).Return(int64(41), nil)
+
+		tasks, total, err := service.ListTasks(ctx, services.RedemptionTaskStatusDeadLettered, 20, 40)
+		require.NoError(t, err)
+		assert.Len(t, tasks, 1)
+		assert.Equal(t, int64(41), total)
+	})
+
+	t.Run("lists every status without a filter", func(t *testing.T) {
+		mockQuerier.EXPECT().ListRedemptionTasks(ctx, db.ListRedemptionTasksParams{Limit: 20}).Return([]db.RedemptionTask{}, nil)
+		mockQuerier.EXPECT().CountRedemptionTasks(ctx, pgtype.Text{}).Return(int64(0), nil)
+
+		_, _, err := service.ListTasks(ctx, "", 20, 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects unknown statuses", func(t *testing.T) {
+		_, _, err := service.ListTasks(ctx, "lost", 20, 0)
+		assert.ErrorIs(t, err, services.ErrInvalidRedemptionTaskStatus)
+	})
+}
+
+func TestRedemptionQueueService_RequeueTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewRedemptionQueueService(mockQuerier)
+	ctx := context.Background()
+	taskID := uuid.New()
+
+	t.Run("requeues at a new priority", func(t *testing.T) {
+		priority := int32(100)
+		mockQuerier.EXPECT().RequeueRedemptionTask(ctx, db.RequeueRedemptionTaskParams{
+			Priority: pgtype.Int4{Int32: 100, Valid: true},
+			ID:       taskID,
+		}).Return(db.RedemptionTask{ID: taskID, Status: services.RedemptionTaskStatusPending, Priority: 100}, nil)
+
+		task, err := service.RequeueTask(ctx, taskID, &priority)
+		require.NoError(t, err)
+		assert.Equal(t, int32(100), task.Priority)
+	})
+
+	t.Run("refuses tasks that are processing or succeeded", func(t *testing.T) {
+		mockQuerier.EXPECT().RequeueRedemptionTask(ctx, db.RequeueRedemptionTaskParams{ID: taskID}).Return(db.RedemptionTask{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().GetRedemptionTask(ctx, taskID).Return(db.RedemptionTask{ID: taskID, Status: services.RedemptionTaskStatusSucceeded}, nil)
+
+		_, err := service.RequeueTask(ctx, taskID, nil)
+		assert.ErrorIs(t, err, services.ErrRedemptionTaskNotRequeueable)
+	})
+
+	t.Run("reports missing tasks as not found", func(t *testing.T) {
+		mockQuerier.EXPECT().RequeueRedemptionTask(ctx, db.RequeueRedemptionTaskParams{ID: taskID}).Return(db.RedemptionTask{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().GetRedemptionTask(ctx, taskID).Return(db.RedemptionTask{}, pgx.ErrNoRows)
+
+		_, err := service.RequeueTask(ctx, taskID, nil)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+}
+
+func TestRedemptionQueueService_GetQueueStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewRedemptionQueueService(mockQuerier)
+	ctx := context.Background()
+	openedAt := time.Now().Add(-time.Minute)
+
+	mockQuerier.EXPECT().GetRedemptionTaskStats(ctx, gomock.Any()).Return([]db.GetRedemptionTaskStatsRow{
+		{Status: services.RedemptionTaskStatusPending, TaskCount: 7, ReadyCount: 4, OldestCreatedAt: pgtype.Timestamptz{Time: openedAt, Valid: true}},
+		{Status: services.RedemptionTaskStatusDeadLettered, TaskCount: 1},
+	}, nil)
+	mockQuerier.EXPECT().GetCircuitBreaker(ctx, services.DelegationServerCircuitBreaker).Return(db.CircuitBreaker{
+		Name:                services.DelegationServerCircuitBreaker,
+		State:               services.CircuitBreakerStateOpen,
+		ConsecutiveFailures: 3,
+		StateChangedAt:      pgtype.Timestamptz{Time: openedAt, Valid: true},
+	}, nil)
+
+	stats, err := service.GetQueueStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats.Statuses, 2)
+	assert.Equal(t, int64(4), stats.Statuses[0].ReadyCount)
+	require.NotNil(t, stats.Statuses[0].OldestAt)
+	assert.Nil(t, stats.Statuses[1].OldestAt)
+	assert.Equal(t, services.CircuitBreakerStateOpen, stats.CircuitState)
+	assert.Equal(t, int32(3), stats.ConsecutiveFailures)
+	require.NotNil(t, stats.CircuitChangedAt)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	ctx := context.Background()
+	name := services.DelegationServerCircuitBreaker
+	breaker := services.NewCircuitBreaker(mockQuerier, name, 3, 5*time.Minute)
+
+	t.Run("allows calls before any failure was recorded", func(t *testing.T) {
+		mockQuerier.EXPECT().GetCircuitBreaker(ctx, name).Return(db.CircuitBreaker{}, pgx.ErrNoRows)
+
+		allowed, err := breaker.Allow(ctx)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("blocks calls while open", func(t *testing.T) {
+		mockQuerier.EXPECT().GetCircuitBreaker(ctx, name).Return(db.CircuitBreaker{Name: name, State: services.CircuitBreakerStateOpen}, nil)
+		mockQuerier.EXPECT().HalfOpenCircuitBreaker(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.HalfOpenCircuitBreakerParams) (db.CircuitBreaker, error) {
+				assert.WithinDuration(t, time.Now().Add(-5*time.Minute), arg.ResetBefore.Time, time.Second)
+				return db.CircuitBreaker{}, pgx.ErrNoRows
+			})
+
+		allowed, err := breaker.Allow(ctx)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+	})
+
+	t.Run("lets one probe through after the reset timeout", func(t *testing.T) {
+		mockQuerier.EXPECT().GetCircuitBreaker(ctx, name).Return(db.CircuitBreaker{Name: name, State: services.CircuitBreakerStateOpen}, nil)
+		mockQuerier.EXPECT().HalfOpenCircuitBreaker(ctx, gomock.Any()).Return(db.CircuitBreaker{Name: name, State: services.CircuitBreakerStateHalfOpen}, nil)
+
+		allowed, err := breaker.Allow(ctx)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("records failures against the shared threshold", func(t *testing.T) {
+		mockQuerier.EXPECT().RecordCircuitBreakerFailure(ctx, db.RecordCircuitBreakerFailureParams{
+			Name:             name,
+			FailureThreshold: 3,
+			LastError:        pgtype.Text{String: "connection refused", Valid: true},
+		}).Return(db.CircuitBreaker{Name: name, State: services.CircuitBreakerStateClosed, ConsecutiveFailures: 1}, nil)
+
+		state, err := breaker.RecordFailure(ctx, errors.New("connection refused"))
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), state.ConsecutiveFailures)
+	})
+
+	t.Run("closes on success", func(t *testing.T) {
+		mockQuerier.EXPECT().RecordCircuitBreakerSuccess(ctx, name).Return(int64(1), nil)
+
+		require.NoError(t, breaker.RecordSuccess(ctx))
+	})
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var subscriptionRenewalStatuses = []string{
+	SubscriptionRenewalStatusClaimed,
+	SubscriptionRenewalStatusRedeeming,
+	SubscriptionRenewalStatusRedeemed,
+	SubscriptionRenewalStatusSucceeded,
+	SubscriptionRenewalStatusFailed,
+	SubscriptionRenewalStatusInterrupted,
+}
+
+var (
+	// ErrInvalidSubscriptionRenewalStatus is returned when renewals are filtered by an unknown status
+	ErrInvalidSubscriptionRenewalStatus = errors.New("invalid subscription renewal status")
+	// ErrSubscriptionRenewalNotInterrupted is returned when a renewal that is not parked for review is requeued or resolved
+	ErrSubscriptionRenewalNotInterrupted = errors.New("only interrupted subscription renewals can be requeued or resolved")
+	// ErrTransactionHashRequired is returned when an interrupted renewal is resolved without its transaction
+	ErrTransactionHashRequired = errors.New("transaction hash is required")
+)
+
+// RenewalReviewService lets admins review subscription renewals the renewal engine parked because it could
+// not tell whether the customer was charged
+type RenewalReviewService struct {
+	queries db.Querier
+}
+
+// NewRenewalReviewService creates a renewal review service
+func NewRenewalReviewService(queries db.Querier) *RenewalReviewService {
+	return &RenewalReviewService{queries: queries}
+}
+
+// GetRenewal gets a subscription renewal by ID
+func (s *RenewalReviewService) GetRenewal(ctx context.Context, id uuid.UUID) (*db.SubscriptionRenewal, error) {
+	renewal, err := s.queries.GetSubscriptionRenewal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &renewal, nil
+}
+
+// ListRenewals lists subscription renewals most recently updated first, optionally filtered by status,
+// with the total count
+func (s *RenewalReviewService) ListRenewals(ctx context.Context, status string, limit, offset int32) ([]db.SubscriptionRenewal, int64, error) {
+	var statusFilter pgtype.Text
+	if status != "" {
+		if !slices.Contains(subscriptionRenewalStatuses, status) {
+			return nil, 0, fmt.Errorf("%w: %s", ErrInvalidSubscriptionRenewalStatus, status)
+		}
+		statusFilter = helpers.StringToNullableText(status)
+	}
+
+	renewals, err := s.queries.ListSubscriptionRenewals(ctx, db.ListSubscriptionRenewalsParams{
+		Status: statusFilter,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list subscription renewals: %w", err)
+	}
+
+	total, err := s.queries.CountSubscriptionRenewals(ctx, statusFilter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count subscription renewals: %w", err)
+	}
+	return renewals, total, nil
+}
+
+// RequeueRenewal hands an interrupted renewal back to the renewal engine with a fresh set of attempts, which
+// renews the period again on its next run. Only requeue a renewal once its redemption is known not to have gone through on chain.
+func (s *RenewalReviewService) RequeueRenewal(ctx context.Context, id uuid.UUID) (*db.SubscriptionRenewal, error) {
+	renewal, err := s.queries.RequeueSubscriptionRenewal(ctx, db.RequeueSubscriptionRenewalParams{
+		ErrorMessage: helpers.StringToNullableText("requeued after review"),
+		ID:           id,
+	})
+	if err != nil {
+		return nil, s.notInterrupted(ctx, id, err)
+	}
+	return &renewal, nil
+}
+
+// ResolveRenewal records the transaction an interrupted renewal's redemption went through with. The renewal
+// engine records the payment on its next run without charging the customer again.
+func (s *RenewalReviewService) ResolveRenewal(ctx context.Context, id uuid.UUID, transactionHash string) (*db.SubscriptionRenewal, error) {
+	transactionHash = strings.TrimSpace(transactionHash)
+	if transactionHash == "" {
+		return nil, ErrTransactionHashRequired
+	}
+
+	renewal, err := s.queries.ResolveSubscriptionRenewal(ctx, db.ResolveSubscriptionRenewalParams{
+		TransactionHash: helpers.StringToNullableText(transactionHash),
+		ID:              id,
+	})
+	if err != nil {
+		return nil, s.notInterrupted(ctx, id, err)
+	}
+	return &renewal, nil
+}
+
+// notInterrupted explains why a requeue or resolve matched no interrupted renewal
+func (s *RenewalReviewService) notInterrupted(ctx context.Context, id uuid.UUID, err error) error {
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to update subscription renewal: %w", err)
+	}
+
+	// Tell a missing renewal apart from one that is not parked for review
+	if _, getErr := s.queries.GetSubscriptionRenewal(ctx, id); getErr != nil {
+		return getErr
+	}
+	return ErrSubscriptionRenewalNotInterrupted
+}
@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRenewalReviewService_ListRenewals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewRenewalReviewService(mockQuerier)
+	ctx := context.Background()
+
+	t.Run("filters by status", func(t *testing.T) {
+		status := pgtype.Text{String: services.SubscriptionRenewalStatusInterrupted, Valid: true}
+		mockQuerier.EXPECT().ListSubscriptionRenewals(ctx, db.ListSubscriptionRenewalsParams{
+			Status: status,
+			Limit:  10,
+			Offset: 20,
+		}).Return([]db.SubscriptionRenewal{{ID: uuid.New()}}, nil)
+		mockQuerier.EXPECT().CountSubscriptionRenewals(ctx, status).Return(int64(21), nil)
+
+		renewals, total, err := service.ListRenewals(ctx, services.SubscriptionRenewalStatusInterrupted, 10, 20)
+		require.NoError(t, err)
+		assert.Len(t, renewals, 1)
+		assert.Equal(t, int64(21), total)
+	})
+
+	t.Run("rejects an unknown status", func(t *testing.T) {
+		_, _, err := service.ListRenewals(ctx, "parked", 10, 0)
+		assert.ErrorIs(t, err, services.ErrInvalidSubscriptionRenewalStatus)
+	})
+}
+
+func TestRenewalReviewService_RequeueRenewal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewRenewalReviewService(mockQuerier)
+	ctx := context.Background()
+	renewalID := uuid.New()
+
+	t.Run("requeues an interrupted renewal", func(t *testing.T) {
+		mockQuerier.EXPECT().RequeueSubscriptionRenewal(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.RequeueSubscriptionRenewalParams) (db.SubscriptionRenewal, error) {
+				assert.Equal(t, renewalID, arg.ID)
+				return db.SubscriptionRenewal{ID: renewalID, Status: services.SubscriptionRenewalStatusFailed}, nil
+			})
+
+		renewal, err := service.RequeueRenewal(ctx, renewalID)
+		require.NoError(t, err)
+		assert.Equal(t, services.SubscriptionRenewalStatusFailed, renewal.Status)
+	})
+
+	t.Run("refuses a renewal that is not interrupted", func(t *testing.T) {
+		mockQuerier.EXPECT().RequeueSubscriptionRenewal(ctx, gomock.Any()).Return(db.SubscriptionRenewal{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().GetSubscriptionRenewal(ctx, renewalID).Return(db.SubscriptionRenewal{ID: renewalID}, nil)
+
+		_, err := service.RequeueRenewal(ctx, renewalID)
+		assert.ErrorIs(t, err, services.ErrSubscriptionRenewalNotInterrupted)
+	})
+
+	t.Run("reports a missing renewal", func(t *testing.T) {
+		mockQuerier.EXPECT().RequeueSubscriptionRenewal(ctx, gomock.Any()).Return(db.SubscriptionRenewal{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().GetSubscriptionRenewal(ctx, renewalID).Return(db.SubscriptionRenewal{}, pgx.ErrNoRows)
+
+		_, err := service.RequeueRenewal(ctx, renewalID)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+}
+
+func TestRenewalReviewService_ResolveRenewal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewRenewalReviewService(mockQuerier)
+	ctx := context.Background()
+	renewalID := uuid.New()
+
+	t.Run("records the transaction for the next run", func(t *testing.T) {
+		mockQuerier.EXPECT().ResolveSubscriptionRenewal(ctx, db.ResolveSubscriptionRenewalParams{
+			TransactionHash: pgtype.Text{String: "0xabc", Valid: true},
+			ID:              renewalID,
+		}).Return(db.SubscriptionRenewal{ID: renewalID, Status: services.SubscriptionRenewalStatusRedeemed}, nil)
+
+		renewal, err := service.ResolveRenewal(ctx, renewalID, " 0xabc ")
+		require.NoError(t, err)
+		assert.Equal(t, services.SubscriptionRenewalStatusRedeemed, renewal.Status)
+	})
+
+	t.Run("requires a transaction", func(t *testing.T) {
+		_, err := service.ResolveRenewal(ctx, renewalID, " ")
+		assert.ErrorIs(t, err, services.ErrTransactionHashRequired)
+	})
+
+	t.Run("refuses a renewal that is not interrupted", func(t *testing.T) {
+		mockQuerier.EXPECT().ResolveSubscriptionRenewal(ctx, gomock.Any()).Return(db.SubscriptionRenewal{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().GetSubscriptionRenewal(ctx, renewalID).Return(db.SubscriptionRenewal{ID: renewalID}, nil)
+
+		_, err := service.ResolveRenewal(ctx, renewalID, "0xabc")
+		assert.ErrorIs(t, err, services.ErrSubscriptionRenewalNotInterrupted)
+	})
+}
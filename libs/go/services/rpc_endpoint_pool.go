@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// infuraProvider names the endpoint built from the RPC API key and a network's RPC ID
+const infuraProvider = "infura"
+
+// RPCEndpointConfig is an RPC node a network can be reached through
+type RPCEndpointConfig struct {
+	// Provider groups endpoints that share a rate limit, such as every network on one API key
+	Provider string
+	URL      string
+}
+
+// RPCPoolConfig configures how networks are reached through several RPC endpoints
+type RPCPoolConfig struct {
+	// Endpoints lists extra endpoints by network RPC ID, in order of preference. They are tried after the
+	// Infura endpoint when an RPC API key is set.
+	Endpoints map[string][]RPCEndpointConfig
+	// RateLimits caps the requests per second sent to each provider, across all its networks
+	RateLimits map[string]float64
+	// DefaultRateLimit caps providers without their own limit; zero leaves them unlimited
+	DefaultRateLimit float64
+	// FailureThreshold is how many consecutive failures take an endpoint out of rotation
+	FailureThreshold int
+	// Cooldown is how long an endpoint stays out of rotation before it is tried again
+	Cooldown time.Duration
+	// HealthCheckTimeout bounds each health check request
+	HealthCheckTimeout time.Duration
+}
+
+// DefaultRPCPoolConfig returns the default RPC pool configuration: Infura only and no rate limits
+func DefaultRPCPoolConfig() RPCPoolConfig {
+	return RPCPoolConfig{
+		Endpoints:          map[string][]RPCEndpointConfig{},
+		RateLimits:         map[string]float64{},
+		FailureThreshold:   3,
+		Cooldown:           30 * time.Second,
+		HealthCheckTimeout: 5 * time.Second,
+	}
+}
+
+// RPCPoolConfigFromEnv reads extra endpoints from RPC_ENDPOINTS and provider rate limits from RPC_RATE_LIMITS
+// on top of the defaults
+func RPCPoolConfigFromEnv() (RPCPoolConfig, error) {
+	config := DefaultRPCPoolConfig()
+
+	endpoints, err := ParseRPCEndpoints(os.Getenv("RPC_ENDPOINTS"))
+	if err != nil {
+		return config, fmt.Errorf("invalid RPC_ENDPOINTS: %w", err)
+	}
+	config.Endpoints = endpoints
+
+	limits, err := ParseRPCRateLimits(os.Getenv("RPC_RATE_LIMITS"))
+	if err != nil {
+		return config, fmt.Errorf("invalid RPC_RATE_LIMITS: %w", err)
+	}
+	config.RateLimits = limits
+	return config, nil
+}
+
+// ParseRPCEndpoints parses a comma separated list of rpc_id=provider:url entries. A network's entries keep
+// their order, which is the order they are tried in.
+func ParseRPCEndpoints(value string) (map[string][]RPCEndpointConfig, error) {
+	endpoints := map[string][]RPCEndpointConfig{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		rpcID, endpoint, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(rpcID) == "" {
+			return nil, fmt.Errorf("expected rpc_id=provider:url, got %q", entry)
+		}
+		provider, rawURL, ok := strings.Cut(endpoint, ":")
+		if !ok || strings.TrimSpace(provider) == "" {
+			return nil, fmt.Errorf("expected provider:url for %s", rpcID)
+		}
+
+		parsed, err := url.Parse(strings.TrimSpace(rawURL))
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return nil, fmt.Errorf("invalid endpoint URL for %s", rpcID)
+		}
+
+		rpcID = strings.TrimSpace(rpcID)
+		endpoints[rpcID] = append(endpoints[rpcID], RPCEndpointConfig{
+			Provider: strings.TrimSpace(provider),
+			URL:      parsed.String(),
+		})
+	}
+	return endpoints, nil
+}
+
+// ParseRPCRateLimits parses a comma separated list of provider=requests_per_second pairs
+func ParseRPCRateLimits(value string) (map[string]float64, error) {
+	limits := map[string]float64{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		provider, rawLimit, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(provider) == "" {
+			return nil, fmt.Errorf("expected provider=requests_per_second, got %q", entry)
+		}
+		limit, err := strconv.ParseFloat(strings.TrimSpace(rawLimit), 64)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid rate limit for %s: %q", provider, rawLimit)
+		}
+		limits[strings.TrimSpace(provider)] = limit
+	}
+	return limits, nil
+}
+
+// RPCRateLimiters hands out one rate limiter per provider, so that every network reached through a
+// provider shares its limit
+type RPCRateLimiters struct {
+	limits       map[string]float64
+	defaultLimit float64
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewRPCRateLimiters creates the provider rate limiters for a pool configuration
+func NewRPCRateLimiters(config RPCPoolConfig) *RPCRateLimiters {
+	return &RPCRateLimiters{
+		limits:       config.RateLimits,
+		defaultLimit: config.DefaultRateLimit,
+		limiters:     make(map[string]*rate.Limiter),
+	}
+}
+
+// limiter returns a provider's rate limiter, or nil when it is unlimited
+func (r *RPCRateLimiters) limiter(provider string) *rate.Limiter {
+	if r == nil {
+		return nil
+	}
+
+	limit, ok := r.limits[provider]
+	if !ok {
+		limit = r.defaultLimit
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limiter, ok := r.limiters[provider]; ok {
+		return limiter
+	}
+	limiter := rate.NewLimiter(rate.Limit(limit), max(1, int(limit)))
+	r.limiters[provider] = limiter
+	return limiter
+}
+
+// rpcEndpoint is a client for one endpoint and its health
+type rpcEndpoint[C any] struct {
+	provider string
+	url      string
+	client   C
+	limiter  *rate.Limiter
+
+	mu             sync.Mutex
+	failures       int
+	unhealthyUntil time.Time
+	lastErr        string
+}
+
+// rpcPool sends each call to the first endpoint that is healthy and within its provider's rate limit, and
+// fails over to the next when an endpoint cannot answer
+type rpcPool[C any] struct {
+	network   string
+	endpoints []*rpcEndpoint[C]
+	// failover reports whether an error means the endpoint failed, rather than the node rejecting the request
+	failover func(error) bool
+	config   RPCPoolConfig
+	logger   *zap.Logger
+}
+
+// dialRPCPool connects to a network's endpoints. Endpoints that cannot be dialed are left out.
+func dialRPCPool[C any](network string, endpoints []RPCEndpointConfig, dial func(string) (C, error), failover func(error) bool, limiters *RPCRateLimiters, config RPCPoolConfig, logger *zap.Logger) (*rpcPool[C], error) {
+	pool := &rpcPool[C]{
+		network:  network,
+		failover: failover,
+		config:   config,
+		logger:   logger,
+	}
+
+	for i, endpoint := range endpoints {
+		client, err := dial(endpoint.URL)
+		if err != nil {
+			logger.Error("Failed to connect to RPC endpoint",
+				zap.String("network", network),
+				zap.String("provider", endpoint.Provider),
+				zap.Int("endpoint", i),
+				zap.String("error", redactEndpointURL(err, endpoint.URL)))
+			continue
+		}
+		pool.endpoints = append(pool.endpoints, &rpcEndpoint[C]{
+			provider: endpoint.Provider,
+			url:      endpoint.URL,
+			client:   client,
+			limiter:  limiters.limiter(endpoint.Provider),
+		})
+	}
+
+	if len(pool.endpoints) == 0 {
+		return nil, fmt.Errorf("no RPC endpoint for %s could be dialed", network)
+	}
+	return pool, nil
+}
+
+// do runs call against the network's endpoints until one answers. Healthy endpoints with rate limit headroom
+// are tried first, then throttled ones once their limiter allows, then those out of rotation.
+func (p *rpcPool[C]) do(ctx context.Context, call func(C) error) error {
+	now := time.Now()
+	var throttled, unhealthy []*rpcEndpoint[C]
+	var lastErr error
+
+	for _, endpoint := range p.endpoints {
+		if !endpoint.available(now) {
+			unhealthy = append(unhealthy, endpoint)
+			continue
+		}
+		if endpoint.limiter != nil && !endpoint.limiter.Allow() {
+			throttled = append(throttled, endpoint)
+			continue
+		}
+		done, err := p.try(ctx, endpoint, call)
+		if done {
+			return err
+		}
+		lastErr = err
+	}
+
+	for _, endpoint := range append(throttled, unhealthy...) {
+		if endpoint.limiter != nil {
+			if err := endpoint.limiter.Wait(ctx); err != nil {
+				if lastErr == nil {
+					lastErr = err
+				}
+				break
+			}
+		}
+		done, err := p.try(ctx, endpoint, call)
+		if done {
+			return err
+		}
+		lastErr = err
+	}
+
+	return fmt.Errorf("all RPC endpoints for %s failed: %w", p.network, lastErr)
+}
+
+// try runs call against one endpoint. It is done unless the endpoint failed and another should be tried.
+func (p *rpcPool[C]) try(ctx context.Context, endpoint *rpcEndpoint[C], call func(C) error) (bool, error) {
+	err := call(endpoint.client)
+	if err == nil || !p.failover(err) {
+		p.recordSuccess(endpoint)
+		return true, err
+	}
+	p.recordFailure(endpoint, err)
+	// A cancelled caller is not an endpoint failure worth failing over for
+	return ctx.Err() != nil, err
+}
+
+// checkHealth probes every endpoint, taking failing ones out of rotation and returning recovered ones
+func (p *rpcPool[C]) checkHealth(ctx context.Context, probe func(context.Context, C) error) {
+	for _, endpoint := range p.endpoints {
+		if endpoint.limiter != nil {
+			if err := endpoint.limiter.Wait(ctx); err != nil {
+				return
+			}
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, p.config.HealthCheckTimeout)
+		err := probe(probeCtx, endpoint.client)
+		cancel()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			p.recordFailure(endpoint, err)
+		} else {
+			p.recordSuccess(endpoint)
+		}
+	}
+}
+
+// status returns the health of each endpoint, in order of preference
+func (p *rpcPool[C]) status() []business.RPCEndpointStatus {
+	now := time.Now()
+	statuses := make([]business.RPCEndpointStatus, 0, len(p.endpoints))
+	for _, endpoint := range p.endpoints {
+		endpoint.mu.Lock()
+		statuses = append(statuses, business.RPCEndpointStatus{
+			Provider:            endpoint.provider,
+			Healthy:             endpoint.failures < p.config.FailureThreshold || !now.Before(endpoint.unhealthyUntil),
+			ConsecutiveFailures: endpoint.failures,
+			LastError:           endpoint.lastErr,
+		})
+		endpoint.mu.Unlock()
+	}
+	return statuses
+}
+
+// close closes every endpoint's client
+func (p *rpcPool[C]) close(closeClient func(C)) {
+	for _, endpoint := range p.endpoints {
+		closeClient(endpoint.client)
+	}
+}
+
+func (p *rpcPool[C]) recordSuccess(endpoint *rpcEndpoint[C]) {
+	endpoint.mu.Lock()
+	recovered := endpoint.failures >= p.config.FailureThreshold
+	endpoint.failures = 0
+	endpoint.unhealthyUntil = time.Time{}
+	endpoint.lastErr = ""
+	endpoint.mu.Unlock()
+
+	if recovered {
+		p.logger.Info("RPC endpoint back in rotation",
+			zap.String("network", p.network),
+			zap.String("provider", endpoint.provider))
+	}
+}
+
+func (p *rpcPool[C]) recordFailure(endpoint *rpcEndpoint[C], err error) {
+	endpoint.mu.Lock()
+	endpoint.failures++
+	endpoint.lastErr = redactEndpointURL(err, endpoint.url)
+	failures := endpoint.failures
+	if failures >= p.config.FailureThreshold {
+		endpoint.unhealthyUntil = time.Now().Add(p.config.Cooldown)
+	}
+	endpoint.mu.Unlock()
+
+	if failures == p.config.FailureThreshold {
+		p.logger.Warn("RPC endpoint taken out of rotation",
+			zap.String("network", p.network),
+			zap.String("provider", endpoint.provider),
+			zap.Int("consecutive_failures", failures),
+			zap.Duration("cooldown", p.config.Cooldown),
+			zap.String("error", endpoint.lastErr))
+	}
+}
+
+// available reports whether an endpoint is in rotation, or has sat out its cooldown and may be tried again
+func (e *rpcEndpoint[C]) available(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.unhealthyUntil.IsZero() || !now.Before(e.unhealthyUntil)
+}
+
+// redactEndpointURL keeps endpoint URLs, which usually carry an API key, out of logged errors
+func redactEndpointURL(err error, endpointURL string) string {
+	return strings.ReplaceAll(err.Error(), endpointURL, "<rpc endpoint>")
+}
+
+// poolCall runs a call that returns a value against a pool
+func poolCall[C, T any](ctx context.Context, pool *rpcPool[C], call func(C) (T, error)) (T, error) {
+	var result T
+	err := pool.do(ctx, func(client C) error {
+		var err error
+		result, err = call(client)
+		return err
+	})
+	return result, err
+}
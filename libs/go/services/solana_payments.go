@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dsClient "github.com/cyphera/cyphera-api/libs/go/client/delegation_server"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// SplDelegatePayments makes subscription payments on Solana, where customers approve the payment
+// delegate on their SPL token account instead of signing a delegation. Implemented by BlockchainService.
+type SplDelegatePayments interface {
+	SimulateSplDelegateTransfer(ctx context.Context, networkID uuid.UUID, transfer business.SplDelegateTransfer) (uint64, error)
+	TransferAsDelegate(ctx context.Context, networkID uuid.UUID, transfer business.SplDelegateTransfer) (string, error)
+	VerifySplApproval(ctx context.Context, networkID uuid.UUID, approval business.SplApproval) (*business.VerifiedSplApproval, error)
+	GetTransactionReceipt(ctx context.Context, networkID uuid.UUID, txHash string) (*business.MinedTransaction, error)
+}
+
+// SplApprovalReader reads the delegate approval on an SPL token account. Implemented by BlockchainService.
+type SplApprovalReader interface {
+	GetSplTokenAccount(ctx context.Context, networkID uuid.UUID, address string) (*business.SplTokenAccount, error)
+}
+
+// WithSplPayments creates a new subscription service instance that charges Solana subscriptions through payments
+func (s *SubscriptionService) WithSplPayments(payments SplDelegatePayments) *SubscriptionService {
+	clone := s.WithRenewalConfig(s.renewalConfig)
+	clone.splPayments = payments
+	return clone
+}
+
+// newSplDelegateTransfer builds the transfer that charges a Solana subscription. The delegation's
+// authority is the customer's token account and its delegator the wallet that owns it.
+func newSplDelegateTransfer(delegator, authority string, execution dsClient.ExecutionObject) *business.SplDelegateTransfer {
+	return &business.SplDelegateTransfer{
+		SourceTokenAccount: authority,
+		SourceOwner:        delegator,
+		Mint:               execution.TokenContractAddress,
+		Decimals:           uint8(execution.TokenDecimals),
+		Amount:             uint64(execution.TokenAmount),
+		DestinationOwner:   execution.MerchantAddress,
+	}
+}
+
+// verifySplApproval checks on-chain that a new Solana subscription's approval was signed by the subscriber
+// for this product token and covers its price, and that neither it nor its token account already backs another subscription
+func (s *SubscriptionService) verifySplApproval(ctx context.Context, createParams params.CreateSubscriptionWithDelegationParams) (*business.VerifiedSplApproval, error) {
+	delegation := createParams.DelegationData
+	if delegation.Delegator != createParams.SubscriberAddress {
+		return nil, fmt.Errorf("%w: approval owner %s is not the subscriber %s", ErrSplApprovalUnverified, delegation.Delegator, createParams.SubscriberAddress)
+	}
+
+	if _, err := s.queries.GetSplApprovalBySignature(ctx, delegation.Signature); err == nil {
+		return nil, fmt.Errorf("%w: approval %s already backs a subscription", ErrSplApprovalUnverified, delegation.Signature)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to look up approval: %w", err)
+	}
+	if err := checkSplTokenAccountUnbound(ctx, s.queries, delegation.Authority, pgtype.UUID{}); err != nil {
+		return nil, err
+	}
+
+	approval, err := s.splPayments.VerifySplApproval(ctx, createParams.Network.ID, business.SplApproval{
+		Signature:    delegation.Signature,
+		TokenAccount: delegation.Authority,
+		Owner:        delegation.Delegator,
+		Memo:         SplApprovalMemo(createParams.ProductTokenID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if approval.Mint != "" && approval.Mint != createParams.Token.ContractAddress {
+		return nil, fmt.Errorf("%w: approval is for mint %s, not %s", ErrSplApprovalUnverified, approval.Mint, createParams.Token.ContractAddress)
+	}
+	if approval.Amount < uint64(createParams.TokenAmount) {
+		return nil, fmt.Errorf("%w: approval delegates %d of %d", ErrSplApprovalMissing, approval.Amount, createParams.TokenAmount)
+	}
+	return approval, nil
+}
+
+// bindSplApproval records that a verified approval backs a new subscription's delegation, so it cannot back
+// another and renewals can check they charge the approval made for them
+func (s *SubscriptionService) bindSplApproval(ctx context.Context, tx pgx.Tx, createParams params.CreateSubscriptionWithDelegationParams, delegationID uuid.UUID, approval business.VerifiedSplApproval) error {
+	var qtx db.Querier = s.queries
+	if tx != nil {
+		qtx = db.New(tx)
+	}
+
+	_, err := qtx.CreateSplApproval(ctx, db.CreateSplApprovalParams{
+		Signature:      createParams.DelegationData.Signature,
+		DelegationID:   delegationID,
+		ProductTokenID: createParams.ProductTokenID,
+		TokenAccount:   createParams.DelegationData.Authority,
+		Owner:          createParams.DelegationData.Delegator,
+		Slot:           int64(approval.Slot),
+	})
+	return err
+}
+
+// checkSplTokenAccountUnbound checks that a token account does not already pay for a live subscription other
+// than subscriptionID. The account holds one delegate approval, so approving it again for another subscription
+// would replace the approval the existing subscription renews with.
+func checkSplTokenAccountUnbound(ctx context.Context, queries db.Querier, tokenAccount string, subscriptionID pgtype.UUID) error {
+	bound, err := queries.GetActiveSplApprovalByTokenAccount(ctx, db.GetActiveSplApprovalByTokenAccountParams{
+		TokenAccount:   tokenAccount,
+		SubscriptionID: subscriptionID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up token account approvals: %w", err)
+	}
+	return fmt.Errorf("%w: token account %s already pays for a subscription with approval %s; use another token account",
+		ErrSplApprovalUnverified, tokenAccount, bound.Signature)
+}
+
+// checkSplApprovalBinding checks that a transfer for a Solana subscription charges the token account of the
+// approval its customer made for the subscription's product token
+func (s *SubscriptionService) checkSplApprovalBinding(ctx context.Context, qtx db.Querier, subscription db.Subscription, transfer business.SplDelegateTransfer) error {
+	binding, err := qtx.GetSplApprovalByDelegationID(ctx, subscription.DelegationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: subscription %s has no verified approval", ErrSplApprovalMissing, subscription.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get approval: %w", err)
+	}
+
+	if binding.ProductTokenID != subscription.ProductTokenID ||
+		binding.TokenAccount != transfer.SourceTokenAccount || binding.Owner != transfer.SourceOwner {
+		return fmt.Errorf("%w: approval %s was not made for subscription %s", ErrSplApprovalMissing, binding.Signature, subscription.ID)
+	}
+	return nil
+}
+
+// isSplTransferFailure reports whether an SPL delegate transfer failed because of the customer's account
+// or approval, rather than an unreachable cluster
+func isSplTransferFailure(err error) bool {
+	return errors.Is(err, ErrSplApprovalMissing) || errors.Is(err, ErrSplTransferRejected)
+}
+
+// initialPaymentUnavailable returns why a new subscription's first payment cannot be taken on a network, or nil
+func (s *SubscriptionService) initialPaymentUnavailable(network db.Network) error {
+	if network.NetworkType == db.NetworkTypeSolana {
+		if s.splPayments == nil {
+			return fmt.Errorf("solana payments are not configured")
+		}
+		return nil
+	}
+	if s.delegationClient == nil {
+		return fmt.Errorf("delegation client is not configured")
+	}
+	return nil
+}
+
+// redeemInitialPayment takes a new subscription's first payment, through the delegation server or, on
+// Solana, as the SPL payment delegate. Check initialPaymentUnavailable first.
+func (s *SubscriptionService) redeemInitialPayment(ctx context.Context, network db.Network, delegation []byte, delegator, authority string, execution dsClient.ExecutionObject) (string, error) {
+	if network.NetworkType == db.NetworkTypeSolana {
+		return s.splPayments.TransferAsDelegate(ctx, network.ID, *newSplDelegateTransfer(delegator, authority, execution))
+	}
+	return s.delegationClient.RedeemDelegation(ctx, delegation, execution)
+}
+
+// redeemSplRenewal charges a prepared Solana renewal with a delegate transfer and records it
+func (s *SubscriptionService) redeemSplRenewal(ctx context.Context, qtx db.Querier, redemption *renewalRedemption, leaseOwner pgtype.Text) error {
+	if s.splPayments == nil {
+		return fmt.Errorf("solana payments are not configured")
+	}
+	networkID := redemption.productToken.NetworkID
+
+	// The payment delegate is shared by every merchant, so only the approval made for this subscription is charged
+	if err := s.checkSplApprovalBinding(ctx, qtx, redemption.subscription, *redemption.splTransfer); err != nil {
+		if isSplTransferFailure(err) {
+			s.handleFailedRenewalRedemption(ctx, qtx, redemption.subscription, err)
+		}
+		return fmt.Errorf("redemption failed: %w", err)
+	}
+
+	// Check the approval first so a transfer that would fail is never sent
+	if _, err := s.splPayments.SimulateSplDelegateTransfer(ctx, networkID, *redemption.splTransfer); err != nil {
+		if isSplTransferFailure(err) {
+			s.handleFailedRenewalRedemption(ctx, qtx, redemption.subscription, err)
+			return fmt.Errorf("redemption failed: %w", err)
+		}
+		s.logger.Warn("Could not simulate SPL renewal transfer, sending it without a dry run",
+			zap.String("subscription_id", redemption.subscription.ID.String()),
+			zap.Error(err))
+	}
+
+	// Record that the transfer is in flight; if this run stops before it returns, the renewal needs review
+	if _, err := qtx.MarkSubscriptionRenewalRedeeming(ctx, db.MarkSubscriptionRenewalRedeemingParams{
+		ID:         redemption.renewal.ID,
+		LeaseOwner: leaseOwner,
+	}); err != nil {
+		return fmt.Errorf("failed to start renewal redemption: %w", err)
+	}
+
+	signature, err := s.splPayments.TransferAsDelegate(ctx, networkID, *redemption.splTransfer)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTransferOutcomeUnknown):
+			return fmt.Errorf("transfer was sent but not confirmed: %v: %w", err, errRenewalOutcomeUnknown)
+		case isSplTransferFailure(err):
+			s.handleFailedRenewalRedemption(ctx, qtx, redemption.subscription, err)
+		}
+		return fmt.Errorf("redemption failed: %w", err)
+	}
+
+	return s.completeRenewalRedemption(ctx, qtx, redemption, leaseOwner, signature)
+}
+
+// resumeSplRenewalRedemption records a Solana renewal an interrupted run sent, once its transfer is confirmed
+func (s *SubscriptionService) resumeSplRenewalRedemption(ctx context.Context, qtx db.Querier, redemption *renewalRedemption) error {
+	signature := redemption.renewal.TransactionHash.String
+	logFields := []zap.Field{
+		zap.String("subscription_id", redemption.subscription.ID.String()),
+		zap.String("tx_hash", signature),
+	}
+
+	if s.splPayments != nil {
+		mined, err := s.splPayments.GetTransactionReceipt(ctx, redemption.productToken.NetworkID, signature)
+		switch {
+		case errors.Is(err, ErrTransactionNotFound):
+			// The renewal stays redeemed and resumes once its lease runs out
+			return fmt.Errorf("transfer %s is not confirmed yet", signature)
+		case err != nil:
+			s.logger.Warn("Could not check the status of a resumed transfer", append(logFields, zap.Error(err))...)
+		case mined.Status == 0:
+			err := fmt.Errorf("transfer %s failed on-chain: %w", signature, errRenewalReverted)
+			s.handleFailedRenewalRedemption(ctx, qtx, redemption.subscription, err)
+			return err
+		}
+	}
+
+	s.logger.Info("Resuming interrupted renewal from its recorded transfer", logFields...)
+	return s.recordRenewalRedemption(ctx, qtx, redemption, signature, !redemption.periodAdvanced)
+}
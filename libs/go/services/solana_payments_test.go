@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeSplPayments answers approval checks with a fixed approval and records the transfers it is asked to make
+type fakeSplPayments struct {
+	approval  *business.VerifiedSplApproval
+	verified  []business.SplApproval
+	transfers []business.SplDelegateTransfer
+}
+
+func (f *fakeSplPayments) SimulateSplDelegateTransfer(_ context.Context, _ uuid.UUID, _ business.SplDelegateTransfer) (uint64, error) {
+	return 5000, nil
+}
+
+func (f *fakeSplPayments) TransferAsDelegate(_ context.Context, _ uuid.UUID, transfer business.SplDelegateTransfer) (string, error) {
+	f.transfers = append(f.transfers, transfer)
+	return "transfer-signature", nil
+}
+
+func (f *fakeSplPayments) VerifySplApproval(_ context.Context, _ uuid.UUID, approval business.SplApproval) (*business.VerifiedSplApproval, error) {
+	f.verified = append(f.verified, approval)
+	return f.approval, nil
+}
+
+func (f *fakeSplPayments) GetTransactionReceipt(_ context.Context, _ uuid.UUID, _ string) (*business.MinedTransaction, error) {
+	return &business.MinedTransaction{Status: 1}, nil
+}
+
+func TestSubscriptionService_SolanaApprovalTokenAccount(t *testing.T) {
+	ctx := context.Background()
+	network := db.Network{ID: uuid.New(), Name: "solana", NetworkType: db.NetworkTypeSolana}
+	createParams := params.CreateSubscriptionWithDelegationParams{
+		Product:        db.Product{ID: uuid.New(), WorkspaceID: uuid.New()},
+		ProductToken:   db.GetProductTokenRow{ID: uuid.New(), NetworkType: string(db.NetworkTypeSolana)},
+		MerchantWallet: db.Wallet{WalletAddress: testSolanaOwner},
+		Token:          db.Token{ContractAddress: testSolanaMint, Decimals: 6},
+		Network:        network,
+		DelegationData: params.StoreDelegationDataParams{
+			Delegator: testSolanaOwner,
+			Authority: testSolanaTokenAccount,
+			Signature: testApprovalSignature,
+		},
+		SubscriberAddress: testSolanaOwner,
+		ProductTokenID:    uuid.New(),
+		TokenAmount:       1000000,
+	}
+
+	t.Run("token account already paying for a subscription", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		payments := &fakeSplPayments{approval: &business.VerifiedSplApproval{Mint: testSolanaMint, Amount: 1000000}}
+		service := services.NewSubscriptionService(mockQuerier, nil, nil, nil, nil).WithSplPayments(payments)
+
+		mockQuerier.EXPECT().GetSplApprovalBySignature(ctx, testApprovalSignature).Return(db.SplApproval{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().GetActiveSplApprovalByTokenAccount(ctx, db.GetActiveSplApprovalByTokenAccountParams{
+			TokenAccount:   testSolanaTokenAccount,
+			SubscriptionID: pgtype.UUID{},
+		}).Return(db.SplApproval{Signature: "earlier-approval", TokenAccount: testSolanaTokenAccount}, nil)
+		mockQuerier.EXPECT().CreateFailedSubscriptionAttempt(ctx, gomock.Any()).Return(db.FailedSubscriptionAttempt{}, nil)
+
+		_, err := service.CreateSubscriptionWithDelegation(ctx, nil, createParams)
+		assert.ErrorIs(t, err, services.ErrSplApprovalUnverified)
+
+		// The new approval replaced the one the existing subscription renews with, so nothing is charged
+		assert.Empty(t, payments.verified)
+		assert.Empty(t, payments.transfers)
+	})
+
+	t.Run("token account without a live subscription", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		payments := &fakeSplPayments{approval: &business.VerifiedSplApproval{Mint: testSolanaMint, Amount: 10}}
+		service := services.NewSubscriptionService(mockQuerier, nil, nil, nil, nil).WithSplPayments(payments)
+
+		mockQuerier.EXPECT().GetSplApprovalBySignature(ctx, testApprovalSignature).Return(db.SplApproval{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().GetActiveSplApprovalByTokenAccount(ctx, gomock.Any()).Return(db.SplApproval{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().CreateFailedSubscriptionAttempt(ctx, gomock.Any()).Return(db.FailedSubscriptionAttempt{}, nil)
+
+		// The account is free, so the approval is checked on-chain; this one delegates less than the price
+		_, err := service.CreateSubscriptionWithDelegation(ctx, nil, createParams)
+		assert.ErrorIs(t, err, services.ErrSplApprovalMissing)
+		assert.Len(t, payments.verified, 1)
+		assert.Empty(t, payments.transfers)
+	})
+}
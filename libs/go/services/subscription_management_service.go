@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,12 +17,16 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrInvalidSubscriptionState is returned when a change does not apply to a subscription in its current status
+var ErrInvalidSubscriptionState = errors.New("invalid subscription state")
+
 // SubscriptionManagementService handles all subscription lifecycle operations
 type SubscriptionManagementService struct {
 	db             db.Querier
 	calculator     IProrationCalculator
 	paymentService IPaymentService
 	emailService   IEmailService
+	mrrMovements   *MRRMovementService
 	logger         *zap.Logger
 }
 
@@ -37,6 +42,7 @@ func NewSubscriptionManagementService(
 		calculator:     calculator,
 		paymentService: paymentService,
 		emailService:   emailService,
+		mrrMovements:   NewMRRMovementService(db),
 		logger:         zap.L(),
 	}
 }
@@ -57,6 +63,7 @@ func NewSubscriptionManagementServiceWithDependencies(
 		calculator:     calculator,
 		paymentService: paymentService,
 		emailService:   emailService,
+		mrrMovements:   NewMRRMovementService(db),
 		logger:         logger,
 	}
 }
@@ -76,7 +83,7 @@ func (sms *SubscriptionManagementService) UpgradeSubscription(
 
 	// Validate subscription can be upgraded
 	if sub.Status != db.SubscriptionStatusActive {
-		return fmt.Errorf("can only upgrade active subscriptions, current status: %s", sub.Status)
+		return fmt.Errorf("%w: can only upgrade active subscriptions, current status: %s", ErrInvalidSubscriptionState, sub.Status)
 	}
 
 	// Calculate new total (simplified - in real implementation would calculate from line items)
@@ -127,6 +134,7 @@ func (sms *SubscriptionManagementService) UpgradeSubscription(
 	if err != nil {
 		return fmt.Errorf("failed to update subscription: %w", err)
 	}
+	sms.recordMRRMovement(ctx, sub, business.MRRSourceUpgrade, oldTotal, newTotal, &scheduleChange.ID)
 
 	// Create proration record
 	_, err = sms.db.CreateProrationRecord(ctx, db.CreateProrationRecordParams{
@@ -234,7 +242,7 @@ func (sms *SubscriptionManagementService) DowngradeSubscription(
 
 	// Validate subscription can be downgraded
 	if sub.Status != db.SubscriptionStatusActive {
-		return fmt.Errorf("can only downgrade active subscriptions")
+		return fmt.Errorf("%w: can only downgrade active subscriptions", ErrInvalidSubscriptionState)
 	}
 
 	// Schedule for end of period
@@ -315,7 +323,7 @@ func (sms *SubscriptionManagementService) CancelSubscription(
 
 	// Check if already cancelled
 	if sub.Status == db.SubscriptionStatusCanceled || sub.CancelAt.Valid {
-		return fmt.Errorf("subscription already cancelled")
+		return fmt.Errorf("%w: subscription already cancelled", ErrInvalidSubscriptionState)
 	}
 
 	// Set cancellation for end of period
@@ -400,7 +408,7 @@ func (sms *SubscriptionManagementService) PauseSubscription(
 
 	// Validate pause request
 	if sub.Status != db.SubscriptionStatusActive {
-		return fmt.Errorf("can only pause active subscriptions")
+		return fmt.Errorf("%w: can only pause active subscriptions", ErrInvalidSubscriptionState)
 	}
 
 	// Calculate any pause credit
@@ -424,6 +432,7 @@ func (sms *SubscriptionManagementService) PauseSubscription(
 	if err != nil {
 		return fmt.Errorf("failed to pause subscription: %w", err)
 	}
+	sms.recordMRRMovement(ctx, sub, business.MRRSourcePause, int64(sub.TotalAmountInCents), 0, nil)
 
 	// Create schedule change for automatic resume if pause end date provided
 	if pauseUntil != nil {
@@ -517,7 +526,7 @@ func (sms *SubscriptionManagementService) ResumeSubscription(
 	}
 
 	if sub.Status != db.SubscriptionStatusSuspended {
-		return fmt.Errorf("can only resume paused subscriptions")
+		return fmt.Errorf("%w: can only resume paused subscriptions", ErrInvalidSubscriptionState)
 	}
 
 	// Calculate new billing cycle
@@ -534,6 +543,7 @@ func (sms *SubscriptionManagementService) ResumeSubscription(
 	if err != nil {
 		return fmt.Errorf("failed to resume subscription: %w", err)
 	}
+	sms.recordMRRMovement(ctx, sub, business.MRRSourceResume, 0, int64(sub.TotalAmountInCents), nil)
 
 	// Process immediate payment for new period
 	// TODO: Implement payment processing
@@ -592,7 +602,8 @@ func (sms *SubscriptionManagementService) ResumeSubscription(
 	return nil
 }
 
-// ReactivateCancelledSubscription removes a scheduled cancellation
+// ReactivateCancelledSubscription removes a scheduled cancellation. Churn is only recorded when a
+// cancellation takes effect, so undoing one before then moves no MRR.
 func (sms *SubscriptionManagementService) ReactivateCancelledSubscription(
 	ctx context.Context,
 	subscriptionID uuid.UUID,
@@ -600,7 +611,7 @@ func (sms *SubscriptionManagementService) ReactivateCancelledSubscription(
 	_, err := sms.db.ReactivateScheduledCancellation(ctx, subscriptionID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("subscription is not scheduled for cancellation")
+			return fmt.Errorf("%w: subscription is not scheduled for cancellation", ErrInvalidSubscriptionState)
 		}
 		return fmt.Errorf("failed to reactivate subscription: %w", err)
 	}
@@ -819,11 +830,38 @@ func (sms *SubscriptionManagementService) executeDowngrade(ctx context.Context,
 }
 
 func (sms *SubscriptionManagementService) executeCancellation(ctx context.Context, change db.SubscriptionScheduleChange) error {
-	_, err := sms.db.CancelSubscriptionImmediately(ctx, db.CancelSubscriptionImmediatelyParams{
+	sub, err := sms.db.CancelSubscriptionImmediately(ctx, db.CancelSubscriptionImmediatelyParams{
 		ID:                 change.SubscriptionID,
 		CancellationReason: change.Reason,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	sms.recordMRRMovement(ctx, sub, business.MRRSourceCancel, int64(sub.TotalAmountInCents), 0, &change.ID)
+	return nil
+}
+
+// recordMRRMovement adds a change to the MRR movements ledger. The ledger explains MRR rather than
+// driving it, so failures are logged instead of failing the change.
+func (sms *SubscriptionManagementService) recordMRRMovement(ctx context.Context, sub db.Subscription, source string, fromAmountCents, toAmountCents int64, scheduleChangeID *uuid.UUID) {
+	if fromAmountCents == toAmountCents {
+		return
+	}
+
+	_, err := sms.mrrMovements.RecordMovement(ctx, params.RecordMRRMovementParams{
+		Subscription:     sub,
+		Source:           source,
+		FromAmountCents:  fromAmountCents,
+		ToAmountCents:    toAmountCents,
+		ScheduleChangeID: scheduleChangeID,
+		OccurredAt:       time.Now(),
+	})
+	if err != nil && !errors.Is(err, ErrNoMRRMovement) {
+		sms.logger.Error("Failed to record MRR movement",
+			zap.String("subscription_id", sub.ID.String()),
+			zap.String("source", source),
+			zap.Error(err))
+	}
 }
 
 func (sms *SubscriptionManagementService) executeResume(ctx context.Context, change db.SubscriptionScheduleChange) error {
@@ -1132,7 +1170,7 @@ func (sms *SubscriptionManagementService) ChangePrice(ctx context.Context, subsc
 
 	// Validate subscription can be changed
 	if sub.Status != db.SubscriptionStatusActive {
-		return fmt.Errorf("can only change price for active subscriptions, current status: %s", sub.Status)
+		return fmt.Errorf("%w: can only change price for active subscriptions, current status: %s", ErrInvalidSubscriptionState, sub.Status)
 	}
 
 	// Get current price from total amount (this is simplified - in production would get from product)
@@ -130,7 +130,7 @@ func (m *mockPaymentService) GetPayment(ctx context.Context, params params.GetPa
 	}, nil
 }
 
-func (m *mockPaymentService) GetPaymentByTransactionHash(ctx context.Context, txHash string) (*db.Payment, error) {
+func (m *mockPaymentService) GetPaymentByTransactionHash(ctx context.Context, txHash string, subscriptionEventID *uuid.UUID) (*db.Payment, error) {
 	return &db.Payment{
 		ID:            uuid.New(),
 		AmountInCents: 1000,
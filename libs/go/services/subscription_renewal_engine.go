@@ -0,0 +1,564 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Subscription renewal statuses stored on subscription_renewals
+const (
+	SubscriptionRenewalStatusClaimed     = "claimed"
+	SubscriptionRenewalStatusRedeeming   = "redeeming"
+	SubscriptionRenewalStatusRedeemed    = "redeemed"
+	SubscriptionRenewalStatusSucceeded   = "succeeded"
+	SubscriptionRenewalStatusFailed      = "failed"
+	SubscriptionRenewalStatusInterrupted = "interrupted"
+)
+
+// errRenewalOutcomeUnknown marks renewal errors after which it is unknown whether the customer was charged.
+// Such renewals are parked for review instead of being retried.
+var errRenewalOutcomeUnknown = errors.New("renewal redemption outcome unknown")
+
+// errRenewalAttemptsExhausted marks renewals that failed on every attempt they were allowed. They are parked
+// for review like renewals whose outcome is unknown, and only an admin requeue renews the period again.
+var errRenewalAttemptsExhausted = errors.New("renewal attempts exhausted")
+
+// errRenewalReverted marks a resumed renewal whose recorded transaction reverted on-chain. The customer was
+// not charged, so the renewal fails like any other and is retried.
+var errRenewalReverted = errors.New("renewal redemption reverted")
+
+// renewalInterruptedAlert tags log entries about renewals parked for review, for log-based alerts and metrics
+const renewalInterruptedAlert = "subscription_renewal_interrupted"
+
+// SubscriptionRenewalConfig controls how due subscriptions are claimed and renewed
+type SubscriptionRenewalConfig struct {
+	// WorkerCount is how many subscriptions are renewed at the same time
+	WorkerCount int
+	// BatchSize is how many due subscriptions are claimed at a time
+	BatchSize int32
+	// LeaseDuration is how long a claim stays exclusive; it must outlast a redemption, RPC timeout included.
+	// A failed renewal is retried once its lease has run out.
+	LeaseDuration time.Duration
+	// MaxAttempts is how many times a period is tried before a failing renewal is parked for review
+	MaxAttempts int32
+	// DeadlineMargin stops new renewals once the context deadline (the Lambda timeout) is this close
+	DeadlineMargin time.Duration
+	// FailureThreshold is how many consecutive delegation server health check failures open the circuit breaker
+	FailureThreshold int
+	// ResetTimeout is how long the circuit breaker stays open before the delegation server is probed again
+	ResetTimeout time.Duration
+	// RedemptionBatchSize is how many renewals on the same network are redeemed in one transaction.
+	// One or less redeems each renewal on its own.
+	RedemptionBatchSize int
+}
+
+// DefaultSubscriptionRenewalConfig returns the renewal settings used when none are configured
+func DefaultSubscriptionRenewalConfig() SubscriptionRenewalConfig {
+	return SubscriptionRenewalConfig{
+		WorkerCount:         5,
+		BatchSize:           25,
+		LeaseDuration:       10 * time.Minute,
+		MaxAttempts:         5,
+		DeadlineMargin:      4 * time.Minute,
+		FailureThreshold:    3,
+		ResetTimeout:        5 * time.Minute,
+		RedemptionBatchSize: 1,
+	}
+}
+
+// SubscriptionRenewalEngine renews due subscriptions with a bounded worker pool. Subscriptions are claimed
+// with FOR UPDATE SKIP LOCKED under a lease, and each billing period is tracked by its own idempotency key,
+// so overlapping runs never renew the same period twice and a crashed run resumes where it stopped.
+type SubscriptionRenewalEngine struct {
+	subscriptionService *SubscriptionService
+	queries             db.Querier
+	config              SubscriptionRenewalConfig
+	leaseOwner          pgtype.Text
+	logger              *zap.Logger
+
+	// circuitBreaker is the delegation server breaker shared with the redemption processor, so renewals
+	// stop being claimed while the server is down whichever process noticed it first
+	circuitBreaker *CircuitBreaker
+
+	// healthMu guards serverHealthy, which is set once the delegation server passed a health check in this run
+	healthMu      sync.Mutex
+	serverHealthy bool
+}
+
+// NewSubscriptionRenewalEngine creates a renewal engine for one processing run
+func NewSubscriptionRenewalEngine(subscriptionService *SubscriptionService, config SubscriptionRenewalConfig) *SubscriptionRenewalEngine {
+	defaults := DefaultSubscriptionRenewalConfig()
+	if config.WorkerCount <= 0 {
+		config.WorkerCount = defaults.WorkerCount
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.LeaseDuration <= 0 {
+		config.LeaseDuration = defaults.LeaseDuration
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaults.FailureThreshold
+	}
+	if config.ResetTimeout <= 0 {
+		config.ResetTimeout = defaults.ResetTimeout
+	}
+	if config.RedemptionBatchSize <= 0 {
+		config.RedemptionBatchSize = defaults.RedemptionBatchSize
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "subscription-processor"
+	}
+
+	return &SubscriptionRenewalEngine{
+		subscriptionService: subscriptionService,
+		queries:             subscriptionService.queries,
+		config:              config,
+		leaseOwner:          pgtype.Text{String: fmt.Sprintf("%s:%s", hostname, uuid.New().String()), Valid: true},
+		logger:              subscriptionService.logger,
+		circuitBreaker:      NewCircuitBreaker(subscriptionService.queries, DelegationServerCircuitBreaker, int32(config.FailureThreshold), config.ResetTimeout),
+	}
+}
+
+// Run claims and renews due subscriptions until none are left, the circuit breaker opens or the context
+// deadline gets too close. Claimed renewals that were not started are released for the next run.
+func (e *SubscriptionRenewalEngine) Run(ctx context.Context) (*responses.ProcessDueSubscriptionsResult, error) {
+	result := &responses.ProcessDueSubscriptionsResult{}
+	claimedAny := false
+
+	for !e.shouldStop(ctx) && !e.circuitOpen(ctx) {
+		renewals, err := e.claim(ctx)
+		if err != nil {
+			if !claimedAny {
+				e.logger.Error("Failed to claim subscriptions due for redemption", zap.Error(err))
+				return nil, err
+			}
+			e.logger.Error("Failed to claim next batch of due subscriptions", zap.Error(err))
+			break
+		}
+		if len(renewals) == 0 {
+			break
+		}
+		claimedAny = true
+
+		e.logger.Info("Claimed subscriptions due for redemption",
+			zap.Int("count", len(renewals)),
+			zap.String("lease_owner", e.leaseOwner.String))
+
+		e.processBatch(ctx, renewals, result)
+
+		if len(renewals) < int(e.config.BatchSize) {
+			break
+		}
+	}
+
+	if !claimedAny {
+		e.logger.Info("No subscriptions found due for renewal")
+	}
+
+	e.countAwaitingReview(ctx, result)
+
+	return result, nil
+}
+
+// claim leases the next batch of renewals, starting with redeemed renewals an earlier run did not finish recording
+func (e *SubscriptionRenewalEngine) claim(ctx context.Context) ([]db.SubscriptionRenewal, error) {
+	now := time.Now()
+	nowPgType := pgtype.Timestamptz{Time: now, Valid: true}
+	leaseExpiresAt := pgtype.Timestamptz{Time: now.Add(e.config.LeaseDuration), Valid: true}
+
+	renewals, err := e.queries.ClaimRedeemedSubscriptionRenewals(ctx, db.ClaimRedeemedSubscriptionRenewalsParams{
+		LeaseOwner:     e.leaseOwner,
+		LeaseExpiresAt: leaseExpiresAt,
+		Now:            nowPgType,
+		BatchSize:      e.config.BatchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim redeemed renewals: %w", err)
+	}
+
+	remaining := e.config.BatchSize - int32(len(renewals))
+	if remaining <= 0 {
+		return renewals, nil
+	}
+
+	due, err := e.queries.ClaimDueSubscriptionRenewals(ctx, db.ClaimDueSubscriptionRenewalsParams{
+		Now:            nowPgType,
+		BatchSize:      remaining,
+		LeaseOwner:     e.leaseOwner,
+		LeaseExpiresAt: leaseExpiresAt,
+	})
+	if err != nil {
+		// Redeemed renewals already claimed are still processed
+		if len(renewals) > 0 {
+			e.logger.Error("Failed to claim due subscriptions", zap.Error(err))
+			return renewals, nil
+		}
+		return nil, fmt.Errorf("failed to claim due subscriptions: %w", err)
+	}
+
+	return append(renewals, due...), nil
+}
+
+// renewalOutcomes adds renewal outcomes to a run's result from several workers
+type renewalOutcomes struct {
+	mu     sync.Mutex
+	result *responses.ProcessDueSubscriptionsResult
+}
+
+// processed counts a renewal that was attempted
+func (o *renewalOutcomes) processed(renewal db.SubscriptionRenewal, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.result.ProcessedCount++
+	if err != nil {
+		if errors.Is(err, errRenewalOutcomeUnknown) || errors.Is(err, errRenewalAttemptsExhausted) {
+			o.result.InterruptedCount++
+		}
+		o.result.FailedCount++
+		o.result.FailedIDs = append(o.result.FailedIDs, renewal.SubscriptionID)
+		o.result.ProcessingErrors = append(o.result.ProcessingErrors, err.Error())
+	} else {
+		o.result.SuccessfulCount++
+		o.result.ProcessedIDs = append(o.result.ProcessedIDs, renewal.SubscriptionID)
+	}
+}
+
+// deferred counts a renewal that was released without being attempted
+func (o *renewalOutcomes) deferred() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.result.DeferredCount++
+}
+
+// processBatch renews claimed subscriptions and adds the outcomes to result. With redemption batching on,
+// renewals that still need redeeming are grouped by network first; the rest run on the worker pool.
+func (e *SubscriptionRenewalEngine) processBatch(ctx context.Context, renewals []db.SubscriptionRenewal, result *responses.ProcessDueSubscriptionsResult) {
+	outcomes := &renewalOutcomes{result: result}
+
+	if e.config.RedemptionBatchSize > 1 {
+		renewals = e.redeemInBatches(ctx, renewals, outcomes)
+	}
+
+	tasks := make(chan db.SubscriptionRenewal)
+	var wg sync.WaitGroup
+
+	for i := 0; i < e.config.WorkerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for renewal := range tasks {
+				// Redeemed renewals are only recorded, so they do not need the delegation server
+				needsRedemption := renewal.Status != SubscriptionRenewalStatusRedeemed
+				if e.shouldStop(ctx) || (needsRedemption && !e.delegationServerAvailable(ctx)) {
+					e.release(renewal)
+					outcomes.deferred()
+					continue
+				}
+
+				outcomes.processed(renewal, e.renew(ctx, renewal))
+			}
+		}()
+	}
+
+	for _, renewal := range renewals {
+		tasks <- renewal
+	}
+	close(tasks)
+	wg.Wait()
+}
+
+// redeemInBatches redeems newly claimed renewals in batches of up to RedemptionBatchSize per network and
+// returns the renewals it left for the worker pool
+func (e *SubscriptionRenewalEngine) redeemInBatches(ctx context.Context, renewals []db.SubscriptionRenewal, outcomes *renewalOutcomes) []db.SubscriptionRenewal {
+	var remaining []db.SubscriptionRenewal
+	var networks []string
+	groups := make(map[string][]*renewalRedemption)
+
+	for _, renewal := range renewals {
+		if renewal.Status != SubscriptionRenewalStatusClaimed {
+			remaining = append(remaining, renewal)
+			continue
+		}
+		if e.shouldStop(ctx) {
+			e.release(renewal)
+			outcomes.deferred()
+			continue
+		}
+
+		redemption, err := e.subscriptionService.prepareRenewalRedemption(ctx, e.queries, renewal)
+		if err != nil || redemption == nil {
+			outcomes.processed(renewal, e.finish(ctx, renewal, err))
+			continue
+		}
+
+		// Solana renewals are transfers by the payment delegate, which cannot be batched and do not go
+		// through the delegation server
+		if redemption.splTransfer != nil {
+			err := e.subscriptionService.redeemRenewal(ctx, e.queries, redemption, e.leaseOwner)
+			outcomes.processed(renewal, e.finish(ctx, renewal, err))
+			continue
+		}
+
+		network := fmt.Sprintf("%d:%s", redemption.execution.ChainID, redemption.execution.NetworkName)
+		if _, ok := groups[network]; !ok {
+			networks = append(networks, network)
+		}
+		groups[network] = append(groups[network], redemption)
+	}
+
+	for _, network := range networks {
+		group := groups[network]
+		for start := 0; start < len(group); start += e.config.RedemptionBatchSize {
+			chunk := group[start:min(start+e.config.RedemptionBatchSize, len(group))]
+
+			if e.shouldStop(ctx) || !e.delegationServerAvailable(ctx) {
+				for _, redemption := range chunk {
+					e.release(redemption.renewal)
+					outcomes.deferred()
+				}
+				continue
+			}
+
+			if len(chunk) == 1 {
+				renewal := chunk[0].renewal
+				err := e.subscriptionService.redeemRenewal(ctx, e.queries, chunk[0], e.leaseOwner)
+				outcomes.processed(renewal, e.finish(ctx, renewal, err))
+				continue
+			}
+
+			e.logger.Info("Redeeming subscription renewals in one transaction",
+				zap.String("network", network),
+				zap.Int("count", len(chunk)))
+
+			errs := e.subscriptionService.redeemRenewalBatch(ctx, e.queries, chunk, e.leaseOwner)
+			for i, redemption := range chunk {
+				outcomes.processed(redemption.renewal, e.finish(ctx, redemption.renewal, errs[i]))
+			}
+		}
+	}
+
+	return remaining
+}
+
+// renew processes one claimed renewal and records its outcome
+func (e *SubscriptionRenewalEngine) renew(ctx context.Context, renewal db.SubscriptionRenewal) error {
+	// A previous run stopped while the redemption was in flight, so the customer may have been charged
+	if renewal.Status == SubscriptionRenewalStatusRedeeming {
+		e.logger.Warn("Renewal was interrupted during redemption, parking it for review", renewalLogFields(renewal)...)
+		e.interrupt(ctx, renewal, "interrupted while the redemption was in flight")
+		return fmt.Errorf("renewal %s needs review: %w", renewal.IdempotencyKey, errRenewalOutcomeUnknown)
+	}
+
+	e.logger.Info("Processing subscription renewal", renewalLogFields(renewal)...)
+
+	err := e.subscriptionService.processSingleSubscription(ctx, e.queries, renewal, e.leaseOwner)
+	return e.finish(ctx, renewal, err)
+}
+
+// finish records the outcome of processing a renewal and returns the processing error
+func (e *SubscriptionRenewalEngine) finish(ctx context.Context, renewal db.SubscriptionRenewal, err error) error {
+	logFields := renewalLogFields(renewal)
+
+	if err != nil {
+		e.logger.Error("Failed to process subscription", append(logFields, zap.Error(err))...)
+
+		if errors.Is(err, errRenewalOutcomeUnknown) {
+			e.interrupt(ctx, renewal, err.Error())
+			return err
+		}
+
+		// The recorded transaction never charged the customer, so it is dropped and the period retried
+		if errors.Is(err, errRenewalReverted) {
+			if _, failErr := e.queries.FailRevertedSubscriptionRenewal(ctx, db.FailRevertedSubscriptionRenewalParams{
+				ErrorMessage: helpers.StringToNullableText(err.Error()),
+				ID:           renewal.ID,
+				LeaseOwner:   e.leaseOwner,
+			}); failErr != nil {
+				e.logger.Error("Failed to record reverted subscription renewal", append(logFields, zap.Error(failErr))...)
+			}
+			return err
+		}
+
+		// Redeemed renewals only have their recording left, so the cap is for periods that keep failing to renew
+		if renewal.Status != SubscriptionRenewalStatusRedeemed && renewal.Attempts >= e.config.MaxAttempts {
+			e.interrupt(ctx, renewal, fmt.Sprintf("failed %d times; last error: %s", renewal.Attempts, err))
+			return fmt.Errorf("renewal %s failed %d times: %v: %w", renewal.IdempotencyKey, renewal.Attempts, err, errRenewalAttemptsExhausted)
+		}
+
+		// A redeemed renewal is not failed; it keeps its transaction and resumes once the lease runs out
+		failed, failErr := e.queries.FailSubscriptionRenewal(ctx, db.FailSubscriptionRenewalParams{
+			ErrorMessage: helpers.StringToNullableText(err.Error()),
+			ID:           renewal.ID,
+			LeaseOwner:   e.leaseOwner,
+		})
+		if failErr != nil {
+			e.logger.Error("Failed to record failed subscription renewal", append(logFields, zap.Error(failErr))...)
+		} else if failed == 0 {
+			e.logger.Warn("Renewal redeemed but not fully recorded, it will resume on a later run", logFields...)
+		}
+		return err
+	}
+
+	if _, err := e.queries.CompleteSubscriptionRenewal(ctx, db.CompleteSubscriptionRenewalParams{
+		ID:         renewal.ID,
+		LeaseOwner: e.leaseOwner,
+	}); err != nil {
+		// The subscription has already moved to its next period, so the renewal cannot be claimed again
+		e.logger.Error("Failed to mark subscription renewal as succeeded", append(logFields, zap.Error(err))...)
+	}
+	return nil
+}
+
+// renewalLogFields identifies a renewal in log entries
+func renewalLogFields(renewal db.SubscriptionRenewal) []zap.Field {
+	return []zap.Field{
+		zap.String("subscription_id", renewal.SubscriptionID.String()),
+		zap.String("idempotency_key", renewal.IdempotencyKey),
+		zap.Int32("attempt", renewal.Attempts),
+	}
+}
+
+// interrupt parks a renewal whose redemption outcome is unknown or that ran out of attempts. Parked renewals are never claimed again
+// until they are requeued or resolved through RenewalReviewService, so each one raises an alert.
+func (e *SubscriptionRenewalEngine) interrupt(ctx context.Context, renewal db.SubscriptionRenewal, reason string) {
+	e.logger.Error("Subscription renewal parked for review",
+		zap.String("alert", renewalInterruptedAlert),
+		zap.String("subscription_id", renewal.SubscriptionID.String()),
+		zap.String("renewal_id", renewal.ID.String()),
+		zap.String("idempotency_key", renewal.IdempotencyKey),
+		zap.String("reason", reason))
+
+	if _, err := e.queries.InterruptSubscriptionRenewal(ctx, db.InterruptSubscriptionRenewalParams{
+		ErrorMessage: helpers.StringToNullableText(reason),
+		ID:           renewal.ID,
+		LeaseOwner:   e.leaseOwner,
+	}); err != nil {
+		e.logger.Error("Failed to park interrupted subscription renewal",
+			zap.String("subscription_id", renewal.SubscriptionID.String()),
+			zap.String("idempotency_key", renewal.IdempotencyKey),
+			zap.Error(err))
+	}
+}
+
+// countAwaitingReview adds how many renewals are parked for review to a run's result, and alerts while
+// any are left so that none are forgotten
+func (e *SubscriptionRenewalEngine) countAwaitingReview(ctx context.Context, result *responses.ProcessDueSubscriptionsResult) {
+	count, err := e.queries.CountSubscriptionRenewals(ctx, helpers.StringToNullableText(SubscriptionRenewalStatusInterrupted))
+	if err != nil {
+		e.logger.Error("Failed to count subscription renewals awaiting review", zap.Error(err))
+		return
+	}
+
+	result.AwaitingReviewCount = count
+	if count > 0 {
+		e.logger.Warn("Subscription renewals are awaiting review",
+			zap.String("alert", renewalInterruptedAlert),
+			zap.Int64("count", count))
+	}
+}
+
+// release gives a claimed renewal back so the next run can pick it up straight away
+func (e *SubscriptionRenewalEngine) release(renewal db.SubscriptionRenewal) {
+	// Released on a fresh context so that renewals are still handed back once the run's context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := e.queries.ReleaseSubscriptionRenewalLease(ctx, db.ReleaseSubscriptionRenewalLeaseParams{
+		ID:         renewal.ID,
+		LeaseOwner: e.leaseOwner,
+	}); err != nil {
+		e.logger.Error("Failed to release subscription renewal lease",
+			zap.String("subscription_id", renewal.SubscriptionID.String()),
+			zap.Error(err))
+	}
+}
+
+// shouldStop reports whether no further renewals should be started in this run
+func (e *SubscriptionRenewalEngine) shouldStop(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < e.config.DeadlineMargin {
+		return true
+	}
+	return false
+}
+
+// circuitOpen reports whether the delegation server breaker is open, in which case no more renewals are
+// claimed; redeemed renewals left over are recorded by a later run
+func (e *SubscriptionRenewalEngine) circuitOpen(ctx context.Context) bool {
+	if e.subscriptionService.delegationClient == nil {
+		return false
+	}
+
+	breaker, err := e.circuitBreaker.State(ctx)
+	if err != nil {
+		e.logger.Error("Failed to check delegation server circuit breaker", zap.Error(err))
+		return false
+	}
+	if breaker.State == CircuitBreakerStateClosed {
+		return false
+	}
+
+	e.logger.Warn("Delegation server circuit breaker is open, not claiming renewals",
+		zap.String("state", breaker.State),
+		zap.Int32("consecutive_failures", breaker.ConsecutiveFailures))
+	return true
+}
+
+// delegationServerAvailable reports whether renewals may call the delegation server. The server is health
+// checked until one check passes, then trusted for the rest of the run; failed checks count towards the
+// shared circuit breaker, and while it is open renewals are deferred without calling the server.
+func (e *SubscriptionRenewalEngine) delegationServerAvailable(ctx context.Context) bool {
+	delegationClient := e.subscriptionService.delegationClient
+	if delegationClient == nil {
+		return true
+	}
+
+	e.healthMu.Lock()
+	defer e.healthMu.Unlock()
+	if e.serverHealthy {
+		return true
+	}
+
+	allowed, err := e.circuitBreaker.Allow(ctx)
+	if err != nil {
+		e.logger.Error("Failed to check delegation server circuit breaker", zap.Error(err))
+		return false
+	}
+	if !allowed {
+		return false
+	}
+
+	if err := delegationClient.HealthCheck(ctx); err != nil {
+		e.logger.Warn("Delegation server unavailable, deferring renewal", zap.Error(err))
+		if _, cbErr := e.circuitBreaker.RecordFailure(ctx, err); cbErr != nil {
+			e.logger.Error("Failed to record delegation server failure", zap.Error(cbErr))
+		}
+		return false
+	}
+
+	if err := e.circuitBreaker.RecordSuccess(ctx); err != nil {
+		e.logger.Error("Failed to reset delegation server circuit breaker", zap.Error(err))
+	}
+	e.serverHealthy = true
+	return true
+}
@@ -0,0 +1,521 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	dsClient "github.com/cyphera/cyphera-api/libs/go/client/delegation_server"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/proto"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// expectCircuitBreakerClosed mocks a delegation server circuit breaker that has never opened
+func expectCircuitBreakerClosed(mockQuerier *mocks.MockQuerier) {
+	mockQuerier.EXPECT().GetCircuitBreaker(gomock.Any(), services.DelegationServerCircuitBreaker).Return(db.CircuitBreaker{}, pgx.ErrNoRows).AnyTimes()
+	mockQuerier.EXPECT().RecordCircuitBreakerSuccess(gomock.Any(), services.DelegationServerCircuitBreaker).Return(int64(0), nil).AnyTimes()
+}
+
+func TestSubscriptionRenewalEngine_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := createSubscriptionService(ctrl, mockQuerier, nil)
+	ctx := context.Background()
+	expectNoRenewalsAwaitingReview(mockQuerier)
+
+	config := services.DefaultSubscriptionRenewalConfig()
+	config.BatchSize = 2
+	config.WorkerCount = 2
+
+	periodDueAt := pgtype.Timestamptz{Time: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	newRenewal := func(status string) db.SubscriptionRenewal {
+		subscriptionID := uuid.New()
+		return db.SubscriptionRenewal{
+			ID:             uuid.New(),
+			SubscriptionID: subscriptionID,
+			IdempotencyKey: "renewal:" + subscriptionID.String() + ":1740787200",
+			PeriodDueAt:    periodDueAt,
+			Status:         status,
+			Attempts:       1,
+		}
+	}
+
+	t.Run("returns an empty result when nothing is due", func(t *testing.T) {
+		mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{}, nil)
+		mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{}, nil)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, config).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.ProcessedCount)
+	})
+
+	t.Run("fails the run when nothing could be claimed", func(t *testing.T) {
+		mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(ctx, gomock.Any()).Return(nil, errors.New("connection refused"))
+
+		_, err := services.NewSubscriptionRenewalEngine(service, config).Run(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("claims due subscriptions under one lease", func(t *testing.T) {
+		renewal := newRenewal(services.SubscriptionRenewalStatusClaimed)
+		var leaseOwner pgtype.Text
+
+		mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.ClaimRedeemedSubscriptionRenewalsParams) ([]db.SubscriptionRenewal, error) {
+				leaseOwner = arg.LeaseOwner
+				assert.True(t, arg.LeaseOwner.Valid)
+				assert.Equal(t, int32(2), arg.BatchSize)
+				return []db.SubscriptionRenewal{}, nil
+			})
+		mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.ClaimDueSubscriptionRenewalsParams) ([]db.SubscriptionRenewal, error) {
+				assert.Equal(t, leaseOwner, arg.LeaseOwner)
+				assert.Equal(t, config.LeaseDuration, arg.LeaseExpiresAt.Time.Sub(arg.Now.Time))
+				return []db.SubscriptionRenewal{renewal}, nil
+			})
+		// The period was renewed after the claim, so there is nothing left to redeem
+		mockQuerier.EXPECT().GetSubscription(ctx, renewal.SubscriptionID).Return(db.Subscription{
+			ID:                 renewal.SubscriptionID,
+			Status:             db.SubscriptionStatusActive,
+			NextRedemptionDate: pgtype.Timestamptz{Time: periodDueAt.Time.AddDate(0, 1, 0), Valid: true},
+		}, nil)
+		mockQuerier.EXPECT().CompleteSubscriptionRenewal(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.CompleteSubscriptionRenewalParams) (db.SubscriptionRenewal, error) {
+				assert.Equal(t, renewal.ID, arg.ID)
+				assert.Equal(t, leaseOwner, arg.LeaseOwner)
+				return renewal, nil
+			})
+
+		result, err := services.NewSubscriptionRenewalEngine(service, config).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.ProcessedCount)
+		assert.Equal(t, 1, result.SuccessfulCount)
+		assert.Equal(t, []uuid.UUID{renewal.SubscriptionID}, result.ProcessedIDs)
+	})
+
+	t.Run("keeps claiming while batches are full", func(t *testing.T) {
+		first := newRenewal(services.SubscriptionRenewalStatusClaimed)
+		second := newRenewal(services.SubscriptionRenewalStatusClaimed)
+		third := newRenewal(services.SubscriptionRenewalStatusClaimed)
+
+		gomock.InOrder(
+			mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{}, nil),
+			mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{first, second}, nil),
+			mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{}, nil),
+			mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{third}, nil),
+		)
+		for _, renewal := range []db.SubscriptionRenewal{first, second, third} {
+			mockQuerier.EXPECT().GetSubscription(gomock.Any(), renewal.SubscriptionID).Return(db.Subscription{
+				ID:     renewal.SubscriptionID,
+				Status: db.SubscriptionStatusCompleted,
+			}, nil)
+		}
+		mockQuerier.EXPECT().CompleteSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(db.SubscriptionRenewal{}, nil).Times(3)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, config).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.ProcessedCount)
+		assert.Equal(t, 3, result.SuccessfulCount)
+	})
+
+	t.Run("parks a renewal interrupted during redemption for review", func(t *testing.T) {
+		renewal := newRenewal(services.SubscriptionRenewalStatusRedeeming)
+
+		mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{}, nil)
+		mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{renewal}, nil)
+		mockQuerier.EXPECT().InterruptSubscriptionRenewal(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.InterruptSubscriptionRenewalParams) (db.SubscriptionRenewal, error) {
+				assert.Equal(t, renewal.ID, arg.ID)
+				assert.True(t, arg.ErrorMessage.Valid)
+				return renewal, nil
+			})
+
+		result, err := services.NewSubscriptionRenewalEngine(service, config).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FailedCount)
+		assert.Equal(t, 1, result.InterruptedCount)
+		assert.Equal(t, []uuid.UUID{renewal.SubscriptionID}, result.FailedIDs)
+	})
+
+	t.Run("reports renewals awaiting review", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		service := createSubscriptionService(ctrl, mockQuerier, nil)
+
+		mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{}, nil)
+		mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{}, nil)
+		mockQuerier.EXPECT().CountSubscriptionRenewals(ctx, pgtype.Text{String: services.SubscriptionRenewalStatusInterrupted, Valid: true}).Return(int64(3), nil)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, config).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), result.AwaitingReviewCount)
+	})
+
+	t.Run("does not record a redemption twice when resuming", func(t *testing.T) {
+		renewal := newRenewal(services.SubscriptionRenewalStatusRedeemed)
+		renewal.TransactionHash = pgtype.Text{String: "0xabc", Valid: true}
+
+		mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{renewal}, nil)
+		mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.ClaimDueSubscriptionRenewalsParams) ([]db.SubscriptionRenewal, error) {
+				// Resumed renewals take up part of the batch
+				assert.Equal(t, int32(1), arg.BatchSize)
+				return []db.SubscriptionRenewal{}, nil
+			})
+		mockQuerier.EXPECT().GetSubscription(ctx, renewal.SubscriptionID).Return(db.Subscription{
+			ID:                 renewal.SubscriptionID,
+			Status:             db.SubscriptionStatusActive,
+			NextRedemptionDate: pgtype.Timestamptz{Time: periodDueAt.Time.AddDate(0, 1, 0), Valid: true},
+		}, nil)
+		mockQuerier.EXPECT().GetSubscriptionRedemptionEventByTransactionHash(ctx, db.GetSubscriptionRedemptionEventByTransactionHashParams{
+			SubscriptionID:  renewal.SubscriptionID,
+			TransactionHash: renewal.TransactionHash,
+		}).Return(db.SubscriptionEvent{
+			ID:             uuid.New(),
+			SubscriptionID: renewal.SubscriptionID,
+			EventType:      db.SubscriptionEventTypeRedeem,
+		}, nil)
+		mockQuerier.EXPECT().CompleteSubscriptionRenewal(ctx, gomock.Any()).Return(renewal, nil)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, config).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.SuccessfulCount)
+	})
+
+	t.Run("keeps a redeemed renewal for the next run when recording fails", func(t *testing.T) {
+		renewal := newRenewal(services.SubscriptionRenewalStatusRedeemed)
+		renewal.TransactionHash = pgtype.Text{String: "0xdef", Valid: true}
+
+		mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{renewal}, nil)
+		mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(ctx, gomock.Any()).Return([]db.SubscriptionRenewal{}, nil)
+		mockQuerier.EXPECT().GetSubscription(ctx, renewal.SubscriptionID).Return(db.Subscription{}, errors.New("connection reset"))
+		// Only claimed or redeeming renewals fail, so no row is updated
+		mockQuerier.EXPECT().FailSubscriptionRenewal(ctx, gomock.Any()).Return(int64(0), nil)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, config).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FailedCount)
+	})
+
+	t.Run("does not claim once the deadline is too close", func(t *testing.T) {
+		shortConfig := config
+		shortConfig.DeadlineMargin = time.Hour
+		deadlineCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		result, err := services.NewSubscriptionRenewalEngine(service, shortConfig).Run(deadlineCtx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.ProcessedCount)
+		assert.Equal(t, 0, result.DeferredCount)
+	})
+}
+
+func TestSubscriptionRenewalEngine_Redemption(t *testing.T) {
+	ctx := context.Background()
+	periodDueAt := pgtype.Timestamptz{Time: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	setup := func(t *testing.T) (*mocks.MockQuerier, *services.SubscriptionService, *dsClient.FakeServer) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		expectNoRenewalsAwaitingReview(mockQuerier)
+		expectCircuitBreakerClosed(mockQuerier)
+
+		server := dsClient.NewFakeServer()
+		t.Cleanup(server.Close)
+		client, err := server.Client()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = client.Close() })
+
+		return mockQuerier, createSubscriptionService(ctrl, mockQuerier, client), server
+	}
+
+	// expectPrepared mocks the lookups of a due renewal on Base and returns the renewal
+	expectPrepared := func(mockQuerier *mocks.MockQuerier, status string) db.SubscriptionRenewal {
+		subscription := db.Subscription{
+			ID:                 uuid.New(),
+			Status:             db.SubscriptionStatusActive,
+			ProductID:          uuid.New(),
+			CustomerID:         uuid.New(),
+			DelegationID:       uuid.New(),
+			CustomerWalletID:   pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			ProductTokenID:     uuid.New(),
+			TokenAmount:        1000000,
+			NextRedemptionDate: periodDueAt,
+		}
+		product := db.Product{ID: subscription.ProductID, WorkspaceID: uuid.New(), WalletID: uuid.New(), PriceType: db.PriceTypeOneTime}
+		productToken := db.GetProductTokenRow{ID: subscription.ProductTokenID, TokenID: uuid.New(), ChainID: 8453, NetworkName: "Base"}
+
+		mockQuerier.EXPECT().GetSubscription(gomock.Any(), subscription.ID).Return(subscription, nil)
+		mockQuerier.EXPECT().GetProductWithoutWorkspaceId(gomock.Any(), product.ID).Return(product, nil)
+		mockQuerier.EXPECT().GetCustomer(gomock.Any(), subscription.CustomerID).Return(db.Customer{ID: subscription.CustomerID}, nil)
+		mockQuerier.EXPECT().GetDelegationData(gomock.Any(), subscription.DelegationID).Return(db.DelegationDatum{
+			ID:        subscription.DelegationID,
+			Delegate:  "0x1111111111111111111111111111111111111111",
+			Delegator: "0x2222222222222222222222222222222222222222",
+			Caveats:   json.RawMessage(`[]`),
+			Signature: "0xsig",
+		}, nil)
+		mockQuerier.EXPECT().GetWalletByID(gomock.Any(), gomock.Any()).Return(db.Wallet{
+			WalletAddress: "0x3333333333333333333333333333333333333333",
+		}, nil)
+		mockQuerier.EXPECT().GetCustomerWallet(gomock.Any(), subscription.CustomerWalletID.Bytes).Return(db.CustomerWallet{}, nil)
+		mockQuerier.EXPECT().GetProductToken(gomock.Any(), productToken.ID).Return(productToken, nil)
+		mockQuerier.EXPECT().GetToken(gomock.Any(), productToken.TokenID).Return(db.Token{
+			ContractAddress: "0x4444444444444444444444444444444444444444",
+			Decimals:        6,
+		}, nil)
+
+		return db.SubscriptionRenewal{
+			ID:             uuid.New(),
+			SubscriptionID: subscription.ID,
+			IdempotencyKey: "renewal:" + subscription.ID.String() + ":1740787200",
+			PeriodDueAt:    periodDueAt,
+			Status:         status,
+			Attempts:       1,
+		}
+	}
+
+	t.Run("does not send a redemption that would revert", func(t *testing.T) {
+		mockQuerier, service, server := setup(t)
+		server.SimulateHandler = func(*proto.RedeemDelegationRequest) (*proto.SimulateRedemptionResponse, error) {
+			return &proto.SimulateRedemptionResponse{Error: &proto.RedemptionError{
+				Code:         proto.ErrorCode_ERROR_CODE_EXECUTION_REVERTED,
+				Message:      "redemption would revert",
+				RevertReason: "ERC20: transfer amount exceeds balance",
+			}}, nil
+		}
+
+		renewal := expectPrepared(mockQuerier, services.SubscriptionRenewalStatusClaimed)
+		expectRenewalClaim(mockQuerier, renewal)
+		mockQuerier.EXPECT().UpdateSubscriptionStatus(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.UpdateSubscriptionStatusParams) (db.Subscription, error) {
+				assert.Equal(t, db.SubscriptionStatusOverdue, arg.Status)
+				return db.Subscription{}, nil
+			})
+		mockQuerier.EXPECT().ListInvoicesBySubscription(gomock.Any(), gomock.Any()).Return(nil, nil)
+		mockQuerier.EXPECT().FailSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, services.DefaultSubscriptionRenewalConfig()).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FailedCount)
+		assert.Contains(t, result.ProcessingErrors[0], "transfer amount exceeds balance")
+		assert.Len(t, server.SimulateRequests(), 1)
+		assert.Empty(t, server.RedeemRequests())
+	})
+
+	t.Run("leaves the subscription alone after a retryable failure", func(t *testing.T) {
+		mockQuerier, service, server := setup(t)
+		server.RedeemHandler = func(*proto.RedeemDelegationRequest) (*proto.RedeemDelegationResponse, error) {
+			return &proto.RedeemDelegationResponse{Error: &proto.RedemptionError{
+				Code:      proto.ErrorCode_ERROR_CODE_NETWORK_ERROR,
+				Message:   "bundler unreachable",
+				Retryable: true,
+			}}, nil
+		}
+
+		renewal := expectPrepared(mockQuerier, services.SubscriptionRenewalStatusClaimed)
+		expectRenewalClaim(mockQuerier, renewal)
+		mockQuerier.EXPECT().MarkSubscriptionRenewalRedeeming(gomock.Any(), gomock.Any()).Return(renewal, nil)
+		mockQuerier.EXPECT().FailSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, services.DefaultSubscriptionRenewalConfig()).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FailedCount)
+	})
+
+	t.Run("parks a redemption that was sent but not confirmed", func(t *testing.T) {
+		mockQuerier, service, server := setup(t)
+		server.RedeemHandler = func(*proto.RedeemDelegationRequest) (*proto.RedeemDelegationResponse, error) {
+			return &proto.RedeemDelegationResponse{Error: &proto.RedemptionError{
+				Code:    proto.ErrorCode_ERROR_CODE_CONFIRMATION_TIMEOUT,
+				Message: "user operation not confirmed in time",
+			}}, nil
+		}
+
+		renewal := expectPrepared(mockQuerier, services.SubscriptionRenewalStatusClaimed)
+		expectRenewalClaim(mockQuerier, renewal)
+		mockQuerier.EXPECT().MarkSubscriptionRenewalRedeeming(gomock.Any(), gomock.Any()).Return(renewal, nil)
+		mockQuerier.EXPECT().InterruptSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(renewal, nil)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, services.DefaultSubscriptionRenewalConfig()).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FailedCount)
+	})
+
+	t.Run("redeems renewals on one network in a single transaction", func(t *testing.T) {
+		mockQuerier, service, server := setup(t)
+
+		first := expectPrepared(mockQuerier, services.SubscriptionRenewalStatusClaimed)
+		second := expectPrepared(mockQuerier, services.SubscriptionRenewalStatusClaimed)
+		expectRenewalClaim(mockQuerier, first, second)
+		mockQuerier.EXPECT().MarkSubscriptionRenewalRedeeming(gomock.Any(), gomock.Any()).Return(db.SubscriptionRenewal{}, nil).Times(2)
+
+		var txHashes []string
+		mockQuerier.EXPECT().MarkSubscriptionRenewalRedeemed(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.MarkSubscriptionRenewalRedeemedParams) (db.SubscriptionRenewal, error) {
+				txHashes = append(txHashes, arg.TransactionHash.String)
+				return db.SubscriptionRenewal{}, nil
+			}).Times(2)
+		// Recording fails after the redemption, so both renewals keep their transaction for the next run
+		mockQuerier.EXPECT().UpdateSubscriptionStatus(gomock.Any(), gomock.Any()).Return(db.Subscription{}, nil).Times(2)
+		mockQuerier.EXPECT().IncrementSubscriptionRedemption(gomock.Any(), gomock.Any()).Return(db.Subscription{}, errors.New("connection reset")).Times(2)
+		mockQuerier.EXPECT().FailSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(int64(0), nil).Times(2)
+
+		config := services.DefaultSubscriptionRenewalConfig()
+		config.RedemptionBatchSize = 5
+
+		result, err := services.NewSubscriptionRenewalEngine(service, config).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.ProcessedCount)
+
+		batches := server.BatchRequests()
+		require.Len(t, batches, 1)
+		require.Len(t, batches[0].GetRedemptions(), 2)
+		assert.Equal(t, first.ID.String(), batches[0].GetRedemptions()[0].GetReferenceId())
+		assert.Equal(t, second.ID.String(), batches[0].GetRedemptions()[1].GetReferenceId())
+		assert.Empty(t, server.RedeemRequests())
+
+		require.Len(t, txHashes, 2)
+		assert.Equal(t, txHashes[0], txHashes[1])
+	})
+
+	t.Run("waits for a resumed redemption that is still pending", func(t *testing.T) {
+		mockQuerier, service, server := setup(t)
+		server.StatusHandler = func(*proto.GetRedemptionStatusRequest) (*proto.GetRedemptionStatusResponse, error) {
+			return &proto.GetRedemptionStatusResponse{Status: proto.RedemptionStatus_REDEMPTION_STATUS_PENDING}, nil
+		}
+
+		renewal := expectPrepared(mockQuerier, services.SubscriptionRenewalStatusRedeemed)
+		renewal.TransactionHash = pgtype.Text{String: "0xabc", Valid: true}
+		mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(gomock.Any(), gomock.Any()).Return([]db.SubscriptionRenewal{renewal}, nil)
+		mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(gomock.Any(), gomock.Any()).Return([]db.SubscriptionRenewal{}, nil)
+		mockQuerier.EXPECT().GetSubscriptionRedemptionEventByTransactionHash(gomock.Any(), gomock.Any()).Return(db.SubscriptionEvent{}, pgx.ErrNoRows)
+		mockQuerier.EXPECT().FailSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, services.DefaultSubscriptionRenewalConfig()).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FailedCount)
+
+		statusRequests := server.StatusRequests()
+		require.Len(t, statusRequests, 1)
+		assert.Equal(t, "0xabc", statusRequests[0].GetTransactionHash())
+		assert.Equal(t, uint32(8453), statusRequests[0].GetChainId())
+	})
+
+	t.Run("fails a resumed redemption that reverted on-chain", func(t *testing.T) {
+		mockQuerier, service, server := setup(t)
+		server.StatusHandler = func(*proto.GetRedemptionStatusRequest) (*proto.GetRedemptionStatusResponse, error) {
+			return &proto.GetRedemptionStatusResponse{Status: proto.RedemptionStatus_REDEMPTION_STATUS_FAILED}, nil
+		}
+
+		renewal := expectPrepared(mockQuerier, services.SubscriptionRenewalStatusRedeemed)
+		renewal.TransactionHash = pgtype.Text{String: "0xdef", Valid: true}
+		mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(gomock.Any(), gomock.Any()).Return([]db.SubscriptionRenewal{renewal}, nil)
+		mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(gomock.Any(), gomock.Any()).Return([]db.SubscriptionRenewal{}, nil)
+		mockQuerier.EXPECT().GetSubscriptionRedemptionEventByTransactionHash(gomock.Any(), gomock.Any()).Return(db.SubscriptionEvent{}, pgx.ErrNoRows)
+		// The customer was not charged, so the subscription goes overdue and the period is retried
+		mockQuerier.EXPECT().UpdateSubscriptionStatus(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.UpdateSubscriptionStatusParams) (db.Subscription, error) {
+				assert.Equal(t, db.SubscriptionStatusOverdue, arg.Status)
+				return db.Subscription{}, nil
+			})
+		mockQuerier.EXPECT().ListInvoicesBySubscription(gomock.Any(), gomock.Any()).Return(nil, nil)
+		mockQuerier.EXPECT().FailRevertedSubscriptionRenewal(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.FailRevertedSubscriptionRenewalParams) (int64, error) {
+				assert.Equal(t, renewal.ID, arg.ID)
+				assert.Contains(t, arg.ErrorMessage.String, "reverted on-chain")
+				return 1, nil
+			})
+
+		result, err := services.NewSubscriptionRenewalEngine(service, services.DefaultSubscriptionRenewalConfig()).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FailedCount)
+		assert.Equal(t, 0, result.InterruptedCount)
+	})
+	t.Run("checks the delegation server once per run", func(t *testing.T) {
+		mockQuerier, service, server := setup(t)
+		server.SimulateHandler = func(*proto.RedeemDelegationRequest) (*proto.SimulateRedemptionResponse, error) {
+			return &proto.SimulateRedemptionResponse{Error: &proto.RedemptionError{
+				Code:    proto.ErrorCode_ERROR_CODE_EXECUTION_REVERTED,
+				Message: "redemption would revert",
+			}}, nil
+		}
+
+		first := expectPrepared(mockQuerier, services.SubscriptionRenewalStatusClaimed)
+		second := expectPrepared(mockQuerier, services.SubscriptionRenewalStatusClaimed)
+		expectRenewalClaim(mockQuerier, first, second)
+		mockQuerier.EXPECT().UpdateSubscriptionStatus(gomock.Any(), gomock.Any()).Return(db.Subscription{}, nil).Times(2)
+		mockQuerier.EXPECT().ListInvoicesBySubscription(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+		mockQuerier.EXPECT().FailSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(2)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, services.DefaultSubscriptionRenewalConfig()).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.FailedCount)
+		assert.Equal(t, 1, server.HealthChecks())
+	})
+
+	t.Run("parks a renewal for review once it runs out of attempts", func(t *testing.T) {
+		mockQuerier, service, server := setup(t)
+		server.SimulateHandler = func(*proto.RedeemDelegationRequest) (*proto.SimulateRedemptionResponse, error) {
+			return &proto.SimulateRedemptionResponse{Error: &proto.RedemptionError{
+				Code:    proto.ErrorCode_ERROR_CODE_EXECUTION_REVERTED,
+				Message: "redemption would revert",
+			}}, nil
+		}
+
+		config := services.DefaultSubscriptionRenewalConfig()
+		renewal := expectPrepared(mockQuerier, services.SubscriptionRenewalStatusClaimed)
+		renewal.Attempts = config.MaxAttempts
+		expectRenewalClaim(mockQuerier, renewal)
+		mockQuerier.EXPECT().UpdateSubscriptionStatus(gomock.Any(), gomock.Any()).Return(db.Subscription{}, nil)
+		mockQuerier.EXPECT().ListInvoicesBySubscription(gomock.Any(), gomock.Any()).Return(nil, nil)
+		mockQuerier.EXPECT().InterruptSubscriptionRenewal(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg db.InterruptSubscriptionRenewalParams) (db.SubscriptionRenewal, error) {
+				assert.Equal(t, renewal.ID, arg.ID)
+				assert.Contains(t, arg.ErrorMessage.String, "failed 5 times")
+				return db.SubscriptionRenewal{}, nil
+			})
+
+		result, err := services.NewSubscriptionRenewalEngine(service, config).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FailedCount)
+		assert.Equal(t, 1, result.InterruptedCount)
+	})
+
+	t.Run("claims nothing while the shared circuit breaker is open", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		expectNoRenewalsAwaitingReview(mockQuerier)
+
+		server := dsClient.NewFakeServer()
+		t.Cleanup(server.Close)
+		client, err := server.Client()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = client.Close() })
+		service := createSubscriptionService(ctrl, mockQuerier, client)
+
+		// The redemption processor opened the breaker; no renewals are claimed and the server is not called
+		mockQuerier.EXPECT().GetCircuitBreaker(gomock.Any(), services.DelegationServerCircuitBreaker).Return(db.CircuitBreaker{
+			Name:                services.DelegationServerCircuitBreaker,
+			State:               services.CircuitBreakerStateOpen,
+			ConsecutiveFailures: 3,
+		}, nil)
+
+		result, err := services.NewSubscriptionRenewalEngine(service, services.DefaultSubscriptionRenewalConfig()).Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.ProcessedCount)
+		assert.Empty(t, server.RedeemRequests())
+	})
+}
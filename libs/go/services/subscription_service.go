@@ -15,9 +15,11 @@ import (
 	"github.com/cyphera/cyphera-api/libs/go/helpers"
 	"github.com/cyphera/cyphera-api/libs/go/interfaces"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/proto"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/requests"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -31,6 +33,9 @@ type SubscriptionService struct {
 	paymentService       *PaymentService
 	customerService      *CustomerService
 	invoiceService       interfaces.InvoiceService
+	renewalConfig        SubscriptionRenewalConfig
+	splPayments          SplDelegatePayments     // Charges Solana subscriptions; nil when Solana is not configured
+	gasEstimates         *RedemptionGasEstimator // Estimates renewal redemption fees; nil when not configured
 	logger               *zap.Logger
 	lastRedemptionTxHash string // Stores the transaction hash from the last successful redemption
 }
@@ -43,6 +48,7 @@ func NewSubscriptionService(queries db.Querier, delegationClient *dsClient.Deleg
 		paymentService:   paymentService,
 		customerService:  customerService,
 		invoiceService:   invoiceService,
+		renewalConfig:    DefaultSubscriptionRenewalConfig(),
 		logger:           logger.Log,
 	}
 }
@@ -55,10 +61,36 @@ func (s *SubscriptionService) WithTransaction(tx pgx.Tx) *SubscriptionService {
 		paymentService:   s.paymentService,
 		customerService:  s.customerService,
 		invoiceService:   s.invoiceService,
+		renewalConfig:    s.renewalConfig,
+		splPayments:      s.splPayments,
+		gasEstimates:     s.gasEstimates,
 		logger:           s.logger,
 	}
 }
 
+// WithRenewalConfig creates a new subscription service instance that renews due subscriptions with the given settings
+func (s *SubscriptionService) WithRenewalConfig(config SubscriptionRenewalConfig) *SubscriptionService {
+	return &SubscriptionService{
+		queries:          s.queries,
+		delegationClient: s.delegationClient,
+		paymentService:   s.paymentService,
+		customerService:  s.customerService,
+		invoiceService:   s.invoiceService,
+		renewalConfig:    config,
+		splPayments:      s.splPayments,
+		gasEstimates:     s.gasEstimates,
+		logger:           s.logger,
+	}
+}
+
+// WithGasEstimates creates a new subscription service instance that estimates the network fee of each renewal
+// redemption before sending it and records the estimate with the renewal's payment
+func (s *SubscriptionService) WithGasEstimates(estimator *RedemptionGasEstimator) *SubscriptionService {
+	clone := s.WithRenewalConfig(s.renewalConfig)
+	clone.gasEstimates = estimator
+	return clone
+}
+
 // SubscriptionExistsError is a custom error for when a subscription already exists
 type SubscriptionExistsError struct {
 	Subscription *db.Subscription
@@ -524,9 +556,33 @@ func (s *SubscriptionService) CreateSubscription(ctx context.Context, tx pgx.Tx,
 		}
 	}
 
+	if err := s.recordNewSubscriptionMRR(ctx, qtx, subscription, params.Product); err != nil {
+		s.logger.Error("Failed to record new subscription MRR",
+			zap.String("subscription_id", subscription.ID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
 	return &subscription, nil
 }
 
+// recordNewSubscriptionMRR adds a new subscription to the MRR movements ledger. It runs in the
+// subscription's transaction, so the ledger and the subscription are created together.
+func (s *SubscriptionService) recordNewSubscriptionMRR(ctx context.Context, qtx db.Querier, subscription db.Subscription, product db.Product) error {
+	_, err := NewMRRMovementService(qtx).RecordMovement(ctx, params.RecordMRRMovementParams{
+		Subscription:    subscription,
+		Product:         &product,
+		Source:          business.MRRSourceCreate,
+		FromAmountCents: 0,
+		ToAmountCents:   int64(subscription.TotalAmountInCents),
+		OccurredAt:      subscription.CreatedAt.Time,
+	})
+	if err != nil && !errors.Is(err, ErrNoMRRMovement) {
+		return err
+	}
+	return nil
+}
+
 // ProcessInitialRedemption executes the initial token redemption for a new subscription
 func (s *SubscriptionService) ProcessInitialRedemption(ctx context.Context, tx pgx.Tx, redemptionParams params.InitialRedemptionParams) (*db.Subscription, error) {
 	var qtx db.Querier
@@ -573,10 +629,17 @@ func (s *SubscriptionService) ProcessInitialRedemption(ctx context.Context, tx p
 	}
 
 	// Execute the redemption
-	if s.delegationClient == nil {
-		return nil, fmt.Errorf("delegation client is not configured")
+	if err := s.initialPaymentUnavailable(redemptionParams.Network); err != nil {
+		return nil, err
 	}
-	txHash, err := s.delegationClient.RedeemDelegation(ctx, delegationBytes, executionObject)
+	if redemptionParams.Network.NetworkType == db.NetworkTypeSolana {
+		transfer := newSplDelegateTransfer(redemptionParams.DelegationData.Delegator, redemptionParams.DelegationData.Authority, executionObject)
+		if err := s.checkSplApprovalBinding(ctx, qtx, redemptionParams.Subscription, *transfer); err != nil {
+			return nil, fmt.Errorf("delegation redemption failed: %w", err)
+		}
+	}
+	txHash, err := s.redeemInitialPayment(ctx, redemptionParams.Network, delegationBytes,
+		redemptionParams.DelegationData.Delegator, redemptionParams.DelegationData.Authority, executionObject)
 	if err != nil {
 		s.logger.Error("Delegation redemption failed",
 			zap.Error(err),
@@ -791,11 +854,22 @@ func (s *SubscriptionService) CreateSubscriptionWithDelegation(ctx context.Conte
 	}
 
 	// STEP 2: Execute blockchain transaction FIRST (before any DB writes)
-	if s.delegationClient == nil {
-		return nil, fmt.Errorf("delegation client is not configured")
+	if err := s.initialPaymentUnavailable(createParams.Network); err != nil {
+		return nil, err
+	}
+
+	// Solana approvals are checked on-chain before anything is charged under them
+	var splApproval *business.VerifiedSplApproval
+	if createParams.Network.NetworkType == db.NetworkTypeSolana {
+		splApproval, err = s.verifySplApproval(ctx, createParams)
+		if err != nil {
+			s.logFailedSubscriptionAttempt(ctx, nil, createParams, normalizedAddress, err, "")
+			return nil, fmt.Errorf("approval verification failed: %w", err)
+		}
 	}
 
-	txHash, err := s.delegationClient.RedeemDelegation(ctx, delegationBytes, executionObject)
+	txHash, err := s.redeemInitialPayment(ctx, createParams.Network, delegationBytes,
+		createParams.DelegationData.Delegator, createParams.DelegationData.Authority, executionObject)
 	if err != nil {
 		s.logger.Error("Delegation redemption failed",
 			zap.Error(err),
@@ -819,6 +893,7 @@ func (s *SubscriptionService) CreateSubscriptionWithDelegation(ctx context.Conte
 		CreateParams:      createParams,
 		TransactionHash:   txHash,
 		NormalizedAddress: normalizedAddress,
+		SplApproval:       splApproval,
 	})
 	if err != nil {
 		// Transaction succeeded but DB operations failed - log with detailed context
@@ -875,6 +950,12 @@ func (s *SubscriptionService) CreateSubscriptionAfterPayment(ctx context.Context
 	}
 	trackProgress("delegation_data", delegationData.ID)
 
+	if afterPaymentParams.SplApproval != nil {
+		if err := s.bindSplApproval(ctx, tx, createParams, delegationData.ID, *afterPaymentParams.SplApproval); err != nil {
+			return nil, fmt.Errorf("failed to bind approval (tx: %s, created: %v): %w", txHash, createdEntities, err)
+		}
+	}
+
 	// STEP 3: Calculate subscription periods
 	periodStart, periodEnd, nextRedemption := helpers.CalculateSubscriptionPeriods(createParams.Product)
 
@@ -1442,101 +1523,125 @@ func (s *SubscriptionService) logFailedSubscriptionAttempt(
 	}
 }
 
-// ProcessDueSubscriptions finds and processes all subscriptions that are due for redemption
-// This method expects to be called within a transaction context
+// ProcessDueSubscriptions finds and processes all subscriptions that are due for redemption.
+// Due subscriptions are claimed under a lease, so overlapping runs never redeem the same period twice.
 func (s *SubscriptionService) ProcessDueSubscriptions(ctx context.Context) (*responses.ProcessDueSubscriptionsResult, error) {
 	s.logger.Info("Processing due subscriptions")
-	result := &responses.ProcessDueSubscriptionsResult{}
-	now := time.Now()
 
-	// Query for subscriptions due for redemption
-	nowPgType := pgtype.Timestamptz{Time: now, Valid: true}
-	subscriptions, err := s.queries.ListSubscriptionsDueForRedemption(ctx, nowPgType)
+	result, err := NewSubscriptionRenewalEngine(s, s.renewalConfig).Run(ctx)
 	if err != nil {
-		s.logger.Error("Failed to fetch subscriptions due for redemption", zap.Error(err))
-		return nil, fmt.Errorf("failed to fetch subscriptions: %w", err)
-	}
-
-	result.ProcessedCount = len(subscriptions)
-	if result.ProcessedCount == 0 {
-		s.logger.Info("No subscriptions found due for renewal")
-		return result, nil
-	}
-
-	s.logger.Info("Found subscriptions due for redemption",
-		zap.Int("count", result.ProcessedCount))
-
-	// Process each subscription
-	for i, subscription := range subscriptions {
-		s.logger.Info("Processing subscription",
-			zap.Int("current", i+1),
-			zap.Int("total", result.ProcessedCount),
-			zap.String("subscription_id", subscription.ID.String()),
-			zap.String("status", string(subscription.Status)))
-
-		// Skip non-processable statuses
-		if !(subscription.Status == db.SubscriptionStatusActive || subscription.Status == db.SubscriptionStatusOverdue) {
-			s.logger.Info("Skipping subscription with non-processable status",
-				zap.String("subscription_id", subscription.ID.String()),
-				zap.String("status", string(subscription.Status)))
-			continue
-		}
-
-		// Process the subscription
-		err := s.processSingleSubscription(ctx, s.queries, subscription)
-		if err != nil {
-			s.logger.Error("Failed to process subscription",
-				zap.String("subscription_id", subscription.ID.String()),
-				zap.Error(err))
-			result.FailedCount++
-		} else {
-			result.SuccessfulCount++
-		}
+		return nil, err
 	}
 
 	s.logger.Info("Completed processing due subscriptions",
 		zap.Int("total", result.ProcessedCount),
 		zap.Int("succeeded", result.SuccessfulCount),
-		zap.Int("failed", result.FailedCount))
+		zap.Int("failed", result.FailedCount),
+		zap.Int("deferred", result.DeferredCount),
+		zap.Int("interrupted", result.InterruptedCount),
+		zap.Int64("awaiting_review", result.AwaitingReviewCount))
 
 	return result, nil
 }
 
-// processSingleSubscription processes a single subscription for redemption
-func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx db.Querier, subscription db.ListSubscriptionsDueForRedemptionRow) error {
-	var subEvent db.SubscriptionEvent
+// renewalRedemption is a claimed renewal with everything needed to redeem and record it
+type renewalRedemption struct {
+	renewal        db.SubscriptionRenewal
+	subscription   db.Subscription
+	product        db.Product
+	customer       db.Customer
+	customerWallet db.CustomerWallet
+	productToken   db.GetProductTokenRow
+	delegation     []byte
+	execution      dsClient.ExecutionObject
+	// splTransfer charges the renewal on Solana, where there is no delegation to redeem
+	splTransfer *business.SplDelegateTransfer
+	// gasEstimate is the redemption's estimated network fee, made before it was sent
+	gasEstimate *responses.EstimateGasFeeResult
+	// periodAdvanced is true when the subscription was renewed after the renewal was claimed
+	periodAdvanced bool
+}
 
-	// Re-fetch subscription for idempotency check
-	currentSub, err := qtx.GetSubscription(ctx, subscription.ID)
-	if err != nil {
-		return fmt.Errorf("failed to re-fetch subscription: %w", err)
+// processSingleSubscription processes a single subscription for redemption under a claimed renewal.
+// A renewal that already holds a transaction hash was redeemed by an earlier run and is only recorded.
+func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx db.Querier, renewal db.SubscriptionRenewal, leaseOwner pgtype.Text) error {
+	redemption, err := s.prepareRenewalRedemption(ctx, qtx, renewal)
+	if err != nil || redemption == nil {
+		return err
 	}
 
-	// Check if already processed
-	if currentSub.Status == db.SubscriptionStatusCompleted {
-		s.logger.Info("Subscription already completed",
-			zap.String("subscription_id", currentSub.ID.String()))
-		return nil
+	if renewal.TransactionHash.Valid {
+		return s.resumeRenewalRedemption(ctx, qtx, redemption)
+	}
+	return s.redeemRenewal(ctx, qtx, redemption, leaseOwner)
+}
+
+// prepareRenewalRedemption loads what a claimed renewal needs to be redeemed. It returns nil when there is
+// nothing left to do, because the period was already renewed or the subscription can no longer be charged.
+func (s *SubscriptionService) prepareRenewalRedemption(ctx context.Context, qtx db.Querier, renewal db.SubscriptionRenewal) (*renewalRedemption, error) {
+	resuming := renewal.TransactionHash.Valid
+
+	// Re-fetch subscription for idempotency check
+	currentSub, err := qtx.GetSubscription(ctx, renewal.SubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-fetch subscription: %w", err)
+	}
+
+	// The period has moved on when the subscription was renewed after it was claimed
+	periodAdvanced := !currentSub.NextRedemptionDate.Valid || !currentSub.NextRedemptionDate.Time.Equal(renewal.PeriodDueAt.Time)
+
+	if resuming {
+		// Nothing is left to record once the redemption event exists
+		if _, err := qtx.GetSubscriptionRedemptionEventByTransactionHash(ctx, db.GetSubscriptionRedemptionEventByTransactionHashParams{
+			SubscriptionID:  currentSub.ID,
+			TransactionHash: renewal.TransactionHash,
+		}); err == nil {
+			s.logger.Info("Redemption already recorded for interrupted renewal",
+				zap.String("subscription_id", currentSub.ID.String()),
+				zap.String("tx_hash", renewal.TransactionHash.String))
+			return nil, nil
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to look up redemption event: %w", err)
+		}
+	} else {
+		// Check if already processed
+		if currentSub.Status == db.SubscriptionStatusCompleted {
+			s.logger.Info("Subscription already completed",
+				zap.String("subscription_id", currentSub.ID.String()))
+			return nil, nil
+		}
+
+		if periodAdvanced {
+			s.logger.Info("Subscription period already renewed",
+				zap.String("subscription_id", currentSub.ID.String()),
+				zap.String("idempotency_key", renewal.IdempotencyKey))
+			return nil, nil
+		}
+
+		// Skip non-processable statuses
+		if !(currentSub.Status == db.SubscriptionStatusActive || currentSub.Status == db.SubscriptionStatusOverdue) {
+			s.logger.Info("Skipping subscription with non-processable status",
+				zap.String("subscription_id", currentSub.ID.String()),
+				zap.String("status", string(currentSub.Status)))
+			return nil, nil
+		}
 	}
 
 	// Get required data
-	product, err := qtx.GetProductWithoutWorkspaceId(ctx, subscription.ProductID)
+	product, err := qtx.GetProductWithoutWorkspaceId(ctx, currentSub.ProductID)
 	if err != nil {
-		return fmt.Errorf("failed to get product: %w", err)
+		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
-	// Price is now embedded in product - no need to fetch separately
-	price := product // Use product as price for code that expects price variable
-
-	customer, err := qtx.GetCustomer(ctx, subscription.CustomerID)
+	customer, err := qtx.GetCustomer(ctx, currentSub.CustomerID)
 	if err != nil {
-		return fmt.Errorf("failed to get customer: %w", err)
+		return nil, fmt.Errorf("failed to get customer: %w", err)
 	}
 
 	// Get delegation data
-	delegationData, err := qtx.GetDelegationData(ctx, subscription.DelegationID)
+	delegationData, err := qtx.GetDelegationData(ctx, currentSub.DelegationID)
 	if err != nil {
-		return fmt.Errorf("failed to get delegation data: %w", err)
+		return nil, fmt.Errorf("failed to get delegation data: %w", err)
 	}
 
 	// Get merchant wallet for the product
@@ -1545,24 +1650,24 @@ func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx
 		WorkspaceID: product.WorkspaceID,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to get merchant wallet: %w", err)
+		return nil, fmt.Errorf("failed to get merchant wallet: %w", err)
 	}
 
-	customerWallet, err := qtx.GetCustomerWallet(ctx, subscription.CustomerWalletID.Bytes)
+	customerWallet, err := qtx.GetCustomerWallet(ctx, currentSub.CustomerWalletID.Bytes)
 	if err != nil {
-		return fmt.Errorf("failed to get customer wallet: %w", err)
+		return nil, fmt.Errorf("failed to get customer wallet: %w", err)
 	}
 
 	// Get product token info
-	productToken, err := qtx.GetProductToken(ctx, subscription.ProductTokenID)
+	productToken, err := qtx.GetProductToken(ctx, currentSub.ProductTokenID)
 	if err != nil {
-		return fmt.Errorf("failed to get product token: %w", err)
+		return nil, fmt.Errorf("failed to get product token: %w", err)
 	}
 
 	// Prepare for redemption
 	caveatsJSON, err := json.Marshal(delegationData.Caveats)
 	if err != nil {
-		return fmt.Errorf("failed to marshal caveats: %w", err)
+		return nil, fmt.Errorf("failed to marshal caveats: %w", err)
 	}
 
 	delegationForRedemption := dsClient.DelegationData{
@@ -1576,102 +1681,293 @@ func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx
 
 	delegationBytes, err := json.Marshal(delegationForRedemption)
 	if err != nil {
-		return fmt.Errorf("failed to marshal delegation: %w", err)
+		return nil, fmt.Errorf("failed to marshal delegation: %w", err)
 	}
 
 	// Get token details
 	token, err := qtx.GetToken(ctx, productToken.TokenID)
 	if err != nil {
-		return fmt.Errorf("failed to get token: %w", err)
+		return nil, fmt.Errorf("failed to get token: %w", err)
 	}
 
-	// Create execution object
-	executionObject := dsClient.ExecutionObject{
-		MerchantAddress:      merchantWallet.WalletAddress,
-		TokenContractAddress: token.ContractAddress,
-		TokenAmount:          int64(subscription.TokenAmount),
-		TokenDecimals:        token.Decimals,
-		ChainID:              uint32(productToken.ChainID),
-		NetworkName:          productToken.NetworkName,
+	redemption := &renewalRedemption{
+		renewal:        renewal,
+		subscription:   currentSub,
+		product:        product,
+		customer:       customer,
+		customerWallet: customerWallet,
+		productToken:   productToken,
+		delegation:     delegationBytes,
+		execution: dsClient.ExecutionObject{
+			MerchantAddress:      merchantWallet.WalletAddress,
+			TokenContractAddress: token.ContractAddress,
+			TokenAmount:          int64(currentSub.TokenAmount),
+			TokenDecimals:        token.Decimals,
+			ChainID:              uint32(productToken.ChainID),
+			NetworkName:          productToken.NetworkName,
+		},
+		periodAdvanced: periodAdvanced,
+	}
+	if productToken.NetworkType == string(db.NetworkTypeSolana) {
+		redemption.splTransfer = newSplDelegateTransfer(delegationData.Delegator, delegationData.Authority, redemption.execution)
+	}
+	return redemption, nil
+}
+
+// resumeRenewalRedemption records a redemption an interrupted run sent but did not finish recording,
+// once the delegation server confirms the transaction was mined
+func (s *SubscriptionService) resumeRenewalRedemption(ctx context.Context, qtx db.Querier, redemption *renewalRedemption) error {
+	if redemption.splTransfer != nil {
+		return s.resumeSplRenewalRedemption(ctx, qtx, redemption)
+	}
+
+	txHash := redemption.renewal.TransactionHash.String
+	logFields := []zap.Field{
+		zap.String("subscription_id", redemption.subscription.ID.String()),
+		zap.String("tx_hash", txHash),
+	}
+
+	if s.delegationClient != nil {
+		redemptionStatus, err := s.delegationClient.GetRedemptionStatus(ctx, txHash, redemption.execution.ChainID, redemption.execution.NetworkName)
+		switch {
+		case err != nil:
+			// The hash was only stored after the server confirmed the redemption, so it is recorded anyway
+			s.logger.Warn("Could not check the status of a resumed redemption", append(logFields, zap.Error(err))...)
+		case redemptionStatus.Status == proto.RedemptionStatus_REDEMPTION_STATUS_PENDING:
+			// The renewal stays redeemed and resumes once its lease runs out
+			return fmt.Errorf("redemption %s is not mined yet", txHash)
+		case redemptionStatus.Status == proto.RedemptionStatus_REDEMPTION_STATUS_FAILED:
+			err := fmt.Errorf("redemption %s reverted on-chain: %w", txHash, errRenewalReverted)
+			s.handleFailedRenewalRedemption(ctx, qtx, redemption.subscription, err)
+			return err
+		}
+	}
+
+	s.logger.Info("Resuming interrupted renewal from its recorded redemption", logFields...)
+	return s.recordRenewalRedemption(ctx, qtx, redemption, txHash, !redemption.periodAdvanced)
+}
+
+// redeemRenewal redeems a prepared renewal on its own and records the redemption
+func (s *SubscriptionService) redeemRenewal(ctx context.Context, qtx db.Querier, redemption *renewalRedemption, leaseOwner pgtype.Text) error {
+	if redemption.splTransfer != nil {
+		return s.redeemSplRenewal(ctx, qtx, redemption, leaseOwner)
 	}
 
 	// Execute redemption
 	if s.delegationClient == nil {
 		return fmt.Errorf("delegation client is not configured")
 	}
-	txHash, err := s.delegationClient.RedeemDelegation(ctx, delegationBytes, executionObject)
+
+	// Dry-run the redemption first so one that would revert is never sent
+	if err := s.simulateRenewalRedemption(ctx, redemption); err != nil {
+		s.handleFailedRenewalRedemption(ctx, qtx, redemption.subscription, err)
+		return fmt.Errorf("redemption failed: %w", err)
+	}
+	redemption.gasEstimate = s.gasEstimates.Estimate(ctx, redemption.productToken.NetworkID, redemption.delegation, redemption.execution)
+
+	// Record that the redemption is in flight; if this run stops before it returns, the renewal needs review
+	if _, err := qtx.MarkSubscriptionRenewalRedeeming(ctx, db.MarkSubscriptionRenewalRedeemingParams{
+		ID:         redemption.renewal.ID,
+		LeaseOwner: leaseOwner,
+	}); err != nil {
+		return fmt.Errorf("failed to start renewal redemption: %w", err)
+	}
+
+	txHash, err := s.delegationClient.RedeemDelegation(ctx, redemption.delegation, redemption.execution)
 	if err != nil {
-		// Update subscription status to overdue on failure
-		_, updateErr := qtx.UpdateSubscriptionStatus(ctx, db.UpdateSubscriptionStatusParams{
-			ID:     subscription.ID,
-			Status: db.SubscriptionStatusOverdue,
+		var redemptionErr *dsClient.RedemptionError
+		if errors.As(err, &redemptionErr) && redemptionErr.OutcomeUnknown() {
+			return fmt.Errorf("redemption was sent but not confirmed: %v: %w", err, errRenewalOutcomeUnknown)
+		}
+		s.handleFailedRenewalRedemption(ctx, qtx, redemption.subscription, err)
+		return fmt.Errorf("redemption failed: %w", err)
+	}
+
+	return s.completeRenewalRedemption(ctx, qtx, redemption, leaseOwner, txHash)
+}
+
+// redeemRenewalBatch redeems prepared renewals on one network in a single transaction and records each
+// redemption. The returned errors line up with redemptions; nil means the renewal was redeemed and recorded.
+func (s *SubscriptionService) redeemRenewalBatch(ctx context.Context, qtx db.Querier, redemptions []*renewalRedemption, leaseOwner pgtype.Text) []error {
+	errs := make([]error, len(redemptions))
+	if s.delegationClient == nil {
+		for i := range redemptions {
+			errs[i] = fmt.Errorf("delegation client is not configured")
+		}
+		return errs
+	}
+
+	// Each redemption is marked in flight before the batch is sent, like a single redemption
+	var sent []int
+	var batch []dsClient.BatchRedemption
+	for i, redemption := range redemptions {
+		redemption.gasEstimate = s.gasEstimates.Estimate(ctx, redemption.productToken.NetworkID, redemption.delegation, redemption.execution)
+		if _, err := qtx.MarkSubscriptionRenewalRedeeming(ctx, db.MarkSubscriptionRenewalRedeemingParams{
+			ID:         redemption.renewal.ID,
+			LeaseOwner: leaseOwner,
+		}); err != nil {
+			errs[i] = fmt.Errorf("failed to start renewal redemption: %w", err)
+			continue
+		}
+		sent = append(sent, i)
+		batch = append(batch, dsClient.BatchRedemption{
+			ReferenceID: redemption.renewal.ID.String(),
+			Signature:   redemption.delegation,
+			Execution:   redemption.execution,
 		})
-		if updateErr != nil {
-			s.logger.Error("Failed to update subscription status",
-				zap.Error(updateErr),
-				zap.String("subscription_id", subscription.ID.String()))
+	}
+	if len(batch) == 0 {
+		return errs
+	}
+
+	results, err := s.delegationClient.BatchRedeemDelegations(ctx, batch)
+	if err != nil {
+		for _, i := range sent {
+			if errors.Is(err, dsClient.ErrUnsupportedByServer) {
+				// Nothing was sent, so the renewals fail and are retried
+				errs[i] = fmt.Errorf("batch redemption failed: %w", err)
+			} else {
+				errs[i] = fmt.Errorf("batch redemption failed: %v: %w", err, errRenewalOutcomeUnknown)
+			}
 		}
+		return errs
+	}
+
+	for j, result := range results {
+		i := sent[j]
+		redemption := redemptions[i]
+
+		if result.Error != nil {
+			if result.Error.OutcomeUnknown() {
+				errs[i] = fmt.Errorf("redemption was sent but not confirmed: %v: %w", result.Error, errRenewalOutcomeUnknown)
+				continue
+			}
+			s.handleFailedRenewalRedemption(ctx, qtx, redemption.subscription, result.Error)
+			errs[i] = fmt.Errorf("redemption failed: %w", result.Error)
+			continue
+		}
+
+		errs[i] = s.completeRenewalRedemption(ctx, qtx, redemption, leaseOwner, result.TransactionHash)
+	}
+	return errs
+}
+
+// simulateRenewalRedemption dry-runs a renewal redemption and returns the reason it would fail.
+// Simulation is best effort: when the server cannot simulate, the redemption is sent anyway.
+func (s *SubscriptionService) simulateRenewalRedemption(ctx context.Context, redemption *renewalRedemption) error {
+	result, err := s.delegationClient.SimulateRedemption(ctx, redemption.delegation, redemption.execution)
+	if err != nil {
+		s.logger.Warn("Could not simulate renewal redemption, sending it without a dry run",
+			zap.String("subscription_id", redemption.subscription.ID.String()),
+			zap.Error(err))
+		return nil
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// completeRenewalRedemption stores a renewal's redemption transaction and records the redemption
+func (s *SubscriptionService) completeRenewalRedemption(ctx context.Context, qtx db.Querier, redemption *renewalRedemption, leaseOwner pgtype.Text, txHash string) error {
+	if _, err := qtx.MarkSubscriptionRenewalRedeemed(ctx, db.MarkSubscriptionRenewalRedeemedParams{
+		TransactionHash: pgtype.Text{String: txHash, Valid: true},
+		ID:              redemption.renewal.ID,
+		LeaseOwner:      leaseOwner,
+	}); err != nil {
+		return fmt.Errorf("failed to record redemption %s: %v: %w", txHash, err, errRenewalOutcomeUnknown)
+	}
+
+	return s.recordRenewalRedemption(ctx, qtx, redemption, txHash, true)
+}
+
+// recordRenewalRedemption records a prepared renewal's redemption
+func (s *SubscriptionService) recordRenewalRedemption(ctx context.Context, qtx db.Querier, redemption *renewalRedemption, txHash string, advancePeriod bool) error {
+	return s.recordSubscriptionRedemption(ctx, qtx, redemption.renewal, redemption.subscription, redemption.product,
+		redemption.customer, redemption.customerWallet, redemption.productToken, txHash, redemption.gasEstimate, advancePeriod)
+}
+
+// handleFailedRenewalRedemption marks a subscription overdue after a failed redemption and opens its
+// invoice for the period. Retryable failures, such as an unreachable network, leave the subscription as
+// it is, since the renewal is retried once its lease runs out.
+func (s *SubscriptionService) handleFailedRenewalRedemption(ctx context.Context, qtx db.Querier, currentSub db.Subscription, redemptionErr error) {
+	var structuredErr *dsClient.RedemptionError
+	if errors.As(redemptionErr, &structuredErr) && structuredErr.Retryable {
+		s.logger.Warn("Renewal redemption failed with a retryable error",
+			zap.String("subscription_id", currentSub.ID.String()),
+			zap.Error(redemptionErr))
+		return
+	}
+
+	// Update subscription status to overdue on failure
+	_, updateErr := qtx.UpdateSubscriptionStatus(ctx, db.UpdateSubscriptionStatusParams{
+		ID:     currentSub.ID,
+		Status: db.SubscriptionStatusOverdue,
+	})
+	if updateErr != nil {
+		s.logger.Error("Failed to update subscription status",
+			zap.Error(updateErr),
+			zap.String("subscription_id", currentSub.ID.String()))
+	}
+	// Check if there's an open invoice for this period and keep it open
+	if s.invoiceService != nil {
+		periodStart := currentSub.CurrentPeriodStart.Time
+		periodEnd := currentSub.CurrentPeriodEnd.Time
 		
-		// Check if there's an open invoice for this period and keep it open
-		if s.invoiceService != nil {
-			periodStart := currentSub.CurrentPeriodStart.Time
-			periodEnd := currentSub.CurrentPeriodEnd.Time
-			
-			// Find any existing invoice for this period
-			invoices, listErr := s.queries.ListInvoicesBySubscription(ctx, db.ListInvoicesBySubscriptionParams{
-				WorkspaceID:    currentSub.WorkspaceID,
-				SubscriptionID: pgtype.UUID{Bytes: subscription.ID, Valid: true},
-				Limit:          5,
-				Offset:         0,
-			})
-			if listErr == nil {
-				for _, inv := range invoices {
-					// Check if this invoice is for the current period
-					lineItems, itemErr := s.queries.GetInvoiceLineItems(ctx, inv.ID)
-					if itemErr == nil {
-						for _, item := range lineItems {
-							if item.PeriodStart.Valid && item.PeriodEnd.Valid &&
-								item.PeriodStart.Time.Equal(periodStart) &&
-								item.PeriodEnd.Time.Equal(periodEnd) {
-								// Found the invoice for this period
-								if inv.Status == "draft" {
-									// Update to open status since payment was attempted
-									_, _ = s.queries.UpdateInvoiceStatus(ctx, db.UpdateInvoiceStatusParams{
-										ID:          inv.ID,
-										WorkspaceID: currentSub.WorkspaceID,
-										Status:      "open",
-									})
-									s.logger.Info("Updated invoice status to open after failed payment",
-										zap.String("invoice_id", inv.ID.String()),
-										zap.String("subscription_id", subscription.ID.String()))
-								}
-								break
+		// Find any existing invoice for this period
+		invoices, listErr := s.queries.ListInvoicesBySubscription(ctx, db.ListInvoicesBySubscriptionParams{
+			WorkspaceID:    currentSub.WorkspaceID,
+			SubscriptionID: pgtype.UUID{Bytes: currentSub.ID, Valid: true},
+			Limit:          5,
+			Offset:         0,
+		})
+		if listErr == nil {
+			for _, inv := range invoices {
+				// Check if this invoice is for the current period
+				lineItems, itemErr := s.queries.GetInvoiceLineItems(ctx, inv.ID)
+				if itemErr == nil {
+					for _, item := range lineItems {
+						if item.PeriodStart.Valid && item.PeriodEnd.Valid &&
+							item.PeriodStart.Time.Equal(periodStart) &&
+							item.PeriodEnd.Time.Equal(periodEnd) {
+							// Found the invoice for this period
+							if inv.Status == "draft" {
+								// Update to open status since payment was attempted
+								_, _ = s.queries.UpdateInvoiceStatus(ctx, db.UpdateInvoiceStatusParams{
+									ID:          inv.ID,
+									WorkspaceID: currentSub.WorkspaceID,
+									Status:      "open",
+								})
+								s.logger.Info("Updated invoice status to open after failed payment",
+									zap.String("invoice_id", inv.ID.String()),
+									zap.String("subscription_id", currentSub.ID.String()))
 							}
+							break
 						}
 					}
 				}
 			}
 		}
-		
-		return fmt.Errorf("redemption failed: %w", err)
 	}
+}
 
-	// Note: Subscription event will be created later before payment creation
-
+// advanceSubscriptionPeriod counts a redemption against the subscription and moves it to its next period,
+// completing it once the term limit is reached
+func (s *SubscriptionService) advanceSubscriptionPeriod(ctx context.Context, qtx db.Querier, subscription db.Subscription, price db.Product) error {
 	// Update subscription with proper term length validation
 	var nextRedemptionDate pgtype.Timestamptz
 	if price.PriceType == db.PriceTypeRecurring {
 		// CRITICAL BUG FIX: Check if subscription has reached its term limit
-		// currentSub.TotalRedemptions will be incremented by IncrementSubscriptionRedemption below
+		// subscription.TotalRedemptions will be incremented by IncrementSubscriptionRedemption below
 		// so we check if the NEXT redemption would exceed the limit
-		if currentSub.TotalRedemptions+1 >= price.TermLength.Int32 {
+		if subscription.TotalRedemptions+1 >= price.TermLength.Int32 {
 			s.logger.Info("Subscription reached maximum periods, marking as completed",
 				zap.String("subscription_id", subscription.ID.String()),
-				zap.Int32("current_redemptions", currentSub.TotalRedemptions),
+				zap.Int32("current_redemptions", subscription.TotalRedemptions),
 				zap.Int32("max_periods", price.TermLength.Int32))
 
 			// Mark as completed - reached maximum periods
-			_, err = qtx.UpdateSubscriptionStatus(ctx, db.UpdateSubscriptionStatusParams{
+			_, err := qtx.UpdateSubscriptionStatus(ctx, db.UpdateSubscriptionStatusParams{
 				ID:     subscription.ID,
 				Status: db.SubscriptionStatusCompleted,
 			})
@@ -1697,13 +1993,13 @@ func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx
 
 			s.logger.Info("Subscription continuing to next period",
 				zap.String("subscription_id", subscription.ID.String()),
-				zap.Int32("current_redemptions", currentSub.TotalRedemptions),
+				zap.Int32("current_redemptions", subscription.TotalRedemptions),
 				zap.Int32("max_periods", price.TermLength.Int32),
 				zap.Time("next_redemption", nextDate))
 			
 			// Generate invoice for next period
 			if s.invoiceService != nil {
-				nextPeriodStart := currentSub.CurrentPeriodEnd.Time
+				nextPeriodStart := subscription.CurrentPeriodEnd.Time
 				nextPeriodEnd := nextDate
 				
 				// Create a draft invoice for the next period
@@ -1726,7 +2022,7 @@ func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx
 		}
 	} else {
 		// One-time price, mark as completed
-		_, err = qtx.UpdateSubscriptionStatus(ctx, db.UpdateSubscriptionStatusParams{
+		_, err := qtx.UpdateSubscriptionStatus(ctx, db.UpdateSubscriptionStatusParams{
 			ID:     subscription.ID,
 			Status: db.SubscriptionStatusCompleted,
 		})
@@ -1740,7 +2036,7 @@ func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx
 		nextRedemptionDate = pgtype.Timestamptz{Valid: false}
 	}
 
-	_, err = qtx.IncrementSubscriptionRedemption(ctx, db.IncrementSubscriptionRedemptionParams{
+	_, err := qtx.IncrementSubscriptionRedemption(ctx, db.IncrementSubscriptionRedemptionParams{
 		ID:                 subscription.ID,
 		TotalAmountInCents: price.UnitAmountInPennies,
 		NextRedemptionDate: nextRedemptionDate,
@@ -1748,6 +2044,33 @@ func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx
 	if err != nil {
 		return fmt.Errorf("failed to update subscription: %w", err)
 	}
+	return nil
+}
+
+// recordSubscriptionRedemption moves a redeemed subscription to its next period and records the redemption
+// event, payment and invoice, with the redemption's gas estimate when one was made. advancePeriod is false when
+// an interrupted run already moved the period on.
+func (s *SubscriptionService) recordSubscriptionRedemption(
+	ctx context.Context,
+	qtx db.Querier,
+	renewal db.SubscriptionRenewal,
+	subscription db.Subscription,
+	product db.Product,
+	customer db.Customer,
+	customerWallet db.CustomerWallet,
+	productToken db.GetProductTokenRow,
+	txHash string,
+	gasEstimate *responses.EstimateGasFeeResult,
+	advancePeriod bool,
+) error {
+	var subEvent db.SubscriptionEvent
+	price := product // Use product as price for code that expects price variable
+
+	if advancePeriod {
+		if err := s.advanceSubscriptionPeriod(ctx, qtx, subscription, price); err != nil {
+			return err
+		}
+	}
 
 	// Create subscription event in database first (similar to ProcessInitialRedemption)
 	eventMetadata := map[string]interface{}{
@@ -1763,6 +2086,7 @@ func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx
 		"redemption_time":   time.Now().Unix(),
 		"subscription_type": string(product.PriceType),
 		"tx_hash":           txHash,
+		"idempotency_key":   renewal.IdempotencyKey,
 	}
 
 	metadataBytes, err := json.Marshal(eventMetadata)
@@ -1789,7 +2113,7 @@ func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx
 
 	payment, err := s.paymentService.CreatePaymentFromSubscriptionEvent(ctx, params.CreatePaymentFromSubscriptionEventParams{
 		SubscriptionEvent: &subEvent,
-		Subscription:      &currentSub,
+		Subscription:      &subscription,
 		Product:           &product,
 		Customer:          &customer,
 		TransactionHash:   txHash,
@@ -1805,9 +2129,11 @@ func (s *SubscriptionService) processSingleSubscription(ctx context.Context, qtx
 			zap.Error(err),
 			zap.String("subscription_id", subscription.ID.String()))
 	} else {
+		s.gasEstimates.Record(ctx, payment, productToken.NetworkID, gasEstimate)
+
 		// Generate invoice for this payment period
-		periodStart := currentSub.CurrentPeriodStart.Time
-		periodEnd := currentSub.CurrentPeriodEnd.Time
+		periodStart := subscription.CurrentPeriodStart.Time
+		periodEnd := subscription.CurrentPeriodEnd.Time
 		
 		// Create invoice and mark it as paid since payment was successful
 		if s.invoiceService != nil {
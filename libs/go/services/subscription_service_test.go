@@ -33,6 +33,17 @@ func createSubscriptionService(ctrl *gomock.Controller, mockQuerier *mocks.MockQ
 	return services.NewSubscriptionService(mockQuerier, mockDelegationClient, paymentService, customerService, mockInvoiceService)
 }
 
+// expectRenewalClaim mocks a processing run that claims the given due renewals and nothing to resume
+func expectRenewalClaim(mockQuerier *mocks.MockQuerier, renewals ...db.SubscriptionRenewal) {
+	mockQuerier.EXPECT().ClaimRedeemedSubscriptionRenewals(gomock.Any(), gomock.Any()).Return([]db.SubscriptionRenewal{}, nil)
+	mockQuerier.EXPECT().ClaimDueSubscriptionRenewals(gomock.Any(), gomock.Any()).Return(renewals, nil)
+}
+
+// expectNoRenewalsAwaitingReview mocks the count of parked renewals that ends every renewal run
+func expectNoRenewalsAwaitingReview(mockQuerier *mocks.MockQuerier) {
+	mockQuerier.EXPECT().CountSubscriptionRenewals(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
+}
+
 // TestProcessSingleSubscription tests the processSingleSubscription method via ProcessDueSubscriptions
 // Since processSingleSubscription is not exported, we test it through ProcessDueSubscriptions
 // This test focuses on the edge cases and error handling within processSingleSubscription
@@ -41,6 +52,7 @@ func TestSubscriptionService_ProcessSingleSubscription(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	expectNoRenewalsAwaitingReview(mockQuerier)
 	// Use nil delegation client since we're testing error paths that don't reach delegation
 	service := createSubscriptionService(ctrl, mockQuerier, nil)
 
@@ -52,6 +64,15 @@ func TestSubscriptionService_ProcessSingleSubscription(t *testing.T) {
 	customerWalletID := uuid.New()
 	walletID := uuid.New()
 	workspaceID := uuid.New()
+	periodDueAt := pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+	renewal := db.SubscriptionRenewal{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		IdempotencyKey: "renewal:" + subscriptionID.String(),
+		PeriodDueAt:    periodDueAt,
+		Status:         services.SubscriptionRenewalStatusClaimed,
+		Attempts:       1,
+	}
 
 	tests := []struct {
 		name      string
@@ -62,20 +83,8 @@ func TestSubscriptionService_ProcessSingleSubscription(t *testing.T) {
 		{
 			name: "handles already completed subscription",
 			setupMock: func() {
-				// Mock getting due subscriptions
-				dueSubscriptions := []db.ListSubscriptionsDueForRedemptionRow{
-					{
-						ID:               subscriptionID,
-						CustomerID:       customerID,
-						ProductID:        productID,
-						ProductTokenID:   productTokenID,
-						TokenAmount:      1000000,
-						DelegationID:     delegationID,
-						CustomerWalletID: pgtype.UUID{Bytes: customerWalletID, Valid: true},
-						Status:           db.SubscriptionStatusActive,
-					},
-				}
-				mockQuerier.EXPECT().ListSubscriptionsDueForRedemption(gomock.Any(), gomock.Any()).Return(dueSubscriptions, nil)
+				// Mock claiming the due renewal
+				expectRenewalClaim(mockQuerier, renewal)
 
 				// Mock re-fetching subscription - already completed
 				currentSub := db.Subscription{
@@ -91,67 +100,47 @@ func TestSubscriptionService_ProcessSingleSubscription(t *testing.T) {
 				}
 				mockQuerier.EXPECT().GetSubscription(gomock.Any(), subscriptionID).Return(currentSub, nil)
 
-				// No further mocks needed - should return early
+				// No further processing - the renewal is closed as succeeded
+				mockQuerier.EXPECT().CompleteSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(db.SubscriptionRenewal{}, nil)
 			},
 			wantErr: false,
 		},
 		{
 			name: "handles subscription fetch error",
 			setupMock: func() {
-				// Mock getting due subscriptions
-				dueSubscriptions := []db.ListSubscriptionsDueForRedemptionRow{
-					{
-						ID:               subscriptionID,
-						CustomerID:       customerID,
-						ProductID:        productID,
-						ProductTokenID:   productTokenID,
-						TokenAmount:      1000000,
-						DelegationID:     delegationID,
-						CustomerWalletID: pgtype.UUID{Bytes: customerWalletID, Valid: true},
-						Status:           db.SubscriptionStatusActive,
-					},
-				}
-				mockQuerier.EXPECT().ListSubscriptionsDueForRedemption(gomock.Any(), gomock.Any()).Return(dueSubscriptions, nil)
+				// Mock claiming the due renewal
+				expectRenewalClaim(mockQuerier, renewal)
 
 				// Mock subscription fetch error
 				mockQuerier.EXPECT().GetSubscription(gomock.Any(), subscriptionID).Return(db.Subscription{}, errors.New("subscription fetch error"))
+				mockQuerier.EXPECT().FailSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(int64(1), nil)
 			},
 			wantErr: false, // ProcessDueSubscriptions handles individual failures gracefully
 		},
 		{
 			name: "handles product fetch error",
 			setupMock: func() {
-				// Mock getting due subscriptions
-				dueSubscriptions := []db.ListSubscriptionsDueForRedemptionRow{
-					{
-						ID:               subscriptionID,
-						CustomerID:       customerID,
-						ProductID:        productID,
-						ProductTokenID:   productTokenID,
-						TokenAmount:      1000000,
-						DelegationID:     delegationID,
-						CustomerWalletID: pgtype.UUID{Bytes: customerWalletID, Valid: true},
-						Status:           db.SubscriptionStatusActive,
-					},
-				}
-				mockQuerier.EXPECT().ListSubscriptionsDueForRedemption(gomock.Any(), gomock.Any()).Return(dueSubscriptions, nil)
+				// Mock claiming the due renewal
+				expectRenewalClaim(mockQuerier, renewal)
 
 				// Mock re-fetching subscription
 				currentSub := db.Subscription{
-					ID:               subscriptionID,
-					CustomerID:       customerID,
-					ProductID:        productID,
-					ProductTokenID:   productTokenID,
-					TokenAmount:      1000000,
-					DelegationID:     delegationID,
-					CustomerWalletID: pgtype.UUID{Bytes: customerWalletID, Valid: true},
-					Status:           db.SubscriptionStatusActive,
-					TotalRedemptions: 0,
+					ID:                 subscriptionID,
+					CustomerID:         customerID,
+					ProductID:          productID,
+					ProductTokenID:     productTokenID,
+					TokenAmount:        1000000,
+					DelegationID:       delegationID,
+					CustomerWalletID:   pgtype.UUID{Bytes: customerWalletID, Valid: true},
+					Status:             db.SubscriptionStatusActive,
+					NextRedemptionDate: periodDueAt,
+					TotalRedemptions:   0,
 				}
 				mockQuerier.EXPECT().GetSubscription(gomock.Any(), subscriptionID).Return(currentSub, nil)
 
 				// Mock product fetch error
 				mockQuerier.EXPECT().GetProductWithoutWorkspaceId(gomock.Any(), productID).Return(db.Product{}, errors.New("product fetch error"))
+				mockQuerier.EXPECT().FailSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(int64(1), nil)
 			},
 			wantErr: false, // ProcessDueSubscriptions handles individual failures gracefully
 		},
@@ -159,32 +148,21 @@ func TestSubscriptionService_ProcessSingleSubscription(t *testing.T) {
 		{
 			name: "handles delegation data fetch error",
 			setupMock: func() {
-				// Mock getting due subscriptions
-				dueSubscriptions := []db.ListSubscriptionsDueForRedemptionRow{
-					{
-						ID:               subscriptionID,
-						CustomerID:       customerID,
-						ProductID:        productID,
-						ProductTokenID:   productTokenID,
-						TokenAmount:      1000000,
-						DelegationID:     delegationID,
-						CustomerWalletID: pgtype.UUID{Bytes: customerWalletID, Valid: true},
-						Status:           db.SubscriptionStatusActive,
-					},
-				}
-				mockQuerier.EXPECT().ListSubscriptionsDueForRedemption(gomock.Any(), gomock.Any()).Return(dueSubscriptions, nil)
+				// Mock claiming the due renewal
+				expectRenewalClaim(mockQuerier, renewal)
 
 				// Mock re-fetching subscription
 				currentSub := db.Subscription{
-					ID:               subscriptionID,
-					CustomerID:       customerID,
-					ProductID:        productID,
-					ProductTokenID:   productTokenID,
-					TokenAmount:      1000000,
-					DelegationID:     delegationID,
-					CustomerWalletID: pgtype.UUID{Bytes: customerWalletID, Valid: true},
-					Status:           db.SubscriptionStatusActive,
-					TotalRedemptions: 0,
+					ID:                 subscriptionID,
+					CustomerID:         customerID,
+					ProductID:          productID,
+					ProductTokenID:     productTokenID,
+					TokenAmount:        1000000,
+					DelegationID:       delegationID,
+					CustomerWalletID:   pgtype.UUID{Bytes: customerWalletID, Valid: true},
+					Status:             db.SubscriptionStatusActive,
+					NextRedemptionDate: periodDueAt,
+					TotalRedemptions:   0,
 				}
 				mockQuerier.EXPECT().GetSubscription(gomock.Any(), subscriptionID).Return(currentSub, nil)
 
@@ -209,6 +187,7 @@ func TestSubscriptionService_ProcessSingleSubscription(t *testing.T) {
 
 				// Mock delegation data fetch error
 				mockQuerier.EXPECT().GetDelegationData(gomock.Any(), delegationID).Return(db.DelegationDatum{}, errors.New("delegation data fetch error"))
+				mockQuerier.EXPECT().FailSubscriptionRenewal(gomock.Any(), gomock.Any()).Return(int64(1), nil)
 			},
 			wantErr: false, // ProcessDueSubscriptions handles individual failures gracefully
 		},
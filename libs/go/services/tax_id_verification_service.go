@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	// validTaxIDCacheTTL is how long a confirmed registration is trusted before checking again
+	validTaxIDCacheTTL = 30 * 24 * time.Hour
+	// invalidTaxIDCacheTTL is shorter so newly registered numbers are picked up quickly
+	invalidTaxIDCacheTTL = 24 * time.Hour
+)
+
+var (
+	// ErrTaxIDNotSupported is returned for tax IDs of countries the registry does not cover
+	ErrTaxIDNotSupported = errors.New("tax ID country is not supported by the registry")
+	// ErrInvalidTaxIDFormat is returned when a tax ID cannot be a valid number for its country
+	ErrInvalidTaxIDFormat = errors.New("tax ID format is invalid")
+	// ErrCustomerHasNoTaxID is returned when verifying a customer without a tax ID
+	ErrCustomerHasNoTaxID = errors.New("customer has no tax ID")
+)
+
+// vatNumberFormats holds the VAT number format of every VIES member state, without the country prefix
+var vatNumberFormats = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^U\d{8}$`),
+	"BE": regexp.MustCompile(`^[01]\d{9}$`),
+	"BG": regexp.MustCompile(`^\d{9,10}$`),
+	"CY": regexp.MustCompile(`^\d{8}[A-Z]$`),
+	"CZ": regexp.MustCompile(`^\d{8,10}$`),
+	"DE": regexp.MustCompile(`^\d{9}$`),
+	"DK": regexp.MustCompile(`^\d{8}$`),
+	"EE": regexp.MustCompile(`^\d{9}$`),
+	"EL": regexp.MustCompile(`^\d{9}$`),
+	"ES": regexp.MustCompile(`^[0-9A-Z]\d{7}[0-9A-Z]$`),
+	"FI": regexp.MustCompile(`^\d{8}$`),
+	"FR": regexp.MustCompile(`^[0-9A-Z]{2}\d{9}$`),
+	"HR": regexp.MustCompile(`^\d{11}$`),
+	"HU": regexp.MustCompile(`^\d{8}$`),
+	"IE": regexp.MustCompile(`^(\d{7}[A-W][A-I]?|\d[A-Z+*]\d{5}[A-W])$`),
+	"IT": regexp.MustCompile(`^\d{11}$`),
+	"LT": regexp.MustCompile(`^(\d{9}|\d{12})$`),
+	"LU": regexp.MustCompile(`^\d{8}$`),
+	"LV": regexp.MustCompile(`^\d{11}$`),
+	"MT": regexp.MustCompile(`^\d{8}$`),
+	"NL": regexp.MustCompile(`^\d{9}B\d{2}$`),
+	"PL": regexp.MustCompile(`^\d{10}$`),
+	"PT": regexp.MustCompile(`^\d{9}$`),
+	"RO": regexp.MustCompile(`^\d{2,10}$`),
+	"SE": regexp.MustCompile(`^\d{12}$`),
+	"SI": regexp.MustCompile(`^\d{8}$`),
+	"SK": regexp.MustCompile(`^\d{10}$`),
+	"XI": regexp.MustCompile(`^(\d{9}|\d{12}|GD\d{3}|HA\d{3})$`),
+}
+
+// TaxIDVerificationService checks customer tax IDs against an official registry. Every answer is
+// stored: it serves as a cache until it expires and as evidence for the invoices that relied on it.
+type TaxIDVerificationService struct {
+	queries  db.Querier
+	registry interfaces.TaxIDRegistry
+	logger   *zap.Logger
+}
+
+// NewTaxIDVerificationService creates a new tax ID verification service
+func NewTaxIDVerificationService(queries db.Querier, registry interfaces.TaxIDRegistry) *TaxIDVerificationService {
+	return &TaxIDVerificationService{
+		queries:  queries,
+		registry: registry,
+		logger:   logger.Log,
+	}
+}
+
+// normalizeVATNumber splits a VAT number into its VIES country code and number. The country prefix
+// of the number wins over countryCode, which is only used for numbers entered without one.
+func normalizeVATNumber(countryCode, vatNumber string) (string, string) {
+	number := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r >= 'A' && r <= 'Z', r == '+', r == '*':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return -1
+		}
+	}, vatNumber)
+
+	country := viesCountryCode(countryCode)
+	if len(number) > 2 {
+		if prefix := viesCountryCode(number[:2]); vatNumberFormats[prefix] != nil {
+			country = prefix
+			number = number[2:]
+		}
+	}
+	return country, number
+}
+
+// isValidVATNumberFormat reports whether a VAT number has the format of its member state
+func isValidVATNumberFormat(countryCode, vatNumber string) bool {
+	country, number := normalizeVATNumber(countryCode, vatNumber)
+	format, ok := vatNumberFormats[country]
+	return ok && format.MatchString(number)
+}
+
+// viesCountryCode maps an ISO country code to the code VIES uses for it
+func viesCountryCode(countryCode string) string {
+	code := strings.ToUpper(strings.TrimSpace(countryCode))
+	if code == "GR" {
+		return "EL"
+	}
+	return code
+}
+
+// viesBillingCountries lists the ISO billing countries whose customers are re-verified with VIES
+func viesBillingCountries() []string {
+	countries := make([]string, 0, len(vatNumberFormats))
+	for code := range vatNumberFormats {
+		switch code {
+		case "EL":
+			countries = append(countries, "GR")
+		case "XI":
+			// Northern Ireland customers bill from GB, where most tax IDs are UK VAT numbers
+		default:
+			countries = append(countries, code)
+		}
+	}
+	return countries
+}
+
+// VerifyTaxID verifies a VAT number, answering from the cache while a previous result is fresh.
+// When customerID is given the customer's verification status is updated as well.
+func (s *TaxIDVerificationService) VerifyTaxID(ctx context.Context, customerID *uuid.UUID, countryCode, taxID string) (*db.TaxIDVerification, error) {
+	return s.verify(ctx, customerID, countryCode, taxID, true)
+}
+
+// VerifyCustomerTaxID checks a customer's stored tax ID with the registry, bypassing the cache
+func (s *TaxIDVerificationService) VerifyCustomerTaxID(ctx context.Context, customerID uuid.UUID) (*db.TaxIDVerification, error) {
+	customer, err := s.queries.GetCustomer(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if !customer.TaxID.Valid || strings.TrimSpace(customer.TaxID.String) == "" {
+		return nil, ErrCustomerHasNoTaxID
+	}
+
+	return s.verify(ctx, &customer.ID, customer.BillingCountry.String, customer.TaxID.String, false)
+}
+
+// ListCustomerVerifications returns a customer's most recent verifications, newest first
+func (s *TaxIDVerificationService) ListCustomerVerifications(ctx context.Context, customerID uuid.UUID, limit int32) ([]db.TaxIDVerification, error) {
+	verifications, err := s.queries.ListTaxIDVerificationsByCustomer(ctx, db.ListTaxIDVerificationsByCustomerParams{
+		CustomerID: pgtype.UUID{Bytes: customerID, Valid: true},
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tax ID verifications: %w", err)
+	}
+	return verifications, nil
+}
+
+// ReverifyCustomerTaxIDs checks again the tax IDs of customers last verified before verifiedBefore,
+// returning how many customers were checked. Registry outages are logged and retried on the next run.
+func (s *TaxIDVerificationService) ReverifyCustomerTaxIDs(ctx context.Context, verifiedBefore time.Time, limit int32) (int, error) {
+	customers, err := s.queries.ListCustomersDueForTaxIDVerification(ctx, db.ListCustomersDueForTaxIDVerificationParams{
+		CountryCodes:   viesBillingCountries(),
+		VerifiedBefore: pgtype.Timestamptz{Time: verifiedBefore, Valid: true},
+		Limit:          limit,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list customers due for tax ID verification: %w", err)
+	}
+
+	checked := 0
+	for _, customer := range customers {
+		customerID := customer.ID
+		verification, err := s.verify(ctx, &customerID, customer.BillingCountry.String, customer.TaxID.String, false)
+		if err != nil {
+			s.logger.Warn("Failed to re-verify customer tax ID",
+				zap.String("customer_id", customer.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		checked++
+
+		if customer.TaxIDVerified.Bool && !verification.IsValid {
+			s.logger.Warn("Customer tax ID is no longer valid",
+				zap.String("customer_id", customer.ID.String()),
+				zap.String("verification_id", verification.ID.String()))
+		}
+	}
+
+	return checked, nil
+}
+
+// verify runs a single verification, optionally answering from the cache
+func (s *TaxIDVerificationService) verify(ctx context.Context, customerID *uuid.UUID, countryCode, taxID string, useCache bool) (*db.TaxIDVerification, error) {
+	country, number := normalizeVATNumber(countryCode, taxID)
+	format, supported := vatNumberFormats[country]
+	if !supported {
+		return nil, ErrTaxIDNotSupported
+	}
+	if !format.MatchString(number) {
+		// A malformed number cannot be registered; record the failed check on the customer
+		if customerID != nil {
+			s.setCustomerVerified(ctx, *customerID, false, time.Now())
+		}
+		return nil, ErrInvalidTaxIDFormat
+	}
+
+	now := time.Now()
+	if useCache {
+		cached, err := s.queries.GetCachedTaxIDVerification(ctx, db.GetCachedTaxIDVerificationParams{
+			CountryCode: country,
+			TaxID:       number,
+			Now:         pgtype.Timestamptz{Time: now, Valid: true},
+		})
+		if err == nil {
+			if customerID != nil {
+				s.setCustomerVerified(ctx, *customerID, cached.IsValid, cached.CheckedAt.Time)
+			}
+			return &cached, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to look up cached tax ID verification: %w", err)
+		}
+	}
+
+	if s.registry == nil {
+		return nil, fmt.Errorf("no tax ID registry configured")
+	}
+	result, err := s.registry.CheckTaxID(ctx, country, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check tax ID with %s: %w", s.registry.Name(), err)
+	}
+
+	ttl := validTaxIDCacheTTL
+	if !result.Valid {
+		ttl = invalidTaxIDCacheTTL
+	}
+	verification, err := s.queries.CreateTaxIDVerification(ctx, db.CreateTaxIDVerificationParams{
+		CustomerID:         uuidToPgtype(customerID),
+		CountryCode:        country,
+		TaxID:              number,
+		IsValid:            result.Valid,
+		RegisteredName:     pgtype.Text{String: result.Name, Valid: result.Name != ""},
+		RegisteredAddress:  pgtype.Text{String: result.Address, Valid: result.Address != ""},
+		ConsultationNumber: pgtype.Text{String: result.ConsultationNumber, Valid: result.ConsultationNumber != ""},
+		Source:             result.Source,
+		CheckedAt:          pgtype.Timestamptz{Time: result.CheckedAt, Valid: true},
+		ExpiresAt:          pgtype.Timestamptz{Time: now.Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store tax ID verification: %w", err)
+	}
+
+	s.logger.Info("Verified tax ID",
+		zap.String("verification_id", verification.ID.String()),
+		zap.String("country_code", country),
+		zap.Bool("valid", verification.IsValid),
+		zap.String("consultation_number", result.ConsultationNumber))
+
+	if customerID != nil {
+		s.setCustomerVerified(ctx, *customerID, verification.IsValid, result.CheckedAt)
+	}
+	return &verification, nil
+}
+
+// setCustomerVerified records a verification outcome on the customer. Failures are logged only;
+// the verification itself is stored either way.
+func (s *TaxIDVerificationService) setCustomerVerified(ctx context.Context, customerID uuid.UUID, valid bool, checkedAt time.Time) {
+	err := s.queries.SetCustomerTaxIDVerified(ctx, db.SetCustomerTaxIDVerifiedParams{
+		TaxIDVerified:   pgtype.Bool{Bool: valid, Valid: true},
+		TaxIDVerifiedAt: pgtype.Timestamptz{Time: checkedAt, Valid: true},
+		ID:              customerID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to update customer tax ID verification",
+			zap.String("customer_id", customerID.String()),
+			zap.Error(err))
+	}
+}
+
+// taxIDVerificationEvidence converts a stored verification into the evidence kept with tax calculations
+func taxIDVerificationEvidence(verification *db.TaxIDVerification) *business.TaxIDVerificationEvidence {
+	return &business.TaxIDVerificationEvidence{
+		VerificationID: verification.ID,
+		TaxIDCheckResult: business.TaxIDCheckResult{
+			CountryCode:        verification.CountryCode,
+			TaxID:              verification.TaxID,
+			Valid:              verification.IsValid,
+			Name:               verification.RegisteredName.String,
+			Address:            verification.RegisteredAddress.String,
+			ConsultationNumber: verification.ConsultationNumber.String,
+			CheckedAt:          verification.CheckedAt.Time,
+			Source:             verification.Source,
+		},
+	}
+}
@@ -0,0 +1,221 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTaxIDVerificationService_VerifyTaxID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	mockRegistry := mocks.NewMockTaxIDRegistry(ctrl)
+	mockRegistry.EXPECT().Name().Return("vies").AnyTimes()
+	service := services.NewTaxIDVerificationService(mockQuerier, mockRegistry)
+	ctx := context.Background()
+
+	customerID := uuid.New()
+
+	t.Run("answers from the cache", func(t *testing.T) {
+		cached := db.TaxIDVerification{
+			ID:          uuid.New(),
+			CountryCode: "DE",
+			TaxID:       "123456789",
+			IsValid:     true,
+			Source:      "vies",
+			CheckedAt:   pgtype.Timestamptz{Time: time.Now().Add(-48 * time.Hour), Valid: true},
+		}
+		mockQuerier.EXPECT().GetCachedTaxIDVerification(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.GetCachedTaxIDVerificationParams) (db.TaxIDVerification, error) {
+				assert.Equal(t, "DE", arg.CountryCode)
+				assert.Equal(t, "123456789", arg.TaxID)
+				return cached, nil
+			})
+		mockQuerier.EXPECT().SetCustomerTaxIDVerified(ctx, db.SetCustomerTaxIDVerifiedParams{
+			TaxIDVerified:   pgtype.Bool{Bool: true, Valid: true},
+			TaxIDVerifiedAt: cached.CheckedAt,
+			ID:              customerID,
+		}).Return(nil)
+
+		verification, err := service.VerifyTaxID(ctx, &customerID, "DE", "de 123.456.789")
+		require.NoError(t, err)
+		assert.Equal(t, cached.ID, verification.ID)
+	})
+
+	t.Run("stores a fresh registry answer", func(t *testing.T) {
+		checkedAt := time.Now()
+		mockQuerier.EXPECT().GetCachedTaxIDVerification(ctx, gomock.Any()).Return(db.TaxIDVerification{}, pgx.ErrNoRows)
+		mockRegistry.EXPECT().CheckTaxID(ctx, "EL", "123456789").Return(&business.TaxIDCheckResult{
+			CountryCode:        "EL",
+			TaxID:              "123456789",
+			Valid:              true,
+			Name:               "ACME AE",
+			ConsultationNumber: "WAPIAAAAY1234567",
+			CheckedAt:          checkedAt,
+			Source:             "vies",
+		}, nil)
+		mockQuerier.EXPECT().CreateTaxIDVerification(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.CreateTaxIDVerificationParams) (db.TaxIDVerification, error) {
+				assert.Equal(t, customerID, uuid.UUID(arg.CustomerID.Bytes))
+				assert.Equal(t, "ACME AE", arg.RegisteredName.String)
+				assert.False(t, arg.RegisteredAddress.Valid)
+				assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), arg.ExpiresAt.Time, time.Minute)
+				return db.TaxIDVerification{
+					ID:          uuid.New(),
+					CustomerID:  arg.CustomerID,
+					CountryCode: arg.CountryCode,
+					TaxID:       arg.TaxID,
+					IsValid:     arg.IsValid,
+					CheckedAt:   arg.CheckedAt,
+				}, nil
+			})
+		mockQuerier.EXPECT().SetCustomerTaxIDVerified(ctx, gomock.Any()).Return(nil)
+
+		verification, err := service.VerifyTaxID(ctx, &customerID, "GR", "123456789")
+		require.NoError(t, err)
+		assert.True(t, verification.IsValid)
+		assert.Equal(t, "EL", verification.CountryCode)
+	})
+
+	t.Run("invalid answers expire sooner", func(t *testing.T) {
+		mockQuerier.EXPECT().GetCachedTaxIDVerification(ctx, gomock.Any()).Return(db.TaxIDVerification{}, pgx.ErrNoRows)
+		mockRegistry.EXPECT().CheckTaxID(ctx, "FR", "40303265045").Return(&business.TaxIDCheckResult{
+			CountryCode: "FR",
+			TaxID:       "40303265045",
+			CheckedAt:   time.Now(),
+			Source:      "vies",
+		}, nil)
+		mockQuerier.EXPECT().CreateTaxIDVerification(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.CreateTaxIDVerificationParams) (db.TaxIDVerification, error) {
+				assert.False(t, arg.CustomerID.Valid)
+				assert.WithinDuration(t, time.Now().Add(24*time.Hour), arg.ExpiresAt.Time, time.Minute)
+				return db.TaxIDVerification{ID: uuid.New(), IsValid: arg.IsValid}, nil
+			})
+
+		verification, err := service.VerifyTaxID(ctx, nil, "", "FR40303265045")
+		require.NoError(t, err)
+		assert.False(t, verification.IsValid)
+	})
+
+	t.Run("malformed number is rejected without a registry call", func(t *testing.T) {
+		mockQuerier.EXPECT().SetCustomerTaxIDVerified(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.SetCustomerTaxIDVerifiedParams) error {
+				assert.False(t, arg.TaxIDVerified.Bool)
+				return nil
+			})
+
+		_, err := service.VerifyTaxID(ctx, &customerID, "DE", "DE123")
+		assert.ErrorIs(t, err, services.ErrInvalidTaxIDFormat)
+	})
+
+	t.Run("countries outside the registry are not supported", func(t *testing.T) {
+		_, err := service.VerifyTaxID(ctx, &customerID, "US", "123456789")
+		assert.ErrorIs(t, err, services.ErrTaxIDNotSupported)
+	})
+
+	t.Run("registry outage is returned and nothing is stored", func(t *testing.T) {
+		mockQuerier.EXPECT().GetCachedTaxIDVerification(ctx, gomock.Any()).Return(db.TaxIDVerification{}, pgx.ErrNoRows)
+		mockRegistry.EXPECT().CheckTaxID(ctx, "DE", "123456789").Return(nil, errors.New("VIES unavailable"))
+
+		_, err := service.VerifyTaxID(ctx, &customerID, "DE", "DE123456789")
+		assert.Error(t, err)
+	})
+}
+
+func TestTaxIDVerificationService_VerifyCustomerTaxID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	mockRegistry := mocks.NewMockTaxIDRegistry(ctrl)
+	service := services.NewTaxIDVerificationService(mockQuerier, mockRegistry)
+	ctx := context.Background()
+
+	customerID := uuid.New()
+
+	t.Run("customer without tax ID", func(t *testing.T) {
+		mockQuerier.EXPECT().GetCustomer(ctx, customerID).Return(db.Customer{ID: customerID}, nil)
+
+		_, err := service.VerifyCustomerTaxID(ctx, customerID)
+		assert.ErrorIs(t, err, services.ErrCustomerHasNoTaxID)
+	})
+
+	t.Run("bypasses the cache", func(t *testing.T) {
+		mockQuerier.EXPECT().GetCustomer(ctx, customerID).Return(db.Customer{
+			ID:             customerID,
+			TaxID:          pgtype.Text{String: "123456789", Valid: true},
+			BillingCountry: pgtype.Text{String: "DE", Valid: true},
+		}, nil)
+		mockRegistry.EXPECT().CheckTaxID(ctx, "DE", "123456789").Return(&business.TaxIDCheckResult{
+			CountryCode: "DE",
+			TaxID:       "123456789",
+			Valid:       true,
+			CheckedAt:   time.Now(),
+			Source:      "vies",
+		}, nil)
+		mockQuerier.EXPECT().CreateTaxIDVerification(ctx, gomock.Any()).Return(db.TaxIDVerification{ID: uuid.New(), IsValid: true}, nil)
+		mockQuerier.EXPECT().SetCustomerTaxIDVerified(ctx, gomock.Any()).Return(nil)
+
+		verification, err := service.VerifyCustomerTaxID(ctx, customerID)
+		require.NoError(t, err)
+		assert.True(t, verification.IsValid)
+	})
+}
+
+func TestTaxIDVerificationService_ReverifyCustomerTaxIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	mockRegistry := mocks.NewMockTaxIDRegistry(ctrl)
+	mockRegistry.EXPECT().Name().Return("vies").AnyTimes()
+	service := services.NewTaxIDVerificationService(mockQuerier, mockRegistry)
+	ctx := context.Background()
+
+	verified := db.Customer{
+		ID:             uuid.New(),
+		TaxID:          pgtype.Text{String: "ATU12345678", Valid: true},
+		BillingCountry: pgtype.Text{String: "AT", Valid: true},
+	}
+	unavailable := db.Customer{
+		ID:             uuid.New(),
+		TaxID:          pgtype.Text{String: "123456789", Valid: true},
+		BillingCountry: pgtype.Text{String: "DE", Valid: true},
+	}
+
+	mockQuerier.EXPECT().ListCustomersDueForTaxIDVerification(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.ListCustomersDueForTaxIDVerificationParams) ([]db.Customer, error) {
+			assert.Contains(t, arg.CountryCodes, "GR")
+			assert.NotContains(t, arg.CountryCodes, "EL")
+			assert.Equal(t, int32(50), arg.Limit)
+			return []db.Customer{verified, unavailable}, nil
+		})
+	mockRegistry.EXPECT().CheckTaxID(ctx, "AT", "U12345678").Return(&business.TaxIDCheckResult{
+		CountryCode: "AT",
+		TaxID:       "U12345678",
+		Valid:       true,
+		CheckedAt:   time.Now(),
+		Source:      "vies",
+	}, nil)
+	mockRegistry.EXPECT().CheckTaxID(ctx, "DE", "123456789").Return(nil, errors.New("VIES unavailable"))
+	mockQuerier.EXPECT().CreateTaxIDVerification(ctx, gomock.Any()).Return(db.TaxIDVerification{ID: uuid.New(), IsValid: true}, nil)
+	mockQuerier.EXPECT().SetCustomerTaxIDVerified(ctx, gomock.Any()).Return(nil)
+
+	checked, err := service.ReverifyCustomerTaxIDs(ctx, time.Now().Add(-30*24*time.Hour), 50)
+	require.NoError(t, err)
+	assert.Equal(t, 1, checked)
+}
@@ -0,0 +1,538 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidReportPeriod is returned for report periods that are empty or out of range
+var ErrInvalidReportPeriod = errors.New("invalid report period")
+
+// taxEventKind is what happened to an invoice's tax within a report period
+type taxEventKind int
+
+const (
+	taxIssued taxEventKind = iota
+	taxVoided
+	taxRefunded
+	taxPaid
+)
+
+// taxReportInvoice is the tax-relevant part of an invoice
+type taxReportInvoice struct {
+	ID                 uuid.UUID
+	Currency           string
+	NetAmountCents     int64 // Subtotal after discounts
+	TaxLines           []business.TaxLineItem
+	ReverseCharge      bool
+	CustomerTaxID      string
+	CustomerName       string
+	CustomerCountry    string
+	ConsultationNumber string
+	IssuedAt           time.Time
+}
+
+// taxEvent is a change to reported tax. share is the part of the invoice it affects: 1 for issuing,
+// voiding and paying, and the refunded part for refunds.
+type taxEvent struct {
+	kind    taxEventKind
+	invoice *taxReportInvoice
+	share   float64
+}
+
+// sign is the direction in which an event moves the tax liability
+func (e taxEvent) sign() float64 {
+	switch e.kind {
+	case taxVoided, taxRefunded:
+		return -e.share
+	default:
+		return e.share
+	}
+}
+
+// TaxReportService aggregates invoiced, collected and refunded tax into the summaries merchants
+// need to file tax returns
+type TaxReportService struct {
+	queries db.Querier
+	logger  *zap.Logger
+}
+
+// NewTaxReportService creates a new tax report service
+func NewTaxReportService(queries db.Querier) *TaxReportService {
+	return &TaxReportService{
+		queries: queries,
+		logger:  logger.Log,
+	}
+}
+
+// QuarterPeriod returns the start and end of a calendar quarter in UTC
+func QuarterPeriod(year, quarter int) (time.Time, time.Time, error) {
+	if quarter < 1 || quarter > 4 || year < 2000 || year > 9999 {
+		return time.Time{}, time.Time{}, ErrInvalidReportPeriod
+	}
+	start := time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 3, 0), nil
+}
+
+// quarterName names the calendar quarter a time falls in, e.g. "2025-Q1"
+func quarterName(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+}
+
+// GetTaxSummary aggregates tax by jurisdiction, rate and currency
+func (s *TaxReportService) GetTaxSummary(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*responses.TaxSummaryReport, error) {
+	events, err := s.loadEvents(ctx, workspaceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		jurisdiction  string
+		taxType       string
+		rate          float64
+		reverseCharge bool
+		currency      string
+	}
+	lines := map[key]*responses.TaxSummaryLine{}
+	counted := map[key]map[uuid.UUID]bool{}
+
+	for _, event := range events {
+		for _, item := range event.invoice.TaxLines {
+			k := key{item.Jurisdiction, item.TaxType, item.Rate, item.IsReversCharge || event.invoice.ReverseCharge, event.invoice.Currency}
+			line, ok := lines[k]
+			if !ok {
+				line = &responses.TaxSummaryLine{
+					Jurisdiction:  k.jurisdiction,
+					TaxType:       k.taxType,
+					Rate:          k.rate,
+					ReverseCharge: k.reverseCharge,
+					Currency:      k.currency,
+				}
+				lines[k] = line
+				counted[k] = map[uuid.UUID]bool{}
+			}
+
+			taxable := scaleCents(taxableAmount(item, event.invoice), event.share)
+			tax := scaleCents(item.TaxAmountCents, event.share)
+			switch event.kind {
+			case taxIssued:
+				if !counted[k][event.invoice.ID] {
+					counted[k][event.invoice.ID] = true
+					line.InvoiceCount++
+				}
+				line.TaxableAmountCents += taxable
+				line.TaxInvoicedCents += tax
+			case taxVoided:
+				line.TaxableAmountCents -= taxable
+				line.TaxVoidedCents += tax
+			case taxRefunded:
+				line.TaxableAmountCents -= taxable
+				line.TaxRefundedCents += tax
+			case taxPaid:
+				line.TaxCollectedCents += tax
+			}
+		}
+	}
+
+	report := &responses.TaxSummaryReport{
+		WorkspaceID: workspaceID.String(),
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Lines:       make([]responses.TaxSummaryLine, 0, len(lines)),
+	}
+	for _, line := range lines {
+		line.NetTaxCents = line.TaxInvoicedCents - line.TaxVoidedCents - line.TaxRefundedCents
+		report.Lines = append(report.Lines, *line)
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		if a.Jurisdiction != b.Jurisdiction {
+			return a.Jurisdiction < b.Jurisdiction
+		}
+		if a.Rate != b.Rate {
+			return a.Rate > b.Rate
+		}
+		return !a.ReverseCharge && b.ReverseCharge
+	})
+	return report, nil
+}
+
+// GetOSSReport builds the EU One-Stop-Shop summary of a quarter: VAT charged to consumers per
+// member state and rate. Voids and refunds of invoices from earlier quarters are reported as
+// corrections to those quarters. Supplies to homeCountry, the merchant's own member state, belong
+// on the domestic return and are left out when it is given.
+func (s *TaxReportService) GetOSSReport(ctx context.Context, workspaceID uuid.UUID, year, quarter int, homeCountry string) (*responses.OSSReport, error) {
+	start, end, err := QuarterPeriod(year, quarter)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.loadEvents(ctx, workspaceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	homeCountry = viesCountryCode(homeCountry)
+
+	type lineKey struct {
+		country  string
+		rate     float64
+		currency string
+	}
+	type correctionKey struct {
+		period   string
+		country  string
+		currency string
+	}
+	lines := map[lineKey]*responses.OSSReportLine{}
+	corrections := map[correctionKey]*responses.OSSCorrectionLine{}
+
+	for _, event := range events {
+		if event.kind == taxPaid || event.invoice.ReverseCharge {
+			continue
+		}
+		for _, item := range event.invoice.TaxLines {
+			if !strings.HasPrefix(item.Jurisdiction, "EU-") || item.IsReversCharge {
+				continue
+			}
+			country := strings.TrimPrefix(item.Jurisdiction, "EU-")
+			if homeCountry != "" && viesCountryCode(country) == homeCountry {
+				continue
+			}
+
+			vat := scaleCents(item.TaxAmountCents, event.sign())
+			if event.kind != taxIssued && event.invoice.IssuedAt.Before(start) {
+				k := correctionKey{quarterName(event.invoice.IssuedAt), country, event.invoice.Currency}
+				correction, ok := corrections[k]
+				if !ok {
+					correction = &responses.OSSCorrectionLine{CorrectedPeriod: k.period, CountryCode: country, Currency: k.currency}
+					corrections[k] = correction
+				}
+				correction.VATAmountCents += vat
+				continue
+			}
+
+			k := lineKey{country, item.Rate, event.invoice.Currency}
+			line, ok := lines[k]
+			if !ok {
+				line = &responses.OSSReportLine{CountryCode: country, Rate: item.Rate, Currency: k.currency}
+				lines[k] = line
+			}
+			line.TaxableAmountCents += scaleCents(taxableAmount(item, event.invoice), event.sign())
+			line.VATAmountCents += vat
+		}
+	}
+
+	report := &responses.OSSReport{
+		WorkspaceID: workspaceID.String(),
+		Period:      quarterName(start),
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Lines:       make([]responses.OSSReportLine, 0, len(lines)),
+		Corrections: make([]responses.OSSCorrectionLine, 0, len(corrections)),
+	}
+	for _, line := range lines {
+		report.Lines = append(report.Lines, *line)
+	}
+	for _, correction := range corrections {
+		report.Corrections = append(report.Corrections, *correction)
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if a.CountryCode != b.CountryCode {
+			return a.CountryCode < b.CountryCode
+		}
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		return a.Rate > b.Rate
+	})
+	sort.Slice(report.Corrections, func(i, j int) bool {
+		a, b := report.Corrections[i], report.Corrections[j]
+		if a.CorrectedPeriod != b.CorrectedPeriod {
+			return a.CorrectedPeriod < b.CorrectedPeriod
+		}
+		if a.CountryCode != b.CountryCode {
+			return a.CountryCode < b.CountryCode
+		}
+		return a.Currency < b.Currency
+	})
+	return report, nil
+}
+
+// GetReverseChargeReport lists the B2B supplies under reverse charge per customer VAT number,
+// as needed for an EC Sales List
+func (s *TaxReportService) GetReverseChargeReport(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*responses.ReverseChargeReport, error) {
+	events, err := s.loadEvents(ctx, workspaceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		vatNumber string
+		currency  string
+	}
+	lines := map[key]*responses.ReverseChargeLine{}
+
+	for _, event := range events {
+		invoice := event.invoice
+		if event.kind == taxPaid || !invoice.ReverseCharge {
+			continue
+		}
+
+		country, number := normalizeVATNumber(invoice.CustomerCountry, invoice.CustomerTaxID)
+		k := key{country + number, invoice.Currency}
+		line, ok := lines[k]
+		if !ok {
+			line = &responses.ReverseChargeLine{
+				CountryCode:  country,
+				VATNumber:    k.vatNumber,
+				CustomerName: invoice.CustomerName,
+				Currency:     k.currency,
+			}
+			lines[k] = line
+		}
+		if event.kind == taxIssued {
+			line.InvoiceCount++
+		}
+		line.AmountCents += scaleCents(invoice.NetAmountCents, event.sign())
+		if invoice.ConsultationNumber != "" {
+			line.ConsultationNumber = invoice.ConsultationNumber
+		}
+	}
+
+	report := &responses.ReverseChargeReport{
+		WorkspaceID: workspaceID.String(),
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Lines:       make([]responses.ReverseChargeLine, 0, len(lines)),
+	}
+	for _, line := range lines {
+		report.Lines = append(report.Lines, *line)
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if a.VATNumber != b.VATNumber {
+			return a.VATNumber < b.VATNumber
+		}
+		return a.Currency < b.Currency
+	})
+	return report, nil
+}
+
+// GetUSStateTaxReport summarizes US sales tax per state, rolling local jurisdictions into their state
+func (s *TaxReportService) GetUSStateTaxReport(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*responses.USStateTaxReport, error) {
+	events, err := s.loadEvents(ctx, workspaceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		state    string
+		currency string
+	}
+	lines := map[key]*responses.USStateTaxLine{}
+
+	for _, event := range events {
+		invoice := event.invoice
+		states := map[string]bool{}
+		for _, item := range invoice.TaxLines {
+			parts := strings.SplitN(item.Jurisdiction, "-", 3)
+			if len(parts) < 2 || parts[0] != "US" {
+				continue
+			}
+			k := key{parts[1], invoice.Currency}
+			line, ok := lines[k]
+			if !ok {
+				line = &responses.USStateTaxLine{State: k.state, Currency: k.currency}
+				lines[k] = line
+			}
+
+			tax := scaleCents(item.TaxAmountCents, event.sign())
+			if event.kind == taxPaid {
+				line.TaxCollectedCents += tax
+				continue
+			}
+
+			// Count each invoice once per state, however many local jurisdictions it touches
+			if !states[k.state] {
+				states[k.state] = true
+				line.GrossSalesCents += scaleCents(invoice.NetAmountCents, event.sign())
+				if event.kind == taxIssued {
+					line.TransactionCount++
+				}
+			}
+			if len(parts) == 2 {
+				line.TaxableSalesCents += scaleCents(taxableAmount(item, invoice), event.sign())
+				line.StateTaxCents += tax
+			} else {
+				line.LocalTaxCents += tax
+			}
+		}
+	}
+
+	report := &responses.USStateTaxReport{
+		WorkspaceID: workspaceID.String(),
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Lines:       make([]responses.USStateTaxLine, 0, len(lines)),
+	}
+	for _, line := range lines {
+		line.NetTaxCents = line.StateTaxCents + line.LocalTaxCents
+		report.Lines = append(report.Lines, *line)
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if a.State != b.State {
+			return a.State < b.State
+		}
+		return a.Currency < b.Currency
+	})
+	return report, nil
+}
+
+// loadEvents collects the tax events of a period: invoices issued, paid and voided, and payments refunded
+func (s *TaxReportService) loadEvents(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) ([]taxEvent, error) {
+	if !start.Before(end) {
+		return nil, ErrInvalidReportPeriod
+	}
+	periodStart := pgtype.Timestamptz{Time: start, Valid: true}
+	periodEnd := pgtype.Timestamptz{Time: end, Valid: true}
+	inPeriod := func(t pgtype.Timestamptz) bool {
+		return t.Valid && !t.Time.Before(start) && t.Time.Before(end)
+	}
+
+	invoices, err := s.queries.ListTaxReportInvoices(ctx, db.ListTaxReportInvoicesParams{
+		WorkspaceID: workspaceID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices for tax report: %w", err)
+	}
+
+	var events []taxEvent
+	for _, row := range invoices {
+		invoice := s.newTaxReportInvoice(row.ID, row.Currency, row.SubtotalCents-row.DiscountCents, row.TaxDetails,
+			row.ReverseChargeApplies, row.CustomerTaxID, row.CustomerName, row.CustomerBusinessName, row.CustomerCountry,
+			row.ConsultationNumber, row.IssuedAt)
+
+		if inPeriod(row.IssuedAt) {
+			events = append(events, taxEvent{kind: taxIssued, invoice: invoice, share: 1})
+		}
+		if row.Status == "paid" && inPeriod(row.PaidAt) {
+			events = append(events, taxEvent{kind: taxPaid, invoice: invoice, share: 1})
+		}
+		if row.Status == "void" {
+			// Invoices voided without a recorded activity are treated as voided when issued
+			voidedAt := row.VoidedAt
+			if !voidedAt.Valid {
+				voidedAt = row.IssuedAt
+			}
+			if inPeriod(voidedAt) {
+				events = append(events, taxEvent{kind: taxVoided, invoice: invoice, share: 1})
+			}
+		}
+	}
+
+	refunds, err := s.queries.ListTaxReportRefunds(ctx, db.ListTaxReportRefundsParams{
+		WorkspaceID: workspaceID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refunds for tax report: %w", err)
+	}
+
+	for _, row := range refunds {
+		invoice := s.newTaxReportInvoice(row.InvoiceID, row.Currency, row.SubtotalCents-row.DiscountCents, row.TaxDetails,
+			row.ReverseChargeApplies, row.CustomerTaxID, row.CustomerName, row.CustomerBusinessName, row.CustomerCountry,
+			row.ConsultationNumber, row.IssuedAt)
+		events = append(events, taxEvent{
+			kind:    taxRefunded,
+			invoice: invoice,
+			share:   refundShare(row.RefundedAmountCents, row.RefundedTaxCents, int64(row.AmountDue), row.TaxAmountCents),
+		})
+	}
+
+	return events, nil
+}
+
+// newTaxReportInvoice builds the tax view of an invoice row. Invoices with unreadable tax details
+// are kept, without tax lines, so that reverse-charge listings still include them.
+func (s *TaxReportService) newTaxReportInvoice(id uuid.UUID, currency string, netAmountCents int64, taxDetails []byte,
+	reverseCharge bool, customerTaxID, customerName, businessName, customerCountry, consultationNumber pgtype.Text,
+	issuedAt pgtype.Timestamptz) *taxReportInvoice {
+	invoice := &taxReportInvoice{
+		ID:                 id,
+		Currency:           strings.ToUpper(currency),
+		NetAmountCents:     netAmountCents,
+		ReverseCharge:      reverseCharge,
+		CustomerTaxID:      customerTaxID.String,
+		CustomerName:       customerName.String,
+		CustomerCountry:    customerCountry.String,
+		ConsultationNumber: consultationNumber.String,
+		IssuedAt:           issuedAt.Time,
+	}
+	if businessName.Valid && businessName.String != "" {
+		invoice.CustomerName = businessName.String
+	}
+
+	if len(taxDetails) > 0 {
+		if err := json.Unmarshal(taxDetails, &invoice.TaxLines); err != nil {
+			s.logger.Warn("Failed to parse invoice tax details for tax report",
+				zap.String("invoice_id", id.String()),
+				zap.Error(err))
+		}
+	}
+	for _, item := range invoice.TaxLines {
+		if item.IsReversCharge {
+			invoice.ReverseCharge = true
+		}
+	}
+	return invoice
+}
+
+// refundShare is the part of an invoice a refund gives back: by the refunded tax when the payment
+// recorded it, otherwise by the refunded amount
+func refundShare(refundedCents, refundedTaxCents, invoiceTotalCents, invoiceTaxCents int64) float64 {
+	var share float64
+	switch {
+	case refundedTaxCents > 0 && invoiceTaxCents > 0:
+		share = float64(refundedTaxCents) / float64(invoiceTaxCents)
+	case invoiceTotalCents > 0:
+		share = float64(refundedCents) / float64(invoiceTotalCents)
+	default:
+		share = 1
+	}
+	return math.Min(share, 1)
+}
+
+// taxableAmount is the amount a tax line was charged on, defaulting to the invoice's net amount
+func taxableAmount(item business.TaxLineItem, invoice *taxReportInvoice) int64 {
+	if item.TaxableAmount != 0 {
+		return item.TaxableAmount
+	}
+	return invoice.NetAmountCents
+}
+
+// scaleCents applies a share to an amount, rounding to the nearest cent
+func scaleCents(cents int64, share float64) int64 {
+	return int64(math.Round(float64(cents) * share))
+}
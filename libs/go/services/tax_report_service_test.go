@@ -0,0 +1,241 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// taxReportInvoice builds an invoice row for the tax report queries
+func taxReportInvoice(t *testing.T, status string, issuedAt time.Time, netCents int64, lines ...business.TaxLineItem) db.ListTaxReportInvoicesRow {
+	t.Helper()
+
+	details, err := json.Marshal(lines)
+	require.NoError(t, err)
+
+	var taxCents int64
+	for _, line := range lines {
+		taxCents += line.TaxAmountCents
+	}
+	return db.ListTaxReportInvoicesRow{
+		ID:             uuid.New(),
+		Status:         status,
+		Currency:       "EUR",
+		SubtotalCents:  netCents,
+		TaxAmountCents: taxCents,
+		TaxDetails:     details,
+		IssuedAt:       pgtype.Timestamptz{Time: issuedAt, Valid: true},
+	}
+}
+
+// taxReportRefund builds a refund row for an invoice row
+func taxReportRefund(invoice db.ListTaxReportInvoicesRow, refundedAt time.Time, refundedCents int64) db.ListTaxReportRefundsRow {
+	return db.ListTaxReportRefundsRow{
+		PaymentID:            uuid.New(),
+		RefundedAmountCents:  refundedCents,
+		RefundedAt:           pgtype.Timestamptz{Time: refundedAt, Valid: true},
+		InvoiceID:            invoice.ID,
+		Currency:             invoice.Currency,
+		AmountDue:            int32(invoice.SubtotalCents + invoice.TaxAmountCents),
+		SubtotalCents:        invoice.SubtotalCents,
+		TaxAmountCents:       invoice.TaxAmountCents,
+		TaxDetails:           invoice.TaxDetails,
+		CustomerTaxID:        invoice.CustomerTaxID,
+		ReverseChargeApplies: invoice.ReverseChargeApplies,
+		IssuedAt:             invoice.IssuedAt,
+		CustomerName:         invoice.CustomerName,
+		CustomerCountry:      invoice.CustomerCountry,
+	}
+}
+
+func vatLine(country string, rate float64, taxable int64) business.TaxLineItem {
+	return business.TaxLineItem{
+		TaxType:        "vat",
+		Jurisdiction:   "EU-" + country,
+		Rate:           rate,
+		TaxableAmount:  taxable,
+		TaxAmountCents: int64(float64(taxable) * rate),
+	}
+}
+
+func TestTaxReportService_OSSReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewTaxReportService(mockQuerier)
+	ctx := context.Background()
+	workspaceID := uuid.New()
+
+	inQ2 := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	inQ1 := time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC)
+
+	issued := taxReportInvoice(t, "paid", inQ2, 10000, vatLine("DE", 0.19, 10000))
+	issued.PaidAt = pgtype.Timestamptz{Time: inQ2, Valid: true}
+	refunded := taxReportInvoice(t, "open", inQ2, 20000, vatLine("DE", 0.19, 20000))
+	voidedLater := taxReportInvoice(t, "void", inQ1, 10000, vatLine("FR", 0.20, 10000))
+	voidedLater.VoidedAt = pgtype.Timestamptz{Time: inQ2, Valid: true}
+	voidedSameQuarter := taxReportInvoice(t, "void", inQ2, 5000, vatLine("IT", 0.22, 5000))
+	voidedSameQuarter.VoidedAt = pgtype.Timestamptz{Time: inQ2.Add(time.Hour), Valid: true}
+	reverseCharge := taxReportInvoice(t, "open", inQ2, 50000, business.TaxLineItem{
+		TaxType: "vat", Jurisdiction: "EU-NL", TaxableAmount: 50000, IsReversCharge: true,
+	})
+	domestic := taxReportInvoice(t, "open", inQ2, 10000, vatLine("AT", 0.20, 10000))
+
+	mockQuerier.EXPECT().ListTaxReportInvoices(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.ListTaxReportInvoicesParams) ([]db.ListTaxReportInvoicesRow, error) {
+			assert.Equal(t, workspaceID, arg.WorkspaceID)
+			assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), arg.PeriodStart.Time)
+			assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), arg.PeriodEnd.Time)
+			return []db.ListTaxReportInvoicesRow{issued, refunded, voidedLater, voidedSameQuarter, reverseCharge, domestic}, nil
+		})
+	mockQuerier.EXPECT().ListTaxReportRefunds(ctx, gomock.Any()).Return([]db.ListTaxReportRefundsRow{
+		taxReportRefund(refunded, inQ2.Add(24*time.Hour), 11900), // Half of the invoice total
+	}, nil)
+
+	report, err := service.GetOSSReport(ctx, workspaceID, 2025, 2, "at")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-Q2", report.Period)
+	require.Len(t, report.Lines, 2)
+	assert.Equal(t, "DE", report.Lines[0].CountryCode)
+	assert.Equal(t, int64(20000), report.Lines[0].TaxableAmountCents)
+	assert.Equal(t, int64(3800), report.Lines[0].VATAmountCents)
+	assert.Equal(t, "IT", report.Lines[1].CountryCode)
+	assert.Equal(t, int64(0), report.Lines[1].VATAmountCents)
+
+	require.Len(t, report.Corrections, 1)
+	assert.Equal(t, "2025-Q1", report.Corrections[0].CorrectedPeriod)
+	assert.Equal(t, "FR", report.Corrections[0].CountryCode)
+	assert.Equal(t, int64(-2000), report.Corrections[0].VATAmountCents)
+}
+
+func TestTaxReportService_TaxSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewTaxReportService(mockQuerier)
+	ctx := context.Background()
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	inPeriod := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	paid := taxReportInvoice(t, "paid", inPeriod, 10000, vatLine("DE", 0.19, 10000))
+	paid.PaidAt = pgtype.Timestamptz{Time: inPeriod, Valid: true}
+	paidOnly := taxReportInvoice(t, "paid", start.AddDate(0, -1, 0), 20000, vatLine("DE", 0.19, 20000))
+	paidOnly.PaidAt = pgtype.Timestamptz{Time: inPeriod, Valid: true}
+	voided := taxReportInvoice(t, "void", start.AddDate(0, -1, 0), 10000, vatLine("DE", 0.19, 10000))
+	voided.VoidedAt = pgtype.Timestamptz{Time: inPeriod, Valid: true}
+
+	mockQuerier.EXPECT().ListTaxReportInvoices(ctx, gomock.Any()).Return([]db.ListTaxReportInvoicesRow{paid, paidOnly, voided}, nil)
+	refund := taxReportRefund(paid, inPeriod, 0)
+	refund.RefundedTaxCents = 950
+	mockQuerier.EXPECT().ListTaxReportRefunds(ctx, gomock.Any()).Return([]db.ListTaxReportRefundsRow{refund}, nil)
+
+	report, err := service.GetTaxSummary(ctx, uuid.New(), start, end)
+	require.NoError(t, err)
+
+	require.Len(t, report.Lines, 1)
+	line := report.Lines[0]
+	assert.Equal(t, "EU-DE", line.Jurisdiction)
+	assert.Equal(t, 1, line.InvoiceCount)
+	assert.Equal(t, int64(1900), line.TaxInvoicedCents)
+	assert.Equal(t, int64(1900), line.TaxVoidedCents)
+	assert.Equal(t, int64(950), line.TaxRefundedCents)
+	assert.Equal(t, int64(-950), line.NetTaxCents)
+	assert.Equal(t, int64(1900+3800), line.TaxCollectedCents)
+	assert.Equal(t, int64(10000-10000-5000), line.TaxableAmountCents)
+}
+
+func TestTaxReportService_ReverseChargeReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewTaxReportService(mockQuerier)
+	ctx := context.Background()
+
+	inPeriod := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	first := taxReportInvoice(t, "open", inPeriod, 50000, business.TaxLineItem{
+		TaxType: "vat", Jurisdiction: "EU-DE", TaxableAmount: 50000, IsReversCharge: true,
+	})
+	first.CustomerTaxID = pgtype.Text{String: "DE 123 456 789", Valid: true}
+	first.CustomerBusinessName = pgtype.Text{String: "ACME GmbH", Valid: true}
+	first.ConsultationNumber = pgtype.Text{String: "WAPIAAAAY1234567", Valid: true}
+	second := first
+	second.ID = uuid.New()
+	second.CustomerTaxID = pgtype.Text{String: "123456789", Valid: true}
+	second.CustomerCountry = pgtype.Text{String: "DE", Valid: true}
+	b2c := taxReportInvoice(t, "open", inPeriod, 10000, vatLine("DE", 0.19, 10000))
+
+	mockQuerier.EXPECT().ListTaxReportInvoices(ctx, gomock.Any()).Return([]db.ListTaxReportInvoicesRow{first, second, b2c}, nil)
+	mockQuerier.EXPECT().ListTaxReportRefunds(ctx, gomock.Any()).Return(nil, nil)
+
+	report, err := service.GetReverseChargeReport(ctx, uuid.New(), inPeriod.AddDate(0, 0, -14), inPeriod.AddDate(0, 0, 17))
+	require.NoError(t, err)
+
+	require.Len(t, report.Lines, 1)
+	assert.Equal(t, "DE123456789", report.Lines[0].VATNumber)
+	assert.Equal(t, "ACME GmbH", report.Lines[0].CustomerName)
+	assert.Equal(t, 2, report.Lines[0].InvoiceCount)
+	assert.Equal(t, int64(100000), report.Lines[0].AmountCents)
+	assert.Equal(t, "WAPIAAAAY1234567", report.Lines[0].ConsultationNumber)
+}
+
+func TestTaxReportService_USStateTaxReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewTaxReportService(mockQuerier)
+	ctx := context.Background()
+
+	inPeriod := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	invoice := taxReportInvoice(t, "paid", inPeriod, 10000,
+		business.TaxLineItem{TaxType: "sales", Jurisdiction: "US-CA", Rate: 0.0725, TaxableAmount: 10000, TaxAmountCents: 725},
+		business.TaxLineItem{TaxType: "sales", Jurisdiction: "US-CA-LOS ANGELES", Rate: 0.0225, TaxableAmount: 10000, TaxAmountCents: 225},
+	)
+	invoice.Currency = "usd"
+	invoice.PaidAt = pgtype.Timestamptz{Time: inPeriod, Valid: true}
+
+	mockQuerier.EXPECT().ListTaxReportInvoices(ctx, gomock.Any()).Return([]db.ListTaxReportInvoicesRow{invoice}, nil)
+	mockQuerier.EXPECT().ListTaxReportRefunds(ctx, gomock.Any()).Return(nil, nil)
+
+	report, err := service.GetUSStateTaxReport(ctx, uuid.New(), inPeriod.AddDate(0, 0, -14), inPeriod.AddDate(0, 0, 17))
+	require.NoError(t, err)
+
+	require.Len(t, report.Lines, 1)
+	line := report.Lines[0]
+	assert.Equal(t, "CA", line.State)
+	assert.Equal(t, "USD", line.Currency)
+	assert.Equal(t, 1, line.TransactionCount)
+	assert.Equal(t, int64(10000), line.GrossSalesCents)
+	assert.Equal(t, int64(10000), line.TaxableSalesCents)
+	assert.Equal(t, int64(725), line.StateTaxCents)
+	assert.Equal(t, int64(225), line.LocalTaxCents)
+	assert.Equal(t, int64(950), line.NetTaxCents)
+	assert.Equal(t, int64(950), line.TaxCollectedCents)
+}
+
+func TestQuarterPeriod(t *testing.T) {
+	start, end, err := services.QuarterPeriod(2025, 4)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, err = services.QuarterPeriod(2025, 5)
+	assert.ErrorIs(t, err, services.ErrInvalidReportPeriod)
+}
@@ -2,27 +2,61 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/cyphera/cyphera-api/libs/go/client/tax_provider"
 	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/interfaces"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
 	"github.com/cyphera/cyphera-api/libs/go/types/business"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
+const (
+	// DefaultTaxProductType is the rate used for product types without a rate of their own
+	DefaultTaxProductType = "default"
+	// InternalTaxProvider names calculations made from the built-in rate tables
+	InternalTaxProvider = "internal"
+
+	// defaultTaxJurisdictionCode is used when neither the customer nor the business address is known.
+	// Workspaces do not carry a tax address yet.
+	defaultTaxJurisdictionCode = "US"
+	// noTaxRulesVersion is recorded in the audit trail when no rate table contributed to a calculation
+	noTaxRulesVersion = "none"
+)
+
+var (
+	// ErrInvalidTaxRate is returned when a tax rate or its effective period is out of range
+	ErrInvalidTaxRate = errors.New("invalid tax rate")
+	// ErrTaxRateOverlap is returned when a new rate overlaps an existing rate for the same jurisdiction and product type
+	ErrTaxRateOverlap = errors.New("tax rate overlaps an existing rate for this jurisdiction and product type")
+	// ErrTaxRateInEffect is returned when deleting a rate that has already been used
+	ErrTaxRateInEffect = errors.New("tax rate has already taken effect; end it instead")
+)
+
 // TaxService handles comprehensive tax calculation and compliance
 type TaxService struct {
-	queries db.Querier
-	logger  *zap.Logger
+	queries  db.Querier
+	provider interfaces.TaxProvider
+	verifier interfaces.TaxIDVerificationService
+	pool     *pgxpool.Pool
+	logger   *zap.Logger
 }
 
-// NewTaxService creates a new tax service
+// NewTaxService creates a new tax service that calculates tax from the built-in rate tables
 func NewTaxService(queries db.Querier) *TaxService {
 	return &TaxService{
 		queries: queries,
@@ -30,6 +64,39 @@ func NewTaxService(queries db.Querier) *TaxService {
 	}
 }
 
+// NewTaxServiceWithDependencies creates a tax service that calculates tax through an external
+// provider, using the built-in rate tables whenever the provider is unavailable, and verifies customer VAT
+// numbers with a registry before applying reverse charge. Either dependency may be nil.
+func NewTaxServiceWithDependencies(queries db.Querier, provider interfaces.TaxProvider, verifier interfaces.TaxIDVerificationService) *TaxService {
+	return &TaxService{
+		queries:  queries,
+		provider: provider,
+		verifier: verifier,
+		logger:   logger.Log,
+	}
+}
+
+// WithTransactions creates a new tax service that makes rate table changes in a single transaction
+func (s *TaxService) WithTransactions(pool *pgxpool.Pool) *TaxService {
+	return &TaxService{
+		queries:  s.queries,
+		provider: s.provider,
+		verifier: s.verifier,
+		pool:     pool,
+		logger:   s.logger,
+	}
+}
+
+// inTransaction runs fn against a database transaction, or directly against the queries when no pool was given
+func (s *TaxService) inTransaction(ctx context.Context, fn func(queries db.Querier) error) error {
+	if s.pool == nil {
+		return fn(s.queries)
+	}
+	return helpers.WithTransaction(ctx, s.pool, func(tx pgx.Tx) error {
+		return fn(db.New(tx))
+	})
+}
+
 // CalculateTax performs comprehensive tax calculation
 func (s *TaxService) CalculateTax(ctx context.Context, params params.TaxCalculationParams) (*responses.TaxCalculationResult, error) {
 	s.logger.Info("Calculating tax",
@@ -44,8 +111,9 @@ func (s *TaxService) CalculateTax(ctx context.Context, params params.TaxCalculat
 		TaxBreakdown:  []business.TaxLineItem{},
 		CalculatedAt:  time.Now(),
 		Confidence:    1.0,
+		Provider:      InternalTaxProvider,
 		AuditTrail: business.TaxAuditTrail{
-			RulesVersion: "v2024.1",
+			RulesVersion: noTaxRulesVersion,
 			AppliedRules: []string{},
 			Notes:        []string{},
 		},
@@ -61,53 +129,188 @@ func (s *TaxService) CalculateTax(ctx context.Context, params params.TaxCalculat
 		return result, nil
 	}
 
-	// Determine tax jurisdiction based on addresses
-	jurisdiction, err := s.determineJurisdiction(ctx, params)
+	// Determine tax jurisdictions based on addresses, with the rates in force right now
+	jurisdictions, err := s.determineJurisdictions(ctx, params, result.CalculatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine jurisdiction: %w", err)
 	}
+	jurisdiction := jurisdictions[0]
 
-	result.AppliedJurisdictions = []string{jurisdiction.Code}
+	result.AppliedJurisdictions = make([]string, 0, len(jurisdictions))
+	for _, j := range jurisdictions {
+		result.AppliedJurisdictions = append(result.AppliedJurisdictions, j.Code)
+	}
 	result.AuditTrail.DetectedLocation = params.CustomerAddress
 
-	// Handle B2B transactions with reverse charge
-	if params.IsB2B && s.shouldApplyReverseCharge(jurisdiction, params) {
+	// Handle B2B transactions with reverse charge. This is decided here, against the verified VAT
+	// number, before any provider is asked, so reverse-charged customers are never charged VAT.
+	if params.IsB2B && s.shouldApplyReverseCharge(ctx, jurisdiction, params, result) {
 		return s.calculateReverseCharge(ctx, params, jurisdiction, result)
 	}
 
+	// Providers price by destination, so transactions without an address stay on the internal tables
+	if s.provider != nil && (params.CustomerAddress != nil || params.BusinessAddress != nil) {
+		providerResult, err := s.provider.CalculateTax(ctx, params)
+		if err == nil {
+			if providerResult.AuditTrail.TaxIDVerification == nil {
+				providerResult.AuditTrail.TaxIDVerification = result.AuditTrail.TaxIDVerification
+			}
+			return providerResult, nil
+		}
+		if !errors.Is(err, tax_provider.ErrUnavailable) {
+			return nil, fmt.Errorf("tax provider %s failed to calculate tax: %w", s.provider.Name(), err)
+		}
+		s.logger.Warn("Tax provider unavailable, falling back to internal rates",
+			zap.String("provider", s.provider.Name()),
+			zap.Error(err))
+		result.AuditTrail.Notes = append(result.AuditTrail.Notes,
+			fmt.Sprintf("Tax provider %s unavailable; calculated from internal rates", s.provider.Name()))
+	}
+
 	// Calculate standard tax
-	return s.calculateStandardTax(ctx, params, jurisdiction, result)
+	return s.calculateStandardTax(ctx, params, jurisdictions, result)
+}
+
+// CommitCalculation records a provider's calculation with that provider once the invoice it taxes
+// has been stored, and returns the provider's transaction ID. Calculations made from the internal
+// tables, or without a transaction reference, have nothing to record.
+func (s *TaxService) CommitCalculation(ctx context.Context, params params.TaxCalculationParams, calculation *responses.TaxCalculationResult) (string, error) {
+	if s.provider == nil || calculation == nil || calculation.Provider != s.provider.Name() || params.TransactionReference == "" {
+		return "", nil
+	}
+	transactionID, err := s.provider.CommitTransaction(ctx, params, calculation)
+	if err != nil {
+		return "", fmt.Errorf("failed to record tax transaction with %s: %w", s.provider.Name(), err)
+	}
+	return transactionID, nil
 }
 
-// determineJurisdiction determines the applicable tax jurisdiction
-func (s *TaxService) determineJurisdiction(ctx context.Context, params params.TaxCalculationParams) (*business.TaxJurisdiction, error) {
+// determineJurisdictions resolves the jurisdictions that tax a transaction: the state, province or
+// country first, followed by any local jurisdictions of the customer's city.
+func (s *TaxService) determineJurisdictions(ctx context.Context, params params.TaxCalculationParams, at time.Time) ([]*business.TaxJurisdiction, error) {
 	// Priority order: Customer address > Business address > Workspace default
 	address := params.CustomerAddress
 	if address == nil {
 		address = params.BusinessAddress
 	}
 
+	var found db.TaxJurisdiction
+	var err error
 	if address == nil {
-		// Use workspace default jurisdiction
-		workspace, err := s.queries.GetWorkspace(ctx, params.WorkspaceID)
-		if err != nil {
+		if _, err := s.queries.GetWorkspace(ctx, params.WorkspaceID); err != nil {
 			return nil, fmt.Errorf("failed to get workspace: %w", err)
 		}
+		found, err = s.queries.GetTaxJurisdictionByCode(ctx, defaultTaxJurisdictionCode)
+	} else {
+		state := strings.ToUpper(strings.TrimSpace(address.State))
+		found, err = s.queries.FindTaxJurisdiction(ctx, db.FindTaxJurisdictionParams{
+			CountryCode:     strings.ToUpper(strings.TrimSpace(address.Country)),
+			SubdivisionCode: pgtype.Text{String: state, Valid: state != ""},
+		})
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		country := defaultTaxJurisdictionCode
+		if address != nil {
+			country = address.Country
+		}
+		// Places without a jurisdiction are not taxed
+		return []*business.TaxJurisdiction{{
+			Code:          country,
+			Name:          country,
+			Type:          "country",
+			TaxType:       "tax",
+			TaxRates:      map[string]float64{DefaultTaxProductType: 0.0},
+			Thresholds:    map[string]int64{},
+			RulesVersions: map[string]string{},
+			IsActive:      true,
+			EffectiveDate: at,
+		}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tax jurisdiction: %w", err)
+	}
+
+	rows := []db.TaxJurisdiction{found}
+	if address != nil && address.City != "" && found.SubdivisionCode.Valid {
+		locals, err := s.queries.ListLocalTaxJurisdictions(ctx, db.ListLocalTaxJurisdictionsParams{
+			ParentID: pgtype.UUID{Bytes: found.ID, Valid: true},
+			Locality: pgtype.Text{String: strings.ToUpper(strings.TrimSpace(address.City)), Valid: true},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get local tax jurisdictions: %w", err)
+		}
+		rows = append(rows, locals...)
+	}
+
+	return s.loadJurisdictionRates(ctx, rows, at)
+}
+
+// loadJurisdictionRates attaches the rates in force at a point in time to each jurisdiction
+func (s *TaxService) loadJurisdictionRates(ctx context.Context, rows []db.TaxJurisdiction, at time.Time) ([]*business.TaxJurisdiction, error) {
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	rates, err := s.queries.ListEffectiveTaxRates(ctx, db.ListEffectiveTaxRatesParams{
+		JurisdictionIds: ids,
+		EffectiveAt:     pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax rates: %w", err)
+	}
+
+	jurisdictions := make([]*business.TaxJurisdiction, len(rows))
+	byID := make(map[uuid.UUID]*business.TaxJurisdiction, len(rows))
+	for i, row := range rows {
+		jurisdictions[i] = &business.TaxJurisdiction{
+			ID:            row.ID,
+			Code:          row.Code,
+			Name:          row.Name,
+			Type:          row.JurisdictionType,
+			TaxType:       row.TaxType,
+			TaxRates:      map[string]float64{},
+			Thresholds:    map[string]int64{},
+			RulesVersions: map[string]string{},
+			IsActive:      row.IsActive,
+			EffectiveDate: row.CreatedAt.Time,
+		}
+		byID[row.ID] = jurisdictions[i]
+	}
 
-		// Parse default tax jurisdiction from workspace
-		return s.getDefaultJurisdiction(workspace), nil
+	for _, rate := range rates {
+		jurisdiction, ok := byID[rate.JurisdictionID]
+		if !ok {
+			continue
+		}
+		jurisdiction.TaxRates[rate.ProductType] = helpers.GetNumericFloat(rate.Rate)
+		jurisdiction.RulesVersions[rate.ProductType] = rate.RulesVersion
+		if rate.ThresholdCents > 0 {
+			jurisdiction.Thresholds[rate.ProductType] = rate.ThresholdCents
+		}
+		if rate.EffectiveFrom.Time.After(jurisdiction.EffectiveDate) {
+			jurisdiction.EffectiveDate = rate.EffectiveFrom.Time
+		}
 	}
 
-	// Determine jurisdiction from address
-	return s.getJurisdictionFromAddress(address), nil
+	return jurisdictions, nil
+}
+
+// applicableRate returns the jurisdiction's rate key for a product type, falling back to the default rate
+func applicableRate(jurisdiction *business.TaxJurisdiction, productType string) (string, bool) {
+	if _, exists := jurisdiction.TaxRates[productType]; exists {
+		return productType, true
+	}
+	_, exists := jurisdiction.TaxRates[DefaultTaxProductType]
+	return DefaultTaxProductType, exists
 }
 
 // shouldApplyReverseCharge determines if reverse charge should be applied for B2B
-func (s *TaxService) shouldApplyReverseCharge(jurisdiction *business.TaxJurisdiction, params params.TaxCalculationParams) bool {
+func (s *TaxService) shouldApplyReverseCharge(ctx context.Context, jurisdiction *business.TaxJurisdiction, params params.TaxCalculationParams, result *responses.TaxCalculationResult) bool {
 	// EU reverse charge logic
 	if strings.HasPrefix(jurisdiction.Code, "EU-") && params.CustomerVATNumber != nil {
-		return s.isValidEUVATNumber(*params.CustomerVATNumber) &&
-			s.isDigitalService(params.ProductType)
+		return s.isDigitalService(params.ProductType) &&
+			s.isVerifiedEUVATNumber(ctx, params, result)
 	}
 
 	// Other jurisdictions with reverse charge rules
@@ -124,7 +327,7 @@ func (s *TaxService) shouldApplyReverseCharge(jurisdiction *business.TaxJurisdic
 func (s *TaxService) calculateReverseCharge(ctx context.Context, params params.TaxCalculationParams, jurisdiction *business.TaxJurisdiction, result *responses.TaxCalculationResult) (*responses.TaxCalculationResult, error) {
 	// In reverse charge, customer pays the tax in their jurisdiction
 	taxLineItem := business.TaxLineItem{
-		TaxType:        "vat",
+		TaxType:        jurisdiction.TaxType,
 		Jurisdiction:   jurisdiction.Code,
 		Rate:           0.0, // Merchant doesn't charge tax
 		TaxableAmount:  params.AmountCents,
@@ -133,6 +336,13 @@ func (s *TaxService) calculateReverseCharge(ctx context.Context, params params.T
 		IsReversCharge: true,
 	}
 
+	// Record the rules the customer's self-assessment falls under
+	if key, ok := applicableRate(jurisdiction, params.ProductType); ok {
+		if version := jurisdiction.RulesVersions[key]; version != "" {
+			result.AuditTrail.RulesVersion = version
+		}
+	}
+
 	result.TaxBreakdown = append(result.TaxBreakdown, taxLineItem)
 	result.TotalTaxCents = 0
 	result.TotalAmountCents = params.AmountCents
@@ -143,75 +353,89 @@ func (s *TaxService) calculateReverseCharge(ctx context.Context, params params.T
 	return result, nil
 }
 
-// calculateStandardTax calculates standard tax rates
-func (s *TaxService) calculateStandardTax(ctx context.Context, params params.TaxCalculationParams, jurisdiction *business.TaxJurisdiction, result *responses.TaxCalculationResult) (*responses.TaxCalculationResult, error) {
-	// Get applicable tax rate for product type
-	taxRate, exists := jurisdiction.TaxRates[params.ProductType]
-	if !exists {
-		taxRate = jurisdiction.TaxRates["default"]
-	}
+// calculateStandardTax calculates standard tax for the jurisdiction and its local jurisdictions
+func (s *TaxService) calculateStandardTax(ctx context.Context, params params.TaxCalculationParams, jurisdictions []*business.TaxJurisdiction, result *responses.TaxCalculationResult) (*responses.TaxCalculationResult, error) {
+	var totalTaxCents int64
+	versions := map[string]bool{}
+
+	for i, jurisdiction := range jurisdictions {
+		// Get applicable tax rate for product type
+		key, exists := applicableRate(jurisdiction, params.ProductType)
+		taxRate := jurisdiction.TaxRates[key]
+		if !exists && i > 0 {
+			// Local jurisdictions without a rate in force don't add a line
+			continue
+		}
+		if version := jurisdiction.RulesVersions[key]; version != "" {
+			versions[version] = true
+		}
 
-	// Check if amount meets minimum threshold
-	threshold, hasThreshold := jurisdiction.Thresholds[params.ProductType]
-	if hasThreshold && params.AmountCents < threshold {
-		taxRate = 0.0
-		result.AuditTrail.Notes = append(result.AuditTrail.Notes,
-			fmt.Sprintf("Amount below tax threshold: %d < %d", params.AmountCents, threshold))
+		// Check if amount meets minimum threshold
+		threshold, hasThreshold := jurisdiction.Thresholds[key]
+		if hasThreshold && params.AmountCents < threshold {
+			taxRate = 0.0
+			result.AuditTrail.Notes = append(result.AuditTrail.Notes,
+				fmt.Sprintf("Amount below %s tax threshold: %d < %d", jurisdiction.Code, params.AmountCents, threshold))
+		}
+
+		taxAmountCents := calculateTaxAmount(params.AmountCents, taxRate)
+
+		result.TaxBreakdown = append(result.TaxBreakdown, business.TaxLineItem{
+			TaxType:        jurisdiction.TaxType,
+			Jurisdiction:   jurisdiction.Code,
+			Rate:           taxRate,
+			TaxableAmount:  params.AmountCents,
+			TaxAmountCents: taxAmountCents,
+			Description:    fmt.Sprintf("%s - %s", jurisdiction.Name, params.ProductType),
+			IsReversCharge: false,
+		})
+		totalTaxCents = addCapped(totalTaxCents, taxAmountCents)
+		result.AuditTrail.AppliedRules = append(result.AuditTrail.AppliedRules,
+			fmt.Sprintf("STANDARD_TAX_%s", jurisdiction.Code))
+	}
+
+	if len(versions) > 0 {
+		rulesVersions := make([]string, 0, len(versions))
+		for version := range versions {
+			rulesVersions = append(rulesVersions, version)
+		}
+		sort.Strings(rulesVersions)
+		result.AuditTrail.RulesVersion = strings.Join(rulesVersions, ",")
 	}
 
-	// Calculate tax amount with overflow protection
-	var taxAmountCents int64
-	if params.AmountCents > math.MaxInt64/2 || taxRate > 0.5 {
+	result.TotalTaxCents = totalTaxCents
+	result.TotalAmountCents = addCapped(params.AmountCents, totalTaxCents)
+
+	return result, nil
+}
+
+// calculateTaxAmount applies a rate to an amount with overflow protection
+func calculateTaxAmount(amountCents int64, taxRate float64) int64 {
+	if amountCents > math.MaxInt64/2 || taxRate > 0.5 {
 		// For very large amounts or high tax rates, use safer calculation
 		// Check if multiplication would overflow
 		maxAmountForRate := int64(float64(math.MaxInt64) / taxRate)
-		if params.AmountCents > maxAmountForRate {
-			taxAmountCents = math.MaxInt64 / 2 // Cap the tax amount
-		} else {
-			taxAmountCents = int64(float64(params.AmountCents) * taxRate)
-		}
-	} else {
-		taxAmountCents = int64(float64(params.AmountCents) * taxRate)
-	}
-
-	// Calculate total with overflow protection
-	var totalAmountCents int64
-	if taxAmountCents >= 0 {
-		// Positive tax amount
-		if params.AmountCents > math.MaxInt64-taxAmountCents {
-			// Would overflow, cap at max value
-			totalAmountCents = math.MaxInt64
-		} else {
-			totalAmountCents = params.AmountCents + taxAmountCents
-		}
-	} else {
-		// Negative tax amount (refund scenario)
-		if params.AmountCents < math.MinInt64-taxAmountCents {
-			// Would underflow, cap at min value
-			totalAmountCents = math.MinInt64
-		} else {
-			totalAmountCents = params.AmountCents + taxAmountCents
+		if amountCents > maxAmountForRate {
+			return math.MaxInt64 / 2 // Cap the tax amount
 		}
 	}
+	return int64(float64(amountCents) * taxRate)
+}
 
-	// Create tax line item
-	taxLineItem := business.TaxLineItem{
-		TaxType:        s.getTaxTypeForJurisdiction(jurisdiction.Code),
-		Jurisdiction:   jurisdiction.Code,
-		Rate:           taxRate,
-		TaxableAmount:  params.AmountCents,
-		TaxAmountCents: taxAmountCents,
-		Description:    fmt.Sprintf("%s - %s", jurisdiction.Name, params.ProductType),
-		IsReversCharge: false,
+// addCapped adds two amounts, capping at the int64 range instead of overflowing
+func addCapped(a, b int64) int64 {
+	if b >= 0 {
+		// Positive amount
+		if a > math.MaxInt64-b {
+			return math.MaxInt64
+		}
+		return a + b
 	}
-
-	result.TaxBreakdown = append(result.TaxBreakdown, taxLineItem)
-	result.TotalTaxCents = taxAmountCents
-	result.TotalAmountCents = totalAmountCents
-	result.AuditTrail.AppliedRules = append(result.AuditTrail.AppliedRules,
-		fmt.Sprintf("STANDARD_TAX_%s", jurisdiction.Code))
-
-	return result, nil
+	// Negative amount (refund scenario)
+	if a < math.MinInt64-b {
+		return math.MinInt64
+	}
+	return a + b
 }
 
 // StoreTaxCalculation stores tax calculation for audit purposes
@@ -267,210 +491,301 @@ func (s *TaxService) StoreTaxCalculation(ctx context.Context, paymentID uuid.UUI
 
 // GetTaxRatesForJurisdiction retrieves current tax rates for a jurisdiction
 func (s *TaxService) GetTaxRatesForJurisdiction(ctx context.Context, jurisdictionCode string) (*business.TaxJurisdiction, error) {
-	// This would normally query a tax rates database or external service
-	return s.getJurisdictionByCode(jurisdictionCode), nil
-}
+	jurisdiction, err := s.queries.GetTaxJurisdictionByCode(ctx, jurisdictionCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax jurisdiction %q: %w", jurisdictionCode, err)
+	}
 
-// isEUCountry checks if a country code is in the EU
-func (s *TaxService) isEUCountry(countryCode string) bool {
-	euCountries := map[string]bool{
-		"AT": true, "BE": true, "BG": true, "HR": true, "CY": true, "CZ": true,
-		"DK": true, "EE": true, "FI": true, "FR": true, "DE": true, "GR": true,
-		"HU": true, "IE": true, "IT": true, "LV": true, "LT": true, "LU": true,
-		"MT": true, "NL": true, "PL": true, "PT": true, "RO": true, "SK": true,
-		"SI": true, "ES": true, "SE": true,
+	jurisdictions, err := s.loadJurisdictionRates(ctx, []db.TaxJurisdiction{jurisdiction}, time.Now())
+	if err != nil {
+		return nil, err
 	}
-	return euCountries[countryCode]
+	return jurisdictions[0], nil
 }
 
-// getCountryName returns a human-readable country name
-func (s *TaxService) getCountryName(countryCode string) string {
-	countryNames := map[string]string{
-		"AT": "Austria", "BE": "Belgium", "BG": "Bulgaria", "HR": "Croatia", "CY": "Cyprus", "CZ": "Czech Republic",
-		"DK": "Denmark", "EE": "Estonia", "FI": "Finland", "FR": "France", "DE": "Germany", "GR": "Greece",
-		"HU": "Hungary", "IE": "Ireland", "IT": "Italy", "LV": "Latvia", "LT": "Lithuania", "LU": "Luxembourg",
-		"MT": "Malta", "NL": "Netherlands", "PL": "Poland", "PT": "Portugal", "RO": "Romania", "SK": "Slovakia",
-		"SI": "Slovenia", "ES": "Spain", "SE": "Sweden",
-	}
-	if name, exists := countryNames[countryCode]; exists {
-		return name
+// ListJurisdictions lists tax jurisdictions, optionally for one country
+func (s *TaxService) ListJurisdictions(ctx context.Context, countryCode string, limit, offset int32) ([]db.TaxJurisdiction, error) {
+	countryCode = strings.ToUpper(strings.TrimSpace(countryCode))
+	jurisdictions, err := s.queries.ListTaxJurisdictions(ctx, db.ListTaxJurisdictionsParams{
+		CountryCode: pgtype.Text{String: countryCode, Valid: countryCode != ""},
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tax jurisdictions: %w", err)
 	}
-	return countryCode
+	return jurisdictions, nil
 }
 
-// ValidateVATNumber validates EU VAT numbers
-func (s *TaxService) ValidateVATNumber(ctx context.Context, vatNumber, countryCode string) (bool, error) {
-	// This is a simplified validation - in production you'd use VIES or similar service
-	return s.isValidEUVATNumber(vatNumber), nil
+// GetJurisdiction retrieves a tax jurisdiction by ID
+func (s *TaxService) GetJurisdiction(ctx context.Context, id uuid.UUID) (*db.TaxJurisdiction, error) {
+	jurisdiction, err := s.queries.GetTaxJurisdiction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &jurisdiction, nil
 }
 
-// Helper functions
-
-// getDefaultJurisdiction returns default tax jurisdiction for workspace
-func (s *TaxService) getDefaultJurisdiction(workspace db.Workspace) *business.TaxJurisdiction {
-	// Parse from workspace configuration or use US as default
-	return &business.TaxJurisdiction{
-		Code: "US",
-		Name: "United States",
-		Type: "country",
-		TaxRates: map[string]float64{
-			"digital":  0.0, // No federal tax on digital goods
-			"physical": 0.0, // Varies by state
-			"service":  0.0, // Varies by state
-			"default":  0.0,
-		},
-		Thresholds: map[string]int64{
-			"default": 0, // No minimum threshold
-		},
-		IsActive:      true,
-		EffectiveDate: time.Now(),
+// CreateJurisdiction creates a tax jurisdiction
+func (s *TaxService) CreateJurisdiction(ctx context.Context, params params.CreateTaxJurisdictionParams) (*db.TaxJurisdiction, error) {
+	taxType := params.TaxType
+	if taxType == "" {
+		taxType = "tax"
+	}
+
+	parentID := pgtype.UUID{}
+	if params.ParentID != nil {
+		if _, err := s.queries.GetTaxJurisdiction(ctx, *params.ParentID); err != nil {
+			return nil, fmt.Errorf("failed to get parent jurisdiction: %w", err)
+		}
+		parentID = pgtype.UUID{Bytes: *params.ParentID, Valid: true}
+	}
+
+	subdivision := strings.ToUpper(strings.TrimSpace(params.SubdivisionCode))
+	locality := strings.ToUpper(strings.TrimSpace(params.Locality))
+	jurisdiction, err := s.queries.CreateTaxJurisdiction(ctx, db.CreateTaxJurisdictionParams{
+		Code:             params.Code,
+		Name:             params.Name,
+		JurisdictionType: params.JurisdictionType,
+		CountryCode:      strings.ToUpper(params.CountryCode),
+		SubdivisionCode:  pgtype.Text{String: subdivision, Valid: subdivision != ""},
+		Locality:         pgtype.Text{String: locality, Valid: locality != ""},
+		ParentID:         parentID,
+		TaxType:          taxType,
+		IsActive:         params.IsActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tax jurisdiction: %w", err)
 	}
+
+	s.logger.Info("Created tax jurisdiction",
+		zap.String("jurisdiction_id", jurisdiction.ID.String()),
+		zap.String("code", jurisdiction.Code))
+
+	return &jurisdiction, nil
 }
 
-// getJurisdictionFromAddress determines jurisdiction from address
-func (s *TaxService) getJurisdictionFromAddress(address *business.Address) *business.TaxJurisdiction {
-	// Simplified jurisdiction mapping - in production this would be more comprehensive
-	jurisdictions := map[string]*business.TaxJurisdiction{
-		"US": {
-			Code:          "US-" + address.State,
-			Name:          fmt.Sprintf("United States - %s", address.State),
-			Type:          "state",
-			TaxRates:      s.getUSStateTaxRates(address.State),
-			Thresholds:    map[string]int64{"default": 0},
-			IsActive:      true,
-			EffectiveDate: time.Now(),
-		},
-		"CA": {
-			Code:          "CA-" + address.State,
-			Name:          fmt.Sprintf("Canada - %s", address.State),
-			Type:          "province",
-			TaxRates:      s.getCanadaTaxRates(address.State),
-			Thresholds:    map[string]int64{"default": 0},
-			IsActive:      true,
-			EffectiveDate: time.Now(),
-		},
-		"GB": {
-			Code: "UK",
-			Name: "United Kingdom",
-			Type: "country",
-			TaxRates: map[string]float64{
-				"digital":  0.20,
-				"physical": 0.20,
-				"service":  0.20,
-				"default":  0.20,
-			},
-			Thresholds:    map[string]int64{"default": 0},
-			IsActive:      true,
-			EffectiveDate: time.Now(),
-		},
+// UpdateJurisdiction updates the name, tax type or active flag of a tax jurisdiction
+func (s *TaxService) UpdateJurisdiction(ctx context.Context, params params.UpdateTaxJurisdictionParams) (*db.TaxJurisdiction, error) {
+	update := db.UpdateTaxJurisdictionParams{ID: params.ID}
+	if params.Name != nil {
+		update.Name = pgtype.Text{String: *params.Name, Valid: true}
+	}
+	if params.TaxType != nil {
+		update.TaxType = pgtype.Text{String: *params.TaxType, Valid: true}
+	}
+	if params.IsActive != nil {
+		update.IsActive = pgtype.Bool{Bool: *params.IsActive, Valid: true}
 	}
 
-	if jurisdiction, exists := jurisdictions[address.Country]; exists {
-		return jurisdiction
+	jurisdiction, err := s.queries.UpdateTaxJurisdiction(ctx, update)
+	if err != nil {
+		return nil, err
 	}
+	return &jurisdiction, nil
+}
 
-	// Check if it's an EU country
-	if s.isEUCountry(address.Country) {
-		return &business.TaxJurisdiction{
-			Code: "EU-" + address.Country,
-			Name: s.getCountryName(address.Country),
-			Type: "country",
-			TaxRates: map[string]float64{
-				"digital":  0.19, // Standard EU VAT rate approximation
-				"physical": 0.19,
-				"service":  0.19,
-				"default":  0.19,
-			},
-			Thresholds:    map[string]int64{"default": 0},
-			IsActive:      true,
-			EffectiveDate: time.Now(),
-		}
+// ListRates lists every rate of a jurisdiction, past, current and scheduled
+func (s *TaxService) ListRates(ctx context.Context, jurisdictionID uuid.UUID) ([]db.TaxRate, error) {
+	if _, err := s.queries.GetTaxJurisdiction(ctx, jurisdictionID); err != nil {
+		return nil, err
 	}
 
-	// Default to no tax for unknown jurisdictions
-	return &business.TaxJurisdiction{
-		Code:          address.Country,
-		Name:          address.Country,
-		Type:          "country",
-		TaxRates:      map[string]float64{"default": 0.0},
-		Thresholds:    map[string]int64{"default": 0},
-		IsActive:      true,
-		EffectiveDate: time.Now(),
+	rates, err := s.queries.ListTaxRatesByJurisdiction(ctx, jurisdictionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tax rates: %w", err)
 	}
+	return rates, nil
 }
 
-// getUSStateTaxRates returns tax rates for US states
-func (s *TaxService) getUSStateTaxRates(state string) map[string]float64 {
-	// Simplified state tax rates - in production this would be comprehensive and up-to-date
-	stateTaxRates := map[string]map[string]float64{
-		"CA": {"digital": 0.0725, "physical": 0.0725, "service": 0.0725, "default": 0.0725},
-		"NY": {"digital": 0.08, "physical": 0.08, "service": 0.08, "default": 0.08},
-		"TX": {"digital": 0.0625, "physical": 0.0625, "service": 0.0625, "default": 0.0625},
-		"FL": {"digital": 0.06, "physical": 0.06, "service": 0.06, "default": 0.06},
+// CreateRate adds a versioned rate to a jurisdiction. An open-ended rate ends the
+// open-ended rate currently in force for the same product type when it takes effect;
+// any other overlap is rejected. The overlap check, supersede and insert run in one
+// transaction holding a lock on the jurisdiction, so concurrent changes cannot both
+// pass the check.
+func (s *TaxService) CreateRate(ctx context.Context, params params.CreateTaxRateParams) (*db.TaxRate, error) {
+	productType := params.ProductType
+	if productType == "" {
+		productType = DefaultTaxProductType
+	}
+	if params.Rate < 0 || params.Rate >= 1 {
+		return nil, fmt.Errorf("%w: rate must be a fraction between 0 and 1", ErrInvalidTaxRate)
+	}
+	if params.ThresholdCents < 0 {
+		return nil, fmt.Errorf("%w: threshold cannot be negative", ErrInvalidTaxRate)
+	}
+	if params.RulesVersion == "" {
+		return nil, fmt.Errorf("%w: rules version is required", ErrInvalidTaxRate)
+	}
+	if params.EffectiveTo != nil && !params.EffectiveTo.After(params.EffectiveFrom) {
+		return nil, fmt.Errorf("%w: effective_to must be after effective_from", ErrInvalidTaxRate)
 	}
 
-	if rates, exists := stateTaxRates[state]; exists {
-		return rates
+	var rate pgtype.Numeric
+	if err := rate.Scan(strconv.FormatFloat(params.Rate, 'f', -1, 64)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTaxRate, err)
 	}
 
-	return map[string]float64{"default": 0.0} // No tax for unknown states
-}
+	effectiveFrom := pgtype.Timestamptz{Time: params.EffectiveFrom, Valid: true}
+	effectiveTo := pgtype.Timestamptz{}
+	if params.EffectiveTo != nil {
+		effectiveTo = pgtype.Timestamptz{Time: *params.EffectiveTo, Valid: true}
+	}
+
+	var created db.TaxRate
+	err := s.inTransaction(ctx, func(queries db.Querier) error {
+		if _, err := queries.LockTaxJurisdiction(ctx, params.JurisdictionID); err != nil {
+			return err
+		}
 
-// getCanadaTaxRates returns tax rates for Canadian provinces
-func (s *TaxService) getCanadaTaxRates(province string) map[string]float64 {
-	// Simplified Canadian tax rates (GST + PST/HST)
-	provinceTaxRates := map[string]map[string]float64{
-		"ON": {"digital": 0.13, "physical": 0.13, "service": 0.13, "default": 0.13},             // HST
-		"BC": {"digital": 0.12, "physical": 0.12, "service": 0.12, "default": 0.12},             // GST + PST
-		"AB": {"digital": 0.05, "physical": 0.05, "service": 0.05, "default": 0.05},             // GST only
-		"QC": {"digital": 0.14975, "physical": 0.14975, "service": 0.14975, "default": 0.14975}, // GST + QST
+		overlapping, err := queries.CountOverlappingTaxRates(ctx, db.CountOverlappingTaxRatesParams{
+			JurisdictionID: params.JurisdictionID,
+			ProductType:    productType,
+			EffectiveTo:    effectiveTo,
+			EffectiveFrom:  effectiveFrom,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check overlapping tax rates: %w", err)
+		}
+		if overlapping > 0 {
+			return ErrTaxRateOverlap
+		}
+
+		if params.EffectiveTo == nil {
+			superseded, err := queries.SupersedeOpenTaxRate(ctx, db.SupersedeOpenTaxRateParams{
+				EffectiveFrom:  effectiveFrom,
+				JurisdictionID: params.JurisdictionID,
+				ProductType:    productType,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to supersede current tax rate: %w", err)
+			}
+			if superseded > 0 {
+				s.logger.Info("Superseded open-ended tax rate",
+					zap.String("jurisdiction_id", params.JurisdictionID.String()),
+					zap.String("product_type", productType),
+					zap.Time("effective_to", params.EffectiveFrom))
+			}
+		}
+
+		created, err = queries.CreateTaxRate(ctx, db.CreateTaxRateParams{
+			JurisdictionID: params.JurisdictionID,
+			ProductType:    productType,
+			Rate:           rate,
+			ThresholdCents: params.ThresholdCents,
+			EffectiveFrom:  effectiveFrom,
+			EffectiveTo:    effectiveTo,
+			RulesVersion:   params.RulesVersion,
+			Description:    pgtype.Text{String: params.Description, Valid: params.Description != ""},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create tax rate: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if rates, exists := provinceTaxRates[province]; exists {
-		return rates
+	s.logger.Info("Created tax rate",
+		zap.String("tax_rate_id", created.ID.String()),
+		zap.String("jurisdiction_id", params.JurisdictionID.String()),
+		zap.String("product_type", productType),
+		zap.String("rules_version", params.RulesVersion))
+
+	return &created, nil
+}
+
+// EndRate sets or brings forward the date a rate stops applying
+func (s *TaxService) EndRate(ctx context.Context, id uuid.UUID, effectiveTo time.Time) (*db.TaxRate, error) {
+	rate, err := s.queries.GetTaxRate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !effectiveTo.After(rate.EffectiveFrom.Time) {
+		return nil, fmt.Errorf("%w: effective_to must be after effective_from", ErrInvalidTaxRate)
+	}
+	if rate.EffectiveTo.Valid && effectiveTo.After(rate.EffectiveTo.Time) {
+		return nil, fmt.Errorf("%w: an ended rate cannot be extended", ErrInvalidTaxRate)
 	}
 
-	return map[string]float64{"default": 0.05} // Default to GST only
+	ended, err := s.queries.EndTaxRate(ctx, db.EndTaxRateParams{
+		ID:          id,
+		EffectiveTo: pgtype.Timestamptz{Time: effectiveTo, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to end tax rate: %w", err)
+	}
+	return &ended, nil
 }
 
-// getJurisdictionByCode retrieves jurisdiction configuration by code
-func (s *TaxService) getJurisdictionByCode(code string) *business.TaxJurisdiction {
-	// This would normally query a database or external service
-	// For now, return a default based on common codes
-	if strings.HasPrefix(code, "US-") {
-		state := strings.TrimPrefix(code, "US-")
-		return &business.TaxJurisdiction{
-			Code:          code,
-			Name:          fmt.Sprintf("United States - %s", state),
-			Type:          "state",
-			TaxRates:      s.getUSStateTaxRates(state),
-			Thresholds:    map[string]int64{"default": 0},
-			IsActive:      true,
-			EffectiveDate: time.Now(),
+// DeleteScheduledRate deletes a rate that has not taken effect yet
+func (s *TaxService) DeleteScheduledRate(ctx context.Context, id uuid.UUID) error {
+	deleted, err := s.queries.DeleteScheduledTaxRate(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tax rate: %w", err)
+	}
+	if deleted == 0 {
+		if _, err := s.queries.GetTaxRate(ctx, id); err != nil {
+			return err
 		}
+		return ErrTaxRateInEffect
 	}
-
-	return s.getDefaultJurisdiction(db.Workspace{}) // Return default
+	return nil
 }
 
-// getTaxTypeForJurisdiction returns the appropriate tax type for a jurisdiction
-func (s *TaxService) getTaxTypeForJurisdiction(code string) string {
-	if strings.HasPrefix(code, "US-") {
-		return "sales"
+// isVerifiedEUVATNumber checks the customer's VAT number with the registry and records the
+// verification in the audit trail. Without a registry only the number's format is checked.
+func (s *TaxService) isVerifiedEUVATNumber(ctx context.Context, params params.TaxCalculationParams, result *responses.TaxCalculationResult) bool {
+	countryCode := ""
+	if params.CustomerAddress != nil {
+		countryCode = params.CustomerAddress.Country
 	}
-	if strings.HasPrefix(code, "CA-") {
-		return "gst"
+
+	if s.verifier == nil {
+		if !isValidVATNumberFormat(countryCode, *params.CustomerVATNumber) {
+			return false
+		}
+		result.AuditTrail.Notes = append(result.AuditTrail.Notes, "VAT number format checked only; not verified with a registry")
+		return true
 	}
-	if strings.HasPrefix(code, "EU-") || code == "UK" {
-		return "vat"
+
+	var customerID *uuid.UUID
+	if params.CustomerID != uuid.Nil {
+		customerID = &params.CustomerID
 	}
-	return "tax"
+
+	verification, err := s.verifier.VerifyTaxID(ctx, customerID, countryCode, *params.CustomerVATNumber)
+	if err != nil {
+		s.logger.Warn("Failed to verify VAT number, charging tax",
+			zap.String("customer_id", params.CustomerID.String()),
+			zap.Error(err))
+		result.AuditTrail.Notes = append(result.AuditTrail.Notes,
+			fmt.Sprintf("VAT number could not be verified (%v); reverse charge not applied", err))
+		return false
+	}
+
+	result.AuditTrail.TaxIDVerification = taxIDVerificationEvidence(verification)
+	if !verification.IsValid {
+		result.AuditTrail.Notes = append(result.AuditTrail.Notes, "VAT number is not registered; reverse charge not applied")
+	}
+	return verification.IsValid
 }
 
-// isValidEUVATNumber validates EU VAT number format
-func (s *TaxService) isValidEUVATNumber(vatNumber string) bool {
-	// Simplified validation - in production use VIES service
-	return len(vatNumber) >= 8 && len(vatNumber) <= 12
+// ValidateVATNumber validates EU VAT numbers with the registry, or by format when none is configured
+func (s *TaxService) ValidateVATNumber(ctx context.Context, vatNumber, countryCode string) (bool, error) {
+	if s.verifier == nil {
+		return isValidVATNumberFormat(countryCode, vatNumber), nil
+	}
+
+	verification, err := s.verifier.VerifyTaxID(ctx, nil, countryCode, vatNumber)
+	if errors.Is(err, ErrInvalidTaxIDFormat) || errors.Is(err, ErrTaxIDNotSupported) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return verification.IsValid, nil
 }
 
 // isDigitalService determines if a product type is a digital service
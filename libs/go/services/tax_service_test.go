@@ -3,10 +3,13 @@ package services_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/cyphera/cyphera-api/libs/go/client/tax_provider"
 	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
 	"github.com/cyphera/cyphera-api/libs/go/mocks"
 	"github.com/cyphera/cyphera-api/libs/go/services"
@@ -14,7 +17,10 @@ import (
 	"github.com/cyphera/cyphera-api/libs/go/types/api/responses"
 	"github.com/cyphera/cyphera-api/libs/go/types/business"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -27,6 +33,7 @@ func TestTaxService_CalculateTax(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	newTaxTestTables().expect(mockQuerier)
 	service := services.NewTaxService(mockQuerier)
 	ctx := context.Background()
 
@@ -366,6 +373,7 @@ func TestTaxService_StoreTaxCalculation(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	newTaxTestTables().expect(mockQuerier)
 	service := services.NewTaxService(mockQuerier)
 	ctx := context.Background()
 
@@ -453,6 +461,7 @@ func TestTaxService_GetTaxRatesForJurisdiction(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	newTaxTestTables().expect(mockQuerier)
 	service := services.NewTaxService(mockQuerier)
 	ctx := context.Background()
 
@@ -469,29 +478,30 @@ func TestTaxService_GetTaxRatesForJurisdiction(t *testing.T) {
 			setupMocks:       func() {},
 			wantErr:          false,
 			expectedTaxRates: map[string]float64{
-				"digital":  0.0725,
-				"physical": 0.0725,
-				"service":  0.0725,
-				"default":  0.0725,
+				"default": 0.0725,
 			},
 		},
 		{
-			name:             "unknown jurisdiction returns default",
-			jurisdictionCode: "XX-UNKNOWN",
+			name:             "EU jurisdiction with product-specific rate",
+			jurisdictionCode: "EU-DE",
 			setupMocks:       func() {},
 			wantErr:          false,
 			expectedTaxRates: map[string]float64{
-				"default": 0.0,
+				"default": 0.19,
+				"ebook":   0.07,
 			},
 		},
+		{
+			name:             "unknown jurisdiction",
+			jurisdictionCode: "XX-UNKNOWN",
+			setupMocks:       func() {},
+			wantErr:          true,
+		},
 		{
 			name:             "empty jurisdiction code",
 			jurisdictionCode: "",
 			setupMocks:       func() {},
-			wantErr:          false,
-			expectedTaxRates: map[string]float64{
-				"default": 0.0,
-			},
+			wantErr:          true,
 		},
 	}
 
@@ -531,6 +541,7 @@ func TestTaxService_ValidateVATNumber(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	newTaxTestTables().expect(mockQuerier)
 	service := services.NewTaxService(mockQuerier)
 	ctx := context.Background()
 
@@ -551,13 +562,21 @@ func TestTaxService_ValidateVATNumber(t *testing.T) {
 			expectedValid: true,
 		},
 		{
-			name:          "valid long EU VAT number",
-			vatNumber:     "FR1234567890", // 12 characters - within validation range
+			name:          "valid French VAT number with check characters",
+			vatNumber:     "FR40303265045",
 			countryCode:   "FR",
 			setupMocks:    func() {},
 			wantErr:       false,
 			expectedValid: true,
 		},
+		{
+			name:          "wrong format for member state",
+			vatNumber:     "FR1234567890",
+			countryCode:   "FR",
+			setupMocks:    func() {},
+			wantErr:       false,
+			expectedValid: false,
+		},
 		{
 			name:          "too short VAT number",
 			vatNumber:     "DE123",
@@ -583,20 +602,28 @@ func TestTaxService_ValidateVATNumber(t *testing.T) {
 			expectedValid: false,
 		},
 		{
-			name:          "minimum length VAT number",
-			vatNumber:     "12345678",
-			countryCode:   "XX",
+			name:          "number without country prefix uses country code",
+			vatNumber:     "123 456 789",
+			countryCode:   "de",
+			setupMocks:    func() {},
+			wantErr:       false,
+			expectedValid: true,
+		},
+		{
+			name:          "Greek VAT number uses VIES prefix",
+			vatNumber:     "EL123456789",
+			countryCode:   "GR",
 			setupMocks:    func() {},
 			wantErr:       false,
 			expectedValid: true,
 		},
 		{
-			name:          "maximum length VAT number",
+			name:          "country outside VIES",
 			vatNumber:     "123456789012",
 			countryCode:   "XX",
 			setupMocks:    func() {},
 			wantErr:       false,
-			expectedValid: true,
+			expectedValid: false,
 		},
 	}
 
@@ -621,6 +648,7 @@ func TestTaxService_JurisdictionHelpers(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	newTaxTestTables().expect(mockQuerier)
 	service := services.NewTaxService(mockQuerier)
 
 	t.Run("getUSStateTaxRates returns correct rates", func(t *testing.T) {
@@ -704,6 +732,7 @@ func TestTaxService_EdgeCases(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	newTaxTestTables().expect(mockQuerier)
 	service := services.NewTaxService(mockQuerier)
 	ctx := context.Background()
 
@@ -834,6 +863,7 @@ func TestTaxService_ReverseChargeScenarios(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQuerier := mocks.NewMockQuerier(ctrl)
+	newTaxTestTables().expect(mockQuerier)
 	service := services.NewTaxService(mockQuerier)
 	ctx := context.Background()
 
@@ -945,6 +975,549 @@ func TestTaxService_ReverseChargeScenarios(t *testing.T) {
 	}
 }
 
+func TestTaxService_RatesFromTables(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	newTaxTestTables().expect(mockQuerier)
+	service := services.NewTaxService(mockQuerier)
+	ctx := context.Background()
+
+	tests := []struct {
+		name                  string
+		address               *business.Address
+		productType           string
+		expectedTaxCents      int64
+		expectedJurisdictions []string
+		expectedRulesVersion  string
+	}{
+		{
+			name:                  "uses the rate in force, not the superseded or scheduled one",
+			address:               &business.Address{Country: "FI"},
+			productType:           "digital",
+			expectedTaxCents:      2550,
+			expectedJurisdictions: []string{"EU-FI"},
+			expectedRulesVersion:  "v2024.2",
+		},
+		{
+			name:                  "product-specific rate",
+			address:               &business.Address{Country: "DE"},
+			productType:           "ebook",
+			expectedTaxCents:      700,
+			expectedJurisdictions: []string{"EU-DE"},
+			expectedRulesVersion:  "v2024.1",
+		},
+		{
+			name:                  "state and local rates",
+			address:               &business.Address{Country: "US", State: "NY", City: "New York"},
+			productType:           "digital",
+			expectedTaxCents:      1250,
+			expectedJurisdictions: []string{"US-NY", "US-NY-NYC"},
+			expectedRulesVersion:  "v2024.1,v2025.1",
+		},
+		{
+			name:                  "amount below threshold",
+			address:               &business.Address{Country: "US", State: "TX"},
+			productType:           "physical",
+			expectedTaxCents:      0,
+			expectedJurisdictions: []string{"US-TX"},
+			expectedRulesVersion:  "v2024.1",
+		},
+		{
+			name:                  "unknown country is not taxed",
+			address:               &business.Address{Country: "XX"},
+			productType:           "digital",
+			expectedTaxCents:      0,
+			expectedJurisdictions: []string{"XX"},
+			expectedRulesVersion:  "none",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.CalculateTax(ctx, params.TaxCalculationParams{
+				WorkspaceID:     uuid.New(),
+				CustomerID:      uuid.New(),
+				AmountCents:     10000,
+				Currency:        "USD",
+				TransactionType: "one_time",
+				ProductType:     tt.productType,
+				CustomerAddress: tt.address,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedTaxCents, result.TotalTaxCents)
+			assert.Equal(t, 10000+tt.expectedTaxCents, result.TotalAmountCents)
+			assert.Equal(t, tt.expectedJurisdictions, result.AppliedJurisdictions)
+			assert.Equal(t, tt.expectedRulesVersion, result.AuditTrail.RulesVersion)
+			assert.Len(t, result.TaxBreakdown, len(tt.expectedJurisdictions))
+		})
+	}
+}
+
+func TestTaxService_CreateRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewTaxService(mockQuerier)
+	ctx := context.Background()
+
+	jurisdictionID := uuid.New()
+	effectiveFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	effectiveTo := effectiveFrom.AddDate(0, 6, 0)
+
+	tests := []struct {
+		name        string
+		params      params.CreateTaxRateParams
+		setupMocks  func()
+		wantErr     error
+		errorString string
+	}{
+		{
+			name: "open-ended rate supersedes the current rate",
+			params: params.CreateTaxRateParams{
+				JurisdictionID: jurisdictionID,
+				Rate:           0.21,
+				EffectiveFrom:  effectiveFrom,
+				RulesVersion:   "v2026.1",
+			},
+			setupMocks: func() {
+				mockQuerier.EXPECT().LockTaxJurisdiction(ctx, jurisdictionID).Return(db.TaxJurisdiction{ID: jurisdictionID}, nil)
+				mockQuerier.EXPECT().CountOverlappingTaxRates(ctx, db.CountOverlappingTaxRatesParams{
+					JurisdictionID: jurisdictionID,
+					ProductType:    "default",
+					EffectiveFrom:  pgtype.Timestamptz{Time: effectiveFrom, Valid: true},
+				}).Return(int64(0), nil)
+				mockQuerier.EXPECT().SupersedeOpenTaxRate(ctx, db.SupersedeOpenTaxRateParams{
+					EffectiveFrom:  pgtype.Timestamptz{Time: effectiveFrom, Valid: true},
+					JurisdictionID: jurisdictionID,
+					ProductType:    "default",
+				}).Return(int64(1), nil)
+				mockQuerier.EXPECT().CreateTaxRate(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, arg db.CreateTaxRateParams) (db.TaxRate, error) {
+						assert.Equal(t, "default", arg.ProductType)
+						assert.Equal(t, 0.21, helpers.GetNumericFloat(arg.Rate))
+						assert.False(t, arg.EffectiveTo.Valid)
+						return db.TaxRate{ID: uuid.New(), JurisdictionID: jurisdictionID, RulesVersion: arg.RulesVersion}, nil
+					})
+			},
+		},
+		{
+			name: "bounded rate does not supersede",
+			params: params.CreateTaxRateParams{
+				JurisdictionID: jurisdictionID,
+				ProductType:    "ebook",
+				Rate:           0.05,
+				EffectiveFrom:  effectiveFrom,
+				EffectiveTo:    &effectiveTo,
+				RulesVersion:   "v2026.1",
+			},
+			setupMocks: func() {
+				mockQuerier.EXPECT().LockTaxJurisdiction(ctx, jurisdictionID).Return(db.TaxJurisdiction{ID: jurisdictionID}, nil)
+				mockQuerier.EXPECT().CountOverlappingTaxRates(ctx, gomock.Any()).Return(int64(0), nil)
+				mockQuerier.EXPECT().CreateTaxRate(ctx, gomock.Any()).Return(db.TaxRate{ID: uuid.New()}, nil)
+			},
+		},
+		{
+			name: "overlapping rate is rejected",
+			params: params.CreateTaxRateParams{
+				JurisdictionID: jurisdictionID,
+				Rate:           0.2,
+				EffectiveFrom:  effectiveFrom,
+				EffectiveTo:    &effectiveTo,
+				RulesVersion:   "v2026.1",
+			},
+			setupMocks: func() {
+				mockQuerier.EXPECT().LockTaxJurisdiction(ctx, jurisdictionID).Return(db.TaxJurisdiction{ID: jurisdictionID}, nil)
+				mockQuerier.EXPECT().CountOverlappingTaxRates(ctx, gomock.Any()).Return(int64(1), nil)
+			},
+			wantErr: services.ErrTaxRateOverlap,
+		},
+		{
+			name: "rate out of range",
+			params: params.CreateTaxRateParams{
+				JurisdictionID: jurisdictionID,
+				Rate:           19,
+				EffectiveFrom:  effectiveFrom,
+				RulesVersion:   "v2026.1",
+			},
+			setupMocks: func() {},
+			wantErr:    services.ErrInvalidTaxRate,
+		},
+		{
+			name: "period ends before it starts",
+			params: params.CreateTaxRateParams{
+				JurisdictionID: jurisdictionID,
+				Rate:           0.2,
+				EffectiveFrom:  effectiveTo,
+				EffectiveTo:    &effectiveFrom,
+				RulesVersion:   "v2026.1",
+			},
+			setupMocks: func() {},
+			wantErr:    services.ErrInvalidTaxRate,
+		},
+		{
+			name: "unknown jurisdiction",
+			params: params.CreateTaxRateParams{
+				JurisdictionID: jurisdictionID,
+				Rate:           0.2,
+				EffectiveFrom:  effectiveFrom,
+				RulesVersion:   "v2026.1",
+			},
+			setupMocks: func() {
+				mockQuerier.EXPECT().LockTaxJurisdiction(ctx, jurisdictionID).Return(db.TaxJurisdiction{}, pgx.ErrNoRows)
+			},
+			wantErr: pgx.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			rate, err := service.CreateRate(ctx, tt.params)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, rate)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, rate)
+		})
+	}
+}
+
+func TestTaxService_DeleteScheduledRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	service := services.NewTaxService(mockQuerier)
+	ctx := context.Background()
+	rateID := uuid.New()
+
+	t.Run("scheduled rate is deleted", func(t *testing.T) {
+		mockQuerier.EXPECT().DeleteScheduledTaxRate(ctx, rateID).Return(int64(1), nil)
+		assert.NoError(t, service.DeleteScheduledRate(ctx, rateID))
+	})
+
+	t.Run("rate in effect must be ended instead", func(t *testing.T) {
+		mockQuerier.EXPECT().DeleteScheduledTaxRate(ctx, rateID).Return(int64(0), nil)
+		mockQuerier.EXPECT().GetTaxRate(ctx, rateID).Return(db.TaxRate{ID: rateID}, nil)
+		assert.ErrorIs(t, service.DeleteScheduledRate(ctx, rateID), services.ErrTaxRateInEffect)
+	})
+
+	t.Run("unknown rate", func(t *testing.T) {
+		mockQuerier.EXPECT().DeleteScheduledTaxRate(ctx, rateID).Return(int64(0), nil)
+		mockQuerier.EXPECT().GetTaxRate(ctx, rateID).Return(db.TaxRate{}, pgx.ErrNoRows)
+		assert.ErrorIs(t, service.DeleteScheduledRate(ctx, rateID), pgx.ErrNoRows)
+	})
+}
+
+func TestTaxService_ExternalProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	newTaxTestTables().expect(mockQuerier)
+	mockProvider := mocks.NewMockTaxProvider(ctrl)
+	mockProvider.EXPECT().Name().Return("taxjar").AnyTimes()
+	service := services.NewTaxServiceWithDependencies(mockQuerier, mockProvider, nil)
+	ctx := context.Background()
+
+	calcParams := params.TaxCalculationParams{
+		WorkspaceID:          uuid.New(),
+		CustomerID:           uuid.New(),
+		AmountCents:          10000,
+		Currency:             "USD",
+		CustomerAddress:      &business.Address{Country: "US", State: "CA", City: "Los Angeles"},
+		ProductType:          "digital",
+		TransactionReference: "INV-2025-0001",
+	}
+
+	t.Run("provider result is used", func(t *testing.T) {
+		mockProvider.EXPECT().CalculateTax(ctx, calcParams).Return(&responses.TaxCalculationResult{
+			SubtotalCents:    10000,
+			TotalTaxCents:    950,
+			TotalAmountCents: 10950,
+			Provider:         "taxjar",
+		}, nil)
+
+		result, err := service.CalculateTax(ctx, calcParams)
+		require.NoError(t, err)
+		assert.Equal(t, int64(950), result.TotalTaxCents)
+		assert.Equal(t, "taxjar", result.Provider)
+		assert.Empty(t, result.ProviderTransactionID, "calculating only quotes")
+	})
+
+	t.Run("provider outage falls back to internal rates", func(t *testing.T) {
+		mockProvider.EXPECT().CalculateTax(ctx, calcParams).Return(nil, fmt.Errorf("%w: timeout", tax_provider.ErrUnavailable))
+
+		result, err := service.CalculateTax(ctx, calcParams)
+		require.NoError(t, err)
+		assert.Equal(t, int64(725), result.TotalTaxCents)
+		assert.Equal(t, services.InternalTaxProvider, result.Provider)
+		assert.Empty(t, result.ProviderTransactionID)
+		assert.Contains(t, result.AuditTrail.Notes, "Tax provider taxjar unavailable; calculated from internal rates")
+	})
+
+	t.Run("provider rejection is returned instead of internal rates", func(t *testing.T) {
+		mockProvider.EXPECT().CalculateTax(ctx, calcParams).Return(nil, errors.New("tax provider rejected request: 422"))
+
+		_, err := service.CalculateTax(ctx, calcParams)
+		assert.Error(t, err)
+	})
+
+	t.Run("reverse charge is decided before the provider is asked", func(t *testing.T) {
+		b2b := calcParams
+		b2b.CustomerAddress = &business.Address{Country: "DE"}
+		b2b.IsB2B = true
+		b2b.CustomerVATNumber = taxStringPtr("DE123456789")
+
+		result, err := service.CalculateTax(ctx, b2b)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), result.TotalTaxCents)
+		assert.Contains(t, result.AuditTrail.AppliedRules, "B2B_REVERSE_CHARGE")
+	})
+
+	t.Run("provider calculations are committed once the invoice exists", func(t *testing.T) {
+		calculation := &responses.TaxCalculationResult{TotalTaxCents: 950, Provider: "taxjar"}
+		mockProvider.EXPECT().CommitTransaction(ctx, calcParams, calculation).Return("INV-2025-0001", nil)
+
+		transactionID, err := service.CommitCalculation(ctx, calcParams, calculation)
+		require.NoError(t, err)
+		assert.Equal(t, "INV-2025-0001", transactionID)
+
+		// Internal calculations have nothing to record
+		transactionID, err = service.CommitCalculation(ctx, calcParams, &responses.TaxCalculationResult{Provider: services.InternalTaxProvider})
+		require.NoError(t, err)
+		assert.Empty(t, transactionID)
+	})
+
+	t.Run("exempt customers skip the provider", func(t *testing.T) {
+		exempt := calcParams
+		exempt.TaxExempt = true
+
+		result, err := service.CalculateTax(ctx, exempt)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), result.TotalTaxCents)
+		assert.Equal(t, services.InternalTaxProvider, result.Provider)
+	})
+}
+
+func TestTaxService_ReverseChargeWithVerifiedVATNumber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	newTaxTestTables().expect(mockQuerier)
+	mockVerifier := mocks.NewMockTaxIDVerificationService(ctrl)
+	service := services.NewTaxServiceWithDependencies(mockQuerier, nil, mockVerifier)
+	ctx := context.Background()
+
+	customerID := uuid.New()
+	calcParams := params.TaxCalculationParams{
+		WorkspaceID:       uuid.New(),
+		CustomerID:        customerID,
+		AmountCents:       10000,
+		Currency:          "EUR",
+		ProductType:       "digital",
+		CustomerAddress:   &business.Address{Country: "DE"},
+		IsB2B:             true,
+		CustomerVATNumber: taxStringPtr("DE123456789"),
+	}
+	verification := func(valid bool) *db.TaxIDVerification {
+		return &db.TaxIDVerification{
+			ID:                 uuid.New(),
+			CountryCode:        "DE",
+			TaxID:              "123456789",
+			IsValid:            valid,
+			ConsultationNumber: pgtype.Text{String: "WAPIAAAAY1234567", Valid: true},
+			Source:             "vies",
+			CheckedAt:          pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		}
+	}
+
+	t.Run("registered VAT number gets reverse charge with evidence", func(t *testing.T) {
+		v := verification(true)
+		mockVerifier.EXPECT().VerifyTaxID(ctx, &customerID, "DE", "DE123456789").Return(v, nil)
+
+		result, err := service.CalculateTax(ctx, calcParams)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), result.TotalTaxCents)
+		assert.Contains(t, result.AuditTrail.AppliedRules, "B2B_REVERSE_CHARGE")
+		require.NotNil(t, result.AuditTrail.TaxIDVerification)
+		assert.Equal(t, v.ID, result.AuditTrail.TaxIDVerification.VerificationID)
+		assert.Equal(t, "WAPIAAAAY1234567", result.AuditTrail.TaxIDVerification.ConsultationNumber)
+	})
+
+	t.Run("unregistered VAT number is charged VAT", func(t *testing.T) {
+		mockVerifier.EXPECT().VerifyTaxID(ctx, &customerID, "DE", "DE123456789").Return(verification(false), nil)
+
+		result, err := service.CalculateTax(ctx, calcParams)
+		require.NoError(t, err)
+		assert.Greater(t, result.TotalTaxCents, int64(0))
+		assert.NotContains(t, result.AuditTrail.AppliedRules, "B2B_REVERSE_CHARGE")
+		require.NotNil(t, result.AuditTrail.TaxIDVerification)
+		assert.False(t, result.AuditTrail.TaxIDVerification.Valid)
+	})
+
+	t.Run("registry outage charges VAT", func(t *testing.T) {
+		mockVerifier.EXPECT().VerifyTaxID(ctx, &customerID, "DE", "DE123456789").Return(nil, errors.New("VIES unavailable"))
+
+		result, err := service.CalculateTax(ctx, calcParams)
+		require.NoError(t, err)
+		assert.Greater(t, result.TotalTaxCents, int64(0))
+		assert.Nil(t, result.AuditTrail.TaxIDVerification)
+	})
+}
+
+// taxTestTables is an in-memory stand-in for the tax_jurisdictions and tax_rates tables
+type taxTestTables struct {
+	jurisdictions []db.TaxJurisdiction
+	rates         []db.TaxRate
+}
+
+func newTaxTestTables() *taxTestTables {
+	tables := &taxTestTables{}
+	since2024 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	us := tables.addJurisdiction("US", "US", "", "", nil, "sales")
+	tables.addRate(us, "default", "0", 0, "v2024.1", since2024, nil)
+	for state, rate := range map[string]string{"CA": "0.0725", "NY": "0.08", "TX": "0.0625", "FL": "0.06"} {
+		j := tables.addJurisdiction("US-"+state, "US", state, "", &us.ID, "sales")
+		tables.addRate(j, "default", rate, 0, "v2024.1", since2024, nil)
+		if state == "NY" {
+			nyc := tables.addJurisdiction("US-NY-NYC", "US", "NY", "NEW YORK", &j.ID, "sales")
+			tables.addRate(nyc, "default", "0.045", 0, "v2025.1", since2024, nil)
+		}
+		if state == "TX" {
+			tables.addRate(j, "physical", "0.0625", 20000, "v2024.1", since2024, nil)
+		}
+	}
+
+	ca := tables.addJurisdiction("CA", "CA", "", "", nil, "gst")
+	tables.addRate(ca, "default", "0.05", 0, "v2024.1", since2024, nil)
+	for province, rate := range map[string]string{"ON": "0.13", "BC": "0.12", "AB": "0.05", "QC": "0.14975"} {
+		j := tables.addJurisdiction("CA-"+province, "CA", province, "", &ca.ID, "gst")
+		tables.addRate(j, "default", rate, 0, "v2024.1", since2024, nil)
+	}
+
+	uk := tables.addJurisdiction("UK", "GB", "", "", nil, "vat")
+	tables.addRate(uk, "default", "0.20", 0, "v2024.1", since2024, nil)
+
+	de := tables.addJurisdiction("EU-DE", "DE", "", "", nil, "vat")
+	tables.addRate(de, "default", "0.19", 0, "v2024.1", since2024, nil)
+	tables.addRate(de, "ebook", "0.07", 0, "v2024.1", since2024, nil)
+
+	fi := tables.addJurisdiction("EU-FI", "FI", "", "", nil, "vat")
+	fiChange := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	tables.addRate(fi, "default", "0.24", 0, "v2024.1", since2024, &fiChange)
+	tables.addRate(fi, "default", "0.255", 0, "v2024.2", fiChange, nil)
+	tables.addRate(fi, "digital", "0.30", 0, "v2099.1", time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+
+	return tables
+}
+
+func (tt *taxTestTables) addJurisdiction(code, country, subdivision, locality string, parentID *uuid.UUID, taxType string) db.TaxJurisdiction {
+	j := db.TaxJurisdiction{
+		ID:               uuid.New(),
+		Code:             code,
+		Name:             code,
+		JurisdictionType: "country",
+		CountryCode:      country,
+		SubdivisionCode:  pgtype.Text{String: subdivision, Valid: subdivision != ""},
+		Locality:         pgtype.Text{String: locality, Valid: locality != ""},
+		TaxType:          taxType,
+		IsActive:         true,
+	}
+	if parentID != nil {
+		j.ParentID = pgtype.UUID{Bytes: *parentID, Valid: true}
+		j.JurisdictionType = "state"
+	}
+	tt.jurisdictions = append(tt.jurisdictions, j)
+	return j
+}
+
+func (tt *taxTestTables) addRate(j db.TaxJurisdiction, productType, rate string, thresholdCents int64, version string, from time.Time, to *time.Time) {
+	r := db.TaxRate{
+		ID:             uuid.New(),
+		JurisdictionID: j.ID,
+		ProductType:    productType,
+		ThresholdCents: thresholdCents,
+		EffectiveFrom:  pgtype.Timestamptz{Time: from, Valid: true},
+		RulesVersion:   version,
+	}
+	if err := r.Rate.Scan(rate); err != nil {
+		panic(err)
+	}
+	if to != nil {
+		r.EffectiveTo = pgtype.Timestamptz{Time: *to, Valid: true}
+	}
+	tt.rates = append(tt.rates, r)
+}
+
+// expect answers the tax table queries from memory, the way the SQL would
+func (tt *taxTestTables) expect(q *mocks.MockQuerier) {
+	q.EXPECT().FindTaxJurisdiction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.FindTaxJurisdictionParams) (db.TaxJurisdiction, error) {
+			var country *db.TaxJurisdiction
+			for i, j := range tt.jurisdictions {
+				if j.CountryCode != arg.CountryCode || j.Locality.Valid || !j.IsActive {
+					continue
+				}
+				if j.SubdivisionCode.Valid && arg.SubdivisionCode.Valid && j.SubdivisionCode.String == arg.SubdivisionCode.String {
+					return j, nil
+				}
+				if !j.SubdivisionCode.Valid {
+					country = &tt.jurisdictions[i]
+				}
+			}
+			if country == nil {
+				return db.TaxJurisdiction{}, pgx.ErrNoRows
+			}
+			return *country, nil
+		}).AnyTimes()
+
+	q.EXPECT().GetTaxJurisdictionByCode(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, code string) (db.TaxJurisdiction, error) {
+			for _, j := range tt.jurisdictions {
+				if j.Code == code {
+					return j, nil
+				}
+			}
+			return db.TaxJurisdiction{}, pgx.ErrNoRows
+		}).AnyTimes()
+
+	q.EXPECT().ListLocalTaxJurisdictions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.ListLocalTaxJurisdictionsParams) ([]db.TaxJurisdiction, error) {
+			locals := []db.TaxJurisdiction{}
+			for _, j := range tt.jurisdictions {
+				if j.ParentID == arg.ParentID && j.Locality == arg.Locality && j.IsActive {
+					locals = append(locals, j)
+				}
+			}
+			return locals, nil
+		}).AnyTimes()
+
+	q.EXPECT().ListEffectiveTaxRates(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.ListEffectiveTaxRatesParams) ([]db.TaxRate, error) {
+			rates := []db.TaxRate{}
+			for _, r := range tt.rates {
+				inForce := !r.EffectiveFrom.Time.After(arg.EffectiveAt.Time) &&
+					(!r.EffectiveTo.Valid || r.EffectiveTo.Time.After(arg.EffectiveAt.Time))
+				for _, id := range arg.JurisdictionIds {
+					if inForce && r.JurisdictionID == id {
+						rates = append(rates, r)
+					}
+				}
+			}
+			return rates, nil
+		}).AnyTimes()
+}
+
 // Helper functions
 func taxStringPtr(s string) *string {
 	return &s
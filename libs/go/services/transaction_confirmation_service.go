@@ -0,0 +1,445 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Confirmation statuses stored on payment_confirmations
+const (
+	PaymentConfirmationPending   = "pending"
+	PaymentConfirmationConfirmed = "confirmed"
+	PaymentConfirmationFinalized = "finalized"
+	PaymentConfirmationDropped   = "dropped"
+	PaymentConfirmationReplaced  = "replaced"
+	PaymentConfirmationReverted  = "reverted"
+)
+
+// TransactionStatusReader reads what a network knows about a sent transaction. It is implemented by BlockchainService.
+type TransactionStatusReader interface {
+	GetTransactionReceipt(ctx context.Context, networkID uuid.UUID, txHash string) (*business.MinedTransaction, error)
+	GetTransaction(ctx context.Context, networkID uuid.UUID, txHash string) (*business.SentTransaction, error)
+	GetBlockNumber(ctx context.Context, networkID uuid.UUID) (uint64, error)
+	GetNonce(ctx context.Context, networkID uuid.UUID, account string) (uint64, error)
+}
+
+// TransactionConfirmationConfig configures the transaction confirmation tracker
+type TransactionConfirmationConfig struct {
+	// CheckInterval is how long a transaction goes between checks
+	CheckInterval time.Duration
+	// BatchSize caps the transactions checked per run
+	BatchSize int32
+	// TrackingWindow is how far back payments are picked up for tracking; older payments are left alone
+	TrackingWindow time.Duration
+	// DroppedAfter is how long a transaction may be neither mined nor in the mempool before it counts as dropped
+	DroppedAfter time.Duration
+}
+
+// DefaultTransactionConfirmationConfig returns the default transaction confirmation tracker configuration
+func DefaultTransactionConfirmationConfig() TransactionConfirmationConfig {
+	return TransactionConfirmationConfig{
+		CheckInterval:  30 * time.Second,
+		BatchSize:      200,
+		TrackingWindow: 24 * time.Hour,
+		DroppedAfter:   30 * time.Minute,
+	}
+}
+
+// TransactionConfirmationService follows the transactions behind crypto payments until they reach their
+// network's finality depth. Payments are recorded as soon as a redemption returns a transaction hash; when
+// that transaction is dropped, replaced or reverted, the payment is failed and the subscription period and
+// invoice it paid for are opened again.
+type TransactionConfirmationService struct {
+	queries db.Querier
+	pool    *pgxpool.Pool
+	chain   TransactionStatusReader
+	config  TransactionConfirmationConfig
+	logger  *zap.Logger
+}
+
+// NewTransactionConfirmationService creates a transaction confirmation tracker. Rollbacks run in a database
+// transaction when pool is given.
+func NewTransactionConfirmationService(
+	queries db.Querier,
+	pool *pgxpool.Pool,
+	chain TransactionStatusReader,
+	config TransactionConfirmationConfig,
+) *TransactionConfirmationService {
+	log := logger.Log
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &TransactionConfirmationService{
+		queries: queries,
+		pool:    pool,
+		chain:   chain,
+		config:  config,
+		logger:  log,
+	}
+}
+
+// confirmationCheck is the outcome of checking one transaction
+type confirmationCheck struct {
+	status        string
+	senderAddress pgtype.Text
+	nonce         pgtype.Int8
+	blockNumber   pgtype.Int8
+	blockHash     pgtype.Text
+	confirmations int32
+	lastSeenAt    pgtype.Timestamptz
+	reason        string
+}
+
+// rolledBack reports whether the transaction did not make it on-chain
+func (c confirmationCheck) rolledBack() bool {
+	switch c.status {
+	case PaymentConfirmationDropped, PaymentConfirmationReplaced, PaymentConfirmationReverted:
+		return true
+	default:
+		return false
+	}
+}
+
+// CheckConfirmations starts tracking newly recorded payments, then checks the transactions that have not
+// reached finality and were not checked within the check interval
+func (s *TransactionConfirmationService) CheckConfirmations(ctx context.Context, now time.Time) (*business.PaymentConfirmationResult, error) {
+	tracked, err := s.queries.TrackNewPaymentConfirmations(ctx, pgtype.Timestamptz{Time: now.Add(-s.config.TrackingWindow), Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to track new payments: %w", err)
+	}
+
+	rows, err := s.queries.ListPaymentConfirmationsToCheck(ctx, db.ListPaymentConfirmationsToCheckParams{
+		CheckedBefore: pgtype.Timestamptz{Time: now.Add(-s.config.CheckInterval), Valid: true},
+		BatchSize:     s.config.BatchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment confirmations to check: %w", err)
+	}
+
+	result := &business.PaymentConfirmationResult{Tracked: tracked}
+	heads := make(map[uuid.UUID]uint64)
+	for _, row := range rows {
+		head, ok := heads[row.NetworkID]
+		if !ok {
+			head, err = s.chain.GetBlockNumber(ctx, row.NetworkID)
+			if err != nil {
+				// The network's RPC is unavailable; its transactions are checked on the next run
+				result.Failed++
+				s.logger.Warn("Failed to get latest block",
+					zap.String("network_id", row.NetworkID.String()),
+					zap.Error(err))
+				continue
+			}
+			heads[row.NetworkID] = head
+		}
+
+		check, err := s.checkTransaction(ctx, row, head, now)
+		if err != nil {
+			result.Failed++
+			s.logger.Warn("Failed to check payment transaction",
+				zap.String("payment_id", row.PaymentID.String()),
+				zap.String("transaction_hash", row.TransactionHash),
+				zap.Error(err))
+			continue
+		}
+		result.Checked++
+
+		if check.rolledBack() {
+			if err := s.rollBack(ctx, row, check, now); err != nil {
+				return result, fmt.Errorf("failed to roll back payment %s: %w", row.PaymentID, err)
+			}
+			result.RolledBack++
+			continue
+		}
+
+		if err := s.recordCheck(ctx, s.queries, row, check); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return result, err
+		}
+		switch check.status {
+		case PaymentConfirmationConfirmed:
+			if row.Status != PaymentConfirmationConfirmed {
+				result.Confirmed++
+			}
+		case PaymentConfirmationFinalized:
+			result.Finalized++
+		}
+	}
+
+	return result, nil
+}
+
+// checkTransaction works out where a payment's transaction stands relative to the latest block
+func (s *TransactionConfirmationService) checkTransaction(ctx context.Context, row db.ListPaymentConfirmationsToCheckRow, head uint64, now time.Time) (confirmationCheck, error) {
+	check := confirmationCheck{status: PaymentConfirmationPending}
+
+	// The sender and nonce tell a replaced transaction from a dropped one once the transaction is gone
+	var sent *business.SentTransaction
+	lookedUp := !row.SenderAddress.Valid
+	if lookedUp {
+		var err error
+		if sent, err = s.lookUpTransaction(ctx, row, &check, now); err != nil {
+			return check, err
+		}
+	}
+
+	mined, err := s.chain.GetTransactionReceipt(ctx, row.NetworkID, row.TransactionHash)
+	if err != nil && !errors.Is(err, ErrTransactionNotFound) {
+		return check, err
+	}
+	if mined != nil {
+		return s.minedCheck(check, row, mined, head, now), nil
+	}
+
+	// Not in a block: the transaction is still waiting in the mempool, was reorged out of the block it
+	// was seen in, or is gone for good
+	if row.BlockHash.Valid {
+		s.logger.Warn("Payment transaction was reorged out of its block",
+			zap.String("payment_id", row.PaymentID.String()),
+			zap.String("transaction_hash", row.TransactionHash),
+			zap.String("block_hash", row.BlockHash.String))
+	}
+
+	if !lookedUp {
+		if sent, err = s.lookUpTransaction(ctx, row, &check, now); err != nil {
+			return check, err
+		}
+	}
+	if sent != nil {
+		return check, nil
+	}
+
+	sender, nonce := row.SenderAddress, row.Nonce
+	if sender.Valid && nonce.Valid {
+		accountNonce, err := s.chain.GetNonce(ctx, row.NetworkID, sender.String)
+		if err != nil {
+			return check, err
+		}
+		if accountNonce > uint64(nonce.Int64) {
+			// The nonce was used, but not by this transaction. Look once more in case the transaction
+			// itself was mined since the receipt was requested.
+			mined, err := s.chain.GetTransactionReceipt(ctx, row.NetworkID, row.TransactionHash)
+			if err != nil && !errors.Is(err, ErrTransactionNotFound) {
+				return check, err
+			}
+			if mined != nil {
+				return s.minedCheck(check, row, mined, head, now), nil
+			}
+			check.status = PaymentConfirmationReplaced
+			check.reason = fmt.Sprintf("transaction %s was replaced by another transaction with nonce %d from %s",
+				row.TransactionHash, nonce.Int64, sender.String)
+			return check, nil
+		}
+	}
+
+	if now.Sub(row.LastSeenAt.Time) >= s.config.DroppedAfter {
+		check.status = PaymentConfirmationDropped
+		check.reason = fmt.Sprintf("transaction %s has not been seen on the network since %s",
+			row.TransactionHash, row.LastSeenAt.Time.UTC().Format(time.RFC3339))
+	}
+	return check, nil
+}
+
+// lookUpTransaction records the sender and nonce of a transaction the network still knows about, when it has them
+func (s *TransactionConfirmationService) lookUpTransaction(ctx context.Context, row db.ListPaymentConfirmationsToCheckRow, check *confirmationCheck, now time.Time) (*business.SentTransaction, error) {
+	sent, err := s.chain.GetTransaction(ctx, row.NetworkID, row.TransactionHash)
+	if errors.Is(err, ErrTransactionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Networks without account nonces report no sender, and their transactions cannot be replaced
+	if sent.From != "" {
+		check.senderAddress = pgtype.Text{String: sent.From, Valid: true}
+		check.nonce = pgtype.Int8{Int64: int64(sent.Nonce), Valid: true}
+	}
+	check.lastSeenAt = pgtype.Timestamptz{Time: now, Valid: true}
+	return sent, nil
+}
+
+// minedCheck sets the confirmation status of a mined transaction from its depth and its network's thresholds
+func (s *TransactionConfirmationService) minedCheck(check confirmationCheck, row db.ListPaymentConfirmationsToCheckRow, mined *business.MinedTransaction, head uint64, now time.Time) confirmationCheck {
+	check.blockNumber = pgtype.Int8{Int64: int64(mined.BlockNumber), Valid: true}
+	check.blockHash = pgtype.Text{String: mined.BlockHash, Valid: true}
+	check.lastSeenAt = pgtype.Timestamptz{Time: now, Valid: true}
+
+	if row.BlockHash.Valid && !strings.EqualFold(row.BlockHash.String, mined.BlockHash) {
+		s.logger.Warn("Payment transaction was reorged into another block",
+			zap.String("payment_id", row.PaymentID.String()),
+			zap.String("transaction_hash", row.TransactionHash),
+			zap.String("previous_block_hash", row.BlockHash.String),
+			zap.String("block_hash", mined.BlockHash))
+	}
+
+	if mined.Status == 0 {
+		check.status = PaymentConfirmationReverted
+		check.reason = fmt.Sprintf("transaction %s reverted in block %d", row.TransactionHash, mined.BlockNumber)
+		return check
+	}
+
+	// The receipt can come from a node that is a block ahead of the one that reported the head
+	confirmations := int32(1)
+	if head >= mined.BlockNumber {
+		confirmations = int32(head - mined.BlockNumber + 1)
+	}
+	check.confirmations = confirmations
+
+	switch {
+	case confirmations >= row.FinalityBlocks:
+		check.status = PaymentConfirmationFinalized
+	case confirmations >= row.ConfirmationBlocks:
+		check.status = PaymentConfirmationConfirmed
+	default:
+		check.status = PaymentConfirmationPending
+	}
+	return check
+}
+
+// recordCheck stores the outcome of a check. pgx.ErrNoRows means another run settled the transaction first.
+func (s *TransactionConfirmationService) recordCheck(ctx context.Context, queries db.Querier, row db.ListPaymentConfirmationsToCheckRow, check confirmationCheck) error {
+	var errorMessage pgtype.Text
+	if check.reason != "" {
+		errorMessage = pgtype.Text{String: check.reason, Valid: true}
+	}
+
+	_, err := queries.UpdatePaymentConfirmation(ctx, db.UpdatePaymentConfirmationParams{
+		Status:        check.status,
+		SenderAddress: check.senderAddress,
+		Nonce:         check.nonce,
+		BlockHash:     check.blockHash,
+		BlockNumber:   check.blockNumber,
+		Confirmations: check.confirmations,
+		LastSeenAt:    check.lastSeenAt,
+		ErrorMessage:  errorMessage,
+		PaymentID:     row.PaymentID,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to record payment confirmation: %w", err)
+	}
+	return err
+}
+
+// rollBack fails a payment whose transaction did not make it on-chain. The renewal it paid for is opened
+// again and the subscription's period advancement is undone, so the period is redeemed again, and the
+// invoice it paid goes back to open.
+func (s *TransactionConfirmationService) rollBack(ctx context.Context, row db.ListPaymentConfirmationsToCheckRow, check confirmationCheck, now time.Time) error {
+	err := s.inTransaction(ctx, func(queries db.Querier) error {
+		if err := s.recordCheck(ctx, queries, row, check); err != nil {
+			return err
+		}
+
+		if _, err := queries.UpdatePaymentStatus(ctx, db.UpdatePaymentStatusParams{
+			ID:           row.PaymentID,
+			WorkspaceID:  row.WorkspaceID,
+			Status:       "failed",
+			ErrorMessage: pgtype.Text{String: check.reason, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("failed to fail payment: %w", err)
+		}
+
+		if row.SubscriptionID.Valid {
+			if err := s.reopenSubscriptionPeriod(ctx, queries, row, check, now); err != nil {
+				return err
+			}
+		}
+
+		if row.InvoiceID.Valid {
+			_, err := queries.ReopenPaidInvoice(ctx, db.ReopenPaidInvoiceParams{
+				ID:          row.InvoiceID.Bytes,
+				WorkspaceID: row.WorkspaceID,
+			})
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("failed to reopen invoice: %w", err)
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Warn("Rolled back payment whose transaction did not make it on-chain",
+		zap.String("payment_id", row.PaymentID.String()),
+		zap.String("transaction_hash", row.TransactionHash),
+		zap.String("confirmation_status", check.status),
+		zap.String("reason", check.reason))
+	return nil
+}
+
+// reopenSubscriptionPeriod makes the period a rolled back payment paid for due again. Renewals go back to
+// the period they renewed; a payment without a renewal, such as a subscription's first, is due from when
+// it was made.
+func (s *TransactionConfirmationService) reopenSubscriptionPeriod(ctx context.Context, queries db.Querier, row db.ListPaymentConfirmationsToCheckRow, check confirmationCheck, now time.Time) error {
+	subscriptionID := uuid.UUID(row.SubscriptionID.Bytes)
+
+	dueAt := row.InitiatedAt
+	renewal, err := queries.ReopenSubscriptionRenewalByTransaction(ctx, db.ReopenSubscriptionRenewalByTransactionParams{
+		ErrorMessage:    pgtype.Text{String: check.reason, Valid: true},
+		SubscriptionID:  subscriptionID,
+		TransactionHash: pgtype.Text{String: row.TransactionHash, Valid: true},
+	})
+	switch {
+	case err == nil:
+		dueAt = renewal.PeriodDueAt
+	case !errors.Is(err, pgx.ErrNoRows):
+		return fmt.Errorf("failed to reopen subscription renewal: %w", err)
+	}
+
+	if _, err := queries.RevertSubscriptionRedemption(ctx, db.RevertSubscriptionRedemptionParams{
+		AmountInCents: int32(row.ProductAmountCents),
+		DueAt:         dueAt,
+		ID:            subscriptionID,
+	}); err != nil {
+		return fmt.Errorf("failed to revert subscription redemption: %w", err)
+	}
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"payment_id":          row.PaymentID.String(),
+		"confirmation_status": check.status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event metadata: %w", err)
+	}
+	if _, err := queries.CreateSubscriptionEvent(ctx, db.CreateSubscriptionEventParams{
+		SubscriptionID:  subscriptionID,
+		EventType:       db.SubscriptionEventTypeFailTransaction,
+		TransactionHash: pgtype.Text{String: row.TransactionHash, Valid: true},
+		AmountInCents:   int32(row.ProductAmountCents),
+		OccurredAt:      pgtype.Timestamptz{Time: now, Valid: true},
+		ErrorMessage:    pgtype.Text{String: check.reason, Valid: true},
+		Metadata:        metadata,
+	}); err != nil {
+		return fmt.Errorf("failed to record transaction failure event: %w", err)
+	}
+	return nil
+}
+
+// inTransaction runs fn against a database transaction, or directly against the queries when no pool was given
+func (s *TransactionConfirmationService) inTransaction(ctx context.Context, fn func(queries db.Querier) error) error {
+	if s.pool == nil {
+		return fn(s.queries)
+	}
+	return helpers.WithTransaction(ctx, s.pool, func(tx pgx.Tx) error {
+		return fn(db.New(tx))
+	})
+}
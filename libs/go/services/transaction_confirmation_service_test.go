@@ -0,0 +1,295 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	paymentTxHash = "0x9f2c4a1b7e3d5f60819a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f70"
+	paymentSender = "0x4A7b1D5e9c3F2a8B6d0E1f4C7a9B2d5E8f1A3c6D"
+)
+
+// fakeTransactionChain answers the confirmation tracker's reads with fixed data
+type fakeTransactionChain struct {
+	head     uint64
+	headErr  error
+	mined    *business.MinedTransaction
+	sent     *business.SentTransaction
+	nonce    uint64
+	nonceErr error
+}
+
+func (c *fakeTransactionChain) GetTransactionReceipt(ctx context.Context, networkID uuid.UUID, txHash string) (*business.MinedTransaction, error) {
+	if c.mined == nil {
+		return nil, services.ErrTransactionNotFound
+	}
+	return c.mined, nil
+}
+
+func (c *fakeTransactionChain) GetTransaction(ctx context.Context, networkID uuid.UUID, txHash string) (*business.SentTransaction, error) {
+	if c.sent == nil {
+		return nil, services.ErrTransactionNotFound
+	}
+	return c.sent, nil
+}
+
+func (c *fakeTransactionChain) GetBlockNumber(ctx context.Context, networkID uuid.UUID) (uint64, error) {
+	return c.head, c.headErr
+}
+
+func (c *fakeTransactionChain) GetNonce(ctx context.Context, networkID uuid.UUID, account string) (uint64, error) {
+	return c.nonce, c.nonceErr
+}
+
+func TestTransactionConfirmationService_CheckConfirmations(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	workspaceID := uuid.New()
+	subscriptionID := uuid.New()
+	invoiceID := uuid.New()
+	periodDueAt := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	initiatedAt := now.Add(-2 * time.Hour)
+
+	confirmationRow := func(mutate func(*db.ListPaymentConfirmationsToCheckRow)) db.ListPaymentConfirmationsToCheckRow {
+		row := db.ListPaymentConfirmationsToCheckRow{
+			PaymentID:          uuid.New(),
+			NetworkID:          uuid.New(),
+			TransactionHash:    paymentTxHash,
+			Status:             services.PaymentConfirmationPending,
+			SenderAddress:      pgtype.Text{String: paymentSender, Valid: true},
+			Nonce:              pgtype.Int8{Int64: 41, Valid: true},
+			LastSeenAt:         pgtype.Timestamptz{Time: now.Add(-time.Minute), Valid: true},
+			WorkspaceID:        workspaceID,
+			SubscriptionID:     pgtype.UUID{Bytes: subscriptionID, Valid: true},
+			InvoiceID:          pgtype.UUID{Bytes: invoiceID, Valid: true},
+			ProductAmountCents: 1500,
+			InitiatedAt:        pgtype.Timestamptz{Time: initiatedAt, Valid: true},
+			ConfirmationBlocks: 3,
+			FinalityBlocks:     12,
+		}
+		if mutate != nil {
+			mutate(&row)
+		}
+		return row
+	}
+
+	run := func(t *testing.T, chain *fakeTransactionChain, rows []db.ListPaymentConfirmationsToCheckRow, expect func(q *mocks.MockQuerier)) *business.PaymentConfirmationResult {
+		ctrl := gomock.NewController(t)
+		q := mocks.NewMockQuerier(ctrl)
+		config := services.DefaultTransactionConfirmationConfig()
+
+		q.EXPECT().TrackNewPaymentConfirmations(gomock.Any(), pgtype.Timestamptz{Time: now.Add(-config.TrackingWindow), Valid: true}).Return(int64(len(rows)), nil)
+		q.EXPECT().ListPaymentConfirmationsToCheck(gomock.Any(), db.ListPaymentConfirmationsToCheckParams{
+			CheckedBefore: pgtype.Timestamptz{Time: now.Add(-config.CheckInterval), Valid: true},
+			BatchSize:     config.BatchSize,
+		}).Return(rows, nil)
+		if expect != nil {
+			expect(q)
+		}
+
+		service := services.NewTransactionConfirmationService(q, nil, chain, config)
+		result, err := service.CheckConfirmations(context.Background(), now)
+		require.NoError(t, err)
+		return result
+	}
+
+	expectRollback := func(q *mocks.MockQuerier, row db.ListPaymentConfirmationsToCheckRow, status string, dueAt time.Time, renewalErr error) {
+		gomock.InOrder(
+			q.EXPECT().UpdatePaymentConfirmation(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, arg db.UpdatePaymentConfirmationParams) (db.PaymentConfirmation, error) {
+					assert.Equal(t, status, arg.Status)
+					assert.True(t, arg.ErrorMessage.Valid)
+					return db.PaymentConfirmation{PaymentID: row.PaymentID, Status: status}, nil
+				}),
+			q.EXPECT().UpdatePaymentStatus(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, arg db.UpdatePaymentStatusParams) (db.Payment, error) {
+					assert.Equal(t, row.PaymentID, arg.ID)
+					assert.Equal(t, "failed", arg.Status)
+					return db.Payment{ID: row.PaymentID}, nil
+				}),
+			q.EXPECT().ReopenSubscriptionRenewalByTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, arg db.ReopenSubscriptionRenewalByTransactionParams) (db.SubscriptionRenewal, error) {
+					assert.Equal(t, subscriptionID, arg.SubscriptionID)
+					assert.Equal(t, paymentTxHash, arg.TransactionHash.String)
+					if renewalErr != nil {
+						return db.SubscriptionRenewal{}, renewalErr
+					}
+					return db.SubscriptionRenewal{SubscriptionID: subscriptionID, PeriodDueAt: pgtype.Timestamptz{Time: periodDueAt, Valid: true}}, nil
+				}),
+			q.EXPECT().RevertSubscriptionRedemption(gomock.Any(), db.RevertSubscriptionRedemptionParams{
+				AmountInCents: 1500,
+				DueAt:         pgtype.Timestamptz{Time: dueAt, Valid: true},
+				ID:            subscriptionID,
+			}).Return(db.Subscription{ID: subscriptionID, Status: db.SubscriptionStatusOverdue}, nil),
+			q.EXPECT().CreateSubscriptionEvent(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, arg db.CreateSubscriptionEventParams) (db.SubscriptionEvent, error) {
+					assert.Equal(t, db.SubscriptionEventTypeFailTransaction, arg.EventType)
+					assert.Equal(t, int32(1500), arg.AmountInCents)
+					assert.Contains(t, string(arg.Metadata), status)
+					return db.SubscriptionEvent{}, nil
+				}),
+			q.EXPECT().ReopenPaidInvoice(gomock.Any(), db.ReopenPaidInvoiceParams{ID: invoiceID, WorkspaceID: workspaceID}).
+				Return(db.Invoice{ID: invoiceID, Status: "open"}, nil),
+		)
+	}
+
+	t.Run("moves mined transactions through the network thresholds", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			head          uint64
+			status        string
+			confirmations int32
+		}{
+			{name: "below the confirmation threshold", head: 101, status: services.PaymentConfirmationPending, confirmations: 2},
+			{name: "at the confirmation threshold", head: 102, status: services.PaymentConfirmationConfirmed, confirmations: 3},
+			{name: "below finality", head: 110, status: services.PaymentConfirmationConfirmed, confirmations: 11},
+			{name: "at finality", head: 111, status: services.PaymentConfirmationFinalized, confirmations: 12},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				row := confirmationRow(nil)
+				chain := &fakeTransactionChain{
+					head:  tt.head,
+					mined: &business.MinedTransaction{BlockNumber: 100, BlockHash: "0xb100", Status: 1},
+				}
+				run(t, chain, []db.ListPaymentConfirmationsToCheckRow{row}, func(q *mocks.MockQuerier) {
+					q.EXPECT().UpdatePaymentConfirmation(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, arg db.UpdatePaymentConfirmationParams) (db.PaymentConfirmation, error) {
+							assert.Equal(t, row.PaymentID, arg.PaymentID)
+							assert.Equal(t, tt.status, arg.Status)
+							assert.Equal(t, tt.confirmations, arg.Confirmations)
+							assert.Equal(t, pgtype.Int8{Int64: 100, Valid: true}, arg.BlockNumber)
+							assert.Equal(t, "0xb100", arg.BlockHash.String)
+							assert.False(t, arg.ErrorMessage.Valid)
+							return db.PaymentConfirmation{PaymentID: row.PaymentID, Status: tt.status}, nil
+						})
+				})
+			})
+		}
+	})
+
+	t.Run("records the new block of a transaction reorged into another block", func(t *testing.T) {
+		row := confirmationRow(func(r *db.ListPaymentConfirmationsToCheckRow) {
+			r.Status = services.PaymentConfirmationConfirmed
+			r.BlockNumber = pgtype.Int8{Int64: 100, Valid: true}
+			r.BlockHash = pgtype.Text{String: "0xb100", Valid: true}
+		})
+		chain := &fakeTransactionChain{
+			head:  102,
+			mined: &business.MinedTransaction{BlockNumber: 101, BlockHash: "0xb101", Status: 1},
+		}
+		result := run(t, chain, []db.ListPaymentConfirmationsToCheckRow{row}, func(q *mocks.MockQuerier) {
+			q.EXPECT().UpdatePaymentConfirmation(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, arg db.UpdatePaymentConfirmationParams) (db.PaymentConfirmation, error) {
+					assert.Equal(t, services.PaymentConfirmationPending, arg.Status)
+					assert.Equal(t, int32(2), arg.Confirmations)
+					assert.Equal(t, "0xb101", arg.BlockHash.String)
+					return db.PaymentConfirmation{}, nil
+				})
+		})
+		assert.Equal(t, 1, result.Checked)
+		assert.Zero(t, result.RolledBack)
+	})
+
+	t.Run("keeps a transaction reorged back into the mempool pending", func(t *testing.T) {
+		row := confirmationRow(func(r *db.ListPaymentConfirmationsToCheckRow) {
+			r.Status = services.PaymentConfirmationConfirmed
+			r.BlockNumber = pgtype.Int8{Int64: 100, Valid: true}
+			r.BlockHash = pgtype.Text{String: "0xb100", Valid: true}
+		})
+		chain := &fakeTransactionChain{
+			head: 104,
+			sent: &business.SentTransaction{From: paymentSender, Nonce: 41, Pending: true},
+		}
+		result := run(t, chain, []db.ListPaymentConfirmationsToCheckRow{row}, func(q *mocks.MockQuerier) {
+			q.EXPECT().UpdatePaymentConfirmation(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, arg db.UpdatePaymentConfirmationParams) (db.PaymentConfirmation, error) {
+					assert.Equal(t, services.PaymentConfirmationPending, arg.Status)
+					assert.False(t, arg.BlockHash.Valid)
+					assert.False(t, arg.BlockNumber.Valid)
+					assert.Equal(t, pgtype.Timestamptz{Time: now, Valid: true}, arg.LastSeenAt)
+					return db.PaymentConfirmation{}, nil
+				})
+		})
+		assert.Zero(t, result.RolledBack)
+	})
+
+	t.Run("waits for a missing transaction until it counts as dropped", func(t *testing.T) {
+		row := confirmationRow(func(r *db.ListPaymentConfirmationsToCheckRow) {
+			r.SenderAddress = pgtype.Text{}
+			r.Nonce = pgtype.Int8{}
+			r.LastSeenAt = pgtype.Timestamptz{Time: now.Add(-5 * time.Minute), Valid: true}
+		})
+		result := run(t, &fakeTransactionChain{head: 100}, []db.ListPaymentConfirmationsToCheckRow{row}, func(q *mocks.MockQuerier) {
+			q.EXPECT().UpdatePaymentConfirmation(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, arg db.UpdatePaymentConfirmationParams) (db.PaymentConfirmation, error) {
+					assert.Equal(t, services.PaymentConfirmationPending, arg.Status)
+					assert.False(t, arg.LastSeenAt.Valid)
+					return db.PaymentConfirmation{}, nil
+				})
+		})
+		assert.Zero(t, result.RolledBack)
+	})
+
+	t.Run("rolls back a replaced transaction to its renewal period", func(t *testing.T) {
+		row := confirmationRow(nil)
+		chain := &fakeTransactionChain{head: 100, nonce: 42}
+		result := run(t, chain, []db.ListPaymentConfirmationsToCheckRow{row}, func(q *mocks.MockQuerier) {
+			expectRollback(q, row, services.PaymentConfirmationReplaced, periodDueAt, nil)
+		})
+		assert.Equal(t, 1, result.RolledBack)
+	})
+
+	t.Run("rolls back a dropped first payment to when it was made", func(t *testing.T) {
+		row := confirmationRow(func(r *db.ListPaymentConfirmationsToCheckRow) {
+			r.SenderAddress = pgtype.Text{}
+			r.Nonce = pgtype.Int8{}
+			r.LastSeenAt = pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true}
+		})
+		result := run(t, &fakeTransactionChain{head: 100}, []db.ListPaymentConfirmationsToCheckRow{row}, func(q *mocks.MockQuerier) {
+			expectRollback(q, row, services.PaymentConfirmationDropped, initiatedAt, pgx.ErrNoRows)
+		})
+		assert.Equal(t, 1, result.RolledBack)
+	})
+
+	t.Run("rolls back a reverted transaction", func(t *testing.T) {
+		row := confirmationRow(nil)
+		chain := &fakeTransactionChain{
+			head:  105,
+			mined: &business.MinedTransaction{BlockNumber: 100, BlockHash: "0xb100", Status: 0},
+		}
+		result := run(t, chain, []db.ListPaymentConfirmationsToCheckRow{row}, func(q *mocks.MockQuerier) {
+			expectRollback(q, row, services.PaymentConfirmationReverted, periodDueAt, nil)
+		})
+		assert.Equal(t, 1, result.RolledBack)
+	})
+
+	t.Run("leaves a payment another run already settled alone", func(t *testing.T) {
+		row := confirmationRow(nil)
+		chain := &fakeTransactionChain{head: 100, nonce: 42}
+		result := run(t, chain, []db.ListPaymentConfirmationsToCheckRow{row}, func(q *mocks.MockQuerier) {
+			q.EXPECT().UpdatePaymentConfirmation(gomock.Any(), gomock.Any()).Return(db.PaymentConfirmation{}, pgx.ErrNoRows)
+		})
+		assert.Equal(t, 1, result.Checked)
+	})
+
+	t.Run("skips networks whose RPC is unavailable", func(t *testing.T) {
+		rows := []db.ListPaymentConfirmationsToCheckRow{confirmationRow(nil), confirmationRow(nil)}
+		result := run(t, &fakeTransactionChain{headErr: errors.New("connection refused")}, rows, nil)
+		assert.Equal(t, 2, result.Failed)
+		assert.Zero(t, result.Checked)
+	})
+}
@@ -0,0 +1,832 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/client/circle"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/logger"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidTreasuryRule is returned when a sweep rule's wallets, token or amounts are out of range
+var ErrInvalidTreasuryRule = errors.New("invalid treasury sweep rule")
+
+const (
+	// minSweepIntervalMinutes matches the check constraint on treasury_sweep_rules
+	minSweepIntervalMinutes = 5
+	// defaultSweepIntervalMinutes is used when a rule does not set an interval
+	defaultSweepIntervalMinutes = 60
+	// circleTransactionPageSize is the largest page Circle returns when listing transactions
+	circleTransactionPageSize = 50
+)
+
+// Circle transaction states that end a transfer
+const (
+	circleTransactionComplete  = "COMPLETE"
+	circleTransactionFailed    = "FAILED"
+	circleTransactionDenied    = "DENIED"
+	circleTransactionCancelled = "CANCELLED"
+)
+
+// TreasuryConfig configures scheduled treasury sweeps
+type TreasuryConfig struct {
+	// BatchSize caps the rules swept and the movements reconciled per run
+	BatchSize int32
+	// ApprovalTimeout is how long a sweep waits for the merchant to approve its transfer before it expires
+	ApprovalTimeout time.Duration
+}
+
+// DefaultTreasuryConfig returns the default treasury configuration
+func DefaultTreasuryConfig() TreasuryConfig {
+	return TreasuryConfig{
+		BatchSize:       100,
+		ApprovalTimeout: 24 * time.Hour,
+	}
+}
+
+// TreasuryConfigFromEnv reads the approval timeout in hours from TREASURY_APPROVAL_TIMEOUT_HOURS on top of the defaults
+func TreasuryConfigFromEnv() (TreasuryConfig, error) {
+	config := DefaultTreasuryConfig()
+
+	if value := strings.TrimSpace(os.Getenv("TREASURY_APPROVAL_TIMEOUT_HOURS")); value != "" {
+		hours, err := strconv.Atoi(value)
+		if err != nil || hours <= 0 {
+			return config, fmt.Errorf("TREASURY_APPROVAL_TIMEOUT_HOURS must be a positive number of hours: %s", value)
+		}
+		config.ApprovalTimeout = time.Duration(hours) * time.Hour
+	}
+	return config, nil
+}
+
+// TreasuryService sweeps merchants' Circle wallets into their treasury wallets. Circle wallets are
+// user-controlled, so a sweep creates a transfer challenge that the merchant approves with their PIN; the
+// resulting transfers are reconciled against Circle's transaction history into a ledger of movements.
+type TreasuryService struct {
+	queries db.Querier
+	circle  circle.CircleClientInterface
+	config  TreasuryConfig
+	logger  *zap.Logger
+}
+
+// NewTreasuryService creates a new treasury service
+func NewTreasuryService(queries db.Querier, circleClient circle.CircleClientInterface, config TreasuryConfig) *TreasuryService {
+	log := logger.Log
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &TreasuryService{
+		queries: queries,
+		circle:  circleClient,
+		config:  config,
+		logger:  log,
+	}
+}
+
+// ListSweepRules returns a workspace's sweep rules
+func (s *TreasuryService) ListSweepRules(ctx context.Context, workspaceID uuid.UUID) ([]business.TreasurySweepRule, error) {
+	rows, err := s.queries.ListTreasurySweepRules(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list treasury sweep rules: %w", err)
+	}
+
+	rules := make([]business.TreasurySweepRule, 0, len(rows))
+	for _, row := range rows {
+		rules = append(rules, toTreasurySweepRule(row))
+	}
+	return rules, nil
+}
+
+// CreateSweepRule adds a sweep rule for one of a workspace's Circle wallets
+func (s *TreasuryService) CreateSweepRule(ctx context.Context, ruleParams params.TreasurySweepRuleParams) (*business.TreasurySweepRule, error) {
+	columns, err := s.treasuryRuleColumns(ctx, ruleParams)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.queries.CreateTreasurySweepRule(ctx, db.CreateTreasurySweepRuleParams{
+		WorkspaceID:          ruleParams.WorkspaceID,
+		Name:                 columns.name,
+		SourceWalletID:       ruleParams.SourceWalletID,
+		DestinationWalletID:  ruleParams.DestinationWalletID,
+		TokenID:              ruleParams.TokenID,
+		ThresholdAmount:      columns.threshold,
+		RetainedAmount:       columns.retained,
+		FeeLevel:             columns.feeLevel,
+		SweepIntervalMinutes: columns.interval,
+		IsActive:             ruleParams.IsActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create treasury sweep rule: %w", err)
+	}
+
+	s.logger.Info("Created treasury sweep rule",
+		zap.String("workspace_id", ruleParams.WorkspaceID.String()),
+		zap.String("rule_id", row.ID.String()),
+		zap.String("source_wallet_id", row.SourceWalletID.String()),
+		zap.String("destination_wallet_id", row.DestinationWalletID.String()))
+
+	rule := toTreasurySweepRule(row)
+	return &rule, nil
+}
+
+// UpdateSweepRule replaces a sweep rule's destination, amounts and schedule
+func (s *TreasuryService) UpdateSweepRule(ctx context.Context, ruleID uuid.UUID, ruleParams params.TreasurySweepRuleParams) (*business.TreasurySweepRule, error) {
+	existing, err := s.queries.GetTreasurySweepRule(ctx, db.GetTreasurySweepRuleParams{
+		ID:          ruleID,
+		WorkspaceID: ruleParams.WorkspaceID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get treasury sweep rule: %w", err)
+	}
+
+	ruleParams.SourceWalletID = existing.SourceWalletID
+	ruleParams.TokenID = existing.TokenID
+	columns, err := s.treasuryRuleColumns(ctx, ruleParams)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.queries.UpdateTreasurySweepRule(ctx, db.UpdateTreasurySweepRuleParams{
+		ID:                   ruleID,
+		WorkspaceID:          ruleParams.WorkspaceID,
+		Name:                 columns.name,
+		DestinationWalletID:  ruleParams.DestinationWalletID,
+		ThresholdAmount:      columns.threshold,
+		RetainedAmount:       columns.retained,
+		FeeLevel:             columns.feeLevel,
+		SweepIntervalMinutes: columns.interval,
+		IsActive:             ruleParams.IsActive,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update treasury sweep rule: %w", err)
+	}
+
+	rule := toTreasurySweepRule(row)
+	return &rule, nil
+}
+
+// DeleteSweepRule stops a sweep rule. Sweeps already waiting for approval are still reconciled.
+func (s *TreasuryService) DeleteSweepRule(ctx context.Context, workspaceID, ruleID uuid.UUID) error {
+	deleted, err := s.queries.DeleteTreasurySweepRule(ctx, db.DeleteTreasurySweepRuleParams{
+		ID:          ruleID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete treasury sweep rule: %w", err)
+	}
+	if deleted == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListMovements returns a page of a workspace's treasury ledger, newest first, with the total matching the filters
+func (s *TreasuryService) ListMovements(ctx context.Context, listParams params.ListTreasuryMovementsParams) ([]business.TreasuryMovement, int64, error) {
+	var ruleID pgtype.UUID
+	if listParams.RuleID != nil {
+		ruleID = pgtype.UUID{Bytes: *listParams.RuleID, Valid: true}
+	}
+	status := pgtype.Text{String: listParams.Status, Valid: listParams.Status != ""}
+
+	rows, err := s.queries.ListTreasuryMovements(ctx, db.ListTreasuryMovementsParams{
+		WorkspaceID: listParams.WorkspaceID,
+		RuleID:      ruleID,
+		Status:      status,
+		Limit:       listParams.Limit,
+		Offset:      listParams.Offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list treasury movements: %w", err)
+	}
+
+	total, err := s.queries.CountTreasuryMovements(ctx, db.CountTreasuryMovementsParams{
+		WorkspaceID: listParams.WorkspaceID,
+		RuleID:      ruleID,
+		Status:      status,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count treasury movements: %w", err)
+	}
+
+	movements := make([]business.TreasuryMovement, 0, len(rows))
+	for _, row := range rows {
+		movements = append(movements, toTreasuryMovement(row))
+	}
+	return movements, total, nil
+}
+
+// RunSweeps checks every rule whose interval has elapsed and, when its wallet holds more than the threshold,
+// creates a transfer challenge for the balance above the retained float. Rules with a sweep still awaiting
+// approval or confirmation are not checked until it settles. Rules are claimed before they are checked, so
+// processor runs that overlap split the due rules between them instead of sweeping one twice.
+func (s *TreasuryService) RunSweeps(ctx context.Context, now time.Time) (*business.TreasurySweepResult, error) {
+	rows, err := s.queries.ClaimDueTreasurySweepRules(ctx, db.ClaimDueTreasurySweepRulesParams{
+		Now:       pgtype.Timestamptz{Time: now, Valid: true},
+		BatchSize: s.config.BatchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due treasury sweep rules: %w", err)
+	}
+
+	result := &business.TreasurySweepResult{}
+	userTokens := map[uuid.UUID]string{}
+	for _, row := range rows {
+		result.Checked++
+
+		outcome, err := s.sweep(ctx, row, userTokens)
+		if err != nil {
+			// A rule that cannot be swept, for instance because Circle is unavailable, is retried after its interval
+			result.Failed++
+			outcome = err.Error()
+			s.logger.Warn("Failed to sweep treasury rule",
+				zap.String("rule_id", row.ID.String()),
+				zap.String("workspace_id", row.WorkspaceID.String()),
+				zap.Error(err))
+		} else if outcome == "" {
+			result.Challenged++
+			outcome = "transfer awaiting approval"
+		} else {
+			result.Skipped++
+		}
+
+		if err := s.queries.RecordTreasurySweepRuleRun(ctx, db.RecordTreasurySweepRuleRunParams{
+			ID:            row.ID,
+			LastRunAt:     pgtype.Timestamptz{Time: now, Valid: true},
+			LastRunResult: pgtype.Text{String: outcome, Valid: true},
+		}); err != nil {
+			return result, fmt.Errorf("failed to record treasury sweep run: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// sweep sizes and submits one rule's transfer. It returns why nothing was swept, or an empty string when a
+// transfer challenge was created.
+func (s *TreasuryService) sweep(ctx context.Context, row db.ClaimDueTreasurySweepRulesRow, userTokens map[uuid.UUID]string) (string, error) {
+	userToken, err := s.userToken(ctx, row.CircleUserID, userTokens)
+	if err != nil {
+		return "", err
+	}
+
+	balances, err := s.circle.GetWalletBalance(ctx, row.CircleWalletID, userToken, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	var token, native *circle.TokenBalance
+	for i, balance := range balances.Data.TokenBalances {
+		if balance.Token.IsNative {
+			native = &balances.Data.TokenBalances[i]
+		}
+		if (row.GasToken && balance.Token.IsNative) || (!row.GasToken && strings.EqualFold(balance.Token.TokenAddress, row.TokenAddress)) {
+			token = &balances.Data.TokenBalances[i]
+		}
+	}
+	if token == nil {
+		return fmt.Sprintf("wallet holds no %s", row.TokenSymbol), nil
+	}
+
+	balance, err := parseDecimalAmount(token.Amount)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s balance from Circle: %w", row.TokenSymbol, err)
+	}
+	threshold := numericToRat(row.ThresholdAmount)
+	if balance.Cmp(threshold) <= 0 {
+		return fmt.Sprintf("balance %s %s is at or below the threshold", formatDecimal(balance), row.TokenSymbol), nil
+	}
+
+	amount := new(big.Rat).Sub(balance, numericToRat(row.RetainedAmount))
+	fee, err := s.estimateSweepFee(ctx, row, token.Token.ID, amount, userToken)
+	if err != nil {
+		return "", err
+	}
+
+	if row.GasToken {
+		// The fee comes out of the swept balance, so the retained float is left untouched
+		amount.Sub(amount, fee)
+	} else {
+		nativeBalance := new(big.Rat)
+		if native != nil {
+			if nativeBalance, err = parseDecimalAmount(native.Amount); err != nil {
+				return "", fmt.Errorf("invalid native balance from Circle: %w", err)
+			}
+		}
+		if nativeBalance.Cmp(fee) < 0 {
+			return fmt.Sprintf("native balance %s is below the estimated fee %s", formatDecimal(nativeBalance), formatDecimal(fee)), nil
+		}
+	}
+
+	amount = truncateDecimal(amount, row.TokenDecimals)
+	if amount.Sign() <= 0 {
+		return fmt.Sprintf("nothing left to sweep after the retained amount and fee of %s", formatDecimal(fee)), nil
+	}
+
+	movement, err := s.queries.CreateTreasuryMovement(ctx, db.CreateTreasuryMovementParams{
+		WorkspaceID:         row.WorkspaceID,
+		RuleID:              row.ID,
+		NetworkID:           row.NetworkID,
+		TokenID:             row.TokenID,
+		SourceWalletID:      row.SourceWalletID,
+		DestinationWalletID: row.DestinationWalletID,
+		Amount:              ratToNumeric(amount),
+		SourceBalance:       ratToNumeric(balance),
+		EstimatedFee:        ratToNumeric(fee),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to record treasury movement: %w", err)
+	}
+
+	// The movement ID doubles as the idempotency key, so a retried run cannot send the sweep twice, and as the
+	// ref ID the transfer is matched back to the movement by
+	challenge, err := s.circle.CreateTransferChallenge(ctx, circle.TransferChallengeRequest{
+		IdempotencyKey:     movement.ID.String(),
+		WalletID:           row.CircleWalletID,
+		DestinationAddress: row.DestinationAddress,
+		Amounts:            []string{formatDecimal(amount)},
+		TokenID:            token.Token.ID,
+		FeeLevel:           row.FeeLevel,
+		RefID:              movement.ID.String(),
+	}, userToken)
+	if err != nil {
+		if failErr := s.queries.FailTreasuryMovement(ctx, db.FailTreasuryMovementParams{
+			ID:           movement.ID,
+			ErrorMessage: pgtype.Text{String: err.Error(), Valid: true},
+		}); failErr != nil {
+			s.logger.Error("Failed to record failed treasury movement",
+				zap.String("movement_id", movement.ID.String()),
+				zap.Error(failErr))
+		}
+		return "", fmt.Errorf("failed to create transfer challenge: %w", err)
+	}
+
+	if err := s.queries.SetTreasuryMovementChallenge(ctx, db.SetTreasuryMovementChallengeParams{
+		ID:          movement.ID,
+		ChallengeID: pgtype.Text{String: challenge.Data.ChallengeID, Valid: true},
+	}); err != nil {
+		return "", fmt.Errorf("failed to record transfer challenge: %w", err)
+	}
+
+	s.logger.Info("Created treasury sweep",
+		zap.String("rule_id", row.ID.String()),
+		zap.String("movement_id", movement.ID.String()),
+		zap.String("amount", formatDecimal(amount)),
+		zap.String("token", row.TokenSymbol),
+		zap.String("estimated_fee", formatDecimal(fee)))
+	return "", nil
+}
+
+// estimateSweepFee asks Circle for the network fee of a transfer at the rule's fee level, in the native token
+func (s *TreasuryService) estimateSweepFee(ctx context.Context, row db.ClaimDueTreasurySweepRulesRow, circleTokenID string, amount *big.Rat, userToken string) (*big.Rat, error) {
+	if amount.Sign() <= 0 {
+		return new(big.Rat), nil
+	}
+
+	estimate, err := s.circle.EstimateTransferFee(ctx, circle.EstimateTransferFeeRequest{
+		DestinationAddress: row.DestinationAddress,
+		Amounts:            []string{formatDecimal(truncateDecimal(amount, row.TokenDecimals))},
+		WalletID:           row.CircleWalletID,
+		TokenID:            circleTokenID,
+	}, userToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate transfer fee: %w", err)
+	}
+
+	level := estimate.Data.Medium
+	switch row.FeeLevel {
+	case business.TreasuryFeeLevelLow:
+		level = estimate.Data.Low
+	case business.TreasuryFeeLevelHigh:
+		level = estimate.Data.High
+	}
+	if level.NetworkFee == "" {
+		return new(big.Rat), nil
+	}
+	fee, err := parseDecimalAmount(level.NetworkFee)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network fee estimate from Circle: %w", err)
+	}
+	return fee, nil
+}
+
+// userToken mints a Circle user token for a workspace's Circle user, reusing one already minted this run
+func (s *TreasuryService) userToken(ctx context.Context, circleUserID uuid.UUID, userTokens map[uuid.UUID]string) (string, error) {
+	if token, ok := userTokens[circleUserID]; ok {
+		return token, nil
+	}
+	resp, err := s.circle.CreateUserToken(ctx, circleUserID.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to create Circle user token: %w", err)
+	}
+	userTokens[circleUserID] = resp.Data.UserToken
+	return resp.Data.UserToken, nil
+}
+
+// ReconcileMovements matches sweeps awaiting approval or confirmation to the transfers Circle sent for them,
+// recording their outcome, and expires sweeps whose challenge was not approved within the approval timeout
+func (s *TreasuryService) ReconcileMovements(ctx context.Context, now time.Time) (*business.TreasuryReconcileResult, error) {
+	rows, err := s.queries.ListOpenTreasuryMovements(ctx, s.config.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open treasury movements: %w", err)
+	}
+
+	// Each workspace has one Circle user, whose transactions cover all of its wallets
+	var order []uuid.UUID
+	byUser := map[uuid.UUID][]db.ListOpenTreasuryMovementsRow{}
+	for _, row := range rows {
+		if _, ok := byUser[row.CircleUserID]; !ok {
+			order = append(order, row.CircleUserID)
+		}
+		byUser[row.CircleUserID] = append(byUser[row.CircleUserID], row)
+	}
+
+	result := &business.TreasuryReconcileResult{}
+	userTokens := map[uuid.UUID]string{}
+	for _, circleUserID := range order {
+		movements := byUser[circleUserID]
+		transactions, err := s.sweepTransactions(ctx, circleUserID, movements, userTokens)
+		if err != nil {
+			result.Errors += len(movements)
+			s.logger.Warn("Failed to list Circle transactions for treasury movements",
+				zap.String("workspace_id", movements[0].WorkspaceID.String()),
+				zap.Error(err))
+			continue
+		}
+
+		for _, movement := range movements {
+			status, err := s.reconcileMovement(ctx, movement, transactions[movement.ID.String()], now)
+			if err != nil {
+				return result, err
+			}
+			result.Checked++
+			switch status {
+			case business.TreasuryMovementCompleted:
+				result.Completed++
+			case business.TreasuryMovementFailed:
+				result.Failed++
+			case business.TreasuryMovementExpired:
+				result.Expired++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// sweepTransactions lists the transactions sent from the movements' wallets since the oldest of them was
+// created, keyed by ref ID
+func (s *TreasuryService) sweepTransactions(ctx context.Context, circleUserID uuid.UUID, movements []db.ListOpenTreasuryMovementsRow, userTokens map[uuid.UUID]string) (map[string]*circle.Transaction, error) {
+	userToken, err := s.userToken(ctx, circleUserID, userTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	from := movements[0].CreatedAt.Time
+	wanted := map[string]bool{}
+	var walletIDs []string
+	seenWallets := map[string]bool{}
+	for _, movement := range movements {
+		wanted[movement.ID.String()] = true
+		if movement.CreatedAt.Time.Before(from) {
+			from = movement.CreatedAt.Time
+		}
+		if !seenWallets[movement.CircleWalletID] {
+			seenWallets[movement.CircleWalletID] = true
+			walletIDs = append(walletIDs, movement.CircleWalletID)
+		}
+	}
+	// Allow for clock skew between the database and Circle
+	from = from.Add(-time.Minute)
+
+	joined := strings.Join(walletIDs, ",")
+	pageSize := circleTransactionPageSize
+	listParams := &circle.ListTransactionsParams{
+		WalletIDs: &joined,
+		From:      &from,
+		PageSize:  &pageSize,
+	}
+
+	transactions := map[string]*circle.Transaction{}
+	for {
+		resp, err := s.circle.ListTransactions(ctx, userToken, listParams)
+		if err != nil {
+			return nil, err
+		}
+		page := resp.Data.Transactions
+		for i := range page {
+			if wanted[page[i].RefID] {
+				transactions[page[i].RefID] = &page[i]
+			}
+		}
+		if len(page) < pageSize || len(transactions) == len(wanted) {
+			return transactions, nil
+		}
+		after := page[len(page)-1].ID
+		listParams.PageAfter = &after
+	}
+}
+
+// reconcileMovement records what Circle reports for a movement's transfer and returns its new status
+func (s *TreasuryService) reconcileMovement(ctx context.Context, movement db.ListOpenTreasuryMovementsRow, tx *circle.Transaction, now time.Time) (string, error) {
+	update := db.ReconcileTreasuryMovementParams{
+		ID:           movement.ID,
+		Status:       movement.Status,
+		ReconciledAt: pgtype.Timestamptz{Time: now, Valid: true},
+	}
+
+	switch {
+	case tx == nil && now.Sub(movement.CreatedAt.Time) > s.config.ApprovalTimeout:
+		update.Status = business.TreasuryMovementExpired
+		update.ErrorMessage = pgtype.Text{String: fmt.Sprintf("transfer was not approved within %s", s.config.ApprovalTimeout), Valid: true}
+	case tx == nil:
+		// Still waiting for the merchant to approve the challenge
+	default:
+		update.CircleTransactionID = pgtype.Text{String: tx.ID, Valid: true}
+		update.CircleState = pgtype.Text{String: tx.State, Valid: true}
+		update.TransactionHash = pgtype.Text{String: tx.TxHash, Valid: tx.TxHash != ""}
+		if tx.NetworkFee != "" {
+			if fee, err := parseDecimalAmount(tx.NetworkFee); err == nil {
+				update.NetworkFee = ratToNumeric(fee)
+			}
+		}
+
+		switch tx.State {
+		case circleTransactionComplete:
+			update.Status = business.TreasuryMovementCompleted
+			if mismatch := sweptAmountMismatch(movement.Amount, tx.Amounts); mismatch != "" {
+				update.ErrorMessage = pgtype.Text{String: mismatch, Valid: true}
+				s.logger.Warn("Treasury sweep amount does not match its movement",
+					zap.String("movement_id", movement.ID.String()),
+					zap.String("circle_transaction_id", tx.ID),
+					zap.String("mismatch", mismatch))
+			}
+		case circleTransactionFailed, circleTransactionDenied, circleTransactionCancelled:
+			update.Status = business.TreasuryMovementFailed
+			reason := tx.ErrorReason
+			if reason == "" {
+				reason = strings.ToLower(tx.State)
+			}
+			update.ErrorMessage = pgtype.Text{String: reason, Valid: true}
+		default:
+			update.Status = business.TreasuryMovementSubmitted
+		}
+	}
+
+	if _, err := s.queries.ReconcileTreasuryMovement(ctx, update); err != nil {
+		return "", fmt.Errorf("failed to reconcile treasury movement: %w", err)
+	}
+
+	if update.Status != movement.Status {
+		s.logger.Info("Treasury movement status changed",
+			zap.String("movement_id", movement.ID.String()),
+			zap.String("from", movement.Status),
+			zap.String("to", update.Status))
+	}
+	return update.Status, nil
+}
+
+// sweptAmountMismatch describes how the amount Circle transferred differs from the movement's, if it does
+func sweptAmountMismatch(expected pgtype.Numeric, amounts []string) string {
+	if len(amounts) != 1 {
+		return fmt.Sprintf("expected one transferred amount, Circle reported %d", len(amounts))
+	}
+	sent, err := parseDecimalAmount(amounts[0])
+	if err != nil {
+		return fmt.Sprintf("Circle reported an invalid amount %q", amounts[0])
+	}
+	if want := numericToRat(expected); sent.Cmp(want) != 0 {
+		return fmt.Sprintf("Circle transferred %s, expected %s", formatDecimal(sent), formatDecimal(want))
+	}
+	return ""
+}
+
+// treasuryRuleColumnValues are a validated sweep rule's database columns
+type treasuryRuleColumnValues struct {
+	name      string
+	threshold pgtype.Numeric
+	retained  pgtype.Numeric
+	feeLevel  string
+	interval  int32
+}
+
+// treasuryRuleColumns validates rule parameters against the workspace's wallets and tokens and converts them
+// to their database columns. The source must be a Circle wallet, and the destination and token must be on its network.
+func (s *TreasuryService) treasuryRuleColumns(ctx context.Context, ruleParams params.TreasurySweepRuleParams) (treasuryRuleColumnValues, error) {
+	var columns treasuryRuleColumnValues
+
+	columns.name = strings.TrimSpace(ruleParams.Name)
+	if columns.name == "" {
+		return columns, fmt.Errorf("%w: name is required", ErrInvalidTreasuryRule)
+	}
+
+	threshold, err := parseDecimalAmount(ruleParams.ThresholdAmount)
+	if err != nil {
+		return columns, fmt.Errorf("%w: threshold_amount %v", ErrInvalidTreasuryRule, err)
+	}
+	retained := new(big.Rat)
+	if ruleParams.RetainedAmount != "" {
+		if retained, err = parseDecimalAmount(ruleParams.RetainedAmount); err != nil {
+			return columns, fmt.Errorf("%w: retained_amount %v", ErrInvalidTreasuryRule, err)
+		}
+	}
+	if retained.Cmp(threshold) > 0 {
+		return columns, fmt.Errorf("%w: retained_amount cannot exceed threshold_amount", ErrInvalidTreasuryRule)
+	}
+	columns.threshold = ratToNumeric(threshold)
+	columns.retained = ratToNumeric(retained)
+
+	columns.feeLevel = strings.ToUpper(strings.TrimSpace(ruleParams.FeeLevel))
+	switch columns.feeLevel {
+	case "":
+		columns.feeLevel = business.TreasuryFeeLevelMedium
+	case business.TreasuryFeeLevelLow, business.TreasuryFeeLevelMedium, business.TreasuryFeeLevelHigh:
+	default:
+		return columns, fmt.Errorf("%w: fee_level must be LOW, MEDIUM or HIGH", ErrInvalidTreasuryRule)
+	}
+
+	columns.interval = ruleParams.SweepIntervalMinutes
+	if columns.interval == 0 {
+		columns.interval = defaultSweepIntervalMinutes
+	}
+	if columns.interval < minSweepIntervalMinutes {
+		return columns, fmt.Errorf("%w: sweep_interval_minutes must be at least %d", ErrInvalidTreasuryRule, minSweepIntervalMinutes)
+	}
+
+	if ruleParams.SourceWalletID == ruleParams.DestinationWalletID {
+		return columns, fmt.Errorf("%w: source and destination wallets must differ", ErrInvalidTreasuryRule)
+	}
+
+	source, err := s.queries.GetWalletWithCircleDataByID(ctx, db.GetWalletWithCircleDataByIDParams{
+		ID:          ruleParams.SourceWalletID,
+		WorkspaceID: ruleParams.WorkspaceID,
+	})
+	if err == pgx.ErrNoRows {
+		return columns, fmt.Errorf("%w: source wallet not found", ErrInvalidTreasuryRule)
+	}
+	if err != nil {
+		return columns, fmt.Errorf("failed to get source wallet: %w", err)
+	}
+	if !source.CircleID.Valid {
+		return columns, fmt.Errorf("%w: source wallet must be a Circle wallet", ErrInvalidTreasuryRule)
+	}
+
+	destination, err := s.queries.GetWalletByID(ctx, db.GetWalletByIDParams{
+		ID:          ruleParams.DestinationWalletID,
+		WorkspaceID: ruleParams.WorkspaceID,
+	})
+	if err == pgx.ErrNoRows {
+		return columns, fmt.Errorf("%w: destination wallet not found", ErrInvalidTreasuryRule)
+	}
+	if err != nil {
+		return columns, fmt.Errorf("failed to get destination wallet: %w", err)
+	}
+	if destination.NetworkID != source.NetworkID {
+		return columns, fmt.Errorf("%w: destination wallet must be on the source wallet's network", ErrInvalidTreasuryRule)
+	}
+
+	token, err := s.queries.GetToken(ctx, ruleParams.TokenID)
+	if err == pgx.ErrNoRows {
+		return columns, fmt.Errorf("%w: token not found", ErrInvalidTreasuryRule)
+	}
+	if err != nil {
+		return columns, fmt.Errorf("failed to get token: %w", err)
+	}
+	if !source.NetworkID.Valid || token.NetworkID != uuid.UUID(source.NetworkID.Bytes) {
+		return columns, fmt.Errorf("%w: token must be on the source wallet's network", ErrInvalidTreasuryRule)
+	}
+
+	return columns, nil
+}
+
+// toTreasurySweepRule converts a database sweep rule to its business representation
+func toTreasurySweepRule(row db.TreasurySweepRule) business.TreasurySweepRule {
+	rule := business.TreasurySweepRule{
+		ID:                   row.ID,
+		WorkspaceID:          row.WorkspaceID,
+		Name:                 row.Name,
+		SourceWalletID:       row.SourceWalletID,
+		DestinationWalletID:  row.DestinationWalletID,
+		TokenID:              row.TokenID,
+		ThresholdAmount:      formatDecimal(numericToRat(row.ThresholdAmount)),
+		RetainedAmount:       formatDecimal(numericToRat(row.RetainedAmount)),
+		FeeLevel:             row.FeeLevel,
+		SweepIntervalMinutes: row.SweepIntervalMinutes,
+		IsActive:             row.IsActive,
+		LastRunResult:        row.LastRunResult.String,
+		CreatedAt:            row.CreatedAt.Time,
+		UpdatedAt:            row.UpdatedAt.Time,
+	}
+	if row.LastRunAt.Valid {
+		rule.LastRunAt = &row.LastRunAt.Time
+	}
+	return rule
+}
+
+// toTreasuryMovement converts a database movement to its business representation
+func toTreasuryMovement(row db.TreasuryMovement) business.TreasuryMovement {
+	movement := business.TreasuryMovement{
+		ID:                  row.ID,
+		WorkspaceID:         row.WorkspaceID,
+		RuleID:              row.RuleID,
+		NetworkID:           row.NetworkID,
+		TokenID:             row.TokenID,
+		SourceWalletID:      row.SourceWalletID,
+		DestinationWalletID: row.DestinationWalletID,
+		Amount:              formatDecimal(numericToRat(row.Amount)),
+		SourceBalance:       formatDecimal(numericToRat(row.SourceBalance)),
+		Status:              row.Status,
+		ChallengeID:         row.ChallengeID.String,
+		CircleTransactionID: row.CircleTransactionID.String,
+		CircleState:         row.CircleState.String,
+		TransactionHash:     row.TransactionHash.String,
+		ErrorMessage:        row.ErrorMessage.String,
+		CreatedAt:           row.CreatedAt.Time,
+		UpdatedAt:           row.UpdatedAt.Time,
+	}
+	if row.EstimatedFee.Valid {
+		movement.EstimatedFee = formatDecimal(numericToRat(row.EstimatedFee))
+	}
+	if row.NetworkFee.Valid {
+		movement.NetworkFee = formatDecimal(numericToRat(row.NetworkFee))
+	}
+	if row.ReconciledAt.Valid {
+		movement.ReconciledAt = &row.ReconciledAt.Time
+	}
+	if row.CompletedAt.Valid {
+		movement.CompletedAt = &row.CompletedAt.Time
+	}
+	return movement
+}
+
+// parseDecimalAmount parses a non-negative decimal amount such as "1250.5"
+func parseDecimalAmount(value string) (*big.Rat, error) {
+	value = strings.TrimSpace(value)
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok || strings.ContainsAny(value, "/eE") {
+		return nil, fmt.Errorf("must be a decimal number, got %q", value)
+	}
+	if amount.Sign() < 0 {
+		return nil, fmt.Errorf("must not be negative, got %q", value)
+	}
+	return amount, nil
+}
+
+// truncateDecimal drops the digits of an amount beyond a token's decimals, rounding towards zero
+func truncateDecimal(amount *big.Rat, decimals int32) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	units := new(big.Int).Quo(new(big.Int).Mul(amount.Num(), scale), amount.Denom())
+	return new(big.Rat).SetFrac(units, scale)
+}
+
+// numericToRat converts a NUMERIC column to an exact rational; NULL converts to zero
+func numericToRat(n pgtype.Numeric) *big.Rat {
+	if !n.Valid || n.Int == nil {
+		return new(big.Rat)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs32(n.Exp))), nil)
+	if n.Exp >= 0 {
+		return new(big.Rat).SetInt(new(big.Int).Mul(n.Int, scale))
+	}
+	return new(big.Rat).SetFrac(n.Int, scale)
+}
+
+// ratToNumeric converts an amount to a NUMERIC(36,18) column, truncating digits beyond the column's scale
+func ratToNumeric(amount *big.Rat) pgtype.Numeric {
+	truncated := truncateDecimal(amount, 18)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	units := new(big.Int).Quo(new(big.Int).Mul(truncated.Num(), scale), truncated.Denom())
+	return pgtype.Numeric{Int: units, Exp: -18, Valid: true}
+}
+
+// formatDecimal formats an amount without trailing zeros, e.g. "1250.5"
+func formatDecimal(amount *big.Rat) string {
+	formatted := amount.FloatString(18)
+	formatted = strings.TrimRight(formatted, "0")
+	return strings.TrimSuffix(formatted, ".")
+}
+
+func abs32(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
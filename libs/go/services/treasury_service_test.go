@@ -0,0 +1,388 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/client/circle"
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/mocks"
+	"github.com/cyphera/cyphera-api/libs/go/services"
+	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func treasuryNumeric(t *testing.T, value string) pgtype.Numeric {
+	t.Helper()
+	var n pgtype.Numeric
+	require.NoError(t, n.Scan(value))
+	return n
+}
+
+func circleBalances(balances ...circle.TokenBalance) *circle.WalletBalanceResponse {
+	resp := &circle.WalletBalanceResponse{}
+	resp.Data.TokenBalances = balances
+	return resp
+}
+
+func circleFeeEstimate(medium string) *circle.EstimateTransferFeeResponse {
+	resp := &circle.EstimateTransferFeeResponse{}
+	resp.Data.Low.NetworkFee = "0.0001"
+	resp.Data.Medium.NetworkFee = medium
+	resp.Data.High.NetworkFee = "0.01"
+	return resp
+}
+
+func TestTreasuryService_RunSweeps(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	usdcAddress := "0x036CbD53842c5426634e7929541eC2318f3dCF7e"
+	nativeBalance := circle.TokenBalance{Amount: "0.05", Token: circle.TokenInfo{ID: "eth-token", IsNative: true, Symbol: "ETH", Decimals: 18}}
+	usdcBalance := func(amount string) circle.TokenBalance {
+		return circle.TokenBalance{Amount: amount, Token: circle.TokenInfo{ID: "usdc-token", Symbol: "USDC", Decimals: 6, TokenAddress: "0x036cbd53842c5426634e7929541ec2318f3dcf7e"}}
+	}
+	usdcRule := func(t *testing.T) db.ClaimDueTreasurySweepRulesRow {
+		return db.ClaimDueTreasurySweepRulesRow{
+			ID:                  uuid.New(),
+			WorkspaceID:         uuid.New(),
+			SourceWalletID:      uuid.New(),
+			DestinationWalletID: uuid.New(),
+			TokenID:             uuid.New(),
+			ThresholdAmount:     treasuryNumeric(t, "1000"),
+			RetainedAmount:      treasuryNumeric(t, "100"),
+			FeeLevel:            business.TreasuryFeeLevelMedium,
+			CircleWalletID:      "circle-wallet-1",
+			CircleUserID:        uuid.New(),
+			DestinationAddress:  "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+			NetworkID:           uuid.New(),
+			TokenAddress:        usdcAddress,
+			TokenSymbol:         "USDC",
+			TokenDecimals:       6,
+		}
+	}
+
+	setup := func(t *testing.T, rule db.ClaimDueTreasurySweepRulesRow, balances *circle.WalletBalanceResponse) (*services.TreasuryService, *mocks.MockQuerier, *mocks.MockCircleClientInterface) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		mockCircle := mocks.NewMockCircleClientInterface(ctrl)
+
+		mockQuerier.EXPECT().ClaimDueTreasurySweepRules(gomock.Any(), db.ClaimDueTreasurySweepRulesParams{
+			Now:       pgtype.Timestamptz{Time: now, Valid: true},
+			BatchSize: services.DefaultTreasuryConfig().BatchSize,
+		}).Return([]db.ClaimDueTreasurySweepRulesRow{rule}, nil)
+		token := &circle.UserTokenResponse{}
+		token.Data.UserToken = "user-token"
+		mockCircle.EXPECT().CreateUserToken(gomock.Any(), rule.CircleUserID.String()).Return(token, nil)
+		mockCircle.EXPECT().GetWalletBalance(gomock.Any(), rule.CircleWalletID, "user-token", nil).Return(balances, nil)
+
+		return services.NewTreasuryService(mockQuerier, mockCircle, services.DefaultTreasuryConfig()), mockQuerier, mockCircle
+	}
+
+	expectRun := func(mockQuerier *mocks.MockQuerier, rule db.ClaimDueTreasurySweepRulesRow, outcome *string) {
+		mockQuerier.EXPECT().RecordTreasurySweepRuleRun(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.RecordTreasurySweepRuleRunParams) error {
+				assert.Equal(t, rule.ID, arg.ID)
+				assert.Equal(t, now, arg.LastRunAt.Time)
+				*outcome = arg.LastRunResult.String
+				return nil
+			})
+	}
+
+	t.Run("sweeps the balance above the retained amount", func(t *testing.T) {
+		rule := usdcRule(t)
+		service, mockQuerier, mockCircle := setup(t, rule, circleBalances(nativeBalance, usdcBalance("1500.25")))
+		movementID := uuid.New()
+
+		mockCircle.EXPECT().EstimateTransferFee(gomock.Any(), gomock.Any(), "user-token").
+			DoAndReturn(func(_ context.Context, req circle.EstimateTransferFeeRequest, _ string) (*circle.EstimateTransferFeeResponse, error) {
+				assert.Equal(t, []string{"1400.25"}, req.Amounts)
+				assert.Equal(t, "usdc-token", req.TokenID)
+				return circleFeeEstimate("0.002"), nil
+			})
+		mockQuerier.EXPECT().CreateTreasuryMovement(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.CreateTreasuryMovementParams) (db.TreasuryMovement, error) {
+				assert.Equal(t, rule.ID, arg.RuleID)
+				assert.Equal(t, rule.NetworkID, arg.NetworkID)
+				amount, err := arg.Amount.Float64Value()
+				require.NoError(t, err)
+				assert.Equal(t, 1400.25, amount.Float64)
+				return db.TreasuryMovement{ID: movementID, RuleID: rule.ID}, nil
+			})
+		mockCircle.EXPECT().CreateTransferChallenge(gomock.Any(), gomock.Any(), "user-token").
+			DoAndReturn(func(_ context.Context, req circle.TransferChallengeRequest, _ string) (*circle.TransferChallengeResponse, error) {
+				assert.Equal(t, movementID.String(), req.IdempotencyKey)
+				assert.Equal(t, movementID.String(), req.RefID)
+				assert.Equal(t, "circle-wallet-1", req.WalletID)
+				assert.Equal(t, rule.DestinationAddress, req.DestinationAddress)
+				assert.Equal(t, []string{"1400.25"}, req.Amounts)
+				assert.Equal(t, business.TreasuryFeeLevelMedium, req.FeeLevel)
+				resp := &circle.TransferChallengeResponse{}
+				resp.Data.ChallengeID = "challenge-1"
+				return resp, nil
+			})
+		mockQuerier.EXPECT().SetTreasuryMovementChallenge(gomock.Any(), db.SetTreasuryMovementChallengeParams{
+			ID:          movementID,
+			ChallengeID: pgtype.Text{String: "challenge-1", Valid: true},
+		}).Return(nil)
+		var outcome string
+		expectRun(mockQuerier, rule, &outcome)
+
+		result, err := service.RunSweeps(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, business.TreasurySweepResult{Checked: 1, Challenged: 1}, *result)
+		assert.Equal(t, "transfer awaiting approval", outcome)
+	})
+
+	t.Run("pays the fee out of the swept amount for the gas token", func(t *testing.T) {
+		rule := usdcRule(t)
+		rule.GasToken = true
+		rule.TokenSymbol = "ETH"
+		rule.TokenDecimals = 18
+		rule.ThresholdAmount = treasuryNumeric(t, "1")
+		rule.RetainedAmount = treasuryNumeric(t, "0.1")
+		service, mockQuerier, mockCircle := setup(t, rule, circleBalances(circle.TokenBalance{Amount: "1.5", Token: circle.TokenInfo{ID: "eth-token", IsNative: true}}))
+
+		mockCircle.EXPECT().EstimateTransferFee(gomock.Any(), gomock.Any(), "user-token").Return(circleFeeEstimate("0.0021"), nil)
+		mockQuerier.EXPECT().CreateTreasuryMovement(gomock.Any(), gomock.Any()).Return(db.TreasuryMovement{ID: uuid.New()}, nil)
+		mockCircle.EXPECT().CreateTransferChallenge(gomock.Any(), gomock.Any(), "user-token").
+			DoAndReturn(func(_ context.Context, req circle.TransferChallengeRequest, _ string) (*circle.TransferChallengeResponse, error) {
+				assert.Equal(t, []string{"1.3979"}, req.Amounts)
+				assert.Equal(t, "eth-token", req.TokenID)
+				return &circle.TransferChallengeResponse{}, nil
+			})
+		mockQuerier.EXPECT().SetTreasuryMovementChallenge(gomock.Any(), gomock.Any()).Return(nil)
+		var outcome string
+		expectRun(mockQuerier, rule, &outcome)
+
+		result, err := service.RunSweeps(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Challenged)
+	})
+
+	t.Run("skips a balance at or below the threshold", func(t *testing.T) {
+		rule := usdcRule(t)
+		service, mockQuerier, _ := setup(t, rule, circleBalances(nativeBalance, usdcBalance("1000")))
+		var outcome string
+		expectRun(mockQuerier, rule, &outcome)
+
+		result, err := service.RunSweeps(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, business.TreasurySweepResult{Checked: 1, Skipped: 1}, *result)
+		assert.Equal(t, "balance 1000 USDC is at or below the threshold", outcome)
+	})
+
+	t.Run("skips when the wallet cannot pay the fee", func(t *testing.T) {
+		rule := usdcRule(t)
+		service, mockQuerier, mockCircle := setup(t, rule, circleBalances(usdcBalance("5000")))
+		mockCircle.EXPECT().EstimateTransferFee(gomock.Any(), gomock.Any(), "user-token").Return(circleFeeEstimate("0.002"), nil)
+		var outcome string
+		expectRun(mockQuerier, rule, &outcome)
+
+		result, err := service.RunSweeps(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Skipped)
+		assert.Equal(t, "native balance 0 is below the estimated fee 0.002", outcome)
+	})
+
+	t.Run("fails the movement when Circle rejects the transfer", func(t *testing.T) {
+		rule := usdcRule(t)
+		service, mockQuerier, mockCircle := setup(t, rule, circleBalances(nativeBalance, usdcBalance("1500")))
+		movementID := uuid.New()
+
+		mockCircle.EXPECT().EstimateTransferFee(gomock.Any(), gomock.Any(), "user-token").Return(circleFeeEstimate("0.002"), nil)
+		mockQuerier.EXPECT().CreateTreasuryMovement(gomock.Any(), gomock.Any()).Return(db.TreasuryMovement{ID: movementID}, nil)
+		mockCircle.EXPECT().CreateTransferChallenge(gomock.Any(), gomock.Any(), "user-token").Return(nil, errors.New("insufficient funds"))
+		mockQuerier.EXPECT().FailTreasuryMovement(gomock.Any(), db.FailTreasuryMovementParams{
+			ID:           movementID,
+			ErrorMessage: pgtype.Text{String: "insufficient funds", Valid: true},
+		}).Return(nil)
+		var outcome string
+		expectRun(mockQuerier, rule, &outcome)
+
+		result, err := service.RunSweeps(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, business.TreasurySweepResult{Checked: 1, Failed: 1}, *result)
+		assert.Contains(t, outcome, "insufficient funds")
+	})
+}
+
+func TestTreasuryService_ReconcileMovements(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	mockQuerier := mocks.NewMockQuerier(ctrl)
+	mockCircle := mocks.NewMockCircleClientInterface(ctrl)
+
+	circleUserID := uuid.New()
+	movement := func(status string, age time.Duration, amount string) db.ListOpenTreasuryMovementsRow {
+		return db.ListOpenTreasuryMovementsRow{
+			ID:             uuid.New(),
+			WorkspaceID:    uuid.New(),
+			Amount:         treasuryNumeric(t, amount),
+			Status:         status,
+			CreatedAt:      pgtype.Timestamptz{Time: now.Add(-age), Valid: true},
+			CircleWalletID: "circle-wallet-1",
+			CircleUserID:   circleUserID,
+		}
+	}
+	completed := movement(business.TreasuryMovementSubmitted, 2*time.Hour, "250")
+	mismatched := movement(business.TreasuryMovementAwaitingApproval, time.Hour, "100")
+	rejected := movement(business.TreasuryMovementAwaitingApproval, time.Hour, "75")
+	sending := movement(business.TreasuryMovementAwaitingApproval, 30*time.Minute, "10")
+	waiting := movement(business.TreasuryMovementAwaitingApproval, time.Hour, "20")
+	abandoned := movement(business.TreasuryMovementAwaitingApproval, 25*time.Hour, "30")
+
+	mockQuerier.EXPECT().ListOpenTreasuryMovements(gomock.Any(), services.DefaultTreasuryConfig().BatchSize).
+		Return([]db.ListOpenTreasuryMovementsRow{completed, mismatched, rejected, sending, waiting, abandoned}, nil)
+	token := &circle.UserTokenResponse{}
+	token.Data.UserToken = "user-token"
+	mockCircle.EXPECT().CreateUserToken(gomock.Any(), circleUserID.String()).Return(token, nil)
+
+	transactions := &circle.TransactionListResponse{}
+	transactions.Data.Transactions = []circle.Transaction{
+		{ID: "tx-1", RefID: completed.ID.String(), State: "COMPLETE", TxHash: "0xabc", Amounts: []string{"250"}, NetworkFee: "0.0012"},
+		{ID: "tx-2", RefID: mismatched.ID.String(), State: "COMPLETE", TxHash: "0xdef", Amounts: []string{"99.5"}},
+		{ID: "tx-3", RefID: rejected.ID.String(), State: "DENIED", ErrorReason: "RISK_SCREENING"},
+		{ID: "tx-4", RefID: sending.ID.String(), State: "SENT", TxHash: "0x123"},
+		{ID: "tx-5", RefID: "unrelated-transfer", State: "COMPLETE"},
+	}
+	mockCircle.EXPECT().ListTransactions(gomock.Any(), "user-token", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, listParams *circle.ListTransactionsParams) (*circle.TransactionListResponse, error) {
+			assert.Equal(t, "circle-wallet-1", *listParams.WalletIDs)
+			assert.Equal(t, abandoned.CreatedAt.Time.Add(-time.Minute), *listParams.From)
+			return transactions, nil
+		})
+
+	updates := map[uuid.UUID]db.ReconcileTreasuryMovementParams{}
+	mockQuerier.EXPECT().ReconcileTreasuryMovement(gomock.Any(), gomock.Any()).Times(6).
+		DoAndReturn(func(_ context.Context, arg db.ReconcileTreasuryMovementParams) (db.TreasuryMovement, error) {
+			updates[arg.ID] = arg
+			return db.TreasuryMovement{ID: arg.ID, Status: arg.Status}, nil
+		})
+
+	service := services.NewTreasuryService(mockQuerier, mockCircle, services.DefaultTreasuryConfig())
+	result, err := service.ReconcileMovements(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, business.TreasuryReconcileResult{Checked: 6, Completed: 2, Failed: 1, Expired: 1}, *result)
+
+	assert.Equal(t, business.TreasuryMovementCompleted, updates[completed.ID].Status)
+	assert.Equal(t, "tx-1", updates[completed.ID].CircleTransactionID.String)
+	assert.Equal(t, "0xabc", updates[completed.ID].TransactionHash.String)
+	assert.True(t, updates[completed.ID].NetworkFee.Valid)
+	assert.False(t, updates[completed.ID].ErrorMessage.Valid)
+
+	assert.Equal(t, business.TreasuryMovementCompleted, updates[mismatched.ID].Status)
+	assert.Equal(t, "Circle transferred 99.5, expected 100", updates[mismatched.ID].ErrorMessage.String)
+
+	assert.Equal(t, business.TreasuryMovementFailed, updates[rejected.ID].Status)
+	assert.Equal(t, "RISK_SCREENING", updates[rejected.ID].ErrorMessage.String)
+
+	assert.Equal(t, business.TreasuryMovementSubmitted, updates[sending.ID].Status)
+	assert.Equal(t, "SENT", updates[sending.ID].CircleState.String)
+
+	assert.Equal(t, business.TreasuryMovementAwaitingApproval, updates[waiting.ID].Status)
+	assert.False(t, updates[waiting.ID].CircleTransactionID.Valid)
+	assert.Equal(t, now, updates[waiting.ID].ReconciledAt.Time)
+
+	assert.Equal(t, business.TreasuryMovementExpired, updates[abandoned.ID].Status)
+}
+
+func TestTreasuryService_CreateSweepRule(t *testing.T) {
+	workspaceID := uuid.New()
+	networkID := uuid.New()
+	sourceID := uuid.New()
+	destinationID := uuid.New()
+	tokenID := uuid.New()
+
+	validParams := func() params.TreasurySweepRuleParams {
+		return params.TreasurySweepRuleParams{
+			WorkspaceID:         workspaceID,
+			Name:                "Sweep USDC",
+			SourceWalletID:      sourceID,
+			DestinationWalletID: destinationID,
+			TokenID:             tokenID,
+			ThresholdAmount:     "1000",
+			RetainedAmount:      "50.5",
+			IsActive:            true,
+		}
+	}
+	circleSource := db.GetWalletWithCircleDataByIDRow{
+		ID:        sourceID,
+		NetworkID: pgtype.UUID{Bytes: networkID, Valid: true},
+		CircleID:  pgtype.Text{String: "circle-wallet-1", Valid: true},
+	}
+	destination := db.Wallet{ID: destinationID, NetworkID: pgtype.UUID{Bytes: networkID, Valid: true}}
+
+	t.Run("creates a rule with defaults", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockQuerier := mocks.NewMockQuerier(ctrl)
+		mockQuerier.EXPECT().GetWalletWithCircleDataByID(gomock.Any(), db.GetWalletWithCircleDataByIDParams{ID: sourceID, WorkspaceID: workspaceID}).Return(circleSource, nil)
+		mockQuerier.EXPECT().GetWalletByID(gomock.Any(), db.GetWalletByIDParams{ID: destinationID, WorkspaceID: workspaceID}).Return(destination, nil)
+		mockQuerier.EXPECT().GetToken(gomock.Any(), tokenID).Return(db.Token{ID: tokenID, NetworkID: networkID}, nil)
+		mockQuerier.EXPECT().CreateTreasurySweepRule(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.CreateTreasurySweepRuleParams) (db.TreasurySweepRule, error) {
+				assert.Equal(t, business.TreasuryFeeLevelMedium, arg.FeeLevel)
+				assert.Equal(t, int32(60), arg.SweepIntervalMinutes)
+				return db.TreasurySweepRule{
+					ID:                   uuid.New(),
+					WorkspaceID:          arg.WorkspaceID,
+					Name:                 arg.Name,
+					ThresholdAmount:      arg.ThresholdAmount,
+					RetainedAmount:       arg.RetainedAmount,
+					FeeLevel:             arg.FeeLevel,
+					SweepIntervalMinutes: arg.SweepIntervalMinutes,
+					IsActive:             arg.IsActive,
+				}, nil
+			})
+
+		service := services.NewTreasuryService(mockQuerier, nil, services.DefaultTreasuryConfig())
+		rule, err := service.CreateSweepRule(context.Background(), validParams())
+		require.NoError(t, err)
+		assert.Equal(t, "1000", rule.ThresholdAmount)
+		assert.Equal(t, "50.5", rule.RetainedAmount)
+	})
+
+	tests := []struct {
+		name    string
+		modify  func(*params.TreasurySweepRuleParams)
+		source  db.GetWalletWithCircleDataByIDRow
+		dest    db.Wallet
+		token   db.Token
+		wantErr string
+	}{
+		{name: "missing name", modify: func(p *params.TreasurySweepRuleParams) { p.Name = " " }, wantErr: "name is required"},
+		{name: "invalid threshold", modify: func(p *params.TreasurySweepRuleParams) { p.ThresholdAmount = "1e3" }, wantErr: "threshold_amount"},
+		{name: "negative retained amount", modify: func(p *params.TreasurySweepRuleParams) { p.RetainedAmount = "-1" }, wantErr: "retained_amount"},
+		{name: "retained above threshold", modify: func(p *params.TreasurySweepRuleParams) { p.RetainedAmount = "1000.01" }, wantErr: "cannot exceed"},
+		{name: "unknown fee level", modify: func(p *params.TreasurySweepRuleParams) { p.FeeLevel = "urgent" }, wantErr: "fee_level"},
+		{name: "interval too short", modify: func(p *params.TreasurySweepRuleParams) { p.SweepIntervalMinutes = 1 }, wantErr: "sweep_interval_minutes"},
+		{name: "same wallet", modify: func(p *params.TreasurySweepRuleParams) { p.DestinationWalletID = sourceID }, wantErr: "must differ"},
+		{name: "source is not a Circle wallet", source: db.GetWalletWithCircleDataByIDRow{ID: sourceID}, wantErr: "must be a Circle wallet"},
+		{name: "destination on another network", source: circleSource, dest: db.Wallet{NetworkID: pgtype.UUID{Bytes: uuid.New(), Valid: true}}, wantErr: "destination wallet must be on"},
+		{name: "token on another network", source: circleSource, dest: destination, token: db.Token{NetworkID: uuid.New()}, wantErr: "token must be on"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockQuerier := mocks.NewMockQuerier(ctrl)
+			mockQuerier.EXPECT().GetWalletWithCircleDataByID(gomock.Any(), gomock.Any()).Return(tt.source, nil).AnyTimes()
+			mockQuerier.EXPECT().GetWalletByID(gomock.Any(), gomock.Any()).Return(tt.dest, nil).AnyTimes()
+			mockQuerier.EXPECT().GetToken(gomock.Any(), gomock.Any()).Return(tt.token, nil).AnyTimes()
+
+			ruleParams := validParams()
+			if tt.modify != nil {
+				tt.modify(&ruleParams)
+			}
+			service := services.NewTreasuryService(mockQuerier, nil, services.DefaultTreasuryConfig())
+			_, err := service.CreateSweepRule(context.Background(), ruleParams)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, services.ErrInvalidTreasuryRule))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
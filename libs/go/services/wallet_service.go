@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/helpers"
 	"github.com/cyphera/cyphera-api/libs/go/logger"
 	"github.com/cyphera/cyphera-api/libs/go/types/api/params"
 	"github.com/cyphera/cyphera-api/libs/go/types/business"
@@ -47,6 +49,8 @@ func (s *WalletService) CreateWallet(ctx context.Context, createParams params.Cr
 		return nil, fmt.Errorf("invalid wallet type: %s", createParams.WalletType)
 	}
 
+	walletAddress := helpers.NormalizeWalletAddress(createParams.WalletAddress, createParams.NetworkType)
+
 	// Convert metadata to JSON
 	metadataJSON := []byte("{}")
 	if createParams.Metadata != nil {
@@ -67,7 +71,7 @@ func (s *WalletService) CreateWallet(ctx context.Context, createParams params.Cr
 	wallet, err := s.queries.CreateWallet(ctx, db.CreateWalletParams{
 		WorkspaceID:   createParams.WorkspaceID,
 		WalletType:    createParams.WalletType,
-		WalletAddress: createParams.WalletAddress,
+		WalletAddress: walletAddress,
 		NetworkType:   db.NetworkType(createParams.NetworkType),
 		NetworkID:     networkID,
 		Nickname:      pgtype.Text{String: createParams.Nickname, Valid: createParams.Nickname != ""},
@@ -80,7 +84,7 @@ func (s *WalletService) CreateWallet(ctx context.Context, createParams params.Cr
 		s.logger.Error("Failed to create wallet",
 			zap.String("workspace_id", createParams.WorkspaceID.String()),
 			zap.String("wallet_type", createParams.WalletType),
-			zap.String("wallet_address", createParams.WalletAddress),
+			zap.String("wallet_address", walletAddress),
 			zap.Error(err))
 		return nil, fmt.Errorf("failed to create wallet: %w", err)
 	}
@@ -336,8 +340,13 @@ func (s *WalletService) DeleteWallet(ctx context.Context, walletID, workspaceID
 
 // GetWalletByAddressAndNetwork retrieves a wallet by address and network
 func (s *WalletService) GetWalletByAddressAndNetwork(ctx context.Context, workspaceID uuid.UUID, walletAddress, networkType string) (*db.Wallet, error) {
+	addressType := db.NetworkTypeEvm
+	if strings.HasPrefix(networkType, string(db.CircleNetworkTypeSOL)) {
+		addressType = db.NetworkTypeSolana
+	}
+
 	wallet, err := s.queries.GetWalletByAddressAndCircleNetworkType(ctx, db.GetWalletByAddressAndCircleNetworkTypeParams{
-		WalletAddress:     walletAddress,
+		WalletAddress:     helpers.NormalizeWalletAddress(walletAddress, string(addressType)),
 		CircleNetworkType: db.CircleNetworkType(networkType),
 	})
 	if err != nil {
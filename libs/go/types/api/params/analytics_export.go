@@ -0,0 +1,30 @@
+package params
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateAnalyticsExportParams contains parameters for queueing an analytics export job
+type CreateAnalyticsExportParams struct {
+	WorkspaceID      uuid.UUID
+	ReportScheduleID *uuid.UUID
+	Datasets         []string
+	Format           string
+	Currency         string // Empty uses the workspace default currency
+	StartDate        time.Time
+	EndDate          time.Time // Exclusive
+}
+
+// AnalyticsReportScheduleParams contains parameters for creating or updating a scheduled report
+type AnalyticsReportScheduleParams struct {
+	WorkspaceID uuid.UUID
+	Name        string
+	Frequency   string
+	Datasets    []string
+	Format      string
+	Currency    string // Empty uses the workspace default currency at delivery
+	Recipients  []string
+	Active      bool
+}
@@ -33,3 +33,20 @@ type UpdateAPIKeyParams struct {
 	RateLimitRPD *int32
 	Metadata     map[string]interface{}
 }
+
+// RotateAPIKeyParams contains parameters for rotating an API key
+type RotateAPIKeyParams struct {
+	ID          uuid.UUID
+	WorkspaceID uuid.UUID
+	GracePeriod time.Duration
+	ExpiresAt   *time.Time
+}
+
+// RecordAPIKeyUsageParams describes a single authenticated request made with an API key
+type RecordAPIKeyUsageParams struct {
+	APIKeyID    uuid.UUID
+	WorkspaceID uuid.UUID
+	ClientIP    string
+	UserAgent   string
+	UsedAt      time.Time
+}
@@ -0,0 +1,68 @@
+package params
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomerPortalScope identifies the customer behind a portal request.
+// WorkspaceID is set for merchant-issued portal sessions, which only see that merchant's data;
+// customers signed in with their own Web3Auth token see every merchant they pay.
+type CustomerPortalScope struct {
+	CustomerID  uuid.UUID
+	WorkspaceID *uuid.UUID
+	SessionID   *uuid.UUID
+}
+
+// CreateCustomerPortalSessionParams contains parameters for creating a portal session link
+type CreateCustomerPortalSessionParams struct {
+	WorkspaceID       uuid.UUID
+	CustomerID        uuid.UUID
+	ReturnURL         string
+	TTL               time.Duration // Zero uses the workspace's session TTL
+	CreatedByAPIKeyID *uuid.UUID
+	CreatedByUserID   *uuid.UUID
+}
+
+// UpdateCustomerPortalSettingsParams contains parameters for updating portal settings.
+// Nil fields keep their current value.
+type UpdateCustomerPortalSettingsParams struct {
+	WorkspaceID        uuid.UUID
+	AllowCancel        *bool
+	AllowPause         *bool
+	AllowResume        *bool
+	AllowUpdateBilling *bool
+	AllowWalletSwitch  *bool
+	DefaultReturnURL   *string
+	SessionTTL         *time.Duration
+}
+
+// UpdateCustomerBillingDetailsParams contains the billing fields a customer may change from the portal.
+// Nil fields keep their current value.
+type UpdateCustomerBillingDetailsParams struct {
+	Scope             CustomerPortalScope
+	Name              *string
+	Email             *string
+	Phone             *string
+	BillingCountry    *string
+	BillingState      *string
+	BillingCity       *string
+	BillingPostalCode *string
+}
+
+// SwitchSubscriptionWalletParams contains parameters for moving a subscription to another customer wallet
+type SwitchSubscriptionWalletParams struct {
+	Scope            CustomerPortalScope
+	SubscriptionID   uuid.UUID
+	CustomerWalletID uuid.UUID
+	Delegation       DelegationParams // Signed by the new wallet
+}
+
+// ReauthorizeSubscriptionParams contains parameters for replacing a subscription's delegation once it can no
+// longer be used to pay for the subscription
+type ReauthorizeSubscriptionParams struct {
+	Scope          CustomerPortalScope
+	SubscriptionID uuid.UUID
+	Delegation     DelegationParams // Signed by the subscription's current wallet
+}
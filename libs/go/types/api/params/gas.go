@@ -2,7 +2,9 @@ package params
 
 import (
 	"math/big"
+	"time"
 
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
 	"github.com/google/uuid"
 )
 
@@ -25,8 +27,16 @@ type EstimateGasFeeParams struct {
 	ContractAddress   *string
 	MethodSignature   *string
 	TokenTransfer     bool
-	EstimatedGasLimit uint64
+	EstimatedGasLimit uint64 // Used when Call is nil or cannot be simulated
 	Currency          string
+	Call              *GasEstimateCall // Transaction to simulate with eth_estimateGas
+}
+
+// GasEstimateCall is the transaction a gas estimate simulates, such as a redemption's calldata
+type GasEstimateCall struct {
+	From string // Hex address of the sender
+	To   string // Hex address of the contract called
+	Data []byte
 }
 
 // SponsorshipCheckParams contains parameters for checking gas sponsorship eligibility
@@ -37,4 +47,38 @@ type SponsorshipCheckParams struct {
 	GasCostUSDCents int64
 	TransactionType string // e.g., "subscription", "one_time", "refund"
 	CustomerTier    string // e.g., "free", "pro", "enterprise"
+	NetworkID       uuid.UUID
+	TokenID         uuid.UUID
+	GasPriceWei     *big.Int  // Nil when unknown; rules with a gas price ceiling then do not match
+	At              time.Time // Zero means now
+}
+
+// SponsorshipRuleParams contains parameters for creating or replacing a sponsorship rule
+type SponsorshipRuleParams struct {
+	WorkspaceID         uuid.UUID
+	Name                string
+	Priority            int32
+	IsActive            bool
+	Conditions          business.SponsorshipRuleConditions
+	Action              business.SponsorshipAction
+	SponsorUpToUSDCents int64
+	SponsorPercentage   int32
+}
+
+// SponsorshipSettlementParams identifies a sponsorship reservation and the actual gas cost of its
+// confirmed transaction. The reservation is looked up by payment when ReservationID is nil.
+type SponsorshipSettlementParams struct {
+	WorkspaceID           uuid.UUID
+	ReservationID         uuid.UUID
+	PaymentID             uuid.UUID
+	ActualGasCostUSDCents int64
+}
+
+// SponsorshipReleaseParams identifies a sponsorship reservation to give back to the budget. The
+// reservation is looked up by payment when ReservationID is nil.
+type SponsorshipReleaseParams struct {
+	WorkspaceID   uuid.UUID
+	ReservationID uuid.UUID
+	PaymentID     uuid.UUID
+	Reason        string
 }
@@ -0,0 +1,30 @@
+package params
+
+import (
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/google/uuid"
+)
+
+// RecordMRRMovementParams contains a subscription change to record in the MRR movements ledger
+type RecordMRRMovementParams struct {
+	Subscription     db.Subscription
+	Product          *db.Product // Looked up from the subscription when nil
+	Source           string      // One of the business.MRRSource values
+	FromAmountCents  int64       // Billing amount per interval before the change
+	ToAmountCents    int64       // Billing amount per interval after the change
+	ScheduleChangeID *uuid.UUID
+	OccurredAt       time.Time
+}
+
+// ListMRRMovementsParams contains filters for the MRR movements drill-down
+type ListMRRMovementsParams struct {
+	WorkspaceID  uuid.UUID
+	Currency     string // Reporting currency; the workspace default when empty
+	StartDate    time.Time
+	EndDate      time.Time
+	MovementType string // Optional movement type filter
+	Limit        int32
+	Offset       int32
+}
@@ -16,6 +16,9 @@ type CreateNetworkParams struct {
 	DisplayName       string
 	ChainNamespace    string
 	GasConfig         *CreateGasConfigParams
+	// Confirmation thresholds in blocks; zero uses the defaults
+	ConfirmationBlocks int32
+	FinalityBlocks     int32
 }
 
 // CreateGasConfigParams contains gas configuration parameters
@@ -57,6 +60,9 @@ type UpdateNetworkParams struct {
 	DisplayName       string
 	ChainNamespace    string
 	GasConfig         *UpdateGasConfigParams
+	// Confirmation thresholds in blocks; nil leaves them unchanged
+	ConfirmationBlocks *int32
+	FinalityBlocks     *int32
 }
 
 // UpdateGasConfigParams contains gas configuration parameters for updates
@@ -46,6 +46,7 @@ type CreateComprehensivePaymentParams struct {
 	ExchangeRate      *string
 	GasFeeUSDCents    *int64
 	GasFeeSponsoredBy *string
+	GasEstimateCall   *GasEstimateCall // Transaction the gas fee is estimated against, when known
 
 	// Tax information
 	TaxExempt         bool
@@ -103,6 +103,7 @@ type ValidateSubscriptionParams struct {
 	TokenAmount               string
 	Delegation                DelegationParams
 	CypheraSmartWalletAddress string // The expected delegate address
+	SolanaDelegateAddress     string // The expected delegate on Solana token account approvals
 }
 
 // CreateProductAddonRelationshipParams contains parameters for creating a product addon relationship
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/cyphera/cyphera-api/libs/go/db"
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
 	"github.com/google/uuid"
 )
 
@@ -168,4 +169,5 @@ type CreateSubscriptionAfterPaymentParams struct {
 	CreateParams      CreateSubscriptionWithDelegationParams
 	TransactionHash   string
 	NormalizedAddress string
+	SplApproval       *business.VerifiedSplApproval // The verified approval behind a Solana subscription
 }
@@ -1,26 +1,29 @@
 package params
 
 import (
+	"time"
+
 	"github.com/cyphera/cyphera-api/libs/go/types/business"
 	"github.com/google/uuid"
 )
 
 // TaxCalculationParams contains parameters for tax calculation
 type TaxCalculationParams struct {
-	WorkspaceID       uuid.UUID
-	CustomerID        uuid.UUID
-	ProductID         *uuid.UUID
-	SubscriptionID    *uuid.UUID
-	AmountCents       int64
-	Currency          string
-	CustomerAddress   *business.Address
-	BusinessAddress   *business.Address
-	TaxExempt         bool
-	TaxExemptionCode  *string
-	CustomerVATNumber *string
-	TransactionType   string // "purchase", "subscription", "refund"
-	ProductType       string // "digital_goods", "physical_goods", "service"
-	IsB2B             bool
+	WorkspaceID          uuid.UUID
+	CustomerID           uuid.UUID
+	ProductID            *uuid.UUID
+	SubscriptionID       *uuid.UUID
+	AmountCents          int64
+	Currency             string
+	CustomerAddress      *business.Address
+	BusinessAddress      *business.Address
+	TaxExempt            bool
+	TaxExemptionCode     *string
+	CustomerVATNumber    *string
+	TransactionType      string // "purchase", "subscription", "refund"
+	ProductType          string // "digital_goods", "physical_goods", "service"
+	IsB2B                bool
+	TransactionReference string // Recorded with external tax providers, e.g. the invoice number
 }
 
 // ShippingInfo contains shipping information for tax calculation
@@ -29,3 +32,37 @@ type ShippingInfo struct {
 	FromAddress *business.Address
 	ToAddress   *business.Address
 }
+
+// CreateTaxJurisdictionParams contains parameters for creating a tax jurisdiction
+type CreateTaxJurisdictionParams struct {
+	Code             string
+	Name             string
+	JurisdictionType string // "country", "state", "province", "county", "city"
+	CountryCode      string
+	SubdivisionCode  string // Required for state, province and local jurisdictions
+	Locality         string // City or county name for local jurisdictions
+	ParentID         *uuid.UUID
+	TaxType          string
+	IsActive         bool
+}
+
+// UpdateTaxJurisdictionParams contains parameters for updating a tax jurisdiction.
+// Nil fields keep their current value.
+type UpdateTaxJurisdictionParams struct {
+	ID       uuid.UUID
+	Name     *string
+	TaxType  *string
+	IsActive *bool
+}
+
+// CreateTaxRateParams contains parameters for adding a versioned tax rate
+type CreateTaxRateParams struct {
+	JurisdictionID uuid.UUID
+	ProductType    string // Empty uses the default rate
+	Rate           float64
+	ThresholdCents int64
+	EffectiveFrom  time.Time
+	EffectiveTo    *time.Time // Nil keeps the rate in force until superseded
+	RulesVersion   string
+	Description    string
+}
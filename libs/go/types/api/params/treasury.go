@@ -0,0 +1,29 @@
+package params
+
+import (
+	"github.com/google/uuid"
+)
+
+// TreasurySweepRuleParams contains parameters for creating or replacing a treasury sweep rule. The source
+// wallet and token of an existing rule cannot be changed.
+type TreasurySweepRuleParams struct {
+	WorkspaceID          uuid.UUID
+	Name                 string
+	SourceWalletID       uuid.UUID
+	DestinationWalletID  uuid.UUID
+	TokenID              uuid.UUID
+	ThresholdAmount      string
+	RetainedAmount       string
+	FeeLevel             string // Defaults to MEDIUM
+	SweepIntervalMinutes int32  // Defaults to 60
+	IsActive             bool
+}
+
+// ListTreasuryMovementsParams filters a workspace's treasury ledger
+type ListTreasuryMovementsParams struct {
+	WorkspaceID uuid.UUID
+	RuleID      *uuid.UUID
+	Status      string
+	Limit       int32
+	Offset      int32
+}
@@ -0,0 +1,21 @@
+package requests
+
+// CreateAnalyticsExportRequest represents the request to export analytics data for a date range
+type CreateAnalyticsExportRequest struct {
+	Datasets  []string `json:"datasets" binding:"required,min=1"` // payments, invoices, subscriptions, mrr_movements, gas_fees
+	Format    string   `json:"format,omitempty"`                  // csv (default) or parquet
+	Currency  string   `json:"currency,omitempty"`                // Defaults to the workspace default currency
+	StartDate string   `json:"start_date" binding:"required"`     // YYYY-MM-DD, inclusive
+	EndDate   string   `json:"end_date" binding:"required"`       // YYYY-MM-DD, inclusive
+}
+
+// AnalyticsReportScheduleRequest represents the request to create or replace a scheduled report
+type AnalyticsReportScheduleRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	Frequency  string   `json:"frequency" binding:"required"` // weekly or monthly
+	Datasets   []string `json:"datasets" binding:"required,min=1"`
+	Format     string   `json:"format,omitempty"`   // csv (default) or parquet
+	Currency   string   `json:"currency,omitempty"` // Defaults to the workspace default currency
+	Recipients []string `json:"recipients" binding:"required,min=1"`
+	Active     *bool    `json:"active,omitempty"` // Defaults to true
+}
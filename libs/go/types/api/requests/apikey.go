@@ -19,3 +19,10 @@ type UpdateAPIKeyRequest struct {
 	AccessLevel string                 `json:"access_level,omitempty" binding:"omitempty,oneof=read write admin"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
+
+// RotateAPIKeyRequest represents the request body for rotating an API key
+type RotateAPIKeyRequest struct {
+	// GracePeriodSeconds is how long the old key stays valid after rotation (defaults to 24 hours)
+	GracePeriodSeconds *int64     `json:"grace_period_seconds,omitempty" binding:"omitempty,min=0,max=2592000"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+}
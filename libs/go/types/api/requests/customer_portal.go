@@ -0,0 +1,46 @@
+package requests
+
+import "github.com/cyphera/cyphera-api/libs/go/types/business"
+
+// CreateCustomerPortalSessionRequest represents the request body for creating a customer portal link
+type CreateCustomerPortalSessionRequest struct {
+	CustomerID string `json:"customer_id" binding:"required,uuid"`
+	ReturnURL  string `json:"return_url,omitempty" binding:"omitempty,url"`
+	// ExpiresInSeconds overrides the workspace's session TTL (5 minutes to 24 hours)
+	ExpiresInSeconds *int64 `json:"expires_in_seconds,omitempty" binding:"omitempty,min=300,max=86400"`
+}
+
+// UpdateCustomerPortalSettingsRequest represents the request body for updating customer portal settings
+type UpdateCustomerPortalSettingsRequest struct {
+	AllowCancel        *bool   `json:"allow_cancel,omitempty"`
+	AllowPause         *bool   `json:"allow_pause,omitempty"`
+	AllowResume        *bool   `json:"allow_resume,omitempty"`
+	AllowUpdateBilling *bool   `json:"allow_update_billing,omitempty"`
+	AllowWalletSwitch  *bool   `json:"allow_wallet_switch,omitempty"`
+	DefaultReturnURL   *string `json:"default_return_url,omitempty" binding:"omitempty,url"`
+	SessionTTLSeconds  *int32  `json:"session_ttl_seconds,omitempty" binding:"omitempty,min=300,max=86400"`
+}
+
+// UpdateCustomerBillingDetailsRequest represents the request body for updating billing details from the portal
+type UpdateCustomerBillingDetailsRequest struct {
+	Name              *string `json:"name,omitempty" binding:"omitempty,max=255"`
+	Email             *string `json:"email,omitempty" binding:"omitempty,email,max=255"`
+	Phone             *string `json:"phone,omitempty" binding:"omitempty,max=255"`
+	BillingCountry    *string `json:"billing_country,omitempty" binding:"omitempty,len=2"`
+	BillingState      *string `json:"billing_state,omitempty" binding:"omitempty,max=100"`
+	BillingCity       *string `json:"billing_city,omitempty" binding:"omitempty,max=100"`
+	BillingPostalCode *string `json:"billing_postal_code,omitempty" binding:"omitempty,max=20"`
+}
+
+// SwitchSubscriptionWalletRequest represents the request body for paying a subscription from another wallet.
+// The delegation must be signed by the new wallet.
+type SwitchSubscriptionWalletRequest struct {
+	CustomerWalletID string                    `json:"customer_wallet_id" binding:"required,uuid"`
+	Delegation       business.DelegationStruct `json:"delegation" binding:"required"`
+}
+
+// ReauthorizeSubscriptionRequest represents the request body for replacing a subscription's delegation
+// after it was revoked, expired or ran out of allowance. The delegation must be signed by the same wallet.
+type ReauthorizeSubscriptionRequest struct {
+	Delegation business.DelegationStruct `json:"delegation" binding:"required"`
+}
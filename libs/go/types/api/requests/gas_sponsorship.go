@@ -1,6 +1,11 @@
 package requests
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+)
 
 // GasSponsorshipConfigRequest represents the request to create/update gas sponsorship config
 type GasSponsorshipConfigRequest struct {
@@ -11,4 +16,30 @@ type GasSponsorshipConfigRequest struct {
 	SponsorForProducts       []uuid.UUID `json:"sponsor_for_products,omitempty"`
 	SponsorForCustomers      []uuid.UUID `json:"sponsor_for_customers,omitempty"`
 	SponsorForTiers          []string    `json:"sponsor_for_tiers,omitempty"`
+
+	PerCustomerMonthlyCapUsdCents *int64 `json:"per_customer_monthly_cap_usd_cents,omitempty"`
+}
+
+// GasSponsorshipRuleRequest represents the request to create or replace a sponsorship rule
+type GasSponsorshipRuleRequest struct {
+	Name                string                             `json:"name" binding:"required"`
+	Priority            int32                              `json:"priority"`
+	IsActive            *bool                              `json:"is_active,omitempty"` // Defaults to true
+	Conditions          business.SponsorshipRuleConditions `json:"conditions"`
+	Action              string                             `json:"action" binding:"required"` // sponsor_full, sponsor_up_to or split_percentage
+	SponsorUpToUsdCents int64                              `json:"sponsor_up_to_usd_cents,omitempty"`
+	SponsorPercentage   int32                              `json:"sponsor_percentage,omitempty"`
+}
+
+// SimulateGasSponsorshipRequest describes a hypothetical transaction to run through the sponsorship policy
+type SimulateGasSponsorshipRequest struct {
+	CustomerID      string     `json:"customer_id,omitempty"`
+	ProductID       string     `json:"product_id,omitempty"`
+	NetworkID       string     `json:"network_id,omitempty"`
+	TokenID         string     `json:"token_id,omitempty"`
+	CustomerTier    string     `json:"customer_tier,omitempty"`
+	TransactionType string     `json:"transaction_type,omitempty"`
+	GasCostUsdCents int64      `json:"gas_cost_usd_cents" binding:"required,gt=0"`
+	GasPriceGwei    *float64   `json:"gas_price_gwei,omitempty"`
+	At              *time.Time `json:"at,omitempty"` // Defaults to now
 }
@@ -14,6 +14,9 @@ type CreateNetworkRequest struct {
 	DisplayName       string                  `json:"display_name,omitempty"`
 	ChainNamespace    string                  `json:"chain_namespace,omitempty"`
 	GasConfig         *CreateGasConfigRequest `json:"gas_config,omitempty"`
+	// Blocks a payment needs before it is confirmed, and after which it is final
+	ConfirmationBlocks int32 `json:"confirmation_blocks,omitempty"`
+	FinalityBlocks     int32 `json:"finality_blocks,omitempty"`
 }
 
 // CreateGasConfigRequest represents gas configuration for creating a network
@@ -44,6 +47,9 @@ type UpdateNetworkRequest struct {
 	DisplayName       string                  `json:"display_name,omitempty"`
 	ChainNamespace    string                  `json:"chain_namespace,omitempty"`
 	GasConfig         *UpdateGasConfigRequest `json:"gas_config,omitempty"`
+	// Blocks a payment needs before it is confirmed, and after which it is final
+	ConfirmationBlocks *int32 `json:"confirmation_blocks,omitempty"`
+	FinalityBlocks     *int32 `json:"finality_blocks,omitempty"`
 }
 
 // UpdateGasConfigRequest represents gas configuration for updating a network
@@ -0,0 +1,6 @@
+package requests
+
+// RequeueRedemptionTaskRequest represents the request to requeue a pending or dead-lettered redemption task
+type RequeueRedemptionTaskRequest struct {
+	Priority *int32 `json:"priority,omitempty"` // Keeps the task's priority when omitted
+}
@@ -0,0 +1,7 @@
+package requests
+
+// ResolveSubscriptionRenewalRequest represents the request to resolve an interrupted subscription renewal
+// with the transaction its redemption went through with
+type ResolveSubscriptionRenewalRequest struct {
+	TransactionHash string `json:"transaction_hash" binding:"required"`
+}
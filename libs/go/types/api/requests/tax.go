@@ -0,0 +1,37 @@
+package requests
+
+// CreateTaxJurisdictionRequest represents the request body for creating a tax jurisdiction
+type CreateTaxJurisdictionRequest struct {
+	Code             string `json:"code" binding:"required,max=50"`
+	Name             string `json:"name" binding:"required,max=255"`
+	JurisdictionType string `json:"jurisdiction_type" binding:"required,oneof=country state province county city"`
+	CountryCode      string `json:"country_code" binding:"required,len=2"`
+	SubdivisionCode  string `json:"subdivision_code,omitempty" binding:"omitempty,max=10"`
+	Locality         string `json:"locality,omitempty" binding:"omitempty,max=255"`
+	ParentID         string `json:"parent_id,omitempty" binding:"omitempty,uuid"`
+	TaxType          string `json:"tax_type,omitempty" binding:"omitempty,oneof=sales vat gst tax"`
+	IsActive         *bool  `json:"is_active,omitempty"`
+}
+
+// UpdateTaxJurisdictionRequest represents the request body for updating a tax jurisdiction
+type UpdateTaxJurisdictionRequest struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,max=255"`
+	TaxType  *string `json:"tax_type,omitempty" binding:"omitempty,oneof=sales vat gst tax"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+// CreateTaxRateRequest represents the request body for adding a versioned tax rate
+type CreateTaxRateRequest struct {
+	ProductType    string  `json:"product_type,omitempty" binding:"omitempty,max=50"`
+	Rate           float64 `json:"rate" binding:"min=0,lt=1"` // 0.0725 for 7.25%
+	ThresholdCents int64   `json:"threshold_cents,omitempty" binding:"omitempty,min=0"`
+	EffectiveFrom  int64   `json:"effective_from" binding:"required"` // Unix timestamp
+	EffectiveTo    *int64  `json:"effective_to,omitempty"`            // Unix timestamp; omit for open-ended rates
+	RulesVersion   string  `json:"rules_version" binding:"required,max=50"`
+	Description    string  `json:"description,omitempty"`
+}
+
+// EndTaxRateRequest represents the request body for ending a tax rate
+type EndTaxRateRequest struct {
+	EffectiveTo int64 `json:"effective_to" binding:"required"` // Unix timestamp
+}
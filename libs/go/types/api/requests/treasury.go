@@ -0,0 +1,15 @@
+package requests
+
+// TreasurySweepRuleRequest represents the request to create or replace a treasury sweep rule. Amounts are
+// decimal strings in whole token units. The source wallet and token of an existing rule cannot be changed.
+type TreasurySweepRuleRequest struct {
+	Name                 string `json:"name" binding:"required"`
+	SourceWalletID       string `json:"source_wallet_id,omitempty"` // Circle wallet to sweep; required on create
+	DestinationWalletID  string `json:"destination_wallet_id" binding:"required"`
+	TokenID              string `json:"token_id,omitempty"` // Required on create
+	ThresholdAmount      string `json:"threshold_amount" binding:"required"`
+	RetainedAmount       string `json:"retained_amount,omitempty"`        // Left in the source wallet, defaults to 0
+	FeeLevel             string `json:"fee_level,omitempty"`              // LOW, MEDIUM or HIGH, defaults to MEDIUM
+	SweepIntervalMinutes int32  `json:"sweep_interval_minutes,omitempty"` // Defaults to 60
+	IsActive             *bool  `json:"is_active,omitempty"`              // Defaults to true
+}
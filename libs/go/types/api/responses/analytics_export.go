@@ -0,0 +1,47 @@
+package responses
+
+// AnalyticsExportJobResponse represents an analytics export job and its files
+type AnalyticsExportJobResponse struct {
+	ID               string                        `json:"id"`
+	Object           string                        `json:"object"`
+	ReportScheduleID *string                       `json:"report_schedule_id,omitempty"`
+	Datasets         []string                      `json:"datasets"`
+	Format           string                        `json:"format"`
+	Currency         string                        `json:"currency"`
+	StartDate        string                        `json:"start_date"`
+	EndDate          string                        `json:"end_date"`
+	Status           string                        `json:"status"`
+	Attempts         int32                         `json:"attempts"`
+	Files            []AnalyticsExportFileResponse `json:"files"`
+	ErrorMessage     string                        `json:"error_message,omitempty"`
+	StartedAt        *int64                        `json:"started_at,omitempty"`
+	CompletedAt      *int64                        `json:"completed_at,omitempty"`
+	CreatedAt        int64                         `json:"created_at"`
+}
+
+// AnalyticsExportFileResponse represents one dataset file of a completed export
+type AnalyticsExportFileResponse struct {
+	Dataset     string `json:"dataset"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	RowCount    int64  `json:"row_count"`
+	SizeBytes   int64  `json:"size_bytes"`
+	DownloadURL string `json:"download_url"`
+}
+
+// AnalyticsReportScheduleResponse represents a scheduled analytics report
+type AnalyticsReportScheduleResponse struct {
+	ID         string   `json:"id"`
+	Object     string   `json:"object"`
+	Name       string   `json:"name"`
+	Frequency  string   `json:"frequency"`
+	Datasets   []string `json:"datasets"`
+	Format     string   `json:"format"`
+	Currency   string   `json:"currency,omitempty"`
+	Recipients []string `json:"recipients"`
+	Active     bool     `json:"active"`
+	NextRunAt  int64    `json:"next_run_at"`
+	LastRunAt  *int64   `json:"last_run_at,omitempty"`
+	CreatedAt  int64    `json:"created_at"`
+	UpdatedAt  int64    `json:"updated_at"`
+}
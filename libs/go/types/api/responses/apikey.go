@@ -12,7 +12,9 @@ type APIKeyResponse struct {
 	CreatedAt   int64                  `json:"created_at"`
 	UpdatedAt   int64                  `json:"updated_at"`
 	KeyPrefix   string                 `json:"key_prefix,omitempty"` // Shows first part of key for identification
-	Key         string                 `json:"key,omitempty"`        // Only included on creation
+	Key         string                 `json:"key,omitempty"`        // Only included on creation and rotation
+	RotatedFrom string                 `json:"rotated_from,omitempty"`
+	RotatedAt   *int64                 `json:"rotated_at,omitempty"`
 }
 
 // ListAPIKeysResponse represents the paginated response for API key list operations
@@ -22,3 +24,28 @@ type ListAPIKeysResponse struct {
 	HasMore bool             `json:"has_more"`
 	Total   int64            `json:"total"`
 }
+
+// RotateAPIKeyResponse represents the response for rotating an API key
+type RotateAPIKeyResponse struct {
+	Object string         `json:"object"`
+	APIKey APIKeyResponse `json:"api_key"`
+	// PreviousKeyExpiresAt is when the rotated key stops being accepted
+	PreviousKeyExpiresAt *int64 `json:"previous_key_expires_at,omitempty"`
+}
+
+// APIKeyUsageResponse represents request counts for an API key on a single day
+type APIKeyUsageResponse struct {
+	Object        string `json:"object"`
+	Date          string `json:"date"`
+	RequestCount  int64  `json:"request_count"`
+	LastIP        string `json:"last_ip,omitempty"`
+	LastUserAgent string `json:"last_user_agent,omitempty"`
+	LastUsedAt    int64  `json:"last_used_at"`
+}
+
+// ListAPIKeyUsageResponse represents the daily usage of an API key over a date range
+type ListAPIKeyUsageResponse struct {
+	Object        string                `json:"object"`
+	Data          []APIKeyUsageResponse `json:"data"`
+	TotalRequests int64                 `json:"total_requests"`
+}
@@ -0,0 +1,95 @@
+package responses
+
+import "github.com/cyphera/cyphera-api/libs/go/types/business"
+
+// CustomerPortalSessionResponse represents a customer portal link
+type CustomerPortalSessionResponse struct {
+	ID          string `json:"id"`
+	Object      string `json:"object"`
+	WorkspaceID string `json:"workspace_id"`
+	CustomerID  string `json:"customer_id"`
+	URL         string `json:"url,omitempty"`   // Only included on creation
+	Token       string `json:"token,omitempty"` // Only included on creation
+	ReturnURL   string `json:"return_url,omitempty"`
+	ExpiresAt   int64  `json:"expires_at"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+// CustomerPortalSettingsResponse represents a workspace's customer portal settings
+type CustomerPortalSettingsResponse struct {
+	Object             string `json:"object"`
+	WorkspaceID        string `json:"workspace_id"`
+	AllowCancel        bool   `json:"allow_cancel"`
+	AllowPause         bool   `json:"allow_pause"`
+	AllowResume        bool   `json:"allow_resume"`
+	AllowUpdateBilling bool   `json:"allow_update_billing"`
+	AllowWalletSwitch  bool   `json:"allow_wallet_switch"`
+	DefaultReturnURL   string `json:"default_return_url,omitempty"`
+	SessionTTLSeconds  int32  `json:"session_ttl_seconds"`
+}
+
+// CustomerBillingDetailsResponse represents a customer's billing address
+type CustomerBillingDetailsResponse struct {
+	Country    string `json:"country,omitempty"`
+	State      string `json:"state,omitempty"`
+	City       string `json:"city,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+}
+
+// CustomerPortalProfileResponse represents the signed-in customer in the portal
+type CustomerPortalProfileResponse struct {
+	Customer CustomerResponse               `json:"customer"`
+	Billing  CustomerBillingDetailsResponse `json:"billing"`
+	Wallets  []CustomerWalletResponse       `json:"wallets"`
+	// Permissions is only set for merchant-issued portal sessions
+	Permissions *business.CustomerPortalPermissions `json:"permissions,omitempty"`
+}
+
+// CustomerPortalSubscriptionResponse represents a subscription as shown in the customer portal
+type CustomerPortalSubscriptionResponse struct {
+	ID                    string                             `json:"id"`
+	Object                string                             `json:"object"`
+	WorkspaceID           string                             `json:"workspace_id"`
+	MerchantName          string                             `json:"merchant_name"`
+	ProductName           string                             `json:"product_name"`
+	Status                string                             `json:"status"`
+	Currency              string                             `json:"currency,omitempty"`
+	AmountInCents         int32                              `json:"amount_in_cents"`
+	CurrentPeriodStart    int64                              `json:"current_period_start"`
+	CurrentPeriodEnd      int64                              `json:"current_period_end"`
+	NextRedemptionDate    *int64                             `json:"next_redemption_date,omitempty"`
+	CancelAt              *int64                             `json:"cancel_at,omitempty"`
+	PausedAt              *int64                             `json:"paused_at,omitempty"`
+	PauseEndsAt           *int64                             `json:"pause_ends_at,omitempty"`
+	CustomerWalletID      string                             `json:"customer_wallet_id,omitempty"`
+	WalletAddress         string                             `json:"wallet_address,omitempty"`
+	Permissions           business.CustomerPortalPermissions `json:"permissions"`
+	ReauthorizationReason string                             `json:"reauthorization_reason,omitempty"`
+	CreatedAt             int64                              `json:"created_at"`
+}
+
+// CustomerPortalInvoiceResponse represents an invoice as shown in the customer portal
+type CustomerPortalInvoiceResponse struct {
+	ID               string `json:"id"`
+	Object           string `json:"object"`
+	WorkspaceID      string `json:"workspace_id"`
+	SubscriptionID   string `json:"subscription_id,omitempty"`
+	InvoiceNumber    string `json:"invoice_number,omitempty"`
+	Status           string `json:"status"`
+	Currency         string `json:"currency"`
+	AmountDue        int32  `json:"amount_due"`
+	AmountPaid       int32  `json:"amount_paid"`
+	AmountRemaining  int32  `json:"amount_remaining"`
+	DueDate          *int64 `json:"due_date,omitempty"`
+	PaidAt           *int64 `json:"paid_at,omitempty"`
+	HostedInvoiceURL string `json:"hosted_invoice_url,omitempty"`
+	InvoicePDF       string `json:"invoice_pdf,omitempty"`
+	CreatedAt        int64  `json:"created_at"`
+}
+
+// CustomerPortalListResponse represents a page of portal resources
+type CustomerPortalListResponse struct {
+	Object  string      `json:"object"`
+	Data    interface{} `json:"data"`
+	HasMore bool        `json:"has_more"`
+}
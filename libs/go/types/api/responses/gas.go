@@ -22,4 +22,10 @@ type EstimateGasFeeResult struct {
 	EstimatedCostUSD      float64 `json:"estimated_cost_usd"`
 	EstimatedCostUSDCents int64   `json:"estimated_cost_usd_cents"`
 	Confidence            float64 `json:"confidence"`
+	EstimateSource        string  `json:"estimate_source"` // "fee_history" or "static"
+	GasUnitsSimulated     bool    `json:"gas_units_simulated"`
+	BaseFeeWei            string  `json:"base_fee_wei,omitempty"`
+	PriorityFeeWei        string  `json:"priority_fee_wei,omitempty"`
+	MaxFeePerGasWei       string  `json:"max_fee_per_gas_wei,omitempty"`
+	L1DataFeeWei          string  `json:"l1_data_fee_wei,omitempty"`
 }
@@ -1,6 +1,9 @@
 package responses
 
-import "github.com/google/uuid"
+import (
+	"github.com/cyphera/cyphera-api/libs/go/types/business"
+	"github.com/google/uuid"
+)
 
 // GasSponsorshipConfigResponse represents a gas sponsorship configuration
 type GasSponsorshipConfigResponse struct {
@@ -14,4 +17,65 @@ type GasSponsorshipConfigResponse struct {
 	SponsorForTiers          []string    `json:"sponsor_for_tiers"`
 	CurrentMonthSpentCents   int64       `json:"current_month_spent_cents"`
 	RemainingBudgetCents     *int64      `json:"remaining_budget_cents,omitempty"`
+
+	CurrentMonthReservedCents int64 `json:"current_month_reserved_cents"`
+
+	PerCustomerMonthlyCapUsdCents *int64 `json:"per_customer_monthly_cap_usd_cents,omitempty"`
+}
+
+// GasSponsorshipRuleResponse represents a sponsorship policy rule
+type GasSponsorshipRuleResponse struct {
+	ID                  string                             `json:"id"`
+	Object              string                             `json:"object"`
+	Name                string                             `json:"name"`
+	Priority            int32                              `json:"priority"`
+	IsActive            bool                               `json:"is_active"`
+	Conditions          business.SponsorshipRuleConditions `json:"conditions"`
+	Action              string                             `json:"action"`
+	SponsorUpToUsdCents *int64                             `json:"sponsor_up_to_usd_cents,omitempty"`
+	SponsorPercentage   *int32                             `json:"sponsor_percentage,omitempty"`
+	CreatedAt           int64                              `json:"created_at"`
+	UpdatedAt           int64                              `json:"updated_at"`
+}
+
+// GasSponsorshipSimulationResponse shows the sponsorship decision for a hypothetical transaction
+// and how each rule evaluated
+type GasSponsorshipSimulationResponse struct {
+	ShouldSponsor              bool                                   `json:"should_sponsor"`
+	Reason                     string                                 `json:"reason"`
+	GasCostUsdCents            int64                                  `json:"gas_cost_usd_cents"`
+	SponsoredAmountUsdCents    int64                                  `json:"sponsored_amount_usd_cents"`
+	CustomerAmountUsdCents     int64                                  `json:"customer_amount_usd_cents"`
+	RuleID                     *string                                `json:"rule_id,omitempty"`
+	RuleName                   string                                 `json:"rule_name,omitempty"`
+	RemainingBudgetCents       int64                                  `json:"remaining_budget_cents"`
+	CustomerMonthSpentUsdCents int64                                  `json:"customer_month_spent_usd_cents"`
+	CustomerMonthlyCapUsdCents *int64                                 `json:"customer_monthly_cap_usd_cents,omitempty"`
+	Rules                      []GasSponsorshipRuleEvaluationResponse `json:"rules"`
+}
+
+// GasSponsorshipRuleEvaluationResponse explains whether one rule matched the simulated transaction
+type GasSponsorshipRuleEvaluationResponse struct {
+	RuleID           string   `json:"rule_id"`
+	Name             string   `json:"name"`
+	Priority         int32    `json:"priority"`
+	Matched          bool     `json:"matched"`
+	Fired            bool     `json:"fired"`
+	FailedConditions []string `json:"failed_conditions"`
+}
+
+// GasSponsorshipLedgerEntryResponse is one movement of the workspace's sponsorship budget
+type GasSponsorshipLedgerEntryResponse struct {
+	ID                    string  `json:"id"`
+	Object                string  `json:"object"`
+	ReservationID         string  `json:"reservation_id"`
+	CustomerID            *string `json:"customer_id,omitempty"`
+	PaymentID             *string `json:"payment_id,omitempty"`
+	EntryType             string  `json:"entry_type"`
+	AmountUsdCents        int64   `json:"amount_usd_cents"`
+	BudgetSpentCents      int64   `json:"budget_spent_cents"`
+	BudgetReservedCents   int64   `json:"budget_reserved_cents"`
+	MonthlyBudgetUsdCents *int64  `json:"monthly_budget_usd_cents,omitempty"`
+	Reason                string  `json:"reason,omitempty"`
+	CreatedAt             int64   `json:"created_at"`
 }
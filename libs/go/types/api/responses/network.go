@@ -18,6 +18,9 @@ type NetworkResponse struct {
 	CreatedAt         int64              `json:"created_at"`
 	UpdatedAt         int64              `json:"updated_at"`
 	GasConfig         *GasConfigResponse `json:"gas_config,omitempty"`
+	// Blocks a payment needs before it is confirmed, and after which it is final
+	ConfirmationBlocks int32 `json:"confirmation_blocks"`
+	FinalityBlocks     int32 `json:"finality_blocks"`
 }
 
 // GasConfigResponse represents gas configuration for a network
@@ -44,6 +44,19 @@ type PaymentResponse struct {
 	Metadata            json.RawMessage `json:"metadata,omitempty"`
 	CreatedAt           time.Time       `json:"created_at"`
 	UpdatedAt           time.Time       `json:"updated_at"`
+
+	// Confirmation is set for crypto payments whose transaction is tracked until it is final on-chain
+	Confirmation *PaymentConfirmationBasic `json:"confirmation,omitempty"`
+}
+
+// PaymentConfirmationBasic represents how far a crypto payment's transaction has progressed on-chain.
+// Status is pending, confirmed or finalized, or dropped, replaced or reverted when the payment was rolled back.
+type PaymentConfirmationBasic struct {
+	Status        string     `json:"status"`
+	Confirmations int32      `json:"confirmations"`
+	BlockNumber   *int64     `json:"block_number,omitempty"`
+	ConfirmedAt   *time.Time `json:"confirmed_at,omitempty"`
+	FinalizedAt   *time.Time `json:"finalized_at,omitempty"`
 }
 
 // CustomerBasic represents basic customer info for embedded responses
@@ -0,0 +1,57 @@
+package responses
+
+// PortfolioResponse is the value of the workspace's wallets in its default currency
+type PortfolioResponse struct {
+	Object          string                    `json:"object"`
+	FiatCurrency    string                    `json:"fiat_currency"`
+	TotalValueCents int64                     `json:"total_value_cents"`
+	AsOf            *int64                    `json:"as_of,omitempty"` // When the oldest balance was read
+	Stale           bool                      `json:"stale"`
+	Wallets         []PortfolioWalletResponse `json:"wallets"`
+}
+
+// PortfolioWalletResponse is one wallet's balances across the networks it is used on
+type PortfolioWalletResponse struct {
+	WalletID        string                  `json:"wallet_id"`
+	WalletAddress   string                  `json:"wallet_address"`
+	WalletType      string                  `json:"wallet_type"`
+	Nickname        string                  `json:"nickname,omitempty"`
+	TotalValueCents int64                   `json:"total_value_cents"`
+	Balances        []WalletBalanceResponse `json:"balances"`
+}
+
+// WalletBalanceResponse is a wallet's last known balance of a token
+type WalletBalanceResponse struct {
+	Object         string `json:"object"`
+	NetworkID      string `json:"network_id"`
+	NetworkName    string `json:"network_name"`
+	ChainID        int32  `json:"chain_id"`
+	TokenID        string `json:"token_id"`
+	TokenSymbol    string `json:"token_symbol"`
+	TokenName      string `json:"token_name"`
+	TokenAddress   string `json:"token_address,omitempty"`
+	Balance        string `json:"balance"` // Empty until the balance is first read
+	Source         string `json:"source"`  // circle or rpc
+	ExchangeRate   string `json:"exchange_rate,omitempty"`
+	FiatValueCents *int64 `json:"fiat_value_cents,omitempty"` // Omitted when the token has no exchange rate
+	FetchedAt      *int64 `json:"fetched_at"`                 // Null until the balance is first read
+	Error          string `json:"error,omitempty"`
+}
+
+// PortfolioSnapshotResponse is the value of the workspace's wallets at the start of a snapshot period
+type PortfolioSnapshotResponse struct {
+	Object          string                     `json:"object"`
+	SnapshotAt      int64                      `json:"snapshot_at"`
+	FiatCurrency    string                     `json:"fiat_currency"`
+	TotalValueCents int64                      `json:"total_value_cents"`
+	Holdings        []PortfolioHoldingResponse `json:"holdings"`
+}
+
+// PortfolioHoldingResponse is the workspace's total balance of a token on a network
+type PortfolioHoldingResponse struct {
+	NetworkID   string `json:"network_id"`
+	TokenID     string `json:"token_id"`
+	TokenSymbol string `json:"token_symbol"`
+	Balance     string `json:"balance"`
+	ValueCents  int64  `json:"value_cents"`
+}
@@ -0,0 +1,50 @@
+package responses
+
+import "encoding/json"
+
+// RedemptionTaskResponse represents a task in the redemption queue
+type RedemptionTaskResponse struct {
+	ID                  string          `json:"id"`
+	Object              string          `json:"object"`
+	SubscriptionID      string          `json:"subscription_id"`
+	DelegationID        string          `json:"delegation_id"`
+	ProductID           string          `json:"product_id"`
+	ProductTokenID      string          `json:"product_token_id"`
+	AmountInCents       int32           `json:"amount_in_cents"`
+	Metadata            json.RawMessage `json:"metadata,omitempty"`
+	IdempotencyKey      string          `json:"idempotency_key,omitempty"`
+	Priority            int32           `json:"priority"`
+	Status              string          `json:"status"`
+	Attempts            int32           `json:"attempts"`
+	MaxAttempts         int32           `json:"max_attempts"`
+	VisibleAt           int64           `json:"visible_at"`
+	LockedBy            string          `json:"locked_by,omitempty"`
+	RedemptionStartedAt *int64          `json:"redemption_started_at,omitempty"`
+	TransactionHash     string          `json:"transaction_hash,omitempty"`
+	LastError           string          `json:"last_error,omitempty"`
+	CompletedAt         *int64          `json:"completed_at,omitempty"`
+	DeadLetteredAt      *int64          `json:"dead_lettered_at,omitempty"`
+	CreatedAt           int64           `json:"created_at"`
+	UpdatedAt           int64           `json:"updated_at"`
+}
+
+// RedemptionQueueStatsResponse summarizes the redemption queue and the delegation server circuit breaker
+type RedemptionQueueStatsResponse struct {
+	Statuses       []RedemptionQueueStatusCountResponse `json:"statuses"`
+	CircuitBreaker CircuitBreakerResponse               `json:"circuit_breaker"`
+}
+
+// RedemptionQueueStatusCountResponse counts the queued tasks in one status
+type RedemptionQueueStatusCountResponse struct {
+	Status          string `json:"status"`
+	TaskCount       int64  `json:"task_count"`
+	ReadyCount      int64  `json:"ready_count"` // Pending tasks that can be claimed now
+	OldestCreatedAt *int64 `json:"oldest_created_at,omitempty"`
+}
+
+// CircuitBreakerResponse represents the shared state of a circuit breaker
+type CircuitBreakerResponse struct {
+	State               string `json:"state"` // closed, open or half_open
+	ConsecutiveFailures int32  `json:"consecutive_failures"`
+	StateChangedAt      *int64 `json:"state_changed_at,omitempty"`
+}
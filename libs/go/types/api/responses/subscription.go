@@ -18,12 +18,15 @@ type ProcessSubscriptionResult struct {
 
 // ProcessDueSubscriptionsResult represents the result of processing all due subscriptions
 type ProcessDueSubscriptionsResult struct {
-	ProcessedCount   int         `json:"processed_count"`
-	SuccessfulCount  int         `json:"successful_count"`
-	FailedCount      int         `json:"failed_count"`
-	ProcessedIDs     []uuid.UUID `json:"processed_ids"`
-	FailedIDs        []uuid.UUID `json:"failed_ids"`
-	ProcessingErrors []string    `json:"processing_errors,omitempty"`
+	ProcessedCount      int         `json:"processed_count"`
+	SuccessfulCount     int         `json:"successful_count"`
+	FailedCount         int         `json:"failed_count"`
+	DeferredCount       int         `json:"deferred_count"`        // Claimed but handed back for a later run
+	InterruptedCount    int         `json:"interrupted_count"`     // Parked for review in this run, counted as failed too
+	AwaitingReviewCount int64       `json:"awaiting_review_count"` // Parked for review after this run, from any run
+	ProcessedIDs        []uuid.UUID `json:"processed_ids"`
+	FailedIDs           []uuid.UUID `json:"failed_ids"`
+	ProcessingErrors    []string    `json:"processing_errors,omitempty"`
 }
 
 // SubscriptionCustomerResponse represents the customer data within a subscription response
@@ -0,0 +1,19 @@
+package responses
+
+// SubscriptionRenewalResponse represents the renewal of one subscription billing period
+type SubscriptionRenewalResponse struct {
+	ID              string `json:"id"`
+	Object          string `json:"object"`
+	SubscriptionID  string `json:"subscription_id"`
+	IdempotencyKey  string `json:"idempotency_key"`
+	PeriodDueAt     int64  `json:"period_due_at"`
+	Status          string `json:"status"`
+	Attempts        int32  `json:"attempts"`
+	LeaseOwner      string `json:"lease_owner,omitempty"`
+	LeaseExpiresAt  *int64 `json:"lease_expires_at,omitempty"`
+	TransactionHash string `json:"transaction_hash,omitempty"`
+	ErrorMessage    string `json:"error_message,omitempty"`
+	CompletedAt     *int64 `json:"completed_at,omitempty"`
+	CreatedAt       int64  `json:"created_at"`
+	UpdatedAt       int64  `json:"updated_at"`
+}
@@ -8,13 +8,65 @@ import (
 
 // TaxCalculationResult contains the calculated tax information
 type TaxCalculationResult struct {
-	SubtotalCents        int64                  `json:"subtotal_cents"`
-	TotalTaxCents        int64                  `json:"total_tax_cents"`
-	TotalAmountCents     int64                  `json:"total_amount_cents"`
-	TaxBreakdown         []business.TaxLineItem `json:"tax_breakdown"`
-	AppliedJurisdictions []string               `json:"applied_jurisdictions"`
-	TaxExemptReason      *string                `json:"tax_exempt_reason,omitempty"`
-	CalculatedAt         time.Time              `json:"calculated_at"`
-	Confidence           float64                `json:"confidence"` // 0.0 to 1.0
-	AuditTrail           business.TaxAuditTrail `json:"audit_trail"`
+	SubtotalCents         int64                  `json:"subtotal_cents"`
+	TotalTaxCents         int64                  `json:"total_tax_cents"`
+	TotalAmountCents      int64                  `json:"total_amount_cents"`
+	TaxBreakdown          []business.TaxLineItem `json:"tax_breakdown"`
+	AppliedJurisdictions  []string               `json:"applied_jurisdictions"`
+	TaxExemptReason       *string                `json:"tax_exempt_reason,omitempty"`
+	CalculatedAt          time.Time              `json:"calculated_at"`
+	Confidence            float64                `json:"confidence"` // 0.0 to 1.0
+	AuditTrail            business.TaxAuditTrail `json:"audit_trail"`
+	Provider              string                 `json:"provider"` // "internal" or the external provider's name
+	ProviderTransactionID string                 `json:"provider_transaction_id,omitempty"`
+}
+
+// TaxJurisdictionResponse represents a tax jurisdiction
+type TaxJurisdictionResponse struct {
+	ID               string            `json:"id"`
+	Object           string            `json:"object"`
+	Code             string            `json:"code"`
+	Name             string            `json:"name"`
+	JurisdictionType string            `json:"jurisdiction_type"`
+	CountryCode      string            `json:"country_code"`
+	SubdivisionCode  string            `json:"subdivision_code,omitempty"`
+	Locality         string            `json:"locality,omitempty"`
+	ParentID         string            `json:"parent_id,omitempty"`
+	TaxType          string            `json:"tax_type"`
+	IsActive         bool              `json:"is_active"`
+	Rates            []TaxRateResponse `json:"rates,omitempty"`
+	CreatedAt        int64             `json:"created_at"`
+	UpdatedAt        int64             `json:"updated_at"`
+}
+
+// TaxRateResponse represents a versioned tax rate
+type TaxRateResponse struct {
+	ID             string  `json:"id"`
+	Object         string  `json:"object"`
+	JurisdictionID string  `json:"jurisdiction_id"`
+	ProductType    string  `json:"product_type"`
+	Rate           float64 `json:"rate"`
+	ThresholdCents int64   `json:"threshold_cents"`
+	EffectiveFrom  int64   `json:"effective_from"`
+	EffectiveTo    *int64  `json:"effective_to,omitempty"`
+	RulesVersion   string  `json:"rules_version"`
+	Description    string  `json:"description,omitempty"`
+	CreatedAt      int64   `json:"created_at"`
+}
+
+// TaxIDVerificationResponse represents the outcome of checking a tax ID with an official registry
+type TaxIDVerificationResponse struct {
+	ID                 string `json:"id"`
+	Object             string `json:"object"`
+	CustomerID         string `json:"customer_id,omitempty"`
+	CountryCode        string `json:"country_code"`
+	TaxID              string `json:"tax_id"`
+	IsValid            bool   `json:"is_valid"`
+	RegisteredName     string `json:"registered_name,omitempty"`
+	RegisteredAddress  string `json:"registered_address,omitempty"`
+	ConsultationNumber string `json:"consultation_number,omitempty"`
+	Source             string `json:"source"`
+	CheckedAt          int64  `json:"checked_at"`
+	ExpiresAt          int64  `json:"expires_at"`
+	CreatedAt          int64  `json:"created_at"`
 }
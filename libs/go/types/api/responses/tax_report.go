@@ -0,0 +1,99 @@
+package responses
+
+import "time"
+
+// Tax reports follow the invoice lifecycle: tax is reported in the period an invoice is issued,
+// reversed in the period it is voided and reduced in the period a payment of it is refunded.
+// Amounts are in the smallest unit of the invoice currency; currencies are never mixed.
+
+// TaxSummaryReport aggregates tax by jurisdiction and rate for a period
+type TaxSummaryReport struct {
+	WorkspaceID string           `json:"workspace_id"`
+	PeriodStart time.Time        `json:"period_start"`
+	PeriodEnd   time.Time        `json:"period_end"`
+	Lines       []TaxSummaryLine `json:"lines"`
+}
+
+// TaxSummaryLine is the tax of one jurisdiction, rate and currency
+type TaxSummaryLine struct {
+	Jurisdiction       string  `json:"jurisdiction"`
+	TaxType            string  `json:"tax_type"`
+	Rate               float64 `json:"rate"`
+	ReverseCharge      bool    `json:"reverse_charge"`
+	Currency           string  `json:"currency"`
+	InvoiceCount       int     `json:"invoice_count"`
+	TaxableAmountCents int64   `json:"taxable_amount_cents"` // Net of voids and refunds
+	TaxInvoicedCents   int64   `json:"tax_invoiced_cents"`   // On invoices issued in the period
+	TaxVoidedCents     int64   `json:"tax_voided_cents"`     // On invoices voided in the period
+	TaxRefundedCents   int64   `json:"tax_refunded_cents"`   // On payments refunded in the period
+	NetTaxCents        int64   `json:"net_tax_cents"`        // Invoiced less voided and refunded
+	TaxCollectedCents  int64   `json:"tax_collected_cents"`  // On invoices paid in the period
+}
+
+// OSSReport is an EU One-Stop-Shop VAT summary for a calendar quarter
+type OSSReport struct {
+	WorkspaceID string              `json:"workspace_id"`
+	Period      string              `json:"period"` // e.g. "2025-Q1"
+	PeriodStart time.Time           `json:"period_start"`
+	PeriodEnd   time.Time           `json:"period_end"`
+	Lines       []OSSReportLine     `json:"lines"`
+	Corrections []OSSCorrectionLine `json:"corrections"`
+}
+
+// OSSReportLine is the VAT due to one member state of consumption at one rate
+type OSSReportLine struct {
+	CountryCode        string  `json:"country_code"`
+	Rate               float64 `json:"rate"`
+	Currency           string  `json:"currency"`
+	TaxableAmountCents int64   `json:"taxable_amount_cents"`
+	VATAmountCents     int64   `json:"vat_amount_cents"`
+}
+
+// OSSCorrectionLine corrects the VAT declared to a member state in an earlier quarter, for
+// invoices voided or refunded in this quarter
+type OSSCorrectionLine struct {
+	CorrectedPeriod string `json:"corrected_period"`
+	CountryCode     string `json:"country_code"`
+	Currency        string `json:"currency"`
+	VATAmountCents  int64  `json:"vat_amount_cents"`
+}
+
+// ReverseChargeReport lists B2B supplies on which the customer accounts for VAT
+type ReverseChargeReport struct {
+	WorkspaceID string              `json:"workspace_id"`
+	PeriodStart time.Time           `json:"period_start"`
+	PeriodEnd   time.Time           `json:"period_end"`
+	Lines       []ReverseChargeLine `json:"lines"`
+}
+
+// ReverseChargeLine is the total supplied to one customer VAT number
+type ReverseChargeLine struct {
+	CountryCode        string `json:"country_code"`
+	VATNumber          string `json:"vat_number"`
+	CustomerName       string `json:"customer_name"`
+	Currency           string `json:"currency"`
+	InvoiceCount       int    `json:"invoice_count"`
+	AmountCents        int64  `json:"amount_cents"` // Net of voids and refunds
+	ConsultationNumber string `json:"consultation_number,omitempty"`
+}
+
+// USStateTaxReport summarizes US sales tax by state
+type USStateTaxReport struct {
+	WorkspaceID string           `json:"workspace_id"`
+	PeriodStart time.Time        `json:"period_start"`
+	PeriodEnd   time.Time        `json:"period_end"`
+	Lines       []USStateTaxLine `json:"lines"`
+}
+
+// USStateTaxLine is the sales tax of one state, including its local jurisdictions
+type USStateTaxLine struct {
+	State             string `json:"state"`
+	Currency          string `json:"currency"`
+	TransactionCount  int    `json:"transaction_count"`
+	GrossSalesCents   int64  `json:"gross_sales_cents"`
+	TaxableSalesCents int64  `json:"taxable_sales_cents"`
+	StateTaxCents     int64  `json:"state_tax_cents"`
+	LocalTaxCents     int64  `json:"local_tax_cents"`
+	NetTaxCents       int64  `json:"net_tax_cents"`
+	TaxCollectedCents int64  `json:"tax_collected_cents"`
+}
@@ -0,0 +1,45 @@
+package responses
+
+// TreasurySweepRuleResponse represents a treasury sweep rule
+type TreasurySweepRuleResponse struct {
+	ID                   string `json:"id"`
+	Object               string `json:"object"`
+	Name                 string `json:"name"`
+	SourceWalletID       string `json:"source_wallet_id"`
+	DestinationWalletID  string `json:"destination_wallet_id"`
+	TokenID              string `json:"token_id"`
+	ThresholdAmount      string `json:"threshold_amount"`
+	RetainedAmount       string `json:"retained_amount"`
+	FeeLevel             string `json:"fee_level"`
+	SweepIntervalMinutes int32  `json:"sweep_interval_minutes"`
+	IsActive             bool   `json:"is_active"`
+	LastRunAt            *int64 `json:"last_run_at,omitempty"`
+	LastRunResult        string `json:"last_run_result,omitempty"`
+	CreatedAt            int64  `json:"created_at"`
+	UpdatedAt            int64  `json:"updated_at"`
+}
+
+// TreasuryMovementResponse is one sweep in the workspace's treasury ledger
+type TreasuryMovementResponse struct {
+	ID                  string `json:"id"`
+	Object              string `json:"object"`
+	RuleID              string `json:"rule_id"`
+	NetworkID           string `json:"network_id"`
+	TokenID             string `json:"token_id"`
+	SourceWalletID      string `json:"source_wallet_id"`
+	DestinationWalletID string `json:"destination_wallet_id"`
+	Amount              string `json:"amount"`
+	SourceBalance       string `json:"source_balance"`
+	EstimatedFee        string `json:"estimated_fee,omitempty"`
+	Status              string `json:"status"`
+	ChallengeID         string `json:"challenge_id,omitempty"` // Approve with the merchant's PIN to send the transfer
+	CircleTransactionID string `json:"circle_transaction_id,omitempty"`
+	CircleState         string `json:"circle_state,omitempty"`
+	TransactionHash     string `json:"transaction_hash,omitempty"`
+	NetworkFee          string `json:"network_fee,omitempty"`
+	ErrorMessage        string `json:"error_message,omitempty"`
+	ReconciledAt        *int64 `json:"reconciled_at,omitempty"`
+	CompletedAt         *int64 `json:"completed_at,omitempty"`
+	CreatedAt           int64  `json:"created_at"`
+	UpdatedAt           int64  `json:"updated_at"`
+}
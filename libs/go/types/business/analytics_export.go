@@ -0,0 +1,49 @@
+package business
+
+// Analytics export datasets
+const (
+	ExportDatasetPayments      = "payments"
+	ExportDatasetInvoices      = "invoices"
+	ExportDatasetSubscriptions = "subscriptions"
+	ExportDatasetMRRMovements  = "mrr_movements"
+	ExportDatasetGasFees       = "gas_fees"
+)
+
+// ExportDatasets lists the datasets an analytics export can include
+var ExportDatasets = []string{
+	ExportDatasetPayments,
+	ExportDatasetInvoices,
+	ExportDatasetSubscriptions,
+	ExportDatasetMRRMovements,
+	ExportDatasetGasFees,
+}
+
+// Analytics export file formats
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatParquet = "parquet"
+)
+
+// Analytics export job statuses
+const (
+	ExportJobStatusPending   = "pending"
+	ExportJobStatusRunning   = "running"
+	ExportJobStatusCompleted = "completed"
+	ExportJobStatusFailed    = "failed"
+)
+
+// Report schedule frequencies
+const (
+	ReportFrequencyWeekly  = "weekly"
+	ReportFrequencyMonthly = "monthly"
+)
+
+// ExportFile describes one dataset file written by an analytics export job
+type ExportFile struct {
+	Dataset     string `json:"dataset"`
+	StorageKey  string `json:"storage_key"`
+	ContentType string `json:"content_type"`
+	Filename    string `json:"filename"`
+	RowCount    int64  `json:"row_count"`
+	SizeBytes   int64  `json:"size_bytes"`
+}
@@ -0,0 +1,42 @@
+package business
+
+import (
+	"math/big"
+)
+
+// FeeEstimate is what a transaction would cost on a network, in its native token's smallest unit
+type FeeEstimate struct {
+	Units          uint64   // Gas units on EVM networks; signatures on Solana
+	PricePerUnit   *big.Int // Expected price of one unit
+	Total          *big.Int
+	NativeSymbol   string
+	NativeDecimals int
+}
+
+// LogFilter selects contract logs to stream from a network
+type LogFilter struct {
+	Addresses []string
+	// Topics restricts each topic position to one of the listed values; an empty position matches anything
+	Topics [][]string
+	// FromBlock is the first block to read; zero starts at the latest block
+	FromBlock uint64
+}
+
+// ChainLog is a log a contract emitted in a mined transaction
+type ChainLog struct {
+	Address         string
+	Topics          []string
+	Data            []byte
+	BlockNumber     uint64
+	BlockHash       string
+	TransactionHash string
+	LogIndex        uint
+}
+
+// RPCEndpointStatus is the health of one RPC endpoint a network is reached through
+type RPCEndpointStatus struct {
+	Provider            string
+	Healthy             bool
+	ConsecutiveFailures int
+	LastError           string
+}
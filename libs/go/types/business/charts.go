@@ -13,6 +13,13 @@ type ChartData struct {
 	Title     string           `json:"title"`
 	Data      []ChartDataPoint `json:"data"`
 	Period    string           `json:"period"`
+	Series    []ChartSeries    `json:"series,omitempty"`
+}
+
+// ChartSeries represents an additional named series drawn alongside a chart's main data
+type ChartSeries struct {
+	Name string           `json:"name"`
+	Data []ChartDataPoint `json:"data"`
 }
 
 // PieChartData represents data for pie charts
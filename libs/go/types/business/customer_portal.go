@@ -0,0 +1,47 @@
+package business
+
+import "github.com/cyphera/cyphera-api/libs/go/db"
+
+// Customer portal actions a merchant can enable or disable
+const (
+	PortalActionCancel        = "cancel"
+	PortalActionPause         = "pause"
+	PortalActionResume        = "resume"
+	PortalActionUpdateBilling = "update_billing"
+	PortalActionSwitchWallet  = "switch_wallet"
+)
+
+// CustomerPortalPermissions lists the portal actions a merchant allows its customers to take
+type CustomerPortalPermissions struct {
+	Cancel        bool `json:"cancel"`
+	Pause         bool `json:"pause"`
+	Resume        bool `json:"resume"`
+	UpdateBilling bool `json:"update_billing"`
+	SwitchWallet  bool `json:"switch_wallet"`
+}
+
+// Allows reports whether the given portal action is enabled
+func (p CustomerPortalPermissions) Allows(action string) bool {
+	switch action {
+	case PortalActionCancel:
+		return p.Cancel
+	case PortalActionPause:
+		return p.Pause
+	case PortalActionResume:
+		return p.Resume
+	case PortalActionUpdateBilling:
+		return p.UpdateBilling
+	case PortalActionSwitchWallet:
+		return p.SwitchWallet
+	default:
+		return false
+	}
+}
+
+// CustomerPortalSubscription is a subscription together with the actions its merchant allows in the portal
+type CustomerPortalSubscription struct {
+	Subscription db.ListCustomerPortalSubscriptionsRow
+	Permissions  CustomerPortalPermissions
+	// Reauthorization is set when the customer must sign a new delegation for the subscription to renew
+	Reauthorization *db.SubscriptionReauthorization
+}
@@ -115,3 +115,32 @@ type NetworkBreakdown struct {
 	Networks map[string]NetworkMetrics `json:"networks"`
 	Tokens   map[string]TokenMetrics   `json:"tokens"`
 }
+
+// Cohort dimensions
+const (
+	CohortByMonth   = "month"
+	CohortByProduct = "product"
+	CohortByNetwork = "network"
+)
+
+// CohortAnalysis represents logo and net revenue retention matrices. Each cohort is one row and
+// each column is a month since the cohort's customers first subscribed.
+type CohortAnalysis struct {
+	CohortBy       string      `json:"cohort_by"`
+	LookbackMonths int         `json:"lookback_months"`
+	Currency       string      `json:"currency"`
+	Cohorts        []CohortRow `json:"cohorts"`
+	CalculatedAt   time.Time   `json:"calculated_at"`
+}
+
+// CohortRow represents one cohort's row of the retention matrices
+type CohortRow struct {
+	Key                 string      `json:"key"`
+	Label               string      `json:"label"`
+	Customers           int64       `json:"customers"`
+	StartingRevenue     MoneyAmount `json:"starting_revenue"`
+	ActiveCustomers     []int64     `json:"active_customers"`
+	RevenueCents        []int64     `json:"revenue_cents"`
+	LogoRetention       []float64   `json:"logo_retention"`
+	NetRevenueRetention []float64   `json:"net_revenue_retention"`
+}
@@ -26,3 +26,10 @@ type CaveatStruct struct {
 	Enforcer string `json:"enforcer"` // Address of the caveat enforcer contract
 	Terms    string `json:"terms"`    // Encoded parameters defining the specific restrictions (hex string)
 }
+
+// DelegationCheckResult summarizes a run of the delegation monitor
+type DelegationCheckResult struct {
+	Checked              int
+	NeedsReauthorization int
+	Failed               int
+}
@@ -1,5 +1,12 @@
 package business
 
+import (
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+)
+
 // GasFeeMetrics represents aggregated gas fee metrics
 type GasFeeMetrics struct {
 	TotalTransactions   int64                      `json:"total_transactions"`
@@ -15,3 +22,36 @@ type NetworkGasStats struct {
 	CostCents    int64 `json:"cost_cents"`
 	AvgCostCents int64 `json:"avg_cost_cents"`
 }
+
+// Gas fee estimate sources
+const (
+	GasFeeEstimateSourceFeeHistory = "fee_history"
+	GasFeeEstimateSourceStatic     = "static"
+)
+
+// GasFeeQuote is a network's current gas pricing, derived from recent blocks' fee history
+type GasFeeQuote struct {
+	NetworkID         uuid.UUID
+	BlockNumber       uint64
+	EIP1559           bool
+	BaseFeeWei        *big.Int // Next block's base fee; nil on networks without EIP-1559
+	PriorityFeeWei    *big.Int // Median tip paid in recent blocks
+	MaxFeePerGasWei   *big.Int // Fee cap that survives several full blocks of base fee growth
+	GasPriceWei       *big.Int // Expected effective gas price
+	BaseFeeVolatility float64  // Highest over lowest base fee in the sampled blocks, minus one
+	FetchedAt         time.Time
+}
+
+// GasFeeEstimateAccuracy compares gas fee estimates with the actual fees paid on one network
+type GasFeeEstimateAccuracy struct {
+	NetworkID          uuid.UUID `json:"network_id"`
+	NetworkName        string    `json:"network_name"`
+	EstimateSource     string    `json:"estimate_source"`
+	SampleCount        int64     `json:"sample_count"`
+	AvgCostRatio       float64   `json:"avg_cost_ratio"`       // Estimated over actual cost; above 1 overestimates
+	MeanAbsErrorRatio  float64   `json:"mean_abs_error_ratio"` // Mean absolute error relative to the actual cost
+	AvgGasUnitsRatio   float64   `json:"avg_gas_units_ratio"`  // Estimated over used gas units
+	UnderestimatedRate float64   `json:"underestimated_rate"`  // Share of estimates below the actual cost
+	EstimatedUSDCents  int64     `json:"estimated_usd_cents"`
+	ActualUSDCents     int64     `json:"actual_usd_cents"`
+}
@@ -0,0 +1,78 @@
+package business
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MRR movement types
+const (
+	MRRMovementNew          = "new"
+	MRRMovementExpansion    = "expansion"
+	MRRMovementContraction  = "contraction"
+	MRRMovementChurn        = "churn"
+	MRRMovementReactivation = "reactivation"
+)
+
+// MRRMovementTypes lists the movement types in reporting order
+var MRRMovementTypes = []string{
+	MRRMovementNew,
+	MRRMovementExpansion,
+	MRRMovementContraction,
+	MRRMovementChurn,
+	MRRMovementReactivation,
+}
+
+// MRR movement sources, the subscription changes that move MRR
+const (
+	MRRSourceCreate    = "create"
+	MRRSourceUpgrade   = "upgrade"
+	MRRSourceDowngrade = "downgrade"
+	MRRSourceCancel    = "cancel"
+	MRRSourcePause     = "pause"
+	MRRSourceResume    = "resume"
+)
+
+// MRRMovement represents one subscription change in the MRR movements ledger. Amounts are
+// monthly values; ReportingMRRDelta is nil until the movement's exchange rate is known.
+type MRRMovement struct {
+	ID                uuid.UUID    `json:"id"`
+	SubscriptionID    uuid.UUID    `json:"subscription_id"`
+	CustomerID        uuid.UUID    `json:"customer_id"`
+	CustomerName      string       `json:"customer_name,omitempty"`
+	CustomerEmail     string       `json:"customer_email,omitempty"`
+	ProductName       string       `json:"product_name"`
+	MovementType      string       `json:"movement_type"`
+	Source            string       `json:"source"`
+	IntervalType      string       `json:"interval_type,omitempty"`
+	FromMRR           MoneyAmount  `json:"from_mrr"`
+	ToMRR             MoneyAmount  `json:"to_mrr"`
+	MRRDelta          MoneyAmount  `json:"mrr_delta"`
+	ExchangeRate      *float64     `json:"exchange_rate,omitempty"`
+	ReportingMRRDelta *MoneyAmount `json:"reporting_mrr_delta,omitempty"`
+	OccurredAt        time.Time    `json:"occurred_at"`
+}
+
+// MRRMovementTotal represents the net MRR change of one movement type
+type MRRMovementTotal struct {
+	MovementType string      `json:"movement_type"`
+	Count        int64       `json:"count"`
+	PendingCount int64       `json:"pending_count"`
+	MRRDelta     MoneyAmount `json:"mrr_delta"`
+}
+
+// MRRMovementReport represents the MRR movements of a period with totals that add up to the net
+// new MRR, and a page of the movements behind them
+type MRRMovementReport struct {
+	StartDate    time.Time          `json:"start_date"`
+	EndDate      time.Time          `json:"end_date"`
+	Currency     string             `json:"currency"`
+	Totals       []MRRMovementTotal `json:"totals"`
+	NetNewMRR    MoneyAmount        `json:"net_new_mrr"`
+	PendingCount int64              `json:"pending_count"`
+	Movements    []MRRMovement      `json:"movements"`
+	TotalItems   int64              `json:"total_items"`
+	Limit        int32              `json:"limit"`
+	Offset       int32              `json:"offset"`
+}
@@ -0,0 +1,8 @@
+package business
+
+// WalletNameRefreshResult counts the outcome of a customer wallet name refresh run
+type WalletNameRefreshResult struct {
+	Checked int // Wallets looked up
+	Changed int // Wallets whose name was set, changed or cleared
+	Errors  int // Wallets whose name could not be looked up
+}
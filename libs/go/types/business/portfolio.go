@@ -0,0 +1,78 @@
+package business
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Where a wallet balance was read from
+const (
+	WalletBalanceSourceCircle = "circle"
+	WalletBalanceSourceRPC    = "rpc"
+)
+
+// WalletBalance is a wallet's last known balance of a token on a network. Balance is a decimal string in
+// whole token units; the fiat value is in the workspace's default currency and is nil when the token has
+// no exchange rate.
+type WalletBalance struct {
+	NetworkID      uuid.UUID
+	NetworkName    string
+	ChainID        int32
+	TokenID        uuid.UUID
+	TokenSymbol    string
+	TokenName      string
+	TokenAddress   string
+	Balance        string
+	Source         string
+	ExchangeRate   string
+	FiatValueCents *int64
+	FetchedAt      *time.Time // Nil, with an empty Balance, until the balance is first read
+	Error          string     // Why the latest refresh could not read the balance
+}
+
+// WalletHoldings is one wallet's balances across the networks it is used on
+type WalletHoldings struct {
+	WalletID        uuid.UUID
+	WalletAddress   string
+	WalletType      string
+	Nickname        string
+	TotalValueCents int64
+	Balances        []WalletBalance
+}
+
+// Portfolio is the value of a workspace's wallets in its default currency
+type Portfolio struct {
+	WorkspaceID     uuid.UUID
+	FiatCurrency    string
+	TotalValueCents int64
+	Wallets         []WalletHoldings
+	AsOf            *time.Time // When the oldest balance was read
+	Stale           bool       // A balance is older than the freshness window or failed to refresh
+}
+
+// PortfolioHolding is a workspace's total balance of a token on a network within a snapshot
+type PortfolioHolding struct {
+	NetworkID   uuid.UUID `json:"network_id"`
+	TokenID     uuid.UUID `json:"token_id"`
+	TokenSymbol string    `json:"token_symbol"`
+	Balance     string    `json:"balance"`
+	ValueCents  int64     `json:"value_cents"`
+}
+
+// PortfolioSnapshot is the value of a workspace's wallets at the start of a snapshot period
+type PortfolioSnapshot struct {
+	SnapshotAt      time.Time
+	FiatCurrency    string
+	TotalValueCents int64
+	Holdings        []PortfolioHolding
+}
+
+// PortfolioSnapshotResult counts the outcome of a snapshot run
+type PortfolioSnapshotResult struct {
+	Checked   int // Workspaces due a snapshot
+	Snapshots int
+	Balances  int // Balances refreshed
+	Errors    int // Balances that could not be read
+	Failed    int // Workspaces that could not be snapshotted
+}
@@ -1,6 +1,10 @@
 package business
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // RedemptionTask represents a task to be processed by the redemption processor
 type RedemptionTask struct {
@@ -10,4 +14,24 @@ type RedemptionTask struct {
 	ProductTokenID uuid.UUID              `json:"product_token_id"`
 	AmountInCents  int32                  `json:"amount_in_cents"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	// IdempotencyKey, when set, stops the same redemption from being queued twice
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// Priority orders the queue; higher priorities are processed first
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// RedemptionQueueStatusCount summarizes the queued redemption tasks in one status
+type RedemptionQueueStatusCount struct {
+	Status     string
+	TaskCount  int64
+	ReadyCount int64
+	OldestAt   *time.Time
+}
+
+// RedemptionQueueStats summarizes the redemption queue and the circuit breaker in front of the delegation server
+type RedemptionQueueStats struct {
+	Statuses            []RedemptionQueueStatusCount
+	CircuitState        string
+	ConsecutiveFailures int32
+	CircuitChangedAt    *time.Time
 }
@@ -13,13 +13,20 @@ type SponsorshipDecision struct {
 	SponsorID       uuid.UUID // ID of the sponsoring entity
 	Reason          string    // Human-readable reason for the decision
 	RemainingBudget int64     // Remaining monthly budget in cents
+
+	SponsoredAmountCents int64     // Portion of the gas cost paid by the sponsor; the customer pays the rest
+	SponsorPercentage    int32     // Share of the actual gas cost sponsored at settlement
+	RuleID               uuid.UUID // Rule that decided, uuid.Nil when the workspace has no rules
+	RuleName             string
+	ReservationID        uuid.UUID // Budget hold backing the decision, set by ReserveSponsorship
 }
 
 // SponsorshipRecord contains details of a sponsored transaction
 type SponsorshipRecord struct {
 	WorkspaceID     uuid.UUID
+	CustomerID      uuid.UUID // Counts towards the customer's monthly cap when set
 	PaymentID       uuid.UUID
-	GasCostUSDCents int64
+	GasCostUSDCents int64 // Sponsored amount
 	SponsorType     string
 	SponsorID       uuid.UUID
 }
@@ -29,20 +36,23 @@ type BudgetStatus struct {
 	WorkspaceID            uuid.UUID
 	MonthlyBudgetCents     int64
 	CurrentMonthSpentCents int64
+	ReservedCents          int64 // Held for sponsored transactions that have not settled yet
 	RemainingBudgetCents   int64
 	LastResetDate          time.Time
 	SponsorshipEnabled     bool
+	AlertedThresholds      []int32 // Budget percentages alerted on this month
 }
 
 // SponsorshipConfigUpdates contains fields that can be updated in sponsorship config
 type SponsorshipConfigUpdates struct {
-	SponsorshipEnabled       *bool
-	SponsorCustomerGas       *bool
-	MonthlyBudgetUSDCents    *int64
-	SponsorThresholdUSDCents *int64
-	SponsorForProducts       *[]uuid.UUID
-	SponsorForCustomers      *[]uuid.UUID
-	SponsorForTiers          *[]string
+	SponsorshipEnabled            *bool
+	SponsorCustomerGas            *bool
+	MonthlyBudgetUSDCents         *int64
+	SponsorThresholdUSDCents      *int64
+	PerCustomerMonthlyCapUSDCents *int64
+	SponsorForProducts            *[]uuid.UUID
+	SponsorForCustomers           *[]uuid.UUID
+	SponsorForTiers               *[]string
 }
 
 // SponsorshipAnalytics contains analytics data for gas sponsorship
@@ -54,3 +64,105 @@ type SponsorshipAnalytics struct {
 	SavingsPercentage     float64 `json:"savings_percentage"`
 	Period                int     `json:"period_days"`
 }
+
+// SponsorshipAction is what a matching sponsorship rule does with the gas cost
+type SponsorshipAction string
+
+const (
+	SponsorshipActionFull            SponsorshipAction = "sponsor_full"     // Sponsor the whole gas cost
+	SponsorshipActionUpTo            SponsorshipAction = "sponsor_up_to"    // Sponsor up to a fixed amount per transaction
+	SponsorshipActionSplitPercentage SponsorshipAction = "split_percentage" // Sponsor a percentage of the gas cost
+)
+
+// SponsorshipRule is one entry of a workspace's ordered sponsorship policy
+type SponsorshipRule struct {
+	ID                  uuid.UUID
+	WorkspaceID         uuid.UUID
+	Name                string
+	Priority            int32
+	IsActive            bool
+	Conditions          SponsorshipRuleConditions
+	Action              SponsorshipAction
+	SponsorUpToUSDCents int64 // For SponsorshipActionUpTo
+	SponsorPercentage   int32 // For SponsorshipActionSplitPercentage
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// SponsorshipRuleConditions must all match for a rule to fire. Empty conditions match everything.
+type SponsorshipRuleConditions struct {
+	NetworkIDs       []uuid.UUID            `json:"network_ids,omitempty"`
+	TokenIDs         []uuid.UUID            `json:"token_ids,omitempty"`
+	ProductIDs       []uuid.UUID            `json:"product_ids,omitempty"`
+	CustomerTiers    []string               `json:"customer_tiers,omitempty"`
+	TransactionTypes []string               `json:"transaction_types,omitempty"`
+	MaxGasPriceGwei  *float64               `json:"max_gas_price_gwei,omitempty"`
+	TimeWindow       *SponsorshipTimeWindow `json:"time_window,omitempty"`
+}
+
+// SponsorshipTimeWindow limits a rule to a date range and/or recurring hours of the week
+type SponsorshipTimeWindow struct {
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`    // Exclusive
+	Weekdays  []string   `json:"weekdays,omitempty"`   // "monday" to "sunday"
+	StartTime string     `json:"start_time,omitempty"` // "15:04", inclusive
+	EndTime   string     `json:"end_time,omitempty"`   // "15:04", exclusive; before StartTime wraps past midnight
+	Timezone  string     `json:"timezone,omitempty"`   // IANA name, defaults to UTC
+}
+
+// SponsorshipRuleEvaluation explains whether one rule matched a transaction
+type SponsorshipRuleEvaluation struct {
+	RuleID           uuid.UUID
+	RuleName         string
+	Priority         int32
+	Matched          bool
+	Fired            bool     // First matching rule, which decides
+	FailedConditions []string // Conditions that did not match
+}
+
+// SponsorshipSimulation is a sponsorship decision together with how it was reached
+type SponsorshipSimulation struct {
+	Decision                *SponsorshipDecision
+	Evaluations             []SponsorshipRuleEvaluation
+	CustomerMonthSpentCents int64
+	CustomerMonthlyCapCents *int64
+}
+
+// Sponsorship reservation statuses
+const (
+	SponsorshipReservationReserved = "reserved"
+	SponsorshipReservationSettled  = "settled"
+	SponsorshipReservationReleased = "released"
+)
+
+// SponsorshipReservation is budget held for a sponsored transaction from the sponsorship decision
+// until the transaction settles with its actual gas cost or is released
+type SponsorshipReservation struct {
+	ID                uuid.UUID
+	WorkspaceID       uuid.UUID
+	CustomerID        uuid.UUID
+	PaymentID         uuid.UUID
+	RuleID            uuid.UUID
+	Status            string
+	ReservedCents     int64
+	SettledCents      int64
+	SponsorPercentage int32
+	ReleaseReason     string
+	ExpiresAt         time.Time
+	CreatedAt         time.Time
+}
+
+// SponsorshipLedgerEntry is one movement of a workspace's sponsorship budget
+type SponsorshipLedgerEntry struct {
+	ID                  uuid.UUID
+	ReservationID       uuid.UUID
+	CustomerID          uuid.UUID
+	PaymentID           uuid.UUID
+	EntryType           string // "reserve", "settle" or "release"
+	AmountCents         int64
+	BudgetSpentCents    int64 // Workspace budget after the entry
+	BudgetReservedCents int64
+	MonthlyBudgetCents  *int64
+	Reason              string
+	CreatedAt           time.Time
+}
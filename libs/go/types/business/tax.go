@@ -34,6 +34,8 @@ type TaxAuditTrail struct {
 	AppliedRules     []string      `json:"applied_rules"`
 	Overrides        []TaxOverride `json:"overrides,omitempty"`
 	Notes            []string      `json:"notes,omitempty"`
+	// TaxIDVerification is the registry check a reverse charge decision relied on
+	TaxIDVerification *TaxIDVerificationEvidence `json:"tax_id_verification,omitempty"`
 }
 
 // TaxOverride represents a manual tax override
@@ -45,13 +47,34 @@ type TaxOverride struct {
 	AppliedAt   time.Time `json:"applied_at"`
 }
 
-// TaxJurisdiction represents a tax jurisdiction with its rules
+// TaxJurisdiction represents a tax jurisdiction with the rates in force at a point in time
 type TaxJurisdiction struct {
+	ID            uuid.UUID          `json:"id"`
 	Code          string             `json:"code"`
 	Name          string             `json:"name"`
-	Type          string             `json:"type"`       // "country", "state", "province", "city"
-	TaxRates      map[string]float64 `json:"tax_rates"`  // product_type -> rate
-	Thresholds    map[string]int64   `json:"thresholds"` // minimum amounts
+	Type          string             `json:"type"`                     // "country", "state", "province", "county", "city"
+	TaxType       string             `json:"tax_type"`                 // "sales", "vat", "gst", "tax"
+	TaxRates      map[string]float64 `json:"tax_rates"`                // product_type -> rate
+	Thresholds    map[string]int64   `json:"thresholds"`               // minimum amounts
+	RulesVersions map[string]string  `json:"rules_versions,omitempty"` // product_type -> rules version of the rate
 	IsActive      bool               `json:"is_active"`
 	EffectiveDate time.Time          `json:"effective_date"`
 }
+
+// TaxIDCheckResult is an official registry's answer about a tax ID
+type TaxIDCheckResult struct {
+	CountryCode        string    `json:"country_code"`
+	TaxID              string    `json:"tax_id"`
+	Valid              bool      `json:"valid"`
+	Name               string    `json:"name,omitempty"`
+	Address            string    `json:"address,omitempty"`
+	ConsultationNumber string    `json:"consultation_number,omitempty"` // Registry request identifier
+	CheckedAt          time.Time `json:"checked_at"`
+	Source             string    `json:"source"` // "vies"
+}
+
+// TaxIDVerificationEvidence identifies the stored verification behind a tax decision
+type TaxIDVerificationEvidence struct {
+	VerificationID uuid.UUID `json:"verification_id"`
+	TaxIDCheckResult
+}
@@ -32,4 +32,72 @@ type TransactionData struct {
 	// Calculated values
 	TotalGasCostWei *big.Int // gasUsed * effectiveGasPrice
 	NetworkID       uuid.UUID
+
+	// Native token the fee was paid in; empty means ETH with 18 decimals
+	NativeSymbol   string
+	NativeDecimals int
+}
+
+// MinedTransaction is the receipt of a transaction that is in a block
+type MinedTransaction struct {
+	BlockNumber uint64
+	BlockHash   string
+	Status      uint64 // 1 = success, 0 = failed
+}
+
+// SentTransaction is a transaction the network knows about, whether mined or still in the mempool.
+// From is empty on networks that do not order an account's transactions by nonce.
+type SentTransaction struct {
+	From    string
+	Nonce   uint64
+	Pending bool
+}
+
+// SplTokenAccount is a Solana SPL token account and the delegate its owner approved
+type SplTokenAccount struct {
+	Address         string
+	ProgramID       string
+	Mint            string
+	Owner           string
+	State           string
+	Amount          uint64
+	Delegate        string
+	DelegatedAmount uint64
+}
+
+// SplApproval is the transaction a customer signed to approve the payment delegate on their token account
+// for one product. Its memo must carry the reference of the product token the approval is for.
+type SplApproval struct {
+	Signature    string
+	TokenAccount string
+	Owner        string // Customer wallet that owns the token account and signed the approval
+	Memo         string
+}
+
+// VerifiedSplApproval is an approval found on-chain as the customer described it
+type VerifiedSplApproval struct {
+	Mint   string // Empty for an approve instruction that does not name its mint
+	Amount uint64 // Delegated amount in base units of the mint
+	Slot   uint64
+}
+
+// SplDelegateTransfer is a payment the Solana payment delegate makes from a customer's token account
+// under the approval the customer signed
+type SplDelegateTransfer struct {
+	SourceTokenAccount string // Customer's token account the delegate was approved on
+	SourceOwner        string // Customer wallet that owns the token account
+	Mint               string
+	Decimals           uint8
+	Amount             uint64 // In base units of the mint
+	DestinationOwner   string // Merchant wallet; the transfer goes to its token account for the mint
+}
+
+// PaymentConfirmationResult summarizes a run of the transaction confirmation tracker
+type PaymentConfirmationResult struct {
+	Tracked    int64
+	Checked    int
+	Confirmed  int
+	Finalized  int
+	RolledBack int
+	Failed     int
 }
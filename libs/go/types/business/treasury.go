@@ -0,0 +1,85 @@
+package business
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fee levels a sweep can be submitted at, as Circle names them
+const (
+	TreasuryFeeLevelLow    = "LOW"
+	TreasuryFeeLevelMedium = "MEDIUM"
+	TreasuryFeeLevelHigh   = "HIGH"
+)
+
+// Treasury movement statuses
+const (
+	TreasuryMovementAwaitingApproval = "awaiting_approval" // Transfer challenge waiting for the merchant's PIN
+	TreasuryMovementSubmitted        = "submitted"         // Approved; Circle is sending the transfer
+	TreasuryMovementCompleted        = "completed"
+	TreasuryMovementFailed           = "failed"
+	TreasuryMovementExpired          = "expired" // The challenge was never approved
+)
+
+// TreasurySweepRule moves a Circle wallet's balance of a token above a threshold into a treasury wallet.
+// Amounts are decimal strings in whole token units.
+type TreasurySweepRule struct {
+	ID                   uuid.UUID
+	WorkspaceID          uuid.UUID
+	Name                 string
+	SourceWalletID       uuid.UUID
+	DestinationWalletID  uuid.UUID
+	TokenID              uuid.UUID
+	ThresholdAmount      string
+	RetainedAmount       string // Left in the source wallet after a sweep
+	FeeLevel             string
+	SweepIntervalMinutes int32
+	IsActive             bool
+	LastRunAt            *time.Time
+	LastRunResult        string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// TreasuryMovement is one sweep in a workspace's treasury ledger
+type TreasuryMovement struct {
+	ID                  uuid.UUID
+	WorkspaceID         uuid.UUID
+	RuleID              uuid.UUID
+	NetworkID           uuid.UUID
+	TokenID             uuid.UUID
+	SourceWalletID      uuid.UUID
+	DestinationWalletID uuid.UUID
+	Amount              string
+	SourceBalance       string
+	EstimatedFee        string // In the network's native token
+	Status              string
+	ChallengeID         string // Circle challenge the merchant approves to send the transfer
+	CircleTransactionID string
+	CircleState         string
+	TransactionHash     string
+	NetworkFee          string
+	ErrorMessage        string
+	ReconciledAt        *time.Time
+	CompletedAt         *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// TreasurySweepResult counts the outcome of a sweep run
+type TreasurySweepResult struct {
+	Checked    int // Rules that were due
+	Challenged int // Sweeps waiting for the merchant's approval
+	Skipped    int // Balance at or below the threshold, or not enough left to pay the fee
+	Failed     int
+}
+
+// TreasuryReconcileResult counts the outcome of reconciling open movements against Circle's transactions
+type TreasuryReconcileResult struct {
+	Checked   int
+	Completed int
+	Failed    int
+	Expired   int
+	Errors    int // Movements that could not be checked
+}